@@ -41,12 +41,16 @@ export enum PluginErrorCode {
   missingSignature = 'signatureMissing',
   invalidSignature = 'signatureInvalid',
   modifiedSignature = 'signatureModified',
+  incompatibleGrafanaVersion = 'grafanaVersionIncompatible',
+  missingExecutable = 'executableMissing',
+  crashedProcess = 'processCrashed',
 }
 
 /** Describes error returned from Grafana plugins API call */
 export interface PluginError {
   errorCode: PluginErrorCode;
   pluginId: string;
+  message?: string;
 }
 
 export interface PluginMeta<T extends KeyValue = {}> {
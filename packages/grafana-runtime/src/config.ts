@@ -44,6 +44,7 @@ export class GrafanaBootConfig implements GrafanaConfig {
   ldapEnabled = false;
   sigV4AuthEnabled = false;
   samlEnabled = false;
+  genericSamlEnabled = false;
   autoAssignOrg = true;
   verifyEmailEnabled = false;
   oauth: any;
@@ -59,6 +60,7 @@ export class GrafanaBootConfig implements GrafanaConfig {
   theme: GrafanaTheme;
   theme2: GrafanaTheme2;
   pluginsToPreload: string[] = [];
+  pluginModuleIntegrity: Record<string, string> = {};
   featureToggles: FeatureToggles = {
     ngalert: false,
     accesscontrol: false,
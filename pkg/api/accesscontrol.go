@@ -0,0 +1,258 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+// GetRoles returns the fixed roles and the roles of the current organization.
+//
+// GET /api/access-control/roles
+func (hs *HTTPServer) GetRoles(c *models.ReqContext) response.Response {
+	roles, err := hs.AccessControlStore.GetRoles(c.Req.Context(), c.OrgId)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get roles", err)
+	}
+
+	fixed := make([]*accesscontrol.RoleDTO, 0, len(accesscontrol.FixedRoles))
+	for _, role := range accesscontrol.FixedRoles {
+		role := role
+		fixed = append(fixed, &role)
+	}
+	sort.Slice(fixed, func(i, j int) bool { return fixed[i].Name < fixed[j].Name })
+
+	return response.JSON(http.StatusOK, append(fixed, roles...))
+}
+
+// GET /api/access-control/roles/:roleUID
+func (hs *HTTPServer) GetRole(c *models.ReqContext) response.Response {
+	role, err := hs.AccessControlStore.GetRole(c.Req.Context(), c.OrgId, c.Params(":roleUID"))
+	if err != nil {
+		return roleErrorToApiResponse(err, "Failed to get role")
+	}
+
+	return response.JSON(http.StatusOK, role)
+}
+
+// POST /api/access-control/roles
+func (hs *HTTPServer) CreateRole(c *models.ReqContext, cmd accesscontrol.CreateRoleCommand) response.Response {
+	if err := accesscontrol.ValidateCustomRole(cmd.Name, cmd.Permissions); err != nil {
+		return response.Error(http.StatusBadRequest, err.Error(), nil)
+	}
+	if rsp := hs.checkPermissionsGranted(c, cmd.Permissions); rsp != nil {
+		return rsp
+	}
+
+	cmd.OrgID = c.OrgId
+	role, err := hs.AccessControlStore.CreateRole(c.Req.Context(), cmd)
+	if err != nil {
+		return roleErrorToApiResponse(err, "Failed to create role")
+	}
+
+	return response.JSON(http.StatusOK, role)
+}
+
+// UpdateRole replaces a role. Managed roles keep their name, only their
+// description and permissions can be changed.
+//
+// PUT /api/access-control/roles/:roleUID
+func (hs *HTTPServer) UpdateRole(c *models.ReqContext, cmd accesscontrol.UpdateRoleCommand) response.Response {
+	cmd.OrgID = c.OrgId
+	cmd.UID = c.Params(":roleUID")
+
+	existing, err := hs.AccessControlStore.GetRole(c.Req.Context(), cmd.OrgID, cmd.UID)
+	if err != nil {
+		return roleErrorToApiResponse(err, "Failed to update role")
+	}
+
+	if existing.IsManaged() {
+		if cmd.Name != existing.Name {
+			return response.Error(http.StatusBadRequest, "Managed roles can't be renamed", nil)
+		}
+		err = accesscontrol.ValidatePermissions(cmd.Permissions)
+	} else {
+		err = accesscontrol.ValidateCustomRole(cmd.Name, cmd.Permissions)
+	}
+	if err != nil {
+		return response.Error(http.StatusBadRequest, err.Error(), nil)
+	}
+	if rsp := hs.checkPermissionsGranted(c, cmd.Permissions); rsp != nil {
+		return rsp
+	}
+
+	role, err := hs.AccessControlStore.UpdateRole(c.Req.Context(), cmd)
+	if err != nil {
+		return roleErrorToApiResponse(err, "Failed to update role")
+	}
+
+	return response.JSON(http.StatusOK, role)
+}
+
+// DeleteRole deletes a role. Assigned roles are only deleted with ?force=true.
+//
+// DELETE /api/access-control/roles/:roleUID
+func (hs *HTTPServer) DeleteRole(c *models.ReqContext) response.Response {
+	cmd := accesscontrol.DeleteRoleCommand{
+		OrgID: c.OrgId,
+		UID:   c.Params(":roleUID"),
+		Force: c.QueryBool("force"),
+	}
+
+	existing, err := hs.AccessControlStore.GetRole(c.Req.Context(), cmd.OrgID, cmd.UID)
+	if err != nil {
+		return roleErrorToApiResponse(err, "Failed to delete role")
+	}
+	if existing.IsManaged() {
+		return roleErrorToApiResponse(accesscontrol.ErrManagedRoleDeletion, "")
+	}
+
+	if err := hs.AccessControlStore.DeleteRole(c.Req.Context(), cmd); err != nil {
+		return roleErrorToApiResponse(err, "Failed to delete role")
+	}
+
+	return response.Success("Role deleted")
+}
+
+// GetBuiltinRoles returns the roles assigned to each built-in role, including fixed roles.
+//
+// GET /api/access-control/builtin-roles
+func (hs *HTTPServer) GetBuiltinRoles(c *models.ReqContext) response.Response {
+	result, err := hs.AccessControlStore.GetBuiltinRoles(c.Req.Context(), c.OrgId)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get built-in roles", err)
+	}
+
+	for builtin, names := range accesscontrol.FixedRoleGrants {
+		for _, name := range names {
+			if role, ok := accesscontrol.FixedRoles[name]; ok {
+				role := role
+				result[builtin] = append(result[builtin], &role)
+			}
+		}
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
+// AddBuiltinRole assigns a role to a built-in role. Only server admins can
+// assign roles to Grafana Admin.
+//
+// POST /api/access-control/builtin-roles
+func (hs *HTTPServer) AddBuiltinRole(c *models.ReqContext, cmd accesscontrol.AddBuiltinRoleCommand) response.Response {
+	if cmd.BuiltinRole == accesscontrol.RoleGrafanaAdmin && !c.IsGrafanaAdmin {
+		return response.Error(http.StatusForbidden, "Only server admins can assign roles to Grafana Admin", nil)
+	}
+	if rsp := hs.checkRoleGranted(c, cmd.RoleUID); rsp != nil {
+		return rsp
+	}
+
+	cmd.OrgID = c.OrgId
+	if err := hs.AccessControlStore.AddBuiltinRole(c.Req.Context(), cmd); err != nil {
+		return roleErrorToApiResponse(err, "Failed to add built-in role assignment")
+	}
+
+	return response.Success("Built-in role grant added")
+}
+
+// DELETE /api/access-control/builtin-roles/:builtinRole/roles/:roleUID
+func (hs *HTTPServer) RemoveBuiltinRole(c *models.ReqContext) response.Response {
+	cmd := accesscontrol.RemoveBuiltinRoleCommand{
+		OrgID:       c.OrgId,
+		RoleUID:     c.Params(":roleUID"),
+		BuiltinRole: c.Params(":builtinRole"),
+	}
+
+	if err := hs.AccessControlStore.RemoveBuiltinRole(c.Req.Context(), cmd); err != nil {
+		return roleErrorToApiResponse(err, "Failed to remove built-in role assignment")
+	}
+
+	return response.Success("Built-in role grant removed")
+}
+
+// GetUserRoles returns the roles assigned directly to a user of the current organization.
+//
+// GET /api/access-control/users/:userId/roles
+func (hs *HTTPServer) GetUserRoles(c *models.ReqContext) response.Response {
+	roles, err := hs.AccessControlStore.GetUserRoles(c.Req.Context(), c.OrgId, c.ParamsInt64(":userId"))
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get user roles", err)
+	}
+
+	return response.JSON(http.StatusOK, roles)
+}
+
+// POST /api/access-control/users/:userId/roles
+func (hs *HTTPServer) AddUserRole(c *models.ReqContext, cmd accesscontrol.AddUserRoleCommand) response.Response {
+	cmd.OrgID = c.OrgId
+	cmd.UserID = c.ParamsInt64(":userId")
+	if rsp := hs.checkRoleGranted(c, cmd.RoleUID); rsp != nil {
+		return rsp
+	}
+
+	if err := hs.AccessControlStore.AddUserRole(c.Req.Context(), cmd); err != nil {
+		return roleErrorToApiResponse(err, "Failed to add user role")
+	}
+
+	return response.Success("Role added to the user")
+}
+
+// DELETE /api/access-control/users/:userId/roles/:roleUID
+func (hs *HTTPServer) RemoveUserRole(c *models.ReqContext) response.Response {
+	cmd := accesscontrol.RemoveUserRoleCommand{
+		OrgID:   c.OrgId,
+		UserID:  c.ParamsInt64(":userId"),
+		RoleUID: c.Params(":roleUID"),
+	}
+
+	if err := hs.AccessControlStore.RemoveUserRole(c.Req.Context(), cmd); err != nil {
+		return roleErrorToApiResponse(err, "Failed to remove user role")
+	}
+
+	return response.Success("Role removed from the user")
+}
+
+// checkPermissionsGranted returns an error response unless the signed in user
+// is granted all the permissions, so that users can't escalate their
+// privileges by creating or assigning roles.
+func (hs *HTTPServer) checkPermissionsGranted(c *models.ReqContext, permissions []accesscontrol.Permission) response.Response {
+	granted, err := hs.AccessControl.GetUserPermissions(c.Req.Context(), c.SignedInUser)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get user permissions", err)
+	}
+	if err := accesscontrol.ValidatePermissionsGranted(granted, permissions); err != nil {
+		return response.Error(http.StatusForbidden, err.Error(), nil)
+	}
+	return nil
+}
+
+// checkRoleGranted returns an error response unless the signed in user is
+// granted all the permissions of the role.
+func (hs *HTTPServer) checkRoleGranted(c *models.ReqContext, roleUID string) response.Response {
+	role, err := hs.AccessControlStore.GetRole(c.Req.Context(), c.OrgId, roleUID)
+	if err != nil {
+		return roleErrorToApiResponse(err, "Failed to get role")
+	}
+	return hs.checkPermissionsGranted(c, role.Permissions)
+}
+
+func roleErrorToApiResponse(err error, message string) response.Response {
+	switch {
+	case errors.Is(err, accesscontrol.ErrRoleNotFound):
+		return response.Error(http.StatusNotFound, err.Error(), nil)
+	case errors.Is(err, accesscontrol.ErrRoleAlreadyExists),
+		errors.Is(err, accesscontrol.ErrVersionLE),
+		errors.Is(err, accesscontrol.ErrRoleAssigned):
+		return response.Error(http.StatusConflict, err.Error(), nil)
+	case errors.Is(err, accesscontrol.ErrInvalidRoleUID),
+		errors.Is(err, accesscontrol.ErrInvalidBuiltinRole),
+		errors.Is(err, accesscontrol.ErrManagedRoleDeletion):
+		return response.Error(http.StatusBadRequest, err.Error(), nil)
+	default:
+		return response.Error(http.StatusInternalServerError, message, err)
+	}
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	acdatabase "github.com/grafana/grafana/pkg/services/accesscontrol/database"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessControlAPIEndpoint_PrivilegeEscalation(t *testing.T) {
+	// an org admin who can manage roles, but not the users of the server
+	adminPermissions := []*accesscontrol.Permission{
+		{Action: accesscontrol.ActionRolesWrite, Scope: accesscontrol.ScopeRolesAll},
+		{Action: accesscontrol.ActionRolesBuiltinAdd, Scope: accesscontrol.ScopeRolesAll},
+		{Action: accesscontrol.ActionUsersRolesAdd, Scope: accesscontrol.ScopeUsersAll},
+		{Action: accesscontrol.ActionOrgUsersRead, Scope: accesscontrol.ScopeUsersAll},
+	}
+
+	setup := func(t *testing.T) (*scenarioContext, *acdatabase.AccessControlStore) {
+		sc, hs := setupAccessControlScenarioContext(t, setting.NewCfg(), "/api/access-control/roles", adminPermissions)
+		sc.m.Use(func(c *models.ReqContext) {
+			c.SignedInUser = &models.SignedInUser{UserId: testUserID, OrgId: testOrgID, OrgRole: models.ROLE_ADMIN}
+			c.IsSignedIn = true
+		})
+		store := &acdatabase.AccessControlStore{SQLStore: sqlstore.InitTestDB(t)}
+		hs.AccessControlStore = store
+		return sc, store
+	}
+
+	createRole := func(t *testing.T, store *acdatabase.AccessControlStore, permissions ...accesscontrol.Permission) *accesscontrol.RoleDTO {
+		role, err := store.CreateRole(context.Background(), accesscontrol.CreateRoleCommand{
+			OrgID:       testOrgID,
+			Name:        "custom:role",
+			Permissions: permissions,
+		})
+		require.NoError(t, err)
+		return role
+	}
+
+	request := func(t *testing.T, sc *scenarioContext, method, url, body string) {
+		sc.resp = httptest.NewRecorder()
+		var err error
+		sc.req, err = http.NewRequest(method, url, strings.NewReader(body))
+		require.NoError(t, err)
+		sc.req.Header.Set("Content-Type", "application/json")
+		sc.exec()
+	}
+
+	t.Run("Should not create a role with permissions the user doesn't have", func(t *testing.T) {
+		sc, _ := setup(t)
+		request(t, sc, http.MethodPost, "/api/access-control/roles",
+			`{"name": "custom:escalate", "permissions": [{"action": "users:delete", "scope": "global:users:*"}]}`)
+		assert.Equal(t, http.StatusForbidden, sc.resp.Code)
+	})
+
+	t.Run("Should create a role with permissions the user has", func(t *testing.T) {
+		sc, _ := setup(t)
+		request(t, sc, http.MethodPost, "/api/access-control/roles",
+			`{"name": "custom:reader", "permissions": [{"action": "org.users:read", "scope": "users:1"}]}`)
+		assert.Equal(t, http.StatusOK, sc.resp.Code)
+	})
+
+	t.Run("Should not update a role with permissions the user doesn't have", func(t *testing.T) {
+		sc, store := setup(t)
+		role := createRole(t, store, accesscontrol.Permission{Action: accesscontrol.ActionOrgUsersRead, Scope: "users:1"})
+		request(t, sc, http.MethodPut, "/api/access-control/roles/"+role.UID,
+			`{"version": 2, "name": "custom:role", "permissions": [{"action": "users:delete", "scope": "global:users:*"}]}`)
+		assert.Equal(t, http.StatusForbidden, sc.resp.Code)
+	})
+
+	t.Run("Should not assign a role with permissions the user doesn't have", func(t *testing.T) {
+		sc, store := setup(t)
+		role := createRole(t, store, accesscontrol.Permission{Action: accesscontrol.ActionUsersDelete, Scope: accesscontrol.ScopeGlobalUsersAll})
+
+		request(t, sc, http.MethodPost, "/api/access-control/users/1/roles", `{"roleUid": "`+role.UID+`"}`)
+		assert.Equal(t, http.StatusForbidden, sc.resp.Code)
+
+		request(t, sc, http.MethodPost, "/api/access-control/builtin-roles", `{"roleUid": "`+role.UID+`", "builtinRole": "Admin"}`)
+		assert.Equal(t, http.StatusForbidden, sc.resp.Code)
+
+		roles, err := store.GetUserRoles(context.Background(), testOrgID, 1)
+		require.NoError(t, err)
+		assert.Empty(t, roles)
+	})
+
+	t.Run("Should not assign roles to Grafana Admin unless the user is a server admin", func(t *testing.T) {
+		sc, store := setup(t)
+		role := createRole(t, store, accesscontrol.Permission{Action: accesscontrol.ActionOrgUsersRead, Scope: "users:1"})
+
+		request(t, sc, http.MethodPost, "/api/access-control/builtin-roles", `{"roleUid": "`+role.UID+`", "builtinRole": "Grafana Admin"}`)
+		assert.Equal(t, http.StatusForbidden, sc.resp.Code)
+
+		request(t, sc, http.MethodPost, "/api/access-control/builtin-roles", `{"roleUid": "`+role.UID+`", "builtinRole": "Admin"}`)
+		assert.Equal(t, http.StatusOK, sc.resp.Code)
+	})
+}
@@ -78,3 +78,11 @@ func (hs *HTTPServer) getAuthorizedSettings(ctx context.Context, user *models.Si
 func getSettingsScope(section, key string) string {
 	return fmt.Sprintf("settings:%s:%s", section, key)
 }
+
+// POST /api/admin/emails/templates/reload
+func (hs *HTTPServer) AdminReloadEmailTemplates(c *models.ReqContext) response.Response {
+	if err := bus.Dispatch(&models.ReloadEmailTemplatesCommand{}); err != nil {
+		return response.Error(http.StatusBadRequest, "Failed to reload the email templates", err)
+	}
+	return response.Success("Email templates reloaded")
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// GET /api/admin/angular-report
+func (hs *HTTPServer) AdminGetAngularReport(c *models.ReqContext) response.Response {
+	report := dtos.AngularReport{
+		Plugins:    []dtos.AngularPlugin{},
+		Dashboards: []dtos.AngularDashboard{},
+	}
+
+	angularPlugins := map[string]bool{}
+	for _, plugin := range hs.PluginManager.Plugins() {
+		if !plugin.AngularDetected {
+			continue
+		}
+		angularPlugins[plugin.Id] = true
+		report.Plugins = append(report.Plugins, dtos.AngularPlugin{
+			Id:      plugin.Id,
+			Name:    plugin.Name,
+			Type:    plugin.Type,
+			Version: plugin.Info.Version,
+		})
+	}
+	sort.Slice(report.Plugins, func(i, j int) bool {
+		return report.Plugins[i].Id < report.Plugins[j].Id
+	})
+
+	if len(angularPlugins) == 0 {
+		return response.JSON(http.StatusOK, report)
+	}
+
+	orgDataSources := map[int64]*dashboardDataSources{}
+	err := hs.SQLStore.ForEachDashboard(c.Req.Context(), func(dash *models.Dashboard) error {
+		dataSources, ok := orgDataSources[dash.OrgId]
+		if !ok {
+			query := models.GetDataSourcesQuery{OrgId: dash.OrgId}
+			if err := bus.Dispatch(&query); err != nil {
+				return err
+			}
+			dataSources = newDashboardDataSources(query.Result)
+			orgDataSources[dash.OrgId] = dataSources
+		}
+
+		pluginIDs := angularPluginsInDashboard(dash.Data, dataSources, angularPlugins)
+		if len(pluginIDs) > 0 {
+			report.Dashboards = append(report.Dashboards, dtos.AngularDashboard{
+				OrgId:   dash.OrgId,
+				Uid:     dash.Uid,
+				Title:   dash.Title,
+				Url:     models.GetDashboardUrl(dash.Uid, dash.Slug),
+				Plugins: pluginIDs,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to scan dashboards", err)
+	}
+
+	return response.JSON(http.StatusOK, report)
+}
+
+// dashboardDataSources resolves the data source references of the panels and
+// queries of the dashboards of an organization to data source types.
+type dashboardDataSources struct {
+	types       map[string]string
+	defaultType string
+}
+
+func newDashboardDataSources(dataSources []*models.DataSource) *dashboardDataSources {
+	ds := &dashboardDataSources{types: map[string]string{}}
+	for _, dataSource := range dataSources {
+		ds.types[dataSource.Name] = dataSource.Type
+		ds.types[dataSource.Uid] = dataSource.Type
+		if dataSource.IsDefault {
+			ds.defaultType = dataSource.Type
+		}
+	}
+	return ds
+}
+
+// resolve returns the type of the data source referenced by a panel or query,
+// which is either the name or UID of the data source, or an object with its UID
+// and type. An empty reference falls back to the data source of the parent panel.
+func (ds *dashboardDataSources) resolve(ref *simplejson.Json, parentType string) string {
+	switch value := ref.Interface().(type) {
+	case nil:
+		return parentType
+	case string:
+		// template variables can't be resolved without the values of the variables
+		if strings.HasPrefix(value, "$") {
+			return ""
+		}
+		return ds.types[value]
+	case map[string]interface{}:
+		if dsType := ref.Get("type").MustString(); dsType != "" {
+			return dsType
+		}
+		return ds.types[ref.Get("uid").MustString()]
+	}
+	return ""
+}
+
+// angularPluginsInDashboard returns the sorted IDs of the AngularJS plugins
+// that are used by the panels of a dashboard, either as panel or data source.
+func angularPluginsInDashboard(dash *simplejson.Json, dataSources *dashboardDataSources, angularPlugins map[string]bool) []string {
+	found := map[string]bool{}
+	var visitPanels func(panels *simplejson.Json)
+	visitPanels = func(panels *simplejson.Json) {
+		for i := range panels.MustArray() {
+			panel := panels.GetIndex(i)
+			if panelType := panel.Get("type").MustString(); angularPlugins[panelType] {
+				found[panelType] = true
+			}
+
+			panelDSType := dataSources.resolve(panel.Get("datasource"), dataSources.defaultType)
+			if angularPlugins[panelDSType] {
+				found[panelDSType] = true
+			}
+			targets := panel.Get("targets")
+			for j := range targets.MustArray() {
+				if dsType := dataSources.resolve(targets.GetIndex(j).Get("datasource"), panelDSType); angularPlugins[dsType] {
+					found[dsType] = true
+				}
+			}
+
+			// the panels of collapsed rows
+			visitPanels(panel.Get("panels"))
+		}
+	}
+
+	visitPanels(dash.Get("panels"))
+	// dashboards of schema versions before 16 have their panels in rows
+	rows := dash.Get("rows")
+	for i := range rows.MustArray() {
+		visitPanels(rows.GetIndex(i).Get("panels"))
+	}
+
+	pluginIDs := make([]string, 0, len(found))
+	for pluginID := range found {
+		pluginIDs = append(pluginIDs, pluginID)
+	}
+	sort.Strings(pluginIDs)
+	return pluginIDs
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAngularPluginsInDashboard(t *testing.T) {
+	dataSources := newDashboardDataSources([]*models.DataSource{
+		{Name: "Angular DS", Uid: "angular-uid", Type: "angular-datasource"},
+		{Name: "Prometheus", Uid: "prom-uid", Type: "prometheus", IsDefault: true},
+	})
+	angularPlugins := map[string]bool{
+		"angular-panel":      true,
+		"angular-datasource": true,
+		"prometheus":         false,
+	}
+
+	t.Run("Should find angular panels and data sources", func(t *testing.T) {
+		dash, err := simplejson.NewJson([]byte(`{
+			"panels": [
+				{"type": "timeseries", "datasource": "Angular DS"},
+				{"type": "row", "collapsed": true, "panels": [
+					{"type": "angular-panel", "datasource": null}
+				]}
+			]
+		}`))
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"angular-datasource", "angular-panel"}, angularPluginsInDashboard(dash, dataSources, angularPlugins))
+	})
+
+	t.Run("Should resolve data source references of queries", func(t *testing.T) {
+		dash, err := simplejson.NewJson([]byte(`{
+			"panels": [
+				{"type": "timeseries", "datasource": "-- Mixed --", "targets": [
+					{"datasource": {"uid": "angular-uid"}},
+					{"datasource": "$ds"}
+				]}
+			]
+		}`))
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"angular-datasource"}, angularPluginsInDashboard(dash, dataSources, angularPlugins))
+	})
+
+	t.Run("Should find angular panels in rows of old dashboards", func(t *testing.T) {
+		dash, err := simplejson.NewJson([]byte(`{
+			"rows": [
+				{"panels": [{"type": "angular-panel"}]}
+			]
+		}`))
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"angular-panel"}, angularPluginsInDashboard(dash, dataSources, angularPlugins))
+	})
+
+	t.Run("Should not report dashboards without angular plugins", func(t *testing.T) {
+		dash, err := simplejson.NewJson([]byte(`{
+			"panels": [
+				{"type": "timeseries", "datasource": {"type": "prometheus", "uid": "prom-uid"}},
+				{"type": "text"}
+			]
+		}`))
+		require.NoError(t, err)
+
+		require.Empty(t, angularPluginsInDashboard(dash, dataSources, angularPlugins))
+	})
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// GET /api/admin/audit
+func (hs *HTTPServer) AdminSearchAuditLog(c *models.ReqContext) response.Response {
+	if !hs.Cfg.AuditLogEnabled {
+		return response.Error(404, "Audit log is disabled", nil)
+	}
+
+	from, err := parseAuditTime(c.Query("from"))
+	if err != nil {
+		return response.Error(400, "Invalid from time", err)
+	}
+	to, err := parseAuditTime(c.Query("to"))
+	if err != nil {
+		return response.Error(400, "Invalid to time", err)
+	}
+
+	query := models.SearchAuditEntriesQuery{
+		Action: c.Query("action"),
+		UserId: c.QueryInt64("userId"),
+		Target: c.Query("target"),
+		From:   from,
+		To:     to,
+		Limit:  c.QueryInt("perpage"),
+		Page:   c.QueryInt("page"),
+	}
+	if err := hs.AuditService.Search(c.Req.Context(), &query); err != nil {
+		return response.Error(500, "Failed to search audit log", err)
+	}
+
+	return response.JSON(200, query.Result)
+}
+
+// parseAuditTime parses a time given either in RFC 3339 format or in milliseconds since the epoch.
+func parseAuditTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(0, ms*int64(time.Millisecond)), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
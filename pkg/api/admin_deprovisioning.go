@@ -0,0 +1,39 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/deprovisioning"
+)
+
+// GET /api/admin/deprovisioning/report
+func (hs *HTTPServer) AdminGetDeprovisioningReport(c *models.ReqContext) response.Response {
+	if hs.DeprovisioningService.IsDisabled() {
+		return response.Error(404, "User deprovisioning is disabled", nil)
+	}
+
+	report := hs.DeprovisioningService.LastReport()
+	if report == nil {
+		return response.Error(404, "Users haven't been checked yet", nil)
+	}
+
+	return response.JSON(200, report)
+}
+
+// POST /api/admin/deprovisioning/run
+func (hs *HTTPServer) AdminRunDeprovisioning(c *models.ReqContext) response.Response {
+	report, err := hs.DeprovisioningService.RunNow(c.Req.Context())
+	if err != nil {
+		if errors.Is(err, deprovisioning.ErrDisabled) {
+			return response.Error(404, "User deprovisioning is disabled", nil)
+		}
+		return response.Error(500, "Failed to deprovision users", err)
+	}
+	if report == nil {
+		return response.Error(409, "Users are being checked by another instance", nil)
+	}
+
+	return response.JSON(200, report)
+}
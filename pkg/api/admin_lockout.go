@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// GET /api/admin/users/:id/lockout
+func (hs *HTTPServer) AdminGetUserLockout(c *models.ReqContext) response.Response {
+	user, errResp := getLockoutUser(c.Req.Context(), c.ParamsInt64(":id"))
+	if errResp != nil {
+		return errResp
+	}
+
+	// users can log in with both their login and email, which are counted separately
+	result := dtos.UserLockout{}
+	now := time.Now()
+	for _, username := range []string{user.Login, user.Email} {
+		if username == "" {
+			continue
+		}
+
+		state, err := hs.LoginLockoutService.GetUserState(username)
+		if err != nil {
+			return response.Error(500, "Failed to get user lockout", err)
+		}
+
+		result.FailedAttempts += state.Failures
+		if state.Lockouts > result.Lockouts {
+			result.Lockouts = state.Lockouts
+		}
+		if state.IsLocked(now) && (result.LockedUntil == nil || state.LockedUntil.After(*result.LockedUntil)) {
+			lockedUntil := state.LockedUntil
+			result.Locked = true
+			result.LockedUntil = &lockedUntil
+		}
+	}
+
+	return response.JSON(200, result)
+}
+
+// DELETE /api/admin/users/:id/lockout
+func (hs *HTTPServer) AdminUnlockUser(c *models.ReqContext) response.Response {
+	user, errResp := getLockoutUser(c.Req.Context(), c.ParamsInt64(":id"))
+	if errResp != nil {
+		return errResp
+	}
+
+	if err := hs.LoginLockoutService.UnlockUser(user.Login, user.Email); err != nil {
+		return response.Error(500, "Failed to unlock user", err)
+	}
+
+	return response.Success("User unlocked")
+}
+
+// DELETE /api/admin/lockouts/ip/:ip
+func (hs *HTTPServer) AdminUnlockIP(c *models.ReqContext) response.Response {
+	ip := c.Params(":ip")
+	if net.ParseIP(ip) == nil {
+		return response.Error(400, "Invalid IP address", nil)
+	}
+
+	if err := hs.LoginLockoutService.UnlockIP(ip); err != nil {
+		return response.Error(500, "Failed to unlock IP address", err)
+	}
+
+	return response.Success("IP address unlocked")
+}
+
+func getLockoutUser(ctx context.Context, userID int64) (*models.User, response.Response) {
+	query := models.GetUserByIdQuery{Id: userID}
+	if err := bus.DispatchCtx(ctx, &query); err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			return nil, response.Error(404, models.ErrUserNotFound.Error(), nil)
+		}
+		return nil, response.Error(500, "Failed to get user", err)
+	}
+
+	return query.Result, nil
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/backendplugin"
+)
+
+// GET /api/admin/plugins
+func (hs *HTTPServer) AdminGetPlugins(c *models.ReqContext) response.Response {
+	result := []dtos.AdminPlugin{}
+	for _, plugin := range hs.PluginManager.Plugins() {
+		// nested plugins are managed together with the app that includes them
+		if plugin.IsCorePlugin || plugin.IncludedInAppId != "" {
+			continue
+		}
+		result = append(result, hs.toAdminPlugin(plugin))
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Id < result[j].Id
+	})
+
+	return response.JSON(http.StatusOK, result)
+}
+
+// POST /api/admin/plugins
+func (hs *HTTPServer) AdminInstallPlugin(c *models.ReqContext, cmd dtos.AdminInstallPluginCommand) response.Response {
+	if hs.PluginManager.GetPlugin(cmd.PluginId) != nil {
+		return response.Error(http.StatusConflict, "Plugin already installed", nil)
+	}
+
+	return hs.adminInstallPlugin(c, cmd.PluginId, cmd.Version, cmd.UploadUID)
+}
+
+// PUT /api/admin/plugins/:pluginId
+func (hs *HTTPServer) AdminUpdatePlugin(c *models.ReqContext, cmd dtos.InstallPluginCommand) response.Response {
+	pluginID := c.Params(":pluginId")
+	plugin := hs.PluginManager.GetPlugin(pluginID)
+	if plugin == nil {
+		return response.Error(http.StatusNotFound, "Plugin not installed", nil)
+	}
+
+	version := cmd.Version
+	if version == "" && cmd.UploadUID == "" {
+		// update to the latest version known by the update checker, which lets
+		// the manager detect that the plugin is already up to date
+		version = plugin.GrafanaNetVersion
+	}
+
+	return hs.adminInstallPlugin(c, pluginID, version, cmd.UploadUID)
+}
+
+// PUT /api/admin/plugins/:pluginId/log-level
+func (hs *HTTPServer) AdminSetPluginLogLevel(c *models.ReqContext, cmd dtos.SetPluginLogLevelCommand) response.Response {
+	err := hs.BackendPluginManager.SetLogLevel(c.Params(":pluginId"), cmd.Level)
+	if errors.Is(err, backendplugin.ErrPluginNotRegistered) {
+		return response.Error(http.StatusNotFound, "Backend plugin not found", err)
+	}
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Invalid log level", err)
+	}
+
+	return response.Success("Plugin log level updated")
+}
+
+// adminInstallPlugin installs or updates a plugin from the catalog, or from
+// a zip archive uploaded through the uploads API, and returns the installed plugin.
+func (hs *HTTPServer) adminInstallPlugin(c *models.ReqContext, pluginID, version, uploadUID string) response.Response {
+	if uploadUID != "" {
+		if resp := hs.installPluginFromUpload(c, pluginID, uploadUID); resp.Status() != http.StatusOK {
+			return resp
+		}
+	} else if err := hs.PluginManager.Install(c.Req.Context(), pluginID, version); err != nil {
+		return translatePluginInstallErrorToAPIError(err)
+	}
+
+	plugin := hs.PluginManager.GetPlugin(pluginID)
+	if plugin == nil {
+		return response.Error(http.StatusInternalServerError, "Plugin was installed but could not be loaded", nil)
+	}
+
+	return response.JSON(http.StatusOK, hs.toAdminPlugin(plugin))
+}
+
+func (hs *HTTPServer) toAdminPlugin(plugin *plugins.PluginBase) dtos.AdminPlugin {
+	dto := dtos.AdminPlugin{
+		Id:            plugin.Id,
+		Name:          plugin.Name,
+		Type:          plugin.Type,
+		Version:       plugin.Info.Version,
+		Signature:     plugin.Signature,
+		LatestVersion: plugin.GrafanaNetVersion,
+		HasUpdate:     plugin.GrafanaNetHasUpdate,
+		PinnedVersion: hs.Cfg.PluginVersionPins[plugin.Id],
+	}
+	if status, ok := hs.BackendPluginManager.Status(plugin.Id); ok {
+		dto.BackendStatus = &status
+	}
+	if level, ok := log.ContextLevel("pluginId", plugin.Id); ok {
+		dto.LogLevel = level
+	}
+	return dto
+}
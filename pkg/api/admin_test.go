@@ -139,6 +139,30 @@ func TestAdmin_AccessControl(t *testing.T) {
 				},
 			},
 		},
+		{
+			expectedCode: http.StatusBadRequest,
+			desc:         "AdminUnlockIP should pass the permission check for user with correct permissions",
+			url:          "/api/admin/lockouts/ip/invalid",
+			method:       http.MethodDelete,
+			permissions: []*accesscontrol.Permission{
+				{
+					Action: accesscontrol.ActionUsersUnlock,
+					Scope:  accesscontrol.ScopeGlobalUsersAll,
+				},
+			},
+		},
+		{
+			expectedCode: http.StatusForbidden,
+			desc:         "AdminUnlockIP should return 403 for user without required permissions",
+			url:          "/api/admin/lockouts/ip/invalid",
+			method:       http.MethodDelete,
+			permissions: []*accesscontrol.Permission{
+				{
+					Action: accesscontrol.ActionUsersUnlock,
+					Scope:  "global:users:1",
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
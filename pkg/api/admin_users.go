@@ -3,12 +3,16 @@ package api
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/infra/metrics"
+	"github.com/grafana/grafana/pkg/infra/network"
+	"github.com/grafana/grafana/pkg/middleware/cookies"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/passwordpolicy"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -29,8 +33,12 @@ func (hs *HTTPServer) AdminCreateUser(c *models.ReqContext, form dtos.AdminCreat
 		}
 	}
 
-	if len(cmd.Password) < 4 {
-		return response.Error(400, "Password is missing or too short", nil)
+	if len(cmd.Password) == 0 {
+		return response.Error(400, "Password is missing", nil)
+	}
+
+	if err := passwordpolicy.Validate(c.Req.Context(), hs.Cfg, nil, cmd.Password); err != nil {
+		return passwordPolicyErrorResponse(err)
 	}
 
 	user, err := hs.Login.CreateUser(cmd)
@@ -56,19 +64,19 @@ func (hs *HTTPServer) AdminCreateUser(c *models.ReqContext, form dtos.AdminCreat
 	return response.JSON(200, result)
 }
 
-func AdminUpdateUserPassword(c *models.ReqContext, form dtos.AdminUpdateUserPasswordForm) response.Response {
+func (hs *HTTPServer) AdminUpdateUserPassword(c *models.ReqContext, form dtos.AdminUpdateUserPasswordForm) response.Response {
 	userID := c.ParamsInt64(":id")
 
-	if len(form.Password) < 4 {
-		return response.Error(400, "New password too short", nil)
-	}
-
 	userQuery := models.GetUserByIdQuery{Id: userID}
 
 	if err := bus.DispatchCtx(c.Req.Context(), &userQuery); err != nil {
 		return response.Error(500, "Could not read user from database", err)
 	}
 
+	if err := passwordpolicy.Validate(c.Req.Context(), hs.Cfg, userQuery.Result, form.Password); err != nil {
+		return passwordPolicyErrorResponse(err)
+	}
+
 	passwordHashed, err := util.EncodePassword(form.Password, userQuery.Result.Salt)
 	if err != nil {
 		return response.Error(500, "Could not encode password", err)
@@ -187,6 +195,53 @@ func (hs *HTTPServer) AdminRevokeUserAuthToken(c *models.ReqContext, cmd models.
 	return hs.revokeUserAuthTokenInternal(c, userID, cmd)
 }
 
+// POST /api/admin/users/:id/impersonate
+func (hs *HTTPServer) AdminImpersonateUser(c *models.ReqContext) response.Response {
+	if !hs.Cfg.ImpersonationEnabled {
+		return response.Error(404, "Impersonation is disabled", nil)
+	}
+
+	userID := c.ParamsInt64(":id")
+	if c.UserId == userID {
+		return response.Error(400, "You cannot impersonate yourself", nil)
+	}
+
+	query := models.GetUserByIdQuery{Id: userID}
+	if err := bus.DispatchCtx(c.Req.Context(), &query); err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			return response.Error(404, models.ErrUserNotFound.Error(), nil)
+		}
+		return response.Error(500, "Failed to get user", err)
+	}
+	user := query.Result
+
+	// admins can't be impersonated, so that an impersonation session never has admin permissions
+	if user.IsAdmin {
+		return response.Error(403, "Cannot impersonate a Grafana Admin", nil)
+	}
+	if user.IsDisabled {
+		return response.Error(400, "Cannot impersonate a disabled user", nil)
+	}
+
+	ip, err := network.GetIPFromAddress(c.RemoteAddr())
+	if err != nil {
+		ip = nil
+	}
+
+	token, err := hs.AuthTokenService.CreateImpersonationToken(c.Req.Context(), user, c.UserId, ip, c.Req.UserAgent())
+	if err != nil {
+		return response.Error(500, "Failed to create impersonation session", err)
+	}
+
+	hs.log.Info("Impersonation started", "impersonator", c.Login, "user", user.Login)
+	cookies.WriteSessionCookie(c, hs.Cfg, token.UnhashedToken, hs.Cfg.ImpersonationSessionDuration)
+
+	return response.JSON(200, util.DynMap{
+		"message": "Impersonating user",
+		"expires": time.Unix(token.CreatedAt, 0).Add(hs.Cfg.ImpersonationSessionDuration),
+	})
+}
+
 // updateUserPermissions updates the user's permissions.
 //
 // Stubbable by tests.
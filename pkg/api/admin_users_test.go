@@ -4,14 +4,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/auth"
 	"github.com/grafana/grafana/pkg/services/login"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
@@ -97,6 +103,44 @@ func TestAdminAPIEndpoint(t *testing.T) {
 			})
 	})
 
+	t.Run("When a server admin impersonates a user", func(t *testing.T) {
+		adminImpersonateUserScenario(t, "Should create an impersonation session when calling POST on",
+			"/api/admin/users/200/impersonate", "/api/admin/users/:id/impersonate", func(sc *scenarioContext) {
+				bus.AddHandlerCtx("test", func(ctx context.Context, cmd *models.GetUserByIdQuery) error {
+					cmd.Result = &models.User{Id: cmd.Id, Login: "viewer"}
+					return nil
+				})
+
+				var impersonatorID int64
+				sc.userAuthTokenService.CreateImpersonationTokenProvider = func(ctx context.Context, user *models.User, id int64, clientIP net.IP, userAgent string) (*models.UserToken, error) {
+					impersonatorID = id
+					return &models.UserToken{UserId: user.Id, ImpersonatorUserId: id, UnhashedToken: "impersonation-token"}, nil
+				}
+
+				sc.fakeReqWithParams("POST", sc.url, map[string]string{}).exec()
+				assert.Equal(t, 200, sc.resp.Code)
+				assert.Equal(t, testUserID, impersonatorID)
+				assert.Contains(t, sc.resp.Header().Get("Set-Cookie"), "grafana_session=impersonation-token")
+			})
+
+		adminImpersonateUserScenario(t, "Should not be allowed for a Grafana Admin when calling POST on",
+			"/api/admin/users/200/impersonate", "/api/admin/users/:id/impersonate", func(sc *scenarioContext) {
+				bus.AddHandlerCtx("test", func(ctx context.Context, cmd *models.GetUserByIdQuery) error {
+					cmd.Result = &models.User{Id: cmd.Id, Login: "admin2", IsAdmin: true}
+					return nil
+				})
+
+				sc.fakeReqWithParams("POST", sc.url, map[string]string{}).exec()
+				assert.Equal(t, 403, sc.resp.Code)
+			})
+
+		adminImpersonateUserScenario(t, "Should not be allowed for the admin themselves when calling POST on",
+			fmt.Sprintf("/api/admin/users/%d/impersonate", testUserID), "/api/admin/users/:id/impersonate", func(sc *scenarioContext) {
+				sc.fakeReqWithParams("POST", sc.url, map[string]string{}).exec()
+				assert.Equal(t, 400, sc.resp.Code)
+			})
+	})
+
 	t.Run("When a server admin gets auth tokens for a non-existing user", func(t *testing.T) {
 		adminGetUserAuthTokensScenario(t, "Should return not found when calling GET on",
 			"/api/admin/users/200/auth-tokens", "/api/admin/users/:id/auth-tokens", func(sc *scenarioContext) {
@@ -304,6 +348,62 @@ func TestAdminAPIEndpoint(t *testing.T) {
 	})
 }
 
+func TestAdminUsersAPIEndpoint_AccessControl(t *testing.T) {
+	tests := []accessControlTestCase{
+		{
+			expectedCode: http.StatusNotFound,
+			desc:         "AdminImpersonateUser should pass the permission check for user with correct permissions",
+			url:          "/api/admin/users/2/impersonate",
+			method:       http.MethodPost,
+			permissions: []*accesscontrol.Permission{
+				{
+					Action: accesscontrol.ActionUsersImpersonate,
+					Scope:  accesscontrol.ScopeGlobalUsersAll,
+				},
+			},
+		},
+		{
+			expectedCode: http.StatusForbidden,
+			desc:         "AdminImpersonateUser should return 403 for user without required permissions",
+			url:          "/api/admin/users/2/impersonate",
+			method:       http.MethodPost,
+			permissions: []*accesscontrol.Permission{
+				{
+					Action: accesscontrol.ActionUsersUnlock,
+					Scope:  accesscontrol.ScopeGlobalUsersAll,
+				},
+			},
+		},
+		{
+			expectedCode: http.StatusForbidden,
+			desc:         "AdminImpersonateUser should return 403 for user with permissions on another user",
+			url:          "/api/admin/users/2/impersonate",
+			method:       http.MethodPost,
+			permissions: []*accesscontrol.Permission{
+				{
+					Action: accesscontrol.ActionUsersImpersonate,
+					Scope:  "global:users:3",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			// impersonation is disabled, so the handler returns 404 once the permission check passes
+			sc, _ := setupAccessControlScenarioContext(t, setting.NewCfg(), test.url, test.permissions)
+			sc.resp = httptest.NewRecorder()
+
+			var err error
+			sc.req, err = http.NewRequest(test.method, test.url, nil)
+			require.NoError(t, err)
+
+			sc.exec()
+			assert.Equal(t, test.expectedCode, sc.resp.Code)
+		})
+	}
+}
+
 func putAdminScenario(t *testing.T, desc string, url string, routePattern string, role models.RoleType,
 	cmd dtos.AdminUpdateUserPermissionsForm, fn scenarioFunc) {
 	t.Run(fmt.Sprintf("%s %s", desc, url), func(t *testing.T) {
@@ -356,6 +456,40 @@ func adminLogoutUserScenario(t *testing.T, desc string, url string, routePattern
 	})
 }
 
+func adminImpersonateUserScenario(t *testing.T, desc string, url string, routePattern string, fn scenarioFunc) {
+	t.Run(fmt.Sprintf("%s %s", desc, url), func(t *testing.T) {
+		t.Cleanup(bus.ClearBusHandlers)
+
+		fakeAuthTokenService := auth.NewFakeUserAuthTokenService()
+
+		hs := HTTPServer{
+			Cfg: &setting.Cfg{
+				LoginCookieName:              "grafana_session",
+				ImpersonationEnabled:         true,
+				ImpersonationSessionDuration: time.Hour,
+			},
+			Bus:              bus.GetBus(),
+			AuthTokenService: fakeAuthTokenService,
+			log:              log.New("test"),
+		}
+
+		sc := setupScenarioContext(t, url)
+		sc.userAuthTokenService = fakeAuthTokenService
+		sc.defaultHandler = routing.Wrap(func(c *models.ReqContext) response.Response {
+			sc.context = c
+			sc.context.UserId = testUserID
+			sc.context.OrgId = testOrgID
+			sc.context.IsGrafanaAdmin = true
+
+			return hs.AdminImpersonateUser(c)
+		})
+
+		sc.m.Post(routePattern, sc.defaultHandler)
+
+		fn(sc)
+	})
+}
+
 func adminRevokeUserAuthTokenScenario(t *testing.T, desc string, url string, routePattern string, cmd models.RevokeAuthTokenCmd, fn scenarioFunc) {
 	t.Run(fmt.Sprintf("%s %s", desc, url), func(t *testing.T) {
 		t.Cleanup(bus.ClearBusHandlers)
@@ -465,6 +599,7 @@ func adminCreateUserScenario(t *testing.T, desc string, url string, routePattern
 
 		hs := HTTPServer{
 			Bus:   bus.GetBus(),
+			Cfg:   setting.NewCfg(),
 			Login: fakeLoginService{expected: cmd},
 		}
 
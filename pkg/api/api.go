@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	acmiddleware "github.com/grafana/grafana/pkg/services/accesscontrol/middleware"
+	"github.com/grafana/grafana/pkg/services/reports"
 )
 
 var plog = log.New("api")
@@ -33,6 +34,7 @@ func (hs *HTTPServer) registerRoutes() {
 	redirectFromLegacyPanelEditURL := middleware.RedirectFromLegacyPanelEditURL(hs.Cfg)
 	authorize := acmiddleware.Middleware(hs.AccessControl)
 	quota := middleware.Quota(hs.QuotaService)
+	audit := middleware.Audit(hs.AuditService)
 	bind := binding.Bind
 
 	r := hs.RouteRegister
@@ -40,9 +42,13 @@ func (hs *HTTPServer) registerRoutes() {
 	// not logged in views
 	r.Get("/logout", hs.Logout)
 	r.Post("/login", quota("session"), bind(dtos.LoginCommand{}), routing.Wrap(hs.LoginPost))
+	r.Get("/login/generic_saml", quota("session"), hs.SAMLLogin)
+	r.Get("/login/kerberos", quota("session"), hs.KerberosLogin)
 	r.Get("/login/:name", quota("session"), hs.OAuthLogin)
 	r.Get("/login", hs.LoginView)
 	r.Get("/invite/:code", hs.Index)
+	r.Get("/generic_saml/metadata", hs.SAMLMetadata)
+	r.Post("/generic_saml/acs", quota("session"), hs.SAMLACS)
 
 	// authed views
 	r.Get("/", reqSignedIn, hs.Index)
@@ -123,7 +129,7 @@ func (hs *HTTPServer) registerRoutes() {
 	r.Get("/user/password/reset", hs.Index)
 
 	r.Post("/api/user/password/send-reset-email", bind(dtos.SendResetPasswordEmailForm{}), routing.Wrap(SendResetPasswordEmail))
-	r.Post("/api/user/password/reset", bind(dtos.ResetUserPasswordForm{}), routing.Wrap(ResetPassword))
+	r.Post("/api/user/password/reset", bind(dtos.ResetUserPasswordForm{}), routing.Wrap(hs.ResetPassword))
 
 	// dashboard snapshots
 	r.Get("/dashboard/snapshot/*", reqNoAuth, hs.Index)
@@ -132,6 +138,10 @@ func (hs *HTTPServer) registerRoutes() {
 	// api renew session based on cookie
 	r.Get("/api/login/ping", quota("session"), routing.Wrap(hs.LoginAPIPing))
 
+	// oauth device authorization flow for cli and headless clients
+	r.Post("/api/login/device/:name", quota("session"), routing.Wrap(hs.StartDeviceLogin))
+	r.Post("/api/login/device/:name/token", quota("session"), bind(dtos.DeviceTokenCommand{}), routing.Wrap(hs.DeviceLoginToken))
+
 	// expose plugin file system assets
 	r.Get("/public/plugins/:pluginId/*", hs.GetPluginAssets)
 
@@ -148,7 +158,7 @@ func (hs *HTTPServer) registerRoutes() {
 			userRoute.Post("/stars/dashboard/:id", routing.Wrap(StarDashboard))
 			userRoute.Delete("/stars/dashboard/:id", routing.Wrap(UnstarDashboard))
 
-			userRoute.Put("/password", bind(models.ChangeUserPasswordCommand{}), routing.Wrap(ChangeUserPassword))
+			userRoute.Put("/password", bind(models.ChangeUserPasswordCommand{}), routing.Wrap(hs.ChangeUserPassword))
 			userRoute.Get("/quotas", routing.Wrap(GetUserQuotas))
 			userRoute.Put("/helpflags/:id", routing.Wrap(SetHelpFlag))
 			// For dev purpose
@@ -158,7 +168,7 @@ func (hs *HTTPServer) registerRoutes() {
 			userRoute.Put("/preferences", bind(dtos.UpdatePrefsCmd{}), routing.Wrap(UpdateUserPreferences))
 
 			userRoute.Get("/auth-tokens", routing.Wrap(hs.GetUserAuthTokens))
-			userRoute.Post("/revoke-auth-token", bind(models.RevokeAuthTokenCmd{}), routing.Wrap(hs.RevokeUserAuthToken))
+			userRoute.Post("/revoke-auth-token", audit(models.AuditActionAuthTokenRevoked, "", ""), bind(models.RevokeAuthTokenCmd{}), routing.Wrap(hs.RevokeUserAuthToken))
 		}, reqSignedInNoAnonymous)
 
 		// users (admin permission required)
@@ -207,9 +217,9 @@ func (hs *HTTPServer) registerRoutes() {
 			orgRoute.Put("/address", reqOrgAdmin, bind(dtos.UpdateOrgAddressForm{}), routing.Wrap(UpdateOrgAddressCurrent))
 			orgRoute.Get("/users", authorize(reqOrgAdmin, accesscontrol.ActionOrgUsersRead, accesscontrol.ScopeUsersAll), routing.Wrap(hs.GetOrgUsersForCurrentOrg))
 			orgRoute.Get("/users/search", authorize(reqOrgAdmin, accesscontrol.ActionOrgUsersRead, accesscontrol.ScopeUsersAll), routing.Wrap(hs.SearchOrgUsersWithPaging))
-			orgRoute.Post("/users", authorize(reqOrgAdmin, accesscontrol.ActionOrgUsersAdd, accesscontrol.ScopeUsersAll), quota("user"), bind(models.AddOrgUserCommand{}), routing.Wrap(AddOrgUserToCurrentOrg))
-			orgRoute.Patch("/users/:userId", authorize(reqOrgAdmin, accesscontrol.ActionOrgUsersRoleUpdate, usersScope), bind(models.UpdateOrgUserCommand{}), routing.Wrap(UpdateOrgUserForCurrentOrg))
-			orgRoute.Delete("/users/:userId", authorize(reqOrgAdmin, accesscontrol.ActionOrgUsersRemove, usersScope), routing.Wrap(RemoveOrgUserForCurrentOrg))
+			orgRoute.Post("/users", audit(models.AuditActionOrgUserAdded, "", ""), authorize(reqOrgAdmin, accesscontrol.ActionOrgUsersAdd, accesscontrol.ScopeUsersAll), quota("user"), bind(models.AddOrgUserCommand{}), routing.Wrap(AddOrgUserToCurrentOrg))
+			orgRoute.Patch("/users/:userId", audit(models.AuditActionOrgUserRoleUpdated, "user", ":userId"), authorize(reqOrgAdmin, accesscontrol.ActionOrgUsersRoleUpdate, usersScope), bind(models.UpdateOrgUserCommand{}), routing.Wrap(UpdateOrgUserForCurrentOrg))
+			orgRoute.Delete("/users/:userId", audit(models.AuditActionOrgUserRemoved, "user", ":userId"), authorize(reqOrgAdmin, accesscontrol.ActionOrgUsersRemove, usersScope), routing.Wrap(RemoveOrgUserForCurrentOrg))
 
 			// invites
 			orgRoute.Get("/invites", authorize(reqOrgAdmin, accesscontrol.ActionUsersCreate), routing.Wrap(GetPendingOrgInvites))
@@ -240,9 +250,9 @@ func (hs *HTTPServer) registerRoutes() {
 			orgsRoute.Put("/address", reqGrafanaAdmin, bind(dtos.UpdateOrgAddressForm{}), routing.Wrap(UpdateOrgAddress))
 			orgsRoute.Delete("/", reqGrafanaAdmin, routing.Wrap(DeleteOrgByID))
 			orgsRoute.Get("/users", authorize(reqGrafanaAdmin, accesscontrol.ActionOrgUsersRead, accesscontrol.ScopeUsersAll), routing.Wrap(hs.GetOrgUsers))
-			orgsRoute.Post("/users", authorize(reqGrafanaAdmin, accesscontrol.ActionOrgUsersAdd, accesscontrol.ScopeUsersAll), bind(models.AddOrgUserCommand{}), routing.Wrap(AddOrgUser))
-			orgsRoute.Patch("/users/:userId", authorize(reqGrafanaAdmin, accesscontrol.ActionOrgUsersRoleUpdate, usersScope), bind(models.UpdateOrgUserCommand{}), routing.Wrap(UpdateOrgUser))
-			orgsRoute.Delete("/users/:userId", authorize(reqGrafanaAdmin, accesscontrol.ActionOrgUsersRemove, usersScope), routing.Wrap(RemoveOrgUser))
+			orgsRoute.Post("/users", audit(models.AuditActionOrgUserAdded, "", ""), authorize(reqGrafanaAdmin, accesscontrol.ActionOrgUsersAdd, accesscontrol.ScopeUsersAll), bind(models.AddOrgUserCommand{}), routing.Wrap(AddOrgUser))
+			orgsRoute.Patch("/users/:userId", audit(models.AuditActionOrgUserRoleUpdated, "user", ":userId"), authorize(reqGrafanaAdmin, accesscontrol.ActionOrgUsersRoleUpdate, usersScope), bind(models.UpdateOrgUserCommand{}), routing.Wrap(UpdateOrgUser))
+			orgsRoute.Delete("/users/:userId", audit(models.AuditActionOrgUserRemoved, "user", ":userId"), authorize(reqGrafanaAdmin, accesscontrol.ActionOrgUsersRemove, usersScope), routing.Wrap(RemoveOrgUser))
 			orgsRoute.Get("/quotas", reqGrafanaAdmin, routing.Wrap(GetOrgQuotas))
 			orgsRoute.Put("/quotas/:target", reqGrafanaAdmin, bind(models.UpdateOrgQuotaCmd{}), routing.Wrap(UpdateOrgQuota))
 		})
@@ -255,8 +265,8 @@ func (hs *HTTPServer) registerRoutes() {
 		// auth api keys
 		apiRoute.Group("/auth/keys", func(keysRoute routing.RouteRegister) {
 			keysRoute.Get("/", routing.Wrap(GetAPIKeys))
-			keysRoute.Post("/", quota("api_key"), bind(models.AddApiKeyCommand{}), routing.Wrap(hs.AddAPIKey))
-			keysRoute.Delete("/:id", routing.Wrap(DeleteAPIKey))
+			keysRoute.Post("/", audit(models.AuditActionAPIKeyCreated, "", ""), quota("api_key"), bind(models.AddApiKeyCommand{}), routing.Wrap(hs.AddAPIKey))
+			keysRoute.Delete("/:id", audit(models.AuditActionAPIKeyDeleted, "api-key", ":id"), routing.Wrap(DeleteAPIKey))
 		}, reqOrgAdmin)
 
 		// Preferences
@@ -267,7 +277,7 @@ func (hs *HTTPServer) registerRoutes() {
 		// Data sources
 		apiRoute.Group("/datasources", func(datasourceRoute routing.RouteRegister) {
 			datasourceRoute.Get("/", routing.Wrap(hs.GetDataSources))
-			datasourceRoute.Post("/", quota("data_source"), bind(models.AddDataSourceCommand{}), routing.Wrap(AddDataSource))
+			datasourceRoute.Post("/", quota("data_source"), bind(models.AddDataSourceCommand{}), routing.Wrap(hs.AddDataSource))
 			datasourceRoute.Put("/:id", bind(models.UpdateDataSourceCommand{}), routing.Wrap(hs.UpdateDataSource))
 			datasourceRoute.Delete("/:id", routing.Wrap(hs.DeleteDataSourceById))
 			datasourceRoute.Delete("/uid/:uid", routing.Wrap(hs.DeleteDataSourceByUID))
@@ -305,28 +315,57 @@ func (hs *HTTPServer) registerRoutes() {
 		apiRoute.Any("/datasources/:id/resources/*", hs.CallDatasourceResource)
 		apiRoute.Any("/datasources/:id/health", routing.Wrap(hs.CheckDatasourceHealth))
 
+		// Access control roles
+		if !hs.AccessControl.IsDisabled() {
+			apiRoute.Group("/access-control", func(acRoute routing.RouteRegister) {
+				const roleUIDScope = `roles:{{ index . ":roleUID" }}`
+				const userIDScope = `users:{{ index . ":userId" }}`
+
+				acRoute.Get("/roles", authorize(reqOrgAdmin, accesscontrol.ActionRolesList), routing.Wrap(hs.GetRoles))
+				acRoute.Post("/roles", authorize(reqOrgAdmin, accesscontrol.ActionRolesWrite), bind(accesscontrol.CreateRoleCommand{}), routing.Wrap(hs.CreateRole))
+				acRoute.Get("/roles/:roleUID", authorize(reqOrgAdmin, accesscontrol.ActionRolesRead, roleUIDScope), routing.Wrap(hs.GetRole))
+				acRoute.Put("/roles/:roleUID", authorize(reqOrgAdmin, accesscontrol.ActionRolesWrite, roleUIDScope), bind(accesscontrol.UpdateRoleCommand{}), routing.Wrap(hs.UpdateRole))
+				acRoute.Delete("/roles/:roleUID", authorize(reqOrgAdmin, accesscontrol.ActionRolesDelete, roleUIDScope), routing.Wrap(hs.DeleteRole))
+
+				acRoute.Get("/builtin-roles", authorize(reqOrgAdmin, accesscontrol.ActionRolesBuiltinList), routing.Wrap(hs.GetBuiltinRoles))
+				acRoute.Post("/builtin-roles", audit(models.AuditActionBuiltinRoleAdded, "", ""), authorize(reqOrgAdmin, accesscontrol.ActionRolesBuiltinAdd), bind(accesscontrol.AddBuiltinRoleCommand{}), routing.Wrap(hs.AddBuiltinRole))
+				acRoute.Delete("/builtin-roles/:builtinRole/roles/:roleUID", audit(models.AuditActionBuiltinRoleRemoved, "role", ":roleUID"), authorize(reqOrgAdmin, accesscontrol.ActionRolesBuiltinRemove, roleUIDScope), routing.Wrap(hs.RemoveBuiltinRole))
+
+				acRoute.Get("/users/:userId/roles", authorize(reqOrgAdmin, accesscontrol.ActionUsersRolesList, userIDScope), routing.Wrap(hs.GetUserRoles))
+				acRoute.Post("/users/:userId/roles", audit(models.AuditActionUserRoleAdded, "user", ":userId"), authorize(reqOrgAdmin, accesscontrol.ActionUsersRolesAdd, userIDScope), bind(accesscontrol.AddUserRoleCommand{}), routing.Wrap(hs.AddUserRole))
+				acRoute.Delete("/users/:userId/roles/:roleUID", audit(models.AuditActionUserRoleRemoved, "user", ":userId"), authorize(reqOrgAdmin, accesscontrol.ActionUsersRolesRemove, userIDScope), routing.Wrap(hs.RemoveUserRole))
+			})
+		}
+
 		// Folders
 		apiRoute.Group("/folders", func(folderRoute routing.RouteRegister) {
+			const folderUIDScope = `folders:uid:{{ index . ":uid" }}`
+
+			// folders are created, updated and deleted according to the folder permissions, checked by the guardian
 			folderRoute.Get("/", routing.Wrap(hs.GetFolders))
 			folderRoute.Get("/id/:id", routing.Wrap(hs.GetFolderByID))
 			folderRoute.Post("/", bind(models.CreateFolderCommand{}), routing.Wrap(hs.CreateFolder))
 
 			folderRoute.Group("/:uid", func(folderUidRoute routing.RouteRegister) {
-				folderUidRoute.Get("/", routing.Wrap(hs.GetFolderByUID))
+				folderUidRoute.Get("/", authorize(reqSignedIn, accesscontrol.ActionFoldersRead, folderUIDScope), routing.Wrap(hs.GetFolderByUID))
 				folderUidRoute.Put("/", bind(models.UpdateFolderCommand{}), routing.Wrap(hs.UpdateFolder))
 				folderUidRoute.Delete("/", routing.Wrap(hs.DeleteFolder))
 
 				folderUidRoute.Group("/permissions", func(folderPermissionRoute routing.RouteRegister) {
 					folderPermissionRoute.Get("/", routing.Wrap(hs.GetFolderPermissionList))
-					folderPermissionRoute.Post("/", bind(dtos.UpdateDashboardAclCommand{}), routing.Wrap(hs.UpdateFolderPermissions))
+					folderPermissionRoute.Post("/", audit(models.AuditActionFolderPermissionsUpdated, "folder", ":uid"), bind(dtos.UpdateDashboardAclCommand{}), routing.Wrap(hs.UpdateFolderPermissions))
 				})
 			})
 		})
 
 		// Dashboard
 		apiRoute.Group("/dashboards", func(dashboardRoute routing.RouteRegister) {
-			dashboardRoute.Get("/uid/:uid", routing.Wrap(hs.GetDashboard))
+			const dashboardUIDScope = `dashboards:uid:{{ index . ":uid" }}`
+
+			dashboardRoute.Get("/uid/:uid", authorize(reqSignedIn, accesscontrol.ActionDashboardsRead, dashboardUIDScope), routing.Wrap(hs.GetDashboard))
 			dashboardRoute.Delete("/uid/:uid", routing.Wrap(hs.DeleteDashboardByUID))
+			dashboardRoute.Get("/uid/:uid/pdf", authorize(reqSignedIn, accesscontrol.ActionDashboardsRead, dashboardUIDScope), hs.RenderDashboardPDF)
+			dashboardRoute.Get("/uid/:uid/panels/:panelId/export", authorize(reqSignedIn, accesscontrol.ActionDashboardsRead, dashboardUIDScope), routing.Wrap(hs.ExportPanelData))
 
 			dashboardRoute.Post("/calculate-diff", bind(dtos.CalculateDiffOptions{}), routing.Wrap(CalculateDashboardDiff))
 			dashboardRoute.Post("/trim", bind(models.TrimDashboardCommand{}), routing.Wrap(hs.TrimDashboard))
@@ -335,6 +374,7 @@ func (hs *HTTPServer) registerRoutes() {
 			dashboardRoute.Get("/home", routing.Wrap(hs.GetHomeDashboard))
 			dashboardRoute.Get("/tags", GetDashboardTags)
 			dashboardRoute.Post("/import", bind(dtos.ImportDashboardCommand{}), routing.Wrap(hs.ImportDashboard))
+			dashboardRoute.Post("/import/archive", bind(dtos.ImportDashboardArchiveCommand{}), routing.Wrap(hs.ImportDashboardArchive))
 
 			dashboardRoute.Group("/id/:dashboardId", func(dashIdRoute routing.RouteRegister) {
 				dashIdRoute.Get("/versions", routing.Wrap(GetDashboardVersions))
@@ -343,7 +383,7 @@ func (hs *HTTPServer) registerRoutes() {
 
 				dashIdRoute.Group("/permissions", func(dashboardPermissionRoute routing.RouteRegister) {
 					dashboardPermissionRoute.Get("/", routing.Wrap(hs.GetDashboardPermissionList))
-					dashboardPermissionRoute.Post("/", bind(dtos.UpdateDashboardAclCommand{}), routing.Wrap(hs.UpdateDashboardPermissions))
+					dashboardPermissionRoute.Post("/", audit(models.AuditActionDashboardPermissionsUpdated, "dashboard", ":dashboardId"), bind(dtos.UpdateDashboardAclCommand{}), routing.Wrap(hs.UpdateDashboardPermissions))
 				})
 			})
 		})
@@ -433,8 +473,32 @@ func (hs *HTTPServer) registerRoutes() {
 			liveRoute.Get("/info/*", routing.Wrap(hs.Live.HandleInfoHTTP))
 		})
 
+		// batch rendering
+		apiRoute.Post("/render/batch", bind(dtos.BatchRenderCommand{}), routing.Wrap(hs.BatchRender))
+
 		// short urls
 		apiRoute.Post("/short-urls", bind(dtos.CreateShortURLCmd{}), routing.Wrap(hs.createShortURL))
+
+		// signed urls
+		apiRoute.Post("/signed-urls", reqSignedInNoAnonymous, bind(dtos.CreateSignedURLCmd{}), routing.Wrap(hs.createSignedURL))
+
+		// resumable uploads
+		apiRoute.Group("/uploads", func(uploadRoute routing.RouteRegister) {
+			uploadRoute.Post("/", bind(dtos.CreateUploadCommand{}), routing.Wrap(hs.CreateUpload))
+			uploadRoute.Get("/:uid", routing.Wrap(hs.GetUpload))
+			uploadRoute.Patch("/:uid", routing.Wrap(hs.AppendUpload))
+			uploadRoute.Delete("/:uid", routing.Wrap(hs.DeleteUpload))
+		}, reqEditorRole)
+
+		// scheduled reports
+		apiRoute.Group("/reports", func(reportsRoute routing.RouteRegister) {
+			reportsRoute.Get("/", routing.Wrap(hs.GetReports))
+			reportsRoute.Post("/", bind(reports.SaveReportCommand{}), routing.Wrap(hs.CreateReport))
+			reportsRoute.Get("/:id", routing.Wrap(hs.GetReport))
+			reportsRoute.Put("/:id", bind(reports.SaveReportCommand{}), routing.Wrap(hs.UpdateReport))
+			reportsRoute.Delete("/:id", routing.Wrap(hs.DeleteReport))
+			reportsRoute.Post("/:id/send", routing.Wrap(hs.SendReport))
+		}, reqEditorRole)
 	}, reqSignedIn)
 
 	// admin api
@@ -442,11 +506,27 @@ func (hs *HTTPServer) registerRoutes() {
 		adminRoute.Get("/settings", authorize(reqGrafanaAdmin, accesscontrol.ActionSettingsRead), routing.Wrap(hs.AdminGetSettings))
 		adminRoute.Get("/stats", authorize(reqGrafanaAdmin, accesscontrol.ActionServerStatsRead), routing.Wrap(AdminGetStats))
 		adminRoute.Post("/pause-all-alerts", reqGrafanaAdmin, bind(dtos.PauseAllAlertsCommand{}), routing.Wrap(PauseAllAlerts))
+		adminRoute.Delete("/lockouts/ip/:ip", authorize(reqGrafanaAdmin, accesscontrol.ActionUsersUnlock, accesscontrol.ScopeGlobalUsersAll), routing.Wrap(hs.AdminUnlockIP))
+		adminRoute.Get("/audit", reqGrafanaAdmin, routing.Wrap(hs.AdminSearchAuditLog))
+		adminRoute.Get("/deprovisioning/report", reqGrafanaAdmin, routing.Wrap(hs.AdminGetDeprovisioningReport))
+		adminRoute.Post("/deprovisioning/run", reqGrafanaAdmin, routing.Wrap(hs.AdminRunDeprovisioning))
 
 		adminRoute.Post("/provisioning/dashboards/reload", authorize(reqGrafanaAdmin, ActionProvisioningReload, ScopeProvisionersDashboards), routing.Wrap(hs.AdminProvisioningReloadDashboards))
 		adminRoute.Post("/provisioning/plugins/reload", authorize(reqGrafanaAdmin, ActionProvisioningReload, ScopeProvisionersPlugins), routing.Wrap(hs.AdminProvisioningReloadPlugins))
 		adminRoute.Post("/provisioning/datasources/reload", authorize(reqGrafanaAdmin, ActionProvisioningReload, ScopeProvisionersDatasources), routing.Wrap(hs.AdminProvisioningReloadDatasources))
 		adminRoute.Post("/provisioning/notifications/reload", authorize(reqGrafanaAdmin, ActionProvisioningReload, ScopeProvisionersNotifications), routing.Wrap(hs.AdminProvisioningReloadNotifications))
+		adminRoute.Post("/provisioning/alerting/reload", authorize(reqGrafanaAdmin, ActionProvisioningReload, ScopeProvisionersAlerting), routing.Wrap(hs.AdminProvisioningReloadAlerting))
+
+		adminRoute.Post("/emails/templates/reload", reqGrafanaAdmin, routing.Wrap(hs.AdminReloadEmailTemplates))
+		adminRoute.Get("/angular-report", reqGrafanaAdmin, routing.Wrap(hs.AdminGetAngularReport))
+		adminRoute.Put("/plugins/:pluginId/log-level", reqGrafanaAdmin, bind(dtos.SetPluginLogLevelCommand{}), routing.Wrap(hs.AdminSetPluginLogLevel))
+
+		if hs.Cfg.PluginAdminEnabled {
+			adminRoute.Get("/plugins", authorize(reqGrafanaAdmin, accesscontrol.ActionPluginsManage), routing.Wrap(hs.AdminGetPlugins))
+			adminRoute.Post("/plugins", authorize(reqGrafanaAdmin, accesscontrol.ActionPluginsManage), bind(dtos.AdminInstallPluginCommand{}), routing.Wrap(hs.AdminInstallPlugin))
+			adminRoute.Put("/plugins/:pluginId", authorize(reqGrafanaAdmin, accesscontrol.ActionPluginsManage), bind(dtos.InstallPluginCommand{}), routing.Wrap(hs.AdminUpdatePlugin))
+			adminRoute.Delete("/plugins/:pluginId", authorize(reqGrafanaAdmin, accesscontrol.ActionPluginsManage), routing.Wrap(hs.UninstallPlugin))
+		}
 
 		adminRoute.Post("/ldap/reload", authorize(reqGrafanaAdmin, accesscontrol.ActionLDAPConfigReload), routing.Wrap(hs.ReloadLDAPCfg))
 		adminRoute.Post("/ldap/sync/:id", authorize(reqGrafanaAdmin, accesscontrol.ActionLDAPUsersSync), routing.Wrap(hs.PostSyncUserWithLDAP))
@@ -458,24 +538,48 @@ func (hs *HTTPServer) registerRoutes() {
 	r.Group("/api/admin/users", func(adminUserRoute routing.RouteRegister) {
 		const userIDScope = `global:users:{{ index . ":id" }}`
 		adminUserRoute.Post("/", authorize(reqGrafanaAdmin, accesscontrol.ActionUsersCreate), bind(dtos.AdminCreateUserForm{}), routing.Wrap(hs.AdminCreateUser))
-		adminUserRoute.Put("/:id/password", authorize(reqGrafanaAdmin, accesscontrol.ActionUsersPasswordUpdate, userIDScope), bind(dtos.AdminUpdateUserPasswordForm{}), routing.Wrap(AdminUpdateUserPassword))
-		adminUserRoute.Put("/:id/permissions", authorize(reqGrafanaAdmin, accesscontrol.ActionUsersPermissionsUpdate, userIDScope), bind(dtos.AdminUpdateUserPermissionsForm{}), routing.Wrap(hs.AdminUpdateUserPermissions))
+		adminUserRoute.Put("/:id/password", authorize(reqGrafanaAdmin, accesscontrol.ActionUsersPasswordUpdate, userIDScope), bind(dtos.AdminUpdateUserPasswordForm{}), routing.Wrap(hs.AdminUpdateUserPassword))
+		adminUserRoute.Put("/:id/permissions", audit(models.AuditActionServerAdminUpdated, "user", ":id"), authorize(reqGrafanaAdmin, accesscontrol.ActionUsersPermissionsUpdate, userIDScope), bind(dtos.AdminUpdateUserPermissionsForm{}), routing.Wrap(hs.AdminUpdateUserPermissions))
 		adminUserRoute.Delete("/:id", authorize(reqGrafanaAdmin, accesscontrol.ActionUsersDelete, userIDScope), routing.Wrap(AdminDeleteUser))
 		adminUserRoute.Post("/:id/disable", authorize(reqGrafanaAdmin, accesscontrol.ActionUsersDisable, userIDScope), routing.Wrap(hs.AdminDisableUser))
 		adminUserRoute.Post("/:id/enable", authorize(reqGrafanaAdmin, accesscontrol.ActionUsersEnable, userIDScope), routing.Wrap(AdminEnableUser))
 		adminUserRoute.Get("/:id/quotas", authorize(reqGrafanaAdmin, accesscontrol.ActionUsersQuotasList, userIDScope), routing.Wrap(GetUserQuotas))
 		adminUserRoute.Put("/:id/quotas/:target", authorize(reqGrafanaAdmin, accesscontrol.ActionUsersQuotasUpdate, userIDScope), bind(models.UpdateUserQuotaCmd{}), routing.Wrap(UpdateUserQuota))
 
-		adminUserRoute.Post("/:id/logout", authorize(reqGrafanaAdmin, accesscontrol.ActionUsersLogout, userIDScope), routing.Wrap(hs.AdminLogoutUser))
+		adminUserRoute.Post("/:id/logout", audit(models.AuditActionAuthTokensRevoked, "user", ":id"), authorize(reqGrafanaAdmin, accesscontrol.ActionUsersLogout, userIDScope), routing.Wrap(hs.AdminLogoutUser))
+		adminUserRoute.Get("/:id/lockout", authorize(reqGrafanaAdmin, accesscontrol.ActionUsersLockoutRead, userIDScope), routing.Wrap(hs.AdminGetUserLockout))
+		adminUserRoute.Delete("/:id/lockout", authorize(reqGrafanaAdmin, accesscontrol.ActionUsersUnlock, userIDScope), routing.Wrap(hs.AdminUnlockUser))
 		adminUserRoute.Get("/:id/auth-tokens", authorize(reqGrafanaAdmin, accesscontrol.ActionUsersAuthTokenList, userIDScope), routing.Wrap(hs.AdminGetUserAuthTokens))
-		adminUserRoute.Post("/:id/revoke-auth-token", authorize(reqGrafanaAdmin, accesscontrol.ActionUsersAuthTokenUpdate, userIDScope), bind(models.RevokeAuthTokenCmd{}), routing.Wrap(hs.AdminRevokeUserAuthToken))
+		adminUserRoute.Post("/:id/impersonate", audit(models.AuditActionImpersonationStarted, "user", ":id"), authorize(reqGrafanaAdmin, accesscontrol.ActionUsersImpersonate, userIDScope), routing.Wrap(hs.AdminImpersonateUser))
+		adminUserRoute.Post("/:id/revoke-auth-token", audit(models.AuditActionAuthTokenRevoked, "user", ":id"), authorize(reqGrafanaAdmin, accesscontrol.ActionUsersAuthTokenUpdate, userIDScope), bind(models.RevokeAuthTokenCmd{}), routing.Wrap(hs.AdminRevokeUserAuthToken))
 	})
 
+	// SCIM provisioning, authenticated with an API key of the provisioned organization
+	if hs.Cfg.SCIMEnabled {
+		r.Group("/scim/v2", func(scimRoute routing.RouteRegister) {
+			scimRoute.Get("/ServiceProviderConfig", routing.Wrap(hs.GetSCIMServiceProviderConfig))
+
+			scimRoute.Get("/Users", routing.Wrap(hs.GetSCIMUsers))
+			scimRoute.Post("/Users", bind(dtos.SCIMUser{}), routing.Wrap(hs.CreateSCIMUser))
+			scimRoute.Get("/Users/:id", routing.Wrap(hs.GetSCIMUser))
+			scimRoute.Put("/Users/:id", bind(dtos.SCIMUser{}), routing.Wrap(hs.ReplaceSCIMUser))
+			scimRoute.Patch("/Users/:id", bind(dtos.SCIMPatchOp{}), routing.Wrap(hs.PatchSCIMUser))
+			scimRoute.Delete("/Users/:id", routing.Wrap(hs.DeleteSCIMUser))
+
+			scimRoute.Get("/Groups", routing.Wrap(hs.GetSCIMGroups))
+			scimRoute.Post("/Groups", bind(dtos.SCIMGroup{}), routing.Wrap(hs.CreateSCIMGroup))
+			scimRoute.Get("/Groups/:id", routing.Wrap(hs.GetSCIMGroup))
+			scimRoute.Put("/Groups/:id", bind(dtos.SCIMGroup{}), routing.Wrap(hs.ReplaceSCIMGroup))
+			scimRoute.Patch("/Groups/:id", bind(dtos.SCIMPatchOp{}), routing.Wrap(hs.PatchSCIMGroup))
+			scimRoute.Delete("/Groups/:id", routing.Wrap(hs.DeleteSCIMGroup))
+		}, reqOrgAdmin)
+	}
+
 	// rendering
 	r.Get("/render/*", reqSignedIn, hs.RenderToPng)
 
 	// grafana.net proxy
-	r.Any("/api/gnet/*", reqSignedIn, ProxyGnetRequest)
+	r.Any("/api/gnet/*", reqSignedIn, hs.ProxyGnetRequest)
 
 	// Gravatar service.
 	avatarCacheServer := avatar.NewCacheServer(hs.Cfg)
@@ -18,11 +18,14 @@ import (
 	"github.com/grafana/grafana/pkg/infra/remotecache"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/registry"
+	"github.com/grafana/grafana/pkg/services/audit"
 	"github.com/grafana/grafana/pkg/services/auth"
 	"github.com/grafana/grafana/pkg/services/auth/jwt"
+	"github.com/grafana/grafana/pkg/services/auth/kerberos"
 	"github.com/grafana/grafana/pkg/services/contexthandler"
 	"github.com/grafana/grafana/pkg/services/rendering"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/urlsigner"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/macaron.v1"
@@ -170,6 +173,7 @@ func getContextHandler(t *testing.T, cfg *setting.Cfg) *contexthandler.ContextHa
 	userAuthTokenSvc := auth.NewFakeUserAuthTokenService()
 	renderSvc := &fakeRenderService{}
 	authJWTSvc := models.NewFakeJWTService()
+	authKerberosSvc := models.NewFakeKerberosService()
 	ctxHdlr := &contexthandler.ContextHandler{}
 
 	err := registry.BuildServiceGraph([]interface{}{cfg}, []*registry.Descriptor{
@@ -193,6 +197,14 @@ func getContextHandler(t *testing.T, cfg *setting.Cfg) *contexthandler.ContextHa
 			Name:     jwt.ServiceName,
 			Instance: authJWTSvc,
 		},
+		{
+			Name:     kerberos.ServiceName,
+			Instance: authKerberosSvc,
+		},
+		{
+			Name:     urlsigner.ServiceName,
+			Instance: &urlsigner.Service{},
+		},
 		{
 			Name:     contexthandler.ServiceName,
 			Instance: ctxHdlr,
@@ -262,6 +274,7 @@ func setupAccessControlScenarioContext(t *testing.T, cfg *setting.Cfg, url strin
 		Cfg:           cfg,
 		RouteRegister: routing.NewRouteRegister(),
 		AccessControl: &fakeAccessControl{permissions: permissions},
+		AuditService:  &audit.Service{Cfg: cfg},
 	}
 
 	sc := setupScenarioContext(t, url)
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/grafana/grafana/pkg/api/datasource"
 	"github.com/grafana/grafana/pkg/api/dtos"
@@ -207,13 +208,29 @@ func validateURL(tp string, u string) response.Response {
 	return nil
 }
 
-func AddDataSource(c *models.ReqContext, cmd models.AddDataSourceCommand) response.Response {
+func (hs *HTTPServer) AddDataSource(c *models.ReqContext, cmd models.AddDataSourceCommand) response.Response {
 	datasourcesLogger.Debug("Received command to add data source", "url", cmd.Url)
 	cmd.OrgId = c.OrgId
 	if resp := validateURL(cmd.Type, cmd.Url); resp != nil {
 		return resp
 	}
 
+	candidate := &models.DataSource{
+		OrgId:         cmd.OrgId,
+		Name:          cmd.Name,
+		Type:          cmd.Type,
+		Url:           cmd.Url,
+		Database:      cmd.Database,
+		User:          cmd.User,
+		BasicAuth:     cmd.BasicAuth,
+		BasicAuthUser: cmd.BasicAuthUser,
+		JsonData:      cmd.JsonData,
+		Uid:           cmd.Uid,
+	}
+	if resp := hs.validateDataSourceSettings(c, candidate, cmd.SecureJsonData); resp != nil {
+		return resp
+	}
+
 	if err := bus.Dispatch(&cmd); err != nil {
 		if errors.Is(err, models.ErrDataSourceNameExists) || errors.Is(err, models.ErrDataSourceUidExists) {
 			return response.Error(409, err.Error(), err)
@@ -244,6 +261,23 @@ func (hs *HTTPServer) UpdateDataSource(c *models.ReqContext, cmd models.UpdateDa
 		return response.Error(500, "Failed to update datasource", err)
 	}
 
+	candidate := &models.DataSource{
+		Id:            cmd.Id,
+		OrgId:         cmd.OrgId,
+		Name:          cmd.Name,
+		Type:          cmd.Type,
+		Url:           cmd.Url,
+		Database:      cmd.Database,
+		User:          cmd.User,
+		BasicAuth:     cmd.BasicAuth,
+		BasicAuthUser: cmd.BasicAuthUser,
+		JsonData:      cmd.JsonData,
+		Uid:           cmd.Uid,
+	}
+	if resp := hs.validateDataSourceSettings(c, candidate, cmd.SecureJsonData); resp != nil {
+		return resp
+	}
+
 	err = bus.Dispatch(&cmd)
 	if err != nil {
 		if errors.Is(err, models.ErrDataSourceUpdatingOldVersion) {
@@ -276,6 +310,42 @@ func (hs *HTTPServer) UpdateDataSource(c *models.ReqContext, cmd models.UpdateDa
 	})
 }
 
+// validateDataSourceSettings validates the settings of a data source of a
+// backend plugin with the plugin, before they are saved.
+func (hs *HTTPServer) validateDataSourceSettings(c *models.ReqContext, ds *models.DataSource, secureJSONData map[string]string) response.Response {
+	plugin := hs.PluginManager.GetDataSource(ds.Type)
+	if plugin == nil || !plugin.Backend {
+		return nil
+	}
+
+	jsonData := []byte("{}")
+	if ds.JsonData != nil {
+		var err error
+		if jsonData, err = ds.JsonData.MarshalJSON(); err != nil {
+			return response.Error(400, "Invalid data source settings", err)
+		}
+	}
+
+	return hs.validatePluginSettings(c, backend.PluginContext{
+		OrgID:    c.OrgId,
+		PluginID: plugin.Id,
+		User:     adapters.BackendUserFromSignedInUser(c.SignedInUser),
+		DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+			ID:                      ds.Id,
+			Name:                    ds.Name,
+			URL:                     ds.Url,
+			UID:                     ds.Uid,
+			Database:                ds.Database,
+			User:                    ds.User,
+			BasicAuthEnabled:        ds.BasicAuth,
+			BasicAuthUser:           ds.BasicAuthUser,
+			JSONData:                jsonData,
+			DecryptedSecureJSONData: secureJSONData,
+			Updated:                 time.Now(),
+		},
+	})
+}
+
 func fillWithSecureJSONData(cmd *models.UpdateDataSourceCommand) error {
 	if len(cmd.SecureJsonData) == 0 {
 		return nil
@@ -1,12 +1,16 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/backendplugin"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -72,9 +76,10 @@ func TestAddDataSource_InvalidURL(t *testing.T) {
 	defer bus.ClearBusHandlers()
 
 	sc := setupScenarioContext(t, "/api/datasources")
+	hs := &HTTPServer{PluginManager: &fakePluginManager{}}
 
 	sc.m.Post(sc.url, routing.Wrap(func(c *models.ReqContext) response.Response {
-		return AddDataSource(c, models.AddDataSourceCommand{
+		return hs.AddDataSource(c, models.AddDataSourceCommand{
 			Name: "Test",
 			Url:  "invalid:url",
 		})
@@ -102,9 +107,10 @@ func TestAddDataSource_URLWithoutProtocol(t *testing.T) {
 	})
 
 	sc := setupScenarioContext(t, "/api/datasources")
+	hs := &HTTPServer{PluginManager: &fakePluginManager{}}
 
 	sc.m.Post(sc.url, routing.Wrap(func(c *models.ReqContext) response.Response {
-		return AddDataSource(c, models.AddDataSourceCommand{
+		return hs.AddDataSource(c, models.AddDataSourceCommand{
 			Name: name,
 			Url:  url,
 		})
@@ -120,9 +126,10 @@ func TestUpdateDataSource_InvalidURL(t *testing.T) {
 	defer bus.ClearBusHandlers()
 
 	sc := setupScenarioContext(t, "/api/datasources/1234")
+	hs := &HTTPServer{PluginManager: &fakePluginManager{}}
 
 	sc.m.Put(sc.url, routing.Wrap(func(c *models.ReqContext) response.Response {
-		return AddDataSource(c, models.AddDataSourceCommand{
+		return hs.AddDataSource(c, models.AddDataSourceCommand{
 			Name: "Test",
 			Url:  "invalid:url",
 		})
@@ -150,9 +157,10 @@ func TestUpdateDataSource_URLWithoutProtocol(t *testing.T) {
 	})
 
 	sc := setupScenarioContext(t, "/api/datasources/1234")
+	hs := &HTTPServer{PluginManager: &fakePluginManager{}}
 
 	sc.m.Put(sc.url, routing.Wrap(func(c *models.ReqContext) response.Response {
-		return AddDataSource(c, models.AddDataSourceCommand{
+		return hs.AddDataSource(c, models.AddDataSourceCommand{
 			Name: name,
 			Url:  url,
 		})
@@ -162,3 +170,52 @@ func TestUpdateDataSource_URLWithoutProtocol(t *testing.T) {
 
 	assert.Equal(t, 200, sc.resp.Code)
 }
+
+// Adding data sources with settings rejected by their backend plugin should lead to an error.
+func TestAddDataSource_InvalidPluginSettings(t *testing.T) {
+	defer bus.ClearBusHandlers()
+
+	bus.AddHandler("sql", func(cmd *models.AddDataSourceCommand) error {
+		cmd.Result = &models.DataSource{}
+		return nil
+	})
+
+	sc := setupScenarioContext(t, "/api/datasources")
+
+	hs := &HTTPServer{
+		PluginManager: &fakePluginManager{dataSources: map[string]*plugins.DataSourcePlugin{
+			"test-datasource": {FrontendPluginBase: plugins.FrontendPluginBase{PluginBase: plugins.PluginBase{Id: "test-datasource"}}, Backend: true},
+		}},
+		BackendPluginManager: &fakeSettingsValidator{},
+	}
+	sc.m.Post(sc.url, routing.Wrap(func(c *models.ReqContext) response.Response {
+		return hs.AddDataSource(c, models.AddDataSourceCommand{
+			Name:           "Test",
+			Type:           "test-datasource",
+			SecureJsonData: map[string]string{"apiKey": "expired"},
+		})
+	}))
+
+	sc.fakeReqWithParams("POST", sc.url, map[string]string{}).exec()
+
+	require.Equal(t, 400, sc.resp.Code)
+	require.JSONEq(t, `{"message": "Invalid plugin settings: the API key is invalid", "fields": {"secureJsonData.apiKey": "invalid"}}`, sc.resp.Body.String())
+}
+
+type fakeSettingsValidator struct {
+	backendplugin.Manager
+}
+
+func (m *fakeSettingsValidator) IsRegistered(pluginID string) bool {
+	return true
+}
+
+func (m *fakeSettingsValidator) ValidateSettings(ctx context.Context, pCtx backend.PluginContext) (*backendplugin.ValidateSettingsResult, error) {
+	if pCtx.DataSourceInstanceSettings.DecryptedSecureJSONData["apiKey"] == "valid" {
+		return &backendplugin.ValidateSettingsResult{Valid: true}, nil
+	}
+	return &backendplugin.ValidateSettingsResult{
+		Message: "the API key is invalid",
+		Fields:  map[string]string{"secureJsonData.apiKey": "invalid"},
+	}, nil
+}
@@ -24,6 +24,19 @@ type LoginCommand struct {
 	Remember bool   `json:"remember"`
 }
 
+type DeviceAuthorization struct {
+	DeviceCode              string `json:"deviceCode"`
+	UserCode                string `json:"userCode"`
+	VerificationURI         string `json:"verificationUri"`
+	VerificationURIComplete string `json:"verificationUriComplete,omitempty"`
+	ExpiresIn               int    `json:"expiresIn"`
+	Interval                int    `json:"interval"`
+}
+
+type DeviceTokenCommand struct {
+	DeviceCode string `json:"deviceCode" binding:"Required"`
+}
+
 type CurrentUser struct {
 	IsSignedIn                 bool               `json:"isSignedIn"`
 	Id                         int64              `json:"id"`
@@ -42,6 +55,7 @@ type CurrentUser struct {
 	HelpFlags1                 models.HelpFlags1  `json:"helpFlags1"`
 	HasEditPermissionInFolders bool               `json:"hasEditPermissionInFolders"`
 	Permissions                UserPermissionsMap `json:"permissions,omitempty"`
+	ImpersonatedBy             string             `json:"impersonatedBy,omitempty"`
 }
 
 type UserPermissionsMap map[string]bool
@@ -3,6 +3,7 @@ package dtos
 import (
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/backendplugin"
 )
 
 type PluginSetting struct {
@@ -21,27 +22,31 @@ type PluginSetting struct {
 
 	LatestVersion string                        `json:"latestVersion"`
 	HasUpdate     bool                          `json:"hasUpdate"`
+	PinnedVersion string                        `json:"pinnedVersion,omitempty"`
 	State         plugins.PluginState           `json:"state"`
 	Signature     plugins.PluginSignatureStatus `json:"signature"`
 	SignatureType plugins.PluginSignatureType   `json:"signatureType"`
 	SignatureOrg  string                        `json:"signatureOrg"`
+	BackendStatus *backendplugin.PluginStatus   `json:"backendStatus,omitempty"`
 }
 
 type PluginListItem struct {
-	Name          string                        `json:"name"`
-	Type          string                        `json:"type"`
-	Id            string                        `json:"id"`
-	Enabled       bool                          `json:"enabled"`
-	Pinned        bool                          `json:"pinned"`
-	Info          *plugins.PluginInfo           `json:"info"`
-	LatestVersion string                        `json:"latestVersion"`
-	HasUpdate     bool                          `json:"hasUpdate"`
-	DefaultNavUrl string                        `json:"defaultNavUrl"`
-	Category      string                        `json:"category"`
-	State         plugins.PluginState           `json:"state"`
-	Signature     plugins.PluginSignatureStatus `json:"signature"`
-	SignatureType plugins.PluginSignatureType   `json:"signatureType"`
-	SignatureOrg  string                        `json:"signatureOrg"`
+	Name            string                        `json:"name"`
+	Type            string                        `json:"type"`
+	Id              string                        `json:"id"`
+	Enabled         bool                          `json:"enabled"`
+	Pinned          bool                          `json:"pinned"`
+	Info            *plugins.PluginInfo           `json:"info"`
+	LatestVersion   string                        `json:"latestVersion"`
+	HasUpdate       bool                          `json:"hasUpdate"`
+	PinnedVersion   string                        `json:"pinnedVersion,omitempty"`
+	DefaultNavUrl   string                        `json:"defaultNavUrl"`
+	Category        string                        `json:"category"`
+	State           plugins.PluginState           `json:"state"`
+	Signature       plugins.PluginSignatureStatus `json:"signature"`
+	SignatureType   plugins.PluginSignatureType   `json:"signatureType"`
+	SignatureOrg    string                        `json:"signatureOrg"`
+	AngularDetected bool                          `json:"angularDetected"`
 }
 
 type PluginList []PluginListItem
@@ -69,5 +74,51 @@ type ImportDashboardCommand struct {
 }
 
 type InstallPluginCommand struct {
+	Version   string `json:"version"`
+	UploadUID string `json:"uploadUid"`
+}
+
+type AdminPlugin struct {
+	Id            string                        `json:"id"`
+	Name          string                        `json:"name"`
+	Type          string                        `json:"type"`
+	Version       string                        `json:"version"`
+	Signature     plugins.PluginSignatureStatus `json:"signature"`
+	LatestVersion string                        `json:"latestVersion,omitempty"`
+	HasUpdate     bool                          `json:"hasUpdate"`
+	PinnedVersion string                        `json:"pinnedVersion,omitempty"`
+	BackendStatus *backendplugin.PluginStatus   `json:"backendStatus,omitempty"`
+	LogLevel      string                        `json:"logLevel,omitempty"`
+}
+
+type SetPluginLogLevelCommand struct {
+	Level string `json:"level"`
+}
+
+type AdminInstallPluginCommand struct {
+	PluginId  string `json:"id" binding:"Required"`
+	Version   string `json:"version"`
+	UploadUID string `json:"uploadUid"`
+}
+
+// AngularReport lists the installed plugins that use AngularJS, and the
+// dashboards that use them.
+type AngularReport struct {
+	Plugins    []AngularPlugin    `json:"plugins"`
+	Dashboards []AngularDashboard `json:"dashboards"`
+}
+
+type AngularPlugin struct {
+	Id      string `json:"id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
 	Version string `json:"version"`
 }
+
+type AngularDashboard struct {
+	OrgId   int64    `json:"orgId"`
+	Uid     string   `json:"uid"`
+	Title   string   `json:"title"`
+	Url     string   `json:"url"`
+	Plugins []string `json:"plugins"`
+}
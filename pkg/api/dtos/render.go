@@ -0,0 +1,30 @@
+package dtos
+
+type BatchRenderCommand struct {
+	Panels []BatchRenderPanel `json:"panels"`
+	// Timeout of the rendering of each panel, in seconds.
+	Timeout int `json:"timeout"`
+	// Upload uploads the images to the external image storage instead of
+	// returning them in a zip archive.
+	Upload bool `json:"upload"`
+}
+
+type BatchRenderPanel struct {
+	// Name of the image, without extension. Defaults to <dashboardUid>-<panelId>.
+	Name         string              `json:"name"`
+	DashboardUID string              `json:"dashboardUid"`
+	PanelID      int64               `json:"panelId"`
+	Width        int                 `json:"width"`
+	Height       int                 `json:"height"`
+	Scale        float64             `json:"scale"`
+	From         string              `json:"from"`
+	To           string              `json:"to"`
+	Timezone     string              `json:"tz"`
+	Vars         map[string][]string `json:"vars"`
+}
+
+type BatchRenderResult struct {
+	Name  string `json:"name"`
+	URL   string `json:"url,omitempty"`
+	Error string `json:"error,omitempty"`
+}
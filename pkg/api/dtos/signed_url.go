@@ -0,0 +1,10 @@
+package dtos
+
+type SignedURL struct {
+	URL string `json:"url"`
+}
+
+type CreateSignedURLCmd struct {
+	Path string `json:"path"`
+	TTL  string `json:"ttl"`
+}
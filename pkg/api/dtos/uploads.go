@@ -0,0 +1,25 @@
+package dtos
+
+type Upload struct {
+	UID    string `json:"uid"`
+	Size   int64  `json:"size"`
+	Offset int64  `json:"offset"`
+}
+
+type CreateUploadCommand struct {
+	Size int64 `json:"size"`
+}
+
+type ImportDashboardArchiveCommand struct {
+	UploadUID string `json:"uploadUid"`
+	Overwrite bool   `json:"overwrite"`
+	FolderId  int64  `json:"folderId"`
+}
+
+type ImportDashboardArchiveResult struct {
+	Path    string `json:"path"`
+	UID     string `json:"uid,omitempty"`
+	Title   string `json:"title,omitempty"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
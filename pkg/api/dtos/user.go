@@ -1,5 +1,7 @@
 package dtos
 
+import "time"
+
 type SignUpForm struct {
 	Email string `json:"email" binding:"Required"`
 }
@@ -44,3 +46,10 @@ type UserLookupDTO struct {
 	Login     string `json:"login"`
 	AvatarURL string `json:"avatarUrl"`
 }
+
+type UserLockout struct {
+	Locked         bool       `json:"locked"`
+	LockedUntil    *time.Time `json:"lockedUntil,omitempty"`
+	FailedAttempts int64      `json:"failedAttempts"`
+	Lockouts       int        `json:"lockouts"`
+}
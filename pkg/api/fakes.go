@@ -6,6 +6,7 @@ type fakePluginManager struct {
 	plugins.Manager
 
 	staticRoutes []*plugins.PluginStaticRoute
+	dataSources  map[string]*plugins.DataSourcePlugin
 }
 
 func (pm *fakePluginManager) GetPlugin(id string) *plugins.PluginBase {
@@ -13,7 +14,7 @@ func (pm *fakePluginManager) GetPlugin(id string) *plugins.PluginBase {
 }
 
 func (pm *fakePluginManager) GetDataSource(id string) *plugins.DataSourcePlugin {
-	return nil
+	return pm.dataSources[id]
 }
 
 func (pm *fakePluginManager) Renderer() *plugins.RendererPlugin {
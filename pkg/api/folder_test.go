@@ -3,6 +3,9 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
@@ -11,7 +14,9 @@ import (
 	"github.com/grafana/grafana/pkg/bus"
 	dboards "github.com/grafana/grafana/pkg/dashboards"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/guardian"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -135,6 +140,49 @@ func callCreateFolder(sc *scenarioContext) {
 	sc.fakeReqWithParams("POST", sc.url, map[string]string{}).exec()
 }
 
+func TestFoldersAPIEndpoint_AccessControl(t *testing.T) {
+	// the permissions of the Viewer role
+	viewerPermissions := []*accesscontrol.Permission{
+		{Action: accesscontrol.ActionDashboardsRead, Scope: accesscontrol.ScopeDashboardsAll},
+		{Action: accesscontrol.ActionFoldersRead, Scope: accesscontrol.ScopeFoldersAll},
+	}
+
+	t.Run("Viewer granted Edit on a folder can update it", func(t *testing.T) {
+		sc, _ := setupAccessControlScenarioContext(t, setting.NewCfg(), "/api/folders/uid", viewerPermissions)
+		sc.m.Use(func(c *models.ReqContext) {
+			c.SignedInUser = &models.SignedInUser{UserId: testUserID, OrgId: testOrgID, OrgRole: models.ROLE_VIEWER}
+			c.IsSignedIn = true
+		})
+
+		// the folder service lets the user update the folder, as the guardian does for the folder permissions
+		origNewFolderService := dashboards.NewFolderService
+		t.Cleanup(func() {
+			dashboards.NewFolderService = origNewFolderService
+		})
+		mockFolderService(&fakeFolderService{
+			UpdateFolderResult: &models.Folder{Id: 1, Uid: "uid", Title: "Folder upd"},
+		})
+		origNewGuardian := guardian.New
+		t.Cleanup(func() {
+			guardian.New = origNewGuardian
+		})
+		guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanSaveValue: true, CanEditValue: true})
+
+		sc.resp = httptest.NewRecorder()
+		var err error
+		sc.req, err = http.NewRequest(http.MethodPut, "/api/folders/uid", strings.NewReader(`{"title": "Folder upd"}`))
+		require.NoError(t, err)
+		sc.req.Header.Set("Content-Type", "application/json")
+		sc.exec()
+
+		require.Equal(t, http.StatusOK, sc.resp.Code)
+		folder := dtos.Folder{}
+		require.NoError(t, json.NewDecoder(sc.resp.Body).Decode(&folder))
+		assert.Equal(t, "Folder upd", folder.Title)
+		assert.True(t, folder.CanSave)
+	})
+}
+
 func createFolderScenario(t *testing.T, desc string, url string, routePattern string, mock *fakeFolderService,
 	cmd models.CreateFolderCommand, fn scenarioFunc) {
 	t.Run(fmt.Sprintf("%s %s", desc, url), func(t *testing.T) {
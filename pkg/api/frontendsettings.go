@@ -131,7 +131,14 @@ func (hs *HTTPServer) getFrontendSettingsMap(c *models.ReqContext) (map[string]i
 	}
 
 	pluginsToPreload := []string{}
+	pluginModuleIntegrity := map[string]string{}
+	addModuleIntegrity := func(plugin *plugins.PluginBase) {
+		if plugin.ModuleIntegrity != "" {
+			pluginModuleIntegrity[plugin.Module] = plugin.ModuleIntegrity
+		}
+	}
 	for _, app := range enabledPlugins.Apps {
+		addModuleIntegrity(&app.PluginBase)
 		if app.Preload {
 			pluginsToPreload = append(pluginsToPreload, app.Module)
 		}
@@ -150,6 +157,7 @@ func (hs *HTTPServer) getFrontendSettingsMap(c *models.ReqContext) (map[string]i
 		}
 
 		meta := dsM["meta"].(*plugins.DataSourcePlugin)
+		addModuleIntegrity(&meta.PluginBase)
 		if meta.Preload {
 			pluginsToPreload = append(pluginsToPreload, meta.Module)
 		}
@@ -161,6 +169,7 @@ func (hs *HTTPServer) getFrontendSettingsMap(c *models.ReqContext) (map[string]i
 			continue
 		}
 
+		addModuleIntegrity(&panel.PluginBase)
 		if panel.Preload {
 			pluginsToPreload = append(pluginsToPreload, panel.Module)
 		}
@@ -223,6 +232,7 @@ func (hs *HTTPServer) getFrontendSettingsMap(c *models.ReqContext) (map[string]i
 		"editorsCanAdmin":            hs.Cfg.EditorsCanAdmin,
 		"disableSanitizeHtml":        hs.Cfg.DisableSanitizeHtml,
 		"pluginsToPreload":           pluginsToPreload,
+		"pluginModuleIntegrity":      pluginModuleIntegrity,
 		"buildInfo": map[string]interface{}{
 			"hideVersion":   hideVersion,
 			"version":       version,
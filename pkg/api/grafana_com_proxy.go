@@ -1,17 +1,45 @@
 package api
 
 import (
+	"bytes"
+	"errors"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"regexp"
+	"strconv"
 	"time"
 
+	"github.com/grafana/grafana/pkg/infra/remotecache"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
 
+const (
+	gnetDashboardCacheKeyPrefix = "gnet-dashboard:"
+	// gnetDashboardCacheTTL is how long the dashboards downloaded from grafana.com
+	// are cached, which is shared by all Grafana instances of a cluster.
+	gnetDashboardCacheTTL = time.Hour
+)
+
+// reGnetDashboard matches the paths of the dashboards of grafana.com and of
+// the downloads of their revisions.
+var reGnetDashboard = regexp.MustCompile(`^dashboards/\d+(/revisions/\d+/download)?$`)
+
+func init() {
+	remotecache.Register(&GnetDashboardResponse{})
+}
+
+// GnetDashboardResponse is a dashboard downloaded from grafana.com, as cached
+// in the remote cache.
+type GnetDashboardResponse struct {
+	ContentType string
+	Body        []byte
+}
+
 var grafanaComProxyTransport = &http.Transport{
 	Proxy: http.ProxyFromEnvironment,
 	Dial: (&net.Dialer{
@@ -40,10 +68,56 @@ func ReverseProxyGnetReq(proxyPath string) *httputil.ReverseProxy {
 	return &httputil.ReverseProxy{Director: director}
 }
 
-func ProxyGnetRequest(c *models.ReqContext) {
+func (hs *HTTPServer) ProxyGnetRequest(c *models.ReqContext) {
 	proxyPath := c.Params("*")
 	proxy := ReverseProxyGnetReq(proxyPath)
 	proxy.Transport = grafanaComProxyTransport
+
+	if c.Req.Method == http.MethodGet && reGnetDashboard.MatchString(proxyPath) {
+		cacheKey := gnetDashboardCacheKeyPrefix + proxyPath
+		if cached, err := hs.RemoteCacheService.Get(cacheKey); err == nil {
+			if dashboard, ok := cached.(*GnetDashboardResponse); ok {
+				c.Resp.Header().Set("Content-Type", dashboard.ContentType)
+				c.Resp.Header().Set("Content-Length", strconv.Itoa(len(dashboard.Body)))
+				c.Resp.WriteHeader(http.StatusOK)
+				if _, err := c.Resp.Write(dashboard.Body); err != nil {
+					hs.log.Debug("Failed to write cached grafana.com dashboard", "path", proxyPath, "err", err)
+				}
+				return
+			}
+		} else if !errors.Is(err, remotecache.ErrCacheItemNotFound) {
+			hs.log.Warn("Failed to get grafana.com dashboard from cache", "path", proxyPath, "err", err)
+		}
+
+		// the transport decompresses the responses itself, which are cached uncompressed
+		c.Req.Request.Header.Del("Accept-Encoding")
+		proxy.ModifyResponse = func(res *http.Response) error {
+			return hs.cacheGnetDashboard(cacheKey, res)
+		}
+	}
+
 	proxy.ServeHTTP(c.Resp, c.Req.Request)
 	c.Resp.Header().Del("Set-Cookie")
 }
+
+// cacheGnetDashboard caches a successful response of grafana.com with a dashboard.
+func (hs *HTTPServer) cacheGnetDashboard(cacheKey string, res *http.Response) error {
+	if res.StatusCode != http.StatusOK || res.Header.Get("Content-Encoding") != "" {
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if err := res.Body.Close(); err != nil {
+		return err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	dashboard := &GnetDashboardResponse{ContentType: res.Header.Get("Content-Type"), Body: body}
+	if err := hs.RemoteCacheService.Set(cacheKey, dashboard, gnetDashboardCacheTTL); err != nil {
+		hs.log.Warn("Failed to cache grafana.com dashboard", "key", cacheKey, "err", err)
+	}
+	return nil
+}
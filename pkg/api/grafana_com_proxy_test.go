@@ -0,0 +1,66 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxyGnetRequest(t *testing.T) {
+	requests := map[string]int{}
+	gnet := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		if r.URL.Path == "/api/dashboards/2" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	}))
+	t.Cleanup(gnet.Close)
+
+	grafanaComURL := setting.GrafanaComUrl
+	setting.GrafanaComUrl = gnet.URL
+	t.Cleanup(func() { setting.GrafanaComUrl = grafanaComURL })
+
+	hs := &HTTPServer{RemoteCacheService: remotecache.NewFakeStore(t), log: log.New("test")}
+	sc := setupScenarioContext(t, "/api/gnet/")
+	sc.m.Get("/api/gnet/*", hs.ProxyGnetRequest)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		sc.fakeReq("GET", "/api/gnet/"+path).exec()
+		return sc.resp
+	}
+
+	t.Run("Should cache dashboards", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			resp := get("dashboards/1")
+			require.Equal(t, http.StatusOK, resp.Code)
+			assert.Equal(t, `{"id": 1}`, resp.Body.String())
+			assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+		}
+		assert.Equal(t, 1, requests["/api/dashboards/1"])
+
+		get("dashboards/1/revisions/3/download")
+		get("dashboards/1/revisions/3/download")
+		assert.Equal(t, 1, requests["/api/dashboards/1/revisions/3/download"])
+	})
+
+	t.Run("Should not cache failed requests", func(t *testing.T) {
+		require.Equal(t, http.StatusNotFound, get("dashboards/2").Code)
+		require.Equal(t, http.StatusNotFound, get("dashboards/2").Code)
+		assert.Equal(t, 2, requests["/api/dashboards/2"])
+	})
+
+	t.Run("Should not cache other requests", func(t *testing.T) {
+		get("dashboards")
+		get("dashboards")
+		assert.Equal(t, 2, requests["/api/dashboards"])
+	})
+}
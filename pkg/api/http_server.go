@@ -13,6 +13,8 @@ import (
 	"strings"
 	"sync"
 
+	loginpkg "github.com/grafana/grafana/pkg/login"
+	"github.com/grafana/grafana/pkg/login/saml"
 	"github.com/grafana/grafana/pkg/login/social"
 	"github.com/grafana/grafana/pkg/services/libraryelements"
 	"github.com/grafana/grafana/pkg/services/librarypanels"
@@ -34,10 +36,14 @@ import (
 	"github.com/grafana/grafana/pkg/plugins/plugindashboards"
 	"github.com/grafana/grafana/pkg/registry"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	acdatabase "github.com/grafana/grafana/pkg/services/accesscontrol/database"
 	"github.com/grafana/grafana/pkg/services/alerting"
+	"github.com/grafana/grafana/pkg/services/audit"
+	"github.com/grafana/grafana/pkg/services/auth/clientcert"
 	"github.com/grafana/grafana/pkg/services/contexthandler"
 	"github.com/grafana/grafana/pkg/services/datasourceproxy"
 	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/deprovisioning"
 	"github.com/grafana/grafana/pkg/services/hooks"
 	"github.com/grafana/grafana/pkg/services/live"
 	"github.com/grafana/grafana/pkg/services/live/pushhttp"
@@ -46,10 +52,13 @@ import (
 	"github.com/grafana/grafana/pkg/services/provisioning"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/reports"
 	"github.com/grafana/grafana/pkg/services/schemaloader"
 	"github.com/grafana/grafana/pkg/services/search"
 	"github.com/grafana/grafana/pkg/services/shorturls"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/uploads"
+	"github.com/grafana/grafana/pkg/services/urlsigner"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/tsdb"
 
@@ -90,6 +99,7 @@ type HTTPServer struct {
 	Login                  login.Service                           `inject:""`
 	License                models.Licensing                        `inject:""`
 	AccessControl          accesscontrol.AccessControl             `inject:""`
+	AccessControlStore     *acdatabase.AccessControlStore          `inject:""`
 	BackendPluginManager   backendplugin.Manager                   `inject:""`
 	DataProxy              *datasourceproxy.DatasourceProxyService `inject:""`
 	PluginRequestValidator models.PluginRequestValidator           `inject:""`
@@ -108,7 +118,15 @@ type HTTPServer struct {
 	LibraryPanelService    librarypanels.Service                   `inject:""`
 	LibraryElementService  libraryelements.Service                 `inject:""`
 	SocialService          social.Service                          `inject:""`
+	SAMLService            *saml.Service                           `inject:""`
 	OAuthTokenService      *oauthtoken.Service                     `inject:""`
+	UploadService          *uploads.Service                        `inject:""`
+	LoginLockoutService    *loginpkg.LockoutService                `inject:""`
+	ClientCertService      *clientcert.Service                     `inject:""`
+	AuditService           *audit.Service                          `inject:""`
+	URLSigner              *urlsigner.Service                      `inject:""`
+	DeprovisioningService  *deprovisioning.Service                 `inject:""`
+	ReportsService         *reports.Service                        `inject:""`
 	Listener               net.Listener
 }
 
@@ -261,6 +279,7 @@ func (hs *HTTPServer) configureHttps() error {
 		},
 	}
 
+	hs.ClientCertService.ConfigureTLS(tlsCfg)
 	hs.httpSrv.TLSConfig = tlsCfg
 	hs.httpSrv.TLSNextProto = make(map[string]func(*http.Server, *tls.Conn, http.Handler))
 
@@ -301,6 +320,7 @@ func (hs *HTTPServer) configureHttp2() error {
 		NextProtos: []string{"h2", "http/1.1"},
 	}
 
+	hs.ClientCertService.ConfigureTLS(tlsCfg)
 	hs.httpSrv.TLSConfig = tlsCfg
 
 	return nil
@@ -441,6 +441,15 @@ func (hs *HTTPServer) setIndexViewData(c *models.ReqContext) (*dtos.IndexViewDat
 		return nil, err
 	}
 
+	impersonatedBy := ""
+	if c.UserToken != nil && c.UserToken.ImpersonatorUserId != 0 {
+		query := models.GetUserByIdQuery{Id: c.UserToken.ImpersonatorUserId}
+		if err := bus.DispatchCtx(c.Req.Context(), &query); err != nil {
+			return nil, err
+		}
+		impersonatedBy = query.Result.Login
+	}
+
 	data := dtos.IndexViewData{
 		User: &dtos.CurrentUser{
 			Id:                         c.UserId,
@@ -459,6 +468,7 @@ func (hs *HTTPServer) setIndexViewData(c *models.ReqContext) (*dtos.IndexViewDat
 			Locale:                     locale,
 			HelpFlags1:                 c.HelpFlags1,
 			HasEditPermissionInFolders: hasEditPerm,
+			ImpersonatedBy:             impersonatedBy,
 		},
 		Settings:                settings,
 		Theme:                   prefs.Theme,
@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/login"
 	"github.com/grafana/grafana/pkg/middleware/cookies"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/passwordpolicy"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/util/errutil"
@@ -98,6 +99,7 @@ func (hs *HTTPServer) LoginView(c *models.ReqContext) {
 
 	viewData.Settings["oauth"] = enabledOAuths
 	viewData.Settings["samlEnabled"] = hs.samlEnabled()
+	viewData.Settings["genericSamlEnabled"] = hs.SAMLService.IsEnabled()
 
 	if loginError, ok := tryGetEncryptedCookie(c, loginErrorCookieName); ok {
 		// this cookie is only set whenever an OAuth login fails
@@ -114,6 +116,11 @@ func (hs *HTTPServer) LoginView(c *models.ReqContext) {
 		return
 	}
 
+	if !c.IsSignedIn && hs.Cfg.KerberosAuthEnabled && hs.Cfg.KerberosAutoLogin {
+		c.Redirect(hs.Cfg.AppSubURL+"/login/kerberos", 307)
+		return
+	}
+
 	if c.IsSignedIn {
 		// Assign login token to auth proxy users if enable_login_token = true
 		if hs.Cfg.AuthProxyEnabled && hs.Cfg.AuthProxyEnableLoginToken {
@@ -211,6 +218,14 @@ func (hs *HTTPServer) LoginPost(c *models.ReqContext, cmd dtos.LoginCommand) res
 			return resp
 		}
 
+		if errors.Is(err, passwordpolicy.ErrPasswordExpired) {
+			resp = response.JSON(http.StatusUnauthorized, util.DynMap{
+				"message": passwordpolicy.ErrPasswordExpired.Message,
+				"code":    passwordpolicy.ErrPasswordExpired.Code,
+			})
+			return resp
+		}
+
 		// Do not expose disabled status,
 		// just show incorrect user credentials error (see #17947)
 		if errors.Is(err, login.ErrUserDisabled) {
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/oauth2"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/metrics"
+	"github.com/grafana/grafana/pkg/login"
+	"github.com/grafana/grafana/pkg/login/social"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// defaultDeviceAuthInterval is the polling interval in seconds clients should use
+// when the provider doesn't return one, as defined in RFC 8628.
+const defaultDeviceAuthInterval = 5
+
+// getDeviceAuthConnector returns the connector of an OAuth provider that has the
+// device authorization flow configured.
+func (hs *HTTPServer) getDeviceAuthConnector(name string) (social.SocialConnector, response.Response) {
+	if hs.SocialService.GetOAuthInfoProvider(name) == nil {
+		return nil, response.Error(http.StatusNotFound, "OAuth not enabled", nil)
+	}
+
+	connect, err := hs.SocialService.GetConnector(name)
+	if err != nil {
+		return nil, response.Error(http.StatusNotFound, fmt.Sprintf("No OAuth with name %s configured", name), err)
+	}
+
+	if !connect.SupportsDeviceAuth() {
+		return nil, response.Error(http.StatusNotFound, fmt.Sprintf("Device authorization not enabled for %s", name), nil)
+	}
+
+	return connect, nil
+}
+
+// StartDeviceLogin starts a device authorization with an OAuth provider, for clients
+// that can't follow the browser redirects of the OAuth login.
+func (hs *HTTPServer) StartDeviceLogin(c *models.ReqContext) response.Response {
+	name := c.Params(":name")
+	connect, errResp := hs.getDeviceAuthConnector(name)
+	if errResp != nil {
+		return errResp
+	}
+
+	oauthClient, err := hs.SocialService.GetOAuthHttpClient(name)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to create OAuth http client", err)
+	}
+
+	auth, err := connect.DeviceAuth(c.Req.Context(), oauthClient)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to start device authorization", err)
+	}
+
+	interval := auth.Interval
+	if interval <= 0 {
+		interval = defaultDeviceAuthInterval
+	}
+
+	return response.JSON(http.StatusOK, dtos.DeviceAuthorization{
+		DeviceCode:              auth.DeviceCode,
+		UserCode:                auth.UserCode,
+		VerificationURI:         auth.VerificationURI,
+		VerificationURIComplete: auth.VerificationURIComplete,
+		ExpiresIn:               auth.ExpiresIn,
+		Interval:                interval,
+	})
+}
+
+// DeviceLoginToken polls the OAuth provider for the token of a device authorization,
+// and signs in the user once the authorization is completed.
+func (hs *HTTPServer) DeviceLoginToken(c *models.ReqContext, cmd dtos.DeviceTokenCommand) response.Response {
+	name := c.Params(":name")
+	connect, errResp := hs.getDeviceAuthConnector(name)
+	if errResp != nil {
+		return errResp
+	}
+
+	oauthClient, err := hs.SocialService.GetOAuthHttpClient(name)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to create OAuth http client", err)
+	}
+
+	token, err := connect.DeviceAccessToken(c.Req.Context(), oauthClient, cmd.DeviceCode)
+	if err != nil {
+		var tokenErr *social.DeviceTokenError
+		if !errors.As(err, &tokenErr) {
+			return response.Error(http.StatusInternalServerError, "Failed to get device token", err)
+		}
+		status := http.StatusUnauthorized
+		if tokenErr.Pending() {
+			status = http.StatusBadRequest
+		}
+		return response.JSON(status, util.DynMap{
+			"message": "Device authorization not completed",
+			"code":    tokenErr.Code,
+		})
+	}
+
+	loginInfo := models.LoginInfo{AuthModule: name}
+	resp := hs.deviceLoginWithToken(c, connect, oauthClient, name, token, &loginInfo)
+
+	loginInfo.HTTPStatus = resp.Status()
+	if resp.Status() >= http.StatusBadRequest {
+		loginInfo.Error = resp.Err()
+		if loginInfo.Error == nil {
+			loginInfo.Error = errors.New(resp.ErrMessage())
+		}
+	}
+	hs.HooksService.RunLoginHook(&loginInfo, c)
+
+	return resp
+}
+
+func (hs *HTTPServer) deviceLoginWithToken(c *models.ReqContext, connect social.SocialConnector, oauthClient *http.Client,
+	name string, token *oauth2.Token, loginInfo *models.LoginInfo) *response.NormalResponse {
+	// token.TokenType was defaulting to "bearer", which is out of spec, so we explicitly set to "Bearer"
+	token.TokenType = "Bearer"
+
+	oauthCtx := context.WithValue(context.Background(), oauth2.HTTPClient, oauthClient)
+
+	// the device flow has no nonce, the ID token is only checked to be signed by the provider
+	if verifier := hs.SocialService.GetOAuthInfoProvider(name).IDTokenVerifier; verifier != nil {
+		if _, err := verifier.Verify(oauthCtx, token); err != nil {
+			return response.Error(http.StatusUnauthorized, "Invalid ID token", err)
+		}
+	}
+
+	userInfo, err := connect.UserInfo(connect.Client(oauthCtx, token), token)
+	if err != nil {
+		var sErr *social.Error
+		if errors.As(err, &sErr) {
+			return response.Error(http.StatusUnauthorized, sErr.Error(), err)
+		}
+		return response.Error(http.StatusInternalServerError, fmt.Sprintf("Failed to get user info from %s", name), err)
+	}
+
+	if userInfo.Email == "" {
+		return response.Error(http.StatusUnauthorized, login.ErrNoEmail.Error(), login.ErrNoEmail)
+	}
+
+	if !connect.IsEmailAllowed(userInfo.Email) {
+		return response.Error(http.StatusUnauthorized, login.ErrEmailNotAllowed.Error(), login.ErrEmailNotAllowed)
+	}
+
+	loginInfo.ExternalUser = *buildExternalUserInfo(token, userInfo, name, hs.SocialService.GetOAuthInfoProvider(name).SkipOrgRoleSync)
+	loginInfo.User, err = syncUser(c, &loginInfo.ExternalUser, connect)
+	if err != nil {
+		return response.Error(http.StatusUnauthorized, getLoginExternalError(err), err)
+	}
+
+	if err := hs.loginUserWithUser(loginInfo.User, c); err != nil {
+		var createTokenErr *models.CreateTokenErr
+		if errors.As(err, &createTokenErr) {
+			return response.Error(createTokenErr.StatusCode, createTokenErr.ExternalErr, createTokenErr.InternalErr)
+		}
+		return response.Error(http.StatusInternalServerError, "Error while signing in user", err)
+	}
+
+	metrics.MApiLoginOAuth.Inc()
+	return response.JSON(http.StatusOK, util.DynMap{
+		"message":    "Logged in",
+		"cookieName": hs.Cfg.LoginCookieName,
+		"token":      c.UserToken.UnhashedToken,
+	})
+}
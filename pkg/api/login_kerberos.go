@@ -0,0 +1,62 @@
+package api
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/metrics"
+	"github.com/grafana/grafana/pkg/middleware/cookies"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// kerberosFallbackBody is shown by browsers that don't negotiate Kerberos, and sends them back to the login form.
+const kerberosFallbackBody = `<!DOCTYPE html><html><head><meta http-equiv="refresh" content="0;url=%s"></head><body></body></html>`
+
+// KerberosLogin asks the browser to negotiate a Kerberos ticket, and starts a session
+// for the user the context handler signed in with the ticket.
+func (hs *HTTPServer) KerberosLogin(c *models.ReqContext) {
+	if !hs.Cfg.KerberosAuthEnabled {
+		c.Handle(hs.Cfg, http.StatusNotFound, "Kerberos authentication not enabled", nil)
+		return
+	}
+
+	if !c.IsSignedIn {
+		fallbackURL := hs.Cfg.AppSubURL + "/login?disableAutoLogin"
+		c.Resp.Header().Set("WWW-Authenticate", "Negotiate")
+		c.Resp.Header().Set("Content-Type", "text/html; charset=UTF-8")
+		c.Resp.WriteHeader(http.StatusUnauthorized)
+		if _, err := fmt.Fprintf(c.Resp, kerberosFallbackBody, html.EscapeString(fallbackURL)); err != nil {
+			hs.log.Error("Failed to write Kerberos negotiation response", "error", err)
+		}
+		return
+	}
+
+	// the user is signed in with a ticket, unless it already has a session
+	if strings.HasPrefix(c.Req.Header.Get("Authorization"), "Negotiate ") {
+		loginInfo := models.LoginInfo{AuthModule: models.AuthModuleKerberos}
+		loginInfo.User = &models.User{Id: c.UserId, Email: c.Email, Login: c.Login}
+		if err := hs.loginUserWithUser(loginInfo.User, c); err != nil {
+			hs.handleOAuthLoginErrorWithRedirect(c, loginInfo, err)
+			return
+		}
+
+		loginInfo.HTTPStatus = http.StatusOK
+		hs.HooksService.RunLoginHook(&loginInfo, c)
+		metrics.MApiLoginKerberos.Inc()
+	}
+
+	if redirectTo, err := url.QueryUnescape(c.GetCookie("redirect_to")); err == nil && len(redirectTo) > 0 {
+		if err := hs.ValidateRedirectTo(redirectTo); err == nil {
+			cookies.DeleteCookie(c.Resp, "redirect_to", hs.CookieOptionsFromCfg)
+			c.Redirect(redirectTo)
+			return
+		}
+		log.Debugf("Ignored invalid redirect_to cookie value: %v", redirectTo)
+	}
+
+	c.Redirect(hs.Cfg.AppSubURL + "/")
+}
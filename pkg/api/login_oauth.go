@@ -26,6 +26,7 @@ import (
 var (
 	oauthLogger          = log.New("oauth")
 	OauthStateCookieName = "oauth_state"
+	OauthNonceCookieName = "oauth_nonce"
 )
 
 func GenStateString() (string, error) {
@@ -83,18 +84,37 @@ func (hs *HTTPServer) OAuthLogin(ctx *models.ReqContext) {
 
 		hashedState := hashStatecode(state, provider.ClientSecret)
 		cookies.WriteCookie(ctx.Resp, OauthStateCookieName, hashedState, hs.Cfg.OAuthCookieMaxAge, hs.CookieOptionsFromCfg)
-		if provider.HostedDomain == "" {
-			ctx.Redirect(connect.AuthCodeURL(state, oauth2.AccessTypeOnline))
-		} else {
-			ctx.Redirect(connect.AuthCodeURL(state, oauth2.SetAuthURLParam("hd", provider.HostedDomain), oauth2.AccessTypeOnline))
+
+		opts := []oauth2.AuthCodeOption{oauth2.AccessTypeOnline}
+		if provider.HostedDomain != "" {
+			opts = append(opts, oauth2.SetAuthURLParam("hd", provider.HostedDomain))
 		}
+		if provider.IDTokenVerifier != nil {
+			// the nonce ties the ID token to this login, so that a token issued for another one is rejected
+			nonce, err := GenStateString()
+			if err != nil {
+				ctx.Logger.Error("Generating nonce failed", "err", err)
+				hs.handleOAuthLoginError(ctx, loginInfo, LoginError{
+					HttpStatus:    http.StatusInternalServerError,
+					PublicMessage: "An internal error occurred",
+				})
+				return
+			}
+			cookies.WriteCookie(ctx.Resp, OauthNonceCookieName, hashStatecode(nonce, provider.ClientSecret), hs.Cfg.OAuthCookieMaxAge, hs.CookieOptionsFromCfg)
+			opts = append(opts, oauth2.SetAuthURLParam("nonce", nonce))
+		}
+		ctx.Redirect(connect.AuthCodeURL(state, opts...))
 		return
 	}
 
 	cookieState := ctx.GetCookie(OauthStateCookieName)
+	cookieNonce := ctx.GetCookie(OauthNonceCookieName)
 
-	// delete cookie
+	// delete cookies
 	cookies.DeleteCookie(ctx.Resp, OauthStateCookieName, hs.CookieOptionsFromCfg)
+	if cookieNonce != "" {
+		cookies.DeleteCookie(ctx.Resp, OauthNonceCookieName, hs.CookieOptionsFromCfg)
+	}
 
 	if cookieState == "" {
 		hs.handleOAuthLoginError(ctx, loginInfo, LoginError{
@@ -139,6 +159,25 @@ func (hs *HTTPServer) OAuthLogin(ctx *models.ReqContext) {
 	// token.TokenType was defaulting to "bearer", which is out of spec, so we explicitly set to "Bearer"
 	token.TokenType = "Bearer"
 
+	if provider.IDTokenVerifier != nil {
+		claims, err := provider.IDTokenVerifier.Verify(oauthCtx, token)
+		if err != nil {
+			hs.handleOAuthLoginError(ctx, loginInfo, LoginError{
+				HttpStatus:    http.StatusUnauthorized,
+				PublicMessage: "login.OAuthLogin(invalid id_token)",
+				Err:           err,
+			})
+			return
+		}
+		if cookieNonce == "" || hashStatecode(claims.Nonce, provider.ClientSecret) != cookieNonce {
+			hs.handleOAuthLoginError(ctx, loginInfo, LoginError{
+				HttpStatus:    http.StatusUnauthorized,
+				PublicMessage: "login.OAuthLogin(nonce mismatch)",
+			})
+			return
+		}
+	}
+
 	oauthLogger.Debug("OAuthLogin Got token", "token", token)
 
 	// set up oauth2 client
@@ -174,7 +213,7 @@ func (hs *HTTPServer) OAuthLogin(ctx *models.ReqContext) {
 		return
 	}
 
-	loginInfo.ExternalUser = *buildExternalUserInfo(token, userInfo, name)
+	loginInfo.ExternalUser = *buildExternalUserInfo(token, userInfo, name, provider.SkipOrgRoleSync)
 	loginInfo.User, err = syncUser(ctx, &loginInfo.ExternalUser, connect)
 	if err != nil {
 		hs.handleOAuthLoginErrorWithRedirect(ctx, loginInfo, err)
@@ -203,8 +242,10 @@ func (hs *HTTPServer) OAuthLogin(ctx *models.ReqContext) {
 	ctx.Redirect(setting.AppSubUrl + "/")
 }
 
-// buildExternalUserInfo returns a ExternalUserInfo struct from OAuth user profile
-func buildExternalUserInfo(token *oauth2.Token, userInfo *social.BasicUserInfo, name string) *models.ExternalUserInfo {
+// buildExternalUserInfo returns a ExternalUserInfo struct from OAuth user profile. The role of
+// the profile is ignored when the organization roles are managed in Grafana, so that they
+// aren't overwritten at login.
+func buildExternalUserInfo(token *oauth2.Token, userInfo *social.BasicUserInfo, name string, skipOrgRoleSync bool) *models.ExternalUserInfo {
 	oauthLogger.Debug("Building external user info from OAuth user info")
 
 	extUser := &models.ExternalUserInfo{
@@ -218,7 +259,7 @@ func buildExternalUserInfo(token *oauth2.Token, userInfo *social.BasicUserInfo,
 		Groups:     userInfo.Groups,
 	}
 
-	if userInfo.Role != "" {
+	if userInfo.Role != "" && !skipOrgRoleSync {
 		rt := models.RoleType(userInfo.Role)
 		if rt.IsValid() {
 			// The user will be assigned a role in either the auto-assigned organization or in the default one
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/infra/metrics"
+	"github.com/grafana/grafana/pkg/login"
+	"github.com/grafana/grafana/pkg/login/saml"
+	"github.com/grafana/grafana/pkg/middleware/cookies"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// SAMLMetadata serves the service provider metadata to be registered with the identity provider.
+func (hs *HTTPServer) SAMLMetadata(ctx *models.ReqContext) {
+	if !hs.SAMLService.IsEnabled() {
+		ctx.Handle(hs.Cfg, http.StatusNotFound, "SAML not enabled", nil)
+		return
+	}
+
+	metadata, err := hs.SAMLService.Metadata()
+	if err != nil {
+		ctx.Handle(hs.Cfg, http.StatusInternalServerError, "Failed to generate SAML metadata", err)
+		return
+	}
+
+	ctx.Resp.Header().Set("Content-Type", "application/samlmetadata+xml")
+	ctx.Resp.WriteHeader(http.StatusOK)
+	if _, err := ctx.Resp.Write(metadata); err != nil {
+		hs.log.Error("Failed to write SAML metadata", "err", err)
+	}
+}
+
+// SAMLLogin redirects the user to the identity provider to start a service provider initiated login.
+func (hs *HTTPServer) SAMLLogin(ctx *models.ReqContext) {
+	loginInfo := models.LoginInfo{AuthModule: saml.AuthModule}
+
+	if !hs.SAMLService.IsEnabled() {
+		hs.handleOAuthLoginError(ctx, loginInfo, LoginError{
+			HttpStatus:    http.StatusNotFound,
+			PublicMessage: "SAML not enabled",
+		})
+		return
+	}
+
+	redirectURL, err := hs.SAMLService.AuthnRequestURL()
+	if err != nil {
+		hs.handleOAuthLoginError(ctx, loginInfo, LoginError{
+			HttpStatus:    http.StatusInternalServerError,
+			PublicMessage: "Failed to create SAML authentication request",
+			Err:           err,
+		})
+		return
+	}
+
+	ctx.Redirect(redirectURL.String())
+}
+
+// SAMLACS is the assertion consumer service receiving the identity provider response.
+func (hs *HTTPServer) SAMLACS(ctx *models.ReqContext) {
+	loginInfo := models.LoginInfo{AuthModule: saml.AuthModule}
+
+	if !hs.SAMLService.IsEnabled() {
+		hs.handleOAuthLoginError(ctx, loginInfo, LoginError{
+			HttpStatus:    http.StatusNotFound,
+			PublicMessage: "SAML not enabled",
+		})
+		return
+	}
+
+	extUser, err := hs.SAMLService.ParseResponse(ctx.Req.Request)
+	if err != nil {
+		hs.handleOAuthLoginErrorWithRedirect(ctx, loginInfo, login.ErrInvalidCredentials, "err", err)
+		return
+	}
+
+	loginInfo.ExternalUser = *extUser
+
+	cmd := &models.UpsertUserCommand{
+		ReqContext:    ctx,
+		ExternalUser:  extUser,
+		SignupAllowed: hs.Cfg.SAML.AllowSignUp,
+	}
+	if err := bus.Dispatch(cmd); err != nil {
+		hs.handleOAuthLoginErrorWithRedirect(ctx, loginInfo, err)
+		return
+	}
+
+	// Do not expose disabled status,
+	// just show incorrect user credentials error (see #17947)
+	if cmd.Result.IsDisabled {
+		hs.handleOAuthLoginErrorWithRedirect(ctx, loginInfo, login.ErrInvalidCredentials)
+		return
+	}
+	loginInfo.User = cmd.Result
+
+	if err := hs.loginUserWithUser(loginInfo.User, ctx); err != nil {
+		hs.handleOAuthLoginErrorWithRedirect(ctx, loginInfo, err)
+		return
+	}
+
+	loginInfo.HTTPStatus = http.StatusOK
+	hs.HooksService.RunLoginHook(&loginInfo, ctx)
+	metrics.MApiLoginSAML.Inc()
+
+	if redirectTo, err := url.QueryUnescape(ctx.GetCookie("redirect_to")); err == nil && len(redirectTo) > 0 {
+		if err := hs.ValidateRedirectTo(redirectTo); err == nil {
+			cookies.DeleteCookie(ctx.Resp, "redirect_to", hs.CookieOptionsFromCfg)
+			ctx.Redirect(redirectTo)
+			return
+		}
+		hs.log.Debug("Ignored invalid redirect_to cookie value", "redirect_to", redirectTo)
+	}
+
+	ctx.Redirect(hs.Cfg.AppSubURL + "/")
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/login"
+	"github.com/grafana/grafana/pkg/login/saml"
 	"github.com/grafana/grafana/pkg/login/social"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/auth"
@@ -108,6 +109,7 @@ func TestLoginErrorCookieAPIEndpoint(t *testing.T) {
 		Cfg:              cfg,
 		SettingsProvider: &setting.OSSImpl{Cfg: cfg},
 		License:          &licensing.OSSLicensingService{},
+		SAMLService:      &saml.Service{},
 		SocialService:    &mockSocialService{},
 	}
 
@@ -155,6 +157,7 @@ func TestLoginViewRedirect(t *testing.T) {
 		Cfg:              cfg,
 		SettingsProvider: &setting.OSSImpl{Cfg: cfg},
 		License:          &licensing.OSSLicensingService{},
+		SAMLService:      &saml.Service{},
 		SocialService:    &mockSocialService{},
 	}
 	hs.Cfg.CookieSecure = true
@@ -496,6 +499,7 @@ func TestLoginOAuthRedirect(t *testing.T) {
 		Cfg:              cfg,
 		SettingsProvider: &setting.OSSImpl{Cfg: cfg},
 		License:          &licensing.OSSLicensingService{},
+		SAMLService:      &saml.Service{},
 		SocialService:    mock,
 	}
 
@@ -519,9 +523,10 @@ func TestLoginInternal(t *testing.T) {
 	fakeViewIndex(t)
 	sc := setupScenarioContext(t, "/login")
 	hs := &HTTPServer{
-		Cfg:     setting.NewCfg(),
-		License: &licensing.OSSLicensingService{},
-		log:     log.New("test"),
+		Cfg:         setting.NewCfg(),
+		License:     &licensing.OSSLicensingService{},
+		SAMLService: &saml.Service{},
+		log:         log.New("test"),
 	}
 
 	sc.defaultHandler = routing.Wrap(func(c *models.ReqContext) {
@@ -570,6 +575,7 @@ func setupAuthProxyLoginTest(t *testing.T, enableLoginToken bool) *scenarioConte
 		Cfg:              sc.cfg,
 		SettingsProvider: &setting.OSSImpl{Cfg: sc.cfg},
 		License:          &licensing.OSSLicensingService{},
+		SAMLService:      &saml.Service{},
 		AuthTokenService: auth.NewFakeUserAuthTokenService(),
 		log:              log.New("hello"),
 		SocialService:    &mockSocialService{},
@@ -699,6 +705,23 @@ func TestLoginPostRunLokingHook(t *testing.T) {
 	}
 }
 
+func TestBuildExternalUserInfo(t *testing.T) {
+	userInfo := &social.BasicUserInfo{Id: "1", Login: "user", Email: "user@example.com", Role: "Admin"}
+
+	t.Run("Role of the provider is synced", func(t *testing.T) {
+		extUser := buildExternalUserInfo(nil, userInfo, "generic_oauth", false)
+
+		assert.Equal(t, "oauth_generic_oauth", extUser.AuthModule)
+		assert.Equal(t, map[int64]models.RoleType{1: models.ROLE_ADMIN}, extUser.OrgRoles)
+	})
+
+	t.Run("Role of the provider is ignored when org role sync is skipped", func(t *testing.T) {
+		extUser := buildExternalUserInfo(nil, userInfo, "generic_oauth", true)
+
+		assert.Empty(t, extUser.OrgRoles)
+	})
+}
+
 type mockSocialService struct {
 	oAuthInfo       *social.OAuthInfo
 	oAuthInfos      map[string]*social.OAuthInfo
@@ -10,6 +10,7 @@ import (
 	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/passwordpolicy"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -178,6 +179,10 @@ func (hs *HTTPServer) CompleteInvite(c *models.ReqContext, completeInvite dtos.C
 		return response.Error(412, fmt.Sprintf("Invite cannot be used in status %s", invite.Status), nil)
 	}
 
+	if err := passwordpolicy.Validate(c.Req.Context(), hs.Cfg, nil, completeInvite.Password); err != nil {
+		return passwordPolicyErrorResponse(err)
+	}
+
 	cmd := models.CreateUserCommand{
 		Email:        completeInvite.Email,
 		Name:         completeInvite.Name,
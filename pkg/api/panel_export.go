@@ -0,0 +1,440 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/frameexport"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/guardian"
+)
+
+// panelExportMaxDataPoints is the maximum number of data points of the queries
+// of panel exports when the panel doesn't set one, since the exports aren't
+// limited by the width of the panel.
+const panelExportMaxDataPoints = 10000
+
+// reTemplateVariable matches the references to template variables: $name,
+// ${name}, ${name:format} and [[name]].
+var reTemplateVariable = regexp.MustCompile(`\$(\w+)|\$\{(\w+)(?::(\w+))?\}|\[\[(\w+)(?::(\w+))?\]\]`)
+
+// ExportPanelData executes the queries of a panel and returns their frames as a
+// CSV file or an XLSX workbook.
+// GET /api/dashboards/uid/:uid/panels/:panelId/export
+func (hs *HTTPServer) ExportPanelData(c *models.ReqContext) response.Response {
+	query := c.Req.URL.Query()
+	format := frameexport.Format(strings.ToLower(query.Get("format")))
+	if format == "" {
+		format = frameexport.FormatCSV
+	}
+	if !frameexport.ValidFormat(format) {
+		return response.Error(http.StatusBadRequest, fmt.Sprintf("Unsupported format %q, expected csv or xlsx", format), nil)
+	}
+
+	dash, rsp := getDashboardHelper(c.Req.Context(), c.OrgId, 0, c.Params(":uid"))
+	if rsp != nil {
+		return rsp
+	}
+	g := guardian.New(dash.Id, c.OrgId, c.SignedInUser)
+	if canView, err := g.CanView(); err != nil || !canView {
+		return dashboardGuardianResponse(err)
+	}
+
+	panelID := c.ParamsInt64(":panelId")
+	panel := findDashboardPanel(dash.Data, panelID)
+	if panel == nil {
+		return response.Error(http.StatusNotFound, "Panel not found", nil)
+	}
+
+	tz := query.Get("tz")
+	if tz == "" {
+		tz = dash.Data.Get("timezone").MustString()
+	}
+	loc := time.UTC
+	if tz != "" && tz != "browser" && !strings.EqualFold(tz, "utc") {
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return response.Error(http.StatusBadRequest, fmt.Sprintf("Unknown timezone %q", tz), err)
+		}
+	}
+
+	maxDataPoints := panel.Get("maxDataPoints").MustInt64(panelExportMaxDataPoints)
+	if v := query.Get("maxDataPoints"); v != "" {
+		var err error
+		if maxDataPoints, err = strconv.ParseInt(v, 10, 64); err != nil || maxDataPoints <= 0 {
+			return response.Error(http.StatusBadRequest, "maxDataPoints must be a positive number", nil)
+		}
+	}
+
+	from, to := query.Get("from"), query.Get("to")
+	if from == "" {
+		from = dash.Data.Get("time").Get("from").MustString("now-6h")
+	}
+	if to == "" {
+		to = dash.Data.Get("time").Get("to").MustString("now")
+	}
+	timeRange := plugins.NewDataTimeRange(from, to)
+	if _, err := timeRange.ParseFrom(); err != nil {
+		return response.Error(http.StatusBadRequest, fmt.Sprintf("Invalid time range start %q", from), err)
+	}
+	if _, err := timeRange.ParseTo(); err != nil {
+		return response.Error(http.StatusBadRequest, fmt.Sprintf("Invalid time range end %q", to), err)
+	}
+
+	frames, rsp := hs.queryPanelData(c, panel, timeRange, maxDataPoints, dashboardVariables(dash.Data, query))
+	if rsp != nil {
+		return rsp
+	}
+	if err := frameexport.Validate(format, frames); err != nil {
+		return response.Error(http.StatusBadRequest, err.Error(), err)
+	}
+
+	name := models.SlugifyTitle(panel.Get("title").MustString())
+	if name == "" {
+		name = fmt.Sprintf("panel-%d", panelID)
+	}
+	return &panelExportResponse{
+		format:   format,
+		filename: fmt.Sprintf("%s-%s.%s", dash.Slug, name, format),
+		frames:   frames,
+		loc:      loc,
+	}
+}
+
+// queryPanelData executes the queries of the panel that aren't hidden, with the
+// template variables replaced, and returns their frames in the order of the
+// queries.
+func (hs *HTTPServer) queryPanelData(c *models.ReqContext, panel *simplejson.Json, timeRange plugins.DataTimeRange,
+	maxDataPoints int64, vars map[string][]string) (data.Frames, response.Response) {
+	intervalMS := (timeRange.GetToAsMsEpoch() - timeRange.GetFromAsMsEpoch()) / maxDataPoints
+	if intervalMS < 1 {
+		intervalMS = 1
+	}
+
+	var refIDs []string
+	var dsOrder []int64
+	sources := map[int64]*models.DataSource{}
+	requests := map[int64]*plugins.DataQuery{}
+	all := plugins.DataQuery{TimeRange: &timeRange, User: c.SignedInUser}
+	hasExpression := false
+
+	panelDS := panel.Get("datasource").Interface()
+	for _, targetObj := range panel.Get("targets").MustArray() {
+		target := simplejson.NewFromAny(interpolateVariables(targetObj, vars))
+		if target.Get("hide").MustBool() {
+			continue
+		}
+
+		dsRef := target.Get("datasource").Interface()
+		if dsRef == nil || dsRef == "" {
+			dsRef = interpolateVariables(panelDS, vars)
+		}
+
+		subQuery := plugins.DataSubQuery{
+			RefID:         target.Get("refId").MustString("A"),
+			MaxDataPoints: maxDataPoints,
+			IntervalMS:    intervalMS,
+			QueryType:     target.Get("queryType").MustString(""),
+			Model:         target,
+		}
+		target.Set("maxDataPoints", maxDataPoints)
+		target.Set("intervalMs", intervalMS)
+
+		if name, ok := dsRef.(string); ok && name == expr.DatasourceName {
+			hasExpression = true
+			target.Set("datasource", expr.DatasourceName)
+			target.Set("datasourceId", expr.DatasourceID)
+		} else {
+			ds, rsp := hs.getPanelDatasource(c, dsRef)
+			if rsp != nil {
+				return nil, rsp
+			}
+			target.Set("datasource", ds.Name)
+			target.Set("datasourceId", ds.Id)
+			subQuery.DataSource = ds
+
+			if _, ok := requests[ds.Id]; !ok {
+				sources[ds.Id] = ds
+				requests[ds.Id] = &plugins.DataQuery{TimeRange: &timeRange, User: c.SignedInUser}
+				dsOrder = append(dsOrder, ds.Id)
+			}
+			requests[ds.Id].Queries = append(requests[ds.Id].Queries, subQuery)
+		}
+
+		refIDs = append(refIDs, subQuery.RefID)
+		all.Queries = append(all.Queries, subQuery)
+	}
+	if len(refIDs) == 0 {
+		return nil, response.Error(http.StatusBadRequest, "The panel has no queries", nil)
+	}
+
+	var qdr *backend.QueryDataResponse
+	if hasExpression {
+		exprService := expr.Service{
+			Cfg:         hs.Cfg,
+			DataService: hs.DataService,
+		}
+		var err error
+		if qdr, err = exprService.WrapTransformData(c.Req.Context(), all); err != nil {
+			return nil, response.Error(http.StatusInternalServerError, "expression request error", err)
+		}
+	} else {
+		// each data source gets its queries in a single request, as for mixed
+		// panels in the browser
+		qdr = backend.NewQueryDataResponse()
+		for _, id := range dsOrder {
+			ds := sources[id]
+			if err := hs.PluginRequestValidator.Validate(ds.Url, nil); err != nil {
+				return nil, response.Error(http.StatusForbidden, "Access denied", err)
+			}
+			resp, err := hs.DataService.HandleRequest(c.Req.Context(), ds, *requests[id])
+			if err != nil {
+				return nil, response.Error(http.StatusInternalServerError, "Metric request error", err)
+			}
+			dsResp, err := resp.ToBackendDataResponse()
+			if err != nil {
+				return nil, response.Error(http.StatusInternalServerError, "error converting results", err)
+			}
+			for refID, res := range dsResp.Responses {
+				qdr.Responses[refID] = res
+			}
+		}
+	}
+
+	var frames data.Frames
+	for _, refID := range refIDs {
+		res := qdr.Responses[refID]
+		if res.Error != nil {
+			return nil, response.Error(http.StatusBadRequest, fmt.Sprintf("Query %s failed: %s", refID, res.Error), res.Error)
+		}
+		for _, frame := range res.Frames {
+			if frame.Name == "" {
+				frame.Name = refID
+			}
+			frames = append(frames, frame)
+		}
+	}
+	return frames, nil
+}
+
+// getPanelDatasource returns the data source of a panel or a query, which is
+// referred to by name, or by UID in an object, and is the default data source
+// when empty.
+func (hs *HTTPServer) getPanelDatasource(c *models.ReqContext, ref interface{}) (*models.DataSource, response.Response) {
+	var ds *models.DataSource
+	switch ref := ref.(type) {
+	case nil, string:
+		name, _ := ref.(string)
+		if name == "" || name == "default" {
+			query := &models.GetDefaultDataSourceQuery{OrgId: c.OrgId, User: c.SignedInUser}
+			if err := bus.Dispatch(query); err != nil {
+				return nil, response.Error(http.StatusBadRequest, "No default data source", err)
+			}
+			ds = query.Result
+			break
+		}
+		query := &models.GetDataSourceQuery{Name: name, OrgId: c.OrgId}
+		if err := bus.Dispatch(query); err != nil {
+			return nil, response.Error(http.StatusBadRequest, fmt.Sprintf("Data source %q not found", name), err)
+		}
+		ds = query.Result
+	case map[string]interface{}:
+		uid, _ := ref["uid"].(string)
+		var err error
+		if ds, err = hs.DatasourceCache.GetDatasourceByUID(uid, c.SignedInUser, c.SkipCache); err != nil {
+			return nil, hs.handleGetDataSourceError(err, 0)
+		}
+		return ds, nil
+	default:
+		return nil, response.Error(http.StatusBadRequest, "Invalid data source of the panel", nil)
+	}
+
+	// the data source is looked up again through the cache, which checks the
+	// permissions of the user
+	cached, err := hs.DatasourceCache.GetDatasource(ds.Id, c.SignedInUser, c.SkipCache)
+	if err != nil {
+		return nil, hs.handleGetDataSourceError(err, ds.Id)
+	}
+	return cached, nil
+}
+
+// findDashboardPanel returns the panel of the dashboard with the ID, including
+// the panels of collapsed rows.
+func findDashboardPanel(dashboard *simplejson.Json, panelID int64) *simplejson.Json {
+	for _, panelObj := range dashboard.Get("panels").MustArray() {
+		panel := simplejson.NewFromAny(panelObj)
+		if panel.Get("id").MustInt64() == panelID {
+			return panel
+		}
+		if found := findDashboardPanel(panel, panelID); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// dashboardVariables returns the values of the template variables of the
+// dashboard, which are their current values unless they are set by the var-
+// parameters of the query.
+func dashboardVariables(dashboard *simplejson.Json, query url.Values) map[string][]string {
+	vars := map[string][]string{}
+	for _, variableObj := range dashboard.Get("templating").Get("list").MustArray() {
+		variable := simplejson.NewFromAny(variableObj)
+		name := variable.Get("name").MustString()
+		if name == "" {
+			continue
+		}
+		current := variable.Get("current").Get("value")
+		values := current.MustStringArray()
+		if values == nil {
+			values = []string{current.MustString()}
+		}
+		vars[name] = values
+	}
+	for key, values := range query {
+		if strings.HasPrefix(key, "var-") {
+			vars[strings.TrimPrefix(key, "var-")] = values
+		}
+	}
+
+	for name, values := range vars {
+		if len(values) != 1 || values[0] != "$__all" {
+			continue
+		}
+		variable := findVariable(dashboard, name)
+		if allValue := variable.Get("allValue").MustString(); allValue != "" {
+			vars[name] = []string{allValue}
+			continue
+		}
+		var options []string
+		for _, optionObj := range variable.Get("options").MustArray() {
+			if value := simplejson.NewFromAny(optionObj).Get("value").MustString(); value != "$__all" {
+				options = append(options, value)
+			}
+		}
+		vars[name] = options
+	}
+	return vars
+}
+
+func findVariable(dashboard *simplejson.Json, name string) *simplejson.Json {
+	for _, variableObj := range dashboard.Get("templating").Get("list").MustArray() {
+		variable := simplejson.NewFromAny(variableObj)
+		if variable.Get("name").MustString() == name {
+			return variable
+		}
+	}
+	return simplejson.New()
+}
+
+// interpolateVariables returns a copy of a value of a query model with the
+// references to the template variables in its strings replaced with their
+// values. Multiple values are formatted according to the format of the
+// reference, as comma separated values by default. The references to other
+// variables, such as $__interval, are left to the data sources.
+func interpolateVariables(v interface{}, vars map[string][]string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		interpolated := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			interpolated[key] = interpolateVariables(value, vars)
+		}
+		return interpolated
+	case []interface{}:
+		interpolated := make([]interface{}, len(v))
+		for i, value := range v {
+			interpolated[i] = interpolateVariables(value, vars)
+		}
+		return interpolated
+	case string:
+		return reTemplateVariable.ReplaceAllStringFunc(v, func(ref string) string {
+			groups := reTemplateVariable.FindStringSubmatch(ref)
+			name, format := groups[1]+groups[2]+groups[4], groups[3]+groups[5]
+			values, ok := vars[name]
+			if !ok {
+				return ref
+			}
+			return formatVariable(values, format)
+		})
+	default:
+		return v
+	}
+}
+
+func formatVariable(values []string, format string) string {
+	if len(values) == 1 && format != "json" {
+		if format == "regex" {
+			return regexp.QuoteMeta(values[0])
+		}
+		return values[0]
+	}
+
+	quoted := make([]string, len(values))
+	switch format {
+	case "pipe":
+		return strings.Join(values, "|")
+	case "regex":
+		for i, value := range values {
+			quoted[i] = regexp.QuoteMeta(value)
+		}
+		return "(" + strings.Join(quoted, "|") + ")"
+	case "singlequote":
+		for i, value := range values {
+			quoted[i] = "'" + strings.ReplaceAll(value, "'", `\'`) + "'"
+		}
+		return strings.Join(quoted, ",")
+	case "doublequote":
+		for i, value := range values {
+			quoted[i] = `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+		}
+		return strings.Join(quoted, ",")
+	case "json":
+		encoded, err := json.Marshal(values)
+		if err != nil {
+			return strings.Join(values, ",")
+		}
+		return string(encoded)
+	default:
+		return strings.Join(values, ",")
+	}
+}
+
+// panelExportResponse streams the frames of a panel export in their format.
+type panelExportResponse struct {
+	format   frameexport.Format
+	filename string
+	frames   data.Frames
+	loc      *time.Location
+}
+
+func (r *panelExportResponse) WriteTo(c *models.ReqContext) {
+	header := c.Resp.Header()
+	header.Set("Content-Type", r.format.ContentType())
+	header.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, r.filename))
+	c.Resp.WriteHeader(http.StatusOK)
+
+	if err := frameexport.Write(c.Resp, r.format, r.frames, r.loc); err != nil {
+		c.Logger.Error("Failed to write panel data export", "err", err)
+	}
+}
+
+func (r *panelExportResponse) Body() []byte {
+	return nil
+}
+
+func (r *panelExportResponse) Status() int {
+	return http.StatusOK
+}
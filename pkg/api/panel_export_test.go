@@ -0,0 +1,162 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/validations"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportPanelData(t *testing.T) {
+	dashJSON, err := simplejson.NewJson([]byte(`{
+		"title": "Test dash",
+		"time": {"from": "now-1h", "to": "now"},
+		"templating": {"list": [{"name": "host", "current": {"value": ["a", "b"]}}]},
+		"panels": [
+			{"id": 1, "type": "row", "collapsed": true, "panels": [
+				{"id": 2, "title": "CPU usage", "datasource": "test-ds", "targets": [
+					{"refId": "A", "expr": "cpu{host=~\"${host:regex}\"}"},
+					{"refId": "B", "expr": "hidden", "hide": true}
+				]}
+			]}
+		]
+	}`))
+	require.NoError(t, err)
+	dash := models.NewDashboardFromJson(dashJSON)
+	dash.Id = 1
+	dash.Uid = "abc"
+	ds := &models.DataSource{Id: 3, OrgId: 1, Name: "test-ds", Type: "test"}
+
+	bus.AddHandlerCtx("test", func(ctx context.Context, query *models.GetDashboardQuery) error {
+		query.Result = dash
+		return nil
+	})
+	bus.AddHandler("test", func(query *models.GetDataSourceQuery) error {
+		if query.Name != ds.Name {
+			return models.ErrDataSourceNotFound
+		}
+		query.Result = ds
+		return nil
+	})
+	t.Cleanup(bus.ClearBusHandlers)
+
+	origNewGuardian := guardian.New
+	t.Cleanup(func() { guardian.New = origNewGuardian })
+	guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanViewValue: true})
+
+	var queries []plugins.DataSubQuery
+	dataService := tsdb.NewService()
+	dataService.RegisterQueryHandler("test", func(*models.DataSource) (plugins.DataPlugin, error) {
+		return plugins.DataPluginFunc(func(ctx context.Context, ds *models.DataSource, query plugins.DataQuery) (plugins.DataResponse, error) {
+			queries = query.Queries
+			frame := data.NewFrame("",
+				data.NewField("time", nil, []time.Time{time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)}),
+				data.NewField("value", nil, []float64{42}),
+			)
+			return plugins.DataResponse{Results: map[string]plugins.DataQueryResult{
+				"A": {RefID: "A", Dataframes: plugins.NewDecodedDataFrames(data.Frames{frame})},
+			}}, nil
+		}), nil
+	})
+
+	hs := &HTTPServer{
+		Cfg:                    setting.NewCfg(),
+		log:                    log.New("test"),
+		DataService:            &dataService,
+		DatasourceCache:        &fakeDatasourceCache{ds: ds},
+		PluginRequestValidator: &validations.OSSPluginRequestValidator{},
+	}
+
+	sc := setupScenarioContext(t, "/api/dashboards/uid/abc/panels/2/export")
+	sc.m.Get("/api/dashboards/uid/:uid/panels/:panelId/export", routing.Wrap(hs.ExportPanelData))
+
+	t.Run("Should export the data of the visible queries as CSV", func(t *testing.T) {
+		sc.fakeReqWithParams("GET", "/api/dashboards/uid/abc/panels/2/export", map[string]string{"tz": "Europe/Paris"}).exec()
+		require.Equal(t, http.StatusOK, sc.resp.Code, sc.resp.Body.String())
+		assert.Equal(t, "text/csv; charset=utf-8", sc.resp.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="test-dash-cpu-usage.csv"`, sc.resp.Header().Get("Content-Disposition"))
+		assert.Equal(t, "time,value\n2021-06-01T14:00:00.000+02:00,42\n", sc.resp.Body.String())
+
+		require.Len(t, queries, 1)
+		assert.Equal(t, "A", queries[0].RefID)
+		assert.Equal(t, `cpu{host=~"(a|b)"}`, queries[0].Model.Get("expr").MustString())
+		assert.Equal(t, int64(3), queries[0].Model.Get("datasourceId").MustInt64())
+		assert.Equal(t, int64(panelExportMaxDataPoints), queries[0].MaxDataPoints)
+	})
+
+	t.Run("Should use the variables of the query", func(t *testing.T) {
+		sc.fakeReqWithParams("GET", "/api/dashboards/uid/abc/panels/2/export", map[string]string{"var-host": "c.1"}).exec()
+		require.Equal(t, http.StatusOK, sc.resp.Code)
+		assert.Equal(t, `cpu{host=~"c\.1"}`, queries[0].Model.Get("expr").MustString())
+	})
+
+	t.Run("Should export the data as XLSX", func(t *testing.T) {
+		sc.fakeReqWithParams("GET", "/api/dashboards/uid/abc/panels/2/export", map[string]string{"format": "xlsx"}).exec()
+		require.Equal(t, http.StatusOK, sc.resp.Code)
+		assert.Equal(t, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", sc.resp.Header().Get("Content-Type"))
+		assert.Equal(t, "PK", sc.resp.Body.String()[:2])
+	})
+
+	t.Run("Should reject unsupported formats", func(t *testing.T) {
+		sc.fakeReqWithParams("GET", "/api/dashboards/uid/abc/panels/2/export", map[string]string{"format": "pdf"}).exec()
+		require.Equal(t, http.StatusBadRequest, sc.resp.Code)
+	})
+
+	t.Run("Should respond 404 for unknown panels", func(t *testing.T) {
+		sc.fakeReqWithParams("GET", "/api/dashboards/uid/abc/panels/5/export", map[string]string{}).exec()
+		require.Equal(t, http.StatusNotFound, sc.resp.Code)
+	})
+
+	t.Run("Should deny users without access to the data source", func(t *testing.T) {
+		hs.DatasourceCache = &fakeDatasourceCache{err: models.ErrDataSourceAccessDenied}
+		t.Cleanup(func() { hs.DatasourceCache = &fakeDatasourceCache{ds: ds} })
+		sc.fakeReqWithParams("GET", "/api/dashboards/uid/abc/panels/2/export", map[string]string{}).exec()
+		require.Equal(t, http.StatusForbidden, sc.resp.Code)
+	})
+}
+
+func TestInterpolateVariables(t *testing.T) {
+	vars := map[string][]string{"host": {"a", "b"}, "env": {"prod"}}
+	for _, tc := range []struct {
+		in, out string
+	}{
+		{in: "$env", out: "prod"},
+		{in: "[[env]]-${env}", out: "prod-prod"},
+		{in: "$host", out: "a,b"},
+		{in: "${host:pipe}", out: "a|b"},
+		{in: "${host:singlequote}", out: "'a','b'"},
+		{in: "${host:json}", out: `["a","b"]`},
+		{in: "$__interval $unknown", out: "$__interval $unknown"},
+	} {
+		assert.Equal(t, tc.out, interpolateVariables(tc.in, vars), tc.in)
+	}
+}
+
+type fakeDatasourceCache struct {
+	datasources.CacheService
+	ds  *models.DataSource
+	err error
+}
+
+func (c *fakeDatasourceCache) GetDatasource(id int64, user *models.SignedInUser, skipCache bool) (*models.DataSource, error) {
+	return c.ds, c.err
+}
+
+func (c *fakeDatasourceCache) GetDatasourceByUID(uid string, user *models.SignedInUser, skipCache bool) (*models.DataSource, error) {
+	return c.ds, c.err
+}
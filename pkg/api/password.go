@@ -7,6 +7,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/passwordpolicy"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -34,7 +35,7 @@ func SendResetPasswordEmail(c *models.ReqContext, form dtos.SendResetPasswordEma
 	return response.Success("Email sent")
 }
 
-func ResetPassword(c *models.ReqContext, form dtos.ResetUserPasswordForm) response.Response {
+func (hs *HTTPServer) ResetPassword(c *models.ReqContext, form dtos.ResetUserPasswordForm) response.Response {
 	query := models.ValidateResetPasswordCodeQuery{Code: form.Code}
 
 	if err := bus.Dispatch(&query); err != nil {
@@ -48,6 +49,10 @@ func ResetPassword(c *models.ReqContext, form dtos.ResetUserPasswordForm) respon
 		return response.Error(400, "Passwords do not match", nil)
 	}
 
+	if err := passwordpolicy.Validate(c.Req.Context(), hs.Cfg, query.Result, form.NewPassword); err != nil {
+		return passwordPolicyErrorResponse(err)
+	}
+
 	cmd := models.ChangeUserPasswordCommand{}
 	cmd.UserId = query.Result.Id
 	var err error
@@ -62,3 +67,13 @@ func ResetPassword(c *models.ReqContext, form dtos.ResetUserPasswordForm) respon
 
 	return response.Success("User password changed")
 }
+
+// passwordPolicyErrorResponse returns the rule broken by a new password with
+// its code, so that the frontend can explain what to change.
+func passwordPolicyErrorResponse(err error) response.Response {
+	var validationErr *passwordpolicy.ValidationError
+	if errors.As(err, &validationErr) {
+		return response.JSON(400, util.DynMap{"message": validationErr.Message, "code": validationErr.Code})
+	}
+	return response.Error(500, "Failed to validate password", err)
+}
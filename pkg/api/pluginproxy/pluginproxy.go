@@ -2,6 +2,7 @@ package pluginproxy
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
@@ -22,6 +23,7 @@ type templateData struct {
 // NewApiPluginProxy create a plugin proxy
 func NewApiPluginProxy(ctx *models.ReqContext, proxyPath string, route *plugins.AppPluginRoute,
 	appID string, cfg *setting.Cfg) *httputil.ReverseProxy {
+	var tokenProvider *genericAccessTokenProvider
 	director := func(req *http.Request) {
 		query := models.GetPluginSettingByIdQuery{OrgId: ctx.OrgId, PluginId: appID}
 		if err := bus.Dispatch(&query); err != nil {
@@ -66,15 +68,56 @@ func NewApiPluginProxy(ctx *models.ReqContext, proxyPath string, route *plugins.
 
 		applyUserHeader(cfg.SendUserHeader, req, ctx.SignedInUser)
 
+		if err := addQueryString(req, route, data); err != nil {
+			ctx.JsonApiErr(500, "Failed to render plugin URL query string", err)
+			return
+		}
+
 		if err := addHeaders(&req.Header, route, data); err != nil {
 			ctx.JsonApiErr(500, "Failed to render plugin headers", err)
 			return
 		}
 
+		tokenProvider, err = getAppTokenProvider(query.Result, route, data)
+		if err != nil {
+			ctx.JsonApiErr(500, "Failed to render plugin route token auth", err)
+			return
+		}
+		if tokenProvider != nil {
+			token, err := tokenProvider.GetAccessToken()
+			if err != nil {
+				ctx.JsonApiErr(500, "Failed to get access token", err)
+				return
+			}
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		}
+
 		if err := setBodyContent(req, route, data); err != nil {
 			logger.Error("Failed to set plugin route body content", "error", err)
 		}
 	}
 
-	return &httputil.ReverseProxy{Director: director}
+	// a token that is rejected before it expires, for example because it has
+	// been revoked, is refreshed with the next request
+	modifyResponse := func(resp *http.Response) error {
+		if tokenProvider != nil && resp.StatusCode == http.StatusUnauthorized {
+			tokenProvider.invalidateAccessToken()
+		}
+		return nil
+	}
+
+	return &httputil.ReverseProxy{Director: director, ModifyResponse: modifyResponse}
+}
+
+// getAppTokenProvider returns the access token provider of an app plugin route
+// with tokenAuth, which gets access tokens with the parameters of tokenAuth, such
+// as the client credentials of the OAuth 2.0 client credentials grant.
+func getAppTokenProvider(ps *models.PluginSetting, route *plugins.AppPluginRoute,
+	data templateData) (*genericAccessTokenProvider, error) {
+	tokenAuth, err := interpolateAuthParams(route.TokenAuth, data)
+	if err != nil || tokenAuth == nil {
+		return nil, err
+	}
+
+	return newAppGenericAccessTokenProvider(ps, route, tokenAuth), nil
 }
@@ -1,8 +1,11 @@
 package pluginproxy
 
 import (
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/grafana/grafana/pkg/bus"
@@ -182,6 +185,76 @@ func TestPluginProxy(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, `{ "url": "https://dynamic.grafana.com", "secret": "123"	}`, string(content))
 	})
+
+	t.Run("When getting a route with token auth", func(t *testing.T) {
+		clearTokenCache()
+		t.Cleanup(clearTokenCache)
+
+		var tokenRequests []url.Values
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			require.NoError(t, req.ParseForm())
+			tokenRequests = append(tokenRequests, req.PostForm)
+			_, err := fmt.Fprintf(w, `{"access_token": "token-%d", "expires_in": 3600}`, len(tokenRequests))
+			require.NoError(t, err)
+		}))
+		t.Cleanup(server.Close)
+
+		route := &plugins.AppPluginRoute{
+			Path: "api/token",
+			URL:  "https://api.example.com",
+			URLParams: []plugins.AppPluginRouteURLParam{
+				{Name: "tenant", Content: "{{.JsonData.tenant}}"},
+			},
+			TokenAuth: &plugins.JwtTokenAuth{
+				Url: server.URL + "/oauth/token",
+				Params: map[string]string{
+					"grant_type":    "client_credentials",
+					"client_id":     "{{.JsonData.clientId}}",
+					"client_secret": "{{.SecureJsonData.clientSecret}}",
+				},
+			},
+		}
+
+		bus.AddHandler("test", func(query *models.GetPluginSettingByIdQuery) error {
+			query.Result = &models.PluginSetting{
+				OrgId:          1,
+				PluginId:       "my-app",
+				JsonData:       map[string]interface{}{"clientId": "my-client", "tenant": "main"},
+				SecureJsonData: securejsondata.GetEncryptedJsonData(map[string]string{"clientSecret": "my-secret"}),
+			}
+			return nil
+		})
+
+		ctx := &models.ReqContext{SignedInUser: &models.SignedInUser{Login: "test_user"}}
+		proxy := NewApiPluginProxy(ctx, "", route, "my-app", &setting.Cfg{})
+		req, err := http.NewRequest(http.MethodGet, "/api/plugin-proxy/my-app/api/token", nil)
+		require.NoError(t, err)
+		proxy.Director(req)
+
+		assert.Equal(t, "Bearer token-1", req.Header.Get("Authorization"))
+		assert.Equal(t, "main", req.URL.Query().Get("tenant"))
+		require.Len(t, tokenRequests, 1)
+		assert.Equal(t, url.Values{
+			"grant_type":    {"client_credentials"},
+			"client_id":     {"my-client"},
+			"client_secret": {"my-secret"},
+		}, tokenRequests[0])
+
+		t.Run("Should reuse the cached token", func(t *testing.T) {
+			req := getPluginProxiedRequest(t, ctx, &setting.Cfg{}, route)
+			assert.Equal(t, "Bearer token-1", req.Header.Get("Authorization"))
+			require.Len(t, tokenRequests, 1)
+		})
+
+		t.Run("Should refresh a token rejected by the API", func(t *testing.T) {
+			err := proxy.ModifyResponse(&http.Response{StatusCode: http.StatusUnauthorized})
+			require.NoError(t, err)
+
+			req := getPluginProxiedRequest(t, ctx, &setting.Cfg{}, route)
+			assert.Equal(t, "Bearer token-2", req.Header.Get("Authorization"))
+			require.Len(t, tokenRequests, 2)
+		})
+	})
 }
 
 // getPluginProxiedRequest is a helper for easier setup of tests based on global config and ReqContext.
@@ -26,10 +26,9 @@ type tokenCacheType struct {
 }
 
 type genericAccessTokenProvider struct {
-	datasourceId      int64
-	datasourceVersion int
-	route             *plugins.AppPluginRoute
-	authParams        *plugins.JwtTokenAuth
+	cacheKeyPrefix string
+	route          *plugins.AppPluginRoute
+	authParams     *plugins.JwtTokenAuth
 }
 
 type jwtToken struct {
@@ -71,10 +70,20 @@ func (token *jwtToken) UnmarshalJSON(b []byte) error {
 func newGenericAccessTokenProvider(ds *models.DataSource, pluginRoute *plugins.AppPluginRoute,
 	authParams *plugins.JwtTokenAuth) *genericAccessTokenProvider {
 	return &genericAccessTokenProvider{
-		datasourceId:      ds.Id,
-		datasourceVersion: ds.Version,
-		route:             pluginRoute,
-		authParams:        authParams,
+		cacheKeyPrefix: fmt.Sprintf("%v_%v", ds.Id, ds.Version),
+		route:          pluginRoute,
+		authParams:     authParams,
+	}
+}
+
+// newAppGenericAccessTokenProvider returns the access token provider of a route of
+// an app plugin. Its tokens are cached until the settings of the app are updated.
+func newAppGenericAccessTokenProvider(ps *models.PluginSetting, pluginRoute *plugins.AppPluginRoute,
+	authParams *plugins.JwtTokenAuth) *genericAccessTokenProvider {
+	return &genericAccessTokenProvider{
+		cacheKeyPrefix: fmt.Sprintf("app_%v_%v_%v", ps.OrgId, ps.PluginId, ps.Updated.UnixNano()),
+		route:          pluginRoute,
+		authParams:     authParams,
 	}
 }
 
@@ -117,12 +126,23 @@ func (provider *genericAccessTokenProvider) GetAccessToken() (string, error) {
 	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
 		return "", err
 	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("no access token in response of token endpoint, status %d", resp.StatusCode)
+	}
 
 	tokenCache.cache[provider.getAccessTokenCacheKey()] = &token
 	logger.Info("Got new access token", "ExpiresOn", token.ExpiresOn)
 	return token.AccessToken, nil
 }
 
+// invalidateAccessToken removes the cached access token, so that the next request
+// gets a new one.
+func (provider *genericAccessTokenProvider) invalidateAccessToken() {
+	tokenCache.Lock()
+	defer tokenCache.Unlock()
+	delete(tokenCache.cache, provider.getAccessTokenCacheKey())
+}
+
 func (provider *genericAccessTokenProvider) getAccessTokenCacheKey() string {
-	return fmt.Sprintf("%v_%v_%v_%v", provider.datasourceId, provider.datasourceVersion, provider.route.Path, provider.route.Method)
+	return fmt.Sprintf("%v_%v_%v", provider.cacheKeyPrefix, provider.route.Path, provider.route.Method)
 }
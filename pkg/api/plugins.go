@@ -1,26 +1,35 @@
 package api
 
 import (
+	"archive/zip"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/adapters"
 	"github.com/grafana/grafana/pkg/plugins/backendplugin"
 	"github.com/grafana/grafana/pkg/plugins/manager/installer"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
 )
 
+var errArchivedDashboardTooLarge = errors.New("dashboard is larger than the maximum size")
+
 var permittedFileExts = []string{
 	".html", ".xhtml", ".css", ".js", ".json", ".jsonld", ".map", ".mjs",
 	".jpeg", ".jpg", ".png", ".gif", ".svg", ".webp", ".ico",
@@ -67,18 +76,20 @@ func (hs *HTTPServer) GetPluginList(c *models.ReqContext) response.Response {
 		}
 
 		listItem := dtos.PluginListItem{
-			Id:            pluginDef.Id,
-			Name:          pluginDef.Name,
-			Type:          pluginDef.Type,
-			Category:      pluginDef.Category,
-			Info:          &pluginDef.Info,
-			LatestVersion: pluginDef.GrafanaNetVersion,
-			HasUpdate:     pluginDef.GrafanaNetHasUpdate,
-			DefaultNavUrl: pluginDef.DefaultNavUrl,
-			State:         pluginDef.State,
-			Signature:     pluginDef.Signature,
-			SignatureType: pluginDef.SignatureType,
-			SignatureOrg:  pluginDef.SignatureOrg,
+			Id:              pluginDef.Id,
+			Name:            pluginDef.Name,
+			Type:            pluginDef.Type,
+			Category:        pluginDef.Category,
+			Info:            &pluginDef.Info,
+			LatestVersion:   pluginDef.GrafanaNetVersion,
+			HasUpdate:       pluginDef.GrafanaNetHasUpdate,
+			PinnedVersion:   hs.Cfg.PluginVersionPins[pluginDef.Id],
+			DefaultNavUrl:   pluginDef.DefaultNavUrl,
+			State:           pluginDef.State,
+			Signature:       pluginDef.Signature,
+			SignatureType:   pluginDef.SignatureType,
+			SignatureOrg:    pluginDef.SignatureOrg,
+			AngularDetected: pluginDef.AngularDetected,
 		}
 
 		if pluginSetting, exists := pluginSettingsMap[pluginDef.Id]; exists {
@@ -129,12 +140,17 @@ func (hs *HTTPServer) GetPluginSettingByID(c *models.ReqContext) response.Respon
 		DefaultNavUrl: def.DefaultNavUrl,
 		LatestVersion: def.GrafanaNetVersion,
 		HasUpdate:     def.GrafanaNetHasUpdate,
+		PinnedVersion: hs.Cfg.PluginVersionPins[def.Id],
 		State:         def.State,
 		Signature:     def.Signature,
 		SignatureType: def.SignatureType,
 		SignatureOrg:  def.SignatureOrg,
 	}
 
+	if status, ok := hs.BackendPluginManager.Status(def.Id); ok {
+		dto.BackendStatus = &status
+	}
+
 	if app := hs.PluginManager.GetApp(def.Id); app != nil {
 		dto.Enabled = app.AutoEnabled
 		dto.Pinned = app.AutoEnabled
@@ -157,12 +173,17 @@ func (hs *HTTPServer) GetPluginSettingByID(c *models.ReqContext) response.Respon
 func (hs *HTTPServer) UpdatePluginSetting(c *models.ReqContext, cmd models.UpdatePluginSettingCmd) response.Response {
 	pluginID := c.Params(":pluginId")
 
-	if app := hs.PluginManager.GetApp(pluginID); app == nil {
+	app := hs.PluginManager.GetApp(pluginID)
+	if app == nil {
 		return response.Error(404, "Plugin not installed", nil)
 	}
 
 	cmd.OrgId = c.OrgId
 	cmd.PluginId = pluginID
+	if resp := hs.validateAppSettings(c, app, cmd); resp != nil {
+		return resp
+	}
+
 	if err := bus.Dispatch(&cmd); err != nil {
 		return response.Error(500, "Failed to update plugin setting", err)
 	}
@@ -170,6 +191,72 @@ func (hs *HTTPServer) UpdatePluginSetting(c *models.ReqContext, cmd models.Updat
 	return response.Success("Plugin settings updated")
 }
 
+// validateAppSettings validates the settings of an enabled app of a backend
+// plugin with the plugin, before they are saved. The saved secure settings are
+// kept unless they are replaced.
+func (hs *HTTPServer) validateAppSettings(c *models.ReqContext, app *plugins.AppPlugin, cmd models.UpdatePluginSettingCmd) response.Response {
+	if !app.Backend || !cmd.Enabled {
+		return nil
+	}
+
+	secureJSONData := map[string]string{}
+	query := models.GetPluginSettingByIdQuery{PluginId: cmd.PluginId, OrgId: cmd.OrgId}
+	if err := bus.Dispatch(&query); err == nil {
+		secureJSONData = query.Result.SecureJsonData.Decrypt()
+	} else if !errors.Is(err, models.ErrPluginSettingNotFound) {
+		return response.Error(500, "Failed to get plugin settings", err)
+	}
+	for key, value := range cmd.SecureJsonData {
+		secureJSONData[key] = value
+	}
+
+	jsonData, err := json.Marshal(cmd.JsonData)
+	if err != nil {
+		return response.Error(400, "Invalid plugin settings", err)
+	}
+
+	return hs.validatePluginSettings(c, backend.PluginContext{
+		OrgID:    c.OrgId,
+		PluginID: app.Id,
+		User:     adapters.BackendUserFromSignedInUser(c.SignedInUser),
+		AppInstanceSettings: &backend.AppInstanceSettings{
+			JSONData:                jsonData,
+			DecryptedSecureJSONData: secureJSONData,
+			Updated:                 time.Now(),
+		},
+	})
+}
+
+// validatePluginSettings validates the settings of a data source or app with
+// its backend plugin, and returns a 400 response with the errors of the plugin
+// if the settings are invalid. Plugins that don't validate their settings
+// accept any settings.
+func (hs *HTTPServer) validatePluginSettings(c *models.ReqContext, pCtx backend.PluginContext) response.Response {
+	if !hs.BackendPluginManager.IsRegistered(pCtx.PluginID) {
+		return nil
+	}
+
+	result, err := hs.BackendPluginManager.ValidateSettings(c.Req.Context(), pCtx)
+	if err != nil {
+		if errors.Is(err, backendplugin.ErrMethodNotImplemented) {
+			return nil
+		}
+		return translatePluginRequestErrorToAPIError(err)
+	}
+	if result.Valid {
+		return nil
+	}
+
+	message := "Invalid plugin settings"
+	if result.Message != "" {
+		message += ": " + result.Message
+	}
+	return response.JSON(400, util.DynMap{
+		"message": message,
+		"fields":  result.Fields,
+	})
+}
+
 func (hs *HTTPServer) GetPluginDashboards(c *models.ReqContext) response.Response {
 	pluginID := c.Params(":pluginId")
 
@@ -241,6 +328,111 @@ func (hs *HTTPServer) ImportDashboard(c *models.ReqContext, apiCmd dtos.ImportDa
 	return response.JSON(200, dashInfo)
 }
 
+// ImportDashboardArchive imports every dashboard JSON file contained in a zip
+// archive uploaded through the uploads API. Failing dashboards don't stop the
+// import of the others and are reported in the results.
+//
+// POST /api/dashboards/import/archive
+func (hs *HTTPServer) ImportDashboardArchive(c *models.ReqContext, apiCmd dtos.ImportDashboardArchiveCommand) response.Response {
+	upload, archivePath, errResp := hs.completedUploadPath(c, apiCmd.UploadUID)
+	if errResp != nil {
+		return errResp
+	}
+
+	archive, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Upload is not a valid zip archive", err)
+	}
+	defer func() {
+		if err := archive.Close(); err != nil {
+			hs.log.Warn("Failed to close dashboard archive", "err", err)
+		}
+	}()
+
+	if len(archive.File) > hs.Cfg.UploadArchiveMaxEntries {
+		return response.Error(http.StatusBadRequest,
+			fmt.Sprintf("Archive contains more than %d files", hs.Cfg.UploadArchiveMaxEntries), nil)
+	}
+
+	results := []dtos.ImportDashboardArchiveResult{}
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || !strings.HasSuffix(strings.ToLower(file.Name), ".json") {
+			continue
+		}
+
+		result := dtos.ImportDashboardArchiveResult{Path: file.Name, Status: "imported"}
+		if err := hs.importArchivedDashboard(c, file, apiCmd, &result); err != nil {
+			result.Status = "failed"
+			result.Message = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	if err := hs.UploadService.Delete(upload); err != nil {
+		hs.log.Warn("Failed to delete dashboard archive upload", "uid", upload.UID, "err", err)
+	}
+
+	return response.JSON(http.StatusOK, results)
+}
+
+func (hs *HTTPServer) importArchivedDashboard(c *models.ReqContext, file *zip.File, apiCmd dtos.ImportDashboardArchiveCommand, result *dtos.ImportDashboardArchiveResult) error {
+	data, err := hs.readArchivedDashboard(file)
+	if err != nil {
+		return err
+	}
+
+	dashboard, err := simplejson.NewJson(data)
+	if err != nil {
+		return err
+	}
+
+	if !hs.LoadSchemaService.IsDisabled() {
+		dashboard, err = hs.LoadSchemaService.DashboardApplyDefaults(dashboard)
+		if err != nil {
+			return err
+		}
+	}
+
+	dashInfo, dash, err := hs.PluginManager.ImportDashboard("", "", c.OrgId, apiCmd.FolderId,
+		dashboard, apiCmd.Overwrite, nil, c.SignedInUser, hs.DataService)
+	if err != nil {
+		return err
+	}
+	result.UID = dash.Uid
+	result.Title = dashInfo.Title
+
+	return hs.LibraryPanelService.ConnectLibraryPanelsForDashboard(c, dash)
+}
+
+// readArchivedDashboard reads a file of a dashboard archive, which must not be
+// larger than the configured maximum once uncompressed. The size in the
+// archive header can't be trusted, so the read is limited as well.
+func (hs *HTTPServer) readArchivedDashboard(file *zip.File) ([]byte, error) {
+	maxSize := hs.Cfg.UploadArchiveMaxUncompressedSize
+	if file.UncompressedSize64 > uint64(maxSize) {
+		return nil, fmt.Errorf("%w of %d bytes", errArchivedDashboardTooLarge, maxSize)
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := reader.Close(); err != nil {
+			hs.log.Warn("Failed to close archived dashboard", "path", file.Name, "err", err)
+		}
+	}()
+
+	data, err := ioutil.ReadAll(io.LimitReader(reader, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("%w of %d bytes", errArchivedDashboardTooLarge, maxSize)
+	}
+	return data, nil
+}
+
 // CollectPluginMetrics collect metrics from a plugin.
 //
 // /api/plugins/:pluginId/metrics
@@ -375,35 +567,39 @@ func (hs *HTTPServer) CallResource(c *models.ReqContext) {
 }
 
 func (hs *HTTPServer) GetPluginErrorsList(_ *models.ReqContext) response.Response {
-	return response.JSON(200, hs.PluginManager.ScanningErrors())
+	return response.JSON(200, hs.PluginManager.PluginErrors())
 }
 
 func (hs *HTTPServer) InstallPlugin(c *models.ReqContext, dto dtos.InstallPluginCommand) response.Response {
 	pluginID := c.Params("pluginId")
 
+	if dto.UploadUID != "" {
+		return hs.installPluginFromUpload(c, pluginID, dto.UploadUID)
+	}
+
 	err := hs.PluginManager.Install(c.Req.Context(), pluginID, dto.Version)
 	if err != nil {
-		var dupeErr plugins.DuplicatePluginError
-		if errors.As(err, &dupeErr) {
-			return response.Error(http.StatusConflict, "Plugin already installed", err)
-		}
-		var versionUnsupportedErr installer.ErrVersionUnsupported
-		if errors.As(err, &versionUnsupportedErr) {
-			return response.Error(http.StatusConflict, "Plugin version not supported", err)
-		}
-		var versionNotFoundErr installer.ErrVersionNotFound
-		if errors.As(err, &versionNotFoundErr) {
-			return response.Error(http.StatusNotFound, "Plugin version not found", err)
-		}
-		var clientError installer.Response4xxError
-		if errors.As(err, &clientError) {
-			return response.Error(clientError.StatusCode, clientError.Message, err)
-		}
-		if errors.Is(err, plugins.ErrInstallCorePlugin) {
-			return response.Error(http.StatusForbidden, "Cannot install or change a Core plugin", err)
-		}
+		return translatePluginInstallErrorToAPIError(err)
+	}
 
-		return response.Error(http.StatusInternalServerError, "Failed to install plugin", err)
+	return response.JSON(http.StatusOK, []byte{})
+}
+
+// installPluginFromUpload installs a plugin from a zip archive uploaded
+// through the uploads API.
+func (hs *HTTPServer) installPluginFromUpload(c *models.ReqContext, pluginID, uploadUID string) response.Response {
+	upload, archivePath, errResp := hs.completedUploadPath(c, uploadUID)
+	if errResp != nil {
+		return errResp
+	}
+
+	err := hs.PluginManager.InstallFromArchive(c.Req.Context(), pluginID, archivePath)
+	if err != nil {
+		return translatePluginInstallErrorToAPIError(err)
+	}
+
+	if err := hs.UploadService.Delete(upload); err != nil {
+		hs.log.Warn("Failed to delete plugin upload", "uid", upload.UID, "err", err)
 	}
 
 	return response.JSON(http.StatusOK, []byte{})
@@ -429,6 +625,38 @@ func (hs *HTTPServer) UninstallPlugin(c *models.ReqContext) response.Response {
 	return response.JSON(http.StatusOK, []byte{})
 }
 
+func translatePluginInstallErrorToAPIError(err error) response.Response {
+	var dupeErr plugins.DuplicatePluginError
+	if errors.As(err, &dupeErr) {
+		return response.Error(http.StatusConflict, "Plugin already installed", err)
+	}
+	var versionUnsupportedErr installer.ErrVersionUnsupported
+	if errors.As(err, &versionUnsupportedErr) {
+		return response.Error(http.StatusConflict, "Plugin version not supported", err)
+	}
+	var versionNotFoundErr installer.ErrVersionNotFound
+	if errors.As(err, &versionNotFoundErr) {
+		return response.Error(http.StatusNotFound, "Plugin version not found", err)
+	}
+	var clientError installer.Response4xxError
+	if errors.As(err, &clientError) {
+		return response.Error(clientError.StatusCode, clientError.Message, err)
+	}
+	var pinnedErr plugins.PluginVersionPinnedError
+	if errors.As(err, &pinnedErr) {
+		return response.Error(http.StatusConflict, "Plugin version is pinned", err)
+	}
+	var signatureErr plugins.PluginSignatureError
+	if errors.As(err, &signatureErr) {
+		return response.Error(http.StatusBadRequest, "Plugin signature validation failed", err)
+	}
+	if errors.Is(err, plugins.ErrInstallCorePlugin) {
+		return response.Error(http.StatusForbidden, "Cannot install or change a Core plugin", err)
+	}
+
+	return response.Error(http.StatusInternalServerError, "Failed to install plugin", err)
+}
+
 func translatePluginRequestErrorToAPIError(err error) response.Response {
 	if errors.Is(err, backendplugin.ErrPluginNotRegistered) {
 		return response.Error(404, "Plugin not found", err)
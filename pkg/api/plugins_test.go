@@ -1,7 +1,15 @@
 package api
 
 import (
+	"archive/zip"
+	"bytes"
+	"strings"
 	"testing"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_accessForbidden(t *testing.T) {
@@ -87,3 +95,45 @@ func Test_accessForbidden(t *testing.T) {
 		})
 	}
 }
+
+func TestReadArchivedDashboard(t *testing.T) {
+	const maxSize = 1024
+
+	cfg := setting.NewCfg()
+	cfg.UploadArchiveMaxUncompressedSize = maxSize
+	hs := &HTTPServer{Cfg: cfg, log: log.New("test")}
+
+	archive := func(t *testing.T, content string) *zip.File {
+		t.Helper()
+		var buf bytes.Buffer
+		w := zip.NewWriter(&buf)
+		f, err := w.Create("dashboard.json")
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+		return r.File[0]
+	}
+
+	t.Run("Should read a dashboard within the maximum size", func(t *testing.T) {
+		data, err := hs.readArchivedDashboard(archive(t, `{"title": "Dashboard"}`))
+		require.NoError(t, err)
+		assert.Equal(t, `{"title": "Dashboard"}`, string(data))
+	})
+
+	t.Run("Should reject a dashboard larger than the maximum size", func(t *testing.T) {
+		_, err := hs.readArchivedDashboard(archive(t, strings.Repeat(" ", maxSize+1)))
+		require.ErrorIs(t, err, errArchivedDashboardTooLarge)
+	})
+
+	t.Run("Should not read past the size in the archive header", func(t *testing.T) {
+		file := archive(t, strings.Repeat(" ", 10*maxSize))
+		file.UncompressedSize64 = 10
+
+		_, err := hs.readArchivedDashboard(file)
+		require.Error(t, err)
+	})
+}
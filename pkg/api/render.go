@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/guardian"
 	"github.com/grafana/grafana/pkg/services/rendering"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -66,6 +68,19 @@ func (hs *HTTPServer) RenderToPng(c *models.ReqContext) {
 		Headers:           headers,
 	})
 	if err != nil {
+		if errors.Is(err, rendering.ErrQueueFull) {
+			c.Resp.Header().Set("Retry-After", "5")
+			c.Handle(hs.Cfg, http.StatusTooManyRequests, "Too many render requests, try again later", err)
+			return
+		}
+		if errors.Is(err, rendering.ErrRenderQuotaExceeded) {
+			c.Handle(hs.Cfg, http.StatusTooManyRequests, "Render quota of the organization exceeded", err)
+			return
+		}
+		if errors.Is(err, rendering.ErrImageSizeLimit) {
+			c.Handle(hs.Cfg, http.StatusBadRequest, err.Error(), err)
+			return
+		}
 		if errors.Is(err, rendering.ErrTimeout) {
 			c.Handle(hs.Cfg, 500, err.Error(), err)
 			return
@@ -78,3 +93,89 @@ func (hs *HTTPServer) RenderToPng(c *models.ReqContext) {
 	c.Resp.Header().Set("Content-Type", "image/png")
 	http.ServeFile(c.Resp, c.Req.Request, result.FilePath)
 }
+
+// GET /api/dashboards/uid/:uid/pdf
+func (hs *HTTPServer) RenderDashboardPDF(c *models.ReqContext) {
+	dash, rsp := getDashboardHelper(c.Req.Context(), c.OrgId, 0, c.Params(":uid"))
+	if rsp != nil {
+		rsp.WriteTo(c)
+		return
+	}
+	g := guardian.New(dash.Id, c.OrgId, c.SignedInUser)
+	if canView, err := g.CanView(); err != nil || !canView {
+		dashboardGuardianResponse(err).WriteTo(c)
+		return
+	}
+
+	query := c.Req.URL.Query()
+	paperSize := rendering.PaperSize(strings.ToLower(query.Get("paper")))
+	if paperSize == "" {
+		paperSize = rendering.PaperA4
+	}
+	if !rendering.ValidPaperSize(paperSize) {
+		c.JsonApiErr(400, fmt.Sprintf("Unsupported paper size %q", paperSize), nil)
+		return
+	}
+	orientation := query.Get("orientation")
+	if orientation != "" && orientation != "portrait" && orientation != "landscape" {
+		c.JsonApiErr(400, fmt.Sprintf("Unsupported orientation %q, expected portrait or landscape", orientation), nil)
+		return
+	}
+	timeout := 60
+	if query.Get("timeout") != "" {
+		var err error
+		if timeout, err = strconv.Atoi(query.Get("timeout")); err != nil {
+			c.JsonApiErr(400, "Render parameters error", fmt.Errorf("cannot parse timeout as int: %s", err))
+			return
+		}
+	}
+
+	// the other parameters, such as the time range and the template variables,
+	// are passed to the dashboard, which is rendered without the navigation
+	for _, key := range []string{"paper", "orientation", "timeout"} {
+		query.Del(key)
+	}
+	query.Set("orgId", strconv.FormatInt(c.OrgId, 10))
+	query.Set("kiosk", "")
+
+	headers := http.Header{}
+	acceptLanguageHeader := c.Req.Header.Values("Accept-Language")
+	if len(acceptLanguageHeader) > 0 {
+		headers["Accept-Language"] = acceptLanguageHeader
+	}
+
+	result, err := hs.RenderService.RenderPDF(c.Req.Context(), rendering.PDFOpts{
+		Timeout:         time.Duration(timeout) * time.Second,
+		OrgID:           c.OrgId,
+		UserID:          c.UserId,
+		OrgRole:         c.OrgRole,
+		Path:            fmt.Sprintf("d/%s/%s?%s", dash.Uid, dash.Slug, query.Encode()),
+		Timezone:        query.Get("tz"),
+		ConcurrentLimit: hs.Cfg.RendererConcurrentRequestLimit,
+		Headers:         headers,
+		PaperSize:       paperSize,
+		Landscape:       orientation == "landscape",
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, rendering.ErrRenderUnavailable):
+			c.JsonApiErr(http.StatusNotImplemented, "No image renderer available", err)
+		case errors.Is(err, rendering.ErrQueueFull):
+			c.Resp.Header().Set("Retry-After", "5")
+			c.JsonApiErr(http.StatusTooManyRequests, "Too many render requests, try again later", err)
+		case errors.Is(err, rendering.ErrRenderQuotaExceeded):
+			c.JsonApiErr(http.StatusTooManyRequests, "Render quota of the organization exceeded", err)
+		case errors.Is(err, rendering.ErrImageSizeLimit):
+			c.JsonApiErr(http.StatusBadRequest, err.Error(), err)
+		case errors.Is(err, rendering.ErrTimeout):
+			c.JsonApiErr(http.StatusInternalServerError, err.Error(), err)
+		default:
+			c.JsonApiErr(http.StatusInternalServerError, "Rendering failed", err)
+		}
+		return
+	}
+
+	c.Resp.Header().Set("Content-Type", "application/pdf")
+	c.Resp.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.pdf"`, dash.Slug))
+	http.ServeFile(c.Resp, c.Req.Request, result.FilePath)
+}
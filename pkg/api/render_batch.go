@@ -0,0 +1,220 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/components/imguploader"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// batchRenderMaxPanels is the maximum number of panels of a batch render request.
+	batchRenderMaxPanels = 100
+	// batchRenderWorkers is the number of panels of a batch that are rendered at
+	// the same time, so that a batch doesn't fill the render queue.
+	batchRenderWorkers = 4
+)
+
+var reBatchRenderName = regexp.MustCompile(`^[\w.-]+$`)
+
+var newBatchImageUploader = func() (imguploader.ImageUploader, error) {
+	return imguploader.NewImageUploader()
+}
+
+// POST /api/render/batch
+func (hs *HTTPServer) BatchRender(c *models.ReqContext, cmd dtos.BatchRenderCommand) response.Response {
+	if len(cmd.Panels) == 0 {
+		return response.Error(http.StatusBadRequest, "No panels to render", nil)
+	}
+	if len(cmd.Panels) > batchRenderMaxPanels {
+		return response.Error(http.StatusBadRequest, fmt.Sprintf("At most %d panels can be rendered in a batch", batchRenderMaxPanels), nil)
+	}
+	if !hs.RenderService.IsAvailable() {
+		return response.Error(http.StatusNotImplemented, "No image renderer available", nil)
+	}
+
+	// the panels are validated before rendering any of them, so that a batch
+	// either renders or fails as a whole on invalid input
+	names := map[string]bool{}
+	dashboards := map[string]*models.Dashboard{}
+	opts := make([]rendering.Opts, len(cmd.Panels))
+	for i, panel := range cmd.Panels {
+		if panel.DashboardUID == "" || panel.PanelID == 0 {
+			return response.Error(http.StatusBadRequest, fmt.Sprintf("Panel %d: dashboardUid and panelId are required", i), nil)
+		}
+		if panel.Name == "" {
+			cmd.Panels[i].Name = fmt.Sprintf("%s-%d", panel.DashboardUID, panel.PanelID)
+		}
+		name := cmd.Panels[i].Name
+		if !reBatchRenderName.MatchString(name) || name == "results" {
+			return response.Error(http.StatusBadRequest, fmt.Sprintf("Panel %d: invalid name %q", i, name), nil)
+		}
+		if names[name] {
+			return response.Error(http.StatusBadRequest, fmt.Sprintf("Panel %d: duplicate name %q", i, name), nil)
+		}
+		names[name] = true
+
+		dash, ok := dashboards[panel.DashboardUID]
+		if !ok {
+			var rsp response.Response
+			if dash, rsp = getDashboardHelper(c.Req.Context(), c.OrgId, 0, panel.DashboardUID); rsp != nil {
+				return rsp
+			}
+			g := guardian.New(dash.Id, c.OrgId, c.SignedInUser)
+			if canView, err := g.CanView(); err != nil || !canView {
+				return dashboardGuardianResponse(err)
+			}
+			dashboards[panel.DashboardUID] = dash
+		}
+
+		opts[i] = batchRenderOpts(c, dash, panel, cmd.Timeout, hs.Cfg.RendererConcurrentRequestLimit)
+	}
+
+	var uploader imguploader.ImageUploader
+	if cmd.Upload {
+		if setting.ImageUploadProvider == "" {
+			return response.Error(http.StatusBadRequest, "No external image storage configured to upload the images to", nil)
+		}
+		var err error
+		if uploader, err = newBatchImageUploader(); err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to create the image uploader", err)
+		}
+	}
+
+	results := make([]dtos.BatchRenderResult, len(cmd.Panels))
+	files := make([]string, len(cmd.Panels))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < batchRenderWorkers && w < len(cmd.Panels); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i].Name = cmd.Panels[i].Name
+				result, err := hs.RenderService.Render(c.Req.Context(), opts[i])
+				if err != nil {
+					hs.log.Warn("Failed to render panel of batch", "path", opts[i].Path, "err", err)
+					results[i].Error = err.Error()
+					continue
+				}
+				if uploader == nil {
+					files[i] = result.FilePath
+					continue
+				}
+				if results[i].URL, err = uploader.Upload(c.Req.Context(), result.FilePath); err != nil {
+					hs.log.Warn("Failed to upload panel image of batch", "path", opts[i].Path, "err", err)
+					results[i].Error = err.Error()
+				}
+			}
+		}()
+	}
+	for i := range cmd.Panels {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if cmd.Upload {
+		return response.JSON(http.StatusOK, results)
+	}
+
+	archive, err := batchRenderArchive(results, files)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to create the archive of the images", err)
+	}
+	return response.Respond(http.StatusOK, archive).
+		SetHeader("Content-Type", "application/zip").
+		SetHeader("Content-Disposition", `attachment; filename="render.zip"`)
+}
+
+func batchRenderOpts(c *models.ReqContext, dash *models.Dashboard, panel dtos.BatchRenderPanel, timeout, concurrentLimit int) rendering.Opts {
+	query := url.Values{}
+	query.Set("orgId", strconv.FormatInt(c.OrgId, 10))
+	query.Set("panelId", strconv.FormatInt(panel.PanelID, 10))
+	if panel.From != "" {
+		query.Set("from", panel.From)
+	}
+	if panel.To != "" {
+		query.Set("to", panel.To)
+	}
+	if panel.Timezone != "" {
+		query.Set("tz", panel.Timezone)
+	}
+	for name, values := range panel.Vars {
+		query["var-"+name] = values
+	}
+
+	opts := rendering.Opts{
+		Width:             panel.Width,
+		Height:            panel.Height,
+		Timeout:           time.Duration(timeout) * time.Second,
+		OrgID:             c.OrgId,
+		UserID:            c.UserId,
+		OrgRole:           c.OrgRole,
+		Path:              fmt.Sprintf("d-solo/%s/%s?%s", dash.Uid, dash.Slug, query.Encode()),
+		Timezone:          panel.Timezone,
+		ConcurrentLimit:   concurrentLimit,
+		DeviceScaleFactor: panel.Scale,
+	}
+	if opts.Width <= 0 {
+		opts.Width = 1000
+	}
+	if opts.Height <= 0 {
+		opts.Height = 500
+	}
+	if timeout <= 0 {
+		opts.Timeout = 60 * time.Second
+	}
+	return opts
+}
+
+// batchRenderArchive returns a zip archive of the rendered images, named after
+// their panels, with the results of all the panels in results.json.
+func batchRenderArchive(results []dtos.BatchRenderResult, files []string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for i, file := range files {
+		if file == "" {
+			continue
+		}
+		// nolint:gosec
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		f, err := w.Create(results[i].Name + ".png")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := f.Write(data); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := w.Create("results.json")
+	if err != nil {
+		return nil, err
+	}
+	if err := json.NewEncoder(f).Encode(results); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,153 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/imguploader"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchRender(t *testing.T) {
+	dash := models.NewDashboard("Test dash")
+	dash.Id = 1
+	dash.Uid = "abc"
+	bus.AddHandlerCtx("test", func(ctx context.Context, query *models.GetDashboardQuery) error {
+		if query.Uid != dash.Uid {
+			return models.ErrDashboardNotFound
+		}
+		query.Result = dash
+		return nil
+	})
+	t.Cleanup(bus.ClearBusHandlers)
+
+	origNewGuardian := guardian.New
+	t.Cleanup(func() { guardian.New = origNewGuardian })
+	guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanViewValue: true})
+
+	renderService := &fakeBatchRenderService{dir: t.TempDir()}
+	hs := &HTTPServer{Cfg: setting.NewCfg(), RenderService: renderService, log: log.New("test")}
+
+	var cmd dtos.BatchRenderCommand
+	sc := setupScenarioContext(t, "/api/render/batch")
+	sc.m.Post("/api/render/batch", routing.Wrap(func(c *models.ReqContext) response.Response {
+		return hs.BatchRender(c, cmd)
+	}))
+
+	t.Run("Should return the images in a zip archive", func(t *testing.T) {
+		cmd = dtos.BatchRenderCommand{Panels: []dtos.BatchRenderPanel{
+			{DashboardUID: "abc", PanelID: 1, From: "now-1d", Vars: map[string][]string{"host": {"a", "b"}}},
+			{DashboardUID: "abc", PanelID: 2, Name: "failing"},
+		}}
+		sc.fakeReq("POST", "/api/render/batch").exec()
+		require.Equal(t, http.StatusOK, sc.resp.Code)
+		assert.Equal(t, "application/zip", sc.resp.Header().Get("Content-Type"))
+
+		r, err := zip.NewReader(bytes.NewReader(sc.resp.Body.Bytes()), int64(sc.resp.Body.Len()))
+		require.NoError(t, err)
+		files := map[string]string{}
+		for _, f := range r.File {
+			rc, err := f.Open()
+			require.NoError(t, err)
+			b, err := ioutil.ReadAll(rc)
+			require.NoError(t, err)
+			files[f.Name] = string(b)
+		}
+
+		require.Len(t, files, 2)
+		assert.Equal(t, "d-solo/abc/test-dash?from=now-1d&orgId=0&panelId=1&var-host=a&var-host=b", files["abc-1.png"])
+		var results []dtos.BatchRenderResult
+		require.NoError(t, json.Unmarshal([]byte(files["results.json"]), &results))
+		assert.Equal(t, []dtos.BatchRenderResult{
+			{Name: "abc-1"},
+			{Name: "failing", Error: "rendering failed"},
+		}, results)
+	})
+
+	t.Run("Should upload the images to the external image storage", func(t *testing.T) {
+		origProvider := setting.ImageUploadProvider
+		origNewUploader := newBatchImageUploader
+		t.Cleanup(func() {
+			setting.ImageUploadProvider = origProvider
+			newBatchImageUploader = origNewUploader
+		})
+		setting.ImageUploadProvider = "s3"
+		newBatchImageUploader = func() (imguploader.ImageUploader, error) {
+			return &fakeBatchImageUploader{}, nil
+		}
+
+		cmd = dtos.BatchRenderCommand{Upload: true, Panels: []dtos.BatchRenderPanel{{DashboardUID: "abc", PanelID: 1}}}
+		sc.fakeReq("POST", "/api/render/batch").exec()
+		require.Equal(t, http.StatusOK, sc.resp.Code)
+
+		var results []dtos.BatchRenderResult
+		require.NoError(t, json.Unmarshal(sc.resp.Body.Bytes(), &results))
+		require.Len(t, results, 1)
+		assert.True(t, strings.HasPrefix(results[0].URL, "https://images.example.com/"))
+	})
+
+	t.Run("Should reject invalid batches", func(t *testing.T) {
+		for _, panels := range [][]dtos.BatchRenderPanel{
+			{},
+			{{DashboardUID: "abc"}},
+			{{DashboardUID: "abc", PanelID: 1, Name: "../image"}},
+			{{DashboardUID: "abc", PanelID: 1}, {DashboardUID: "abc", PanelID: 1}},
+		} {
+			cmd = dtos.BatchRenderCommand{Panels: panels}
+			sc.fakeReq("POST", "/api/render/batch").exec()
+			assert.Equal(t, http.StatusBadRequest, sc.resp.Code)
+		}
+	})
+
+	t.Run("Should fail when a dashboard doesn't exist", func(t *testing.T) {
+		cmd = dtos.BatchRenderCommand{Panels: []dtos.BatchRenderPanel{{DashboardUID: "other", PanelID: 1}}}
+		sc.fakeReq("POST", "/api/render/batch").exec()
+		assert.Equal(t, http.StatusNotFound, sc.resp.Code)
+	})
+}
+
+// fakeBatchRenderService renders images with their path as content, and fails
+// to render the panels with ID 2.
+type fakeBatchRenderService struct {
+	rendering.Service
+	dir string
+}
+
+func (s *fakeBatchRenderService) IsAvailable() bool {
+	return true
+}
+
+func (s *fakeBatchRenderService) Render(ctx context.Context, opts rendering.Opts) (*rendering.RenderResult, error) {
+	if strings.Contains(opts.Path, "panelId=2") {
+		return nil, errors.New("rendering failed")
+	}
+	path := filepath.Join(s.dir, strings.ReplaceAll(opts.Path, "/", "_")+".png")
+	if err := ioutil.WriteFile(path, []byte(opts.Path), 0600); err != nil {
+		return nil, err
+	}
+	return &rendering.RenderResult{FilePath: path}, nil
+}
+
+type fakeBatchImageUploader struct{}
+
+func (u *fakeBatchImageUploader) Upload(ctx context.Context, path string) (string, error) {
+	return "https://images.example.com/" + filepath.Base(path), nil
+}
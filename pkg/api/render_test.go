@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderDashboardPDF(t *testing.T) {
+	dash := models.NewDashboard("Test dash")
+	dash.Id = 1
+	dash.Uid = "abc"
+	bus.AddHandlerCtx("test", func(ctx context.Context, query *models.GetDashboardQuery) error {
+		query.Result = dash
+		return nil
+	})
+	t.Cleanup(bus.ClearBusHandlers)
+
+	origNewGuardian := guardian.New
+	t.Cleanup(func() { guardian.New = origNewGuardian })
+	dashGuardian := &guardian.FakeDashboardGuardian{CanViewValue: true}
+	guardian.MockDashboardGuardian(dashGuardian)
+
+	pdfPath := filepath.Join(t.TempDir(), "dashboard.pdf")
+	require.NoError(t, ioutil.WriteFile(pdfPath, []byte("%PDF-1.4"), 0600))
+	renderService := &fakePDFRenderService{result: &rendering.RenderResult{FilePath: pdfPath}}
+	hs := &HTTPServer{Cfg: setting.NewCfg(), RenderService: renderService}
+
+	sc := setupScenarioContext(t, "/api/dashboards/uid/abc/pdf")
+	sc.m.Get("/api/dashboards/uid/:uid/pdf", hs.RenderDashboardPDF)
+
+	t.Run("Should render the dashboard with the paper options", func(t *testing.T) {
+		sc.fakeReqWithParams("GET", "/api/dashboards/uid/abc/pdf", map[string]string{
+			"paper": "letter", "orientation": "landscape", "from": "now-1h", "var-host": "a",
+		}).exec()
+		require.Equal(t, http.StatusOK, sc.resp.Code)
+		assert.Equal(t, "application/pdf", sc.resp.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="test-dash.pdf"`, sc.resp.Header().Get("Content-Disposition"))
+		assert.Equal(t, "%PDF-1.4", sc.resp.Body.String())
+
+		opts := renderService.opts
+		assert.Equal(t, rendering.PaperLetter, opts.PaperSize)
+		assert.True(t, opts.Landscape)
+		assert.Equal(t, "d/abc/test-dash?from=now-1h&kiosk=&orgId=0&var-host=a", opts.Path)
+	})
+
+	t.Run("Should default to A4 portrait", func(t *testing.T) {
+		sc.fakeReqWithParams("GET", "/api/dashboards/uid/abc/pdf", map[string]string{}).exec()
+		require.Equal(t, http.StatusOK, sc.resp.Code)
+		assert.Equal(t, rendering.PaperA4, renderService.opts.PaperSize)
+		assert.False(t, renderService.opts.Landscape)
+	})
+
+	t.Run("Should reject unsupported paper sizes", func(t *testing.T) {
+		sc.fakeReqWithParams("GET", "/api/dashboards/uid/abc/pdf", map[string]string{"paper": "a5"}).exec()
+		require.Equal(t, http.StatusBadRequest, sc.resp.Code)
+	})
+
+	t.Run("Should respond 429 when the render queue is full", func(t *testing.T) {
+		renderService.err = rendering.ErrQueueFull
+		t.Cleanup(func() { renderService.err = nil })
+		sc.fakeReqWithParams("GET", "/api/dashboards/uid/abc/pdf", map[string]string{}).exec()
+		require.Equal(t, http.StatusTooManyRequests, sc.resp.Code)
+	})
+
+	t.Run("Should deny users who can't view the dashboard", func(t *testing.T) {
+		dashGuardian.CanViewValue = false
+		t.Cleanup(func() { dashGuardian.CanViewValue = true })
+		sc.fakeReqWithParams("GET", "/api/dashboards/uid/abc/pdf", map[string]string{}).exec()
+		require.Equal(t, http.StatusForbidden, sc.resp.Code)
+	})
+}
+
+type fakePDFRenderService struct {
+	rendering.Service
+	opts   rendering.PDFOpts
+	result *rendering.RenderResult
+	err    error
+}
+
+func (s *fakePDFRenderService) RenderPDF(ctx context.Context, opts rendering.PDFOpts) (*rendering.RenderResult, error) {
+	s.opts = opts
+	return s.result, s.err
+}
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+// GetReports returns the reports of the organization that the signed in user
+// can view.
+//
+// GET /api/reports
+func (hs *HTTPServer) GetReports(c *models.ReqContext) response.Response {
+	if hs.ReportsService.IsDisabled() {
+		return response.Error(http.StatusNotFound, "Reports are disabled", nil)
+	}
+
+	all, err := hs.ReportsService.GetReports(c.Req.Context(), c.OrgId)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get reports", err)
+	}
+
+	result := []*reports.Report{}
+	canViewDashboard := map[string]bool{}
+	for _, report := range all {
+		if !isReportManager(c, report) {
+			canView, ok := canViewDashboard[report.DashboardUid]
+			if !ok {
+				canView = canViewReportDashboard(c, report.DashboardUid) == nil
+				canViewDashboard[report.DashboardUid] = canView
+			}
+			if !canView {
+				continue
+			}
+		}
+		result = append(result, report)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// GetReport returns a report of the organization. Only the creator of the
+// report, org admins and the users who can view its dashboard can get it.
+//
+// GET /api/reports/:id
+func (hs *HTTPServer) GetReport(c *models.ReqContext) response.Response {
+	if hs.ReportsService.IsDisabled() {
+		return response.Error(http.StatusNotFound, "Reports are disabled", nil)
+	}
+
+	report, err := hs.ReportsService.GetReport(c.Req.Context(), c.OrgId, c.ParamsInt64(":id"))
+	if err != nil {
+		return reportErrorToApiResponse(err)
+	}
+	if !isReportManager(c, report) {
+		if rsp := canViewReportDashboard(c, report.DashboardUid); rsp != nil {
+			return rsp
+		}
+	}
+	return response.JSON(http.StatusOK, report)
+}
+
+// CreateReport creates a report, whose dashboard is rendered as the signed in
+// user.
+//
+// POST /api/reports
+func (hs *HTTPServer) CreateReport(c *models.ReqContext, cmd reports.SaveReportCommand) response.Response {
+	if hs.ReportsService.IsDisabled() {
+		return response.Error(http.StatusNotFound, "Reports are disabled", nil)
+	}
+	if rsp := canViewReportDashboard(c, cmd.DashboardUID); rsp != nil {
+		return rsp
+	}
+
+	report, err := hs.ReportsService.CreateReport(c.Req.Context(), c.SignedInUser, cmd)
+	if err != nil {
+		return reportErrorToApiResponse(err)
+	}
+	return response.JSON(http.StatusOK, report)
+}
+
+// UpdateReport updates a report, whose dashboard is then rendered as the
+// signed in user. Only the creator of the report and org admins can update it.
+//
+// PUT /api/reports/:id
+func (hs *HTTPServer) UpdateReport(c *models.ReqContext, cmd reports.SaveReportCommand) response.Response {
+	if hs.ReportsService.IsDisabled() {
+		return response.Error(http.StatusNotFound, "Reports are disabled", nil)
+	}
+	if rsp := hs.canManageReport(c); rsp != nil {
+		return rsp
+	}
+	if rsp := canViewReportDashboard(c, cmd.DashboardUID); rsp != nil {
+		return rsp
+	}
+
+	report, err := hs.ReportsService.UpdateReport(c.Req.Context(), c.SignedInUser, c.ParamsInt64(":id"), cmd)
+	if err != nil {
+		return reportErrorToApiResponse(err)
+	}
+	return response.JSON(http.StatusOK, report)
+}
+
+// DeleteReport deletes a report. Only the creator of the report and org
+// admins can delete it.
+//
+// DELETE /api/reports/:id
+func (hs *HTTPServer) DeleteReport(c *models.ReqContext) response.Response {
+	if hs.ReportsService.IsDisabled() {
+		return response.Error(http.StatusNotFound, "Reports are disabled", nil)
+	}
+	if rsp := hs.canManageReport(c); rsp != nil {
+		return rsp
+	}
+
+	if err := hs.ReportsService.DeleteReport(c.Req.Context(), c.OrgId, c.ParamsInt64(":id")); err != nil {
+		return reportErrorToApiResponse(err)
+	}
+	return response.Success("Report deleted")
+}
+
+// SendReport sends a report now, regardless of its schedule. Only the creator
+// of the report and org admins can send it.
+//
+// POST /api/reports/:id/send
+func (hs *HTTPServer) SendReport(c *models.ReqContext) response.Response {
+	if hs.ReportsService.IsDisabled() {
+		return response.Error(http.StatusNotFound, "Reports are disabled", nil)
+	}
+	if rsp := hs.canManageReport(c); rsp != nil {
+		return rsp
+	}
+
+	if err := hs.ReportsService.SendReport(c.Req.Context(), c.OrgId, c.ParamsInt64(":id")); err != nil {
+		if errors.Is(err, reports.ErrReportNotFound) {
+			return reportErrorToApiResponse(err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to send report", err)
+	}
+	return response.Success("Report sent")
+}
+
+// canManageReport returns an error response unless the signed in user created
+// the report or is an admin of its organization.
+func (hs *HTTPServer) canManageReport(c *models.ReqContext) response.Response {
+	report, err := hs.ReportsService.GetReport(c.Req.Context(), c.OrgId, c.ParamsInt64(":id"))
+	if err != nil {
+		return reportErrorToApiResponse(err)
+	}
+
+	if !isReportManager(c, report) {
+		return response.Error(http.StatusForbidden, "Only the creator of the report or an admin can change it", nil)
+	}
+	return nil
+}
+
+// isReportManager returns whether the signed in user created the report or is
+// an admin of its organization.
+func isReportManager(c *models.ReqContext, report *reports.Report) bool {
+	return report.CreatedBy == c.UserId || c.OrgRole.Includes(models.ROLE_ADMIN)
+}
+
+// canViewReportDashboard returns an error response unless the signed in user
+// can view the dashboard of a report.
+func canViewReportDashboard(c *models.ReqContext, uid string) response.Response {
+	if uid == "" {
+		return response.Error(http.StatusBadRequest, "dashboardUid is required", nil)
+	}
+
+	query := models.GetDashboardQuery{Uid: uid, OrgId: c.OrgId}
+	if err := bus.DispatchCtx(c.Req.Context(), &query); err != nil {
+		if errors.Is(err, models.ErrDashboardNotFound) {
+			return response.Error(http.StatusNotFound, "Dashboard not found", err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to get dashboard", err)
+	}
+
+	g := guardian.New(query.Result.Id, c.OrgId, c.SignedInUser)
+	if canView, err := g.CanView(); err != nil || !canView {
+		return dashboardGuardianResponse(err)
+	}
+	return nil
+}
+
+func reportErrorToApiResponse(err error) response.Response {
+	switch {
+	case errors.Is(err, reports.ErrReportNotFound):
+		return response.Error(http.StatusNotFound, "Report not found", err)
+	case errors.Is(err, reports.ErrInvalidReport):
+		return response.Error(http.StatusBadRequest, err.Error(), err)
+	default:
+		return response.Error(http.StatusInternalServerError, "Failed to save report", err)
+	}
+}
@@ -0,0 +1,186 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/reports"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportsAPIEndpoint_Ownership(t *testing.T) {
+	const otherUserID int64 = 42
+
+	sqlStore := sqlstore.InitTestDB(t)
+	reportsService := &reports.Service{
+		Cfg:      &setting.Cfg{ReportsEnabled: true, ReportsMaxRecipients: 1},
+		SQLStore: sqlStore,
+	}
+	require.NoError(t, reportsService.Init())
+	hs := &HTTPServer{Cfg: setting.NewCfg(), ReportsService: reportsService}
+
+	cmd := reports.SaveReportCommand{
+		Name:         "Weekly",
+		DashboardUID: "abc",
+		Schedule:     "0 8 * * 1",
+		Recipients:   []string{"ops@example.com"},
+	}
+	createReport := func(t *testing.T, createdBy int64) *reports.Report {
+		t.Helper()
+		user := &models.SignedInUser{UserId: createdBy, OrgId: testOrgID, OrgRole: models.ROLE_EDITOR}
+		report, err := reportsService.CreateReport(context.Background(), user, cmd)
+		require.NoError(t, err)
+		return report
+	}
+	setUpDashboard := func(t *testing.T, canView bool) {
+		bus.AddHandlerCtx("test", func(ctx context.Context, query *models.GetDashboardQuery) error {
+			query.Result = &models.Dashboard{Id: 1, Uid: query.Uid, OrgId: query.OrgId}
+			return nil
+		})
+		origNewGuardian := guardian.New
+		t.Cleanup(func() { guardian.New = origNewGuardian })
+		guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanViewValue: canView})
+	}
+
+	reportURL := func(report *reports.Report) string {
+		return fmt.Sprintf("/api/reports/%d", report.Id)
+	}
+	listedReportIDs := func(t *testing.T, sc *scenarioContext) []int64 {
+		t.Helper()
+		require.Equal(t, http.StatusOK, sc.resp.Code)
+		var result []reports.Report
+		require.NoError(t, json.Unmarshal(sc.resp.Body.Bytes(), &result))
+		ids := []int64{}
+		for _, report := range result {
+			ids = append(ids, report.Id)
+		}
+		return ids
+	}
+
+	t.Run("Editors who did not create the report", func(t *testing.T) {
+		loggedInUserScenarioWithRole(t, "Should not be able to update it", "PUT", "/api/reports/1", "/api/reports/:id",
+			models.ROLE_EDITOR, func(sc *scenarioContext) {
+				setUpDashboard(t, true)
+				report := createReport(t, otherUserID)
+				sc.m.Put("/api/reports/:id", sc.defaultHandler)
+				sc.handlerFunc = func(c *models.ReqContext) response.Response { return hs.UpdateReport(c, cmd) }
+
+				sc.fakeReqWithParams("PUT", reportURL(report), map[string]string{}).exec()
+				assert.Equal(t, http.StatusForbidden, sc.resp.Code)
+			})
+
+		loggedInUserScenarioWithRole(t, "Should not be able to get it without access to its dashboard", "GET", "/api/reports/1", "/api/reports/:id",
+			models.ROLE_EDITOR, func(sc *scenarioContext) {
+				setUpDashboard(t, false)
+				report := createReport(t, otherUserID)
+				sc.handlerFunc = hs.GetReport
+
+				sc.fakeReqWithParams("GET", reportURL(report), map[string]string{}).exec()
+				assert.Equal(t, http.StatusForbidden, sc.resp.Code)
+			})
+
+		loggedInUserScenarioWithRole(t, "Should be able to get it with access to its dashboard", "GET", "/api/reports/1", "/api/reports/:id",
+			models.ROLE_EDITOR, func(sc *scenarioContext) {
+				setUpDashboard(t, true)
+				report := createReport(t, otherUserID)
+				sc.handlerFunc = hs.GetReport
+
+				sc.fakeReqWithParams("GET", reportURL(report), map[string]string{}).exec()
+				assert.Equal(t, http.StatusOK, sc.resp.Code)
+			})
+
+		loggedInUserScenarioWithRole(t, "Should not list it without access to its dashboard", "GET", "/api/reports", "/api/reports",
+			models.ROLE_EDITOR, func(sc *scenarioContext) {
+				setUpDashboard(t, false)
+				other := createReport(t, otherUserID)
+				own := createReport(t, testUserID)
+				sc.handlerFunc = hs.GetReports
+
+				sc.fakeReqWithParams("GET", "/api/reports", map[string]string{}).exec()
+				ids := listedReportIDs(t, sc)
+				assert.Contains(t, ids, own.Id)
+				assert.NotContains(t, ids, other.Id)
+			})
+
+		loggedInUserScenarioWithRole(t, "Should not be able to delete it", "DELETE", "/api/reports/1", "/api/reports/:id",
+			models.ROLE_EDITOR, func(sc *scenarioContext) {
+				report := createReport(t, otherUserID)
+				sc.handlerFunc = hs.DeleteReport
+
+				sc.fakeReqWithParams("DELETE", reportURL(report), map[string]string{}).exec()
+				assert.Equal(t, http.StatusForbidden, sc.resp.Code)
+				_, err := reportsService.GetReport(context.Background(), testOrgID, report.Id)
+				require.NoError(t, err)
+			})
+
+		loggedInUserScenarioWithRole(t, "Should not be able to send it", "POST", "/api/reports/1/send", "/api/reports/:id/send",
+			models.ROLE_EDITOR, func(sc *scenarioContext) {
+				report := createReport(t, otherUserID)
+				sc.m.Post("/api/reports/:id/send", sc.defaultHandler)
+				sc.handlerFunc = hs.SendReport
+
+				sc.fakeReqWithParams("POST", reportURL(report)+"/send", map[string]string{}).exec()
+				assert.Equal(t, http.StatusForbidden, sc.resp.Code)
+			})
+	})
+
+	loggedInUserScenarioWithRole(t, "The creator of the report should be able to update it", "PUT", "/api/reports/1", "/api/reports/:id",
+		models.ROLE_EDITOR, func(sc *scenarioContext) {
+			setUpDashboard(t, true)
+			report := createReport(t, testUserID)
+			sc.m.Put("/api/reports/:id", sc.defaultHandler)
+			sc.handlerFunc = func(c *models.ReqContext) response.Response { return hs.UpdateReport(c, cmd) }
+
+			sc.fakeReqWithParams("PUT", reportURL(report), map[string]string{}).exec()
+			assert.Equal(t, http.StatusOK, sc.resp.Code)
+		})
+
+	loggedInUserScenarioWithRole(t, "The creator of the report should be able to delete it", "DELETE", "/api/reports/1", "/api/reports/:id",
+		models.ROLE_EDITOR, func(sc *scenarioContext) {
+			report := createReport(t, testUserID)
+			sc.handlerFunc = hs.DeleteReport
+
+			sc.fakeReqWithParams("DELETE", reportURL(report), map[string]string{}).exec()
+			assert.Equal(t, http.StatusOK, sc.resp.Code)
+		})
+
+	loggedInUserScenarioWithRole(t, "Org admins should be able to delete any report", "DELETE", "/api/reports/1", "/api/reports/:id",
+		models.ROLE_ADMIN, func(sc *scenarioContext) {
+			report := createReport(t, otherUserID)
+			sc.handlerFunc = hs.DeleteReport
+
+			sc.fakeReqWithParams("DELETE", reportURL(report), map[string]string{}).exec()
+			assert.Equal(t, http.StatusOK, sc.resp.Code)
+		})
+
+	loggedInUserScenarioWithRole(t, "Org admins should be able to list any report", "GET", "/api/reports", "/api/reports",
+		models.ROLE_ADMIN, func(sc *scenarioContext) {
+			setUpDashboard(t, false)
+			report := createReport(t, otherUserID)
+			sc.handlerFunc = hs.GetReports
+
+			sc.fakeReqWithParams("GET", "/api/reports", map[string]string{}).exec()
+			assert.Contains(t, listedReportIDs(t, sc), report.Id)
+		})
+
+	loggedInUserScenarioWithRole(t, "Should return 404 for a report of another organization", "DELETE", "/api/reports/1", "/api/reports/:id",
+		models.ROLE_ADMIN, func(sc *scenarioContext) {
+			user := &models.SignedInUser{UserId: testUserID, OrgId: testOrgID + 1, OrgRole: models.ROLE_EDITOR}
+			report, err := reportsService.CreateReport(context.Background(), user, cmd)
+			require.NoError(t, err)
+			sc.handlerFunc = hs.DeleteReport
+
+			sc.fakeReqWithParams("DELETE", reportURL(report), map[string]string{}).exec()
+			assert.Equal(t, http.StatusNotFound, sc.resp.Code)
+		})
+}
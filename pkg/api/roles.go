@@ -16,6 +16,7 @@ const (
 	ScopeProvisionersPlugins       = "provisioners:plugins"
 	ScopeProvisionersDatasources   = "provisioners:datasources"
 	ScopeProvisionersNotifications = "provisioners:notifications"
+	ScopeProvisionersAlerting      = "provisioners:alerting"
 )
 
 // declareFixedRoles declares to the AccessControl service fixed roles and their
@@ -0,0 +1,984 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// The SCIM 2.0 endpoints let identity providers such as Okta or Azure AD
+// provision the users and teams of the organization the API key belongs to.
+// Groups are mapped to teams. Deprovisioned users are disabled rather than
+// deleted, so their dashboards and history are kept.
+
+const (
+	scimDefaultCount = 100
+	scimMaxCount     = 1000
+)
+
+var errSCIMInvalidFilter = errors.New("only filters of the form 'attribute eq \"value\"' are supported")
+
+// GET /scim/v2/ServiceProviderConfig
+func (hs *HTTPServer) GetSCIMServiceProviderConfig(c *models.ReqContext) response.Response {
+	return scimJSON(http.StatusOK, map[string]interface{}{
+		"schemas":          []string{dtos.SCIMSchemaServiceProviderConfig},
+		"documentationUri": "https://grafana.com/docs/grafana/latest/auth/scim/",
+		"patch":            map[string]bool{"supported": true},
+		"bulk":             map[string]interface{}{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":           map[string]interface{}{"supported": true, "maxResults": scimMaxCount},
+		"changePassword":   map[string]bool{"supported": false},
+		"sort":             map[string]bool{"supported": false},
+		"etag":             map[string]bool{"supported": false},
+		"authenticationSchemes": []map[string]string{{
+			"type":        "oauthbearertoken",
+			"name":        "API key",
+			"description": "Grafana API key with the Admin role",
+		}},
+	})
+}
+
+// GET /scim/v2/Users
+func (hs *HTTPServer) GetSCIMUsers(c *models.ReqContext) response.Response {
+	startIndex, count := scimPagination(c)
+
+	var users []*models.User
+	if filter := c.Query("filter"); filter != "" {
+		attr, value, err := parseSCIMFilter(filter)
+		if err != nil {
+			return scimError(http.StatusBadRequest, "invalidFilter", err.Error())
+		}
+
+		user, err := hs.findSCIMUser(c, attr, value)
+		if err != nil {
+			return scimError(http.StatusBadRequest, "invalidFilter", err.Error())
+		}
+		if user != nil {
+			users = append(users, user)
+		}
+	} else {
+		query := models.GetOrgUsersQuery{OrgId: c.OrgId}
+		if err := bus.Dispatch(&query); err != nil {
+			return scimError(http.StatusInternalServerError, "", "Failed to list users")
+		}
+
+		for _, orgUser := range query.Result {
+			userQuery := models.GetUserByIdQuery{Id: orgUser.UserId}
+			if err := bus.DispatchCtx(c.Req.Context(), &userQuery); err != nil {
+				return scimError(http.StatusInternalServerError, "", "Failed to list users")
+			}
+			users = append(users, userQuery.Result)
+		}
+	}
+
+	total := len(users)
+	from, to := scimPageBounds(total, startIndex, count)
+	users = users[from:to]
+	resources := make([]dtos.SCIMUser, 0, len(users))
+	for _, user := range users {
+		scimUser, err := hs.toSCIMUser(c, user)
+		if err != nil {
+			return scimError(http.StatusInternalServerError, "", "Failed to list users")
+		}
+		resources = append(resources, scimUser)
+	}
+
+	return scimJSON(http.StatusOK, dtos.SCIMListResponse{
+		Schemas:      []string{dtos.SCIMSchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+// GET /scim/v2/Users/:id
+func (hs *HTTPServer) GetSCIMUser(c *models.ReqContext) response.Response {
+	user, resp := hs.getSCIMUser(c)
+	if resp != nil {
+		return resp
+	}
+
+	scimUser, err := hs.toSCIMUser(c, user)
+	if err != nil {
+		return scimError(http.StatusInternalServerError, "", "Failed to get user")
+	}
+	return scimJSON(http.StatusOK, scimUser)
+}
+
+// POST /scim/v2/Users
+func (hs *HTTPServer) CreateSCIMUser(c *models.ReqContext, form dtos.SCIMUser) response.Response {
+	if form.UserName == "" {
+		return scimError(http.StatusBadRequest, "invalidValue", "userName is required")
+	}
+
+	user, err := hs.Login.CreateUser(models.CreateUserCommand{
+		Login:        form.UserName,
+		Email:        scimPrimaryEmail(form),
+		Name:         scimDisplayName(form),
+		IsDisabled:   form.Active != nil && !*form.Active,
+		SkipOrgSetup: true,
+	})
+	if errors.Is(err, models.ErrUserAlreadyExists) {
+		return hs.addExistingSCIMUser(c, form)
+	}
+	if err != nil {
+		return scimError(http.StatusInternalServerError, "", "Failed to create user")
+	}
+
+	err = bus.Dispatch(&models.AddOrgUserCommand{OrgId: c.OrgId, UserId: user.Id, Role: models.RoleType(hs.Cfg.SCIMOrgRole)})
+	if err != nil {
+		return scimError(http.StatusInternalServerError, "", "Failed to add user to organization")
+	}
+	if err := bus.Dispatch(&models.SetUsingOrgCommand{UserId: user.Id, OrgId: c.OrgId}); err != nil {
+		return scimError(http.StatusInternalServerError, "", "Failed to add user to organization")
+	}
+	user.OrgId = c.OrgId
+
+	if err := setSCIMExternalID(user.Id, form.ExternalID); err != nil {
+		return scimError(http.StatusInternalServerError, "", "Failed to create user")
+	}
+
+	scimUser, err := hs.toSCIMUser(c, user)
+	if err != nil {
+		return scimError(http.StatusInternalServerError, "", "Failed to create user")
+	}
+	return scimJSON(http.StatusCreated, scimUser)
+}
+
+// addExistingSCIMUser adds a user who already exists in other organizations
+// to the organization, instead of creating it. The user itself is left
+// unchanged, since it isn't managed by the organization alone.
+func (hs *HTTPServer) addExistingSCIMUser(c *models.ReqContext, form dtos.SCIMUser) response.Response {
+	conflict := scimError(http.StatusConflict, "uniqueness", fmt.Sprintf("User with userName '%s' already exists", form.UserName))
+
+	query := models.GetUserByLoginQuery{LoginOrEmail: form.UserName}
+	if err := bus.Dispatch(&query); err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			// the email belongs to another user
+			return conflict
+		}
+		return scimError(http.StatusInternalServerError, "", "Failed to create user")
+	}
+	user := query.Result
+
+	isMember, err := isOrgMember(c, user.Id)
+	if err != nil {
+		return scimError(http.StatusInternalServerError, "", "Failed to create user")
+	}
+	if isMember {
+		return conflict
+	}
+
+	err = bus.Dispatch(&models.AddOrgUserCommand{OrgId: c.OrgId, UserId: user.Id, Role: models.RoleType(hs.Cfg.SCIMOrgRole)})
+	if err != nil {
+		return scimError(http.StatusInternalServerError, "", "Failed to add user to organization")
+	}
+
+	scimUser, err := hs.toSCIMUser(c, user)
+	if err != nil {
+		return scimError(http.StatusInternalServerError, "", "Failed to create user")
+	}
+	return scimJSON(http.StatusCreated, scimUser)
+}
+
+// PUT /scim/v2/Users/:id
+func (hs *HTTPServer) ReplaceSCIMUser(c *models.ReqContext, form dtos.SCIMUser) response.Response {
+	user, resp := hs.getSCIMUser(c)
+	if resp != nil {
+		return resp
+	}
+	if resp := checkSCIMUserManaged(c, user); resp != nil {
+		return resp
+	}
+
+	return hs.saveSCIMUser(c, user, form)
+}
+
+// PATCH /scim/v2/Users/:id
+func (hs *HTTPServer) PatchSCIMUser(c *models.ReqContext, form dtos.SCIMPatchOp) response.Response {
+	user, resp := hs.getSCIMUser(c)
+	if resp != nil {
+		return resp
+	}
+	if resp := checkSCIMUserManaged(c, user); resp != nil {
+		return resp
+	}
+
+	scimUser, err := hs.toSCIMUser(c, user)
+	if err != nil {
+		return scimError(http.StatusInternalServerError, "", "Failed to update user")
+	}
+	for _, op := range form.Operations {
+		if err := applySCIMUserPatch(&scimUser, op); err != nil {
+			return scimError(http.StatusBadRequest, "invalidPath", err.Error())
+		}
+	}
+
+	return hs.saveSCIMUser(c, user, scimUser)
+}
+
+// DeleteSCIMUser deprovisions a user by disabling it and revoking its sessions.
+// Users the organization doesn't manage alone are removed from it instead.
+//
+// DELETE /scim/v2/Users/:id
+func (hs *HTTPServer) DeleteSCIMUser(c *models.ReqContext) response.Response {
+	user, resp := hs.getSCIMUser(c)
+	if resp != nil {
+		return resp
+	}
+	managed, err := isSCIMUserManaged(c, user)
+	if err != nil {
+		return scimError(http.StatusInternalServerError, "", "Failed to deprovision user")
+	}
+	if !managed {
+		if err := bus.Dispatch(&models.RemoveOrgUserCommand{OrgId: c.OrgId, UserId: user.Id}); err != nil {
+			return scimError(http.StatusInternalServerError, "", "Failed to deprovision user")
+		}
+		return response.Empty(http.StatusNoContent)
+	}
+
+	if err := hs.setSCIMUserActive(c, user, false); err != nil {
+		return scimError(http.StatusInternalServerError, "", "Failed to deprovision user")
+	}
+	return response.Empty(http.StatusNoContent)
+}
+
+// GET /scim/v2/Groups
+func (hs *HTTPServer) GetSCIMGroups(c *models.ReqContext) response.Response {
+	startIndex, count := scimPagination(c)
+
+	query := models.SearchTeamsQuery{
+		OrgId:        c.OrgId,
+		SignedInUser: c.SignedInUser,
+		HiddenUsers:  hs.Cfg.HiddenUsers,
+	}
+	if filter := c.Query("filter"); filter != "" {
+		attr, value, err := parseSCIMFilter(filter)
+		if err != nil || attr != "displayname" {
+			return scimError(http.StatusBadRequest, "invalidFilter", errSCIMInvalidFilter.Error())
+		}
+		query.Name = value
+	}
+	if err := bus.Dispatch(&query); err != nil {
+		return scimError(http.StatusInternalServerError, "", "Failed to list groups")
+	}
+
+	total := len(query.Result.Teams)
+	from, to := scimPageBounds(total, startIndex, count)
+	teams := query.Result.Teams[from:to]
+
+	resources := make([]dtos.SCIMGroup, 0, len(teams))
+	for _, team := range teams {
+		group, err := hs.toSCIMGroup(c, team)
+		if err != nil {
+			return scimError(http.StatusInternalServerError, "", "Failed to list groups")
+		}
+		resources = append(resources, group)
+	}
+
+	return scimJSON(http.StatusOK, dtos.SCIMListResponse{
+		Schemas:      []string{dtos.SCIMSchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+// GET /scim/v2/Groups/:id
+func (hs *HTTPServer) GetSCIMGroup(c *models.ReqContext) response.Response {
+	team, resp := hs.getSCIMGroup(c)
+	if resp != nil {
+		return resp
+	}
+
+	group, err := hs.toSCIMGroup(c, team)
+	if err != nil {
+		return scimError(http.StatusInternalServerError, "", "Failed to get group")
+	}
+	return scimJSON(http.StatusOK, group)
+}
+
+// POST /scim/v2/Groups
+func (hs *HTTPServer) CreateSCIMGroup(c *models.ReqContext, form dtos.SCIMGroup) response.Response {
+	if form.DisplayName == "" {
+		return scimError(http.StatusBadRequest, "invalidValue", "displayName is required")
+	}
+
+	team, err := createTeam(hs.SQLStore, form.DisplayName, "", c.OrgId)
+	if err != nil {
+		if errors.Is(err, models.ErrTeamNameTaken) {
+			return scimError(http.StatusConflict, "uniqueness", fmt.Sprintf("Group with displayName '%s' already exists", form.DisplayName))
+		}
+		return scimError(http.StatusInternalServerError, "", "Failed to create group")
+	}
+
+	if err := hs.syncSCIMGroupMembers(c, team.Id, form.Members); err != nil {
+		return scimError(http.StatusInternalServerError, "", "Failed to add group members")
+	}
+
+	group, err := hs.toSCIMGroup(c, &models.TeamDTO{Id: team.Id, OrgId: team.OrgId, Name: team.Name})
+	if err != nil {
+		return scimError(http.StatusInternalServerError, "", "Failed to create group")
+	}
+	return scimJSON(http.StatusCreated, group)
+}
+
+// PUT /scim/v2/Groups/:id
+func (hs *HTTPServer) ReplaceSCIMGroup(c *models.ReqContext, form dtos.SCIMGroup) response.Response {
+	team, resp := hs.getSCIMGroup(c)
+	if resp != nil {
+		return resp
+	}
+
+	if form.DisplayName == "" {
+		return scimError(http.StatusBadRequest, "invalidValue", "displayName is required")
+	}
+
+	return hs.saveSCIMGroup(c, team, form)
+}
+
+// PATCH /scim/v2/Groups/:id
+func (hs *HTTPServer) PatchSCIMGroup(c *models.ReqContext, form dtos.SCIMPatchOp) response.Response {
+	team, resp := hs.getSCIMGroup(c)
+	if resp != nil {
+		return resp
+	}
+
+	group, err := hs.toSCIMGroup(c, team)
+	if err != nil {
+		return scimError(http.StatusInternalServerError, "", "Failed to update group")
+	}
+	for _, op := range form.Operations {
+		if err := applySCIMGroupPatch(&group, op); err != nil {
+			return scimError(http.StatusBadRequest, "invalidPath", err.Error())
+		}
+	}
+
+	return hs.saveSCIMGroup(c, team, group)
+}
+
+// DELETE /scim/v2/Groups/:id
+func (hs *HTTPServer) DeleteSCIMGroup(c *models.ReqContext) response.Response {
+	team, resp := hs.getSCIMGroup(c)
+	if resp != nil {
+		return resp
+	}
+
+	if err := bus.Dispatch(&models.DeleteTeamCommand{OrgId: c.OrgId, Id: team.Id}); err != nil {
+		return scimError(http.StatusInternalServerError, "", "Failed to delete group")
+	}
+	return response.Empty(http.StatusNoContent)
+}
+
+// getSCIMUser returns the user of the :id parameter if it belongs to the organization.
+func (hs *HTTPServer) getSCIMUser(c *models.ReqContext) (*models.User, response.Response) {
+	userID, err := strconv.ParseInt(c.Params(":id"), 10, 64)
+	if err != nil {
+		return nil, scimError(http.StatusNotFound, "", "User not found")
+	}
+
+	query := models.GetUserByIdQuery{Id: userID}
+	if err := bus.Dispatch(&query); err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			return nil, scimError(http.StatusNotFound, "", "User not found")
+		}
+		return nil, scimError(http.StatusInternalServerError, "", "Failed to get user")
+	}
+
+	isMember, err := isOrgMember(c, userID)
+	if err != nil {
+		return nil, scimError(http.StatusInternalServerError, "", "Failed to get user")
+	}
+	if !isMember {
+		return nil, scimError(http.StatusNotFound, "", "User not found")
+	}
+
+	return query.Result, nil
+}
+
+// findSCIMUser returns the user of the organization matching a filter on
+// userName, externalId or emails.value, or nil if none does.
+func (hs *HTTPServer) findSCIMUser(c *models.ReqContext, attr, value string) (*models.User, error) {
+	var user *models.User
+	switch attr {
+	case "username":
+		query := models.GetUserByLoginQuery{LoginOrEmail: value}
+		if err := bus.Dispatch(&query); err != nil {
+			if errors.Is(err, models.ErrUserNotFound) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		user = query.Result
+	case "emails", "emails.value":
+		query := models.GetUserByEmailQuery{Email: value}
+		if err := bus.Dispatch(&query); err != nil {
+			if errors.Is(err, models.ErrUserNotFound) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		user = query.Result
+	case "externalid":
+		if value == "" {
+			return nil, nil
+		}
+		authQuery := models.GetAuthInfoQuery{AuthModule: models.AuthModuleSCIM, AuthId: value}
+		if err := bus.Dispatch(&authQuery); err != nil {
+			if errors.Is(err, models.ErrUserNotFound) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		query := models.GetUserByIdQuery{Id: authQuery.Result.UserId}
+		if err := bus.Dispatch(&query); err != nil {
+			return nil, err
+		}
+		user = query.Result
+	default:
+		return nil, errSCIMInvalidFilter
+	}
+
+	isMember, err := isOrgMember(c, user.Id)
+	if err != nil || !isMember {
+		return nil, err
+	}
+	return user, nil
+}
+
+// saveSCIMUser updates a user from its SCIM representation.
+func (hs *HTTPServer) saveSCIMUser(c *models.ReqContext, user *models.User, form dtos.SCIMUser) response.Response {
+	if form.UserName == "" {
+		return scimError(http.StatusBadRequest, "invalidValue", "userName is required")
+	}
+
+	cmd := models.UpdateUserCommand{
+		UserId: user.Id,
+		Login:  form.UserName,
+		Email:  scimPrimaryEmail(form),
+		Name:   scimDisplayName(form),
+		Theme:  user.Theme,
+	}
+	if cmd.Email == "" {
+		cmd.Email = user.Email
+	}
+	for _, loginOrEmail := range []string{cmd.Login, cmd.Email} {
+		query := models.GetUserByLoginQuery{LoginOrEmail: loginOrEmail}
+		if err := bus.Dispatch(&query); err == nil && query.Result.Id != user.Id {
+			return scimError(http.StatusConflict, "uniqueness", fmt.Sprintf("User with userName or email '%s' already exists", loginOrEmail))
+		}
+	}
+	if err := bus.Dispatch(&cmd); err != nil {
+		return scimError(http.StatusInternalServerError, "", "Failed to update user")
+	}
+	user.Login, user.Email, user.Name = cmd.Login, cmd.Email, cmd.Name
+
+	if form.Active != nil && *form.Active == user.IsDisabled {
+		if err := hs.setSCIMUserActive(c, user, *form.Active); err != nil {
+			return scimError(http.StatusInternalServerError, "", "Failed to update user")
+		}
+	}
+
+	if err := setSCIMExternalID(user.Id, form.ExternalID); err != nil {
+		return scimError(http.StatusInternalServerError, "", "Failed to update user")
+	}
+
+	scimUser, err := hs.toSCIMUser(c, user)
+	if err != nil {
+		return scimError(http.StatusInternalServerError, "", "Failed to update user")
+	}
+	return scimJSON(http.StatusOK, scimUser)
+}
+
+// setSCIMExternalID links the user to its identity provider account, which
+// also marks the user as externally managed. The user is unlinked when the
+// external ID is empty.
+func setSCIMExternalID(userID int64, externalID string) error {
+	query := models.GetAuthInfoQuery{UserId: userID, AuthModule: models.AuthModuleSCIM}
+	if err := bus.Dispatch(&query); err != nil {
+		if !errors.Is(err, models.ErrUserNotFound) {
+			return err
+		}
+		if externalID == "" {
+			return nil
+		}
+		return bus.Dispatch(&models.SetAuthInfoCommand{UserId: userID, AuthModule: models.AuthModuleSCIM, AuthId: externalID})
+	}
+
+	if query.Result.AuthId == externalID {
+		return nil
+	}
+	if externalID == "" {
+		return bus.Dispatch(&models.DeleteAuthInfoCommand{UserAuth: query.Result})
+	}
+	return bus.Dispatch(&models.UpdateAuthInfoCommand{UserId: userID, AuthModule: models.AuthModuleSCIM, AuthId: externalID})
+}
+
+func (hs *HTTPServer) setSCIMUserActive(c *models.ReqContext, user *models.User, active bool) error {
+	if err := bus.Dispatch(&models.DisableUserCommand{UserId: user.Id, IsDisabled: !active}); err != nil {
+		return err
+	}
+	user.IsDisabled = !active
+
+	if active {
+		return nil
+	}
+	return hs.AuthTokenService.RevokeAllUserTokens(c.Req.Context(), user.Id)
+}
+
+func (hs *HTTPServer) toSCIMUser(c *models.ReqContext, user *models.User) (dtos.SCIMUser, error) {
+	active := !user.IsDisabled
+	id := strconv.FormatInt(user.Id, 10)
+	scimUser := dtos.SCIMUser{
+		Schemas:     []string{dtos.SCIMSchemaUser},
+		ID:          id,
+		UserName:    user.Login,
+		Name:        dtos.SCIMName{Formatted: user.Name},
+		DisplayName: user.Name,
+		Active:      &active,
+		Groups:      []dtos.SCIMMember{},
+		Meta: &dtos.SCIMMeta{
+			ResourceType: "User",
+			Created:      &user.Created,
+			LastModified: &user.Updated,
+			Location:     hs.Cfg.AppURL + "scim/v2/Users/" + id,
+		},
+	}
+	if user.Email != "" {
+		scimUser.Emails = []dtos.SCIMEmail{{Value: user.Email, Type: "work", Primary: true}}
+	}
+
+	authQuery := models.GetAuthInfoQuery{UserId: user.Id, AuthModule: models.AuthModuleSCIM}
+	if err := bus.Dispatch(&authQuery); err != nil {
+		if !errors.Is(err, models.ErrUserNotFound) {
+			return scimUser, err
+		}
+	} else {
+		scimUser.ExternalID = authQuery.Result.AuthId
+	}
+
+	teamsQuery := models.GetTeamsByUserQuery{OrgId: c.OrgId, UserId: user.Id}
+	if err := bus.Dispatch(&teamsQuery); err != nil {
+		return scimUser, err
+	}
+	for _, team := range teamsQuery.Result {
+		scimUser.Groups = append(scimUser.Groups, dtos.SCIMMember{Value: strconv.FormatInt(team.Id, 10), Display: team.Name})
+	}
+
+	return scimUser, nil
+}
+
+// getSCIMGroup returns the team of the :id parameter if it belongs to the organization.
+func (hs *HTTPServer) getSCIMGroup(c *models.ReqContext) (*models.TeamDTO, response.Response) {
+	teamID, err := strconv.ParseInt(c.Params(":id"), 10, 64)
+	if err != nil {
+		return nil, scimError(http.StatusNotFound, "", "Group not found")
+	}
+
+	query := models.GetTeamByIdQuery{OrgId: c.OrgId, Id: teamID, SignedInUser: c.SignedInUser, HiddenUsers: hs.Cfg.HiddenUsers}
+	if err := bus.Dispatch(&query); err != nil {
+		if errors.Is(err, models.ErrTeamNotFound) {
+			return nil, scimError(http.StatusNotFound, "", "Group not found")
+		}
+		return nil, scimError(http.StatusInternalServerError, "", "Failed to get group")
+	}
+
+	return query.Result, nil
+}
+
+// saveSCIMGroup updates a team and its members from its SCIM representation.
+func (hs *HTTPServer) saveSCIMGroup(c *models.ReqContext, team *models.TeamDTO, form dtos.SCIMGroup) response.Response {
+	if form.DisplayName != team.Name {
+		cmd := models.UpdateTeamCommand{OrgId: c.OrgId, Id: team.Id, Name: form.DisplayName, Email: team.Email}
+		if err := bus.Dispatch(&cmd); err != nil {
+			if errors.Is(err, models.ErrTeamNameTaken) {
+				return scimError(http.StatusConflict, "uniqueness", fmt.Sprintf("Group with displayName '%s' already exists", form.DisplayName))
+			}
+			return scimError(http.StatusInternalServerError, "", "Failed to update group")
+		}
+		team.Name = form.DisplayName
+	}
+
+	if err := hs.syncSCIMGroupMembers(c, team.Id, form.Members); err != nil {
+		return scimError(http.StatusInternalServerError, "", "Failed to update group members")
+	}
+
+	group, err := hs.toSCIMGroup(c, team)
+	if err != nil {
+		return scimError(http.StatusInternalServerError, "", "Failed to update group")
+	}
+	return scimJSON(http.StatusOK, group)
+}
+
+// syncSCIMGroupMembers makes the members of the team match the given ones.
+// Unknown users and users outside of the organization are ignored.
+func (hs *HTTPServer) syncSCIMGroupMembers(c *models.ReqContext, teamID int64, members []dtos.SCIMMember) error {
+	query := models.GetTeamMembersQuery{OrgId: c.OrgId, TeamId: teamID}
+	if err := bus.Dispatch(&query); err != nil {
+		return err
+	}
+
+	wanted := map[int64]bool{}
+	for _, member := range members {
+		userID, err := strconv.ParseInt(member.Value, 10, 64)
+		if err != nil {
+			continue
+		}
+		wanted[userID] = true
+	}
+
+	for _, member := range query.Result {
+		if wanted[member.UserId] {
+			delete(wanted, member.UserId)
+			continue
+		}
+		cmd := models.RemoveTeamMemberCommand{OrgId: c.OrgId, TeamId: teamID, UserId: member.UserId}
+		if err := bus.Dispatch(&cmd); err != nil && !errors.Is(err, models.ErrTeamMemberNotFound) {
+			return err
+		}
+	}
+
+	for userID := range wanted {
+		isMember, err := isOrgMember(c, userID)
+		if err != nil {
+			return err
+		}
+		if !isMember {
+			c.Logger.Warn("Ignoring SCIM group member outside of the organization", "userId", userID, "teamId", teamID)
+			continue
+		}
+		if err := addTeamMember(hs.SQLStore, userID, c.OrgId, teamID, true, 0); err != nil && !errors.Is(err, models.ErrTeamMemberAlreadyAdded) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (hs *HTTPServer) toSCIMGroup(c *models.ReqContext, team *models.TeamDTO) (dtos.SCIMGroup, error) {
+	id := strconv.FormatInt(team.Id, 10)
+	group := dtos.SCIMGroup{
+		Schemas:     []string{dtos.SCIMSchemaGroup},
+		ID:          id,
+		DisplayName: team.Name,
+		Members:     []dtos.SCIMMember{},
+		Meta: &dtos.SCIMMeta{
+			ResourceType: "Group",
+			Location:     hs.Cfg.AppURL + "scim/v2/Groups/" + id,
+		},
+	}
+
+	query := models.GetTeamMembersQuery{OrgId: c.OrgId, TeamId: team.Id}
+	if err := bus.Dispatch(&query); err != nil {
+		return group, err
+	}
+	for _, member := range query.Result {
+		group.Members = append(group.Members, dtos.SCIMMember{Value: strconv.FormatInt(member.UserId, 10), Display: member.Login})
+	}
+
+	return group, nil
+}
+
+// checkSCIMUserManaged returns an error response unless the organization
+// manages the user alone.
+func checkSCIMUserManaged(c *models.ReqContext, user *models.User) response.Response {
+	managed, err := isSCIMUserManaged(c, user)
+	if err != nil {
+		return scimError(http.StatusInternalServerError, "", "Failed to get user")
+	}
+	if !managed {
+		return scimError(http.StatusForbidden, "", "Server admins and users of other organizations can't be changed through SCIM")
+	}
+	return nil
+}
+
+// isSCIMUserManaged returns whether the organization manages the user alone.
+// Changes to users apply to all their organizations, so server admins and
+// users of other organizations can't be changed through the SCIM API of an
+// organization.
+func isSCIMUserManaged(c *models.ReqContext, user *models.User) (bool, error) {
+	if user.IsAdmin {
+		return false, nil
+	}
+
+	query := models.GetUserOrgListQuery{UserId: user.Id}
+	if err := bus.Dispatch(&query); err != nil {
+		return false, err
+	}
+	for _, org := range query.Result {
+		if org.OrgId != c.OrgId {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func isOrgMember(c *models.ReqContext, userID int64) (bool, error) {
+	query := models.GetUserOrgListQuery{UserId: userID}
+	if err := bus.Dispatch(&query); err != nil {
+		return false, err
+	}
+	for _, org := range query.Result {
+		if org.OrgId == c.OrgId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// applySCIMUserPatch applies a PATCH operation to a user. Only the attributes
+// Grafana stores are supported, and remove operations only clear externalId.
+func applySCIMUserPatch(user *dtos.SCIMUser, op dtos.SCIMPatchOperation) error {
+	path := strings.ToLower(op.Path)
+	if path == "" {
+		values, ok := op.Value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("operation without path must have an object value")
+		}
+		for attr, value := range values {
+			if err := applySCIMUserPatch(user, dtos.SCIMPatchOperation{Op: op.Op, Path: attr, Value: value}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if strings.EqualFold(op.Op, "remove") {
+		if path != "externalid" {
+			return fmt.Errorf("attribute '%s' can't be removed", op.Path)
+		}
+		user.ExternalID = ""
+		return nil
+	}
+
+	switch path {
+	case "active":
+		active, err := scimBool(op.Value)
+		if err != nil {
+			return err
+		}
+		user.Active = &active
+		return nil
+	case "emails":
+		emails, ok := op.Value.([]interface{})
+		if !ok || len(emails) == 0 {
+			return fmt.Errorf("emails must be a list")
+		}
+		email, _ := emails[0].(map[string]interface{})
+		value, _ := email["value"].(string)
+		user.Emails = []dtos.SCIMEmail{{Value: value, Primary: true}}
+		return nil
+	}
+
+	value, ok := op.Value.(string)
+	if !ok {
+		return fmt.Errorf("attribute '%s' must be a string", op.Path)
+	}
+	switch path {
+	case "username":
+		user.UserName = value
+	case "externalid":
+		user.ExternalID = value
+	case "displayname", "name.formatted":
+		user.DisplayName = value
+		user.Name.Formatted = value
+	case "name.givenname":
+		user.Name.GivenName = value
+		user.DisplayName, user.Name.Formatted = "", ""
+	case "name.familyname":
+		user.Name.FamilyName = value
+		user.DisplayName, user.Name.Formatted = "", ""
+	case `emails[type eq "work"].value`, "emails.value":
+		user.Emails = []dtos.SCIMEmail{{Value: value, Primary: true}}
+	default:
+		return fmt.Errorf("attribute '%s' is not supported", op.Path)
+	}
+	return nil
+}
+
+// applySCIMGroupPatch applies a PATCH operation to a group.
+func applySCIMGroupPatch(group *dtos.SCIMGroup, op dtos.SCIMPatchOperation) error {
+	path := strings.ToLower(op.Path)
+	if path == "" {
+		values, ok := op.Value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("operation without path must have an object value")
+		}
+		for attr, value := range values {
+			if err := applySCIMGroupPatch(group, dtos.SCIMPatchOperation{Op: op.Op, Path: attr, Value: value}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if path == "displayname" {
+		value, ok := op.Value.(string)
+		if !ok || strings.EqualFold(op.Op, "remove") {
+			return fmt.Errorf("displayName must be a string")
+		}
+		group.DisplayName = value
+		return nil
+	}
+
+	// remove operations can select the member in the path, as in members[value eq "2"]
+	if strings.HasPrefix(path, "members[") && strings.EqualFold(op.Op, "remove") {
+		_, value, err := parseSCIMFilter(strings.TrimSuffix(op.Path[len("members["):], "]"))
+		if err != nil {
+			return err
+		}
+		group.Members = removeSCIMMembers(group.Members, []dtos.SCIMMember{{Value: value}})
+		return nil
+	}
+	if path != "members" {
+		return fmt.Errorf("attribute '%s' is not supported", op.Path)
+	}
+
+	var members []dtos.SCIMMember
+	if values, ok := op.Value.([]interface{}); ok {
+		for _, v := range values {
+			member, _ := v.(map[string]interface{})
+			if value, ok := member["value"].(string); ok {
+				members = append(members, dtos.SCIMMember{Value: value})
+			}
+		}
+	}
+
+	switch strings.ToLower(op.Op) {
+	case "add":
+		group.Members = append(removeSCIMMembers(group.Members, members), members...)
+	case "replace":
+		group.Members = members
+	case "remove":
+		if op.Value == nil {
+			group.Members = nil
+		} else {
+			group.Members = removeSCIMMembers(group.Members, members)
+		}
+	default:
+		return fmt.Errorf("operation '%s' is not supported", op.Op)
+	}
+	return nil
+}
+
+func removeSCIMMembers(members, removed []dtos.SCIMMember) []dtos.SCIMMember {
+	result := make([]dtos.SCIMMember, 0, len(members))
+	for _, member := range members {
+		found := false
+		for _, r := range removed {
+			if r.Value == member.Value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, member)
+		}
+	}
+	return result
+}
+
+// parseSCIMFilter parses the filters identity providers use to look up
+// resources, of the form 'attribute eq "value"'. The attribute is lower cased.
+func parseSCIMFilter(filter string) (string, string, error) {
+	parts := strings.SplitN(strings.TrimSpace(filter), " ", 3)
+	if len(parts) != 3 || !strings.EqualFold(parts[1], "eq") {
+		return "", "", errSCIMInvalidFilter
+	}
+
+	value, err := strconv.Unquote(strings.TrimSpace(parts[2]))
+	if err != nil {
+		return "", "", errSCIMInvalidFilter
+	}
+
+	return strings.ToLower(parts[0]), value, nil
+}
+
+func scimPagination(c *models.ReqContext) (int, int) {
+	startIndex := c.QueryInt("startIndex")
+	if startIndex < 1 {
+		startIndex = 1
+	}
+
+	count := scimDefaultCount
+	if c.Query("count") != "" {
+		count = c.QueryInt("count")
+	}
+	if count < 0 {
+		count = 0
+	}
+	if count > scimMaxCount {
+		count = scimMaxCount
+	}
+
+	return startIndex, count
+}
+
+// scimPageBounds returns the slice bounds of a page of results, SCIM start
+// indexes being 1-based.
+func scimPageBounds(total, startIndex, count int) (int, int) {
+	from := startIndex - 1
+	if from > total {
+		from = total
+	}
+	to := from + count
+	if to > total {
+		to = total
+	}
+	return from, to
+}
+
+func scimPrimaryEmail(user dtos.SCIMUser) string {
+	for _, email := range user.Emails {
+		if email.Primary {
+			return email.Value
+		}
+	}
+	if len(user.Emails) > 0 {
+		return user.Emails[0].Value
+	}
+	return ""
+}
+
+func scimDisplayName(user dtos.SCIMUser) string {
+	if user.DisplayName != "" {
+		return user.DisplayName
+	}
+	if user.Name.Formatted != "" {
+		return user.Name.Formatted
+	}
+	return strings.TrimSpace(user.Name.GivenName + " " + user.Name.FamilyName)
+}
+
+// scimBool accepts the boolean strings some identity providers send, such as "False".
+func scimBool(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		return strconv.ParseBool(strings.ToLower(v))
+	default:
+		return false, fmt.Errorf("active must be a boolean")
+	}
+}
+
+func scimJSON(status int, body interface{}) response.Response {
+	return response.JSON(status, body).SetHeader("Content-Type", "application/scim+json")
+}
+
+func scimError(status int, scimType, detail string) response.Response {
+	return scimJSON(status, dtos.SCIMError{
+		Schemas:  []string{dtos.SCIMSchemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+}
@@ -0,0 +1,281 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSCIMUserAPIEndpoint(t *testing.T) {
+	hs := &HTTPServer{Cfg: setting.NewCfg()}
+
+	setUpHandlers := func(orgID int64) {
+		bus.AddHandlerCtx("test", func(ctx context.Context, query *models.GetUserByIdQuery) error {
+			query.Result = &models.User{Id: query.Id, Login: "jdoe", Email: "jdoe@example.com", Name: "John Doe", IsDisabled: true}
+			return nil
+		})
+		bus.AddHandler("test", func(query *models.GetUserOrgListQuery) error {
+			query.Result = []*models.UserOrgDTO{{OrgId: orgID}}
+			return nil
+		})
+		bus.AddHandler("test", func(query *models.GetAuthInfoQuery) error {
+			query.Result = &models.UserAuth{UserId: query.UserId, AuthModule: models.AuthModuleSCIM, AuthId: "00u1"}
+			return nil
+		})
+		bus.AddHandler("test", func(query *models.GetTeamsByUserQuery) error {
+			query.Result = []*models.TeamDTO{{Id: 3, Name: "Ops"}}
+			return nil
+		})
+	}
+
+	loggedInUserScenarioWithRole(t, "When calling GET on", "GET", "/scim/v2/Users/2", "/scim/v2/Users/:id", models.ROLE_ADMIN, func(sc *scenarioContext) {
+		setUpHandlers(testOrgID)
+
+		sc.handlerFunc = hs.GetSCIMUser
+		sc.fakeReqWithParams("GET", sc.url, map[string]string{}).exec()
+
+		require.Equal(t, http.StatusOK, sc.resp.Code)
+		assert.Equal(t, "application/scim+json", sc.resp.Header().Get("Content-Type"))
+
+		var user dtos.SCIMUser
+		require.NoError(t, json.Unmarshal(sc.resp.Body.Bytes(), &user))
+		assert.Equal(t, "2", user.ID)
+		assert.Equal(t, "00u1", user.ExternalID)
+		assert.Equal(t, "jdoe", user.UserName)
+		assert.Equal(t, []dtos.SCIMEmail{{Value: "jdoe@example.com", Type: "work", Primary: true}}, user.Emails)
+		require.NotNil(t, user.Active)
+		assert.False(t, *user.Active)
+		assert.Equal(t, []dtos.SCIMMember{{Value: "3", Display: "Ops"}}, user.Groups)
+	})
+
+	loggedInUserScenarioWithRole(t, "When calling GET on a user of another organization", "GET", "/scim/v2/Users/2", "/scim/v2/Users/:id", models.ROLE_ADMIN, func(sc *scenarioContext) {
+		setUpHandlers(testOrgID + 1)
+
+		sc.handlerFunc = hs.GetSCIMUser
+		sc.fakeReqWithParams("GET", sc.url, map[string]string{}).exec()
+
+		require.Equal(t, http.StatusNotFound, sc.resp.Code)
+
+		var scimErr dtos.SCIMError
+		require.NoError(t, json.Unmarshal(sc.resp.Body.Bytes(), &scimErr))
+		assert.Equal(t, "404", scimErr.Status)
+	})
+
+	loggedInUserScenarioWithRole(t, "When calling PUT on a user of several organizations", "PUT", "/scim/v2/Users/2", "/scim/v2/Users/:id", models.ROLE_ADMIN, func(sc *scenarioContext) {
+		setUpHandlers(testOrgID)
+		bus.AddHandler("test", func(query *models.GetUserOrgListQuery) error {
+			query.Result = []*models.UserOrgDTO{{OrgId: testOrgID}, {OrgId: testOrgID + 1}}
+			return nil
+		})
+		updated := false
+		bus.AddHandler("test", func(cmd *models.UpdateUserCommand) error {
+			updated = true
+			return nil
+		})
+
+		sc.handlerFunc = func(c *models.ReqContext) response.Response {
+			return hs.ReplaceSCIMUser(c, dtos.SCIMUser{UserName: "admin"})
+		}
+		sc.m.Put("/scim/v2/Users/:id", sc.defaultHandler)
+		sc.fakeReqWithParams("PUT", sc.url, map[string]string{}).exec()
+
+		require.Equal(t, http.StatusForbidden, sc.resp.Code)
+		assert.False(t, updated)
+	})
+
+	loggedInUserScenarioWithRole(t, "When calling DELETE on a server admin", "DELETE", "/scim/v2/Users/2", "/scim/v2/Users/:id", models.ROLE_ADMIN, func(sc *scenarioContext) {
+		setUpHandlers(testOrgID)
+		bus.AddHandlerCtx("test", func(ctx context.Context, query *models.GetUserByIdQuery) error {
+			query.Result = &models.User{Id: query.Id, Login: "admin", IsAdmin: true}
+			return nil
+		})
+		disabled := false
+		bus.AddHandler("test", func(cmd *models.DisableUserCommand) error {
+			disabled = true
+			return nil
+		})
+		var removed *models.RemoveOrgUserCommand
+		bus.AddHandler("test", func(cmd *models.RemoveOrgUserCommand) error {
+			removed = cmd
+			return nil
+		})
+
+		sc.handlerFunc = hs.DeleteSCIMUser
+		sc.fakeReqWithParams("DELETE", sc.url, map[string]string{}).exec()
+
+		require.Equal(t, http.StatusNoContent, sc.resp.Code)
+		assert.False(t, disabled)
+		require.NotNil(t, removed)
+		assert.Equal(t, int64(2), removed.UserId)
+		assert.Equal(t, testOrgID, removed.OrgId)
+	})
+
+	loggedInUserScenarioWithRole(t, "When calling POST for a user of another organization", "POST", "/scim/v2/Users", "/scim/v2/Users", models.ROLE_ADMIN, func(sc *scenarioContext) {
+		setUpHandlers(testOrgID + 1)
+		hs := &HTTPServer{Cfg: setting.NewCfg(), Login: fakeLoginService{}}
+		hs.Cfg.SCIMOrgRole = string(models.ROLE_VIEWER)
+		bus.AddHandler("test", func(query *models.GetUserByLoginQuery) error {
+			query.Result = &models.User{Id: 2, Login: query.LoginOrEmail}
+			return nil
+		})
+		var added *models.AddOrgUserCommand
+		bus.AddHandler("test", func(cmd *models.AddOrgUserCommand) error {
+			added = cmd
+			return nil
+		})
+
+		sc.handlerFunc = func(c *models.ReqContext) response.Response {
+			return hs.CreateSCIMUser(c, dtos.SCIMUser{UserName: existingTestLogin})
+		}
+		sc.m.Post("/scim/v2/Users", sc.defaultHandler)
+		sc.fakeReqWithParams("POST", sc.url, map[string]string{}).exec()
+
+		require.Equal(t, http.StatusCreated, sc.resp.Code)
+		require.NotNil(t, added)
+		assert.Equal(t, int64(2), added.UserId)
+		assert.Equal(t, testOrgID, added.OrgId)
+		assert.Equal(t, models.ROLE_VIEWER, added.Role)
+	})
+}
+
+func TestSetSCIMExternalID(t *testing.T) {
+	t.Cleanup(bus.ClearBusHandlers)
+
+	setUpHandlers := func(t *testing.T, authInfo *models.UserAuth) *[]interface{} {
+		t.Helper()
+		bus.ClearBusHandlers()
+		bus.AddHandler("test", func(query *models.GetAuthInfoQuery) error {
+			if authInfo == nil {
+				return models.ErrUserNotFound
+			}
+			query.Result = authInfo
+			return nil
+		})
+		var cmds []interface{}
+		bus.AddHandler("test", func(cmd *models.SetAuthInfoCommand) error {
+			cmds = append(cmds, cmd)
+			return nil
+		})
+		bus.AddHandler("test", func(cmd *models.UpdateAuthInfoCommand) error {
+			cmds = append(cmds, cmd)
+			return nil
+		})
+		bus.AddHandler("test", func(cmd *models.DeleteAuthInfoCommand) error {
+			cmds = append(cmds, cmd)
+			return nil
+		})
+		return &cmds
+	}
+
+	t.Run("Should link a user to the external ID", func(t *testing.T) {
+		cmds := setUpHandlers(t, nil)
+		require.NoError(t, setSCIMExternalID(2, "00u1"))
+		assert.Equal(t, []interface{}{&models.SetAuthInfoCommand{UserId: 2, AuthModule: models.AuthModuleSCIM, AuthId: "00u1"}}, *cmds)
+	})
+
+	t.Run("Should not link a user without an external ID", func(t *testing.T) {
+		cmds := setUpHandlers(t, nil)
+		require.NoError(t, setSCIMExternalID(2, ""))
+		assert.Empty(t, *cmds)
+	})
+
+	t.Run("Should unlink a user when the external ID is removed", func(t *testing.T) {
+		authInfo := &models.UserAuth{UserId: 2, AuthModule: models.AuthModuleSCIM, AuthId: "00u1"}
+		cmds := setUpHandlers(t, authInfo)
+		require.NoError(t, setSCIMExternalID(2, ""))
+		assert.Equal(t, []interface{}{&models.DeleteAuthInfoCommand{UserAuth: authInfo}}, *cmds)
+	})
+}
+
+func TestParseSCIMFilter(t *testing.T) {
+	attr, value, err := parseSCIMFilter(`userName eq "jdoe@example.com"`)
+	require.NoError(t, err)
+	assert.Equal(t, "username", attr)
+	assert.Equal(t, "jdoe@example.com", value)
+
+	attr, value, err = parseSCIMFilter(`displayName EQ "Site Reliability"`)
+	require.NoError(t, err)
+	assert.Equal(t, "displayname", attr)
+	assert.Equal(t, "Site Reliability", value)
+
+	for _, filter := range []string{`userName sw "j"`, `userName eq jdoe`, `userName eq "a" and active eq true`} {
+		_, _, err := parseSCIMFilter(filter)
+		assert.ErrorIs(t, err, errSCIMInvalidFilter, filter)
+	}
+}
+
+func TestApplySCIMUserPatch(t *testing.T) {
+	t.Run("replaces attributes by path", func(t *testing.T) {
+		user := dtos.SCIMUser{UserName: "jdoe"}
+		require.NoError(t, applySCIMUserPatch(&user, dtos.SCIMPatchOperation{Op: "Replace", Path: "active", Value: "False"}))
+		require.NoError(t, applySCIMUserPatch(&user, dtos.SCIMPatchOperation{Op: "replace", Path: `emails[type eq "work"].value`, Value: "john@example.com"}))
+		require.NoError(t, applySCIMUserPatch(&user, dtos.SCIMPatchOperation{Op: "replace", Path: "name.givenName", Value: "John"}))
+
+		require.NotNil(t, user.Active)
+		assert.False(t, *user.Active)
+		assert.Equal(t, "john@example.com", scimPrimaryEmail(user))
+		assert.Equal(t, "John", scimDisplayName(user))
+	})
+
+	t.Run("replaces attributes without path", func(t *testing.T) {
+		user := dtos.SCIMUser{UserName: "jdoe", ExternalID: "00u1"}
+		op := dtos.SCIMPatchOperation{Op: "replace", Value: map[string]interface{}{"active": true, "userName": "john"}}
+		require.NoError(t, applySCIMUserPatch(&user, op))
+		require.NoError(t, applySCIMUserPatch(&user, dtos.SCIMPatchOperation{Op: "remove", Path: "externalId"}))
+
+		require.NotNil(t, user.Active)
+		assert.True(t, *user.Active)
+		assert.Equal(t, "john", user.UserName)
+		assert.Empty(t, user.ExternalID)
+	})
+
+	t.Run("rejects unsupported attributes", func(t *testing.T) {
+		user := dtos.SCIMUser{}
+		require.Error(t, applySCIMUserPatch(&user, dtos.SCIMPatchOperation{Op: "replace", Path: "title", Value: "CEO"}))
+		require.Error(t, applySCIMUserPatch(&user, dtos.SCIMPatchOperation{Op: "remove", Path: "userName"}))
+	})
+}
+
+func TestApplySCIMGroupPatch(t *testing.T) {
+	members := func(values ...string) []interface{} {
+		result := make([]interface{}, 0, len(values))
+		for _, v := range values {
+			result = append(result, map[string]interface{}{"value": v})
+		}
+		return result
+	}
+
+	group := dtos.SCIMGroup{DisplayName: "Ops", Members: []dtos.SCIMMember{{Value: "1"}}}
+
+	require.NoError(t, applySCIMGroupPatch(&group, dtos.SCIMPatchOperation{Op: "add", Path: "members", Value: members("1", "2", "3")}))
+	assert.Equal(t, []dtos.SCIMMember{{Value: "1"}, {Value: "2"}, {Value: "3"}}, group.Members)
+
+	require.NoError(t, applySCIMGroupPatch(&group, dtos.SCIMPatchOperation{Op: "remove", Path: `members[value eq "2"]`}))
+	require.NoError(t, applySCIMGroupPatch(&group, dtos.SCIMPatchOperation{Op: "Remove", Path: "members", Value: members("3")}))
+	assert.Equal(t, []dtos.SCIMMember{{Value: "1"}}, group.Members)
+
+	op := dtos.SCIMPatchOperation{Op: "replace", Value: map[string]interface{}{"displayName": "SRE", "members": members("4")}}
+	require.NoError(t, applySCIMGroupPatch(&group, op))
+	assert.Equal(t, "SRE", group.DisplayName)
+	assert.Equal(t, []dtos.SCIMMember{{Value: "4"}}, group.Members)
+}
+
+func TestSCIMPageBounds(t *testing.T) {
+	from, to := scimPageBounds(5, 1, 2)
+	assert.Equal(t, []int{0, 2}, []int{from, to})
+
+	from, to = scimPageBounds(5, 5, 2)
+	assert.Equal(t, []int{4, 5}, []int{from, to})
+
+	from, to = scimPageBounds(5, 10, 2)
+	assert.Equal(t, []int{5, 5}, []int{from, to})
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/urlsigner"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// createSignedURL handles requests to create signed URLs, granting access to a rendered image
+// or a snapshot as the signed in user until they expire.
+func (hs *HTTPServer) createSignedURL(c *models.ReqContext, cmd dtos.CreateSignedURLCmd) response.Response {
+	if c.UserId == 0 {
+		return response.Error(400, "Signed URLs can only be created by users", nil)
+	}
+
+	var ttl time.Duration
+	if cmd.TTL != "" {
+		var err error
+		if ttl, err = time.ParseDuration(cmd.TTL); err != nil || ttl <= 0 {
+			return response.Error(400, "Invalid TTL", err)
+		}
+	}
+
+	path := "/" + strings.TrimPrefix(strings.TrimSpace(cmd.Path), "/")
+	signed, err := hs.URLSigner.Sign(path, c.UserId, c.OrgId, ttl)
+	if err != nil {
+		if errors.Is(err, urlsigner.ErrDisabled) {
+			return response.Error(404, "URL signing is disabled", nil)
+		}
+		if errors.Is(err, urlsigner.ErrPathNotSignable) {
+			return response.Error(400, err.Error(), nil)
+		}
+		return response.Error(400, "Invalid path", err)
+	}
+
+	return response.JSON(200, dtos.SignedURL{
+		URL: strings.TrimSuffix(setting.AppUrl, "/") + signed,
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/passwordpolicy"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -73,6 +74,10 @@ func (hs *HTTPServer) SignUpStep2(c *models.ReqContext, form dtos.SignUpStep2For
 		OrgName:  form.OrgName,
 	}
 
+	if err := passwordpolicy.Validate(c.Req.Context(), hs.Cfg, nil, form.Password); err != nil {
+		return passwordPolicyErrorResponse(err)
+	}
+
 	// verify email
 	if setting.VerifyEmailEnabled {
 		if ok, rsp := verifyUserSignUpEmail(form.Email, form.Code); !ok {
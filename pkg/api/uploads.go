@@ -0,0 +1,131 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/uploads"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// The upload endpoints follow the tus protocol: the client creates an upload
+// of a known size and sends chunks with PATCH requests carrying the offset
+// they start at. After an interruption, the client asks for the current
+// offset and continues from there.
+const (
+	uploadOffsetHeader = "Upload-Offset"
+	uploadLengthHeader = "Upload-Length"
+)
+
+// CreateUpload starts a resumable upload.
+//
+// POST /api/uploads
+func (hs *HTTPServer) CreateUpload(c *models.ReqContext, cmd dtos.CreateUploadCommand) response.Response {
+	upload, err := hs.UploadService.Create(c.OrgId, c.UserId, cmd.Size)
+	if err != nil {
+		if errors.Is(err, uploads.ErrInvalidSize) {
+			return response.Error(http.StatusRequestEntityTooLarge, "Upload size must be between 1 byte and the configured maximum", err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to create upload", err)
+	}
+
+	return uploadResponse(http.StatusCreated, upload).
+		SetHeader("Location", setting.AppSubUrl+"/api/uploads/"+upload.UID)
+}
+
+// GetUpload returns the offset to resume an upload from.
+//
+// GET /api/uploads/:uid
+func (hs *HTTPServer) GetUpload(c *models.ReqContext) response.Response {
+	upload, err := hs.UploadService.Get(c.OrgId, c.UserId, c.Params(":uid"))
+	if err != nil {
+		return uploadErrorToApiResponse(err)
+	}
+
+	return uploadResponse(http.StatusOK, upload)
+}
+
+// AppendUpload appends the request body to an upload. The Upload-Offset
+// header has to match the number of bytes received so far.
+//
+// PATCH /api/uploads/:uid
+func (hs *HTTPServer) AppendUpload(c *models.ReqContext) response.Response {
+	offset, err := strconv.ParseInt(c.Req.Header.Get(uploadOffsetHeader), 10, 64)
+	if err != nil || offset < 0 {
+		return response.Error(http.StatusBadRequest, "Upload-Offset header must be set to a non-negative integer", nil)
+	}
+
+	upload, err := hs.UploadService.Get(c.OrgId, c.UserId, c.Params(":uid"))
+	if err != nil {
+		return uploadErrorToApiResponse(err)
+	}
+
+	offset, err = hs.UploadService.Append(upload, offset, c.Req.Request.Body)
+	if err != nil {
+		return uploadErrorToApiResponse(err).SetHeader(uploadOffsetHeader, strconv.FormatInt(offset, 10))
+	}
+
+	return response.Empty(http.StatusNoContent).
+		SetHeader(uploadOffsetHeader, strconv.FormatInt(offset, 10))
+}
+
+// DeleteUpload cancels an upload.
+//
+// DELETE /api/uploads/:uid
+func (hs *HTTPServer) DeleteUpload(c *models.ReqContext) response.Response {
+	upload, err := hs.UploadService.Get(c.OrgId, c.UserId, c.Params(":uid"))
+	if err != nil {
+		return uploadErrorToApiResponse(err)
+	}
+
+	if err := hs.UploadService.Delete(upload); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to delete upload", err)
+	}
+
+	return response.Empty(http.StatusNoContent)
+}
+
+// completedUploadPath returns the path of the data of a complete upload
+// created by the signed in user.
+func (hs *HTTPServer) completedUploadPath(c *models.ReqContext, uid string) (*uploads.Upload, string, response.Response) {
+	upload, err := hs.UploadService.Get(c.OrgId, c.UserId, uid)
+	if err != nil {
+		return nil, "", uploadErrorToApiResponse(err)
+	}
+
+	path, err := hs.UploadService.Path(upload)
+	if err != nil {
+		return nil, "", uploadErrorToApiResponse(err)
+	}
+
+	return upload, path, nil
+}
+
+func uploadResponse(status int, upload *uploads.Upload) *response.NormalResponse {
+	return response.JSON(status, dtos.Upload{
+		UID:    upload.UID,
+		Size:   upload.Size,
+		Offset: upload.Offset,
+	}).
+		SetHeader(uploadOffsetHeader, strconv.FormatInt(upload.Offset, 10)).
+		SetHeader(uploadLengthHeader, strconv.FormatInt(upload.Size, 10))
+}
+
+func uploadErrorToApiResponse(err error) *response.NormalResponse {
+	switch {
+	case errors.Is(err, uploads.ErrUploadNotFound):
+		return response.Error(http.StatusNotFound, "Upload not found", nil)
+	case errors.Is(err, uploads.ErrOffsetMismatch):
+		return response.Error(http.StatusConflict, "Upload-Offset does not match the received bytes", nil)
+	case errors.Is(err, uploads.ErrUploadTooLarge):
+		return response.Error(http.StatusRequestEntityTooLarge, "Chunk exceeds the declared upload size", nil)
+	case errors.Is(err, uploads.ErrUploadIncomplete):
+		return response.Error(http.StatusConflict, "Upload is not complete", nil)
+	default:
+		return response.Error(http.StatusInternalServerError, "Failed to receive upload", err)
+	}
+}
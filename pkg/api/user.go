@@ -7,6 +7,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/passwordpolicy"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -216,7 +217,7 @@ func (hs *HTTPServer) ChangeActiveOrgAndRedirectToHome(c *models.ReqContext) {
 	c.Redirect(hs.Cfg.AppSubURL + "/")
 }
 
-func ChangeUserPassword(c *models.ReqContext, cmd models.ChangeUserPasswordCommand) response.Response {
+func (hs *HTTPServer) ChangeUserPassword(c *models.ReqContext, cmd models.ChangeUserPasswordCommand) response.Response {
 	if setting.LDAPEnabled || setting.AuthProxyEnabled {
 		return response.Error(400, "Not allowed to change password when LDAP or Auth Proxy is enabled", nil)
 	}
@@ -235,9 +236,8 @@ func ChangeUserPassword(c *models.ReqContext, cmd models.ChangeUserPasswordComma
 		return response.Error(401, "Invalid old password", nil)
 	}
 
-	password := models.Password(cmd.NewPassword)
-	if password.IsWeak() {
-		return response.Error(400, "New password is too short", nil)
+	if err := passwordpolicy.Validate(c.Req.Context(), hs.Cfg, userQuery.Result, cmd.NewPassword); err != nil {
+		return passwordPolicyErrorResponse(err)
 	}
 
 	cmd.UserId = c.UserId
@@ -250,6 +250,14 @@ func ChangeUserPassword(c *models.ReqContext, cmd models.ChangeUserPasswordComma
 		return response.Error(500, "Failed to change user password", err)
 	}
 
+	// changing the password signs the user out of all of their sessions, keep them
+	// signed in on the one they changed it from
+	if c.UserToken != nil {
+		if err := hs.loginUserWithUser(userQuery.Result, c); err != nil {
+			return response.Error(500, "Failed to create new session", err)
+		}
+	}
+
 	return response.Success("User password changed")
 }
 
@@ -355,8 +363,14 @@ func GetAuthProviderLabel(authModule string) string {
 		return "GitLab"
 	case "oauth_grafana_com", "oauth_grafananet":
 		return "grafana.com"
-	case "auth.saml":
+	case "auth.saml", "auth.generic_saml":
 		return "SAML"
+	case "scim":
+		return "SCIM"
+	case "kerberos":
+		return "Kerberos"
+	case "clientcert":
+		return "Client certificate"
 	case "ldap", "":
 		return "LDAP"
 	default:
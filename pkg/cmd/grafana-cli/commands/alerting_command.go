@@ -0,0 +1,151 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/services"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+const (
+	alertingExportPath  = "/api/v1/ngalert/export"
+	alertingImportPath  = "/api/v1/ngalert/import"
+	alertingMigratePath = "/api/v1/ngalert/migrate"
+)
+
+// alertingExportCommand writes the alerting configuration of the organization of the API key to
+// the given file, or to stdout.
+func alertingExportCommand(c utils.CommandLine) error {
+	body, err := sendAlertingRequest(c, http.MethodGet, alertingExportPath, "", nil)
+	if err != nil {
+		return err
+	}
+
+	path := c.Args().First()
+	if path == "" || path == "-" {
+		_, err := os.Stdout.Write(body)
+		return err
+	}
+
+	if err := ioutil.WriteFile(path, body, 0600); err != nil {
+		return fmt.Errorf("failed to write the alerting configuration: %w", err)
+	}
+	logger.Infof("%s Alerting configuration exported to %s\n", color.GreenString("✔"), path)
+	return nil
+}
+
+// alertingImportCommand imports the alerting configuration of the given file, or of stdin, into
+// the organization of the API key.
+func alertingImportCommand(c utils.CommandLine) error {
+	path := c.Args().First()
+	if path == "" {
+		return fmt.Errorf("the file to import is required, use - to read it from stdin")
+	}
+
+	var body []byte
+	var err error
+	if path == "-" {
+		body, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		// We can ignore the gosec G304 warning since the path stems from the command line.
+		// nolint:gosec
+		body, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read the alerting configuration: %w", err)
+	}
+
+	if _, err := sendAlertingRequest(c, http.MethodPost, alertingImportPath, "application/yaml", bytes.NewReader(body)); err != nil {
+		return err
+	}
+	logger.Infof("%s Alerting configuration imported\n", color.GreenString("✔"))
+	return nil
+}
+
+// alertingMigrateCommand migrates the dashboard alerts and the notification channels of the organization
+// of the API key to alert rules and contact points, and prints the report of the migration.
+func alertingMigrateCommand(c utils.CommandLine) error {
+	dryRun := c.Bool("dry-run")
+	payload, err := json.Marshal(apimodels.PostableLegacyAlertingMigration{DryRun: dryRun})
+	if err != nil {
+		return err
+	}
+	body, err := sendAlertingRequest(c, http.MethodPost, alertingMigratePath, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	var report apimodels.LegacyAlertingMigrationReport
+	if err := json.Unmarshal(body, &report); err != nil {
+		return fmt.Errorf("failed to read the report of the migration: %w", err)
+	}
+
+	for _, a := range report.MigratedAlerts {
+		logger.Infof("%s Alert %q of panel %d of dashboard %s migrated to alert rule %s\n", color.GreenString("✔"), a.Name, a.PanelID, a.DashboardUID, a.RuleUID)
+	}
+	for _, a := range report.UnmigratedAlerts {
+		logger.Infof("%s Alert %q of panel %d of dashboard %s can't be migrated: %s\n", color.RedString("✘"), a.Name, a.PanelID, a.DashboardUID, a.Error)
+	}
+	for _, ch := range report.UnmigratedChannels {
+		logger.Infof("%s Notification channel %q can't be migrated: %s\n", color.RedString("✘"), ch.Name, ch.Error)
+	}
+
+	summary := fmt.Sprintf("%d alerts migrated, %d alerts and %d notification channels can't be migrated, %d contact points",
+		len(report.MigratedAlerts), len(report.UnmigratedAlerts), len(report.UnmigratedChannels), len(report.ContactPoints))
+	if dryRun {
+		logger.Infof("Dry run: %s\n", summary)
+		return nil
+	}
+	logger.Infof("%s Migration done: %s\n", color.GreenString("✔"), summary)
+	return nil
+}
+
+func sendAlertingRequest(c utils.CommandLine, method string, path string, contentType string, body io.Reader) ([]byte, error) {
+	url := strings.TrimSuffix(c.String("grafana-url"), "/") + path
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	if apiKey := c.String("api-key"); apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	res, err := services.HttpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", url, err)
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode/100 != 2 {
+		var errResponse struct {
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(resBody, &errResponse); err != nil || errResponse.Message == "" {
+			errResponse.Message = http.StatusText(res.StatusCode)
+		}
+		return nil, fmt.Errorf("%s %s failed with status %d: %s", method, url, res.StatusCode, errResponse.Message)
+	}
+	return resBody, nil
+}
@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestAlertingCommands(t *testing.T) {
+	const exported = "apiVersion: 1\ngroups: []\n"
+
+	var imported string
+	var migrations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"Invalid API key"}`))
+			return
+		}
+
+		switch r.URL.Path {
+		case alertingExportPath:
+			w.Header().Set("Content-Type", "application/yaml")
+			_, _ = w.Write([]byte(exported))
+		case alertingImportPath:
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			assert.Equal(t, "application/yaml", r.Header.Get("Content-Type"))
+			imported = string(body)
+			w.WriteHeader(http.StatusAccepted)
+		case alertingMigratePath:
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			migrations = append(migrations, string(body))
+			_, _ = w.Write([]byte(`{"dryRun": true, "migratedAlerts": [{"alertId": 1, "name": "High CPU", "ruleUid": "abc"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	newCommandLine := func(t *testing.T, apiKey string, args ...string) utils.CommandLine {
+		t.Helper()
+		flagSet := flag.NewFlagSet("test", 0)
+		flagSet.String("grafana-url", "", "")
+		flagSet.String("api-key", "", "")
+		flagSet.Bool("dry-run", false, "")
+		require.NoError(t, flagSet.Parse(append([]string{"-grafana-url", server.URL + "/", "-api-key", apiKey}, args...)))
+		return &utils.ContextCommandLine{Context: cli.NewContext(&cli.App{Name: "test"}, flagSet, nil)}
+	}
+
+	path := filepath.Join(t.TempDir(), "alerting.yaml")
+
+	t.Run("Export writes the alerting configuration to the file", func(t *testing.T) {
+		require.NoError(t, alertingExportCommand(newCommandLine(t, "secret", path)))
+
+		content, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, exported, string(content))
+	})
+
+	t.Run("Import posts the alerting configuration of the file", func(t *testing.T) {
+		require.NoError(t, alertingImportCommand(newCommandLine(t, "secret", path)))
+		assert.Equal(t, exported, imported)
+	})
+
+	t.Run("Import requires a file", func(t *testing.T) {
+		require.Error(t, alertingImportCommand(newCommandLine(t, "secret")))
+	})
+
+	t.Run("Migrate posts whether it's a dry run", func(t *testing.T) {
+		require.NoError(t, alertingMigrateCommand(newCommandLine(t, "secret", "-dry-run")))
+		require.NoError(t, alertingMigrateCommand(newCommandLine(t, "secret")))
+		assert.Equal(t, []string{`{"dryRun":true}`, `{"dryRun":false}`}, migrations)
+	})
+
+	t.Run("Errors of the server are returned", func(t *testing.T) {
+		err := alertingExportCommand(newCommandLine(t, "wrong", path))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed with status 401: Invalid API key")
+	})
+}
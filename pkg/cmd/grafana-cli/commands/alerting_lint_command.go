@@ -0,0 +1,371 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/expr"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const alertingFrontendSettingsPath = "/api/frontend/settings"
+
+var yamlErrorLine = regexp.MustCompile(`line (\d+)`)
+
+// lintProblem is a problem of an alerting provisioning file, at the given line.
+type lintProblem struct {
+	File    string
+	Line    int
+	Message string
+}
+
+func (p lintProblem) String() string {
+	return fmt.Sprintf("%s:%d: %s", p.File, p.Line, p.Message)
+}
+
+// lintDatasource is a data source of the Grafana server that the queries of the rules are checked against.
+type lintDatasource struct {
+	UID  string `json:"uid"`
+	Type string `json:"type"`
+	Meta struct {
+		Alerting bool `json:"alerting"`
+		Backend  bool `json:"backend"`
+	} `json:"meta"`
+}
+
+// alertingLintCommand checks the alert rules of the alerting provisioning files of the given directory,
+// or of the given file, and reports the problems with their line numbers. It fails when a problem is
+// found, so that it can be used in CI.
+func alertingLintCommand(c utils.CommandLine) error {
+	path := c.Args().First()
+	if path == "" {
+		return fmt.Errorf("the directory of the alerting provisioning files is required")
+	}
+
+	var datasources map[string]lintDatasource
+	if c.Bool("check-datasources") {
+		var err error
+		if datasources, err = fetchLintDatasources(c); err != nil {
+			return err
+		}
+	}
+
+	files, err := alertingLintFiles(path)
+	if err != nil {
+		return err
+	}
+
+	linter := newAlertingLinter(datasources)
+	for _, file := range files {
+		if err := linter.lintFile(file); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range linter.problems {
+		logger.Errorf("%s\n", p)
+	}
+	if len(linter.problems) > 0 {
+		return fmt.Errorf("found %d problems in %d alerting provisioning files", len(linter.problems), len(files))
+	}
+	logger.Infof("%s No problems found in %d alerting provisioning files\n", color.GreenString("✔"), len(files))
+	return nil
+}
+
+// fetchLintDatasources returns the data sources of the organization of the API key by UID.
+func fetchLintDatasources(c utils.CommandLine) (map[string]lintDatasource, error) {
+	body, err := sendAlertingRequest(c, http.MethodGet, alertingFrontendSettingsPath, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var settings struct {
+		Datasources map[string]lintDatasource `json:"datasources"`
+	}
+	if err := json.Unmarshal(body, &settings); err != nil {
+		return nil, fmt.Errorf("failed to read the data sources: %w", err)
+	}
+
+	datasources := make(map[string]lintDatasource, len(settings.Datasources))
+	for _, ds := range settings.Datasources {
+		datasources[ds.UID] = ds
+	}
+	return datasources, nil
+}
+
+// alertingLintFiles returns the YAML files of the directory, like the provisioning does, or the file itself.
+func alertingLintFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && (strings.HasSuffix(entry.Name(), ".yaml") || strings.HasSuffix(entry.Name(), ".yml")) {
+			files = append(files, filepath.Join(path, entry.Name()))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+type alertingLinter struct {
+	// datasources are the data sources of the Grafana server by UID, the queries aren't checked
+	// against them when nil.
+	datasources map[string]lintDatasource
+	// ruleFiles are the files of the rules by UID, as the rules are provisioned by UID.
+	ruleFiles map[string]string
+	problems  []lintProblem
+}
+
+func newAlertingLinter(datasources map[string]lintDatasource) *alertingLinter {
+	return &alertingLinter{
+		datasources: datasources,
+		ruleFiles:   map[string]string{},
+	}
+}
+
+func (l *alertingLinter) report(file string, node *yaml.Node, format string, args ...interface{}) {
+	line := 0
+	if node != nil {
+		line = node.Line
+	}
+	l.problems = append(l.problems, lintProblem{File: file, Line: line, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *alertingLinter) lintFile(file string) error {
+	// We can ignore the gosec G304 warning since the path stems from the command line.
+	// nolint:gosec
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		p := lintProblem{File: file, Message: err.Error()}
+		if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+			p.Line, _ = strconv.Atoi(m[1])
+		}
+		l.problems = append(l.problems, p)
+		return nil
+	}
+	if len(doc.Content) == 0 {
+		l.report(file, &doc, "the file is empty")
+		return nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		l.report(file, root, "the file should be a mapping of apiVersion, groups, deleteGroups and alertmanager")
+		return nil
+	}
+
+	if version := lintMappingValue(root, "apiVersion"); version == nil {
+		l.report(file, root, "required field apiVersion is missing")
+	} else if version.Value != strconv.Itoa(apimodels.AlertingExportAPIVersion) {
+		l.report(file, version, "unsupported apiVersion %s, expected %d", version.Value, apimodels.AlertingExportAPIVersion)
+	}
+
+	groups := lintMappingValue(root, "groups")
+	if groups == nil {
+		return nil
+	}
+	if groups.Kind != yaml.SequenceNode {
+		l.report(file, groups, "groups should be a list of rule groups")
+		return nil
+	}
+	for i, group := range groups.Content {
+		l.lintGroup(file, i, group)
+	}
+	return nil
+}
+
+func (l *alertingLinter) lintGroup(file string, index int, group *yaml.Node) {
+	var g apimodels.AlertingExportRuleGroup
+	if err := lintDecode(group, &g); err != nil {
+		l.report(file, group, "rule group %d is invalid: %v", index+1, err)
+		return
+	}
+	if g.Folder == "" {
+		l.report(file, group, "rule group %d doesn't contain required field folder", index+1)
+	}
+	if g.Name == "" {
+		l.report(file, group, "rule group %d doesn't contain required field name", index+1)
+	}
+	if g.Interval < 0 || time.Duration(g.Interval)%(10*time.Second) != 0 {
+		l.report(file, lintMappingValue(group, "interval"), "interval %s of rule group %q should be a multiple of the default scheduler interval of 10s", g.Interval, g.Name)
+	}
+	if g.EvaluationOffset < 0 || (g.Interval > 0 && g.EvaluationOffset >= g.Interval) {
+		l.report(file, lintMappingValue(group, "evaluationOffset"), "evaluation offset %s of rule group %q should be shorter than the interval", g.EvaluationOffset, g.Name)
+	}
+
+	rules := lintMappingValue(group, "rules")
+	if rules == nil || rules.Kind != yaml.SequenceNode || len(rules.Content) != len(g.Rules) {
+		l.report(file, group, "rule group %q doesn't contain a list of rules", g.Name)
+		return
+	}
+	for i, rule := range g.Rules {
+		l.lintRule(file, g.Name, i, rule, rules.Content[i])
+	}
+}
+
+func (l *alertingLinter) lintRule(file string, group string, index int, rule apimodels.PostableExtendedRuleNode, node *yaml.Node) {
+	ga := rule.GrafanaManagedAlert
+	gaNode := lintMappingValue(node, "grafana_alert")
+	if ga == nil || gaNode == nil {
+		l.report(file, node, "rule %d of rule group %q isn't a Grafana managed alert rule", index+1, group)
+		return
+	}
+
+	name := fmt.Sprintf("rule %q", ga.Title)
+	if ga.Title == "" {
+		name = fmt.Sprintf("rule %d of rule group %q", index+1, group)
+		l.report(file, gaNode, "%s doesn't contain required field title", name)
+	}
+
+	// rules are matched by UID so that their state is kept when the file is provisioned again
+	if ga.UID == "" {
+		l.report(file, gaNode, "%s doesn't contain required field uid", name)
+	} else if other, ok := l.ruleFiles[ga.UID]; ok {
+		l.report(file, lintMappingValue(gaNode, "uid"), "uid %q of %s is already used by a rule of %s", ga.UID, name, other)
+	} else {
+		l.ruleFiles[ga.UID] = file
+	}
+
+	switch ngmodels.NoDataState(ga.NoDataState) {
+	case "", ngmodels.Alerting, ngmodels.NoData, ngmodels.OK, ngmodels.KeepLastState:
+	default:
+		l.report(file, lintMappingValue(gaNode, "no_data_state"), "unknown no data state %q of %s", ga.NoDataState, name)
+	}
+	switch ngmodels.ExecutionErrorState(ga.ExecErrState) {
+	case "", ngmodels.AlertingErrState, ngmodels.OkErrState, ngmodels.KeepLastStateErrState:
+	default:
+		l.report(file, lintMappingValue(gaNode, "exec_err_state"), "unknown error state %q of %s", ga.ExecErrState, name)
+	}
+
+	dataNode := lintMappingValue(gaNode, "data")
+	if len(ga.Data) == 0 || dataNode == nil || len(dataNode.Content) != len(ga.Data) {
+		l.report(file, gaNode, "%s doesn't contain queries or expressions", name)
+		return
+	}
+
+	refIDs := make(map[string]struct{}, len(ga.Data))
+	for _, q := range ga.Data {
+		refIDs[q.RefID] = struct{}{}
+	}
+	for i := range ga.Data {
+		l.lintQuery(file, name, &ga.Data[i], dataNode.Content[i], refIDs)
+	}
+
+	if ga.Condition == "" {
+		l.report(file, gaNode, "%s doesn't contain required field condition", name)
+	} else if _, ok := refIDs[ga.Condition]; !ok {
+		l.report(file, lintMappingValue(gaNode, "condition"), "condition %s of %s isn't the refId of a query or expression", ga.Condition, name)
+	}
+}
+
+// lintQuery reports the problems of a query or expression, given the refIds of the rule.
+func (l *alertingLinter) lintQuery(file string, rule string, q *ngmodels.AlertQuery, node *yaml.Node, refIDs map[string]struct{}) {
+	if q.RefID == "" {
+		l.report(file, node, "a query of %s doesn't contain required field refId", rule)
+		return
+	}
+	if q.DatasourceUID == "" {
+		l.report(file, node, "query %s of %s doesn't contain required field datasourceUid", q.RefID, rule)
+		return
+	}
+	if err := q.PreSave(); err != nil {
+		l.report(file, node, "invalid query %s of %s: %v", q.RefID, rule, err)
+		return
+	}
+
+	isExpression, err := q.IsExpression()
+	if err != nil {
+		l.report(file, node, "invalid query %s of %s: %v", q.RefID, rule, err)
+		return
+	}
+	if !isExpression {
+		if l.datasources != nil {
+			l.lintDatasource(file, rule, q, lintMappingValue(node, "datasourceUid"))
+		}
+		return
+	}
+
+	// the expression is parsed like when the rule is evaluated, without running the queries
+	var model map[string]interface{}
+	if err := json.Unmarshal(q.Model, &model); err != nil {
+		l.report(file, node, "invalid expression %s of %s: %v", q.RefID, rule, err)
+		return
+	}
+	tr := q.RelativeTimeRange.ToTimeRange(time.Now())
+	command, err := expr.ParseCommand(q.RefID, model, expr.TimeRange{From: tr.From, To: tr.To})
+	if err != nil {
+		l.report(file, lintMappingValue(node, "model"), "invalid expression %s of %s: %v", q.RefID, rule, err)
+		return
+	}
+	for _, refID := range command.NeedsVars() {
+		if _, ok := refIDs[refID]; !ok {
+			l.report(file, lintMappingValue(node, "model"), "expression %s of %s refers to the unknown refId %s", q.RefID, rule, refID)
+		}
+	}
+}
+
+// lintDatasource reports when the data source of a query doesn't exist on the Grafana server or doesn't support alerting.
+func (l *alertingLinter) lintDatasource(file string, rule string, q *ngmodels.AlertQuery, node *yaml.Node) {
+	ds, ok := l.datasources[q.DatasourceUID]
+	switch {
+	case !ok:
+		l.report(file, node, "data source %s of query %s of %s isn't found", q.DatasourceUID, q.RefID, rule)
+	case !ds.Meta.Alerting || !ds.Meta.Backend:
+		l.report(file, node, "data source %s of type %s of query %s of %s doesn't support alerting", q.DatasourceUID, ds.Type, q.RefID, rule)
+	}
+}
+
+// lintMappingValue returns the value of the key of a YAML mapping, or nil.
+func lintMappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// lintDecode decodes a YAML node with the JSON names of the fields, like the export format.
+func lintDecode(node *yaml.Node, v interface{}) error {
+	var simple interface{}
+	if err := node.Decode(&simple); err != nil {
+		return err
+	}
+	b, err := json.Marshal(simple)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
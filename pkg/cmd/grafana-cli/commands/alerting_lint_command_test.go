@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestAlertingLintCommand(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != alertingFrontendSettingsPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"datasources": {
+			"Prometheus": {"uid": "prometheus", "type": "prometheus", "meta": {"alerting": true, "backend": true}},
+			"Loki": {"uid": "loki", "type": "loki", "meta": {"alerting": false, "backend": true}}
+		}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	newCommandLine := func(t *testing.T, args ...string) utils.CommandLine {
+		t.Helper()
+		flagSet := flag.NewFlagSet("test", 0)
+		flagSet.String("grafana-url", "", "")
+		flagSet.String("api-key", "", "")
+		flagSet.Bool("check-datasources", false, "")
+		require.NoError(t, flagSet.Parse(append([]string{"-grafana-url", server.URL + "/", "-api-key", "secret"}, args...)))
+		return &utils.ContextCommandLine{Context: cli.NewContext(&cli.App{Name: "test"}, flagSet, nil)}
+	}
+
+	t.Run("Lint succeeds when the file has no problems", func(t *testing.T) {
+		require.NoError(t, alertingLintCommand(newCommandLine(t, "testdata/alerting-lint/valid.yaml")))
+		require.NoError(t, alertingLintCommand(newCommandLine(t, "-check-datasources", "testdata/alerting-lint/valid.yaml")))
+	})
+
+	t.Run("Lint fails when a file of the directory has problems", func(t *testing.T) {
+		err := alertingLintCommand(newCommandLine(t, "testdata/alerting-lint"))
+		require.EqualError(t, err, "found 5 problems in 3 alerting provisioning files")
+	})
+
+	t.Run("Lint requires a directory or a file", func(t *testing.T) {
+		require.Error(t, alertingLintCommand(newCommandLine(t)))
+		require.Error(t, alertingLintCommand(newCommandLine(t, "testdata/alerting-lint/missing.yaml")))
+	})
+}
+
+func TestAlertingLinter(t *testing.T) {
+	lint := func(t *testing.T, datasources map[string]lintDatasource, paths ...string) []string {
+		t.Helper()
+		linter := newAlertingLinter(datasources)
+		for _, path := range paths {
+			files, err := alertingLintFiles(path)
+			require.NoError(t, err)
+			for _, file := range files {
+				require.NoError(t, linter.lintFile(file))
+			}
+		}
+		problems := make([]string, 0, len(linter.problems))
+		for _, p := range linter.problems {
+			problems = append(problems, p.String())
+		}
+		return problems
+	}
+
+	t.Run("problems are reported with their line numbers", func(t *testing.T) {
+		assert.Equal(t, []string{
+			`testdata/alerting-lint/invalid.yaml:24: unknown no data state "Unknown" of rule "Memory almost full"`,
+			`testdata/alerting-lint/invalid.yaml:22: expression B of rule "Memory almost full" refers to the unknown refId D`,
+			`testdata/alerting-lint/invalid.yaml:10: condition C of rule "Memory almost full" isn't the refId of a query or expression`,
+		}, lint(t, nil, "testdata/alerting-lint/invalid.yaml"))
+	})
+
+	t.Run("syntax errors are reported with their line numbers", func(t *testing.T) {
+		assert.Equal(t, []string{
+			`testdata/alerting-lint/syntax.yml:3: yaml: line 3: did not find expected ',' or ']'`,
+		}, lint(t, nil, "testdata/alerting-lint/syntax.yml"))
+	})
+
+	t.Run("uids are unique across the files", func(t *testing.T) {
+		assert.Equal(t, []string{
+			`testdata/alerting-lint/valid.yaml:12: uid "disk-full" of rule "Disk almost full" is already used by a rule of testdata/alerting-lint/invalid.yaml`,
+		}, lint(t, nil, "testdata/alerting-lint/invalid.yaml", "testdata/alerting-lint/valid.yaml")[3:])
+	})
+
+	t.Run("data sources are checked when they are given", func(t *testing.T) {
+		datasources := map[string]lintDatasource{}
+		assert.Equal(t, []string{
+			`testdata/alerting-lint/valid.yaml:20: data source prometheus of query A of rule "Disk almost full" isn't found`,
+		}, lint(t, datasources, "testdata/alerting-lint/valid.yaml"))
+
+		datasources["prometheus"] = lintDatasource{UID: "prometheus", Type: "prometheus"}
+		assert.Equal(t, []string{
+			`testdata/alerting-lint/valid.yaml:20: data source prometheus of type prometheus of query A of rule "Disk almost full" doesn't support alerting`,
+		}, lint(t, datasources, "testdata/alerting-lint/valid.yaml"))
+	})
+}
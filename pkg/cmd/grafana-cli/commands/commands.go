@@ -64,6 +64,12 @@ func runPluginCommand(command func(commandLine utils.CommandLine) error) func(co
 	}
 }
 
+func runAlertingCommand(command func(commandLine utils.CommandLine) error) func(context *cli.Context) error {
+	return func(context *cli.Context) error {
+		return command(&utils.ContextCommandLine{Context: context})
+	}
+}
+
 func runCueCommand(command func(commandLine utils.CommandLine) error) func(context *cli.Context) error {
 	return func(context *cli.Context) error {
 		return command(&utils.ContextCommandLine{Context: context})
@@ -127,6 +133,23 @@ var adminCommands = []*cli.Command{
 			},
 		},
 	},
+	{
+		Name:   "rotate-secret-key",
+		Usage:  "rotate-secret-key <new secret key>",
+		Action: runDbCommand(datamigrations.RotateSecretKey),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "key-from-stdin",
+				Usage: "Read the new secret key from stdin",
+				Value: false,
+			},
+			&cli.IntFlag{
+				Name:  "batch-size",
+				Usage: "Number of rows re-encrypted in each transaction",
+				Value: 100,
+			},
+		},
+	},
 	{
 		Name:  "data-migration",
 		Usage: "Runs a script that migrates or cleanups data in your db",
@@ -140,6 +163,63 @@ var adminCommands = []*cli.Command{
 	},
 }
 
+var alertingFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:  "grafana-url",
+		Usage: "URL of the Grafana server",
+		Value: "http://localhost:3000",
+	},
+	&cli.StringFlag{
+		Name:    "api-key",
+		Usage:   "API key of the organization, with the Editor role at least",
+		EnvVars: []string{"GRAFANA_API_KEY"},
+	},
+}
+
+var alertingCommands = []*cli.Command{
+	{
+		Name:   "export",
+		Usage:  "export <file (optional)>",
+		Action: runAlertingCommand(alertingExportCommand),
+		Flags:  alertingFlags,
+	},
+	{
+		Name:   "import",
+		Usage:  "import <file, or - for stdin>",
+		Action: runAlertingCommand(alertingImportCommand),
+		Flags:  alertingFlags,
+	},
+	{
+		Name:   "migrate",
+		Usage:  "migrate the dashboard alerts and notification channels to alert rules and contact points",
+		Action: runAlertingCommand(alertingMigrateCommand),
+		Description: `migrate converts the dashboard alerts and the notification channels of the organization
+of the API key, which requires the Admin role. The Alertmanager configuration of the organization
+is replaced by the contact points of the notification channels. Use --dry-run to only report the
+alerts that can and can't be migrated.`,
+		Flags: append([]cli.Flag{
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "report the migration without migrating",
+			},
+		}, alertingFlags...),
+	},
+	{
+		Name:   "lint",
+		Usage:  "lint <directory or file>",
+		Action: runAlertingCommand(alertingLintCommand),
+		Description: `lint checks the alert rules of the alerting provisioning files of a directory,
+and reports the problems with their line numbers. The data sources of the queries
+are checked against the Grafana server with --check-datasources.`,
+		Flags: append([]cli.Flag{
+			&cli.BoolFlag{
+				Name:  "check-datasources",
+				Usage: "check that the data sources of the queries exist on the Grafana server and support alerting",
+			},
+		}, alertingFlags...),
+	},
+}
+
 var cueCommands = []*cli.Command{
 	{
 		Name:   "validate-schema",
@@ -185,6 +265,12 @@ var Commands = []*cli.Command{
 		Usage:       "Grafana admin commands",
 		Subcommands: adminCommands,
 	},
+	{
+		Name:        "alerting",
+		Aliases:     []string{"alerts"},
+		Usage:       "Export, import, migrate and lint alert rules, contact points and notification policies",
+		Subcommands: alertingCommands,
+	},
 	{
 		Name:        "cue",
 		Usage:       "Cue validation commands",
@@ -0,0 +1,310 @@
+package datamigrations
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/components/securejsondata"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const defaultRotationBatchSize = 100
+
+// reencryptFunc re-encrypts a stored value with the new key, given a function re-encrypting a
+// single secret.
+type reencryptFunc func(value []byte, reencrypt func([]byte) ([]byte, error)) ([]byte, error)
+
+// encryptedTable is a table with columns encrypted with the secret key.
+type encryptedTable struct {
+	name      string
+	label     string
+	columns   []string
+	reencrypt reencryptFunc
+	// binary is set for blob columns, which are updated with the raw bytes.
+	binary bool
+	// where restricts the rotation to the rows with encrypted columns, if only some of them have.
+	where string
+}
+
+var encryptedTables = []encryptedTable{
+	{name: "data_source", label: "data sources", columns: []string{"secure_json_data"}, reencrypt: reencryptSecureJSONData},
+	{name: "plugin_setting", label: "plugin settings", columns: []string{"secure_json_data"}, reencrypt: reencryptSecureJSONData},
+	{name: "alert_notification", label: "notification channels", columns: []string{"secure_settings"}, reencrypt: reencryptSecureJSONData},
+	{name: "alert_configuration", label: "Alertmanager configurations", columns: []string{"alertmanager_configuration"}, reencrypt: reencryptAlertmanagerConfig},
+	{name: "user_auth", label: "user OAuth tokens", columns: []string{"o_auth_access_token", "o_auth_refresh_token", "o_auth_token_type"}, reencrypt: reencryptUserAuthToken},
+	{name: "dashboard_snapshot", label: "dashboard snapshots", columns: []string{"dashboard_encrypted"}, reencrypt: reencryptRaw, binary: true},
+	{name: "notification_queue", label: "queued webhooks", columns: []string{"message"}, reencrypt: reencryptBase64, where: "type = 'webhook'"},
+	{name: "data_keys", label: "data keys", columns: []string{"encrypted_data"}, reencrypt: reencryptBase64},
+}
+
+// RotateSecretKey re-encrypts the secrets stored in the database with a new secret key.
+func RotateSecretKey(c utils.CommandLine, sqlStore *sqlstore.SQLStore) error {
+	newKey := ""
+	if c.Bool("key-from-stdin") {
+		logger.Infof("New secret key: ")
+
+		scanner := bufio.NewScanner(os.Stdin)
+		if ok := scanner.Scan(); !ok {
+			if err := scanner.Err(); err != nil {
+				return fmt.Errorf("can't read secret key from stdin: %w", err)
+			}
+			return fmt.Errorf("can't read secret key from stdin")
+		}
+		newKey = scanner.Text()
+	} else {
+		newKey = c.Args().First()
+	}
+
+	if newKey == "" {
+		return fmt.Errorf("new secret key is required")
+	}
+	if newKey == setting.SecretKey {
+		return fmt.Errorf("new secret key is the current secret key")
+	}
+
+	batchSize := c.Int("batch-size")
+	if batchSize <= 0 {
+		batchSize = defaultRotationBatchSize
+	}
+
+	if err := rotateSecretKey(context.Background(), sqlStore, setting.SecretKey, newKey, batchSize); err != nil {
+		return err
+	}
+
+	logger.Info("\n")
+	logger.Infof("%s All secrets are encrypted with the new secret key\n", color.GreenString("✔"))
+	logger.Info("\n")
+	logger.Warn("Warning: Set secret_key to the new secret key in the configuration of every Grafana server and restart them. " +
+		"Until then, the stored secrets can't be decrypted. Users are signed out, since their sessions are hashed with the secret key.")
+	return nil
+}
+
+func rotateSecretKey(ctx context.Context, sqlStore *sqlstore.SQLStore, oldKey, newKey string, batchSize int) error {
+	rotation := secrets.SecretKeyRotation{}
+	err := sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		if err := secrets.CheckSecretKeyRotation(sess); err != nil {
+			return err
+		}
+
+		rotation = secrets.SecretKeyRotation{State: secrets.SecretKeyRotationRunning, Started: time.Now(), Updated: time.Now()}
+		_, err := sess.Insert(&rotation)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	reencrypt := func(payload []byte) ([]byte, error) {
+		decrypted, err := util.Decrypt(payload, oldKey)
+		if err != nil {
+			return nil, err
+		}
+		return util.Encrypt(decrypted, newKey)
+	}
+
+	for _, table := range encryptedTables {
+		if err := rotateTable(ctx, sqlStore, &rotation, table, reencrypt, batchSize); err != nil {
+			if rotation.CurrentTable == "" {
+				// nothing was re-encrypted, the rotation can start over
+				forgetErr := sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+					_, err := sess.ID(rotation.Id).Delete(&secrets.SecretKeyRotation{})
+					return err
+				})
+				if forgetErr != nil {
+					logger.Errorf("Failed to forget the rotation of the secret key: %s\n", forgetErr)
+				}
+			}
+			return err
+		}
+	}
+
+	return sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		rotation.State = secrets.SecretKeyRotationDone
+		rotation.Updated = time.Now()
+		_, err := sess.ID(rotation.Id).Cols("state", "updated").Update(&rotation)
+		return err
+	})
+}
+
+// rotateTable re-encrypts the rows of the table in batches, recording the last row of each batch
+// in the same transaction as the batch.
+func rotateTable(ctx context.Context, sqlStore *sqlstore.SQLStore, rotation *secrets.SecretKeyRotation, table encryptedTable,
+	reencrypt func([]byte) ([]byte, error), batchSize int) error {
+	var total int64
+	err := sqlStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		var err error
+		query := sess.Table(table.name)
+		if table.where != "" {
+			query = query.Where(table.where)
+		}
+		total, err = query.Count()
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to count %s: %w", table.label, err)
+	}
+
+	var lastID int64
+	done := 0
+	for {
+		var rows []map[string][]byte
+		batchLastID := lastID
+		err := sqlStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+			query := sess.Table(table.name).Cols(append([]string{"id"}, table.columns...)...).Where("id > ?", lastID)
+			if table.where != "" {
+				query = query.And(table.where)
+			}
+			err := query.OrderBy("id").Limit(batchSize).Find(&rows)
+			if err != nil || len(rows) == 0 {
+				return err
+			}
+
+			for _, row := range rows {
+				id, err := strconv.ParseInt(string(row["id"]), 10, 64)
+				if err != nil {
+					return err
+				}
+
+				update := map[string]interface{}{}
+				for _, column := range table.columns {
+					if len(row[column]) == 0 {
+						continue
+					}
+					value, err := table.reencrypt(row[column], reencrypt)
+					if err != nil {
+						return fmt.Errorf("failed to re-encrypt %s of %s %d: %w", column, table.name, id, err)
+					}
+					if table.binary {
+						update[column] = value
+					} else {
+						update[column] = string(value)
+					}
+				}
+				if len(update) > 0 {
+					if _, err := sess.Table(table.name).Where("id = ?", id).Update(update); err != nil {
+						return err
+					}
+				}
+				batchLastID = id
+			}
+
+			progress := secrets.SecretKeyRotation{CurrentTable: table.name, LastId: batchLastID, Updated: time.Now()}
+			_, err = sess.ID(rotation.Id).Cols("current_table", "last_id", "updated").Update(&progress)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		lastID = batchLastID
+		rotation.CurrentTable = table.name
+		rotation.LastId = lastID
+		done += len(rows)
+		logger.Infof("Re-encrypted %d/%d %s\n", done, total, table.label)
+	}
+
+	if done == 0 {
+		logger.Infof("No %s to re-encrypt\n", table.label)
+	}
+	return nil
+}
+
+// reencryptSecureJSONData re-encrypts the values of a JSON object of secrets, as stored by
+// securejsondata.SecureJsonData.
+func reencryptSecureJSONData(value []byte, reencrypt func([]byte) ([]byte, error)) ([]byte, error) {
+	var data securejsondata.SecureJsonData
+	if err := json.Unmarshal(value, &data); err != nil {
+		return nil, err
+	}
+
+	for key, secret := range data {
+		reencrypted, err := reencrypt(secret)
+		if err != nil {
+			return nil, err
+		}
+		data[key] = reencrypted
+	}
+	return json.Marshal(data)
+}
+
+// reencryptRaw re-encrypts a secret stored as is.
+func reencryptRaw(value []byte, reencrypt func([]byte) ([]byte, error)) ([]byte, error) {
+	return reencrypt(value)
+}
+
+// reencryptBase64 re-encrypts a base64 encoded secret.
+func reencryptBase64(value []byte, reencrypt func([]byte) ([]byte, error)) ([]byte, error) {
+	decoded, err := base64.StdEncoding.DecodeString(string(value))
+	if err != nil {
+		return nil, err
+	}
+
+	reencrypted, err := reencrypt(decoded)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(base64.StdEncoding.EncodeToString(reencrypted)), nil
+}
+
+// reencryptUserAuthToken re-encrypts a base64 encoded OAuth token, unless it's encrypted with the
+// data key of the user, which is re-encrypted instead.
+func reencryptUserAuthToken(value []byte, reencrypt func([]byte) ([]byte, error)) ([]byte, error) {
+	decoded, err := base64.StdEncoding.DecodeString(string(value))
+	if err != nil {
+		return nil, err
+	}
+	if secrets.IsEncryptedWithDataKey(decoded) {
+		return value, nil
+	}
+	return reencryptBase64(value, reencrypt)
+}
+
+// reencryptAlertmanagerConfig re-encrypts the secure settings of the Grafana managed receivers
+// of an Alertmanager configuration. The rest of the configuration is left as is.
+func reencryptAlertmanagerConfig(value []byte, reencrypt func([]byte) ([]byte, error)) ([]byte, error) {
+	var config map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, err
+	}
+
+	amConfig, _ := config["alertmanager_config"].(map[string]interface{})
+	receivers, _ := amConfig["receivers"].([]interface{})
+	for _, receiver := range receivers {
+		receiver, _ := receiver.(map[string]interface{})
+		grafanaReceivers, _ := receiver["grafana_managed_receiver_configs"].([]interface{})
+		for _, grafanaReceiver := range grafanaReceivers {
+			grafanaReceiver, _ := grafanaReceiver.(map[string]interface{})
+			secureSettings, _ := grafanaReceiver["secureSettings"].(map[string]interface{})
+			for key, secret := range secureSettings {
+				secret, ok := secret.(string)
+				if !ok {
+					continue
+				}
+				reencrypted, err := reencryptBase64([]byte(secret), reencrypt)
+				if err != nil {
+					return nil, err
+				}
+				secureSettings[key] = string(reencrypted)
+			}
+		}
+	}
+	return json.Marshal(config)
+}
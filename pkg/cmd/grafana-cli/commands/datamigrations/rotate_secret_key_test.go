@@ -0,0 +1,211 @@
+package datamigrations
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/securedata"
+	"github.com/grafana/grafana/pkg/components/securejsondata"
+	"github.com/grafana/grafana/pkg/models"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotateSecretKey(t *testing.T) {
+	const oldKey = "old-secret-key"
+	const newKey = "new-secret-key"
+
+	previousKey := setting.SecretKey
+	setting.SecretKey = oldKey
+	t.Cleanup(func() { setting.SecretKey = previousKey })
+
+	encrypt := func(t *testing.T, value string) string {
+		t.Helper()
+		encrypted, err := util.Encrypt([]byte(value), oldKey)
+		require.NoError(t, err)
+		return base64.StdEncoding.EncodeToString(encrypted)
+	}
+
+	decrypt := func(t *testing.T, value string) string {
+		t.Helper()
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		require.NoError(t, err)
+		decrypted, err := util.Decrypt(decoded, newKey)
+		require.NoError(t, err)
+		return string(decrypted)
+	}
+
+	setup := func(t *testing.T) *sqlstore.SQLStore {
+		t.Helper()
+		sqlStore := sqlstore.InitTestDB(t)
+		session := sqlStore.NewSession(context.Background())
+		defer session.Close()
+
+		datasources := []*models.DataSource{
+			{Type: "prometheus", Name: "prometheus", Uid: "prom", Created: time.Now(), Updated: time.Now(),
+				SecureJsonData: securejsondata.GetEncryptedJsonData(map[string]string{"basicAuthPassword": "prom-password"})},
+			{Type: "loki", Name: "loki", Uid: "loki", Created: time.Now(), Updated: time.Now(),
+				SecureJsonData: securejsondata.GetEncryptedJsonData(map[string]string{"basicAuthPassword": "loki-password"})},
+			{Type: "graphite", Name: "graphite", Uid: "graphite", Created: time.Now(), Updated: time.Now()},
+		}
+		_, err := session.Insert(&datasources)
+		require.NoError(t, err)
+
+		_, err = session.Insert(&models.UserAuth{
+			UserId:            1,
+			AuthModule:        "oauth_generic_oauth",
+			AuthId:            "1",
+			Created:           time.Now(),
+			OAuthAccessToken:  encrypt(t, "access-token"),
+			OAuthRefreshToken: encrypt(t, "refresh-token"),
+			OAuthTokenType:    encrypt(t, "Bearer"),
+		})
+		require.NoError(t, err)
+
+		secretsService := &secrets.Service{SQLStore: sqlStore}
+		require.NoError(t, secretsService.Init())
+		withDataKey, err := secretsService.Encrypt(context.Background(), []byte("user-2-token"), secrets.UserScope(2))
+		require.NoError(t, err)
+		_, err = session.Insert(&models.UserAuth{
+			UserId:           2,
+			AuthModule:       "oauth_generic_oauth",
+			AuthId:           "2",
+			Created:          time.Now(),
+			OAuthAccessToken: base64.StdEncoding.EncodeToString(withDataKey),
+		})
+		require.NoError(t, err)
+
+		config := `{"alertmanager_config":{"receivers":[{"name":"email","grafana_managed_receiver_configs":[` +
+			`{"uid":"abc","type":"slack","settings":{"recipient":"#alerts"},"secureSettings":{"url":"` + encrypt(t, "https://hooks.slack.com/secret") + `"}}]}]}}`
+		_, err = session.Insert(&ngmodels.AlertConfiguration{AlertmanagerConfiguration: config, ConfigurationVersion: "v1", OrgID: 1})
+		require.NoError(t, err)
+
+		for _, message := range []struct{ queueType, message string }{{"email", "{}"}, {"webhook", encrypt(t, `{"Url":"https://example.com"}`)}} {
+			_, err = session.Exec("INSERT INTO notification_queue (type, message, attempts, next_attempt, created) VALUES (?, ?, 1, ?, ?)",
+				message.queueType, message.message, time.Now(), time.Now())
+			require.NoError(t, err)
+		}
+
+		snapshot, err := securedata.Encrypt([]byte(`{"title":"snapshot"}`))
+		require.NoError(t, err)
+		_, err = session.Insert(&models.DashboardSnapshot{Key: "snapshot", DeleteKey: "delete-snapshot", OrgId: 1,
+			Expires: time.Now().Add(time.Hour), Created: time.Now(), Updated: time.Now(), DashboardEncrypted: snapshot})
+		require.NoError(t, err)
+
+		return sqlStore
+	}
+
+	t.Run("Secrets are encrypted with the new key", func(t *testing.T) {
+		sqlStore := setup(t)
+		require.NoError(t, rotateSecretKey(context.Background(), sqlStore, oldKey, newKey, 1))
+
+		session := sqlStore.NewSession(context.Background())
+		defer session.Close()
+
+		var datasources []*models.DataSource
+		require.NoError(t, session.OrderBy("id").Find(&datasources))
+		require.Len(t, datasources, 3)
+		for i, password := range []string{"prom-password", "loki-password"} {
+			decrypted, err := util.Decrypt(datasources[i].SecureJsonData["basicAuthPassword"], newKey)
+			require.NoError(t, err)
+			assert.Equal(t, password, string(decrypted))
+		}
+
+		var userAuth models.UserAuth
+		_, err := session.Where("user_id = ?", 1).Get(&userAuth)
+		require.NoError(t, err)
+		assert.Equal(t, "access-token", decrypt(t, userAuth.OAuthAccessToken))
+		assert.Equal(t, "refresh-token", decrypt(t, userAuth.OAuthRefreshToken))
+		assert.Equal(t, "Bearer", decrypt(t, userAuth.OAuthTokenType))
+
+		setting.SecretKey = newKey
+		t.Cleanup(func() { setting.SecretKey = oldKey })
+		userAuth = models.UserAuth{}
+		_, err = session.Where("user_id = ?", 2).Get(&userAuth)
+		require.NoError(t, err)
+		decoded, err := base64.StdEncoding.DecodeString(userAuth.OAuthAccessToken)
+		require.NoError(t, err)
+		secretsService := &secrets.Service{SQLStore: sqlStore}
+		require.NoError(t, secretsService.Init())
+		decrypted, err := secretsService.Decrypt(context.Background(), decoded)
+		require.NoError(t, err)
+		assert.Equal(t, "user-2-token", string(decrypted))
+
+		var alertConfig ngmodels.AlertConfiguration
+		_, err = session.Get(&alertConfig)
+		require.NoError(t, err)
+		var config struct {
+			AlertmanagerConfig struct {
+				Receivers []struct {
+					GrafanaManagedReceiverConfigs []struct {
+						Settings       map[string]string `json:"settings"`
+						SecureSettings map[string]string `json:"secureSettings"`
+					} `json:"grafana_managed_receiver_configs"`
+				} `json:"receivers"`
+			} `json:"alertmanager_config"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(alertConfig.AlertmanagerConfiguration), &config))
+		receiver := config.AlertmanagerConfig.Receivers[0].GrafanaManagedReceiverConfigs[0]
+		assert.Equal(t, "#alerts", receiver.Settings["recipient"])
+		assert.Equal(t, "https://hooks.slack.com/secret", decrypt(t, receiver.SecureSettings["url"]))
+
+		var snapshot models.DashboardSnapshot
+		_, err = session.Where("delete_key = ?", "delete-snapshot").Get(&snapshot)
+		require.NoError(t, err)
+		decrypted, err = util.Decrypt(snapshot.DashboardEncrypted, newKey)
+		require.NoError(t, err)
+		assert.Equal(t, `{"title":"snapshot"}`, string(decrypted))
+
+		queued, err := session.QueryString("SELECT type, message FROM notification_queue ORDER BY id")
+		require.NoError(t, err)
+		require.Len(t, queued, 2)
+		assert.Equal(t, "{}", queued[0]["message"])
+		assert.Equal(t, `{"Url":"https://example.com"}`, decrypt(t, queued[1]["message"]))
+
+		var rotation secrets.SecretKeyRotation
+		_, err = session.Get(&rotation)
+		require.NoError(t, err)
+		assert.Equal(t, secrets.SecretKeyRotationDone, rotation.State)
+	})
+
+	t.Run("Rotation refuses to start after an interrupted rotation", func(t *testing.T) {
+		sqlStore := setup(t)
+		session := sqlStore.NewSession(context.Background())
+		defer session.Close()
+		_, err := session.Insert(&secrets.SecretKeyRotation{State: secrets.SecretKeyRotationRunning, CurrentTable: "data_source", LastId: 1, Started: time.Now(), Updated: time.Now()})
+		require.NoError(t, err)
+
+		err = rotateSecretKey(context.Background(), sqlStore, oldKey, newKey, 1)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, secrets.ErrSecretKeyRotationInterrupted)
+
+		var datasource models.DataSource
+		_, err = session.Where("uid = ?", "loki").Get(&datasource)
+		require.NoError(t, err)
+		decrypted, err := util.Decrypt(datasource.SecureJsonData["basicAuthPassword"], oldKey)
+		require.NoError(t, err)
+		assert.Equal(t, "loki-password", string(decrypted))
+	})
+
+	t.Run("Rotation can start over when it fails before re-encrypting anything", func(t *testing.T) {
+		sqlStore := setup(t)
+		session := sqlStore.NewSession(context.Background())
+		defer session.Close()
+		_, err := session.Exec("UPDATE data_source SET secure_json_data = 'not json' WHERE uid = 'prom'")
+		require.NoError(t, err)
+
+		require.Error(t, rotateSecretKey(context.Background(), sqlStore, oldKey, newKey, 10))
+
+		_, err = session.Exec("UPDATE data_source SET secure_json_data = NULL WHERE uid = 'prom'")
+		require.NoError(t, err)
+		require.NoError(t, rotateSecretKey(context.Background(), sqlStore, oldKey, newKey, 10))
+	})
+}
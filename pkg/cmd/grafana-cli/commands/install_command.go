@@ -61,6 +61,7 @@ func (cmd Command) installCommand(c utils.CommandLine) error {
 	skipTLSVerify := c.Bool("insecure")
 
 	i := installer.New(skipTLSVerify, services.GrafanaVersion, services.Logger)
+	i.SetRepoAuthToken(c.PluginRepoURL(), c.String("repoToken"))
 	return i.Install(context.Background(), pluginID, version, c.PluginDirectory(), c.PluginURL(), c.PluginRepoURL())
 }
 
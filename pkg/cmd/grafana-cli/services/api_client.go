@@ -13,6 +13,7 @@ import (
 	"os"
 	"path"
 	"runtime"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/models"
@@ -178,6 +179,10 @@ func createRequest(repoUrl string, subPaths ...string) (*http.Request, error) {
 	req.Header.Set("grafana-os", runtime.GOOS)
 	req.Header.Set("grafana-arch", runtime.GOARCH)
 	req.Header.Set("User-Agent", "grafana "+GrafanaVersion)
+	// only send the token to the plugin repository, not to the other download URLs
+	if repoAuthToken != "" && strings.HasPrefix(u.String(), repoURL) {
+		req.Header.Set("Authorization", "Bearer "+repoAuthToken)
+	}
 
 	return req, err
 }
@@ -97,3 +97,23 @@ func asBadRequestError(t *testing.T, err error) *BadRequestError {
 	assert.FailNow(t, "Error was not of type BadRequestError")
 	return nil
 }
+
+func TestCreateRequest(t *testing.T) {
+	SetRepoAuthToken("https://plugins.example.com/api/plugins", "token")
+	t.Cleanup(func() {
+		SetRepoAuthToken("", "")
+	})
+
+	t.Run("Sends the token to the plugin repository", func(t *testing.T) {
+		req, err := createRequest("https://plugins.example.com/api/plugins", "repo", "test")
+		require.NoError(t, err)
+		assert.Equal(t, "https://plugins.example.com/api/plugins/repo/test", req.URL.String())
+		assert.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+	})
+
+	t.Run("Doesn't send the token to other URLs", func(t *testing.T) {
+		req, err := createRequest("https://example.com/plugin.zip")
+		require.NoError(t, err)
+		assert.Empty(t, req.Header.Get("Authorization"))
+	})
+}
@@ -21,6 +21,9 @@ var (
 	GrafanaVersion      string
 	ErrNotFoundError    = errors.New("404 not found error")
 	Logger              *logger.CLILogger
+
+	repoURL       string
+	repoAuthToken string
 )
 
 type BadRequestError struct {
@@ -43,6 +46,13 @@ func Init(version string, skipTLSVerify bool, debugMode bool) {
 	Logger = logger.New(debugMode)
 }
 
+// SetRepoAuthToken makes the requests to the plugin repository at url
+// authenticate with a bearer token, for private plugin repositories.
+func SetRepoAuthToken(url, token string) {
+	repoURL = url
+	repoAuthToken = token
+}
+
 func makeHttpClient(skipTLSVerify bool, timeout time.Duration) http.Client {
 	tr := &http.Transport{
 		Proxy: http.ProxyFromEnvironment,
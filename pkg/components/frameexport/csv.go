@@ -0,0 +1,44 @@
+package frameexport
+
+import (
+	"encoding/csv"
+	"io"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// WriteCSV writes the frames to w as CSV, each frame with a header row of the
+// names of its fields, and separated by empty lines. The rows are written as
+// they are formatted, so that large frames aren't buffered.
+func WriteCSV(w io.Writer, frames data.Frames, loc *time.Location) error {
+	cw := csv.NewWriter(w)
+	for i, frame := range frames {
+		if i > 0 {
+			cw.Flush()
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+
+		record := make([]string, len(frame.Fields))
+		for j, field := range frame.Fields {
+			record[j] = columnName(field)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+
+		rows := frame.Rows()
+		for row := 0; row < rows; row++ {
+			for j, field := range frame.Fields {
+				record[j] = formatValue(concreteAt(field, row), loc)
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,115 @@
+// Package frameexport writes data frames as CSV files or XLSX workbooks, for
+// the exports of panel data.
+package frameexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// Format is a file format of exported data frames.
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+// timeLayout is the layout of the times of CSV files.
+const timeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// ValidFormat returns whether the format is supported.
+func ValidFormat(format Format) bool {
+	return format == FormatCSV || format == FormatXLSX
+}
+
+// ContentType returns the media type of the files of the format.
+func (f Format) ContentType() string {
+	if f == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Write writes the frames to w in the format, with their times in loc.
+func Write(w io.Writer, format Format, frames data.Frames, loc *time.Location) error {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, frames, loc)
+	case FormatXLSX:
+		return WriteXLSX(w, frames, loc)
+	default:
+		return fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// Validate returns an error if the frames can't be written in the format, so
+// that it can be checked before writing them.
+func Validate(format Format, frames data.Frames) error {
+	switch format {
+	case FormatCSV:
+		return nil
+	case FormatXLSX:
+		return validateXLSX(frames)
+	default:
+		return fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// columnName returns the name of the column of a field, which is its display
+// name when the data source sets one, followed by its labels.
+func columnName(field *data.Field) string {
+	name := field.Name
+	if field.Config != nil {
+		if field.Config.DisplayNameFromDS != "" {
+			return field.Config.DisplayNameFromDS
+		}
+		if field.Config.DisplayName != "" {
+			return field.Config.DisplayName
+		}
+	}
+	if len(field.Labels) > 0 {
+		labels := "{" + field.Labels.String() + "}"
+		if name == "" {
+			return labels
+		}
+		return name + " " + labels
+	}
+	return name
+}
+
+// formatValue formats a value of a field as text, which is empty for null
+// values.
+func formatValue(v interface{}, loc *time.Location) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return v.In(loc).Format(timeLayout)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case string:
+		return v
+	case json.RawMessage:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// concreteAt returns the value of a field at a row, dereferencing the values of
+// nullable fields.
+func concreteAt(field *data.Field, row int) interface{} {
+	v, ok := field.ConcreteAt(row)
+	if !ok {
+		return nil
+	}
+	return v
+}
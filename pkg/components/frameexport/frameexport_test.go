@@ -0,0 +1,80 @@
+package frameexport
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFrames() data.Frames {
+	t1 := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	value := 1.5
+	return data.Frames{
+		data.NewFrame("A",
+			data.NewField("time", nil, []time.Time{t1, t1.Add(time.Minute)}),
+			data.NewField("value", data.Labels{"host": "a"}, []*float64{&value, nil}),
+		),
+		data.NewFrame("B",
+			data.NewField("name", nil, []string{`say "hi", <there>`}),
+			data.NewField("up", nil, []bool{true}),
+		),
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatCSV, testFrames(), loc))
+	assert.Equal(t, `time,value {host=a}
+2021-06-01T14:00:00.000+02:00,1.5
+2021-06-01T14:01:00.000+02:00,
+
+name,up
+"say ""hi"", <there>",true
+`, buf.String())
+}
+
+func TestWriteXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatXLSX, testFrames(), time.UTC))
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	parts := map[string]string{}
+	for _, f := range r.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := ioutil.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		parts[f.Name] = string(content)
+	}
+
+	assert.Contains(t, parts["xl/workbook.xml"], `<sheet name="A" sheetId="1" r:id="rId1"/><sheet name="B" sheetId="2" r:id="rId2"/>`)
+	assert.Contains(t, parts["[Content_Types].xml"], `/xl/worksheets/sheet2.xml`)
+	assert.Equal(t, xlsxSheetStart+
+		`<row><c s="2" t="inlineStr"><is><t xml:space="preserve">time</t></is></c><c s="2" t="inlineStr"><is><t xml:space="preserve">value {host=a}</t></is></c></row>`+
+		`<row><c s="1"><v>44348.5</v></c><c><v>1.5</v></c></row>`+
+		`<row><c s="1"><v>44348.50069444445</v></c><c/></row>`+
+		xlsxSheetEnd, parts["xl/worksheets/sheet1.xml"])
+	assert.Contains(t, parts["xl/worksheets/sheet2.xml"],
+		`<row><c t="inlineStr"><is><t xml:space="preserve">say &#34;hi&#34;, &lt;there&gt;</t></is></c><c t="b"><v>1</v></c></row>`)
+}
+
+func TestSheetNames(t *testing.T) {
+	names := sheetNames(data.Frames{
+		data.NewFrame(""),
+		data.NewFrame("cpu/usage [%]"),
+		data.NewFrame("a very long frame name that is too long"),
+		data.NewFrame("A very long frame name that is too long"),
+	})
+	assert.Equal(t, []string{"Sheet1", "cpu_usage _%_", "a very long frame name that is ", "A very long frame name that (2)"}, names)
+}
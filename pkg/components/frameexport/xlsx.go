@@ -0,0 +1,245 @@
+package frameexport
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+const (
+	// xlsxMaxRows and xlsxMaxColumns are the limits of the sheets of XLSX
+	// workbooks, including the header row.
+	xlsxMaxRows    = 1 << 20
+	xlsxMaxColumns = 1 << 14
+	// xlsxMaxSheetName is the maximum length of the names of sheets.
+	xlsxMaxSheetName = 31
+	// xlsxEpoch is the Unix time of the epoch of the serial dates of workbooks.
+	xlsxEpoch = -2209161600
+)
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`%s</Types>`
+	xlsxSheetContentType = `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`
+	xlsxRels             = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets>%s</sheets></workbook>`
+	xlsxWorkbookSheet = `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`
+	xlsxWorkbookRels  = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">%s` +
+		`<Relationship Id="rIdStyles" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`
+	xlsxWorkbookSheetRel = `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`
+	// xlsxStyles has the default style of cells, the style of times at index 1
+	// and the bold style of the header row at index 2.
+	xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm:ss"/></numFmts>` +
+		`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="3"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+		`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+		`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+		`</styleSheet>`
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+// WriteXLSX writes the frames to w as an XLSX workbook, with a sheet for each
+// frame named after it. The first row of each sheet has the names of the fields
+// of the frame. Numbers, booleans and times are written as such, so that they
+// can be computed with. The sheets are written to w as they are formatted.
+func WriteXLSX(w io.Writer, frames data.Frames, loc *time.Location) error {
+	if err := validateXLSX(frames); err != nil {
+		return err
+	}
+	if len(frames) == 0 {
+		// a workbook needs at least one sheet
+		frames = data.Frames{data.NewFrame("")}
+	}
+
+	zw := zip.NewWriter(w)
+	names := sheetNames(frames)
+
+	var contentTypes, sheets, rels strings.Builder
+	for i, name := range names {
+		fmt.Fprintf(&contentTypes, xlsxSheetContentType, i+1)
+		fmt.Fprintf(&sheets, xlsxWorkbookSheet, xmlEscape(name), i+1, i+1)
+		fmt.Fprintf(&rels, xlsxWorkbookSheetRel, i+1, i+1)
+	}
+	for _, part := range []struct{ name, content string }{
+		{"[Content_Types].xml", fmt.Sprintf(xlsxContentTypes, contentTypes.String())},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, sheets.String())},
+		{"xl/_rels/workbook.xml.rels", fmt.Sprintf(xlsxWorkbookRels, rels.String())},
+		{"xl/styles.xml", xlsxStyles},
+	} {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+
+	for i, frame := range frames {
+		f, err := zw.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1))
+		if err != nil {
+			return err
+		}
+		if err := writeSheet(f, frame, loc); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// validateXLSX returns an error if a frame doesn't fit in a sheet.
+func validateXLSX(frames data.Frames) error {
+	for _, frame := range frames {
+		if frame.Rows()+1 > xlsxMaxRows || len(frame.Fields) > xlsxMaxColumns {
+			return fmt.Errorf("frame %q has more rows or columns than an XLSX sheet can hold", frame.Name)
+		}
+	}
+	return nil
+}
+
+func writeSheet(w io.Writer, frame *data.Frame, loc *time.Location) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(xlsxSheetStart)
+
+	bw.WriteString("<row>")
+	for _, field := range frame.Fields {
+		writeStringCell(bw, columnName(field), 2)
+	}
+	bw.WriteString("</row>")
+
+	rows := frame.Rows()
+	for row := 0; row < rows; row++ {
+		bw.WriteString("<row>")
+		for _, field := range frame.Fields {
+			writeCell(bw, concreteAt(field, row), loc)
+		}
+		bw.WriteString("</row>")
+	}
+
+	bw.WriteString(xlsxSheetEnd)
+	return bw.Flush()
+}
+
+func writeCell(w *bufio.Writer, v interface{}, loc *time.Location) {
+	switch v := v.(type) {
+	case nil:
+		w.WriteString("<c/>")
+	case time.Time:
+		// serial dates count the days since the epoch in local time
+		_, offset := v.In(loc).Zone()
+		seconds := float64(v.Unix()+int64(offset)-xlsxEpoch) + float64(v.Nanosecond())/1e9
+		writeNumberCell(w, strconv.FormatFloat(seconds/86400, 'f', -1, 64), 1)
+	case bool:
+		value := "0"
+		if v {
+			value = "1"
+		}
+		w.WriteString(`<c t="b"><v>` + value + `</v></c>`)
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			writeStringCell(w, formatValue(v, loc), 0)
+			return
+		}
+		writeNumberCell(w, strconv.FormatFloat(v, 'f', -1, 64), 0)
+	case float32:
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			writeStringCell(w, formatValue(v, loc), 0)
+			return
+		}
+		writeNumberCell(w, strconv.FormatFloat(float64(v), 'f', -1, 32), 0)
+	case int8, int16, int32, int64, uint8, uint16, uint32, uint64:
+		writeNumberCell(w, fmt.Sprint(v), 0)
+	default:
+		writeStringCell(w, formatValue(v, loc), 0)
+	}
+}
+
+func writeNumberCell(w *bufio.Writer, value string, style int) {
+	if style != 0 {
+		fmt.Fprintf(w, `<c s="%d"><v>%s</v></c>`, style, value)
+		return
+	}
+	w.WriteString("<c><v>" + value + "</v></c>")
+}
+
+func writeStringCell(w *bufio.Writer, value string, style int) {
+	if style != 0 {
+		fmt.Fprintf(w, `<c s="%d" t="inlineStr"><is><t xml:space="preserve">`, style)
+	} else {
+		w.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+	}
+	_ = xml.EscapeText(w, []byte(value))
+	w.WriteString("</t></is></c>")
+}
+
+// sheetNames returns the names of the sheets of the frames, which are unique
+// and valid names of sheets.
+func sheetNames(frames data.Frames) []string {
+	names := make([]string, len(frames))
+	used := map[string]bool{}
+	for i, frame := range frames {
+		name := strings.Map(func(r rune) rune {
+			if strings.ContainsRune(`[]:*?/\`, r) {
+				return '_'
+			}
+			return r
+		}, strings.Trim(frame.Name, "'"))
+		if name == "" {
+			name = fmt.Sprintf("Sheet%d", i+1)
+		}
+		name = truncate(name, xlsxMaxSheetName)
+
+		unique := name
+		for n := 2; used[strings.ToLower(unique)]; n++ {
+			suffix := fmt.Sprintf(" (%d)", n)
+			unique = truncate(name, xlsxMaxSheetName-len(suffix)) + suffix
+		}
+		used[strings.ToLower(unique)] = true
+		names[i] = unique
+	}
+	return names
+}
+
+// truncate returns the first max runes of s.
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -47,6 +47,45 @@ type UserUpdated struct {
 	Email     string    `json:"email"`
 }
 
+// UserAccessChanged is published when the organization memberships, roles,
+// team memberships or account state of a user change.
+type UserAccessChanged struct {
+	Timestamp time.Time `json:"timestamp"`
+	UserId    int64     `json:"user_id"`
+}
+
+// UserAdminRoleGranted is published when a user becomes an administrator of an
+// organization, or a Grafana Admin when the organization is 0.
+type UserAdminRoleGranted struct {
+	Timestamp time.Time `json:"timestamp"`
+	UserId    int64     `json:"user_id"`
+	OrgId     int64     `json:"org_id"`
+}
+
+// Reasons for revoking the credentials of a user.
+const (
+	CredentialsRevokedPasswordChanged = "password-changed"
+	CredentialsRevokedUserDisabled    = "user-disabled"
+	CredentialsRevokedOAuthRevoked    = "oauth-revoked"
+)
+
+// UserCredentialsRevoked is published when the password of a user changes, the
+// user is disabled, or the identity provider revokes their access, so that all
+// of their sessions are signed out.
+type UserCredentialsRevoked struct {
+	Timestamp time.Time `json:"timestamp"`
+	UserId    int64     `json:"user_id"`
+	Reason    string    `json:"reason"`
+}
+
+// DashboardAclUpdated is published when the permissions of a dashboard or
+// folder change, including when a dashboard is moved to another folder.
+type DashboardAclUpdated struct {
+	Timestamp   time.Time `json:"timestamp"`
+	OrgId       int64     `json:"org_id"`
+	DashboardId int64     `json:"dashboard_id"`
+}
+
 type DataSourceDeleted struct {
 	Timestamp time.Time `json:"timestamp"`
 	Name      string    `json:"name"`
@@ -106,23 +106,42 @@ func buildCMDNode(dp *simple.DirectedGraph, rn *rawNode) (*CMDNode, error) {
 		CMDType: commandType,
 	}
 
+	node.Command, err = unmarshalCommand(commandType, rn)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// ParseCommand returns the command of the expression query with the given refId, so that
+// the expression and the refIds that it depends on can be checked without running it.
+func ParseCommand(refID string, query map[string]interface{}, timeRange TimeRange) (Command, error) {
+	rn := &rawNode{
+		RefID:     refID,
+		Query:     query,
+		TimeRange: timeRange,
+	}
+	commandType, err := rn.GetCommandType()
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalCommand(commandType, rn)
+}
+
+func unmarshalCommand(commandType CommandType, rn *rawNode) (Command, error) {
 	switch commandType {
 	case TypeMath:
-		node.Command, err = UnmarshalMathCommand(rn)
+		return UnmarshalMathCommand(rn)
 	case TypeReduce:
-		node.Command, err = UnmarshalReduceCommand(rn)
+		return UnmarshalReduceCommand(rn)
 	case TypeResample:
-		node.Command, err = UnmarshalResampleCommand(rn)
+		return UnmarshalResampleCommand(rn)
 	case TypeClassicConditions:
-		node.Command, err = classic.UnmarshalConditionsCmd(rn.Query, rn.RefID)
+		return classic.UnmarshalConditionsCmd(rn.Query, rn.RefID)
 	default:
 		return nil, fmt.Errorf("expression command type '%v' in '%v' not implemented", commandType, rn.RefID)
 	}
-	if err != nil {
-		return nil, err
-	}
-
-	return node, nil
 }
 
 const (
@@ -246,38 +265,115 @@ func (dn *DSNode) Execute(ctx context.Context, vars mathexp.Vars, s *Service) (m
 			return mathexp.Results{}, fmt.Errorf("failed to execute query %v: %w", refID, qr.Error)
 		}
 
-		if len(qr.Frames) == 1 {
-			frame := qr.Frames[0]
-			if frame.TimeSeriesSchema().Type == data.TimeSeriesTypeNot && isNumberTable(frame) {
-				logger.Debug("expression datasource query (numberSet)", "query", refID)
-				numberSet, err := extractNumberSet(frame)
-				if err != nil {
-					return mathexp.Results{}, err
-				}
-				for _, n := range numberSet {
-					vals = append(vals, n)
-				}
-
-				return mathexp.Results{
-					Values: vals,
-				}, nil
-			}
+		logger.Debug("expression datasource query", "query", refID, "frames", len(qr.Frames))
+		v, err := framesToValues(qr.Frames)
+		if err != nil {
+			return mathexp.Results{}, err
 		}
+		vals = append(vals, v...)
+	}
+	return mathexp.Results{
+		Values: vals,
+	}, nil
+}
 
-		for _, frame := range qr.Frames {
-			logger.Debug("expression datasource query (seriesSet)", "query", refID)
-			series, err := WideToMany(frame)
+// seriesNameLabel is the label added to the series of a datasource query whose labels do not tell
+// them apart, with the name of the series as value. This way each series of datasources that do not
+// return labels, such as Graphite targets or SQL value columns, is alerted on separately.
+const seriesNameLabel = "name"
+
+// framesToValues converts the frames returned by a datasource query into number sets and series.
+// Tables with a single numeric column become numbers labeled by their string columns, long time series
+// become a series per value column and combination of string columns, and wide time series become
+// a series per value column.
+func framesToValues(frames data.Frames) ([]mathexp.Value, error) {
+	vals := make([]mathexp.Value, 0)
+	names := make([]string, 0)
+	for _, frame := range frames {
+		schema := frame.TimeSeriesSchema()
+		switch {
+		case schema.Type == data.TimeSeriesTypeNot && isNumberTable(frame):
+			numberSet, err := extractNumberSet(frame)
 			if err != nil {
-				return mathexp.Results{}, err
+				return nil, err
 			}
-			for _, s := range series {
-				vals = append(vals, s)
+			for _, n := range numberSet {
+				vals = append(vals, n)
+				names = append(names, "")
 			}
+			continue
+		case schema.Type == data.TimeSeriesTypeLong:
+			if frame.Rows() == 0 {
+				continue
+			}
+			wide, err := data.LongToWide(frame, nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert long series to wide series: %w", err)
+			}
+			frame = wide
+			schema = frame.TimeSeriesSchema()
+		}
+
+		seriesNames := make([]string, 0, len(schema.ValueIndices))
+		for _, valIdx := range schema.ValueIndices {
+			seriesNames = append(seriesNames, seriesName(frame, valIdx, len(schema.ValueIndices)))
+		}
+
+		series, err := WideToMany(frame)
+		if err != nil {
+			return nil, err
+		}
+		for i, s := range series {
+			vals = append(vals, s)
+			names = append(names, seriesNames[i])
+		}
+	}
+
+	addSeriesNameLabels(vals, names)
+	return vals, nil
+}
+
+// seriesName returns the name of the series of the value field at the given index of a wide frame.
+func seriesName(frame *data.Frame, valIdx int, valueCount int) string {
+	field := frame.Fields[valIdx]
+	if field.Config != nil && field.Config.DisplayNameFromDS != "" {
+		return field.Config.DisplayNameFromDS
+	}
+	if valueCount == 1 && frame.Name != "" {
+		return frame.Name
+	}
+	return field.Name
+}
+
+// addSeriesNameLabels adds the name of the series to the labels of the series that have the
+// same labels as another one.
+func addSeriesNameLabels(vals []mathexp.Value, names []string) {
+	byLabels := make(map[string][]int, len(vals))
+	for i, v := range vals {
+		key := v.GetLabels().String()
+		byLabels[key] = append(byLabels[key], i)
+	}
+
+	for _, idxs := range byLabels {
+		if len(idxs) < 2 {
+			continue
+		}
+		for _, i := range idxs {
+			labels := vals[i].GetLabels()
+			if names[i] == "" {
+				continue
+			}
+			if _, ok := labels[seriesNameLabel]; ok {
+				continue
+			}
+			labels = labels.Copy()
+			if labels == nil {
+				labels = data.Labels{}
+			}
+			labels[seriesNameLabel] = names[i]
+			vals[i].SetLabels(labels)
 		}
 	}
-	return mathexp.Results{
-		Values: vals,
-	}, nil
 }
 
 func isNumberTable(frame *data.Frame) bool {
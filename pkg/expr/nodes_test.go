@@ -0,0 +1,135 @@
+package expr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFramesToValues(t *testing.T) {
+	times := []time.Time{time.Unix(1, 0), time.Unix(2, 0)}
+
+	labelsOf := func(vals []mathexp.Value) []data.Labels {
+		labels := make([]data.Labels, 0, len(vals))
+		for _, v := range vals {
+			labels = append(labels, v.GetLabels())
+		}
+		return labels
+	}
+
+	tests := []struct {
+		name           string
+		frames         data.Frames
+		expectedType   interface{}
+		expectedLabels []data.Labels
+	}{
+		{
+			name: "series with distinct labels keep their labels",
+			frames: data.Frames{
+				data.NewFrame("",
+					data.NewField("time", nil, times),
+					data.NewField("value", data.Labels{"host": "a"}, []*float64{fp(1), fp(2)})),
+				data.NewFrame("",
+					data.NewField("time", nil, times),
+					data.NewField("value", data.Labels{"host": "b"}, []*float64{fp(1), fp(2)})),
+			},
+			expectedType:   mathexp.Series{},
+			expectedLabels: []data.Labels{{"host": "a"}, {"host": "b"}},
+		},
+		{
+			name: "series without labels are labeled with their display name",
+			frames: data.Frames{
+				data.NewFrame("servers.a.cpu",
+					data.NewField("time", nil, times),
+					data.NewField("value", data.Labels{}, []*float64{fp(1), fp(2)}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "servers.a.cpu"})),
+				data.NewFrame("servers.b.cpu",
+					data.NewField("time", nil, times),
+					data.NewField("value", data.Labels{}, []*float64{fp(1), fp(2)}).SetConfig(&data.FieldConfig{DisplayNameFromDS: "servers.b.cpu"})),
+			},
+			expectedType:   mathexp.Series{},
+			expectedLabels: []data.Labels{{"name": "servers.a.cpu"}, {"name": "servers.b.cpu"}},
+		},
+		{
+			name: "series with the same labels are labeled with their frame name",
+			frames: data.Frames{
+				data.NewFrame("cpu",
+					data.NewField("time", nil, times),
+					data.NewField("value", data.Labels{"host": "a"}, []*float64{fp(1), fp(2)})),
+				data.NewFrame("mem",
+					data.NewField("time", nil, times),
+					data.NewField("value", data.Labels{"host": "a"}, []*float64{fp(1), fp(2)})),
+			},
+			expectedType:   mathexp.Series{},
+			expectedLabels: []data.Labels{{"host": "a", "name": "cpu"}, {"host": "a", "name": "mem"}},
+		},
+		{
+			name: "each value field of a wide frame is a series labeled with its field name",
+			frames: data.Frames{
+				data.NewFrame("",
+					data.NewField("time", nil, times),
+					data.NewField("cpu", nil, []*float64{fp(1), fp(2)}),
+					data.NewField("mem", nil, []*float64{fp(3), fp(4)})),
+			},
+			expectedType:   mathexp.Series{},
+			expectedLabels: []data.Labels{{"name": "cpu"}, {"name": "mem"}},
+		},
+		{
+			name: "long frames are a series per combination of string columns",
+			frames: data.Frames{
+				data.NewFrame("",
+					data.NewField("time", nil, []time.Time{time.Unix(1, 0), time.Unix(1, 0), time.Unix(2, 0), time.Unix(2, 0)}),
+					data.NewField("host", nil, []string{"a", "b", "a", "b"}),
+					data.NewField("value", nil, []float64{1, 2, 3, 4})),
+			},
+			expectedType:   mathexp.Series{},
+			expectedLabels: []data.Labels{{"host": "a"}, {"host": "b"}},
+		},
+		{
+			name: "empty long frames have no series",
+			frames: data.Frames{
+				data.NewFrame("",
+					data.NewField("time", nil, []time.Time{}),
+					data.NewField("host", nil, []string{}),
+					data.NewField("value", nil, []float64{})),
+			},
+			expectedLabels: []data.Labels{},
+		},
+		{
+			name: "each table is a number per row labeled by its string columns",
+			frames: data.Frames{
+				data.NewFrame("",
+					data.NewField("host", nil, []string{"a", "b"}),
+					data.NewField("value", nil, []float64{1, 2})),
+				data.NewFrame("",
+					data.NewField("host", nil, []string{"c"}),
+					data.NewField("value", nil, []float64{3})),
+			},
+			expectedType:   mathexp.Number{},
+			expectedLabels: []data.Labels{{"host": "a"}, {"host": "b"}, {"host": "c"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vals, err := framesToValues(tt.frames)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedLabels, labelsOf(vals))
+			for _, v := range vals {
+				require.IsType(t, tt.expectedType, v)
+			}
+		})
+	}
+
+	t.Run("unsorted long frames are rejected", func(t *testing.T) {
+		_, err := framesToValues(data.Frames{
+			data.NewFrame("",
+				data.NewField("time", nil, []time.Time{time.Unix(2, 0), time.Unix(1, 0)}),
+				data.NewField("host", nil, []string{"a", "b"}),
+				data.NewField("value", nil, []float64{1, 2})),
+		})
+		require.Error(t, err)
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/go-stack/stack"
 	"github.com/grafana/grafana/pkg/util"
@@ -23,6 +24,18 @@ var loggersToClose []DisposableHandler
 var loggersToReload []ReloadableHandler
 var filters map[string]log15.Lvl
 
+// contextLevels are the log levels set at runtime for the records of the loggers
+// with a context value, such as the loggers of a plugin, keyed by key=value.
+var (
+	contextLevelsMu sync.RWMutex
+	contextLevels   = map[string]contextLogLevel{}
+)
+
+type contextLogLevel struct {
+	name  string
+	level log15.Lvl
+}
+
 func init() {
 	loggersToClose = make([]DisposableHandler, 0)
 	loggersToReload = make([]ReloadableHandler, 0)
@@ -150,6 +163,60 @@ func getLogLevelFromString(levelName string) log15.Lvl {
 	return level
 }
 
+// SetContextLevel sets the log level of the records of the loggers with a context
+// value at runtime, which takes precedence over the configured levels and filters.
+// An empty level name removes the level.
+func SetContextLevel(key, value, levelName string) error {
+	contextLevelsMu.Lock()
+	defer contextLevelsMu.Unlock()
+
+	if levelName == "" {
+		delete(contextLevels, key+"="+value)
+		return nil
+	}
+
+	levelName = strings.ToLower(levelName)
+	level, ok := logLevels[levelName]
+	if !ok {
+		return fmt.Errorf("unknown log level %q", levelName)
+	}
+	contextLevels[key+"="+value] = contextLogLevel{name: levelName, level: level}
+	return nil
+}
+
+// ContextLevel returns the log level set at runtime for a context value.
+func ContextLevel(key, value string) (string, bool) {
+	contextLevelsMu.RLock()
+	defer contextLevelsMu.RUnlock()
+
+	level, ok := contextLevels[key+"="+value]
+	return level.name, ok
+}
+
+// contextLevel returns the log level set at runtime for a context value of a record.
+func contextLevel(ctx []interface{}) (log15.Lvl, bool) {
+	contextLevelsMu.RLock()
+	defer contextLevelsMu.RUnlock()
+
+	if len(contextLevels) == 0 {
+		return 0, false
+	}
+	for i := 0; i+1 < len(ctx); i += 2 {
+		key, ok := ctx[i].(string)
+		if !ok {
+			continue
+		}
+		value, ok := ctx[i+1].(string)
+		if !ok {
+			continue
+		}
+		if level, ok := contextLevels[key+"="+value]; ok {
+			return level.level, true
+		}
+	}
+	return 0, false
+}
+
 func getFilters(filterStrArray []string) map[string]log15.Lvl {
 	filterMap := make(map[string]log15.Lvl)
 
@@ -263,6 +330,10 @@ func ReadLoggingConfig(modes []string, logsPath string, cfg *ini.File) error {
 
 func LogFilterHandler(maxLevel log15.Lvl, filters map[string]log15.Lvl, h log15.Handler) log15.Handler {
 	return log15.FilterHandler(func(r *log15.Record) (pass bool) {
+		if level, ok := contextLevel(r.Ctx); ok {
+			return r.Lvl <= level
+		}
+
 		if len(filters) > 0 {
 			for i := 0; i < len(r.Ctx); i += 2 {
 				key, ok := r.Ctx[i].(string)
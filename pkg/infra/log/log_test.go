@@ -0,0 +1,53 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/inconshreveable/log15"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogFilterHandler(t *testing.T) {
+	var records []*log15.Record
+	handler := LogFilterHandler(log15.LvlInfo, map[string]log15.Lvl{"plugins.backend": log15.LvlWarn}, log15.FuncHandler(func(r *log15.Record) error {
+		records = append(records, r)
+		return nil
+	}))
+	logger := log15.New()
+	logger.SetHandler(handler)
+
+	pluginLogger := logger.New("logger", "plugins.backend", "pluginId", "test-datasource")
+	otherLogger := logger.New("logger", "plugins.backend", "pluginId", "other-datasource")
+
+	t.Run("Should filter records with the configured levels", func(t *testing.T) {
+		records = nil
+		pluginLogger.Info("info")
+		pluginLogger.Warn("warn")
+		require.Len(t, records, 1)
+		require.Equal(t, "warn", records[0].Msg)
+	})
+
+	t.Run("Should filter records with the level of their context value", func(t *testing.T) {
+		require.NoError(t, SetContextLevel("pluginId", "test-datasource", "debug"))
+		t.Cleanup(func() {
+			require.NoError(t, SetContextLevel("pluginId", "test-datasource", ""))
+		})
+
+		level, ok := ContextLevel("pluginId", "test-datasource")
+		require.True(t, ok)
+		require.Equal(t, "debug", level)
+
+		records = nil
+		pluginLogger.Debug("debug")
+		otherLogger.Debug("debug")
+		otherLogger.Info("info")
+		require.Len(t, records, 1)
+		require.Equal(t, "debug", records[0].Msg)
+	})
+
+	t.Run("Should reject unknown levels", func(t *testing.T) {
+		require.Error(t, SetContextLevel("pluginId", "test-datasource", "verbose"))
+		_, ok := ContextLevel("pluginId", "test-datasource")
+		require.False(t, ok)
+	})
+}
@@ -61,6 +61,9 @@ var (
 	// MApiLoginSAML is a metric api login SAML counter
 	MApiLoginSAML prometheus.Counter
 
+	// MApiLoginKerberos is a metric api login Kerberos counter
+	MApiLoginKerberos prometheus.Counter
+
 	// MApiOrgCreate is a metric api org created counter
 	MApiOrgCreate prometheus.Counter
 
@@ -106,6 +109,9 @@ var (
 	// MRenderingQueue is a metric gauge for image rendering queue size
 	MRenderingQueue prometheus.Gauge
 
+	// MRenderingQueueWaiting is a metric gauge for image rendering requests waiting in the queue
+	MRenderingQueueWaiting prometheus.Gauge
+
 	// MAccessEvaluationCount is a metric gauge for total number of evaluation requests
 	MAccessEvaluationCount prometheus.Counter
 )
@@ -121,6 +127,9 @@ var (
 	// MRenderingSummary is a metric summary for image rendering request duration
 	MRenderingSummary *prometheus.SummaryVec
 
+	// MRenderingQueueWaitSummary is a metric summary for the time image rendering requests wait in the queue
+	MRenderingQueueWaitSummary prometheus.Summary
+
 	// MAccessPermissionsSummary is a metric summary for loading permissions request duration when evaluating access
 	MAccessPermissionsSummary prometheus.Histogram
 
@@ -304,6 +313,12 @@ func init() {
 		Namespace: ExporterName,
 	})
 
+	MApiLoginKerberos = newCounterStartingAtZero(prometheus.CounterOpts{
+		Name:      "api_login_kerberos_total",
+		Help:      "api login kerberos counter",
+		Namespace: ExporterName,
+	})
+
 	MApiOrgCreate = newCounterStartingAtZero(prometheus.CounterOpts{
 		Name:      "api_org_create_total",
 		Help:      "api org created counter",
@@ -408,6 +423,19 @@ func init() {
 		Namespace: ExporterName,
 	})
 
+	MRenderingQueueWaiting = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:      "rendering_queue_waiting",
+		Help:      "number of rendering requests waiting in the queue",
+		Namespace: ExporterName,
+	})
+
+	MRenderingQueueWaitSummary = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name:       "rendering_queue_wait_duration_milliseconds",
+		Help:       "summary of the time rendering requests wait in the queue",
+		Objectives: objectiveMap,
+		Namespace:  ExporterName,
+	})
+
 	MDataSourceProxyReqTimer = prometheus.NewSummary(prometheus.SummaryOpts{
 		Name:       "api_dataproxy_request_all_milliseconds",
 		Help:       "summary for dataproxy request duration",
@@ -621,6 +649,7 @@ func initMetricVars() {
 		MApiLoginPost,
 		MApiLoginOAuth,
 		MApiLoginSAML,
+		MApiLoginKerberos,
 		MApiOrgCreate,
 		MApiDashboardSnapshotCreate,
 		MApiDashboardSnapshotExternal,
@@ -637,6 +666,8 @@ func initMetricVars() {
 		MRenderingRequestTotal,
 		MRenderingSummary,
 		MRenderingQueue,
+		MRenderingQueueWaiting,
+		MRenderingQueueWaitSummary,
 		MAccessPermissionsSummary,
 		MAccessEvaluationsSummary,
 		MAlertingActiveAlerts,
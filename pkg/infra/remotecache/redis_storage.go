@@ -1,6 +1,7 @@
 package remotecache
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"strconv"
@@ -116,3 +117,33 @@ func (s *redisStorage) Delete(key string) error {
 	cmd := s.c.Del(key)
 	return cmd.Err()
 }
+
+// Publish sends a message to all subscribers of the channel.
+func (s *redisStorage) Publish(channel string, msg string) error {
+	return s.c.Publish(channel, msg).Err()
+}
+
+// Subscribe calls fn for every message published to the channel until the context is canceled.
+func (s *redisStorage) Subscribe(ctx context.Context, channel string, fn func(msg string)) error {
+	pubsub, err := s.c.Subscribe(channel)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		// closing unblocks ReceiveMessage below
+		_ = pubsub.Close()
+	}()
+
+	for {
+		msg, err := pubsub.ReceiveMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		fn(msg.Payload)
+	}
+}
@@ -20,6 +20,9 @@ var (
 	// ErrInvalidCacheType is returned if the type is invalid
 	ErrInvalidCacheType = errors.New("invalid remote cache name")
 
+	// ErrPubSubNotSupported is returned if the cache type can't broadcast messages
+	ErrPubSubNotSupported = errors.New("remote cache does not support pub/sub")
+
 	defaultMaxCacheExpiration = time.Hour * 24
 )
 
@@ -51,6 +54,17 @@ type CacheStorage interface {
 	Delete(key string) error
 }
 
+// PubSub is implemented by cache storages that can broadcast messages to
+// every Grafana instance sharing the cache.
+type PubSub interface {
+	// Publish sends a message to all subscribers of the channel
+	Publish(channel string, msg string) error
+
+	// Subscribe calls fn for every message published to the channel
+	// and blocks until the context is canceled
+	Subscribe(ctx context.Context, channel string, fn func(msg string)) error
+}
+
 // RemoteCache allows Grafana to cache data outside its own process
 type RemoteCache struct {
 	log      log.Logger
@@ -78,6 +92,27 @@ func (ds *RemoteCache) Delete(key string) error {
 	return ds.client.Delete(key)
 }
 
+// Publish sends a message to all instances subscribed to the channel.
+// It returns ErrPubSubNotSupported if the cache type can't broadcast messages.
+func (ds *RemoteCache) Publish(channel string, msg string) error {
+	ps, ok := ds.client.(PubSub)
+	if !ok {
+		return ErrPubSubNotSupported
+	}
+	return ps.Publish(channel, msg)
+}
+
+// Subscribe calls fn for every message published to the channel until the
+// context is canceled. It returns ErrPubSubNotSupported if the cache type
+// can't broadcast messages.
+func (ds *RemoteCache) Subscribe(ctx context.Context, channel string, fn func(msg string)) error {
+	ps, ok := ds.client.(PubSub)
+	if !ok {
+		return ErrPubSubNotSupported
+	}
+	return ps.Subscribe(ctx, channel, fn)
+}
+
 // Init initializes the service
 func (ds *RemoteCache) Init() error {
 	ds.log = log.New("cache.remote")
@@ -2,6 +2,7 @@ package login
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -20,6 +21,9 @@ var (
 	ErrAbsoluteRedirectTo    = errors.New("absolute URLs are not allowed for redirect_to cookie value")
 	ErrInvalidRedirectTo     = errors.New("invalid redirect_to cookie value")
 	ErrForbiddenRedirectTo   = errors.New("forbidden redirect_to cookie value")
+
+	// ErrTooManyLoginAttemptsFromIP wraps ErrTooManyLoginAttempts so that both are handled alike.
+	ErrTooManyLoginAttemptsFromIP = fmt.Errorf("%w: too many incorrect login attempts from this address", ErrTooManyLoginAttempts)
 )
 
 var loginLogger = log.New("login")
@@ -42,6 +46,9 @@ func authenticateUser(query *models.LoginUserQuery) error {
 	if err == nil || (!errors.Is(err, models.ErrUserNotFound) && !errors.Is(err, ErrInvalidCredentials) &&
 		!errors.Is(err, ErrUserDisabled)) {
 		query.AuthModule = "grafana"
+		if err == nil {
+			onLoginSucceeded(query)
+		}
 		return err
 	}
 
@@ -49,6 +56,9 @@ func authenticateUser(query *models.LoginUserQuery) error {
 	if ldapEnabled {
 		query.AuthModule = models.AuthModuleLDAP
 		if ldapErr == nil || !errors.Is(ldapErr, ldap.ErrInvalidCredentials) {
+			if ldapErr == nil {
+				onLoginSucceeded(query)
+			}
 			return ldapErr
 		}
 
@@ -68,6 +78,12 @@ func authenticateUser(query *models.LoginUserQuery) error {
 	return err
 }
 
+func onLoginSucceeded(query *models.LoginUserQuery) {
+	if err := resetLoginAttempts(query); err != nil {
+		loginLogger.Error("Failed to reset login attempts", "err", err)
+	}
+}
+
 func validatePasswordSet(password string) error {
 	if len(password) == 0 {
 		return ErrPasswordEmpty
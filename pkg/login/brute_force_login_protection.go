@@ -1,47 +1,29 @@
 package login
 
 import (
-	"time"
-
-	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/models"
 )
 
-var (
-	maxInvalidLoginAttempts int64 = 5
-	loginAttemptsWindow           = time.Minute * 5
-)
-
 var validateLoginAttempts = func(query *models.LoginUserQuery) error {
-	if query.Cfg.DisableBruteForceLoginProtection {
+	if query.Cfg.DisableBruteForceLoginProtection || lockout == nil {
 		return nil
 	}
 
-	loginAttemptCountQuery := models.GetUserLoginAttemptCountQuery{
-		Username: query.Username,
-		Since:    time.Now().Add(-loginAttemptsWindow),
-	}
-
-	if err := bus.Dispatch(&loginAttemptCountQuery); err != nil {
-		return err
-	}
-
-	if loginAttemptCountQuery.Result >= maxInvalidLoginAttempts {
-		return ErrTooManyLoginAttempts
-	}
-
-	return nil
+	return lockout.Check(query.Username, query.IpAddress)
 }
 
 var saveInvalidLoginAttempt = func(query *models.LoginUserQuery) error {
-	if query.Cfg.DisableBruteForceLoginProtection {
+	if query.Cfg.DisableBruteForceLoginProtection || lockout == nil {
 		return nil
 	}
 
-	loginAttemptCommand := models.CreateLoginAttemptCommand{
-		Username:  query.Username,
-		IpAddress: query.IpAddress,
+	return lockout.RecordFailure(query.Username, query.IpAddress)
+}
+
+var resetLoginAttempts = func(query *models.LoginUserQuery) error {
+	if lockout == nil {
+		return nil
 	}
 
-	return bus.Dispatch(&loginAttemptCommand)
+	return lockout.RecordSuccess(query.Username)
 }
@@ -19,38 +19,38 @@ func TestValidateLoginAttempts(t *testing.T) {
 	}{
 		{
 			name:          "When brute force protection enabled and user login attempt count is less than max",
-			loginAttempts: maxInvalidLoginAttempts - 1,
+			loginAttempts: 4,
 			cfg:           cfgWithBruteForceLoginProtectionEnabled(t),
 			expected:      nil,
 		},
 		{
 			name:          "When brute force protection enabled and user login attempt count equals max",
-			loginAttempts: maxInvalidLoginAttempts,
+			loginAttempts: 5,
 			cfg:           cfgWithBruteForceLoginProtectionEnabled(t),
 			expected:      ErrTooManyLoginAttempts,
 		},
 		{
 			name:          "When brute force protection enabled and user login attempt count is greater than max",
-			loginAttempts: maxInvalidLoginAttempts + 1,
+			loginAttempts: 6,
 			cfg:           cfgWithBruteForceLoginProtectionEnabled(t),
 			expected:      ErrTooManyLoginAttempts,
 		},
 
 		{
 			name:          "When brute force protection disabled and user login attempt count is less than max",
-			loginAttempts: maxInvalidLoginAttempts - 1,
+			loginAttempts: 4,
 			cfg:           cfgWithBruteForceLoginProtectionDisabled(t),
 			expected:      nil,
 		},
 		{
 			name:          "When brute force protection disabled and user login attempt count equals max",
-			loginAttempts: maxInvalidLoginAttempts,
+			loginAttempts: 5,
 			cfg:           cfgWithBruteForceLoginProtectionDisabled(t),
 			expected:      nil,
 		},
 		{
 			name:          "When brute force protection disabled and user login attempt count is greater than max",
-			loginAttempts: maxInvalidLoginAttempts + 1,
+			loginAttempts: 6,
 			cfg:           cfgWithBruteForceLoginProtectionDisabled(t),
 			expected:      nil,
 		},
@@ -58,9 +58,9 @@ func TestValidateLoginAttempts(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			withLoginAttempts(t, tc.loginAttempts)
+			withLoginAttempts(t, tc.cfg, tc.loginAttempts)
 
-			query := &models.LoginUserQuery{Username: "user", Cfg: tc.cfg}
+			query := &models.LoginUserQuery{Username: "user", IpAddress: "192.168.1.1:56433", Cfg: tc.cfg}
 			err := validateLoginAttempts(query)
 			require.Equal(t, tc.expected, err)
 		})
@@ -69,25 +69,26 @@ func TestValidateLoginAttempts(t *testing.T) {
 
 func TestSaveInvalidLoginAttempt(t *testing.T) {
 	t.Run("When brute force protection enabled", func(t *testing.T) {
-		t.Cleanup(func() { bus.ClearBusHandlers() })
+		cfg := cfgWithBruteForceLoginProtectionEnabled(t)
+		setupLockoutService(t, cfg)
 
-		createLoginAttemptCmd := &models.CreateLoginAttemptCommand{}
+		var createLoginAttemptCmd *models.CreateLoginAttemptCommand
 		bus.AddHandler("test", func(cmd *models.CreateLoginAttemptCommand) error {
 			createLoginAttemptCmd = cmd
 			return nil
 		})
 
 		err := saveInvalidLoginAttempt(&models.LoginUserQuery{
-			Username:  "user",
+			Username:  "User",
 			Password:  "pwd",
 			IpAddress: "192.168.1.1:56433",
-			Cfg:       cfgWithBruteForceLoginProtectionEnabled(t),
+			Cfg:       cfg,
 		})
 		require.NoError(t, err)
 
 		require.NotNil(t, createLoginAttemptCmd)
 		assert.Equal(t, "user", createLoginAttemptCmd.Username)
-		assert.Equal(t, "192.168.1.1:56433", createLoginAttemptCmd.IpAddress)
+		assert.Equal(t, "192.168.1.1", createLoginAttemptCmd.IpAddress)
 	})
 
 	t.Run("When brute force protection disabled", func(t *testing.T) {
@@ -125,10 +126,11 @@ func cfgWithBruteForceLoginProtectionEnabled(t *testing.T) *setting.Cfg {
 	return cfg
 }
 
-func withLoginAttempts(t *testing.T, loginAttempts int64) {
+func withLoginAttempts(t *testing.T, cfg *setting.Cfg, loginAttempts int64) {
 	t.Helper()
-	bus.AddHandler("test", func(query *models.GetUserLoginAttemptCountQuery) error {
-		query.Result = loginAttempts
-		return nil
-	})
+
+	setupLockoutService(t, cfg)
+	for i := int64(0); i < loginAttempts; i++ {
+		require.NoError(t, lockout.RecordFailure("user", "192.168.1.1:56433"))
+	}
 }
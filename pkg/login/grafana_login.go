@@ -1,10 +1,12 @@
 package login
 
 import (
+	"context"
 	"crypto/subtle"
 
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/passwordpolicy"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -20,6 +22,29 @@ var validatePassword = func(providedPassword string, userPassword string, userSa
 	return nil
 }
 
+// validatePasswordExpiry is only called with a valid password, so that
+// expiry does not reveal whether a guessed password is correct.
+var validatePasswordExpiry = func(query *models.LoginUserQuery, user *models.User) error {
+	if query.Cfg == nil {
+		return nil
+	}
+
+	ctx := context.Background()
+	if query.ReqContext != nil {
+		ctx = query.ReqContext.Req.Context()
+	}
+
+	expired, err := passwordpolicy.IsExpired(ctx, query.Cfg, user)
+	if err != nil {
+		return err
+	}
+	if expired {
+		return passwordpolicy.ErrPasswordExpired
+	}
+
+	return nil
+}
+
 var loginUsingGrafanaDB = func(query *models.LoginUserQuery) error {
 	userQuery := models.GetUserByLoginQuery{LoginOrEmail: query.Username}
 
@@ -37,6 +62,10 @@ var loginUsingGrafanaDB = func(query *models.LoginUserQuery) error {
 		return err
 	}
 
+	if err := validatePasswordExpiry(query, user); err != nil {
+		return err
+	}
+
 	query.User = user
 	return nil
 }
@@ -0,0 +1,288 @@
+package login
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/registry"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	userLockoutKeyPrefix = "login-lockout:user:"
+	ipLockoutKeyPrefix   = "login-lockout:ip:"
+)
+
+func init() {
+	remotecache.Register(&LockoutState{})
+	registry.RegisterService(&LockoutService{})
+}
+
+// lockout is the service used by the auth handler, set when the service is initialized.
+var lockout *LockoutService
+
+// LockoutState is the failed login attempts of an account or client address.
+type LockoutState struct {
+	// Failures is the number of failed attempts in the window, counted from
+	// the login_attempt table when the state is read.
+	Failures int64 `json:"-"`
+	// WindowStart is when failed attempts are counted from, after a
+	// successful login, an unlock or the end of a lockout.
+	WindowStart time.Time
+	// Lockouts is the number of consecutive lockouts, doubling the duration of the next one.
+	Lockouts    int
+	LockedUntil time.Time
+}
+
+// IsLocked returns true if logins are blocked at the given time.
+func (s *LockoutState) IsLocked(now time.Time) bool {
+	return now.Before(s.LockedUntil)
+}
+
+// LockoutService protects against brute force logins by counting failed
+// attempts per account and per client address. Failed attempts are rows of
+// the login_attempt table, so that concurrent attempts are all counted, and
+// lockouts are kept in the remote cache, so that the limits are shared by all
+// Grafana instances. Reaching a limit blocks logins for a duration which
+// doubles with every consecutive lockout.
+type LockoutService struct {
+	Cfg         *setting.Cfg             `inject:""`
+	RemoteCache *remotecache.RemoteCache `inject:""`
+
+	now func() time.Time
+}
+
+func (s *LockoutService) Init() error {
+	s.now = time.Now
+	lockout = s
+	return nil
+}
+
+// Check returns an error if logins for the user or from the address are blocked.
+func (s *LockoutService) Check(username, ipAddress string) error {
+	if s.Cfg.DisableBruteForceLoginProtection {
+		return nil
+	}
+
+	now := s.now()
+	if s.Cfg.BruteForceMaxAttemptsPerUser > 0 {
+		state, err := s.get(userLockoutKey(username))
+		if err != nil {
+			return err
+		}
+		if state.IsLocked(now) {
+			return ErrTooManyLoginAttempts
+		}
+	}
+
+	if s.Cfg.BruteForceMaxAttemptsPerIP > 0 {
+		state, err := s.get(ipLockoutKey(ipAddress))
+		if err != nil {
+			return err
+		}
+		if state.IsLocked(now) {
+			return ErrTooManyLoginAttemptsFromIP
+		}
+	}
+
+	return nil
+}
+
+// RecordFailure counts a failed login attempt for the user and the address.
+func (s *LockoutService) RecordFailure(username, ipAddress string) error {
+	if s.Cfg.DisableBruteForceLoginProtection {
+		return nil
+	}
+
+	username = normalizeUsername(username)
+	ipAddress = stripPort(ipAddress)
+	cmd := models.CreateLoginAttemptCommand{Username: username, IpAddress: ipAddress}
+	if err := bus.Dispatch(&cmd); err != nil {
+		return err
+	}
+
+	if s.Cfg.BruteForceMaxAttemptsPerUser > 0 {
+		err := s.recordFailure(userLockoutKey(username), s.Cfg.BruteForceMaxAttemptsPerUser, userFailures(username))
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.Cfg.BruteForceMaxAttemptsPerIP > 0 {
+		err := s.recordFailure(ipLockoutKey(ipAddress), s.Cfg.BruteForceMaxAttemptsPerIP, ipFailures(ipAddress))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RecordSuccess resets the failed login attempts of the user. The attempts of
+// the address are kept, since an attacker might own a valid account.
+func (s *LockoutService) RecordSuccess(username string) error {
+	if s.Cfg.DisableBruteForceLoginProtection || s.Cfg.BruteForceMaxAttemptsPerUser <= 0 {
+		return nil
+	}
+
+	return s.reset(userLockoutKey(username))
+}
+
+// GetUserState returns the failed login attempts of the given login or email.
+func (s *LockoutService) GetUserState(username string) (*LockoutState, error) {
+	username = normalizeUsername(username)
+	state, err := s.get(userLockoutKey(username))
+	if err != nil {
+		return nil, err
+	}
+
+	state.Failures, err = userFailures(username)(s.windowStart(state))
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// UnlockUser resets the failed login attempts of the given logins or emails.
+func (s *LockoutService) UnlockUser(usernames ...string) error {
+	for _, username := range usernames {
+		if username == "" {
+			continue
+		}
+		if err := s.reset(userLockoutKey(username)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UnlockIP resets the failed login attempts from the given address.
+func (s *LockoutService) UnlockIP(ipAddress string) error {
+	return s.reset(ipLockoutKey(stripPort(ipAddress)))
+}
+
+// recordFailure locks the key out once the failed attempts counted by
+// failures reach maxAttempts. The failed attempt itself is already stored, so
+// that concurrent failures can't be lost.
+func (s *LockoutService) recordFailure(key string, maxAttempts int64, failures func(since time.Time) (int64, error)) error {
+	state, err := s.get(key)
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	if state.IsLocked(now) {
+		return nil
+	}
+
+	count, err := failures(s.windowStart(state))
+	if err != nil {
+		return err
+	}
+	if count < maxAttempts {
+		return nil
+	}
+
+	// The failures which caused the lockout aren't counted once it ends.
+	state.LockedUntil = now.Add(s.lockoutDuration(state.Lockouts))
+	state.Lockouts++
+	state.WindowStart = state.LockedUntil
+
+	// Keep the state long enough for repeated lockouts to escalate.
+	expiry := state.LockedUntil.Sub(now)
+	if expiry < 0 {
+		expiry = 0
+	}
+	expiry += s.Cfg.BruteForceMaxLockoutDuration + s.Cfg.BruteForceAttemptsWindow
+
+	return s.RemoteCache.Set(key, state, expiry)
+}
+
+// reset clears the lockout of the key and stops counting the failed attempts
+// made until now.
+func (s *LockoutService) reset(key string) error {
+	// Attempts are stored with a precision of a second, so those of the
+	// current second are forgotten as well.
+	windowStart := s.now().Truncate(time.Second).Add(time.Second)
+	return s.RemoteCache.Set(key, &LockoutState{WindowStart: windowStart}, s.Cfg.BruteForceAttemptsWindow)
+}
+
+// windowStart returns when the failed attempts of the state are counted from.
+func (s *LockoutService) windowStart(state *LockoutState) time.Time {
+	windowStart := s.now().Add(-s.Cfg.BruteForceAttemptsWindow)
+	if state.WindowStart.After(windowStart) {
+		return state.WindowStart
+	}
+	return windowStart
+}
+
+// lockoutDuration returns the duration of a lockout after the given number of
+// previous consecutive lockouts.
+func (s *LockoutService) lockoutDuration(previousLockouts int) time.Duration {
+	duration := s.Cfg.BruteForceLockoutDuration
+	for i := 0; i < previousLockouts && duration < s.Cfg.BruteForceMaxLockoutDuration; i++ {
+		duration *= 2
+	}
+	if duration > s.Cfg.BruteForceMaxLockoutDuration {
+		duration = s.Cfg.BruteForceMaxLockoutDuration
+	}
+	return duration
+}
+
+func (s *LockoutService) get(key string) (*LockoutState, error) {
+	value, err := s.RemoteCache.Get(key)
+	if err != nil {
+		if errors.Is(err, remotecache.ErrCacheItemNotFound) {
+			return &LockoutState{}, nil
+		}
+		return nil, err
+	}
+
+	state, ok := value.(*LockoutState)
+	if !ok {
+		return nil, fmt.Errorf("unexpected login lockout state of type %T", value)
+	}
+	return state, nil
+}
+
+func userFailures(username string) func(since time.Time) (int64, error) {
+	return func(since time.Time) (int64, error) {
+		query := models.GetUserLoginAttemptCountQuery{Username: username, Since: since}
+		err := bus.Dispatch(&query)
+		return query.Result, err
+	}
+}
+
+func ipFailures(ipAddress string) func(since time.Time) (int64, error) {
+	return func(since time.Time) (int64, error) {
+		query := models.GetIPLoginAttemptCountQuery{IpAddress: ipAddress, Since: since}
+		err := bus.Dispatch(&query)
+		return query.Result, err
+	}
+}
+
+func userLockoutKey(username string) string {
+	return userLockoutKeyPrefix + normalizeUsername(username)
+}
+
+func ipLockoutKey(ipAddress string) string {
+	return ipLockoutKeyPrefix + stripPort(ipAddress)
+}
+
+func normalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+// stripPort strips the port from the remote address of the request.
+func stripPort(ipAddress string) string {
+	if host, _, err := net.SplitHostPort(ipAddress); err == nil {
+		return host
+	}
+	return ipAddress
+}
@@ -0,0 +1,184 @@
+package login
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupLockoutService(t *testing.T, cfg *setting.Cfg) *LockoutService {
+	t.Helper()
+
+	cfg.BruteForceMaxAttemptsPerUser = 5
+	cfg.BruteForceAttemptsWindow = 5 * time.Minute
+	cfg.BruteForceLockoutDuration = 5 * time.Minute
+	cfg.BruteForceMaxLockoutDuration = 15 * time.Minute
+
+	origLockout := lockout
+	t.Cleanup(func() {
+		lockout = origLockout
+	})
+
+	s := &LockoutService{Cfg: cfg, RemoteCache: remotecache.NewFakeStore(t)}
+	require.NoError(t, s.Init())
+
+	// login attempts stored like the login_attempt table does
+	var attempts []models.LoginAttempt
+	var mu sync.Mutex
+	t.Cleanup(bus.ClearBusHandlers)
+	bus.AddHandler("test", func(cmd *models.CreateLoginAttemptCommand) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts = append(attempts, models.LoginAttempt{Username: cmd.Username, IpAddress: cmd.IpAddress, Created: s.now().Unix()})
+		return nil
+	})
+	bus.AddHandler("test", func(query *models.GetUserLoginAttemptCountQuery) error {
+		mu.Lock()
+		defer mu.Unlock()
+		query.Result = 0
+		for _, attempt := range attempts {
+			if attempt.Username == query.Username && attempt.Created >= query.Since.Unix() {
+				query.Result++
+			}
+		}
+		return nil
+	})
+	bus.AddHandler("test", func(query *models.GetIPLoginAttemptCountQuery) error {
+		mu.Lock()
+		defer mu.Unlock()
+		query.Result = 0
+		for _, attempt := range attempts {
+			if attempt.IpAddress == query.IpAddress && attempt.Created >= query.Since.Unix() {
+				query.Result++
+			}
+		}
+		return nil
+	})
+	return s
+}
+
+func TestLockoutService(t *testing.T) {
+	const ip = "192.168.1.1:56433"
+
+	t.Run("locks the user after too many failed attempts", func(t *testing.T) {
+		s := setupLockoutService(t, setting.NewCfg())
+
+		for i := 0; i < 4; i++ {
+			require.NoError(t, s.RecordFailure("user", ip))
+		}
+		require.NoError(t, s.Check("user", ip))
+
+		require.NoError(t, s.RecordFailure("USER", ip))
+		require.ErrorIs(t, s.Check("user", ip), ErrTooManyLoginAttempts)
+		require.NoError(t, s.Check("other", ip))
+	})
+
+	t.Run("counts concurrent failed attempts", func(t *testing.T) {
+		s := setupLockoutService(t, setting.NewCfg())
+
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, s.RecordFailure("user", ip))
+			}()
+		}
+		wg.Wait()
+		require.ErrorIs(t, s.Check("user", ip), ErrTooManyLoginAttempts)
+	})
+
+	t.Run("forgets failed attempts outside of the window", func(t *testing.T) {
+		s := setupLockoutService(t, setting.NewCfg())
+		now := time.Now()
+		s.now = func() time.Time { return now }
+
+		for i := 0; i < 4; i++ {
+			require.NoError(t, s.RecordFailure("user", ip))
+		}
+		now = now.Add(6 * time.Minute)
+		require.NoError(t, s.RecordFailure("user", ip))
+		require.NoError(t, s.Check("user", ip))
+	})
+
+	t.Run("doubles the duration of consecutive lockouts up to the maximum", func(t *testing.T) {
+		s := setupLockoutService(t, setting.NewCfg())
+		now := time.Now()
+		s.now = func() time.Time { return now }
+
+		lockUser := func() time.Duration {
+			for i := 0; i < 5; i++ {
+				require.NoError(t, s.RecordFailure("user", ip))
+			}
+			require.ErrorIs(t, s.Check("user", ip), ErrTooManyLoginAttempts)
+
+			state, err := s.GetUserState("user")
+			require.NoError(t, err)
+			duration := state.LockedUntil.Sub(now)
+			now = state.LockedUntil
+			return duration
+		}
+
+		assert.Equal(t, 5*time.Minute, lockUser())
+		assert.Equal(t, 10*time.Minute, lockUser())
+		assert.Equal(t, 15*time.Minute, lockUser())
+		assert.Equal(t, 15*time.Minute, lockUser())
+		require.NoError(t, s.Check("user", ip))
+	})
+
+	t.Run("resets the user attempts on successful login", func(t *testing.T) {
+		s := setupLockoutService(t, setting.NewCfg())
+
+		for i := 0; i < 4; i++ {
+			require.NoError(t, s.RecordFailure("user", ip))
+		}
+		require.NoError(t, s.RecordSuccess("user"))
+		require.NoError(t, s.RecordFailure("user", ip))
+		require.NoError(t, s.Check("user", ip))
+	})
+
+	t.Run("locks the address after too many failed attempts", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		s := setupLockoutService(t, cfg)
+		cfg.BruteForceMaxAttemptsPerIP = 3
+
+		for _, username := range []string{"a", "b", "c"} {
+			require.NoError(t, s.RecordFailure(username, ip))
+		}
+		require.ErrorIs(t, s.Check("d", "192.168.1.1:60000"), ErrTooManyLoginAttemptsFromIP)
+		require.NoError(t, s.Check("d", "192.168.1.2:60000"))
+
+		require.NoError(t, s.UnlockIP("192.168.1.1"))
+		require.NoError(t, s.Check("d", ip))
+	})
+
+	t.Run("unlocks the user", func(t *testing.T) {
+		s := setupLockoutService(t, setting.NewCfg())
+
+		for i := 0; i < 5; i++ {
+			require.NoError(t, s.RecordFailure("user@example.com", ip))
+		}
+		require.Error(t, s.Check("user@example.com", ip))
+
+		require.NoError(t, s.UnlockUser("user", "", "user@example.com"))
+		require.NoError(t, s.Check("user@example.com", ip))
+	})
+
+	t.Run("does nothing when brute force protection is disabled", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		s := setupLockoutService(t, cfg)
+		cfg.DisableBruteForceLoginProtection = true
+
+		for i := 0; i < 10; i++ {
+			require.NoError(t, s.RecordFailure("user", ip))
+		}
+		require.NoError(t, s.Check("user", ip))
+	})
+}
@@ -0,0 +1,391 @@
+// Package saml implements a SAML 2.0 service provider used to log users in
+// through an external identity provider.
+package saml
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/crewjam/saml"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/registry"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// AuthModule is the auth module name stored for users logged in via SAML.
+	// It differs from the one of Grafana Enterprise SAML, so that both can be
+	// configured at the same time.
+	AuthModule = "auth.generic_saml"
+
+	requestCachePrefix = "saml-request:"
+	requestTTL         = 10 * time.Minute
+)
+
+var (
+	ErrKeyPairNotConfigured      = errors.New("certificate_path and private_key_path must be set")
+	ErrMetadataNotConfigured     = errors.New("either idp_metadata_path or idp_metadata_url must be set")
+	ErrMetadataAmbiguous         = errors.New("only one of idp_metadata_path or idp_metadata_url can be set")
+	ErrPrivateKeyNotRSA          = errors.New("private key must be an RSA key")
+	ErrNoEntityDescriptor        = errors.New("identity provider metadata does not contain an entity descriptor")
+	ErrMissingLoginAttribute     = errors.New("assertion does not contain a login or email")
+	ErrServiceProviderNotEnabled = errors.New("SAML authentication is not enabled")
+	ErrAssertionExpired          = errors.New("assertion is older than max_issue_delay")
+)
+
+var logger = log.New("auth.generic_saml")
+
+func init() {
+	registry.RegisterService(&Service{})
+}
+
+// Service is the SAML service provider.
+type Service struct {
+	Cfg         *setting.Cfg             `inject:""`
+	RemoteCache *remotecache.RemoteCache `inject:""`
+
+	sp *saml.ServiceProvider
+}
+
+// Init initializes the service provider from the [auth.generic_saml] settings.
+func (s *Service) Init() error {
+	if !s.Cfg.SAML.Enabled {
+		return nil
+	}
+
+	sp, err := newServiceProvider(s.Cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize SAML service provider: %w", err)
+	}
+	s.sp = sp
+
+	// The library rejects responses issued before its own maximum delay, so
+	// a longer delay can't be configured.
+	if s.Cfg.SAML.MaxIssueDelay > saml.MaxIssueDelay {
+		logger.Warn("max_issue_delay is longer than the maximum supported, which is used instead",
+			"max_issue_delay", s.Cfg.SAML.MaxIssueDelay, "maximum", saml.MaxIssueDelay)
+	}
+
+	return nil
+}
+
+// IsEnabled returns whether SAML authentication is configured.
+func (s *Service) IsEnabled() bool {
+	return s.sp != nil
+}
+
+// Metadata returns the XML metadata describing this service provider.
+func (s *Service) Metadata() ([]byte, error) {
+	if s.sp == nil {
+		return nil, ErrServiceProviderNotEnabled
+	}
+
+	buf, err := xml.MarshalIndent(s.sp.Metadata(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), buf...), nil
+}
+
+// AuthnRequestURL creates an authentication request for the identity provider
+// using the HTTP-Redirect binding and returns the URL to redirect the user to.
+// The request ID is remembered and sent as relay state so that the response
+// can be matched to it, since the identity provider posts the response
+// cross-site and cookies can't be relied upon.
+func (s *Service) AuthnRequestURL() (*url.URL, error) {
+	if s.sp == nil {
+		return nil, ErrServiceProviderNotEnabled
+	}
+
+	req, err := s.sp.MakeAuthenticationRequest(s.sp.GetSSOBindingLocation(saml.HTTPRedirectBinding))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.RemoteCache.Set(requestCachePrefix+req.ID, true, requestTTL); err != nil {
+		return nil, err
+	}
+
+	return req.Redirect(req.ID), nil
+}
+
+// ParseResponse validates the signed SAML response posted to the assertion
+// consumer service and maps the assertion to an external user.
+func (s *Service) ParseResponse(r *http.Request) (*models.ExternalUserInfo, error) {
+	if s.sp == nil {
+		return nil, ErrServiceProviderNotEnabled
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	var possibleRequestIDs []string
+	if requestID := r.PostForm.Get("RelayState"); requestID != "" {
+		// Each request ID is only accepted once.
+		if _, err := s.RemoteCache.Get(requestCachePrefix + requestID); err == nil {
+			if err := s.RemoteCache.Delete(requestCachePrefix + requestID); err != nil {
+				return nil, err
+			}
+			possibleRequestIDs = append(possibleRequestIDs, requestID)
+		}
+	}
+
+	assertion, err := s.sp.ParseResponse(r, possibleRequestIDs)
+	if err != nil {
+		var invalidErr *saml.InvalidResponseError
+		if errors.As(err, &invalidErr) {
+			logger.Debug("Invalid SAML response", "err", invalidErr.PrivateErr)
+		}
+		return nil, err
+	}
+
+	if err := checkIssueInstant(assertion, s.Cfg.SAML.MaxIssueDelay, saml.TimeNow()); err != nil {
+		logger.Debug("Invalid SAML response", "err", err)
+		return nil, err
+	}
+
+	return s.externalUserFromAssertion(assertion)
+}
+
+// checkIssueInstant returns an error if the assertion was issued more than the
+// max delay before now.
+func checkIssueInstant(assertion *saml.Assertion, maxDelay time.Duration, now time.Time) error {
+	if assertion.IssueInstant.Add(maxDelay).Before(now) {
+		return fmt.Errorf("%w: expired at %s", ErrAssertionExpired, assertion.IssueInstant.Add(maxDelay))
+	}
+	return nil
+}
+
+func (s *Service) externalUserFromAssertion(assertion *saml.Assertion) (*models.ExternalUserInfo, error) {
+	conf := s.Cfg.SAML
+	attrs := assertionAttributes(assertion)
+
+	extUser := &models.ExternalUserInfo{
+		AuthModule: AuthModule,
+		Name:       attrs.first(conf.AssertionAttributeName),
+		Login:      attrs.first(conf.AssertionAttributeLogin),
+		Email:      attrs.first(conf.AssertionAttributeEmail),
+		OrgRoles:   map[int64]models.RoleType{},
+	}
+
+	if assertion.Subject != nil && assertion.Subject.NameID != nil {
+		extUser.AuthId = assertion.Subject.NameID.Value
+	}
+	if extUser.Login == "" {
+		extUser.Login = extUser.Email
+	}
+	if extUser.Login == "" {
+		return nil, ErrMissingLoginAttribute
+	}
+	if extUser.AuthId == "" {
+		extUser.AuthId = extUser.Login
+	}
+
+	if conf.AssertionAttributeGroups != "" {
+		extUser.Groups = attrs[conf.AssertionAttributeGroups]
+	}
+
+	if conf.AssertionAttributeRole != "" {
+		role, isGrafanaAdmin := mapRole(conf, attrs[conf.AssertionAttributeRole])
+		extUser.OrgRoles[s.roleOrgID()] = role
+		extUser.IsGrafanaAdmin = &isGrafanaAdmin
+	}
+
+	return extUser, nil
+}
+
+// roleOrgID returns the organization the mapped role applies to.
+func (s *Service) roleOrgID() int64 {
+	if s.Cfg.AutoAssignOrg && s.Cfg.AutoAssignOrgId > 0 {
+		return int64(s.Cfg.AutoAssignOrgId)
+	}
+	return 1
+}
+
+// mapRole maps the values of the role attribute to an organization role.
+// The highest matching role wins and users without any match become viewers.
+func mapRole(conf setting.SAMLSettings, values []string) (models.RoleType, bool) {
+	isGrafanaAdmin := containsAny(conf.RoleValuesGrafanaAdmin, values)
+
+	switch {
+	case isGrafanaAdmin, containsAny(conf.RoleValuesAdmin, values):
+		return models.ROLE_ADMIN, isGrafanaAdmin
+	case containsAny(conf.RoleValuesEditor, values):
+		return models.ROLE_EDITOR, false
+	default:
+		return models.ROLE_VIEWER, false
+	}
+}
+
+func containsAny(configured []string, values []string) bool {
+	for _, c := range configured {
+		for _, v := range values {
+			if strings.EqualFold(c, v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// attributes maps both the name and the friendly name of each assertion
+// attribute to its values.
+type attributes map[string][]string
+
+func (a attributes) first(name string) string {
+	if values := a[name]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func assertionAttributes(assertion *saml.Assertion) attributes {
+	attrs := attributes{}
+	for _, statement := range assertion.AttributeStatements {
+		for _, attr := range statement.Attributes {
+			var values []string
+			for _, v := range attr.Values {
+				values = append(values, v.Value)
+			}
+
+			attrs[attr.Name] = append(attrs[attr.Name], values...)
+			if attr.FriendlyName != "" && attr.FriendlyName != attr.Name {
+				attrs[attr.FriendlyName] = append(attrs[attr.FriendlyName], values...)
+			}
+		}
+	}
+	return attrs
+}
+
+func newServiceProvider(cfg *setting.Cfg) (*saml.ServiceProvider, error) {
+	conf := cfg.SAML
+
+	if conf.CertificatePath == "" || conf.PrivateKeyPath == "" {
+		return nil, ErrKeyPairNotConfigured
+	}
+	keyPair, err := tls.LoadX509KeyPair(conf.CertificatePath, conf.PrivateKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := keyPair.PrivateKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, ErrPrivateKeyNotRSA
+	}
+	cert, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		return nil, err
+	}
+
+	idpMetadata, err := loadIdPMetadata(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	metadataURL, err := url.Parse(cfg.AppURL + "generic_saml/metadata")
+	if err != nil {
+		return nil, err
+	}
+	acsURL, err := url.Parse(cfg.AppURL + "generic_saml/acs")
+	if err != nil {
+		return nil, err
+	}
+
+	return &saml.ServiceProvider{
+		Key:                   key,
+		Certificate:           cert,
+		MetadataURL:           *metadataURL,
+		AcsURL:                *acsURL,
+		IDPMetadata:           idpMetadata,
+		MetadataValidDuration: conf.MetadataValidDuration,
+		AllowIDPInitiated:     conf.AllowIdPInitiated,
+	}, nil
+}
+
+func loadIdPMetadata(conf setting.SAMLSettings) (*saml.EntityDescriptor, error) {
+	var data []byte
+	var err error
+
+	switch {
+	case conf.IdPMetadataPath != "" && conf.IdPMetadataURL != "":
+		return nil, ErrMetadataAmbiguous
+	case conf.IdPMetadataPath != "":
+		// nolint:gosec
+		// We can ignore the gosec G304 warning on this one because the path comes from grafana configuration file
+		data, err = ioutil.ReadFile(conf.IdPMetadataPath)
+	case conf.IdPMetadataURL != "":
+		data, err = fetchIdPMetadata(conf.IdPMetadataURL)
+	default:
+		return nil, ErrMetadataNotConfigured
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return parseMetadata(data)
+}
+
+func fetchIdPMetadata(metadataURL string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metadataURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching identity provider metadata failed with status %d", resp.StatusCode)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+// parseMetadata parses identity provider metadata which can either be a single
+// EntityDescriptor or an EntitiesDescriptor wrapping one or more of them.
+func parseMetadata(data []byte) (*saml.EntityDescriptor, error) {
+	entity := &saml.EntityDescriptor{}
+	if err := xml.Unmarshal(data, entity); err == nil {
+		return entity, nil
+	}
+
+	entities := &saml.EntitiesDescriptor{}
+	if err := xml.Unmarshal(data, entities); err != nil {
+		return nil, err
+	}
+
+	for len(entities.EntityDescriptors) == 0 && len(entities.EntitiesDescriptors) > 0 {
+		entities = &entities.EntitiesDescriptors[0]
+	}
+	for i := range entities.EntityDescriptors {
+		if len(entities.EntityDescriptors[i].IDPSSODescriptors) > 0 {
+			return &entities.EntityDescriptors[i], nil
+		}
+	}
+
+	return nil, ErrNoEntityDescriptor
+}
@@ -0,0 +1,143 @@
+package saml
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/crewjam/saml"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapRole(t *testing.T) {
+	conf := setting.SAMLSettings{
+		RoleValuesEditor:       []string{"editor", "developer"},
+		RoleValuesAdmin:        []string{"admin"},
+		RoleValuesGrafanaAdmin: []string{"superadmin"},
+	}
+
+	tests := []struct {
+		name           string
+		values         []string
+		role           models.RoleType
+		isGrafanaAdmin bool
+	}{
+		{name: "no values", values: nil, role: models.ROLE_VIEWER},
+		{name: "unknown value", values: []string{"guest"}, role: models.ROLE_VIEWER},
+		{name: "editor", values: []string{"Developer"}, role: models.ROLE_EDITOR},
+		{name: "highest role wins", values: []string{"editor", "admin"}, role: models.ROLE_ADMIN},
+		{name: "grafana admin", values: []string{"superadmin"}, role: models.ROLE_ADMIN, isGrafanaAdmin: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			role, isGrafanaAdmin := mapRole(conf, tc.values)
+			assert.Equal(t, tc.role, role)
+			assert.Equal(t, tc.isGrafanaAdmin, isGrafanaAdmin)
+		})
+	}
+}
+
+func TestExternalUserFromAssertion(t *testing.T) {
+	cfg := setting.NewCfg()
+	cfg.SAML = setting.SAMLSettings{
+		AssertionAttributeName:   "displayName",
+		AssertionAttributeLogin:  "login",
+		AssertionAttributeEmail:  "mail",
+		AssertionAttributeGroups: "groups",
+		AssertionAttributeRole:   "role",
+		RoleValuesEditor:         []string{"editor"},
+	}
+	s := &Service{Cfg: cfg}
+
+	attribute := func(name, friendlyName string, values ...string) saml.Attribute {
+		attr := saml.Attribute{Name: name, FriendlyName: friendlyName}
+		for _, v := range values {
+			attr.Values = append(attr.Values, saml.AttributeValue{Value: v})
+		}
+		return attr
+	}
+
+	t.Run("maps attributes and role", func(t *testing.T) {
+		assertion := &saml.Assertion{
+			Subject: &saml.Subject{NameID: &saml.NameID{Value: "subject-id"}},
+			AttributeStatements: []saml.AttributeStatement{{
+				Attributes: []saml.Attribute{
+					attribute("urn:oid:2.16.840.1.113730.3.1.241", "displayName", "Jane Doe"),
+					attribute("login", "", "jane"),
+					attribute("mail", "", "jane@example.com"),
+					attribute("groups", "", "ops", "dev"),
+					attribute("role", "", "editor"),
+				},
+			}},
+		}
+
+		extUser, err := s.externalUserFromAssertion(assertion)
+		require.NoError(t, err)
+		assert.Equal(t, AuthModule, extUser.AuthModule)
+		assert.Equal(t, "subject-id", extUser.AuthId)
+		assert.Equal(t, "Jane Doe", extUser.Name)
+		assert.Equal(t, "jane", extUser.Login)
+		assert.Equal(t, "jane@example.com", extUser.Email)
+		assert.Equal(t, []string{"ops", "dev"}, extUser.Groups)
+		assert.Equal(t, models.ROLE_EDITOR, extUser.OrgRoles[1])
+		require.NotNil(t, extUser.IsGrafanaAdmin)
+		assert.False(t, *extUser.IsGrafanaAdmin)
+	})
+
+	t.Run("falls back to email as login", func(t *testing.T) {
+		assertion := &saml.Assertion{
+			AttributeStatements: []saml.AttributeStatement{{
+				Attributes: []saml.Attribute{attribute("mail", "", "jane@example.com")},
+			}},
+		}
+
+		extUser, err := s.externalUserFromAssertion(assertion)
+		require.NoError(t, err)
+		assert.Equal(t, "jane@example.com", extUser.Login)
+		assert.Equal(t, "jane@example.com", extUser.AuthId)
+	})
+
+	t.Run("fails without login or email", func(t *testing.T) {
+		_, err := s.externalUserFromAssertion(&saml.Assertion{})
+		require.ErrorIs(t, err, ErrMissingLoginAttribute)
+	})
+}
+
+func TestCheckIssueInstant(t *testing.T) {
+	issued := time.Date(2021, 6, 7, 8, 0, 0, 0, time.UTC)
+	assertion := &saml.Assertion{IssueInstant: issued}
+
+	require.NoError(t, checkIssueInstant(assertion, 30*time.Second, issued.Add(30*time.Second)))
+
+	err := checkIssueInstant(assertion, 30*time.Second, issued.Add(31*time.Second))
+	require.True(t, errors.Is(err, ErrAssertionExpired), err)
+}
+
+func TestParseMetadata(t *testing.T) {
+	const entity = `<EntityDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata" entityID="https://idp.example.com">
+  <IDPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+    <SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://idp.example.com/sso"/>
+  </IDPSSODescriptor>
+</EntityDescriptor>`
+
+	t.Run("single entity descriptor", func(t *testing.T) {
+		ed, err := parseMetadata([]byte(entity))
+		require.NoError(t, err)
+		assert.Equal(t, "https://idp.example.com", ed.EntityID)
+	})
+
+	t.Run("entities descriptor", func(t *testing.T) {
+		ed, err := parseMetadata([]byte(`<EntitiesDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata">` + entity + `</EntitiesDescriptor>`))
+		require.NoError(t, err)
+		assert.Equal(t, "https://idp.example.com", ed.EntityID)
+	})
+
+	t.Run("entities descriptor without identity provider", func(t *testing.T) {
+		_, err := parseMetadata([]byte(`<EntitiesDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata"></EntitiesDescriptor>`))
+		require.ErrorIs(t, err, ErrNoEntityDescriptor)
+	})
+}
@@ -0,0 +1,189 @@
+package social
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// Error codes returned by the token endpoint while the user has not completed
+// the device authorization, as defined in RFC 8628.
+const (
+	DeviceAuthorizationPending = "authorization_pending"
+	DeviceSlowDown             = "slow_down"
+	DeviceAccessDenied         = "access_denied"
+	DeviceExpiredToken         = "expired_token"
+)
+
+// DeviceAuthorization is the response of the device authorization endpoint of a provider.
+type DeviceAuthorization struct {
+	DeviceCode              string
+	UserCode                string
+	VerificationURI         string
+	VerificationURIComplete string
+	ExpiresIn               int
+	Interval                int
+}
+
+// DeviceTokenError is returned when the provider refuses to issue a token for a device code.
+type DeviceTokenError struct {
+	Code        string
+	Description string
+}
+
+func (e *DeviceTokenError) Error() string {
+	if e.Description == "" {
+		return e.Code
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Description)
+}
+
+// Pending returns true if the client should keep polling for the token.
+func (e *DeviceTokenError) Pending() bool {
+	return e.Code == DeviceAuthorizationPending || e.Code == DeviceSlowDown
+}
+
+func (s *SocialBase) SupportsDeviceAuth() bool {
+	return s.deviceAuthUrl != ""
+}
+
+// DeviceAuth starts a device authorization with the provider.
+func (s *SocialBase) DeviceAuth(ctx context.Context, client *http.Client) (*DeviceAuthorization, error) {
+	if !s.SupportsDeviceAuth() {
+		return nil, fmt.Errorf("device authorization is not configured")
+	}
+
+	params := url.Values{"client_id": {s.ClientID}}
+	if len(s.Scopes) > 0 {
+		params.Set("scope", strings.Join(s.Scopes, " "))
+	}
+
+	status, body, err := s.httpPostForm(ctx, client, s.deviceAuthUrl, params)
+	if err != nil {
+		return nil, err
+	}
+	if status >= 300 {
+		return nil, fmt.Errorf("device authorization request failed with status %d: %s", status, string(body))
+	}
+
+	var data struct {
+		DeviceCode              string `json:"device_code"`
+		UserCode                string `json:"user_code"`
+		VerificationURI         string `json:"verification_uri"`
+		VerificationURL         string `json:"verification_url"`
+		VerificationURIComplete string `json:"verification_uri_complete"`
+		ExpiresIn               int    `json:"expires_in"`
+		Interval                int    `json:"interval"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("error parsing device authorization response: %w", err)
+	}
+	if data.DeviceCode == "" || data.UserCode == "" {
+		return nil, fmt.Errorf("device authorization response is missing the device or user code")
+	}
+
+	// Google and older Azure AD endpoints return verification_url instead of verification_uri
+	verificationURI := data.VerificationURI
+	if verificationURI == "" {
+		verificationURI = data.VerificationURL
+	}
+
+	return &DeviceAuthorization{
+		DeviceCode:              data.DeviceCode,
+		UserCode:                data.UserCode,
+		VerificationURI:         verificationURI,
+		VerificationURIComplete: data.VerificationURIComplete,
+		ExpiresIn:               data.ExpiresIn,
+		Interval:                data.Interval,
+	}, nil
+}
+
+// DeviceAccessToken requests the token for a device code from the provider. A *DeviceTokenError
+// is returned as long as the user hasn't completed the authorization.
+func (s *SocialBase) DeviceAccessToken(ctx context.Context, client *http.Client, deviceCode string) (*oauth2.Token, error) {
+	params := url.Values{
+		"grant_type":  {deviceCodeGrantType},
+		"device_code": {deviceCode},
+		"client_id":   {s.ClientID},
+	}
+	if s.ClientSecret != "" {
+		params.Set("client_secret", s.ClientSecret)
+	}
+
+	status, body, err := s.httpPostForm(ctx, client, s.Endpoint.TokenURL, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		AccessToken      string `json:"access_token"`
+		TokenType        string `json:"token_type"`
+		RefreshToken     string `json:"refresh_token"`
+		ExpiresIn        int64  `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("error parsing device token response with status %d: %w", status, err)
+	}
+
+	// GitHub reports pending authorizations with a 200 status code
+	if data.Error != "" {
+		return nil, &DeviceTokenError{Code: data.Error, Description: data.ErrorDescription}
+	}
+	if status >= 300 || data.AccessToken == "" {
+		return nil, fmt.Errorf("device token request failed with status %d: %s", status, string(body))
+	}
+
+	var extra map[string]interface{}
+	if err := json.Unmarshal(body, &extra); err != nil {
+		return nil, err
+	}
+
+	token := (&oauth2.Token{
+		AccessToken:  data.AccessToken,
+		TokenType:    data.TokenType,
+		RefreshToken: data.RefreshToken,
+	}).WithExtra(extra)
+	if data.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(data.ExpiresIn) * time.Second)
+	}
+
+	return token, nil
+}
+
+func (s *SocialBase) httpPostForm(ctx context.Context, client *http.Client, endpoint string, params url.Values) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(params.Encode()))
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	r, err := client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	defer func() {
+		if err := r.Body.Close(); err != nil {
+			s.log.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return r.StatusCode, body, nil
+}
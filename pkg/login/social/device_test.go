@@ -0,0 +1,122 @@
+package social
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/inconshreveable/log15"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func newDeviceTestProvider(ts *httptest.Server) *SocialBase {
+	return &SocialBase{
+		Config: &oauth2.Config{
+			ClientID:     "client",
+			ClientSecret: "secret",
+			Scopes:       []string{"openid", "email"},
+			Endpoint:     oauth2.Endpoint{TokenURL: ts.URL + "/token"},
+		},
+		log:           newLogger("device_test", log15.LvlDebug),
+		deviceAuthUrl: ts.URL + "/device",
+	}
+}
+
+func TestSocialBase_DeviceAuth(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "/device", r.URL.Path)
+		assert.Equal(t, "client", r.PostForm.Get("client_id"))
+		assert.Equal(t, "openid email", r.PostForm.Get("scope"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{"device_code":"dev","user_code":"ABCD-EFGH","verification_url":"https://example.com/device","expires_in":1800,"interval":5}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	provider := newDeviceTestProvider(ts)
+	require.True(t, provider.SupportsDeviceAuth())
+
+	auth, err := provider.DeviceAuth(context.Background(), ts.Client())
+	require.NoError(t, err)
+	assert.Equal(t, &DeviceAuthorization{
+		DeviceCode:      "dev",
+		UserCode:        "ABCD-EFGH",
+		VerificationURI: "https://example.com/device",
+		ExpiresIn:       1800,
+		Interval:        5,
+	}, auth)
+}
+
+func TestSocialBase_DeviceAccessToken(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		expectedCode string
+		pending      bool
+	}{
+		{
+			name:         "Given a pending authorization with a 400 status, return a pending error",
+			status:       http.StatusBadRequest,
+			body:         `{"error":"authorization_pending"}`,
+			expectedCode: DeviceAuthorizationPending,
+			pending:      true,
+		},
+		{
+			name:         "Given a slow down error with a 200 status, return a pending error",
+			status:       http.StatusOK,
+			body:         `{"error":"slow_down","error_description":"too many requests"}`,
+			expectedCode: DeviceSlowDown,
+			pending:      true,
+		},
+		{
+			name:         "Given an expired device code, return a final error",
+			status:       http.StatusBadRequest,
+			body:         `{"error":"expired_token"}`,
+			expectedCode: DeviceExpiredToken,
+		},
+		{
+			name:   "Given an access token, return the token",
+			status: http.StatusOK,
+			body:   `{"access_token":"access","token_type":"bearer","refresh_token":"refresh","expires_in":3600,"id_token":"id"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, r.ParseForm())
+				assert.Equal(t, "/token", r.URL.Path)
+				assert.Equal(t, deviceCodeGrantType, r.PostForm.Get("grant_type"))
+				assert.Equal(t, "dev", r.PostForm.Get("device_code"))
+				assert.Equal(t, "secret", r.PostForm.Get("client_secret"))
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(test.status)
+				_, err := w.Write([]byte(test.body))
+				require.NoError(t, err)
+			}))
+			defer ts.Close()
+
+			token, err := newDeviceTestProvider(ts).DeviceAccessToken(context.Background(), ts.Client(), "dev")
+			if test.expectedCode != "" {
+				var tokenErr *DeviceTokenError
+				require.ErrorAs(t, err, &tokenErr)
+				assert.Equal(t, test.expectedCode, tokenErr.Code)
+				assert.Equal(t, test.pending, tokenErr.Pending())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "access", token.AccessToken)
+			assert.Equal(t, "refresh", token.RefreshToken)
+			assert.Equal(t, "id", token.Extra("id_token"))
+			assert.False(t, token.Expiry.IsZero())
+		})
+	}
+}
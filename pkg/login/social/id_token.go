@@ -0,0 +1,139 @@
+package social
+
+import (
+	"context"
+	"crypto"
+	_ "crypto/sha256" // hashes of the at_hash claim
+	_ "crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	authjwt "github.com/grafana/grafana/pkg/services/auth/jwt"
+	"golang.org/x/oauth2"
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+var (
+	ErrIDTokenMissing         = errors.New("no id_token found")
+	ErrIDTokenUnsigned        = errors.New("id_token is not signed with a supported algorithm")
+	ErrIDTokenInvalid         = errors.New("id_token signature is invalid")
+	ErrIDTokenAccessTokenHash = errors.New("id_token at_hash does not match the access token")
+)
+
+// jwksCacheTTL is how long the key set of a provider is cached. It is fetched again
+// sooner when an ID token is signed with a key that isn't in the cached key set.
+const jwksCacheTTL = time.Hour
+
+// idTokenLeeway is the clock skew tolerated when validating the expiry of ID tokens.
+const idTokenLeeway = time.Minute
+
+// idTokenAlgorithms are the signature algorithms accepted for ID tokens, with the hash
+// used for at_hash. Unsigned tokens and tokens signed with the client secret are rejected.
+var idTokenAlgorithms = map[jose.SignatureAlgorithm]crypto.Hash{
+	jose.RS256: crypto.SHA256,
+	jose.RS384: crypto.SHA384,
+	jose.RS512: crypto.SHA512,
+	jose.PS256: crypto.SHA256,
+	jose.PS384: crypto.SHA384,
+	jose.PS512: crypto.SHA512,
+	jose.ES256: crypto.SHA256,
+	jose.ES384: crypto.SHA384,
+	jose.ES512: crypto.SHA512,
+}
+
+// IDTokenClaims are the claims of a verified ID token that the login flow checks.
+type IDTokenClaims struct {
+	Subject string
+	Nonce   string
+}
+
+// IDTokenVerifier validates the ID tokens of an OpenID Connect provider against the keys
+// published at its JWKS endpoint.
+type IDTokenVerifier struct {
+	clientID string
+	issuer   string
+	keySet   authjwt.KeySet
+}
+
+// NewIDTokenVerifier returns a verifier of the ID tokens issued to the client, signed with
+// the keys of the key set. The issuer isn't checked when empty.
+func NewIDTokenVerifier(clientID, issuer string, keySet authjwt.KeySet) *IDTokenVerifier {
+	return &IDTokenVerifier{
+		clientID: clientID,
+		issuer:   issuer,
+		keySet:   keySet,
+	}
+}
+
+// Verify checks the signature, issuer, audience and expiry of the ID token returned with
+// the token, and that its at_hash, if any, matches the access token. The nonce is returned
+// for the caller to compare with the one it sent.
+func (v *IDTokenVerifier) Verify(ctx context.Context, token *oauth2.Token) (*IDTokenClaims, error) {
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, ErrIDTokenMissing
+	}
+
+	parsed, err := jwt.ParseSigned(rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse id_token: %w", err)
+	}
+	if len(parsed.Headers) != 1 {
+		return nil, ErrIDTokenUnsigned
+	}
+	hash, ok := idTokenAlgorithms[jose.SignatureAlgorithm(parsed.Headers[0].Algorithm)]
+	if !ok {
+		return nil, ErrIDTokenUnsigned
+	}
+
+	kid := parsed.Headers[0].KeyID
+	keys, err := v.keySet.Key(ctx, kid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get id_token key: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("id_token key %q not found", kid)
+	}
+
+	var claims jwt.Claims
+	var extra struct {
+		Nonce  string `json:"nonce"`
+		AtHash string `json:"at_hash"`
+	}
+	err = ErrIDTokenInvalid
+	for _, key := range keys {
+		if err = parsed.Claims(key, &claims, &extra); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, ErrIDTokenInvalid
+	}
+
+	expected := jwt.Expected{
+		Issuer:   v.issuer,
+		Audience: jwt.Audience{v.clientID},
+		Time:     time.Now(),
+	}
+	if err := claims.ValidateWithLeeway(expected, idTokenLeeway); err != nil {
+		return nil, fmt.Errorf("invalid id_token claims: %w", err)
+	}
+
+	if extra.AtHash != "" && extra.AtHash != accessTokenHash(hash, token.AccessToken) {
+		return nil, ErrIDTokenAccessTokenHash
+	}
+
+	return &IDTokenClaims{Subject: claims.Subject, Nonce: extra.Nonce}, nil
+}
+
+// accessTokenHash returns the at_hash of the access token, the base64url encoding of the
+// left half of its hash.
+func accessTokenHash(hash crypto.Hash, accessToken string) string {
+	h := hash.New()
+	_, _ = h.Write([]byte(accessToken))
+	sum := h.Sum(nil)
+	return base64.RawURLEncoding.EncodeToString(sum[:len(sum)/2])
+}
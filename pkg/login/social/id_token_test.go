@@ -0,0 +1,121 @@
+package social
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	authjwt "github.com/grafana/grafana/pkg/services/auth/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+func TestIDTokenVerifier(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewEncoder(w).Encode(jose.JSONWebKeySet{
+			Keys: []jose.JSONWebKey{{Key: key.Public(), KeyID: "key-1", Algorithm: string(jose.RS256), Use: "sig"}},
+		}))
+	}))
+	t.Cleanup(server.Close)
+
+	keySet := authjwt.NewKeySetHTTP(server.URL, server.Client(), remotecache.NewFakeStore(t), jwksCacheTTL, logger)
+	verifier := NewIDTokenVerifier("grafana", "https://idp.example.com", keySet)
+
+	sign := func(t *testing.T, alg jose.SignatureAlgorithm, signingKey interface{}, kid string, claims map[string]interface{}) string {
+		t.Helper()
+		opts := (&jose.SignerOptions{}).WithHeader("kid", kid)
+		signer, err := jose.NewSigner(jose.SigningKey{Algorithm: alg, Key: signingKey}, opts)
+		require.NoError(t, err)
+		raw, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+		require.NoError(t, err)
+		return raw
+	}
+
+	newClaims := func() map[string]interface{} {
+		return map[string]interface{}{
+			"iss":     "https://idp.example.com",
+			"sub":     "user-1",
+			"aud":     "grafana",
+			"exp":     time.Now().Add(time.Hour).Unix(),
+			"nonce":   "nonce-1",
+			"at_hash": accessTokenHash(crypto.SHA256, "access-token"),
+		}
+	}
+
+	withIDToken := func(rawIDToken string) *oauth2.Token {
+		return (&oauth2.Token{AccessToken: "access-token"}).WithExtra(map[string]interface{}{"id_token": rawIDToken})
+	}
+
+	t.Run("Valid token", func(t *testing.T) {
+		claims, err := verifier.Verify(context.Background(), withIDToken(sign(t, jose.RS256, key, "key-1", newClaims())))
+		require.NoError(t, err)
+		assert.Equal(t, "user-1", claims.Subject)
+		assert.Equal(t, "nonce-1", claims.Nonce)
+	})
+
+	t.Run("Missing token", func(t *testing.T) {
+		_, err := verifier.Verify(context.Background(), &oauth2.Token{AccessToken: "access-token"})
+		require.ErrorIs(t, err, ErrIDTokenMissing)
+	})
+
+	t.Run("Unsigned token", func(t *testing.T) {
+		header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+		payload, err := json.Marshal(newClaims())
+		require.NoError(t, err)
+		raw := header + "." + base64.RawURLEncoding.EncodeToString(payload) + "."
+
+		_, err = verifier.Verify(context.Background(), withIDToken(raw))
+		require.Error(t, err)
+	})
+
+	t.Run("Token signed with a shared secret", func(t *testing.T) {
+		_, err := verifier.Verify(context.Background(), withIDToken(sign(t, jose.HS256, []byte("client-secret-of-32-bytes-length"), "key-1", newClaims())))
+		require.ErrorIs(t, err, ErrIDTokenUnsigned)
+	})
+
+	t.Run("Token signed with another key", func(t *testing.T) {
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		_, err = verifier.Verify(context.Background(), withIDToken(sign(t, jose.RS256, otherKey, "key-1", newClaims())))
+		require.ErrorIs(t, err, ErrIDTokenInvalid)
+	})
+
+	t.Run("Token issued to another client", func(t *testing.T) {
+		claims := newClaims()
+		claims["aud"] = "other-client"
+
+		_, err := verifier.Verify(context.Background(), withIDToken(sign(t, jose.RS256, key, "key-1", claims)))
+		require.Error(t, err)
+	})
+
+	t.Run("Expired token", func(t *testing.T) {
+		claims := newClaims()
+		claims["exp"] = time.Now().Add(-time.Hour).Unix()
+
+		_, err := verifier.Verify(context.Background(), withIDToken(sign(t, jose.RS256, key, "key-1", claims)))
+		require.Error(t, err)
+	})
+
+	t.Run("Token of another access token", func(t *testing.T) {
+		claims := newClaims()
+		claims["at_hash"] = accessTokenHash(crypto.SHA256, "other-access-token")
+
+		_, err := verifier.Verify(context.Background(), withIDToken(sign(t, jose.RS256, key, "key-1", claims)))
+		require.ErrorIs(t, err, ErrIDTokenAccessTokenHash)
+	})
+}
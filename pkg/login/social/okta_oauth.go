@@ -5,6 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
 
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/util/errutil"
@@ -12,9 +15,20 @@ import (
 	"gopkg.in/square/go-jose.v2/jwt"
 )
 
+// oktaGroupsPageSize is the largest page size of the Okta groups API, to limit the
+// number of requests for users with many groups.
+const oktaGroupsPageSize = 200
+
+// oktaGroupsMaxPages is the maximum number of pages of the Okta groups API that are followed.
+const oktaGroupsMaxPages = 50
+
+var oktaNextLinkPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
 type SocialOkta struct {
 	*SocialBase
 	apiUrl              string
+	groupsApiUrl        string
+	groupsFilter        *regexp.Regexp
 	allowedGroups       []string
 	roleAttributePath   string
 	roleAttributeStrict bool
@@ -78,6 +92,10 @@ func (s *SocialOkta) UserInfo(client *http.Client, token *oauth2.Token) (*BasicU
 		return nil, err
 	}
 
+	if err := s.extractGroups(&data, client); err != nil {
+		return nil, err
+	}
+
 	role, err := s.extractRole(&data)
 	if err != nil {
 		s.log.Error("Failed to extract role", "error", err)
@@ -120,6 +138,107 @@ func (s *SocialOkta) extractAPI(data *OktaUserInfoJson, client *http.Client) err
 	return nil
 }
 
+// extractGroups replaces the groups claim of the user info with the groups of the Okta groups API
+// when configured, since the claim is capped by Okta, and keeps the groups matching the groups filter.
+// The groups are written back to the user info, so that role_attribute_path can map them to a role.
+func (s *SocialOkta) extractGroups(data *OktaUserInfoJson, client *http.Client) error {
+	if s.groupsApiUrl == "" && s.groupsFilter == nil {
+		return nil
+	}
+
+	groups := data.Groups
+	if s.groupsApiUrl != "" {
+		var err error
+		if groups, err = s.fetchGroups(client); err != nil {
+			return err
+		}
+	}
+
+	data.Groups = make([]string, 0, len(groups))
+	for _, group := range groups {
+		if s.groupsFilter == nil || s.groupsFilter.MatchString(group) {
+			data.Groups = append(data.Groups, group)
+		}
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data.rawJSON, &raw); err != nil {
+		return errutil.Wrapf(err, "error decoding user info response")
+	}
+	raw["groups"] = data.Groups
+	rawJSON, err := json.Marshal(raw)
+	if err != nil {
+		return errutil.Wrapf(err, "error encoding user info")
+	}
+	data.rawJSON = rawJSON
+
+	return nil
+}
+
+// fetchGroups returns the names of the groups of the user from all the pages of the Okta groups API.
+func (s *SocialOkta) fetchGroups(client *http.Client) ([]string, error) {
+	apiURL, err := url.Parse(s.apiUrl)
+	if err != nil {
+		return nil, errutil.Wrapf(err, "invalid API URL")
+	}
+	next, err := url.Parse(s.groupsApiUrl)
+	if err != nil {
+		return nil, errutil.Wrapf(err, "invalid groups API URL")
+	}
+	query := next.Query()
+	if query.Get("limit") == "" {
+		query.Set("limit", strconv.Itoa(oktaGroupsPageSize))
+		next.RawQuery = query.Encode()
+	}
+
+	groups := make([]string, 0)
+	for page, pageURL := 1, next.String(); pageURL != ""; page++ {
+		if page > oktaGroupsMaxPages {
+			return nil, fmt.Errorf("the groups of the user have more than %d pages", oktaGroupsMaxPages)
+		}
+
+		response, err := s.httpGet(client, pageURL)
+		if err != nil {
+			s.log.Debug("Error getting groups", "url", pageURL, "error", err)
+			return nil, errutil.Wrapf(err, "error getting groups")
+		}
+
+		var pageGroups []struct {
+			Profile struct {
+				Name string `json:"name"`
+			} `json:"profile"`
+		}
+		if err := json.Unmarshal(response.Body, &pageGroups); err != nil {
+			return nil, errutil.Wrapf(err, "error decoding groups response")
+		}
+		for _, group := range pageGroups {
+			groups = append(groups, group.Profile.Name)
+		}
+
+		// Okta sends a Link header for each of the "self" and "next" pages
+		pageURL = ""
+		for _, link := range response.Headers.Values("Link") {
+			if matches := oktaNextLinkPattern.FindStringSubmatch(link); matches != nil {
+				pageURL = matches[1]
+			}
+		}
+
+		// The access token is sent with each page, so only pages of the Okta API are followed.
+		if pageURL != "" {
+			nextURL, err := url.Parse(pageURL)
+			if err != nil {
+				return nil, errutil.Wrapf(err, "invalid next page of the groups")
+			}
+			if nextURL.Scheme != apiURL.Scheme || nextURL.Host != apiURL.Host {
+				return nil, fmt.Errorf("next page of the groups %q is not on the host of the API URL", pageURL)
+			}
+		}
+	}
+
+	s.log.Debug("Received groups", "count", len(groups))
+	return groups, nil
+}
+
 func (s *SocialOkta) extractRole(data *OktaUserInfoJson) (string, error) {
 	if s.roleAttributePath == "" {
 		return "", nil
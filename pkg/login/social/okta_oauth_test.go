@@ -0,0 +1,104 @@
+package social
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/inconshreveable/log15"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+func TestSocialOkta_UserInfo(t *testing.T) {
+	groupsPages := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var body string
+		switch {
+		case r.URL.Path == "/userinfo":
+			body = `{"name": "Okta User", "email": "okta@example.com", "groups": ["Everyone"]}`
+		case r.URL.Path == "/groups" && r.URL.Query().Get("after") == "":
+			assert.Equal(t, "200", r.URL.Query().Get("limit"))
+			w.Header().Add("Link", fmt.Sprintf(`<http://%s/groups?limit=200>; rel="self"`, r.Host))
+			w.Header().Add("Link", fmt.Sprintf(`<http://%s/groups?after=page2&limit=200>; rel="next"`, r.Host))
+			body = `[{"profile": {"name": "Everyone"}}, {"profile": {"name": "grafana-editors"}}]`
+		case r.URL.Path == "/groups":
+			w.Header().Add("Link", fmt.Sprintf(`<http://%s/groups?after=page2&limit=200>; rel="self"`, r.Host))
+			body = `[{"profile": {"name": "grafana-admins"}}]`
+		case r.URL.Path == "/groups-other-host":
+			w.Header().Add("Link", `<http://attacker.example.com/groups?after=page2&limit=200>; rel="next"`)
+			body = `[{"profile": {"name": "Everyone"}}]`
+		case r.URL.Path == "/groups-endless":
+			groupsPages++
+			w.Header().Add("Link", fmt.Sprintf(`<http://%s/groups-endless?after=page%d>; rel="next"`, r.Host, groupsPages+1))
+			body = `[{"profile": {"name": "Everyone"}}]`
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	newProvider := func(groupsApiUrl string, groupsFilter *regexp.Regexp) *SocialOkta {
+		return &SocialOkta{
+			SocialBase:        &SocialBase{log: newLogger("okta_test", log15.LvlDebug)},
+			apiUrl:            ts.URL + "/userinfo",
+			groupsApiUrl:      groupsApiUrl,
+			groupsFilter:      groupsFilter,
+			roleAttributePath: "contains(groups[*], 'grafana-admins') && 'Admin' || 'Viewer'",
+		}
+	}
+
+	sig, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte("secret")}, (&jose.SignerOptions{}).WithType("JWT"))
+	require.NoError(t, err)
+	idToken, err := jwt.Signed(sig).Claims(OktaClaims{ID: "1234", Email: "okta@example.com", Name: "Okta User"}).CompactSerialize()
+	require.NoError(t, err)
+	token := (&oauth2.Token{}).WithExtra(map[string]interface{}{"id_token": idToken})
+
+	t.Run("Groups of the user info", func(t *testing.T) {
+		userInfo, err := newProvider("", nil).UserInfo(ts.Client(), token)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"Everyone"}, userInfo.Groups)
+		assert.Equal(t, "Viewer", userInfo.Role)
+	})
+
+	t.Run("Groups of all the pages of the groups API", func(t *testing.T) {
+		userInfo, err := newProvider(ts.URL+"/groups", nil).UserInfo(ts.Client(), token)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"Everyone", "grafana-editors", "grafana-admins"}, userInfo.Groups)
+		assert.Equal(t, "Admin", userInfo.Role)
+	})
+
+	t.Run("Groups matching the groups filter", func(t *testing.T) {
+		userInfo, err := newProvider(ts.URL+"/groups", regexp.MustCompile("^grafana-")).UserInfo(ts.Client(), token)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"grafana-editors", "grafana-admins"}, userInfo.Groups)
+		assert.Equal(t, "Admin", userInfo.Role)
+	})
+
+	t.Run("Next page of the groups on another host", func(t *testing.T) {
+		_, err := newProvider(ts.URL+"/groups-other-host", nil).UserInfo(ts.Client(), token)
+		require.Error(t, err)
+	})
+
+	t.Run("Too many pages of groups", func(t *testing.T) {
+		_, err := newProvider(ts.URL+"/groups-endless", nil).UserInfo(ts.Client(), token)
+		require.Error(t, err)
+		assert.Equal(t, oktaGroupsMaxPages, groupsPages)
+	})
+
+	t.Run("Groups API error", func(t *testing.T) {
+		_, err := newProvider(ts.URL+"/missing", nil).UserInfo(ts.Client(), token)
+		require.Error(t, err)
+	})
+}
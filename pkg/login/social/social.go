@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"regexp"
 	"strings"
 
 	"context"
@@ -13,7 +14,9 @@ import (
 	"golang.org/x/oauth2"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
 	"github.com/grafana/grafana/pkg/registry"
+	authjwt "github.com/grafana/grafana/pkg/services/auth/jwt"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -27,7 +30,8 @@ func init() {
 }
 
 type SocialService struct {
-	Cfg *setting.Cfg `inject:""`
+	Cfg         *setting.Cfg             `inject:""`
+	RemoteCache *remotecache.RemoteCache `inject:""`
 
 	socialMap     map[string]SocialConnector
 	oAuthProvider map[string]*OAuthInfo
@@ -37,11 +41,13 @@ type OAuthInfo struct {
 	ClientId, ClientSecret string
 	Scopes                 []string
 	AuthUrl, TokenUrl      string
+	DeviceAuthUrl          string
 	Enabled                bool
 	EmailAttributeName     string
 	EmailAttributePath     string
 	RoleAttributePath      string
 	RoleAttributeStrict    bool
+	SkipOrgRoleSync        bool
 	GroupsAttributePath    string
 	AllowedDomains         []string
 	HostedDomain           string
@@ -52,6 +58,9 @@ type OAuthInfo struct {
 	TlsClientKey           string
 	TlsClientCa            string
 	TlsSkipVerify          bool
+
+	// IDTokenVerifier validates the ID tokens of the provider, when enabled with validate_id_token.
+	IDTokenVerifier *IDTokenVerifier
 }
 
 func (ss *SocialService) Init() error {
@@ -67,12 +76,14 @@ func (ss *SocialService) Init() error {
 			Scopes:              util.SplitString(sec.Key("scopes").String()),
 			AuthUrl:             sec.Key("auth_url").String(),
 			TokenUrl:            sec.Key("token_url").String(),
+			DeviceAuthUrl:       sec.Key("device_auth_url").String(),
 			ApiUrl:              sec.Key("api_url").String(),
 			Enabled:             sec.Key("enabled").MustBool(),
 			EmailAttributeName:  sec.Key("email_attribute_name").String(),
 			EmailAttributePath:  sec.Key("email_attribute_path").String(),
 			RoleAttributePath:   sec.Key("role_attribute_path").String(),
 			RoleAttributeStrict: sec.Key("role_attribute_strict").MustBool(),
+			SkipOrgRoleSync:     sec.Key("skip_org_role_sync").MustBool(),
 			GroupsAttributePath: sec.Key("groups_attribute_path").String(),
 			AllowedDomains:      util.SplitString(sec.Key("allowed_domains").String()),
 			HostedDomain:        sec.Key("hosted_domain").String(),
@@ -99,6 +110,19 @@ func (ss *SocialService) Init() error {
 
 		ss.oAuthProvider[name] = info
 
+		if sec.Key("validate_id_token").MustBool(false) {
+			jwksURL := sec.Key("jwk_set_url").String()
+			if !strings.HasPrefix(jwksURL, "https://") {
+				return fmt.Errorf("jwk_set_url must be an https URL to validate the ID tokens of %s", name)
+			}
+			client, err := ss.GetOAuthHttpClient(name)
+			if err != nil {
+				return err
+			}
+			keySet := authjwt.NewKeySetHTTP(jwksURL, client, ss.RemoteCache, jwksCacheTTL, logger)
+			info.IDTokenVerifier = NewIDTokenVerifier(info.ClientId, sec.Key("id_token_issuer").String(), keySet)
+		}
+
 		config := oauth2.Config{
 			ClientID:     info.ClientId,
 			ClientSecret: info.ClientSecret,
@@ -150,9 +174,19 @@ func (ss *SocialService) Init() error {
 
 		// Okta
 		if name == "okta" {
+			var groupsFilter *regexp.Regexp
+			if filter := sec.Key("groups_filter").String(); filter != "" {
+				var err error
+				if groupsFilter, err = regexp.Compile(filter); err != nil {
+					return fmt.Errorf("invalid groups_filter for Okta: %w", err)
+				}
+			}
+
 			ss.socialMap["okta"] = &SocialOkta{
 				SocialBase:          newSocialBase(name, &config, info),
 				apiUrl:              info.ApiUrl,
+				groupsApiUrl:        sec.Key("groups_api_url").String(),
+				groupsFilter:        groupsFilter,
 				allowedGroups:       util.SplitString(sec.Key("allowed_groups").String()),
 				roleAttributePath:   info.RoleAttributePath,
 				roleAttributeStrict: info.RoleAttributeStrict,
@@ -220,6 +254,10 @@ type SocialConnector interface {
 	Exchange(ctx context.Context, code string, authOptions ...oauth2.AuthCodeOption) (*oauth2.Token, error)
 	Client(ctx context.Context, t *oauth2.Token) *http.Client
 	TokenSource(ctx context.Context, t *oauth2.Token) oauth2.TokenSource
+
+	SupportsDeviceAuth() bool
+	DeviceAuth(ctx context.Context, client *http.Client) (*DeviceAuthorization, error)
+	DeviceAccessToken(ctx context.Context, client *http.Client, deviceCode string) (*oauth2.Token, error)
 }
 
 type SocialBase struct {
@@ -227,6 +265,7 @@ type SocialBase struct {
 	log            log.Logger
 	allowSignup    bool
 	allowedDomains []string
+	deviceAuthUrl  string
 }
 
 type Error struct {
@@ -263,6 +302,7 @@ func newSocialBase(name string, config *oauth2.Config, info *OAuthInfo) *SocialB
 		log:            logger,
 		allowSignup:    info.AllowSignup,
 		allowedDomains: info.AllowedDomains,
+		deviceAuthUrl:  info.DeviceAuthUrl,
 	}
 }
 
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+
+	"gopkg.in/macaron.v1"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/audit"
+)
+
+// Audit returns a function that returns a middleware recording the requests of a route in the
+// audit log once they're handled. The target of the action is read from the given route parameter.
+func Audit(auditService *audit.Service) func(action, targetType, targetParam string) macaron.Handler {
+	return func(action, targetType, targetParam string) macaron.Handler {
+		return func(c *models.ReqContext) {
+			c.Next()
+
+			status := c.Resp.Status()
+			entry := models.AuditEntry{
+				Action:  action,
+				Success: status < 400,
+			}
+			if targetParam != "" {
+				entry.Target = targetType + ":" + c.Params(targetParam)
+			}
+			if !entry.Success {
+				entry.Message = http.StatusText(status)
+			}
+
+			auditService.Record(c, entry)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/contexthandler"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddlewareClientCertAuth(t *testing.T) {
+	const id int64 = 12
+	const orgID int64 = 2
+
+	configure := func(cfg *setting.Cfg) {
+		cfg.ClientCertAuthEnabled = true
+		cfg.ClientCertUsernameSource = "subject_cn"
+	}
+
+	withCert := func(sc *scenarioContext, cert *x509.Certificate) {
+		sc.req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+	}
+
+	middlewareScenario(t, "Verified certificate of a known user", func(t *testing.T, sc *scenarioContext) {
+		var upserted *models.UpsertUserCommand
+		bus.AddHandler("upsert-user", func(cmd *models.UpsertUserCommand) error {
+			upserted = cmd
+			cmd.Result = &models.User{Id: id, Login: cmd.ExternalUser.Login}
+			return nil
+		})
+		bus.AddHandlerCtx("get-sign-user", func(ctx context.Context, query *models.GetSignedInUserQuery) error {
+			query.Result = &models.SignedInUser{
+				UserId: query.UserId,
+				OrgId:  orgID,
+				Login:  "deploy-bot",
+			}
+			return nil
+		})
+
+		sc.fakeReq("GET", "/")
+		withCert(sc, &x509.Certificate{Subject: pkix.Name{CommonName: "deploy-bot"}})
+		sc.exec()
+
+		assert.Equal(t, 200, sc.resp.Code)
+		assert.True(t, sc.context.IsSignedIn)
+		assert.Equal(t, id, sc.context.UserId)
+		require.NotNil(t, upserted)
+		assert.False(t, upserted.SignupAllowed)
+		assert.Equal(t, models.AuthModuleClientCert, upserted.ExternalUser.AuthModule)
+		assert.Equal(t, "deploy-bot", upserted.ExternalUser.Login)
+	}, configure)
+
+	middlewareScenario(t, "Verified certificate without the configured username field", func(t *testing.T, sc *scenarioContext) {
+		sc.fakeReq("GET", "/")
+		withCert(sc, &x509.Certificate{Subject: pkix.Name{CommonName: "deploy-bot"}})
+		sc.exec()
+
+		assert.Equal(t, 401, sc.resp.Code)
+		assert.Equal(t, contexthandler.InvalidClientCert, sc.respJson["message"])
+	}, configure, func(cfg *setting.Cfg) {
+		cfg.ClientCertUsernameSource = "san_email"
+	})
+
+	middlewareScenario(t, "TLS connection without a client certificate", func(t *testing.T, sc *scenarioContext) {
+		sc.fakeReq("GET", "/")
+		sc.req.TLS = &tls.ConnectionState{}
+		sc.exec()
+
+		assert.Equal(t, 200, sc.resp.Code)
+		assert.False(t, sc.context.IsSignedIn)
+	}, configure)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/contexthandler"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMiddlewareJWTAuth(t *testing.T) {
@@ -111,6 +112,108 @@ func TestMiddlewareJWTAuth(t *testing.T) {
 		assert.Equal(t, contexthandler.InvalidJWT, sc.respJson["message"])
 	}, configure, configureEmailClaim)
 
+	configureRoleSync := func(cfg *setting.Cfg) {
+		cfg.JWTAuthRoleAttributePath = "contains(roles[*], 'admin') && 'Admin' || 'Viewer'"
+		cfg.JWTAuthAutoSignUp = true
+	}
+
+	middlewareScenario(t, "Valid token syncs user and role", func(t *testing.T, sc *scenarioContext) {
+		myUsername := "vladimir"
+		sc.jwtAuthService.VerifyProvider = func(ctx context.Context, token string) (models.JWTClaims, error) {
+			return models.JWTClaims{
+				"sub":          "vladimir-id",
+				"foo-username": myUsername,
+				"roles":        []interface{}{"dev", "admin"},
+			}, nil
+		}
+		var upsert *models.UpsertUserCommand
+		bus.AddHandler("upsert-user", func(cmd *models.UpsertUserCommand) error {
+			upsert = cmd
+			cmd.Result = &models.User{Id: id}
+			return nil
+		})
+		bus.AddHandlerCtx("get-sign-user", func(ctx context.Context, query *models.GetSignedInUserQuery) error {
+			query.Result = &models.SignedInUser{
+				UserId:  query.UserId,
+				OrgId:   orgID,
+				OrgRole: models.ROLE_ADMIN,
+			}
+			return nil
+		})
+
+		sc.fakeReq("GET", "/").withJWTAuthHeader(token).exec()
+		assert.Equal(t, 200, sc.resp.Code)
+		assert.True(t, sc.context.IsSignedIn)
+		assert.Equal(t, id, sc.context.UserId)
+		require.NotNil(t, upsert)
+		assert.True(t, upsert.SignupAllowed)
+		assert.Equal(t, "jwt", upsert.ExternalUser.AuthModule)
+		assert.Equal(t, "vladimir-id", upsert.ExternalUser.AuthId)
+		assert.Equal(t, myUsername, upsert.ExternalUser.Login)
+		assert.Equal(t, map[int64]models.RoleType{1: models.ROLE_ADMIN}, upsert.ExternalUser.OrgRoles)
+	}, configure, configureUsernameClaim, configureRoleSync)
+
+	middlewareScenario(t, "Valid token syncs user once", func(t *testing.T, sc *scenarioContext) {
+		role := "admin"
+		sc.jwtAuthService.VerifyProvider = func(ctx context.Context, token string) (models.JWTClaims, error) {
+			return models.JWTClaims{
+				"sub":          "vladimir-id",
+				"foo-username": "vladimir",
+				"roles":        []interface{}{"dev", role},
+			}, nil
+		}
+		upserts := 0
+		bus.AddHandler("upsert-user", func(cmd *models.UpsertUserCommand) error {
+			upserts++
+			cmd.Result = &models.User{Id: id}
+			return nil
+		})
+		deleted := false
+		bus.AddHandlerCtx("get-sign-user", func(ctx context.Context, query *models.GetSignedInUserQuery) error {
+			if deleted {
+				deleted = false
+				return models.ErrUserNotFound
+			}
+			query.Result = &models.SignedInUser{UserId: query.UserId, OrgId: orgID}
+			return nil
+		})
+
+		sc.fakeReq("GET", "/").withJWTAuthHeader(token).exec()
+		sc.fakeReq("GET", "/").withJWTAuthHeader(token).exec()
+		assert.Equal(t, 200, sc.resp.Code)
+		assert.Equal(t, id, sc.context.UserId)
+		assert.Equal(t, 1, upserts)
+
+		// Changed claims sync the user again
+		role = "dev"
+		sc.fakeReq("GET", "/").withJWTAuthHeader(token).exec()
+		assert.Equal(t, 200, sc.resp.Code)
+		assert.Equal(t, 2, upserts)
+
+		// A cached user that was deleted is synced again
+		deleted = true
+		sc.fakeReq("GET", "/").withJWTAuthHeader(token).exec()
+		assert.Equal(t, 200, sc.resp.Code)
+		assert.Equal(t, id, sc.context.UserId)
+		assert.Equal(t, 3, upserts)
+	}, configure, configureUsernameClaim, configureRoleSync)
+
+	middlewareScenario(t, "Valid token with invalid role and strict role mapping", func(t *testing.T, sc *scenarioContext) {
+		sc.jwtAuthService.VerifyProvider = func(ctx context.Context, token string) (models.JWTClaims, error) {
+			return models.JWTClaims{
+				"foo-username": "vladimir",
+				"role":         "superuser",
+			}, nil
+		}
+
+		sc.fakeReq("GET", "/").withJWTAuthHeader(token).exec()
+		assert.Equal(t, 401, sc.resp.Code)
+		assert.Equal(t, contexthandler.InvalidJWT, sc.respJson["message"])
+	}, configure, configureUsernameClaim, func(cfg *setting.Cfg) {
+		cfg.JWTAuthRoleAttributePath = "role"
+		cfg.JWTAuthRoleAttributeStrict = true
+	})
+
 	middlewareScenario(t, "Invalid token", func(t *testing.T, sc *scenarioContext) {
 		var verifiedToken string
 		sc.jwtAuthService.VerifyProvider = func(ctx context.Context, token string) (models.JWTClaims, error) {
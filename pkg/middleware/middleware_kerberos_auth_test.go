@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/contexthandler"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddlewareKerberosAuth(t *testing.T) {
+	const id int64 = 12
+	const orgID int64 = 2
+
+	configure := func(cfg *setting.Cfg) {
+		cfg.KerberosAuthEnabled = true
+		cfg.KerberosStripRealm = true
+		cfg.KerberosAllowedRealms = []string{"EXAMPLE.COM"}
+	}
+
+	token := "c29tZS10aWNrZXQ="
+
+	middlewareScenario(t, "Valid ticket from an allowed realm", func(t *testing.T, sc *scenarioContext) {
+		var verifiedToken string
+		sc.kerberosAuthService.VerifyProvider = func(ctx context.Context, token string, clientIP net.IP) (*models.KerberosPrincipal, error) {
+			verifiedToken = token
+			return &models.KerberosPrincipal{Username: "vladimir", Realm: "example.com", DisplayName: "Vladimir"}, nil
+		}
+		var upserted *models.ExternalUserInfo
+		bus.AddHandler("upsert-user", func(cmd *models.UpsertUserCommand) error {
+			upserted = cmd.ExternalUser
+			cmd.Result = &models.User{Id: id, Login: cmd.ExternalUser.Login}
+			return nil
+		})
+		bus.AddHandlerCtx("get-sign-user", func(ctx context.Context, query *models.GetSignedInUserQuery) error {
+			query.Result = &models.SignedInUser{
+				UserId: query.UserId,
+				OrgId:  orgID,
+				Login:  "vladimir",
+			}
+			return nil
+		})
+
+		sc.fakeReq("GET", "/").withAuthorizationHeader("Negotiate " + token).exec()
+		assert.Equal(t, token, verifiedToken)
+		assert.Equal(t, 200, sc.resp.Code)
+		assert.True(t, sc.context.IsSignedIn)
+		assert.Equal(t, id, sc.context.UserId)
+		require.NotNil(t, upserted)
+		assert.Equal(t, models.AuthModuleKerberos, upserted.AuthModule)
+		assert.Equal(t, "vladimir@example.com", upserted.AuthId)
+		assert.Equal(t, "vladimir", upserted.Login)
+		assert.Equal(t, "Vladimir", upserted.Name)
+	}, configure)
+
+	middlewareScenario(t, "Valid ticket from a realm that is not allowed", func(t *testing.T, sc *scenarioContext) {
+		sc.kerberosAuthService.VerifyProvider = func(ctx context.Context, token string, clientIP net.IP) (*models.KerberosPrincipal, error) {
+			return &models.KerberosPrincipal{Username: "vladimir", Realm: "OTHER.COM"}, nil
+		}
+
+		sc.fakeReq("GET", "/").withAuthorizationHeader("Negotiate " + token).exec()
+		assert.Equal(t, 401, sc.resp.Code)
+		assert.Equal(t, contexthandler.InvalidKerberosTicket, sc.respJson["message"])
+	}, configure)
+
+	middlewareScenario(t, "Invalid ticket", func(t *testing.T, sc *scenarioContext) {
+		sc.kerberosAuthService.VerifyProvider = func(ctx context.Context, token string, clientIP net.IP) (*models.KerberosPrincipal, error) {
+			return nil, errors.New("checksum mismatch")
+		}
+
+		sc.fakeReq("GET", "/").withAuthorizationHeader("Negotiate " + token).exec()
+		assert.Equal(t, 401, sc.resp.Code)
+		assert.Equal(t, contexthandler.InvalidKerberosTicket, sc.respJson["message"])
+	}, configure)
+
+	middlewareScenario(t, "Unknown user without auto sign up", func(t *testing.T, sc *scenarioContext) {
+		sc.kerberosAuthService.VerifyProvider = func(ctx context.Context, token string, clientIP net.IP) (*models.KerberosPrincipal, error) {
+			return &models.KerberosPrincipal{Username: "vladimir", Realm: "EXAMPLE.COM"}, nil
+		}
+		bus.AddHandler("upsert-user", func(cmd *models.UpsertUserCommand) error {
+			assert.False(t, cmd.SignupAllowed)
+			return errors.New("user not found")
+		})
+
+		sc.fakeReq("GET", "/").withAuthorizationHeader("Negotiate " + token).exec()
+		assert.Equal(t, 401, sc.resp.Code)
+		assert.Equal(t, contexthandler.InvalidKerberosTicket, sc.respJson["message"])
+	}, configure)
+}
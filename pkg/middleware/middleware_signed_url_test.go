@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/contexthandler"
+	"github.com/grafana/grafana/pkg/services/urlsigner"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddlewareSignedURL(t *testing.T) {
+	const userID int64 = 12
+	const orgID int64 = 2
+
+	configure := func(cfg *setting.Cfg) {
+		cfg.URLSigningEnabled = true
+		cfg.URLSigningDefaultTTL = time.Hour
+		cfg.URLSigningMaxTTL = time.Hour
+	}
+
+	addUserHandlers := func(disabled bool) {
+		bus.AddHandlerCtx("get-user", func(ctx context.Context, query *models.GetUserByIdQuery) error {
+			query.Result = &models.User{Id: query.Id, IsDisabled: disabled}
+			return nil
+		})
+		bus.AddHandlerCtx("get-sign-user", func(ctx context.Context, query *models.GetSignedInUserQuery) error {
+			query.Result = &models.SignedInUser{UserId: query.UserId, OrgId: query.OrgId}
+			return nil
+		})
+	}
+
+	middlewareScenario(t, "Valid signed URL", func(t *testing.T, sc *scenarioContext) {
+		addUserHandlers(false)
+
+		signed, err := sc.contextHandler.URLSigner.Sign("/render/d-solo/abc/dash?panelId=2", userID, orgID, 0)
+		require.NoError(t, err)
+
+		sc.m.Get("/render/*", sc.defaultHandler)
+		sc.fakeReq("GET", signed)
+		sc.exec()
+
+		assert.Equal(t, 200, sc.resp.Code)
+		assert.True(t, sc.context.IsSignedIn)
+		assert.Equal(t, userID, sc.context.UserId)
+		assert.Equal(t, orgID, sc.context.OrgId)
+	}, configure)
+
+	middlewareScenario(t, "Valid URL signed for a role", func(t *testing.T, sc *scenarioContext) {
+		signed, err := sc.contextHandler.URLSigner.SignForRole("/render/d-solo/abc/dash?panelId=2", orgID, models.ROLE_VIEWER, 0)
+		require.NoError(t, err)
+
+		sc.m.Get("/render/*", sc.defaultHandler)
+		sc.fakeReq("GET", signed)
+		sc.exec()
+
+		assert.Equal(t, 200, sc.resp.Code)
+		assert.True(t, sc.context.IsSignedIn)
+		assert.Equal(t, int64(0), sc.context.UserId)
+		assert.Equal(t, orgID, sc.context.OrgId)
+		assert.Equal(t, models.ROLE_VIEWER, sc.context.OrgRole)
+	}, configure)
+
+	middlewareScenario(t, "Signed URL of a disabled user", func(t *testing.T, sc *scenarioContext) {
+		addUserHandlers(true)
+
+		signed, err := sc.contextHandler.URLSigner.Sign("/render/d-solo/abc/dash?panelId=2", userID, orgID, 0)
+		require.NoError(t, err)
+
+		sc.m.Get("/render/*", sc.defaultHandler)
+		sc.fakeReq("GET", signed)
+		sc.exec()
+
+		assert.Equal(t, 401, sc.resp.Code)
+		assert.Equal(t, contexthandler.InvalidSignedURL, sc.respJson["message"])
+	}, configure)
+
+	middlewareScenario(t, "Signed URL used with another method than GET", func(t *testing.T, sc *scenarioContext) {
+		addUserHandlers(false)
+
+		signed, err := sc.contextHandler.URLSigner.Sign("/api/snapshots/abc", userID, orgID, 0)
+		require.NoError(t, err)
+
+		sc.m.Delete("/api/snapshots/:key", sc.defaultHandler)
+		sc.fakeReq("DELETE", signed)
+		sc.exec()
+
+		assert.Equal(t, 401, sc.resp.Code)
+		assert.Equal(t, contexthandler.InvalidSignedURL, sc.respJson["message"])
+	}, configure)
+
+	middlewareScenario(t, "Tampered signed URL", func(t *testing.T, sc *scenarioContext) {
+		signed, err := sc.contextHandler.URLSigner.Sign("/render/d-solo/abc/dash?panelId=2", userID, orgID, 0)
+		require.NoError(t, err)
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		query := u.Query()
+		query.Set(urlsigner.UserIDParam, "1")
+		u.RawQuery = query.Encode()
+
+		sc.m.Get("/render/*", sc.defaultHandler)
+		sc.fakeReq("GET", u.String())
+		sc.exec()
+
+		assert.Equal(t, 401, sc.resp.Code)
+		assert.Equal(t, contexthandler.InvalidSignedURL, sc.respJson["message"])
+	}, configure)
+}
@@ -32,6 +32,7 @@ type scenarioContext struct {
 	url                  string
 	userAuthTokenService *auth.FakeUserAuthTokenService
 	jwtAuthService       *models.FakeJWTService
+	kerberosAuthService  *models.FakeKerberosService
 	remoteCacheService   *remotecache.RemoteCache
 	cfg                  *setting.Cfg
 	sqlStore             *sqlstore.SQLStore
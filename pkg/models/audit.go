@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// Audit actions
+const (
+	AuditActionLogin                       = "login"
+	AuditActionLoginFailed                 = "login-failed"
+	AuditActionAPIKeyCreated               = "api-key-created"
+	AuditActionAPIKeyDeleted               = "api-key-deleted"
+	AuditActionAuthTokenRevoked            = "auth-token-revoked"
+	AuditActionAuthTokensRevoked           = "auth-tokens-revoked"
+	AuditActionDashboardPermissionsUpdated = "dashboard-permissions-updated"
+	AuditActionFolderPermissionsUpdated    = "folder-permissions-updated"
+	AuditActionOrgUserAdded                = "org-user-added"
+	AuditActionOrgUserRoleUpdated          = "org-user-role-updated"
+	AuditActionOrgUserRemoved              = "org-user-removed"
+	AuditActionServerAdminUpdated          = "server-admin-updated"
+	AuditActionUserRoleAdded               = "user-role-added"
+	AuditActionUserRoleRemoved             = "user-role-removed"
+	AuditActionBuiltinRoleAdded            = "builtin-role-added"
+	AuditActionBuiltinRoleRemoved          = "builtin-role-removed"
+	AuditActionImpersonationStarted        = "impersonation-started"
+)
+
+// AuditEntry is an authentication or authorization event recorded in the audit log.
+// The user is the one who performed the action, and the target what it was performed
+// on, as type:id. The impersonator is the Grafana Admin acting as the user, if any.
+type AuditEntry struct {
+	Id                 int64     `json:"id"`
+	OrgId              int64     `json:"orgId"`
+	Action             string    `json:"action"`
+	Success            bool      `json:"success"`
+	UserId             int64     `json:"userId"`
+	UserLogin          string    `json:"userLogin"`
+	ImpersonatorUserId int64     `json:"impersonatorUserId,omitempty"`
+	AuthModule         string    `json:"authModule"`
+	IpAddress          string    `json:"ipAddress"`
+	Target             string    `json:"target"`
+	Message            string    `json:"message"`
+	Created            time.Time `json:"created"`
+}
+
+func (e AuditEntry) TableName() string {
+	return "audit_log"
+}
+
+// ---------------------
+// QUERIES
+
+type SearchAuditEntriesQuery struct {
+	Action string
+	UserId int64
+	Target string
+	From   time.Time
+	To     time.Time
+	Limit  int
+	Page   int
+
+	Result SearchAuditEntriesQueryResult
+}
+
+type SearchAuditEntriesQueryResult struct {
+	TotalCount int64         `json:"totalCount"`
+	Entries    []*AuditEntry `json:"entries"`
+	Page       int           `json:"page"`
+	PerPage    int           `json:"perPage"`
+}
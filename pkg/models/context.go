@@ -39,8 +39,10 @@ func (ctx *ReqContext) Handle(cfg *setting.Cfg, status int, title string, err er
 	ctx.HTML(status, cfg.ErrTemplateName, ctx.Data)
 }
 
+// IsApiRequest returns true for requests to the HTTP API, including the SCIM
+// provisioning endpoints, which get errors instead of login redirects.
 func (ctx *ReqContext) IsApiRequest() bool {
-	return strings.HasPrefix(ctx.Req.URL.Path, "/api")
+	return strings.HasPrefix(ctx.Req.URL.Path, "/api") || strings.HasPrefix(ctx.Req.URL.Path, "/scim/")
 }
 
 func (ctx *ReqContext) JsonApiErr(status int, message string, err error) {
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"context"
+	"net"
+)
+
+// KerberosPrincipal is the client principal of a verified Kerberos ticket.
+type KerberosPrincipal struct {
+	Username    string
+	Realm       string
+	DisplayName string
+}
+
+func (p KerberosPrincipal) String() string {
+	return p.Username + "@" + p.Realm
+}
+
+type KerberosService interface {
+	Verify(ctx context.Context, negotiateToken string, clientIP net.IP) (*KerberosPrincipal, error)
+}
+
+type FakeKerberosService struct {
+	VerifyProvider func(context.Context, string, net.IP) (*KerberosPrincipal, error)
+}
+
+func (s *FakeKerberosService) Verify(ctx context.Context, token string, clientIP net.IP) (*KerberosPrincipal, error) {
+	return s.VerifyProvider(ctx, token, clientIP)
+}
+
+func (s *FakeKerberosService) Init() error {
+	return nil
+}
+
+func NewFakeKerberosService() *FakeKerberosService {
+	return &FakeKerberosService{
+		VerifyProvider: func(ctx context.Context, token string, clientIP net.IP) (*KerberosPrincipal, error) {
+			return &KerberosPrincipal{}, nil
+		},
+	}
+}
@@ -34,3 +34,9 @@ type GetUserLoginAttemptCountQuery struct {
 	Since    time.Time
 	Result   int64
 }
+
+type GetIPLoginAttemptCountQuery struct {
+	IpAddress string
+	Since     time.Time
+	Result    int64
+}
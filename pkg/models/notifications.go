@@ -1,9 +1,13 @@
 package models
 
-import "errors"
+import (
+	"crypto/tls"
+	"errors"
+)
 
 var ErrInvalidEmailCode = errors.New("invalid or expired email code")
 var ErrSmtpNotEnabled = errors.New("SMTP not configured, check your grafana.ini config file's [smtp] section")
+var ErrSmsNotEnabled = errors.New("SMS not configured, check your grafana.ini config file's [sms] section")
 
 // SendEmailAttachFile is a definition of the attached files without path
 type SendEmailAttachFile struct {
@@ -29,6 +33,27 @@ type SendEmailCommandSync struct {
 	SendEmailCommand
 }
 
+// SendSMSCommandSync is the command for sending a text message to phone numbers synchronously
+type SendSMSCommandSync struct {
+	To      []string
+	Message string
+}
+
+// ReloadEmailTemplatesCommand is the command for reloading the templates of the emails
+type ReloadEmailTemplatesCommand struct{}
+
+// SendWebhook is the command for sending a webhook asynchronously. The webhook is stored until it
+// is sent, so that it is retried after a failure or a restart.
+type SendWebhook struct {
+	Url         string
+	User        string
+	Password    string
+	Body        string
+	HttpMethod  string
+	HttpHeader  map[string]string
+	ContentType string
+}
+
 type SendWebhookSync struct {
 	Url         string
 	User        string
@@ -37,6 +62,9 @@ type SendWebhookSync struct {
 	HttpMethod  string
 	HttpHeader  map[string]string
 	ContentType string
+	// TLSConfig is the TLS configuration of the request, such as client certificates or a custom CA,
+	// instead of the default one.
+	TLSConfig *tls.Config
 }
 
 type SendResetPasswordEmailCommand struct {
@@ -8,7 +8,10 @@ import (
 )
 
 const (
-	AuthModuleLDAP = "ldap"
+	AuthModuleLDAP       = "ldap"
+	AuthModuleSCIM       = "scim"
+	AuthModuleKerberos   = "kerberos"
+	AuthModuleClientCert = "clientcert"
 )
 
 type UserAuth struct {
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// UserPasswordHistory is a password a user has had, stored as the salted
+// hash. The newest entry is the current password.
+type UserPasswordHistory struct {
+	Id       int64
+	UserId   int64
+	Password string
+	Created  time.Time
+}
+
+// ---------------------
+// QUERIES
+
+type GetUserPasswordHistoryQuery struct {
+	UserId int64
+	Limit  int
+
+	Result []*UserPasswordHistory
+}
@@ -55,6 +55,9 @@ type UserToken struct {
 	UpdatedAt     int64
 	RevokedAt     int64
 	UnhashedToken string
+
+	// ImpersonatorUserId is the Grafana Admin who created the token to act as the user, if any.
+	ImpersonatorUserId int64
 }
 
 type RevokeAuthTokenCmd struct {
@@ -64,6 +67,7 @@ type RevokeAuthTokenCmd struct {
 // UserTokenService are used for generating and validating user tokens
 type UserTokenService interface {
 	CreateToken(ctx context.Context, user *User, clientIP net.IP, userAgent string) (*UserToken, error)
+	CreateImpersonationToken(ctx context.Context, user *User, impersonatorID int64, clientIP net.IP, userAgent string) (*UserToken, error)
 	LookupToken(ctx context.Context, unhashedToken string) (*UserToken, error)
 	TryRotateToken(ctx context.Context, token *UserToken, clientIP net.IP, userAgent string) (bool, error)
 	RevokeToken(ctx context.Context, token *UserToken, soft bool) error
@@ -0,0 +1,40 @@
+package plugins
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+)
+
+// angularPatterns are found in the modules of plugins that are built with the
+// AngularJS plugin SDK, such as the base classes of panel and query editor
+// controllers and the paths of their templates.
+var angularPatterns = [][]byte{
+	[]byte("PanelCtrl"),
+	[]byte("QueryCtrl"),
+	[]byte("ConfigCtrl"),
+	[]byte("app/plugins/sdk"),
+	[]byte("angular.isNumber("),
+	[]byte("editor.html"),
+	[]byte("ctrl.annotation"),
+}
+
+// detectAngular returns whether the module.js file of a plugin uses AngularJS.
+// Plugins without a module.js file, such as plugins that only have a backend,
+// don't use AngularJS.
+func detectAngular(pluginDir string) bool {
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because the path is the
+	// plugin directory found when scanning the plugins.
+	b, err := ioutil.ReadFile(filepath.Join(pluginDir, "module.js"))
+	if err != nil {
+		return false
+	}
+
+	for _, pattern := range angularPatterns {
+		if bytes.Contains(b, pattern) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package plugins
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectAngular(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		module   string
+		expected bool
+	}{
+		{name: "angular panel", module: `define(["app/plugins/sdk"], function(sdk) { class Ctrl extends sdk.MetricsPanelCtrl {} })`, expected: true},
+		{name: "angular query editor", module: `e.QueryCtrl=o,e.templateUrl="partials/query.editor.html"`, expected: true},
+		{name: "react panel", module: `define(["@grafana/data"], function(data) { return new data.PanelPlugin(Panel) })`, expected: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pluginDir := t.TempDir()
+			err := ioutil.WriteFile(filepath.Join(pluginDir, "module.js"), []byte(tc.module), 0600)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expected, detectAngular(pluginDir))
+		})
+	}
+
+	t.Run("plugin without module", func(t *testing.T) {
+		require.False(t, detectAngular(t.TempDir()))
+	})
+}
@@ -7,3 +7,26 @@ import (
 
 // PluginFactoryFunc is a function type for creating a Plugin.
 type PluginFactoryFunc func(pluginID string, logger log.Logger, env []string) (Plugin, error)
+
+// PluginState is the state of the process of a backend plugin.
+type PluginState string
+
+const (
+	// PluginStateRunning is the state of a plugin process that is running and healthy.
+	PluginStateRunning PluginState = "running"
+	// PluginStateUnhealthy is the state of a plugin process that failed a
+	// health check, and is being restarted.
+	PluginStateUnhealthy PluginState = "unhealthy"
+	// PluginStateExited is the state of a plugin process that exited, and is
+	// waiting to be restarted.
+	PluginStateExited PluginState = "exited"
+	// PluginStateFailed is the state of a plugin process that failed to start.
+	PluginStateFailed PluginState = "failed"
+)
+
+// PluginStatus is the status of the process of a backend plugin.
+type PluginStatus struct {
+	State     PluginState `json:"state"`
+	Restarts  int         `json:"restarts"`
+	LastError string      `json:"lastError,omitempty"`
+}
@@ -22,11 +22,12 @@ var handshake = goplugin.HandshakeConfig{
 	MagicCookieValue: grpcplugin.MagicCookieValue,
 }
 
-func newClientConfig(executablePath string, env []string, logger log.Logger,
+func newClientConfig(args []string, env []string, logger log.Logger,
 	versionedPlugins map[int]goplugin.PluginSet) *goplugin.ClientConfig {
-	// We can ignore gosec G201 here, since the dynamic part of executablePath comes from the plugin definition
+	// We can ignore gosec G201 and G204 here, since the dynamic part of args comes from the plugin definition
+	// and the process settings of the plugin
 	// nolint:gosec
-	cmd := exec.Command(executablePath)
+	cmd := exec.Command(args[0], args[1:]...)
 	cmd.Env = env
 
 	return &goplugin.ClientConfig{
@@ -35,6 +36,7 @@ func newClientConfig(executablePath string, env []string, logger log.Logger,
 		VersionedPlugins: versionedPlugins,
 		Logger:           logWrapper{Logger: logger},
 		AllowedProtocols: []goplugin.Protocol{goplugin.ProtocolGRPC},
+		GRPCDialOptions:  tracingDialOptions(),
 	}
 }
 
@@ -14,6 +14,7 @@ import (
 	"github.com/grafana/grafana/pkg/plugins/backendplugin/pluginextensionv2"
 	"github.com/grafana/grafana/pkg/util/errutil"
 	"github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -92,6 +93,25 @@ func newClientV2(descriptor PluginDescriptor, logger log.Logger, rpcClient plugi
 	return &c, nil
 }
 
+// spreadOver spreads the queries and resource calls of the client over conns,
+// in addition to the connection of the client.
+func (c *clientV2) spreadOver(conns []*grpc.ClientConn) {
+	if c.DataClient != nil {
+		data := &roundRobinDataClient{clients: []pluginv2.DataClient{c.DataClient}}
+		for _, conn := range conns {
+			data.clients = append(data.clients, pluginv2.NewDataClient(conn))
+		}
+		c.DataClient = data
+	}
+	if c.ResourceClient != nil {
+		resource := &roundRobinResourceClient{clients: []pluginv2.ResourceClient{c.ResourceClient}}
+		for _, conn := range conns {
+			resource.clients = append(resource.clients, pluginv2.NewResourceClient(conn))
+		}
+		c.ResourceClient = resource
+	}
+}
+
 func (c *clientV2) CollectMetrics(ctx context.Context) (*backend.CollectMetricsResult, error) {
 	if c.DiagnosticsClient == nil {
 		return &backend.CollectMetricsResult{}, nil
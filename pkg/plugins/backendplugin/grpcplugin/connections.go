@@ -0,0 +1,99 @@
+package grpcplugin
+
+import (
+	"context"
+	"math"
+	"net"
+	"sync/atomic"
+
+	"github.com/grafana/grafana-plugin-sdk-go/genproto/pluginv2"
+	"google.golang.org/grpc"
+)
+
+// dialConnections opens n gRPC connections to the plugin process listening on
+// addr, with the options of the connection opened by go-plugin.
+func dialConnections(addr net.Addr, n int) ([]*grpc.ClientConn, error) {
+	opts := []grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, addr.Network(), addr.String())
+		}),
+		grpc.WithInsecure(),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(math.MaxInt32), grpc.MaxCallSendMsgSize(math.MaxInt32)),
+	}
+	opts = append(opts, tracingDialOptions()...)
+
+	conns := make([]*grpc.ClientConn, 0, n)
+	for i := 0; i < n; i++ {
+		// the address is resolved by the dialer
+		conn, err := grpc.Dial("plugin", opts...)
+		if err != nil {
+			closeConnections(conns)
+			return nil, err
+		}
+		conns = append(conns, conn)
+	}
+	return conns, nil
+}
+
+func closeConnections(conns []*grpc.ClientConn) {
+	for _, conn := range conns {
+		// the connections are closed with the plugin process, which makes the
+		// errors of no interest
+		_ = conn.Close()
+	}
+}
+
+// roundRobinDataClient spreads queries over the data clients of several
+// connections to a plugin.
+type roundRobinDataClient struct {
+	clients []pluginv2.DataClient
+	next    uint32
+}
+
+func (c *roundRobinDataClient) QueryData(ctx context.Context, req *pluginv2.QueryDataRequest, opts ...grpc.CallOption) (*pluginv2.QueryDataResponse, error) {
+	i := atomic.AddUint32(&c.next, 1)
+	return c.clients[i%uint32(len(c.clients))].QueryData(ctx, req, opts...)
+}
+
+// roundRobinResourceClient spreads resource calls over the resource clients of
+// several connections to a plugin.
+type roundRobinResourceClient struct {
+	clients []pluginv2.ResourceClient
+	next    uint32
+}
+
+func (c *roundRobinResourceClient) CallResource(ctx context.Context, req *pluginv2.CallResourceRequest, opts ...grpc.CallOption) (pluginv2.Resource_CallResourceClient, error) {
+	i := atomic.AddUint32(&c.next, 1)
+	return c.clients[i%uint32(len(c.clients))].CallResource(ctx, req, opts...)
+}
+
+// requestLimiter limits the number of concurrent requests to a plugin. A nil
+// limiter doesn't limit them.
+type requestLimiter chan struct{}
+
+func newRequestLimiter(max int) requestLimiter {
+	if max <= 0 {
+		return nil
+	}
+	return make(requestLimiter, max)
+}
+
+// acquire waits until a request can be sent, or ctx is done.
+func (l requestLimiter) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	select {
+	case l <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l requestLimiter) release() {
+	if l != nil {
+		<-l
+	}
+}
@@ -0,0 +1,96 @@
+package grpcplugin
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/genproto/pluginv2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestRoundRobinDataClient(t *testing.T) {
+	clients := []*fakeDataClient{{}, {}, {}}
+	c := &roundRobinDataClient{}
+	for _, client := range clients {
+		c.clients = append(c.clients, client)
+	}
+
+	for i := 0; i < 6; i++ {
+		_, err := c.QueryData(context.Background(), &pluginv2.QueryDataRequest{})
+		require.NoError(t, err)
+	}
+	for _, client := range clients {
+		assert.Equal(t, 2, client.queries)
+	}
+}
+
+func TestRequestLimiter(t *testing.T) {
+	t.Run("Should not limit requests without maximum", func(t *testing.T) {
+		l := newRequestLimiter(0)
+		require.Nil(t, l)
+		require.NoError(t, l.acquire(context.Background()))
+		l.release()
+	})
+
+	t.Run("Should wait for a request to be released", func(t *testing.T) {
+		l := newRequestLimiter(1)
+		require.NoError(t, l.acquire(context.Background()))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		require.Equal(t, context.DeadlineExceeded, l.acquire(ctx))
+
+		l.release()
+		require.NoError(t, l.acquire(context.Background()))
+	})
+}
+
+func TestDialConnections(t *testing.T) {
+	addr, err := net.ResolveUnixAddr("unix", filepath.Join(t.TempDir(), "plugin.sock"))
+	require.NoError(t, err)
+	listener, err := net.ListenUnix("unix", addr)
+	require.NoError(t, err)
+
+	data := &fakeDataServer{}
+	server := grpc.NewServer()
+	pluginv2.RegisterDataServer(server, data)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conns, err := dialConnections(addr, 2)
+	require.NoError(t, err)
+	t.Cleanup(func() { closeConnections(conns) })
+	require.Len(t, conns, 2)
+
+	for _, conn := range conns {
+		_, err := pluginv2.NewDataClient(conn).QueryData(context.Background(), &pluginv2.QueryDataRequest{})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, data.queries)
+}
+
+type fakeDataClient struct {
+	queries int
+}
+
+func (c *fakeDataClient) QueryData(ctx context.Context, req *pluginv2.QueryDataRequest, opts ...grpc.CallOption) (*pluginv2.QueryDataResponse, error) {
+	c.queries++
+	return &pluginv2.QueryDataResponse{}, nil
+}
+
+type fakeDataServer struct {
+	pluginv2.UnimplementedDataServer
+	queries int
+}
+
+func (s *fakeDataServer) QueryData(ctx context.Context, req *pluginv2.QueryDataRequest) (*pluginv2.QueryDataResponse, error) {
+	s.queries++
+	return &pluginv2.QueryDataResponse{}, nil
+}
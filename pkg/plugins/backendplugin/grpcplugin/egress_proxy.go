@@ -0,0 +1,139 @@
+package grpcplugin
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// egressProxy is an HTTP proxy that only forwards the requests of a sandboxed
+// plugin to its allowed hosts. Hosts are either exact host names, or wildcards
+// like *.example.com matching the subdomains of a domain.
+type egressProxy struct {
+	allowedHosts []string
+	listener     net.Listener
+	server       *http.Server
+	transport    *http.Transport
+	logger       log.Logger
+}
+
+func startEgressProxy(allowedHosts []string, logger log.Logger) (*egressProxy, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	p := &egressProxy{
+		allowedHosts: allowedHosts,
+		listener:     listener,
+		transport:    &http.Transport{Proxy: http.ProxyFromEnvironment},
+		logger:       logger,
+	}
+	p.server = &http.Server{Handler: p, ReadHeaderTimeout: 30 * time.Second}
+	go func() {
+		if err := p.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			logger.Error("Plugin sandbox proxy stopped", "error", err)
+		}
+	}()
+
+	return p, nil
+}
+
+func (p *egressProxy) url() string {
+	return "http://" + p.listener.Addr().String()
+}
+
+func (p *egressProxy) close() error {
+	p.transport.CloseIdleConnections()
+	return p.server.Close()
+}
+
+func (p *egressProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if !p.isAllowed(host) {
+		p.logger.Warn("Plugin sandbox blocked request to host that isn't allowed", "host", host)
+		http.Error(w, "host not allowed by the plugin sandbox", http.StatusForbidden)
+		return
+	}
+
+	if r.Method == http.MethodConnect {
+		p.tunnel(w, r)
+		return
+	}
+
+	outReq := r.Clone(r.Context())
+	outReq.RequestURI = ""
+	outReq.Header.Del("Proxy-Connection")
+	outReq.Header.Del("Proxy-Authorization")
+	resp, err := p.transport.RoundTrip(outReq)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			p.logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	for key, values := range resp.Header {
+		for _, v := range values {
+			w.Header().Add(key, v)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = io.Copy(w, resp.Body)
+}
+
+// tunnel connects the plugin to the host of a CONNECT request, which is used
+// for HTTPS requests.
+func (p *egressProxy) tunnel(w http.ResponseWriter, r *http.Request) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "tunneling not supported", http.StatusInternalServerError)
+		return
+	}
+
+	upstream, err := net.DialTimeout("tcp", r.Host, 30*time.Second)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		_ = upstream.Close()
+		return
+	}
+	if _, err := conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n")); err != nil {
+		_ = conn.Close()
+		_ = upstream.Close()
+		return
+	}
+
+	go func() {
+		_, _ = io.Copy(upstream, conn)
+		_ = upstream.Close()
+	}()
+	go func() {
+		_, _ = io.Copy(conn, upstream)
+		_ = conn.Close()
+	}()
+}
+
+func (p *egressProxy) isAllowed(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, allowed := range p.allowedHosts {
+		allowed = strings.ToLower(allowed)
+		if host == allowed || (strings.HasPrefix(allowed, "*.") && strings.HasSuffix(host, allowed[1:])) {
+			return true
+		}
+	}
+	return false
+}
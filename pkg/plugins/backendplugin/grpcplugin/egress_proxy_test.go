@@ -0,0 +1,54 @@
+package grpcplugin
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEgressProxy(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	}))
+	t.Cleanup(upstream.Close)
+
+	proxy, err := startEgressProxy([]string{"127.0.0.1", "*.example.com"}, log.New("test"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, proxy.close())
+	})
+
+	proxyURL, err := url.Parse(proxy.url())
+	require.NoError(t, err)
+	client := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)}}
+
+	t.Run("Should forward requests to allowed hosts", func(t *testing.T) {
+		resp, err := client.Get(upstream.URL)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "hello", string(body))
+	})
+
+	t.Run("Should block requests to other hosts", func(t *testing.T) {
+		resp, err := client.Get("http://grafana.com")
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("Should match wildcard hosts", func(t *testing.T) {
+		require.True(t, proxy.isAllowed("api.example.com"))
+		require.True(t, proxy.isAllowed("API.Example.com."))
+		require.False(t, proxy.isAllowed("example.com"))
+		require.False(t, proxy.isAllowed("api.example.com.evil.org"))
+		require.False(t, proxy.isAllowed("apiexample.com"))
+	})
+}
@@ -3,12 +3,16 @@ package grpcplugin
 import (
 	"context"
 	"errors"
+	"fmt"
+	"path/filepath"
 	"sync"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/plugins/backendplugin"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
 )
 
 type pluginClient interface {
@@ -21,24 +25,38 @@ type pluginClient interface {
 
 type grpcPlugin struct {
 	descriptor     PluginDescriptor
-	clientFactory  func() *plugin.Client
+	clientFactory  func() (*plugin.Client, error)
 	client         *plugin.Client
 	pluginClient   pluginClient
 	logger         log.Logger
 	mutex          sync.RWMutex
 	decommissioned bool
+
+	cgroupPath    string
+	processLimits setting.PluginProcessLimits
+	sandbox       *sandbox
+
+	// conns are the connections to the plugin process opened in addition to
+	// the connection of go-plugin, and limiter limits the requests over them.
+	conns   []*grpc.ClientConn
+	limiter requestLimiter
 }
 
 // newPlugin allocates and returns a new gRPC (external) backendplugin.Plugin.
 func newPlugin(descriptor PluginDescriptor) backendplugin.PluginFactoryFunc {
 	return func(pluginID string, logger log.Logger, env []string) (backendplugin.Plugin, error) {
-		return &grpcPlugin{
+		p := &grpcPlugin{
 			descriptor: descriptor,
 			logger:     logger,
-			clientFactory: func() *plugin.Client {
-				return plugin.NewClient(newClientConfig(descriptor.executablePath, env, logger, descriptor.versionedPlugins))
-			},
-		}, nil
+		}
+		p.clientFactory = func() (*plugin.Client, error) {
+			args, err := processArgs(descriptor.executablePath, env, p.processLimits, p.sandbox)
+			if err != nil {
+				return nil, err
+			}
+			return plugin.NewClient(newClientConfig(args, env, logger, descriptor.versionedPlugins)), nil
+		}
+		return p, nil
 	}
 }
 
@@ -54,12 +72,31 @@ func (p *grpcPlugin) Start(ctx context.Context) error {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
-	p.client = p.clientFactory()
+	if p.processLimits.Sandbox && p.sandbox == nil {
+		sb, err := newSandbox(p.descriptor.pluginID, filepath.Dir(p.descriptor.executablePath),
+			p.processLimits.SandboxAllowedHosts, p.logger)
+		if err != nil {
+			return fmt.Errorf("failed to create plugin sandbox: %w", err)
+		}
+		p.sandbox = sb
+	}
+
+	client, err := p.clientFactory()
+	if err != nil {
+		return err
+	}
+	p.client = client
 	rpcClient, err := p.client.Client()
 	if err != nil {
 		return err
 	}
 
+	if reattach := p.client.ReattachConfig(); reattach != nil {
+		if err := limitProcess(reattach.Pid, p.descriptor.pluginID, p.cgroupPath, p.processLimits); err != nil {
+			p.logger.Error("Failed to limit the resources of the plugin process", "error", err)
+		}
+	}
+
 	if p.client.NegotiatedVersion() < 2 {
 		return errors.New("plugin protocol version not supported")
 	}
@@ -72,6 +109,20 @@ func (p *grpcPlugin) Start(ctx context.Context) error {
 		return errors.New("no compatible plugin implementation found")
 	}
 
+	p.limiter = newRequestLimiter(p.processLimits.MaxConcurrentRequests)
+	closeConnections(p.conns)
+	p.conns = nil
+	if reattach := p.client.ReattachConfig(); reattach != nil && p.processLimits.GRPCConnections > 1 {
+		conns, err := dialConnections(reattach.Addr, p.processLimits.GRPCConnections-1)
+		if err != nil {
+			return fmt.Errorf("failed to connect to the plugin process: %w", err)
+		}
+		p.conns = conns
+		if c, ok := p.pluginClient.(*clientV2); ok {
+			c.spreadOver(conns)
+		}
+	}
+
 	return nil
 }
 
@@ -79,12 +130,43 @@ func (p *grpcPlugin) Stop(ctx context.Context) error {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
+	closeConnections(p.conns)
+	p.conns = nil
 	if p.client != nil {
 		p.client.Kill()
 	}
+	if p.sandbox != nil {
+		if err := p.sandbox.close(); err != nil {
+			p.logger.Warn("Failed to remove plugin sandbox", "error", err)
+		}
+		p.sandbox = nil
+	}
 	return nil
 }
 
+func (p *grpcPlugin) Ping() error {
+	p.mutex.RLock()
+	client := p.client
+	p.mutex.RUnlock()
+
+	if client == nil || client.Exited() {
+		return backendplugin.ErrPluginUnavailable
+	}
+	rpcClient, err := client.Client()
+	if err != nil {
+		return err
+	}
+	return rpcClient.Ping()
+}
+
+func (p *grpcPlugin) SetProcessLimits(cgroupPath string, limits setting.PluginProcessLimits) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.cgroupPath = cgroupPath
+	p.processLimits = limits
+}
+
 func (p *grpcPlugin) IsManaged() bool {
 	return p.descriptor.managed
 }
@@ -111,6 +193,19 @@ func (p *grpcPlugin) IsDecommissioned() bool {
 	return p.decommissioned
 }
 
+// acquireRequest waits until the limits of the plugin allow to send a request,
+// and returns the function that releases the request.
+func (p *grpcPlugin) acquireRequest(ctx context.Context) (func(), error) {
+	p.mutex.RLock()
+	limiter := p.limiter
+	p.mutex.RUnlock()
+
+	if err := limiter.acquire(ctx); err != nil {
+		return nil, err
+	}
+	return limiter.release, nil
+}
+
 func (p *grpcPlugin) getPluginClient() (pluginClient, bool) {
 	p.mutex.RLock()
 	if p.client == nil || p.client.Exited() || p.pluginClient == nil {
@@ -144,6 +239,11 @@ func (p *grpcPlugin) QueryData(ctx context.Context, req *backend.QueryDataReques
 		return nil, backendplugin.ErrPluginUnavailable
 	}
 
+	release, err := p.acquireRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 	return pluginClient.QueryData(ctx, req)
 }
 
@@ -152,6 +252,12 @@ func (p *grpcPlugin) CallResource(ctx context.Context, req *backend.CallResource
 	if !ok {
 		return backendplugin.ErrPluginUnavailable
 	}
+
+	release, err := p.acquireRequest(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
 	return pluginClient.CallResource(ctx, req, sender)
 }
 
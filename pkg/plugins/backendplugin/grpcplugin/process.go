@@ -0,0 +1,89 @@
+package grpcplugin
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// pluginProtocolEnv are the environment variables set by go-plugin for the
+// handshake with the plugin process.
+var pluginProtocolEnv = []string{
+	handshake.MagicCookieKey,
+	"PLUGIN_MIN_PORT",
+	"PLUGIN_MAX_PORT",
+	"PLUGIN_PROTOCOL_VERSIONS",
+	"PLUGIN_CLIENT_CERT",
+}
+
+// processArgs returns the command line of a plugin process, which runs the
+// plugin executable restricted to the environment allowlist of the plugin, and
+// in its sandbox when it's sandboxed.
+func processArgs(executablePath string, env []string, limits setting.PluginProcessLimits, sb *sandbox) ([]string, error) {
+	args := []string{executablePath}
+
+	var err error
+	if len(limits.EnvAllowlist) > 0 {
+		if args, err = withEnvAllowlist(args, env, limits.EnvAllowlist); err != nil {
+			return nil, fmt.Errorf("failed to restrict the environment of the plugin process: %w", err)
+		}
+	}
+	if sb != nil {
+		if args, err = sb.wrap(args); err != nil {
+			return nil, fmt.Errorf("failed to sandbox the plugin process: %w", err)
+		}
+	}
+
+	return args, nil
+}
+
+// withEnvAllowlist returns a command line that runs args without the
+// environment variables of the Grafana server that aren't in the allowlist.
+// Since go-plugin passes the whole environment of the server to the plugin,
+// the variables are unset by running the command through env.
+func withEnvAllowlist(args []string, env []string, allowlist []string) ([]string, error) {
+	if runtime.GOOS == "windows" {
+		return nil, errors.New("environment allowlists aren't supported on Windows")
+	}
+
+	envPath, err := exec.LookPath("env")
+	if err != nil {
+		return nil, err
+	}
+
+	envArgs := []string{envPath}
+	for _, kv := range os.Environ() {
+		key := strings.SplitN(kv, "=", 2)[0]
+		if key == "" || envAllowed(key, allowlist) || envAllowed(key, pluginProtocolEnv) || hasEnv(env, key) {
+			continue
+		}
+		envArgs = append(envArgs, "-u", key)
+	}
+
+	return append(envArgs, args...), nil
+}
+
+// envAllowed returns whether an environment variable is in the allowlist,
+// whose entries can end with * to allow all the variables with a prefix.
+func envAllowed(key string, allowlist []string) bool {
+	for _, allowed := range allowlist {
+		if key == allowed || (strings.HasSuffix(allowed, "*") && strings.HasPrefix(key, strings.TrimSuffix(allowed, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasEnv(env []string, key string) bool {
+	for _, kv := range env {
+		if strings.HasPrefix(kv, key+"=") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+// +build linux
+
+package grpcplugin
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// cpuPeriod is the cgroup CPU period in microseconds, the CPU limit being the
+// share of each period the process can use.
+const cpuPeriod = 100000
+
+// limitProcess sets the nice level of a started plugin process, and moves it
+// to a cgroup v2 limiting its memory and CPU usage. The limits also apply to
+// the children of the process, which run the plugin when it's sandboxed.
+func limitProcess(pid int, pluginID, cgroupPath string, limits setting.PluginProcessLimits) error {
+	pids := processTree(pid)
+
+	if limits.Nice != 0 {
+		for _, pid := range pids {
+			if err := syscall.Setpriority(syscall.PRIO_PROCESS, pid, limits.Nice); err != nil {
+				return fmt.Errorf("failed to set the nice level: %w", err)
+			}
+		}
+	}
+
+	if !limits.HasCgroupLimits() {
+		return nil
+	}
+	if cgroupPath == "" {
+		return fmt.Errorf("memory and CPU limits require the process_cgroup_path setting")
+	}
+
+	dir := filepath.Join(cgroupPath, pluginID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cgroup: %w", err)
+	}
+	// enable the controllers for the cgroups of the plugins, which fails when
+	// they're already enabled, or not delegated to Grafana
+	_ = ioutil.WriteFile(filepath.Join(cgroupPath, "cgroup.subtree_control"), []byte("+cpu +memory"), 0)
+
+	memoryMax := "max"
+	if limits.MemoryLimitMB > 0 {
+		memoryMax = strconv.FormatInt(limits.MemoryLimitMB*1024*1024, 10)
+	}
+	cpuMax := fmt.Sprintf("max %d", cpuPeriod)
+	if limits.CPULimit > 0 {
+		cpuMax = fmt.Sprintf("%d %d", int64(limits.CPULimit*cpuPeriod), cpuPeriod)
+	}
+
+	for file, value := range map[string]string{
+		"memory.max": memoryMax,
+		"cpu.max":    cpuMax,
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, file), []byte(value), 0); err != nil {
+			return fmt.Errorf("failed to write %s of cgroup %s: %w", file, dir, err)
+		}
+	}
+	for _, pid := range pids {
+		if err := ioutil.WriteFile(filepath.Join(dir, "cgroup.procs"), []byte(strconv.Itoa(pid)), 0); err != nil {
+			return fmt.Errorf("failed to move process %d to cgroup %s: %w", pid, dir, err)
+		}
+	}
+
+	return nil
+}
+
+// processTree returns a process and its descendants.
+func processTree(pid int) []int {
+	pids := []int{pid}
+	children, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/task/%d/children", pid, pid))
+	if err != nil {
+		return pids
+	}
+	for _, child := range strings.Fields(string(children)) {
+		if childPID, err := strconv.Atoi(child); err == nil {
+			pids = append(pids, processTree(childPID)...)
+		}
+	}
+	return pids
+}
@@ -0,0 +1,19 @@
+// +build !linux
+
+package grpcplugin
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// limitProcess only supports Linux, where the nice level is set and the
+// memory and CPU usage is limited with cgroups.
+func limitProcess(pid int, pluginID, cgroupPath string, limits setting.PluginProcessLimits) error {
+	if limits.Nice != 0 || limits.HasCgroupLimits() {
+		return fmt.Errorf("plugin process limits aren't supported on %s", runtime.GOOS)
+	}
+	return nil
+}
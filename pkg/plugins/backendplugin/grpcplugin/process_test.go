@@ -0,0 +1,40 @@
+// +build !windows
+
+package grpcplugin
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithEnvAllowlist(t *testing.T) {
+	for k, v := range map[string]string{
+		"GF_TEST_ALLOWED":        "1",
+		"GF_TEST_PREFIX_ALLOWED": "1",
+		"GF_TEST_SECRET":         "1",
+		"GF_TEST_PLUGIN_SETTING": "1",
+	} {
+		require.NoError(t, os.Setenv(k, v))
+		k := k
+		t.Cleanup(func() {
+			_ = os.Unsetenv(k)
+		})
+	}
+
+	args, err := withEnvAllowlist([]string{"/plugins/test/gpx_test"}, []string{"GF_TEST_PLUGIN_SETTING=2"},
+		[]string{"GF_TEST_ALLOWED", "GF_TEST_PREFIX_*"})
+	require.NoError(t, err)
+	require.Equal(t, "/plugins/test/gpx_test", args[len(args)-1])
+
+	unset := map[string]bool{}
+	for i := 1; i < len(args)-1; i += 2 {
+		require.Equal(t, "-u", args[i])
+		unset[args[i+1]] = true
+	}
+	require.True(t, unset["GF_TEST_SECRET"])
+	require.False(t, unset["GF_TEST_ALLOWED"])
+	require.False(t, unset["GF_TEST_PREFIX_ALLOWED"])
+	require.False(t, unset["GF_TEST_PLUGIN_SETTING"])
+}
@@ -0,0 +1,50 @@
+package grpcplugin
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// sandbox restricts a plugin process to its plugin directory, and to the
+// network hosts it's allowed to connect to.
+type sandbox struct {
+	pluginDir string
+	// tmpDir is the only writable directory of the process, where go-plugin
+	// creates the socket that Grafana connects to.
+	tmpDir string
+	// proxy forwards the requests of the process to its allowed hosts, the
+	// process having no network access when it's nil.
+	proxy *egressProxy
+}
+
+func newSandbox(pluginID, pluginDir string, allowedHosts []string, logger log.Logger) (*sandbox, error) {
+	tmpDir, err := ioutil.TempDir("", "grafana-plugin-"+pluginID)
+	if err != nil {
+		return nil, err
+	}
+
+	sb := &sandbox{
+		pluginDir: pluginDir,
+		tmpDir:    tmpDir,
+	}
+	if len(allowedHosts) > 0 {
+		if sb.proxy, err = startEgressProxy(allowedHosts, logger); err != nil {
+			_ = os.RemoveAll(tmpDir)
+			return nil, err
+		}
+	}
+
+	return sb, nil
+}
+
+// close stops the proxy of the sandbox and removes its temporary directory.
+func (sb *sandbox) close() error {
+	if sb.proxy != nil {
+		if err := sb.proxy.close(); err != nil {
+			return err
+		}
+	}
+	return os.RemoveAll(sb.tmpDir)
+}
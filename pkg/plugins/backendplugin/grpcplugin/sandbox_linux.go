@@ -0,0 +1,60 @@
+// +build linux
+
+package grpcplugin
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// sandboxSystemPaths are the paths of the host mounted read-only in sandboxes,
+// for the dynamic libraries, certificates and name resolution of plugins.
+var sandboxSystemPaths = []string{
+	"/usr",
+	"/lib",
+	"/lib64",
+	"/bin",
+	"/sbin",
+	"/etc/ssl",
+	"/etc/pki",
+	"/etc/ca-certificates",
+	"/etc/resolv.conf",
+	"/etc/hosts",
+	"/etc/nsswitch.conf",
+	"/etc/localtime",
+}
+
+// wrap returns a command line that runs args in the sandbox with bubblewrap,
+// in new namespaces where the plugin directory is the only directory of the
+// host besides the system paths, and read-only.
+func (sb *sandbox) wrap(args []string) ([]string, error) {
+	bwrap, err := exec.LookPath("bwrap")
+	if err != nil {
+		return nil, fmt.Errorf("the plugin sandbox requires bubblewrap: %w", err)
+	}
+
+	sbArgs := []string{bwrap, "--die-with-parent", "--new-session", "--unshare-all"}
+	if sb.proxy != nil {
+		sbArgs = append(sbArgs, "--share-net")
+	}
+	for _, path := range sandboxSystemPaths {
+		sbArgs = append(sbArgs, "--ro-bind-try", path, path)
+	}
+	sbArgs = append(sbArgs,
+		"--proc", "/proc",
+		"--dev", "/dev",
+		"--tmpfs", "/tmp",
+		"--ro-bind", sb.pluginDir, sb.pluginDir,
+		"--bind", sb.tmpDir, sb.tmpDir,
+		"--setenv", "TMPDIR", sb.tmpDir,
+		"--chdir", sb.pluginDir,
+	)
+	if sb.proxy != nil {
+		for _, key := range []string{"HTTP_PROXY", "HTTPS_PROXY", "http_proxy", "https_proxy"} {
+			sbArgs = append(sbArgs, "--setenv", key, sb.proxy.url())
+		}
+		sbArgs = append(sbArgs, "--unsetenv", "NO_PROXY", "--unsetenv", "no_proxy")
+	}
+
+	return append(append(sbArgs, "--"), args...), nil
+}
@@ -0,0 +1,13 @@
+// +build !linux
+
+package grpcplugin
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// wrap only supports Linux, where the sandbox is created with bubblewrap.
+func (sb *sandbox) wrap(args []string) ([]string, error) {
+	return nil, fmt.Errorf("the plugin sandbox isn't supported on %s", runtime.GOOS)
+}
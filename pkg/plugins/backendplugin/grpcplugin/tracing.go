@@ -0,0 +1,27 @@
+package grpcplugin
+
+import (
+	"context"
+
+	grpc_opentracing "github.com/grpc-ecosystem/go-grpc-middleware/tracing/opentracing"
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc"
+)
+
+// tracingDialOptions returns the gRPC dial options that start a span for each
+// call to a plugin, and pass its context to the plugin in the gRPC metadata, so
+// that the spans of the plugin join the trace of the request. Calls outside of a
+// trace, such as health checks, aren't traced.
+func tracingDialOptions() []grpc.DialOption {
+	opts := []grpc_opentracing.Option{
+		grpc_opentracing.WithTracer(opentracing.GlobalTracer()),
+		grpc_opentracing.WithFilterFunc(func(ctx context.Context, fullMethodName string) bool {
+			return opentracing.SpanFromContext(ctx) != nil
+		}),
+	}
+
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(grpc_opentracing.UnaryClientInterceptor(opts...)),
+		grpc.WithChainStreamInterceptor(grpc_opentracing.StreamClientInterceptor(opts...)),
+	}
+}
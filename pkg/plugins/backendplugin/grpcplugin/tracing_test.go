@@ -0,0 +1,77 @@
+package grpcplugin
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type metadataHealthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+	md metadata.MD
+}
+
+func (s *metadataHealthServer) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	s.md, _ = metadata.FromIncomingContext(ctx)
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
+
+func TestTracingDialOptions(t *testing.T) {
+	tracer := mocktracer.New()
+	globalTracer := opentracing.GlobalTracer()
+	opentracing.SetGlobalTracer(tracer)
+	t.Cleanup(func() {
+		opentracing.SetGlobalTracer(globalTracer)
+	})
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	healthServer := &metadataHealthServer{}
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	dialOpts := append(tracingDialOptions(), grpc.WithInsecure(), grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+		return listener.Dial()
+	}))
+	conn, err := grpc.Dial("bufnet", dialOpts...)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, conn.Close())
+	})
+	client := grpc_health_v1.NewHealthClient(conn)
+
+	t.Run("Should pass the trace context of a traced call to the plugin", func(t *testing.T) {
+		span, ctx := opentracing.StartSpanFromContext(context.Background(), "request")
+		_, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+		require.NoError(t, err)
+		span.Finish()
+
+		spans := tracer.FinishedSpans()
+		require.Len(t, spans, 2)
+		clientSpan := spans[0]
+		require.Equal(t, "/grpc.health.v1.Health/Check", clientSpan.OperationName)
+		require.Equal(t, span.Context().(mocktracer.MockSpanContext).SpanID, clientSpan.ParentID)
+
+		spanCtx, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(healthServer.md))
+		require.NoError(t, err)
+		require.Equal(t, clientSpan.SpanContext.SpanID, spanCtx.(mocktracer.MockSpanContext).SpanID)
+	})
+
+	t.Run("Should not trace calls outside of a trace", func(t *testing.T) {
+		tracer.Reset()
+		_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+		require.NoError(t, err)
+		require.Empty(t, tracer.FinishedSpans())
+	})
+}
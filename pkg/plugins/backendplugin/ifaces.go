@@ -6,12 +6,16 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 // Manager manages backend plugins.
 type Manager interface {
 	//Register registers a backend plugin
 	Register(pluginID string, factory PluginFactoryFunc) error
+	// RegisterInstance registers an additional instance of a backend plugin,
+	// which runs with the settings of the plugin.
+	RegisterInstance(pluginID, instanceID string, factory PluginFactoryFunc) error
 	// RegisterAndStart registers and starts a backend plugin
 	RegisterAndStart(ctx context.Context, pluginID string, factory PluginFactoryFunc) error
 	// UnregisterAndStop unregisters and stops a backend plugin
@@ -26,10 +30,21 @@ type Manager interface {
 	CheckHealth(ctx context.Context, pCtx backend.PluginContext) (*backend.CheckHealthResult, error)
 	// QueryData query data from a registered backend plugin.
 	QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error)
+	// SubscribeStream, PublishStream and RunStream call the streaming
+	// handlers of a registered backend plugin.
+	backend.StreamHandler
+	// ValidateSettings validates the settings of a data source or app of a
+	// registered backend plugin before they are saved.
+	ValidateSettings(ctx context.Context, pCtx backend.PluginContext) (*ValidateSettingsResult, error)
 	// CallResource calls a plugin resource.
 	CallResource(pluginConfig backend.PluginContext, ctx *models.ReqContext, path string)
 	// Get plugin by its ID.
 	Get(pluginID string) (Plugin, bool)
+	// Status returns the status of the process of a started backend plugin.
+	Status(pluginID string) (PluginStatus, bool)
+	// SetLogLevel sets the log level of a registered backend plugin at runtime.
+	// An empty level restores the configured log level.
+	SetLogLevel(pluginID, level string) error
 }
 
 // Plugin is the backend plugin interface.
@@ -48,3 +63,17 @@ type Plugin interface {
 	backend.CallResourceHandler
 	backend.StreamHandler
 }
+
+// Pinger is implemented by the backend plugins running in a separate process,
+// whose connection can be checked without calling the plugin.
+type Pinger interface {
+	Ping() error
+}
+
+// ProcessLimiter is implemented by the backend plugins running in a separate
+// process, whose resources can be limited.
+type ProcessLimiter interface {
+	// SetProcessLimits sets the limits applied when the process is started,
+	// with cgroupPath being the parent of the cgroups of the plugins.
+	SetProcessLimits(cgroupPath string, limits setting.PluginProcessLimits)
+}
@@ -12,6 +12,8 @@ import (
 var (
 	pluginRequestCounter  *prometheus.CounterVec
 	pluginRequestDuration *prometheus.SummaryVec
+	pluginRestartCounter  *prometheus.CounterVec
+	pluginHealthyGauge    *prometheus.GaugeVec
 )
 
 func init() {
@@ -28,7 +30,42 @@ func init() {
 		Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
 	}, []string{"plugin_id", "endpoint"})
 
-	prometheus.MustRegister(pluginRequestCounter, pluginRequestDuration)
+	pluginRestartCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "grafana",
+		Name:      "plugin_process_restarts_total",
+		Help:      "The total amount of backend plugin process restarts",
+	}, []string{"plugin_id", "status"})
+
+	pluginHealthyGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "grafana",
+		Name:      "plugin_process_healthy",
+		Help:      "Whether the backend plugin process is running and healthy",
+	}, []string{"plugin_id"})
+
+	prometheus.MustRegister(pluginRequestCounter, pluginRequestDuration, pluginRestartCounter, pluginHealthyGauge)
+}
+
+// InstrumentPluginRestart counts a restart of the process of a backend plugin.
+func InstrumentPluginRestart(pluginID string, err error) {
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	pluginRestartCounter.WithLabelValues(pluginID, status).Inc()
+}
+
+// SetPluginHealthy sets whether the process of a backend plugin is healthy.
+func SetPluginHealthy(pluginID string, healthy bool) {
+	value := 0.0
+	if healthy {
+		value = 1
+	}
+	pluginHealthyGauge.WithLabelValues(pluginID).Set(value)
+}
+
+// DeletePluginHealthy removes the health of an unregistered backend plugin.
+func DeletePluginHealthy(pluginID string) {
+	pluginHealthyGauge.DeleteLabelValues(pluginID)
 }
 
 // instrumentPluginRequest instruments success rate and latency of `fn`
@@ -64,11 +101,32 @@ func InstrumentCallResourceRequest(pluginID string, fn func() error) error {
 	return instrumentPluginRequest(pluginID, "callResource", fn)
 }
 
+// InstrumentValidateSettingsRequest instruments validateSettings.
+func InstrumentValidateSettingsRequest(pluginID string, fn func() error) error {
+	return instrumentPluginRequest(pluginID, "validateSettings", fn)
+}
+
 // InstrumentQueryDataRequest instruments success rate and latency of query data requests.
 func InstrumentQueryDataRequest(pluginID string, fn func() error) error {
 	return instrumentPluginRequest(pluginID, "queryData", fn)
 }
 
+// InstrumentSubscribeStreamRequest instruments subscribeStream.
+func InstrumentSubscribeStreamRequest(pluginID string, fn func() error) error {
+	return instrumentPluginRequest(pluginID, "subscribeStream", fn)
+}
+
+// InstrumentPublishStreamRequest instruments publishStream.
+func InstrumentPublishStreamRequest(pluginID string, fn func() error) error {
+	return instrumentPluginRequest(pluginID, "publishStream", fn)
+}
+
+// InstrumentRunStreamRequest instruments runStream, whose duration is the
+// time the stream ran for.
+func InstrumentRunStreamRequest(pluginID string, fn func() error) error {
+	return instrumentPluginRequest(pluginID, "runStream", fn)
+}
+
 // InstrumentQueryDataHandler wraps a backend.QueryDataHandler with instrumentation of success rate and latency.
 func InstrumentQueryDataHandler(handler backend.QueryDataHandler) backend.QueryDataHandler {
 	if handler == nil {
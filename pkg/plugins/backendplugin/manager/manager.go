@@ -23,6 +23,7 @@ import (
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util/errutil"
 	"github.com/grafana/grafana/pkg/util/proxyutil"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 func init() {
@@ -38,14 +39,36 @@ type manager struct {
 	PluginRequestValidator models.PluginRequestValidator `inject:""`
 	pluginsMu              sync.RWMutex
 	plugins                map[string]backendplugin.Plugin
+	statusMu               sync.RWMutex
+	statuses               map[string]backendplugin.PluginStatus
+	metricsCollector       *pluginMetricsCollector
 	logger                 log.Logger
 }
 
+const (
+	// maxRestartBackoff is the longest delay between the restarts of a plugin
+	// process that keeps exiting or failing health checks.
+	maxRestartBackoff = 5 * time.Minute
+	// stableProcessDuration is how long a plugin process must run before an
+	// exit restarts it without delay.
+	stableProcessDuration = time.Minute
+)
+
 func (m *manager) Init() error {
+	m.metricsCollector = newPluginMetricsCollector(m.logger)
+	if m.Cfg.PluginsMetricsScrapeInterval > 0 {
+		if err := prometheus.Register(m.metricsCollector); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 func (m *manager) Run(ctx context.Context) error {
+	if m.Cfg.PluginsMetricsScrapeInterval > 0 {
+		go m.scrapePluginMetrics(ctx)
+	}
+
 	<-ctx.Done()
 	m.stop(ctx)
 	return ctx.Err()
@@ -53,12 +76,23 @@ func (m *manager) Run(ctx context.Context) error {
 
 // Register registers a backend plugin
 func (m *manager) Register(pluginID string, factory backendplugin.PluginFactoryFunc) error {
-	m.logger.Debug("Registering backend plugin", "pluginId", pluginID)
+	return m.register(pluginID, pluginID, factory)
+}
+
+// RegisterInstance registers an additional instance of a backend plugin
+func (m *manager) RegisterInstance(pluginID, instanceID string, factory backendplugin.PluginFactoryFunc) error {
+	return m.register(pluginID, instanceID, factory)
+}
+
+// register registers a backend plugin as instanceID, with the settings of
+// the plugin.
+func (m *manager) register(pluginID, instanceID string, factory backendplugin.PluginFactoryFunc) error {
+	m.logger.Debug("Registering backend plugin", "pluginId", instanceID)
 	m.pluginsMu.Lock()
 	defer m.pluginsMu.Unlock()
 
-	if _, exists := m.plugins[pluginID]; exists {
-		return fmt.Errorf("backend plugin %s already registered", pluginID)
+	if _, exists := m.plugins[instanceID]; exists {
+		return fmt.Errorf("backend plugin %s already registered", instanceID)
 	}
 
 	hostEnv := []string{
@@ -85,14 +119,22 @@ func (m *manager) Register(pluginID string, factory backendplugin.PluginFactoryF
 	pluginSettings := getPluginSettings(pluginID, m.Cfg)
 	env := pluginSettings.ToEnv("GF_PLUGIN", hostEnv)
 
-	pluginLogger := m.logger.New("pluginId", pluginID)
-	plugin, err := factory(pluginID, pluginLogger, env)
+	pluginLogger := m.logger.New("pluginId", instanceID)
+	plugin, err := factory(instanceID, pluginLogger, env)
 	if err != nil {
 		return err
 	}
 
-	m.plugins[pluginID] = plugin
-	m.logger.Debug("Backend plugin registered", "pluginId", pluginID)
+	if limiter, ok := plugin.(backendplugin.ProcessLimiter); ok {
+		limits, exists := m.Cfg.PluginProcessLimits[pluginID]
+		if !exists {
+			limits = m.Cfg.PluginProcessDefaultLimits
+		}
+		limiter.SetProcessLimits(m.Cfg.PluginProcessCgroupPath, limits)
+	}
+
+	m.plugins[instanceID] = plugin
+	m.logger.Debug("Backend plugin registered", "pluginId", instanceID)
 	return nil
 }
 
@@ -134,6 +176,10 @@ func (m *manager) UnregisterAndStop(ctx context.Context, pluginID string) error
 	}
 
 	delete(m.plugins, pluginID)
+	m.deleteStatus(pluginID)
+	if err := log.SetContextLevel("pluginId", pluginID, ""); err != nil {
+		m.logger.Warn("Failed to reset plugin log level", "pluginId", pluginID, "err", err)
+	}
 
 	m.logger.Debug("Backend plugin unregistered", "pluginId", pluginID)
 	return nil
@@ -190,7 +236,7 @@ func (m *manager) start(ctx context.Context, p backendplugin.Plugin) {
 		return
 	}
 
-	if err := startPluginAndRestartKilledProcesses(ctx, p); err != nil {
+	if err := m.startPluginAndRestartKilledProcesses(ctx, p); err != nil {
 		p.Logger().Error("Failed to start plugin", "error", err)
 	}
 }
@@ -208,7 +254,65 @@ func (m *manager) StartPlugin(ctx context.Context, pluginID string) error {
 		return errors.New("backend plugin is managed and cannot be manually started")
 	}
 
-	return startPluginAndRestartKilledProcesses(ctx, p)
+	return m.startPluginAndRestartKilledProcesses(ctx, p)
+}
+
+// Status returns the status of the process of a started backend plugin.
+func (m *manager) Status(pluginID string) (backendplugin.PluginStatus, bool) {
+	m.statusMu.RLock()
+	defer m.statusMu.RUnlock()
+
+	status, ok := m.statuses[pluginID]
+	return status, ok
+}
+
+// SetLogLevel sets the log level of a registered backend plugin at runtime. The
+// plugin process sends its logs to Grafana regardless of the log level of Grafana,
+// so the level takes effect without restarting the plugin.
+func (m *manager) SetLogLevel(pluginID, level string) error {
+	if !m.IsRegistered(pluginID) {
+		return backendplugin.ErrPluginNotRegistered
+	}
+
+	if err := log.SetContextLevel("pluginId", pluginID, level); err != nil {
+		return err
+	}
+	if level == "" {
+		m.logger.Info("Backend plugin log level reset", "pluginId", pluginID)
+	} else {
+		m.logger.Info("Backend plugin log level changed", "pluginId", pluginID, "level", level)
+	}
+	return nil
+}
+
+// setStatus sets the state of a plugin process, and counts the restart when
+// the state follows a restart.
+func (m *manager) setStatus(pluginID string, state backendplugin.PluginState, err error, restarted bool) {
+	m.statusMu.Lock()
+	defer m.statusMu.Unlock()
+
+	if m.statuses == nil {
+		m.statuses = map[string]backendplugin.PluginStatus{}
+	}
+	status := m.statuses[pluginID]
+	status.State = state
+	if err != nil {
+		status.LastError = err.Error()
+	}
+	if restarted {
+		status.Restarts++
+	}
+	m.statuses[pluginID] = status
+
+	instrumentation.SetPluginHealthy(pluginID, state == backendplugin.PluginStateRunning)
+}
+
+func (m *manager) deleteStatus(pluginID string) {
+	m.statusMu.Lock()
+	defer m.statusMu.Unlock()
+
+	delete(m.statuses, pluginID)
+	instrumentation.DeletePluginHealthy(pluginID)
 }
 
 // stop stops all managed backend plugins
@@ -290,6 +394,63 @@ func (m *manager) CheckHealth(ctx context.Context, pluginContext backend.PluginC
 	return resp, nil
 }
 
+// ValidateSettings calls the settings validation resource of a backend plugin,
+// and returns backendplugin.ErrMethodNotImplemented if the plugin doesn't
+// have the resource.
+func (m *manager) ValidateSettings(ctx context.Context, pCtx backend.PluginContext) (*backendplugin.ValidateSettingsResult, error) {
+	p, registered := m.Get(pCtx.PluginID)
+	if !registered {
+		return nil, backendplugin.ErrPluginNotRegistered
+	}
+
+	req := &backend.CallResourceRequest{
+		PluginContext: pCtx,
+		Path:          backendplugin.ValidateSettingsPath,
+		Method:        http.MethodPost,
+		URL:           backendplugin.ValidateSettingsPath,
+		Headers:       map[string][]string{"Content-Type": {"application/json"}},
+	}
+
+	var resp *backend.CallResourceResponse
+	sender := callResourceResponseSenderFunc(func(res *backend.CallResourceResponse) error {
+		if resp == nil {
+			resp = res
+		} else {
+			// the body of streamed responses is sent in several chunks
+			resp.Body = append(resp.Body, res.Body...)
+		}
+		return nil
+	})
+	err := instrumentation.InstrumentValidateSettingsRequest(p.PluginID(), func() error {
+		return p.CallResource(ctx, req, sender)
+	})
+	if err != nil {
+		if errors.Is(err, backendplugin.ErrMethodNotImplemented) || errors.Is(err, backendplugin.ErrPluginUnavailable) {
+			return nil, err
+		}
+		return nil, errutil.Wrap("failed to validate plugin settings", err)
+	}
+
+	if resp == nil || resp.Status == http.StatusNotFound {
+		return nil, backendplugin.ErrMethodNotImplemented
+	}
+	if resp.Status != http.StatusOK {
+		return nil, fmt.Errorf("failed to validate plugin settings: plugin returned status %d", resp.Status)
+	}
+
+	var result backendplugin.ValidateSettingsResult
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		return nil, errutil.Wrap("failed to parse the settings validation result of the plugin", err)
+	}
+	return &result, nil
+}
+
+type callResourceResponseSenderFunc func(res *backend.CallResourceResponse) error
+
+func (fn callResourceResponseSenderFunc) Send(res *backend.CallResourceResponse) error {
+	return fn(res)
+}
+
 func (m *manager) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
 	p, registered := m.Get(req.PluginContext.PluginID)
 	if !registered {
@@ -317,6 +478,59 @@ func (m *manager) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 	return resp, nil
 }
 
+// SubscribeStream asks a registered backend plugin whether a stream can be subscribed to.
+func (m *manager) SubscribeStream(ctx context.Context, req *backend.SubscribeStreamRequest) (*backend.SubscribeStreamResponse, error) {
+	p, registered := m.Get(req.PluginContext.PluginID)
+	if !registered {
+		return nil, backendplugin.ErrPluginNotRegistered
+	}
+
+	var resp *backend.SubscribeStreamResponse
+	err := instrumentation.InstrumentSubscribeStreamRequest(p.PluginID(), func() (innerErr error) {
+		resp, innerErr = p.SubscribeStream(ctx, req)
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// PublishStream asks a registered backend plugin whether data can be published to a stream.
+func (m *manager) PublishStream(ctx context.Context, req *backend.PublishStreamRequest) (*backend.PublishStreamResponse, error) {
+	p, registered := m.Get(req.PluginContext.PluginID)
+	if !registered {
+		return nil, backendplugin.ErrPluginNotRegistered
+	}
+
+	var resp *backend.PublishStreamResponse
+	err := instrumentation.InstrumentPublishStreamRequest(p.PluginID(), func() (innerErr error) {
+		resp, innerErr = p.PublishStream(ctx, req)
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// RunStream runs a stream of a registered backend plugin, which pushes frames
+// through sender until ctx is canceled or the plugin ends the stream. The plugin
+// is looked up on each call, so that a stream which is re-established after the
+// plugin was restarted or reloaded runs in the current plugin process.
+func (m *manager) RunStream(ctx context.Context, req *backend.RunStreamRequest, sender *backend.StreamSender) error {
+	p, registered := m.Get(req.PluginContext.PluginID)
+	if !registered {
+		return backendplugin.ErrPluginNotRegistered
+	}
+
+	return instrumentation.InstrumentRunStreamRequest(p.PluginID(), func() error {
+		return p.RunStream(ctx, req, sender)
+	})
+}
+
 type keepCookiesJSONModel struct {
 	KeepCookies []string `json:"keepCookies"`
 }
@@ -480,13 +694,17 @@ func flushStream(plugin backendplugin.Plugin, stream callResourceClientResponseS
 	}
 }
 
-func startPluginAndRestartKilledProcesses(ctx context.Context, p backendplugin.Plugin) error {
+// startPluginAndRestartKilledProcesses starts a plugin, and monitors its
+// process to restart it when it exits or fails health checks.
+func (m *manager) startPluginAndRestartKilledProcesses(ctx context.Context, p backendplugin.Plugin) error {
 	if err := p.Start(ctx); err != nil {
+		m.setStatus(p.PluginID(), backendplugin.PluginStateFailed, err, false)
 		return err
 	}
+	m.setStatus(p.PluginID(), backendplugin.PluginStateRunning, nil, false)
 
 	go func(ctx context.Context, p backendplugin.Plugin) {
-		if err := restartKilledProcess(ctx, p); err != nil {
+		if err := m.restartKilledProcess(ctx, p); err != nil {
 			p.Logger().Error("Attempt to restart killed plugin process failed", "error", err)
 		}
 	}(ctx, p)
@@ -494,8 +712,13 @@ func startPluginAndRestartKilledProcesses(ctx context.Context, p backendplugin.P
 	return nil
 }
 
-func restartKilledProcess(ctx context.Context, p backendplugin.Plugin) error {
+func (m *manager) restartKilledProcess(ctx context.Context, p backendplugin.Plugin) error {
 	ticker := time.NewTicker(time.Second * 1)
+	defer ticker.Stop()
+
+	started := time.Now()
+	var backoff time.Duration
+	var nextRestart, nextHealthCheck time.Time
 
 	for {
 		select {
@@ -504,26 +727,95 @@ func restartKilledProcess(ctx context.Context, p backendplugin.Plugin) error {
 				return err
 			}
 			return nil
-		case <-ticker.C:
+		case now := <-ticker.C:
 			if p.IsDecommissioned() {
 				p.Logger().Debug("Plugin decommissioned")
 				return nil
 			}
 
-			if !p.Exited() {
+			if nextRestart.IsZero() {
+				if !p.Exited() {
+					if m.Cfg.PluginsHealthCheckInterval <= 0 || now.Before(nextHealthCheck) {
+						continue
+					}
+					nextHealthCheck = now.Add(m.Cfg.PluginsHealthCheckInterval)
+
+					err := m.pingPlugin(p)
+					if err == nil {
+						continue
+					}
+					p.Logger().Warn("Plugin failed health check, restarting", "error", err)
+					m.setStatus(p.PluginID(), backendplugin.PluginStateUnhealthy, err, false)
+					if err := p.Stop(ctx); err != nil {
+						p.Logger().Error("Failed to stop unhealthy plugin", "error", err)
+					}
+				} else {
+					p.Logger().Debug("Plugin process exited")
+					m.setStatus(p.PluginID(), backendplugin.PluginStateExited, nil, false)
+				}
+
+				if now.Sub(started) >= stableProcessDuration {
+					backoff = 0
+				}
+				nextRestart = now.Add(backoff)
+			}
+
+			if now.Before(nextRestart) {
 				continue
 			}
 
 			p.Logger().Debug("Restarting plugin")
-			if err := p.Start(ctx); err != nil {
-				p.Logger().Error("Failed to restart plugin", "error", err)
+			err := p.Start(ctx)
+			instrumentation.InstrumentPluginRestart(p.PluginID(), err)
+			backoff = nextRestartBackoff(backoff)
+			if err != nil {
+				p.Logger().Error("Failed to restart plugin", "error", err, "retryIn", backoff)
+				m.setStatus(p.PluginID(), backendplugin.PluginStateFailed, err, true)
+				nextRestart = now.Add(backoff)
 				continue
 			}
+			m.setStatus(p.PluginID(), backendplugin.PluginStateRunning, nil, true)
+			started = now
+			nextRestart = time.Time{}
+			nextHealthCheck = now.Add(m.Cfg.PluginsHealthCheckInterval)
 			p.Logger().Debug("Plugin restarted")
 		}
 	}
 }
 
+// pingPlugin checks that the process of a plugin responds within the health
+// check timeout. Plugins that don't run in a separate process aren't checked.
+func (m *manager) pingPlugin(p backendplugin.Plugin) error {
+	pinger, ok := p.(backendplugin.Pinger)
+	if !ok {
+		return nil
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- pinger.Ping()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-time.After(m.Cfg.PluginsHealthCheckTimeout):
+		return fmt.Errorf("plugin didn't respond within %s", m.Cfg.PluginsHealthCheckTimeout)
+	}
+}
+
+// nextRestartBackoff doubles the delay before restarting a plugin process,
+// up to maxRestartBackoff.
+func nextRestartBackoff(backoff time.Duration) time.Duration {
+	if backoff == 0 {
+		return time.Second
+	}
+	if backoff *= 2; backoff > maxRestartBackoff {
+		return maxRestartBackoff
+	}
+	return backoff
+}
+
 // callResourceClientResponseStream is used for receiving resource call responses.
 type callResourceClientResponseStream interface {
 	Recv() (*backend.CallResourceResponse, error)
@@ -3,6 +3,7 @@ package manager
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -38,6 +39,12 @@ func TestManager(t *testing.T) {
 			w := httptest.NewRecorder()
 			err = ctx.manager.callResourceInternal(w, req, backend.PluginContext{PluginID: testPluginID})
 			require.Equal(t, backendplugin.ErrPluginNotRegistered, err)
+
+			_, err = ctx.manager.SubscribeStream(context.Background(), &backend.SubscribeStreamRequest{PluginContext: backend.PluginContext{PluginID: testPluginID}})
+			require.Equal(t, backendplugin.ErrPluginNotRegistered, err)
+
+			err = ctx.manager.RunStream(context.Background(), &backend.RunStreamRequest{PluginContext: backend.PluginContext{PluginID: testPluginID}}, nil)
+			require.Equal(t, backendplugin.ErrPluginNotRegistered, err)
 		})
 	})
 
@@ -150,6 +157,16 @@ func TestManager(t *testing.T) {
 						err = ctx.manager.callResourceInternal(w, req, backend.PluginContext{PluginID: testPluginID})
 						require.Equal(t, backendplugin.ErrMethodNotImplemented, err)
 					})
+
+					t.Run("Validate settings should return method not implemented error", func(t *testing.T) {
+						_, err := ctx.manager.ValidateSettings(context.Background(), backend.PluginContext{PluginID: testPluginID})
+						require.Equal(t, backendplugin.ErrMethodNotImplemented, err)
+					})
+
+					t.Run("Run stream should return method not implemented error", func(t *testing.T) {
+						err := ctx.manager.RunStream(context.Background(), &backend.RunStreamRequest{PluginContext: backend.PluginContext{PluginID: testPluginID}}, nil)
+						require.Equal(t, backendplugin.ErrMethodNotImplemented, err)
+					})
 				})
 
 				t.Run("Implemented handlers", func(t *testing.T) {
@@ -201,6 +218,78 @@ func TestManager(t *testing.T) {
 						require.NoError(t, err)
 						require.Equal(t, http.StatusOK, w.Code)
 					})
+
+					t.Run("Validate settings should return the result of the plugin", func(t *testing.T) {
+						ctx.plugin.CallResourceHandlerFunc = func(ctx context.Context,
+							req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+							if req.Path != backendplugin.ValidateSettingsPath || req.Method != http.MethodPost {
+								return sender.Send(&backend.CallResourceResponse{Status: http.StatusNotFound})
+							}
+							if req.PluginContext.DataSourceInstanceSettings.URL != "" {
+								return sender.Send(&backend.CallResourceResponse{Status: http.StatusOK, Body: []byte(`{"valid": true}`)})
+							}
+							if err := sender.Send(&backend.CallResourceResponse{Status: http.StatusOK, Body: []byte(`{"valid": false, `)}); err != nil {
+								return err
+							}
+							return sender.Send(&backend.CallResourceResponse{Body: []byte(`"message": "URL is missing", "fields": {"url": "required"}}`)})
+						}
+
+						res, err := ctx.manager.ValidateSettings(context.Background(), backend.PluginContext{
+							PluginID:                   testPluginID,
+							DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{},
+						})
+						require.NoError(t, err)
+						require.Equal(t, &backendplugin.ValidateSettingsResult{
+							Valid:   false,
+							Message: "URL is missing",
+							Fields:  map[string]string{"url": "required"},
+						}, res)
+
+						res, err = ctx.manager.ValidateSettings(context.Background(), backend.PluginContext{
+							PluginID:                   testPluginID,
+							DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{URL: "http://localhost"},
+						})
+						require.NoError(t, err)
+						require.True(t, res.Valid)
+					})
+
+					t.Run("Validate settings should return method not implemented error without the resource", func(t *testing.T) {
+						ctx.plugin.CallResourceHandlerFunc = func(ctx context.Context,
+							req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+							return sender.Send(&backend.CallResourceResponse{Status: http.StatusNotFound})
+						}
+
+						_, err := ctx.manager.ValidateSettings(context.Background(), backend.PluginContext{PluginID: testPluginID})
+						require.Equal(t, backendplugin.ErrMethodNotImplemented, err)
+					})
+
+					t.Run("Subscribe stream should return expected response", func(t *testing.T) {
+						ctx.plugin.SubscribeStreamFunc = func(ctx context.Context, req *backend.SubscribeStreamRequest) (*backend.SubscribeStreamResponse, error) {
+							return &backend.SubscribeStreamResponse{Status: backend.SubscribeStreamStatusOK}, nil
+						}
+
+						res, err := ctx.manager.SubscribeStream(context.Background(), &backend.SubscribeStreamRequest{
+							PluginContext: backend.PluginContext{PluginID: testPluginID},
+							Path:          "tail",
+						})
+						require.NoError(t, err)
+						require.Equal(t, backend.SubscribeStreamStatusOK, res.Status)
+					})
+
+					t.Run("Run stream should push frames of the plugin", func(t *testing.T) {
+						ctx.plugin.RunStreamFunc = func(ctx context.Context, req *backend.RunStreamRequest, sender *backend.StreamSender) error {
+							return sender.SendJSON([]byte(`{"path":"` + req.Path + `"}`))
+						}
+
+						packetSender := &testStreamPacketSender{}
+						err := ctx.manager.RunStream(context.Background(), &backend.RunStreamRequest{
+							PluginContext: backend.PluginContext{PluginID: testPluginID},
+							Path:          "tail",
+						}, backend.NewStreamSender(packetSender))
+						require.NoError(t, err)
+						require.Len(t, packetSender.packets, 1)
+						require.JSONEq(t, `{"path":"tail"}`, string(packetSender.packets[0].Data))
+					})
 				})
 
 				t.Run("Should be able to decommission a running plugin", func(t *testing.T) {
@@ -278,6 +367,86 @@ func TestManager(t *testing.T) {
 		})
 	})
 
+	newManagerScenario(t, false, func(t *testing.T, ctx *managerScenarioCtx) {
+		t.Run("Should register plugin instance with the settings of the plugin", func(t *testing.T) {
+			ctx.cfg.PluginSettings = setting.PluginSettings{testPluginID: {"key": "value"}}
+
+			err := ctx.manager.RegisterInstance(testPluginID, testPluginID+"-1", ctx.factory)
+			require.NoError(t, err)
+			require.Equal(t, testPluginID+"-1", ctx.plugin.pluginID)
+			require.Contains(t, ctx.env, "GF_PLUGIN_KEY=value")
+			require.True(t, ctx.manager.IsRegistered(testPluginID+"-1"))
+			require.False(t, ctx.manager.IsRegistered(testPluginID))
+		})
+	})
+
+	newManagerScenario(t, false, func(t *testing.T, ctx *managerScenarioCtx) {
+		t.Run("Log level scenario", func(t *testing.T) {
+			err := ctx.manager.SetLogLevel(testPluginID, "debug")
+			require.Equal(t, backendplugin.ErrPluginNotRegistered, err)
+
+			err = ctx.manager.Register(testPluginID, ctx.factory)
+			require.NoError(t, err)
+
+			err = ctx.manager.SetLogLevel(testPluginID, "verbose")
+			require.Error(t, err)
+
+			err = ctx.manager.SetLogLevel(testPluginID, "debug")
+			require.NoError(t, err)
+			level, ok := log.ContextLevel("pluginId", testPluginID)
+			require.True(t, ok)
+			require.Equal(t, "debug", level)
+
+			t.Run("Should reset log level of unregistered plugin", func(t *testing.T) {
+				err := ctx.manager.UnregisterAndStop(context.Background(), testPluginID)
+				require.NoError(t, err)
+				_, ok := log.ContextLevel("pluginId", testPluginID)
+				require.False(t, ok)
+			})
+		})
+	})
+
+	newManagerScenario(t, false, func(t *testing.T, ctx *managerScenarioCtx) {
+		t.Run("Health check scenario", func(t *testing.T) {
+			ctx.cfg.PluginsHealthCheckInterval = time.Millisecond
+			ctx.cfg.PluginsHealthCheckTimeout = time.Second
+
+			err := ctx.manager.Register(testPluginID, ctx.factory)
+			require.NoError(t, err)
+
+			cCtx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			err = ctx.manager.StartPlugin(cCtx, testPluginID)
+			require.NoError(t, err)
+
+			status, ok := ctx.manager.Status(testPluginID)
+			require.True(t, ok)
+			require.Equal(t, backendplugin.PluginStatus{State: backendplugin.PluginStateRunning}, status)
+
+			t.Run("Should restart plugin failing health checks", func(t *testing.T) {
+				ctx.plugin.setPingErr(errors.New("connection refused"))
+				require.Eventually(t, func() bool {
+					status, _ := ctx.manager.Status(testPluginID)
+					return status.Restarts == 1
+				}, 5*time.Second, 10*time.Millisecond)
+				ctx.plugin.setPingErr(nil)
+
+				status, _ := ctx.manager.Status(testPluginID)
+				require.Equal(t, backendplugin.PluginStateRunning, status.State)
+				require.Equal(t, "connection refused", status.LastError)
+				require.Equal(t, 1, ctx.plugin.getStopCount())
+				require.Equal(t, 2, ctx.plugin.getStartCount())
+			})
+
+			t.Run("Should remove status of unregistered plugin", func(t *testing.T) {
+				err := ctx.manager.UnregisterAndStop(context.Background(), testPluginID)
+				require.NoError(t, err)
+				_, ok := ctx.manager.Status(testPluginID)
+				require.False(t, ok)
+			})
+		})
+	})
+
 	newManagerScenario(t, true, func(t *testing.T, ctx *managerScenarioCtx) {
 		t.Run("Plugin registration scenario when Grafana is licensed", func(t *testing.T) {
 			ctx.license.edition = "Enterprise"
@@ -307,6 +476,19 @@ func TestManager(t *testing.T) {
 	})
 }
 
+func TestNextRestartBackoff(t *testing.T) {
+	var backoffs []time.Duration
+	var backoff time.Duration
+	for i := 0; i < 11; i++ {
+		backoff = nextRestartBackoff(backoff)
+		backoffs = append(backoffs, backoff)
+	}
+	require.Equal(t, []time.Duration{
+		time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 32 * time.Second,
+		64 * time.Second, 128 * time.Second, 256 * time.Second, maxRestartBackoff, maxRestartBackoff,
+	}, backoffs)
+}
+
 type managerScenarioCtx struct {
 	cfg     *setting.Cfg
 	license *testLicensingService
@@ -365,11 +547,14 @@ type testPlugin struct {
 	managed        bool
 	exited         bool
 	decommissioned bool
+	pingErr        error
 	backend.CollectMetricsHandlerFunc
 	backend.CheckHealthHandlerFunc
 	backend.QueryDataHandlerFunc
 	backend.CallResourceHandlerFunc
-	mutex sync.RWMutex
+	SubscribeStreamFunc func(ctx context.Context, req *backend.SubscribeStreamRequest) (*backend.SubscribeStreamResponse, error)
+	RunStreamFunc       func(ctx context.Context, req *backend.RunStreamRequest, sender *backend.StreamSender) error
+	mutex               sync.RWMutex
 }
 
 func (tp *testPlugin) PluginID() string {
@@ -420,6 +605,30 @@ func (tp *testPlugin) IsDecommissioned() bool {
 	return tp.decommissioned
 }
 
+func (tp *testPlugin) Ping() error {
+	tp.mutex.RLock()
+	defer tp.mutex.RUnlock()
+	return tp.pingErr
+}
+
+func (tp *testPlugin) setPingErr(err error) {
+	tp.mutex.Lock()
+	defer tp.mutex.Unlock()
+	tp.pingErr = err
+}
+
+func (tp *testPlugin) getStartCount() int {
+	tp.mutex.RLock()
+	defer tp.mutex.RUnlock()
+	return tp.startCount
+}
+
+func (tp *testPlugin) getStopCount() int {
+	tp.mutex.RLock()
+	defer tp.mutex.RUnlock()
+	return tp.stopCount
+}
+
 func (tp *testPlugin) kill() {
 	tp.mutex.Lock()
 	defer tp.mutex.Unlock()
@@ -459,6 +668,10 @@ func (tp *testPlugin) CallResource(ctx context.Context, req *backend.CallResourc
 }
 
 func (tp *testPlugin) SubscribeStream(ctx context.Context, request *backend.SubscribeStreamRequest) (*backend.SubscribeStreamResponse, error) {
+	if tp.SubscribeStreamFunc != nil {
+		return tp.SubscribeStreamFunc(ctx, request)
+	}
+
 	return nil, backendplugin.ErrMethodNotImplemented
 }
 
@@ -467,9 +680,22 @@ func (tp *testPlugin) PublishStream(ctx context.Context, request *backend.Publis
 }
 
 func (tp *testPlugin) RunStream(ctx context.Context, request *backend.RunStreamRequest, sender *backend.StreamSender) error {
+	if tp.RunStreamFunc != nil {
+		return tp.RunStreamFunc(ctx, request, sender)
+	}
+
 	return backendplugin.ErrMethodNotImplemented
 }
 
+type testStreamPacketSender struct {
+	packets []*backend.StreamPacket
+}
+
+func (s *testStreamPacketSender) Send(packet *backend.StreamPacket) error {
+	s.packets = append(s.packets, packet)
+	return nil
+}
+
 type testLicensingService struct {
 	edition    string
 	hasLicense bool
@@ -0,0 +1,217 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/plugins/backendplugin"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+const (
+	// pluginMetricPrefix is prepended to the names of the metrics of backend
+	// plugins, so that they don't collide with the metrics of Grafana.
+	pluginMetricPrefix = "plugin_"
+	// pluginIDLabel is the label added to the metrics of backend plugins.
+	pluginIDLabel = "plugin_id"
+)
+
+// pluginMetricsCollector exposes the metrics last scraped from the backend
+// plugins as a prometheus.Collector.
+type pluginMetricsCollector struct {
+	logger   log.Logger
+	mu       sync.RWMutex
+	families []*dto.MetricFamily
+}
+
+func newPluginMetricsCollector(logger log.Logger) *pluginMetricsCollector {
+	return &pluginMetricsCollector{logger: logger}
+}
+
+// Describe sends no descriptors, which registers the collector as unchecked
+// since the metrics of the plugins are not known in advance.
+func (c *pluginMetricsCollector) Describe(chan<- *prometheus.Desc) {}
+
+func (c *pluginMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, family := range c.families {
+		desc := prometheus.NewDesc(family.GetName(), family.GetHelp(), nil, nil)
+		for _, metric := range family.Metric {
+			ch <- &pluginMetric{desc: desc, metric: metric}
+		}
+	}
+}
+
+// set replaces the collected metrics with the metric families of each plugin.
+// The metrics of the plugins are prefixed with pluginMetricPrefix and labelled
+// with the plugin ID. Families of several plugins with the same name are merged,
+// keeping the help and type of the first plugin.
+func (c *pluginMetricsCollector) set(pluginFamilies map[string]map[string]*dto.MetricFamily) {
+	pluginIDs := make([]string, 0, len(pluginFamilies))
+	for pluginID := range pluginFamilies {
+		pluginIDs = append(pluginIDs, pluginID)
+	}
+	sort.Strings(pluginIDs)
+
+	merged := map[string]*dto.MetricFamily{}
+	var names []string
+	for _, pluginID := range pluginIDs {
+		for familyName, family := range pluginFamilies[pluginID] {
+			name := pluginMetricPrefix + familyName
+			mergedFamily, exists := merged[name]
+			if !exists {
+				mergedFamily = &dto.MetricFamily{
+					Name: &name,
+					Help: family.Help,
+					Type: family.Type,
+				}
+				merged[name] = mergedFamily
+				names = append(names, name)
+			} else if mergedFamily.GetType() != family.GetType() {
+				c.logger.Debug("Skipping plugin metric with conflicting type", "pluginId", pluginID, "metric", name)
+				continue
+			}
+
+			for _, metric := range family.Metric {
+				mergedFamily.Metric = append(mergedFamily.Metric, withPluginIDLabel(metric, pluginID))
+			}
+		}
+	}
+
+	sort.Strings(names)
+	families := make([]*dto.MetricFamily, 0, len(names))
+	for _, name := range names {
+		families = append(families, merged[name])
+	}
+
+	c.mu.Lock()
+	c.families = families
+	c.mu.Unlock()
+}
+
+// withPluginIDLabel returns a copy of a metric with the plugin ID label, which
+// replaces a label of the plugin with the same name.
+func withPluginIDLabel(metric *dto.Metric, pluginID string) *dto.Metric {
+	name, value := pluginIDLabel, pluginID
+	labels := []*dto.LabelPair{{Name: &name, Value: &value}}
+	for _, label := range metric.Label {
+		if label.GetName() != pluginIDLabel {
+			labels = append(labels, label)
+		}
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].GetName() < labels[j].GetName()
+	})
+
+	return &dto.Metric{
+		Label:       labels,
+		Gauge:       metric.Gauge,
+		Counter:     metric.Counter,
+		Summary:     metric.Summary,
+		Untyped:     metric.Untyped,
+		Histogram:   metric.Histogram,
+		TimestampMs: metric.TimestampMs,
+	}
+}
+
+// pluginMetric is a prometheus.Metric scraped from a backend plugin.
+type pluginMetric struct {
+	desc   *prometheus.Desc
+	metric *dto.Metric
+}
+
+func (m *pluginMetric) Desc() *prometheus.Desc {
+	return m.desc
+}
+
+func (m *pluginMetric) Write(out *dto.Metric) error {
+	out.Label = m.metric.Label
+	out.Gauge = m.metric.Gauge
+	out.Counter = m.metric.Counter
+	out.Summary = m.metric.Summary
+	out.Untyped = m.metric.Untyped
+	out.Histogram = m.metric.Histogram
+	out.TimestampMs = m.metric.TimestampMs
+	return nil
+}
+
+// scrapePluginMetrics collects the metrics of the registered backend plugins
+// every metrics_scrape_interval, until ctx is done.
+func (m *manager) scrapePluginMetrics(ctx context.Context) {
+	ticker := time.NewTicker(m.Cfg.PluginsMetricsScrapeInterval)
+	defer ticker.Stop()
+
+	for {
+		m.collectPluginMetrics(ctx, m.Cfg.PluginsMetricsScrapeInterval)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// collectPluginMetrics collects the metrics of the registered backend plugins
+// and replaces the metrics exposed by the collector. The metrics of plugins that
+// fail to respond within timeout, or don't implement metrics, are dropped.
+func (m *manager) collectPluginMetrics(ctx context.Context, timeout time.Duration) {
+	m.pluginsMu.RLock()
+	pluginIDs := make([]string, 0, len(m.plugins))
+	for pluginID := range m.plugins {
+		pluginIDs = append(pluginIDs, pluginID)
+	}
+	m.pluginsMu.RUnlock()
+
+	var (
+		mu             sync.Mutex
+		wg             sync.WaitGroup
+		pluginFamilies = map[string]map[string]*dto.MetricFamily{}
+	)
+	for _, pluginID := range pluginIDs {
+		wg.Add(1)
+		go func(pluginID string) {
+			defer wg.Done()
+
+			families, err := m.scrapePlugin(ctx, pluginID, timeout)
+			if err != nil {
+				if !errors.Is(err, backendplugin.ErrMethodNotImplemented) && !errors.Is(err, backendplugin.ErrPluginNotRegistered) {
+					m.logger.Debug("Failed to collect plugin metrics", "pluginId", pluginID, "err", err)
+				}
+				return
+			}
+
+			mu.Lock()
+			pluginFamilies[pluginID] = families
+			mu.Unlock()
+		}(pluginID)
+	}
+	wg.Wait()
+
+	m.metricsCollector.set(pluginFamilies)
+}
+
+func (m *manager) scrapePlugin(ctx context.Context, pluginID string, timeout time.Duration) (map[string]*dto.MetricFamily, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	res, err := m.CollectMetrics(ctx, pluginID)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+
+	var parser expfmt.TextParser
+	return parser.TextToMetricFamilies(bytes.NewReader(res.PrometheusMetrics))
+}
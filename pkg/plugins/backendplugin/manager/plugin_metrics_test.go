@@ -0,0 +1,78 @@
+package manager
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/plugins/backendplugin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPluginMetrics(t *testing.T) {
+	newManagerScenario(t, false, func(t *testing.T, ctx *managerScenarioCtx) {
+		metricsFactory := func(metrics string) backendplugin.PluginFactoryFunc {
+			return func(pluginID string, logger log.Logger, env []string) (backendplugin.Plugin, error) {
+				return &testPlugin{
+					pluginID: pluginID,
+					logger:   logger,
+					CollectMetricsHandlerFunc: func(ctx context.Context) (*backend.CollectMetricsResult, error) {
+						return &backend.CollectMetricsResult{PrometheusMetrics: []byte(metrics)}, nil
+					},
+				}, nil
+			}
+		}
+
+		require.NoError(t, ctx.manager.Register("plugin-a", metricsFactory(`# HELP requests_total Requests.
+# TYPE requests_total counter
+requests_total{method="GET"} 3
+# HELP queue_size Queue size.
+# TYPE queue_size gauge
+queue_size{plugin_id="other"} 2
+`)))
+		require.NoError(t, ctx.manager.Register("plugin-b", metricsFactory(`# HELP requests_total Handled requests.
+# TYPE requests_total counter
+requests_total{method="POST"} 5
+# HELP queue_size Queue size.
+# TYPE queue_size counter
+queue_size 7
+`)))
+		require.NoError(t, ctx.manager.Register(testPluginID, ctx.factory))
+
+		ctx.manager.collectPluginMetrics(context.Background(), time.Second)
+
+		registry := prometheus.NewRegistry()
+		require.NoError(t, registry.Register(ctx.manager.metricsCollector))
+
+		t.Run("Should expose the metrics of the plugins with a plugin_id label", func(t *testing.T) {
+			err := testutil.GatherAndCompare(registry, strings.NewReader(`# HELP plugin_queue_size Queue size.
+# TYPE plugin_queue_size gauge
+plugin_queue_size{plugin_id="plugin-a"} 2
+# HELP plugin_requests_total Requests.
+# TYPE plugin_requests_total counter
+plugin_requests_total{method="GET",plugin_id="plugin-a"} 3
+plugin_requests_total{method="POST",plugin_id="plugin-b"} 5
+`))
+			require.NoError(t, err)
+		})
+
+		t.Run("Should drop the metrics of unregistered plugins", func(t *testing.T) {
+			require.NoError(t, ctx.manager.UnregisterAndStop(context.Background(), "plugin-a"))
+			ctx.manager.collectPluginMetrics(context.Background(), time.Second)
+
+			err := testutil.GatherAndCompare(registry, strings.NewReader(`# HELP plugin_queue_size Queue size.
+# TYPE plugin_queue_size counter
+plugin_queue_size{plugin_id="plugin-b"} 7
+# HELP plugin_requests_total Handled requests.
+# TYPE plugin_requests_total counter
+plugin_requests_total{method="POST",plugin_id="plugin-b"} 5
+`))
+			require.NoError(t, err)
+		})
+	})
+}
@@ -29,7 +29,8 @@ func (ps pluginSettings) ToEnv(prefix string, hostEnv []string) []string {
 func getPluginSettings(plugID string, cfg *setting.Cfg) pluginSettings {
 	ps := pluginSettings{}
 	for k, v := range cfg.PluginSettings[plugID] {
-		if k == "path" || strings.ToLower(k) == "id" {
+		if k == "path" || k == "signature_verification" || k == "signature_types" || k == "pinned_version" || strings.HasPrefix(k, "process_") ||
+			strings.ToLower(k) == "id" {
 			continue
 		}
 
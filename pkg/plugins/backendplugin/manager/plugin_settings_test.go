@@ -29,6 +29,21 @@ func TestPluginSettings(t *testing.T) {
 			require.Len(t, ps, 2)
 		})
 
+		t.Run("Should skip signature policy and version pin settings", func(t *testing.T) {
+			cfg.PluginSettings["plugin"]["signature_verification"] = "warn"
+			cfg.PluginSettings["plugin"]["signature_types"] = "private"
+			cfg.PluginSettings["plugin"]["pinned_version"] = "1.0.0"
+			ps := getPluginSettings("plugin", cfg)
+			require.Len(t, ps, 2)
+		})
+
+		t.Run("Should skip process limit settings", func(t *testing.T) {
+			cfg.PluginSettings["plugin"]["process_memory_limit_mb"] = "512"
+			cfg.PluginSettings["plugin"]["process_env_allowlist"] = "PATH"
+			ps := getPluginSettings("plugin", cfg)
+			require.Len(t, ps, 2)
+		})
+
 		t.Run("Should skip id setting", func(t *testing.T) {
 			cfg.PluginSettings["plugin"]["id"] = "value"
 			ps := getPluginSettings("plugin", cfg)
@@ -0,0 +1,20 @@
+package backendplugin
+
+// ValidateSettingsPath is the path of the resource of a backend plugin that
+// validates the settings of a data source or app before they are saved. The
+// resource is called with a POST request, whose plugin context holds the
+// settings to save, and responds with a ValidateSettingsResult. Plugins
+// without the resource accept any settings.
+const ValidateSettingsPath = "_validate-settings"
+
+// ValidateSettingsResult is the result of the validation of the settings of a
+// data source or app by its backend plugin.
+type ValidateSettingsResult struct {
+	// Valid is false when the settings must not be saved.
+	Valid bool `json:"valid"`
+	// Message explains why the settings are invalid.
+	Message string `json:"message,omitempty"`
+	// Fields maps the invalid settings, as url or jsonData.region, to their
+	// errors.
+	Fields map[string]string `json:"fields,omitempty"`
+}
@@ -5,4 +5,6 @@ type ErrorCode string
 type PluginError struct {
 	ErrorCode `json:"errorCode"`
 	PluginID  string `json:"pluginId,omitempty"`
+	Message   string `json:"message,omitempty"`
+	PluginDir string `json:"-"`
 }
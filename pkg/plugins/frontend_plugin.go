@@ -1,6 +1,9 @@
 package plugins
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"io/ioutil"
 	"net/url"
 	"path"
 	"path/filepath"
@@ -23,6 +26,7 @@ func (fp *FrontendPluginBase) InitFrontendPlugin(cfg *setting.Cfg) []*PluginStat
 				PluginId:  fp.Id,
 			},
 		}
+		fp.AngularDetected = detectAngular(fp.PluginDir)
 	}
 
 	fp.handleModuleDefaults(cfg)
@@ -54,7 +58,10 @@ func (fp *FrontendPluginBase) setPathsBasedOnApp(app *AppPlugin, cfg *setting.Cf
 	fp.IncludedInAppId = app.Id
 	fp.BaseUrl = app.BaseUrl
 
-	if isExternalPlugin(app.PluginDir, cfg) {
+	if isCDNURL(app.BaseUrl) {
+		fp.Module = util.JoinURLFragments(app.BaseUrl, appSubPath) + "/module.js"
+		fp.ModuleIntegrity = moduleIntegrity(fp.PluginDir)
+	} else if isExternalPlugin(app.PluginDir, cfg) {
 		fp.Module = util.JoinURLFragments("plugins/"+app.Id, appSubPath) + "/module"
 	} else {
 		fp.Module = util.JoinURLFragments("app/plugins/app/"+app.Id, appSubPath) + "/module"
@@ -63,6 +70,15 @@ func (fp *FrontendPluginBase) setPathsBasedOnApp(app *AppPlugin, cfg *setting.Cf
 
 func (fp *FrontendPluginBase) handleModuleDefaults(cfg *setting.Cfg) {
 	if isExternalPlugin(fp.PluginDir, cfg) {
+		if cfg.PluginsCDNBaseURL != "" && fp.Info.Version != "" {
+			// the assets of the plugin are served by the CDN from a directory
+			// that mirrors the plugin directory of this version of the plugin
+			fp.BaseUrl = util.JoinURLFragments(cfg.PluginsCDNBaseURL, path.Join(fp.Id, fp.Info.Version))
+			fp.Module = fp.BaseUrl + "/module.js"
+			fp.ModuleIntegrity = moduleIntegrity(fp.PluginDir)
+			return
+		}
+
 		fp.Module = path.Join("plugins", fp.Id, "module")
 		fp.BaseUrl = path.Join("public/plugins", fp.Id)
 		return
@@ -79,6 +95,28 @@ func (fp *FrontendPluginBase) handleModuleDefaults(cfg *setting.Cfg) {
 	fp.BaseUrl = path.Join("public/app/plugins", fp.Type, currentDir)
 }
 
+// moduleIntegrity returns the subresource integrity hash of the module.js file
+// of a plugin, which lets the browser check the module loaded from the CDN.
+// An empty string is returned if the file can't be read.
+func moduleIntegrity(pluginDir string) string {
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because the path is the
+	// plugin directory found when scanning the plugins.
+	b, err := ioutil.ReadFile(filepath.Join(pluginDir, "module.js"))
+	if err != nil {
+		return ""
+	}
+
+	h := sha256.Sum256(b)
+	return "sha256-" + base64.StdEncoding.EncodeToString(h[:])
+}
+
+// isCDNURL returns whether the base URL of a plugin points to the plugin CDN.
+func isCDNURL(baseURL string) bool {
+	u, err := url.Parse(baseURL)
+	return err == nil && u.IsAbs()
+}
+
 func isExternalPlugin(pluginDir string, cfg *setting.Cfg) bool {
 	return !strings.Contains(pluginDir, cfg.StaticRootPath)
 }
@@ -98,5 +136,9 @@ func evalRelativePluginUrlPath(pathStr, baseUrl, pluginType string) string {
 		return pathStr
 	}
 
+	if isCDNURL(baseUrl) {
+		return util.JoinURLFragments(baseUrl, path.Clean(pathStr))
+	}
+
 	return path.Join(baseUrl, pathStr)
 }
@@ -1,6 +1,10 @@
 package plugins
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"io/ioutil"
+	"path/filepath"
 	"testing"
 
 	"github.com/grafana/grafana/pkg/setting"
@@ -32,3 +36,50 @@ func TestFrontendPlugin(t *testing.T) {
 		So(fp.Module, ShouldEqual, "app/plugins/app/testdata/datasources/datasource/module")
 	})
 }
+
+func TestFrontendPluginCDN(t *testing.T) {
+	Convey("When the plugin CDN is configured", t, func() {
+		pluginDir := t.TempDir()
+		module := []byte("define([], function() {});")
+		err := ioutil.WriteFile(filepath.Join(pluginDir, "module.js"), module, 0600)
+		So(err, ShouldBeNil)
+		hash := sha256.Sum256(module)
+
+		cfg := setting.NewCfg()
+		cfg.StaticRootPath = "/usr/share/grafana/public"
+		cfg.PluginsCDNBaseURL = "https://cdn.example.com/plugins"
+
+		fp := &FrontendPluginBase{
+			PluginBase: PluginBase{
+				Id:        "test-app",
+				Type:      "app",
+				PluginDir: pluginDir,
+				Info: PluginInfo{
+					Version: "1.2.0",
+					Logos:   PluginLogos{Small: "img/logo.svg"},
+				},
+			},
+		}
+		fp.InitFrontendPlugin(cfg)
+
+		Convey("Should serve the assets of external plugins from the CDN", func() {
+			So(fp.BaseUrl, ShouldEqual, "https://cdn.example.com/plugins/test-app/1.2.0")
+			So(fp.Module, ShouldEqual, "https://cdn.example.com/plugins/test-app/1.2.0/module.js")
+			So(fp.ModuleIntegrity, ShouldEqual, "sha256-"+base64.StdEncoding.EncodeToString(hash[:]))
+			So(fp.Info.Logos.Small, ShouldEqual, "https://cdn.example.com/plugins/test-app/1.2.0/img/logo.svg")
+		})
+
+		Convey("Should serve the assets of nested plugins from the CDN of the app", func() {
+			nested := &FrontendPluginBase{
+				PluginBase: PluginBase{
+					PluginDir: filepath.Join(pluginDir, "datasources", "ds"),
+				},
+			}
+			app := &AppPlugin{FrontendPluginBase: *fp}
+			nested.setPathsBasedOnApp(app, cfg)
+
+			So(nested.Module, ShouldEqual, "https://cdn.example.com/plugins/test-app/1.2.0/datasources/ds/module.js")
+			So(nested.ModuleIntegrity, ShouldEqual, "")
+		})
+	})
+}
@@ -50,12 +50,19 @@ type Manager interface {
 		requestHandler DataRequestHandler) (PluginDashboardInfoDTO, *models.Dashboard, error)
 	// ScanningErrors returns plugin scanning errors encountered.
 	ScanningErrors() []PluginError
+	// PluginErrors returns the errors of the plugins that failed to load, and of
+	// the backend plugins whose process can't run.
+	PluginErrors() []PluginError
 	// LoadPluginDashboard loads a plugin dashboard.
 	LoadPluginDashboard(pluginID, path string) (*models.Dashboard, error)
 	// IsAppInstalled returns whether an app is installed.
 	IsAppInstalled(id string) bool
 	// Install installs a plugin.
 	Install(ctx context.Context, pluginID, version string) error
+	// InstallFromArchive installs a plugin from a local zip archive.
+	InstallFromArchive(ctx context.Context, pluginID, archivePath string) error
+	// InstallFromOCI installs a plugin packaged as an OCI artifact of a registry.
+	InstallFromOCI(ctx context.Context, pluginID, ref string) error
 	// Uninstall uninstalls a plugin.
 	Uninstall(ctx context.Context, pluginID string) error
 }
@@ -76,6 +83,10 @@ type DataRequestHandler interface {
 type PluginInstaller interface {
 	// Install finds the plugin given the provided information and installs in the provided plugins directory.
 	Install(ctx context.Context, pluginID, version, pluginsDirectory, pluginZipURL, pluginRepoURL string) error
+	// InstallFromArchive extracts a local plugin zip archive into the provided plugins directory.
+	InstallFromArchive(ctx context.Context, pluginID, archivePath, pluginsDirectory, pluginRepoURL string) error
+	// InstallFromOCI pulls a plugin packaged as an OCI artifact of a registry into the provided plugins directory.
+	InstallFromOCI(ctx context.Context, pluginID, ref, pluginsDirectory, pluginRepoURL string) error
 	// Uninstall removes the specified plugin from the provided plugins directory.
 	Uninstall(ctx context.Context, pluginPath string) error
 	// GetUpdateInfo returns update information if the requested plugin is supported on the running system.
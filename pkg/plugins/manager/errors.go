@@ -8,4 +8,19 @@ const (
 	signatureMissing  plugins.ErrorCode = "signatureMissing"
 	signatureModified plugins.ErrorCode = "signatureModified"
 	signatureInvalid  plugins.ErrorCode = "signatureInvalid"
+
+	signatureTypeMismatch plugins.ErrorCode = "signatureTypeMismatch"
+
+	grafanaVersionIncompatible plugins.ErrorCode = "grafanaVersionIncompatible"
+	executableMissing          plugins.ErrorCode = "executableMissing"
+	processCrashed             plugins.ErrorCode = "processCrashed"
 )
+
+// isSignatureError returns whether a plugin error is a failed signature validation.
+func isSignatureError(code plugins.ErrorCode) bool {
+	switch code {
+	case signatureMissing, signatureModified, signatureInvalid, signatureTypeMismatch:
+		return true
+	}
+	return false
+}
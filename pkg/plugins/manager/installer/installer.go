@@ -34,8 +34,19 @@ type Installer struct {
 	httpClientNoTimeout http.Client
 	grafanaVersion      string
 	log                 plugins.PluginInstallerLogger
+
+	repoURL       string
+	repoAuthToken string
+
+	resolveDependency DependencyResolver
 }
 
+// DependencyResolver resolves the version of a plugin dependency to install,
+// given the version required by the plugin that depends on it. It returns false
+// when the dependency doesn't need to be installed, and an error when the
+// dependency conflicts with the plugins and settings of the Grafana instance.
+type DependencyResolver func(pluginID, version string) (string, bool, error)
+
 const (
 	permissionsDeniedMessage = "could not create %q, permission denied, make sure you have write access to plugin dir"
 )
@@ -89,6 +100,19 @@ func New(skipTLSVerify bool, grafanaVersion string, logger plugins.PluginInstall
 	}
 }
 
+// SetRepoAuthToken makes the installer authenticate the requests to the plugin
+// repository at repoURL with a bearer token, for private plugin repositories.
+func (i *Installer) SetRepoAuthToken(repoURL, token string) {
+	i.repoURL = repoURL
+	i.repoAuthToken = token
+}
+
+// SetDependencyResolver makes the installer resolve the plugin dependencies of
+// the installed plugins with resolver, instead of installing all of them.
+func (i *Installer) SetDependencyResolver(resolver DependencyResolver) {
+	i.resolveDependency = resolver
+}
+
 // Install downloads the plugin code as a zip file from specified URL
 // and then extracts the zip into the provided plugins directory.
 func (i *Installer) Install(ctx context.Context, pluginID, version, pluginsDir, pluginZipURL, pluginRepoURL string) error {
@@ -157,24 +181,70 @@ func (i *Installer) Install(ctx context.Context, pluginID, version, pluginsDir,
 		return errutil.Wrap("failed to close tmp file", err)
 	}
 
-	err = i.extractFiles(tmpFile.Name(), pluginID, pluginsDir, isInternal)
+	return i.installArchive(ctx, tmpFile.Name(), pluginID, pluginsDir, pluginRepoURL, isInternal)
+}
+
+// InstallFromArchive extracts a plugin zip file which is already available
+// locally into the provided plugins directory and installs its dependencies.
+func (i *Installer) InstallFromArchive(ctx context.Context, pluginID, archivePath, pluginsDir, pluginRepoURL string) error {
+	i.log.Debugf("Installing plugin\nfrom: %s\ninto: %s", archivePath, pluginsDir)
+
+	return i.installArchive(ctx, archivePath, pluginID, pluginsDir, pluginRepoURL, false)
+}
+
+func (i *Installer) installArchive(ctx context.Context, archivePath, pluginID, pluginsDir, pluginRepoURL string, allowSymlinks bool) error {
+	err := i.extractFiles(archivePath, pluginID, pluginsDir, allowSymlinks)
 	if err != nil {
 		return errutil.Wrap("failed to extract plugin archive", err)
 	}
 
 	res, _ := toPluginDTO(pluginsDir, pluginID)
 
-	i.log.Successf("Downloaded %s v%s zip successfully", res.ID, res.Info.Version)
+	i.log.Successf("Extracted %s v%s zip successfully", res.ID, res.Info.Version)
+
+	// resolve all dependencies before installing any of them, so that a conflict
+	// doesn't leave the plugin with part of its dependencies installed
+	deps, err := i.resolveDependencies(res)
+	if err != nil {
+		if err := i.Uninstall(ctx, filepath.Join(pluginsDir, pluginID)); err != nil {
+			i.log.Warn("Failed to remove plugin with unresolved dependencies", "pluginId", pluginID, "err", err)
+		}
+		return err
+	}
 
 	// download dependency plugins
-	for _, dep := range res.Dependencies.Plugins {
+	for _, dep := range deps {
 		i.log.Infof("Fetching %s dependencies...", res.ID)
-		if err := i.Install(ctx, dep.ID, normalizeVersion(dep.Version), pluginsDir, "", pluginRepoURL); err != nil {
+		if err := i.Install(ctx, dep.ID, dep.Version, pluginsDir, "", pluginRepoURL); err != nil {
 			return errutil.Wrapf(err, "failed to install plugin %s", dep.ID)
 		}
 	}
 
-	return err
+	return nil
+}
+
+// resolveDependencies returns the plugin dependencies of a plugin that need to
+// be installed, with the versions to install.
+func (i *Installer) resolveDependencies(plugin InstalledPlugin) ([]PluginDependency, error) {
+	var deps []PluginDependency
+	for _, dep := range plugin.Dependencies.Plugins {
+		version := normalizeVersion(dep.Version)
+		if i.resolveDependency != nil {
+			resolved, install, err := i.resolveDependency(dep.ID, version)
+			if err != nil {
+				return nil, errutil.Wrapf(err, "failed to resolve dependency %s of plugin %s", dep.ID, plugin.ID)
+			}
+			if !install {
+				i.log.Debugf("Dependency %s of plugin %s is already installed", dep.ID, plugin.ID)
+				continue
+			}
+			version = resolved
+		}
+
+		dep.Version = version
+		deps = append(deps, dep)
+	}
+	return deps, nil
 }
 
 // Uninstall removes the specified plugin from the provided plugin directory.
@@ -346,6 +416,10 @@ func (i *Installer) createRequest(URL string, subPaths ...string) (*http.Request
 	req.Header.Set("grafana-os", runtime.GOOS)
 	req.Header.Set("grafana-arch", runtime.GOARCH)
 	req.Header.Set("User-Agent", "grafana "+i.grafanaVersion)
+	// only send the token to the plugin repository, not to the other download URLs
+	if i.repoAuthToken != "" && strings.HasPrefix(u.String(), i.repoURL) {
+		req.Header.Set("Authorization", "Bearer "+i.repoAuthToken)
+	}
 
 	return req, err
 }
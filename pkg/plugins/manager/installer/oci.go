@@ -0,0 +1,303 @@
+package installer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+const (
+	// OCIManifestMediaType is the media type of the manifests of OCI artifacts.
+	OCIManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	// PluginLayerMediaType is the media type of the layer that holds the zip
+	// archive of a plugin packaged as an OCI artifact.
+	PluginLayerMediaType = "application/vnd.grafana.plugin.layer.v1+zip"
+)
+
+var (
+	reOCIDigest       = regexp.MustCompile("^sha256:[a-f0-9]{64}$")
+	reAuthenticateArg = regexp.MustCompile(`(\w+)="([^"]*)"`)
+)
+
+// OCIReference references a plugin packaged as an OCI artifact, by tag as in
+// registry/repository:tag, or by digest as in registry/repository@sha256:digest.
+type OCIReference struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// ParseOCIReference parses a reference to an OCI artifact. The registry is
+// required, and the tag defaults to latest.
+func ParseOCIReference(ref string) (OCIReference, error) {
+	s := strings.TrimPrefix(ref, "oci://")
+	slash := strings.Index(s, "/")
+	if slash <= 0 {
+		return OCIReference{}, fmt.Errorf("invalid OCI reference %q: the registry is missing", ref)
+	}
+
+	r := OCIReference{Registry: s[:slash]}
+	s = s[slash+1:]
+	if at := strings.Index(s, "@"); at >= 0 {
+		r.Digest = s[at+1:]
+		s = s[:at]
+		if !reOCIDigest.MatchString(r.Digest) {
+			return OCIReference{}, fmt.Errorf("invalid OCI reference %q: only sha256 digests are supported", ref)
+		}
+	}
+	if colon := strings.LastIndex(s, ":"); colon >= 0 {
+		r.Tag = s[colon+1:]
+		s = s[:colon]
+	}
+	r.Repository = s
+	if r.Repository == "" {
+		return OCIReference{}, fmt.Errorf("invalid OCI reference %q: the repository is missing", ref)
+	}
+	if r.Tag == "" && r.Digest == "" {
+		r.Tag = "latest"
+	}
+
+	return r, nil
+}
+
+func (r OCIReference) String() string {
+	s := r.Registry + "/" + r.Repository
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+// reference returns the digest of the artifact if the reference pins it, and
+// its tag otherwise.
+func (r OCIReference) reference() string {
+	if r.Digest != "" {
+		return r.Digest
+	}
+	return r.Tag
+}
+
+type ociManifest struct {
+	MediaType string          `json:"mediaType"`
+	Layers    []ociDescriptor `json:"layers"`
+}
+
+type ociDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+// InstallFromOCI pulls a plugin packaged as an OCI artifact from its registry,
+// verifies the digests of the artifact manifest and of the plugin archive, and
+// extracts the archive into the provided plugins directory.
+func (i *Installer) InstallFromOCI(ctx context.Context, pluginID, ref, pluginsDir, pluginRepoURL string) error {
+	ociRef, err := ParseOCIReference(ref)
+	if err != nil {
+		return err
+	}
+
+	i.log.Debugf("Installing plugin\nfrom: %s\ninto: %s", ociRef, pluginsDir)
+
+	tmpFile, err := ioutil.TempFile("", "*.zip")
+	if err != nil {
+		return errutil.Wrap("failed to create temporary file", err)
+	}
+	defer func() {
+		if err := os.Remove(tmpFile.Name()); err != nil {
+			i.log.Warn("Failed to remove temporary file", "file", tmpFile.Name(), "err", err)
+		}
+	}()
+
+	err = i.pullOCIArtifact(ctx, ociRef, tmpFile)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errutil.Wrapf(err, "failed to pull plugin %s from %s", pluginID, ociRef)
+	}
+
+	return i.installArchive(ctx, tmpFile.Name(), pluginID, pluginsDir, pluginRepoURL, false)
+}
+
+// pullOCIArtifact writes the plugin archive of an OCI artifact to w.
+func (i *Installer) pullOCIArtifact(ctx context.Context, ref OCIReference, w io.Writer) error {
+	c := &ociClient{httpClient: &i.httpClientNoTimeout, ref: ref}
+
+	body, err := c.get(ctx, "manifests/"+ref.reference(), OCIManifestMediaType)
+	if err != nil {
+		return err
+	}
+	manifestBytes, err := ioutil.ReadAll(body)
+	closeBody(body)
+	if err != nil {
+		return errutil.Wrap("failed to read the artifact manifest", err)
+	}
+	if ref.Digest != "" && ref.Digest != ociDigest(manifestBytes) {
+		return fmt.Errorf("the digest of the artifact manifest doesn't match %s", ref.Digest)
+	}
+
+	var manifest ociManifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return errutil.Wrap("failed to parse the artifact manifest", err)
+	}
+	layer, err := pluginLayer(manifest)
+	if err != nil {
+		return err
+	}
+
+	body, err = c.get(ctx, "blobs/"+layer.Digest, "")
+	if err != nil {
+		return err
+	}
+	defer closeBody(body)
+
+	h := sha256.New()
+	n, err := io.Copy(w, io.TeeReader(body, h))
+	if err != nil {
+		return errutil.Wrap("failed to download the plugin archive", err)
+	}
+	if layer.Size > 0 && n != layer.Size {
+		return fmt.Errorf("the size of the plugin archive doesn't match the artifact manifest")
+	}
+	if "sha256:"+hex.EncodeToString(h.Sum(nil)) != layer.Digest {
+		return fmt.Errorf("the digest of the plugin archive doesn't match the artifact manifest")
+	}
+
+	return nil
+}
+
+// pluginLayer returns the layer of an artifact manifest with the plugin archive.
+func pluginLayer(manifest ociManifest) (ociDescriptor, error) {
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != PluginLayerMediaType && layer.MediaType != "application/zip" {
+			continue
+		}
+		if !reOCIDigest.MatchString(layer.Digest) {
+			return ociDescriptor{}, fmt.Errorf("unsupported digest %q of the plugin archive", layer.Digest)
+		}
+		return layer, nil
+	}
+	return ociDescriptor{}, fmt.Errorf("the artifact has no layer of type %s", PluginLayerMediaType)
+}
+
+func ociDigest(b []byte) string {
+	sum := sha256.Sum256(b)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func closeBody(body io.ReadCloser) {
+	// the body has been read, so the error is of no interest
+	_ = body.Close()
+}
+
+// ociClient pulls from a repository of an OCI registry, using the anonymous
+// bearer tokens of the registry token service when the registry requires them.
+type ociClient struct {
+	httpClient *http.Client
+	ref        OCIReference
+	token      string
+}
+
+func (c *ociClient) get(ctx context.Context, path, accept string) (io.ReadCloser, error) {
+	res, err := c.do(ctx, path, accept)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == http.StatusUnauthorized && c.token == "" {
+		challenge := res.Header.Get("WWW-Authenticate")
+		closeBody(res.Body)
+		if err := c.authenticate(ctx, challenge); err != nil {
+			return nil, err
+		}
+		if res, err = c.do(ctx, path, accept); err != nil {
+			return nil, err
+		}
+	}
+
+	if res.StatusCode/100 != 2 {
+		closeBody(res.Body)
+		return nil, fmt.Errorf("registry returned invalid status for %s: %s", path, res.Status)
+	}
+	return res.Body, nil
+}
+
+func (c *ociClient) do(ctx context.Context, path, accept string) (*http.Response, error) {
+	u := fmt.Sprintf("https://%s/v2/%s/%s", c.ref.Registry, c.ref.Repository, path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	return c.httpClient.Do(req)
+}
+
+// authenticate requests a pull token for the repository from the token service
+// of a bearer challenge of the registry.
+func (c *ociClient) authenticate(ctx context.Context, challenge string) error {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return fmt.Errorf("registry %s requires unsupported authentication", c.ref.Registry)
+	}
+	args := map[string]string{}
+	for _, m := range reAuthenticateArg.FindAllStringSubmatch(challenge, -1) {
+		args[m[1]] = m[2]
+	}
+	if args["realm"] == "" {
+		return fmt.Errorf("registry %s returned a bearer challenge without realm", c.ref.Registry)
+	}
+
+	params := url.Values{}
+	if args["service"] != "" {
+		params.Set("service", args["service"])
+	}
+	params.Set("scope", fmt.Sprintf("repository:%s:pull", c.ref.Repository))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, args["realm"]+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return errutil.Wrap("failed to request a registry token", err)
+	}
+	defer closeBody(res.Body)
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("registry token service returned invalid status: %s", res.Status)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&token); err != nil {
+		return errutil.Wrap("failed to parse the registry token", err)
+	}
+	c.token = token.Token
+	if c.token == "" {
+		c.token = token.AccessToken
+	}
+	if c.token == "" {
+		return fmt.Errorf("registry token service returned no token")
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package installer
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOCIReference(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+	tcs := []struct {
+		ref      string
+		expected OCIReference
+		err      string
+	}{
+		{ref: "registry.example.com/plugins/test-panel:1.0.0", expected: OCIReference{Registry: "registry.example.com", Repository: "plugins/test-panel", Tag: "1.0.0"}},
+		{ref: "oci://localhost:5000/test-panel", expected: OCIReference{Registry: "localhost:5000", Repository: "test-panel", Tag: "latest"}},
+		{ref: "registry.example.com/test-panel@" + digest, expected: OCIReference{Registry: "registry.example.com", Repository: "test-panel", Digest: digest}},
+		{ref: "test-panel:1.0.0", err: `invalid OCI reference "test-panel:1.0.0": the registry is missing`},
+		{ref: "registry.example.com/:1.0.0", err: `invalid OCI reference "registry.example.com/:1.0.0": the repository is missing`},
+		{ref: "registry.example.com/test-panel@md5:abc", err: `invalid OCI reference "registry.example.com/test-panel@md5:abc": only sha256 digests are supported`},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.ref, func(t *testing.T) {
+			ref, err := ParseOCIReference(tc.ref)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, ref)
+		})
+	}
+}
+
+func TestInstallFromOCI(t *testing.T) {
+	archive := pluginArchive(t, "test-panel", `{"id": "test-panel", "type": "panel", "info": {"version": "1.0.0"}}`)
+	registry := newTestRegistry(t, "plugins/test-panel", "1.0.0", archive)
+	host := strings.TrimPrefix(registry.server.URL, "https://")
+
+	t.Run("Should install the plugin archive of the artifact", func(t *testing.T) {
+		pluginsDir := t.TempDir()
+		i := New(true, "8.0.0", &testLogger{})
+		err := i.InstallFromOCI(context.Background(), "test-panel", host+"/plugins/test-panel:1.0.0", pluginsDir, "")
+		require.NoError(t, err)
+
+		b, err := ioutil.ReadFile(filepath.Join(pluginsDir, "test-panel", "plugin.json"))
+		require.NoError(t, err)
+		assert.Contains(t, string(b), `"version": "1.0.0"`)
+		assert.Equal(t, 1, registry.tokenRequests)
+	})
+
+	t.Run("Should install an artifact pinned by digest", func(t *testing.T) {
+		pluginsDir := t.TempDir()
+		i := New(true, "8.0.0", &testLogger{})
+		err := i.InstallFromOCI(context.Background(), "test-panel", host+"/plugins/test-panel@"+registry.manifestDigest, pluginsDir, "")
+		require.NoError(t, err)
+	})
+
+	t.Run("Should fail when the manifest doesn't match the pinned digest", func(t *testing.T) {
+		pluginsDir := t.TempDir()
+		i := New(true, "8.0.0", &testLogger{})
+		digest := "sha256:" + strings.Repeat("a", 64)
+		registry.manifests[digest] = registry.manifests["1.0.0"]
+		err := i.InstallFromOCI(context.Background(), "test-panel", host+"/plugins/test-panel@"+digest, pluginsDir, "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "the digest of the artifact manifest doesn't match")
+		assert.NoDirExists(t, filepath.Join(pluginsDir, "test-panel"))
+	})
+
+	t.Run("Should fail when the archive doesn't match its digest", func(t *testing.T) {
+		pluginsDir := t.TempDir()
+		i := New(true, "8.0.0", &testLogger{})
+		for digest := range registry.blobs {
+			registry.blobs[digest] = append([]byte{}, archive...)
+			registry.blobs[digest][0] ^= 0xff
+		}
+		err := i.InstallFromOCI(context.Background(), "test-panel", host+"/plugins/test-panel:1.0.0", pluginsDir, "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "the digest of the plugin archive doesn't match the artifact manifest")
+		assert.NoDirExists(t, filepath.Join(pluginsDir, "test-panel"))
+	})
+}
+
+func pluginArchive(t *testing.T, pluginID, pluginJSON string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, err := w.Create(pluginID + "/plugin.json")
+	require.NoError(t, err)
+	_, err = f.Write([]byte(pluginJSON))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+// testRegistry is an OCI registry with a repository of a plugin artifact, which
+// requires an anonymous bearer token.
+type testRegistry struct {
+	server         *httptest.Server
+	manifests      map[string][]byte
+	blobs          map[string][]byte
+	manifestDigest string
+	tokenRequests  int
+}
+
+func newTestRegistry(t *testing.T, repository, tag string, archive []byte) *testRegistry {
+	t.Helper()
+
+	layerDigest := ociDigest(archive)
+	manifest, err := json.Marshal(ociManifest{
+		MediaType: OCIManifestMediaType,
+		Layers:    []ociDescriptor{{MediaType: PluginLayerMediaType, Digest: layerDigest, Size: int64(len(archive))}},
+	})
+	require.NoError(t, err)
+
+	r := &testRegistry{
+		manifests:      map[string][]byte{tag: manifest, ociDigest(manifest): manifest},
+		blobs:          map[string][]byte{layerDigest: archive},
+		manifestDigest: ociDigest(manifest),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, req *http.Request) {
+		r.tokenRequests++
+		if req.URL.Query().Get("scope") != "repository:"+repository+":pull" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"token": "pull-token"}`))
+	})
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer pull-token" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, r.server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		path := strings.TrimPrefix(req.URL.Path, "/v2/"+repository+"/")
+		var content []byte
+		switch {
+		case strings.HasPrefix(path, "manifests/"):
+			content = r.manifests[strings.TrimPrefix(path, "manifests/")]
+			w.Header().Set("Content-Type", OCIManifestMediaType)
+		case strings.HasPrefix(path, "blobs/"):
+			content = r.blobs[strings.TrimPrefix(path, "blobs/")]
+		}
+		if content == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(content)
+	})
+
+	r.server = httptest.NewTLSServer(mux)
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+type testLogger struct{}
+
+func (l *testLogger) Successf(format string, args ...interface{}) {}
+func (l *testLogger) Failuref(format string, args ...interface{}) {}
+func (l *testLogger) Info(args ...interface{})                    {}
+func (l *testLogger) Infof(format string, args ...interface{})    {}
+func (l *testLogger) Debug(args ...interface{})                   {}
+func (l *testLogger) Debugf(format string, args ...interface{})   {}
+func (l *testLogger) Warn(args ...interface{})                    {}
+func (l *testLogger) Warnf(format string, args ...interface{})    {}
+func (l *testLogger) Error(args ...interface{})                   {}
+func (l *testLogger) Errorf(format string, args ...interface{})   {}
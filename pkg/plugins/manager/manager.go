@@ -73,6 +73,13 @@ type PluginManager struct {
 	apps         map[string]*plugins.AppPlugin
 	staticRoutes []*plugins.PluginStaticRoute
 	pluginsMu    sync.RWMutex
+
+	// pluginFingerprints tracks the external plugins changed on disk since they were loaded.
+	pluginFingerprints map[string]time.Time
+	reloadMu           sync.Mutex
+
+	// notifiedUpdates are the plugin updates logged by the update checker.
+	notifiedUpdates map[string]string
 }
 
 func init() {
@@ -90,6 +97,9 @@ func newManager(cfg *setting.Cfg) *PluginManager {
 		plugins:     map[string]*plugins.PluginBase{},
 		panels:      map[string]*plugins.PanelPlugin{},
 		apps:        map[string]*plugins.AppPlugin{},
+
+		pluginFingerprints: map[string]time.Time{},
+		notifiedUpdates:    map[string]string{},
 	}
 }
 
@@ -97,7 +107,7 @@ func (pm *PluginManager) Init() error {
 	pm.log = log.New("plugins")
 	plog = log.New("plugins")
 	pm.pluginScanningErrors = map[string]plugins.PluginError{}
-	pm.pluginInstaller = installer.New(false, pm.Cfg.BuildVersion, installerLog)
+	pm.pluginInstaller = pm.newPluginInstaller()
 
 	pm.log.Info("Starting plugin search")
 
@@ -127,6 +137,22 @@ func (pm *PluginManager) Init() error {
 	return nil
 }
 
+func (pm *PluginManager) newPluginInstaller() *installer.Installer {
+	i := installer.New(false, pm.Cfg.BuildVersion, installerLog)
+	i.SetRepoAuthToken(pm.pluginRepoURL(), pm.Cfg.PluginRepositoryAuthToken)
+	i.SetDependencyResolver(pm.resolveDependency)
+	return i
+}
+
+// pluginRepoURL returns the URL of the plugin repository that plugins are
+// installed from, which is grafana.com unless a private repository is configured.
+func (pm *PluginManager) pluginRepoURL() string {
+	if pm.Cfg.PluginRepositoryURL != "" {
+		return strings.TrimSuffix(pm.Cfg.PluginRepositoryURL, "/")
+	}
+	return grafanaComURL
+}
+
 func (pm *PluginManager) initExternalPlugins() error {
 	// check if plugins dir exists
 	exists, err := fs.Exists(pm.Cfg.PluginsPath)
@@ -188,6 +214,10 @@ func (pm *PluginManager) initExternalPlugins() error {
 func (pm *PluginManager) Run(ctx context.Context) error {
 	pm.checkForUpdates()
 
+	if pm.Cfg.PluginsHotReloadInterval > 0 {
+		go pm.runHotReload(ctx)
+	}
+
 	ticker := time.NewTicker(time.Minute * 10)
 	run := true
 
@@ -402,10 +432,18 @@ func (pm *PluginManager) scan(pluginDir string, requireSigned bool) error {
 		if signingError != nil {
 			pm.log.Debug("Failed to validate plugin signature. Will skip loading", "id", plugin.Id,
 				"signature", plugin.Signature, "status", signingError.ErrorCode)
+			signingError.PluginDir = plugin.PluginDir
 			pm.pluginScanningErrors[plugin.Id] = *signingError
 			continue
 		}
 
+		if versionError := pm.checkGrafanaVersion(plugin); versionError != nil {
+			pm.log.Warn("Skipping loading plugin", "id", plugin.Id, "reason", versionError.Message)
+			versionError.PluginDir = plugin.PluginDir
+			pm.pluginScanningErrors[plugin.Id] = *versionError
+			continue
+		}
+
 		pm.log.Debug("Attempting to add plugin", "id", plugin.Id)
 
 		pluginGoType, exists := pluginTypes[plugin.Type]
@@ -518,6 +556,9 @@ func (pm *PluginManager) loadPlugin(jsonParser *json.Decoder, pluginBase *plugin
 	pb.SignatureOrg = pluginBase.SignatureOrg
 
 	pm.plugins[pb.Id] = pb
+	if fingerprint, err := pluginFingerprint(pb.PluginDir); err == nil {
+		pm.pluginFingerprints[pb.Id] = fingerprint
+	}
 	pm.log.Debug("Successfully added plugin", "id", pb.Id)
 	return nil
 }
@@ -599,7 +640,7 @@ func (*PluginScanner) IsBackendOnlyPlugin(pluginType string) bool {
 func (s *PluginScanner) validateSignature(plugin *plugins.PluginBase) *plugins.PluginError {
 	if plugin.Signature == plugins.PluginSignatureValid {
 		s.log.Debug("Plugin has valid signature", "id", plugin.Id)
-		return nil
+		return s.validateSignatureType(plugin)
 	}
 
 	if plugin.Root != nil {
@@ -613,7 +654,7 @@ func (s *PluginScanner) validateSignature(plugin *plugins.PluginBase) *plugins.P
 			plugin.Signature = plugin.Root.Signature
 			if plugin.Signature == plugins.PluginSignatureValid {
 				s.log.Debug("Plugin has valid signature (inherited from root)", "id", plugin.Id)
-				return nil
+				return s.validateSignatureType(plugin)
 			}
 		}
 	} else {
@@ -625,9 +666,20 @@ func (s *PluginScanner) validateSignature(plugin *plugins.PluginBase) *plugins.P
 		return nil
 	}
 
+	policy := s.cfg.PluginSignaturePolicies[plugin.Id]
+	switch policy.Verification {
+	case setting.PluginSignatureSkip:
+		s.log.Debug("Skipping signature verification of plugin", "pluginID", plugin.Id, "signature", plugin.Signature)
+		return nil
+	case setting.PluginSignatureWarn:
+		s.log.Warn("Running a plugin without a valid signature", "pluginID", plugin.Id, "pluginDir",
+			plugin.PluginDir, "signature", plugin.Signature)
+		return nil
+	}
+
 	switch plugin.Signature {
 	case plugins.PluginSignatureUnsigned:
-		if allowed := s.allowUnsigned(plugin); !allowed {
+		if allowed := policy.Verification != setting.PluginSignatureRequire && s.allowUnsigned(plugin); !allowed {
 			s.log.Debug("Plugin is unsigned", "pluginID", plugin.Id)
 			s.errors = append(s.errors, fmt.Errorf("plugin '%s' is unsigned", plugin.Id))
 			return &plugins.PluginError{
@@ -654,6 +706,37 @@ func (s *PluginScanner) validateSignature(plugin *plugins.PluginBase) *plugins.P
 	}
 }
 
+// validateSignatureType validates that a plugin with a valid signature is signed
+// with one of the signature types configured for the plugin.
+func (s *PluginScanner) validateSignatureType(plugin *plugins.PluginBase) *plugins.PluginError {
+	policy := s.cfg.PluginSignaturePolicies[plugin.Id]
+	if !s.requireSigned || len(policy.Types) == 0 || policy.Verification == setting.PluginSignatureSkip {
+		return nil
+	}
+
+	signatureType := plugin.SignatureType
+	if signatureType == "" && plugin.Root != nil {
+		signatureType = plugin.Root.SignatureType
+	}
+	for _, t := range policy.Types {
+		if t == string(signatureType) {
+			return nil
+		}
+	}
+
+	if policy.Verification == setting.PluginSignatureWarn {
+		s.log.Warn("Running a plugin with an unexpected signature type", "pluginID", plugin.Id,
+			"signatureType", signatureType, "expected", policy.Types)
+		return nil
+	}
+
+	s.log.Debug("Plugin has an unexpected signature type", "pluginID", plugin.Id, "signatureType", signatureType)
+	s.errors = append(s.errors, fmt.Errorf("plugin '%s' has an unexpected signature type '%s'", plugin.Id, signatureType))
+	return &plugins.PluginError{
+		ErrorCode: signatureTypeMismatch,
+	}
+}
+
 func (s *PluginScanner) allowUnsigned(plugin *plugins.PluginBase) bool {
 	if s.allowUnsignedPluginsCondition != nil {
 		return s.allowUnsignedPluginsCondition(plugin)
@@ -679,6 +762,7 @@ func (pm *PluginManager) ScanningErrors() []plugins.PluginError {
 		scanningErrs = append(scanningErrs, plugins.PluginError{
 			ErrorCode: e.ErrorCode,
 			PluginID:  id,
+			Message:   e.Message,
 		})
 	}
 	return scanningErrs
@@ -725,6 +809,11 @@ func (pm *PluginManager) StaticRoutes() []*plugins.PluginStaticRoute {
 }
 
 func (pm *PluginManager) Install(ctx context.Context, pluginID, version string) error {
+	version, err := pm.pinnedInstallVersion(pluginID, version)
+	if err != nil {
+		return err
+	}
+
 	plugin := pm.GetPlugin(pluginID)
 
 	var pluginZipURL string
@@ -741,7 +830,7 @@ func (pm *PluginManager) Install(ctx context.Context, pluginID, version string)
 		}
 
 		// get plugin update information to confirm if upgrading is possible
-		updateInfo, err := pm.pluginInstaller.GetUpdateInfo(pluginID, version, grafanaComURL)
+		updateInfo, err := pm.pluginInstaller.GetUpdateInfo(pluginID, version, pm.pluginRepoURL())
 		if err != nil {
 			return err
 		}
@@ -755,17 +844,105 @@ func (pm *PluginManager) Install(ctx context.Context, pluginID, version string)
 		}
 	}
 
-	err := pm.pluginInstaller.Install(ctx, pluginID, version, pm.Cfg.PluginsPath, pluginZipURL, grafanaComURL)
+	err = pm.pluginInstaller.Install(ctx, pluginID, version, pm.Cfg.PluginsPath, pluginZipURL, pm.pluginRepoURL())
 	if err != nil {
 		return err
 	}
 
-	err = pm.initExternalPlugins()
+	err = pm.Reload(ctx)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return pm.verifyInstall(ctx, pluginID)
+}
+
+func (pm *PluginManager) InstallFromArchive(ctx context.Context, pluginID, archivePath string) error {
+	return pm.replacePlugin(ctx, pluginID, func() error {
+		return pm.pluginInstaller.InstallFromArchive(ctx, pluginID, archivePath, pm.Cfg.PluginsPath, pm.pluginRepoURL())
+	})
+}
+
+// InstallFromOCI installs a plugin packaged as an OCI artifact, referenced as
+// registry/repository:tag or registry/repository@digest.
+func (pm *PluginManager) InstallFromOCI(ctx context.Context, pluginID, ref string) error {
+	return pm.replacePlugin(ctx, pluginID, func() error {
+		return pm.pluginInstaller.InstallFromOCI(ctx, pluginID, ref, pm.Cfg.PluginsPath, pm.pluginRepoURL())
+	})
+}
+
+// replacePlugin removes the existing installation of a plugin, installs it with
+// install and loads it.
+func (pm *PluginManager) replacePlugin(ctx context.Context, pluginID string, install func() error) error {
+	plugin := pm.GetPlugin(pluginID)
+	if plugin != nil {
+		if plugin.IsCorePlugin {
+			return plugins.ErrInstallCorePlugin
+		}
+
+		// remove existing installation of plugin
+		err := pm.Uninstall(context.Background(), plugin.Id)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := install()
+	if err != nil {
+		return err
+	}
+
+	err = pm.Reload(ctx)
+	if err != nil {
+		return err
+	}
+
+	return pm.verifyInstall(ctx, pluginID)
+}
+
+// resolveDependency resolves the version of a plugin dependency to install. Core
+// plugins, and plugins installed with at least the required version, are not
+// installed again. Dependencies are installed with the required version, which
+// must satisfy the pinned version of the dependency.
+func (pm *PluginManager) resolveDependency(pluginID, requiredVersion string) (string, bool, error) {
+	if plugin := pm.GetPlugin(pluginID); plugin != nil {
+		if plugin.IsCorePlugin || satisfiesDependencyVersion(plugin.Info.Version, requiredVersion) {
+			return "", false, nil
+		}
+	}
+
+	version, err := pm.pinnedInstallVersion(pluginID, requiredVersion)
+	if err != nil {
+		return "", false, err
+	}
+	return version, true, nil
+}
+
+// verifyInstall checks that a freshly installed plugin has been loaded. A plugin that
+// failed the signature validation, or isn't compatible with Grafana, is removed again,
+// rather than being left behind in the plugins directory.
+func (pm *PluginManager) verifyInstall(ctx context.Context, pluginID string) error {
+	if pm.GetPlugin(pluginID) != nil {
+		delete(pm.pluginScanningErrors, pluginID)
+		return nil
+	}
+
+	loadError, exists := pm.pluginScanningErrors[pluginID]
+	if !exists {
+		return fmt.Errorf("plugin %q was installed but could not be loaded", pluginID)
+	}
+	delete(pm.pluginScanningErrors, pluginID)
+
+	if loadError.PluginDir != "" {
+		if err := pm.pluginInstaller.Uninstall(ctx, loadError.PluginDir); err != nil {
+			pm.log.Warn("Failed to remove plugin that could not be loaded", "id", pluginID, "err", err)
+		}
+	}
+
+	if !isSignatureError(loadError.ErrorCode) {
+		return fmt.Errorf("plugin %q was installed but could not be loaded: %s", pluginID, loadError.Message)
+	}
+	return plugins.PluginSignatureError{PluginID: pluginID, ErrorCode: loadError.ErrorCode}
 }
 
 func (pm *PluginManager) Uninstall(ctx context.Context, pluginID string) error {
@@ -784,14 +961,7 @@ func (pm *PluginManager) Uninstall(ctx context.Context, pluginID string) error {
 		return plugins.ErrUninstallOutsideOfPluginDir
 	}
 
-	if pm.BackendPluginManager.IsRegistered(pluginID) {
-		err := pm.BackendPluginManager.UnregisterAndStop(ctx, pluginID)
-		if err != nil {
-			return err
-		}
-	}
-
-	err = pm.unregister(plugin)
+	err = pm.unload(ctx, plugin)
 	if err != nil {
 		return err
 	}
@@ -815,6 +985,7 @@ func (pm *PluginManager) unregister(plugin *plugins.PluginBase) error {
 	}
 
 	delete(pm.plugins, plugin.Id)
+	delete(pm.pluginFingerprints, plugin.Id)
 
 	pm.removeStaticRoute(plugin.Id)
 
@@ -1,17 +1,22 @@
 package manager
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/plugins/backendplugin"
@@ -371,6 +376,81 @@ func TestPluginManager_Init(t *testing.T) {
 		assert.Nil(t, pm.plugins[("test")])
 	})
 
+	t.Run("With unsigned plugin and signature verification policy", func(t *testing.T) {
+		for _, tc := range []struct {
+			verification setting.PluginSignatureVerification
+			allowed      bool
+		}{
+			{verification: setting.PluginSignatureWarn, allowed: true},
+			{verification: setting.PluginSignatureSkip, allowed: true},
+			{verification: setting.PluginSignatureRequire, allowed: false},
+		} {
+			pm := createManager(t, func(pm *PluginManager) {
+				pm.Cfg.PluginsPath = "testdata/unsigned-datasource"
+				pm.Cfg.PluginsAllowUnsigned = []string{"test"}
+				pm.Cfg.PluginSignaturePolicies = map[string]setting.PluginSignaturePolicy{
+					"test": {Verification: tc.verification},
+				}
+			})
+			err := pm.Init()
+			require.NoError(t, err)
+
+			if tc.allowed {
+				assert.Empty(t, pm.scanningErrors, tc.verification)
+				assert.NotNil(t, pm.GetPlugin("test"), tc.verification)
+			} else {
+				assert.Equal(t, []error{fmt.Errorf(`plugin 'test' is unsigned`)}, pm.scanningErrors, tc.verification)
+				assert.Nil(t, pm.GetPlugin("test"), tc.verification)
+			}
+		}
+	})
+
+	t.Run("With modified plugin and warn signature verification policy", func(t *testing.T) {
+		origAppURL := setting.AppUrl
+		t.Cleanup(func() {
+			setting.AppUrl = origAppURL
+		})
+		setting.AppUrl = defaultAppURL
+
+		pm := createManager(t, func(pm *PluginManager) {
+			pm.Cfg.PluginsPath = "testdata/invalid-v2-signature"
+			pm.Cfg.PluginSignaturePolicies = map[string]setting.PluginSignaturePolicy{
+				"test": {Verification: setting.PluginSignatureWarn},
+			}
+		})
+		err := pm.Init()
+		require.NoError(t, err)
+		assert.Empty(t, pm.scanningErrors)
+		assert.NotNil(t, pm.GetPlugin("test"))
+	})
+
+	t.Run("With plugin signed with an unexpected signature type", func(t *testing.T) {
+		pm := createManager(t, func(pm *PluginManager) {
+			pm.Cfg.PluginsPath = "testdata/valid-v2-signature"
+			pm.Cfg.PluginSignaturePolicies = map[string]setting.PluginSignaturePolicy{
+				"test": {Types: []string{"private"}},
+			}
+		})
+		err := pm.Init()
+		require.NoError(t, err)
+		assert.Equal(t, []error{fmt.Errorf(`plugin 'test' has an unexpected signature type 'grafana'`)}, pm.scanningErrors)
+		assert.Equal(t, []plugins.PluginError{{ErrorCode: signatureTypeMismatch, PluginID: "test"}}, pm.ScanningErrors())
+		assert.Nil(t, pm.GetPlugin("test"))
+	})
+
+	t.Run("With plugin signed with an expected signature type", func(t *testing.T) {
+		pm := createManager(t, func(pm *PluginManager) {
+			pm.Cfg.PluginsPath = "testdata/valid-v2-signature"
+			pm.Cfg.PluginSignaturePolicies = map[string]setting.PluginSignaturePolicy{
+				"test": {Types: []string{"commercial", "grafana"}},
+			}
+		})
+		err := pm.Init()
+		require.NoError(t, err)
+		assert.Empty(t, pm.scanningErrors)
+		assert.NotNil(t, pm.GetPlugin("test"))
+	})
+
 	t.Run("With plugin that contains symlink file + directory", func(t *testing.T) {
 		origAppURL := setting.AppUrl
 		t.Cleanup(func() {
@@ -523,12 +603,21 @@ func TestPluginManager_Installer(t *testing.T) {
 			}, err)
 		})
 
+		t.Run("Install from archive replaces existing installation", func(t *testing.T) {
+			err := pm.InstallFromArchive(context.Background(), pluginID, "plugin.zip")
+			require.NoError(t, err)
+
+			assert.Equal(t, 2, installer.installCount)
+			assert.Equal(t, 1, installer.uninstallCount)
+			assert.NotNil(t, pm.GetPlugin(pluginID))
+		})
+
 		t.Run("Uninstall base case", func(t *testing.T) {
 			err := pm.Uninstall(context.Background(), pluginID)
 			require.NoError(t, err)
 
-			assert.Equal(t, 1, installer.installCount)
-			assert.Equal(t, 1, installer.uninstallCount)
+			assert.Equal(t, 2, installer.installCount)
+			assert.Equal(t, 2, installer.uninstallCount)
 
 			assert.Nil(t, pm.GetDataSource(pluginID))
 			assert.Nil(t, pm.GetPlugin(pluginID))
@@ -540,6 +629,248 @@ func TestPluginManager_Installer(t *testing.T) {
 			})
 		})
 	})
+	t.Run("Install removes plugin with invalid signature", func(t *testing.T) {
+		pm := createManager(t)
+		err := pm.Init()
+		require.NoError(t, err)
+
+		installer := &fakePluginInstaller{}
+		pm.pluginInstaller = installer
+		pm.Cfg.PluginsPath = "testdata/unsigned-datasource"
+
+		err = pm.Install(context.Background(), "test", "1.0.0")
+		require.Equal(t, plugins.PluginSignatureError{PluginID: "test", ErrorCode: signatureMissing}, err)
+
+		assert.Equal(t, 1, installer.installCount)
+		assert.Equal(t, []string{"testdata/unsigned-datasource/plugin"}, installer.uninstalledPaths)
+		assert.Nil(t, pm.GetPlugin("test"))
+		assert.Empty(t, pm.ScanningErrors())
+	})
+	t.Run("Install respects pinned version", func(t *testing.T) {
+		pm := createManager(t)
+		err := pm.Init()
+		require.NoError(t, err)
+
+		installer := &fakePluginInstaller{}
+		pm.pluginInstaller = installer
+		pm.Cfg.PluginsPath = "testdata/installer"
+		pm.Cfg.PluginVersionPins = map[string]string{"test": "~> 1.0.0", "other": "2.0.0"}
+
+		err = pm.Install(context.Background(), "test", "1.1.0")
+		require.Equal(t, plugins.PluginVersionPinnedError{PluginID: "test", Version: "1.1.0", PinnedVersion: "~> 1.0.0"}, err)
+
+		err = pm.Install(context.Background(), "test", "")
+		require.Equal(t, plugins.PluginVersionPinnedError{PluginID: "test", PinnedVersion: "~> 1.0.0"}, err)
+		assert.Equal(t, 0, installer.installCount)
+
+		version, err := pm.pinnedInstallVersion("other", "")
+		require.NoError(t, err)
+		assert.Equal(t, "2.0.0", version)
+
+		err = pm.Install(context.Background(), "test", "1.0.0")
+		require.NoError(t, err)
+		assert.Equal(t, 1, installer.installCount)
+	})
+	t.Run("Resolves plugin dependencies", func(t *testing.T) {
+		pm := createManager(t)
+		err := pm.Init()
+		require.NoError(t, err)
+
+		pm.Cfg.PluginVersionPins = map[string]string{"pinned-panel": "~> 1.0.0"}
+		pm.plugins["installed-panel"] = &plugins.PluginBase{Id: "installed-panel", Info: plugins.PluginInfo{Version: "1.2.0"}}
+
+		t.Run("Skips core plugins", func(t *testing.T) {
+			_, install, err := pm.resolveDependency("graph", "1.0.0")
+			require.NoError(t, err)
+			assert.False(t, install)
+		})
+
+		t.Run("Skips plugins installed with the required version", func(t *testing.T) {
+			_, install, err := pm.resolveDependency("installed-panel", "1.1.0")
+			require.NoError(t, err)
+			assert.False(t, install)
+		})
+
+		t.Run("Updates plugins installed with an older version", func(t *testing.T) {
+			version, install, err := pm.resolveDependency("installed-panel", "2.0.0")
+			require.NoError(t, err)
+			assert.True(t, install)
+			assert.Equal(t, "2.0.0", version)
+		})
+
+		t.Run("Installs missing plugins with the required version", func(t *testing.T) {
+			version, install, err := pm.resolveDependency("pinned-panel", "1.0.1")
+			require.NoError(t, err)
+			assert.True(t, install)
+			assert.Equal(t, "1.0.1", version)
+		})
+
+		t.Run("Returns an error when the required version conflicts with the pinned version", func(t *testing.T) {
+			_, _, err := pm.resolveDependency("pinned-panel", "1.1.0")
+			require.Equal(t, plugins.PluginVersionPinnedError{PluginID: "pinned-panel", Version: "1.1.0", PinnedVersion: "~> 1.0.0"}, err)
+		})
+	})
+}
+
+func TestPluginManager_PluginErrors(t *testing.T) {
+	t.Run("With plugin requiring a newer Grafana version", func(t *testing.T) {
+		pm := createManager(t, func(pm *PluginManager) {
+			pm.Cfg.PluginsPath = "testdata/incompatible-grafana-version"
+			pm.Cfg.PluginsAllowUnsigned = []string{"test"}
+			pm.Cfg.BuildVersion = "8.1.0"
+		})
+		err := pm.Init()
+		require.NoError(t, err)
+
+		assert.Nil(t, pm.GetPlugin("test"))
+		assert.Equal(t, []plugins.PluginError{{
+			ErrorCode: grafanaVersionIncompatible,
+			PluginID:  "test",
+			Message:   "plugin requires Grafana >=8.2.0, but Grafana is version 8.1.0",
+		}}, pm.PluginErrors())
+	})
+
+	t.Run("With plugin requiring the Grafana version of a pre-release", func(t *testing.T) {
+		pm := createManager(t, func(pm *PluginManager) {
+			pm.Cfg.PluginsPath = "testdata/incompatible-grafana-version"
+			pm.Cfg.PluginsAllowUnsigned = []string{"test"}
+			pm.Cfg.BuildVersion = "8.2.0-beta1"
+		})
+		err := pm.Init()
+		require.NoError(t, err)
+
+		assert.NotNil(t, pm.GetPlugin("test"))
+		assert.Empty(t, pm.PluginErrors())
+	})
+
+	t.Run("With backend plugins missing their executable or with a crashed process", func(t *testing.T) {
+		fm := &fakeBackendPluginManager{statuses: map[string]backendplugin.PluginStatus{
+			"crashed": {State: backendplugin.PluginStateExited, LastError: "exit status 2"},
+			"running": {State: backendplugin.PluginStateRunning},
+		}}
+		pm := createManager(t, func(pm *PluginManager) {
+			pm.BackendPluginManager = fm
+		})
+		pm.log = log.New("test")
+
+		for _, pluginID := range []string{"crashed", "running", "missing"} {
+			pluginDir := filepath.Join(t.TempDir(), pluginID)
+			require.NoError(t, os.MkdirAll(pluginDir, 0750))
+			if pluginID != "missing" {
+				executable := filepath.Join(pluginDir, plugins.ComposePluginStartCommand("gpx_test"))
+				require.NoError(t, ioutil.WriteFile(executable, nil, 0600))
+			}
+			pm.dataSources[pluginID] = &plugins.DataSourcePlugin{
+				FrontendPluginBase: plugins.FrontendPluginBase{
+					PluginBase: plugins.PluginBase{Id: pluginID, PluginDir: pluginDir},
+				},
+				Backend:    true,
+				Executable: "gpx_test",
+			}
+		}
+
+		assert.Equal(t, []plugins.PluginError{
+			{ErrorCode: processCrashed, PluginID: "crashed", Message: "exit status 2"},
+			{ErrorCode: executableMissing, PluginID: "missing", Message: "executable " + plugins.ComposePluginStartCommand("gpx_test") + " not found"},
+		}, pm.PluginErrors())
+	})
+}
+
+func TestPluginManager_updatePluginVersions(t *testing.T) {
+	pm := createManager(t, func(pm *PluginManager) {
+		pm.Cfg.PluginVersionPins = map[string]string{"pinned": "< 2.0"}
+	})
+	pm.log = log.New("test")
+	pm.plugins = map[string]*plugins.PluginBase{
+		"outdated": {Id: "outdated", Info: plugins.PluginInfo{Version: "1.0.0"}},
+		"latest":   {Id: "latest", Info: plugins.PluginInfo{Version: "1.2.0"}},
+		"pinned":   {Id: "pinned", Info: plugins.PluginInfo{Version: "1.0.0"}},
+	}
+
+	pm.updatePluginVersions([]grafanaNetPlugin{
+		{Slug: "outdated", Version: "1.1.0"},
+		{Slug: "latest", Version: "1.2.0"},
+		{Slug: "pinned", Version: "2.0.0"},
+	})
+
+	assert.Equal(t, "1.1.0", pm.plugins["outdated"].GrafanaNetVersion)
+	assert.True(t, pm.plugins["outdated"].GrafanaNetHasUpdate)
+	assert.False(t, pm.plugins["latest"].GrafanaNetHasUpdate)
+	assert.Equal(t, "2.0.0", pm.plugins["pinned"].GrafanaNetVersion)
+	assert.False(t, pm.plugins["pinned"].GrafanaNetHasUpdate)
+	assert.Equal(t, map[string]string{"outdated": "1.1.0"}, pm.notifiedUpdates)
+}
+
+func TestPluginManager_Reload(t *testing.T) {
+	pluginsDir := t.TempDir()
+	fm := &fakeBackendPluginManager{}
+	pm := createManager(t, func(pm *PluginManager) {
+		pm.Cfg.PluginsPath = pluginsDir
+		pm.Cfg.PluginsAllowUnsigned = []string{"test"}
+		pm.BackendPluginManager = fm
+	})
+	err := pm.Init()
+	require.NoError(t, err)
+	require.Nil(t, pm.GetPlugin("test"))
+
+	pluginDir := filepath.Join(pluginsDir, "test")
+	writePluginJSON := func(version string, modTime time.Time) {
+		t.Helper()
+
+		pluginJSON, err := ioutil.ReadFile("testdata/unsigned-datasource/plugin/plugin.json")
+		require.NoError(t, err)
+		pluginJSON = bytes.Replace(pluginJSON, []byte(`"description": "Test",`),
+			[]byte(fmt.Sprintf(`"description": "Test", "version": %q,`, version)), 1)
+
+		require.NoError(t, os.MkdirAll(pluginDir, 0750))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(pluginDir, "plugin.json"), pluginJSON, 0600))
+		require.NoError(t, os.Chtimes(filepath.Join(pluginDir, "plugin.json"), modTime, modTime))
+	}
+
+	t.Run("Loads added plugins", func(t *testing.T) {
+		writePluginJSON("1.0.0", time.Now().Add(-time.Hour))
+
+		err := pm.Reload(context.Background())
+		require.NoError(t, err)
+
+		plugin := pm.GetPlugin("test")
+		require.NotNil(t, plugin)
+		assert.Equal(t, "1.0.0", plugin.Info.Version)
+		assert.Equal(t, []string{"test"}, fm.registeredPlugins)
+	})
+
+	t.Run("Keeps unchanged plugins", func(t *testing.T) {
+		plugin := pm.GetPlugin("test")
+
+		err := pm.Reload(context.Background())
+		require.NoError(t, err)
+
+		assert.Same(t, plugin, pm.GetPlugin("test"))
+	})
+
+	t.Run("Reloads changed plugins", func(t *testing.T) {
+		writePluginJSON("1.1.0", time.Now().Add(time.Hour))
+
+		err := pm.Reload(context.Background())
+		require.NoError(t, err)
+
+		plugin := pm.GetPlugin("test")
+		require.NotNil(t, plugin)
+		assert.Equal(t, "1.1.0", plugin.Info.Version)
+		assert.Equal(t, []string{"test"}, fm.registeredPlugins)
+	})
+
+	t.Run("Unloads removed plugins", func(t *testing.T) {
+		require.NoError(t, os.RemoveAll(pluginDir))
+
+		err := pm.Reload(context.Background())
+		require.NoError(t, err)
+
+		assert.Nil(t, pm.GetPlugin("test"))
+		assert.Nil(t, pm.GetDataSource("test"))
+		assert.Empty(t, fm.registeredPlugins)
+		assert.NotNil(t, pm.GetDataSource("graphite"))
+	})
 }
 
 func verifyCorePluginCatalogue(t *testing.T, pm *PluginManager) {
@@ -628,6 +959,7 @@ func verifyBundledPlugins(t *testing.T, pm *PluginManager) {
 
 type fakeBackendPluginManager struct {
 	registeredPlugins []string
+	statuses          map[string]backendplugin.PluginStatus
 }
 
 func (f *fakeBackendPluginManager) Register(pluginID string, factory backendplugin.PluginFactoryFunc) error {
@@ -635,6 +967,11 @@ func (f *fakeBackendPluginManager) Register(pluginID string, factory backendplug
 	return nil
 }
 
+func (f *fakeBackendPluginManager) RegisterInstance(pluginID, instanceID string, factory backendplugin.PluginFactoryFunc) error {
+	f.registeredPlugins = append(f.registeredPlugins, instanceID)
+	return nil
+}
+
 func (f *fakeBackendPluginManager) RegisterAndStart(ctx context.Context, pluginID string, factory backendplugin.PluginFactoryFunc) error {
 	f.registeredPlugins = append(f.registeredPlugins, pluginID)
 	return nil
@@ -644,6 +981,15 @@ func (f *fakeBackendPluginManager) Get(pluginID string) (backendplugin.Plugin, b
 	return nil, false
 }
 
+func (f *fakeBackendPluginManager) SetLogLevel(pluginID, level string) error {
+	return nil
+}
+
+func (f *fakeBackendPluginManager) Status(pluginID string) (backendplugin.PluginStatus, bool) {
+	status, ok := f.statuses[pluginID]
+	return status, ok
+}
+
 func (f *fakeBackendPluginManager) UnregisterAndStop(ctx context.Context, pluginID string) error {
 	var result []string
 
@@ -685,11 +1031,28 @@ func (f *fakeBackendPluginManager) QueryData(ctx context.Context, req *backend.Q
 func (f *fakeBackendPluginManager) CallResource(pluginConfig backend.PluginContext, ctx *models.ReqContext, path string) {
 }
 
+func (f *fakeBackendPluginManager) SubscribeStream(ctx context.Context, req *backend.SubscribeStreamRequest) (*backend.SubscribeStreamResponse, error) {
+	return nil, nil
+}
+
+func (f *fakeBackendPluginManager) ValidateSettings(ctx context.Context, pCtx backend.PluginContext) (*backendplugin.ValidateSettingsResult, error) {
+	return nil, backendplugin.ErrMethodNotImplemented
+}
+
+func (f *fakeBackendPluginManager) PublishStream(ctx context.Context, req *backend.PublishStreamRequest) (*backend.PublishStreamResponse, error) {
+	return nil, nil
+}
+
+func (f *fakeBackendPluginManager) RunStream(ctx context.Context, req *backend.RunStreamRequest, sender *backend.StreamSender) error {
+	return nil
+}
+
 var _ backendplugin.Manager = &fakeBackendPluginManager{}
 
 type fakePluginInstaller struct {
-	installCount   int
-	uninstallCount int
+	installCount     int
+	uninstallCount   int
+	uninstalledPaths []string
 }
 
 func (f *fakePluginInstaller) Install(ctx context.Context, pluginID, version, pluginsDirectory, pluginZipURL, pluginRepoURL string) error {
@@ -697,8 +1060,19 @@ func (f *fakePluginInstaller) Install(ctx context.Context, pluginID, version, pl
 	return nil
 }
 
+func (f *fakePluginInstaller) InstallFromArchive(ctx context.Context, pluginID, archivePath, pluginsDirectory, pluginRepoURL string) error {
+	f.installCount++
+	return nil
+}
+
+func (f *fakePluginInstaller) InstallFromOCI(ctx context.Context, pluginID, ref, pluginsDirectory, pluginRepoURL string) error {
+	f.installCount++
+	return nil
+}
+
 func (f *fakePluginInstaller) Uninstall(ctx context.Context, pluginPath string) error {
 	f.uninstallCount++
+	f.uninstalledPaths = append(f.uninstalledPaths, pluginPath)
 	return nil
 }
 
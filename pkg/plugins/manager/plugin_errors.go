@@ -0,0 +1,119 @@
+package manager
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/grafana/grafana/pkg/infra/fs"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/backendplugin"
+)
+
+// PluginErrors returns the errors of the plugins that failed to load, and of the
+// external backend plugins that are missing their executable or whose process
+// exited or failed to start, sorted by plugin ID.
+func (pm *PluginManager) PluginErrors() []plugins.PluginError {
+	pluginErrors := pm.ScanningErrors()
+
+	for pluginID, executable := range pm.backendExecutables() {
+		exists, err := fs.Exists(executable)
+		if err != nil {
+			pm.log.Warn("Failed to check plugin executable", "id", pluginID, "err", err)
+		} else if !exists {
+			pluginErrors = append(pluginErrors, plugins.PluginError{
+				ErrorCode: executableMissing,
+				PluginID:  pluginID,
+				Message:   fmt.Sprintf("executable %s not found", filepath.Base(executable)),
+			})
+			continue
+		}
+
+		status, ok := pm.BackendPluginManager.Status(pluginID)
+		if !ok || (status.State != backendplugin.PluginStateExited && status.State != backendplugin.PluginStateFailed) {
+			continue
+		}
+		message := status.LastError
+		if message == "" {
+			message = fmt.Sprintf("plugin process %s", status.State)
+		}
+		pluginErrors = append(pluginErrors, plugins.PluginError{
+			ErrorCode: processCrashed,
+			PluginID:  pluginID,
+			Message:   message,
+		})
+	}
+
+	sort.Slice(pluginErrors, func(i, j int) bool {
+		return pluginErrors[i].PluginID < pluginErrors[j].PluginID
+	})
+	return pluginErrors
+}
+
+// backendExecutables returns the paths of the executables of the loaded external
+// backend plugins for the current OS and architecture, by plugin ID.
+func (pm *PluginManager) backendExecutables() map[string]string {
+	pm.pluginsMu.RLock()
+	defer pm.pluginsMu.RUnlock()
+
+	executables := map[string]string{}
+	add := func(pb *plugins.PluginBase, executable string) {
+		if pb.IsCorePlugin || strings.HasPrefix(pb.PluginDir, pm.Cfg.StaticRootPath) {
+			return
+		}
+		executables[pb.Id] = filepath.Join(pb.PluginDir, plugins.ComposePluginStartCommand(executable))
+	}
+
+	for _, ds := range pm.dataSources {
+		if ds.Backend {
+			add(&ds.PluginBase, ds.Executable)
+		}
+	}
+	for _, app := range pm.apps {
+		if app.Backend {
+			add(&app.PluginBase, app.Executable)
+		}
+	}
+	if pm.renderer != nil {
+		add(&pm.renderer.PluginBase, "plugin_start")
+	}
+	return executables
+}
+
+// checkGrafanaVersion returns an error when the version of Grafana doesn't satisfy
+// the grafanaDependency of an external plugin. Pre-releases of Grafana satisfy the
+// dependencies on the version they precede.
+func (pm *PluginManager) checkGrafanaVersion(plugin *plugins.PluginBase) *plugins.PluginError {
+	dependency := plugin.Dependencies.GrafanaDependency
+	if dependency == "" || strings.HasPrefix(plugin.PluginDir, pm.Cfg.StaticRootPath) {
+		return nil
+	}
+
+	constraint, err := semver.NewConstraint(dependency)
+	if err != nil {
+		pm.log.Warn("Plugin has an invalid grafanaDependency", "id", plugin.Id, "grafanaDependency", dependency, "err", err)
+		return nil
+	}
+	version, err := semver.NewVersion(pm.Cfg.BuildVersion)
+	if err != nil {
+		return nil
+	}
+	if version.Prerelease() != "" {
+		release, err := version.SetPrerelease("")
+		if err != nil {
+			return nil
+		}
+		version = &release
+	}
+
+	if constraint.Check(version) {
+		return nil
+	}
+	return &plugins.PluginError{
+		ErrorCode: grafanaVersionIncompatible,
+		PluginID:  plugin.Id,
+		Message:   fmt.Sprintf("plugin requires Grafana %s, but Grafana is version %s", dependency, pm.Cfg.BuildVersion),
+	}
+}
@@ -0,0 +1,133 @@
+package manager
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/plugins"
+)
+
+// Reload unloads the external plugins that were removed from or changed on disk,
+// and loads the plugins that were added or changed. The backend processes of
+// reloaded plugins are restarted.
+func (pm *PluginManager) Reload(ctx context.Context) error {
+	pm.reloadMu.Lock()
+	defer pm.reloadMu.Unlock()
+
+	for _, p := range pm.Plugins() {
+		// the renderer plugin is started by the rendering service
+		if !pm.isExternalPlugin(p) || p.Type == "renderer" {
+			continue
+		}
+
+		fingerprint, err := pluginFingerprint(p.PluginDir)
+		if err == nil && fingerprint.Equal(pm.getPluginFingerprint(p.Id)) {
+			continue
+		}
+
+		if os.IsNotExist(err) {
+			pm.log.Info("Unloading removed plugin", "id", p.Id)
+		} else {
+			pm.log.Info("Reloading changed plugin", "id", p.Id)
+		}
+		if err := pm.unload(ctx, p); err != nil {
+			pm.log.Error("Failed to unload plugin", "id", p.Id, "err", err)
+		}
+	}
+
+	for id, e := range pm.pluginScanningErrors {
+		if _, err := os.Stat(e.PluginDir); os.IsNotExist(err) {
+			delete(pm.pluginScanningErrors, id)
+		}
+	}
+
+	return pm.initExternalPlugins()
+}
+
+// runHotReload reloads the external plugins every hot_reload_interval.
+func (pm *PluginManager) runHotReload(ctx context.Context) {
+	ticker := time.NewTicker(pm.Cfg.PluginsHotReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := pm.Reload(ctx); err != nil {
+				pm.log.Error("Failed to reload plugins", "err", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// unload stops the backend process of a plugin and unregisters it, leaving
+// its files in place.
+func (pm *PluginManager) unload(ctx context.Context, plugin *plugins.PluginBase) error {
+	if pm.BackendPluginManager.IsRegistered(plugin.Id) {
+		if err := pm.BackendPluginManager.UnregisterAndStop(ctx, plugin.Id); err != nil {
+			return err
+		}
+	}
+
+	return pm.unregister(plugin)
+}
+
+// isExternalPlugin returns whether a plugin was loaded from the plugins directory
+// or from the path of a plugin setting, which are the directories that are scanned
+// again when reloading plugins.
+func (pm *PluginManager) isExternalPlugin(plugin *plugins.PluginBase) bool {
+	if plugin.IsCorePlugin {
+		return false
+	}
+
+	dirs := []string{pm.Cfg.PluginsPath}
+	for _, settings := range pm.Cfg.PluginSettings {
+		if path := settings["path"]; path != "" {
+			dirs = append(dirs, path)
+		}
+	}
+
+	for _, dir := range dirs {
+		rel, err := filepath.Rel(dir, plugin.PluginDir)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (pm *PluginManager) getPluginFingerprint(pluginID string) time.Time {
+	pm.pluginsMu.RLock()
+	defer pm.pluginsMu.RUnlock()
+
+	return pm.pluginFingerprints[pluginID]
+}
+
+// pluginFingerprint returns the latest modification time of the files in the
+// top level of a plugin directory, which is where the plugin.json file, the
+// frontend module and the backend executables are replaced when the plugin is
+// updated.
+func pluginFingerprint(pluginDir string) (time.Time, error) {
+	dir, err := os.Stat(pluginDir)
+	if err != nil {
+		return time.Time{}, err
+	}
+	files, err := ioutil.ReadDir(pluginDir)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// the directory itself is modified when files are added or removed
+	latest := dir.ModTime()
+	for _, f := range files {
+		if f.ModTime().After(latest) {
+			latest = f.ModTime()
+		}
+	}
+	return latest, nil
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/hashicorp/go-version"
 )
@@ -39,6 +40,70 @@ func (pm *PluginManager) getAllExternalPluginSlugs() string {
 	return strings.Join(result, ",")
 }
 
+// updatePluginVersions sets the latest versions of the installed plugins in the
+// plugin repository, and logs the updates that weren't available at the
+// previous check. Updates that don't satisfy the pinned version of a plugin
+// aren't offered.
+func (pm *PluginManager) updatePluginVersions(gNetPlugins []grafanaNetPlugin) {
+	for _, plug := range pm.Plugins() {
+		for _, gplug := range gNetPlugins {
+			if gplug.Slug == plug.Id {
+				plug.GrafanaNetVersion = gplug.Version
+
+				plugVersion, err1 := version.NewVersion(plug.Info.Version)
+				gplugVersion, err2 := version.NewVersion(gplug.Version)
+
+				if err1 != nil || err2 != nil {
+					plug.GrafanaNetHasUpdate = plug.Info.Version != plug.GrafanaNetVersion
+				} else {
+					plug.GrafanaNetHasUpdate = plugVersion.LessThan(gplugVersion)
+				}
+
+				if pin, ok := pm.Cfg.PluginVersionPins[plug.Id]; ok && !satisfiesVersionPin(gplug.Version, pin) {
+					plug.GrafanaNetHasUpdate = false
+				}
+
+				if plug.GrafanaNetHasUpdate && pm.notifiedUpdates[plug.Id] != gplug.Version {
+					pm.log.Info("Plugin update available", "pluginId", plug.Id, "version", plug.Info.Version,
+						"latestVersion", gplug.Version)
+					pm.notifiedUpdates[plug.Id] = gplug.Version
+				}
+			}
+		}
+	}
+}
+
+// pinnedInstallVersion returns the version of a plugin to install, which must
+// satisfy the pinned_version setting of the plugin. When no version is
+// requested, the pinned version is installed if it's an exact version.
+func (pm *PluginManager) pinnedInstallVersion(pluginID, requested string) (string, error) {
+	pin, ok := pm.Cfg.PluginVersionPins[pluginID]
+	if !ok {
+		return requested, nil
+	}
+
+	if requested == "" {
+		if _, err := version.NewVersion(pin); err == nil {
+			return pin, nil
+		}
+	} else if satisfiesVersionPin(requested, pin) {
+		return requested, nil
+	}
+
+	return "", plugins.PluginVersionPinnedError{PluginID: pluginID, Version: requested, PinnedVersion: pin}
+}
+
+// satisfiesVersionPin returns whether a plugin version satisfies a pinned
+// version constraint.
+func satisfiesVersionPin(v, pin string) bool {
+	constraint, err := version.NewConstraint(pin)
+	if err != nil {
+		return false
+	}
+	parsed, err := version.NewVersion(v)
+	return err == nil && constraint.Check(parsed)
+}
+
 func (pm *PluginManager) checkForUpdates() {
 	if !pm.Cfg.CheckForUpdates {
 		return
@@ -47,7 +112,15 @@ func (pm *PluginManager) checkForUpdates() {
 	pm.log.Debug("Checking for updates")
 
 	pluginSlugs := pm.getAllExternalPluginSlugs()
-	resp, err := httpClient.Get("https://grafana.com/api/plugins/versioncheck?slugIn=" + pluginSlugs + "&grafanaVersion=" + setting.BuildVersion)
+	req, err := http.NewRequest(http.MethodGet, pm.pluginRepoURL()+"/versioncheck?slugIn="+pluginSlugs+"&grafanaVersion="+setting.BuildVersion, nil)
+	if err != nil {
+		log.Tracef("Failed to create plugins repo request, %v", err.Error())
+		return
+	}
+	if pm.Cfg.PluginRepositoryAuthToken != "" {
+		req.Header.Set("Authorization", "Bearer "+pm.Cfg.PluginRepositoryAuthToken)
+	}
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		log.Tracef("Failed to get plugins repo from grafana.com, %v", err.Error())
 		return
@@ -71,22 +144,7 @@ func (pm *PluginManager) checkForUpdates() {
 		return
 	}
 
-	for _, plug := range pm.Plugins() {
-		for _, gplug := range gNetPlugins {
-			if gplug.Slug == plug.Id {
-				plug.GrafanaNetVersion = gplug.Version
-
-				plugVersion, err1 := version.NewVersion(plug.Info.Version)
-				gplugVersion, err2 := version.NewVersion(gplug.Version)
-
-				if err1 != nil || err2 != nil {
-					plug.GrafanaNetHasUpdate = plug.Info.Version != plug.GrafanaNetVersion
-				} else {
-					plug.GrafanaNetHasUpdate = plugVersion.LessThan(gplugVersion)
-				}
-			}
-		}
-	}
+	pm.updatePluginVersions(gNetPlugins)
 
 	resp2, err := httpClient.Get("https://raw.githubusercontent.com/grafana/grafana/main/latest.json")
 	if err != nil {
@@ -125,3 +183,17 @@ func (pm *PluginManager) checkForUpdates() {
 		pm.grafanaHasUpdate = currVersion.LessThan(latestVersion)
 	}
 }
+
+// satisfiesDependencyVersion returns whether an installed plugin version is at
+// least the version required by a plugin that depends on it.
+func satisfiesDependencyVersion(v, required string) bool {
+	if required == "" || v == required {
+		return true
+	}
+	parsed, err := version.NewVersion(v)
+	if err != nil {
+		return false
+	}
+	requiredVersion, err := version.NewVersion(required)
+	return err == nil && !parsed.LessThan(requiredVersion)
+}
@@ -44,6 +44,32 @@ func (e DuplicatePluginError) Is(err error) bool {
 	return ok
 }
 
+// PluginSignatureError is returned when an installed plugin is rejected because
+// its signature couldn't be validated.
+type PluginSignatureError struct {
+	PluginID  string
+	ErrorCode ErrorCode
+}
+
+func (e PluginSignatureError) Error() string {
+	return fmt.Sprintf("plugin '%s' failed signature validation: %s", e.PluginID, e.ErrorCode)
+}
+
+// PluginVersionPinnedError is returned when installing a version of a plugin
+// that doesn't satisfy its pinned_version setting.
+type PluginVersionPinnedError struct {
+	PluginID      string
+	Version       string
+	PinnedVersion string
+}
+
+func (e PluginVersionPinnedError) Error() string {
+	if e.Version == "" {
+		return fmt.Sprintf("plugin '%s' is pinned to version '%s', specify the version to install", e.PluginID, e.PinnedVersion)
+	}
+	return fmt.Sprintf("plugin '%s' version %s doesn't satisfy its pinned version '%s'", e.PluginID, e.Version, e.PinnedVersion)
+}
+
 // PluginLoader can load a plugin.
 type PluginLoader interface {
 	// Load loads a plugin and returns it.
@@ -67,12 +93,16 @@ type PluginBase struct {
 	Signature    PluginSignatureStatus `json:"signature"`
 	Backend      bool                  `json:"backend"`
 
+	// AngularDetected is set for the external plugins that use AngularJS.
+	AngularDetected bool `json:"angularDetected"`
+
 	IncludedInAppId string              `json:"-"`
 	PluginDir       string              `json:"-"`
 	DefaultNavUrl   string              `json:"-"`
 	IsCorePlugin    bool                `json:"-"`
 	SignatureType   PluginSignatureType `json:"-"`
 	SignatureOrg    string              `json:"-"`
+	ModuleIntegrity string              `json:"-"`
 
 	GrafanaNetVersion   string `json:"-"`
 	GrafanaNetHasUpdate bool   `json:"-"`
@@ -81,8 +111,9 @@ type PluginBase struct {
 }
 
 type PluginDependencies struct {
-	GrafanaVersion string                 `json:"grafanaVersion"`
-	Plugins        []PluginDependencyItem `json:"plugins"`
+	GrafanaVersion    string                 `json:"grafanaVersion"`
+	GrafanaDependency string                 `json:"grafanaDependency,omitempty"`
+	Plugins           []PluginDependencyItem `json:"plugins"`
 }
 
 type PluginInclude struct {
@@ -2,8 +2,12 @@ package plugindashboards
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 
 	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/plugins"
@@ -142,6 +146,18 @@ func (s *Service) autoUpdateAppDashboard(pluginDashInfo *plugins.PluginDashboard
 	if err != nil {
 		return err
 	}
+
+	// another Grafana instance of the cluster may have imported the dashboard
+	// since the plugin dashboards were listed
+	imported, err := s.isDashboardImported(dash, pluginDashInfo.PluginId, orgID)
+	if err != nil {
+		return err
+	}
+	if imported {
+		s.logger.Debug("Skipping unchanged App dashboard", "dashboard", dash.Title, "rev", pluginDashInfo.Revision)
+		return nil
+	}
+
 	s.logger.Info("Auto updating App dashboard", "dashboard", dash.Title, "newRev",
 		pluginDashInfo.Revision, "oldRev", pluginDashInfo.ImportedRevision)
 	user := &models.SignedInUser{UserId: 0, OrgRole: models.ROLE_ADMIN}
@@ -149,3 +165,49 @@ func (s *Service) autoUpdateAppDashboard(pluginDashInfo *plugins.PluginDashboard
 		nil, user, s.DataService)
 	return err
 }
+
+// isDashboardImported returns whether the dashboard of a plugin is imported with
+// the same content.
+func (s *Service) isDashboardImported(dash *models.Dashboard, pluginID string, orgID int64) (bool, error) {
+	query := models.GetDashboardsByPluginIdQuery{PluginId: pluginID, OrgId: orgID}
+	if err := bus.Dispatch(&query); err != nil {
+		return false, err
+	}
+
+	hash, err := dashboardContentHash(dash.Data)
+	if err != nil {
+		return false, err
+	}
+	for _, existing := range query.Result {
+		if existing.Slug != dash.Slug {
+			continue
+		}
+		existingHash, err := dashboardContentHash(existing.Data)
+		if err != nil {
+			return false, err
+		}
+		return existingHash == hash, nil
+	}
+	return false, nil
+}
+
+// dashboardContentHash returns the hash of the content of a dashboard, without
+// the fields that are set when the dashboard is imported.
+func dashboardContentHash(data *simplejson.Json) (string, error) {
+	content := map[string]interface{}{}
+	for key, value := range data.MustMap() {
+		switch key {
+		case "id", "uid", "version", "__inputs":
+			continue
+		}
+		content[key] = value
+	}
+
+	// the keys of maps are marshalled in sorted order
+	b, err := json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,50 @@
+package plugindashboards
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsDashboardImported(t *testing.T) {
+	t.Cleanup(bus.ClearBusHandlers)
+
+	imported := models.NewDashboardFromJson(mustJSON(t, `{"id": 3, "uid": "abc", "version": 2, "title": "Overview", "revision": 2, "panels": [{"type": "graph"}]}`))
+	bus.AddHandler("test", func(query *models.GetDashboardsByPluginIdQuery) error {
+		query.Result = []*models.Dashboard{imported}
+		return nil
+	})
+	s := &Service{}
+
+	t.Run("Should return true for a dashboard with the same content", func(t *testing.T) {
+		dash := models.NewDashboardFromJson(mustJSON(t, `{"title": "Overview", "panels": [{"type": "graph"}], "revision": 2, "__inputs": []}`))
+		ok, err := s.isDashboardImported(dash, "test-app", 1)
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("Should return false for a dashboard with another content", func(t *testing.T) {
+		dash := models.NewDashboardFromJson(mustJSON(t, `{"title": "Overview", "panels": [{"type": "table"}], "revision": 3}`))
+		ok, err := s.isDashboardImported(dash, "test-app", 1)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("Should return false for a dashboard that isn't imported", func(t *testing.T) {
+		dash := models.NewDashboardFromJson(mustJSON(t, `{"title": "Details"}`))
+		ok, err := s.isDashboardImported(dash, "test-app", 1)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
+func mustJSON(t *testing.T, s string) *simplejson.Json {
+	t.Helper()
+
+	data, err := simplejson.NewJson([]byte(s))
+	require.NoError(t, err)
+	return data
+}
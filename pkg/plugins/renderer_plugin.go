@@ -3,7 +3,9 @@ package plugins
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"path/filepath"
+	"sync"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/plugins/backendplugin"
@@ -18,6 +20,11 @@ type RendererPlugin struct {
 	Executable           string `json:"executable,omitempty"`
 	GrpcPluginV2         pluginextensionv2.RendererPlugin
 	backendPluginManager backendplugin.Manager
+	executablePath       string
+
+	instancesMu sync.RWMutex
+	// instances are the additional instances started by StartInstances.
+	instances []pluginextensionv2.RendererPlugin
 }
 
 func (r *RendererPlugin) Load(decoder *json.Decoder, base *PluginBase,
@@ -29,8 +36,8 @@ func (r *RendererPlugin) Load(decoder *json.Decoder, base *PluginBase,
 	r.backendPluginManager = backendPluginManager
 
 	cmd := ComposePluginStartCommand("plugin_start")
-	fullpath := filepath.Join(base.PluginDir, cmd)
-	factory := grpcplugin.NewRendererPlugin(r.Id, fullpath, r.onPluginStart)
+	r.executablePath = filepath.Join(base.PluginDir, cmd)
+	factory := grpcplugin.NewRendererPlugin(r.Id, r.executablePath, r.onPluginStart)
 	if err := backendPluginManager.Register(r.Id, factory); err != nil {
 		return nil, errutil.Wrapf(err, "failed to register backend plugin")
 	}
@@ -46,6 +53,52 @@ func (r *RendererPlugin) Start(ctx context.Context) error {
 	return nil
 }
 
+// StartInstances starts the renderer plugin, and count-1 additional instances
+// of it registered as <plugin id>-<n>, each running in its own process.
+func (r *RendererPlugin) StartInstances(ctx context.Context, count int) error {
+	if err := r.Start(ctx); err != nil {
+		return err
+	}
+
+	r.instancesMu.Lock()
+	r.instances = make([]pluginextensionv2.RendererPlugin, count-1)
+	r.instancesMu.Unlock()
+
+	for i := 1; i < count; i++ {
+		instanceID := fmt.Sprintf("%s-%d", r.Id, i)
+		factory := grpcplugin.NewRendererPlugin(instanceID, r.executablePath, r.onInstanceStart(i))
+		if err := r.backendPluginManager.RegisterInstance(r.Id, instanceID, factory); err != nil {
+			return errutil.Wrapf(err, "failed to register renderer plugin instance")
+		}
+		if err := r.backendPluginManager.StartPlugin(ctx, instanceID); err != nil {
+			return errutil.Wrapf(err, "Failed to start renderer plugin instance")
+		}
+	}
+
+	return nil
+}
+
+// Instance returns an instance of the renderer plugin, the instance 0 being
+// the registered plugin.
+func (r *RendererPlugin) Instance(i int) pluginextensionv2.RendererPlugin {
+	if i == 0 {
+		return r.GrpcPluginV2
+	}
+
+	r.instancesMu.RLock()
+	defer r.instancesMu.RUnlock()
+	return r.instances[i-1]
+}
+
+func (r *RendererPlugin) onInstanceStart(i int) grpcplugin.StartRendererFunc {
+	return func(pluginID string, renderer pluginextensionv2.RendererPlugin, logger log.Logger) error {
+		r.instancesMu.Lock()
+		defer r.instancesMu.Unlock()
+		r.instances[i-1] = renderer
+		return nil
+	}
+}
+
 func (r *RendererPlugin) onPluginStart(pluginID string, renderer pluginextensionv2.RendererPlugin, logger log.Logger) error {
 	r.GrpcPluginV2 = renderer
 	return nil
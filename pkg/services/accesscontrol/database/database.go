@@ -0,0 +1,495 @@
+// Package database stores the custom and managed access control roles, their
+// permissions and their assignments to users and built-in roles.
+package database
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/registry"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func init() {
+	registry.RegisterService(&AccessControlStore{})
+}
+
+type role struct {
+	ID          int64 `xorm:"pk autoincr 'id'"`
+	OrgID       int64 `xorm:"org_id"`
+	Version     int64
+	UID         string `xorm:"uid"`
+	Name        string
+	Description string
+	Created     time.Time
+	Updated     time.Time
+}
+
+func (role) TableName() string {
+	return "role"
+}
+
+type permission struct {
+	ID      int64 `xorm:"pk autoincr 'id'"`
+	RoleID  int64 `xorm:"role_id"`
+	Action  string
+	Scope   string
+	Created time.Time
+	Updated time.Time
+}
+
+func (permission) TableName() string {
+	return "permission"
+}
+
+type userRole struct {
+	ID      int64 `xorm:"pk autoincr 'id'"`
+	OrgID   int64 `xorm:"org_id"`
+	UserID  int64 `xorm:"user_id"`
+	RoleID  int64 `xorm:"role_id"`
+	Created time.Time
+}
+
+func (userRole) TableName() string {
+	return "user_role"
+}
+
+type builtinRole struct {
+	ID      int64 `xorm:"pk autoincr 'id'"`
+	OrgID   int64 `xorm:"org_id"`
+	Role    string
+	RoleID  int64 `xorm:"role_id"`
+	Created time.Time
+	Updated time.Time
+}
+
+func (builtinRole) TableName() string {
+	return "builtin_role"
+}
+
+// AccessControlStore is the SQL store of the access control roles.
+type AccessControlStore struct {
+	SQLStore *sqlstore.SQLStore `inject:""`
+}
+
+func (s *AccessControlStore) Init() error {
+	return nil
+}
+
+// GetRoles returns the roles of the organization with their permissions.
+func (s *AccessControlStore) GetRoles(ctx context.Context, orgID int64) ([]*accesscontrol.RoleDTO, error) {
+	var result []*accesscontrol.RoleDTO
+	err := s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		roles := make([]*role, 0)
+		if err := sess.Where("org_id = ?", orgID).Asc("name").Find(&roles); err != nil {
+			return err
+		}
+
+		var err error
+		result, err = withPermissions(sess, roles)
+		return err
+	})
+	return result, err
+}
+
+// GetRole returns the role of the organization with the given UID.
+func (s *AccessControlStore) GetRole(ctx context.Context, orgID int64, uid string) (*accesscontrol.RoleDTO, error) {
+	var result *accesscontrol.RoleDTO
+	err := s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		r, err := getRole(sess, orgID, uid)
+		if err != nil {
+			return err
+		}
+
+		roles, err := withPermissions(sess, []*role{r})
+		if err != nil {
+			return err
+		}
+		result = roles[0]
+		return nil
+	})
+	return result, err
+}
+
+// CreateRole creates a role with its permissions. A UID is generated if none is given.
+func (s *AccessControlStore) CreateRole(ctx context.Context, cmd accesscontrol.CreateRoleCommand) (*accesscontrol.RoleDTO, error) {
+	var result *accesscontrol.RoleDTO
+	err := s.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		var err error
+		result, err = s.createRole(sess, cmd, 1)
+		return err
+	})
+	return result, err
+}
+
+// UpdateRole replaces the name, description and permissions of a role. The
+// version has to be greater than the stored one to detect concurrent changes.
+func (s *AccessControlStore) UpdateRole(ctx context.Context, cmd accesscontrol.UpdateRoleCommand) (*accesscontrol.RoleDTO, error) {
+	var result *accesscontrol.RoleDTO
+	err := s.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		r, err := getRole(sess, cmd.OrgID, cmd.UID)
+		if err != nil {
+			return err
+		}
+		if cmd.Version <= r.Version {
+			return accesscontrol.ErrVersionLE
+		}
+
+		r.Version = cmd.Version
+		r.Name = cmd.Name
+		r.Description = cmd.Description
+		r.Updated = time.Now()
+		if _, err := sess.ID(r.ID).Cols("version", "name", "description", "updated").Update(r); err != nil {
+			if s.SQLStore.Dialect.IsUniqueConstraintViolation(err) {
+				return accesscontrol.ErrRoleAlreadyExists
+			}
+			return err
+		}
+
+		if _, err := sess.Where("role_id = ?", r.ID).Delete(&permission{}); err != nil {
+			return err
+		}
+		if err := insertPermissions(sess, r.ID, cmd.Permissions); err != nil {
+			return err
+		}
+
+		roles, err := withPermissions(sess, []*role{r})
+		if err != nil {
+			return err
+		}
+		result = roles[0]
+		return nil
+	})
+	return result, err
+}
+
+// DeleteRole deletes a role. Assigned roles are only deleted with Force,
+// which removes their assignments too.
+func (s *AccessControlStore) DeleteRole(ctx context.Context, cmd accesscontrol.DeleteRoleCommand) error {
+	return s.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		r, err := getRole(sess, cmd.OrgID, cmd.UID)
+		if err != nil {
+			return err
+		}
+
+		if !cmd.Force {
+			users, err := sess.Where("role_id = ?", r.ID).Count(&userRole{})
+			if err != nil {
+				return err
+			}
+			builtins, err := sess.Where("role_id = ?", r.ID).Count(&builtinRole{})
+			if err != nil {
+				return err
+			}
+			if users+builtins > 0 {
+				return accesscontrol.ErrRoleAssigned
+			}
+		}
+
+		for _, bean := range []interface{}{&userRole{}, &builtinRole{}, &permission{}} {
+			if _, err := sess.Where("role_id = ?", r.ID).Delete(bean); err != nil {
+				return err
+			}
+		}
+		_, err = sess.ID(r.ID).Delete(&role{})
+		return err
+	})
+}
+
+// GetUserRoles returns the roles assigned directly to the user in the organization.
+func (s *AccessControlStore) GetUserRoles(ctx context.Context, orgID, userID int64) ([]*accesscontrol.RoleDTO, error) {
+	var result []*accesscontrol.RoleDTO
+	err := s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		roles := make([]*role, 0)
+		if err := sess.Where("org_id = ? AND id IN (SELECT role_id FROM user_role WHERE org_id = ? AND user_id = ?)", orgID, orgID, userID).
+			Asc("name").Find(&roles); err != nil {
+			return err
+		}
+
+		var err error
+		result, err = withPermissions(sess, roles)
+		return err
+	})
+	return result, err
+}
+
+// AddUserRole assigns a role to a user. Assigning a role twice is not an error.
+func (s *AccessControlStore) AddUserRole(ctx context.Context, cmd accesscontrol.AddUserRoleCommand) error {
+	return s.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		r, err := getRole(sess, cmd.OrgID, cmd.RoleUID)
+		if err != nil {
+			return err
+		}
+
+		exists, err := sess.Where("org_id = ? AND user_id = ? AND role_id = ?", cmd.OrgID, cmd.UserID, r.ID).Exist(&userRole{})
+		if err != nil || exists {
+			return err
+		}
+
+		_, err = sess.Insert(&userRole{OrgID: cmd.OrgID, UserID: cmd.UserID, RoleID: r.ID, Created: time.Now()})
+		return err
+	})
+}
+
+// RemoveUserRole removes a role from a user.
+func (s *AccessControlStore) RemoveUserRole(ctx context.Context, cmd accesscontrol.RemoveUserRoleCommand) error {
+	return s.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		r, err := getRole(sess, cmd.OrgID, cmd.RoleUID)
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Where("org_id = ? AND user_id = ? AND role_id = ?", cmd.OrgID, cmd.UserID, r.ID).Delete(&userRole{})
+		return err
+	})
+}
+
+// GetBuiltinRoles returns the roles of the organization assigned to each built-in role.
+func (s *AccessControlStore) GetBuiltinRoles(ctx context.Context, orgID int64) (map[string][]*accesscontrol.RoleDTO, error) {
+	result := map[string][]*accesscontrol.RoleDTO{}
+	err := s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		assignments := make([]*builtinRole, 0)
+		if err := sess.Where("org_id = ?", orgID).Find(&assignments); err != nil {
+			return err
+		}
+		if len(assignments) == 0 {
+			return nil
+		}
+
+		roleIDs := make([]int64, 0, len(assignments))
+		for _, a := range assignments {
+			roleIDs = append(roleIDs, a.RoleID)
+		}
+		roles := make([]*role, 0)
+		if err := sess.In("id", roleIDs).Asc("name").Find(&roles); err != nil {
+			return err
+		}
+		dtos, err := withPermissions(sess, roles)
+		if err != nil {
+			return err
+		}
+
+		byID := make(map[int64]*accesscontrol.RoleDTO, len(dtos))
+		for _, dto := range dtos {
+			byID[dto.ID] = dto
+		}
+		for _, a := range assignments {
+			if dto, ok := byID[a.RoleID]; ok {
+				result[a.Role] = append(result[a.Role], dto)
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+// AddBuiltinRole assigns a role to a built-in role. Assigning a role twice is not an error.
+func (s *AccessControlStore) AddBuiltinRole(ctx context.Context, cmd accesscontrol.AddBuiltinRoleCommand) error {
+	if err := accesscontrol.ValidateBuiltInRoles([]string{cmd.BuiltinRole}); err != nil {
+		return err
+	}
+
+	return s.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		r, err := getRole(sess, cmd.OrgID, cmd.RoleUID)
+		if err != nil {
+			return err
+		}
+		return addBuiltinRole(sess, cmd.OrgID, r.ID, cmd.BuiltinRole)
+	})
+}
+
+// RemoveBuiltinRole removes a role from a built-in role.
+func (s *AccessControlStore) RemoveBuiltinRole(ctx context.Context, cmd accesscontrol.RemoveBuiltinRoleCommand) error {
+	return s.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		r, err := getRole(sess, cmd.OrgID, cmd.RoleUID)
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Where("org_id = ? AND role_id = ? AND role = ?", cmd.OrgID, r.ID, cmd.BuiltinRole).Delete(&builtinRole{})
+		return err
+	})
+}
+
+// GetUserPermissions returns the permissions of the roles assigned to the
+// user and to the given built-in roles in the organization.
+func (s *AccessControlStore) GetUserPermissions(ctx context.Context, orgID, userID int64, builtinRoles []string) ([]*accesscontrol.Permission, error) {
+	result := make([]*accesscontrol.Permission, 0)
+	err := s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		q := sess.Table("permission").Cols("permission.action", "permission.scope").
+			Where("permission.role_id IN (SELECT role_id FROM user_role WHERE org_id = ? AND user_id = ?)", orgID, userID)
+		if len(builtinRoles) > 0 {
+			args := []interface{}{orgID}
+			for _, b := range builtinRoles {
+				args = append(args, b)
+			}
+			q = q.Or("permission.role_id IN (SELECT role_id FROM builtin_role WHERE org_id = ? AND role IN (?"+
+				strings.Repeat(",?", len(builtinRoles)-1)+"))", args...)
+		}
+		return q.Find(&result)
+	})
+	return result, err
+}
+
+// SeedManagedRoles creates the managed roles missing in any organization.
+func (s *AccessControlStore) SeedManagedRoles(ctx context.Context) error {
+	var orgIDs []int64
+	err := s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		return sess.Table("org").Cols("id").Find(&orgIDs)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, orgID := range orgIDs {
+		if err := s.SeedOrgManagedRoles(ctx, orgID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SeedOrgManagedRoles creates the managed roles missing in the organization
+// and assigns them to their built-in role. Existing managed roles are kept
+// as they are, since they might have been modified.
+func (s *AccessControlStore) SeedOrgManagedRoles(ctx context.Context, orgID int64) error {
+	return s.SQLStore.WithTransactionalDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		for builtin, managed := range accesscontrol.ManagedRoles {
+			exists, err := sess.Where("org_id = ? AND name = ?", orgID, managed.Name).Exist(&role{})
+			if err != nil {
+				return err
+			}
+			if exists {
+				continue
+			}
+
+			created, err := s.createRole(sess, accesscontrol.CreateRoleCommand{
+				OrgID:       orgID,
+				Name:        managed.Name,
+				Description: managed.Description,
+				Permissions: managed.Permissions,
+			}, managed.Version)
+			if err != nil {
+				return err
+			}
+
+			if err := addBuiltinRole(sess, orgID, created.ID, builtin); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *AccessControlStore) createRole(sess *sqlstore.DBSession, cmd accesscontrol.CreateRoleCommand, version int64) (*accesscontrol.RoleDTO, error) {
+	uid := cmd.UID
+	if uid == "" {
+		uid = util.GenerateShortUID()
+	} else if !util.IsValidShortUID(uid) {
+		return nil, accesscontrol.ErrInvalidRoleUID
+	}
+
+	now := time.Now()
+	r := &role{
+		OrgID:       cmd.OrgID,
+		Version:     version,
+		UID:         uid,
+		Name:        cmd.Name,
+		Description: cmd.Description,
+		Created:     now,
+		Updated:     now,
+	}
+	if _, err := sess.Insert(r); err != nil {
+		if s.SQLStore.Dialect.IsUniqueConstraintViolation(err) {
+			return nil, accesscontrol.ErrRoleAlreadyExists
+		}
+		return nil, err
+	}
+
+	if err := insertPermissions(sess, r.ID, cmd.Permissions); err != nil {
+		return nil, err
+	}
+
+	roles, err := withPermissions(sess, []*role{r})
+	if err != nil {
+		return nil, err
+	}
+	return roles[0], nil
+}
+
+func getRole(sess *sqlstore.DBSession, orgID int64, uid string) (*role, error) {
+	r := &role{}
+	has, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Get(r)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, accesscontrol.ErrRoleNotFound
+	}
+	return r, nil
+}
+
+func addBuiltinRole(sess *sqlstore.DBSession, orgID, roleID int64, builtin string) error {
+	exists, err := sess.Where("org_id = ? AND role_id = ? AND role = ?", orgID, roleID, builtin).Exist(&builtinRole{})
+	if err != nil || exists {
+		return err
+	}
+
+	now := time.Now()
+	_, err = sess.Insert(&builtinRole{OrgID: orgID, RoleID: roleID, Role: builtin, Created: now, Updated: now})
+	return err
+}
+
+func insertPermissions(sess *sqlstore.DBSession, roleID int64, permissions []accesscontrol.Permission) error {
+	now := time.Now()
+	seen := map[accesscontrol.Permission]bool{}
+	for _, p := range permissions {
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+
+		if _, err := sess.Insert(&permission{RoleID: roleID, Action: p.Action, Scope: p.Scope, Created: now, Updated: now}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func withPermissions(sess *sqlstore.DBSession, roles []*role) ([]*accesscontrol.RoleDTO, error) {
+	result := make([]*accesscontrol.RoleDTO, 0, len(roles))
+	if len(roles) == 0 {
+		return result, nil
+	}
+
+	ids := make([]int64, 0, len(roles))
+	byID := make(map[int64]*accesscontrol.RoleDTO, len(roles))
+	for _, r := range roles {
+		dto := &accesscontrol.RoleDTO{
+			ID:          r.ID,
+			OrgID:       r.OrgID,
+			Version:     r.Version,
+			UID:         r.UID,
+			Name:        r.Name,
+			Description: r.Description,
+			Permissions: []accesscontrol.Permission{},
+		}
+		ids = append(ids, r.ID)
+		byID[r.ID] = dto
+		result = append(result, dto)
+	}
+
+	permissions := make([]*permission, 0)
+	if err := sess.In("role_id", ids).Asc("action", "scope").Find(&permissions); err != nil {
+		return nil, err
+	}
+	for _, p := range permissions {
+		dto := byID[p.RoleID]
+		dto.Permissions = append(dto.Permissions, accesscontrol.Permission{Action: p.Action, Scope: p.Scope})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,149 @@
+// +build integration
+
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestStore(t *testing.T) *AccessControlStore {
+	t.Helper()
+
+	return &AccessControlStore{SQLStore: sqlstore.InitTestDB(t)}
+}
+
+func TestAccessControlStore_Roles(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestStore(t)
+
+	created, err := store.CreateRole(ctx, accesscontrol.CreateRoleCommand{
+		OrgID: 1,
+		UID:   "reader",
+		Name:  "custom:reader",
+		Permissions: []accesscontrol.Permission{
+			{Action: accesscontrol.ActionDashboardsRead, Scope: "dashboards:uid:abc"},
+			{Action: accesscontrol.ActionDashboardsRead, Scope: "dashboards:uid:abc"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), created.Version)
+	assert.Len(t, created.Permissions, 1)
+
+	t.Run("rejects duplicate roles", func(t *testing.T) {
+		_, err := store.CreateRole(ctx, accesscontrol.CreateRoleCommand{OrgID: 1, UID: "reader", Name: "custom:other"})
+		require.ErrorIs(t, err, accesscontrol.ErrRoleAlreadyExists)
+	})
+
+	t.Run("scopes roles to the organization", func(t *testing.T) {
+		_, err := store.GetRole(ctx, 2, "reader")
+		require.ErrorIs(t, err, accesscontrol.ErrRoleNotFound)
+	})
+
+	t.Run("updates the role when the version is greater", func(t *testing.T) {
+		_, err := store.UpdateRole(ctx, accesscontrol.UpdateRoleCommand{OrgID: 1, UID: "reader", Version: 1, Name: "custom:reader"})
+		require.ErrorIs(t, err, accesscontrol.ErrVersionLE)
+
+		updated, err := store.UpdateRole(ctx, accesscontrol.UpdateRoleCommand{
+			OrgID:       1,
+			UID:         "reader",
+			Version:     2,
+			Name:        "custom:reader",
+			Permissions: []accesscontrol.Permission{{Action: accesscontrol.ActionFoldersRead, Scope: accesscontrol.ScopeFoldersAll}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []accesscontrol.Permission{{Action: accesscontrol.ActionFoldersRead, Scope: accesscontrol.ScopeFoldersAll}}, updated.Permissions)
+	})
+
+	t.Run("only deletes assigned roles when forced", func(t *testing.T) {
+		require.NoError(t, store.AddUserRole(ctx, accesscontrol.AddUserRoleCommand{OrgID: 1, UserID: 10, RoleUID: "reader"}))
+
+		err := store.DeleteRole(ctx, accesscontrol.DeleteRoleCommand{OrgID: 1, UID: "reader"})
+		require.ErrorIs(t, err, accesscontrol.ErrRoleAssigned)
+
+		require.NoError(t, store.DeleteRole(ctx, accesscontrol.DeleteRoleCommand{OrgID: 1, UID: "reader", Force: true}))
+		roles, err := store.GetUserRoles(ctx, 1, 10)
+		require.NoError(t, err)
+		assert.Empty(t, roles)
+	})
+}
+
+func TestAccessControlStore_GetUserPermissions(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestStore(t)
+
+	_, err := store.CreateRole(ctx, accesscontrol.CreateRoleCommand{
+		OrgID:       1,
+		UID:         "user",
+		Name:        "custom:user",
+		Permissions: []accesscontrol.Permission{{Action: "custom:user", Scope: "custom:*"}},
+	})
+	require.NoError(t, err)
+	_, err = store.CreateRole(ctx, accesscontrol.CreateRoleCommand{
+		OrgID:       1,
+		UID:         "editor",
+		Name:        "custom:editor",
+		Permissions: []accesscontrol.Permission{{Action: "custom:editor", Scope: "custom:*"}},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, store.AddUserRole(ctx, accesscontrol.AddUserRoleCommand{OrgID: 1, UserID: 10, RoleUID: "user"}))
+	require.NoError(t, store.AddBuiltinRole(ctx, accesscontrol.AddBuiltinRoleCommand{OrgID: 1, RoleUID: "editor", BuiltinRole: string(models.ROLE_EDITOR)}))
+
+	actions := func(permissions []*accesscontrol.Permission) []string {
+		result := make([]string, 0, len(permissions))
+		for _, p := range permissions {
+			result = append(result, p.Action)
+		}
+		return result
+	}
+
+	permissions, err := store.GetUserPermissions(ctx, 1, 10, []string{string(models.ROLE_VIEWER)})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"custom:user"}, actions(permissions))
+
+	permissions, err = store.GetUserPermissions(ctx, 1, 11, []string{string(models.ROLE_EDITOR), string(models.ROLE_VIEWER)})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"custom:editor"}, actions(permissions))
+
+	permissions, err = store.GetUserPermissions(ctx, 2, 10, []string{string(models.ROLE_EDITOR)})
+	require.NoError(t, err)
+	assert.Empty(t, permissions)
+
+	err = store.AddBuiltinRole(ctx, accesscontrol.AddBuiltinRoleCommand{OrgID: 1, RoleUID: "editor", BuiltinRole: "Unknown"})
+	require.ErrorIs(t, err, accesscontrol.ErrInvalidBuiltinRole)
+}
+
+func TestAccessControlStore_SeedOrgManagedRoles(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestStore(t)
+
+	require.NoError(t, store.SeedOrgManagedRoles(ctx, 1))
+	builtins, err := store.GetBuiltinRoles(ctx, 1)
+	require.NoError(t, err)
+	for builtin, managed := range accesscontrol.ManagedRoles {
+		require.Len(t, builtins[builtin], 1)
+		assert.Equal(t, managed.Name, builtins[builtin][0].Name)
+		assert.True(t, builtins[builtin][0].IsManaged())
+	}
+
+	// modified managed roles are kept when seeding again
+	viewer := builtins[string(models.ROLE_VIEWER)][0]
+	_, err = store.UpdateRole(ctx, accesscontrol.UpdateRoleCommand{OrgID: 1, UID: viewer.UID, Version: viewer.Version + 1, Name: viewer.Name})
+	require.NoError(t, err)
+
+	require.NoError(t, store.SeedOrgManagedRoles(ctx, 1))
+	role, err := store.GetRole(ctx, 1, viewer.UID)
+	require.NoError(t, err)
+	assert.Empty(t, role.Permissions)
+
+	roles, err := store.GetRoles(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, roles, len(accesscontrol.ManagedRoles))
+}
@@ -5,4 +5,14 @@ import "errors"
 var (
 	ErrFixedRolePrefixMissing = errors.New("fixed role should be prefixed with '" + FixedRolePrefix + "'")
 	ErrInvalidBuiltinRole     = errors.New("built-in role is not valid")
+	ErrRoleNotFound           = errors.New("role not found")
+	ErrInvalidRoleUID         = errors.New("role uid contains illegal characters or is too long")
+	ErrRoleAlreadyExists      = errors.New("role with the same uid or name already exists")
+	ErrVersionLE              = errors.New("the provided role version is smaller than or equal to the stored role")
+	ErrRoleAssigned           = errors.New("role is assigned to users or built-in roles")
+	ErrReservedRoleName       = errors.New("role name is reserved for fixed and managed roles")
+	ErrManagedRoleDeletion    = errors.New("managed roles can't be deleted")
+	ErrMissingAction          = errors.New("permission action is missing")
+	ErrInvalidScope           = errors.New("scope should not contain meta-characters like * or ?, except in the last position")
+	ErrPermissionNotGranted   = errors.New("permission is not granted to the user")
 )
@@ -0,0 +1,59 @@
+package accesscontrol
+
+import (
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// ManagedRoles are seeded in every organization and assigned to the built-in
+// organization role they are keyed by. They grant what the built-in roles
+// allowed without access control, and can be modified per organization to
+// change what the built-in roles allow. The roles of child built-in roles
+// apply too, so the Admin role gets the Editor and Viewer permissions.
+var ManagedRoles = map[string]RoleDTO{
+	string(models.ROLE_VIEWER): {
+		Name:        ManagedRolePrefix + "builtins:viewer:permissions",
+		Description: "Permissions of the Viewer role in this organization.",
+		Version:     1,
+		Permissions: []Permission{
+			{
+				Action: ActionDashboardsRead,
+				Scope:  ScopeDashboardsAll,
+			},
+			{
+				Action: ActionFoldersRead,
+				Scope:  ScopeFoldersAll,
+			},
+		},
+	},
+	string(models.ROLE_EDITOR): {
+		Name:        ManagedRolePrefix + "builtins:editor:permissions",
+		Description: "Permissions of the Editor role in this organization.",
+		Version:     1,
+		Permissions: []Permission{
+			{
+				Action: ActionDashboardsWrite,
+				Scope:  ScopeDashboardsAll,
+			},
+			{
+				Action: ActionDashboardsDelete,
+				Scope:  ScopeDashboardsAll,
+			},
+			{
+				Action: ActionFoldersCreate,
+			},
+			{
+				Action: ActionFoldersWrite,
+				Scope:  ScopeFoldersAll,
+			},
+			{
+				Action: ActionFoldersDelete,
+				Scope:  ScopeFoldersAll,
+			},
+		},
+	},
+	string(models.ROLE_ADMIN): {
+		Name:        ManagedRolePrefix + "builtins:admin:permissions",
+		Description: "Permissions of the Admin role in this organization, in addition to the Editor permissions.",
+		Version:     1,
+	},
+}
@@ -1,6 +1,7 @@
 package accesscontrol
 
 import (
+	"strings"
 	"time"
 )
 
@@ -22,6 +23,8 @@ type Role struct {
 }
 
 type RoleDTO struct {
+	ID          int64        `json:"-"`
+	OrgID       int64        `json:"-"`
 	Version     int64        `json:"version"`
 	UID         string       `json:"uid"`
 	Name        string       `json:"name"`
@@ -29,6 +32,65 @@ type RoleDTO struct {
 	Permissions []Permission `json:"permissions,omitempty"`
 }
 
+// IsFixed returns true for roles declared in code, which can't be modified.
+func (p RoleDTO) IsFixed() bool {
+	return strings.HasPrefix(p.Name, FixedRolePrefix)
+}
+
+// IsManaged returns true for roles seeded in every organization, which can
+// be modified but not deleted.
+func (p RoleDTO) IsManaged() bool {
+	return strings.HasPrefix(p.Name, ManagedRolePrefix)
+}
+
+type CreateRoleCommand struct {
+	OrgID       int64        `json:"-"`
+	UID         string       `json:"uid"`
+	Name        string       `json:"name" binding:"Required"`
+	Description string       `json:"description"`
+	Permissions []Permission `json:"permissions"`
+}
+
+type UpdateRoleCommand struct {
+	OrgID       int64        `json:"-"`
+	UID         string       `json:"-"`
+	Version     int64        `json:"version" binding:"Required"`
+	Name        string       `json:"name" binding:"Required"`
+	Description string       `json:"description"`
+	Permissions []Permission `json:"permissions"`
+}
+
+type DeleteRoleCommand struct {
+	OrgID int64
+	UID   string
+	// Force removes the assignments of the role as well.
+	Force bool
+}
+
+type AddUserRoleCommand struct {
+	OrgID   int64  `json:"-"`
+	UserID  int64  `json:"-"`
+	RoleUID string `json:"roleUid" binding:"Required"`
+}
+
+type RemoveUserRoleCommand struct {
+	OrgID   int64
+	UserID  int64
+	RoleUID string
+}
+
+type AddBuiltinRoleCommand struct {
+	OrgID       int64  `json:"-"`
+	RoleUID     string `json:"roleUid" binding:"Required"`
+	BuiltinRole string `json:"builtinRole" binding:"Required"`
+}
+
+type RemoveBuiltinRoleCommand struct {
+	OrgID       int64
+	RoleUID     string
+	BuiltinRole string
+}
+
 type Permission struct {
 	Action string `json:"action"`
 	Scope  string `json:"scope"`
@@ -70,6 +132,9 @@ const (
 	ActionUsersLogout            = "users:logout"
 	ActionUsersQuotasList        = "users.quotas:list"
 	ActionUsersQuotasUpdate      = "users.quotas:update"
+	ActionUsersLockoutRead       = "users.lockout:read"
+	ActionUsersUnlock            = "users:unlock"
+	ActionUsersImpersonate       = "users:impersonate"
 
 	// Org actions
 	ActionOrgUsersRead       = "org.users:read"
@@ -95,6 +160,29 @@ const (
 	// Plugin actions
 	ActionPluginsManage = "plugins:manage"
 
+	// Roles actions
+	ActionRolesList          = "roles:list"
+	ActionRolesRead          = "roles:read"
+	ActionRolesWrite         = "roles:write"
+	ActionRolesDelete        = "roles:delete"
+	ActionRolesBuiltinList   = "roles.builtin:list"
+	ActionRolesBuiltinAdd    = "roles.builtin:add"
+	ActionRolesBuiltinRemove = "roles.builtin:remove"
+	ActionUsersRolesList     = "users.roles:list"
+	ActionUsersRolesAdd      = "users.roles:add"
+	ActionUsersRolesRemove   = "users.roles:remove"
+
+	// Dashboards actions
+	ActionDashboardsRead   = "dashboards:read"
+	ActionDashboardsWrite  = "dashboards:write"
+	ActionDashboardsDelete = "dashboards:delete"
+
+	// Folders actions
+	ActionFoldersRead   = "folders:read"
+	ActionFoldersCreate = "folders:create"
+	ActionFoldersWrite  = "folders:write"
+	ActionFoldersDelete = "folders:delete"
+
 	// Global Scopes
 	ScopeGlobalUsersAll = "global:users:*"
 
@@ -103,8 +191,21 @@ const (
 
 	// Settings scope
 	ScopeSettingsAll = "settings:*"
+
+	// Roles scope
+	ScopeRolesAll = "roles:*"
+
+	// Dashboards scope
+	ScopeDashboardsAll = "dashboards:*"
+
+	// Folders scope
+	ScopeFoldersAll = "folders:*"
 )
 
 const RoleGrafanaAdmin = "Grafana Admin"
 
 const FixedRolePrefix = "fixed:"
+
+// ManagedRolePrefix is the prefix of the roles seeded in every organization
+// and assigned to its built-in roles.
+const ManagedRolePrefix = "managed:"
@@ -3,11 +3,14 @@ package ossaccesscontrol
 import (
 	"context"
 
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/infra/usagestats"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/database"
 	"github.com/grafana/grafana/pkg/services/accesscontrol/evaluator"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/prometheus/client_golang/prometheus"
@@ -15,8 +18,9 @@ import (
 
 // OSSAccessControlService is the service implementing role based access control.
 type OSSAccessControlService struct {
-	Cfg           *setting.Cfg          `inject:""`
-	UsageStats    usagestats.UsageStats `inject:""`
+	Cfg           *setting.Cfg                 `inject:""`
+	UsageStats    usagestats.UsageStats        `inject:""`
+	Store         *database.AccessControlStore `inject:""`
 	Log           log.Logger
 	registrations accesscontrol.RegistrationList
 }
@@ -27,7 +31,17 @@ func (ac *OSSAccessControlService) Init() error {
 
 	ac.registerUsageMetrics()
 
-	return nil
+	if ac.IsDisabled() || ac.Store == nil {
+		return nil
+	}
+
+	bus.AddEventListener(ac.onOrgCreated)
+
+	return ac.Store.SeedManagedRoles(context.Background())
+}
+
+func (ac *OSSAccessControlService) onOrgCreated(evt *events.OrgCreated) error {
+	return ac.Store.SeedOrgManagedRoles(context.Background(), evt.Id)
 }
 
 func (ac *OSSAccessControlService) IsDisabled() bool {
@@ -60,13 +74,21 @@ func (ac *OSSAccessControlService) Evaluate(ctx context.Context, user *models.Si
 	return evaluator.Evaluate(ctx, ac, user, permission, scope...)
 }
 
-// GetUserPermissions returns user permissions based on built-in roles
+// GetUserPermissions returns user permissions based on built-in roles, and
+// the roles stored for the organization of the user
 func (ac *OSSAccessControlService) GetUserPermissions(ctx context.Context, user *models.SignedInUser) ([]*accesscontrol.Permission, error) {
 	timer := prometheus.NewTimer(metrics.MAccessPermissionsSummary)
 	defer timer.ObserveDuration()
 
 	builtinRoles := ac.GetUserBuiltInRoles(user)
 	permissions := make([]*accesscontrol.Permission, 0)
+	if ac.Store != nil {
+		stored, err := ac.Store.GetUserPermissions(ctx, user.OrgId, user.UserId, builtinRoles)
+		if err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, stored...)
+	}
 	for _, builtin := range builtinRoles {
 		if roleNames, ok := accesscontrol.FixedRoleGrants[builtin]; ok {
 			for _, name := range roleNames {
@@ -46,6 +46,60 @@ var (
 		}),
 	}
 
+	permissionsAdminReadRole = RoleDTO{
+		Name:    permissionsAdminRead,
+		Version: 1,
+		Permissions: []Permission{
+			{
+				Action: ActionRolesList,
+				Scope:  ScopeRolesAll,
+			},
+			{
+				Action: ActionRolesRead,
+				Scope:  ScopeRolesAll,
+			},
+			{
+				Action: ActionRolesBuiltinList,
+				Scope:  ScopeRolesAll,
+			},
+			{
+				Action: ActionUsersRolesList,
+				Scope:  ScopeUsersAll,
+			},
+		},
+	}
+
+	permissionsAdminEditRole = RoleDTO{
+		Name:    permissionsAdminEdit,
+		Version: 1,
+		Permissions: ConcatPermissions(permissionsAdminReadRole.Permissions, []Permission{
+			{
+				Action: ActionRolesWrite,
+				Scope:  ScopeRolesAll,
+			},
+			{
+				Action: ActionRolesDelete,
+				Scope:  ScopeRolesAll,
+			},
+			{
+				Action: ActionRolesBuiltinAdd,
+				Scope:  ScopeRolesAll,
+			},
+			{
+				Action: ActionRolesBuiltinRemove,
+				Scope:  ScopeRolesAll,
+			},
+			{
+				Action: ActionUsersRolesAdd,
+				Scope:  ScopeUsersAll,
+			},
+			{
+				Action: ActionUsersRolesRemove,
+				Scope:  ScopeUsersAll,
+			},
+		}),
+	}
+
 	serverAdminReadRole = RoleDTO{
 		Version: 1,
 		Name:    serverAdminRead,
@@ -99,7 +153,7 @@ var (
 
 	usersAdminReadRole = RoleDTO{
 		Name:    usersAdminRead,
-		Version: 1,
+		Version: 2,
 		Permissions: []Permission{
 			{
 				Action: ActionUsersRead,
@@ -117,12 +171,16 @@ var (
 				Action: ActionUsersQuotasList,
 				Scope:  ScopeGlobalUsersAll,
 			},
+			{
+				Action: ActionUsersLockoutRead,
+				Scope:  ScopeGlobalUsersAll,
+			},
 		},
 	}
 
 	usersAdminEditRole = RoleDTO{
 		Name:    usersAdminEdit,
-		Version: 1,
+		Version: 2,
 		Permissions: ConcatPermissions(usersAdminReadRole.Permissions, []Permission{
 			{
 				Action: ActionUsersPasswordUpdate,
@@ -163,6 +221,14 @@ var (
 				Action: ActionUsersQuotasUpdate,
 				Scope:  ScopeGlobalUsersAll,
 			},
+			{
+				Action: ActionUsersUnlock,
+				Scope:  ScopeGlobalUsersAll,
+			},
+			{
+				Action: ActionUsersImpersonate,
+				Scope:  ScopeGlobalUsersAll,
+			},
 		}),
 	}
 )
@@ -171,6 +237,9 @@ var (
 const (
 	datasourcesEditorRead = "fixed:datasources:editor:read"
 
+	permissionsAdminEdit = "fixed:permissions:admin:edit"
+	permissionsAdminRead = "fixed:permissions:admin:read"
+
 	serverAdminRead = "fixed:server:admin:read"
 
 	settingsAdminRead = "fixed:settings:admin:read"
@@ -200,6 +269,8 @@ var (
 		usersOrgRead:          usersOrgReadRole,
 		ldapAdminEdit:         ldapAdminEditRole,
 		ldapAdminRead:         ldapAdminReadRole,
+		permissionsAdminEdit:  permissionsAdminEditRole,
+		permissionsAdminRead:  permissionsAdminReadRole,
 		serverAdminRead:       serverAdminReadRole,
 		settingsAdminRead:     settingsAdminReadRole,
 	}
@@ -210,6 +281,8 @@ var (
 		RoleGrafanaAdmin: {
 			ldapAdminEdit,
 			ldapAdminRead,
+			permissionsAdminEdit,
+			permissionsAdminRead,
 			serverAdminRead,
 			settingsAdminRead,
 			usersAdminEdit,
@@ -218,6 +291,7 @@ var (
 			usersOrgRead,
 		},
 		string(models.ROLE_ADMIN): {
+			permissionsAdminRead,
 			usersOrgEdit,
 			usersOrgRead,
 		},
@@ -248,6 +322,57 @@ func ValidateFixedRole(role RoleDTO) error {
 	return nil
 }
 
+// ValidateCustomRole errors when a role stored in the database uses a name
+// reserved for fixed and managed roles or contains invalid scopes
+func ValidateCustomRole(name string, permissions []Permission) error {
+	if strings.HasPrefix(name, FixedRolePrefix) || strings.HasPrefix(name, ManagedRolePrefix) {
+		return ErrReservedRoleName
+	}
+	return ValidatePermissions(permissions)
+}
+
+// ValidatePermissions errors when a permission has no action or an invalid scope
+func ValidatePermissions(permissions []Permission) error {
+	for _, p := range permissions {
+		if p.Action == "" {
+			return ErrMissingAction
+		}
+		if p.Scope != "" && !ValidateScope(p.Scope) {
+			return fmt.Errorf("'%s' %w", p.Scope, ErrInvalidScope)
+		}
+	}
+	return nil
+}
+
+// ValidatePermissionsGranted errors when a permission is not included in the
+// granted permissions, so that users can't give roles more than they have.
+// A scoped permission is included in a granted permission of the same action
+// whose scope is the same or a wildcard matching it.
+func ValidatePermissionsGranted(granted []*Permission, permissions []Permission) error {
+	for _, p := range permissions {
+		if !isPermissionGranted(granted, p) {
+			return fmt.Errorf("'%s' on '%s' %w", p.Action, p.Scope, ErrPermissionNotGranted)
+		}
+	}
+	return nil
+}
+
+func isPermissionGranted(granted []*Permission, permission Permission) bool {
+	for _, g := range granted {
+		if g == nil || g.Action != permission.Action {
+			continue
+		}
+		if g.Scope == permission.Scope {
+			return true
+		}
+		if permission.Scope != "" && g.Scope != "" && ValidateScope(g.Scope) && strings.HasSuffix(g.Scope, "*") &&
+			strings.HasPrefix(permission.Scope, strings.TrimSuffix(g.Scope, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
 // ValidateBuiltInRoles errors when a built-in role does not match expected pattern
 func ValidateBuiltInRoles(builtInRoles []string) error {
 	for _, br := range builtInRoles {
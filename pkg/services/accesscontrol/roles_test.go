@@ -72,3 +72,100 @@ func TestConcatPermissions(t *testing.T) {
 	perms := ConcatPermissions(perms1, perms2)
 	assert.ElementsMatch(t, perms, expected)
 }
+
+func TestValidateCustomRole(t *testing.T) {
+	tests := []struct {
+		desc        string
+		name        string
+		permissions []Permission
+		expected    error
+	}{
+		{
+			desc:        "custom role is valid",
+			name:        "custom:reader",
+			permissions: []Permission{{Action: ActionDashboardsRead, Scope: "dashboards:uid:abc"}},
+		},
+		{
+			desc:     "fixed role name is reserved",
+			name:     "fixed:custom",
+			expected: ErrReservedRoleName,
+		},
+		{
+			desc:     "managed role name is reserved",
+			name:     "managed:custom",
+			expected: ErrReservedRoleName,
+		},
+		{
+			desc:        "permission without action is invalid",
+			name:        "custom:reader",
+			permissions: []Permission{{Scope: ScopeDashboardsAll}},
+			expected:    ErrMissingAction,
+		},
+		{
+			desc:        "wildcard before the last position is invalid",
+			name:        "custom:reader",
+			permissions: []Permission{{Action: ActionDashboardsRead, Scope: "dashboards:*:abc"}},
+			expected:    ErrInvalidScope,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			err := ValidateCustomRole(tt.name, tt.permissions)
+			if tt.expected == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestValidatePermissionsGranted(t *testing.T) {
+	granted := []*Permission{
+		{Action: ActionUsersRolesAdd, Scope: ScopeUsersAll},
+		{Action: ActionDashboardsRead, Scope: "dashboards:uid:abc"},
+		{Action: ActionServerStatsRead},
+	}
+
+	tests := []struct {
+		desc        string
+		permissions []Permission
+		expected    error
+	}{
+		{
+			desc:        "permissions matching a wildcard scope are granted",
+			permissions: []Permission{{Action: ActionUsersRolesAdd, Scope: "users:1"}, {Action: ActionUsersRolesAdd, Scope: ScopeUsersAll}},
+		},
+		{
+			desc:        "permissions with the same scope are granted",
+			permissions: []Permission{{Action: ActionDashboardsRead, Scope: "dashboards:uid:abc"}, {Action: ActionServerStatsRead}},
+		},
+		{
+			desc:        "permissions on a wider scope are not granted",
+			permissions: []Permission{{Action: ActionDashboardsRead, Scope: ScopeDashboardsAll}},
+			expected:    ErrPermissionNotGranted,
+		},
+		{
+			desc:        "permissions without scope are not granted by scoped permissions",
+			permissions: []Permission{{Action: ActionDashboardsRead}},
+			expected:    ErrPermissionNotGranted,
+		},
+		{
+			desc:        "permissions with other actions are not granted",
+			permissions: []Permission{{Action: ActionUsersDelete, Scope: ScopeGlobalUsersAll}},
+			expected:    ErrPermissionNotGranted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			err := ValidatePermissionsGranted(granted, tt.permissions)
+			if tt.expected == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
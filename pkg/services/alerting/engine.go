@@ -13,6 +13,7 @@ import (
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/registry"
 	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/urlsigner"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/ext"
@@ -29,6 +30,7 @@ type AlertEngine struct {
 	RequestValidator models.PluginRequestValidator `inject:""`
 	DataService      plugins.DataRequestHandler    `inject:""`
 	Cfg              *setting.Cfg                  `inject:""`
+	URLSigner        *urlsigner.Service            `inject:""`
 
 	execQueue     chan *Job
 	ticker        *Ticker
@@ -56,7 +58,7 @@ func (e *AlertEngine) Init() error {
 	e.evalHandler = NewEvalHandler(e.DataService)
 	e.ruleReader = newRuleReader()
 	e.log = log.New("alerting.engine")
-	e.resultHandler = newResultHandler(e.RenderService)
+	e.resultHandler = newResultHandler(e.RenderService, e.URLSigner)
 	return nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/grafana/grafana/pkg/bus"
@@ -12,6 +13,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/urlsigner"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -83,16 +85,18 @@ type ShowWhen struct {
 	Is    string `json:"is"`
 }
 
-func newNotificationService(renderService rendering.Service) *notificationService {
+func newNotificationService(renderService rendering.Service, urlSigner *urlsigner.Service) *notificationService {
 	return &notificationService{
 		log:           log.New("alerting.notifier"),
 		renderService: renderService,
+		urlSigner:     urlSigner,
 	}
 }
 
 type notificationService struct {
 	log           log.Logger
 	renderService rendering.Service
+	urlSigner     *urlsigner.Service
 }
 
 func (n *notificationService) SendIfNeeded(evalCtx *EvalContext) error {
@@ -236,6 +240,19 @@ func (n *notificationService) renderAndUploadImage(evalCtx *EvalContext, timeout
 
 	if evalCtx.ImagePublicURL != "" {
 		n.log.Debug("Uploaded alert panel image to external image store", "ruleId", evalCtx.Rule.ID, "url", evalCtx.ImagePublicURL, "took", took)
+		return nil
+	}
+
+	// without an external image store, link to the panel rendered by Grafana through a signed URL,
+	// which grants access to that image only, instead of credentials in the link
+	if n.urlSigner != nil && n.urlSigner.Cfg.URLSigningEnabled {
+		path := fmt.Sprintf("/render/%s&width=%d&height=%d", renderOpts.Path, renderOpts.Width, renderOpts.Height)
+		signed, err := n.urlSigner.SignForRole(path, evalCtx.Rule.OrgID, renderOpts.OrgRole, n.urlSigner.Cfg.URLSigningNotificationTTL)
+		if err != nil {
+			n.log.Warn("Failed to sign alert panel image URL", "ruleId", evalCtx.Rule.ID, "error", err)
+			return nil
+		}
+		evalCtx.ImagePublicURL = strings.TrimSuffix(setting.AppUrl, "/") + signed
 	}
 
 	return nil
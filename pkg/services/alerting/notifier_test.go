@@ -2,6 +2,7 @@ package alerting
 
 import (
 	"context"
+	"net/url"
 	"testing"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/urlsigner"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/stretchr/testify/assert"
 
@@ -151,6 +153,32 @@ func TestNotificationService(t *testing.T) {
 			assert.Equal(t, evalCtxWithoutMatch.Rule.Name, ctx.Rule.Name)
 			assert.Equal(t, evalCtxWithoutMatch.Rule.Message, ctx.Rule.Message)
 		})
+
+	notificationServiceScenario(t, "Given alert rule with upload image enabled and no external image store should link to a signed render URL",
+		evalCtx, true, func(sc *scenarioContext) {
+			signer := &urlsigner.Service{Cfg: &setting.Cfg{
+				URLSigningEnabled:         true,
+				URLSigningDefaultTTL:      time.Hour,
+				URLSigningMaxTTL:          24 * time.Hour,
+				URLSigningNotificationTTL: 24 * time.Hour,
+			}}
+			require.NoError(sc.t, signer.Init())
+			sc.notificationService.urlSigner = signer
+			t.Cleanup(func() { evalCtx.ImagePublicURL = "" })
+
+			err := sc.notificationService.SendIfNeeded(evalCtx)
+			require.NoError(sc.t, err)
+
+			require.Equalf(sc.t, 1, sc.imageUploadCount, "expected image to be uploaded, but wasn't")
+			u, err := url.Parse(evalCtx.ImagePublicURL)
+			require.NoError(sc.t, err)
+			assert.Equal(sc.t, "/render/d-solo/db-uid/", u.Path)
+			assert.Equal(sc.t, "1000", u.Query().Get("width"))
+
+			grant, err := signer.Verify(u)
+			require.NoError(sc.t, err)
+			assert.Equal(sc.t, &urlsigner.Grant{OrgID: evalCtx.Rule.OrgID, OrgRole: models.ROLE_ADMIN}, grant)
+		})
 }
 
 type scenarioContext struct {
@@ -263,7 +291,7 @@ func notificationServiceScenario(t *testing.T, name string, evalCtx *EvalContext
 			},
 		}
 
-		scenarioCtx.notificationService = newNotificationService(renderService)
+		scenarioCtx.notificationService = newNotificationService(renderService, nil)
 		fn(scenarioCtx)
 	})
 }
@@ -365,6 +393,10 @@ func (s *testRenderService) RenderCSV(ctx context.Context, opts rendering.CSVOpt
 	return nil, nil
 }
 
+func (s *testRenderService) RenderPDF(ctx context.Context, opts rendering.PDFOpts) (*rendering.RenderResult, error) {
+	return nil, nil
+}
+
 func (s *testRenderService) RenderErrorImage(err error) (*rendering.RenderResult, error) {
 	if s.renderErrorImageProvider != nil {
 		return s.renderErrorImageProvider(err)
@@ -33,6 +33,12 @@ func init() {
 				PropertyName: "addresses",
 				Required:     true,
 			},
+			{
+				Label:        "Embed image",
+				Description:  "Embed the image of the panel in the email, even when it is uploaded to an external image storage, for the mail clients that block remote content",
+				Element:      alerting.ElementTypeCheckbox,
+				PropertyName: "embedImage",
+			},
 		},
 	})
 }
@@ -43,7 +49,9 @@ type EmailNotifier struct {
 	NotifierBase
 	Addresses   []string
 	SingleEmail bool
-	log         log.Logger
+	// EmbedImage embeds the image of the panel even when it is in an external image storage.
+	EmbedImage bool
+	log        log.Logger
 }
 
 // NewEmailNotifier is the constructor function
@@ -63,6 +71,7 @@ func NewEmailNotifier(model *models.AlertNotification) (alerting.Notifier, error
 		NotifierBase: NewNotifierBase(model),
 		Addresses:    addresses,
 		SingleEmail:  singleEmail,
+		EmbedImage:   model.Settings.Get("embedImage").MustBool(false),
 		log:          log.New("alerting.notifier.email"),
 	}, nil
 }
@@ -106,15 +115,19 @@ func (en *EmailNotifier) Notify(evalContext *alerting.EvalContext) error {
 	}
 
 	if en.NeedsImage() {
-		if evalContext.ImagePublicURL != "" {
-			cmd.Data["ImageLink"] = evalContext.ImagePublicURL
-		} else {
+		embedded := false
+		if evalContext.ImagePublicURL == "" || en.EmbedImage {
 			file, err := os.Stat(evalContext.ImageOnDiskPath)
 			if err == nil {
 				cmd.EmbeddedFiles = []string{evalContext.ImageOnDiskPath}
 				cmd.Data["EmbeddedImage"] = file.Name()
+				embedded = true
 			}
 		}
+		// the image is linked when it can't be embedded
+		if !embedded && evalContext.ImagePublicURL != "" {
+			cmd.Data["ImageLink"] = evalContext.ImagePublicURL
+		}
 	}
 
 	if err := bus.DispatchCtx(evalContext.Ctx, cmd); err != nil {
@@ -36,7 +36,7 @@ func init() {
 }
 
 func handleNotificationTestCommand(ctx context.Context, cmd *NotificationTestCommand) error {
-	notifier := newNotificationService(nil)
+	notifier := newNotificationService(nil, nil)
 
 	model := &models.AlertNotification{
 		Name:     cmd.Name,
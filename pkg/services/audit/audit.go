@@ -0,0 +1,273 @@
+// Package audit records logins, token changes and permission changes in the audit
+// log, and forwards them to the optional file and webhook sinks.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/network"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/registry"
+	"github.com/grafana/grafana/pkg/services/hooks"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+var getTime = time.Now
+
+func init() {
+	registry.RegisterService(&Service{})
+}
+
+type Service struct {
+	Cfg          *setting.Cfg        `inject:""`
+	SQLStore     *sqlstore.SQLStore  `inject:""`
+	HooksService *hooks.HooksService `inject:""`
+
+	log           log.Logger
+	fileMu        sync.Mutex
+	file          *os.File
+	webhookClient *http.Client
+}
+
+func (s *Service) Init() error {
+	s.log = log.New("audit")
+
+	if !s.Cfg.AuditLogEnabled {
+		return nil
+	}
+
+	if s.Cfg.AuditLogFilePath != "" {
+		// nolint:gosec
+		// We can ignore the gosec G304 warning on this one because `file_path` comes from grafana configuration file.
+		file, err := os.OpenFile(s.Cfg.AuditLogFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0640)
+		if err != nil {
+			return fmt.Errorf("failed to open audit log file: %w", err)
+		}
+		s.file = file
+	}
+
+	if s.Cfg.AuditLogWebhookURL != "" {
+		s.webhookClient = &http.Client{Timeout: s.Cfg.AuditLogWebhookTimeout}
+	}
+
+	s.HooksService.AddLoginHook(s.loginHook)
+
+	return nil
+}
+
+// Run removes the entries older than the retention period.
+func (s *Service) Run(ctx context.Context) error {
+	if !s.Cfg.AuditLogEnabled || s.Cfg.AuditLogRetentionDays <= 0 {
+		return nil
+	}
+
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		s.deleteExpiredEntries(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Record adds an entry to the audit log. The user who performed the action, their
+// organization and IP address are read from the request when the entry has no user.
+// Errors are logged, so that the audit log never fails the request.
+func (s *Service) Record(c *models.ReqContext, entry models.AuditEntry) {
+	if !s.Cfg.AuditLogEnabled {
+		return
+	}
+
+	if entry.UserId == 0 && c.IsSignedIn {
+		entry.UserId = c.UserId
+		entry.UserLogin = c.Login
+		if c.UserToken != nil {
+			entry.ImpersonatorUserId = c.UserToken.ImpersonatorUserId
+		}
+	}
+	if entry.OrgId == 0 && c.SignedInUser != nil {
+		entry.OrgId = c.OrgId
+	}
+	if entry.IpAddress == "" {
+		entry.IpAddress = clientIP(c)
+	}
+	entry.Created = getTime()
+
+	if err := s.add(c.Req.Context(), &entry); err != nil {
+		s.log.Error("Failed to add audit log entry", "action", entry.Action, "error", err)
+		return
+	}
+
+	s.writeFile(&entry)
+	s.postWebhook(&entry)
+}
+
+func (s *Service) add(ctx context.Context, entry *models.AuditEntry) error {
+	return s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		_, err := sess.Insert(entry)
+		return err
+	})
+}
+
+// Search returns the entries matching the query, newest first.
+func (s *Service) Search(ctx context.Context, query *models.SearchAuditEntriesQuery) error {
+	if query.Limit <= 0 || query.Limit > 1000 {
+		query.Limit = 1000
+	}
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+
+	whereConditions := []string{"1 = 1"}
+	whereParams := []interface{}{}
+	if query.Action != "" {
+		whereConditions = append(whereConditions, "action = ?")
+		whereParams = append(whereParams, query.Action)
+	}
+	if query.UserId != 0 {
+		whereConditions = append(whereConditions, "user_id = ?")
+		whereParams = append(whereParams, query.UserId)
+	}
+	if query.Target != "" {
+		whereConditions = append(whereConditions, "target = ?")
+		whereParams = append(whereParams, query.Target)
+	}
+	if !query.From.IsZero() {
+		whereConditions = append(whereConditions, "created >= ?")
+		whereParams = append(whereParams, query.From)
+	}
+	if !query.To.IsZero() {
+		whereConditions = append(whereConditions, "created <= ?")
+		whereParams = append(whereParams, query.To)
+	}
+	where := strings.Join(whereConditions, " AND ")
+
+	return s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		entries := make([]*models.AuditEntry, 0)
+		offset := query.Limit * (query.Page - 1)
+		if err := sess.Where(where, whereParams...).Desc("created", "id").Limit(query.Limit, offset).Find(&entries); err != nil {
+			return err
+		}
+
+		count, err := sess.Where(where, whereParams...).Count(&models.AuditEntry{})
+		if err != nil {
+			return err
+		}
+
+		query.Result = models.SearchAuditEntriesQueryResult{
+			TotalCount: count,
+			Entries:    entries,
+			Page:       query.Page,
+			PerPage:    query.Limit,
+		}
+		return nil
+	})
+}
+
+func (s *Service) deleteExpiredEntries(ctx context.Context) {
+	before := getTime().AddDate(0, 0, -s.Cfg.AuditLogRetentionDays)
+	err := s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		res, err := sess.Exec("DELETE FROM audit_log WHERE created < ?", before)
+		if err != nil {
+			return err
+		}
+		if affected, err := res.RowsAffected(); err == nil && affected > 0 {
+			s.log.Debug("Deleted expired audit log entries", "count", affected)
+		}
+		return nil
+	})
+	if err != nil {
+		s.log.Error("Failed to delete expired audit log entries", "error", err)
+	}
+}
+
+func (s *Service) loginHook(info *models.LoginInfo, c *models.ReqContext) {
+	entry := models.AuditEntry{
+		Action:     models.AuditActionLogin,
+		Success:    info.Error == nil,
+		AuthModule: info.AuthModule,
+		UserLogin:  info.LoginUsername,
+	}
+	if info.Error != nil {
+		entry.Action = models.AuditActionLoginFailed
+		entry.Message = info.Error.Error()
+	}
+	if info.User != nil {
+		entry.UserId = info.User.Id
+		entry.UserLogin = info.User.Login
+	} else if entry.UserLogin == "" {
+		entry.UserLogin = info.ExternalUser.Login
+	}
+
+	s.Record(c, entry)
+}
+
+func (s *Service) writeFile(entry *models.AuditEntry) {
+	if s.file == nil {
+		return
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		s.log.Error("Failed to marshal audit log entry", "error", err)
+		return
+	}
+
+	s.fileMu.Lock()
+	defer s.fileMu.Unlock()
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		s.log.Error("Failed to write audit log file", "error", err)
+	}
+}
+
+// postWebhook sends the entry to the webhook in the background, so that slow
+// receivers don't delay the request.
+func (s *Service) postWebhook(entry *models.AuditEntry) {
+	if s.webhookClient == nil {
+		return
+	}
+
+	body, err := json.Marshal(entry)
+	if err != nil {
+		s.log.Error("Failed to marshal audit log entry", "error", err)
+		return
+	}
+
+	go func() {
+		resp, err := s.webhookClient.Post(s.Cfg.AuditLogWebhookURL, "application/json", bytes.NewReader(body))
+		if err != nil {
+			s.log.Error("Failed to send audit log entry to webhook", "error", err)
+			return
+		}
+		defer func() {
+			if err := resp.Body.Close(); err != nil {
+				s.log.Warn("Failed to close response body", "err", err)
+			}
+		}()
+		if resp.StatusCode/100 != 2 {
+			s.log.Error("Audit log webhook returned an error", "status", resp.Status)
+		}
+	}()
+}
+
+func clientIP(c *models.ReqContext) string {
+	ip, err := network.GetIPFromAddress(c.RemoteAddr())
+	if err != nil {
+		return ""
+	}
+	return ip.String()
+}
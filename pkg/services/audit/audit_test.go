@@ -0,0 +1,119 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/hooks"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/macaron.v1"
+)
+
+func TestService(t *testing.T) {
+	sqlStore := sqlstore.InitTestDB(t)
+	filePath := filepath.Join(t.TempDir(), "audit.log")
+	s := &Service{
+		Cfg: &setting.Cfg{
+			AuditLogEnabled:  true,
+			AuditLogFilePath: filePath,
+		},
+		SQLStore:     sqlStore,
+		HooksService: &hooks.HooksService{},
+	}
+	require.NoError(t, s.Init())
+	t.Cleanup(func() {
+		require.NoError(t, s.file.Close())
+	})
+
+	origGetTime := getTime
+	t.Cleanup(func() {
+		getTime = origGetTime
+	})
+	now := time.Date(2021, time.June, 1, 12, 0, 0, 0, time.UTC)
+	getTime = func() time.Time {
+		return now
+	}
+
+	newContext := func(user *models.SignedInUser) *models.ReqContext {
+		return &models.ReqContext{
+			Context: &macaron.Context{
+				Req: macaron.Request{Request: httptest.NewRequest("POST", "/", nil)},
+			},
+			SignedInUser: user,
+			IsSignedIn:   user.UserId != 0,
+		}
+	}
+
+	admin := newContext(&models.SignedInUser{UserId: 1, OrgId: 2, Login: "admin"})
+	s.Record(admin, models.AuditEntry{Action: models.AuditActionAPIKeyDeleted, Success: true, Target: "api-key:3"})
+	now = now.Add(time.Minute)
+	s.loginHook(&models.LoginInfo{LoginUsername: "unknown", Error: models.ErrUserNotFound}, newContext(&models.SignedInUser{}))
+
+	t.Run("Entries are filled from the request", func(t *testing.T) {
+		query := models.SearchAuditEntriesQuery{Action: models.AuditActionAPIKeyDeleted}
+		require.NoError(t, s.Search(context.Background(), &query))
+
+		require.Len(t, query.Result.Entries, 1)
+		entry := query.Result.Entries[0]
+		assert.Equal(t, int64(1), entry.UserId)
+		assert.Equal(t, "admin", entry.UserLogin)
+		assert.Equal(t, int64(2), entry.OrgId)
+		assert.Equal(t, "192.0.2.1", entry.IpAddress)
+		assert.Equal(t, "api-key:3", entry.Target)
+		assert.True(t, entry.Success)
+	})
+
+	t.Run("Entries are returned newest first", func(t *testing.T) {
+		query := models.SearchAuditEntriesQuery{}
+		require.NoError(t, s.Search(context.Background(), &query))
+
+		require.Len(t, query.Result.Entries, 2)
+		assert.Equal(t, int64(2), query.Result.TotalCount)
+		entry := query.Result.Entries[0]
+		assert.Equal(t, models.AuditActionLoginFailed, entry.Action)
+		assert.Equal(t, "unknown", entry.UserLogin)
+		assert.Equal(t, models.ErrUserNotFound.Error(), entry.Message)
+		assert.False(t, entry.Success)
+	})
+
+	t.Run("Entries are filtered by time", func(t *testing.T) {
+		query := models.SearchAuditEntriesQuery{To: now.Add(-time.Second)}
+		require.NoError(t, s.Search(context.Background(), &query))
+
+		require.Len(t, query.Result.Entries, 1)
+		assert.Equal(t, models.AuditActionAPIKeyDeleted, query.Result.Entries[0].Action)
+	})
+
+	t.Run("Entries are written to the file", func(t *testing.T) {
+		content, err := ioutil.ReadFile(filePath)
+		require.NoError(t, err)
+
+		var entry models.AuditEntry
+		lines := bytes.Split(bytes.TrimSpace(content), []byte("\n"))
+		require.Len(t, lines, 2)
+		require.NoError(t, json.Unmarshal(lines[1], &entry))
+		assert.Equal(t, models.AuditActionLoginFailed, entry.Action)
+	})
+
+	t.Run("Expired entries are deleted", func(t *testing.T) {
+		s.Cfg.AuditLogRetentionDays = 1
+		// the first entry is 30 seconds past the retention, and the second 30 seconds short of it
+		now = now.Add(24*time.Hour - 30*time.Second)
+		s.deleteExpiredEntries(context.Background())
+
+		query := models.SearchAuditEntriesQuery{}
+		require.NoError(t, s.Search(context.Background(), &query))
+		require.Len(t, query.Result.Entries, 1)
+		assert.Equal(t, models.AuditActionLoginFailed, query.Result.Entries[0].Action)
+	})
+}
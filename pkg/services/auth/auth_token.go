@@ -10,6 +10,8 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/serverlock"
 
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/registry"
@@ -33,6 +35,7 @@ var getTime = time.Now
 const urgentRotateTime = 1 * time.Minute
 
 type UserAuthTokenService struct {
+	Bus               bus.Bus                       `inject:""`
 	SQLStore          *sqlstore.SQLStore            `inject:""`
 	ServerLockService *serverlock.ServerLockService `inject:""`
 	Cfg               *setting.Cfg                  `inject:""`
@@ -41,6 +44,20 @@ type UserAuthTokenService struct {
 
 func (s *UserAuthTokenService) Init() error {
 	s.log = log.New("auth")
+	s.Bus.AddEventListener(s.onUserCredentialsRevoked)
+	return nil
+}
+
+// onUserCredentialsRevoked signs the user out of all of their sessions. Tokens are
+// stored in the database, so they stop working on every instance at once.
+func (s *UserAuthTokenService) onUserCredentialsRevoked(evt *events.UserCredentialsRevoked) error {
+	if err := s.RevokeAllUserTokens(context.Background(), evt.UserId); err != nil {
+		// the change itself succeeded, don't fail it because the sessions couldn't be revoked
+		s.log.Error("Failed to revoke user tokens", "userId", evt.UserId, "reason", evt.Reason, "error", err)
+		return nil
+	}
+
+	s.log.Info("Revoked all user tokens", "userId", evt.UserId, "reason", evt.Reason)
 	return nil
 }
 
@@ -61,6 +78,16 @@ func (s *UserAuthTokenService) ActiveTokenCount(ctx context.Context) (int64, err
 }
 
 func (s *UserAuthTokenService) CreateToken(ctx context.Context, user *models.User, clientIP net.IP, userAgent string) (*models.UserToken, error) {
+	return s.createToken(ctx, user, 0, clientIP, userAgent)
+}
+
+// CreateImpersonationToken creates a token for a Grafana Admin to act as the user. The token expires
+// after the impersonation session duration, regardless of the login lifetime settings.
+func (s *UserAuthTokenService) CreateImpersonationToken(ctx context.Context, user *models.User, impersonatorID int64, clientIP net.IP, userAgent string) (*models.UserToken, error) {
+	return s.createToken(ctx, user, impersonatorID, clientIP, userAgent)
+}
+
+func (s *UserAuthTokenService) createToken(ctx context.Context, user *models.User, impersonatorID int64, clientIP net.IP, userAgent string) (*models.UserToken, error) {
 	token, err := util.RandomHex(16)
 	if err != nil {
 		return nil, err
@@ -86,6 +113,8 @@ func (s *UserAuthTokenService) CreateToken(ctx context.Context, user *models.Use
 		SeenAt:        0,
 		RevokedAt:     0,
 		AuthTokenSeen: false,
+
+		ImpersonatorUserId: impersonatorID,
 	}
 
 	err = s.SQLStore.WithDbSession(ctx, func(dbSession *sqlstore.DBSession) error {
@@ -142,6 +171,13 @@ func (s *UserAuthTokenService) LookupToken(ctx context.Context, unhashedToken st
 		}
 	}
 
+	if model.ImpersonatorUserId != 0 && model.CreatedAt <= getTime().Add(-s.Cfg.ImpersonationSessionDuration).Unix() {
+		return nil, &models.TokenExpiredError{
+			UserID:  model.UserId,
+			TokenID: model.Id,
+		}
+	}
+
 	if model.AuthToken != hashedToken && model.PrevAuthToken == hashedToken && model.AuthTokenSeen {
 		modelCopy := model
 		modelCopy.AuthTokenSeen = false
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/setting"
 
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -130,6 +131,18 @@ func TestUserAuthToken(t *testing.T) {
 					So(err, ShouldBeNil)
 					So(model2, ShouldBeNil)
 				})
+
+				Convey("Revoking the user's credentials revokes all user tokens", func() {
+					err := userAuthTokenService.onUserCredentialsRevoked(&events.UserCredentialsRevoked{
+						UserId: userID,
+						Reason: events.CredentialsRevokedPasswordChanged,
+					})
+					So(err, ShouldBeNil)
+
+					tokens, err := userAuthTokenService.GetUserTokens(context.Background(), userID)
+					So(err, ShouldBeNil)
+					So(tokens, ShouldBeEmpty)
+				})
 			})
 
 			Convey("When revoking users tokens in a batch", func() {
@@ -237,6 +250,29 @@ func TestUserAuthToken(t *testing.T) {
 			})
 		})
 
+		Convey("impersonation token expires after the impersonation session duration", func() {
+			userToken, err := userAuthTokenService.CreateImpersonationToken(context.Background(), user, 1,
+				net.ParseIP("192.168.10.11"), "some user agent")
+			So(err, ShouldBeNil)
+			So(userToken.ImpersonatorUserId, ShouldEqual, 1)
+
+			getTime = func() time.Time {
+				return t.Add(time.Hour).Add(-time.Second)
+			}
+
+			stillGood, err := userAuthTokenService.LookupToken(context.Background(), userToken.UnhashedToken)
+			So(err, ShouldBeNil)
+			So(stillGood.ImpersonatorUserId, ShouldEqual, 1)
+
+			getTime = func() time.Time {
+				return t.Add(time.Hour)
+			}
+
+			notGood, err := userAuthTokenService.LookupToken(context.Background(), userToken.UnhashedToken)
+			So(err, ShouldHaveSameTypeAs, &models.TokenExpiredError{})
+			So(notGood, ShouldBeNil)
+		})
+
 		Convey("can properly rotate tokens", func() {
 			userToken, err := userAuthTokenService.CreateToken(context.Background(), user,
 				net.ParseIP("192.168.10.11"), "some user agent")
@@ -531,6 +567,7 @@ func createTestContext(t *testing.T) *testContext {
 			LoginMaxInactiveLifetime:     maxInactiveDurationVal,
 			LoginMaxLifetime:             maxLifetimeDurationVal,
 			TokenRotationIntervalMinutes: 10,
+			ImpersonationSessionDuration: time.Hour,
 		},
 		log: log.New("test-logger"),
 	}
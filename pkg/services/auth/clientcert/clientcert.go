@@ -0,0 +1,314 @@
+// Package clientcert configures the HTTPS listener to verify TLS client certificates,
+// and maps verified certificates to Grafana logins.
+package clientcert
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/registry"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const ServiceName = "ClientCertAuthService"
+
+func init() {
+	registry.Register(&registry.Descriptor{
+		Name:         ServiceName,
+		Instance:     &Service{},
+		InitPriority: registry.Medium,
+	})
+}
+
+var (
+	ErrCertificateRevoked = errors.New("client certificate is revoked")
+	ErrNoUsername         = errors.New("client certificate has no username")
+)
+
+type Service struct {
+	Cfg *setting.Cfg `inject:""`
+
+	log     log.Logger
+	caCerts []*x509.Certificate
+	caPool  *x509.CertPool
+
+	crlMu      sync.Mutex
+	crl        *pkix.CertificateList
+	crlModTime time.Time
+
+	ocspClient *http.Client
+	ocspMu     sync.Mutex
+	ocspCache  map[string]*ocsp.Response
+}
+
+func (s *Service) Init() error {
+	if !s.Cfg.ClientCertAuthEnabled {
+		return nil
+	}
+
+	s.log = log.New("auth.clientcert")
+
+	if s.Cfg.Protocol != setting.HTTPSScheme && s.Cfg.Protocol != setting.HTTP2Scheme {
+		return errors.New("client certificate authentication requires protocol https or h2")
+	}
+
+	if err := s.loadCAs(); err != nil {
+		return err
+	}
+
+	if s.Cfg.ClientCertCRLFile != "" {
+		if err := s.loadCRL(); err != nil {
+			return err
+		}
+	}
+
+	s.ocspClient = &http.Client{Timeout: s.Cfg.ClientCertOCSPTimeout}
+	s.ocspCache = map[string]*ocsp.Response{}
+
+	return nil
+}
+
+// ConfigureTLS makes the TLS listener ask clients for certificates issued by the configured CAs.
+func (s *Service) ConfigureTLS(tlsCfg *tls.Config) {
+	if !s.Cfg.ClientCertAuthEnabled {
+		return
+	}
+
+	tlsCfg.ClientCAs = s.caPool
+	tlsCfg.ClientAuth = tls.VerifyClientCertIfGiven
+	if s.Cfg.ClientCertRequired {
+		tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	tlsCfg.VerifyPeerCertificate = s.verifyPeerCertificate
+}
+
+func (s *Service) loadCAs() error {
+	if s.Cfg.ClientCertCAFile == "" {
+		return errors.New("client certificate authentication requires a ca_file")
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `ca_file` comes from grafana configuration file.
+	data, err := ioutil.ReadFile(s.Cfg.ClientCertCAFile)
+	if err != nil {
+		return fmt.Errorf("failed to read client certificate ca_file: %w", err)
+	}
+
+	s.caPool = x509.NewCertPool()
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("failed to parse client certificate ca_file: %w", err)
+		}
+		s.caCerts = append(s.caCerts, cert)
+		s.caPool.AddCert(cert)
+	}
+
+	if len(s.caCerts) == 0 {
+		return fmt.Errorf("no certificate found in client certificate ca_file %q", s.Cfg.ClientCertCAFile)
+	}
+
+	return nil
+}
+
+// loadCRL reads the CRL file, and only accepts CRLs signed by one of the configured CAs.
+func (s *Service) loadCRL() error {
+	info, err := os.Stat(s.Cfg.ClientCertCRLFile)
+	if err != nil {
+		return fmt.Errorf("failed to read client certificate crl_file: %w", err)
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `crl_file` comes from grafana configuration file.
+	data, err := ioutil.ReadFile(s.Cfg.ClientCertCRLFile)
+	if err != nil {
+		return fmt.Errorf("failed to read client certificate crl_file: %w", err)
+	}
+
+	crl, err := x509.ParseCRL(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse client certificate crl_file: %w", err)
+	}
+
+	signed := false
+	for _, ca := range s.caCerts {
+		if ca.CheckCRLSignature(crl) == nil {
+			signed = true
+			break
+		}
+	}
+	if !signed {
+		return errors.New("client certificate crl_file is not signed by a CA of ca_file")
+	}
+
+	if crl.HasExpired(time.Now()) {
+		s.log.Warn("Client certificate CRL has expired", "file", s.Cfg.ClientCertCRLFile, "nextUpdate", crl.TBSCertList.NextUpdate)
+	}
+
+	s.crl = crl
+	s.crlModTime = info.ModTime()
+
+	return nil
+}
+
+// verifyPeerCertificate checks the revocation status of the client certificate, once the
+// TLS stack has verified its chain against the configured CAs.
+func (s *Service) verifyPeerCertificate(_ [][]byte, verifiedChains [][]*x509.Certificate) error {
+	if len(verifiedChains) == 0 || len(verifiedChains[0]) == 0 {
+		return nil
+	}
+
+	chain := verifiedChains[0]
+	cert := chain[0]
+
+	if err := s.checkCRL(cert); err != nil {
+		s.log.Warn("Rejected client certificate", "subject", cert.Subject.String(), "serial", cert.SerialNumber.String(), "error", err)
+		return err
+	}
+
+	if s.Cfg.ClientCertOCSPEnabled && len(chain) > 1 {
+		if err := s.checkOCSP(cert, chain[1]); err != nil {
+			s.log.Warn("Rejected client certificate", "subject", cert.Subject.String(), "serial", cert.SerialNumber.String(), "error", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) checkCRL(cert *x509.Certificate) error {
+	if s.Cfg.ClientCertCRLFile == "" {
+		return nil
+	}
+
+	s.crlMu.Lock()
+	defer s.crlMu.Unlock()
+
+	// reload the CRL when it's replaced, keeping the previous one if the new one is invalid
+	if info, err := os.Stat(s.Cfg.ClientCertCRLFile); err == nil && !info.ModTime().Equal(s.crlModTime) {
+		if err := s.loadCRL(); err != nil {
+			s.log.Error("Failed to reload client certificate CRL", "error", err)
+		}
+	}
+
+	for _, revoked := range s.crl.TBSCertList.RevokedCertificates {
+		if revoked.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+			return ErrCertificateRevoked
+		}
+	}
+
+	return nil
+}
+
+// checkOCSP asks the OCSP responder of the certificate for its status, caching the
+// responses until their next update.
+func (s *Service) checkOCSP(cert, issuer *x509.Certificate) error {
+	if len(cert.OCSPServer) == 0 {
+		return nil
+	}
+
+	cacheKey := string(issuer.SubjectKeyId) + "/" + cert.SerialNumber.String()
+
+	s.ocspMu.Lock()
+	resp, ok := s.ocspCache[cacheKey]
+	s.ocspMu.Unlock()
+
+	if !ok || time.Now().After(resp.NextUpdate) {
+		var err error
+		resp, err = s.queryOCSP(cert, issuer)
+		if err != nil {
+			return err
+		}
+
+		if !resp.NextUpdate.IsZero() {
+			s.ocspMu.Lock()
+			s.ocspCache[cacheKey] = resp
+			s.ocspMu.Unlock()
+		}
+	}
+
+	switch resp.Status {
+	case ocsp.Good:
+		return nil
+	case ocsp.Revoked:
+		return ErrCertificateRevoked
+	default:
+		return errors.New("client certificate status is unknown to the OCSP responder")
+	}
+}
+
+func (s *Service) queryOCSP(cert, issuer *x509.Certificate) (*ocsp.Response, error) {
+	req, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	httpResp, err := s.ocspClient.Post(cert.OCSPServer[0], "application/ocsp-request", bytes.NewReader(req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query OCSP responder: %w", err)
+	}
+	defer func() {
+		if err := httpResp.Body.Close(); err != nil {
+			s.log.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OCSP responder returned status %d", httpResp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return ocsp.ParseResponseForCert(body, cert, issuer)
+}
+
+// Username returns the Grafana login of a client certificate, read from the field given
+// by the username_source setting.
+func Username(cert *x509.Certificate, source string) (string, error) {
+	var username string
+	switch source {
+	case "san_email":
+		if len(cert.EmailAddresses) > 0 {
+			username = cert.EmailAddresses[0]
+		}
+	case "san_dns":
+		if len(cert.DNSNames) > 0 {
+			username = cert.DNSNames[0]
+		}
+	case "san_uri":
+		if len(cert.URIs) > 0 {
+			username = cert.URIs[0].String()
+		}
+	default:
+		username = cert.Subject.CommonName
+	}
+
+	if username == "" {
+		return "", ErrNoUsername
+	}
+	return username, nil
+}
@@ -0,0 +1,130 @@
+package clientcert
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsername(t *testing.T) {
+	uri, err := url.Parse("spiffe://example.com/deploy")
+	require.NoError(t, err)
+	cert := &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "deploy-bot"},
+		EmailAddresses: []string{"deploy@example.com"},
+		DNSNames:       []string{"deploy.example.com"},
+		URIs:           []*url.URL{uri},
+	}
+
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{source: "subject_cn", expected: "deploy-bot"},
+		{source: "san_email", expected: "deploy@example.com"},
+		{source: "san_dns", expected: "deploy.example.com"},
+		{source: "san_uri", expected: "spiffe://example.com/deploy"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.source, func(t *testing.T) {
+			username, err := Username(cert, test.source)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, username)
+		})
+	}
+
+	t.Run("Missing field", func(t *testing.T) {
+		_, err := Username(&x509.Certificate{}, "san_email")
+		require.ErrorIs(t, err, ErrNoUsername)
+	})
+}
+
+func TestService_CRL(t *testing.T) {
+	dir := t.TempDir()
+
+	caCert, caKey, caDER := newTestCA(t)
+
+	caFile := filepath.Join(dir, "ca.pem")
+	require.NoError(t, ioutil.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}), 0600))
+
+	crlDER, err := caCert.CreateCRL(rand.Reader, caKey, []pkix.RevokedCertificate{
+		{SerialNumber: big.NewInt(42), RevocationTime: time.Now()},
+	}, time.Now(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	crlFile := filepath.Join(dir, "ca.crl")
+	require.NoError(t, ioutil.WriteFile(crlFile, crlDER, 0600))
+
+	s := &Service{
+		Cfg: &setting.Cfg{
+			Protocol:              setting.HTTPSScheme,
+			ClientCertAuthEnabled: true,
+			ClientCertCAFile:      caFile,
+			ClientCertCRLFile:     crlFile,
+		},
+	}
+	require.NoError(t, s.Init())
+
+	t.Run("Revoked certificate is rejected", func(t *testing.T) {
+		err := s.verifyPeerCertificate(nil, [][]*x509.Certificate{{{SerialNumber: big.NewInt(42)}, caCert}})
+		require.ErrorIs(t, err, ErrCertificateRevoked)
+	})
+
+	t.Run("Certificate that isn't revoked is accepted", func(t *testing.T) {
+		err := s.verifyPeerCertificate(nil, [][]*x509.Certificate{{{SerialNumber: big.NewInt(43)}, caCert}})
+		require.NoError(t, err)
+	})
+
+	t.Run("CRL of another CA is refused", func(t *testing.T) {
+		otherCert, otherKey, _ := newTestCA(t)
+		otherDER, err := otherCert.CreateCRL(rand.Reader, otherKey, nil, time.Now(), time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		otherFile := filepath.Join(dir, "other.crl")
+		require.NoError(t, ioutil.WriteFile(otherFile, otherDER, 0600))
+
+		other := &Service{
+			Cfg: &setting.Cfg{
+				Protocol:              setting.HTTPSScheme,
+				ClientCertAuthEnabled: true,
+				ClientCertCAFile:      caFile,
+				ClientCertCRLFile:     otherFile,
+			},
+		}
+		require.Error(t, other.Init())
+	})
+}
+
+func newTestCA(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert, key, der
+}
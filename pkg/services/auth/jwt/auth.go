@@ -26,7 +26,7 @@ type AuthService struct {
 	Cfg         *setting.Cfg             `inject:""`
 	RemoteCache *remotecache.RemoteCache `inject:""`
 
-	keySet           keySet
+	keySet           KeySet
 	log              log.Logger
 	expect           map[string]interface{}
 	expectRegistered jwt.Expected
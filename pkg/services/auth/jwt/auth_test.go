@@ -156,6 +156,9 @@ func TestCachingJWKHTTPResponse(t *testing.T) {
 	jwkCachingScenario(t, "respects TTL setting", func(t *testing.T, sc cachingScenarioContext) {
 		var err error
 
+		// Keep unknown key IDs from refreshing the key set.
+		sc.authJWTSvc.keySet.(*keySetHTTP).lastRefreshed = time.Now()
+
 		token0 := sign(t, &jwKeys[0], jwt.Claims{Subject: subject})
 		token1 := sign(t, &jwKeys[1], jwt.Claims{Subject: subject})
 
@@ -178,6 +181,23 @@ func TestCachingJWKHTTPResponse(t *testing.T) {
 		cfg.JWTAuthCacheTTL = time.Second
 	})
 
+	jwkCachingScenario(t, "refreshes the key set on unknown key ID", func(t *testing.T, sc cachingScenarioContext) {
+		var err error
+
+		_, err = sc.authJWTSvc.Verify(sc.ctx, sign(t, &jwKeys[0], jwt.Claims{Subject: subject}))
+		require.NoError(t, err)
+		_, err = sc.authJWTSvc.Verify(sc.ctx, sign(t, &jwKeys[1], jwt.Claims{Subject: subject}))
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, *sc.reqCount)
+
+		// Refreshes are rate limited.
+		_, err = sc.authJWTSvc.Verify(sc.ctx, sign(t, &jwKeys[2], jwt.Claims{Subject: subject}))
+		require.Error(t, err)
+
+		assert.Equal(t, 2, *sc.reqCount)
+	})
+
 	jwkCachingScenario(t, "does not cache the response when TTL is zero", func(t *testing.T, sc cachingScenarioContext) {
 		for i := 0; i < 2; i++ {
 			_, err := sc.authJWTSvc.Verify(sc.ctx, sign(t, &jwKeys[i], jwt.Claims{Subject: subject}))
@@ -271,6 +291,42 @@ func TestClaimValidation(t *testing.T) {
 		cfg.JWTAuthExpectClaims = `{"my-str": "foo", "my-number": 123}`
 	})
 
+	scenario(t, "validates iss field against allowed issuers", func(t *testing.T, sc scenarioContext) {
+		var err error
+
+		_, err = sc.authJWTSvc.Verify(sc.ctx, sign(t, key, jwt.Claims{Issuer: "http://foo"}))
+		require.NoError(t, err)
+
+		_, err = sc.authJWTSvc.Verify(sc.ctx, sign(t, key, jwt.Claims{Issuer: "http://bar"}))
+		require.NoError(t, err)
+
+		_, err = sc.authJWTSvc.Verify(sc.ctx, sign(t, key, jwt.Claims{Issuer: "http://baz"}))
+		require.Error(t, err)
+
+		_, err = sc.authJWTSvc.Verify(sc.ctx, sign(t, key, jwt.Claims{Subject: "foo"}))
+		require.Error(t, err)
+	}, configurePKIXPublicKeyFile, func(t *testing.T, cfg *setting.Cfg) {
+		cfg.JWTAuthAllowedIssuers = []string{"http://foo", "http://bar"}
+	})
+
+	scenario(t, "validates aud field against allowed audiences", func(t *testing.T, sc scenarioContext) {
+		var err error
+
+		_, err = sc.authJWTSvc.Verify(sc.ctx, sign(t, key, jwt.Claims{Audience: []string{"foo"}}))
+		require.NoError(t, err)
+
+		_, err = sc.authJWTSvc.Verify(sc.ctx, sign(t, key, jwt.Claims{Audience: []string{"baz", "bar"}}))
+		require.NoError(t, err)
+
+		_, err = sc.authJWTSvc.Verify(sc.ctx, sign(t, key, jwt.Claims{Audience: []string{"baz"}}))
+		require.Error(t, err)
+
+		_, err = sc.authJWTSvc.Verify(sc.ctx, sign(t, key, jwt.Claims{Subject: "foo"}))
+		require.Error(t, err)
+	}, configurePKIXPublicKeyFile, func(t *testing.T, cfg *setting.Cfg) {
+		cfg.JWTAuthAllowedAudiences = []string{"foo", "bar"}
+	})
+
 	scenario(t, "validates exp claim of the token", func(t *testing.T, sc scenarioContext) {
 		var err error
 
@@ -13,6 +13,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -25,7 +26,13 @@ var ErrKeySetIsNotConfigured = errors.New("key set for jwt verification is not c
 var ErrKeySetConfigurationAmbiguous = errors.New("key set configuration is ambiguous: you should set either key_file, jwk_set_file or jwk_set_url")
 var ErrJWTSetURLMustHaveHTTPSScheme = errors.New("jwt_set_url must have https scheme")
 
-type keySet interface {
+// jwksMinRefreshInterval limits how often an unknown key ID can force the
+// key set to be fetched again, so that tokens with made-up key IDs can't be
+// used to flood the key set endpoint.
+const jwksMinRefreshInterval = time.Minute
+
+// KeySet returns the keys of a JSON Web Key Set with a key ID.
+type KeySet interface {
 	Key(ctx context.Context, kid string) ([]jose.JSONWebKey, error)
 }
 
@@ -40,6 +47,9 @@ type keySetHTTP struct {
 	cache           *remotecache.RemoteCache
 	cacheKey        string
 	cacheExpiration time.Duration
+
+	refreshMu     sync.Mutex
+	lastRefreshed time.Time
 }
 
 func (s *AuthService) checkKeySetConfiguration() error {
@@ -150,27 +160,34 @@ func (s *AuthService) initKeySet() error {
 		if urlParsed.Scheme != "https" {
 			return ErrJWTSetURLMustHaveHTTPSScheme
 		}
-		s.keySet = &keySetHTTP{
-			url:             urlStr,
-			log:             s.log,
-			client:          &http.Client{},
-			cacheKey:        fmt.Sprintf("auth-jwt:jwk-%s", urlStr),
-			cacheExpiration: s.Cfg.JWTAuthCacheTTL,
-			cache:           s.RemoteCache,
-		}
+		s.keySet = NewKeySetHTTP(urlStr, &http.Client{}, s.RemoteCache, s.Cfg.JWTAuthCacheTTL, s.log)
 	}
 
 	return nil
 }
 
+// NewKeySetHTTP returns the key set published at the URL, which is fetched with the client.
+// The key set is cached for the cache expiration, or fetched for every key when it is 0. The
+// cached key set is fetched again when a key ID is unknown, at most once a minute.
+func NewKeySetHTTP(url string, client *http.Client, cache *remotecache.RemoteCache, cacheExpiration time.Duration, logger log.Logger) KeySet {
+	return &keySetHTTP{
+		url:             url,
+		log:             logger,
+		client:          client,
+		cacheKey:        fmt.Sprintf("auth-jwt:jwk-%s", url),
+		cacheExpiration: cacheExpiration,
+		cache:           cache,
+	}
+}
+
 func (ks keySetJWKS) Key(ctx context.Context, keyID string) ([]jose.JSONWebKey, error) {
 	return ks.JSONWebKeySet.Key(keyID), nil
 }
 
-func (ks *keySetHTTP) getJWKS(ctx context.Context) (keySetJWKS, error) {
+func (ks *keySetHTTP) getJWKS(ctx context.Context, skipCache bool) (keySetJWKS, error) {
 	var jwks keySetJWKS
 
-	if ks.cacheExpiration > 0 {
+	if ks.cacheExpiration > 0 && !skipCache {
 		if val, err := ks.cache.Get(ks.cacheKey); err == nil {
 			err := json.Unmarshal(val.([]byte), &jwks)
 			return jwks, err
@@ -194,6 +211,10 @@ func (ks *keySetHTTP) getJWKS(ctx context.Context) (keySetJWKS, error) {
 		}
 	}()
 
+	if resp.StatusCode != http.StatusOK {
+		return jwks, fmt.Errorf("failed to get key set: %s", resp.Status)
+	}
+
 	var jsonBuf bytes.Buffer
 	if err := json.NewDecoder(io.TeeReader(resp.Body, &jsonBuf)).Decode(&jwks); err != nil {
 		return jwks, err
@@ -205,10 +226,37 @@ func (ks *keySetHTTP) getJWKS(ctx context.Context) (keySetJWKS, error) {
 	return jwks, err
 }
 
-func (ks keySetHTTP) Key(ctx context.Context, kid string) ([]jose.JSONWebKey, error) {
-	jwks, err := ks.getJWKS(ctx)
+func (ks *keySetHTTP) Key(ctx context.Context, kid string) ([]jose.JSONWebKey, error) {
+	jwks, err := ks.getJWKS(ctx, false)
 	if err != nil {
 		return nil, err
 	}
+
+	keys, err := jwks.Key(ctx, kid)
+	if err != nil || len(keys) > 0 || kid == "" || ks.cacheExpiration <= 0 {
+		return keys, err
+	}
+
+	// The key ID is unknown to the cached key set, which usually means that
+	// the identity provider has rotated its keys.
+	if !ks.allowRefresh() {
+		return keys, nil
+	}
+
+	ks.log.Debug("Key ID not found in cached key set, refreshing", "url", ks.url, "kid", kid)
+	if jwks, err = ks.getJWKS(ctx, true); err != nil {
+		return nil, err
+	}
 	return jwks.Key(ctx, kid)
 }
+
+func (ks *keySetHTTP) allowRefresh() bool {
+	ks.refreshMu.Lock()
+	defer ks.refreshMu.Unlock()
+
+	if time.Since(ks.lastRefreshed) < jwksMinRefreshInterval {
+		return false
+	}
+	ks.lastRefreshed = time.Now()
+	return true
+}
@@ -123,6 +123,13 @@ func (s *AuthService) validateClaims(claims models.JWTClaims) error {
 		return err
 	}
 
+	if allowed := s.Cfg.JWTAuthAllowedIssuers; len(allowed) > 0 && !containsAny(allowed, registeredClaims.Issuer) {
+		return fmt.Errorf("issuer %q is not allowed", registeredClaims.Issuer)
+	}
+	if allowed := s.Cfg.JWTAuthAllowedAudiences; len(allowed) > 0 && !containsAny(allowed, registeredClaims.Audience...) {
+		return fmt.Errorf("none of the audiences %q is allowed", registeredClaims.Audience)
+	}
+
 	for key, expected := range s.expect {
 		value, ok := claims[key]
 		if !ok {
@@ -135,3 +142,14 @@ func (s *AuthService) validateClaims(claims models.JWTClaims) error {
 
 	return nil
 }
+
+func containsAny(allowed []string, values ...string) bool {
+	for _, a := range allowed {
+		for _, v := range values {
+			if a == v {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+// Package kerberos verifies the Kerberos tickets sent by clients in SPNEGO
+// Negotiate Authorization headers.
+package kerberos
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/jcmturner/gokrb5/v8/keytab"
+	"github.com/jcmturner/gokrb5/v8/service"
+	"github.com/jcmturner/gokrb5/v8/spnego"
+	"github.com/jcmturner/gokrb5/v8/types"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/registry"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const ServiceName = "KerberosAuthService"
+
+func init() {
+	registry.Register(&registry.Descriptor{
+		Name:         ServiceName,
+		Instance:     &AuthService{},
+		InitPriority: registry.Medium,
+	})
+}
+
+var errNotAPReq = errors.New("token is not a Kerberos AP-REQ")
+
+type AuthService struct {
+	Cfg *setting.Cfg `inject:""`
+
+	keytab *keytab.Keytab
+	log    log.Logger
+}
+
+func (s *AuthService) Init() error {
+	if !s.Cfg.KerberosAuthEnabled {
+		return nil
+	}
+
+	s.log = log.New("auth.kerberos")
+
+	if s.Cfg.KerberosKeytabFile == "" {
+		return errors.New("kerberos authentication requires a keytab_file")
+	}
+
+	kt, err := keytab.Load(s.Cfg.KerberosKeytabFile)
+	if err != nil {
+		return fmt.Errorf("failed to load Kerberos keytab %q: %w", s.Cfg.KerberosKeytabFile, err)
+	}
+	s.keytab = kt
+
+	return nil
+}
+
+// Verify verifies the Kerberos ticket of a Negotiate Authorization header, either wrapped
+// in an SPNEGO token or sent as a raw Kerberos token, and returns the client principal.
+func (s *AuthService) Verify(ctx context.Context, negotiateToken string, clientIP net.IP) (*models.KerberosPrincipal, error) {
+	b, err := base64.StdEncoding.DecodeString(negotiateToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode Negotiate token: %w", err)
+	}
+
+	mechToken := b
+	var st spnego.SPNEGOToken
+	if err := st.Unmarshal(b); err == nil {
+		if !st.Init {
+			return nil, errors.New("SPNEGO token is not a NegTokenInit")
+		}
+		mechToken = st.NegTokenInit.MechTokenBytes
+	}
+
+	var krb5Token spnego.KRB5Token
+	if err := krb5Token.Unmarshal(mechToken); err != nil {
+		return nil, err
+	}
+	if !krb5Token.IsAPReq() {
+		return nil, errNotAPReq
+	}
+
+	options := []func(*service.Settings){}
+	if s.Cfg.KerberosServicePrincipal != "" {
+		options = append(options, service.KeytabPrincipal(s.Cfg.KerberosServicePrincipal))
+	}
+	if clientIP != nil {
+		options = append(options, service.ClientAddress(types.HostAddressFromNetIP(clientIP)))
+	}
+
+	ok, creds, err := service.VerifyAPREQ(&krb5Token.APReq, service.NewSettings(s.keytab, options...))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("invalid Kerberos ticket")
+	}
+
+	principal := &models.KerberosPrincipal{
+		Username:    creds.UserName(),
+		Realm:       creds.Realm(),
+		DisplayName: creds.GetADCredentials().FullName,
+	}
+	s.log.Debug("Verified Kerberos ticket", "principal", principal.String())
+
+	return principal, nil
+}
@@ -20,6 +20,8 @@ type userAuthToken struct {
 	UpdatedAt     int64
 	RevokedAt     int64
 	UnhashedToken string `xorm:"-"`
+
+	ImpersonatorUserId int64
 }
 
 func userAuthTokenFromUserToken(ut *models.UserToken) (*userAuthToken, error) {
@@ -46,6 +48,7 @@ func (uat *userAuthToken) fromUserToken(ut *models.UserToken) error {
 	uat.UpdatedAt = ut.UpdatedAt
 	uat.RevokedAt = ut.RevokedAt
 	uat.UnhashedToken = ut.UnhashedToken
+	uat.ImpersonatorUserId = ut.ImpersonatorUserId
 
 	return nil
 }
@@ -68,6 +71,7 @@ func (uat *userAuthToken) toUserToken(ut *models.UserToken) error {
 	ut.UpdatedAt = uat.UpdatedAt
 	ut.RevokedAt = uat.RevokedAt
 	ut.UnhashedToken = uat.UnhashedToken
+	ut.ImpersonatorUserId = uat.ImpersonatorUserId
 
 	return nil
 }
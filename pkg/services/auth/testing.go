@@ -8,16 +8,17 @@ import (
 )
 
 type FakeUserAuthTokenService struct {
-	CreateTokenProvider          func(ctx context.Context, user *models.User, clientIP net.IP, userAgent string) (*models.UserToken, error)
-	TryRotateTokenProvider       func(ctx context.Context, token *models.UserToken, clientIP net.IP, userAgent string) (bool, error)
-	LookupTokenProvider          func(ctx context.Context, unhashedToken string) (*models.UserToken, error)
-	RevokeTokenProvider          func(ctx context.Context, token *models.UserToken, soft bool) error
-	RevokeAllUserTokensProvider  func(ctx context.Context, userId int64) error
-	ActiveAuthTokenCount         func(ctx context.Context) (int64, error)
-	GetUserTokenProvider         func(ctx context.Context, userId, userTokenId int64) (*models.UserToken, error)
-	GetUserTokensProvider        func(ctx context.Context, userId int64) ([]*models.UserToken, error)
-	GetUserRevokedTokensProvider func(ctx context.Context, userId int64) ([]*models.UserToken, error)
-	BatchRevokedTokenProvider    func(ctx context.Context, userIds []int64) error
+	CreateTokenProvider              func(ctx context.Context, user *models.User, clientIP net.IP, userAgent string) (*models.UserToken, error)
+	CreateImpersonationTokenProvider func(ctx context.Context, user *models.User, impersonatorID int64, clientIP net.IP, userAgent string) (*models.UserToken, error)
+	TryRotateTokenProvider           func(ctx context.Context, token *models.UserToken, clientIP net.IP, userAgent string) (bool, error)
+	LookupTokenProvider              func(ctx context.Context, unhashedToken string) (*models.UserToken, error)
+	RevokeTokenProvider              func(ctx context.Context, token *models.UserToken, soft bool) error
+	RevokeAllUserTokensProvider      func(ctx context.Context, userId int64) error
+	ActiveAuthTokenCount             func(ctx context.Context) (int64, error)
+	GetUserTokenProvider             func(ctx context.Context, userId, userTokenId int64) (*models.UserToken, error)
+	GetUserTokensProvider            func(ctx context.Context, userId int64) ([]*models.UserToken, error)
+	GetUserRevokedTokensProvider     func(ctx context.Context, userId int64) ([]*models.UserToken, error)
+	BatchRevokedTokenProvider        func(ctx context.Context, userIds []int64) error
 }
 
 func NewFakeUserAuthTokenService() *FakeUserAuthTokenService {
@@ -28,6 +29,12 @@ func NewFakeUserAuthTokenService() *FakeUserAuthTokenService {
 				UnhashedToken: "",
 			}, nil
 		},
+		CreateImpersonationTokenProvider: func(ctx context.Context, user *models.User, impersonatorID int64, clientIP net.IP, userAgent string) (*models.UserToken, error) {
+			return &models.UserToken{
+				UserId:             user.Id,
+				ImpersonatorUserId: impersonatorID,
+			}, nil
+		},
 		TryRotateTokenProvider: func(ctx context.Context, token *models.UserToken, clientIP net.IP, userAgent string) (bool, error) {
 			return false, nil
 		},
@@ -68,6 +75,10 @@ func (s *FakeUserAuthTokenService) CreateToken(ctx context.Context, user *models
 	return s.CreateTokenProvider(context.Background(), user, clientIP, userAgent)
 }
 
+func (s *FakeUserAuthTokenService) CreateImpersonationToken(ctx context.Context, user *models.User, impersonatorID int64, clientIP net.IP, userAgent string) (*models.UserToken, error) {
+	return s.CreateImpersonationTokenProvider(context.Background(), user, impersonatorID, clientIP, userAgent)
+}
+
 func (s *FakeUserAuthTokenService) LookupToken(ctx context.Context, unhashedToken string) (*models.UserToken, error) {
 	return s.LookupTokenProvider(context.Background(), unhashedToken)
 }
@@ -0,0 +1,186 @@
+// Package authwebhook posts failed logins, logins from new devices and admin role
+// grants to a webhook as they happen, so that they can be ingested by a SIEM.
+package authwebhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/network"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/registry"
+	"github.com/grafana/grafana/pkg/services/hooks"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// Event types
+const (
+	EventLoginFailed      = "login-failed"
+	EventLoginNewDevice   = "login-new-device"
+	EventAdminRoleGranted = "admin-role-granted"
+)
+
+var getTime = time.Now
+
+func init() {
+	registry.RegisterService(&Service{})
+}
+
+// Event is the JSON document posted to the webhook. The organization of an
+// admin-role-granted event is 0 when the user was made a Grafana Admin.
+type Event struct {
+	Type       string    `json:"type"`
+	Timestamp  time.Time `json:"timestamp"`
+	UserId     int64     `json:"userId,omitempty"`
+	UserLogin  string    `json:"userLogin,omitempty"`
+	OrgId      int64     `json:"orgId,omitempty"`
+	Role       string    `json:"role,omitempty"`
+	AuthModule string    `json:"authModule,omitempty"`
+	IpAddress  string    `json:"ipAddress,omitempty"`
+	UserAgent  string    `json:"userAgent,omitempty"`
+	Message    string    `json:"message,omitempty"`
+}
+
+type Service struct {
+	Cfg              *setting.Cfg            `inject:""`
+	Bus              bus.Bus                 `inject:""`
+	HooksService     *hooks.HooksService     `inject:""`
+	AuthTokenService models.UserTokenService `inject:""`
+
+	log    log.Logger
+	client *http.Client
+	events map[string]bool
+}
+
+func (s *Service) Init() error {
+	s.log = log.New("authwebhook")
+
+	if s.Cfg.AuthWebhookURL == "" {
+		return nil
+	}
+
+	s.client = &http.Client{Timeout: s.Cfg.AuthWebhookTimeout}
+	s.events = make(map[string]bool, len(s.Cfg.AuthWebhookEvents))
+	for _, event := range s.Cfg.AuthWebhookEvents {
+		s.events[event] = true
+	}
+
+	s.HooksService.AddLoginHook(s.loginHook)
+	s.Bus.AddEventListener(s.onUserAdminRoleGranted)
+
+	return nil
+}
+
+func (s *Service) loginHook(info *models.LoginInfo, c *models.ReqContext) {
+	if info.Error != nil {
+		s.send(c, Event{
+			Type:       EventLoginFailed,
+			UserLogin:  info.LoginUsername,
+			AuthModule: info.AuthModule,
+			Message:    info.Error.Error(),
+		})
+		return
+	}
+
+	if info.User == nil || !s.events[EventLoginNewDevice] || !s.isNewDevice(c, info.User.Id) {
+		return
+	}
+
+	s.send(c, Event{
+		Type:       EventLoginNewDevice,
+		UserId:     info.User.Id,
+		UserLogin:  info.User.Login,
+		AuthModule: info.AuthModule,
+	})
+}
+
+// isNewDevice returns true if the user has no other session from the browser they signed in with.
+func (s *Service) isNewDevice(c *models.ReqContext, userID int64) bool {
+	tokens, err := s.AuthTokenService.GetUserTokens(c.Req.Context(), userID)
+	if err != nil {
+		s.log.Error("Failed to get user tokens", "userId", userID, "error", err)
+		return false
+	}
+
+	userAgent := c.Req.UserAgent()
+	for _, token := range tokens {
+		if c.UserToken != nil && token.Id == c.UserToken.Id {
+			continue
+		}
+		if token.UserAgent == userAgent {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Service) onUserAdminRoleGranted(evt *events.UserAdminRoleGranted) error {
+	role := string(models.ROLE_ADMIN)
+	if evt.OrgId == 0 {
+		role = "Grafana Admin"
+	}
+
+	s.send(nil, Event{
+		Type:      EventAdminRoleGranted,
+		Timestamp: evt.Timestamp,
+		UserId:    evt.UserId,
+		OrgId:     evt.OrgId,
+		Role:      role,
+	})
+	return nil
+}
+
+// send posts the event in the background, so that slow receivers don't delay the
+// request. The address and browser of the client are added when there is a request.
+func (s *Service) send(c *models.ReqContext, event Event) {
+	if !s.events[event.Type] {
+		return
+	}
+
+	if event.Timestamp.IsZero() {
+		event.Timestamp = getTime()
+	}
+	if c != nil {
+		if ip, err := network.GetIPFromAddress(c.RemoteAddr()); err == nil {
+			event.IpAddress = ip.String()
+		}
+		event.UserAgent = c.Req.UserAgent()
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		s.log.Error("Failed to marshal authentication event", "type", event.Type, "error", err)
+		return
+	}
+
+	go s.post(body, event.Type)
+}
+
+func (s *Service) post(body []byte, eventType string) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, s.Cfg.AuthWebhookURL, bytes.NewReader(body))
+	if err != nil {
+		s.log.Error("Failed to create authentication event request", "error", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		s.log.Error("Failed to send authentication event to webhook", "type", eventType, "error", err)
+		return
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			s.log.Warn("Failed to close response body", "err", err)
+		}
+	}()
+	if resp.StatusCode/100 != 2 {
+		s.log.Error("Authentication event webhook returned an error", "type", eventType, "status", resp.Status)
+	}
+}
@@ -0,0 +1,122 @@
+package authwebhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/auth"
+	"github.com/grafana/grafana/pkg/services/hooks"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/macaron.v1"
+)
+
+func TestService(t *testing.T) {
+	received := make(chan Event, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event Event
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		received <- event
+	}))
+	t.Cleanup(server.Close)
+
+	tokenService := auth.NewFakeUserAuthTokenService()
+	tokenService.GetUserTokensProvider = func(ctx context.Context, userId int64) ([]*models.UserToken, error) {
+		return []*models.UserToken{
+			{Id: 1, UserId: userId, UserAgent: "known browser"},
+			{Id: 2, UserId: userId, UserAgent: "new browser"},
+		}, nil
+	}
+
+	s := &Service{
+		Cfg: &setting.Cfg{
+			AuthWebhookURL:     server.URL,
+			AuthWebhookEvents:  []string{EventLoginFailed, EventLoginNewDevice, EventAdminRoleGranted},
+			AuthWebhookTimeout: time.Second,
+		},
+		Bus:              bus.New(),
+		HooksService:     &hooks.HooksService{},
+		AuthTokenService: tokenService,
+	}
+	require.NoError(t, s.Init())
+
+	newContext := func(userAgent string, token *models.UserToken) *models.ReqContext {
+		req := httptest.NewRequest("POST", "/login", nil)
+		req.Header.Set("User-Agent", userAgent)
+		return &models.ReqContext{
+			Context:   &macaron.Context{Req: macaron.Request{Request: req}},
+			UserToken: token,
+		}
+	}
+
+	receive := func(t *testing.T) Event {
+		t.Helper()
+		select {
+		case event := <-received:
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the webhook")
+			return Event{}
+		}
+	}
+
+	t.Run("Failed logins are sent", func(t *testing.T) {
+		s.loginHook(&models.LoginInfo{LoginUsername: "admin", AuthModule: "ldap", Error: models.ErrUserNotFound}, newContext("browser", nil))
+
+		event := receive(t)
+		assert.Equal(t, EventLoginFailed, event.Type)
+		assert.Equal(t, "admin", event.UserLogin)
+		assert.Equal(t, "ldap", event.AuthModule)
+		assert.Equal(t, "192.0.2.1", event.IpAddress)
+		assert.Equal(t, "browser", event.UserAgent)
+		assert.Equal(t, models.ErrUserNotFound.Error(), event.Message)
+	})
+
+	t.Run("Logins from a browser with another session aren't sent", func(t *testing.T) {
+		s.loginHook(&models.LoginInfo{User: &models.User{Id: 3, Login: "user"}}, newContext("known browser", &models.UserToken{Id: 3}))
+
+		assert.False(t, s.isNewDevice(newContext("known browser", &models.UserToken{Id: 3}), 3))
+	})
+
+	t.Run("Logins from a new browser are sent", func(t *testing.T) {
+		s.loginHook(&models.LoginInfo{User: &models.User{Id: 3, Login: "user"}}, newContext("new browser", &models.UserToken{Id: 2}))
+
+		event := receive(t)
+		assert.Equal(t, EventLoginNewDevice, event.Type)
+		assert.Equal(t, int64(3), event.UserId)
+		assert.Equal(t, "user", event.UserLogin)
+		assert.Equal(t, "new browser", event.UserAgent)
+	})
+
+	t.Run("Admin role grants are sent", func(t *testing.T) {
+		require.NoError(t, s.Bus.Publish(&events.UserAdminRoleGranted{Timestamp: time.Now(), UserId: 4, OrgId: 2}))
+
+		event := receive(t)
+		assert.Equal(t, EventAdminRoleGranted, event.Type)
+		assert.Equal(t, int64(4), event.UserId)
+		assert.Equal(t, int64(2), event.OrgId)
+		assert.Equal(t, "Admin", event.Role)
+	})
+
+	t.Run("Events that aren't listed aren't sent", func(t *testing.T) {
+		s.events = map[string]bool{EventAdminRoleGranted: true}
+		t.Cleanup(func() {
+			s.events = map[string]bool{EventLoginFailed: true, EventLoginNewDevice: true, EventAdminRoleGranted: true}
+		})
+
+		s.loginHook(&models.LoginInfo{LoginUsername: "admin", Error: models.ErrUserNotFound}, newContext("browser", nil))
+		require.NoError(t, s.Bus.Publish(&events.UserAdminRoleGranted{Timestamp: time.Now(), UserId: 5}))
+
+		event := receive(t)
+		assert.Equal(t, EventAdminRoleGranted, event.Type)
+		assert.Equal(t, "Grafana Admin", event.Role)
+	})
+}
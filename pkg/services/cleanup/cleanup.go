@@ -146,8 +146,13 @@ func (srv *CleanUpService) deleteOldLoginAttempts() {
 		return
 	}
 
+	// the failed attempts are counted over the brute force attempts window
+	window := srv.Cfg.BruteForceAttemptsWindow
+	if window < time.Minute*10 {
+		window = time.Minute * 10
+	}
 	cmd := models.DeleteOldLoginAttemptsCommand{
-		OlderThan: time.Now().Add(time.Minute * -10),
+		OlderThan: time.Now().Add(-window),
 	}
 	if err := bus.Dispatch(&cmd); err != nil {
 		srv.log.Error("Problem deleting expired login attempts", "error", err.Error())
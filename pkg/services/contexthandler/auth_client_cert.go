@@ -0,0 +1,57 @@
+package contexthandler
+
+import (
+	"errors"
+
+	"github.com/grafana/grafana/pkg/login"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/auth/clientcert"
+)
+
+const InvalidClientCert = "Invalid client certificate"
+
+// initContextWithClientCert signs in the user of the client certificate verified by the TLS listener.
+func (h *ContextHandler) initContextWithClientCert(ctx *models.ReqContext, orgID int64) bool {
+	if !h.Cfg.ClientCertAuthEnabled || ctx.Req.TLS == nil || len(ctx.Req.TLS.VerifiedChains) == 0 {
+		return false
+	}
+
+	cert := ctx.Req.TLS.VerifiedChains[0][0]
+	username, err := clientcert.Username(cert, h.Cfg.ClientCertUsernameSource)
+	if err != nil {
+		ctx.Logger.Debug("Failed to get username from client certificate", "subject", cert.Subject.String(), "error", err)
+		ctx.JsonApiErr(401, InvalidClientCert, err)
+		return true
+	}
+
+	extUser := &models.ExternalUserInfo{
+		AuthModule: models.AuthModuleClientCert,
+		AuthId:     username,
+		Login:      username,
+		Name:       cert.Subject.CommonName,
+	}
+	if h.Cfg.ClientCertUsernameSource == "san_email" {
+		extUser.Email = username
+	}
+
+	upsert := &models.UpsertUserCommand{
+		ReqContext:    ctx,
+		SignupAllowed: h.Cfg.ClientCertAutoSignUp,
+		ExternalUser:  extUser,
+	}
+	user, err := h.signInExternalUser(ctx, orgID, upsert)
+	if err != nil {
+		if errors.Is(err, login.ErrInvalidCredentials) {
+			ctx.Logger.Debug("Failed to find user of client certificate", "username", username)
+		} else {
+			ctx.Logger.Error("Failed to sync user of client certificate", "username", username, "error", err)
+		}
+		ctx.JsonApiErr(401, InvalidClientCert, err)
+		return true
+	}
+
+	ctx.SignedInUser = user
+	ctx.IsSignedIn = true
+
+	return true
+}
@@ -2,10 +2,12 @@ package contexthandler
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/login"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/jmespath/go-jmespath"
 )
 
 const InvalidJWT = "Invalid JWT"
@@ -42,6 +44,20 @@ func (h *ContextHandler) initContextWithJWT(ctx *models.ReqContext, orgId int64)
 		return true
 	}
 
+	if h.Cfg.JWTAuthAutoSignUp || h.Cfg.JWTAuthRoleAttributePath != "" {
+		user, err := h.upsertJWTUser(ctx, orgId, claims, query.Login, query.Email)
+		if err != nil {
+			ctx.Logger.Debug("Failed to sync user using JWT claims", "error", err)
+			ctx.JsonApiErr(401, InvalidJWT, err)
+			return true
+		}
+
+		ctx.SignedInUser = user
+		ctx.IsSignedIn = true
+
+		return true
+	}
+
 	if err := bus.DispatchCtx(ctx.Req.Context(), &query); err != nil {
 		if errors.Is(err, models.ErrUserNotFound) {
 			ctx.Logger.Debug(
@@ -62,3 +78,58 @@ func (h *ContextHandler) initContextWithJWT(ctx *models.ReqContext, orgId int64)
 
 	return true
 }
+
+// upsertJWTUser creates or updates the user described by the JWT claims,
+// syncing the organization role from the configured role attribute path.
+func (h *ContextHandler) upsertJWTUser(ctx *models.ReqContext, orgID int64, claims models.JWTClaims, login, email string) (*models.SignedInUser, error) {
+	extUser := &models.ExternalUserInfo{
+		AuthModule: "jwt",
+		Login:      login,
+		Email:      email,
+	}
+	extUser.AuthId, _ = claims["sub"].(string)
+	extUser.Name, _ = claims["name"].(string)
+	if extUser.Login == "" {
+		extUser.Login = email
+	}
+	if extUser.AuthId == "" {
+		extUser.AuthId = extUser.Login
+	}
+
+	if path := h.Cfg.JWTAuthRoleAttributePath; path != "" {
+		role, err := extractJWTRole(path, claims)
+		if err != nil {
+			if h.Cfg.JWTAuthRoleAttributeStrict {
+				return nil, err
+			}
+			ctx.Logger.Debug("Failed to extract role from JWT claims", "error", err)
+		} else {
+			// The user will be assigned a role in either the auto-assigned organization or in the default one
+			orgID := int64(1)
+			if h.Cfg.AutoAssignOrg && h.Cfg.AutoAssignOrgId > 0 {
+				orgID = int64(h.Cfg.AutoAssignOrgId)
+			}
+			extUser.OrgRoles = map[int64]models.RoleType{orgID: role}
+		}
+	}
+
+	upsert := &models.UpsertUserCommand{
+		ReqContext:    ctx,
+		SignupAllowed: h.Cfg.JWTAuthAutoSignUp,
+		ExternalUser:  extUser,
+	}
+	return h.signInExternalUser(ctx, orgID, upsert)
+}
+
+func extractJWTRole(path string, claims models.JWTClaims) (models.RoleType, error) {
+	val, err := jmespath.Search(path, map[string]interface{}(claims))
+	if err != nil {
+		return "", fmt.Errorf("failed to search JWT claims with path %q: %w", path, err)
+	}
+
+	role, _ := val.(string)
+	if rt := models.RoleType(role); rt.IsValid() {
+		return rt, nil
+	}
+	return "", fmt.Errorf("role attribute path %q returned invalid role %v", path, val)
+}
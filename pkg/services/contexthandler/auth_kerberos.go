@@ -0,0 +1,96 @@
+package contexthandler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/network"
+	"github.com/grafana/grafana/pkg/login"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+const InvalidKerberosTicket = "Invalid Kerberos ticket"
+
+const negotiateScheme = "Negotiate"
+
+// initContextWithKerberos signs in the user of the Kerberos ticket sent in a Negotiate Authorization header.
+func (h *ContextHandler) initContextWithKerberos(ctx *models.ReqContext, orgID int64) bool {
+	if !h.Cfg.KerberosAuthEnabled {
+		return false
+	}
+
+	parts := strings.SplitN(ctx.Req.Header.Get("Authorization"), " ", 2)
+	if len(parts) != 2 || parts[0] != negotiateScheme {
+		return false
+	}
+
+	ip, err := network.GetIPFromAddress(ctx.RemoteAddr())
+	if err != nil {
+		ctx.Logger.Debug("Failed to get IP from client address", "addr", ctx.RemoteAddr())
+		ip = nil
+	}
+
+	principal, err := h.KerberosAuthService.Verify(ctx.Req.Context(), parts[1], ip)
+	if err != nil {
+		ctx.Logger.Debug("Failed to verify Kerberos ticket", "error", err)
+		ctx.JsonApiErr(401, InvalidKerberosTicket, err)
+		return true
+	}
+
+	if !h.isKerberosRealmAllowed(principal.Realm) {
+		ctx.Logger.Debug("Kerberos realm not allowed", "principal", principal.String())
+		ctx.JsonApiErr(401, InvalidKerberosTicket, fmt.Errorf("realm %q is not allowed", principal.Realm))
+		return true
+	}
+
+	user, err := h.upsertKerberosUser(ctx, orgID, principal)
+	if err != nil {
+		if errors.Is(err, login.ErrInvalidCredentials) {
+			ctx.Logger.Debug("Failed to find user of Kerberos principal", "principal", principal.String())
+		} else {
+			ctx.Logger.Error("Failed to sync user of Kerberos principal", "principal", principal.String(), "error", err)
+		}
+		ctx.JsonApiErr(401, InvalidKerberosTicket, err)
+		return true
+	}
+
+	ctx.SignedInUser = user
+	ctx.IsSignedIn = true
+
+	return true
+}
+
+func (h *ContextHandler) isKerberosRealmAllowed(realm string) bool {
+	if len(h.Cfg.KerberosAllowedRealms) == 0 {
+		return true
+	}
+
+	for _, allowed := range h.Cfg.KerberosAllowedRealms {
+		if strings.EqualFold(allowed, realm) {
+			return true
+		}
+	}
+	return false
+}
+
+// upsertKerberosUser finds the user of a Kerberos principal, creating it when
+// auto sign up is enabled.
+func (h *ContextHandler) upsertKerberosUser(ctx *models.ReqContext, orgID int64, principal *models.KerberosPrincipal) (*models.SignedInUser, error) {
+	login := principal.String()
+	if h.Cfg.KerberosStripRealm {
+		login = principal.Username
+	}
+
+	upsert := &models.UpsertUserCommand{
+		ReqContext:    ctx,
+		SignupAllowed: h.Cfg.KerberosAutoSignUp,
+		ExternalUser: &models.ExternalUserInfo{
+			AuthModule: models.AuthModuleKerberos,
+			AuthId:     principal.String(),
+			Login:      login,
+			Name:       principal.DisplayName,
+		},
+	}
+	return h.signInExternalUser(ctx, orgID, upsert)
+}
@@ -13,9 +13,11 @@ import (
 	"github.com/grafana/grafana/pkg/registry"
 	"github.com/grafana/grafana/pkg/services/auth"
 	"github.com/grafana/grafana/pkg/services/auth/jwt"
+	"github.com/grafana/grafana/pkg/services/auth/kerberos"
 	"github.com/grafana/grafana/pkg/services/contexthandler/authproxy"
 	"github.com/grafana/grafana/pkg/services/rendering"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/urlsigner"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/stretchr/testify/require"
 	macaron "gopkg.in/macaron.v1"
@@ -110,6 +112,7 @@ func getContextHandler(t *testing.T) *ContextHandler {
 	userAuthTokenSvc := auth.NewFakeUserAuthTokenService()
 	renderSvc := &fakeRenderService{}
 	authJWTSvc := models.NewFakeJWTService()
+	authKerberosSvc := models.NewFakeKerberosService()
 	svc := &ContextHandler{}
 
 	err := registry.BuildServiceGraph([]interface{}{cfg}, []*registry.Descriptor{
@@ -133,6 +136,14 @@ func getContextHandler(t *testing.T) *ContextHandler {
 			Name:     jwt.ServiceName,
 			Instance: authJWTSvc,
 		},
+		{
+			Name:     kerberos.ServiceName,
+			Instance: authKerberosSvc,
+		},
+		{
+			Name:     urlsigner.ServiceName,
+			Instance: &urlsigner.Service{},
+		},
 		{
 			Name:     ServiceName,
 			Instance: svc,
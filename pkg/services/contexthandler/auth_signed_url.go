@@ -0,0 +1,69 @@
+package contexthandler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/login"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/urlsigner"
+)
+
+const InvalidSignedURL = "Invalid signed URL"
+
+var errSignedURLMethod = errors.New("signed URLs are only valid for GET and HEAD requests")
+
+// initContextWithSignedURL signs in the user a signed URL was created for, for the URL only. URLs
+// signed for a role sign in a user without an ID with the role in the organization, like render keys.
+// The signature doesn't cover the method, so signed URLs only sign in read requests.
+func (h *ContextHandler) initContextWithSignedURL(ctx *models.ReqContext) bool {
+	if !h.Cfg.URLSigningEnabled {
+		return false
+	}
+
+	grant, err := h.URLSigner.Verify(ctx.Req.URL)
+	if errors.Is(err, urlsigner.ErrNotSigned) {
+		return false
+	}
+	if err != nil {
+		ctx.Logger.Debug("Failed to verify signed URL", "error", err)
+		ctx.JsonApiErr(401, InvalidSignedURL, err)
+		return true
+	}
+
+	if ctx.Req.Method != http.MethodGet && ctx.Req.Method != http.MethodHead {
+		ctx.JsonApiErr(401, InvalidSignedURL, errSignedURLMethod)
+		return true
+	}
+
+	if grant.UserID == 0 {
+		ctx.SignedInUser = &models.SignedInUser{OrgId: grant.OrgID, OrgRole: grant.OrgRole}
+		ctx.IsSignedIn = true
+		return true
+	}
+
+	// the user might have been disabled since the URL was signed
+	userQuery := models.GetUserByIdQuery{Id: grant.UserID}
+	if err := bus.DispatchCtx(ctx.Req.Context(), &userQuery); err != nil {
+		ctx.Logger.Error("Failed to get user of signed URL", "userId", grant.UserID, "error", err)
+		ctx.JsonApiErr(401, InvalidSignedURL, err)
+		return true
+	}
+	if userQuery.Result.IsDisabled {
+		ctx.JsonApiErr(401, InvalidSignedURL, login.ErrUserDisabled)
+		return true
+	}
+
+	query := models.GetSignedInUserQuery{OrgId: grant.OrgID, UserId: grant.UserID}
+	if err := bus.DispatchCtx(ctx.Req.Context(), &query); err != nil {
+		ctx.Logger.Error("Failed to get user of signed URL", "userId", grant.UserID, "error", err)
+		ctx.JsonApiErr(401, InvalidSignedURL, err)
+		return true
+	}
+
+	ctx.SignedInUser = query.Result
+	ctx.IsSignedIn = true
+
+	return true
+}
@@ -1,10 +1,16 @@
 package authproxy
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -223,3 +229,57 @@ func TestMiddlewareContext_ldap(t *testing.T) {
 		assert.False(t, stub.loginCalled)
 	})
 }
+
+func TestVerifySignature(t *testing.T) {
+	const secret = "proxy-secret"
+	const email = "markelog@example.com"
+
+	sign := func(values ...string) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		_, err := mac.Write([]byte(strings.Join(values, "\n")))
+		require.NoError(t, err)
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	prepare := func(t *testing.T, timestamp, signature string) *AuthProxy {
+		return prepareMiddleware(t, nil, func(req *http.Request, cfg *setting.Cfg) {
+			cfg.AuthProxySignatureSecret = secret
+			cfg.AuthProxySignatureHeader = "X-WEBAUTH-SIGNATURE"
+			cfg.AuthProxyTimestampHeader = "X-WEBAUTH-TIMESTAMP"
+			cfg.AuthProxySignatureMaxAge = time.Minute
+			cfg.AuthProxyHeaders = map[string]string{"Email": "X-WEBAUTH-EMAIL", "Role": "X-WEBAUTH-ROLE"}
+
+			req.Header.Set("X-WEBAUTH-EMAIL", email)
+			req.Header.Set("X-WEBAUTH-TIMESTAMP", timestamp)
+			req.Header.Set("X-WEBAUTH-SIGNATURE", signature)
+		})
+	}
+
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+
+	t.Run("accepts a valid signature", func(t *testing.T) {
+		auth := prepare(t, now, sign(now, hdrName, email, ""))
+		require.NoError(t, auth.VerifySignature())
+	})
+
+	t.Run("is not required without a secret", func(t *testing.T) {
+		auth := prepareMiddleware(t, nil, nil)
+		require.NoError(t, auth.VerifySignature())
+	})
+
+	t.Run("rejects a signature of other headers", func(t *testing.T) {
+		auth := prepare(t, now, sign(now, "admin", email, ""))
+		require.Error(t, auth.VerifySignature())
+	})
+
+	t.Run("rejects an expired timestamp", func(t *testing.T) {
+		old := strconv.FormatInt(time.Now().Add(-2*time.Minute).Unix(), 10)
+		auth := prepare(t, old, sign(old, hdrName, email, ""))
+		require.Error(t, auth.VerifySignature())
+	})
+
+	t.Run("rejects a missing signature", func(t *testing.T) {
+		auth := prepare(t, now, "")
+		require.Error(t, auth.VerifySignature())
+	})
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/login"
 	"github.com/grafana/grafana/pkg/services/rendering"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/urlsigner"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/opentracing/opentracing-go"
@@ -45,12 +46,14 @@ func init() {
 
 // ContextHandler is a middleware.
 type ContextHandler struct {
-	Cfg              *setting.Cfg             `inject:""`
-	AuthTokenService models.UserTokenService  `inject:""`
-	JWTAuthService   models.JWTService        `inject:""`
-	RemoteCache      *remotecache.RemoteCache `inject:""`
-	RenderService    rendering.Service        `inject:""`
-	SQLStore         *sqlstore.SQLStore       `inject:""`
+	Cfg                 *setting.Cfg             `inject:""`
+	AuthTokenService    models.UserTokenService  `inject:""`
+	JWTAuthService      models.JWTService        `inject:""`
+	KerberosAuthService models.KerberosService   `inject:""`
+	RemoteCache         *remotecache.RemoteCache `inject:""`
+	RenderService       rendering.Service        `inject:""`
+	SQLStore            *sqlstore.SQLStore       `inject:""`
+	URLSigner           *urlsigner.Service       `inject:""`
 
 	// GetTime returns the current time.
 	// Stubbable by tests.
@@ -115,7 +118,10 @@ func (h *ContextHandler) Middleware(mContext *macaron.Context) {
 	switch {
 	case h.initContextWithRenderAuth(reqContext):
 	case h.initContextWithAPIKey(reqContext):
+	case h.initContextWithSignedURL(reqContext):
+	case h.initContextWithKerberos(reqContext, orgID):
 	case h.initContextWithBasicAuth(reqContext, orgID):
+	case h.initContextWithClientCert(reqContext, orgID):
 	case h.initContextWithAuthProxy(reqContext, orgID):
 	case h.initContextWithToken(reqContext, orgID):
 	case h.initContextWithJWT(reqContext, orgID):
@@ -444,6 +450,14 @@ func (h *ContextHandler) initContextWithAuthProxy(reqContext *models.ReqContext,
 		return true
 	}
 
+	// Check if the headers are signed by the proxy
+	if err := auth.VerifySignature(); err != nil {
+		h.handleError(reqContext, err, 407, func(details error) {
+			logger.Error("Failed to verify auth proxy signature", "message", err.Error(), "error", details)
+		})
+		return true
+	}
+
 	id, err := logUserIn(auth, username, logger, false)
 	if err != nil {
 		h.handleError(reqContext, err, 407, nil)
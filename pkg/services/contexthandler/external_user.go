@@ -0,0 +1,79 @@
+package contexthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/contexthandler/authproxy"
+)
+
+// externalUserCachePrefix is the prefix of the cache keys of the IDs of the
+// users synced from JWT claims, Kerberos principals and client certificates.
+const externalUserCachePrefix = "external-user-sync-ttl:%s"
+
+// externalUserSyncTTL is how long the ID of a synced user is cached, after which
+// the user is synced again.
+const externalUserSyncTTL = 15 * time.Minute
+
+// signInExternalUser returns the signed in user of an external authentication,
+// which is created or updated by the upsert command. The ID of the user is
+// cached by the external user info, so that the user is only synced again once
+// the info changes or the cache expires.
+func (h *ContextHandler) signInExternalUser(ctx *models.ReqContext, orgID int64, upsert *models.UpsertUserCommand) (*models.SignedInUser, error) {
+	cacheKey, err := externalUserCacheKey(upsert)
+	if err != nil {
+		return nil, err
+	}
+
+	if id, err := h.RemoteCache.Get(cacheKey); err == nil {
+		query := models.GetSignedInUserQuery{OrgId: orgID, UserId: id.(int64)}
+		if err := bus.DispatchCtx(ctx.Req.Context(), &query); err == nil {
+			return query.Result, nil
+		}
+
+		// The user may have been deleted since it was cached, so it is synced
+		// again without the cache.
+		ctx.Logger.Debug("Failed to get cached external user, syncing it again", "userID", id)
+		if err := h.RemoteCache.Delete(cacheKey); err != nil && !errors.Is(err, remotecache.ErrCacheItemNotFound) {
+			ctx.Logger.Error("Failed to remove external user from cache", "error", err)
+		}
+	}
+
+	if err := bus.Dispatch(upsert); err != nil {
+		return nil, err
+	}
+
+	query := models.GetSignedInUserQuery{OrgId: orgID, UserId: upsert.Result.Id}
+	if err := bus.DispatchCtx(ctx.Req.Context(), &query); err != nil {
+		return nil, err
+	}
+
+	if err := h.RemoteCache.Set(cacheKey, upsert.Result.Id, externalUserSyncTTL); err != nil {
+		ctx.Logger.Error("Failed to store external user in cache", "error", err)
+	}
+
+	return query.Result, nil
+}
+
+// externalUserCacheKey returns the cache key of the ID of the user synced by the
+// upsert command, which changes with any of the info synced.
+func externalUserCacheKey(upsert *models.UpsertUserCommand) (string, error) {
+	info, err := json.Marshal(struct {
+		SignupAllowed bool
+		ExternalUser  *models.ExternalUserInfo
+	}{upsert.SignupAllowed, upsert.ExternalUser})
+	if err != nil {
+		return "", err
+	}
+
+	hashedKey, err := authproxy.HashCacheKey(string(info))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(externalUserCachePrefix, hashedKey), nil
+}
@@ -0,0 +1,417 @@
+// Package deprovisioning periodically disables or deletes the LDAP and OAuth users who
+// no longer exist in their directory.
+package deprovisioning
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/registry"
+	"github.com/grafana/grafana/pkg/services/multildap"
+	"github.com/grafana/grafana/pkg/services/oauthtoken"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// Sources of users
+const (
+	SourceLDAP  = "ldap"
+	SourceOAuth = "oauth"
+)
+
+// Statuses of the users in the report
+const (
+	StatusPending      = "pending"
+	StatusWouldDisable = "would-disable"
+	StatusWouldDelete  = "would-delete"
+	StatusDisabled     = "disabled"
+	StatusDeleted      = "deleted"
+	StatusFailed       = "failed"
+)
+
+var ErrDisabled = errors.New("user deprovisioning is disabled")
+
+var (
+	getTime       = time.Now
+	getLDAPConfig = multildap.GetConfig
+	newLDAP       = multildap.New
+)
+
+func init() {
+	registry.RegisterService(&Service{})
+}
+
+// Report lists the users missing from their directory found by the last run.
+type Report struct {
+	Started  time.Time     `json:"started"`
+	Finished time.Time     `json:"finished"`
+	DryRun   bool          `json:"dryRun"`
+	Action   string        `json:"action"`
+	Users    []*ReportUser `json:"users"`
+}
+
+// ReportUser is a user missing from their directory. Users are pending until they have
+// been missing for the grace period.
+type ReportUser struct {
+	UserId       int64     `json:"userId"`
+	Login        string    `json:"login"`
+	AuthModule   string    `json:"authModule"`
+	MissingSince time.Time `json:"missingSince"`
+	Status       string    `json:"status"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// userDeprovisioning records since when a user is missing from their directory.
+type userDeprovisioning struct {
+	Id           int64
+	UserId       int64
+	AuthModule   string
+	MissingSince time.Time
+}
+
+func (userDeprovisioning) TableName() string {
+	return "user_deprovisioning"
+}
+
+// externalUser is a user and the module they last signed in with.
+type externalUser struct {
+	UserId     int64
+	Login      string
+	IsDisabled bool
+	AuthModule string
+	Created    time.Time
+}
+
+type Service struct {
+	Cfg               *setting.Cfg                  `inject:""`
+	SQLStore          *sqlstore.SQLStore            `inject:""`
+	ServerLockService *serverlock.ServerLockService `inject:""`
+	OAuthTokenService *oauthtoken.Service           `inject:""`
+
+	log            log.Logger
+	hasOAuthAccess func(ctx context.Context, userID int64) (bool, error)
+
+	reportMu sync.Mutex
+	report   *Report
+}
+
+func (s *Service) Init() error {
+	s.log = log.New("deprovisioning")
+	s.hasOAuthAccess = s.OAuthTokenService.HasAccess
+	return nil
+}
+
+// IsDisabled returns true if user deprovisioning is turned off.
+func (s *Service) IsDisabled() bool {
+	return !s.Cfg.DeprovisioningEnabled
+}
+
+// Run checks the users at every interval until the context is canceled.
+func (s *Service) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.Cfg.DeprovisioningInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := s.RunNow(ctx); err != nil {
+				s.log.Error("Failed to deprovision users", "error", err)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// LastReport returns the report of the last run, or nil if the users haven't been checked yet.
+func (s *Service) LastReport() *Report {
+	s.reportMu.Lock()
+	defer s.reportMu.Unlock()
+	return s.report
+}
+
+// RunNow checks the users, and disables or deletes the ones missing from their directory for
+// longer than the grace period, unless running dry. Only one instance runs it at a time.
+func (s *Service) RunNow(ctx context.Context) (*Report, error) {
+	if s.IsDisabled() {
+		return nil, ErrDisabled
+	}
+
+	var report *Report
+	var runErr error
+	err := s.ServerLockService.LockAndExecute(ctx, "deprovision users", s.Cfg.DeprovisioningInterval/2, func() {
+		report, runErr = s.run(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if runErr != nil {
+		return nil, runErr
+	}
+	if report == nil {
+		// another instance ran it recently
+		return s.LastReport(), nil
+	}
+
+	s.reportMu.Lock()
+	s.report = report
+	s.reportMu.Unlock()
+	return report, nil
+}
+
+func (s *Service) run(ctx context.Context) (*Report, error) {
+	report := &Report{
+		Started: getTime(),
+		DryRun:  s.Cfg.DeprovisioningDryRun,
+		Action:  s.Cfg.DeprovisioningAction,
+		Users:   []*ReportUser{},
+	}
+
+	users, err := s.getExternalUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := []*externalUser{}
+	missingIDs := map[int64]bool{}
+	for source, sourceUsers := range groupBySource(users) {
+		if !s.isSourceEnabled(source) {
+			continue
+		}
+
+		var sourceMissing map[int64]bool
+		var sourceErr error
+		switch source {
+		case SourceLDAP:
+			sourceMissing, sourceErr = s.findMissingLDAPUsers(sourceUsers)
+		case SourceOAuth:
+			sourceMissing = s.findMissingOAuthUsers(ctx, sourceUsers)
+		}
+		if sourceErr != nil {
+			// the users can't be told apart from the ones missing, so none of them is changed
+			s.log.Warn("Failed to check users, skipping source", "source", source, "error", sourceErr)
+			continue
+		}
+
+		for _, user := range sourceUsers {
+			if sourceMissing[user.UserId] {
+				missing = append(missing, user)
+				missingIDs[user.UserId] = true
+			}
+		}
+	}
+
+	if err := s.forgetFound(ctx, missingIDs); err != nil {
+		return nil, err
+	}
+
+	for _, user := range missing {
+		reportUser, err := s.handleMissing(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		report.Users = append(report.Users, reportUser)
+	}
+
+	report.Finished = getTime()
+	s.log.Info("Checked users for deprovisioning", "users", len(users), "missing", len(missing), "dryRun", report.DryRun)
+	return report, nil
+}
+
+func (s *Service) isSourceEnabled(source string) bool {
+	for _, enabled := range s.Cfg.DeprovisioningSources {
+		if enabled == source {
+			return true
+		}
+	}
+	return false
+}
+
+// getExternalUsers returns the users who last signed in with LDAP or OAuth. Disabled users are
+// left out when they would only be disabled again.
+func (s *Service) getExternalUsers(ctx context.Context) ([]*externalUser, error) {
+	var rows []*externalUser
+	err := s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		return sess.SQL(`SELECT u.id AS user_id, u.login, u.is_disabled, ua.auth_module, ua.created
+			FROM user_auth ua INNER JOIN `+s.SQLStore.Dialect.Quote("user")+` u ON u.id = ua.user_id
+			WHERE ua.auth_module = ? OR ua.auth_module LIKE ?`, models.AuthModuleLDAP, "oauth_%").Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	latest := map[int64]*externalUser{}
+	for _, row := range rows {
+		if current, ok := latest[row.UserId]; !ok || row.Created.After(current.Created) {
+			latest[row.UserId] = row
+		}
+	}
+
+	users := make([]*externalUser, 0, len(latest))
+	for _, user := range latest {
+		if user.IsDisabled && s.Cfg.DeprovisioningAction == "disable" {
+			continue
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+func groupBySource(users []*externalUser) map[string][]*externalUser {
+	grouped := map[string][]*externalUser{}
+	for _, user := range users {
+		source := SourceOAuth
+		if user.AuthModule == models.AuthModuleLDAP {
+			source = SourceLDAP
+		}
+		grouped[source] = append(grouped[source], user)
+	}
+	return grouped
+}
+
+// findMissingLDAPUsers returns the users that none of the LDAP servers knows. Every server must
+// be available, since the users of a server that is down would otherwise look missing.
+func (s *Service) findMissingLDAPUsers(users []*externalUser) (map[int64]bool, error) {
+	config, err := getLDAPConfig(s.Cfg)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return nil, errors.New("LDAP is not enabled")
+	}
+
+	ldap := newLDAP(config.Servers)
+	statuses, err := ldap.Ping()
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range statuses {
+		if !status.Available {
+			return nil, status.Error
+		}
+	}
+
+	logins := make([]string, 0, len(users))
+	for _, user := range users {
+		logins = append(logins, user.Login)
+	}
+	found, err := ldap.Users(logins)
+	if err != nil {
+		return nil, err
+	}
+
+	foundLogins := map[string]bool{}
+	for _, user := range found {
+		foundLogins[strings.ToLower(user.Login)] = true
+	}
+
+	missing := map[int64]bool{}
+	for _, user := range users {
+		if !foundLogins[strings.ToLower(user.Login)] {
+			missing[user.UserId] = true
+		}
+	}
+	return missing, nil
+}
+
+// findMissingOAuthUsers returns the users whose provider refuses to refresh their token. Users
+// who can't be checked are considered present.
+func (s *Service) findMissingOAuthUsers(ctx context.Context, users []*externalUser) map[int64]bool {
+	missing := map[int64]bool{}
+	for _, user := range users {
+		hasAccess, err := s.hasOAuthAccess(ctx, user.UserId)
+		if err != nil {
+			s.log.Warn("Failed to check OAuth access of user", "userId", user.UserId, "authModule", user.AuthModule, "error", err)
+			continue
+		}
+		if !hasAccess {
+			missing[user.UserId] = true
+		}
+	}
+	return missing
+}
+
+// forgetFound forgets the users that weren't found missing this time, either because they are
+// back in their directory, or because they couldn't be checked. Their grace period starts over
+// the next time they are missing.
+func (s *Service) forgetFound(ctx context.Context, missing map[int64]bool) error {
+	return s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		var tracked []*userDeprovisioning
+		if err := sess.Find(&tracked); err != nil {
+			return err
+		}
+		for _, entry := range tracked {
+			if missing[entry.UserId] {
+				continue
+			}
+			if _, err := sess.ID(entry.Id).Delete(&userDeprovisioning{}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Service) handleMissing(ctx context.Context, user *externalUser) (*ReportUser, error) {
+	entry := userDeprovisioning{}
+	err := s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		has, err := sess.Where("user_id = ?", user.UserId).Get(&entry)
+		if err != nil || has {
+			return err
+		}
+		entry = userDeprovisioning{UserId: user.UserId, AuthModule: user.AuthModule, MissingSince: getTime()}
+		_, err = sess.Insert(&entry)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reportUser := &ReportUser{
+		UserId:       user.UserId,
+		Login:        user.Login,
+		AuthModule:   user.AuthModule,
+		MissingSince: entry.MissingSince,
+		Status:       StatusPending,
+	}
+	if getTime().Sub(entry.MissingSince) < s.Cfg.DeprovisioningGracePeriod {
+		return reportUser, nil
+	}
+
+	if s.Cfg.DeprovisioningDryRun {
+		reportUser.Status = StatusWouldDisable
+		if s.Cfg.DeprovisioningAction == "delete" {
+			reportUser.Status = StatusWouldDelete
+		}
+		return reportUser, nil
+	}
+
+	if s.Cfg.DeprovisioningAction == "delete" {
+		err = bus.Dispatch(&models.DeleteUserCommand{UserId: user.UserId})
+		reportUser.Status = StatusDeleted
+	} else {
+		err = bus.Dispatch(&models.DisableUserCommand{UserId: user.UserId, IsDisabled: true})
+		reportUser.Status = StatusDisabled
+	}
+	if err != nil {
+		s.log.Error("Failed to deprovision user", "userId", user.UserId, "action", s.Cfg.DeprovisioningAction, "error", err)
+		reportUser.Status = StatusFailed
+		reportUser.Error = err.Error()
+		return reportUser, nil
+	}
+
+	s.log.Info("Deprovisioned user missing from their directory", "userId", user.UserId, "login", user.Login,
+		"authModule", user.AuthModule, "missingSince", entry.MissingSince, "action", s.Cfg.DeprovisioningAction)
+
+	return reportUser, s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		_, err := sess.ID(entry.Id).Delete(&userDeprovisioning{})
+		return err
+	})
+}
@@ -0,0 +1,149 @@
+package deprovisioning
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/ldap"
+	"github.com/grafana/grafana/pkg/services/multildap"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLDAP struct {
+	multildap.IMultiLDAP
+	logins    []string
+	available bool
+}
+
+func (f *fakeLDAP) Ping() ([]*multildap.ServerStatus, error) {
+	return []*multildap.ServerStatus{{Host: "ldap.example.com", Available: f.available}}, nil
+}
+
+func (f *fakeLDAP) Users(logins []string) ([]*models.ExternalUserInfo, error) {
+	users := []*models.ExternalUserInfo{}
+	for _, login := range logins {
+		for _, known := range f.logins {
+			if login == known {
+				users = append(users, &models.ExternalUserInfo{Login: login})
+			}
+		}
+	}
+	return users, nil
+}
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	getTime = func() time.Time { return now }
+	t.Cleanup(func() { getTime = time.Now })
+
+	directory := &fakeLDAP{logins: []string{"ldap-present"}, available: true}
+	getLDAPConfig = func(*setting.Cfg) (*ldap.Config, error) {
+		return &ldap.Config{Servers: []*ldap.ServerConfig{{Host: "ldap.example.com"}}}, nil
+	}
+	newLDAP = func([]*ldap.ServerConfig) multildap.IMultiLDAP { return directory }
+	t.Cleanup(func() {
+		getLDAPConfig = multildap.GetConfig
+		newLDAP = multildap.New
+	})
+
+	store := sqlstore.InitTestDB(t)
+	createUser := func(t *testing.T, login, authModule string) int64 {
+		t.Helper()
+		user, err := store.CreateUser(ctx, models.CreateUserCommand{Login: login, Email: login + "@example.com"})
+		require.NoError(t, err)
+		err = store.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+			_, err := sess.Insert(&models.UserAuth{UserId: user.Id, AuthModule: authModule, AuthId: login, Created: now})
+			return err
+		})
+		require.NoError(t, err)
+		return user.Id
+	}
+
+	presentLDAPUser := createUser(t, "ldap-present", models.AuthModuleLDAP)
+	missingLDAPUser := createUser(t, "ldap-missing", models.AuthModuleLDAP)
+	presentOAuthUser := createUser(t, "oauth-present", "oauth_generic_oauth")
+	missingOAuthUser := createUser(t, "oauth-missing", "oauth_generic_oauth")
+	_, err := store.CreateUser(ctx, models.CreateUserCommand{Login: "local"})
+	require.NoError(t, err)
+
+	cfg := &setting.Cfg{
+		DeprovisioningEnabled:     true,
+		DeprovisioningInterval:    time.Hour,
+		DeprovisioningSources:     []string{SourceLDAP, SourceOAuth},
+		DeprovisioningAction:      "disable",
+		DeprovisioningGracePeriod: 24 * time.Hour,
+		DeprovisioningDryRun:      true,
+	}
+	s := &Service{Cfg: cfg, SQLStore: store}
+	require.NoError(t, s.Init())
+	s.hasOAuthAccess = func(ctx context.Context, userID int64) (bool, error) {
+		return userID != missingOAuthUser, nil
+	}
+
+	statuses := func(report *Report) map[int64]string {
+		result := map[int64]string{}
+		for _, user := range report.Users {
+			result[user.UserId] = user.Status
+		}
+		return result
+	}
+
+	isDisabled := func(t *testing.T, userID int64) bool {
+		t.Helper()
+		query := models.GetUserByIdQuery{Id: userID}
+		require.NoError(t, sqlstore.GetUserById(ctx, &query))
+		return query.Result.IsDisabled
+	}
+
+	t.Run("Missing users are pending during the grace period", func(t *testing.T) {
+		report, err := s.run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, map[int64]string{
+			missingLDAPUser:  StatusPending,
+			missingOAuthUser: StatusPending,
+		}, statuses(report))
+	})
+
+	t.Run("Missing users aren't changed in dry run mode", func(t *testing.T) {
+		now = now.Add(25 * time.Hour)
+
+		report, err := s.run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, map[int64]string{
+			missingLDAPUser:  StatusWouldDisable,
+			missingOAuthUser: StatusWouldDisable,
+		}, statuses(report))
+		assert.False(t, isDisabled(t, missingLDAPUser))
+	})
+
+	t.Run("Users aren't checked when an LDAP server is unavailable", func(t *testing.T) {
+		directory.available = false
+		t.Cleanup(func() { directory.available = true })
+
+		report, err := s.run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, map[int64]string{missingOAuthUser: StatusWouldDisable}, statuses(report))
+	})
+
+	t.Run("Missing users are disabled after the grace period", func(t *testing.T) {
+		cfg.DeprovisioningDryRun = false
+
+		report, err := s.run(ctx)
+		require.NoError(t, err)
+		// the grace period of the LDAP user started over when they couldn't be checked
+		assert.Equal(t, map[int64]string{
+			missingLDAPUser:  StatusPending,
+			missingOAuthUser: StatusDisabled,
+		}, statuses(report))
+		assert.True(t, isDisabled(t, missingOAuthUser))
+		assert.False(t, isDisabled(t, missingLDAPUser))
+		assert.False(t, isDisabled(t, presentOAuthUser))
+		assert.False(t, isDisabled(t, presentLDAPUser))
+	})
+}
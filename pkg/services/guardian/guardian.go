@@ -6,6 +6,7 @@ import (
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/registry"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -14,6 +15,23 @@ var (
 	ErrGuardianOverride         = errors.New("you can only override a permission to be higher")
 )
 
+func init() {
+	registry.RegisterService(&Service{})
+}
+
+// cfg is the configuration read by the guardians, set when the service is initialized.
+var cfg *setting.Cfg
+
+// Service provides the guardians, which are created without it, with the configuration.
+type Service struct {
+	Cfg *setting.Cfg `inject:""`
+}
+
+func (s *Service) Init() error {
+	cfg = s.Cfg
+	return nil
+}
+
 // DashboardGuardian to be used for guard against operations without access on dashboard and acl
 type DashboardGuardian interface {
 	CanSave() (bool, error)
@@ -73,6 +91,13 @@ func (g *dashboardGuardianImpl) CanAdmin() (bool, error) {
 }
 
 func (g *dashboardGuardianImpl) HasPermission(permission models.PermissionType) (bool, error) {
+	if g.user.IsAnonymous {
+		allowed, err := g.anonymousCanAccess()
+		if err != nil || !allowed {
+			return g.logHasPermissionResult(permission, false, err)
+		}
+	}
+
 	if g.user.OrgRole == models.ROLE_ADMIN {
 		return g.logHasPermissionResult(permission, true, nil)
 	}
@@ -86,6 +111,62 @@ func (g *dashboardGuardianImpl) HasPermission(permission models.PermissionType)
 	return g.logHasPermissionResult(permission, result, err)
 }
 
+// anonymousCanAccess checks the dashboard against the dashboards and folders
+// anonymous users are restricted to, or excluded from.
+func (g *dashboardGuardianImpl) anonymousCanAccess() (bool, error) {
+	if cfg == nil {
+		return true, nil
+	}
+
+	switch cfg.AnonymousDashboardAccess {
+	case "include":
+		return g.isListedForAnonymous()
+	case "exclude":
+		listed, err := g.isListedForAnonymous()
+		return !listed, err
+	default:
+		return true, nil
+	}
+}
+
+func (g *dashboardGuardianImpl) isListedForAnonymous() (bool, error) {
+	// the General folder can't be listed
+	if g.dashId == 0 {
+		return false, nil
+	}
+
+	query := models.GetDashboardQuery{Id: g.dashId, OrgId: g.orgId}
+	if err := bus.Dispatch(&query); err != nil {
+		return false, err
+	}
+	dash := query.Result
+
+	if dash.IsFolder {
+		return containsString(cfg.AnonymousFolderUIDs, dash.Uid), nil
+	}
+	if containsString(cfg.AnonymousDashboardUIDs, dash.Uid) {
+		return true, nil
+	}
+	if dash.FolderId == 0 || len(cfg.AnonymousFolderUIDs) == 0 {
+		return false, nil
+	}
+
+	folderQuery := models.GetDashboardQuery{Id: dash.FolderId, OrgId: g.orgId}
+	if err := bus.Dispatch(&folderQuery); err != nil {
+		return false, err
+	}
+	return containsString(cfg.AnonymousFolderUIDs, folderQuery.Result.Uid), nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 func (g *dashboardGuardianImpl) logHasPermissionResult(permission models.PermissionType, hasPermission bool, err error) (bool, error) {
 	if err != nil {
 		return hasPermission, err
@@ -768,3 +768,76 @@ func TestGuardianGetAclWithoutDuplicates(t *testing.T) {
 		})
 	})
 }
+
+func TestGuardianAnonymousDashboardAccess(t *testing.T) {
+	bus.ClearBusHandlers()
+	origCfg := cfg
+	t.Cleanup(func() {
+		cfg = origCfg
+	})
+
+	dashboards := map[int64]*models.Dashboard{
+		parentFolderID:   {Id: parentFolderID, Uid: "folder", IsFolder: true},
+		childDashboardID: {Id: childDashboardID, Uid: "child", FolderId: parentFolderID},
+		dashboardID:      {Id: dashboardID, Uid: "dash"},
+	}
+	bus.AddHandler("test", func(query *models.GetDashboardQuery) error {
+		query.Result = dashboards[query.Id]
+		return nil
+	})
+	bus.AddHandler("test", func(query *models.GetDashboardAclInfoListQuery) error {
+		query.Result = []*models.DashboardAclInfoDTO{
+			{OrgId: orgID, DashboardId: query.DashboardID, Role: &viewerRole, Permission: models.PERMISSION_VIEW},
+		}
+		return nil
+	})
+
+	tests := []struct {
+		desc       string
+		access     string
+		dashboards []string
+		folders    []string
+		expected   map[int64]bool
+	}{
+		{
+			desc:     "All dashboards",
+			access:   "all",
+			expected: map[int64]bool{parentFolderID: true, childDashboardID: true, dashboardID: true},
+		},
+		{
+			desc:     "Only the dashboards of an included folder",
+			access:   "include",
+			folders:  []string{"folder"},
+			expected: map[int64]bool{parentFolderID: true, childDashboardID: true, dashboardID: false},
+		},
+		{
+			desc:       "Only an included dashboard",
+			access:     "include",
+			dashboards: []string{"dash"},
+			expected:   map[int64]bool{parentFolderID: false, childDashboardID: false, dashboardID: true},
+		},
+		{
+			desc:     "All but the dashboards of an excluded folder",
+			access:   "exclude",
+			folders:  []string{"folder"},
+			expected: map[int64]bool{parentFolderID: false, childDashboardID: false, dashboardID: true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			cfg = &setting.Cfg{
+				AnonymousDashboardAccess: test.access,
+				AnonymousDashboardUIDs:   test.dashboards,
+				AnonymousFolderUIDs:      test.folders,
+			}
+
+			for id, expected := range test.expected {
+				user := &models.SignedInUser{OrgId: orgID, OrgRole: models.ROLE_VIEWER, IsAnonymous: true}
+				canView, err := New(id, orgID, user).CanView()
+				require.NoError(t, err)
+				require.Equal(t, expected, canView, "dashboard %d", id)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// Package identitycache forwards invalidations of the in-memory identity
+// cache to every Grafana instance sharing the remote cache, so that changes
+// made on one instance are not served stale by the others.
+package identitycache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/registry"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	channel = "grafana-identity-cache-invalidation"
+
+	resubscribeInterval = 10 * time.Second
+)
+
+const (
+	kindUser         = "user"
+	kindOrg          = "org"
+	kindDashboardAcl = "dashboard-acl"
+)
+
+func init() {
+	registry.RegisterService(&Service{})
+}
+
+// invalidation is the message broadcast to the other instances.
+type invalidation struct {
+	Kind string `json:"kind"`
+	ID   int64  `json:"id"`
+}
+
+// Service relays identity cache invalidations through the remote cache.
+// Only remote caches supporting pub/sub can relay them; with the others,
+// instances rely on the cache TTL.
+type Service struct {
+	Cfg         *setting.Cfg             `inject:""`
+	Bus         bus.Bus                  `inject:""`
+	SQLStore    *sqlstore.SQLStore       `inject:""`
+	RemoteCache *remotecache.RemoteCache `inject:""`
+
+	log log.Logger
+}
+
+// Init registers the listeners for the events invalidating the cache.
+func (s *Service) Init() error {
+	s.log = log.New("identitycache")
+
+	s.Bus.AddEventListener(s.onUserUpdated)
+	s.Bus.AddEventListener(s.onUserAccessChanged)
+	s.Bus.AddEventListener(s.onUserCredentialsRevoked)
+	s.Bus.AddEventListener(s.onOrgUpdated)
+	s.Bus.AddEventListener(s.onDashboardAclUpdated)
+
+	return nil
+}
+
+// IsDisabled returns true if the identity cache is turned off.
+func (s *Service) IsDisabled() bool {
+	return s.Cfg.IdentityCacheTTL <= 0
+}
+
+// Run applies the invalidations published by other instances until the context is canceled.
+func (s *Service) Run(ctx context.Context) error {
+	for {
+		err := s.RemoteCache.Subscribe(ctx, channel, s.handleMessage)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if errors.Is(err, remotecache.ErrPubSubNotSupported) {
+			s.log.Debug("Remote cache does not support pub/sub, identity cache entries expire after their TTL on other instances")
+			<-ctx.Done()
+			return ctx.Err()
+		}
+
+		s.log.Warn("Identity cache invalidation subscription failed, retrying", "err", err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(resubscribeInterval):
+		}
+	}
+}
+
+func (s *Service) handleMessage(msg string) {
+	var inv invalidation
+	if err := json.Unmarshal([]byte(msg), &inv); err != nil {
+		s.log.Warn("Failed to decode identity cache invalidation", "err", err)
+		return
+	}
+
+	switch inv.Kind {
+	case kindUser:
+		s.SQLStore.InvalidateUserCache(inv.ID)
+	case kindOrg:
+		s.SQLStore.InvalidateOrgCache()
+	case kindDashboardAcl:
+		s.SQLStore.InvalidateDashboardAclCache(inv.ID)
+	default:
+		s.log.Debug("Ignoring unknown identity cache invalidation", "kind", inv.Kind)
+	}
+}
+
+func (s *Service) publish(inv invalidation) error {
+	msg, err := json.Marshal(inv)
+	if err != nil {
+		return err
+	}
+
+	err = s.RemoteCache.Publish(channel, string(msg))
+	if err != nil && !errors.Is(err, remotecache.ErrPubSubNotSupported) {
+		// the change itself succeeded, other instances will catch up once their entries expire
+		s.log.Warn("Failed to publish identity cache invalidation", "kind", inv.Kind, "id", inv.ID, "err", err)
+	}
+	return nil
+}
+
+func (s *Service) onUserUpdated(evt *events.UserUpdated) error {
+	return s.publish(invalidation{Kind: kindUser, ID: evt.Id})
+}
+
+func (s *Service) onUserAccessChanged(evt *events.UserAccessChanged) error {
+	return s.publish(invalidation{Kind: kindUser, ID: evt.UserId})
+}
+
+func (s *Service) onUserCredentialsRevoked(evt *events.UserCredentialsRevoked) error {
+	return s.publish(invalidation{Kind: kindUser, ID: evt.UserId})
+}
+
+func (s *Service) onOrgUpdated(evt *events.OrgUpdated) error {
+	return s.publish(invalidation{Kind: kindOrg, ID: evt.Id})
+}
+
+func (s *Service) onDashboardAclUpdated(evt *events.DashboardAclUpdated) error {
+	return s.publish(invalidation{Kind: kindDashboardAcl, ID: evt.OrgId})
+}
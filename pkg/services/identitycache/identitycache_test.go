@@ -0,0 +1,70 @@
+package identitycache
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService(t *testing.T) {
+	ss := sqlstore.InitTestDB(t)
+	ss.Cfg.IdentityCacheTTL = time.Minute
+	t.Cleanup(func() { ss.Cfg.IdentityCacheTTL = 0 })
+
+	s := &Service{
+		Cfg:         ss.Cfg,
+		Bus:         bus.New(),
+		SQLStore:    ss,
+		RemoteCache: remotecache.NewFakeStore(t),
+		log:         log.New("identitycache.test"),
+	}
+	require.NoError(t, s.Init())
+
+	user, err := ss.CreateUser(context.Background(), models.CreateUserCommand{
+		Email: "identitycache@test.com",
+		Login: "identitycache",
+	})
+	require.NoError(t, err)
+
+	t.Run("applies user invalidations from other instances", func(t *testing.T) {
+		ss.CacheService.Flush()
+		require.NoError(t, ss.GetUserByIdWithCacheCtx(context.Background(), &models.GetUserByIdQuery{Id: user.Id}))
+		require.NotEmpty(t, ss.CacheService.Items())
+
+		s.handleMessage(`{"kind":"user","id":` + strconv.FormatInt(user.Id, 10) + `}`)
+
+		assert.Empty(t, ss.CacheService.Items())
+	})
+
+	t.Run("ignores malformed messages", func(t *testing.T) {
+		ss.CacheService.Flush()
+		require.NoError(t, ss.GetUserByIdWithCacheCtx(context.Background(), &models.GetUserByIdQuery{Id: user.Id}))
+
+		s.handleMessage(`not json`)
+		s.handleMessage(`{"kind":"unknown","id":1}`)
+
+		assert.NotEmpty(t, ss.CacheService.Items())
+	})
+
+	t.Run("events don't fail without pub/sub support", func(t *testing.T) {
+		assert.NoError(t, s.Bus.Publish(&events.UserAccessChanged{UserId: user.Id}))
+		assert.NoError(t, s.Bus.Publish(&events.DashboardAclUpdated{OrgId: user.OrgId}))
+		assert.NoError(t, s.Bus.Publish(&events.UserCredentialsRevoked{UserId: user.Id}))
+	})
+
+	t.Run("stops when the context is canceled without pub/sub support", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.ErrorIs(t, s.Run(ctx), context.Canceled)
+	})
+}
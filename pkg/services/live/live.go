@@ -107,16 +107,15 @@ type GrafanaLive struct {
 	storage          *database.Storage
 }
 
+// getStreamPlugin returns the stream handler of a backend plugin. Stream calls
+// go through the backend plugin manager, which routes them to the plugin that is
+// registered when the call is made, so that streams keep working after the
+// plugin process was restarted or the plugin was reloaded.
 func (g *GrafanaLive) getStreamPlugin(pluginID string) (backend.StreamHandler, error) {
-	plugin, ok := g.PluginManager.BackendPluginManager.Get(pluginID)
-	if !ok {
+	if _, ok := g.PluginManager.BackendPluginManager.Get(pluginID); !ok {
 		return nil, fmt.Errorf("plugin not found: %s", pluginID)
 	}
-	streamHandler, ok := plugin.(backend.StreamHandler)
-	if !ok {
-		return nil, fmt.Errorf("%s plugin does not implement StreamHandler: %#v", pluginID, plugin)
-	}
-	return streamHandler, nil
+	return g.PluginManager.BackendPluginManager, nil
 }
 
 // AddMigration defines database migrations.
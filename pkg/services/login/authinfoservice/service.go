@@ -9,6 +9,7 @@ import (
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/registry"
 	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 )
 
@@ -28,6 +29,7 @@ type Implementation struct {
 	Bus                   bus.Bus                     `inject:""`
 	SQLStore              *sqlstore.SQLStore          `inject:""`
 	UserProtectionService login.UserProtectionService `inject:""`
+	SecretsService        *secrets.Service            `inject:""`
 
 	logger log.Logger
 }
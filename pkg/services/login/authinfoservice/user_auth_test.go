@@ -4,10 +4,12 @@ package authinfoservice
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 
 	"github.com/grafana/grafana/pkg/bus"
@@ -20,10 +22,13 @@ import (
 //nolint:goconst
 func TestUserAuth(t *testing.T) {
 	sqlStore := sqlstore.InitTestDB(t)
+	secretsService := &secrets.Service{SQLStore: sqlStore}
+	require.NoError(t, secretsService.Init())
 	srv := &Implementation{
 		Bus:                   bus.New(),
 		SQLStore:              sqlStore,
 		UserProtectionService: OSSUserProtectionImpl{},
+		SecretsService:        secretsService,
 	}
 	srv.Init()
 
@@ -167,6 +172,14 @@ func TestUserAuth(t *testing.T) {
 			require.Equal(t, getAuthQuery.Result.OAuthAccessToken, token.AccessToken)
 			require.Equal(t, getAuthQuery.Result.OAuthRefreshToken, token.RefreshToken)
 			require.Equal(t, getAuthQuery.Result.OAuthTokenType, token.TokenType)
+
+			// the tokens are encrypted with the data key of the user
+			stored := &models.UserAuth{UserId: user.Id}
+			_, err = sqlStore.NewSession(context.Background()).Desc("created").Get(stored)
+			require.Nil(t, err)
+			decoded, err := base64.StdEncoding.DecodeString(stored.OAuthRefreshToken)
+			require.Nil(t, err)
+			require.True(t, secrets.IsEncryptedWithDataKey(decoded))
 		})
 
 		t.Run("Always return the most recently used auth_module", func(t *testing.T) {
@@ -45,26 +45,31 @@ type Alertmanager interface {
 	// Alerts
 	GetAlerts(active, silenced, inhibited bool, filter []string, receiver string) (apimodels.GettableAlerts, error)
 	GetAlertGroups(active, silenced, inhibited bool, filter []string, receiver string) (apimodels.AlertGroups, error)
+	PutAlerts(postableAlerts apimodels.PostableAlerts) error
 
 	// Testing
 	TestReceivers(ctx context.Context, c apimodels.TestReceiversConfigParams) (*notifier.TestReceiversResult, error)
+	TestTemplate(ctx context.Context, c apimodels.TestTemplatePayload) (string, error)
 }
 
 // API handlers.
 type API struct {
-	Cfg              *setting.Cfg
-	DatasourceCache  datasources.CacheService
-	RouteRegister    routing.RouteRegister
-	DataService      *tsdb.Service
-	QuotaService     *quota.QuotaService
-	Schedule         schedule.ScheduleService
-	RuleStore        store.RuleStore
-	InstanceStore    store.InstanceStore
-	AlertingStore    store.AlertingStore
-	AdminConfigStore store.AdminConfigurationStore
-	DataProxy        *datasourceproxy.DatasourceProxyService
-	Alertmanager     Alertmanager
-	StateManager     *state.Manager
+	Cfg                *setting.Cfg
+	DatasourceCache    datasources.CacheService
+	RouteRegister      routing.RouteRegister
+	DataService        *tsdb.Service
+	QuotaService       *quota.QuotaService
+	Schedule           schedule.ScheduleService
+	RuleStore          store.RuleStore
+	InstanceStore      store.InstanceStore
+	HistoryStore       store.StateHistoryStore
+	AlertingStore      store.AlertingStore
+	AdminConfigStore   store.AdminConfigurationStore
+	MigrationStore     store.LegacyAlertingMigrationStore
+	RecordedQueryStore store.RecordedQueryStore
+	DataProxy          *datasourceproxy.DatasourceProxyService
+	Alertmanager       Alertmanager
+	StateManager       *state.Manager
 }
 
 // RegisterAPIEndpoints registers API handlers
@@ -74,11 +79,14 @@ func (api *API) RegisterAPIEndpoints(m *metrics.Metrics) {
 		DataProxy: api.DataProxy,
 	}
 
+	alertmanagerSrv := AlertmanagerSrv{store: api.AlertingStore, am: api.Alertmanager, quotaService: api.QuotaService, log: logger}
+	rulerSrv := RulerSrv{DatasourceCache: api.DatasourceCache, QuotaService: api.QuotaService, manager: api.StateManager, store: api.RuleStore, log: logger}
+
 	// Register endpoints for proxying to Alertmanager-compatible backends.
 	api.RegisterAlertmanagerApiEndpoints(NewForkedAM(
 		api.DatasourceCache,
 		NewLotexAM(proxy, logger),
-		AlertmanagerSrv{store: api.AlertingStore, am: api.Alertmanager, log: logger},
+		alertmanagerSrv,
 	), m)
 	// Register endpoints for proxying to Prometheus-compatible backends.
 	api.RegisterPrometheusApiEndpoints(NewForkedProm(
@@ -90,7 +98,7 @@ func (api *API) RegisterAPIEndpoints(m *metrics.Metrics) {
 	api.RegisterRulerApiEndpoints(NewForkedRuler(
 		api.DatasourceCache,
 		NewLotexRuler(proxy, logger),
-		RulerSrv{DatasourceCache: api.DatasourceCache, QuotaService: api.QuotaService, manager: api.StateManager, store: api.RuleStore, log: logger},
+		rulerSrv,
 	), m)
 	api.RegisterTestingApiEndpoints(TestingApiSrv{
 		AlertingProxy:   proxy,
@@ -100,8 +108,59 @@ func (api *API) RegisterAPIEndpoints(m *metrics.Metrics) {
 		log:             logger,
 	}, m)
 	api.RegisterConfigurationApiEndpoints(AdminSrv{
-		store:     api.AdminConfigStore,
+		store:          api.AdminConfigStore,
+		migrationStore: api.MigrationStore,
+		am:             api.Alertmanager,
+		log:            logger,
+		scheduler:      api.Schedule,
+	}, m)
+	api.RegisterExportApiEndpoints(ExportSrv{
+		ruler:        rulerSrv,
+		alertmanager: alertmanagerSrv,
+		log:          logger,
+	}, m)
+	api.RegisterPoliciesApiEndpoints(PolicySrv{
+		store: api.AlertingStore,
+		am:    api.Alertmanager,
+		log:   logger,
+	}, m)
+	api.RegisterInhibitionsApiEndpoints(InhibitionSrv{
+		store: api.AlertingStore,
+		am:    api.Alertmanager,
+		log:   logger,
+	}, m)
+	api.RegisterTemplatesApiEndpoints(TemplateSrv{
+		store: api.AlertingStore,
+		am:    api.Alertmanager,
+		log:   logger,
+	}, m)
+	api.RegisterHistoryApiEndpoints(HistorySrv{
+		store:     api.HistoryStore,
+		ruleStore: api.RuleStore,
 		log:       logger,
-		scheduler: api.Schedule,
+	}, m)
+	api.RegisterDeadLettersApiEndpoints(DeadLetterSrv{
+		store: api.AlertingStore,
+		log:   logger,
+	}, m)
+	api.RegisterRulesApiEndpoints(RulesSrv{
+		store:   api.RuleStore,
+		manager: api.StateManager,
+		log:     logger,
+	}, m)
+	api.RegisterRecordedQueriesApiEndpoints(RecordedQuerySrv{
+		store:           api.RecordedQueryStore,
+		DatasourceCache: api.DatasourceCache,
+		log:             logger,
+	}, m)
+	api.RegisterAcknowledgementsApiEndpoints(AcknowledgementsSrv{
+		store:   api.RuleStore,
+		manager: api.StateManager,
+		log:     logger,
+	}, m)
+	api.RegisterRuleVersionsApiEndpoints(RuleVersionsSrv{
+		store:   api.RuleStore,
+		manager: api.StateManager,
+		log:     logger,
 	}, m)
 }
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// AcknowledgementsSrv acknowledges the firing alerts, so that the escalation chains stop notifying
+// other contact points about them.
+type AcknowledgementsSrv struct {
+	store   store.RuleStore
+	manager *state.Manager
+	log     log.Logger
+}
+
+func (srv AcknowledgementsSrv) RouteAcknowledgeAlerts(c *models.ReqContext, body apimodels.AcknowledgePayload) response.Response {
+	if resp := srv.checkRule(c, body.RuleUID); resp != nil {
+		return resp
+	}
+
+	by := c.SignedInUser.Login
+	if by == "" {
+		by = fmt.Sprintf("api-key-%d", c.SignedInUser.ApiKeyId)
+	}
+	return toAcknowledgeResult(srv.manager.Acknowledge(c.OrgId, body.RuleUID, body.Labels, by, timeNow()))
+}
+
+func (srv AcknowledgementsSrv) RouteUnacknowledgeAlerts(c *models.ReqContext, body apimodels.AcknowledgePayload) response.Response {
+	if resp := srv.checkRule(c, body.RuleUID); resp != nil {
+		return resp
+	}
+	return toAcknowledgeResult(srv.manager.Unacknowledge(c.OrgId, body.RuleUID, body.Labels))
+}
+
+// checkRule returns an error response if the user can't acknowledge the alerts of the alert rule.
+func (srv AcknowledgementsSrv) checkRule(c *models.ReqContext, ruleUID string) response.Response {
+	if !c.HasUserRole(models.ROLE_EDITOR) {
+		return accessForbiddenResp()
+	}
+	if ruleUID == "" {
+		return ErrResp(http.StatusBadRequest, errors.New("missing rule UID"), "")
+	}
+
+	q := ngmodels.GetAlertRuleByUIDQuery{OrgID: c.OrgId, UID: ruleUID}
+	if err := srv.store.GetAlertRuleByUID(&q); err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to get alert rule")
+	}
+	namespaceMap, err := srv.store.GetNamespaces(c.OrgId, c.SignedInUser)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get namespaces visible to the user")
+	}
+	if _, ok := namespaceMap[q.Result.NamespaceUID]; !ok {
+		return ErrResp(http.StatusNotFound, ngmodels.ErrAlertRuleNotFound, "")
+	}
+	return nil
+}
+
+func toAcknowledgeResult(states []*state.State) response.Response {
+	result := apimodels.AcknowledgeResult{Alerts: make([]apimodels.AcknowledgedAlert, 0, len(states))}
+	for _, s := range states {
+		alert := apimodels.AcknowledgedAlert{
+			Labels:         s.Labels,
+			ActiveAt:       s.StartsAt,
+			AcknowledgedBy: s.AcknowledgedBy,
+		}
+		if s.IsAcknowledged() {
+			acknowledgedAt := s.AcknowledgedAt
+			alert.AcknowledgedAt = &acknowledgedAt
+		}
+		result.Alerts = append(result.Alerts, alert)
+	}
+	return response.JSON(http.StatusOK, result)
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 
@@ -10,15 +11,18 @@ import (
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrations/ualert"
 	"github.com/grafana/grafana/pkg/util"
 
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 )
 
 type AdminSrv struct {
-	scheduler Scheduler
-	store     store.AdminConfigurationStore
-	log       log.Logger
+	scheduler      Scheduler
+	store          store.AdminConfigurationStore
+	migrationStore store.LegacyAlertingMigrationStore
+	am             Alertmanager
+	log            log.Logger
 }
 
 func (srv AdminSrv) RouteGetAlertmanagers(c *models.ReqContext) response.Response {
@@ -55,7 +59,8 @@ func (srv AdminSrv) RouteGetNGalertConfig(c *models.ReqContext) response.Respons
 	}
 
 	resp := apimodels.GettableNGalertConfig{
-		Alertmanagers: cfg.Alertmanagers,
+		Alertmanagers:       cfg.Alertmanagers,
+		AlertmanagersChoice: cfg.SendAlertsTo.String(),
 	}
 	return response.JSON(http.StatusOK, resp)
 }
@@ -65,8 +70,17 @@ func (srv AdminSrv) RoutePostNGalertConfig(c *models.ReqContext, body apimodels.
 		return accessForbiddenResp()
 	}
 
+	sendAlertsTo, err := ngmodels.ParseAlertmanagersChoice(body.AlertmanagersChoice)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if sendAlertsTo == ngmodels.ExternalAlertmanagers && len(body.Alertmanagers) == 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("at least one Alertmanager must be provided to only send alerts to external Alertmanagers"), "")
+	}
+
 	cfg := &ngmodels.AdminConfiguration{
 		Alertmanagers: body.Alertmanagers,
+		SendAlertsTo:  sendAlertsTo,
 		OrgID:         c.OrgId,
 	}
 
@@ -93,3 +107,57 @@ func (srv AdminSrv) RouteDeleteNGalertConfig(c *models.ReqContext) response.Resp
 
 	return response.JSON(http.StatusOK, util.DynMap{"message": "admin configuration deleted"})
 }
+
+func (srv AdminSrv) RoutePostLegacyAlertingMigration(c *models.ReqContext, body apimodels.PostableLegacyAlertingMigration) response.Response {
+	if c.OrgRole != models.ROLE_ADMIN {
+		return accessForbiddenResp()
+	}
+
+	report, err := srv.migrationStore.MigrateLegacyAlerting(c.OrgId, body.DryRun)
+	if err != nil {
+		if errors.Is(err, ualert.ErrOrgAlreadyMigrated) {
+			return ErrResp(http.StatusConflict, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to migrate the dashboard alerts")
+	}
+
+	// the contact points are checked on a dry run too, the configuration replaces the one of the organization
+	if report.AlertmanagerConfig != nil {
+		var cfg apimodels.PostableUserConfig
+		if err := json.Unmarshal(report.AlertmanagerConfig, &cfg); err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to read the migrated Alertmanager configuration")
+		}
+		if err := cfg.ProcessConfig(); err != nil {
+			return ErrResp(http.StatusBadRequest, err, "failed to post process the migrated Alertmanager configuration")
+		}
+		if !body.DryRun {
+			if err := srv.am.SaveAndApplyConfig(c.OrgId, &cfg); err != nil {
+				srv.log.Error("unable to save and apply the migrated alertmanager configuration", "err", err)
+				return ErrResp(http.StatusBadRequest, err, "failed to save and apply the migrated Alertmanager configuration")
+			}
+		}
+	}
+
+	return response.JSON(http.StatusOK, toLegacyAlertingMigrationReport(report, body.DryRun))
+}
+
+func toLegacyAlertingMigrationReport(report *ualert.OrgMigrationReport, dryRun bool) apimodels.LegacyAlertingMigrationReport {
+	result := apimodels.LegacyAlertingMigrationReport{
+		DryRun:             dryRun,
+		MigratedAlerts:     make([]apimodels.MigratedLegacyAlert, 0, len(report.MigratedAlerts)),
+		UnmigratedAlerts:   make([]apimodels.UnmigratedLegacyAlert, 0, len(report.UnmigratedAlerts)),
+		ContactPoints:      make([]string, 0, len(report.ContactPoints)),
+		UnmigratedChannels: make([]apimodels.UnmigratedNotificationChannel, 0, len(report.UnmigratedChannels)),
+	}
+	for _, a := range report.MigratedAlerts {
+		result.MigratedAlerts = append(result.MigratedAlerts, apimodels.MigratedLegacyAlert(a))
+	}
+	for _, a := range report.UnmigratedAlerts {
+		result.UnmigratedAlerts = append(result.UnmigratedAlerts, apimodels.UnmigratedLegacyAlert(a))
+	}
+	result.ContactPoints = append(result.ContactPoints, report.ContactPoints...)
+	for _, ch := range report.UnmigratedChannels {
+		result.UnmigratedChannels = append(result.UnmigratedChannels, apimodels.UnmigratedNotificationChannel(ch))
+	}
+	return result
+}
@@ -16,6 +16,7 @@ import (
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -25,9 +26,10 @@ const (
 )
 
 type AlertmanagerSrv struct {
-	am    Alertmanager
-	store store.AlertingStore
-	log   log.Logger
+	am           Alertmanager
+	store        store.AlertingStore
+	quotaService *quota.QuotaService
+	log          log.Logger
 }
 
 type UnknownReceiverError struct {
@@ -38,7 +40,9 @@ func (e UnknownReceiverError) Error() string {
 	return fmt.Sprintf("unknown receiver: %s", e.UID)
 }
 
-func (srv AlertmanagerSrv) loadSecureSettings(orgId int64, receivers []*apimodels.PostableApiReceiver) error {
+// loadSecureSettings copies the secure settings of the stored receivers missing from the receivers.
+// Receivers with a UID that isn't stored are rejected, unless allowUnknownUIDs is set.
+func (srv AlertmanagerSrv) loadSecureSettings(orgId int64, receivers []*apimodels.PostableApiReceiver, allowUnknownUIDs bool) error {
 	// Get the last known working configuration
 	query := ngmodels.GetLatestAlertmanagerConfigurationQuery{OrgID: orgId}
 	if err := srv.store.GetLatestAlertmanagerConfiguration(&query); err != nil {
@@ -66,6 +70,9 @@ func (srv AlertmanagerSrv) loadSecureSettings(orgId int64, receivers []*apimodel
 
 			cgmr, ok := currentReceiverMap[gr.UID]
 			if !ok {
+				if allowUnknownUIDs {
+					continue
+				}
 				// it tries to update a receiver that didn't previously exist
 				return UnknownReceiverError{UID: gr.UID}
 			}
@@ -112,7 +119,8 @@ func (srv AlertmanagerSrv) RouteCreateSilence(c *models.ReqContext, postableSile
 
 		return ErrResp(http.StatusInternalServerError, err, "failed to create silence")
 	}
-	return response.JSON(http.StatusAccepted, util.DynMap{"message": "silence created", "id": silenceID})
+	// silenceID is the field of the response of the Alertmanager API
+	return response.JSON(http.StatusOK, util.DynMap{"message": "silence created", "id": silenceID, "silenceID": silenceID})
 }
 
 func (srv AlertmanagerSrv) RouteDeleteAlertingConfig(c *models.ReqContext) response.Response {
@@ -281,7 +289,7 @@ func (srv AlertmanagerSrv) RoutePostAlertingConfig(c *models.ReqContext, body ap
 		}
 	}
 
-	if err := srv.loadSecureSettings(c.OrgId, body.AlertmanagerConfig.Receivers); err != nil {
+	if err := srv.loadSecureSettings(c.OrgId, body.AlertmanagerConfig.Receivers, false); err != nil {
 		var unknownReceiverError UnknownReceiverError
 		if errors.As(err, &unknownReceiverError) {
 			return ErrResp(http.StatusBadRequest, err, "")
@@ -293,6 +301,14 @@ func (srv AlertmanagerSrv) RoutePostAlertingConfig(c *models.ReqContext, body ap
 		return ErrResp(http.StatusInternalServerError, err, "failed to post process Alertmanager configuration")
 	}
 
+	usage := quotaUsage{}
+	if err := addContactPointUsage(usage, srv.store, c.OrgId, &body); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get latest configuration")
+	}
+	if resp := checkQuotas(c, srv.quotaService, usage); resp != nil {
+		return resp
+	}
+
 	if err := srv.am.SaveAndApplyConfig(c.OrgId, &body); err != nil {
 		srv.log.Error("unable to save and apply alertmanager configuration", "err", err)
 		return ErrResp(http.StatusBadRequest, err, "failed to save and apply Alertmanager configuration")
@@ -302,7 +318,17 @@ func (srv AlertmanagerSrv) RoutePostAlertingConfig(c *models.ReqContext, body ap
 }
 
 func (srv AlertmanagerSrv) RoutePostAMAlerts(c *models.ReqContext, body apimodels.PostableAlerts) response.Response {
-	return NotImplementedResp
+	if !c.HasUserRole(models.ROLE_EDITOR) {
+		return accessForbiddenResp()
+	}
+	if err := srv.am.PutAlerts(body); err != nil {
+		var validationErr *notifier.AlertValidationError
+		if errors.As(err, &validationErr) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to create alerts")
+	}
+	return response.JSON(http.StatusOK, util.DynMap{"message": "alerts created"})
 }
 
 func (srv AlertmanagerSrv) RoutePostTestReceivers(c *models.ReqContext, body apimodels.TestReceiversConfigParams) response.Response {
@@ -310,7 +336,7 @@ func (srv AlertmanagerSrv) RoutePostTestReceivers(c *models.ReqContext, body api
 		return accessForbiddenResp()
 	}
 
-	if err := srv.loadSecureSettings(c.OrgId, body.Receivers); err != nil {
+	if err := srv.loadSecureSettings(c.OrgId, body.Receivers, false); err != nil {
 		var unknownReceiverError UnknownReceiverError
 		if errors.As(err, &unknownReceiverError) {
 			return ErrResp(http.StatusBadRequest, err, "")
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// defaultDeadLettersLimit is the number of failed notifications returned when no limit is given.
+const defaultDeadLettersLimit = 100
+
+// DeadLetterSrv serves the notifications that could not be sent after all their attempts.
+type DeadLetterSrv struct {
+	store store.NotificationDeadLetterStore
+	log   log.Logger
+}
+
+func (srv DeadLetterSrv) RouteGetNotificationDeadLetters(c *models.ReqContext) response.Response {
+	if !c.HasUserRole(models.ROLE_EDITOR) {
+		return accessForbiddenResp()
+	}
+
+	query := ngmodels.ListNotificationDeadLettersQuery{
+		OrgID:    c.SignedInUser.OrgId,
+		Receiver: c.Query("receiver"),
+		From:     c.QueryInt64("from"),
+		To:       c.QueryInt64("to"),
+		Limit:    c.QueryInt("limit"),
+	}
+	if query.Limit < 0 || query.Limit > store.NotificationDeadLetterMaxLimit {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("limit must be between 1 and %d", store.NotificationDeadLetterMaxLimit), "")
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultDeadLettersLimit
+	}
+
+	if err := srv.store.ListNotificationDeadLetters(&query); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get the failed notifications")
+	}
+
+	result := apimodels.GettableNotificationDeadLetters{DeadLetters: make([]apimodels.GettableNotificationDeadLetter, 0, len(query.Result))}
+	for _, d := range query.Result {
+		result.DeadLetters = append(result.DeadLetters, apimodels.GettableNotificationDeadLetter{
+			Receiver:        d.Receiver,
+			IntegrationName: d.IntegrationName,
+			IntegrationType: d.IntegrationType,
+			Alerts:          d.Alerts,
+			Attempts:        d.Attempts,
+			Error:           d.Error,
+			Time:            d.Time().UTC(),
+		})
+	}
+	return response.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,250 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v3"
+)
+
+// ExportSrv exports and imports the alerting configuration of an organization, so that it can be
+// kept under version control.
+type ExportSrv struct {
+	ruler        RulerSrv
+	alertmanager AlertmanagerSrv
+	log          log.Logger
+}
+
+func (srv ExportSrv) RouteGetAlertingExport(c *models.ReqContext) response.Response {
+	if !c.HasUserRole(models.ROLE_EDITOR) {
+		return accessForbiddenResp()
+	}
+
+	namespaceMap, err := srv.ruler.store.GetNamespaces(c.OrgId, c.SignedInUser)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get namespaces visible to the user")
+	}
+
+	export := apimodels.AlertingExport{APIVersion: apimodels.AlertingExportAPIVersion}
+	if len(namespaceMap) > 0 {
+		namespaceUIDs := make([]string, 0, len(namespaceMap))
+		for uid := range namespaceMap {
+			namespaceUIDs = append(namespaceUIDs, uid)
+		}
+
+		q := ngmodels.ListAlertRulesQuery{
+			OrgID:         c.SignedInUser.OrgId,
+			NamespaceUIDs: namespaceUIDs,
+		}
+		if err := srv.ruler.store.GetOrgAlertRules(&q); err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to get alert rules")
+		}
+		export.Groups = toAlertingExportRuleGroups(q.Result, namespaceMap)
+	}
+
+	query := ngmodels.GetLatestAlertmanagerConfigurationQuery{OrgID: c.OrgId}
+	if err := srv.alertmanager.store.GetLatestAlertmanagerConfiguration(&query); err != nil {
+		if !errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+			return ErrResp(http.StatusInternalServerError, err, "failed to get latest configuration")
+		}
+	} else {
+		cfg, err := notifier.Load([]byte(query.Result.AlertmanagerConfiguration))
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to unmarshal alertmanager configuration")
+		}
+		// the secure settings are kept when the configuration is imported again
+		for _, gr := range cfg.GetGrafanaReceiverMap() {
+			gr.SecureSettings = nil
+		}
+		export.Alertmanager = cfg
+	}
+
+	yml, err := yaml.Marshal(export)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to marshal the alerting configuration")
+	}
+	return response.Respond(http.StatusOK, yml).SetHeader("Content-Type", "application/yaml")
+}
+
+func (srv ExportSrv) RoutePostAlertingImport(c *models.ReqContext) response.Response {
+	if !c.HasUserRole(models.ROLE_EDITOR) {
+		return accessForbiddenResp()
+	}
+
+	body, err := ioutil.ReadAll(c.Req.Request.Body)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to read the alerting configuration")
+	}
+
+	var export apimodels.AlertingExport
+	if err := yaml.Unmarshal(body, &export); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to parse the alerting configuration")
+	}
+
+	// validate everything before updating anything
+	cmds := make([]store.UpdateRuleGroupCmd, 0, len(export.Groups))
+	groups := make(map[string]struct{}, len(export.Groups))
+	for _, group := range export.Groups {
+		namespace, err := srv.ruler.store.GetNamespaceByTitle(group.Folder, c.SignedInUser.OrgId, c.SignedInUser, true)
+		if err != nil {
+			return toNamespaceErrorResponse(err)
+		}
+
+		if group.Name == "" {
+			return ErrResp(http.StatusBadRequest, errors.New("rule group name is not valid"), "folder %s", group.Folder)
+		}
+		key := fmt.Sprintf("%s/%s", namespace.Uid, group.Name)
+		if _, ok := groups[key]; ok {
+			return ErrResp(http.StatusBadRequest, errors.New("rule group is defined more than once"), "rule group %s of folder %s", group.Name, group.Folder)
+		}
+		groups[key] = struct{}{}
+
+		for _, r := range group.Rules {
+			if r.GrafanaManagedAlert == nil {
+				return ErrResp(http.StatusBadRequest, errors.New("only Grafana managed alert rules can be imported"), "rule group %s of folder %s", group.Name, group.Folder)
+			}
+			cond := ngmodels.Condition{
+				Condition: r.GrafanaManagedAlert.Condition,
+				OrgID:     c.SignedInUser.OrgId,
+				Data:      r.GrafanaManagedAlert.Data,
+			}
+			if err := validateCondition(cond, c.SignedInUser, c.SkipCache, srv.ruler.DatasourceCache); err != nil {
+				return ErrResp(http.StatusBadRequest, err, "failed to validate alert rule %s", r.GrafanaManagedAlert.Title)
+			}
+		}
+
+		cmds = append(cmds, store.UpdateRuleGroupCmd{
+			OrgID:           c.SignedInUser.OrgId,
+			NamespaceUID:    namespace.Uid,
+			RuleGroupConfig: group.RuleGroupConfig(),
+			KeepUIDs:        true,
+		})
+	}
+
+	usage := quotaUsage{}
+	for _, cmd := range cmds {
+		if err := addRuleGroupUsage(usage, srv.ruler.store, cmd.OrgID, cmd.NamespaceUID, cmd.RuleGroupConfig); err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to get rule group %s", cmd.RuleGroupConfig.Name)
+		}
+	}
+	if export.Alertmanager != nil {
+		if err := addContactPointUsage(usage, srv.alertmanager.store, c.OrgId, export.Alertmanager); err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to get latest configuration")
+		}
+	}
+	if resp := checkQuotas(c, srv.ruler.QuotaService, usage); resp != nil {
+		return resp
+	}
+
+	if export.Alertmanager != nil {
+		// contact points of another Grafana instance are created with their UID
+		if err := srv.alertmanager.loadSecureSettings(c.OrgId, export.Alertmanager.AlertmanagerConfig.Receivers, true); err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "")
+		}
+		if err := export.Alertmanager.ProcessConfig(); err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to post process Alertmanager configuration")
+		}
+	}
+
+	for _, cmd := range cmds {
+		if err := srv.ruler.store.UpdateRuleGroup(cmd); err != nil {
+			if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+				return ErrResp(http.StatusNotFound, err, "failed to update rule group %s", cmd.RuleGroupConfig.Name)
+			} else if errors.Is(err, ngmodels.ErrAlertRuleFailedValidation) || errors.Is(err, ngmodels.ErrAlertRuleUniqueConstraintViolation) {
+				return ErrResp(http.StatusBadRequest, err, "failed to update rule group %s", cmd.RuleGroupConfig.Name)
+			}
+			return ErrResp(http.StatusInternalServerError, err, "failed to update rule group %s", cmd.RuleGroupConfig.Name)
+		}
+
+		for _, r := range cmd.RuleGroupConfig.Rules {
+			srv.ruler.manager.RemoveByRuleUID(c.OrgId, r.GrafanaManagedAlert.UID)
+		}
+	}
+
+	if export.Alertmanager != nil {
+		if err := srv.alertmanager.am.SaveAndApplyConfig(c.OrgId, export.Alertmanager); err != nil {
+			srv.log.Error("unable to save and apply alertmanager configuration", "err", err)
+			return ErrResp(http.StatusBadRequest, err, "failed to save and apply Alertmanager configuration")
+		}
+	}
+
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": "alerting configuration imported"})
+}
+
+// toAlertingExportRuleGroups groups the rules by folder and rule group, sorted so that
+// exporting the same rules always gives the same document.
+func toAlertingExportRuleGroups(rules []*ngmodels.AlertRule, namespaceMap map[string]*models.Folder) []apimodels.AlertingExportRuleGroup {
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].ID < rules[j].ID
+	})
+
+	groups := make([]apimodels.AlertingExportRuleGroup, 0)
+	groupIndex := make(map[string]int)
+	for _, r := range rules {
+		folder, ok := namespaceMap[r.NamespaceUID]
+		if !ok {
+			continue
+		}
+		key := fmt.Sprintf("%s/%s", r.NamespaceUID, r.RuleGroup)
+		i, ok := groupIndex[key]
+		if !ok {
+			i = len(groups)
+			groupIndex[key] = i
+			groups = append(groups, apimodels.AlertingExportRuleGroup{
+				Folder:           folder.Title,
+				Name:             r.RuleGroup,
+				Interval:         model.Duration(time.Duration(r.IntervalSeconds) * time.Second),
+				EvaluationOffset: model.Duration(time.Duration(r.OffsetSeconds) * time.Second),
+			})
+		}
+		groups[i].Rules = append(groups[i].Rules, toPostableExtendedRuleNode(*r))
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Folder != groups[j].Folder {
+			return groups[i].Folder < groups[j].Folder
+		}
+		return groups[i].Name < groups[j].Name
+	})
+	return groups
+}
+
+func toPostableExtendedRuleNode(r ngmodels.AlertRule) apimodels.PostableExtendedRuleNode {
+	var isPaused *bool
+	if r.IsPaused {
+		isPaused = &r.IsPaused
+	}
+	return apimodels.PostableExtendedRuleNode{
+		ApiRuleNode: &apimodels.ApiRuleNode{
+			For:         model.Duration(r.For),
+			Annotations: r.Annotations,
+			Labels:      r.Labels,
+		},
+		GrafanaManagedAlert: &apimodels.PostableGrafanaRule{
+			Title:            r.Title,
+			Condition:        r.Condition,
+			Data:             r.Data,
+			UID:              r.UID,
+			NoDataState:      apimodels.NoDataState(r.NoDataState),
+			ExecErrState:     apimodels.ExecutionErrorState(r.ExecErrState),
+			IsPaused:         isPaused,
+			NoDataThreshold:  r.NoDataThreshold,
+			ExecErrThreshold: r.ExecErrThreshold,
+
+			DisableDashboardAnnotations: r.DisableDashboardAnnotations,
+		},
+	}
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// defaultStateHistoryLimit is the number of state transitions returned when no limit is given.
+const defaultStateHistoryLimit = 100
+
+// HistorySrv serves the recorded state transitions of the alert instances.
+type HistorySrv struct {
+	store     store.StateHistoryStore
+	ruleStore store.RuleStore
+	log       log.Logger
+}
+
+func (srv HistorySrv) RouteGetAlertStateHistory(c *models.ReqContext) response.Response {
+	query := ngmodels.ListAlertStateHistoryQuery{
+		OrgID:   c.SignedInUser.OrgId,
+		RuleUID: c.Query("ruleUID"),
+		State:   ngmodels.InstanceStateType(c.Query("state")),
+		From:    c.QueryInt64("from"),
+		To:      c.QueryInt64("to"),
+		Limit:   c.QueryInt("limit"),
+	}
+	if query.State != "" && !query.State.IsValid() {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid state %q", query.State), "")
+	}
+	if query.Limit < 0 || query.Limit > store.StateHistoryMaxLimit {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("limit must be between 1 and %d", store.StateHistoryMaxLimit), "")
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultStateHistoryLimit
+	}
+
+	history := apimodels.GettableAlertStateHistory{History: []apimodels.GettableAlertStateTransition{}}
+
+	namespaceMap, err := srv.ruleStore.GetNamespaces(c.OrgId, c.SignedInUser)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get namespaces visible to the user")
+	}
+	if len(namespaceMap) == 0 {
+		return response.JSON(http.StatusOK, history)
+	}
+	for uid := range namespaceMap {
+		query.NamespaceUIDs = append(query.NamespaceUIDs, uid)
+	}
+
+	if err := srv.store.ListAlertStateHistory(&query); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get the alert state history")
+	}
+
+	for _, e := range query.Result {
+		history.History = append(history.History, apimodels.GettableAlertStateTransition{
+			RuleUID:          e.RuleUID,
+			RuleTitle:        e.RuleTitle,
+			Labels:           e.Labels,
+			PreviousState:    string(e.PreviousState),
+			State:            string(e.CurrentState),
+			Values:           e.Values,
+			EvaluationString: e.EvaluationString,
+			Error:            e.Error,
+			Time:             e.Time().UTC(),
+		})
+	}
+	return response.JSON(http.StatusOK, history)
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/prometheus/alertmanager/config"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// InhibitionSrv manages the inhibition rules of the Grafana Alertmanager, leaving the rest of its
+// configuration unchanged.
+type InhibitionSrv struct {
+	store store.AlertingStore
+	am    Alertmanager
+	log   log.Logger
+}
+
+func (srv InhibitionSrv) RouteGetInhibitRules(c *models.ReqContext) response.Response {
+	if !c.HasUserRole(models.ROLE_EDITOR) {
+		return accessForbiddenResp()
+	}
+
+	cfg, errResp := getLatestConfig(srv.store, c.OrgId)
+	if errResp != nil {
+		return errResp
+	}
+
+	rules := apimodels.InhibitRules{InhibitRules: cfg.AlertmanagerConfig.InhibitRules}
+	if rules.InhibitRules == nil {
+		rules.InhibitRules = []*config.InhibitRule{}
+	}
+	return response.JSON(http.StatusOK, rules)
+}
+
+func (srv InhibitionSrv) RoutePutInhibitRules(c *models.ReqContext, body apimodels.InhibitRules) response.Response {
+	if !c.HasUserRole(models.ROLE_EDITOR) {
+		return accessForbiddenResp()
+	}
+
+	cfg, errResp := getLatestConfig(srv.store, c.OrgId)
+	if errResp != nil {
+		return errResp
+	}
+
+	updated := *cfg
+	updated.AlertmanagerConfig.InhibitRules = body.InhibitRules
+	cfg, err := reloadConfig(&updated)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid inhibition rules")
+	}
+
+	return saveAndApplyConfig(srv.am, srv.log, c.OrgId, cfg, "inhibition rules updated")
+}
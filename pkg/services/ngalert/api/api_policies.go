@@ -0,0 +1,181 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// PolicySrv manages the notification policy tree of the Grafana Alertmanager, leaving the rest of
+// its configuration unchanged.
+type PolicySrv struct {
+	store store.AlertingStore
+	am    Alertmanager
+	log   log.Logger
+}
+
+func (srv PolicySrv) RouteGetPolicyTree(c *models.ReqContext) response.Response {
+	if !c.HasUserRole(models.ROLE_EDITOR) {
+		return accessForbiddenResp()
+	}
+
+	cfg, errResp := getLatestConfig(srv.store, c.OrgId)
+	if errResp != nil {
+		return errResp
+	}
+
+	return response.JSON(http.StatusOK, cfg.AlertmanagerConfig.Route)
+}
+
+func (srv PolicySrv) RoutePutPolicyTree(c *models.ReqContext, tree apimodels.PolicyTree) response.Response {
+	if !c.HasUserRole(models.ROLE_EDITOR) {
+		return accessForbiddenResp()
+	}
+
+	cfg, errResp := getLatestConfig(srv.store, c.OrgId)
+	if errResp != nil {
+		return errResp
+	}
+
+	cfg, err := withPolicyTree(cfg, &tree)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid notification policy tree")
+	}
+
+	return saveAndApplyConfig(srv.am, srv.log, c.OrgId, cfg, "notification policies updated")
+}
+
+func (srv PolicySrv) RouteDeletePolicyTree(c *models.ReqContext) response.Response {
+	if !c.HasUserRole(models.ROLE_EDITOR) {
+		return accessForbiddenResp()
+	}
+
+	cfg, errResp := getLatestConfig(srv.store, c.OrgId)
+	if errResp != nil {
+		return errResp
+	}
+
+	tree := *cfg.AlertmanagerConfig.Route
+	tree.Routes = nil
+	cfg, err := withPolicyTree(cfg, &tree)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to delete the specific notification policies")
+	}
+
+	return saveAndApplyConfig(srv.am, srv.log, c.OrgId, cfg, "specific notification policies deleted")
+}
+
+func (srv PolicySrv) RouteTestPolicyTree(c *models.ReqContext, body apimodels.PolicyTestPayload) response.Response {
+	if !c.HasUserRole(models.ROLE_EDITOR) {
+		return accessForbiddenResp()
+	}
+
+	if err := body.Labels.Validate(); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid labels")
+	}
+
+	cfg, errResp := getLatestConfig(srv.store, c.OrgId)
+	if errResp != nil {
+		return errResp
+	}
+
+	if body.Tree != nil {
+		var err error
+		if cfg, err = withPolicyTree(cfg, body.Tree); err != nil {
+			return ErrResp(http.StatusBadRequest, err, "invalid notification policy tree")
+		}
+	}
+
+	return response.JSON(http.StatusOK, testPolicyTree(cfg.AlertmanagerConfig.Route, body.Labels))
+}
+
+// getLatestConfig returns the saved configuration of the Grafana Alertmanager of the organization,
+// or the response to send if it can't.
+func getLatestConfig(st store.AlertingStore, orgID int64) (*apimodels.PostableUserConfig, response.Response) {
+	query := ngmodels.GetLatestAlertmanagerConfigurationQuery{OrgID: orgID}
+	if err := st.GetLatestAlertmanagerConfiguration(&query); err != nil {
+		if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+			return nil, ErrResp(http.StatusNotFound, err, "")
+		}
+		return nil, ErrResp(http.StatusInternalServerError, err, "failed to get latest configuration")
+	}
+
+	cfg, err := notifier.Load([]byte(query.Result.AlertmanagerConfiguration))
+	if err != nil {
+		return nil, ErrResp(http.StatusInternalServerError, err, "failed to unmarshal alertmanager configuration")
+	}
+	return cfg, nil
+}
+
+// saveAndApplyConfig saves and applies a configuration of the Grafana Alertmanager that was
+// changed from the one returned by getLatestConfig.
+func saveAndApplyConfig(am Alertmanager, logger log.Logger, orgID int64, cfg *apimodels.PostableUserConfig, message string) response.Response {
+	// the secure settings of the saved configuration are already encrypted, so the configuration
+	// is saved without being processed again.
+	if err := am.SaveAndApplyConfig(orgID, cfg); err != nil {
+		logger.Error("unable to save and apply alertmanager configuration", "err", err)
+		return ErrResp(http.StatusBadRequest, err, "failed to save and apply Alertmanager configuration")
+	}
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": message})
+}
+
+// withPolicyTree returns the configuration with the given notification policy tree. The configuration
+// goes through the validation of the configurations posted to the Alertmanager API, so that the
+// policies only refer to the existing contact points and mute timings.
+func withPolicyTree(cfg *apimodels.PostableUserConfig, tree *apimodels.PolicyTree) (*apimodels.PostableUserConfig, error) {
+	updated := *cfg
+	updated.AlertmanagerConfig.Route = tree
+	return reloadConfig(&updated)
+}
+
+// reloadConfig returns a changed configuration as if it were loaded again, so that it goes through
+// the validation of the configurations posted to the Alertmanager API.
+func reloadConfig(cfg *apimodels.PostableUserConfig) (*apimodels.PostableUserConfig, error) {
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the configuration: %w", err)
+	}
+	return notifier.Load(b)
+}
+
+// testPolicyTree routes an alert with the given labels through the notification policy tree the
+// way the Alertmanager dispatcher does.
+func testPolicyTree(tree *apimodels.PolicyTree, labels model.LabelSet) apimodels.PolicyTestResult {
+	result := apimodels.PolicyTestResult{Matches: []apimodels.MatchingPolicy{}}
+	for _, r := range dispatch.NewRoute(tree, nil).Match(labels) {
+		// the alerts are grouped by all their labels regardless of the inherited labels
+		groupBy := []string{"..."}
+		if !r.RouteOpts.GroupByAll {
+			groupBy = make([]string, 0, len(r.RouteOpts.GroupBy))
+			for ln := range r.RouteOpts.GroupBy {
+				groupBy = append(groupBy, string(ln))
+			}
+			sort.Strings(groupBy)
+		}
+
+		result.Matches = append(result.Matches, apimodels.MatchingPolicy{
+			Path:              r.Key(),
+			Receiver:          r.RouteOpts.Receiver,
+			GroupBy:           groupBy,
+			GroupWait:         model.Duration(r.RouteOpts.GroupWait),
+			GroupInterval:     model.Duration(r.RouteOpts.GroupInterval),
+			RepeatInterval:    model.Duration(r.RouteOpts.RepeatInterval),
+			MuteTimeIntervals: r.RouteOpts.MuteTimeIntervals,
+		})
+	}
+	return result
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+)
+
+const policiesTestConfig = `{
+	"alertmanager_config": {
+		"route": {
+			"receiver": "default",
+			"group_by": ["alertname"],
+			"routes": [
+				{
+					"receiver": "pager",
+					"matchers": ["severity=\"critical\""],
+					"group_wait": "10s",
+					"continue": true,
+					"mute_time_intervals": ["weekends"]
+				},
+				{
+					"receiver": "ops",
+					"matchers": ["team=\"ops\""],
+					"group_by": ["..."]
+				}
+			]
+		},
+		"mute_time_intervals": [{"name": "weekends", "time_intervals": [{"weekdays": ["saturday", "sunday"]}]}],
+		"receivers": [
+			{"name": "default", "grafana_managed_receiver_configs": [{"uid": "default", "name": "default", "type": "email", "settings": {"addresses": "default@example.com"}}]},
+			{"name": "pager", "grafana_managed_receiver_configs": [{"uid": "pager", "name": "pager", "type": "email", "settings": {"addresses": "pager@example.com"}}]},
+			{"name": "ops", "grafana_managed_receiver_configs": [{"uid": "ops", "name": "ops", "type": "email", "settings": {"addresses": "ops@example.com"}}]}
+		]
+	}
+}`
+
+func TestTestPolicyTree(t *testing.T) {
+	cfg, err := notifier.Load([]byte(policiesTestConfig))
+	require.NoError(t, err)
+	tree := cfg.AlertmanagerConfig.Route
+
+	t.Run("alert matching no specific policy is routed by the root policy", func(t *testing.T) {
+		result := testPolicyTree(tree, model.LabelSet{"alertname": "cpu"})
+		require.Equal(t, []apimodels.MatchingPolicy{{
+			Path:           "{}",
+			Receiver:       "default",
+			GroupBy:        []string{"alertname"},
+			GroupWait:      model.Duration(30e9),
+			GroupInterval:  model.Duration(5 * 60e9),
+			RepeatInterval: model.Duration(4 * 3600e9),
+		}}, result.Matches)
+	})
+
+	t.Run("alert matching a policy that continues is routed by the following ones too", func(t *testing.T) {
+		result := testPolicyTree(tree, model.LabelSet{"alertname": "cpu", "severity": "critical", "team": "ops"})
+		require.Len(t, result.Matches, 2)
+
+		require.Equal(t, `{}/{severity="critical"}`, result.Matches[0].Path)
+		require.Equal(t, "pager", result.Matches[0].Receiver)
+		require.Equal(t, []string{"alertname"}, result.Matches[0].GroupBy)
+		require.Equal(t, model.Duration(10e9), result.Matches[0].GroupWait)
+		require.Equal(t, []string{"weekends"}, result.Matches[0].MuteTimeIntervals)
+
+		require.Equal(t, `{}/{team="ops"}`, result.Matches[1].Path)
+		require.Equal(t, "ops", result.Matches[1].Receiver)
+		require.Equal(t, []string{"..."}, result.Matches[1].GroupBy)
+		require.Equal(t, model.Duration(30e9), result.Matches[1].GroupWait)
+	})
+}
+
+func TestWithPolicyTree(t *testing.T) {
+	cfg, err := notifier.Load([]byte(policiesTestConfig))
+	require.NoError(t, err)
+
+	parseTree := func(t *testing.T, s string) *apimodels.PolicyTree {
+		t.Helper()
+		var tree apimodels.PolicyTree
+		require.NoError(t, json.Unmarshal([]byte(s), &tree))
+		return &tree
+	}
+
+	t.Run("valid tree replaces the one of the configuration", func(t *testing.T) {
+		updated, err := withPolicyTree(cfg, parseTree(t, `{"receiver": "ops", "routes": [{"receiver": "pager", "matchers": ["severity=\"critical\""]}]}`))
+		require.NoError(t, err)
+		require.Equal(t, "ops", updated.AlertmanagerConfig.Route.Receiver)
+		require.Len(t, updated.AlertmanagerConfig.Route.Routes, 1)
+		require.Len(t, updated.AlertmanagerConfig.Receivers, 3)
+		require.Len(t, updated.AlertmanagerConfig.MuteTimeIntervals, 1)
+
+		// the original configuration is unchanged
+		require.Equal(t, "default", cfg.AlertmanagerConfig.Route.Receiver)
+	})
+
+	for _, tc := range []struct {
+		desc string
+		tree string
+		err  string
+	}{
+		{
+			desc: "tree without default contact point",
+			tree: `{"routes": [{"receiver": "ops"}]}`,
+			err:  "root route must specify a default receiver",
+		},
+		{
+			desc: "root policy with matchers",
+			tree: `{"receiver": "default", "matchers": ["team=\"ops\""]}`,
+			err:  "root route must not have any matchers",
+		},
+		{
+			desc: "unknown contact point",
+			tree: `{"receiver": "default", "routes": [{"receiver": "unknown"}]}`,
+			err:  "unexpected receiver (unknown) is undefined",
+		},
+		{
+			desc: "unknown mute timing",
+			tree: `{"receiver": "default", "routes": [{"receiver": "ops", "mute_time_intervals": ["holidays"]}]}`,
+			err:  `undefined mute time interval "holidays" used in route`,
+		},
+		{
+			desc: "duplicate group by labels",
+			tree: `{"receiver": "default", "routes": [{"receiver": "ops", "group_by": ["team", "team"]}]}`,
+			err:  "duplicated label",
+		},
+	} {
+		t.Run(tc.desc+" should return error", func(t *testing.T) {
+			_, err := withPolicyTree(cfg, parseTree(t, tc.tree))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
@@ -40,13 +40,15 @@ func (srv PrometheusSrv) RouteGetAlertStatuses(c *models.ReqContext) response.Re
 		if len(alertState.Results) > 0 && alertState.State == eval.Alerting {
 			valString = alertState.Results[0].EvaluationString
 		}
-		alertResponse.Data.Alerts = append(alertResponse.Data.Alerts, &apimodels.Alert{
+		alert := &apimodels.Alert{
 			Labels:      map[string]string(alertState.Labels),
 			Annotations: map[string]string{}, //TODO: Once annotations are added to the evaluation result, set them here
 			State:       alertState.State.String(),
 			ActiveAt:    &startsAt,
 			Value:       valString,
-		})
+		}
+		setAcknowledgement(alert, alertState)
+		alertResponse.Data.Alerts = append(alertResponse.Data.Alerts, alert)
 	}
 	return response.JSON(http.StatusOK, alertResponse)
 }
@@ -141,6 +143,7 @@ func (srv PrometheusSrv) RouteGetRuleStatuses(c *models.ReqContext) response.Res
 					ActiveAt:    &activeAt,
 					Value:       valString, // TODO: set this once it is added to the evaluation results
 				}
+				setAcknowledgement(alert, alertState)
 
 				if alertState.LastEvaluationTime.After(newRule.LastEvaluation) {
 					newRule.LastEvaluation = alertState.LastEvaluationTime
@@ -178,3 +181,13 @@ func (srv PrometheusSrv) RouteGetRuleStatuses(c *models.ReqContext) response.Res
 	}
 	return response.JSON(http.StatusOK, ruleResponse)
 }
+
+// setAcknowledgement sets who acknowledged the firing alert of a state and when.
+func setAcknowledgement(alert *apimodels.Alert, alertState *state.State) {
+	if !alertState.IsAcknowledged() {
+		return
+	}
+	acknowledgedAt := alertState.AcknowledgedAt
+	alert.AcknowledgedBy = alertState.AcknowledgedBy
+	alert.AcknowledgedAt = &acknowledgedAt
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// RecordedQuerySrv manages the recorded queries, whose results are written as metrics.
+type RecordedQuerySrv struct {
+	store           store.RecordedQueryStore
+	DatasourceCache datasources.CacheService
+	log             log.Logger
+}
+
+func (srv RecordedQuerySrv) RouteGetRecordedQueries(c *models.ReqContext) response.Response {
+	if !c.HasUserRole(models.ROLE_EDITOR) {
+		return accessForbiddenResp()
+	}
+
+	q := ngmodels.ListRecordedQueriesQuery{OrgID: c.OrgId}
+	if err := srv.store.GetRecordedQueries(&q); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get recorded queries")
+	}
+
+	result := make(apimodels.GettableRecordedQueries, 0, len(q.Result))
+	for _, rq := range q.Result {
+		result = append(result, toGettableRecordedQuery(rq))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv RecordedQuerySrv) RouteGetRecordedQuery(c *models.ReqContext) response.Response {
+	if !c.HasUserRole(models.ROLE_EDITOR) {
+		return accessForbiddenResp()
+	}
+
+	q := ngmodels.GetRecordedQueryByUIDQuery{OrgID: c.OrgId, UID: c.Params(":UID")}
+	if err := srv.store.GetRecordedQueryByUID(&q); err != nil {
+		return toRecordedQueryErrorResponse(err, "failed to get recorded query")
+	}
+	return response.JSON(http.StatusOK, toGettableRecordedQuery(q.Result))
+}
+
+func (srv RecordedQuerySrv) RoutePostRecordedQuery(c *models.ReqContext, body apimodels.PostableRecordedQuery) response.Response {
+	return srv.saveRecordedQuery(c, "", body, http.StatusCreated)
+}
+
+func (srv RecordedQuerySrv) RoutePutRecordedQuery(c *models.ReqContext, body apimodels.PostableRecordedQuery) response.Response {
+	return srv.saveRecordedQuery(c, c.Params(":UID"), body, http.StatusOK)
+}
+
+func (srv RecordedQuerySrv) RouteDeleteRecordedQuery(c *models.ReqContext) response.Response {
+	if !c.HasUserRole(models.ROLE_EDITOR) {
+		return accessForbiddenResp()
+	}
+
+	if err := srv.store.DeleteRecordedQueryByUID(c.OrgId, c.Params(":UID")); err != nil {
+		return toRecordedQueryErrorResponse(err, "failed to delete recorded query")
+	}
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": "recorded query deleted"})
+}
+
+func (srv RecordedQuerySrv) saveRecordedQuery(c *models.ReqContext, uid string, body apimodels.PostableRecordedQuery, status int) response.Response {
+	if !c.HasUserRole(models.ROLE_EDITOR) {
+		return accessForbiddenResp()
+	}
+
+	// the target must be one of the queries or expressions, whose data sources the user can query
+	if err := validateCondition(ngmodels.Condition{Condition: body.Target, OrgID: c.OrgId, Data: body.Data}, c.SignedInUser, c.SkipCache, srv.DatasourceCache); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid recorded query")
+	}
+
+	rq := &ngmodels.RecordedQuery{
+		OrgID:           c.OrgId,
+		UID:             uid,
+		Name:            body.Name,
+		Description:     body.Description,
+		Data:            body.Data,
+		Target:          body.Target,
+		IntervalSeconds: int64(time.Duration(body.Interval).Seconds()),
+		Labels:          body.Labels,
+		Active:          body.Active,
+	}
+	if err := srv.store.SaveRecordedQuery(rq); err != nil {
+		return toRecordedQueryErrorResponse(err, "failed to save recorded query")
+	}
+	return response.JSON(status, toGettableRecordedQuery(rq))
+}
+
+func toRecordedQueryErrorResponse(err error, message string) response.Response {
+	switch {
+	case errors.Is(err, ngmodels.ErrRecordedQueryNotFound):
+		return ErrResp(http.StatusNotFound, err, "")
+	case errors.Is(err, ngmodels.ErrRecordedQueryFailedValidation):
+		return ErrResp(http.StatusBadRequest, err, "")
+	default:
+		return ErrResp(http.StatusInternalServerError, err, message)
+	}
+}
+
+func toGettableRecordedQuery(rq *ngmodels.RecordedQuery) apimodels.GettableRecordedQuery {
+	return apimodels.GettableRecordedQuery{
+		UID:         rq.UID,
+		Name:        rq.Name,
+		Description: rq.Description,
+		Data:        rq.Data,
+		Target:      rq.Target,
+		Interval:    model.Duration(time.Duration(rq.IntervalSeconds) * time.Second),
+		Labels:      rq.Labels,
+		Active:      rq.Active,
+		Updated:     rq.Updated,
+	}
+}
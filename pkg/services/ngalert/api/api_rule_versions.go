@@ -0,0 +1,246 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// RuleVersionsSrv serves the versions of the alert rules, which are saved each time a rule is saved,
+// so that the changes of a rule can be reviewed and reverted.
+type RuleVersionsSrv struct {
+	store   store.RuleStore
+	manager *state.Manager
+	log     log.Logger
+}
+
+func (srv RuleVersionsSrv) RouteGetRuleVersions(c *models.ReqContext) response.Response {
+	ruleUID := c.Params(":RuleUID")
+	if _, _, resp := srv.getRule(c, ruleUID); resp != nil {
+		return resp
+	}
+
+	query := ngmodels.ListAlertRuleVersionsQuery{
+		OrgID:   c.OrgId,
+		RuleUID: ruleUID,
+		Limit:   c.QueryInt("limit"),
+		Start:   c.QueryInt("start"),
+	}
+	if query.Limit < 0 || query.Start < 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("limit and start must not be negative"), "")
+	}
+	if err := srv.store.GetAlertRuleVersions(&query); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get the versions of the alert rule")
+	}
+
+	versions := make(apimodels.RuleVersions, 0, len(query.Result))
+	for _, v := range query.Result {
+		versions = append(versions, toRuleVersion(v))
+	}
+	return response.JSON(http.StatusOK, versions)
+}
+
+func (srv RuleVersionsSrv) RouteGetRuleVersion(c *models.ReqContext) response.Response {
+	ruleUID := c.Params(":RuleUID")
+	rule, folder, resp := srv.getRule(c, ruleUID)
+	if resp != nil {
+		return resp
+	}
+
+	versionID, err := strconv.ParseInt(c.Params(":Version"), 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid version %q", c.Params(":Version")), "")
+	}
+	v, resp := srv.getVersion(c, ruleUID, versionID)
+	if resp != nil {
+		return resp
+	}
+
+	version := toRuleVersion(v)
+	node := toGettableExtendedRuleNode(fromRuleVersion(rule, v), folder.Id)
+	version.Rule = &node
+	return response.JSON(http.StatusOK, version)
+}
+
+func (srv RuleVersionsSrv) RouteDiffRuleVersions(c *models.ReqContext, body apimodels.RuleVersionsDiffPayload) response.Response {
+	ruleUID := c.Params(":RuleUID")
+	rule, _, resp := srv.getRule(c, ruleUID)
+	if resp != nil {
+		return resp
+	}
+
+	var diffType dashdiffs.DiffType
+	switch body.DiffType {
+	case "", "basic":
+		diffType = dashdiffs.DiffBasic
+	case "json":
+		diffType = dashdiffs.DiffJSON
+	case "delta":
+		diffType = dashdiffs.DiffDelta
+	default:
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid diff type %q", body.DiffType), "")
+	}
+
+	base, resp := srv.getVersion(c, ruleUID, body.Base)
+	if resp != nil {
+		return resp
+	}
+	baseData, err := toDiffableRule(fromRuleVersion(rule, base))
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to encode the base version")
+	}
+	newVersion, resp := srv.getVersion(c, ruleUID, body.New)
+	if resp != nil {
+		return resp
+	}
+	newData, err := toDiffableRule(fromRuleVersion(rule, newVersion))
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to encode the new version")
+	}
+
+	result, err := dashdiffs.CalculateJSONDiff(baseData, newData, diffType)
+	if err != nil {
+		if errors.Is(err, dashdiffs.ErrNilDiff) {
+			return ErrResp(http.StatusBadRequest, errors.New("the versions are identical"), "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to compute the diff")
+	}
+
+	if diffType == dashdiffs.DiffDelta {
+		return response.Respond(http.StatusOK, result.Delta).SetHeader("Content-Type", "application/json")
+	}
+	return response.Respond(http.StatusOK, result.Delta).SetHeader("Content-Type", "text/html")
+}
+
+func (srv RuleVersionsSrv) RouteRestoreRuleVersion(c *models.ReqContext, body apimodels.RestoreRuleVersionPayload) response.Response {
+	ruleUID := c.Params(":RuleUID")
+	rule, folder, resp := srv.getRule(c, ruleUID)
+	if resp != nil {
+		return resp
+	}
+	if _, err := srv.store.GetNamespaceByTitle(folder.Title, c.OrgId, c.SignedInUser, true); err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+
+	v, resp := srv.getVersion(c, ruleUID, body.Version)
+	if resp != nil {
+		return resp
+	}
+	if v.Version == rule.Version {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("version %d is the current version of the alert rule", v.Version), "")
+	}
+
+	restored := fromRuleVersion(rule, v)
+	if err := srv.store.UpsertAlertRules([]store.UpsertRule{{Existing: rule, New: restored, RestoredFrom: v.Version}}); err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleFailedValidation) || errors.Is(err, ngmodels.ErrAlertRuleUniqueConstraintViolation) {
+			return ErrResp(http.StatusBadRequest, err, "failed to restore the alert rule")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to restore the alert rule")
+	}
+	srv.log.Info("alert rule restored", "orgId", c.OrgId, "uid", ruleUID, "version", v.Version)
+
+	srv.manager.RemoveByRuleUID(c.OrgId, ruleUID)
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": fmt.Sprintf("alert rule restored from version %d", v.Version)})
+}
+
+// getRule returns the alert rule and its folder, or an error response if the user can't
+// see the versions of the alert rule.
+func (srv RuleVersionsSrv) getRule(c *models.ReqContext, ruleUID string) (*ngmodels.AlertRule, *models.Folder, response.Response) {
+	if !c.HasUserRole(models.ROLE_EDITOR) {
+		return nil, nil, accessForbiddenResp()
+	}
+
+	q := ngmodels.GetAlertRuleByUIDQuery{OrgID: c.OrgId, UID: ruleUID}
+	if err := srv.store.GetAlertRuleByUID(&q); err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+			return nil, nil, ErrResp(http.StatusNotFound, err, "")
+		}
+		return nil, nil, ErrResp(http.StatusInternalServerError, err, "failed to get alert rule")
+	}
+	namespaceMap, err := srv.store.GetNamespaces(c.OrgId, c.SignedInUser)
+	if err != nil {
+		return nil, nil, ErrResp(http.StatusInternalServerError, err, "failed to get namespaces visible to the user")
+	}
+	folder, ok := namespaceMap[q.Result.NamespaceUID]
+	if !ok {
+		return nil, nil, ErrResp(http.StatusNotFound, ngmodels.ErrAlertRuleNotFound, "")
+	}
+	return q.Result, folder, nil
+}
+
+func (srv RuleVersionsSrv) getVersion(c *models.ReqContext, ruleUID string, version int64) (*ngmodels.AlertRuleVersion, response.Response) {
+	q := ngmodels.GetAlertRuleVersionQuery{OrgID: c.OrgId, RuleUID: ruleUID, Version: version}
+	if err := srv.store.GetAlertRuleVersion(&q); err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleVersionNotFound) {
+			return nil, ErrResp(http.StatusNotFound, fmt.Errorf("%w %d", err, version), "")
+		}
+		return nil, ErrResp(http.StatusInternalServerError, err, "failed to get alert rule version")
+	}
+	return q.Result, nil
+}
+
+func toRuleVersion(v *ngmodels.AlertRuleVersion) apimodels.RuleVersion {
+	// the messages are the same as the ones of the dashboard versions
+	message := ""
+	switch {
+	case v.RestoredFrom > 0:
+		message = fmt.Sprintf("Restored from version %d", v.RestoredFrom)
+	case v.ParentVersion == 0:
+		message = "Initial save"
+	}
+	return apimodels.RuleVersion{
+		Version:       v.Version,
+		ParentVersion: v.ParentVersion,
+		RestoredFrom:  v.RestoredFrom,
+		Created:       v.Created,
+		Message:       message,
+		Title:         v.Title,
+	}
+}
+
+// fromRuleVersion returns the alert rule with the definition it had at the version. The evaluation
+// interval and the paused state are kept, as they are shared by the rules of the group or changed
+// separately from the definition of the rule.
+func fromRuleVersion(rule *ngmodels.AlertRule, v *ngmodels.AlertRuleVersion) ngmodels.AlertRule {
+	r := *rule
+	r.Version = v.Version
+	r.Updated = v.Created
+	r.Title = v.Title
+	r.Condition = v.Condition
+	r.Data = v.Data
+	r.For = v.For
+	r.Annotations = v.Annotations
+	r.Labels = v.Labels
+	r.NoDataState = v.NoDataState
+	r.ExecErrState = v.ExecErrState
+	r.NoDataThreshold = v.NoDataThreshold
+	r.ExecErrThreshold = v.ExecErrThreshold
+	r.DisableDashboardAnnotations = v.DisableDashboardAnnotations
+	return r
+}
+
+// toDiffableRule returns the JSON of the alert rule without the fields that change with each version.
+func toDiffableRule(r ngmodels.AlertRule) (*simplejson.Json, error) {
+	r.ID = 0
+	r.Version = 0
+	r.Updated = time.Time{}
+	b, err := json.Marshal(toGettableExtendedRuleNode(r, 0))
+	if err != nil {
+		return nil, err
+	}
+	return simplejson.NewJson(b)
+}
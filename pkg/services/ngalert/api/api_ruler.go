@@ -92,8 +92,9 @@ func (srv RulerSrv) RouteGetNamespaceRulesConfig(c *models.ReqContext) response.
 		if !ok {
 			ruleGroupInterval := model.Duration(time.Duration(r.IntervalSeconds) * time.Second)
 			ruleGroupConfigs[r.RuleGroup] = apimodels.GettableRuleGroupConfig{
-				Name:     r.RuleGroup,
-				Interval: ruleGroupInterval,
+				Name:             r.RuleGroup,
+				Interval:         ruleGroupInterval,
+				EvaluationOffset: model.Duration(time.Duration(r.OffsetSeconds) * time.Second),
 				Rules: []apimodels.GettableExtendedRuleNode{
 					toGettableExtendedRuleNode(*r, namespace.Id),
 				},
@@ -128,18 +129,20 @@ func (srv RulerSrv) RouteGetRulegGroupConfig(c *models.ReqContext) response.Resp
 		return ErrResp(http.StatusInternalServerError, err, "failed to get group alert rules")
 	}
 
-	var ruleGroupInterval model.Duration
+	var ruleGroupInterval, ruleGroupOffset model.Duration
 	ruleNodes := make([]apimodels.GettableExtendedRuleNode, 0, len(q.Result))
 	for _, r := range q.Result {
 		ruleGroupInterval = model.Duration(time.Duration(r.IntervalSeconds) * time.Second)
+		ruleGroupOffset = model.Duration(time.Duration(r.OffsetSeconds) * time.Second)
 		ruleNodes = append(ruleNodes, toGettableExtendedRuleNode(*r, namespace.Id))
 	}
 
 	result := apimodels.RuleGroupConfigResponse{
 		GettableRuleGroupConfig: apimodels.GettableRuleGroupConfig{
-			Name:     ruleGroup,
-			Interval: ruleGroupInterval,
-			Rules:    ruleNodes,
+			Name:             ruleGroup,
+			Interval:         ruleGroupInterval,
+			EvaluationOffset: ruleGroupOffset,
+			Rules:            ruleNodes,
 		},
 	}
 	return response.JSON(http.StatusAccepted, result)
@@ -178,8 +181,9 @@ func (srv RulerSrv) RouteGetRulesConfig(c *models.ReqContext) response.Response
 			ruleGroupInterval := model.Duration(time.Duration(r.IntervalSeconds) * time.Second)
 			configs[namespace] = make(map[string]apimodels.GettableRuleGroupConfig)
 			configs[namespace][r.RuleGroup] = apimodels.GettableRuleGroupConfig{
-				Name:     r.RuleGroup,
-				Interval: ruleGroupInterval,
+				Name:             r.RuleGroup,
+				Interval:         ruleGroupInterval,
+				EvaluationOffset: model.Duration(time.Duration(r.OffsetSeconds) * time.Second),
 				Rules: []apimodels.GettableExtendedRuleNode{
 					toGettableExtendedRuleNode(*r, folder.Id),
 				},
@@ -189,8 +193,9 @@ func (srv RulerSrv) RouteGetRulesConfig(c *models.ReqContext) response.Response
 			if !ok {
 				ruleGroupInterval := model.Duration(time.Duration(r.IntervalSeconds) * time.Second)
 				configs[namespace][r.RuleGroup] = apimodels.GettableRuleGroupConfig{
-					Name:     r.RuleGroup,
-					Interval: ruleGroupInterval,
+					Name:             r.RuleGroup,
+					Interval:         ruleGroupInterval,
+					EvaluationOffset: model.Duration(time.Duration(r.OffsetSeconds) * time.Second),
 					Rules: []apimodels.GettableExtendedRuleNode{
 						toGettableExtendedRuleNode(*r, folder.Id),
 					},
@@ -218,18 +223,6 @@ func (srv RulerSrv) RoutePostNameRulesConfig(c *models.ReqContext, ruleGroupConf
 		return toNamespaceErrorResponse(err)
 	}
 
-	// quotas are checked in advanced
-	// that is acceptable under the assumption that there will be only one alert rule under the rule group
-	// alternatively we should check the quotas after the rule group update
-	// and rollback the transaction in case of violation
-	limitReached, err := srv.QuotaService.QuotaReached(c, "alert_rule")
-	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "failed to get quota")
-	}
-	if limitReached {
-		return ErrResp(http.StatusForbidden, errors.New("quota reached"), "")
-	}
-
 	// TODO validate UID uniqueness in the payload
 
 	//TODO: Should this belong in alerting-api?
@@ -237,6 +230,17 @@ func (srv RulerSrv) RoutePostNameRulesConfig(c *models.ReqContext, ruleGroupConf
 		return ErrResp(http.StatusBadRequest, errors.New("rule group name is not valid"), "")
 	}
 
+	// quotas are checked in advance with the rules that the update adds to the rule group
+	// alternatively we should check the quotas after the rule group update
+	// and rollback the transaction in case of violation
+	usage := quotaUsage{}
+	if err := addRuleGroupUsage(usage, srv.store, c.SignedInUser.OrgId, namespace.Uid, ruleGroupConfig); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get rule group")
+	}
+	if resp := checkQuotas(c, srv.QuotaService, usage); resp != nil {
+		return resp
+	}
+
 	var alertRuleUIDs []string
 	for _, r := range ruleGroupConfig.Rules {
 		cond := ngmodels.Condition{
@@ -273,20 +277,25 @@ func (srv RulerSrv) RoutePostNameRulesConfig(c *models.ReqContext, ruleGroupConf
 func toGettableExtendedRuleNode(r ngmodels.AlertRule, namespaceID int64) apimodels.GettableExtendedRuleNode {
 	gettableExtendedRuleNode := apimodels.GettableExtendedRuleNode{
 		GrafanaManagedAlert: &apimodels.GettableGrafanaRule{
-			ID:              r.ID,
-			OrgID:           r.OrgID,
-			Title:           r.Title,
-			Condition:       r.Condition,
-			Data:            r.Data,
-			Updated:         r.Updated,
-			IntervalSeconds: r.IntervalSeconds,
-			Version:         r.Version,
-			UID:             r.UID,
-			NamespaceUID:    r.NamespaceUID,
-			NamespaceID:     namespaceID,
-			RuleGroup:       r.RuleGroup,
-			NoDataState:     apimodels.NoDataState(r.NoDataState),
-			ExecErrState:    apimodels.ExecutionErrorState(r.ExecErrState),
+			ID:               r.ID,
+			OrgID:            r.OrgID,
+			Title:            r.Title,
+			Condition:        r.Condition,
+			Data:             r.Data,
+			Updated:          r.Updated,
+			IntervalSeconds:  r.IntervalSeconds,
+			Version:          r.Version,
+			UID:              r.UID,
+			NamespaceUID:     r.NamespaceUID,
+			NamespaceID:      namespaceID,
+			RuleGroup:        r.RuleGroup,
+			NoDataState:      apimodels.NoDataState(r.NoDataState),
+			ExecErrState:     apimodels.ExecutionErrorState(r.ExecErrState),
+			IsPaused:         r.IsPaused,
+			NoDataThreshold:  r.NoDataThreshold,
+			ExecErrThreshold: r.ExecErrThreshold,
+
+			DisableDashboardAnnotations: r.DisableDashboardAnnotations,
 		},
 	}
 	gettableExtendedRuleNode.ApiRuleNode = &apimodels.ApiRuleNode{
@@ -0,0 +1,237 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// RulesSrv changes many Grafana managed alert rules at once, selecting them by folder, labels or
+// data source.
+type RulesSrv struct {
+	store   store.RuleStore
+	manager *state.Manager
+	log     log.Logger
+}
+
+func (srv RulesSrv) RoutePauseRules(c *models.ReqContext, body apimodels.BulkRulesPayload) response.Response {
+	return srv.updateRules(c, body.Selector, func(r *ngmodels.AlertRule) bool {
+		if r.IsPaused {
+			return false
+		}
+		r.IsPaused = true
+		return true
+	}, nil)
+}
+
+func (srv RulesSrv) RouteResumeRules(c *models.ReqContext, body apimodels.BulkRulesPayload) response.Response {
+	return srv.updateRules(c, body.Selector, func(r *ngmodels.AlertRule) bool {
+		if !r.IsPaused {
+			return false
+		}
+		r.IsPaused = false
+		return true
+	}, nil)
+}
+
+func (srv RulesSrv) RouteEditRules(c *models.ReqContext, body apimodels.BulkEditRulesPayload) response.Response {
+	if body.Interval == 0 && len(body.Labels) == 0 && len(body.RemoveLabels) == 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("no interval or labels to change"), "")
+	}
+	for name := range body.Labels {
+		if !model.LabelName(name).IsValid() {
+			return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid label name %q", name), "")
+		}
+	}
+
+	intervalSeconds := int64(time.Duration(body.Interval).Seconds())
+	setInterval := func(r *ngmodels.AlertRule) bool {
+		if body.Interval == 0 || r.IntervalSeconds == intervalSeconds {
+			return false
+		}
+		r.IntervalSeconds = intervalSeconds
+		return true
+	}
+	return srv.updateRules(c, body.Selector, func(r *ngmodels.AlertRule) bool {
+		changed := setInterval(r)
+		for name, value := range body.Labels {
+			if current, ok := r.Labels[name]; ok && current == value {
+				continue
+			}
+			if r.Labels == nil {
+				r.Labels = make(map[string]string, len(body.Labels))
+			}
+			r.Labels[name] = value
+			changed = true
+		}
+		for _, name := range body.RemoveLabels {
+			if _, ok := r.Labels[name]; ok {
+				delete(r.Labels, name)
+				changed = true
+			}
+		}
+		return changed
+	}, setInterval)
+}
+
+// updateRules applies the update to the rules that match the selector, and saves the ones that
+// it changed. The group update, if any, is applied to the other rules of their rule groups.
+func (srv RulesSrv) updateRules(c *models.ReqContext, selector apimodels.RuleSelector, update, groupUpdate func(*ngmodels.AlertRule) bool) response.Response {
+	if !c.HasUserRole(models.ROLE_EDITOR) {
+		return accessForbiddenResp()
+	}
+
+	matchRule, err := newRuleMatcher(selector)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid selector")
+	}
+
+	// only the rules of the folders that the user can see are selected
+	namespaces, err := srv.store.GetNamespaces(c.OrgId, c.SignedInUser)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get namespaces visible to the user")
+	}
+	q := ngmodels.ListAlertRulesQuery{OrgID: c.OrgId}
+	for uid := range namespaces {
+		q.NamespaceUIDs = append(q.NamespaceUIDs, uid)
+	}
+	if len(q.NamespaceUIDs) == 0 {
+		return response.JSON(http.StatusOK, apimodels.BulkRulesResult{UIDs: []string{}})
+	}
+	if err := srv.store.GetOrgAlertRules(&q); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get alert rules")
+	}
+
+	type ruleGroupKey struct {
+		namespaceUID string
+		ruleGroup    string
+	}
+	var (
+		changed       = make(map[string]*ngmodels.AlertRule)
+		selected      = make(map[string]struct{})
+		selectedGroup = make(map[ruleGroupKey]struct{})
+	)
+	for _, r := range q.Result {
+		if !matchRule(r) {
+			continue
+		}
+		selected[r.UID] = struct{}{}
+		selectedGroup[ruleGroupKey{r.NamespaceUID, r.RuleGroup}] = struct{}{}
+		if update(r) {
+			changed[r.UID] = r
+		}
+	}
+	if groupUpdate != nil {
+		for _, r := range q.Result {
+			if _, ok := selectedGroup[ruleGroupKey{r.NamespaceUID, r.RuleGroup}]; !ok {
+				continue
+			}
+			if _, ok := selected[r.UID]; ok {
+				continue
+			}
+			if groupUpdate(r) {
+				changed[r.UID] = r
+			}
+		}
+	}
+
+	// the user must be able to edit all the folders of the changed rules
+	checked := make(map[string]struct{})
+	for _, r := range changed {
+		if _, ok := checked[r.NamespaceUID]; ok {
+			continue
+		}
+		if _, err := srv.store.GetNamespaceByTitle(namespaces[r.NamespaceUID].Title, c.OrgId, c.SignedInUser, true); err != nil {
+			return toNamespaceErrorResponse(err)
+		}
+		checked[r.NamespaceUID] = struct{}{}
+	}
+
+	uids := make([]string, 0, len(changed))
+	upsertRules := make([]store.UpsertRule, 0, len(changed))
+	for _, r := range q.Result {
+		if _, ok := changed[r.UID]; !ok {
+			continue
+		}
+		uids = append(uids, r.UID)
+		upsertRules = append(upsertRules, store.UpsertRule{Existing: r, New: *r})
+	}
+	if len(upsertRules) == 0 {
+		return response.JSON(http.StatusOK, apimodels.BulkRulesResult{UIDs: uids})
+	}
+
+	if err := srv.store.UpsertAlertRules(upsertRules); err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleFailedValidation) {
+			return ErrResp(http.StatusBadRequest, err, "failed to update the alert rules")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to update the alert rules")
+	}
+	srv.log.Info("alert rules updated in bulk", "orgId", c.OrgId, "count", len(uids))
+
+	for _, uid := range uids {
+		srv.manager.RemoveByRuleUID(c.OrgId, uid)
+	}
+	return response.JSON(http.StatusOK, apimodels.BulkRulesResult{UIDs: uids})
+}
+
+// newRuleMatcher returns a function that tells whether an alert rule matches all the criteria of the selector.
+func newRuleMatcher(selector apimodels.RuleSelector) (func(*ngmodels.AlertRule) bool, error) {
+	if len(selector.FolderUIDs) == 0 && len(selector.Matchers) == 0 && len(selector.DatasourceUIDs) == 0 {
+		return nil, errors.New("at least one folder, matcher or data source is required")
+	}
+
+	folders := make(map[string]struct{}, len(selector.FolderUIDs))
+	for _, uid := range selector.FolderUIDs {
+		folders[uid] = struct{}{}
+	}
+	datasources := make(map[string]struct{}, len(selector.DatasourceUIDs))
+	for _, uid := range selector.DatasourceUIDs {
+		datasources[uid] = struct{}{}
+	}
+	matchers := make([]*labels.Matcher, 0, len(selector.Matchers))
+	for _, s := range selector.Matchers {
+		m, err := labels.ParseMatcher(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid matcher %q: %w", s, err)
+		}
+		matchers = append(matchers, m)
+	}
+
+	return func(r *ngmodels.AlertRule) bool {
+		if len(folders) > 0 {
+			if _, ok := folders[r.NamespaceUID]; !ok {
+				return false
+			}
+		}
+		for _, m := range matchers {
+			if !m.Matches(r.Labels[m.Name]) {
+				return false
+			}
+		}
+		if len(datasources) > 0 {
+			found := false
+			for _, q := range r.Data {
+				if _, ok := datasources[q.DatasourceUID]; ok {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// TemplateSrv manages the notification templates of the Grafana Alertmanager, leaving the rest of
+// its configuration unchanged.
+type TemplateSrv struct {
+	store store.AlertingStore
+	am    Alertmanager
+	log   log.Logger
+}
+
+func (srv TemplateSrv) RouteGetTemplates(c *models.ReqContext) response.Response {
+	if !c.HasUserRole(models.ROLE_EDITOR) {
+		return accessForbiddenResp()
+	}
+
+	cfg, errResp := getLatestConfig(srv.store, c.OrgId)
+	if errResp != nil {
+		return errResp
+	}
+
+	templates := make(apimodels.NotificationTemplates, 0, len(cfg.TemplateFiles))
+	for name, content := range cfg.TemplateFiles {
+		templates = append(templates, apimodels.NotificationTemplate{Name: name, Template: content})
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return response.JSON(http.StatusOK, templates)
+}
+
+func (srv TemplateSrv) RouteGetTemplate(c *models.ReqContext) response.Response {
+	if !c.HasUserRole(models.ROLE_EDITOR) {
+		return accessForbiddenResp()
+	}
+
+	cfg, errResp := getLatestConfig(srv.store, c.OrgId)
+	if errResp != nil {
+		return errResp
+	}
+
+	name := c.Params(":Name")
+	content, ok := cfg.TemplateFiles[name]
+	if !ok {
+		return ErrResp(http.StatusNotFound, fmt.Errorf("template %q not found", name), "")
+	}
+	return response.JSON(http.StatusOK, apimodels.NotificationTemplate{Name: name, Template: content})
+}
+
+func (srv TemplateSrv) RoutePutTemplate(c *models.ReqContext, body apimodels.NotificationTemplateContent) response.Response {
+	if !c.HasUserRole(models.ROLE_EDITOR) {
+		return accessForbiddenResp()
+	}
+
+	name := c.Params(":Name")
+	if err := validateTemplateName(name); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if err := notifier.ValidateTemplate(name, body.Template); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	cfg, errResp := getLatestConfig(srv.store, c.OrgId)
+	if errResp != nil {
+		return errResp
+	}
+
+	if cfg.TemplateFiles == nil {
+		cfg.TemplateFiles = map[string]string{}
+	}
+	cfg.TemplateFiles[name] = body.Template
+	cfg.AlertmanagerConfig.Templates = templatePaths(cfg.TemplateFiles)
+
+	return saveAndApplyConfig(srv.am, srv.log, c.OrgId, cfg, "template saved")
+}
+
+func (srv TemplateSrv) RouteDeleteTemplate(c *models.ReqContext) response.Response {
+	if !c.HasUserRole(models.ROLE_EDITOR) {
+		return accessForbiddenResp()
+	}
+
+	cfg, errResp := getLatestConfig(srv.store, c.OrgId)
+	if errResp != nil {
+		return errResp
+	}
+
+	name := c.Params(":Name")
+	if _, ok := cfg.TemplateFiles[name]; !ok {
+		return ErrResp(http.StatusNotFound, fmt.Errorf("template %q not found", name), "")
+	}
+	delete(cfg.TemplateFiles, name)
+	cfg.AlertmanagerConfig.Templates = templatePaths(cfg.TemplateFiles)
+
+	return saveAndApplyConfig(srv.am, srv.log, c.OrgId, cfg, "template deleted")
+}
+
+func (srv TemplateSrv) RouteTestTemplate(c *models.ReqContext, body apimodels.TestTemplatePayload) response.Response {
+	if !c.HasUserRole(models.ROLE_EDITOR) {
+		return accessForbiddenResp()
+	}
+
+	text, err := srv.am.TestTemplate(c.Req.Context(), body)
+	if err != nil {
+		var invalidTemplateErr notifier.InvalidTemplateError
+		if errors.As(err, &invalidTemplateErr) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to render the template")
+	}
+
+	return response.JSON(http.StatusOK, apimodels.TestTemplateResult{Text: text})
+}
+
+// validateTemplateName checks that the name of a template can be used as the name of its file in
+// the working directory of the Alertmanager.
+func validateTemplateName(name string) error {
+	if name == "" || name != filepath.Base(filepath.Clean(name)) {
+		return fmt.Errorf("template name %q is not valid", name)
+	}
+	// the names of the default templates of Grafana start with __
+	if strings.HasPrefix(name, "__") {
+		return fmt.Errorf("template name %q is reserved", name)
+	}
+	return nil
+}
+
+// templatePaths returns the templates of the Alertmanager configuration for the given template files.
+func templatePaths(templateFiles map[string]string) []string {
+	paths := make([]string, 0, len(templateFiles))
+	for name := range templateFiles {
+		paths = append(paths, name)
+	}
+	sort.Strings(paths)
+	return paths
+}
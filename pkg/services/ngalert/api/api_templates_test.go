@@ -0,0 +1,22 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTemplateName(t *testing.T) {
+	require.NoError(t, validateTemplateName("custom.tmpl"))
+	require.NoError(t, validateTemplateName("slack"))
+
+	require.EqualError(t, validateTemplateName(""), `template name "" is not valid`)
+	require.EqualError(t, validateTemplateName("../custom.tmpl"), `template name "../custom.tmpl" is not valid`)
+	require.EqualError(t, validateTemplateName("dir/custom.tmpl"), `template name "dir/custom.tmpl" is not valid`)
+	require.EqualError(t, validateTemplateName("__default__.tmpl"), `template name "__default__.tmpl" is reserved`)
+}
+
+func TestTemplatePaths(t *testing.T) {
+	require.Equal(t, []string{"a.tmpl", "b.tmpl"}, templatePaths(map[string]string{"b.tmpl": "", "a.tmpl": ""}))
+	require.Empty(t, templatePaths(nil))
+}
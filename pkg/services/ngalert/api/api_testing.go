@@ -13,6 +13,8 @@ import (
 	"github.com/grafana/grafana/pkg/services/datasources"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/tsdb"
 	"github.com/grafana/grafana/pkg/util"
@@ -98,3 +100,73 @@ func (srv TestingApiSrv) RouteEvalQueries(c *models.ReqContext, cmd apimodels.Ev
 
 	return response.JSONStreaming(http.StatusOK, evalResults)
 }
+
+func (srv TestingApiSrv) RouteTestAlertRule(c *models.ReqContext, body apimodels.TestAlertRulePayload) response.Response {
+	rule := body.Rule.GrafanaManagedAlert
+	if rule == nil {
+		return ErrResp(http.StatusBadRequest, errors.New("only Grafana managed alert rules can be tested"), "")
+	}
+
+	alertRule := &ngmodels.AlertRule{
+		OrgID:        c.SignedInUser.OrgId,
+		Title:        rule.Title,
+		Condition:    rule.Condition,
+		Data:         rule.Data,
+		UID:          rule.UID,
+		NamespaceUID: body.NamespaceUID,
+		NoDataState:  ngmodels.NoDataState(rule.NoDataState),
+		ExecErrState: ngmodels.ExecutionErrorState(rule.ExecErrState),
+	}
+	if body.Rule.ApiRuleNode != nil {
+		alertRule.Labels = body.Rule.ApiRuleNode.Labels
+		alertRule.Annotations = body.Rule.ApiRuleNode.Annotations
+	}
+
+	condition := ngmodels.Condition{
+		Condition: alertRule.Condition,
+		OrgID:     alertRule.OrgID,
+		Data:      alertRule.Data,
+	}
+	if err := validateCondition(condition, c.SignedInUser, c.SkipCache, srv.DatasourceCache); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid condition")
+	}
+
+	now := body.Now
+	if now.IsZero() {
+		now = timeNow()
+	}
+
+	evaluator := eval.Evaluator{Cfg: srv.Cfg, Log: srv.log}
+	results, err := evaluator.ConditionEval(&condition, now, srv.DataService)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to evaluate the alert rule")
+	}
+
+	return response.JSON(http.StatusOK, testAlertRuleResponse(srv.log, alertRule, results))
+}
+
+// testAlertRuleResponse returns the results of the evaluation of the alert rule with the labels and
+// annotations of their alerts, the way the state manager builds them.
+func testAlertRuleResponse(logger log.Logger, alertRule *ngmodels.AlertRule, results eval.Results) apimodels.TestAlertRuleResponse {
+	res := apimodels.TestAlertRuleResponse{Instances: make([]apimodels.TestAlertRuleInstance, 0, len(results))}
+	for _, result := range results {
+		labels, annotations := state.InstanceLabelsAndAnnotations(logger, alertRule, result)
+		instance := apimodels.TestAlertRuleInstance{
+			State:            result.State.String(),
+			Labels:           labels,
+			Annotations:      annotations,
+			EvaluationString: result.EvaluationString,
+		}
+		if len(result.Values) > 0 {
+			instance.Values = make(map[string]*float64, len(result.Values))
+			for refID, v := range result.Values {
+				instance.Values[refID] = v.Value
+			}
+		}
+		if result.Error != nil {
+			instance.Error = result.Error.Error()
+		}
+		res.Instances = append(res.Instances, instance)
+	}
+	return res
+}
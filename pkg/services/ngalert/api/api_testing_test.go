@@ -0,0 +1,68 @@
+package api
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestTestAlertRuleResponse(t *testing.T) {
+	rule := &ngmodels.AlertRule{
+		UID:          "disk-full",
+		Title:        "Disk full",
+		NamespaceUID: "folder",
+		Labels:       map[string]string{"severity": "critical"},
+		Annotations:  map[string]string{"summary": "{{ $labels.instance }} is {{ $values.B }}% full"},
+	}
+	value := 95.5
+
+	res := testAlertRuleResponse(log.New("test"), rule, eval.Results{
+		{
+			Instance:         data.Labels{"instance": "db-1"},
+			State:            eval.Alerting,
+			EvaluationString: "[ var='B' labels={instance=db-1} value=95.5 ]",
+			Values:           map[string]eval.NumberValueCapture{"B": {Var: "B", Labels: data.Labels{"instance": "db-1"}, Value: &value}},
+		},
+		{
+			Instance: data.Labels{"instance": "db-2"},
+			State:    eval.Error,
+			Error:    errors.New("query timed out"),
+		},
+	})
+
+	require.Equal(t, apimodels.TestAlertRuleResponse{Instances: []apimodels.TestAlertRuleInstance{
+		{
+			State: "Alerting",
+			Labels: map[string]string{
+				"__alert_rule_uid__":           "disk-full",
+				"__alert_rule_namespace_uid__": "folder",
+				"alertname":                    "Disk full",
+				"instance":                     "db-1",
+				"severity":                     "critical",
+			},
+			Annotations:      map[string]string{"summary": "db-1 is 95.5% full"},
+			EvaluationString: "[ var='B' labels={instance=db-1} value=95.5 ]",
+			Values:           map[string]*float64{"B": &value},
+		},
+		{
+			State: "Error",
+			Labels: map[string]string{
+				"__alert_rule_uid__":           "disk-full",
+				"__alert_rule_namespace_uid__": "folder",
+				"alertname":                    "Disk full",
+				"instance":                     "db-2",
+				"severity":                     "critical",
+			},
+			// the template of the annotation is kept when it can't be expanded
+			Annotations: map[string]string{"summary": "{{ $labels.instance }} is {{ $values.B }}% full"},
+			Error:       "query timed out",
+		},
+	}}, res)
+}
@@ -0,0 +1,50 @@
+/*Package api contains base API implementation of unified alerting
+ *
+ *Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ *
+ *Do not manually edit these files, please find ngalert/api/swagger-codegen/ for commands on how to generate them.
+ */
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-macaron/binding"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+)
+
+type AcknowledgementsApiService interface {
+	RouteAcknowledgeAlerts(*models.ReqContext, apimodels.AcknowledgePayload) response.Response
+	RouteUnacknowledgeAlerts(*models.ReqContext, apimodels.AcknowledgePayload) response.Response
+}
+
+func (api *API) RegisterAcknowledgementsApiEndpoints(srv AcknowledgementsApiService, m *metrics.Metrics) {
+	api.RouteRegister.Group("", func(group routing.RouteRegister) {
+		group.Post(
+			toMacaronPath("/api/v1/ngalert/alerts/acknowledge"),
+			binding.Bind(apimodels.AcknowledgePayload{}),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/ngalert/alerts/acknowledge",
+				srv.RouteAcknowledgeAlerts,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/ngalert/alerts/unacknowledge"),
+			binding.Bind(apimodels.AcknowledgePayload{}),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/ngalert/alerts/unacknowledge",
+				srv.RouteUnacknowledgeAlerts,
+				m,
+			),
+		)
+	}, middleware.ReqSignedIn)
+}
@@ -23,6 +23,7 @@ type ConfigurationApiService interface {
 	RouteDeleteNGalertConfig(*models.ReqContext) response.Response
 	RouteGetAlertmanagers(*models.ReqContext) response.Response
 	RouteGetNGalertConfig(*models.ReqContext) response.Response
+	RoutePostLegacyAlertingMigration(*models.ReqContext, apimodels.PostableLegacyAlertingMigration) response.Response
 	RoutePostNGalertConfig(*models.ReqContext, apimodels.PostableNGalertConfig) response.Response
 }
 
@@ -65,5 +66,15 @@ func (api *API) RegisterConfigurationApiEndpoints(srv ConfigurationApiService, m
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/ngalert/migrate"),
+			binding.Bind(apimodels.PostableLegacyAlertingMigration{}),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/ngalert/migrate",
+				srv.RoutePostLegacyAlertingMigration,
+				m,
+			),
+		)
 	}, middleware.ReqSignedIn)
 }
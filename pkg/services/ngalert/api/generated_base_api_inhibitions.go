@@ -0,0 +1,49 @@
+/*Package api contains base API implementation of unified alerting
+ *
+ *Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ *
+ *Do not manually edit these files, please find ngalert/api/swagger-codegen/ for commands on how to generate them.
+ */
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-macaron/binding"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+)
+
+type InhibitionsApiService interface {
+	RouteGetInhibitRules(*models.ReqContext) response.Response
+	RoutePutInhibitRules(*models.ReqContext, apimodels.InhibitRules) response.Response
+}
+
+func (api *API) RegisterInhibitionsApiEndpoints(srv InhibitionsApiService, m *metrics.Metrics) {
+	api.RouteRegister.Group("", func(group routing.RouteRegister) {
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/inhibit-rules"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/inhibit-rules",
+				srv.RouteGetInhibitRules,
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/ngalert/inhibit-rules"),
+			binding.Bind(apimodels.InhibitRules{}),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/ngalert/inhibit-rules",
+				srv.RoutePutInhibitRules,
+				m,
+			),
+		)
+	}, middleware.ReqSignedIn)
+}
@@ -0,0 +1,70 @@
+/*Package api contains base API implementation of unified alerting
+ *
+ *Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ *
+ *Do not manually edit these files, please find ngalert/api/swagger-codegen/ for commands on how to generate them.
+ */
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-macaron/binding"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+)
+
+type PoliciesApiService interface {
+	RouteDeletePolicyTree(*models.ReqContext) response.Response
+	RouteGetPolicyTree(*models.ReqContext) response.Response
+	RoutePutPolicyTree(*models.ReqContext, apimodels.PolicyTree) response.Response
+	RouteTestPolicyTree(*models.ReqContext, apimodels.PolicyTestPayload) response.Response
+}
+
+func (api *API) RegisterPoliciesApiEndpoints(srv PoliciesApiService, m *metrics.Metrics) {
+	api.RouteRegister.Group("", func(group routing.RouteRegister) {
+		group.Delete(
+			toMacaronPath("/api/v1/ngalert/policies"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/ngalert/policies",
+				srv.RouteDeletePolicyTree,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/policies"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/policies",
+				srv.RouteGetPolicyTree,
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/ngalert/policies"),
+			binding.Bind(apimodels.PolicyTree{}),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/ngalert/policies",
+				srv.RoutePutPolicyTree,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/ngalert/policies/test"),
+			binding.Bind(apimodels.PolicyTestPayload{}),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/ngalert/policies/test",
+				srv.RouteTestPolicyTree,
+				m,
+			),
+		)
+	}, middleware.ReqSignedIn)
+}
@@ -0,0 +1,80 @@
+/*Package api contains base API implementation of unified alerting
+ *
+ *Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ *
+ *Do not manually edit these files, please find ngalert/api/swagger-codegen/ for commands on how to generate them.
+ */
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-macaron/binding"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+)
+
+type RecordedQueriesApiService interface {
+	RouteDeleteRecordedQuery(*models.ReqContext) response.Response
+	RouteGetRecordedQueries(*models.ReqContext) response.Response
+	RouteGetRecordedQuery(*models.ReqContext) response.Response
+	RoutePostRecordedQuery(*models.ReqContext, apimodels.PostableRecordedQuery) response.Response
+	RoutePutRecordedQuery(*models.ReqContext, apimodels.PostableRecordedQuery) response.Response
+}
+
+func (api *API) RegisterRecordedQueriesApiEndpoints(srv RecordedQueriesApiService, m *metrics.Metrics) {
+	api.RouteRegister.Group("", func(group routing.RouteRegister) {
+		group.Delete(
+			toMacaronPath("/api/v1/ngalert/recorded-queries/{UID}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/ngalert/recorded-queries/{UID}",
+				srv.RouteDeleteRecordedQuery,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/recorded-queries"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/recorded-queries",
+				srv.RouteGetRecordedQueries,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/recorded-queries/{UID}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/recorded-queries/{UID}",
+				srv.RouteGetRecordedQuery,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/ngalert/recorded-queries"),
+			binding.Bind(apimodels.PostableRecordedQuery{}),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/ngalert/recorded-queries",
+				srv.RoutePostRecordedQuery,
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/ngalert/recorded-queries/{UID}"),
+			binding.Bind(apimodels.PostableRecordedQuery{}),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/ngalert/recorded-queries/{UID}",
+				srv.RoutePutRecordedQuery,
+				m,
+			),
+		)
+	}, middleware.ReqSignedIn)
+}
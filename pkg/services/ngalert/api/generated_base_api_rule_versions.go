@@ -0,0 +1,70 @@
+/*Package api contains base API implementation of unified alerting
+ *
+ *Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ *
+ *Do not manually edit these files, please find ngalert/api/swagger-codegen/ for commands on how to generate them.
+ */
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-macaron/binding"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+)
+
+type RuleVersionsApiService interface {
+	RouteDiffRuleVersions(*models.ReqContext, apimodels.RuleVersionsDiffPayload) response.Response
+	RouteGetRuleVersion(*models.ReqContext) response.Response
+	RouteGetRuleVersions(*models.ReqContext) response.Response
+	RouteRestoreRuleVersion(*models.ReqContext, apimodels.RestoreRuleVersionPayload) response.Response
+}
+
+func (api *API) RegisterRuleVersionsApiEndpoints(srv RuleVersionsApiService, m *metrics.Metrics) {
+	api.RouteRegister.Group("", func(group routing.RouteRegister) {
+		group.Post(
+			toMacaronPath("/api/v1/ngalert/rules/{RuleUID}/versions/diff"),
+			binding.Bind(apimodels.RuleVersionsDiffPayload{}),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/ngalert/rules/{RuleUID}/versions/diff",
+				srv.RouteDiffRuleVersions,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/rules/{RuleUID}/versions/{Version}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/rules/{RuleUID}/versions/{Version}",
+				srv.RouteGetRuleVersion,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/rules/{RuleUID}/versions"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/rules/{RuleUID}/versions",
+				srv.RouteGetRuleVersions,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/ngalert/rules/{RuleUID}/restore"),
+			binding.Bind(apimodels.RestoreRuleVersionPayload{}),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/ngalert/rules/{RuleUID}/restore",
+				srv.RouteRestoreRuleVersion,
+				m,
+			),
+		)
+	}, middleware.ReqSignedIn)
+}
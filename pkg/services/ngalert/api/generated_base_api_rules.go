@@ -0,0 +1,61 @@
+/*Package api contains base API implementation of unified alerting
+ *
+ *Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ *
+ *Do not manually edit these files, please find ngalert/api/swagger-codegen/ for commands on how to generate them.
+ */
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-macaron/binding"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+)
+
+type RulesApiService interface {
+	RouteEditRules(*models.ReqContext, apimodels.BulkEditRulesPayload) response.Response
+	RoutePauseRules(*models.ReqContext, apimodels.BulkRulesPayload) response.Response
+	RouteResumeRules(*models.ReqContext, apimodels.BulkRulesPayload) response.Response
+}
+
+func (api *API) RegisterRulesApiEndpoints(srv RulesApiService, m *metrics.Metrics) {
+	api.RouteRegister.Group("", func(group routing.RouteRegister) {
+		group.Post(
+			toMacaronPath("/api/v1/ngalert/rules/edit"),
+			binding.Bind(apimodels.BulkEditRulesPayload{}),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/ngalert/rules/edit",
+				srv.RouteEditRules,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/ngalert/rules/pause"),
+			binding.Bind(apimodels.BulkRulesPayload{}),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/ngalert/rules/pause",
+				srv.RoutePauseRules,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/ngalert/rules/resume"),
+			binding.Bind(apimodels.BulkRulesPayload{}),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/ngalert/rules/resume",
+				srv.RouteResumeRules,
+				m,
+			),
+		)
+	}, middleware.ReqSignedIn)
+}
@@ -0,0 +1,80 @@
+/*Package api contains base API implementation of unified alerting
+ *
+ *Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ *
+ *Do not manually edit these files, please find ngalert/api/swagger-codegen/ for commands on how to generate them.
+ */
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-macaron/binding"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+)
+
+type TemplatesApiService interface {
+	RouteDeleteTemplate(*models.ReqContext) response.Response
+	RouteGetTemplate(*models.ReqContext) response.Response
+	RouteGetTemplates(*models.ReqContext) response.Response
+	RoutePutTemplate(*models.ReqContext, apimodels.NotificationTemplateContent) response.Response
+	RouteTestTemplate(*models.ReqContext, apimodels.TestTemplatePayload) response.Response
+}
+
+func (api *API) RegisterTemplatesApiEndpoints(srv TemplatesApiService, m *metrics.Metrics) {
+	api.RouteRegister.Group("", func(group routing.RouteRegister) {
+		group.Delete(
+			toMacaronPath("/api/v1/ngalert/templates/{Name}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/ngalert/templates/{Name}",
+				srv.RouteDeleteTemplate,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/templates/{Name}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/templates/{Name}",
+				srv.RouteGetTemplate,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/templates"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/templates",
+				srv.RouteGetTemplates,
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/ngalert/templates/{Name}"),
+			binding.Bind(apimodels.NotificationTemplateContent{}),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/ngalert/templates/{Name}",
+				srv.RoutePutTemplate,
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/ngalert/templates/test"),
+			binding.Bind(apimodels.TestTemplatePayload{}),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/ngalert/templates/test",
+				srv.RouteTestTemplate,
+				m,
+			),
+		)
+	}, middleware.ReqSignedIn)
+}
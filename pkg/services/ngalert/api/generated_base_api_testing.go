@@ -21,6 +21,7 @@ import (
 
 type TestingApiService interface {
 	RouteEvalQueries(*models.ReqContext, apimodels.EvalQueriesPayload) response.Response
+	RouteTestAlertRule(*models.ReqContext, apimodels.TestAlertRulePayload) response.Response
 	RouteTestReceiverConfig(*models.ReqContext, apimodels.ExtendedReceiver) response.Response
 	RouteTestRuleConfig(*models.ReqContext, apimodels.TestRulePayload) response.Response
 }
@@ -37,6 +38,16 @@ func (api *API) RegisterTestingApiEndpoints(srv TestingApiService, m *metrics.Me
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/alert-rules/test"),
+			binding.Bind(apimodels.TestAlertRulePayload{}),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/alert-rules/test",
+				srv.RouteTestAlertRule,
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/receiver/test/{Recipient}"),
 			binding.Bind(apimodels.ExtendedReceiver{}),
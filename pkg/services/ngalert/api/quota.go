@@ -0,0 +1,80 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/quota"
+)
+
+// The quota targets of unified alerting.
+const (
+	alertRuleQuotaTarget         = "alert_rule"
+	alertRuleGroupQuotaTarget    = "alert_rule_group"
+	alertContactPointQuotaTarget = "alert_contact_point"
+)
+
+// quotaUsage is the number of items of each quota target that a request adds, negative when it removes some.
+type quotaUsage map[string]int64
+
+// checkQuotas returns an error response when the items added by the request exceed the quotas of the org.
+func checkQuotas(c *models.ReqContext, quotaService *quota.QuotaService, usage quotaUsage) response.Response {
+	for _, target := range []string{alertRuleQuotaTarget, alertRuleGroupQuotaTarget, alertContactPointQuotaTarget} {
+		limitReached, err := quotaService.QuotaReachedWith(c, target, usage[target])
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to get quota")
+		}
+		if limitReached {
+			return ErrResp(http.StatusForbidden, errors.New("quota reached"), "")
+		}
+	}
+	return nil
+}
+
+// addRuleGroupUsage adds the alert rules and the rule group that updating a rule group adds, as
+// the rules of the group are replaced by the ones of the update.
+func addRuleGroupUsage(usage quotaUsage, ruleStore store.RuleStore, orgID int64, namespaceUID string, ruleGroupConfig apimodels.PostableRuleGroupConfig) error {
+	q := ngmodels.ListRuleGroupAlertRulesQuery{
+		OrgID:        orgID,
+		NamespaceUID: namespaceUID,
+		RuleGroup:    ruleGroupConfig.Name,
+	}
+	if err := ruleStore.GetRuleGroupAlertRules(&q); err != nil {
+		return err
+	}
+
+	usage[alertRuleQuotaTarget] += int64(len(ruleGroupConfig.Rules) - len(q.Result))
+	switch {
+	case len(q.Result) == 0 && len(ruleGroupConfig.Rules) > 0:
+		usage[alertRuleGroupQuotaTarget]++
+	case len(q.Result) > 0 && len(ruleGroupConfig.Rules) == 0:
+		usage[alertRuleGroupQuotaTarget]--
+	}
+	return nil
+}
+
+// addContactPointUsage adds the contact points that replacing the Alertmanager configuration of the org adds.
+func addContactPointUsage(usage quotaUsage, alertingStore store.AlertingStore, orgID int64, cfg *apimodels.PostableUserConfig) error {
+	current := 0
+	query := ngmodels.GetLatestAlertmanagerConfigurationQuery{OrgID: orgID}
+	if err := alertingStore.GetLatestAlertmanagerConfiguration(&query); err != nil {
+		if !errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+			return err
+		}
+	} else {
+		currentConfig, err := notifier.Load([]byte(query.Result.AlertmanagerConfiguration))
+		if err != nil {
+			return err
+		}
+		current = len(currentConfig.AlertmanagerConfig.Receivers)
+	}
+
+	usage[alertContactPointQuotaTarget] += int64(len(cfg.AlertmanagerConfig.Receivers) - current)
+	return nil
+}
@@ -0,0 +1,59 @@
+package definitions
+
+import (
+	"time"
+)
+
+// swagger:route POST /api/v1/ngalert/alerts/acknowledge acknowledgements RouteAcknowledgeAlerts
+//
+// Acknowledge the firing alerts of a Grafana managed alert rule, so that they are not escalated anymore until they stop firing.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: AcknowledgeResult
+//       400: ValidationError
+//       403: Failure
+//       404: Failure
+
+// swagger:route POST /api/v1/ngalert/alerts/unacknowledge acknowledgements RouteUnacknowledgeAlerts
+//
+// Remove the acknowledgement of the firing alerts of a Grafana managed alert rule, so that they are escalated again.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: AcknowledgeResult
+//       400: ValidationError
+//       403: Failure
+//       404: Failure
+
+// swagger:parameters RouteAcknowledgeAlerts RouteUnacknowledgeAlerts
+type AcknowledgeParams struct {
+	// in:body
+	Body AcknowledgePayload
+}
+
+// swagger:model
+type AcknowledgePayload struct {
+	// The UID of the alert rule.
+	RuleUID string `json:"ruleUid"`
+	// The labels of the alerts, all the firing alerts of the rule when it's empty.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// swagger:model
+type AcknowledgeResult struct {
+	// The firing alerts that have the labels, with their acknowledgement.
+	Alerts []AcknowledgedAlert `json:"alerts"`
+}
+
+type AcknowledgedAlert struct {
+	Labels   map[string]string `json:"labels"`
+	ActiveAt time.Time         `json:"activeAt"`
+	// The login of the user who acknowledged the alert, empty when it's not acknowledged.
+	AcknowledgedBy string     `json:"acknowledgedBy,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledgedAt,omitempty"`
+}
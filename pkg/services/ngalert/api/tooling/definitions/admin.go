@@ -46,6 +46,19 @@ import v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 //       200: Ack
 //       500: Failure
 
+// swagger:route POST /api/v1/ngalert/migrate configuration RoutePostLegacyAlertingMigration
+//
+// Migrates the dashboard alerts and the notification channels of the user's organization to alert rules and
+// contact points. The dashboard alerts that can't be migrated are reported. A dry run only reports the migration.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: LegacyAlertingMigrationReport
+//       400: ValidationError
+//       409: Failure
+
 // swagger:parameters RoutePostNGalertConfig
 type NGalertConfig struct {
 	// in:body
@@ -55,11 +68,14 @@ type NGalertConfig struct {
 // swagger:model
 type PostableNGalertConfig struct {
 	Alertmanagers []string `json:"alertmanagers"`
+	// Which Alertmanager(s) receive the alerts: all, internal or external. Defaults to all.
+	AlertmanagersChoice string `json:"alertmanagersChoice,omitempty"`
 }
 
 // swagger:model
 type GettableNGalertConfig struct {
-	Alertmanagers []string `json:"alertmanagers"`
+	Alertmanagers       []string `json:"alertmanagers"`
+	AlertmanagersChoice string   `json:"alertmanagersChoice"`
 }
 
 // swagger:model
@@ -67,3 +83,53 @@ type GettableAlertmanagers struct {
 	Status string                 `json:"status"`
 	Data   v1.AlertManagersResult `json:"data"`
 }
+
+// swagger:parameters RoutePostLegacyAlertingMigration
+type LegacyAlertingMigrationParams struct {
+	// in:body
+	Body PostableLegacyAlertingMigration
+}
+
+// swagger:model
+type PostableLegacyAlertingMigration struct {
+	// DryRun reports the migration without saving the alert rules and the contact points.
+	DryRun bool `json:"dryRun"`
+}
+
+// swagger:model
+type LegacyAlertingMigrationReport struct {
+	DryRun             bool                            `json:"dryRun"`
+	MigratedAlerts     []MigratedLegacyAlert           `json:"migratedAlerts"`
+	UnmigratedAlerts   []UnmigratedLegacyAlert         `json:"unmigratedAlerts"`
+	ContactPoints      []string                        `json:"contactPoints"`
+	UnmigratedChannels []UnmigratedNotificationChannel `json:"unmigratedChannels"`
+}
+
+// MigratedLegacyAlert is a dashboard alert that is migrated to an alert rule.
+type MigratedLegacyAlert struct {
+	AlertID      int64  `json:"alertId"`
+	DashboardUID string `json:"dashboardUid"`
+	PanelID      int64  `json:"panelId"`
+	Name         string `json:"name"`
+	RuleUID      string `json:"ruleUid"`
+	FolderUID    string `json:"folderUid"`
+	// Paused is set when the dashboard alert is paused, the alert rule is then paused too.
+	Paused bool `json:"paused,omitempty"`
+}
+
+// UnmigratedLegacyAlert is a dashboard alert that can't be migrated to an alert rule.
+type UnmigratedLegacyAlert struct {
+	AlertID      int64  `json:"alertId"`
+	DashboardUID string `json:"dashboardUid"`
+	PanelID      int64  `json:"panelId"`
+	Name         string `json:"name"`
+	Error        string `json:"error"`
+}
+
+// UnmigratedNotificationChannel is a notification channel that can't be migrated to a contact point.
+type UnmigratedNotificationChannel struct {
+	UID   string `json:"uid"`
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Error string `json:"error"`
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/pkg/errors"
 	amv2 "github.com/prometheus/alertmanager/api/v2/models"
 	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
 	"gopkg.in/yaml.v3"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
@@ -98,7 +100,7 @@ import (
 // create silence
 //
 //     Responses:
-//       201: gettableSilence
+//       200: gettableSilence
 //       400: ValidationError
 
 // swagger:route GET /api/alertmanager/{Recipient}/api/v2/silence/{SilenceId} alertmanager RouteGetSilence
@@ -221,6 +223,34 @@ func (s *GettableStatus) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// MarshalJSON adds the configuration in YAML to the configuration, in the original field of the status
+// of the Alertmanager API, so that the clients of the Alertmanager API can read it.
+func (s GettableStatus) MarshalJSON() ([]byte, error) {
+	type plain GettableStatus
+	if s.Config == nil {
+		return json.Marshal(plain(s))
+	}
+
+	b, err := json.Marshal(s.Config)
+	if err != nil {
+		return nil, err
+	}
+	cfg := map[string]interface{}{}
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return nil, err
+	}
+	original, err := yaml.Marshal(s.Config)
+	if err != nil {
+		return nil, err
+	}
+	cfg["original"] = string(original)
+
+	return json.Marshal(struct {
+		plain
+		Config map[string]interface{} `json:"config"`
+	}{plain: plain(s), Config: cfg})
+}
+
 func NewGettableStatus(cfg *PostableApiAlertingConfig) *GettableStatus {
 	// In Grafana, the only field we support is Config.
 	cs := amv2.ClusterStatusStatusDisabled
@@ -304,6 +334,16 @@ type PostableAlerts struct {
 	PostableAlerts []amv2.PostableAlert `yaml:"" json:""`
 }
 
+// UnmarshalJSON reads the alerts from a JSON array, as posted to the Alertmanager API.
+func (p *PostableAlerts) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &p.PostableAlerts)
+}
+
+// MarshalJSON writes the alerts as a JSON array, as posted to the Alertmanager API.
+func (p PostableAlerts) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.PostableAlerts)
+}
+
 // swagger:parameters RoutePostAlertingConfig
 type BodyAlertingConfig struct {
 	// in:body
@@ -552,15 +592,191 @@ func (c *GettableApiAlertingConfig) validate() error {
 		}
 	}
 
-	return nil
+	if err := c.validateEscalationReceivers(receivers); err != nil {
+		return err
+	}
+	return c.validateRateLimitReceivers(receivers)
 }
 
 // Config is the top-level configuration for Alertmanager's config files.
 type Config struct {
-	Global       *config.GlobalConfig  `yaml:"global,omitempty" json:"global,omitempty"`
-	Route        *config.Route         `yaml:"route,omitempty" json:"route,omitempty"`
-	InhibitRules []*config.InhibitRule `yaml:"inhibit_rules,omitempty" json:"inhibit_rules,omitempty"`
-	Templates    []string              `yaml:"templates" json:"templates"`
+	Global            *config.GlobalConfig  `yaml:"global,omitempty" json:"global,omitempty"`
+	Route             *config.Route         `yaml:"route,omitempty" json:"route,omitempty"`
+	InhibitRules      []*config.InhibitRule `yaml:"inhibit_rules,omitempty" json:"inhibit_rules,omitempty"`
+	Templates         []string              `yaml:"templates" json:"templates"`
+	MuteTimeIntervals []MuteTimeInterval    `yaml:"mute_time_intervals,omitempty" json:"mute_time_intervals,omitempty"`
+	EscalationChains  []EscalationChain     `yaml:"escalation_chains,omitempty" json:"escalation_chains,omitempty"`
+	RateLimits        []RateLimit           `yaml:"rate_limits,omitempty" json:"rate_limits,omitempty"`
+}
+
+// MuteTimeInterval is a named set of recurring time intervals, such as the nights and the weekends,
+// during which the notifications of the routes that refer to it by name are muted.
+type MuteTimeInterval struct {
+	Name          string                      `yaml:"name" json:"name"`
+	TimeIntervals []timeinterval.TimeInterval `yaml:"time_intervals" json:"time_intervals"`
+	// The name of the time zone of the time intervals, as in the IANA Time Zone database, such as
+	// "Europe/Paris". The time intervals are in UTC if it's empty.
+	Location string `yaml:"location,omitempty" json:"location,omitempty"`
+}
+
+// LoadLocation returns the time zone of the time intervals.
+func (mt MuteTimeInterval) LoadLocation() (*time.Location, error) {
+	if mt.Location == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(mt.Location)
+}
+
+// validateMuteTimeIntervals checks that the mute time intervals are well defined, and that the
+// routes only refer to existing ones.
+func (c *Config) validateMuteTimeIntervals() error {
+	names := make(map[string]struct{}, len(c.MuteTimeIntervals))
+	for _, mt := range c.MuteTimeIntervals {
+		if mt.Name == "" {
+			return fmt.Errorf("missing name in mute time interval")
+		}
+		if _, ok := names[mt.Name]; ok {
+			return fmt.Errorf("mute time interval %q is not unique", mt.Name)
+		}
+		names[mt.Name] = struct{}{}
+		if _, err := mt.LoadLocation(); err != nil {
+			return fmt.Errorf("invalid location of mute time interval %q: %w", mt.Name, err)
+		}
+	}
+
+	if c.Route == nil {
+		return nil
+	}
+	if len(c.Route.MuteTimeIntervals) > 0 {
+		return fmt.Errorf("root route must not have any mute time intervals")
+	}
+	for _, name := range AllMuteTimeIntervals(c.Route) {
+		if _, ok := names[name]; !ok {
+			return fmt.Errorf("undefined mute time interval %q used in route", name)
+		}
+	}
+	return nil
+}
+
+// EscalationChain notifies other contact points about the firing alerts it matches that are not
+// acknowledged after the delays of its steps. An alert is only escalated by the first chain that
+// matches it.
+type EscalationChain struct {
+	Name     string          `yaml:"name" json:"name"`
+	Matchers config.Matchers `yaml:"matchers,omitempty" json:"matchers,omitempty"`
+	// The steps, by increasing delay.
+	Steps []EscalationStep `yaml:"steps" json:"steps"`
+}
+
+// EscalationStep notifies a contact point about the alerts that are firing for longer than its delay.
+type EscalationStep struct {
+	// The delay since the alert started firing.
+	After    model.Duration `yaml:"after" json:"after"`
+	Receiver string         `yaml:"receiver" json:"receiver"`
+}
+
+// Matches returns true if the escalation chain matches the labels of an alert.
+func (ec EscalationChain) Matches(lset model.LabelSet) bool {
+	for _, m := range ec.Matchers {
+		if !m.Matches(string(lset[model.LabelName(m.Name)])) {
+			return false
+		}
+	}
+	return true
+}
+
+// DueStep returns the number of steps whose delay elapsed for an alert that is firing since startsAt.
+func (ec EscalationChain) DueStep(startsAt, now time.Time) int {
+	due := 0
+	for _, step := range ec.Steps {
+		if now.Sub(startsAt) < time.Duration(step.After) {
+			break
+		}
+		due++
+	}
+	return due
+}
+
+// validateEscalationChains checks that the escalation chains are well defined.
+func (c *Config) validateEscalationChains() error {
+	names := make(map[string]struct{}, len(c.EscalationChains))
+	for _, ec := range c.EscalationChains {
+		if ec.Name == "" {
+			return fmt.Errorf("missing name in escalation chain")
+		}
+		if _, ok := names[ec.Name]; ok {
+			return fmt.Errorf("escalation chain %q is not unique", ec.Name)
+		}
+		names[ec.Name] = struct{}{}
+		if len(ec.Steps) == 0 {
+			return fmt.Errorf("escalation chain %q has no steps", ec.Name)
+		}
+		var previous model.Duration
+		for _, step := range ec.Steps {
+			if step.Receiver == "" {
+				return fmt.Errorf("missing receiver in a step of escalation chain %q", ec.Name)
+			}
+			if step.After <= previous {
+				return fmt.Errorf("the delays of the steps of escalation chain %q must be positive and increasing", ec.Name)
+			}
+			previous = step.After
+		}
+	}
+	return nil
+}
+
+// validateEscalationReceivers checks that the steps of the escalation chains refer to existing receivers.
+func (c *Config) validateEscalationReceivers(receivers map[string]struct{}) error {
+	for _, ec := range c.EscalationChains {
+		for _, step := range ec.Steps {
+			if _, ok := receivers[step.Receiver]; !ok {
+				return fmt.Errorf("undefined receiver %q used in escalation chain %q", step.Receiver, ec.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// RateLimit limits the number of notifications that each integration of a contact point sends, to
+// protect the mail relays and the chat webhooks from alert storms. The notifications over the limit
+// are not sent, and the next notification sent reports the number of alerts that were suppressed.
+type RateLimit struct {
+	Receiver string `yaml:"receiver" json:"receiver"`
+	// The maximum number of notifications per minute, unlimited when 0.
+	MaxPerMinute int `yaml:"max_per_minute,omitempty" json:"max_per_minute,omitempty"`
+	// The maximum number of notifications per hour, unlimited when 0.
+	MaxPerHour int `yaml:"max_per_hour,omitempty" json:"max_per_hour,omitempty"`
+}
+
+// validateRateLimits checks that the rate limits are well defined.
+func (c *Config) validateRateLimits() error {
+	receivers := make(map[string]struct{}, len(c.RateLimits))
+	for _, rl := range c.RateLimits {
+		if rl.Receiver == "" {
+			return fmt.Errorf("missing receiver in rate limit")
+		}
+		if _, ok := receivers[rl.Receiver]; ok {
+			return fmt.Errorf("receiver %q has more than one rate limit", rl.Receiver)
+		}
+		receivers[rl.Receiver] = struct{}{}
+		if rl.MaxPerMinute < 0 || rl.MaxPerHour < 0 {
+			return fmt.Errorf("the limits of the rate limit of receiver %q must not be negative", rl.Receiver)
+		}
+		if rl.MaxPerMinute == 0 && rl.MaxPerHour == 0 {
+			return fmt.Errorf("the rate limit of receiver %q has no limit", rl.Receiver)
+		}
+	}
+	return nil
+}
+
+// validateRateLimitReceivers checks that the rate limits refer to existing receivers.
+func (c *Config) validateRateLimitReceivers(receivers map[string]struct{}) error {
+	for _, rl := range c.RateLimits {
+		if _, ok := receivers[rl.Receiver]; !ok {
+			return fmt.Errorf("undefined receiver %q used in rate limit", rl.Receiver)
+		}
+	}
+	return nil
 }
 
 // Config is the entrypoint for the embedded Alertmanager config with the exception of receivers.
@@ -599,7 +815,7 @@ func (c *Config) UnmarshalJSON(b []byte) error {
 	if len(c.Route.Receiver) == 0 {
 		return fmt.Errorf("root route must specify a default receiver")
 	}
-	if len(c.Route.Match) > 0 || len(c.Route.MatchRE) > 0 {
+	if len(c.Route.Match) > 0 || len(c.Route.MatchRE) > 0 || len(c.Route.Matchers) > 0 {
 		return fmt.Errorf("root route must not have any matchers")
 	}
 
@@ -609,7 +825,13 @@ func (c *Config) UnmarshalJSON(b []byte) error {
 		}
 	}
 
-	return nil
+	if err := c.validateMuteTimeIntervals(); err != nil {
+		return err
+	}
+	if err := c.validateEscalationChains(); err != nil {
+		return err
+	}
+	return c.validateRateLimits()
 }
 
 type PostableApiAlertingConfig struct {
@@ -682,7 +904,10 @@ func (c *PostableApiAlertingConfig) validate() error {
 		}
 	}
 
-	return nil
+	if err := c.validateEscalationReceivers(receivers); err != nil {
+		return err
+	}
+	return c.validateRateLimitReceivers(receivers)
 }
 
 // Type requires validate has been called and just checks the first receiver type
@@ -717,6 +942,21 @@ func AllReceivers(route *config.Route) (res []string) {
 	return res
 }
 
+// AllMuteTimeIntervals will recursively walk a routing tree and return a list of all the
+// referenced mute time interval names.
+func AllMuteTimeIntervals(route *config.Route) (res []string) {
+	if route == nil {
+		return res
+	}
+
+	res = append(res, route.MuteTimeIntervals...)
+
+	for _, subRoute := range route.Routes {
+		res = append(res, AllMuteTimeIntervals(subRoute)...)
+	}
+	return res
+}
+
 type GettableGrafanaReceiver struct {
 	UID                   string           `json:"uid"`
 	Name                  string           `json:"name"`
@@ -5,7 +5,9 @@ import (
 	"io/ioutil"
 	"strings"
 	"testing"
+	"time"
 
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
 	"github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
@@ -670,3 +672,350 @@ func Test_Marshaling_Validation(t *testing.T) {
 	expected := []model.LabelName{"alertname"}
 	require.Equal(t, expected, tmp.AlertmanagerConfig.Config.Route.GroupBy)
 }
+
+func Test_AllMuteTimeIntervals(t *testing.T) {
+	input := &config.Route{
+		Receiver: "foo",
+		Routes: []*config.Route{
+			{
+				Receiver:          "bar",
+				MuteTimeIntervals: []string{"weekends"},
+				Routes: []*config.Route{
+					{
+						Receiver:          "bazz",
+						MuteTimeIntervals: []string{"nights", "holidays"},
+					},
+				},
+			},
+			{
+				Receiver: "buzz",
+			},
+		},
+	}
+
+	require.Equal(t, []string{"weekends", "nights", "holidays"}, AllMuteTimeIntervals(input))
+
+	// test empty
+	var empty []string
+	require.Equal(t, empty, AllMuteTimeIntervals(&config.Route{}))
+}
+
+func Test_MuteTimeIntervals_Unmarshaling(t *testing.T) {
+	for _, tc := range []struct {
+		desc  string
+		input string
+		err   string
+	}{
+		{
+			desc: "success",
+			input: `{
+				"route": {"receiver": "graf", "routes": [{"receiver": "graf", "mute_time_intervals": ["out-of-hours"]}]},
+				"mute_time_intervals": [{
+					"name": "out-of-hours",
+					"location": "Europe/Paris",
+					"time_intervals": [{"weekdays": ["saturday", "sunday"]}, {"times": [{"start_time": "18:00", "end_time": "24:00"}]}]
+				}]
+			}`,
+		},
+		{
+			desc: "failure missing name",
+			input: `{
+				"route": {"receiver": "graf"},
+				"mute_time_intervals": [{"time_intervals": [{"weekdays": ["sunday"]}]}]
+			}`,
+			err: "missing name in mute time interval",
+		},
+		{
+			desc: "failure duplicate name",
+			input: `{
+				"route": {"receiver": "graf"},
+				"mute_time_intervals": [
+					{"name": "weekends", "time_intervals": [{"weekdays": ["sunday"]}]},
+					{"name": "weekends", "time_intervals": [{"weekdays": ["saturday"]}]}
+				]
+			}`,
+			err: `mute time interval "weekends" is not unique`,
+		},
+		{
+			desc: "failure invalid location",
+			input: `{
+				"route": {"receiver": "graf"},
+				"mute_time_intervals": [{"name": "weekends", "location": "Mars/Olympus", "time_intervals": [{"weekdays": ["sunday"]}]}]
+			}`,
+			err: `invalid location of mute time interval "weekends": unknown time zone Mars/Olympus`,
+		},
+		{
+			desc: "failure invalid time range",
+			input: `{
+				"route": {"receiver": "graf"},
+				"mute_time_intervals": [{"name": "nights", "time_intervals": [{"times": [{"start_time": "18:00", "end_time": "08:00"}]}]}]
+			}`,
+			err: "start time cannot be equal or greater than end time",
+		},
+		{
+			desc: "failure mute time intervals on the root route",
+			input: `{
+				"route": {"receiver": "graf", "mute_time_intervals": ["weekends"]},
+				"mute_time_intervals": [{"name": "weekends", "time_intervals": [{"weekdays": ["sunday"]}]}]
+			}`,
+			err: "root route must not have any mute time intervals",
+		},
+		{
+			desc: "failure undefined mute time interval",
+			input: `{
+				"route": {"receiver": "graf", "routes": [{"receiver": "graf", "mute_time_intervals": ["holidays"]}]},
+				"mute_time_intervals": [{"name": "weekends", "time_intervals": [{"weekdays": ["sunday"]}]}]
+			}`,
+			err: `undefined mute time interval "holidays" used in route`,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			var out Config
+			err := json.Unmarshal([]byte(tc.input), &out)
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Len(t, out.MuteTimeIntervals, 1)
+			require.Equal(t, "out-of-hours", out.MuteTimeIntervals[0].Name)
+			require.Len(t, out.MuteTimeIntervals[0].TimeIntervals, 2)
+			location, err := out.MuteTimeIntervals[0].LoadLocation()
+			require.NoError(t, err)
+			require.Equal(t, "Europe/Paris", location.String())
+
+			// the mute time intervals survive a round trip
+			encoded, err := json.Marshal(out)
+			require.NoError(t, err)
+			var roundtrip Config
+			require.NoError(t, json.Unmarshal(encoded, &roundtrip))
+			require.Equal(t, out.MuteTimeIntervals, roundtrip.MuteTimeIntervals)
+		})
+	}
+}
+
+func Test_EscalationChains_Unmarshaling(t *testing.T) {
+	receivers := `"receivers": [
+		{"name": "graf", "grafana_managed_receiver_configs": [{"name": "slack", "type": "slack", "settings": {}}]},
+		{"name": "on-call", "grafana_managed_receiver_configs": [{"name": "pagerduty", "type": "pagerduty", "settings": {}}]}
+	]`
+	for _, tc := range []struct {
+		desc  string
+		input string
+		err   string
+	}{
+		{
+			desc: "success",
+			input: `{
+				"route": {"receiver": "graf"},
+				"escalation_chains": [{
+					"name": "critical",
+					"matchers": ["severity=\"critical\""],
+					"steps": [{"after": "15m", "receiver": "on-call"}, {"after": "1h", "receiver": "graf"}]
+				}],
+				` + receivers + `
+			}`,
+		},
+		{
+			desc: "failure missing name",
+			input: `{
+				"route": {"receiver": "graf"},
+				"escalation_chains": [{"steps": [{"after": "15m", "receiver": "on-call"}]}],
+				` + receivers + `
+			}`,
+			err: "missing name in escalation chain",
+		},
+		{
+			desc: "failure duplicate name",
+			input: `{
+				"route": {"receiver": "graf"},
+				"escalation_chains": [
+					{"name": "critical", "steps": [{"after": "15m", "receiver": "on-call"}]},
+					{"name": "critical", "steps": [{"after": "30m", "receiver": "on-call"}]}
+				],
+				` + receivers + `
+			}`,
+			err: `escalation chain "critical" is not unique`,
+		},
+		{
+			desc: "failure no steps",
+			input: `{
+				"route": {"receiver": "graf"},
+				"escalation_chains": [{"name": "critical"}],
+				` + receivers + `
+			}`,
+			err: `escalation chain "critical" has no steps`,
+		},
+		{
+			desc: "failure decreasing delays",
+			input: `{
+				"route": {"receiver": "graf"},
+				"escalation_chains": [{"name": "critical", "steps": [{"after": "1h", "receiver": "on-call"}, {"after": "15m", "receiver": "graf"}]}],
+				` + receivers + `
+			}`,
+			err: `the delays of the steps of escalation chain "critical" must be positive and increasing`,
+		},
+		{
+			desc: "failure undefined receiver",
+			input: `{
+				"route": {"receiver": "graf"},
+				"escalation_chains": [{"name": "critical", "steps": [{"after": "15m", "receiver": "managers"}]}],
+				` + receivers + `
+			}`,
+			err: `undefined receiver "managers" used in escalation chain "critical"`,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			var out PostableApiAlertingConfig
+			err := json.Unmarshal([]byte(tc.input), &out)
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Len(t, out.EscalationChains, 1)
+			chain := out.EscalationChains[0]
+			require.Equal(t, "critical", chain.Name)
+			require.True(t, chain.Matches(model.LabelSet{"severity": "critical", "team": "db"}))
+			require.False(t, chain.Matches(model.LabelSet{"severity": "warning"}))
+
+			startsAt := time.Unix(0, 0)
+			require.Equal(t, 0, chain.DueStep(startsAt, startsAt.Add(10*time.Minute)))
+			require.Equal(t, 1, chain.DueStep(startsAt, startsAt.Add(15*time.Minute)))
+			require.Equal(t, 2, chain.DueStep(startsAt, startsAt.Add(2*time.Hour)))
+
+			// the escalation chains survive a round trip
+			encoded, err := json.Marshal(out)
+			require.NoError(t, err)
+			var roundtrip PostableApiAlertingConfig
+			require.NoError(t, json.Unmarshal(encoded, &roundtrip))
+			require.Equal(t, out.EscalationChains, roundtrip.EscalationChains)
+		})
+	}
+}
+
+func Test_RateLimits_Unmarshaling(t *testing.T) {
+	receivers := `"receivers": [
+		{"name": "graf", "grafana_managed_receiver_configs": [{"name": "slack", "type": "slack", "settings": {}}]},
+		{"name": "email", "grafana_managed_receiver_configs": [{"name": "email", "type": "email", "settings": {}}]}
+	]`
+	for _, tc := range []struct {
+		desc  string
+		input string
+		err   string
+	}{
+		{
+			desc: "success",
+			input: `{
+				"route": {"receiver": "graf"},
+				"rate_limits": [{"receiver": "email", "max_per_minute": 5, "max_per_hour": 60}],
+				` + receivers + `
+			}`,
+		},
+		{
+			desc: "failure missing receiver",
+			input: `{
+				"route": {"receiver": "graf"},
+				"rate_limits": [{"max_per_minute": 5}],
+				` + receivers + `
+			}`,
+			err: "missing receiver in rate limit",
+		},
+		{
+			desc: "failure duplicate receiver",
+			input: `{
+				"route": {"receiver": "graf"},
+				"rate_limits": [{"receiver": "email", "max_per_minute": 5}, {"receiver": "email", "max_per_hour": 60}],
+				` + receivers + `
+			}`,
+			err: `receiver "email" has more than one rate limit`,
+		},
+		{
+			desc: "failure negative limit",
+			input: `{
+				"route": {"receiver": "graf"},
+				"rate_limits": [{"receiver": "email", "max_per_minute": -1}],
+				` + receivers + `
+			}`,
+			err: `the limits of the rate limit of receiver "email" must not be negative`,
+		},
+		{
+			desc: "failure no limit",
+			input: `{
+				"route": {"receiver": "graf"},
+				"rate_limits": [{"receiver": "email"}],
+				` + receivers + `
+			}`,
+			err: `the rate limit of receiver "email" has no limit`,
+		},
+		{
+			desc: "failure undefined receiver",
+			input: `{
+				"route": {"receiver": "graf"},
+				"rate_limits": [{"receiver": "webhook", "max_per_minute": 5}],
+				` + receivers + `
+			}`,
+			err: `undefined receiver "webhook" used in rate limit`,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			var out PostableApiAlertingConfig
+			err := json.Unmarshal([]byte(tc.input), &out)
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, []RateLimit{{Receiver: "email", MaxPerMinute: 5, MaxPerHour: 60}}, out.RateLimits)
+
+			// the rate limits survive a round trip
+			encoded, err := json.Marshal(out)
+			require.NoError(t, err)
+			var roundtrip PostableApiAlertingConfig
+			require.NoError(t, json.Unmarshal(encoded, &roundtrip))
+			require.Equal(t, out.RateLimits, roundtrip.RateLimits)
+		})
+	}
+}
+
+func Test_GettableStatus_Marshaling(t *testing.T) {
+	var cfg PostableApiAlertingConfig
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"route": {"receiver": "graf"},
+		"receivers": [{"name": "graf", "grafana_managed_receiver_configs": [{"name": "slack", "type": "slack", "settings": {"url": "http://localhost"}}]}]
+	}`), &cfg))
+
+	encoded, err := json.Marshal(NewGettableStatus(&cfg))
+	require.NoError(t, err)
+
+	// the configuration is in YAML in the original field, as in the status of the Alertmanager API
+	var amStatus amv2.AlertmanagerStatus
+	require.NoError(t, json.Unmarshal(encoded, &amStatus))
+	require.Contains(t, *amStatus.Config.Original, "receiver: graf")
+
+	var status GettableStatus
+	require.NoError(t, json.Unmarshal(encoded, &status))
+	require.Equal(t, "graf", status.Config.Route.Receiver)
+	require.Len(t, status.Config.Receivers, 1)
+	require.Equal(t, "slack", status.Config.Receivers[0].GrafanaManagedReceivers[0].Type)
+}
+
+func Test_PostableAlerts_Unmarshaling(t *testing.T) {
+	var alerts PostableAlerts
+	require.NoError(t, json.Unmarshal([]byte(`[{"labels": {"alertname": "DiskFull"}}, {"labels": {"alertname": "HighCPU"}}]`), &alerts))
+	require.Len(t, alerts.PostableAlerts, 2)
+	require.Equal(t, "HighCPU", alerts.PostableAlerts[1].Labels["alertname"])
+
+	// the alerts are marshaled as an array too
+	encoded, err := json.Marshal(alerts)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(encoded), "["))
+	var roundtrip PostableAlerts
+	require.NoError(t, json.Unmarshal(encoded, &roundtrip))
+	require.Equal(t, alerts, roundtrip)
+}
@@ -96,9 +96,12 @@ type NamespaceConfigResponse map[string][]GettableRuleGroupConfig
 
 // swagger:model
 type PostableRuleGroupConfig struct {
-	Name     string                     `yaml:"name" json:"name"`
-	Interval model.Duration             `yaml:"interval,omitempty" json:"interval,omitempty"`
-	Rules    []PostableExtendedRuleNode `yaml:"rules" json:"rules"`
+	Name     string         `yaml:"name" json:"name"`
+	Interval model.Duration `yaml:"interval,omitempty" json:"interval,omitempty"`
+	// EvaluationOffset is the time after the start of each interval at which the Grafana managed rules
+	// of the group are evaluated. It's decided by the scheduler when it's not set.
+	EvaluationOffset model.Duration             `yaml:"evaluation_offset,omitempty" json:"evaluation_offset,omitempty"`
+	Rules            []PostableExtendedRuleNode `yaml:"rules" json:"rules"`
 }
 
 func (c *PostableRuleGroupConfig) UnmarshalJSON(b []byte) error {
@@ -142,9 +145,10 @@ func (c *PostableRuleGroupConfig) validate() error {
 
 // swagger:model
 type GettableRuleGroupConfig struct {
-	Name     string                     `yaml:"name" json:"name"`
-	Interval model.Duration             `yaml:"interval,omitempty" json:"interval,omitempty"`
-	Rules    []GettableExtendedRuleNode `yaml:"rules" json:"rules"`
+	Name             string                     `yaml:"name" json:"name"`
+	Interval         model.Duration             `yaml:"interval,omitempty" json:"interval,omitempty"`
+	EvaluationOffset model.Duration             `yaml:"evaluation_offset,omitempty" json:"evaluation_offset,omitempty"`
+	Rules            []GettableExtendedRuleNode `yaml:"rules" json:"rules"`
 }
 
 func (c *GettableRuleGroupConfig) UnmarshalJSON(b []byte) error {
@@ -282,6 +286,8 @@ const (
 	Alerting NoDataState = "Alerting"
 	NoData   NoDataState = "NoData"
 	OK       NoDataState = "OK"
+	// KeepLastState keeps the state of the alert when there is no data.
+	KeepLastState NoDataState = "KeepLastState"
 )
 
 // swagger:enum ExecutionErrorState
@@ -289,6 +295,9 @@ type ExecutionErrorState string
 
 const (
 	AlertingErrState ExecutionErrorState = "Alerting"
+	OkErrState       ExecutionErrorState = "OK"
+	// KeepLastStateErrState keeps the state of the alert when the evaluation fails.
+	KeepLastStateErrState ExecutionErrorState = "KeepLastState"
 )
 
 // swagger:model
@@ -299,22 +308,35 @@ type PostableGrafanaRule struct {
 	UID          string              `json:"uid" yaml:"uid"`
 	NoDataState  NoDataState         `json:"no_data_state" yaml:"no_data_state"`
 	ExecErrState ExecutionErrorState `json:"exec_err_state" yaml:"exec_err_state"`
+	// IsPaused pauses the evaluation of the rule. The rule keeps its current value when it is not set.
+	IsPaused *bool `json:"is_paused,omitempty" yaml:"is_paused,omitempty"`
+	// NoDataThreshold is the number of consecutive evaluations with no data after which the no data state applies.
+	NoDataThreshold int `json:"no_data_threshold,omitempty" yaml:"no_data_threshold,omitempty"`
+	// ExecErrThreshold is the number of consecutive failed evaluations after which the error state applies.
+	ExecErrThreshold int `json:"exec_err_threshold,omitempty" yaml:"exec_err_threshold,omitempty"`
+	// DisableDashboardAnnotations stops writing the state changes of the alerts to the dashboard panel of the rule.
+	DisableDashboardAnnotations bool `json:"disable_dashboard_annotations,omitempty" yaml:"disable_dashboard_annotations,omitempty"`
 }
 
 // swagger:model
 type GettableGrafanaRule struct {
-	ID              int64               `json:"id" yaml:"id"`
-	OrgID           int64               `json:"orgId" yaml:"orgId"`
-	Title           string              `json:"title" yaml:"title"`
-	Condition       string              `json:"condition" yaml:"condition"`
-	Data            []models.AlertQuery `json:"data" yaml:"data"`
-	Updated         time.Time           `json:"updated" yaml:"updated"`
-	IntervalSeconds int64               `json:"intervalSeconds" yaml:"intervalSeconds"`
-	Version         int64               `json:"version" yaml:"version"`
-	UID             string              `json:"uid" yaml:"uid"`
-	NamespaceUID    string              `json:"namespace_uid" yaml:"namespace_uid"`
-	NamespaceID     int64               `json:"namespace_id" yaml:"namespace_id"`
-	RuleGroup       string              `json:"rule_group" yaml:"rule_group"`
-	NoDataState     NoDataState         `json:"no_data_state" yaml:"no_data_state"`
-	ExecErrState    ExecutionErrorState `json:"exec_err_state" yaml:"exec_err_state"`
+	ID               int64               `json:"id" yaml:"id"`
+	OrgID            int64               `json:"orgId" yaml:"orgId"`
+	Title            string              `json:"title" yaml:"title"`
+	Condition        string              `json:"condition" yaml:"condition"`
+	Data             []models.AlertQuery `json:"data" yaml:"data"`
+	Updated          time.Time           `json:"updated" yaml:"updated"`
+	IntervalSeconds  int64               `json:"intervalSeconds" yaml:"intervalSeconds"`
+	Version          int64               `json:"version" yaml:"version"`
+	UID              string              `json:"uid" yaml:"uid"`
+	NamespaceUID     string              `json:"namespace_uid" yaml:"namespace_uid"`
+	NamespaceID      int64               `json:"namespace_id" yaml:"namespace_id"`
+	RuleGroup        string              `json:"rule_group" yaml:"rule_group"`
+	NoDataState      NoDataState         `json:"no_data_state" yaml:"no_data_state"`
+	ExecErrState     ExecutionErrorState `json:"exec_err_state" yaml:"exec_err_state"`
+	IsPaused         bool                `json:"is_paused" yaml:"is_paused"`
+	NoDataThreshold  int                 `json:"no_data_threshold" yaml:"no_data_threshold"`
+	ExecErrThreshold int                 `json:"exec_err_threshold" yaml:"exec_err_threshold"`
+
+	DisableDashboardAnnotations bool `json:"disable_dashboard_annotations" yaml:"disable_dashboard_annotations"`
 }
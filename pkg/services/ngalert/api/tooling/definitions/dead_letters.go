@@ -0,0 +1,54 @@
+package definitions
+
+import "time"
+
+// swagger:route GET /api/v1/ngalert/dead-letters dead-letters RouteGetNotificationDeadLetters
+//
+// Get the notifications of the Grafana Alertmanager of the user's organization that could not be sent after all their attempts, most recent first.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: GettableNotificationDeadLetters
+//       400: ValidationError
+//       403: Failure
+
+// swagger:parameters RouteGetNotificationDeadLetters
+type NotificationDeadLettersParams struct {
+	// Only the notifications of this receiver.
+	// in: query
+	// required: false
+	Receiver string `json:"receiver"`
+	// Only the notifications at or after this epoch in milliseconds.
+	// in: query
+	// required: false
+	From int64 `json:"from"`
+	// Only the notifications at or before this epoch in milliseconds.
+	// in: query
+	// required: false
+	To int64 `json:"to"`
+	// The maximum number of notifications, at most 1000.
+	// in: query
+	// required: false
+	// default: 100
+	Limit int `json:"limit"`
+}
+
+// swagger:model
+type GettableNotificationDeadLetters struct {
+	DeadLetters []GettableNotificationDeadLetter `json:"deadLetters"`
+}
+
+// GettableNotificationDeadLetter is a notification that could not be sent after all its attempts.
+type GettableNotificationDeadLetter struct {
+	Receiver        string `json:"receiver"`
+	IntegrationName string `json:"integrationName"`
+	IntegrationType string `json:"integrationType"`
+	// The labels of the alerts of the notification.
+	Alerts   []map[string]string `json:"alerts"`
+	Attempts int                 `json:"attempts"`
+	Error    string              `json:"error"`
+	// The time of the last attempt.
+	Time time.Time `json:"time"`
+}
@@ -0,0 +1,112 @@
+package definitions
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v3"
+)
+
+// swagger:route GET /api/v1/ngalert/export export RouteGetAlertingExport
+//
+// Export the rule groups, contact points and notification policies of the user's organization. The secure settings of the contact points are not exported.
+//
+//     Produces:
+//     - application/yaml
+//
+//     Responses:
+//       200: AlertingExport
+//       403: Failure
+
+// swagger:route POST /api/v1/ngalert/import export RoutePostAlertingImport
+//
+// Import rule groups, contact points and notification policies into the user's organization. Rule groups and contact points are matched by UID, so importing the same document again leaves them unchanged.
+//
+//     Consumes:
+//     - application/yaml
+//     - application/json
+//
+//     Responses:
+//       202: Ack
+//       400: ValidationError
+//       403: Failure
+//       404: Failure
+
+// AlertingExportAPIVersion is the version of the format of the exported alerting configuration.
+const AlertingExportAPIVersion = 1
+
+// swagger:parameters RoutePostAlertingImport
+type AlertingImportParams struct {
+	// in:body
+	Body AlertingExport
+}
+
+// AlertingExport is the alerting configuration of an organization, as exported and imported.
+// It's encoded to YAML through its JSON representation, so that the queries of the rules and the
+// Alertmanager configuration keep the format of the rest of the API.
+//
+// swagger:model
+type AlertingExport struct {
+	APIVersion int                       `json:"apiVersion"`
+	Groups     []AlertingExportRuleGroup `json:"groups,omitempty"`
+	// The Alertmanager configuration holding the contact points and the notification policies.
+	Alertmanager *PostableUserConfig `json:"alertmanager,omitempty"`
+}
+
+// AlertingExportRuleGroup is a rule group of the folder with the given title.
+type AlertingExportRuleGroup struct {
+	Folder           string                     `json:"folder"`
+	Name             string                     `json:"name"`
+	Interval         model.Duration             `json:"interval,omitempty"`
+	EvaluationOffset model.Duration             `json:"evaluationOffset,omitempty"`
+	Rules            []PostableExtendedRuleNode `json:"rules"`
+}
+
+// RuleGroupConfig returns the rule group as posted to the ruler API.
+func (g AlertingExportRuleGroup) RuleGroupConfig() PostableRuleGroupConfig {
+	return PostableRuleGroupConfig{
+		Name:             g.Name,
+		Interval:         g.Interval,
+		EvaluationOffset: g.EvaluationOffset,
+		Rules:            g.Rules,
+	}
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (e AlertingExport) MarshalYAML() (interface{}, error) {
+	type plain AlertingExport
+	b, err := json.Marshal(plain(e))
+	if err != nil {
+		return nil, err
+	}
+
+	var simple interface{}
+	if err := json.Unmarshal(b, &simple); err != nil {
+		return nil, err
+	}
+	return simple, nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (e *AlertingExport) UnmarshalYAML(value *yaml.Node) error {
+	var simple interface{}
+	if err := value.Decode(&simple); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(simple)
+	if err != nil {
+		return err
+	}
+
+	type plain AlertingExport
+	if err := json.Unmarshal(b, (*plain)(e)); err != nil {
+		return err
+	}
+
+	if e.APIVersion != AlertingExportAPIVersion {
+		return fmt.Errorf("unsupported apiVersion %d, expected %d", e.APIVersion, AlertingExportAPIVersion)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package definitions
+
+import (
+	"github.com/prometheus/alertmanager/config"
+)
+
+// swagger:route GET /api/v1/ngalert/inhibit-rules inhibitions RouteGetInhibitRules
+//
+// Get the inhibition rules of the Grafana Alertmanager of the user's organization.
+//
+//     Responses:
+//       200: InhibitRules
+//       403: Failure
+//       404: Failure
+
+// swagger:route PUT /api/v1/ngalert/inhibit-rules inhibitions RoutePutInhibitRules
+//
+// Replace the inhibition rules of the Grafana Alertmanager of the user's organization, leaving the rest of its configuration unchanged.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: Ack
+//       400: ValidationError
+//       403: Failure
+//       404: Failure
+
+// swagger:parameters RoutePutInhibitRules
+type InhibitRulesParams struct {
+	// in:body
+	Body InhibitRules
+}
+
+// InhibitRules are the inhibition rules of the Alertmanager. The notifications of the alerts that
+// match the target matchers of a rule are suppressed while an alert that matches its source
+// matchers is firing, and both alerts have the same values for the equal labels.
+//
+// swagger:model
+type InhibitRules struct {
+	InhibitRules []*config.InhibitRule `json:"inhibit_rules"`
+}
@@ -0,0 +1,96 @@
+package definitions
+
+import (
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/common/model"
+)
+
+// swagger:route GET /api/v1/ngalert/policies policies RouteGetPolicyTree
+//
+// Get the notification policy tree of the Grafana Alertmanager of the user's organization.
+//
+//     Responses:
+//       200: policyTree
+//       403: Failure
+//       404: Failure
+
+// swagger:route PUT /api/v1/ngalert/policies policies RoutePutPolicyTree
+//
+// Replace the notification policy tree of the Grafana Alertmanager of the user's organization. The contact points and the mute timings it refers to must exist.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: Ack
+//       400: ValidationError
+//       403: Failure
+//       404: Failure
+
+// swagger:route DELETE /api/v1/ngalert/policies policies RouteDeletePolicyTree
+//
+// Delete the specific notification policies of the Grafana Alertmanager of the user's organization. The root policy is kept, so that all the alerts are sent to its contact point.
+//
+//     Responses:
+//       202: Ack
+//       403: Failure
+//       404: Failure
+
+// swagger:route POST /api/v1/ngalert/policies/test policies RouteTestPolicyTree
+//
+// Test which notification policies an alert with the given labels matches, without sending any notification.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: PolicyTestResult
+//       400: ValidationError
+//       403: Failure
+//       404: Failure
+
+// PolicyTree is the notification policy tree. Its root is the root policy, which sets the defaults
+// of the specific policies nested below it.
+//
+// swagger:model policyTree
+type PolicyTree = config.Route
+
+// swagger:parameters RoutePutPolicyTree
+type PolicyTreeParams struct {
+	// in:body
+	Body PolicyTree
+}
+
+// swagger:parameters RouteTestPolicyTree
+type PolicyTestParams struct {
+	// in:body
+	Body PolicyTestPayload
+}
+
+// swagger:model
+type PolicyTestPayload struct {
+	// The labels of the alert to route.
+	Labels model.LabelSet `json:"labels"`
+	// The notification policy tree to route the alert with instead of the saved one, so that changes
+	// can be tried before saving them.
+	Tree *PolicyTree `json:"tree,omitempty"`
+}
+
+// swagger:model
+type PolicyTestResult struct {
+	// The notification policies the alert matches, in order. The alert is sent to the contact point of each of them.
+	Matches []MatchingPolicy `json:"matches"`
+}
+
+// MatchingPolicy is a notification policy matched by an alert, with the settings it inherits from
+// its parents.
+type MatchingPolicy struct {
+	// The matchers of the policies from the root policy to the matching one.
+	Path              string         `json:"path"`
+	Receiver          string         `json:"receiver"`
+	GroupBy           []string       `json:"group_by"`
+	GroupWait         model.Duration `json:"group_wait"`
+	GroupInterval     model.Duration `json:"group_interval"`
+	RepeatInterval    model.Duration `json:"repeat_interval"`
+	MuteTimeIntervals []string       `json:"mute_time_intervals,omitempty"`
+}
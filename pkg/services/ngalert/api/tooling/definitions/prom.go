@@ -123,6 +123,9 @@ type Alert struct {
 	ActiveAt *time.Time `json:"activeAt"`
 	// required: true
 	Value string `json:"value"`
+	// The login of the user who acknowledged the firing alert, empty when it's not acknowledged.
+	AcknowledgedBy string     `json:"acknowledgedBy,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledgedAt,omitempty"`
 }
 
 // override the labels type with a map for generation.
@@ -0,0 +1,111 @@
+package definitions
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/prometheus/common/model"
+)
+
+// swagger:route GET /api/v1/ngalert/recorded-queries recorded_queries RouteGetRecordedQueries
+//
+// Get the recorded queries of the user's organization.
+//
+//     Responses:
+//       200: GettableRecordedQueries
+//       403: Failure
+
+// swagger:route GET /api/v1/ngalert/recorded-queries/{UID} recorded_queries RouteGetRecordedQuery
+//
+// Get a recorded query of the user's organization.
+//
+//     Responses:
+//       200: GettableRecordedQuery
+//       403: Failure
+//       404: Failure
+
+// swagger:route POST /api/v1/ngalert/recorded-queries recorded_queries RoutePostRecordedQuery
+//
+// Create a recorded query in the user's organization.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: GettableRecordedQuery
+//       400: ValidationError
+//       403: Failure
+
+// swagger:route PUT /api/v1/ngalert/recorded-queries/{UID} recorded_queries RoutePutRecordedQuery
+//
+// Replace a recorded query of the user's organization.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: GettableRecordedQuery
+//       400: ValidationError
+//       403: Failure
+//       404: Failure
+
+// swagger:route DELETE /api/v1/ngalert/recorded-queries/{UID} recorded_queries RouteDeleteRecordedQuery
+//
+// Delete a recorded query of the user's organization.
+//
+//     Responses:
+//       202: Ack
+//       403: Failure
+//       404: Failure
+
+// swagger:parameters RouteGetRecordedQuery RouteDeleteRecordedQuery
+type RecordedQueryUIDParam struct {
+	// in:path
+	UID string
+}
+
+// swagger:parameters RoutePostRecordedQuery
+type PostRecordedQueryParams struct {
+	// in:body
+	Body PostableRecordedQuery
+}
+
+// swagger:parameters RoutePutRecordedQuery
+type PutRecordedQueryParams struct {
+	// in:path
+	UID string
+	// in:body
+	Body PostableRecordedQuery
+}
+
+// swagger:model
+type PostableRecordedQuery struct {
+	// The name of the recorded metric.
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Data        []models.AlertQuery `json:"data"`
+	// The RefID of the query or expression whose results are recorded.
+	Target string `json:"target"`
+	// The interval at which the query is evaluated, the default interval of the alert rules when it is not set.
+	Interval model.Duration `json:"interval,omitempty"`
+	// The labels added to the recorded series.
+	Labels map[string]string `json:"labels,omitempty"`
+	// The query is only evaluated when it is active.
+	Active bool `json:"active"`
+}
+
+// swagger:model
+type GettableRecordedQuery struct {
+	UID         string              `json:"uid"`
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Data        []models.AlertQuery `json:"data"`
+	Target      string              `json:"target"`
+	Interval    model.Duration      `json:"interval"`
+	Labels      map[string]string   `json:"labels,omitempty"`
+	Active      bool                `json:"active"`
+	Updated     time.Time           `json:"updated"`
+}
+
+// swagger:model
+type GettableRecordedQueries []GettableRecordedQuery
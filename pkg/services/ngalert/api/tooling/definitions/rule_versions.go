@@ -0,0 +1,127 @@
+package definitions
+
+import "time"
+
+// swagger:route GET /api/v1/ngalert/rules/{RuleUID}/versions rules RouteGetRuleVersions
+//
+// List the versions of a Grafana managed alert rule, most recent first. A version is saved each time the rule is saved.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleVersions
+//       403: Failure
+//       404: Failure
+
+// swagger:route GET /api/v1/ngalert/rules/{RuleUID}/versions/{Version} rules RouteGetRuleVersion
+//
+// Get a version of a Grafana managed alert rule, with its definition.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleVersion
+//       403: Failure
+//       404: Failure
+
+// swagger:route POST /api/v1/ngalert/rules/{RuleUID}/versions/diff rules RouteDiffRuleVersions
+//
+// Compute the diff of two versions of a Grafana managed alert rule, in the formats of the diffs of the dashboard versions.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//     - text/html
+//
+//     Responses:
+//       200: Ack
+//       400: ValidationError
+//       403: Failure
+//       404: Failure
+
+// swagger:route POST /api/v1/ngalert/rules/{RuleUID}/restore rules RouteRestoreRuleVersion
+//
+// Restore the definition of a Grafana managed alert rule from one of its versions, saving it as a new version.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: Ack
+//       400: ValidationError
+//       403: Failure
+//       404: Failure
+
+// swagger:parameters RouteGetRuleVersions
+type RuleVersionsParams struct {
+	// in: path
+	RuleUID string
+	// The maximum number of versions, all of them if not set.
+	// in: query
+	// required: false
+	Limit int `json:"limit"`
+	// The number of most recent versions to skip.
+	// in: query
+	// required: false
+	Start int `json:"start"`
+}
+
+// swagger:parameters RouteGetRuleVersion
+type RuleVersionParams struct {
+	// in: path
+	RuleUID string
+	// in: path
+	Version int64
+}
+
+// swagger:parameters RouteDiffRuleVersions
+type RuleVersionsDiffParams struct {
+	// in: path
+	RuleUID string
+	// in:body
+	Body RuleVersionsDiffPayload
+}
+
+// swagger:parameters RouteRestoreRuleVersion
+type RestoreRuleVersionParams struct {
+	// in: path
+	RuleUID string
+	// in:body
+	Body RestoreRuleVersionPayload
+}
+
+// swagger:model
+type RuleVersions []RuleVersion
+
+// swagger:model
+type RuleVersion struct {
+	Version       int64     `json:"version"`
+	ParentVersion int64     `json:"parentVersion"`
+	RestoredFrom  int64     `json:"restoredFrom"`
+	Created       time.Time `json:"created"`
+	Message       string    `json:"message"`
+	Title         string    `json:"title"`
+	// The definition of the rule at this version, only returned for a single version.
+	Rule *GettableExtendedRuleNode `json:"rule,omitempty"`
+}
+
+// swagger:model
+type RuleVersionsDiffPayload struct {
+	// The version to compare from.
+	Base int64 `json:"base"`
+	// The version to compare to.
+	New int64 `json:"new"`
+	// The format of the diff.
+	// enum: basic,json,delta
+	DiffType string `json:"diffType"`
+}
+
+// swagger:model
+type RestoreRuleVersionPayload struct {
+	// The version to restore.
+	Version int64 `json:"version"`
+}
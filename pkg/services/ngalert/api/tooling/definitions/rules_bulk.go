@@ -0,0 +1,86 @@
+package definitions
+
+import (
+	"github.com/prometheus/common/model"
+)
+
+// swagger:route POST /api/v1/ngalert/rules/pause rules RoutePauseRules
+//
+// Pause the Grafana managed alert rules of the user's organization that match the selector. Paused rules are not evaluated and their alerts are removed.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: BulkRulesResult
+//       400: ValidationError
+//       403: Failure
+
+// swagger:route POST /api/v1/ngalert/rules/resume rules RouteResumeRules
+//
+// Resume the Grafana managed alert rules of the user's organization that match the selector.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: BulkRulesResult
+//       400: ValidationError
+//       403: Failure
+
+// swagger:route POST /api/v1/ngalert/rules/edit rules RouteEditRules
+//
+// Change the evaluation interval or the labels of the Grafana managed alert rules of the user's organization that match the selector.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: BulkRulesResult
+//       400: ValidationError
+//       403: Failure
+
+// swagger:parameters RoutePauseRules RouteResumeRules
+type BulkRulesParams struct {
+	// in:body
+	Body BulkRulesPayload
+}
+
+// swagger:model
+type BulkRulesPayload struct {
+	Selector RuleSelector `json:"selector"`
+}
+
+// swagger:parameters RouteEditRules
+type BulkEditRulesParams struct {
+	// in:body
+	Body BulkEditRulesPayload
+}
+
+// swagger:model
+type BulkEditRulesPayload struct {
+	Selector RuleSelector `json:"selector"`
+	// The evaluation interval is set on the whole rule groups of the selected rules, as all the
+	// rules of a group are evaluated at the same interval.
+	Interval model.Duration `json:"interval,omitempty"`
+	// The labels to add to the selected rules, replacing the ones with the same name.
+	Labels map[string]string `json:"labels,omitempty"`
+	// The names of the labels to remove from the selected rules.
+	RemoveLabels []string `json:"removeLabels,omitempty"`
+}
+
+// RuleSelector selects the alert rules that match all its criteria. At least one is required.
+type RuleSelector struct {
+	// The UIDs of the folders of the rules.
+	FolderUIDs []string `json:"folderUids,omitempty"`
+	// The label matchers of the rules, such as severity="critical" or team=~"db|infra".
+	Matchers []string `json:"matchers,omitempty"`
+	// The UIDs of the data sources that the rules query, a rule matches if it queries any of them.
+	DatasourceUIDs []string `json:"datasourceUids,omitempty"`
+}
+
+// swagger:model
+type BulkRulesResult struct {
+	// The UIDs of the rules that were changed.
+	UIDs []string `json:"uids"`
+}
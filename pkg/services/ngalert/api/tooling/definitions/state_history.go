@@ -0,0 +1,60 @@
+package definitions
+
+import "time"
+
+// swagger:route GET /api/v1/ngalert/history history RouteGetAlertStateHistory
+//
+// Get the state transitions of the alert instances of the rules visible to the user, most recent first.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: GettableAlertStateHistory
+//       400: ValidationError
+
+// swagger:parameters RouteGetAlertStateHistory
+type AlertStateHistoryParams struct {
+	// Only the transitions of the alert rule with this UID.
+	// in: query
+	// required: false
+	RuleUID string `json:"ruleUID"`
+	// Only the transitions to this state.
+	// in: query
+	// required: false
+	// enum: Normal,Alerting,Pending,NoData,Error
+	State string `json:"state"`
+	// Only the transitions at or after this epoch in milliseconds.
+	// in: query
+	// required: false
+	From int64 `json:"from"`
+	// Only the transitions at or before this epoch in milliseconds.
+	// in: query
+	// required: false
+	To int64 `json:"to"`
+	// The maximum number of transitions, at most 1000.
+	// in: query
+	// required: false
+	// default: 100
+	Limit int `json:"limit"`
+}
+
+// swagger:model
+type GettableAlertStateHistory struct {
+	History []GettableAlertStateTransition `json:"history"`
+}
+
+// GettableAlertStateTransition is a state transition of an alert instance, with the evaluation
+// that caused it.
+type GettableAlertStateTransition struct {
+	RuleUID       string            `json:"ruleUID"`
+	RuleTitle     string            `json:"ruleTitle"`
+	Labels        map[string]string `json:"labels"`
+	PreviousState string            `json:"previousState"`
+	State         string            `json:"state"`
+	// The value of the reduce and math expressions of the evaluation by RefID.
+	Values           map[string]*float64 `json:"values,omitempty"`
+	EvaluationString string              `json:"evaluationString,omitempty"`
+	Error            string              `json:"error,omitempty"`
+	Time             time.Time           `json:"time"`
+}
@@ -0,0 +1,121 @@
+package definitions
+
+import (
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+// swagger:route GET /api/v1/ngalert/templates templates RouteGetTemplates
+//
+// Get the notification templates of the Grafana Alertmanager of the user's organization.
+//
+//     Responses:
+//       200: NotificationTemplates
+//       403: Failure
+//       404: Failure
+
+// swagger:route GET /api/v1/ngalert/templates/{Name} templates RouteGetTemplate
+//
+// Get a notification template of the Grafana Alertmanager of the user's organization.
+//
+//     Responses:
+//       200: NotificationTemplate
+//       403: Failure
+//       404: Failure
+
+// swagger:route PUT /api/v1/ngalert/templates/{Name} templates RoutePutTemplate
+//
+// Create or replace a notification template of the Grafana Alertmanager of the user's organization.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: Ack
+//       400: ValidationError
+//       403: Failure
+//       404: Failure
+
+// swagger:route DELETE /api/v1/ngalert/templates/{Name} templates RouteDeleteTemplate
+//
+// Delete a notification template of the Grafana Alertmanager of the user's organization. The contact points that use it are not checked.
+//
+//     Responses:
+//       202: Ack
+//       403: Failure
+//       404: Failure
+
+// swagger:route POST /api/v1/ngalert/templates/test templates RouteTestTemplate
+//
+// Render a template the way the templated settings of the contact points are rendered, without sending any notification.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: TestTemplateResult
+//       400: ValidationError
+//       403: Failure
+
+// swagger:parameters RouteGetTemplate RouteDeleteTemplate
+type NotificationTemplateNameParam struct {
+	// in:path
+	Name string
+}
+
+// swagger:parameters RoutePutTemplate
+type NotificationTemplateParams struct {
+	// in:path
+	Name string
+	// in:body
+	Body NotificationTemplateContent
+}
+
+// swagger:model
+type NotificationTemplateContent struct {
+	// The content of the template file. It can define several named templates that the contact
+	// points refer to with {{ template "name" . }}.
+	Template string `json:"template"`
+}
+
+// swagger:model
+type NotificationTemplate struct {
+	Name     string `json:"name"`
+	Template string `json:"template"`
+}
+
+// swagger:model
+type NotificationTemplates []NotificationTemplate
+
+// swagger:parameters RouteTestTemplate
+type TestTemplateParams struct {
+	// in:body
+	Body TestTemplatePayload
+}
+
+// swagger:model
+type TestTemplatePayload struct {
+	// The text to render, as in a templated setting of a contact point. The templates it defines
+	// replace the saved templates with the same name, so that changes can be tried before saving them.
+	Template string `json:"template"`
+	// The alerts to render the text with. A test alert is used when there are none.
+	Alerts []TestTemplateAlert `json:"alerts,omitempty"`
+}
+
+// TestTemplateAlert is an alert to render a template with.
+type TestTemplateAlert struct {
+	Labels      model.LabelSet `json:"labels"`
+	Annotations model.LabelSet `json:"annotations,omitempty"`
+	// The values of the reduce and math expressions of the alert rule by RefID.
+	Values map[string]float64 `json:"values,omitempty"`
+	// The alert starts now when it is not set.
+	StartsAt time.Time `json:"startsAt,omitempty"`
+	// The alert is resolved when it is set in the past.
+	EndsAt time.Time `json:"endsAt,omitempty"`
+}
+
+// swagger:model
+type TestTemplateResult struct {
+	Text string `json:"text"`
+}
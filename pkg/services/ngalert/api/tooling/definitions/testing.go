@@ -53,6 +53,20 @@ import (
 //     Responses:
 //       200: EvalQueriesResponse
 
+// swagger:route Post /api/v1/alert-rules/test testing RouteTestAlertRule
+//
+// Evaluate a Grafana managed alert rule against the current data of its queries, without saving it or sending any notification.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: TestAlertRuleResponse
+//       400: ValidationError
+
 // swagger:parameters RouteTestReceiverConfig
 type TestReceiverRequest struct {
 	// in:body
@@ -73,6 +87,39 @@ type TestRulePayload struct {
 	GrafanaManagedCondition *models.EvalAlertConditionCommand `json:"grafana_condition,omitempty"`
 }
 
+// swagger:parameters RouteTestAlertRule
+type TestAlertRuleRequest struct {
+	// in:body
+	Body TestAlertRulePayload
+}
+
+// swagger:model
+type TestAlertRulePayload struct {
+	// The alert rule, as in the rule groups of the ruler API.
+	Rule PostableExtendedRuleNode `json:"rule"`
+	// The UID of the folder of the alert rule, used for the __alert_rule_namespace_uid__ label.
+	NamespaceUID string `json:"namespace_uid,omitempty"`
+	// The time to evaluate the alert rule at, now by default.
+	Now time.Time `json:"now"`
+}
+
+// swagger:model
+type TestAlertRuleResponse struct {
+	Instances []TestAlertRuleInstance `json:"instances"`
+}
+
+// TestAlertRuleInstance is the result of the evaluation of an alert instance, with the labels and
+// annotations its alert would be sent with.
+type TestAlertRuleInstance struct {
+	// The result of the condition: Normal, Alerting, NoData or Error.
+	State            string              `json:"state"`
+	Labels           map[string]string   `json:"labels"`
+	Annotations      map[string]string   `json:"annotations"`
+	EvaluationString string              `json:"evaluation_string,omitempty"`
+	Values           map[string]*float64 `json:"values,omitempty"`
+	Error            string              `json:"error,omitempty"`
+}
+
 // swagger:parameters RouteEvalQueries
 type EvalQueriesRequest struct {
 	// in:body
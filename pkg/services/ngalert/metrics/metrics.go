@@ -36,6 +36,21 @@ type Metrics struct {
 	EvalFailures         *prometheus.CounterVec
 	EvalDuration         *prometheus.SummaryVec
 	GroupRules           *prometheus.GaugeVec
+	// SchedulerTickDrift is the delay between the time of a tick of the scheduler and the time it is handled.
+	SchedulerTickDrift prometheus.Histogram
+	// NotificationAttempts and NotificationFailures count the notifications by receiver and integration, including the retries.
+	NotificationAttempts *prometheus.CounterVec
+	NotificationFailures *prometheus.CounterVec
+	// ExternalAlertmanagerQueueLength is the number of alerts waiting to be sent to the external Alertmanagers of an organization.
+	ExternalAlertmanagerQueueLength *prometheus.GaugeVec
+	// RecordedQueryEvaluations and RecordedQueryFailures count the evaluations of the recorded queries.
+	RecordedQueryEvaluations prometheus.Counter
+	RecordedQueryFailures    prometheus.Counter
+	// Escalations counts the alerts escalated to the contact points of the escalation chains, by receiver.
+	Escalations *prometheus.CounterVec
+	// NotificationsRateLimited counts the notifications that are not sent because of the rate limit of their
+	// receiver, by receiver and integration.
+	NotificationsRateLimited *prometheus.CounterVec
 }
 
 func init() {
@@ -79,8 +94,6 @@ func NewMetrics(r prometheus.Registerer) *Metrics {
 			Name:      "active_configurations",
 			Help:      "The number of active, non default alertmanager configurations for grafana managed alerts",
 		}),
-		// TODO: once rule groups support multiple rules, consider partitioning
-		// on rule group as well as tenant, similar to loki|cortex.
 		EvalTotal: promauto.With(r).NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace: "grafana",
@@ -88,10 +101,8 @@ func NewMetrics(r prometheus.Registerer) *Metrics {
 				Name:      "rule_evaluations_total",
 				Help:      "The total number of rule evaluations.",
 			},
-			[]string{"user"},
+			[]string{"user", "rule_group"},
 		),
-		// TODO: once rule groups support multiple rules, consider partitioning
-		// on rule group as well as tenant, similar to loki|cortex.
 		EvalFailures: promauto.With(r).NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace: "grafana",
@@ -99,7 +110,7 @@ func NewMetrics(r prometheus.Registerer) *Metrics {
 				Name:      "rule_evaluation_failures_total",
 				Help:      "The total number of rule evaluation failures.",
 			},
-			[]string{"user"},
+			[]string{"user", "rule_group"},
 		),
 		EvalDuration: promauto.With(r).NewSummaryVec(
 			prometheus.SummaryOpts{
@@ -109,7 +120,7 @@ func NewMetrics(r prometheus.Registerer) *Metrics {
 				Help:       "The duration for a rule to execute.",
 				Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
 			},
-			[]string{"user"},
+			[]string{"user", "rule_group"},
 		),
 		// TODO: once rule groups support multiple rules, consider partitioning
 		// on rule group as well as tenant, similar to loki|cortex.
@@ -122,6 +133,76 @@ func NewMetrics(r prometheus.Registerer) *Metrics {
 			},
 			[]string{"user"},
 		),
+		SchedulerTickDrift: promauto.With(r).NewHistogram(
+			prometheus.HistogramOpts{
+				Namespace: "grafana",
+				Subsystem: "alerting",
+				Name:      "schedule_tick_drift_seconds",
+				Help:      "The delay between the time of a tick of the scheduler and the time it is handled.",
+				Buckets:   []float64{0.001, 0.01, 0.1, 0.5, 1, 2.5, 5, 10},
+			},
+		),
+		NotificationAttempts: promauto.With(r).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "grafana",
+				Subsystem: "alerting",
+				Name:      "notification_attempts_total",
+				Help:      "The total number of attempts to send a notification to a receiver.",
+			},
+			[]string{"receiver", "integration"},
+		),
+		NotificationFailures: promauto.With(r).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "grafana",
+				Subsystem: "alerting",
+				Name:      "notification_failures_total",
+				Help:      "The total number of failed attempts to send a notification to a receiver.",
+			},
+			[]string{"receiver", "integration"},
+		),
+		ExternalAlertmanagerQueueLength: promauto.With(r).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "grafana",
+				Subsystem: "alerting",
+				Name:      "external_alertmanager_queue_length",
+				Help:      "The number of alerts waiting to be sent to the external Alertmanagers.",
+			},
+			[]string{"org"},
+		),
+		RecordedQueryEvaluations: promauto.With(r).NewCounter(
+			prometheus.CounterOpts{
+				Namespace: "grafana",
+				Subsystem: "alerting",
+				Name:      "recorded_query_evaluations_total",
+				Help:      "The total number of evaluations of the recorded queries.",
+			},
+		),
+		RecordedQueryFailures: promauto.With(r).NewCounter(
+			prometheus.CounterOpts{
+				Namespace: "grafana",
+				Subsystem: "alerting",
+				Name:      "recorded_query_failures_total",
+				Help:      "The total number of evaluations of the recorded queries whose results could not be written.",
+			},
+		),
+		Escalations: promauto.With(r).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "grafana",
+				Subsystem: "alerting",
+				Name:      "escalations_total",
+				Help:      "The total number of alerts escalated to the contact points of the escalation chains.",
+			},
+			[]string{"receiver"},
+		),
+		NotificationsRateLimited: promauto.With(r).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "grafana",
+				Subsystem: "alerting",
+				Name:      "notifications_rate_limited_total",
+				Help:      "The total number of notifications not sent because of the rate limit of their receiver.",
+			},
+			[]string{"receiver", "integration"},
+		),
 	}
 }
 
@@ -5,6 +5,41 @@ import (
 	"fmt"
 )
 
+// AlertmanagersChoice tells which Alertmanager(s) receive the alerts of an organization.
+type AlertmanagersChoice int
+
+const (
+	// AllAlertmanagers sends alerts to the internal Alertmanager and to the external Alertmanager(s).
+	AllAlertmanagers AlertmanagersChoice = iota
+	// InternalAlertmanager only sends alerts to the internal Alertmanager.
+	InternalAlertmanager
+	// ExternalAlertmanagers only sends alerts to the external Alertmanager(s).
+	ExternalAlertmanagers
+)
+
+var alertmanagersChoiceNames = map[AlertmanagersChoice]string{
+	AllAlertmanagers:      "all",
+	InternalAlertmanager:  "internal",
+	ExternalAlertmanagers: "external",
+}
+
+func (c AlertmanagersChoice) String() string {
+	return alertmanagersChoiceNames[c]
+}
+
+// ParseAlertmanagersChoice returns the choice with the name. An empty name is AllAlertmanagers.
+func ParseAlertmanagersChoice(name string) (AlertmanagersChoice, error) {
+	if name == "" {
+		return AllAlertmanagers, nil
+	}
+	for choice, choiceName := range alertmanagersChoiceNames {
+		if choiceName == name {
+			return choice, nil
+		}
+	}
+	return AllAlertmanagers, fmt.Errorf("invalid alertmanagers choice %q, must be one of all, internal or external", name)
+}
+
 // AdminConfiguration represents the ngalert administration configuration settings.
 type AdminConfiguration struct {
 	ID    int64 `xorm:"pk autoincr 'id'"`
@@ -13,6 +48,10 @@ type AdminConfiguration struct {
 	// List of Alertmanager(s) URL to push alerts to.
 	Alertmanagers []string
 
+	// SendAlertsTo tells whether the alerts are sent to the internal Alertmanager, the external
+	// Alertmanager(s) or both.
+	SendAlertsTo AlertmanagersChoice
+
 	CreatedAt int64 `xorm:"created"`
 	UpdatedAt int64 `xorm:"updated"`
 }
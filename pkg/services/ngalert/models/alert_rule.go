@@ -19,6 +19,8 @@ var (
 	ErrAlertRuleFailedValidation = errors.New("invalid alert rule")
 	// ErrAlertRuleUniqueConstraintViolation
 	ErrAlertRuleUniqueConstraintViolation = errors.New("a conflicting alert rule is found: rule title under the same organisation and folder should be unique")
+	// ErrAlertRuleVersionNotFound is an error for an unknown version of an alert rule.
+	ErrAlertRuleVersionNotFound = errors.New("could not find alert rule version")
 )
 
 type NoDataState string
@@ -31,6 +33,8 @@ const (
 	Alerting NoDataState = "Alerting"
 	NoData   NoDataState = "NoData"
 	OK       NoDataState = "OK"
+	// KeepLastState keeps the state of the alert when there is no data.
+	KeepLastState NoDataState = "KeepLastState"
 )
 
 type ExecutionErrorState string
@@ -41,6 +45,9 @@ func (executionErrorState ExecutionErrorState) String() string {
 
 const (
 	AlertingErrState ExecutionErrorState = "Alerting"
+	OkErrState       ExecutionErrorState = "OK"
+	// KeepLastStateErrState keeps the state of the alert when the evaluation fails.
+	KeepLastStateErrState ExecutionErrorState = "KeepLastState"
 )
 
 const (
@@ -68,6 +75,18 @@ type AlertRule struct {
 	For         time.Duration
 	Annotations map[string]string
 	Labels      map[string]string
+	// IsPaused is set when the alert rule is not evaluated.
+	IsPaused bool
+	// OffsetSeconds is the time after the start of each interval at which the rule group is evaluated,
+	// the scheduler decides when it's zero.
+	OffsetSeconds int64
+	// NoDataThreshold and ExecErrThreshold are the number of consecutive evaluations with no data
+	// or with an error after which NoDataState and ExecErrState apply. Zero and one apply them at once.
+	NoDataThreshold  int
+	ExecErrThreshold int
+	// DisableDashboardAnnotations stops writing the state changes of the alerts to the dashboard
+	// panel that the rule is linked to.
+	DisableDashboardAnnotations bool
 }
 
 // AlertRuleKey is the alert definition identifier
@@ -114,13 +133,19 @@ type AlertRuleVersion struct {
 	Condition       string
 	Data            []AlertQuery
 	IntervalSeconds int64
+	OffsetSeconds   int64
 	NoDataState     NoDataState
 	ExecErrState    ExecutionErrorState
 	// ideally this field should have been apimodels.ApiDuration
 	// but this is currently not possible because of circular dependencies
-	For         time.Duration
-	Annotations map[string]string
-	Labels      map[string]string
+	For              time.Duration
+	Annotations      map[string]string
+	Labels           map[string]string
+	IsPaused         bool
+	NoDataThreshold  int
+	ExecErrThreshold int
+
+	DisableDashboardAnnotations bool
 }
 
 // GetAlertRuleByUIDQuery is the query for retrieving/deleting an alert rule by UID and organisation ID.
@@ -131,6 +156,27 @@ type GetAlertRuleByUIDQuery struct {
 	Result *AlertRule
 }
 
+// ListAlertRuleVersionsQuery is the query for listing the versions of an alert rule, most recent first.
+type ListAlertRuleVersionsQuery struct {
+	OrgID   int64
+	RuleUID string
+	// Limit is the maximum number of versions, all of them if zero.
+	Limit int
+	// Start is the number of most recent versions to skip.
+	Start int
+
+	Result []*AlertRuleVersion
+}
+
+// GetAlertRuleVersionQuery is the query for retrieving a version of an alert rule.
+type GetAlertRuleVersionQuery struct {
+	OrgID   int64
+	RuleUID string
+	Version int64
+
+	Result *AlertRuleVersion
+}
+
 // ListAlertRulesQuery is the query for listing alert rules
 type ListAlertRulesQuery struct {
 	OrgID         int64
@@ -15,6 +15,14 @@ type AlertInstance struct {
 	CurrentStateSince time.Time
 	CurrentStateEnd   time.Time
 	LastEvalTime      time.Time
+	// AcknowledgedBy and AcknowledgedAt are the login of the user who acknowledged the firing alert
+	// and when, empty when it's not acknowledged.
+	AcknowledgedBy string
+	AcknowledgedAt time.Time
+	// EscalationStep is the number of steps of its escalation chain the firing alert was escalated to.
+	EscalationStep int
+	// AnnotationID is the dashboard annotation of the firing alert, ended when the alert stops firing.
+	AnnotationID int64 `xorm:"annotation_id"`
 }
 
 // InstanceStateType is an enum for instance states.
@@ -52,6 +60,10 @@ type SaveAlertInstanceCommand struct {
 	LastEvalTime      time.Time
 	CurrentStateSince time.Time
 	CurrentStateEnd   time.Time
+	AcknowledgedBy    string
+	AcknowledgedAt    time.Time
+	EscalationStep    int
+	AnnotationID      int64
 }
 
 // GetAlertInstanceQuery is the query for retrieving/deleting an alert definition by ID.
@@ -83,6 +95,10 @@ type ListAlertInstancesQueryResult struct {
 	CurrentStateSince time.Time         `json:"currentStateSince"`
 	CurrentStateEnd   time.Time         `json:"currentStateEnd"`
 	LastEvalTime      time.Time         `json:"lastEvalTime"`
+	AcknowledgedBy    string            `json:"acknowledgedBy"`
+	AcknowledgedAt    time.Time         `json:"acknowledgedAt"`
+	EscalationStep    int               `json:"escalationStep"`
+	AnnotationID      int64             `xorm:"annotation_id" json:"annotationId"`
 }
 
 // ValidateAlertInstance validates that the alert instance contains an alert rule id,
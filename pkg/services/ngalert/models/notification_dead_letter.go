@@ -0,0 +1,58 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// NotificationDeadLetter is a notification that could not be sent after all its attempts.
+type NotificationDeadLetter struct {
+	ID              int64 `xorm:"pk autoincr 'id'"`
+	OrgID           int64 `xorm:"org_id"`
+	Receiver        string
+	IntegrationName string
+	IntegrationType string
+	// Alerts are the labels of the alerts of the notification.
+	Alerts   NotificationDeadLetterAlerts
+	Attempts int
+	Error    string
+	// Epoch is the time of the last attempt in milliseconds.
+	Epoch int64
+}
+
+// Time returns the time of the last attempt.
+func (d NotificationDeadLetter) Time() time.Time {
+	return time.Unix(0, d.Epoch*int64(time.Millisecond))
+}
+
+// NotificationDeadLetterAlerts are the labels of the alerts of a notification.
+type NotificationDeadLetterAlerts []map[string]string
+
+// FromDB loads the alerts stored in the database as json.
+// FromDB is part of the xorm Conversion interface.
+func (a *NotificationDeadLetterAlerts) FromDB(b []byte) error {
+	if len(b) == 0 {
+		*a = NotificationDeadLetterAlerts{}
+		return nil
+	}
+	return json.Unmarshal(b, a)
+}
+
+// ToDB serializes the alerts as json.
+// ToDB is part of the xorm Conversion interface.
+func (a *NotificationDeadLetterAlerts) ToDB() ([]byte, error) {
+	return json.Marshal(a)
+}
+
+// ListNotificationDeadLettersQuery is the query for listing the failed notifications of an
+// organization, most recent first.
+type ListNotificationDeadLettersQuery struct {
+	OrgID    int64
+	Receiver string
+	// From and To are the bounds of the epoch of the notifications in milliseconds, ignored when 0.
+	From  int64
+	To    int64
+	Limit int
+
+	Result []*NotificationDeadLetter
+}
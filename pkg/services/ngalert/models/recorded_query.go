@@ -0,0 +1,62 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrRecordedQueryNotFound is an error for an unknown recorded query.
+	ErrRecordedQueryNotFound = errors.New("could not find recorded query")
+	// ErrRecordedQueryFailedValidation indicates that the recorded query is invalid.
+	ErrRecordedQueryFailedValidation = errors.New("invalid recorded query")
+)
+
+// RecordedQuery is a query that is evaluated periodically, and whose results are written as the
+// samples of a metric, so that the dashboards can query the metric instead of the query itself.
+type RecordedQuery struct {
+	ID    int64  `xorm:"pk autoincr 'id'"`
+	OrgID int64  `xorm:"org_id"`
+	UID   string `xorm:"uid"`
+	// Name is the name of the recorded metric.
+	Name        string
+	Description string
+	Data        []AlertQuery
+	// Target is the RefID of the query or expression whose results are recorded.
+	Target          string
+	IntervalSeconds int64
+	// Labels are added to the labels of the recorded series.
+	Labels  map[string]string
+	Active  bool
+	Updated time.Time
+}
+
+// PreSave sets default values and loads the updated model for each query.
+func (rq *RecordedQuery) PreSave(timeNow func() time.Time) error {
+	for i, q := range rq.Data {
+		if err := q.PreSave(); err != nil {
+			return fmt.Errorf("invalid query %s: %w", q.RefID, err)
+		}
+		rq.Data[i] = q
+	}
+	rq.Updated = timeNow()
+	return nil
+}
+
+// ListRecordedQueriesQuery is the query for listing the recorded queries.
+type ListRecordedQueriesQuery struct {
+	// OrgID is the organization of the recorded queries, all the organizations when 0.
+	OrgID      int64
+	ActiveOnly bool
+
+	Result []*RecordedQuery
+}
+
+// GetRecordedQueryByUIDQuery is the query for retrieving a recorded query by its UID.
+type GetRecordedQueryByUIDQuery struct {
+	OrgID int64
+	UID   string
+
+	Result *RecordedQuery
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AlertStateHistoryEntry is a state transition of an alert instance.
+type AlertStateHistoryEntry struct {
+	ID            int64  `xorm:"pk autoincr 'id'"`
+	OrgID         int64  `xorm:"org_id"`
+	RuleUID       string `xorm:"rule_uid"`
+	Labels        InstanceLabels
+	LabelsHash    string
+	PreviousState InstanceStateType
+	CurrentState  InstanceStateType
+	// Values contains the value of the reduce and math expressions of the evaluation by RefID.
+	Values           AlertStateHistoryValues `xorm:"evaluation_values"`
+	EvaluationString string
+	Error            string `xorm:"evaluation_error"`
+	// Epoch is the time of the evaluation that caused the transition in milliseconds.
+	Epoch int64
+}
+
+// Time returns the time of the evaluation that caused the transition.
+func (e AlertStateHistoryEntry) Time() time.Time {
+	return time.Unix(0, e.Epoch*int64(time.Millisecond))
+}
+
+// AlertStateHistoryValues is the value of the expressions of an evaluation by RefID.
+type AlertStateHistoryValues map[string]*float64
+
+// FromDB loads the values stored in the database as json.
+// FromDB is part of the xorm Conversion interface.
+func (v *AlertStateHistoryValues) FromDB(b []byte) error {
+	if len(b) == 0 {
+		*v = AlertStateHistoryValues{}
+		return nil
+	}
+	return json.Unmarshal(b, v)
+}
+
+// ToDB serializes the values as json.
+// ToDB is part of the xorm Conversion interface.
+func (v *AlertStateHistoryValues) ToDB() ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// ListAlertStateHistoryQuery is the query for listing the state transitions of the alert instances
+// of an organization, most recent first.
+type ListAlertStateHistoryQuery struct {
+	OrgID int64
+	// NamespaceUIDs restricts the transitions to the rules of the given folders when not empty.
+	NamespaceUIDs []string
+	RuleUID       string
+	State         InstanceStateType
+	// From and To are the bounds of the epoch of the transitions in milliseconds, ignored when 0.
+	From  int64
+	To    int64
+	Limit int
+
+	Result []*ListAlertStateHistoryQueryResult
+}
+
+// ListAlertStateHistoryQueryResult is a state transition with the title of its rule.
+type ListAlertStateHistoryQueryResult struct {
+	AlertStateHistoryEntry `xorm:"extends"`
+	RuleTitle              string `xorm:"rule_title"`
+}
@@ -20,8 +20,11 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/api"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/recording"
 	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
+	"github.com/grafana/grafana/pkg/services/ngalert/sender"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/rendering"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/tsdb"
@@ -37,6 +40,8 @@ const (
 	baseIntervalSeconds = 10
 	// default alert definition interval
 	defaultIntervalSeconds int64 = 6 * baseIntervalSeconds
+	// how often the state transitions and the failed notifications older than their maximum age are deleted
+	stateHistoryCleanupInterval = time.Hour
 )
 
 // AlertNG is the service for evaluating the condition of an alert definition.
@@ -51,8 +56,14 @@ type AlertNG struct {
 	DataService     *tsdb.Service                           `inject:""`
 	DataProxy       *datasourceproxy.DatasourceProxyService `inject:""`
 	QuotaService    *quota.QuotaService                     `inject:""`
+	RenderService   rendering.Service                       `inject:""`
 	schedule        schedule.ScheduleService
 	stateManager    *state.Manager
+	historyStore    store.StateHistoryStore
+	deadLetterStore store.NotificationDeadLetterStore
+	ruleStore       store.RuleStore
+	// recorder evaluates the recorded queries, it is nil when their results can't be written
+	recorder *recording.Recorder
 
 	// Alerting notification services
 	Alertmanager *notifier.Alertmanager
@@ -92,25 +103,60 @@ func (ng *AlertNG) Init() error {
 		Notifier:                ng.Alertmanager,
 		Metrics:                 ng.Metrics,
 		AdminConfigPollInterval: ng.Cfg.AdminConfigPollInterval,
+		SenderTLSConfig: sender.TLSConfig{
+			CAFile:             ng.Cfg.ExternalAlertmanagerCAFile,
+			CertFile:           ng.Cfg.ExternalAlertmanagerCertFile,
+			KeyFile:            ng.Cfg.ExternalAlertmanagerKeyFile,
+			InsecureSkipVerify: ng.Cfg.ExternalAlertmanagerTLSSkipVerify,
+		},
+		EvaluationSharding: ng.Cfg.HAEvaluationSharding,
+		PeerStore:          store,
+		PeerTimeout:        ng.Cfg.HAPeerTimeout,
+		EvaluationJitter:   schedule.JitterStrategy(ng.Cfg.EvaluationJitter),
+	}
+	if ng.Cfg.CaptureScreenshots {
+		schedCfg.RenderService = ng.RenderService
 	}
 
-	ng.stateManager = state.NewManager(ng.Log, ng.Metrics, store, store)
+	ng.historyStore = store
+	ng.deadLetterStore = store
+	ng.ruleStore = store
+	ng.stateManager = state.NewManager(ng.Log, ng.Metrics, store, store, store)
 	ng.schedule = schedule.NewScheduler(schedCfg, ng.DataService, ng.Cfg.AppURL, ng.stateManager)
 
+	if ng.Cfg.RecordedQueriesRemoteWriteURL != "" {
+		writer, err := recording.NewRemoteWriter(ng.Cfg.RecordedQueriesRemoteWriteURL, ng.Cfg.RecordedQueriesRemoteWriteUser, ng.Cfg.RecordedQueriesRemoteWritePassword, ng.Cfg.RecordedQueriesRemoteWriteTimeout)
+		if err != nil {
+			return err
+		}
+		ng.recorder = recording.NewRecorder(recording.RecorderCfg{
+			Store:        store,
+			Writer:       writer,
+			Evaluator:    eval.Evaluator{Cfg: ng.Cfg, Log: ng.Log},
+			DataService:  ng.DataService,
+			BaseInterval: baseInterval,
+			Metrics:      ng.Metrics,
+			Logger:       log.New("ngalert.recording"),
+		})
+	}
+
 	api := api.API{
-		Cfg:              ng.Cfg,
-		DatasourceCache:  ng.DatasourceCache,
-		RouteRegister:    ng.RouteRegister,
-		DataService:      ng.DataService,
-		Schedule:         ng.schedule,
-		DataProxy:        ng.DataProxy,
-		QuotaService:     ng.QuotaService,
-		InstanceStore:    store,
-		RuleStore:        store,
-		AlertingStore:    store,
-		AdminConfigStore: store,
-		Alertmanager:     ng.Alertmanager,
-		StateManager:     ng.stateManager,
+		Cfg:                ng.Cfg,
+		DatasourceCache:    ng.DatasourceCache,
+		RouteRegister:      ng.RouteRegister,
+		DataService:        ng.DataService,
+		Schedule:           ng.schedule,
+		DataProxy:          ng.DataProxy,
+		QuotaService:       ng.QuotaService,
+		InstanceStore:      store,
+		HistoryStore:       store,
+		RuleStore:          store,
+		AlertingStore:      store,
+		AdminConfigStore:   store,
+		MigrationStore:     store,
+		RecordedQueryStore: store,
+		Alertmanager:       ng.Alertmanager,
+		StateManager:       ng.stateManager,
 	}
 	api.RegisterAPIEndpoints(ng.Metrics)
 
@@ -120,7 +166,11 @@ func (ng *AlertNG) Init() error {
 // Run starts the scheduler and Alertmanager.
 func (ng *AlertNG) Run(ctx context.Context) error {
 	ng.Log.Debug("ngalert starting")
-	ng.stateManager.Warm()
+	// with evaluation sharding, the scheduler warms the cache with the states of the alert rules
+	// this instance evaluates
+	if !ng.Cfg.HAEvaluationSharding {
+		ng.stateManager.Warm()
+	}
 
 	children, subCtx := errgroup.WithContext(ctx)
 	children.Go(func() error {
@@ -129,9 +179,74 @@ func (ng *AlertNG) Run(ctx context.Context) error {
 	children.Go(func() error {
 		return ng.Alertmanager.Run(subCtx)
 	})
+	if ng.recorder != nil {
+		children.Go(func() error {
+			return ng.recorder.Run(subCtx)
+		})
+	}
+	if ng.Cfg.StateHistoryMaxAge > 0 {
+		children.Go(func() error {
+			return ng.cleanUpStateHistory(subCtx)
+		})
+	}
+	if ng.Cfg.NotificationDeadLetterMaxAge > 0 {
+		children.Go(func() error {
+			return ng.cleanUpNotificationDeadLetters(subCtx)
+		})
+	}
 	return children.Wait()
 }
 
+// cleanUpStateHistory periodically deletes the alert state transitions older than the configured maximum age.
+func (ng *AlertNG) cleanUpStateHistory(ctx context.Context) error {
+	ticker := time.NewTicker(stateHistoryCleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			before := time.Now().Add(-ng.Cfg.StateHistoryMaxAge)
+			deleted, err := ng.historyStore.DeleteAlertStateHistoryBefore(before.UnixNano() / int64(time.Millisecond))
+			if err != nil {
+				ng.Log.Error("failed to delete old alert state transitions", "err", err)
+				continue
+			}
+			ng.Log.Debug("deleted old alert state transitions", "count", deleted)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// cleanUpNotificationDeadLetters periodically deletes the failed notifications older than the configured maximum age.
+func (ng *AlertNG) cleanUpNotificationDeadLetters(ctx context.Context) error {
+	ticker := time.NewTicker(stateHistoryCleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			before := time.Now().Add(-ng.Cfg.NotificationDeadLetterMaxAge)
+			deleted, err := ng.deadLetterStore.DeleteNotificationDeadLettersBefore(before.UnixNano() / int64(time.Millisecond))
+			if err != nil {
+				ng.Log.Error("failed to delete old failed notifications", "err", err)
+				continue
+			}
+			ng.Log.Debug("deleted old failed notifications", "count", deleted)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// GetRuleStore returns the storage of the alert rules, so that they can be provisioned from files.
+func (ng *AlertNG) GetRuleStore() store.RuleStore {
+	return ng.ruleStore
+}
+
+// GetAlertmanager returns the embedded Alertmanager, so that its configuration can be provisioned from files.
+func (ng *AlertNG) GetAlertmanager() *notifier.Alertmanager {
+	return ng.Alertmanager
+}
+
 // IsDisabled returns true if the alerting service is disable for this instance.
 func (ng *AlertNG) IsDisabled() bool {
 	if ng.Cfg == nil {
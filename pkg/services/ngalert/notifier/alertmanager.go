@@ -56,7 +56,8 @@ const (
 {
 	"alertmanager_config": {
 		"route": {
-			"receiver": "grafana-default-email"
+			"receiver": "grafana-default-email",
+			"group_by": ["alertname"]
 		},
 		"receivers": [{
 			"name": "grafana-default-email",
@@ -90,6 +91,11 @@ type Alertmanager struct {
 	marker          types.Marker
 	alerts          *mem.Alerts
 	route           *dispatch.Route
+	// integrations are the integrations of the receivers by name, the escalation chains notify them directly.
+	integrations map[string][]notify.Integration
+	// rateLimiters are the rate limiters of the integrations by receiver and index, they are kept across the
+	// configuration changes.
+	rateLimiters map[string]*rateLimiter
 
 	dispatcher *dispatch.Dispatcher
 	inhibitor  *inhibit.Inhibitor
@@ -108,6 +114,9 @@ type Alertmanager struct {
 	reloadConfigMtx sync.RWMutex
 	config          *apimodels.PostableUserConfig
 	configHash      [16]byte
+
+	// startTime is the uptime of the status of the Alertmanager API.
+	startTime time.Time
 }
 
 func New(cfg *setting.Cfg, store store.AlertingStore, m *metrics.Metrics) (*Alertmanager, error) {
@@ -120,6 +129,7 @@ func New(cfg *setting.Cfg, store store.AlertingStore, m *metrics.Metrics) (*Aler
 		dispatcherMetrics: dispatch.NewDispatcherMetrics(m.Registerer),
 		Store:             store,
 		Metrics:           m,
+		startTime:         time.Now(),
 	}
 
 	am.gokitLogger = gokit_log.NewLogfmtLogger(logging.NewWrapper(am.logger))
@@ -387,10 +397,16 @@ func (am *Alertmanager) applyConfig(cfg *apimodels.PostableUserConfig, rawConfig
 	}
 
 	// Finally, build the integrations map using the receiver configuration and templates.
-	integrationsMap, err := am.buildIntegrationsMap(cfg.AlertmanagerConfig.Receivers, tmpl)
+	rateLimiters := make(map[string]*rateLimiter)
+	integrationsMap, err := am.buildIntegrationsMap(cfg.AlertmanagerConfig.Receivers, cfg.AlertmanagerConfig.RateLimits, rateLimiters, tmpl)
+	if err != nil {
+		return err
+	}
+	muteTimes, err := buildMuteTimeIntervals(cfg.AlertmanagerConfig.MuteTimeIntervals)
 	if err != nil {
 		return err
 	}
+
 	// Now, let's put together our notification pipeline
 	routingStage := make(notify.RoutingStage, len(integrationsMap))
 
@@ -406,11 +422,14 @@ func (am *Alertmanager) applyConfig(cfg *apimodels.PostableUserConfig, rawConfig
 
 	inhibitionStage := notify.NewMuteStage(am.inhibitor)
 	silencingStage := notify.NewMuteStage(am.silencer)
+	timeMuteStage := newTimeMuteStage(muteTimes)
 	for name := range integrationsMap {
 		stage := am.createReceiverStage(name, integrationsMap[name], waitFunc, am.notificationLog)
-		routingStage[name] = notify.MultiStage{silencingStage, inhibitionStage, stage}
+		routingStage[name] = notify.MultiStage{silencingStage, inhibitionStage, timeMuteStage, stage}
 	}
 
+	am.integrations = integrationsMap
+	am.rateLimiters = rateLimiters
 	am.route = dispatch.NewRoute(cfg.AlertmanagerConfig.Route, nil)
 	am.dispatcher = dispatch.NewDispatcher(am.alerts, am.route, routingStage, am.marker, timeoutFunc, am.gokitLogger, am.dispatcherMetrics)
 
@@ -437,10 +456,20 @@ func (am *Alertmanager) WorkingDirPath() string {
 }
 
 // buildIntegrationsMap builds a map of name to the list of Grafana integration notifiers off of a list of receiver config.
-func (am *Alertmanager) buildIntegrationsMap(receivers []*apimodels.PostableApiReceiver, templates *template.Template) (map[string][]notify.Integration, error) {
+// The rate limiters of the integrations of the receivers with a rate limit are added to rateLimiters.
+func (am *Alertmanager) buildIntegrationsMap(receivers []*apimodels.PostableApiReceiver, rateLimits []apimodels.RateLimit, rateLimiters map[string]*rateLimiter, templates *template.Template) (map[string][]notify.Integration, error) {
+	limits := make(map[string]apimodels.RateLimit, len(rateLimits))
+	for _, rl := range rateLimits {
+		limits[rl.Receiver] = rl
+	}
+
 	integrationsMap := make(map[string][]notify.Integration, len(receivers))
 	for _, receiver := range receivers {
-		integrations, err := am.buildReceiverIntegrations(receiver, templates)
+		var limit *apimodels.RateLimit
+		if rl, ok := limits[receiver.Name]; ok {
+			limit = &rl
+		}
+		integrations, err := am.buildReceiverIntegrations(receiver, limit, rateLimiters, templates)
 		if err != nil {
 			return nil, err
 		}
@@ -456,18 +485,77 @@ type NotificationChannel interface {
 }
 
 // buildReceiverIntegrations builds a list of integration notifiers off of a receiver config.
-func (am *Alertmanager) buildReceiverIntegrations(receiver *apimodels.PostableApiReceiver, tmpl *template.Template) ([]notify.Integration, error) {
+func (am *Alertmanager) buildReceiverIntegrations(receiver *apimodels.PostableApiReceiver, limit *apimodels.RateLimit, rateLimiters map[string]*rateLimiter, tmpl *template.Template) ([]notify.Integration, error) {
 	var integrations []notify.Integration
 	for i, r := range receiver.GrafanaManagedReceivers {
 		n, err := am.buildReceiverIntegration(r, tmpl)
 		if err != nil {
 			return nil, err
 		}
+		n = &instrumentedNotifier{NotificationChannel: n, receiver: receiver.Name, integration: r.Type, metrics: am.Metrics}
+		// only the notifiers that send HTTP requests are retried
+		if r.Type != "email" {
+			n = &retryingNotifier{
+				NotificationChannel: n,
+				policy: retryPolicy{
+					maxAttempts: am.Settings.NotificationMaxAttempts,
+					backoff:     am.Settings.NotificationRetryBackoff,
+					timeout:     am.Settings.NotificationTimeout,
+				},
+				store:           am.Store,
+				logger:          am.logger,
+				receiver:        receiver.Name,
+				integrationName: r.Name,
+				integrationType: r.Type,
+			}
+		}
+		// the retries of a notification count as a single notification
+		if limit != nil {
+			n = &rateLimitedNotifier{
+				NotificationChannel: n,
+				limiter:             am.rateLimiter(rateLimiters, fmt.Sprintf("%s/%d", receiver.Name, i), *limit),
+				logger:              am.logger,
+				metrics:             am.Metrics,
+				receiver:            receiver.Name,
+				integration:         r.Type,
+			}
+		}
 		integrations = append(integrations, notify.NewIntegration(n, n, r.Type, i))
 	}
 	return integrations, nil
 }
 
+// rateLimiter returns the rate limiter of the integration with the given key with the limits of its receiver.
+// The rate limiter of the integration in the current configuration is reused, so that the notifications
+// sent before a configuration change still count.
+func (am *Alertmanager) rateLimiter(rateLimiters map[string]*rateLimiter, key string, limit apimodels.RateLimit) *rateLimiter {
+	l, ok := am.rateLimiters[key]
+	if !ok {
+		l = &rateLimiter{}
+	}
+	l.setLimits(limit.MaxPerMinute, limit.MaxPerHour)
+	rateLimiters[key] = l
+	return l
+}
+
+// instrumentedNotifier counts the attempts to send a notification with a notifier, and the
+// failed ones.
+type instrumentedNotifier struct {
+	NotificationChannel
+	receiver    string
+	integration string
+	metrics     *metrics.Metrics
+}
+
+func (n *instrumentedNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	n.metrics.NotificationAttempts.WithLabelValues(n.receiver, n.integration).Inc()
+	retry, err := n.NotificationChannel.Notify(ctx, as...)
+	if err != nil {
+		n.metrics.NotificationFailures.WithLabelValues(n.receiver, n.integration).Inc()
+	}
+	return retry, err
+}
+
 func (am *Alertmanager) buildReceiverIntegration(r *apimodels.PostableGrafanaReceiver, tmpl *template.Template) (NotificationChannel, error) {
 	// secure settings are already encrypted at this point
 	secureSettings := securejsondata.SecureJsonData(make(map[string][]byte, len(r.SecureSettings)))
@@ -530,6 +618,12 @@ func (am *Alertmanager) buildReceiverIntegration(r *apimodels.PostableGrafanaRec
 		n, err = channels.NewOpsgenieNotifier(cfg, tmpl)
 	case "prometheus-alertmanager":
 		n, err = channels.NewAlertmanagerNotifier(cfg, tmpl)
+	case "sns":
+		n, err = channels.NewSNSNotifier(cfg, tmpl)
+	case "matrix":
+		n, err = channels.NewMatrixNotifier(cfg, tmpl)
+	case "sms":
+		n, err = channels.NewSMSNotifier(cfg, tmpl)
 	default:
 		return nil, InvalidReceiverError{
 			Receiver: r,
@@ -553,45 +647,7 @@ func (am *Alertmanager) PutAlerts(postableAlerts apimodels.PostableAlerts) error
 	alerts := make([]*types.Alert, 0, len(postableAlerts.PostableAlerts))
 	var validationErr *AlertValidationError
 	for _, a := range postableAlerts.PostableAlerts {
-		alert := &types.Alert{
-			Alert: model.Alert{
-				Labels:       model.LabelSet{},
-				Annotations:  model.LabelSet{},
-				StartsAt:     time.Time(a.StartsAt),
-				EndsAt:       time.Time(a.EndsAt),
-				GeneratorURL: a.GeneratorURL.String(),
-			},
-			UpdatedAt: now,
-		}
-
-		for k, v := range a.Labels {
-			if len(v) == 0 || k == ngmodels.NamespaceUIDLabel { // Skip empty and namespace UID labels.
-				continue
-			}
-			alert.Alert.Labels[model.LabelName(k)] = model.LabelValue(v)
-		}
-		for k, v := range a.Annotations {
-			if len(v) == 0 { // Skip empty annotation.
-				continue
-			}
-			alert.Alert.Annotations[model.LabelName(k)] = model.LabelValue(v)
-		}
-
-		// Ensure StartsAt is set.
-		if alert.StartsAt.IsZero() {
-			if alert.EndsAt.IsZero() {
-				alert.StartsAt = now
-			} else {
-				alert.StartsAt = alert.EndsAt
-			}
-		}
-		// If no end time is defined, set a timeout after which an alert
-		// is marked resolved if it is not updated.
-		if alert.EndsAt.IsZero() {
-			alert.Timeout = true
-			alert.EndsAt = now.Add(defaultResolveTimeout)
-		}
-
+		alert := alertFromPostable(a, now)
 		if alert.EndsAt.After(now) {
 			am.Metrics.Firing().Inc()
 		} else {
@@ -622,6 +678,49 @@ func (am *Alertmanager) PutAlerts(postableAlerts apimodels.PostableAlerts) error
 	return nil
 }
 
+// alertFromPostable returns the Alertmanager alert of a postable alert, received at now.
+func alertFromPostable(a amv2.PostableAlert, now time.Time) *types.Alert {
+	alert := &types.Alert{
+		Alert: model.Alert{
+			Labels:       model.LabelSet{},
+			Annotations:  model.LabelSet{},
+			StartsAt:     time.Time(a.StartsAt),
+			EndsAt:       time.Time(a.EndsAt),
+			GeneratorURL: a.GeneratorURL.String(),
+		},
+		UpdatedAt: now,
+	}
+
+	for k, v := range a.Labels {
+		if len(v) == 0 || k == ngmodels.NamespaceUIDLabel { // Skip empty and namespace UID labels.
+			continue
+		}
+		alert.Alert.Labels[model.LabelName(k)] = model.LabelValue(v)
+	}
+	for k, v := range a.Annotations {
+		if len(v) == 0 { // Skip empty annotation.
+			continue
+		}
+		alert.Alert.Annotations[model.LabelName(k)] = model.LabelValue(v)
+	}
+
+	// Ensure StartsAt is set.
+	if alert.StartsAt.IsZero() {
+		if alert.EndsAt.IsZero() {
+			alert.StartsAt = now
+		} else {
+			alert.StartsAt = alert.EndsAt
+		}
+	}
+	// If no end time is defined, set a timeout after which an alert
+	// is marked resolved if it is not updated.
+	if alert.EndsAt.IsZero() {
+		alert.Timeout = true
+		alert.EndsAt = now.Add(defaultResolveTimeout)
+	}
+	return alert
+}
+
 // validateAlert is a.Validate() while additionally allowing
 // space for label and annotation names.
 func validateAlert(a *types.Alert) error {
@@ -2,14 +2,19 @@ package notifier
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"sort"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/infra/log"
+	gfmodels "github.com/grafana/grafana/pkg/models"
 
 	gokit_log "github.com/go-kit/kit/log"
 	"github.com/go-openapi/strfmt"
@@ -17,6 +22,7 @@ import (
 	"github.com/prometheus/alertmanager/provider/mem"
 	"github.com/prometheus/alertmanager/types"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 
@@ -340,3 +346,105 @@ func TestPutAlert(t *testing.T) {
 		})
 	}
 }
+
+func TestAlertmanager_GroupsNotifications(t *testing.T) {
+	am := setupAMTest(t)
+
+	cfg, err := Load([]byte(`{
+		"alertmanager_config": {
+			"route": {
+				"receiver": "webhook",
+				"group_by": ["alertname"],
+				"group_wait": "1s",
+				"group_interval": "2s"
+			},
+			"receivers": [{"name": "webhook", "grafana_managed_receiver_configs": [{"uid": "", "name": "webhook", "type": "webhook", "settings": {"url": "http://localhost/webhook"}}]}]
+		}
+	}`))
+	require.NoError(t, err)
+	require.NoError(t, am.SaveAndApplyConfig(1, cfg))
+
+	var (
+		mtx           sync.Mutex
+		notifications = map[string][]int{}
+	)
+	bus.AddHandlerCtx("test", func(ctx context.Context, cmd *gfmodels.SendWebhookSync) error {
+		var body struct {
+			CommonLabels map[string]string `json:"commonLabels"`
+			Alerts       []interface{}     `json:"alerts"`
+		}
+		if err := json.Unmarshal([]byte(cmd.Body), &body); err != nil {
+			return err
+		}
+		mtx.Lock()
+		defer mtx.Unlock()
+		alertname := body.CommonLabels["alertname"]
+		notifications[alertname] = append(notifications[alertname], len(body.Alerts))
+		return nil
+	})
+	getNotifications := func() map[string][]int {
+		mtx.Lock()
+		defer mtx.Unlock()
+		result := make(map[string][]int, len(notifications))
+		for k, v := range notifications {
+			result[k] = append([]int(nil), v...)
+		}
+		return result
+	}
+
+	newAlerts := func(alertname string, from, to int) []models.PostableAlert {
+		alerts := make([]models.PostableAlert, 0, to-from)
+		for i := from; i < to; i++ {
+			alerts = append(alerts, models.PostableAlert{
+				Alert: models.Alert{Labels: models.LabelSet{"alertname": alertname, "instance": fmt.Sprintf("instance-%d", i)}},
+			})
+		}
+		return alerts
+	}
+
+	// a burst of alerts of the same alert rule is sent in a single notification
+	require.NoError(t, am.PutAlerts(apimodels.PostableAlerts{PostableAlerts: append(newAlerts("DiskFull", 0, 100), newAlerts("HighCPU", 0, 1)...)}))
+	require.Eventually(t, func() bool {
+		return len(getNotifications()) == 2
+	}, 5*time.Second, 100*time.Millisecond)
+	require.Equal(t, map[string][]int{"DiskFull": {100}, "HighCPU": {1}}, getNotifications())
+
+	// the alerts that are sent again are not notified again before the repeat interval
+	require.NoError(t, am.PutAlerts(apimodels.PostableAlerts{PostableAlerts: newAlerts("DiskFull", 0, 100)}))
+	time.Sleep(3 * time.Second)
+	require.Equal(t, map[string][]int{"DiskFull": {100}, "HighCPU": {1}}, getNotifications())
+
+	// a new alert in the group is notified with the others at the next group interval
+	require.NoError(t, am.PutAlerts(apimodels.PostableAlerts{PostableAlerts: newAlerts("DiskFull", 100, 101)}))
+	require.Eventually(t, func() bool {
+		return len(getNotifications()["DiskFull"]) == 2
+	}, 5*time.Second, 100*time.Millisecond)
+	require.Equal(t, map[string][]int{"DiskFull": {100, 101}, "HighCPU": {1}}, getNotifications())
+}
+
+func TestInstrumentedNotifier(t *testing.T) {
+	m := metrics.NewMetrics(prometheus.NewRegistry())
+	fake := &fakeNotificationChannel{}
+	n := &instrumentedNotifier{NotificationChannel: fake, receiver: "ops", integration: "webhook", metrics: m}
+
+	_, err := n.Notify(context.Background())
+	require.NoError(t, err)
+	fake.err = errors.New("unavailable")
+	_, err = n.Notify(context.Background())
+	require.EqualError(t, err, "unavailable")
+
+	require.Equal(t, 2.0, testutil.ToFloat64(m.NotificationAttempts.WithLabelValues("ops", "webhook")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.NotificationFailures.WithLabelValues("ops", "webhook")))
+}
+
+type fakeNotificationChannel struct {
+	err error
+}
+
+func (n *fakeNotificationChannel) Notify(_ context.Context, _ ...*types.Alert) (bool, error) {
+	return n.err != nil, n.err
+}
+
+func (n *fakeNotificationChannel) SendResolved() bool {
+	return true
+}
@@ -143,17 +143,69 @@ func GetAvailableNotifiers() []*alerting.NotifierPlugin {
 		},
 		{
 			Type:        "kafka",
-			Name:        "Kafka REST Proxy",
-			Description: "Sends notifications to Kafka Rest Proxy",
+			Name:        "Kafka",
+			Description: "Sends notifications to Kafka through a Kafka REST Proxy or the Kafka protocol",
 			Heading:     "Kafka settings",
 			Options: []alerting.NotifierOption{
+				{
+					Label:   "Transport",
+					Element: alerting.ElementTypeSelect,
+					SelectOptions: []alerting.SelectOption{
+						{
+							Value: channels.KafkaTransportREST,
+							Label: "REST Proxy",
+						},
+						{
+							Value: channels.KafkaTransportNative,
+							Label: "Native",
+						},
+					},
+					Description:  "Publish the events through a Kafka REST Proxy, or directly to the brokers",
+					PropertyName: "kafkaTransport",
+				},
 				{
 					Label:        "Kafka REST Proxy",
 					Element:      alerting.ElementTypeInput,
 					InputType:    alerting.InputTypeText,
 					Placeholder:  "http://localhost:8082",
+					Description:  "Required with the REST Proxy transport",
 					PropertyName: "kafkaRestProxy",
-					Required:     true,
+				},
+				{
+					Label:        "Brokers",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					Placeholder:  "broker1:9092,broker2:9092",
+					Description:  "Comma-separated list of the addresses of the brokers",
+					PropertyName: "kafkaBrokers",
+					ShowWhen: alerting.ShowWhen{
+						Field: "kafkaTransport",
+						Is:    channels.KafkaTransportNative,
+					},
+				},
+				{
+					Label:        "TLS",
+					Element:      alerting.ElementTypeCheckbox,
+					Description:  "Connect to the brokers with TLS",
+					PropertyName: "kafkaTLS",
+					ShowWhen: alerting.ShowWhen{
+						Field: "kafkaTransport",
+						Is:    channels.KafkaTransportNative,
+					},
+				},
+				{
+					Label:        "Username",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					Description:  "Basic authentication of the REST Proxy, or SASL/PLAIN authentication of the brokers",
+					PropertyName: "kafkaUsername",
+				},
+				{
+					Label:        "Password",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypePassword,
+					PropertyName: "kafkaPassword",
+					Secure:       true,
 				},
 				{
 					Label:        "Topic",
@@ -163,6 +215,33 @@ func GetAvailableNotifiers() []*alerting.NotifierPlugin {
 					PropertyName: "kafkaTopic",
 					Required:     true,
 				},
+				{
+					Label:   "Format",
+					Element: alerting.ElementTypeSelect,
+					SelectOptions: []alerting.SelectOption{
+						{
+							Value: channels.KafkaFormatJSON,
+							Label: "JSON",
+						},
+						{
+							Value: channels.KafkaFormatAvro,
+							Label: "Avro",
+						},
+					},
+					PropertyName: "kafkaFormat",
+				},
+				{
+					Label:        "Schema registry",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					Placeholder:  "http://localhost:8081",
+					Description:  "The schema registry where the Avro schema of the events is registered, required with the native transport",
+					PropertyName: "kafkaSchemaRegistry",
+					ShowWhen: alerting.ShowWhen{
+						Field: "kafkaFormat",
+						Is:    channels.KafkaFormatAvro,
+					},
+				},
 			},
 		},
 		{
@@ -184,12 +263,26 @@ func GetAvailableNotifiers() []*alerting.NotifierPlugin {
 					PropertyName: "addresses",
 					Required:     true,
 				},
+				{
+					Label:        "Subject",
+					Description:  "Templated subject of the email",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					PropertyName: "subject",
+					Placeholder:  `{{ template "default.title" . }}`,
+				},
 				{ // New in 8.0.
 					Label:        "Message",
 					Description:  "Optional message to include with the email. You can use template variables",
 					Element:      alerting.ElementTypeTextArea,
 					PropertyName: "message",
 				},
+				{
+					Label:        "Embed images",
+					Description:  "Embed the images of the panels in the email, even when they are uploaded to an external image storage, for the mail clients that block remote content",
+					Element:      alerting.ElementTypeCheckbox,
+					PropertyName: "embedImages",
+				},
 			},
 		},
 		{
@@ -274,10 +367,12 @@ func GetAvailableNotifiers() []*alerting.NotifierPlugin {
 					Placeholder:  "VictorOps url",
 					PropertyName: "url",
 					Required:     true,
+					Secure:       true,
 				},
 				{ // New in 8.0.
 					Label:        "Message Type",
 					Element:      alerting.ElementTypeSelect,
+					Description:  "Leave empty to set the message type from the severity label of the alerts, CRITICAL by default.",
 					PropertyName: "messageType",
 					SelectOptions: []alerting.SelectOption{
 						{
@@ -287,6 +382,10 @@ func GetAvailableNotifiers() []*alerting.NotifierPlugin {
 							Value: "WARNING",
 							Label: "WARNING",
 						},
+						{
+							Value: "INFO",
+							Label: "INFO",
+						},
 					},
 				},
 			},
@@ -612,6 +711,10 @@ func GetAvailableNotifiers() []*alerting.NotifierPlugin {
 							Value: "PUT",
 							Label: "PUT",
 						},
+						{
+							Value: "PATCH",
+							Label: "PATCH",
+						},
 					},
 					PropertyName: "httpMethod",
 				},
@@ -635,6 +738,66 @@ func GetAvailableNotifiers() []*alerting.NotifierPlugin {
 					InputType:    alerting.InputTypeText,
 					PropertyName: "maxAlerts",
 				},
+				{
+					Label:        "Title",
+					Description:  "Templated title field of the webhook payload",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					PropertyName: "title",
+					Placeholder:  `{{ template "default.title" . }}`,
+				},
+				{
+					Label:        "Message",
+					Description:  "Templated message field of the webhook payload",
+					Element:      alerting.ElementTypeTextArea,
+					PropertyName: "message",
+					Placeholder:  `{{ template "default.message" . }}`,
+				},
+				{
+					Label:        "Payload",
+					Description:  "Templated body of the request, which replaces the webhook payload. The title and message are not sent when it's set.",
+					Element:      alerting.ElementTypeTextArea,
+					PropertyName: "payload",
+				},
+				{
+					Label:        "Content Type",
+					Description:  "Content type of the body of the request",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					PropertyName: "contentType",
+					Placeholder:  "application/json",
+				},
+				{
+					Label:        "HTTP Headers",
+					Description:  "Additional headers of the request, one \"Name: value\" header per line. The values are templated.",
+					Element:      alerting.ElementTypeTextArea,
+					PropertyName: "httpHeaders",
+				},
+				{
+					Label:        "TLS CA Certificate",
+					Description:  "PEM encoded certificates of the CA of the receiver, instead of the CAs of the system",
+					Element:      alerting.ElementTypeTextArea,
+					PropertyName: "tlsCACert",
+				},
+				{
+					Label:        "TLS Client Certificate",
+					Description:  "PEM encoded client certificate, for the receivers that require mutual TLS",
+					Element:      alerting.ElementTypeTextArea,
+					PropertyName: "tlsClientCert",
+				},
+				{
+					Label:        "TLS Client Key",
+					Description:  "PEM encoded key of the client certificate",
+					Element:      alerting.ElementTypeTextArea,
+					PropertyName: "tlsClientKey",
+					Secure:       true,
+				},
+				{
+					Label:        "Skip TLS Verify",
+					Description:  "Don't verify the certificate of the receiver",
+					Element:      alerting.ElementTypeCheckbox,
+					PropertyName: "tlsSkipVerify",
+				},
 			},
 		},
 		{
@@ -798,7 +961,7 @@ func GetAvailableNotifiers() []*alerting.NotifierPlugin {
 				}, {
 					Label:        "Override priority",
 					Element:      alerting.ElementTypeCheckbox,
-					Description:  "Allow the alert priority to be set using the og_priority annotation",
+					Description:  "Allow the alert priority to be set using the og_priority annotation or label, or else the severity label",
 					PropertyName: "overridePriority",
 				},
 				{
@@ -823,5 +986,188 @@ func GetAvailableNotifiers() []*alerting.NotifierPlugin {
 				},
 			},
 		},
+		{
+			Type:        "matrix",
+			Name:        "Matrix",
+			Description: "Sends HTML-formatted notifications to a Matrix room",
+			Heading:     "Matrix settings",
+			Options: []alerting.NotifierOption{
+				{
+					Label:        "Homeserver URL",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					Placeholder:  "https://matrix.org",
+					PropertyName: "homeserverUrl",
+					Required:     true,
+				},
+				{
+					Label:        "Access token",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypePassword,
+					Description:  "The access token of the user that sends the notifications, who must have joined the room",
+					PropertyName: "accessToken",
+					Required:     true,
+					Secure:       true,
+				},
+				{
+					Label:        "Room ID",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					Placeholder:  "!abcdefghijklmnop:matrix.org",
+					Description:  "The internal ID of the room, in its advanced settings",
+					PropertyName: "roomId",
+					Required:     true,
+				},
+				{
+					Label:        "Message",
+					Element:      alerting.ElementTypeTextArea,
+					Placeholder:  `{{ template "default.message" . }}`,
+					PropertyName: "message",
+				},
+			},
+		},
+		{
+			Type:        "sns",
+			Name:        "AWS SNS",
+			Description: "Publishes notifications to an AWS SNS topic",
+			Heading:     "AWS SNS settings",
+			Options: []alerting.NotifierOption{
+				{
+					Label:        "Topic ARN",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					Placeholder:  "arn:aws:sns:us-east-1:123456789012:alerts",
+					Description:  "Topics whose name ends with .fifo are FIFO topics",
+					PropertyName: "topicArn",
+					Required:     true,
+				},
+				{
+					Label:        "Region",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					Description:  "Defaults to the region of the topic",
+					PropertyName: "region",
+				},
+				{
+					Label:        "Endpoint",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					Description:  "Custom endpoint of the SNS API, such as a VPC endpoint",
+					PropertyName: "endpoint",
+				},
+				{
+					Label:   "Authentication provider",
+					Element: alerting.ElementTypeSelect,
+					SelectOptions: []alerting.SelectOption{
+						{
+							Value: channels.SNSAuthProviderDefault,
+							Label: "AWS SDK default",
+						},
+						{
+							Value: channels.SNSAuthProviderKeys,
+							Label: "Access and secret key",
+						},
+					},
+					Description:  "The AWS SDK default uses the environment, the shared credentials file or the IAM role of the instance",
+					PropertyName: "authProvider",
+				},
+				{
+					Label:        "Access key",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					PropertyName: "accessKey",
+					ShowWhen: alerting.ShowWhen{
+						Field: "authProvider",
+						Is:    channels.SNSAuthProviderKeys,
+					},
+					Secure: true,
+				},
+				{
+					Label:        "Secret key",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypePassword,
+					PropertyName: "secretKey",
+					ShowWhen: alerting.ShowWhen{
+						Field: "authProvider",
+						Is:    channels.SNSAuthProviderKeys,
+					},
+					Secure: true,
+				},
+				{
+					Label:        "Assume role ARN",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					Description:  "The ARN of an IAM role to assume to publish the notifications",
+					PropertyName: "assumeRoleArn",
+				},
+				{
+					Label:        "External ID",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					Description:  "The external ID required by the trust policy of the assumed role",
+					PropertyName: "externalId",
+				},
+				{
+					Label:        "Subject",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					Placeholder:  `{{ template "default.title" . }}`,
+					Description:  "Used by the email subscriptions, truncated to 100 characters",
+					PropertyName: "subject",
+				},
+				{
+					Label:        "Message",
+					Element:      alerting.ElementTypeTextArea,
+					Placeholder:  `{{ template "default.message" . }}`,
+					PropertyName: "message",
+				},
+				{
+					Label:   "Message format",
+					Element: alerting.ElementTypeSelect,
+					SelectOptions: []alerting.SelectOption{
+						{
+							Value: channels.SNSMessageFormatText,
+							Label: "Text",
+						},
+						{
+							Value: channels.SNSMessageFormatJSON,
+							Label: "JSON",
+						},
+					},
+					Description:  "JSON publishes the payload of the webhook contact point, with the message in its message field",
+					PropertyName: "messageFormat",
+				},
+				{
+					Label:        "Message group ID",
+					Element:      alerting.ElementTypeInput,
+					InputType:    alerting.InputTypeText,
+					Description:  "The message group of FIFO topics, defaults to a hash of the alert group",
+					PropertyName: "messageGroupId",
+				},
+			},
+		},
+		{
+			Type:        "sms",
+			Name:        "SMS",
+			Description: "Sends text messages using the Grafana server configured SMS provider",
+			Heading:     "SMS settings",
+			Options: []alerting.NotifierOption{
+				{
+					Label:        "Phone numbers",
+					Element:      alerting.ElementTypeTextArea,
+					Placeholder:  "+15551234567",
+					Description:  "Phone numbers in the international format. You can enter multiple phone numbers using a \";\" separator",
+					PropertyName: "phoneNumbers",
+					Required:     true,
+				},
+				{
+					Label:        "Message",
+					Element:      alerting.ElementTypeTextArea,
+					Placeholder:  `{{ template "default.title" . }}`,
+					Description:  "Templated text message, truncated to 1600 characters",
+					PropertyName: "message",
+				},
+			},
+		},
 	}
 }
@@ -3,7 +3,9 @@ package channels
 import (
 	"context"
 	"net/url"
+	"os"
 	"path"
+	"path/filepath"
 
 	"github.com/prometheus/alertmanager/template"
 	"github.com/prometheus/alertmanager/types"
@@ -21,7 +23,10 @@ type EmailNotifier struct {
 	old_notifiers.NotifierBase
 	Addresses   []string
 	SingleEmail bool
+	Subject     string
 	Message     string
+	// EmbedImages embeds the images of the panels even when they are in an external image storage.
+	EmbedImages bool
 	log         log.Logger
 	tmpl        *template.Template
 }
@@ -53,7 +58,9 @@ func NewEmailNotifier(model *NotificationChannelConfig, t *template.Template) (*
 		}),
 		Addresses:   addresses,
 		SingleEmail: singleEmail,
+		Subject:     model.Settings.Get("subject").MustString(`{{ template "default.title" . }}`),
 		Message:     model.Settings.Get("message").MustString(),
+		EmbedImages: model.Settings.Get("embedImages").MustBool(false),
 		log:         log.New("alerting.notifier.email"),
 		tmpl:        t,
 	}, nil
@@ -64,7 +71,7 @@ func (en *EmailNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool,
 	var tmplErr error
 	tmpl, data := TmplText(ctx, en.tmpl, as, en.log, &tmplErr)
 
-	title := tmpl(`{{ template "default.title" . }}`)
+	title := tmpl(en.Subject)
 
 	alertPageURL := en.tmpl.ExternalURL.String()
 	ruleURL := en.tmpl.ExternalURL.String()
@@ -79,6 +86,26 @@ func (en *EmailNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool,
 		en.log.Debug("failed to parse external URL", "url", en.tmpl.ExternalURL.String(), "err", err.Error())
 	}
 
+	// embed the images of the panels as inline parts referenced by their content ID, unless
+	// they are in an external image storage and are not required to be embedded
+	embeddedFiles := []string{}
+	embedded := map[string]bool{}
+	for i, alert := range data.Alerts {
+		if (alert.ImageURL != "" && !en.EmbedImages) || alert.imagePath == "" {
+			continue
+		}
+		if _, err := os.Stat(alert.imagePath); err != nil {
+			en.log.Debug("failed to find the image of the alert", "path", alert.imagePath, "err", err.Error())
+			continue
+		}
+		name := filepath.Base(alert.imagePath)
+		data.Alerts[i].ImageURL = "cid:" + name
+		if !embedded[alert.imagePath] {
+			embedded[alert.imagePath] = true
+			embeddedFiles = append(embeddedFiles, alert.imagePath)
+		}
+	}
+
 	cmd := &models.SendEmailCommandSync{
 		SendEmailCommand: models.SendEmailCommand{
 			Subject: title,
@@ -94,9 +121,10 @@ func (en *EmailNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool,
 				"RuleUrl":           ruleURL,
 				"AlertPageUrl":      alertPageURL,
 			},
-			To:          en.Addresses,
-			SingleEmail: en.SingleEmail,
-			Template:    "ng_alert_notification",
+			To:            en.Addresses,
+			SingleEmail:   en.SingleEmail,
+			Template:      "ng_alert_notification",
+			EmbeddedFiles: embeddedFiles,
 		},
 	}
 
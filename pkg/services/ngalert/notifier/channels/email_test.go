@@ -2,7 +2,9 @@ package channels
 
 import (
 	"context"
+	"io/ioutil"
 	"net/url"
+	"path/filepath"
 	"testing"
 
 	"github.com/prometheus/alertmanager/template"
@@ -105,4 +107,118 @@ func TestEmailNotifier(t *testing.T) {
 			},
 		}, expected)
 	})
+	t.Run("the subject of the email is templated", func(t *testing.T) {
+		settingsJSON, err := simplejson.NewJson([]byte(`{
+			"addresses": "someops@example.com",
+			"subject": "{{ len .Alerts.Firing }} firing alert(s) for {{ .CommonLabels.alertname }}"
+		}`))
+		require.NoError(t, err)
+
+		emailNotifier, err := NewEmailNotifier(&NotificationChannelConfig{
+			Name:     "ops",
+			Type:     "email",
+			Settings: settingsJSON,
+		}, tmpl)
+		require.NoError(t, err)
+
+		var cmd *models.SendEmailCommandSync
+		bus.AddHandlerCtx("test", func(ctx context.Context, c *models.SendEmailCommandSync) error {
+			cmd = c
+			return nil
+		})
+
+		ok, err := emailNotifier.Notify(context.Background(), &types.Alert{
+			Alert: model.Alert{
+				Labels: model.LabelSet{"alertname": "AlwaysFiring", "severity": "warning"},
+			},
+		})
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		require.Equal(t, "1 firing alert(s) for AlwaysFiring", cmd.Subject)
+		require.Equal(t, "1 firing alert(s) for AlwaysFiring", cmd.Data["Title"])
+	})
+	t.Run("the images of the panels are linked or embedded", func(t *testing.T) {
+		settingsJSON, err := simplejson.NewJson([]byte(`{"addresses": "someops@example.com"}`))
+		require.NoError(t, err)
+
+		emailNotifier, err := NewEmailNotifier(&NotificationChannelConfig{
+			Name:     "ops",
+			Type:     "email",
+			Settings: settingsJSON,
+		}, tmpl)
+		require.NoError(t, err)
+
+		var cmd *models.SendEmailCommandSync
+		bus.AddHandlerCtx("test", func(ctx context.Context, c *models.SendEmailCommandSync) error {
+			cmd = c
+			return nil
+		})
+
+		imagePath := filepath.Join(t.TempDir(), "panel.png")
+		require.NoError(t, ioutil.WriteFile(imagePath, []byte("png"), 0600))
+
+		ok, err := emailNotifier.Notify(context.Background(),
+			&types.Alert{Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "Uploaded"},
+				Annotations: model.LabelSet{"__image_url__": "https://images.example.com/panel.png", "__image_path__": model.LabelValue(imagePath)},
+			}},
+			&types.Alert{Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "Embedded"},
+				Annotations: model.LabelSet{"__image_path__": model.LabelValue(imagePath)},
+			}},
+			&types.Alert{Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "Missing"},
+				Annotations: model.LabelSet{"__image_path__": "/does/not/exist.png"},
+			}},
+		)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		alerts := cmd.Data["Alerts"].(ExtendedAlerts)
+		require.Len(t, alerts, 3)
+		require.Equal(t, "https://images.example.com/panel.png", alerts[0].ImageURL)
+		require.Equal(t, "cid:panel.png", alerts[1].ImageURL)
+		require.Empty(t, alerts[2].ImageURL)
+		require.Equal(t, []string{imagePath}, cmd.EmbeddedFiles)
+	})
+	t.Run("the images of the panels are embedded when embedImages is set", func(t *testing.T) {
+		settingsJSON, err := simplejson.NewJson([]byte(`{"addresses": "someops@example.com", "embedImages": true}`))
+		require.NoError(t, err)
+
+		emailNotifier, err := NewEmailNotifier(&NotificationChannelConfig{
+			Name:     "ops",
+			Type:     "email",
+			Settings: settingsJSON,
+		}, tmpl)
+		require.NoError(t, err)
+
+		var cmd *models.SendEmailCommandSync
+		bus.AddHandlerCtx("test", func(ctx context.Context, c *models.SendEmailCommandSync) error {
+			cmd = c
+			return nil
+		})
+
+		imagePath := filepath.Join(t.TempDir(), "panel.png")
+		require.NoError(t, ioutil.WriteFile(imagePath, []byte("png"), 0600))
+
+		ok, err := emailNotifier.Notify(context.Background(),
+			&types.Alert{Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "Uploaded"},
+				Annotations: model.LabelSet{"__image_url__": "https://images.example.com/panel.png", "__image_path__": model.LabelValue(imagePath)},
+			}},
+			&types.Alert{Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "OnlyUploaded"},
+				Annotations: model.LabelSet{"__image_url__": "https://images.example.com/other.png"},
+			}},
+		)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		alerts := cmd.Data["Alerts"].(ExtendedAlerts)
+		require.Len(t, alerts, 2)
+		require.Equal(t, "cid:panel.png", alerts[0].ImageURL)
+		require.Equal(t, "https://images.example.com/other.png", alerts[1].ImageURL)
+		require.Equal(t, []string{imagePath}, cmd.EmbeddedFiles)
+	})
 }
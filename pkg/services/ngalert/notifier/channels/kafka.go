@@ -1,42 +1,134 @@
 package channels
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/Shopify/sarama"
+	"github.com/linkedin/goavro/v2"
 	"github.com/prometheus/alertmanager/notify"
 	"github.com/prometheus/alertmanager/template"
 	"github.com/prometheus/alertmanager/types"
 	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/bus"
-	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
 	old_notifiers "github.com/grafana/grafana/pkg/services/alerting/notifiers"
 )
 
+const (
+	// KafkaTransportREST publishes the events through a Kafka REST Proxy.
+	KafkaTransportREST = "rest"
+	// KafkaTransportNative publishes the events to the Kafka brokers with the Kafka protocol.
+	KafkaTransportNative = "native"
+
+	// KafkaFormatJSON publishes the events as JSON.
+	KafkaFormatJSON = "json"
+	// KafkaFormatAvro publishes the events as Avro, with the schema registered in a schema registry.
+	KafkaFormatAvro = "avro"
+)
+
+// kafkaAvroSchema is the Avro schema of the events, registered under the <topic>-value subject.
+const kafkaAvroSchema = `{
+	"type": "record",
+	"name": "AlertEvent",
+	"namespace": "grafana.alerting",
+	"fields": [
+		{"name": "alert_state", "type": "string"},
+		{"name": "description", "type": "string"},
+		{"name": "client", "type": "string"},
+		{"name": "details", "type": "string"},
+		{"name": "client_url", "type": "string"},
+		{"name": "incident_key", "type": "string"},
+		{"name": "labels", "type": {"type": "map", "values": "string"}}
+	]
+}`
+
+// kafkaProducer is the part of the Kafka producer used by the notifier.
+type kafkaProducer interface {
+	SendMessage(msg *sarama.ProducerMessage) (partition int32, offset int64, err error)
+	Close() error
+}
+
 // KafkaNotifier is responsible for sending
 // alert notifications to Kafka.
 type KafkaNotifier struct {
 	old_notifiers.NotifierBase
-	Endpoint string
-	Topic    string
-	log      log.Logger
-	tmpl     *template.Template
+	Transport      string
+	Endpoint       string
+	Brokers        []string
+	Topic          string
+	Format         string
+	SchemaRegistry string
+	TLS            bool
+	Username       string
+	Password       string
+	log            log.Logger
+	tmpl           *template.Template
+
+	codec *goavro.Codec
+	// the IDs of the schema in the schema registry, by subject
+	schemaIDsMtx sync.Mutex
+	schemaIDs    map[string]int
 }
 
 // NewKafkaNotifier is the constructor function for the Kafka notifier.
 func NewKafkaNotifier(model *NotificationChannelConfig, t *template.Template) (*KafkaNotifier, error) {
+	transport := model.Settings.Get("kafkaTransport").MustString(KafkaTransportREST)
 	endpoint := model.Settings.Get("kafkaRestProxy").MustString()
-	if endpoint == "" {
-		return nil, receiverInitError{Cfg: *model, Reason: "could not find kafka rest proxy endpoint property in settings"}
+	var brokers []string
+	switch transport {
+	case KafkaTransportREST:
+		if endpoint == "" {
+			return nil, receiverInitError{Cfg: *model, Reason: "could not find kafka rest proxy endpoint property in settings"}
+		}
+	case KafkaTransportNative:
+		for _, b := range strings.Split(model.Settings.Get("kafkaBrokers").MustString(), ",") {
+			if b = strings.TrimSpace(b); b != "" {
+				brokers = append(brokers, b)
+			}
+		}
+		if len(brokers) == 0 {
+			return nil, receiverInitError{Cfg: *model, Reason: "could not find kafka brokers property in settings"}
+		}
+	default:
+		return nil, receiverInitError{Cfg: *model, Reason: fmt.Sprintf("invalid kafka transport %q", transport)}
 	}
+
 	topic := model.Settings.Get("kafkaTopic").MustString()
 	if topic == "" {
 		return nil, receiverInitError{Cfg: *model, Reason: "could not find kafka topic property in settings"}
 	}
 
+	format := model.Settings.Get("kafkaFormat").MustString(KafkaFormatJSON)
+	schemaRegistry := model.Settings.Get("kafkaSchemaRegistry").MustString()
+	var codec *goavro.Codec
+	switch format {
+	case KafkaFormatJSON:
+	case KafkaFormatAvro:
+		// the REST Proxy registers the schema itself
+		if transport == KafkaTransportNative && schemaRegistry == "" {
+			return nil, receiverInitError{Cfg: *model, Reason: "could not find kafka schema registry property in settings"}
+		}
+		var err error
+		if codec, err = goavro.NewCodec(kafkaAvroSchema); err != nil {
+			return nil, receiverInitError{Cfg: *model, Reason: "invalid avro schema", Err: err}
+		}
+	default:
+		return nil, receiverInitError{Cfg: *model, Reason: fmt.Sprintf("invalid kafka format %q", format)}
+	}
+
 	return &KafkaNotifier{
 		NotifierBase: old_notifiers.NewNotifierBase(&models.AlertNotification{
 			Uid:                   model.UID,
@@ -45,10 +137,19 @@ func NewKafkaNotifier(model *NotificationChannelConfig, t *template.Template) (*
 			DisableResolveMessage: model.DisableResolveMessage,
 			Settings:              model.Settings,
 		}),
-		Endpoint: endpoint,
-		Topic:    topic,
-		log:      log.New("alerting.notifier.kafka"),
-		tmpl:     t,
+		Transport:      transport,
+		Endpoint:       endpoint,
+		Brokers:        brokers,
+		Topic:          topic,
+		Format:         format,
+		SchemaRegistry: schemaRegistry,
+		TLS:            model.Settings.Get("kafkaTLS").MustBool(false),
+		Username:       model.Settings.Get("kafkaUsername").MustString(),
+		Password:       model.DecryptedValue("kafkaPassword", model.Settings.Get("kafkaPassword").MustString()),
+		log:            log.New("alerting.notifier.kafka"),
+		tmpl:           t,
+		codec:          codec,
+		schemaIDs:      map[string]int{},
 	}, nil
 }
 
@@ -62,49 +163,66 @@ func (kn *KafkaNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool,
 		state = models.AlertStateOK
 	}
 
-	kn.log.Debug("Notifying Kafka", "alert_state", state)
+	kn.log.Debug("Notifying Kafka", "alert_state", state, "transport", kn.Transport, "format", kn.Format)
 
 	var tmplErr error
-	tmpl, _ := TmplText(ctx, kn.tmpl, as, kn.log, &tmplErr)
-
-	bodyJSON := simplejson.New()
-	bodyJSON.Set("alert_state", state)
-	bodyJSON.Set("description", tmpl(`{{ template "default.title" . }}`))
-	bodyJSON.Set("client", "Grafana")
-	bodyJSON.Set("details", tmpl(`{{ template "default.message" . }}`))
-
-	ruleURL := joinUrlPath(kn.tmpl.ExternalURL.String(), "/alerting/list", kn.log)
-	bodyJSON.Set("client_url", ruleURL)
+	tmpl, data := TmplText(ctx, kn.tmpl, as, kn.log, &tmplErr)
 
 	groupKey, err := notify.ExtractGroupKey(ctx)
 	if err != nil {
 		return false, err
 	}
-	bodyJSON.Set("incident_key", groupKey.Hash())
 
-	valueJSON := simplejson.New()
-	valueJSON.Set("value", bodyJSON)
+	labels := make(map[string]interface{}, len(data.CommonLabels))
+	for k, v := range data.CommonLabels {
+		labels[k] = v
+	}
+	event := map[string]interface{}{
+		"alert_state":  string(state),
+		"description":  tmpl(`{{ template "default.title" . }}`),
+		"client":       "Grafana",
+		"details":      tmpl(`{{ template "default.message" . }}`),
+		"client_url":   joinUrlPath(kn.tmpl.ExternalURL.String(), "/alerting/list", kn.log),
+		"incident_key": groupKey.Hash(),
+		"labels":       labels,
+	}
+	topic := tmpl(kn.Topic)
 
-	recordJSON := simplejson.New()
-	recordJSON.Set("records", []interface{}{valueJSON})
+	if tmplErr != nil {
+		kn.log.Debug("failed to template Kafka message", "err", tmplErr.Error())
+	}
 
-	body, err := recordJSON.MarshalJSON()
+	if kn.Transport == KafkaTransportNative {
+		if err := kn.produce(ctx, topic, event); err != nil {
+			kn.log.Error("Failed to send notification to Kafka", "error", err, "topic", topic)
+			return false, err
+		}
+		return true, nil
+	}
+
+	records := map[string]interface{}{
+		"records": []interface{}{map[string]interface{}{"value": event}},
+	}
+	contentType := "application/vnd.kafka.json.v2+json"
+	if kn.Format == KafkaFormatAvro {
+		records["value_schema"] = kafkaAvroSchema
+		contentType = "application/vnd.kafka.avro.v2+json"
+	}
+	body, err := json.Marshal(records)
 	if err != nil {
 		return false, err
 	}
 
-	topicURL := strings.TrimRight(kn.Endpoint, "/") + "/topics/" + tmpl(kn.Topic)
-
-	if tmplErr != nil {
-		kn.log.Debug("failed to template Kafka message", "err", tmplErr.Error())
-	}
+	topicURL := strings.TrimRight(kn.Endpoint, "/") + "/topics/" + topic
 
 	cmd := &models.SendWebhookSync{
 		Url:        topicURL,
+		User:       kn.Username,
+		Password:   kn.Password,
 		Body:       string(body),
 		HttpMethod: "POST",
 		HttpHeader: map[string]string{
-			"Content-Type": "application/vnd.kafka.json.v2+json",
+			"Content-Type": contentType,
 			"Accept":       "application/vnd.kafka.v2+json",
 		},
 	}
@@ -117,6 +235,116 @@ func (kn *KafkaNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool,
 	return true, nil
 }
 
+// produce publishes the event to the topic with the Kafka protocol, keyed by the incident key so
+// that the events of an alert group stay in order on the same partition.
+func (kn *KafkaNotifier) produce(ctx context.Context, topic string, event map[string]interface{}) error {
+	var value []byte
+	if kn.Format == KafkaFormatAvro {
+		id, err := kn.schemaID(ctx, topic+"-value")
+		if err != nil {
+			return err
+		}
+		// the wire format of the schema registry: a zero magic byte and the ID of the schema
+		value = make([]byte, 5, 256)
+		binary.BigEndian.PutUint32(value[1:], uint32(id))
+		if value, err = kn.codec.BinaryFromNative(value, event); err != nil {
+			return fmt.Errorf("failed to encode avro event: %w", err)
+		}
+	} else {
+		var err error
+		if value, err = json.Marshal(event); err != nil {
+			return err
+		}
+	}
+
+	producer, err := newKafkaProducer(kn)
+	if err != nil {
+		return fmt.Errorf("failed to connect to Kafka: %w", err)
+	}
+	defer func() {
+		if err := producer.Close(); err != nil {
+			kn.log.Warn("Failed to close Kafka producer", "err", err)
+		}
+	}()
+
+	_, _, err = producer.SendMessage(&sarama.ProducerMessage{
+		Topic: topic,
+		Key:   sarama.StringEncoder(event["incident_key"].(string)),
+		Value: sarama.ByteEncoder(value),
+	})
+	return err
+}
+
+// newKafkaProducer connects a producer to the brokers of the notifier.
+// Stubbable by tests.
+var newKafkaProducer = func(kn *KafkaNotifier) (kafkaProducer, error) {
+	cfg := sarama.NewConfig()
+	cfg.ClientID = "grafana"
+	cfg.Producer.RequiredAcks = sarama.WaitForAll
+	cfg.Producer.Return.Successes = true
+	cfg.Net.DialTimeout = 10 * time.Second
+	if kn.TLS {
+		cfg.Net.TLS.Enable = true
+		cfg.Net.TLS.Config = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	if kn.Username != "" {
+		cfg.Net.SASL.Enable = true
+		cfg.Net.SASL.Mechanism = sarama.SASLTypePlaintext
+		cfg.Net.SASL.User = kn.Username
+		cfg.Net.SASL.Password = kn.Password
+	}
+	return sarama.NewSyncProducer(kn.Brokers, cfg)
+}
+
+// schemaID returns the ID of the Avro schema of the events under the subject, registering it in
+// the schema registry on the first event. Registering a schema that is already registered returns
+// its ID.
+func (kn *KafkaNotifier) schemaID(ctx context.Context, subject string) (int, error) {
+	kn.schemaIDsMtx.Lock()
+	defer kn.schemaIDsMtx.Unlock()
+	if id, ok := kn.schemaIDs[subject]; ok {
+		return id, nil
+	}
+
+	body, err := json.Marshal(map[string]string{"schema": kafkaAvroSchema})
+	if err != nil {
+		return 0, err
+	}
+	u := strings.TrimRight(kn.SchemaRegistry, "/") + "/subjects/" + url.PathEscape(subject) + "/versions"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to register avro schema: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			kn.log.Warn("Failed to close response body", "err", err)
+		}
+	}()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode/100 != 2 {
+		return 0, fmt.Errorf("failed to register avro schema: schema registry responded with status code %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var registered struct {
+		ID int `json:"id"`
+	}
+	if err := json.Unmarshal(respBody, &registered); err != nil {
+		return 0, fmt.Errorf("failed to parse the response of the schema registry: %w", err)
+	}
+	kn.schemaIDs[subject] = registered.ID
+	return registered.ID, nil
+}
+
 func (kn *KafkaNotifier) SendResolved() bool {
 	return !kn.GetDisableResolveMessage()
 }
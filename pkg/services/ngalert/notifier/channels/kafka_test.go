@@ -2,15 +2,21 @@ package channels
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 
+	"github.com/Shopify/sarama"
+	"github.com/linkedin/goavro/v2"
 	"github.com/prometheus/alertmanager/notify"
 	"github.com/prometheus/alertmanager/types"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/securejsondata"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/models"
 )
@@ -54,7 +60,8 @@ func TestKafkaNotifier(t *testing.T) {
 						"client_url": "http://localhost/alerting/list",
 						"description": "[FIRING:1]  (val1)",
 						"details": "**Firing**\n\nLabels:\n - alertname = alert1\n - lbl1 = val1\nAnnotations:\n - ann1 = annv1\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Clbl1%3Dval1\nDashboard: http://localhost/d/abcd\nPanel: http://localhost/d/abcd?viewPanel=efgh\n",
-						"incident_key": "6e3538104c14b583da237e9693b76debbc17f0f8058ef20492e5853096cf8733"
+						"incident_key": "6e3538104c14b583da237e9693b76debbc17f0f8058ef20492e5853096cf8733",
+						"labels": {"alertname": "alert1", "lbl1": "val1"}
 					  }
 					}
 				  ]
@@ -89,7 +96,8 @@ func TestKafkaNotifier(t *testing.T) {
 						"client_url": "http://localhost/alerting/list",
 						"description": "[FIRING:2]  ",
 						"details": "**Firing**\n\nLabels:\n - alertname = alert1\n - lbl1 = val1\nAnnotations:\n - ann1 = annv1\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Clbl1%3Dval1\n\nLabels:\n - alertname = alert1\n - lbl1 = val2\nAnnotations:\n - ann1 = annv2\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Clbl1%3Dval2\n",
-						"incident_key": "6e3538104c14b583da237e9693b76debbc17f0f8058ef20492e5853096cf8733"
+						"incident_key": "6e3538104c14b583da237e9693b76debbc17f0f8058ef20492e5853096cf8733",
+						"labels": {"alertname": "alert1"}
 					  }
 					}
 				  ]
@@ -103,6 +111,22 @@ func TestKafkaNotifier(t *testing.T) {
 			name:         "Topic missing",
 			settings:     `{"kafkaRestProxy": "http://localhost"}`,
 			expInitError: `failed to validate receiver "kafka_testing" of type "kafka": could not find kafka topic property in settings`,
+		}, {
+			name:         "Invalid transport",
+			settings:     `{"kafkaTransport": "grpc", "kafkaTopic": "sometopic"}`,
+			expInitError: `failed to validate receiver "kafka_testing" of type "kafka": invalid kafka transport "grpc"`,
+		}, {
+			name:         "Brokers missing",
+			settings:     `{"kafkaTransport": "native", "kafkaBrokers": " , ", "kafkaTopic": "sometopic"}`,
+			expInitError: `failed to validate receiver "kafka_testing" of type "kafka": could not find kafka brokers property in settings`,
+		}, {
+			name:         "Invalid format",
+			settings:     `{"kafkaRestProxy": "http://localhost", "kafkaTopic": "sometopic", "kafkaFormat": "protobuf"}`,
+			expInitError: `failed to validate receiver "kafka_testing" of type "kafka": invalid kafka format "protobuf"`,
+		}, {
+			name:         "Schema registry missing",
+			settings:     `{"kafkaTransport": "native", "kafkaBrokers": "localhost:9092", "kafkaTopic": "sometopic", "kafkaFormat": "avro"}`,
+			expInitError: `failed to validate receiver "kafka_testing" of type "kafka": could not find kafka schema registry property in settings`,
 		},
 	}
 
@@ -150,3 +174,185 @@ func TestKafkaNotifier(t *testing.T) {
 		})
 	}
 }
+
+func TestKafkaNotifierAvroRESTProxy(t *testing.T) {
+	tmpl := templateForTests(t)
+
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	settingsJSON, err := simplejson.NewJson([]byte(`{
+		"kafkaRestProxy": "http://localhost/",
+		"kafkaTopic": "alerts-{{ .CommonLabels.team }}",
+		"kafkaFormat": "avro",
+		"kafkaUsername": "user"
+	}`))
+	require.NoError(t, err)
+
+	pn, err := NewKafkaNotifier(&NotificationChannelConfig{
+		Name:           "kafka_testing",
+		Type:           "kafka",
+		Settings:       settingsJSON,
+		SecureSettings: securejsondata.GetEncryptedJsonData(map[string]string{"kafkaPassword": "pass"}),
+	}, tmpl)
+	require.NoError(t, err)
+
+	var webhook *models.SendWebhookSync
+	bus.AddHandlerCtx("test", func(ctx context.Context, cmd *models.SendWebhookSync) error {
+		webhook = cmd
+		return nil
+	})
+
+	ctx := notify.WithGroupKey(context.Background(), "alertname")
+	ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": ""})
+	ok, err := pn.Notify(ctx, &types.Alert{
+		Alert: model.Alert{
+			Labels: model.LabelSet{"alertname": "alert1", "team": "ops"},
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	require.Equal(t, "http://localhost/topics/alerts-ops", webhook.Url)
+	require.Equal(t, "application/vnd.kafka.avro.v2+json", webhook.HttpHeader["Content-Type"])
+	require.Equal(t, "user", webhook.User)
+	require.Equal(t, "pass", webhook.Password)
+
+	body, err := simplejson.NewJson([]byte(webhook.Body))
+	require.NoError(t, err)
+	require.Equal(t, kafkaAvroSchema, body.Get("value_schema").MustString())
+
+	// the REST Proxy encodes the records with the schema
+	codec, err := goavro.NewCodec(kafkaAvroSchema)
+	require.NoError(t, err)
+	value, err := body.Get("records").GetIndex(0).Get("value").MarshalJSON()
+	require.NoError(t, err)
+	_, _, err = codec.NativeFromTextual(value)
+	require.NoError(t, err)
+}
+
+type fakeKafkaProducer struct {
+	msgs   []*sarama.ProducerMessage
+	closed bool
+}
+
+func (f *fakeKafkaProducer) SendMessage(msg *sarama.ProducerMessage) (int32, int64, error) {
+	f.msgs = append(f.msgs, msg)
+	return 0, 0, nil
+}
+
+func (f *fakeKafkaProducer) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestKafkaNotifierNative(t *testing.T) {
+	tmpl := templateForTests(t)
+
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	registrations := 0
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		registrations++
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/subjects/alerts-value/versions", r.URL.Path)
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, kafkaAvroSchema, body["schema"])
+		_, err := w.Write([]byte(`{"id": 42}`))
+		require.NoError(t, err)
+	}))
+	t.Cleanup(registry.Close)
+
+	cases := []struct {
+		name     string
+		settings string
+		expValue func(t *testing.T, value []byte)
+	}{
+		{
+			name:     "JSON",
+			settings: `{"kafkaTransport": "native", "kafkaBrokers": "broker1:9092, broker2:9092", "kafkaTopic": "alerts"}`,
+			expValue: func(t *testing.T, value []byte) {
+				require.JSONEq(t, `{
+					"alert_state": "alerting",
+					"client": "Grafana",
+					"client_url": "http://localhost/alerting/list",
+					"description": "[FIRING:1]  (val1)",
+					"details": "**Firing**\n\nLabels:\n - alertname = alert1\n - lbl1 = val1\nAnnotations:\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Clbl1%3Dval1\n",
+					"incident_key": "6e3538104c14b583da237e9693b76debbc17f0f8058ef20492e5853096cf8733",
+					"labels": {"alertname": "alert1", "lbl1": "val1"}
+				}`, string(value))
+			},
+		}, {
+			name:     "Avro",
+			settings: `{"kafkaTransport": "native", "kafkaBrokers": "broker1:9092, broker2:9092", "kafkaTopic": "alerts", "kafkaFormat": "avro", "kafkaSchemaRegistry": "` + registry.URL + `"}`,
+			expValue: func(t *testing.T, value []byte) {
+				require.Equal(t, []byte{0, 0, 0, 0, 42}, value[:5])
+				codec, err := goavro.NewCodec(kafkaAvroSchema)
+				require.NoError(t, err)
+				native, _, err := codec.NativeFromBinary(value[5:])
+				require.NoError(t, err)
+				event := native.(map[string]interface{})
+				require.Equal(t, "alerting", event["alert_state"])
+				require.Equal(t, "6e3538104c14b583da237e9693b76debbc17f0f8058ef20492e5853096cf8733", event["incident_key"])
+				require.Equal(t, map[string]interface{}{"alertname": "alert1", "lbl1": "val1"}, event["labels"])
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			settingsJSON, err := simplejson.NewJson([]byte(c.settings))
+			require.NoError(t, err)
+
+			pn, err := NewKafkaNotifier(&NotificationChannelConfig{
+				Name:     "kafka_testing",
+				Type:     "kafka",
+				Settings: settingsJSON,
+			}, tmpl)
+			require.NoError(t, err)
+
+			producer := &fakeKafkaProducer{}
+			origNewKafkaProducer := newKafkaProducer
+			t.Cleanup(func() {
+				newKafkaProducer = origNewKafkaProducer
+			})
+			newKafkaProducer = func(kn *KafkaNotifier) (kafkaProducer, error) {
+				require.Equal(t, []string{"broker1:9092", "broker2:9092"}, kn.Brokers)
+				return producer, nil
+			}
+
+			registrations = 0
+			ctx := notify.WithGroupKey(context.Background(), "alertname")
+			ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": ""})
+			for i := 0; i < 2; i++ {
+				ok, err := pn.Notify(ctx, &types.Alert{
+					Alert: model.Alert{
+						Labels: model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+					},
+				})
+				require.NoError(t, err)
+				require.True(t, ok)
+			}
+			require.True(t, producer.closed)
+			require.Len(t, producer.msgs, 2)
+
+			msg := producer.msgs[0]
+			require.Equal(t, "alerts", msg.Topic)
+			key, err := msg.Key.Encode()
+			require.NoError(t, err)
+			require.Equal(t, "6e3538104c14b583da237e9693b76debbc17f0f8058ef20492e5853096cf8733", string(key))
+			value, err := msg.Value.Encode()
+			require.NoError(t, err)
+			c.expValue(t, value)
+
+			// the schema is only registered once
+			if pn.Format == KafkaFormatAvro {
+				require.Equal(t, 1, registrations)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	old_notifiers "github.com/grafana/grafana/pkg/services/alerting/notifiers"
+)
+
+// MatrixNotifier is responsible for sending
+// alert notifications to a Matrix room.
+type MatrixNotifier struct {
+	old_notifiers.NotifierBase
+	HomeserverURL string
+	AccessToken   string
+	RoomID        string
+	Message       string
+	log           log.Logger
+	tmpl          *template.Template
+}
+
+// matrixMessage is the content of an m.room.message event.
+type matrixMessage struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format"`
+	FormattedBody string `json:"formatted_body"`
+}
+
+// NewMatrixNotifier is the constructor for the Matrix notifier.
+func NewMatrixNotifier(model *NotificationChannelConfig, t *template.Template) (*MatrixNotifier, error) {
+	if model.Settings == nil {
+		return nil, receiverInitError{Cfg: *model, Reason: "no settings supplied"}
+	}
+
+	homeserverURL := model.Settings.Get("homeserverUrl").MustString()
+	if homeserverURL == "" {
+		return nil, receiverInitError{Cfg: *model, Reason: "could not find homeserver url property in settings"}
+	}
+	if _, err := url.Parse(homeserverURL); err != nil {
+		return nil, receiverInitError{Cfg: *model, Reason: "invalid homeserver url", Err: err}
+	}
+	accessToken := model.DecryptedValue("accessToken", model.Settings.Get("accessToken").MustString())
+	if accessToken == "" {
+		return nil, receiverInitError{Cfg: *model, Reason: "could not find access token in settings"}
+	}
+	roomID := model.Settings.Get("roomId").MustString()
+	if roomID == "" {
+		return nil, receiverInitError{Cfg: *model, Reason: "could not find room id in settings"}
+	}
+
+	return &MatrixNotifier{
+		NotifierBase: old_notifiers.NewNotifierBase(&models.AlertNotification{
+			Uid:                   model.UID,
+			Name:                  model.Name,
+			Type:                  model.Type,
+			DisableResolveMessage: model.DisableResolveMessage,
+			Settings:              model.Settings,
+			SecureSettings:        model.SecureSettings,
+		}),
+		HomeserverURL: homeserverURL,
+		AccessToken:   accessToken,
+		RoomID:        roomID,
+		Message:       model.Settings.Get("message").MustString(`{{ template "default.message" . }}`),
+		log:           log.New("alerting.notifier.matrix"),
+		tmpl:          t,
+	}, nil
+}
+
+// Notify sends the alert notification to the Matrix room, as a message with an HTML body.
+func (mn *MatrixNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	alerts := types.Alerts(as...)
+
+	var tmplErr error
+	tmpl, _ := TmplText(ctx, mn.tmpl, as, mn.log, &tmplErr)
+
+	title := tmpl(`{{ template "default.title" . }}`)
+	message := tmpl(mn.Message)
+	ruleURL := joinUrlPath(mn.tmpl.ExternalURL.String(), "/alerting/list", mn.log)
+	roomID := tmpl(mn.RoomID)
+
+	if tmplErr != nil {
+		mn.log.Debug("failed to template Matrix message", "err", tmplErr.Error())
+	}
+
+	msg := matrixMessage{
+		MsgType: "m.text",
+		Body:    fmt.Sprintf("%s\n%s\n\n%s", title, ruleURL, message),
+		Format:  "org.matrix.custom.html",
+		FormattedBody: fmt.Sprintf(`<h4><font data-mx-color="%s">%s</font></h4><p><a href="%s">View alert rules</a></p><p>%s</p>`,
+			getAlertStatusColor(alerts.Status()),
+			html.EscapeString(title),
+			html.EscapeString(ruleURL),
+			strings.ReplaceAll(html.EscapeString(strings.TrimSpace(message)), "\n", "<br>"),
+		),
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return false, err
+	}
+
+	groupKey, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return false, err
+	}
+	// the transaction ID must be new for each message, as the homeserver ignores the messages sent
+	// again with a transaction ID it has already seen
+	txnID := fmt.Sprintf("grafana-%s-%d", groupKey.Hash()[:16], time.Now().UnixNano())
+	u := fmt.Sprintf("%s/_matrix/client/r0/rooms/%s/send/m.room.message/%s",
+		strings.TrimRight(mn.HomeserverURL, "/"), url.PathEscape(roomID), url.PathEscape(txnID))
+
+	cmd := &models.SendWebhookSync{
+		Url:        u,
+		Body:       string(body),
+		HttpMethod: "PUT",
+		HttpHeader: map[string]string{
+			"Content-Type":  "application/json",
+			"Authorization": "Bearer " + mn.AccessToken,
+		},
+	}
+
+	if err := bus.DispatchCtx(ctx, cmd); err != nil {
+		mn.log.Error("Failed to send notification to Matrix", "error", err, "room", roomID)
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (mn *MatrixNotifier) SendResolved() bool {
+	return !mn.GetDisableResolveMessage()
+}
@@ -0,0 +1,131 @@
+package channels
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/securejsondata"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+func TestMatrixNotifier(t *testing.T) {
+	tmpl := templateForTests(t)
+
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	cases := []struct {
+		name           string
+		settings       string
+		secureSettings map[string]string
+		alerts         []*types.Alert
+		expURLPrefix   string
+		expMsg         string
+		expInitError   string
+	}{
+		{
+			name:           "One firing alert",
+			settings:       `{"homeserverUrl": "https://matrix.example.com/", "roomId": "!abc:example.com"}`,
+			secureSettings: map[string]string{"accessToken": "token"},
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels:      model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+						Annotations: model.LabelSet{"ann1": "annv1"},
+					},
+				},
+			},
+			expURLPrefix: "https://matrix.example.com/_matrix/client/r0/rooms/%21abc:example.com/send/m.room.message/grafana-6e3538104c14b583-",
+			expMsg: `{
+			  "msgtype": "m.text",
+			  "body": "[FIRING:1]  (val1)\nhttp://localhost/alerting/list\n\n**Firing**\n\nLabels:\n - alertname = alert1\n - lbl1 = val1\nAnnotations:\n - ann1 = annv1\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Clbl1%3Dval1\n",
+			  "format": "org.matrix.custom.html",
+			  "formatted_body": "<h4><font data-mx-color=\"#D63232\">[FIRING:1]  (val1)</font></h4><p><a href=\"http://localhost/alerting/list\">View alert rules</a></p><p>**Firing**<br><br>Labels:<br> - alertname = alert1<br> - lbl1 = val1<br>Annotations:<br> - ann1 = annv1<br>Silence: http://localhost/alerting/silence/new?alertmanager=grafana&amp;matchers=alertname%3Dalert1%2Clbl1%3Dval1</p>"
+			}`,
+		}, {
+			name:     "Resolved alert with a custom message",
+			settings: `{"homeserverUrl": "https://matrix.example.com", "roomId": "!abc:example.com", "accessToken": "token", "message": "<b>{{ .Status }}</b>"}`,
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels: model.LabelSet{"alertname": "alert1"},
+						EndsAt: time.Now().Add(-1 * time.Minute),
+					},
+				},
+			},
+			expURLPrefix: "https://matrix.example.com/_matrix/client/r0/rooms/%21abc:example.com/send/m.room.message/grafana-",
+			expMsg: `{
+			  "msgtype": "m.text",
+			  "body": "[RESOLVED]  \nhttp://localhost/alerting/list\n\n<b>resolved</b>",
+			  "format": "org.matrix.custom.html",
+			  "formatted_body": "<h4><font data-mx-color=\"#36a64f\">[RESOLVED]  </font></h4><p><a href=\"http://localhost/alerting/list\">View alert rules</a></p><p>&lt;b&gt;resolved&lt;/b&gt;</p>"
+			}`,
+		}, {
+			name:         "Error in initing, no homeserver URL",
+			settings:     `{"roomId": "!abc:example.com", "accessToken": "token"}`,
+			expInitError: `failed to validate receiver "matrix_testing" of type "matrix": could not find homeserver url property in settings`,
+		}, {
+			name:         "Error in initing, no access token",
+			settings:     `{"homeserverUrl": "https://matrix.example.com", "roomId": "!abc:example.com"}`,
+			expInitError: `failed to validate receiver "matrix_testing" of type "matrix": could not find access token in settings`,
+		}, {
+			name:         "Error in initing, no room ID",
+			settings:     `{"homeserverUrl": "https://matrix.example.com", "accessToken": "token"}`,
+			expInitError: `failed to validate receiver "matrix_testing" of type "matrix": could not find room id in settings`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			settingsJSON, err := simplejson.NewJson([]byte(c.settings))
+			require.NoError(t, err)
+
+			m := &NotificationChannelConfig{
+				Name:           "matrix_testing",
+				Type:           "matrix",
+				Settings:       settingsJSON,
+				SecureSettings: securejsondata.GetEncryptedJsonData(c.secureSettings),
+			}
+
+			pn, err := NewMatrixNotifier(m, tmpl)
+			if c.expInitError != "" {
+				require.Error(t, err)
+				require.Equal(t, c.expInitError, err.Error())
+				return
+			}
+			require.NoError(t, err)
+
+			var webhooks []*models.SendWebhookSync
+			bus.AddHandlerCtx("test", func(ctx context.Context, webhook *models.SendWebhookSync) error {
+				webhooks = append(webhooks, webhook)
+				return nil
+			})
+
+			ctx := notify.WithGroupKey(context.Background(), "alertname")
+			ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": ""})
+			for i := 0; i < 2; i++ {
+				ok, err := pn.Notify(ctx, c.alerts...)
+				require.NoError(t, err)
+				require.True(t, ok)
+			}
+
+			require.Len(t, webhooks, 2)
+			require.Equal(t, "PUT", webhooks[0].HttpMethod)
+			require.Equal(t, "Bearer token", webhooks[0].HttpHeader["Authorization"])
+			require.Contains(t, webhooks[0].Url, c.expURLPrefix)
+			require.JSONEq(t, c.expMsg, webhooks[0].Body)
+			// each message has its own transaction ID
+			require.NotEqual(t, webhooks[0].Url, webhooks[1].Url)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"sort"
+	"strings"
 
 	"github.com/prometheus/alertmanager/notify"
 	"github.com/prometheus/alertmanager/template"
@@ -28,6 +29,16 @@ const (
 var (
 	OpsgenieAlertURL = "https://api.opsgenie.com/v2/alerts"
 	ValidPriorities  = map[string]bool{"P1": true, "P2": true, "P3": true, "P4": true, "P5": true}
+
+	// opsgeniePriorityBySeverity maps the usual values of the severity label to the priorities of Opsgenie.
+	opsgeniePriorityBySeverity = map[string]string{
+		"critical": "P1",
+		"high":     "P2",
+		"error":    "P2",
+		"warning":  "P3",
+		"low":      "P4",
+		"info":     "P5",
+	}
 )
 
 // OpsgenieNotifier is responsible for sending alert notifications to Opsgenie.
@@ -160,18 +171,10 @@ func (on *OpsgenieNotifier) buildOpsgenieMessage(ctx context.Context, alerts mod
 		tmpl(`{{ template "default.message" . }}`),
 	)
 
-	var priority string
-
 	// In the new alerting system we've moved away from the grafana-tags. Instead, annotations on the rule itself should be used.
 	lbls := make(map[string]string, len(data.CommonLabels))
 	for k, v := range data.CommonLabels {
 		lbls[k] = tmpl(v)
-
-		if k == "og_priority" {
-			if ValidPriorities[v] {
-				priority = v
-			}
-		}
 	}
 
 	bodyJSON.Set("message", title)
@@ -194,8 +197,10 @@ func (on *OpsgenieNotifier) buildOpsgenieMessage(ctx context.Context, alerts mod
 	}
 	sort.Strings(tags)
 
-	if priority != "" && on.OverridePriority {
-		bodyJSON.Set("priority", priority)
+	if on.OverridePriority {
+		if priority := opsgeniePriority(data); priority != "" {
+			bodyJSON.Set("priority", priority)
+		}
 	}
 
 	bodyJSON.Set("tags", tags)
@@ -209,6 +214,19 @@ func (on *OpsgenieNotifier) buildOpsgenieMessage(ctx context.Context, alerts mod
 	return bodyJSON, apiURL, nil
 }
 
+// opsgeniePriority returns the priority of the alerts from the og_priority annotation or label they
+// have in common, or else from their common severity label. Opsgenie uses its default priority
+// if it's empty.
+func opsgeniePriority(data *ExtendedData) string {
+	if p := data.CommonAnnotations["og_priority"]; ValidPriorities[p] {
+		return p
+	}
+	if p := data.CommonLabels["og_priority"]; ValidPriorities[p] {
+		return p
+	}
+	return opsgeniePriorityBySeverity[strings.ToLower(data.CommonLabels["severity"])]
+}
+
 func (on *OpsgenieNotifier) SendResolved() bool {
 	return !on.GetDisableResolveMessage()
 }
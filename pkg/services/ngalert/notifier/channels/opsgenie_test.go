@@ -137,6 +137,92 @@ func TestOpsgenieNotifier(t *testing.T) {
 			}`,
 			expMsgError: nil,
 		},
+		{
+			name:     "Priority from the og_priority annotation",
+			settings: `{"apiKey": "abcdefgh0123456789", "sendTagsAs": "details"}`,
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels:      model.LabelSet{"alertname": "alert1", "severity": "critical"},
+						Annotations: model.LabelSet{"og_priority": "P2"},
+					},
+				},
+			},
+			expMsg: `{
+				"alias": "6e3538104c14b583da237e9693b76debbc17f0f8058ef20492e5853096cf8733",
+				"description": "[FIRING:1]  (critical)\nhttp://localhost/alerting/list\n\n**Firing**\n\nLabels:\n - alertname = alert1\n - severity = critical\nAnnotations:\n - og_priority = P2\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Cseverity%3Dcritical\n",
+				"details": {
+					"alertname": "alert1",
+					"severity": "critical",
+					"url": "http://localhost/alerting/list"
+				},
+				"message": "[FIRING:1]  (critical)",
+				"priority": "P2",
+				"source": "Grafana",
+				"tags": []
+			}`,
+		},
+		{
+			name:     "Priority from the severity label",
+			settings: `{"apiKey": "abcdefgh0123456789", "sendTagsAs": "details"}`,
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels: model.LabelSet{"alertname": "alert1", "severity": "Warning"},
+					},
+				},
+			},
+			expMsg: `{
+				"alias": "6e3538104c14b583da237e9693b76debbc17f0f8058ef20492e5853096cf8733",
+				"description": "[FIRING:1]  (Warning)\nhttp://localhost/alerting/list\n\n**Firing**\n\nLabels:\n - alertname = alert1\n - severity = Warning\nAnnotations:\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Cseverity%3DWarning\n",
+				"details": {
+					"alertname": "alert1",
+					"severity": "Warning",
+					"url": "http://localhost/alerting/list"
+				},
+				"message": "[FIRING:1]  (Warning)",
+				"priority": "P3",
+				"source": "Grafana",
+				"tags": []
+			}`,
+		},
+		{
+			name:     "Priority is not set when override priority is false",
+			settings: `{"apiKey": "abcdefgh0123456789", "sendTagsAs": "details", "overridePriority": false}`,
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels: model.LabelSet{"alertname": "alert1", "og_priority": "P1"},
+					},
+				},
+			},
+			expMsg: `{
+				"alias": "6e3538104c14b583da237e9693b76debbc17f0f8058ef20492e5853096cf8733",
+				"description": "[FIRING:1]  (P1)\nhttp://localhost/alerting/list\n\n**Firing**\n\nLabels:\n - alertname = alert1\n - og_priority = P1\nAnnotations:\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Cog_priority%3DP1\n",
+				"details": {
+					"alertname": "alert1",
+					"og_priority": "P1",
+					"url": "http://localhost/alerting/list"
+				},
+				"message": "[FIRING:1]  (P1)",
+				"source": "Grafana",
+				"tags": []
+			}`,
+		},
+		{
+			name:     "Resolved closes the alert when auto close is true",
+			settings: `{"apiKey": "abcdefgh0123456789"}`,
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels:      model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+						Annotations: model.LabelSet{"ann1": "annv1"},
+						EndsAt:      time.Now().Add(-1 * time.Minute),
+					},
+				},
+			},
+			expMsg: `{"source": "Grafana"}`,
+		},
 		{
 			name:     "Resolved is not sent when auto close is false",
 			settings: `{"apiKey": "abcdefgh0123456789", "autoClose": false}`,
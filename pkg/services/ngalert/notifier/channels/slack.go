@@ -7,16 +7,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
 
-	"github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/alertmanager/template"
 	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
@@ -132,27 +135,63 @@ func NewSlackNotifier(model *NotificationChannelConfig, t *template.Template) (*
 
 // slackMessage is the slackMessage for sending a slack notification.
 type slackMessage struct {
-	Channel     string                   `json:"channel,omitempty"`
-	Username    string                   `json:"username,omitempty"`
-	IconEmoji   string                   `json:"icon_emoji,omitempty"`
-	IconURL     string                   `json:"icon_url,omitempty"`
-	Attachments []attachment             `json:"attachments"`
-	Blocks      []map[string]interface{} `json:"blocks"`
+	Channel   string `json:"channel,omitempty"`
+	Username  string `json:"username,omitempty"`
+	IconEmoji string `json:"icon_emoji,omitempty"`
+	IconURL   string `json:"icon_url,omitempty"`
+	// Text is shown in the notifications about the message, which don't show its blocks.
+	Text        string       `json:"text"`
+	Attachments []attachment `json:"attachments"`
+	Blocks      []slackBlock `json:"blocks,omitempty"`
 }
 
-// attachment is used to display a richly-formatted message block.
+// attachment holds the blocks of the message, so that they are shown with the color of the
+// status of the alerts.
 type attachment struct {
-	Title      string              `json:"title,omitempty"`
-	TitleLink  string              `json:"title_link,omitempty"`
-	Text       string              `json:"text"`
-	Fallback   string              `json:"fallback"`
-	Fields     []config.SlackField `json:"fields,omitempty"`
-	Footer     string              `json:"footer"`
-	FooterIcon string              `json:"footer_icon"`
-	Color      string              `json:"color,omitempty"`
-	Ts         int64               `json:"ts,omitempty"`
+	Color    string       `json:"color,omitempty"`
+	Fallback string       `json:"fallback"`
+	Blocks   []slackBlock `json:"blocks"`
 }
 
+// slackBlock is a Block Kit layout block. See https://api.slack.com/reference/block-kit/blocks.
+type slackBlock struct {
+	Type     string        `json:"type"`
+	Text     *slackText    `json:"text,omitempty"`
+	Fields   []slackText   `json:"fields,omitempty"`
+	Elements []interface{} `json:"elements,omitempty"`
+	ImageURL string        `json:"image_url,omitempty"`
+	AltText  string        `json:"alt_text,omitempty"`
+}
+
+// slackText is a text object, formatted with mrkdwn or as plain text.
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// slackButton is a button of an actions block that opens a URL.
+type slackButton struct {
+	Type string    `json:"type"`
+	Text slackText `json:"text"`
+	URL  string    `json:"url"`
+}
+
+// slackImage is an image element of a context block.
+type slackImage struct {
+	Type     string `json:"type"`
+	ImageURL string `json:"image_url"`
+	AltText  string `json:"alt_text"`
+}
+
+// The limits of the lengths of the Block Kit objects, beyond which Slack rejects the message.
+const (
+	slackMaxHeaderLength = 150
+	slackMaxTextLength   = 3000
+	slackMaxFields       = 10
+	slackMaxFieldLength  = 2000
+	slackMaxURLLength    = 3000
+)
+
 // Notify sends an alert notification to Slack.
 func (sn *SlackNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
 	msg, err := sn.buildSlackMessage(ctx, as)
@@ -185,9 +224,77 @@ func (sn *SlackNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool,
 	if err := sendSlackRequest(request, sn.log); err != nil {
 		return false, err
 	}
+
+	// the message is already sent, so that a failed upload is not retried along with it
+	if imagePath := firingImagePath(as); imagePath != "" {
+		if err := sn.uploadImage(ctx, imagePath, msg.Text); err != nil {
+			sn.log.Warn("Failed to upload the image of the alert to Slack", "path", imagePath, "err", err)
+		}
+	}
 	return true, nil
 }
 
+// filesUploadURL returns the URL of the files.upload method of the Slack API whose chat.postMessage
+// method the notifier uses, or an empty string if it uses an incoming webhook or can't upload.
+func (sn *SlackNotifier) filesUploadURL() string {
+	if sn.Token == "" || sn.Recipient == "" || !strings.HasSuffix(sn.URL.Path, "/chat.postMessage") {
+		return ""
+	}
+	u := *sn.URL
+	u.Path = strings.TrimSuffix(u.Path, "/chat.postMessage") + "/files.upload"
+	return u.String()
+}
+
+// uploadImage uploads the image of the panel of an alert to the channel of the notifier with the
+// files.upload method, for the images that are not publicly available.
+func (sn *SlackNotifier) uploadImage(ctx context.Context, imagePath, title string) error {
+	uploadURL := sn.filesUploadURL()
+	if uploadURL == "" {
+		return nil
+	}
+
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because the image is a file that was
+	// written by Grafana in its data directory.
+	f, err := os.Open(imagePath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			sn.log.Warn("Failed to close file", "path", imagePath, "err", err)
+		}
+	}()
+	fw, err := w.CreateFormFile("file", filepath.Base(imagePath))
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(fw, f); err != nil {
+		return err
+	}
+	if err := w.WriteField("channels", sn.Recipient); err != nil {
+		return err
+	}
+	if err := w.WriteField("initial_comment", title); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to close multipart writer: %w", err)
+	}
+
+	sn.log.Debug("Uploading image to Slack", "url", uploadURL, "path", imagePath)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadURL, &b)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	request.Header.Set("Content-Type", w.FormDataContentType())
+	request.Header.Set("User-Agent", "Grafana")
+	request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", sn.Token))
+	return sendSlackRequest(request, sn.log)
+}
+
 // sendSlackRequest sends a request to the Slack API.
 // Stubbable by tests.
 var sendSlackRequest = func(request *http.Request, logger log.Logger) error {
@@ -240,29 +347,71 @@ var sendSlackRequest = func(request *http.Request, logger log.Logger) error {
 	return nil
 }
 
+// firingImageURL returns the public URL of the image of the panel of the first firing alert that
+// has one. Slack can only show images that are publicly available.
+func firingImageURL(as []*types.Alert) string {
+	for _, a := range as {
+		if a.Resolved() {
+			continue
+		}
+		if u := a.Annotations["__image_url__"]; u != "" {
+			return string(u)
+		}
+	}
+	return ""
+}
+
+// firingImagePath returns the file of the image of the panel of the first firing alert that has
+// one and whose image is not publicly available.
+func firingImagePath(as []*types.Alert) string {
+	for _, a := range as {
+		if a.Resolved() || a.Annotations["__image_url__"] != "" {
+			continue
+		}
+		if p := a.Annotations["__image_path__"]; p != "" {
+			return string(p)
+		}
+	}
+	return ""
+}
+
 func (sn *SlackNotifier) buildSlackMessage(ctx context.Context, as []*types.Alert) (*slackMessage, error) {
 	alerts := types.Alerts(as...)
 	var tmplErr error
-	tmpl, _ := TmplText(ctx, sn.tmpl, as, sn.log, &tmplErr)
+	tmpl, data := TmplText(ctx, sn.tmpl, as, sn.log, &tmplErr)
 
-	ruleURL := joinUrlPath(sn.tmpl.ExternalURL.String(), "/alerting/list", sn.log)
+	title := tmpl(sn.Title)
+	blocks := []slackBlock{
+		{Type: "header", Text: &slackText{Type: "plain_text", Text: truncateSlackText(title, slackMaxHeaderLength)}},
+	}
+	if text := tmpl(sn.Text); strings.TrimSpace(text) != "" {
+		blocks = append(blocks, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: truncateSlackText(text, slackMaxTextLength)}})
+	}
+	if fields := slackLabelFields(data.CommonLabels); len(fields) > 0 {
+		blocks = append(blocks, slackBlock{Type: "section", Fields: fields})
+	}
+	if imageURL := firingImageURL(as); imageURL != "" {
+		blocks = append(blocks, slackBlock{Type: "image", ImageURL: imageURL, AltText: truncateSlackText(title, slackMaxTextLength)})
+	}
+	if buttons := sn.slackButtons(data); len(buttons) > 0 {
+		blocks = append(blocks, slackBlock{Type: "actions", Elements: buttons})
+	}
+	blocks = append(blocks, slackBlock{Type: "context", Elements: []interface{}{
+		slackImage{Type: "image", ImageURL: FooterIconURL, AltText: "Grafana"},
+		slackText{Type: "mrkdwn", Text: "Grafana v" + setting.BuildVersion},
+	}})
 
 	req := &slackMessage{
 		Channel:   tmpl(sn.Recipient),
 		Username:  tmpl(sn.Username),
 		IconEmoji: tmpl(sn.IconEmoji),
 		IconURL:   tmpl(sn.IconURL),
+		Text:      title,
 		Attachments: []attachment{
 			{
-				Color:      getAlertStatusColor(alerts.Status()),
-				Title:      tmpl(sn.Title),
-				Fallback:   tmpl(sn.Title),
-				Footer:     "Grafana v" + setting.BuildVersion,
-				FooterIcon: FooterIconURL,
-				Ts:         time.Now().Unix(),
-				TitleLink:  ruleURL,
-				Text:       tmpl(sn.Text),
-				Fields:     nil, // TODO. Should be a config.
+				Color:    getAlertStatusColor(alerts.Status()),
+				Fallback: title,
+				Blocks:   blocks,
 			},
 		},
 	}
@@ -294,20 +443,74 @@ func (sn *SlackNotifier) buildSlackMessage(ctx context.Context, as []*types.Aler
 	}
 
 	if mentionsBuilder.Len() > 0 {
-		req.Blocks = []map[string]interface{}{
-			{
-				"type": "section",
-				"text": map[string]interface{}{
-					"type": "mrkdwn",
-					"text": mentionsBuilder.String(),
-				},
-			},
+		req.Blocks = []slackBlock{
+			{Type: "section", Text: &slackText{Type: "mrkdwn", Text: mentionsBuilder.String()}},
 		}
 	}
 
 	return req, nil
 }
 
+// slackLabelFields returns the labels common to all the alerts as the fields of a section block.
+func slackLabelFields(labels template.KV) []slackText {
+	var fields []slackText
+	for _, p := range labels.SortedPairs() {
+		if len(fields) == slackMaxFields {
+			break
+		}
+		fields = append(fields, slackText{Type: "mrkdwn", Text: truncateSlackText(fmt.Sprintf("*%s*\n%s", p.Name, p.Value), slackMaxFieldLength)})
+	}
+	return fields
+}
+
+// slackButtons returns the buttons linking back to Grafana. The alert rule, the dashboard, the
+// panel and the silence of the alerts are only linked when they're the same for all the alerts.
+func (sn *SlackNotifier) slackButtons(data *ExtendedData) []interface{} {
+	common := func(url func(a ExtendedAlert) string) string {
+		if len(data.Alerts) == 0 {
+			return ""
+		}
+		u := url(data.Alerts[0])
+		for _, a := range data.Alerts[1:] {
+			if url(a) != u {
+				return ""
+			}
+		}
+		return u
+	}
+
+	var buttons []interface{}
+	addButton := func(text, u string) {
+		if u != "" && len(u) <= slackMaxURLLength {
+			buttons = append(buttons, slackButton{Type: "button", Text: slackText{Type: "plain_text", Text: text}, URL: u})
+		}
+	}
+
+	if ruleURL := common(func(a ExtendedAlert) string { return a.GeneratorURL }); ruleURL != "" {
+		addButton("View alert rule", ruleURL)
+	} else {
+		addButton("View alert rules", joinUrlPath(sn.tmpl.ExternalURL.String(), "/alerting/list", sn.log))
+	}
+	if panelURL := common(func(a ExtendedAlert) string { return a.PanelURL }); panelURL != "" {
+		addButton("View panel", panelURL)
+	} else {
+		addButton("View dashboard", common(func(a ExtendedAlert) string { return a.DashboardURL }))
+	}
+	if data.Status == string(model.AlertFiring) {
+		addButton("Silence", common(func(a ExtendedAlert) string { return a.SilenceURL }))
+	}
+	return buttons
+}
+
+// truncateSlackText truncates a text to the maximum length of a Block Kit text object.
+func truncateSlackText(text string, maxLength int) string {
+	runes := []rune(text)
+	if len(runes) <= maxLength {
+		return text
+	}
+	return string(runes[:maxLength-1]) + "…"
+}
+
 func (sn *SlackNotifier) SendResolved() bool {
 	return !sn.GetDisableResolveMessage()
 }
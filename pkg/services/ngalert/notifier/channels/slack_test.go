@@ -3,9 +3,13 @@ package channels
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/prometheus/alertmanager/notify"
@@ -24,6 +28,27 @@ func TestSlackNotifier(t *testing.T) {
 	require.NoError(t, err)
 	tmpl.ExternalURL = externalURL
 
+	header := func(title string) slackBlock {
+		return slackBlock{Type: "header", Text: &slackText{Type: "plain_text", Text: title}}
+	}
+	section := func(text string) slackBlock {
+		return slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: text}}
+	}
+	fields := func(fields ...string) slackBlock {
+		b := slackBlock{Type: "section"}
+		for _, f := range fields {
+			b.Fields = append(b.Fields, slackText{Type: "mrkdwn", Text: f})
+		}
+		return b
+	}
+	button := func(text, url string) slackButton {
+		return slackButton{Type: "button", Text: slackText{Type: "plain_text", Text: text}, URL: url}
+	}
+	footer := slackBlock{Type: "context", Elements: []interface{}{
+		slackImage{Type: "image", ImageURL: "https://grafana.com/assets/img/fav32.png", AltText: "Grafana"},
+		slackText{Type: "mrkdwn", Text: "Grafana v"},
+	}}
+
 	cases := []struct {
 		name         string
 		settings     string
@@ -51,17 +76,22 @@ func TestSlackNotifier(t *testing.T) {
 				Channel:   "#testchannel",
 				Username:  "Grafana",
 				IconEmoji: ":emoji:",
+				Text:      "[FIRING:1]  (val1)",
 				Attachments: []attachment{
 					{
-						Title:      "[FIRING:1]  (val1)",
-						TitleLink:  "http://localhost/alerting/list",
-						Text:       "**Firing**\n\nLabels:\n - alertname = alert1\n - lbl1 = val1\nAnnotations:\n - ann1 = annv1\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Clbl1%3Dval1\nDashboard: http://localhost/d/abcd\nPanel: http://localhost/d/abcd?viewPanel=efgh\n",
-						Fallback:   "[FIRING:1]  (val1)",
-						Fields:     nil,
-						Footer:     "Grafana v",
-						FooterIcon: "https://grafana.com/assets/img/fav32.png",
-						Color:      "#D63232",
-						Ts:         0,
+						Color:    "#D63232",
+						Fallback: "[FIRING:1]  (val1)",
+						Blocks: []slackBlock{
+							header("[FIRING:1]  (val1)"),
+							section("**Firing**\n\nLabels:\n - alertname = alert1\n - lbl1 = val1\nAnnotations:\n - ann1 = annv1\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Clbl1%3Dval1\nDashboard: http://localhost/d/abcd\nPanel: http://localhost/d/abcd?viewPanel=efgh\n"),
+							fields("*alertname*\nalert1", "*lbl1*\nval1"),
+							{Type: "actions", Elements: []interface{}{
+								button("View alert rules", "http://localhost/alerting/list"),
+								button("View panel", "http://localhost/d/abcd?viewPanel=efgh"),
+								button("Silence", "http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Clbl1%3Dval1"),
+							}},
+							footer,
+						},
 					},
 				},
 			},
@@ -77,8 +107,9 @@ func TestSlackNotifier(t *testing.T) {
 			alerts: []*types.Alert{
 				{
 					Alert: model.Alert{
-						Labels:      model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
-						Annotations: model.LabelSet{"ann1": "annv1"},
+						Labels:       model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+						Annotations:  model.LabelSet{"ann1": "annv1"},
+						GeneratorURL: "http://localhost/alerting/abcd/edit",
 					},
 				},
 			},
@@ -86,17 +117,59 @@ func TestSlackNotifier(t *testing.T) {
 				Channel:   "#testchannel",
 				Username:  "Grafana",
 				IconEmoji: ":emoji:",
+				Text:      "[FIRING:1]  (val1)",
+				Attachments: []attachment{
+					{
+						Color:    "#D63232",
+						Fallback: "[FIRING:1]  (val1)",
+						Blocks: []slackBlock{
+							header("[FIRING:1]  (val1)"),
+							section("**Firing**\n\nLabels:\n - alertname = alert1\n - lbl1 = val1\nAnnotations:\n - ann1 = annv1\nSource: http://localhost/alerting/abcd/edit\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Clbl1%3Dval1\n"),
+							fields("*alertname*\nalert1", "*lbl1*\nval1"),
+							{Type: "actions", Elements: []interface{}{
+								button("View alert rule", "http://localhost/alerting/abcd/edit"),
+								button("Silence", "http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Clbl1%3Dval1"),
+							}},
+							footer,
+						},
+					},
+				},
+			},
+			expMsgError: nil,
+		},
+		{
+			name: "Correct config with the image of the panel",
+			settings: `{
+				"url": "https://webhook.com",
+				"recipient": "#testchannel"
+			}`,
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels:      model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+						Annotations: model.LabelSet{"ann1": "annv1", "__image_url__": "https://images.example.com/abcd.png", "__image_path__": "/var/lib/grafana/png/abcd.png"},
+					},
+				},
+			},
+			expMsg: &slackMessage{
+				Channel:  "#testchannel",
+				Username: "Grafana",
+				Text:     "[FIRING:1]  (val1)",
 				Attachments: []attachment{
 					{
-						Title:      "[FIRING:1]  (val1)",
-						TitleLink:  "http://localhost/alerting/list",
-						Text:       "**Firing**\n\nLabels:\n - alertname = alert1\n - lbl1 = val1\nAnnotations:\n - ann1 = annv1\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Clbl1%3Dval1\n",
-						Fallback:   "[FIRING:1]  (val1)",
-						Fields:     nil,
-						Footer:     "Grafana v",
-						FooterIcon: "https://grafana.com/assets/img/fav32.png",
-						Color:      "#D63232",
-						Ts:         0,
+						Color:    "#D63232",
+						Fallback: "[FIRING:1]  (val1)",
+						Blocks: []slackBlock{
+							header("[FIRING:1]  (val1)"),
+							section("**Firing**\n\nLabels:\n - alertname = alert1\n - lbl1 = val1\nAnnotations:\n - ann1 = annv1\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Clbl1%3Dval1\n"),
+							fields("*alertname*\nalert1", "*lbl1*\nval1"),
+							{Type: "image", ImageURL: "https://images.example.com/abcd.png", AltText: "[FIRING:1]  (val1)"},
+							{Type: "actions", Elements: []interface{}{
+								button("View alert rules", "http://localhost/alerting/list"),
+								button("Silence", "http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Clbl1%3Dval1"),
+							}},
+							footer,
+						},
 					},
 				},
 			},
@@ -108,7 +181,8 @@ func TestSlackNotifier(t *testing.T) {
 				"token": "1234",
 				"recipient": "#testchannel",
 				"icon_emoji": ":emoji:",
-				"title": "{{ .Alerts.Firing | len }} firing, {{ .Alerts.Resolved | len }} resolved"
+				"title": "{{ .Alerts.Firing | len }} firing, {{ .Alerts.Resolved | len }} resolved",
+				"mentionUsers": "user1"
 			}`,
 			alerts: []*types.Alert{
 				{
@@ -128,19 +202,23 @@ func TestSlackNotifier(t *testing.T) {
 				Channel:   "#testchannel",
 				Username:  "Grafana",
 				IconEmoji: ":emoji:",
+				Text:      "2 firing, 0 resolved",
 				Attachments: []attachment{
 					{
-						Title:      "2 firing, 0 resolved",
-						TitleLink:  "http://localhost/alerting/list",
-						Text:       "**Firing**\n\nLabels:\n - alertname = alert1\n - lbl1 = val1\nAnnotations:\n - ann1 = annv1\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Clbl1%3Dval1\n\nLabels:\n - alertname = alert1\n - lbl1 = val2\nAnnotations:\n - ann1 = annv2\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Clbl1%3Dval2\n",
-						Fallback:   "2 firing, 0 resolved",
-						Fields:     nil,
-						Footer:     "Grafana v",
-						FooterIcon: "https://grafana.com/assets/img/fav32.png",
-						Color:      "#D63232",
-						Ts:         0,
+						Color:    "#D63232",
+						Fallback: "2 firing, 0 resolved",
+						Blocks: []slackBlock{
+							header("2 firing, 0 resolved"),
+							section("**Firing**\n\nLabels:\n - alertname = alert1\n - lbl1 = val1\nAnnotations:\n - ann1 = annv1\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Clbl1%3Dval1\n\nLabels:\n - alertname = alert1\n - lbl1 = val2\nAnnotations:\n - ann1 = annv2\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Clbl1%3Dval2\n"),
+							fields("*alertname*\nalert1"),
+							{Type: "actions", Elements: []interface{}{
+								button("View alert rules", "http://localhost/alerting/list"),
+							}},
+							footer,
+						},
 					},
 				},
+				Blocks: []slackBlock{section("<@user1>")},
 			},
 			expMsgError: nil,
 		}, {
@@ -206,11 +284,6 @@ func TestSlackNotifier(t *testing.T) {
 			require.True(t, ok)
 			require.NoError(t, err)
 
-			// Getting Ts from actual since that can't be predicted.
-			var obj slackMessage
-			require.NoError(t, json.Unmarshal([]byte(body), &obj))
-			c.expMsg.Attachments[0].Ts = obj.Attachments[0].Ts
-
 			expBody, err := json.Marshal(c.expMsg)
 			require.NoError(t, err)
 
@@ -218,3 +291,70 @@ func TestSlackNotifier(t *testing.T) {
 		})
 	}
 }
+
+func TestSlackNotifierImageUpload(t *testing.T) {
+	tmpl := templateForTests(t)
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	imagePath := filepath.Join(t.TempDir(), "abcd.png")
+	require.NoError(t, os.WriteFile(imagePath, []byte("png"), 0600))
+	alerts := []*types.Alert{
+		{
+			Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "alert1"},
+				Annotations: model.LabelSet{"__image_path__": model.LabelValue(imagePath)},
+			},
+		},
+	}
+
+	var requests []*http.Request
+	origSendSlackRequest := sendSlackRequest
+	t.Cleanup(func() {
+		sendSlackRequest = origSendSlackRequest
+	})
+	sendSlackRequest = func(request *http.Request, log log.Logger) error {
+		requests = append(requests, request)
+		if strings.HasSuffix(request.URL.Path, "/files.upload") {
+			return errors.New("not_in_channel")
+		}
+		return nil
+	}
+
+	notify := func(t *testing.T, settings string) {
+		t.Helper()
+		requests = nil
+		settingsJSON, err := simplejson.NewJson([]byte(settings))
+		require.NoError(t, err)
+		pn, err := NewSlackNotifier(&NotificationChannelConfig{Name: "slack_testing", Type: "slack", Settings: settingsJSON}, tmpl)
+		require.NoError(t, err)
+		ok, err := pn.Notify(context.Background(), alerts...)
+		// the message is sent even if the image can't be uploaded
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	t.Run("the image is uploaded to the channel with the Slack API", func(t *testing.T) {
+		notify(t, `{"token": "1234", "recipient": "#testchannel"}`)
+		require.Len(t, requests, 2)
+
+		upload := requests[1]
+		require.Equal(t, "https://slack.com/api/files.upload", upload.URL.String())
+		require.Equal(t, "Bearer 1234", upload.Header.Get("Authorization"))
+		require.NoError(t, upload.ParseMultipartForm(1024))
+		require.Equal(t, "#testchannel", upload.FormValue("channels"))
+		require.Equal(t, "[FIRING:1]  (alert1)", upload.FormValue("initial_comment"))
+		file, header, err := upload.FormFile("file")
+		require.NoError(t, err)
+		require.Equal(t, "abcd.png", header.Filename)
+		b, err := io.ReadAll(file)
+		require.NoError(t, err)
+		require.Equal(t, "png", string(b))
+	})
+
+	t.Run("the image isn't uploaded with an incoming webhook", func(t *testing.T) {
+		notify(t, `{"url": "https://webhook.com", "recipient": "#testchannel"}`)
+		require.Len(t, requests, 1)
+	})
+}
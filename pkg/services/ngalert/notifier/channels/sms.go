@@ -0,0 +1,93 @@
+package channels
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	old_notifiers "github.com/grafana/grafana/pkg/services/alerting/notifiers"
+)
+
+// phoneNumberPattern matches the phone numbers in the E.164 format, such as +15551234567.
+var phoneNumberPattern = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+
+// SMSNotifier is responsible for sending alert notifications
+// as text messages, with the SMS provider of the server.
+type SMSNotifier struct {
+	old_notifiers.NotifierBase
+	PhoneNumbers []string
+	Message      string
+	log          log.Logger
+	tmpl         *template.Template
+}
+
+// NewSMSNotifier is the constructor for the SMS notifier.
+func NewSMSNotifier(model *NotificationChannelConfig, t *template.Template) (*SMSNotifier, error) {
+	if model.Settings == nil {
+		return nil, receiverInitError{Cfg: *model, Reason: "no settings supplied"}
+	}
+
+	var phoneNumbers []string
+	for _, n := range strings.FieldsFunc(model.Settings.Get("phoneNumbers").MustString(), func(r rune) bool {
+		return r == ';' || r == ',' || r == '\n'
+	}) {
+		n = strings.TrimSpace(n)
+		if n == "" {
+			continue
+		}
+		if !phoneNumberPattern.MatchString(n) {
+			return nil, receiverInitError{Cfg: *model, Reason: fmt.Sprintf("invalid phone number %q, it must be in the international format, such as +15551234567", n)}
+		}
+		phoneNumbers = append(phoneNumbers, n)
+	}
+	if len(phoneNumbers) == 0 {
+		return nil, receiverInitError{Cfg: *model, Reason: "could not find phone numbers in settings"}
+	}
+
+	return &SMSNotifier{
+		NotifierBase: old_notifiers.NewNotifierBase(&models.AlertNotification{
+			Uid:                   model.UID,
+			Name:                  model.Name,
+			Type:                  model.Type,
+			DisableResolveMessage: model.DisableResolveMessage,
+			Settings:              model.Settings,
+		}),
+		PhoneNumbers: phoneNumbers,
+		Message:      model.Settings.Get("message").MustString(`{{ template "default.title" . }}`),
+		log:          log.New("alerting.notifier.sms"),
+		tmpl:         t,
+	}, nil
+}
+
+// Notify sends the templated message to the phone numbers.
+func (sn *SMSNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	var tmplErr error
+	tmpl, _ := TmplText(ctx, sn.tmpl, as, sn.log, &tmplErr)
+
+	message := strings.TrimSpace(tmpl(sn.Message))
+	if tmplErr != nil {
+		sn.log.Debug("failed to template SMS message", "err", tmplErr.Error())
+	}
+
+	cmd := &models.SendSMSCommandSync{
+		To:      sn.PhoneNumbers,
+		Message: message,
+	}
+	if err := bus.DispatchCtx(ctx, cmd); err != nil {
+		sn.log.Error("Failed to send SMS notification", "error", err)
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (sn *SMSNotifier) SendResolved() bool {
+	return !sn.GetDisableResolveMessage()
+}
@@ -0,0 +1,102 @@
+package channels
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+func TestSMSNotifier(t *testing.T) {
+	tmpl := templateForTests(t)
+
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	cases := []struct {
+		name         string
+		settings     string
+		alerts       []*types.Alert
+		expTo        []string
+		expMsg       string
+		expInitError string
+	}{
+		{
+			name:     "Default message",
+			settings: `{"phoneNumbers": "+15551234567; +15557654321"}`,
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels: model.LabelSet{"alertname": "DiskFull", "instance": "db-1"},
+					},
+				},
+			},
+			expTo:  []string{"+15551234567", "+15557654321"},
+			expMsg: "[FIRING:1]  (db-1)",
+		}, {
+			name:     "Custom message",
+			settings: `{"phoneNumbers": "+15551234567", "message": "{{ .CommonLabels.alertname }} is {{ .Status }}"}`,
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels: model.LabelSet{"alertname": "DiskFull"},
+					},
+				},
+			},
+			expTo:  []string{"+15551234567"},
+			expMsg: "DiskFull is firing",
+		}, {
+			name:         "No phone numbers",
+			settings:     `{}`,
+			expInitError: `failed to validate receiver "sms_testing" of type "sms": could not find phone numbers in settings`,
+		}, {
+			name:         "Invalid phone number",
+			settings:     `{"phoneNumbers": "555-1234"}`,
+			expInitError: `failed to validate receiver "sms_testing" of type "sms": invalid phone number "555-1234", it must be in the international format, such as +15551234567`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			settingsJSON, err := simplejson.NewJson([]byte(c.settings))
+			require.NoError(t, err)
+
+			m := &NotificationChannelConfig{
+				Name:     "sms_testing",
+				Type:     "sms",
+				Settings: settingsJSON,
+			}
+
+			pn, err := NewSMSNotifier(m, tmpl)
+			if c.expInitError != "" {
+				require.Error(t, err)
+				require.Equal(t, c.expInitError, err.Error())
+				return
+			}
+			require.NoError(t, err)
+
+			var cmd *models.SendSMSCommandSync
+			bus.AddHandlerCtx("test", func(ctx context.Context, c *models.SendSMSCommandSync) error {
+				cmd = c
+				return nil
+			})
+
+			ctx := notify.WithGroupKey(context.Background(), "alertname")
+			ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": ""})
+			ok, err := pn.Notify(ctx, c.alerts...)
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.Equal(t, c.expTo, cmd.To)
+			require.Equal(t, c.expMsg, cmd.Message)
+		})
+	}
+}
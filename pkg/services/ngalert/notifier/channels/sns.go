@@ -0,0 +1,288 @@
+package channels
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	old_notifiers "github.com/grafana/grafana/pkg/services/alerting/notifiers"
+)
+
+const (
+	// SNSAuthProviderDefault uses the default credentials of the AWS SDK, such as the IAM role of
+	// the instance or of the task Grafana runs in.
+	SNSAuthProviderDefault = "default"
+	// SNSAuthProviderKeys uses the access key and the secret key of the contact point.
+	SNSAuthProviderKeys = "keys"
+
+	// SNSMessageFormatText publishes the templated message.
+	SNSMessageFormatText = "text"
+	// SNSMessageFormatJSON publishes the payload of the webhook contact point, for the subscribers
+	// that process the alerts, such as Lambda functions.
+	SNSMessageFormatJSON = "json"
+
+	// the limits of SNS, beyond which the messages are rejected
+	snsMaxSubjectLength      = 100
+	snsMaxMessageSize        = 256 * 1024
+	snsMaxMessageAttributes  = 10
+	snsMessageAttributeState = "status"
+)
+
+// snsPublisher is the part of the SNS client used by the notifier.
+// Stubbable by tests.
+type snsPublisher interface {
+	PublishWithContext(ctx aws.Context, input *sns.PublishInput, opts ...request.Option) (*sns.PublishOutput, error)
+}
+
+// SNSNotifier is responsible for publishing
+// alert notifications to an AWS SNS topic.
+type SNSNotifier struct {
+	old_notifiers.NotifierBase
+	TopicARN       string
+	Region         string
+	Endpoint       string
+	AuthProvider   string
+	AccessKey      string
+	SecretKey      string
+	AssumeRoleARN  string
+	ExternalID     string
+	Subject        string
+	Message        string
+	MessageFormat  string
+	MessageGroupID string
+	log            log.Logger
+	tmpl           *template.Template
+
+	clientMtx sync.Mutex
+	client    snsPublisher
+}
+
+// NewSNSNotifier is the constructor for the SNS notifier.
+func NewSNSNotifier(model *NotificationChannelConfig, t *template.Template) (*SNSNotifier, error) {
+	if model.Settings == nil {
+		return nil, receiverInitError{Cfg: *model, Reason: "no settings supplied"}
+	}
+
+	topicARN := strings.TrimSpace(model.Settings.Get("topicArn").MustString())
+	if topicARN == "" {
+		return nil, receiverInitError{Cfg: *model, Reason: "could not find topic ARN in settings"}
+	}
+	parsedARN, err := arn.Parse(topicARN)
+	if err != nil || parsedARN.Service != "sns" {
+		return nil, receiverInitError{Cfg: *model, Reason: fmt.Sprintf("invalid topic ARN %q", topicARN), Err: err}
+	}
+	// the region of the topic is the default, so that it doesn't have to be set twice
+	region := model.Settings.Get("region").MustString(parsedARN.Region)
+
+	authProvider := model.Settings.Get("authProvider").MustString(SNSAuthProviderDefault)
+	accessKey := model.DecryptedValue("accessKey", model.Settings.Get("accessKey").MustString())
+	secretKey := model.DecryptedValue("secretKey", model.Settings.Get("secretKey").MustString())
+	switch authProvider {
+	case SNSAuthProviderDefault:
+	case SNSAuthProviderKeys:
+		if accessKey == "" || secretKey == "" {
+			return nil, receiverInitError{Cfg: *model, Reason: "access key and secret key must be specified when using the keys authentication provider"}
+		}
+	default:
+		return nil, receiverInitError{Cfg: *model, Reason: fmt.Sprintf("invalid authentication provider %q", authProvider)}
+	}
+
+	messageFormat := model.Settings.Get("messageFormat").MustString(SNSMessageFormatText)
+	if messageFormat != SNSMessageFormatText && messageFormat != SNSMessageFormatJSON {
+		return nil, receiverInitError{Cfg: *model, Reason: fmt.Sprintf("invalid message format %q", messageFormat)}
+	}
+
+	return &SNSNotifier{
+		NotifierBase: old_notifiers.NewNotifierBase(&models.AlertNotification{
+			Uid:                   model.UID,
+			Name:                  model.Name,
+			Type:                  model.Type,
+			DisableResolveMessage: model.DisableResolveMessage,
+			Settings:              model.Settings,
+		}),
+		TopicARN:       topicARN,
+		Region:         region,
+		Endpoint:       model.Settings.Get("endpoint").MustString(),
+		AuthProvider:   authProvider,
+		AccessKey:      accessKey,
+		SecretKey:      secretKey,
+		AssumeRoleARN:  model.Settings.Get("assumeRoleArn").MustString(),
+		ExternalID:     model.Settings.Get("externalId").MustString(),
+		Subject:        model.Settings.Get("subject").MustString(`{{ template "default.title" . }}`),
+		Message:        model.Settings.Get("message").MustString(`{{ template "default.message" . }}`),
+		MessageFormat:  messageFormat,
+		MessageGroupID: model.Settings.Get("messageGroupId").MustString(),
+		log:            log.New("alerting.notifier.sns"),
+		tmpl:           t,
+	}, nil
+}
+
+// isFIFO tells whether the topic is a FIFO topic, whose messages must have a group ID and a
+// deduplication ID.
+func (sn *SNSNotifier) isFIFO() bool {
+	return strings.HasSuffix(sn.TopicARN, ".fifo")
+}
+
+// Notify publishes the alert notification to the SNS topic.
+func (sn *SNSNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	groupKey, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var tmplErr error
+	tmpl, data := TmplText(ctx, sn.tmpl, as, sn.log, &tmplErr)
+
+	message := tmpl(sn.Message)
+	if sn.MessageFormat == SNSMessageFormatJSON {
+		msg := &webhookMessage{
+			Version:      "1",
+			ExtendedData: data,
+			GroupKey:     groupKey.String(),
+			Title:        tmpl(sn.Subject),
+			Message:      message,
+		}
+		if types.Alerts(as...).Status() == model.AlertFiring {
+			msg.State = string(models.AlertStateAlerting)
+		} else {
+			msg.State = string(models.AlertStateOK)
+		}
+		b, err := json.Marshal(msg)
+		if err != nil {
+			return false, err
+		}
+		message = string(b)
+	}
+	if len(message) > snsMaxMessageSize {
+		return false, fmt.Errorf("the message of %d bytes is larger than the maximum size of SNS messages", len(message))
+	}
+
+	input := &sns.PublishInput{
+		TopicArn:          aws.String(sn.TopicARN),
+		Subject:           aws.String(snsSubject(tmpl(sn.Subject))),
+		Message:           aws.String(message),
+		MessageAttributes: snsMessageAttributes(data),
+	}
+	if sn.isFIFO() {
+		groupID := tmpl(sn.MessageGroupID)
+		if groupID == "" {
+			groupID = groupKey.Hash()
+		}
+		input.MessageGroupId = aws.String(groupID)
+		// the retries of the same notification are deduplicated
+		input.MessageDeduplicationId = aws.String(fmt.Sprintf("%x", sha256.Sum256([]byte(groupKey.String()+"\n"+message))))
+	}
+	if tmplErr != nil {
+		sn.log.Debug("failed to template SNS message", "err", tmplErr.Error())
+	}
+
+	client, err := sn.getClient()
+	if err != nil {
+		return false, err
+	}
+	sn.log.Debug("Publishing SNS message", "topic", sn.TopicARN)
+	if _, err := client.PublishWithContext(ctx, input); err != nil {
+		sn.log.Warn("Failed to publish SNS message", "topic", sn.TopicARN, "err", err)
+		return false, fmt.Errorf("failed to publish SNS message: %w", err)
+	}
+	return true, nil
+}
+
+// snsSubject returns the subject of the message, which is only used by the email subscriptions and
+// must be a single line of ASCII characters.
+func snsSubject(subject string) string {
+	b := strings.Builder{}
+	for _, r := range subject {
+		if b.Len() == snsMaxSubjectLength {
+			break
+		}
+		switch {
+		case r == '\n' || r == '\r' || r == '\t':
+			b.WriteRune(' ')
+		case r >= ' ' && r <= '~':
+			b.WriteRune(r)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// snsMessageAttributes returns the status of the alerts and the labels common to all of them as
+// message attributes, so that the subscriptions can filter the messages.
+func snsMessageAttributes(data *ExtendedData) map[string]*sns.MessageAttributeValue {
+	attributes := map[string]*sns.MessageAttributeValue{
+		snsMessageAttributeState: {DataType: aws.String("String"), StringValue: aws.String(data.Status)},
+	}
+	for _, p := range data.CommonLabels.SortedPairs() {
+		if len(attributes) == snsMaxMessageAttributes {
+			break
+		}
+		if p.Name == snsMessageAttributeState || p.Value == "" {
+			continue
+		}
+		attributes[p.Name] = &sns.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(p.Value)}
+	}
+	return attributes
+}
+
+// getClient returns the SNS client of the notifier, which is created on the first notification.
+func (sn *SNSNotifier) getClient() (snsPublisher, error) {
+	sn.clientMtx.Lock()
+	defer sn.clientMtx.Unlock()
+	if sn.client != nil {
+		return sn.client, nil
+	}
+
+	client, err := newSNSClient(sn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SNS client: %w", err)
+	}
+	sn.client = client
+	return client, nil
+}
+
+// newSNSClient creates the SNS client of a notifier.
+// Stubbable by tests.
+var newSNSClient = func(sn *SNSNotifier) (snsPublisher, error) {
+	cfg := aws.NewConfig().WithRegion(sn.Region)
+	if sn.Endpoint != "" {
+		cfg = cfg.WithEndpoint(sn.Endpoint)
+	}
+	if sn.AuthProvider == SNSAuthProviderKeys {
+		cfg = cfg.WithCredentials(credentials.NewStaticCredentials(sn.AccessKey, sn.SecretKey, ""))
+	}
+
+	sess, err := session.NewSession(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if sn.AssumeRoleARN != "" {
+		creds := stscreds.NewCredentials(sess, sn.AssumeRoleARN, func(p *stscreds.AssumeRoleProvider) {
+			if sn.ExternalID != "" {
+				p.ExternalID = aws.String(sn.ExternalID)
+			}
+		})
+		return sns.New(sess, &aws.Config{Credentials: creds}), nil
+	}
+	return sns.New(sess), nil
+}
+
+func (sn *SNSNotifier) SendResolved() bool {
+	return !sn.GetDisableResolveMessage()
+}
@@ -0,0 +1,243 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/securejsondata"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+type fakeSNSPublisher struct {
+	inputs []*sns.PublishInput
+	err    error
+}
+
+func (f *fakeSNSPublisher) PublishWithContext(_ aws.Context, input *sns.PublishInput, _ ...request.Option) (*sns.PublishOutput, error) {
+	f.inputs = append(f.inputs, input)
+	return &sns.PublishOutput{}, f.err
+}
+
+func TestSNSNotifier(t *testing.T) {
+	tmpl := templateForTests(t)
+
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	firing := []*types.Alert{
+		{
+			Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "alert1", "severity": "critical", "team": "ops"},
+				Annotations: model.LabelSet{"ann1": "annv1"},
+			},
+		},
+	}
+
+	cases := []struct {
+		name           string
+		settings       string
+		secureSettings map[string]string
+		alerts         []*types.Alert
+		publishErr     error
+		expInitError   string
+		expMsgError    string
+		expRegion      string
+		expInput       func(t *testing.T, input *sns.PublishInput)
+	}{
+		{
+			name:      "Text message with the labels as message attributes",
+			settings:  `{"topicArn": "arn:aws:sns:eu-west-1:123456789012:alerts"}`,
+			alerts:    firing,
+			expRegion: "eu-west-1",
+			expInput: func(t *testing.T, input *sns.PublishInput) {
+				require.Equal(t, "arn:aws:sns:eu-west-1:123456789012:alerts", *input.TopicArn)
+				require.Equal(t, "[FIRING:1]  (critical ops)", *input.Subject)
+				require.Equal(t, "**Firing**\n\nLabels:\n - alertname = alert1\n - severity = critical\n - team = ops\nAnnotations:\n - ann1 = annv1\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Cseverity%3Dcritical%2Cteam%3Dops\n", *input.Message)
+				require.Nil(t, input.MessageGroupId)
+				require.Nil(t, input.MessageDeduplicationId)
+
+				attributes := map[string]string{}
+				for k, v := range input.MessageAttributes {
+					require.Equal(t, "String", *v.DataType)
+					attributes[k] = *v.StringValue
+				}
+				require.Equal(t, map[string]string{
+					"status":    "firing",
+					"alertname": "alert1",
+					"severity":  "critical",
+					"team":      "ops",
+				}, attributes)
+			},
+		}, {
+			name:     "JSON message for resolved alerts",
+			settings: `{"topicArn": "arn:aws:sns:eu-west-1:123456789012:alerts", "region": "us-east-1", "messageFormat": "json", "message": "{{ len .Alerts }} alerts"}`,
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels: model.LabelSet{"alertname": "alert1", "status": "custom"},
+						EndsAt: time.Now().Add(-1 * time.Minute),
+					},
+				},
+			},
+			expRegion: "us-east-1",
+			expInput: func(t *testing.T, input *sns.PublishInput) {
+				var msg map[string]interface{}
+				require.NoError(t, json.Unmarshal([]byte(*input.Message), &msg))
+				require.Equal(t, "resolved", msg["status"])
+				require.Equal(t, "ok", msg["state"])
+				require.Equal(t, "1 alerts", msg["message"])
+				require.Equal(t, "[RESOLVED]  (custom)", msg["title"])
+				require.Equal(t, "alertname", msg["groupKey"])
+				require.Len(t, msg["alerts"], 1)
+
+				// the status attribute isn't overridden by the label of the same name
+				require.Equal(t, "resolved", *input.MessageAttributes["status"].StringValue)
+				require.Equal(t, "[RESOLVED]  (custom)", *input.Subject)
+			},
+		}, {
+			name:     "FIFO topic with a templated message group",
+			settings: `{"topicArn": "arn:aws:sns:eu-west-1:123456789012:alerts.fifo", "messageGroupId": "{{ .CommonLabels.team }}"}`,
+			alerts:   firing,
+			expInput: func(t *testing.T, input *sns.PublishInput) {
+				require.Equal(t, "ops", *input.MessageGroupId)
+				require.Len(t, *input.MessageDeduplicationId, 64)
+			},
+		}, {
+			name:     "FIFO topic with the default message group",
+			settings: `{"topicArn": "arn:aws:sns:eu-west-1:123456789012:alerts.fifo"}`,
+			alerts:   firing,
+			expInput: func(t *testing.T, input *sns.PublishInput) {
+				require.Equal(t, notify.Key("alertname").Hash(), *input.MessageGroupId)
+			},
+		}, {
+			name:     "The message attributes are limited",
+			settings: `{"topicArn": "arn:aws:sns:eu-west-1:123456789012:alerts"}`,
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels: model.LabelSet{
+							"a": "1", "b": "2", "c": "3", "d": "4", "e": "5", "f": "6",
+							"g": "7", "h": "8", "i": "9", "j": "10", "k": "11",
+						},
+					},
+				},
+			},
+			expInput: func(t *testing.T, input *sns.PublishInput) {
+				require.Len(t, input.MessageAttributes, 10)
+				require.Contains(t, input.MessageAttributes, "status")
+				require.Contains(t, input.MessageAttributes, "i")
+				require.NotContains(t, input.MessageAttributes, "j")
+			},
+		}, {
+			name:           "Static credentials from the secure settings",
+			settings:       `{"topicArn": "arn:aws:sns:eu-west-1:123456789012:alerts", "authProvider": "keys"}`,
+			secureSettings: map[string]string{"accessKey": "access", "secretKey": "secret"},
+			alerts:         firing,
+		}, {
+			name:        "Too large messages",
+			settings:    `{"topicArn": "arn:aws:sns:eu-west-1:123456789012:alerts", "message": "` + strings.Repeat("a", snsMaxMessageSize+1) + `"}`,
+			alerts:      firing,
+			expMsgError: "the message of 262145 bytes is larger than the maximum size of SNS messages",
+		}, {
+			name:        "Publishing errors",
+			settings:    `{"topicArn": "arn:aws:sns:eu-west-1:123456789012:alerts"}`,
+			alerts:      firing,
+			publishErr:  errors.New("AuthorizationError"),
+			expMsgError: "failed to publish SNS message: AuthorizationError",
+		}, {
+			name:         "Error in initing, no topic ARN",
+			settings:     `{}`,
+			expInitError: `failed to validate receiver "sns_testing" of type "sns": could not find topic ARN in settings`,
+		}, {
+			name:         "Error in initing, invalid topic ARN",
+			settings:     `{"topicArn": "arn:aws:sqs:eu-west-1:123456789012:alerts"}`,
+			expInitError: `failed to validate receiver "sns_testing" of type "sns": invalid topic ARN "arn:aws:sqs:eu-west-1:123456789012:alerts"`,
+		}, {
+			name:         "Error in initing, missing keys",
+			settings:     `{"topicArn": "arn:aws:sns:eu-west-1:123456789012:alerts", "authProvider": "keys", "accessKey": "access"}`,
+			expInitError: `failed to validate receiver "sns_testing" of type "sns": access key and secret key must be specified when using the keys authentication provider`,
+		}, {
+			name:         "Error in initing, invalid message format",
+			settings:     `{"topicArn": "arn:aws:sns:eu-west-1:123456789012:alerts", "messageFormat": "xml"}`,
+			expInitError: `failed to validate receiver "sns_testing" of type "sns": invalid message format "xml"`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			settingsJSON, err := simplejson.NewJson([]byte(c.settings))
+			require.NoError(t, err)
+
+			m := &NotificationChannelConfig{
+				Name:           "sns_testing",
+				Type:           "sns",
+				Settings:       settingsJSON,
+				SecureSettings: securejsondata.GetEncryptedJsonData(c.secureSettings),
+			}
+
+			pn, err := NewSNSNotifier(m, tmpl)
+			if c.expInitError != "" {
+				require.Error(t, err)
+				require.Equal(t, c.expInitError, err.Error())
+				return
+			}
+			require.NoError(t, err)
+
+			publisher := &fakeSNSPublisher{err: c.publishErr}
+			origNewSNSClient := newSNSClient
+			t.Cleanup(func() {
+				newSNSClient = origNewSNSClient
+			})
+			clients := 0
+			newSNSClient = func(sn *SNSNotifier) (snsPublisher, error) {
+				clients++
+				if c.expRegion != "" {
+					require.Equal(t, c.expRegion, sn.Region)
+				}
+				if c.secureSettings != nil {
+					require.Equal(t, c.secureSettings["accessKey"], sn.AccessKey)
+					require.Equal(t, c.secureSettings["secretKey"], sn.SecretKey)
+				}
+				return publisher, nil
+			}
+
+			ctx := notify.WithGroupKey(context.Background(), "alertname")
+			ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": ""})
+			ok, err := pn.Notify(ctx, c.alerts...)
+			if c.expMsgError != "" {
+				require.False(t, ok)
+				require.Error(t, err)
+				require.Equal(t, c.expMsgError, err.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.Len(t, publisher.inputs, 1)
+			if c.expInput != nil {
+				c.expInput(t, publisher.inputs[0])
+			}
+
+			// the client is reused by the next notifications
+			_, err = pn.Notify(ctx, c.alerts...)
+			require.NoError(t, err)
+			require.Equal(t, 1, clients)
+			require.Len(t, publisher.inputs, 2)
+			// so are the deduplication IDs of the same notifications
+			require.Equal(t, publisher.inputs[0].MessageDeduplicationId, publisher.inputs[1].MessageDeduplicationId)
+		})
+	}
+}
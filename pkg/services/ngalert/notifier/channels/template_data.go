@@ -2,6 +2,7 @@ package channels
 
 import (
 	"context"
+	"encoding/json"
 	"net/url"
 	"path"
 	"sort"
@@ -30,6 +31,14 @@ type ExtendedAlert struct {
 	DashboardURL string      `json:"dashboardURL"`
 	PanelURL     string      `json:"panelURL"`
 	ValueString  string      `json:"valueString"`
+	// Values are the values of the reduce and math expressions of the alert rule by RefID.
+	Values map[string]float64 `json:"values,omitempty"`
+	// ImageURL is the public URL of the image of the panel of the alert rule, captured when the
+	// alert started firing.
+	ImageURL string `json:"imageURL,omitempty"`
+
+	// imagePath is the file of the image of the panel, for the notifiers that attach it.
+	imagePath string
 }
 
 type ExtendedAlerts []ExtendedAlert
@@ -67,6 +76,17 @@ func extendAlert(alert template.Alert, externalURL string, logger log.Logger) *E
 		Fingerprint:  alert.Fingerprint,
 	}
 
+	if alert.Annotations != nil {
+		extended.ValueString = alert.Annotations[`__value_string__`]
+		if values := alert.Annotations[`__values__`]; values != "" {
+			if err := json.Unmarshal([]byte(values), &extended.Values); err != nil {
+				logger.Debug("failed to parse the values of the alert", "values", values, "err", err.Error())
+			}
+		}
+		extended.ImageURL = alert.Annotations[`__image_url__`]
+		extended.imagePath = alert.Annotations[`__image_path__`]
+	}
+
 	// fill in some grafana-specific urls
 	if len(externalURL) == 0 {
 		return extended
@@ -88,10 +108,6 @@ func extendAlert(alert template.Alert, externalURL string, logger log.Logger) *E
 		}
 	}
 
-	if alert.Annotations != nil {
-		extended.ValueString = alert.Annotations[`__value_string__`]
-	}
-
 	matchers := make([]string, 0)
 	for key, value := range alert.Labels {
 		if !(strings.HasPrefix(key, "__") && strings.HasSuffix(key, "__")) {
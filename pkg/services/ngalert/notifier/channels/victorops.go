@@ -26,10 +26,21 @@ const (
 	victoropsAlertStateRecovery = "RECOVERY"
 )
 
+// victoropsMessageTypeBySeverity maps the usual values of the severity label to the message types of VictorOps.
+var victoropsMessageTypeBySeverity = map[string]string{
+	"critical": victoropsAlertStateCritical,
+	"high":     victoropsAlertStateCritical,
+	"error":    victoropsAlertStateCritical,
+	"warning":  "WARNING",
+	"low":      "INFO",
+	"info":     "INFO",
+}
+
 // NewVictoropsNotifier creates an instance of VictoropsNotifier that
 // handles posting notifications to Victorops REST API
 func NewVictoropsNotifier(model *NotificationChannelConfig, t *template.Template) (*VictoropsNotifier, error) {
-	url := model.Settings.Get("url").MustString()
+	// the url holds the API key and the routing key of the integration
+	url := model.DecryptedValue("url", model.Settings.Get("url").MustString())
 	if url == "" {
 		return nil, receiverInitError{Cfg: *model, Reason: "could not find victorops url property in settings"}
 	}
@@ -65,9 +76,12 @@ func (vn *VictoropsNotifier) Notify(ctx context.Context, as ...*types.Alert) (bo
 	vn.log.Debug("Executing victorops notification", "notification", vn.Name)
 
 	var tmplErr error
-	tmpl, _ := TmplText(ctx, vn.tmpl, as, vn.log, &tmplErr)
+	tmpl, data := TmplText(ctx, vn.tmpl, as, vn.log, &tmplErr)
 
 	messageType := strings.ToUpper(tmpl(vn.MessageType))
+	if messageType == "" {
+		messageType = victoropsMessageTypeBySeverity[strings.ToLower(data.CommonLabels["severity"])]
+	}
 	if messageType == "" {
 		messageType = victoropsAlertStateCritical
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/prometheus/alertmanager/notify"
 	"github.com/prometheus/alertmanager/types"
@@ -11,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/securejsondata"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/models"
 )
@@ -23,12 +25,14 @@ func TestVictoropsNotifier(t *testing.T) {
 	tmpl.ExternalURL = externalURL
 
 	cases := []struct {
-		name         string
-		settings     string
-		alerts       []*types.Alert
-		expMsg       string
-		expInitError string
-		expMsgError  error
+		name           string
+		settings       string
+		secureSettings map[string]string
+		alerts         []*types.Alert
+		expURL         string
+		expMsg         string
+		expInitError   string
+		expMsgError    error
 	}{
 		{
 			name:     "One alert",
@@ -75,6 +79,65 @@ func TestVictoropsNotifier(t *testing.T) {
 			  "state_message": "**Firing**\n\nLabels:\n - alertname = alert1\n - lbl1 = val1\nAnnotations:\n - ann1 = annv1\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Clbl1%3Dval1\n\nLabels:\n - alertname = alert1\n - lbl1 = val2\nAnnotations:\n - ann1 = annv2\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Clbl1%3Dval2\n"
 			}`,
 			expMsgError: nil,
+		}, {
+			name:           "Message type from the severity label and url from the secure settings",
+			settings:       `{}`,
+			secureSettings: map[string]string{"url": "https://alert.victorops.com/integrations/generic/20131114/alert/apikey/routingkey"},
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels: model.LabelSet{"alertname": "alert1", "severity": "warning"},
+					},
+				},
+			},
+			expURL: "https://alert.victorops.com/integrations/generic/20131114/alert/apikey/routingkey",
+			expMsg: `{
+			  "alert_url": "http://localhost/alerting/list",
+			  "entity_display_name": "[FIRING:1]  (warning)",
+			  "entity_id": "6e3538104c14b583da237e9693b76debbc17f0f8058ef20492e5853096cf8733",
+			  "message_type": "WARNING",
+			  "monitoring_tool": "Grafana v",
+			  "state_message": "**Firing**\n\nLabels:\n - alertname = alert1\n - severity = warning\nAnnotations:\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Cseverity%3Dwarning\n"
+			}`,
+		}, {
+			name:     "Message type from the settings over the severity label",
+			settings: `{"url": "http://localhost", "messageType": "critical"}`,
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels: model.LabelSet{"alertname": "alert1", "severity": "info"},
+					},
+				},
+			},
+			expURL: "http://localhost",
+			expMsg: `{
+			  "alert_url": "http://localhost/alerting/list",
+			  "entity_display_name": "[FIRING:1]  (info)",
+			  "entity_id": "6e3538104c14b583da237e9693b76debbc17f0f8058ef20492e5853096cf8733",
+			  "message_type": "CRITICAL",
+			  "monitoring_tool": "Grafana v",
+			  "state_message": "**Firing**\n\nLabels:\n - alertname = alert1\n - severity = info\nAnnotations:\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Cseverity%3Dinfo\n"
+			}`,
+		}, {
+			name:     "Resolved alerts recover the incident",
+			settings: `{"url": "http://localhost", "messageType": "WARNING"}`,
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels: model.LabelSet{"alertname": "alert1"},
+						EndsAt: time.Now().Add(-1 * time.Minute),
+					},
+				},
+			},
+			expURL: "http://localhost",
+			expMsg: `{
+			  "alert_url": "http://localhost/alerting/list",
+			  "entity_display_name": "[RESOLVED]  ",
+			  "entity_id": "6e3538104c14b583da237e9693b76debbc17f0f8058ef20492e5853096cf8733",
+			  "message_type": "RECOVERY",
+			  "monitoring_tool": "Grafana v",
+			  "state_message": "**Resolved**\n\nLabels:\n - alertname = alert1\nAnnotations:\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1\n"
+			}`,
 		}, {
 			name:         "Error in initing, no URL",
 			settings:     `{}`,
@@ -88,9 +151,10 @@ func TestVictoropsNotifier(t *testing.T) {
 			require.NoError(t, err)
 
 			m := &NotificationChannelConfig{
-				Name:     "victorops_testing",
-				Type:     "victorops",
-				Settings: settingsJSON,
+				Name:           "victorops_testing",
+				Type:           "victorops",
+				Settings:       settingsJSON,
+				SecureSettings: securejsondata.GetEncryptedJsonData(c.secureSettings),
 			}
 
 			pn, err := NewVictoropsNotifier(m, tmpl)
@@ -102,8 +166,10 @@ func TestVictoropsNotifier(t *testing.T) {
 			require.NoError(t, err)
 
 			body := ""
+			u := ""
 			bus.AddHandlerCtx("test", func(ctx context.Context, webhook *models.SendWebhookSync) error {
 				body = webhook.Body
+				u = webhook.Url
 				return nil
 			})
 
@@ -128,6 +194,9 @@ func TestVictoropsNotifier(t *testing.T) {
 			body = string(b)
 
 			require.JSONEq(t, c.expMsg, body)
+			if c.expURL != "" {
+				require.Equal(t, c.expURL, u)
+			}
 		})
 	}
 }
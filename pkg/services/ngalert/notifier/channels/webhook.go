@@ -2,7 +2,14 @@ package channels
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	texttemplate "text/template"
 
 	"github.com/prometheus/alertmanager/notify"
 	"github.com/prometheus/alertmanager/template"
@@ -24,8 +31,17 @@ type WebhookNotifier struct {
 	Password   string
 	HTTPMethod string
 	MaxAlerts  int
-	log        log.Logger
-	tmpl       *template.Template
+	Title      string
+	Message    string
+	// Payload is the template of the body of the request, which replaces the webhook message when set.
+	Payload     string
+	ContentType string
+	// Headers are the templates of the values of the headers of the request, by name.
+	Headers map[string]string
+	// TLSConfig has the client certificate and the CA of the receivers behind mutual TLS.
+	TLSConfig *tls.Config
+	log       log.Logger
+	tmpl      *template.Template
 }
 
 // NewWebHookNotifier is the constructor for
@@ -38,6 +54,29 @@ func NewWebHookNotifier(model *NotificationChannelConfig, t *template.Template)
 	if url == "" {
 		return nil, receiverInitError{Cfg: *model, Reason: "could not find url property in settings"}
 	}
+	httpMethod := model.Settings.Get("httpMethod").MustString(http.MethodPost)
+	if httpMethod != http.MethodPost && httpMethod != http.MethodPut && httpMethod != http.MethodPatch {
+		return nil, receiverInitError{Cfg: *model, Reason: fmt.Sprintf("unsupported HTTP method %q, it must be POST, PUT or PATCH", httpMethod)}
+	}
+	payload := model.Settings.Get("payload").MustString()
+	if payload != "" {
+		if _, err := texttemplate.New("payload").Funcs(texttemplate.FuncMap(template.DefaultFuncs)).Parse(payload); err != nil {
+			return nil, receiverInitError{Cfg: *model, Reason: "invalid payload template", Err: err}
+		}
+	}
+	headers, err := webhookHeaders(model.Settings.Get("httpHeaders").Interface())
+	if err != nil {
+		return nil, receiverInitError{Cfg: *model, Reason: "invalid HTTP headers", Err: err}
+	}
+	tlsConfig, err := webhookTLSConfig(
+		model.Settings.Get("tlsCACert").MustString(),
+		model.Settings.Get("tlsClientCert").MustString(),
+		model.DecryptedValue("tlsClientKey", model.Settings.Get("tlsClientKey").MustString()),
+		model.Settings.Get("tlsSkipVerify").MustBool(false),
+	)
+	if err != nil {
+		return nil, receiverInitError{Cfg: *model, Reason: "invalid TLS configuration", Err: err}
+	}
 	return &WebhookNotifier{
 		NotifierBase: old_notifiers.NewNotifierBase(&models.AlertNotification{
 			Uid:                   model.UID,
@@ -46,16 +85,87 @@ func NewWebHookNotifier(model *NotificationChannelConfig, t *template.Template)
 			DisableResolveMessage: model.DisableResolveMessage,
 			Settings:              model.Settings,
 		}),
-		URL:        url,
-		User:       model.Settings.Get("username").MustString(),
-		Password:   model.DecryptedValue("password", model.Settings.Get("password").MustString()),
-		HTTPMethod: model.Settings.Get("httpMethod").MustString("POST"),
-		MaxAlerts:  model.Settings.Get("maxAlerts").MustInt(0),
-		log:        log.New("alerting.notifier.webhook"),
-		tmpl:       t,
+		URL:         url,
+		User:        model.Settings.Get("username").MustString(),
+		Password:    model.DecryptedValue("password", model.Settings.Get("password").MustString()),
+		HTTPMethod:  httpMethod,
+		MaxAlerts:   model.Settings.Get("maxAlerts").MustInt(0),
+		Title:       model.Settings.Get("title").MustString(`{{ template "default.title" . }}`),
+		Message:     model.Settings.Get("message").MustString(`{{ template "default.message" . }}`),
+		Payload:     payload,
+		ContentType: model.Settings.Get("contentType").MustString("application/json"),
+		Headers:     headers,
+		TLSConfig:   tlsConfig,
+		log:         log.New("alerting.notifier.webhook"),
+		tmpl:        t,
 	}, nil
 }
 
+// webhookTLSConfig returns the TLS configuration of the requests with the PEM encoded CA
+// certificates and client certificate and key, or nil when the default one is used.
+func webhookTLSConfig(caCert, clientCert, clientKey string, skipVerify bool) (*tls.Config, error) {
+	if caCert == "" && clientCert == "" && clientKey == "" && !skipVerify {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{InsecureSkipVerify: skipVerify}
+	if caCert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(caCert)) {
+			return nil, errors.New("the CA certificate is not a valid PEM encoded certificate")
+		}
+		cfg.RootCAs = pool
+	}
+	if clientCert != "" || clientKey != "" {
+		if clientCert == "" || clientKey == "" {
+			return nil, errors.New("both the client certificate and key are required")
+		}
+		cert, err := tls.X509KeyPair([]byte(clientCert), []byte(clientKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate or key: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// webhookHeaders reads the HTTP headers of the settings, either an object of the values by name,
+// or a text with a "Name: value" header per line.
+func webhookHeaders(v interface{}) (map[string]string, error) {
+	headers := map[string]string{}
+	switch v := v.(type) {
+	case nil:
+	case map[string]interface{}:
+		for name, value := range v {
+			s, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("the value of header %q is not a string", name)
+			}
+			headers[name] = s
+		}
+	case string:
+		for _, line := range strings.Split(v, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			parts := strings.SplitN(line, ":", 2)
+			if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+				return nil, fmt.Errorf("the header %q is not in the \"Name: value\" format", line)
+			}
+			headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		}
+	default:
+		return nil, fmt.Errorf("the headers must be an object or a text")
+	}
+	for name := range headers {
+		if strings.EqualFold(name, "Content-Type") {
+			return nil, fmt.Errorf("the content type is set with the content type setting")
+		}
+	}
+	return headers, nil
+}
+
 // webhookMessage defines the JSON object send to webhook endpoints.
 type webhookMessage struct {
 	*ExtendedData
@@ -65,11 +175,13 @@ type webhookMessage struct {
 	GroupKey        string `json:"groupKey"`
 	TruncatedAlerts int    `json:"truncatedAlerts"`
 
-	// Deprecated, to be removed in 8.1.
-	// These are present to make migration a little less disruptive.
+	// Templated from the title and message settings of the webhook.
 	Title   string `json:"title"`
-	State   string `json:"state"`
 	Message string `json:"message"`
+
+	// Deprecated, to be removed in 8.1.
+	// This is present to make migration a little less disruptive.
+	State string `json:"state"`
 }
 
 // Notify implements the Notifier interface.
@@ -82,36 +194,38 @@ func (wn *WebhookNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool
 	as, numTruncated := truncateAlerts(wn.MaxAlerts, as)
 	var tmplErr error
 	tmpl, data := TmplText(ctx, wn.tmpl, as, wn.log, &tmplErr)
-	msg := &webhookMessage{
-		Version:         "1",
-		ExtendedData:    data,
-		GroupKey:        groupKey.String(),
-		TruncatedAlerts: numTruncated,
-		Title:           tmpl(`{{ template "default.title" . }}`),
-		Message:         tmpl(`{{ template "default.message" . }}`),
-	}
 
-	if types.Alerts(as...).Status() == model.AlertFiring {
-		msg.State = string(models.AlertStateAlerting)
+	var body string
+	if wn.Payload != "" {
+		body = tmpl(wn.Payload)
 	} else {
-		msg.State = string(models.AlertStateOK)
+		b, err := wn.message(groupKey.String(), numTruncated, as, data, tmpl)
+		if err != nil {
+			return false, err
+		}
+		body = string(b)
+	}
+	headers := make(map[string]string, len(wn.Headers))
+	for name, value := range wn.Headers {
+		headers[name] = tmpl(value)
 	}
-
 	if tmplErr != nil {
+		// a custom payload is not sent when it can't be templated, the receiver expects a fixed schema
+		if wn.Payload != "" {
+			return false, fmt.Errorf("failed to template the webhook payload: %w", tmplErr)
+		}
 		wn.log.Debug("failed to template webhook message", "err", tmplErr.Error())
 	}
 
-	body, err := json.Marshal(msg)
-	if err != nil {
-		return false, err
-	}
-
 	cmd := &models.SendWebhookSync{
-		Url:        wn.URL,
-		User:       wn.User,
-		Password:   wn.Password,
-		Body:       string(body),
-		HttpMethod: wn.HTTPMethod,
+		Url:         wn.URL,
+		User:        wn.User,
+		Password:    wn.Password,
+		Body:        body,
+		HttpMethod:  wn.HTTPMethod,
+		HttpHeader:  headers,
+		ContentType: wn.ContentType,
+		TLSConfig:   wn.TLSConfig,
 	}
 
 	if err := bus.DispatchCtx(ctx, cmd); err != nil {
@@ -121,6 +235,26 @@ func (wn *WebhookNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool
 	return true, nil
 }
 
+// message returns the default body of the webhook requests.
+func (wn *WebhookNotifier) message(groupKey string, numTruncated int, as []*types.Alert, data *ExtendedData, tmpl func(string) string) ([]byte, error) {
+	msg := &webhookMessage{
+		Version:         "1",
+		ExtendedData:    data,
+		GroupKey:        groupKey,
+		TruncatedAlerts: numTruncated,
+		Title:           tmpl(wn.Title),
+		Message:         tmpl(wn.Message),
+	}
+
+	if types.Alerts(as...).Status() == model.AlertFiring {
+		msg.State = string(models.AlertStateAlerting)
+	} else {
+		msg.State = string(models.AlertStateOK)
+	}
+
+	return json.Marshal(msg)
+}
+
 func truncateAlerts(maxAlerts int, alerts []*types.Alert) ([]*types.Alert, int) {
 	if maxAlerts > 0 && len(alerts) > maxAlerts {
 		return alerts[:maxAlerts], len(alerts) - maxAlerts
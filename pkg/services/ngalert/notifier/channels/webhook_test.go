@@ -165,6 +165,58 @@ func TestWebhookNotifier(t *testing.T) {
 				Message:         "**Firing**\n\nLabels:\n - alertname = alert1\n - lbl1 = val1\nAnnotations:\n - ann1 = annv1\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Clbl1%3Dval1\n\nLabels:\n - alertname = alert1\n - lbl1 = val2\nAnnotations:\n - ann1 = annv2\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3Dalert1%2Clbl1%3Dval2\n",
 			},
 			expMsgError: nil,
+		}, {
+			name: "Custom title and message with the image of the panel",
+			settings: `{
+				"url": "http://localhost/test",
+				"title": "{{ .CommonLabels.alertname }} is {{ .Status }}",
+				"message": "{{ range .Alerts }}{{ .Labels.instance }}: {{ .Values.B }}%{{ end }}"
+			}`,
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels:      model.LabelSet{"alertname": "DiskFull", "instance": "db-1"},
+						Annotations: model.LabelSet{"__values__": `{"B":95.5}`, "__image_url__": "https://images.example.com/db-1.png"},
+					},
+				},
+			},
+			expUrl:        "http://localhost/test",
+			expHttpMethod: "POST",
+			expMsg: &webhookMessage{
+				ExtendedData: &ExtendedData{
+					Receiver: "my_receiver",
+					Status:   "firing",
+					Alerts: ExtendedAlerts{
+						{
+							Status: "firing",
+							Labels: template.KV{
+								"alertname": "DiskFull",
+								"instance":  "db-1",
+							},
+							Annotations: template.KV{},
+							Fingerprint: "fd807b82f98f9135",
+							SilenceURL:  "http://localhost/alerting/silence/new?alertmanager=grafana&matchers=alertname%3DDiskFull%2Cinstance%3Ddb-1",
+							Values:      map[string]float64{"B": 95.5},
+							ImageURL:    "https://images.example.com/db-1.png",
+						},
+					},
+					GroupLabels: template.KV{
+						"alertname": "",
+					},
+					CommonLabels: template.KV{
+						"alertname": "DiskFull",
+						"instance":  "db-1",
+					},
+					CommonAnnotations: template.KV{},
+					ExternalURL:       "http://localhost",
+				},
+				Version:  "1",
+				GroupKey: "alertname",
+				Title:    "DiskFull is firing",
+				State:    "alerting",
+				Message:  "db-1: 95.5%",
+			},
+			expMsgError: nil,
 		}, {
 			name:         "Error in initing",
 			settings:     `{}`,
@@ -221,3 +273,155 @@ func TestWebhookNotifier(t *testing.T) {
 		})
 	}
 }
+
+func TestWebhookNotifierCustomRequest(t *testing.T) {
+	tmpl := templateForTests(t)
+
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	alerts := []*types.Alert{
+		{
+			Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "DiskFull", "instance": "db-1"},
+				Annotations: model.LabelSet{"summary": "The disk is full"},
+			},
+		},
+	}
+
+	cases := []struct {
+		name           string
+		settings       string
+		expBody        string
+		expHeaders     map[string]string
+		expContentType string
+		expHttpMethod  string
+		expInitError   string
+		expMsgError    string
+	}{
+		{
+			name: "Custom payload, headers and method",
+			settings: `{
+				"url": "http://localhost/incidents",
+				"httpMethod": "PATCH",
+				"payload": "{\"short_description\": \"{{ (index .Alerts 0).Annotations.summary }}\", \"count\": {{ len .Alerts.Firing }}}",
+				"httpHeaders": {"X-Source": "grafana", "X-Group": "{{ .CommonLabels.alertname }}"}
+			}`,
+			expBody:        `{"short_description": "The disk is full", "count": 1}`,
+			expHeaders:     map[string]string{"X-Source": "grafana", "X-Group": "DiskFull"},
+			expContentType: "application/json",
+			expHttpMethod:  "PATCH",
+		},
+		{
+			name: "Headers as text and custom content type",
+			settings: `{
+				"url": "http://localhost/incidents",
+				"payload": "alert={{ .CommonLabels.alertname }}",
+				"contentType": "application/x-www-form-urlencoded",
+				"httpHeaders": "X-Source: grafana\n\nAuthorization: Bearer token"
+			}`,
+			expBody:        `alert=DiskFull`,
+			expHeaders:     map[string]string{"X-Source": "grafana", "Authorization": "Bearer token"},
+			expContentType: "application/x-www-form-urlencoded",
+			expHttpMethod:  "POST",
+		},
+		{
+			name:         "Invalid payload template",
+			settings:     `{"url": "http://localhost/incidents", "payload": "{{ .Alerts"}`,
+			expInitError: `failed to validate receiver "webhook_testing" of type "webhook": invalid payload template: template: payload:1: unclosed action`,
+		},
+		{
+			name:         "Invalid header",
+			settings:     `{"url": "http://localhost/incidents", "httpHeaders": "X-Source"}`,
+			expInitError: `failed to validate receiver "webhook_testing" of type "webhook": invalid HTTP headers: the header "X-Source" is not in the "Name: value" format`,
+		},
+		{
+			name:         "Content type header",
+			settings:     `{"url": "http://localhost/incidents", "httpHeaders": {"Content-Type": "text/plain"}}`,
+			expInitError: `failed to validate receiver "webhook_testing" of type "webhook": invalid HTTP headers: the content type is set with the content type setting`,
+		},
+		{
+			name:         "Unsupported method",
+			settings:     `{"url": "http://localhost/incidents", "httpMethod": "GET"}`,
+			expInitError: `failed to validate receiver "webhook_testing" of type "webhook": unsupported HTTP method "GET", it must be POST, PUT or PATCH`,
+		},
+		{
+			name:        "Payload that can't be templated",
+			settings:    `{"url": "http://localhost/incidents", "payload": "{{ template \"undefined\" . }}"}`,
+			expMsgError: `failed to template the webhook payload: template: :1:12: executing "" at <{{template "undefined" .}}>: template "undefined" not defined`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			settingsJSON, err := simplejson.NewJson([]byte(c.settings))
+			require.NoError(t, err)
+
+			m := &NotificationChannelConfig{
+				Name:     "webhook_testing",
+				Type:     "webhook",
+				Settings: settingsJSON,
+			}
+
+			pn, err := NewWebHookNotifier(m, tmpl)
+			if c.expInitError != "" {
+				require.EqualError(t, err, c.expInitError)
+				return
+			}
+			require.NoError(t, err)
+
+			var payload *models.SendWebhookSync
+			bus.AddHandlerCtx("test", func(ctx context.Context, webhook *models.SendWebhookSync) error {
+				payload = webhook
+				return nil
+			})
+
+			ctx := notify.WithGroupKey(context.Background(), "alertname")
+			ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": ""})
+			ctx = notify.WithReceiverName(ctx, "my_receiver")
+			ok, err := pn.Notify(ctx, alerts...)
+			if c.expMsgError != "" {
+				require.False(t, ok)
+				require.EqualError(t, err, c.expMsgError)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			require.Equal(t, c.expBody, payload.Body)
+			require.Equal(t, c.expHeaders, payload.HttpHeader)
+			require.Equal(t, c.expContentType, payload.ContentType)
+			require.Equal(t, c.expHttpMethod, payload.HttpMethod)
+		})
+	}
+}
+
+func TestWebhookTLSConfig(t *testing.T) {
+	t.Run("the default TLS configuration is used without settings", func(t *testing.T) {
+		cfg, err := webhookTLSConfig("", "", "", false)
+		require.NoError(t, err)
+		require.Nil(t, cfg)
+	})
+
+	t.Run("invalid CA certificate", func(t *testing.T) {
+		_, err := webhookTLSConfig("not a certificate", "", "", false)
+		require.EqualError(t, err, "the CA certificate is not a valid PEM encoded certificate")
+	})
+
+	t.Run("client certificate without key", func(t *testing.T) {
+		_, err := webhookTLSConfig("", "-----BEGIN CERTIFICATE-----", "", false)
+		require.EqualError(t, err, "both the client certificate and key are required")
+	})
+
+	t.Run("invalid client certificate", func(t *testing.T) {
+		_, err := webhookTLSConfig("", "not a certificate", "not a key", false)
+		require.Error(t, err)
+	})
+
+	t.Run("skip verify", func(t *testing.T) {
+		cfg, err := webhookTLSConfig("", "", "", true)
+		require.NoError(t, err)
+		require.True(t, cfg.InsecureSkipVerify)
+	})
+}
@@ -0,0 +1,72 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// EscalationChains returns the escalation chains of the applied configuration.
+func (am *Alertmanager) EscalationChains() []apimodels.EscalationChain {
+	am.reloadConfigMtx.RLock()
+	defer am.reloadConfigMtx.RUnlock()
+	if !am.ready() {
+		return nil
+	}
+	return am.config.AlertmanagerConfig.EscalationChains
+}
+
+// NotifyReceiver sends the alerts that are not silenced or inhibited to the integrations of a
+// receiver, without going through the notification policies. It's used to escalate the alerts
+// that are not acknowledged in time.
+func (am *Alertmanager) NotifyReceiver(ctx context.Context, receiver string, postableAlerts apimodels.PostableAlerts) error {
+	am.reloadConfigMtx.RLock()
+	integrations, ok := am.integrations[receiver]
+	silencer, inhibitor := am.silencer, am.inhibitor
+	am.reloadConfigMtx.RUnlock()
+	if !ok {
+		return fmt.Errorf("receiver %q is undefined", receiver)
+	}
+
+	now := time.Now()
+	alerts := make([]*types.Alert, 0, len(postableAlerts.PostableAlerts))
+	fingerprints := make([]string, 0, len(postableAlerts.PostableAlerts))
+	for _, a := range postableAlerts.PostableAlerts {
+		alert := alertFromPostable(a, now)
+		if silencer.Mutes(alert.Labels) || inhibitor.Mutes(alert.Labels) {
+			continue
+		}
+		alerts = append(alerts, alert)
+		fingerprints = append(fingerprints, alert.Fingerprint().String())
+	}
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	// some integrations deduplicate the notifications with the same group key, so that it
+	// must be specific to the escalated alerts
+	sort.Strings(fingerprints)
+	ctx = notify.WithGroupKey(ctx, fmt.Sprintf("{}/escalation:{receiver=%q}:%s", receiver, strings.Join(fingerprints, ",")))
+	ctx = notify.WithReceiverName(ctx, receiver)
+	ctx = notify.WithGroupLabels(ctx, model.LabelSet{})
+	ctx = notify.WithNow(ctx, now)
+
+	var errs []string
+	for _, integration := range integrations {
+		if _, err := integration.Notify(ctx, alerts...); err != nil {
+			errs = append(errs, fmt.Sprintf("%s[%d]: %s", integration.Name(), integration.Index(), err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to notify receiver %q: %s", receiver, strings.Join(errs, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	gokit_log "github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/alertmanager/types"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// muteTimeInterval is a mute time interval of the configuration with its time zone loaded.
+type muteTimeInterval struct {
+	timeIntervals []timeinterval.TimeInterval
+	location      *time.Location
+}
+
+// contains tells whether the time is within one of the time intervals, in their time zone.
+func (mt muteTimeInterval) contains(t time.Time) bool {
+	t = t.In(mt.location)
+	for _, ti := range mt.timeIntervals {
+		if ti.ContainsTime(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func buildMuteTimeIntervals(intervals []apimodels.MuteTimeInterval) (map[string]muteTimeInterval, error) {
+	muteTimes := make(map[string]muteTimeInterval, len(intervals))
+	for _, mt := range intervals {
+		location, err := mt.LoadLocation()
+		if err != nil {
+			return nil, fmt.Errorf("invalid location of mute time interval %q: %w", mt.Name, err)
+		}
+		muteTimes[mt.Name] = muteTimeInterval{
+			timeIntervals: mt.TimeIntervals,
+			location:      location,
+		}
+	}
+	return muteTimes, nil
+}
+
+// timeMuteStage drops the alerts of the routes that are within one of their mute time intervals.
+// It's the TimeMuteStage of the Alertmanager, with the time intervals evaluated in their time zone.
+type timeMuteStage struct {
+	muteTimes map[string]muteTimeInterval
+}
+
+func newTimeMuteStage(muteTimes map[string]muteTimeInterval) *timeMuteStage {
+	return &timeMuteStage{muteTimes: muteTimes}
+}
+
+// Exec implements the notify.Stage interface.
+func (tms timeMuteStage) Exec(ctx context.Context, l gokit_log.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+	muteTimeIntervalNames, ok := notify.MuteTimeIntervalNames(ctx)
+	if !ok {
+		return ctx, alerts, nil
+	}
+	now, ok := notify.Now(ctx)
+	if !ok {
+		return ctx, alerts, errors.New("missing now timestamp")
+	}
+
+	for _, name := range muteTimeIntervalNames {
+		mt, ok := tms.muteTimes[name]
+		if !ok {
+			return ctx, alerts, fmt.Errorf("mute time interval %s doesn't exist in config", name)
+		}
+		if mt.contains(now) {
+			// If the current time is inside a mute time interval, all alerts are removed from the pipeline.
+			_ = level.Debug(l).Log("msg", "Notifications not sent, route is within mute time interval", "muteTimeInterval", name)
+			return ctx, nil, nil
+		}
+	}
+	return ctx, alerts, nil
+}
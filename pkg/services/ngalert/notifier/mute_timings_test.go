@@ -0,0 +1,88 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	gokit_log "github.com/go-kit/kit/log"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestTimeMuteStage(t *testing.T) {
+	var intervals []apimodels.MuteTimeInterval
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"name": "weekends", "time_intervals": [{"weekdays": ["saturday", "sunday"]}]},
+		{"name": "paris-nights", "location": "Europe/Paris", "time_intervals": [{"times": [{"start_time": "00:00", "end_time": "08:00"}]}]}
+	]`), &intervals))
+	muteTimes, err := buildMuteTimeIntervals(intervals)
+	require.NoError(t, err)
+	stage := newTimeMuteStage(muteTimes)
+
+	alerts := []*types.Alert{{Alert: model.Alert{Labels: model.LabelSet{"alertname": "test"}}}}
+
+	for _, tc := range []struct {
+		desc  string
+		names []string
+		now   time.Time
+		muted bool
+	}{
+		{
+			desc:  "route without mute time intervals",
+			now:   time.Date(2021, time.June, 5, 12, 0, 0, 0, time.UTC), // Saturday
+			muted: false,
+		},
+		{
+			desc:  "within the mute time interval",
+			names: []string{"weekends"},
+			now:   time.Date(2021, time.June, 5, 12, 0, 0, 0, time.UTC), // Saturday
+			muted: true,
+		},
+		{
+			desc:  "outside of the mute time interval",
+			names: []string{"weekends"},
+			now:   time.Date(2021, time.June, 7, 12, 0, 0, 0, time.UTC), // Monday
+			muted: false,
+		},
+		{
+			desc:  "within the mute time interval in its time zone",
+			names: []string{"weekends", "paris-nights"},
+			now:   time.Date(2021, time.June, 7, 23, 30, 0, 0, time.UTC), // 01:30 on Tuesday in Paris
+			muted: true,
+		},
+		{
+			desc:  "outside of the mute time interval in its time zone",
+			names: []string{"paris-nights"},
+			now:   time.Date(2021, time.June, 8, 7, 30, 0, 0, time.UTC), // 09:30 on Tuesday in Paris
+			muted: false,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := notify.WithNow(context.Background(), tc.now)
+			if tc.names != nil {
+				ctx = notify.WithMuteTimeIntervals(ctx, tc.names)
+			}
+
+			_, out, err := stage.Exec(ctx, gokit_log.NewNopLogger(), alerts...)
+			require.NoError(t, err)
+			if tc.muted {
+				require.Empty(t, out)
+			} else {
+				require.Equal(t, alerts, out)
+			}
+		})
+	}
+
+	t.Run("unknown mute time interval should return error", func(t *testing.T) {
+		ctx := notify.WithNow(context.Background(), time.Now())
+		ctx = notify.WithMuteTimeIntervals(ctx, []string{"holidays"})
+		_, _, err := stage.Exec(ctx, gokit_log.NewNopLogger(), alerts...)
+		require.EqualError(t, err, "mute time interval holidays doesn't exist in config")
+	})
+}
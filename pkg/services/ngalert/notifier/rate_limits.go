@@ -0,0 +1,114 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+)
+
+// suppressedAlertsName is the name of the alert that reports the alerts suppressed by a rate limit.
+const suppressedAlertsName = "NotificationsSuppressed"
+
+// rateLimiter tracks the notifications sent by an integration in the last hour, and the alerts of
+// the notifications it suppressed since the last one sent.
+type rateLimiter struct {
+	mtx          sync.Mutex
+	maxPerMinute int
+	maxPerHour   int
+	// sent are the times of the notifications sent in the last hour, in order.
+	sent []time.Time
+	// suppressed is the number of alerts of the notifications suppressed since the last one sent.
+	suppressed int
+}
+
+func (l *rateLimiter) setLimits(maxPerMinute, maxPerHour int) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.maxPerMinute = maxPerMinute
+	l.maxPerHour = maxPerHour
+}
+
+// allow tells whether a notification of the given number of alerts can be sent at now, and records it.
+// When it can, it also returns the number of alerts suppressed since the last notification sent.
+func (l *rateLimiter) allow(now time.Time, alerts int) (bool, int) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	i := 0
+	for i < len(l.sent) && now.Sub(l.sent[i]) >= time.Hour {
+		i++
+	}
+	l.sent = l.sent[i:]
+
+	lastMinute := 0
+	for _, t := range l.sent {
+		if now.Sub(t) < time.Minute {
+			lastMinute++
+		}
+	}
+	if (l.maxPerMinute > 0 && lastMinute >= l.maxPerMinute) || (l.maxPerHour > 0 && len(l.sent) >= l.maxPerHour) {
+		l.suppressed += alerts
+		return false, 0
+	}
+
+	l.sent = append(l.sent, now)
+	suppressed := l.suppressed
+	l.suppressed = 0
+	return true, suppressed
+}
+
+// rateLimitedNotifier doesn't send the notifications over the rate limit of its receiver. The
+// next notification sent has an additional alert with the number of alerts that were suppressed.
+type rateLimitedNotifier struct {
+	NotificationChannel
+	limiter *rateLimiter
+	logger  log.Logger
+	metrics *metrics.Metrics
+	// receiver and integration identify the integration in the logs and the metrics.
+	receiver    string
+	integration string
+}
+
+func (n *rateLimitedNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	now, ok := notify.Now(ctx)
+	if !ok {
+		now = time.Now()
+	}
+
+	allowed, suppressed := n.limiter.allow(now, len(as))
+	if !allowed {
+		n.logger.Warn("notification not sent, the rate limit of the receiver is reached", "receiver", n.receiver, "integration", n.integration, "alerts", len(as))
+		n.metrics.NotificationsRateLimited.WithLabelValues(n.receiver, n.integration).Inc()
+		return false, nil
+	}
+	if suppressed > 0 {
+		as = append(as, suppressedAlertsSummary(n.receiver, suppressed, now))
+	}
+	return n.NotificationChannel.Notify(ctx, as...)
+}
+
+// suppressedAlertsSummary returns the alert that reports the number of alerts suppressed by the rate limit of a receiver.
+func suppressedAlertsSummary(receiver string, suppressed int, now time.Time) *types.Alert {
+	return &types.Alert{
+		Alert: model.Alert{
+			Labels: model.LabelSet{
+				model.AlertNameLabel: suppressedAlertsName,
+				"receiver":           model.LabelValue(receiver),
+			},
+			Annotations: model.LabelSet{
+				"summary":     model.LabelValue(fmt.Sprintf("%d alerts suppressed", suppressed)),
+				"description": model.LabelValue(fmt.Sprintf("The notifications of %d alerts were not sent because the rate limit of contact point %q was reached.", suppressed, receiver)),
+			},
+			StartsAt: now,
+		},
+		UpdatedAt: now,
+	}
+}
@@ -0,0 +1,103 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+)
+
+func TestRateLimiter(t *testing.T) {
+	start := time.Unix(0, 0)
+
+	t.Run("the notifications over the limit per minute are suppressed", func(t *testing.T) {
+		l := &rateLimiter{}
+		l.setLimits(2, 0)
+
+		for i := 0; i < 2; i++ {
+			allowed, suppressed := l.allow(start.Add(time.Duration(i)*time.Second), 1)
+			require.True(t, allowed)
+			require.Equal(t, 0, suppressed)
+		}
+		allowed, _ := l.allow(start.Add(10*time.Second), 3)
+		require.False(t, allowed)
+		allowed, _ = l.allow(start.Add(20*time.Second), 2)
+		require.False(t, allowed)
+
+		// the first notification after the window reports the suppressed alerts
+		allowed, suppressed := l.allow(start.Add(time.Minute), 1)
+		require.True(t, allowed)
+		require.Equal(t, 5, suppressed)
+		allowed, suppressed = l.allow(start.Add(2*time.Minute), 1)
+		require.True(t, allowed)
+		require.Equal(t, 0, suppressed)
+	})
+
+	t.Run("the notifications over the limit per hour are suppressed", func(t *testing.T) {
+		l := &rateLimiter{}
+		l.setLimits(10, 3)
+
+		for i := 0; i < 3; i++ {
+			allowed, _ := l.allow(start.Add(time.Duration(i)*10*time.Minute), 1)
+			require.True(t, allowed)
+		}
+		allowed, _ := l.allow(start.Add(59*time.Minute), 1)
+		require.False(t, allowed)
+		allowed, suppressed := l.allow(start.Add(time.Hour), 1)
+		require.True(t, allowed)
+		require.Equal(t, 1, suppressed)
+	})
+}
+
+func TestRateLimitedNotifier(t *testing.T) {
+	channel := &recordingNotificationChannel{}
+	n := &rateLimitedNotifier{
+		NotificationChannel: channel,
+		limiter:             &rateLimiter{maxPerMinute: 1},
+		logger:              log.New("test"),
+		metrics:             metrics.NewMetrics(prometheus.NewRegistry()),
+		receiver:            "ops",
+		integration:         "email",
+	}
+	alert := func(name string) *types.Alert {
+		return &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": model.LabelValue(name)}}}
+	}
+	start := time.Unix(0, 0)
+
+	_, err := n.Notify(notify.WithNow(context.Background(), start), alert("DiskFull"))
+	require.NoError(t, err)
+	retry, err := n.Notify(notify.WithNow(context.Background(), start.Add(time.Second)), alert("HighCPU"), alert("HighMemory"))
+	require.NoError(t, err)
+	require.False(t, retry)
+	require.Len(t, channel.notifications, 1)
+
+	_, err = n.Notify(notify.WithNow(context.Background(), start.Add(time.Minute)), alert("DiskFull"))
+	require.NoError(t, err)
+	require.Len(t, channel.notifications, 2)
+	sent := channel.notifications[1]
+	require.Len(t, sent, 2)
+	require.Equal(t, model.LabelValue(suppressedAlertsName), sent[1].Labels[model.AlertNameLabel])
+	require.Equal(t, model.LabelValue("2 alerts suppressed"), sent[1].Annotations["summary"])
+	require.False(t, sent[1].Resolved())
+}
+
+type recordingNotificationChannel struct {
+	notifications [][]*types.Alert
+}
+
+func (n *recordingNotificationChannel) Notify(_ context.Context, as ...*types.Alert) (bool, error) {
+	n.notifications = append(n.notifications, as)
+	return false, nil
+}
+
+func (n *recordingNotificationChannel) SendResolved() bool {
+	return true
+}
@@ -60,19 +60,7 @@ func (e ReceiverTimeoutError) Error() string {
 func (am *Alertmanager) TestReceivers(ctx context.Context, c apimodels.TestReceiversConfigParams) (*TestReceiversResult, error) {
 	// now represents the start time of the test
 	now := time.Now()
-	testAlert := &types.Alert{
-		Alert: model.Alert{
-			Labels: model.LabelSet{
-				model.LabelName("alertname"): "TestAlert",
-				model.LabelName("instance"):  "Grafana",
-			},
-			Annotations: model.LabelSet{
-				model.LabelName("summary"): "Notification test",
-			},
-			StartsAt: now,
-		},
-		UpdatedAt: now,
-	}
+	testAlert := newTestAlert(now)
 
 	// we must set a group key that is unique per test as some receivers use this key to deduplicate alerts
 	ctx = notify.WithGroupKey(ctx, testAlert.Labels.String()+now.String())
@@ -224,3 +212,20 @@ func processNotifierError(config *apimodels.PostableGrafanaReceiver, err error)
 
 	return err
 }
+
+// newTestAlert returns the alert that is sent when testing the receivers.
+func newTestAlert(now time.Time) *types.Alert {
+	return &types.Alert{
+		Alert: model.Alert{
+			Labels: model.LabelSet{
+				model.LabelName("alertname"): "TestAlert",
+				model.LabelName("instance"):  "Grafana",
+			},
+			Annotations: model.LabelSet{
+				model.LabelName("summary"): "Notification test",
+			},
+			StartsAt: now,
+		},
+		UpdatedAt: now,
+	}
+}
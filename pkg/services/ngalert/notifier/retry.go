@@ -0,0 +1,107 @@
+package notifier
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// retryPolicy is how a notification is retried before it is recorded as failed.
+type retryPolicy struct {
+	maxAttempts int
+	// backoff is the delay before the first retry, it doubles after each attempt.
+	backoff time.Duration
+	// timeout is the timeout of each attempt, none when 0.
+	timeout time.Duration
+}
+
+// retryingNotifier retries a notification with a backoff, and records it in the store when all
+// its attempts failed. It never asks the Alertmanager to retry it, the notification is sent again
+// with the next notification of its group.
+type retryingNotifier struct {
+	NotificationChannel
+	policy retryPolicy
+	store  store.NotificationDeadLetterStore
+	logger log.Logger
+	// receiver, integrationName and integrationType identify the integration in the records.
+	receiver        string
+	integrationName string
+	integrationType string
+}
+
+func (n *retryingNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	maxAttempts := n.policy.maxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	backoff := n.policy.backoff
+
+	var (
+		attempt int
+		err     error
+	)
+	for attempt = 1; ; attempt++ {
+		err = n.attempt(ctx, as)
+		if err == nil {
+			return false, nil
+		}
+		if attempt >= maxAttempts {
+			break
+		}
+		n.logger.Debug("failed to send the notification, retrying", "receiver", n.receiver, "integration", n.integrationName, "attempt", attempt, "backoff", backoff, "err", err)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		backoff *= 2
+	}
+
+	n.logger.Warn("failed to send the notification", "receiver", n.receiver, "integration", n.integrationName, "attempts", attempt, "err", err)
+	n.saveDeadLetter(as, attempt, err)
+	return false, err
+}
+
+func (n *retryingNotifier) attempt(ctx context.Context, as []*types.Alert) error {
+	if n.policy.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, n.policy.timeout)
+		defer cancel()
+	}
+	_, err := n.NotificationChannel.Notify(ctx, as...)
+	return err
+}
+
+func (n *retryingNotifier) saveDeadLetter(as []*types.Alert, attempts int, err error) {
+	alerts := make(ngmodels.NotificationDeadLetterAlerts, 0, len(as))
+	for _, a := range as {
+		labels := make(map[string]string, len(a.Labels))
+		for k, v := range a.Labels {
+			labels[string(k)] = string(v)
+		}
+		alerts = append(alerts, labels)
+	}
+
+	if err := n.store.SaveNotificationDeadLetter(ngmodels.NotificationDeadLetter{
+		OrgID:           mainOrgID,
+		Receiver:        n.receiver,
+		IntegrationName: n.integrationName,
+		IntegrationType: n.integrationType,
+		Alerts:          alerts,
+		Attempts:        attempts,
+		Error:           err.Error(),
+		Epoch:           time.Now().UnixNano() / int64(time.Millisecond),
+	}); err != nil {
+		n.logger.Error("failed to record the failed notification", "receiver", n.receiver, "integration", n.integrationName, "err", err)
+	}
+}
@@ -0,0 +1,122 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestRetryingNotifier(t *testing.T) {
+	alert := &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "DiskFull"}}}
+
+	setup := func(failures int, policy retryPolicy) (*retryingNotifier, *failingNotificationChannel, *fakeDeadLetterStore) {
+		channel := &failingNotificationChannel{failures: failures}
+		deadLetters := &fakeDeadLetterStore{}
+		return &retryingNotifier{
+			NotificationChannel: channel,
+			policy:              policy,
+			store:               deadLetters,
+			logger:              log.New("test"),
+			receiver:            "ops",
+			integrationName:     "ops-webhook",
+			integrationType:     "webhook",
+		}, channel, deadLetters
+	}
+
+	t.Run("the notification is retried until it is sent", func(t *testing.T) {
+		n, channel, deadLetters := setup(2, retryPolicy{maxAttempts: 3, backoff: 10 * time.Millisecond})
+
+		start := time.Now()
+		retry, err := n.Notify(context.Background(), alert)
+		require.NoError(t, err)
+		require.False(t, retry)
+		require.Equal(t, 3, channel.attempts)
+		// the backoff doubles after each attempt
+		require.GreaterOrEqual(t, int64(time.Since(start)), int64(30*time.Millisecond))
+		require.Empty(t, deadLetters.saved)
+	})
+
+	t.Run("the notification is recorded when all its attempts failed", func(t *testing.T) {
+		n, channel, deadLetters := setup(5, retryPolicy{maxAttempts: 3, backoff: time.Millisecond})
+
+		retry, err := n.Notify(context.Background(), alert)
+		require.EqualError(t, err, "unavailable")
+		require.False(t, retry)
+		require.Equal(t, 3, channel.attempts)
+
+		require.Len(t, deadLetters.saved, 1)
+		d := deadLetters.saved[0]
+		require.Equal(t, "ops", d.Receiver)
+		require.Equal(t, "ops-webhook", d.IntegrationName)
+		require.Equal(t, "webhook", d.IntegrationType)
+		require.Equal(t, ngmodels.NotificationDeadLetterAlerts{{"alertname": "DiskFull"}}, d.Alerts)
+		require.Equal(t, 3, d.Attempts)
+		require.Equal(t, "unavailable", d.Error)
+	})
+
+	t.Run("the retries stop when the context is done", func(t *testing.T) {
+		n, channel, deadLetters := setup(5, retryPolicy{maxAttempts: 3, backoff: time.Hour})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := n.Notify(ctx, alert)
+		require.Error(t, err)
+		require.Equal(t, 1, channel.attempts)
+		require.Len(t, deadLetters.saved, 1)
+		require.Equal(t, 1, deadLetters.saved[0].Attempts)
+	})
+
+	t.Run("each attempt has a timeout", func(t *testing.T) {
+		n, channel, _ := setup(0, retryPolicy{maxAttempts: 1, timeout: time.Minute})
+
+		_, err := n.Notify(context.Background(), alert)
+		require.NoError(t, err)
+		deadline, ok := channel.ctx.Deadline()
+		require.True(t, ok)
+		require.WithinDuration(t, time.Now().Add(time.Minute), deadline, 5*time.Second)
+	})
+}
+
+type failingNotificationChannel struct {
+	failures int
+	attempts int
+	ctx      context.Context
+}
+
+func (n *failingNotificationChannel) Notify(ctx context.Context, _ ...*types.Alert) (bool, error) {
+	n.attempts++
+	n.ctx = ctx
+	if n.attempts <= n.failures {
+		return true, errors.New("unavailable")
+	}
+	return false, nil
+}
+
+func (n *failingNotificationChannel) SendResolved() bool {
+	return true
+}
+
+type fakeDeadLetterStore struct {
+	saved []ngmodels.NotificationDeadLetter
+}
+
+func (s *fakeDeadLetterStore) SaveNotificationDeadLetter(d ngmodels.NotificationDeadLetter) error {
+	s.saved = append(s.saved, d)
+	return nil
+}
+
+func (s *fakeDeadLetterStore) ListNotificationDeadLetters(_ *ngmodels.ListNotificationDeadLettersQuery) error {
+	return nil
+}
+
+func (s *fakeDeadLetterStore) DeleteNotificationDeadLettersBefore(_ int64) (int64, error) {
+	return 0, nil
+}
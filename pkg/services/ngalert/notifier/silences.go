@@ -23,7 +23,7 @@ func (am *Alertmanager) ListSilences(filter []string) (apimodels.GettableSilence
 	matchers, err := parseFilter(filter)
 	if err != nil {
 		am.logger.Error("failed to parse matchers", "err", err)
-		return nil, fmt.Errorf("%s: %w", ErrListSilencesBadPayload.Error(), err)
+		return nil, fmt.Errorf("%s: %w", err.Error(), ErrListSilencesBadPayload)
 	}
 
 	psils, _, err := am.silences.Query()
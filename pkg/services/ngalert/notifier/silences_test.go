@@ -0,0 +1,88 @@
+package notifier
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestSilences(t *testing.T) {
+	am := setupAMTest(t)
+
+	newSilence := func(startsAt, endsAt time.Time, matchers ...*models.Matcher) *apimodels.PostableSilence {
+		comment := "maintenance"
+		createdBy := "on-call"
+		starts := strfmt.DateTime(startsAt)
+		ends := strfmt.DateTime(endsAt)
+		return &apimodels.PostableSilence{
+			Silence: models.Silence{
+				Comment:   &comment,
+				CreatedBy: &createdBy,
+				StartsAt:  &starts,
+				EndsAt:    &ends,
+				Matchers:  matchers,
+			},
+		}
+	}
+	matcher := func(name, value string) *models.Matcher {
+		isEqual := true
+		isRegex := false
+		return &models.Matcher{Name: &name, Value: &value, IsEqual: &isEqual, IsRegex: &isRegex}
+	}
+
+	now := time.Now()
+
+	t.Run("invalid silences are rejected", func(t *testing.T) {
+		_, err := am.CreateSilence(newSilence(now.Add(time.Hour), now, matcher("alertname", "Alert1")))
+		require.True(t, errors.Is(err, ErrCreateSilenceBadPayload))
+
+		_, err = am.CreateSilence(newSilence(now.Add(-2*time.Hour), now.Add(-time.Hour), matcher("alertname", "Alert1")))
+		require.True(t, errors.Is(err, ErrCreateSilenceBadPayload))
+
+		_, err = am.CreateSilence(newSilence(now, now.Add(time.Hour)))
+		require.True(t, errors.Is(err, ErrCreateSilenceBadPayload))
+	})
+
+	firstID, err := am.CreateSilence(newSilence(now, now.Add(time.Hour), matcher("alertname", "Alert1")))
+	require.NoError(t, err)
+	secondID, err := am.CreateSilence(newSilence(now, now.Add(time.Hour), matcher("alertname", "Alert2"), matcher("team", "ops")))
+	require.NoError(t, err)
+
+	t.Run("silences can be listed and filtered by labels", func(t *testing.T) {
+		silences, err := am.ListSilences(nil)
+		require.NoError(t, err)
+		require.Len(t, silences, 2)
+
+		silences, err = am.ListSilences([]string{`team="ops"`})
+		require.NoError(t, err)
+		require.Len(t, silences, 1)
+		require.Equal(t, secondID, *silences[0].ID)
+
+		_, err = am.ListSilences([]string{`team=~"("`})
+		require.True(t, errors.Is(err, ErrListSilencesBadPayload))
+	})
+
+	t.Run("deleted silences are expired", func(t *testing.T) {
+		require.NoError(t, am.DeleteSilence(firstID))
+
+		silence, err := am.GetSilence(firstID)
+		require.NoError(t, err)
+		require.Equal(t, models.SilenceStatusStateExpired, *silence.Status.State)
+
+		silence, err = am.GetSilence(secondID)
+		require.NoError(t, err)
+		require.Equal(t, models.SilenceStatusStateActive, *silence.Status.State)
+	})
+
+	t.Run("unknown silences are not found", func(t *testing.T) {
+		_, err := am.GetSilence("unknown")
+		require.True(t, errors.Is(err, ErrSilenceNotFound))
+		require.True(t, errors.Is(am.DeleteSilence("unknown"), ErrSilenceNotFound))
+	})
+}
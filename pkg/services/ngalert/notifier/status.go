@@ -1,6 +1,8 @@
 package notifier
 
 import (
+	"github.com/go-openapi/strfmt"
+
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 )
 
@@ -12,5 +14,19 @@ func (am *Alertmanager) GetStatus() apimodels.GettableStatus {
 	if am.ready() {
 		config = am.config.AlertmanagerConfig
 	}
-	return *apimodels.NewGettableStatus(&config)
+	status := apimodels.NewGettableStatus(&config)
+
+	// the clients of the Alertmanager API expect the uptime and the version of the Alertmanager
+	uptime := strfmt.DateTime(am.startTime)
+	status.Uptime = &uptime
+	if v := am.Settings.BuildVersion; v != "" {
+		status.VersionInfo.Version = &v
+	}
+	if v := am.Settings.BuildCommit; v != "" {
+		status.VersionInfo.Revision = &v
+	}
+	if v := am.Settings.BuildBranch; v != "" {
+		status.VersionInfo.Branch = &v
+	}
+	return *status
 }
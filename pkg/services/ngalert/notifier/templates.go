@@ -0,0 +1,91 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	tmpltext "text/template"
+	"time"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels"
+)
+
+type InvalidTemplateError struct {
+	Err error
+}
+
+func (e InvalidTemplateError) Error() string {
+	return fmt.Sprintf("the template is invalid: %s", e.Err)
+}
+
+// ValidateTemplate checks that the content of a template file can be parsed with the functions
+// available to the notification templates.
+func ValidateTemplate(name, content string) error {
+	if _, err := tmpltext.New(name).Funcs(tmpltext.FuncMap(template.DefaultFuncs)).Parse(content); err != nil {
+		return InvalidTemplateError{Err: err}
+	}
+	return nil
+}
+
+// TestTemplate renders the text the way the contact points render their templated settings, with
+// the templates of the Alertmanager and the given alerts.
+func (am *Alertmanager) TestTemplate(ctx context.Context, c apimodels.TestTemplatePayload) (string, error) {
+	tmpl, err := am.getTemplate()
+	if err != nil {
+		return "", fmt.Errorf("failed to get template: %w", err)
+	}
+
+	now := time.Now()
+	alerts := make([]*types.Alert, 0, len(c.Alerts))
+	for _, a := range c.Alerts {
+		alerts = append(alerts, newTemplateTestAlert(a, now))
+	}
+	if len(alerts) == 0 {
+		alerts = append(alerts, newTestAlert(now))
+	}
+
+	ctx = notify.WithReceiverName(ctx, "TestReceiver")
+	ctx = notify.WithGroupLabels(ctx, model.LabelSet{})
+
+	var tmplErr error
+	text, _ := channels.TmplText(ctx, tmpl, alerts, am.logger, &tmplErr)
+	s := text(c.Template)
+	if tmplErr != nil {
+		return "", InvalidTemplateError{Err: tmplErr}
+	}
+	return s, nil
+}
+
+func newTemplateTestAlert(a apimodels.TestTemplateAlert, now time.Time) *types.Alert {
+	annotations := a.Annotations.Clone()
+	if annotations == nil {
+		annotations = model.LabelSet{}
+	}
+	// the values reach the templates the same way as the values of the evaluation of the alert rules
+	if len(a.Values) > 0 {
+		if b, err := json.Marshal(a.Values); err == nil {
+			annotations["__values__"] = model.LabelValue(b)
+		}
+	}
+
+	startsAt := a.StartsAt
+	if startsAt.IsZero() {
+		startsAt = now
+	}
+
+	return &types.Alert{
+		Alert: model.Alert{
+			Labels:      a.Labels,
+			Annotations: annotations,
+			StartsAt:    startsAt,
+			EndsAt:      a.EndsAt,
+		},
+		UpdatedAt: now,
+	}
+}
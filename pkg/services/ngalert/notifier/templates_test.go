@@ -0,0 +1,74 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestValidateTemplate(t *testing.T) {
+	require.NoError(t, ValidateTemplate("custom.tmpl", `{{ define "custom.title" }}{{ .Status | toUpper }}{{ end }}`))
+
+	err := ValidateTemplate("custom.tmpl", `{{ define "custom.title" }}{{ .Status | unknown }}{{ end }}`)
+	require.EqualError(t, err, `the template is invalid: template: custom.tmpl:1: function "unknown" not defined`)
+}
+
+func TestTestTemplate(t *testing.T) {
+	am := setupAMTest(t)
+
+	cfg, err := Load([]byte(`{
+		"template_files": {
+			"custom.tmpl": "{{ define \"custom.title\" }}{{ len .Alerts.Firing }} firing, {{ len .Alerts.Resolved }} resolved{{ end }}"
+		},
+		"alertmanager_config": {
+			"route": {"receiver": "default"},
+			"templates": ["custom.tmpl"],
+			"receivers": [{"name": "default", "grafana_managed_receiver_configs": [{"uid": "", "name": "email", "type": "email", "settings": {"addresses": "ops@example.com"}}]}]
+		}
+	}`))
+	require.NoError(t, err)
+	require.NoError(t, am.SaveAndApplyConfig(1, cfg))
+
+	t.Run("the saved templates are used", func(t *testing.T) {
+		text, err := am.TestTemplate(context.Background(), apimodels.TestTemplatePayload{
+			Template: `{{ template "custom.title" . }}`,
+			Alerts: []apimodels.TestTemplateAlert{
+				{Labels: model.LabelSet{"alertname": "DiskFull"}},
+				{Labels: model.LabelSet{"alertname": "DiskFull", "instance": "db-1"}, EndsAt: time.Now().Add(-time.Minute)},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "1 firing, 1 resolved", text)
+	})
+
+	t.Run("the templates defined in the text replace the saved ones", func(t *testing.T) {
+		text, err := am.TestTemplate(context.Background(), apimodels.TestTemplatePayload{
+			Template: `{{ define "custom.title" }}{{ .CommonLabels.alertname }}{{ end }}{{ template "custom.title" . }}`,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "TestAlert", text)
+	})
+
+	t.Run("the values of the alerts are available", func(t *testing.T) {
+		text, err := am.TestTemplate(context.Background(), apimodels.TestTemplatePayload{
+			Template: `{{ range .Alerts }}{{ .Labels.instance }} is {{ .Values.B }}% full{{ end }}`,
+			Alerts: []apimodels.TestTemplateAlert{
+				{Labels: model.LabelSet{"instance": "db-1"}, Values: map[string]float64{"B": 95.5}},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "db-1 is 95.5% full", text)
+	})
+
+	t.Run("an invalid template returns an error", func(t *testing.T) {
+		_, err := am.TestTemplate(context.Background(), apimodels.TestTemplatePayload{
+			Template: `{{ template "missing" . }}`,
+		})
+		require.ErrorAs(t, err, &InvalidTemplateError{})
+	})
+}
@@ -0,0 +1,152 @@
+package recording
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	prommodel "github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/tsdb"
+)
+
+// maxConcurrentEvaluations is the maximum number of recorded queries evaluated at once.
+const maxConcurrentEvaluations = 10
+
+// Recorder evaluates the active recorded queries at their interval, and writes their results.
+type Recorder struct {
+	store        store.RecordedQueryStore
+	writer       Writer
+	baseInterval time.Duration
+	metrics      *metrics.Metrics
+	log          log.Logger
+	// evaluate executes the queries and expressions of a recorded query.
+	evaluate func(orgID int64, data []models.AlertQuery, now time.Time) (*backend.QueryDataResponse, error)
+}
+
+// RecorderCfg is the configuration of the recorder.
+type RecorderCfg struct {
+	Store        store.RecordedQueryStore
+	Writer       Writer
+	Evaluator    eval.Evaluator
+	DataService  *tsdb.Service
+	BaseInterval time.Duration
+	Metrics      *metrics.Metrics
+	Logger       log.Logger
+}
+
+func NewRecorder(cfg RecorderCfg) *Recorder {
+	return &Recorder{
+		store:        cfg.Store,
+		writer:       cfg.Writer,
+		baseInterval: cfg.BaseInterval,
+		metrics:      cfg.Metrics,
+		log:          cfg.Logger,
+		evaluate: func(orgID int64, data []models.AlertQuery, now time.Time) (*backend.QueryDataResponse, error) {
+			return cfg.Evaluator.QueriesAndExpressionsEval(orgID, data, now, cfg.DataService)
+		},
+	}
+}
+
+// Run evaluates the recorded queries until the context is done.
+func (r *Recorder) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.baseInterval)
+	defer ticker.Stop()
+
+	var tickNum int64
+	for {
+		select {
+		case tick := <-ticker.C:
+			tickNum++
+			r.recordAll(ctx, tickNum, tick)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// recordAll evaluates the active recorded queries whose interval is due at the tick.
+func (r *Recorder) recordAll(ctx context.Context, tickNum int64, now time.Time) {
+	q := models.ListRecordedQueriesQuery{ActiveOnly: true}
+	if err := r.store.GetRecordedQueries(&q); err != nil {
+		r.log.Error("failed to get the recorded queries", "err", err)
+		return
+	}
+
+	baseIntervalSeconds := int64(r.baseInterval.Seconds())
+	sem := make(chan struct{}, maxConcurrentEvaluations)
+	var wg sync.WaitGroup
+	for _, rq := range q.Result {
+		freq := rq.IntervalSeconds / baseIntervalSeconds
+		if freq < 1 || tickNum%freq != 0 {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(rq *models.RecordedQuery) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			r.metrics.RecordedQueryEvaluations.Inc()
+			if err := r.record(ctx, rq, now); err != nil {
+				r.metrics.RecordedQueryFailures.Inc()
+				r.log.Error("failed to record the query", "org", rq.OrgID, "uid", rq.UID, "name", rq.Name, "err", err)
+			}
+		}(rq)
+	}
+	wg.Wait()
+}
+
+// record evaluates the recorded query and writes its results.
+func (r *Recorder) record(ctx context.Context, rq *models.RecordedQuery, now time.Time) error {
+	resp, err := r.evaluate(rq.OrgID, rq.Data, now)
+	if err != nil {
+		return err
+	}
+	res, ok := resp.Responses[rq.Target]
+	if !ok {
+		return fmt.Errorf("no result for the target %s", rq.Target)
+	}
+	if res.Error != nil {
+		return res.Error
+	}
+	return r.writer.Write(ctx, toSamples(rq, res.Frames, now))
+}
+
+// toSamples returns a sample for each numeric field of the frames, with the value of its last row
+// and the labels of the field and of the recorded query.
+func toSamples(rq *models.RecordedQuery, frames data.Frames, now time.Time) []Sample {
+	var samples []Sample
+	for _, frame := range frames {
+		for _, field := range frame.Fields {
+			if !field.Type().Numeric() || field.Len() == 0 {
+				continue
+			}
+			v, err := field.NullableFloatAt(field.Len() - 1)
+			if err != nil || v == nil {
+				continue
+			}
+
+			labels := make(map[string]string, len(field.Labels)+len(rq.Labels)+1)
+			for name, value := range field.Labels {
+				labels[name] = value
+			}
+			for name, value := range rq.Labels {
+				labels[name] = value
+			}
+			labels[prommodel.MetricNameLabel] = rq.Name
+			samples = append(samples, Sample{Labels: labels, Value: *v, Timestamp: now})
+		}
+	}
+	return samples
+}
@@ -0,0 +1,151 @@
+package recording
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptr "github.com/xorcare/pointer"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestToSamples(t *testing.T) {
+	now := time.Unix(1000, 0)
+	rq := &models.RecordedQuery{Name: "db_requests", Labels: map[string]string{"team": "db"}}
+
+	frames := data.Frames{
+		// a number per series, as returned by the reduce and math expressions
+		data.NewFrame("", data.NewField("", data.Labels{"instance": "a"}, []*float64{ptr.Float64(1)})),
+		data.NewFrame("", data.NewField("", data.Labels{"instance": "b"}, []*float64{nil})),
+		// the last value of a time series
+		data.NewFrame("",
+			data.NewField("time", nil, []time.Time{now.Add(-time.Minute), now}),
+			data.NewField("value", data.Labels{"instance": "c", "team": "web"}, []float64{2, 3}),
+		),
+		data.NewFrame("", data.NewField("value", nil, []float64{})),
+	}
+
+	assert.Equal(t, []Sample{
+		{Labels: map[string]string{"__name__": "db_requests", "instance": "a", "team": "db"}, Value: 1, Timestamp: now},
+		{Labels: map[string]string{"__name__": "db_requests", "instance": "c", "team": "db"}, Value: 3, Timestamp: now},
+	}, toSamples(rq, frames, now))
+}
+
+func TestRecordAll(t *testing.T) {
+	store := &fakeRecordedQueryStore{queries: []*models.RecordedQuery{
+		{OrgID: 1, UID: "every-tick", Name: "every_tick", Target: "A", IntervalSeconds: 10, Active: true},
+		{OrgID: 1, UID: "every-3-ticks", Name: "every_3_ticks", Target: "A", IntervalSeconds: 30, Active: true},
+		{OrgID: 1, UID: "failing", Name: "failing", Target: "B", IntervalSeconds: 10, Active: true},
+	}}
+	writer := &fakeWriter{}
+	m := metrics.NewMetrics(prometheus.NewRegistry())
+	r := &Recorder{
+		store:        store,
+		writer:       writer,
+		baseInterval: 10 * time.Second,
+		metrics:      m,
+		log:          log.New("test"),
+		evaluate: func(orgID int64, _ []models.AlertQuery, _ time.Time) (*backend.QueryDataResponse, error) {
+			return &backend.QueryDataResponse{Responses: backend.Responses{
+				"A": {Frames: data.Frames{data.NewFrame("", data.NewField("", nil, []*float64{ptr.Float64(5)}))}},
+			}}, nil
+		},
+	}
+
+	now := time.Unix(1000, 0)
+	r.recordAll(context.Background(), 1, now)
+	assert.ElementsMatch(t, []string{"every_tick"}, writer.names())
+	r.recordAll(context.Background(), 3, now)
+	assert.ElementsMatch(t, []string{"every_tick", "every_tick", "every_3_ticks"}, writer.names())
+
+	assert.Equal(t, float64(5), testutil.ToFloat64(m.RecordedQueryEvaluations))
+	// the target of the failing query has no result
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.RecordedQueryFailures))
+
+	writer.err = errors.New("remote write failed")
+	r.recordAll(context.Background(), 4, now)
+	assert.Equal(t, float64(4), testutil.ToFloat64(m.RecordedQueryFailures))
+}
+
+func TestRemoteWriter(t *testing.T) {
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	w, err := NewRemoteWriter(server.URL, "user", "password", time.Second)
+	require.NoError(t, err)
+	require.NoError(t, w.Write(context.Background(), []Sample{{Labels: map[string]string{"__name__": "metric"}, Value: 1, Timestamp: time.Now()}}))
+	assert.Equal(t, "snappy", headers.Get("Content-Encoding"))
+	assert.Equal(t, "Basic dXNlcjpwYXNzd29yZA==", headers.Get("Authorization"))
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	t.Cleanup(failing.Close)
+	w, err = NewRemoteWriter(failing.URL, "", "", time.Second)
+	require.NoError(t, err)
+	require.Error(t, w.Write(context.Background(), []Sample{{Labels: map[string]string{"__name__": "metric"}, Value: 1, Timestamp: time.Now()}}))
+}
+
+type fakeRecordedQueryStore struct {
+	queries []*models.RecordedQuery
+}
+
+func (s *fakeRecordedQueryStore) GetRecordedQueries(query *models.ListRecordedQueriesQuery) error {
+	query.Result = s.queries
+	return nil
+}
+
+func (s *fakeRecordedQueryStore) GetRecordedQueryByUID(*models.GetRecordedQueryByUIDQuery) error {
+	return models.ErrRecordedQueryNotFound
+}
+
+func (s *fakeRecordedQueryStore) SaveRecordedQuery(*models.RecordedQuery) error {
+	return nil
+}
+
+func (s *fakeRecordedQueryStore) DeleteRecordedQueryByUID(int64, string) error {
+	return nil
+}
+
+type fakeWriter struct {
+	mtx     sync.Mutex
+	samples []Sample
+	err     error
+}
+
+func (w *fakeWriter) Write(_ context.Context, samples []Sample) error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	if w.err != nil {
+		return w.err
+	}
+	w.samples = append(w.samples, samples...)
+	return nil
+}
+
+func (w *fakeWriter) names() []string {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	names := make([]string, 0, len(w.samples))
+	for _, s := range w.samples {
+		names = append(names, s.Labels["__name__"])
+	}
+	return names
+}
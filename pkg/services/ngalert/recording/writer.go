@@ -0,0 +1,77 @@
+package recording
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/m3db/prometheus_remote_client_golang/promremote"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// Sample is a value of a recorded series.
+type Sample struct {
+	// Labels are the labels of the series, including its metric name.
+	Labels    map[string]string
+	Value     float64
+	Timestamp time.Time
+}
+
+// Writer writes the samples of the recorded queries to a backend.
+type Writer interface {
+	Write(ctx context.Context, samples []Sample) error
+}
+
+// RemoteWriter writes the samples to a Prometheus remote write endpoint.
+type RemoteWriter struct {
+	client  promremote.Client
+	headers map[string]string
+}
+
+// NewRemoteWriter returns a writer to the remote write endpoint at the URL, with basic
+// authentication when the user is set.
+func NewRemoteWriter(url, user, password string, timeout time.Duration) (*RemoteWriter, error) {
+	client, err := promremote.NewClient(promremote.NewConfig(
+		promremote.WriteURLOption(url),
+		promremote.HTTPClientTimeoutOption(timeout),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the remote write client: %w", err)
+	}
+
+	headers := map[string]string{"User-Agent": fmt.Sprintf("Grafana/%s", setting.BuildVersion)}
+	if user != "" {
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+password))
+	}
+	return &RemoteWriter{client: client, headers: headers}, nil
+}
+
+func (w *RemoteWriter) Write(ctx context.Context, samples []Sample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	series := make(promremote.TSList, 0, len(samples))
+	for _, s := range samples {
+		labels := make([]promremote.Label, 0, len(s.Labels))
+		for name, value := range s.Labels {
+			labels = append(labels, promremote.Label{Name: name, Value: value})
+		}
+		// the labels of a series must be sorted by name
+		sort.Slice(labels, func(i, j int) bool {
+			return labels[i].Name < labels[j].Name
+		})
+		series = append(series, promremote.TimeSeries{
+			Labels:    labels,
+			Datapoint: promremote.Datapoint{Timestamp: s.Timestamp, Value: s.Value},
+		})
+	}
+
+	if _, err := w.client.WriteTimeSeries(ctx, series, promremote.WriteOptions{Headers: w.headers}); err != nil {
+		return fmt.Errorf("failed to write the samples: %w", err)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package schedule
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"path"
@@ -31,33 +32,68 @@ func FromAlertStateToPostableAlerts(logger log.Logger, firingStates []*state.Sta
 		if !alertState.NeedsSending(stateManager.ResendDelay) {
 			continue
 		}
-		nL := alertState.Labels.Copy()
-		nA := data.Labels(alertState.Annotations).Copy()
-
-		if len(alertState.Results) > 0 {
-			nA["__value_string__"] = alertState.Results[0].EvaluationString
-		}
-
-		genURL := appURL
-		if uid := nL[ngModels.RuleUIDLabel]; len(uid) > 0 && u != nil {
-			oldPath := u.Path
-			u.Path = path.Join(u.Path, fmt.Sprintf("/alerting/%s/edit", uid))
-			genURL = u.String()
-			u.Path = oldPath
-		}
-
-		alerts.PostableAlerts = append(alerts.PostableAlerts, models.PostableAlert{
-			Annotations: models.LabelSet(nA),
-			StartsAt:    strfmt.DateTime(alertState.StartsAt),
-			EndsAt:      strfmt.DateTime(alertState.EndsAt),
-			Alert: models.Alert{
-				Labels:       models.LabelSet(nL),
-				GeneratorURL: strfmt.URI(genURL),
-			},
-		})
+		alerts.PostableAlerts = append(alerts.PostableAlerts, newPostableAlert(alertState, appURL, u))
 		alertState.LastSentAt = ts
 		sentAlerts = append(sentAlerts, alertState)
 	}
 	stateManager.Put(sentAlerts)
 	return alerts
 }
+
+// newPostableAlert returns the alert of a state, with the URL of its alert rule in the application
+// at u as generator URL.
+func newPostableAlert(alertState *state.State, appURL string, u *url.URL) models.PostableAlert {
+	nL := alertState.Labels.Copy()
+	nA := data.Labels(alertState.Annotations).Copy()
+
+	if len(alertState.Results) > 0 {
+		nA["__value_string__"] = alertState.Results[0].EvaluationString
+		if values := encodeValues(alertState.Results[len(alertState.Results)-1].Values); values != "" {
+			nA["__values__"] = values
+		}
+	}
+
+	if alertState.ImageURL != "" {
+		nA["__image_url__"] = alertState.ImageURL
+	}
+	if alertState.ImagePath != "" {
+		nA["__image_path__"] = alertState.ImagePath
+	}
+
+	genURL := appURL
+	if uid := nL[ngModels.RuleUIDLabel]; len(uid) > 0 && u != nil {
+		oldPath := u.Path
+		u.Path = path.Join(u.Path, fmt.Sprintf("/alerting/%s/edit", uid))
+		genURL = u.String()
+		u.Path = oldPath
+	}
+
+	return models.PostableAlert{
+		Annotations: models.LabelSet(nA),
+		StartsAt:    strfmt.DateTime(alertState.StartsAt),
+		EndsAt:      strfmt.DateTime(alertState.EndsAt),
+		Alert: models.Alert{
+			Labels:       models.LabelSet(nL),
+			GeneratorURL: strfmt.URI(genURL),
+		},
+	}
+}
+
+// encodeValues returns the values of an evaluation as a JSON object by RefID, so that the
+// notification templates can use them. It returns an empty string when there are no values.
+func encodeValues(values map[string]state.EvaluationValue) string {
+	m := make(map[string]float64, len(values))
+	for refID, v := range values {
+		if v.Value != nil {
+			m[refID] = *v.Value
+		}
+	}
+	if len(m) == 0 {
+		return ""
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
@@ -0,0 +1,19 @@
+package schedule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+func TestEncodeValues(t *testing.T) {
+	value := 95.5
+	require.Equal(t, `{"B":95.5}`, encodeValues(map[string]state.EvaluationValue{
+		"B": {Value: &value},
+		"C": {Value: nil},
+	}))
+	require.Equal(t, "", encodeValues(map[string]state.EvaluationValue{"C": {Value: nil}}))
+	require.Equal(t, "", encodeValues(nil))
+}
@@ -0,0 +1,98 @@
+package schedule
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+// EscalationNotifier is a Notifier that can also notify the contact points of the escalation chains
+// about the firing alerts that are not acknowledged in time.
+type EscalationNotifier interface {
+	EscalationChains() []apimodels.EscalationChain
+	NotifyReceiver(ctx context.Context, receiver string, alerts apimodels.PostableAlerts) error
+}
+
+// escalations notifies the contact points of the steps of the first escalation chain that matches
+// each firing alert, once the alert is firing for longer than the delay of the step and as long as
+// it's not acknowledged.
+type escalations struct {
+	notifier     EscalationNotifier
+	stateManager *state.Manager
+	appURL       string
+	metrics      *metrics.Metrics
+	log          log.Logger
+	// usesInternalAlertmanager returns true if the alerts of an organization are sent to the
+	// Alertmanager whose escalation chains apply.
+	usesInternalAlertmanager func(orgID int64) bool
+}
+
+func (e *escalations) run(ctx context.Context, c clock.Clock, interval time.Duration) {
+	ticker := c.Ticker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			e.escalate(ctx, now)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// escalate notifies the contact points of the steps that became due. When several steps of an
+// alert are due at once, such as after its escalation chain changed, it's only escalated to the
+// last one.
+func (e *escalations) escalate(ctx context.Context, now time.Time) {
+	chains := e.notifier.EscalationChains()
+	if len(chains) == 0 {
+		return
+	}
+
+	byReceiver := make(map[string][]*state.State)
+	for _, s := range e.stateManager.GetFiring() {
+		if s.IsAcknowledged() || !e.usesInternalAlertmanager(s.OrgID) {
+			continue
+		}
+		lset := make(model.LabelSet, len(s.Labels))
+		for name, value := range s.Labels {
+			lset[model.LabelName(name)] = model.LabelValue(value)
+		}
+		for _, chain := range chains {
+			if !chain.Matches(lset) {
+				continue
+			}
+			if due := chain.DueStep(s.StartsAt, now); due > s.EscalationStep {
+				receiver := chain.Steps[due-1].Receiver
+				byReceiver[receiver] = append(byReceiver[receiver], s)
+				s.EscalationStep = due
+			}
+			break
+		}
+	}
+
+	u, err := url.Parse(e.appURL)
+	if err != nil {
+		u = nil
+	}
+	for receiver, states := range byReceiver {
+		alerts := apimodels.PostableAlerts{PostableAlerts: make([]models.PostableAlert, 0, len(states))}
+		for _, s := range states {
+			alerts.PostableAlerts = append(alerts.PostableAlerts, newPostableAlert(s, e.appURL, u))
+		}
+		e.metrics.Escalations.WithLabelValues(receiver).Add(float64(len(states)))
+		e.log.Debug("escalating alerts", "receiver", receiver, "count", len(states))
+		if err := e.notifier.NotifyReceiver(ctx, receiver, alerts); err != nil {
+			e.log.Error("failed to escalate alerts", "receiver", receiver, "count", len(states), "err", err)
+		}
+	}
+}
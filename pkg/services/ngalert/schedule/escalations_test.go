@@ -0,0 +1,122 @@
+package schedule
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+func TestEscalations(t *testing.T) {
+	critical, err := labels.NewMatcher(labels.MatchEqual, "severity", "critical")
+	require.NoError(t, err)
+	notifier := &fakeEscalationNotifier{
+		chains: []apimodels.EscalationChain{
+			{
+				Name:     "critical",
+				Matchers: config.Matchers{critical},
+				Steps: []apimodels.EscalationStep{
+					{After: model.Duration(10 * time.Minute), Receiver: "on-call"},
+					{After: model.Duration(time.Hour), Receiver: "managers"},
+				},
+			},
+			{
+				Name:  "others",
+				Steps: []apimodels.EscalationStep{{After: model.Duration(30 * time.Minute), Receiver: "team"}},
+			},
+		},
+	}
+
+	m := metrics.NewMetrics(prometheus.NewRegistry())
+	manager := state.NewManager(log.New("test"), m, nil, nil, nil)
+	t.Cleanup(manager.Close)
+
+	startsAt := time.Unix(1000, 0)
+	newState := func(id string, lbs data.Labels) *state.State {
+		return &state.State{AlertRuleUID: "rule", OrgID: 1, CacheId: id, State: eval.Alerting, StartsAt: startsAt, Labels: lbs}
+	}
+	db := newState("db", data.Labels{"alertname": "db", "severity": "critical"})
+	web := newState("web", data.Labels{"alertname": "web", "severity": "warning"})
+	acknowledged := newState("acknowledged", data.Labels{"alertname": "acknowledged", "severity": "critical"})
+	acknowledged.AcknowledgedBy, acknowledged.AcknowledgedAt = "admin", startsAt
+	external := newState("external", data.Labels{"alertname": "external", "severity": "critical"})
+	external.OrgID = 2
+	manager.Put([]*state.State{db, web, acknowledged, external})
+
+	e := &escalations{
+		notifier:     notifier,
+		stateManager: manager,
+		appURL:       "http://localhost:3000/",
+		metrics:      m,
+		log:          log.New("test"),
+		usesInternalAlertmanager: func(orgID int64) bool {
+			return orgID == 1
+		},
+	}
+
+	e.escalate(context.Background(), startsAt.Add(5*time.Minute))
+	assert.Empty(t, notifier.notified())
+
+	e.escalate(context.Background(), startsAt.Add(10*time.Minute))
+	e.escalate(context.Background(), startsAt.Add(11*time.Minute))
+	assert.Equal(t, []string{"on-call:db"}, notifier.notified())
+	assert.Equal(t, 1, db.EscalationStep)
+
+	e.escalate(context.Background(), startsAt.Add(45*time.Minute))
+	assert.Equal(t, []string{"on-call:db", "team:web"}, notifier.notified())
+
+	// once acknowledged, the alert is not escalated anymore
+	db.AcknowledgedBy, db.AcknowledgedAt = "editor", startsAt.Add(50*time.Minute)
+	e.escalate(context.Background(), startsAt.Add(2*time.Hour))
+	assert.Equal(t, []string{"on-call:db", "team:web"}, notifier.notified())
+
+	// the steps that became due at once are skipped but the last one
+	acknowledged.AcknowledgedBy, acknowledged.AcknowledgedAt = "", time.Time{}
+	e.escalate(context.Background(), startsAt.Add(2*time.Hour))
+	assert.Equal(t, []string{"on-call:db", "team:web", "managers:acknowledged"}, notifier.notified())
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.Escalations.WithLabelValues("on-call")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.Escalations.WithLabelValues("managers")))
+}
+
+type fakeEscalationNotifier struct {
+	fakeNotifier
+	chains []apimodels.EscalationChain
+
+	mtx           sync.Mutex
+	notifications []string
+}
+
+func (n *fakeEscalationNotifier) EscalationChains() []apimodels.EscalationChain {
+	return n.chains
+}
+
+func (n *fakeEscalationNotifier) NotifyReceiver(_ context.Context, receiver string, alerts apimodels.PostableAlerts) error {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	for _, a := range alerts.PostableAlerts {
+		n.notifications = append(n.notifications, receiver+":"+a.Labels["alertname"])
+	}
+	return nil
+}
+
+func (n *fakeEscalationNotifier) notified() []string {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	return append([]string(nil), n.notifications...)
+}
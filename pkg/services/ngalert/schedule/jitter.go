@@ -0,0 +1,45 @@
+package schedule
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// JitterStrategy tells how the evaluations of the alert rules are spread across their interval,
+// so that the alert rules with the same interval don't all query their data sources at the same tick.
+type JitterStrategy string
+
+const (
+	// JitterNone evaluates the alert rules at the start of their interval.
+	JitterNone JitterStrategy = "none"
+	// JitterByGroup evaluates the alert rules of a rule group together, at an offset derived from the group.
+	JitterByGroup JitterStrategy = "group"
+	// JitterByRule evaluates each alert rule at an offset derived from the rule.
+	JitterByRule JitterStrategy = "rule"
+)
+
+// evaluationOffset returns the number of ticks after the start of its interval at which the alert rule
+// is evaluated, given the number of ticks of its interval. The explicit offset of the rule group takes
+// precedence over the jitter. The jitter is derived from a hash so that it is the same on every tick and
+// on every instance, and a rule keeps being evaluated exactly once per interval.
+func evaluationOffset(r *models.AlertRule, frequency int64, baseIntervalSeconds int64, strategy JitterStrategy) int64 {
+	if frequency <= 1 {
+		return 0
+	}
+	if r.OffsetSeconds > 0 {
+		return (r.OffsetSeconds / baseIntervalSeconds) % frequency
+	}
+
+	h := fnv.New64a()
+	switch strategy {
+	case JitterByGroup:
+		_, _ = fmt.Fprintf(h, "%d/%s/%s", r.OrgID, r.NamespaceUID, r.RuleGroup)
+	case JitterByRule:
+		_, _ = fmt.Fprintf(h, "%d/%s", r.OrgID, r.UID)
+	default:
+		return 0
+	}
+	return int64(h.Sum64() % uint64(frequency))
+}
@@ -0,0 +1,63 @@
+package schedule
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluationOffset(t *testing.T) {
+	rules := make([]*models.AlertRule, 0, 100)
+	for i := 0; i < 100; i++ {
+		rules = append(rules, &models.AlertRule{
+			OrgID:        1,
+			UID:          fmt.Sprintf("rule-%d", i),
+			NamespaceUID: "folder",
+			RuleGroup:    fmt.Sprintf("group-%d", i/2),
+		})
+	}
+	const frequency = 6
+
+	t.Run("without jitter the rules are evaluated at the start of their interval", func(t *testing.T) {
+		for _, r := range rules {
+			assert.Equal(t, int64(0), evaluationOffset(r, frequency, 10, JitterNone))
+		}
+	})
+
+	for _, strategy := range []JitterStrategy{JitterByGroup, JitterByRule} {
+		t.Run(fmt.Sprintf("the rules are spread across their interval with the %s jitter", strategy), func(t *testing.T) {
+			offsets := map[int64]int{}
+			for _, r := range rules {
+				offset := evaluationOffset(r, frequency, 10, strategy)
+				require.GreaterOrEqual(t, offset, int64(0))
+				require.Less(t, offset, int64(frequency))
+				// the offset is the same on every tick
+				require.Equal(t, offset, evaluationOffset(r, frequency, 10, strategy))
+				offsets[offset]++
+			}
+			assert.Len(t, offsets, frequency)
+		})
+	}
+
+	t.Run("the rules of a group are evaluated together with the group jitter", func(t *testing.T) {
+		for i := 0; i < len(rules); i += 2 {
+			assert.Equal(t, evaluationOffset(rules[i], frequency, 10, JitterByGroup), evaluationOffset(rules[i+1], frequency, 10, JitterByGroup))
+		}
+	})
+
+	t.Run("the offset of the group takes precedence over the jitter", func(t *testing.T) {
+		r := *rules[0]
+		r.OffsetSeconds = 30
+		assert.Equal(t, int64(3), evaluationOffset(&r, frequency, 10, JitterNone))
+		assert.Equal(t, int64(3), evaluationOffset(&r, frequency, 10, JitterByRule))
+	})
+
+	t.Run("the rules evaluated at every tick have no offset", func(t *testing.T) {
+		assert.Equal(t, int64(0), evaluationOffset(rules[0], 1, 10, JitterByRule))
+		assert.Equal(t, int64(0), evaluationOffset(rules[0], 0, 10, JitterByRule))
+	})
+}
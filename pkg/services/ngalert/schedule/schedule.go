@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/sender"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/rendering"
 	"github.com/grafana/grafana/pkg/tsdb"
 
 	"github.com/benbjohnson/clock"
@@ -87,7 +88,22 @@ type schedule struct {
 	sendersMtx              sync.RWMutex
 	sendersCfgHash          map[int64]string
 	senders                 map[int64]*sender.Sender
+	sendAlertsTo            map[int64]models.AlertmanagersChoice
+	senderTLSConfig         sender.TLSConfig
 	adminConfigPollInterval time.Duration
+
+	// sharding partitions the evaluation of the alert rules across the Grafana instances,
+	// nil when every instance evaluates all the alert rules.
+	sharding *ruleSharding
+
+	// screenshots captures the panels of the alert rules, nil when it is disabled.
+	screenshots *screenshots
+
+	// escalations escalates the alerts that are not acknowledged in time, nil when the notifier can't.
+	escalations *escalations
+
+	// jitter spreads the evaluations of the alert rules across their interval.
+	jitter JitterStrategy
 }
 
 // SchedulerCfg is the scheduler configuration.
@@ -105,6 +121,15 @@ type SchedulerCfg struct {
 	Notifier                Notifier
 	Metrics                 *metrics.Metrics
 	AdminConfigPollInterval time.Duration
+	SenderTLSConfig         sender.TLSConfig
+	// EvaluationSharding partitions the evaluation of the alert rules across the instances recorded in PeerStore.
+	EvaluationSharding bool
+	PeerStore          store.SchedulerPeerStore
+	PeerTimeout        time.Duration
+	// RenderService captures the panels of the alert rules when their alerts start firing, nil to disable it.
+	RenderService rendering.Service
+	// EvaluationJitter spreads the evaluations of the alert rules across their interval, none by default.
+	EvaluationJitter JitterStrategy
 }
 
 // NewScheduler returns a new schedule.
@@ -130,7 +155,26 @@ func NewScheduler(cfg SchedulerCfg, dataService *tsdb.Service, appURL string, st
 		stateManager:            stateManager,
 		senders:                 map[int64]*sender.Sender{},
 		sendersCfgHash:          map[int64]string{},
+		sendAlertsTo:            map[int64]models.AlertmanagersChoice{},
+		senderTLSConfig:         cfg.SenderTLSConfig,
 		adminConfigPollInterval: cfg.AdminConfigPollInterval,
+		jitter:                  cfg.EvaluationJitter,
+	}
+	if cfg.EvaluationSharding {
+		sch.sharding = newRuleSharding(cfg.PeerStore, cfg.PeerTimeout, cfg.Logger)
+	}
+	if cfg.RenderService != nil {
+		sch.screenshots = newScreenshots(cfg.RenderService, cfg.Logger)
+	}
+	if n, ok := cfg.Notifier.(EscalationNotifier); ok {
+		sch.escalations = &escalations{
+			notifier:                 n,
+			stateManager:             stateManager,
+			appURL:                   appURL,
+			metrics:                  cfg.Metrics,
+			log:                      cfg.Logger,
+			usesInternalAlertmanager: sch.usesInternalAlertmanager,
+		}
 	}
 	return &sch
 }
@@ -171,6 +215,14 @@ func (sch *schedule) Run(ctx context.Context) error {
 		}
 	}()
 
+	if sch.escalations != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sch.escalations.run(ctx, sch.clock, sch.baseInterval)
+		}()
+	}
+
 	wg.Wait()
 	return nil
 }
@@ -187,8 +239,10 @@ func (sch *schedule) SyncAndApplyConfigFromDatabase() error {
 
 	orgsFound := make(map[int64]struct{}, len(cfgs))
 	sch.sendersMtx.Lock()
+	sendAlertsTo := make(map[int64]models.AlertmanagersChoice, len(cfgs))
 	for _, cfg := range cfgs {
 		orgsFound[cfg.OrgID] = struct{}{} // keep track of the which senders we need to keep.
+		sendAlertsTo[cfg.OrgID] = cfg.SendAlertsTo
 
 		existing, ok := sch.senders[cfg.OrgID]
 
@@ -224,7 +278,7 @@ func (sch *schedule) SyncAndApplyConfigFromDatabase() error {
 
 		// No sender and have Alertmanager(s) to send to - start a new one.
 		sch.log.Info("creating new sender for the external alertmanagers", "org", cfg.OrgID, "alertmanagers", cfg.Alertmanagers)
-		s, err := sender.New(sch.metrics)
+		s, err := sender.New(sch.metrics, sch.senderTLSConfig)
 		if err != nil {
 			sch.log.Error("unable to start the sender", "err", err, "org", cfg.OrgID)
 			continue
@@ -251,12 +305,14 @@ func (sch *schedule) SyncAndApplyConfigFromDatabase() error {
 			delete(sch.sendersCfgHash, orgID)
 		}
 	}
+	sch.sendAlertsTo = sendAlertsTo
 	sch.sendersMtx.Unlock()
 
 	// We can now stop these senders w/o having to hold a lock.
 	for orgID, s := range sendersToStop {
 		sch.log.Info("stopping sender", "org", orgID)
 		s.Stop()
+		sch.metrics.ExternalAlertmanagerQueueLength.DeleteLabelValues(fmt.Sprint(orgID))
 		sch.log.Info("stopped sender", "org", orgID)
 	}
 
@@ -265,6 +321,23 @@ func (sch *schedule) SyncAndApplyConfigFromDatabase() error {
 	return nil
 }
 
+// usesInternalAlertmanager returns true if the alerts of an organization are sent to the internal Alertmanager.
+func (sch *schedule) usesInternalAlertmanager(orgID int64) bool {
+	sch.sendersMtx.RLock()
+	defer sch.sendersMtx.RUnlock()
+	_, hasSender := sch.senders[orgID]
+	return sch.sendAlertsTo[orgID] != models.ExternalAlertmanagers || !hasSender
+}
+
+// updateSenderMetrics records the number of alerts waiting to be sent by the sender of each organization.
+func (sch *schedule) updateSenderMetrics() {
+	sch.sendersMtx.RLock()
+	defer sch.sendersMtx.RUnlock()
+	for orgID, s := range sch.senders {
+		sch.metrics.ExternalAlertmanagerQueueLength.WithLabelValues(fmt.Sprint(orgID)).Set(float64(s.QueueLength()))
+	}
+}
+
 // AlertmanagersFor returns all the discovered Alertmanager(s) for a particular organization.
 func (sch *schedule) AlertmanagersFor(orgID int64) []*url.URL {
 	sch.sendersMtx.RLock()
@@ -315,7 +388,12 @@ func (sch *schedule) ruleEvaluationLoop(ctx context.Context) error {
 	for {
 		select {
 		case tick := <-sch.heartbeat.C:
+			sch.metrics.SchedulerTickDrift.Observe(sch.clock.Now().Sub(tick).Seconds())
+			sch.updateSenderMetrics()
 			tickNum := tick.Unix() / int64(sch.baseInterval.Seconds())
+			if sch.sharding != nil {
+				sch.sharding.heartbeat(tick)
+			}
 			alertRules := sch.fetchAllDetails()
 			sch.log.Debug("alert rules fetched", "count", len(alertRules))
 
@@ -333,12 +411,25 @@ func (sch *schedule) ruleEvaluationLoop(ctx context.Context) error {
 			readyToRun := make([]readyToRunItem, 0)
 			for _, item := range alertRules {
 				key := item.GetKey()
+				// the alert rules evaluated by another instance are stopped like the deleted ones
+				if sch.sharding != nil && !sch.sharding.owns(key) {
+					continue
+				}
+				// the paused alert rules are stopped like the deleted ones as well
+				if item.IsPaused {
+					continue
+				}
+
 				itemVersion := item.Version
 				newRoutine := !sch.registry.exists(key)
 				ruleInfo := sch.registry.getOrCreateInfo(key, itemVersion)
 				invalidInterval := item.IntervalSeconds%int64(sch.baseInterval.Seconds()) != 0
 
 				if newRoutine && !invalidInterval {
+					if sch.sharding != nil {
+						// carry on from the states saved by the instance that evaluated the alert rule before
+						sch.stateManager.WarmRule(key.OrgID, key.UID)
+					}
 					dispatcherGroup.Go(func() error {
 						return sch.ruleRoutine(ctx, key, ruleInfo.evalCh, ruleInfo.stopCh)
 					})
@@ -352,7 +443,8 @@ func (sch *schedule) ruleEvaluationLoop(ctx context.Context) error {
 				}
 
 				itemFrequency := item.IntervalSeconds / int64(sch.baseInterval.Seconds())
-				if item.IntervalSeconds != 0 && tickNum%itemFrequency == 0 {
+				offset := evaluationOffset(item, itemFrequency, int64(sch.baseInterval.Seconds()), sch.jitter)
+				if item.IntervalSeconds != 0 && tickNum%itemFrequency == offset {
 					readyToRun = append(readyToRun, readyToRunItem{key: key, ruleInfo: ruleInfo})
 				}
 
@@ -382,6 +474,9 @@ func (sch *schedule) ruleEvaluationLoop(ctx context.Context) error {
 				}
 				ruleInfo.stopCh <- struct{}{}
 				sch.registry.del(key)
+				if sch.sharding != nil {
+					sch.stateManager.RemoveByRuleUID(key.OrgID, key.UID)
+				}
 			}
 		case <-ctx.Done():
 			waitErr := dispatcherGroup.Wait()
@@ -396,6 +491,9 @@ func (sch *schedule) ruleEvaluationLoop(ctx context.Context) error {
 			}
 
 			sch.stateManager.Close()
+			if sch.sharding != nil {
+				sch.sharding.leave()
+			}
 			return waitErr
 		}
 	}
@@ -436,15 +534,16 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key models.AlertRul
 				}
 				results, err := sch.evaluator.ConditionEval(&condition, ctx.now, sch.dataService)
 				var (
-					end    = timeNow()
-					tenant = fmt.Sprint(alertRule.OrgID)
-					dur    = end.Sub(start).Seconds()
+					end       = timeNow()
+					tenant    = fmt.Sprint(alertRule.OrgID)
+					ruleGroup = alertRule.NamespaceUID + ";" + alertRule.RuleGroup
+					dur       = end.Sub(start).Seconds()
 				)
 
-				sch.metrics.EvalTotal.WithLabelValues(tenant).Inc()
-				sch.metrics.EvalDuration.WithLabelValues(tenant).Observe(dur)
+				sch.metrics.EvalTotal.WithLabelValues(tenant, ruleGroup).Inc()
+				sch.metrics.EvalDuration.WithLabelValues(tenant, ruleGroup).Observe(dur)
 				if err != nil {
-					sch.metrics.EvalFailures.WithLabelValues(tenant).Inc()
+					sch.metrics.EvalFailures.WithLabelValues(tenant, ruleGroup).Inc()
 					// consider saving alert instance on error
 					sch.log.Error("failed to evaluate alert rule", "title", alertRule.Title,
 						"key", key, "attempt", attempt, "now", ctx.now, "duration", end.Sub(start), "error", err)
@@ -453,18 +552,26 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key models.AlertRul
 
 				processedStates := sch.stateManager.ProcessEvalResults(alertRule, results)
 				sch.saveAlertStates(processedStates)
+				if sch.screenshots != nil {
+					sch.screenshots.capture(grafanaCtx, alertRule, processedStates)
+				}
 				alerts := FromAlertStateToPostableAlerts(sch.log, processedStates, sch.stateManager, sch.appURL)
-				sch.log.Debug("sending alerts to notifier", "count", len(alerts.PostableAlerts), "alerts", alerts.PostableAlerts)
-				err = sch.notifier.PutAlerts(alerts)
-				if err != nil {
-					sch.log.Error("failed to put alerts in the notifier", "count", len(alerts.PostableAlerts), "err", err)
+				sch.sendersMtx.RLock()
+				defer sch.sendersMtx.RUnlock()
+				s, hasSender := sch.senders[alertRule.OrgID]
+				sendAlertsTo := sch.sendAlertsTo[alertRule.OrgID]
+
+				// Only skip the internal Alertmanager when the alerts can go to an external one.
+				if sendAlertsTo != models.ExternalAlertmanagers || !hasSender {
+					sch.log.Debug("sending alerts to notifier", "count", len(alerts.PostableAlerts), "alerts", alerts.PostableAlerts)
+					err = sch.notifier.PutAlerts(alerts)
+					if err != nil {
+						sch.log.Error("failed to put alerts in the notifier", "count", len(alerts.PostableAlerts), "err", err)
+					}
 				}
 
 				// Send alerts to external Alertmanager(s) if we have a sender for this organization.
-				sch.sendersMtx.RLock()
-				defer sch.sendersMtx.RUnlock()
-				s, ok := sch.senders[alertRule.OrgID]
-				if ok {
+				if hasSender && sendAlertsTo != models.InternalAlertmanager {
 					s.SendAlerts(alerts)
 				}
 
@@ -507,6 +614,10 @@ func (sch *schedule) saveAlertStates(states []*state.State) {
 			LastEvalTime:      s.LastEvaluationTime,
 			CurrentStateSince: s.StartsAt,
 			CurrentStateEnd:   s.EndsAt,
+			AcknowledgedBy:    s.AcknowledgedBy,
+			AcknowledgedAt:    s.AcknowledgedAt,
+			EscalationStep:    s.EscalationStep,
+			AnnotationID:      s.AnnotationID,
 		}
 		err := sch.instanceStore.SaveAlertInstance(&cmd)
 		if err != nil {
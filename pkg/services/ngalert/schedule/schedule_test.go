@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/ngalert/tests"
 
 	"github.com/benbjohnson/clock"
@@ -104,7 +105,7 @@ func TestWarmStateCache(t *testing.T) {
 		Metrics:                 metrics.NewMetrics(prometheus.NewRegistry()),
 		AdminConfigPollInterval: 10 * time.Minute, // do not poll in unit tests.
 	}
-	st := state.NewManager(schedCfg.Logger, nilMetrics, dbstore, dbstore)
+	st := state.NewManager(schedCfg.Logger, nilMetrics, dbstore, dbstore, dbstore)
 	st.Warm()
 
 	t.Run("instance cache has expected entries", func(t *testing.T) {
@@ -150,7 +151,7 @@ func TestAlertingTicker(t *testing.T) {
 		Metrics:                 metrics.NewMetrics(prometheus.NewRegistry()),
 		AdminConfigPollInterval: 10 * time.Minute, // do not poll in unit tests.
 	}
-	st := state.NewManager(schedCfg.Logger, nilMetrics, dbstore, dbstore)
+	st := state.NewManager(schedCfg.Logger, nilMetrics, dbstore, dbstore, dbstore)
 	sched := schedule.NewScheduler(schedCfg, nil, "http://localhost", st)
 
 	ctx := context.Background()
@@ -218,6 +219,38 @@ func TestAlertingTicker(t *testing.T) {
 		tick := advanceClock(t, mockedClock)
 		assertEvalRun(t, evalAppliedCh, tick, expectedAlertRulesEvaluated...)
 	})
+
+	setPaused := func(rule *models.AlertRule, isPaused bool) {
+		paused := *rule
+		paused.IsPaused = isPaused
+		err := dbstore.UpsertAlertRules([]store.UpsertRule{{Existing: rule, New: paused}})
+		require.NoError(t, err)
+		rule.Version++
+	}
+
+	// pause the alert rule with one second interval
+	setPaused(alerts[2], true)
+	t.Logf("alert rule: %v paused", alerts[2].GetKey())
+
+	expectedAlertRulesEvaluated = []models.AlertRuleKey{}
+	t.Run(fmt.Sprintf("on 8th tick alert rules: %s should be evaluated", concatenate(expectedAlertRulesEvaluated)), func(t *testing.T) {
+		tick := advanceClock(t, mockedClock)
+		assertEvalRun(t, evalAppliedCh, tick, expectedAlertRulesEvaluated...)
+	})
+	expectedAlertRulesStopped = []models.AlertRuleKey{alerts[2].GetKey()}
+	t.Run(fmt.Sprintf("on 8th tick alert rules: %s should be stopped", concatenate(expectedAlertRulesStopped)), func(t *testing.T) {
+		assertStopRun(t, stopAppliedCh, expectedAlertRulesStopped...)
+	})
+
+	// resume the alert rule
+	setPaused(alerts[2], false)
+	t.Logf("alert rule: %v resumed", alerts[2].GetKey())
+
+	expectedAlertRulesEvaluated = []models.AlertRuleKey{alerts[1].GetKey(), alerts[2].GetKey()}
+	t.Run(fmt.Sprintf("on 9th tick alert rules: %s should be evaluated", concatenate(expectedAlertRulesEvaluated)), func(t *testing.T) {
+		tick := advanceClock(t, mockedClock)
+		assertEvalRun(t, evalAppliedCh, tick, expectedAlertRulesEvaluated...)
+	})
 }
 
 func assertEvalRun(t *testing.T, ch <-chan evalAppliedInfo, tick time.Time, keys ...models.AlertRuleKey) {
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/rand"
+	"sync"
 	"testing"
 	"time"
 
@@ -91,6 +92,48 @@ func TestSendingToExternalAlertmanager(t *testing.T) {
 	}, 10*time.Second, 200*time.Millisecond)
 }
 
+func TestSendingToExternalAlertmanager_OnlyExternal(t *testing.T) {
+	t.Cleanup(registry.ClearOverrides)
+
+	fakeAM := NewFakeExternalAlertmanager(t)
+	defer fakeAM.Close()
+	fakeRuleStore := newFakeRuleStore(t)
+	fakeInstanceStore := &fakeInstanceStore{}
+	fakeAdminConfigStore := newFakeAdminConfigStore(t)
+
+	// create alert rule with one second interval
+	alertRule := CreateTestAlertRule(t, fakeRuleStore, 1, 1)
+
+	// Only send the alerts to the external Alertmanager.
+	adminConfig := &models.AdminConfiguration{OrgID: 1, Alertmanagers: []string{fakeAM.server.URL}, SendAlertsTo: models.ExternalAlertmanagers}
+	cmd := store.UpdateAdminConfigurationCmd{AdminConfiguration: adminConfig}
+	require.NoError(t, fakeAdminConfigStore.UpdateAdminConfiguration(cmd))
+
+	sched, mockedClock := setupScheduler(t, fakeRuleStore, fakeInstanceStore, fakeAdminConfigStore)
+	require.NoError(t, sched.SyncAndApplyConfigFromDatabase())
+	require.Eventually(t, func() bool {
+		return len(sched.AlertmanagersFor(1)) == 1
+	}, 10*time.Second, 200*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+	})
+	go func() {
+		err := sched.Run(ctx)
+		require.NoError(t, err)
+	}()
+
+	mockedClock.Add(2 * time.Second)
+
+	// The external Alertmanager receives the alerts, but not the internal one.
+	require.Eventually(t, func() bool {
+		return fakeAM.AlertsCount() >= 1 && fakeAM.AlertNamesCompare([]string{alertRule.Title})
+	}, 10*time.Second, 200*time.Millisecond)
+	require.Equal(t, 0, sched.notifier.(*fakeNotifier).AlertsCount())
+
+}
+
 func TestSendingToExternalAlertmanager_WithMultipleOrgs(t *testing.T) {
 	t.Cleanup(registry.ClearOverrides)
 
@@ -229,6 +272,68 @@ func TestSendingToExternalAlertmanager_WithMultipleOrgs(t *testing.T) {
 	}, 10*time.Second, 200*time.Millisecond)
 }
 
+func TestRuleEvaluationSharding(t *testing.T) {
+	t.Cleanup(registry.ClearOverrides)
+
+	fakeRuleStore := newFakeRuleStore(t)
+	fakeInstanceStore := &fakeInstanceStore{}
+	fakeAdminConfigStore := newFakeAdminConfigStore(t)
+	peerStore := newFakeSchedulerPeerStore()
+
+	rules := make([]*models.AlertRule, 0, 20)
+	for i := 0; i < 20; i++ {
+		rules = append(rules, CreateTestAlertRule(t, fakeRuleStore, 1, 1))
+	}
+
+	sched, mockedClock := setupScheduler(t, fakeRuleStore, fakeInstanceStore, fakeAdminConfigStore)
+	sched.sharding = &ruleSharding{peerID: "a", peerTimeout: time.Minute, store: peerStore, log: sched.log, peers: []string{"a"}}
+	// another instance shares the alert rules
+	require.NoError(t, peerStore.HeartbeatSchedulerPeer("b", mockedClock.Now().Unix()))
+
+	var (
+		mtx       sync.Mutex
+		evaluated = map[models.AlertRuleKey]struct{}{}
+	)
+	sched.evalAppliedFunc = func(key models.AlertRuleKey, _ time.Time) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		evaluated[key] = struct{}{}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() {
+		err := sched.Run(ctx)
+		require.NoError(t, err)
+	}()
+
+	mockedClock.Add(time.Second)
+
+	owned := map[models.AlertRuleKey]struct{}{}
+	require.Eventually(t, func() bool {
+		sched.sharding.mtx.RLock()
+		defer sched.sharding.mtx.RUnlock()
+		return len(sched.sharding.peers) == 2
+	}, 10*time.Second, 100*time.Millisecond)
+	for _, rule := range rules {
+		if sched.sharding.owns(rule.GetKey()) {
+			owned[rule.GetKey()] = struct{}{}
+		}
+	}
+	require.NotEmpty(t, owned)
+	require.Less(t, len(owned), len(rules))
+
+	require.Eventually(t, func() bool {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return len(evaluated) == len(owned)
+	}, 10*time.Second, 100*time.Millisecond)
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	require.Equal(t, owned, evaluated, "only the alert rules of the instance should be evaluated")
+}
+
 func setupScheduler(t *testing.T, rs store.RuleStore, is store.InstanceStore, acs store.AdminConfigurationStore) (*schedule, *clock.Mock) {
 	t.Helper()
 
@@ -248,7 +353,7 @@ func setupScheduler(t *testing.T, rs store.RuleStore, is store.InstanceStore, ac
 		Metrics:                 metrics.NewMetrics(prometheus.NewRegistry()),
 		AdminConfigPollInterval: 10 * time.Minute, // do not poll in unit tests.
 	}
-	st := state.NewManager(schedCfg.Logger, nilMetrics, rs, is)
+	st := state.NewManager(schedCfg.Logger, nilMetrics, rs, is, &fakeStateHistoryStore{})
 	return NewScheduler(schedCfg, nil, "http://localhost", st), mockedClock
 }
 
@@ -307,3 +412,43 @@ func CreateTestAlertRule(t *testing.T, dbstore *fakeRuleStore, intervalSeconds i
 	t.Logf("alert definition: %v with interval: %d created", rule.GetKey(), rule.IntervalSeconds)
 	return rule
 }
+
+func TestRuleEvaluationOffset(t *testing.T) {
+	t.Cleanup(registry.ClearOverrides)
+
+	fakeRuleStore := newFakeRuleStore(t)
+	fakeInstanceStore := &fakeInstanceStore{}
+	fakeAdminConfigStore := newFakeAdminConfigStore(t)
+
+	// the rule group is evaluated every three seconds, two seconds after the start of the interval
+	rule := CreateTestAlertRule(t, fakeRuleStore, 3, 1)
+	rule.OffsetSeconds = 2
+
+	sched, mockedClock := setupScheduler(t, fakeRuleStore, fakeInstanceStore, fakeAdminConfigStore)
+	evaluated := make(chan time.Time, 10)
+	sched.evalAppliedFunc = func(_ models.AlertRuleKey, now time.Time) {
+		evaluated <- now
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() {
+		err := sched.Run(ctx)
+		require.NoError(t, err)
+	}()
+
+	for i := 0; i < 6; i++ {
+		mockedClock.Add(time.Second)
+		tick := mockedClock.Now()
+		if tick.Unix()%3 != 2 {
+			continue
+		}
+		select {
+		case now := <-evaluated:
+			require.Equal(t, tick, now)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("the alert rule was not evaluated at %v", tick)
+		}
+	}
+	require.Empty(t, evaluated, "the alert rule should only be evaluated at its offset")
+}
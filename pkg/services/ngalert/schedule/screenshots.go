@@ -0,0 +1,106 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/imguploader"
+	"github.com/grafana/grafana/pkg/infra/log"
+	gfmodels "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// screenshotTimeout is the maximum time to render and upload the image of a panel, during which
+// the notifications of the alert rule are delayed.
+const screenshotTimeout = 15 * time.Second
+
+// screenshots captures the panel of an alert rule when its alerts start firing, so that the
+// notifications can show it.
+type screenshots struct {
+	renderService rendering.Service
+	newUploader   func() (imguploader.ImageUploader, error)
+	log           log.Logger
+}
+
+func newScreenshots(renderService rendering.Service, logger log.Logger) *screenshots {
+	return &screenshots{
+		renderService: renderService,
+		newUploader:   imguploader.NewImageUploader,
+		log:           logger,
+	}
+}
+
+// capture sets the image of the panel of the alert rule on the states that started firing. The
+// image is kept until the state stops firing, so that it is captured once per firing alert.
+func (s *screenshots) capture(ctx context.Context, alertRule *models.AlertRule, states []*state.State) {
+	var firing []*state.State
+	for _, st := range states {
+		if st.State != eval.Alerting {
+			st.ImageURL, st.ImagePath = "", ""
+			continue
+		}
+		if st.ImagePath == "" {
+			firing = append(firing, st)
+		}
+	}
+	if len(firing) == 0 {
+		return
+	}
+
+	dashboardUID := alertRule.Annotations["__dashboardUid__"]
+	panelID := alertRule.Annotations["__panelId__"]
+	if dashboardUID == "" || panelID == "" {
+		return
+	}
+	if !s.renderService.IsAvailable() {
+		s.log.Debug("the image renderer is not available, the panel of the alert rule is not captured", "uid", alertRule.UID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, screenshotTimeout)
+	defer cancel()
+
+	imagePath, imageURL, err := s.renderAndUpload(ctx, alertRule.OrgID, dashboardUID, panelID)
+	if err != nil {
+		s.log.Error("failed to capture the panel of the alert rule", "uid", alertRule.UID, "err", err)
+		if imagePath == "" {
+			return
+		}
+	}
+	for _, st := range firing {
+		st.ImagePath, st.ImageURL = imagePath, imageURL
+	}
+}
+
+func (s *screenshots) renderAndUpload(ctx context.Context, orgID int64, dashboardUID, panelID string) (string, string, error) {
+	uploader, err := s.newUploader()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create the image uploader: %w", err)
+	}
+
+	result, err := s.renderService.Render(ctx, rendering.Opts{
+		Width:           1000,
+		Height:          500,
+		Timeout:         screenshotTimeout,
+		OrgID:           orgID,
+		OrgRole:         gfmodels.ROLE_ADMIN,
+		ConcurrentLimit: setting.AlertingRenderLimit,
+		Path:            fmt.Sprintf("d-solo/%s?orgId=%d&panelId=%s", dashboardUID, orgID, panelID),
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to render the panel: %w", err)
+	}
+
+	// the URL is empty when there is no external image storage, the notifiers that can attach
+	// the image use its path instead
+	imageURL, err := uploader.Upload(ctx, result.FilePath)
+	if err != nil {
+		return result.FilePath, "", fmt.Errorf("failed to upload the image: %w", err)
+	}
+	return result.FilePath, imageURL, nil
+}
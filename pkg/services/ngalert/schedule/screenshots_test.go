@@ -0,0 +1,106 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/imguploader"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/rendering"
+)
+
+func TestScreenshots(t *testing.T) {
+	rule := &models.AlertRule{
+		OrgID:       1,
+		UID:         "rule",
+		Annotations: map[string]string{"__dashboardUid__": "dash", "__panelId__": "4"},
+	}
+
+	setup := func(t *testing.T, uploadedURL string, uploadErr error) (*screenshots, *fakeRenderService) {
+		t.Helper()
+		renderService := &fakeRenderService{available: true}
+		s := newScreenshots(renderService, log.New("test"))
+		s.newUploader = func() (imguploader.ImageUploader, error) {
+			return fakeImageUploader{url: uploadedURL, err: uploadErr}, nil
+		}
+		return s, renderService
+	}
+
+	t.Run("the panel is captured once for the alerts that start firing", func(t *testing.T) {
+		s, renderService := setup(t, "https://images.example.com/panel.png", nil)
+		firing := &state.State{State: eval.Alerting}
+		otherFiring := &state.State{State: eval.Alerting}
+		normal := &state.State{State: eval.Normal}
+
+		s.capture(context.Background(), rule, []*state.State{firing, otherFiring, normal})
+
+		require.Equal(t, []string{"d-solo/dash?orgId=1&panelId=4"}, renderService.paths)
+		for _, st := range []*state.State{firing, otherFiring} {
+			require.Equal(t, "/tmp/panel.png", st.ImagePath)
+			require.Equal(t, "https://images.example.com/panel.png", st.ImageURL)
+		}
+		require.Empty(t, normal.ImagePath)
+
+		// the alerts that are still firing keep their image
+		s.capture(context.Background(), rule, []*state.State{firing, otherFiring})
+		require.Len(t, renderService.paths, 1)
+
+		// the image is cleared when the alert stops firing
+		firing.State = eval.Normal
+		s.capture(context.Background(), rule, []*state.State{firing})
+		require.Empty(t, firing.ImagePath)
+		require.Empty(t, firing.ImageURL)
+	})
+
+	t.Run("the image is kept when it can't be uploaded", func(t *testing.T) {
+		s, _ := setup(t, "", errors.New("upload failed"))
+		firing := &state.State{State: eval.Alerting}
+
+		s.capture(context.Background(), rule, []*state.State{firing})
+
+		require.Equal(t, "/tmp/panel.png", firing.ImagePath)
+		require.Empty(t, firing.ImageURL)
+	})
+
+	t.Run("the panel is not captured without a panel or a renderer", func(t *testing.T) {
+		s, renderService := setup(t, "", nil)
+		firing := &state.State{State: eval.Alerting}
+
+		s.capture(context.Background(), &models.AlertRule{OrgID: 1, UID: "no-panel"}, []*state.State{firing})
+		renderService.available = false
+		s.capture(context.Background(), rule, []*state.State{firing})
+
+		require.Empty(t, renderService.paths)
+		require.Empty(t, firing.ImagePath)
+	})
+}
+
+type fakeRenderService struct {
+	rendering.Service
+	available bool
+	paths     []string
+}
+
+func (s *fakeRenderService) IsAvailable() bool {
+	return s.available
+}
+
+func (s *fakeRenderService) Render(_ context.Context, opts rendering.Opts) (*rendering.RenderResult, error) {
+	s.paths = append(s.paths, opts.Path)
+	return &rendering.RenderResult{FilePath: "/tmp/panel.png"}, nil
+}
+
+type fakeImageUploader struct {
+	url string
+	err error
+}
+
+func (u fakeImageUploader) Upload(_ context.Context, _ string) (string, error) {
+	return u.url, u.err
+}
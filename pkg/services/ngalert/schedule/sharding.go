@@ -0,0 +1,112 @@
+package schedule
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// ruleSharding partitions the evaluation of the alert rules across the Grafana instances that
+// share the database. Each instance records a heartbeat at every tick of the scheduler, and the
+// instances with a recent heartbeat are the peers that share the alert rules.
+type ruleSharding struct {
+	peerID      string
+	peerTimeout time.Duration
+	store       store.SchedulerPeerStore
+	log         log.Logger
+
+	mtx   sync.RWMutex
+	peers []string
+}
+
+func newRuleSharding(peerStore store.SchedulerPeerStore, peerTimeout time.Duration, logger log.Logger) *ruleSharding {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "grafana"
+	}
+	peerID := fmt.Sprintf("%s-%s", hostname, util.GenerateShortUID())
+
+	return &ruleSharding{
+		peerID:      peerID,
+		peerTimeout: peerTimeout,
+		store:       peerStore,
+		log:         logger,
+		peers:       []string{peerID},
+	}
+}
+
+// heartbeat records that the instance is evaluating alert rules and refreshes the list of peers.
+// The previous list of peers is kept if the database can't be reached.
+func (rs *ruleSharding) heartbeat(now time.Time) {
+	if err := rs.store.HeartbeatSchedulerPeer(rs.peerID, now.Unix()); err != nil {
+		rs.log.Error("failed to record the heartbeat of the scheduler", "peer", rs.peerID, "err", err)
+		return
+	}
+
+	since := now.Add(-rs.peerTimeout).Unix()
+	if err := rs.store.DeleteSchedulerPeersBefore(since); err != nil {
+		rs.log.Warn("failed to delete the expired scheduler peers", "err", err)
+	}
+
+	peers, err := rs.store.GetSchedulerPeers(since)
+	if err != nil {
+		rs.log.Error("failed to get the scheduler peers", "err", err)
+		return
+	}
+
+	rs.mtx.Lock()
+	defer rs.mtx.Unlock()
+	if strings.Join(peers, ",") != strings.Join(rs.peers, ",") {
+		rs.log.Info("scheduler peers changed", "peers", peers)
+	}
+	rs.peers = peers
+}
+
+// owns tells whether the instance evaluates the alert rule. The alert rule goes to the peer with
+// the highest hash of the peer and the alert rule, so that only the alert rules of a peer that
+// joins or leaves move to another peer.
+func (rs *ruleSharding) owns(key models.AlertRuleKey) bool {
+	rs.mtx.RLock()
+	defer rs.mtx.RUnlock()
+
+	var (
+		owner   string
+		maxHash uint64
+	)
+	for _, peer := range rs.peers {
+		h := fnv.New64a()
+		_, _ = fmt.Fprintf(h, "%s/%d/%s", peer, key.OrgID, key.UID)
+		if sum := mix64(h.Sum64()); owner == "" || sum > maxHash {
+			owner, maxHash = peer, sum
+		}
+	}
+	// when the instance doesn't know any peer, it evaluates all the alert rules
+	return owner == "" || owner == rs.peerID
+}
+
+// leave removes the instance from the peers, so that its alert rules move to the other peers at
+// their next tick.
+func (rs *ruleSharding) leave() {
+	if err := rs.store.DeleteSchedulerPeer(rs.peerID); err != nil {
+		rs.log.Error("failed to remove the scheduler peer", "peer", rs.peerID, "err", err)
+	}
+}
+
+// mix64 is the finalizer of MurmurHash3. The FNV hashes of the peers for the same alert rule only
+// differ by their prefix and are correlated, which would give most alert rules to the same peers.
+func mix64(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
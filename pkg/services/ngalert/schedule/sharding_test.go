@@ -0,0 +1,102 @@
+package schedule
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleSharding(t *testing.T) {
+	keys := make([]models.AlertRuleKey, 0, 100)
+	for i := 0; i < 100; i++ {
+		keys = append(keys, models.AlertRuleKey{OrgID: int64(i%3 + 1), UID: fmt.Sprintf("rule-%d", i)})
+	}
+
+	newPeers := func(peerStore *fakeSchedulerPeerStore, n int) []*ruleSharding {
+		peers := make([]*ruleSharding, 0, n)
+		for i := 0; i < n; i++ {
+			peers = append(peers, newRuleSharding(peerStore, time.Minute, log.New("ngalert.scheduler.test")))
+		}
+		return peers
+	}
+
+	heartbeat := func(now time.Time, peers ...*ruleSharding) {
+		for _, p := range peers {
+			p.heartbeat(now)
+		}
+		// the peers that joined before the others only learn about them at the next heartbeat
+		for _, p := range peers {
+			p.heartbeat(now)
+		}
+	}
+
+	owners := func(peers ...*ruleSharding) map[models.AlertRuleKey][]string {
+		result := make(map[models.AlertRuleKey][]string, len(keys))
+		for _, key := range keys {
+			for _, p := range peers {
+				if p.owns(key) {
+					result[key] = append(result[key], p.peerID)
+				}
+			}
+		}
+		return result
+	}
+
+	t.Run("an instance without peers evaluates all the alert rules", func(t *testing.T) {
+		p := newRuleSharding(newFakeSchedulerPeerStore(), time.Minute, log.New("ngalert.scheduler.test"))
+		for _, key := range keys {
+			require.True(t, p.owns(key))
+		}
+	})
+
+	t.Run("every alert rule is evaluated by a single instance", func(t *testing.T) {
+		peers := newPeers(newFakeSchedulerPeerStore(), 3)
+		heartbeat(time.Now(), peers...)
+
+		rulesByPeer := map[string]int{}
+		for key, o := range owners(peers...) {
+			require.Len(t, o, 1, "alert rule %v", key)
+			rulesByPeer[o[0]]++
+		}
+		require.Len(t, rulesByPeer, 3, "every instance should evaluate alert rules")
+	})
+
+	t.Run("only the alert rules of an instance that leaves move to the other instances", func(t *testing.T) {
+		peerStore := newFakeSchedulerPeerStore()
+		peers := newPeers(peerStore, 3)
+		now := time.Now()
+		heartbeat(now, peers...)
+		before := owners(peers...)
+
+		peers[2].leave()
+		heartbeat(now.Add(10*time.Second), peers[0], peers[1])
+		after := owners(peers[0], peers[1])
+
+		for _, key := range keys {
+			require.Len(t, after[key], 1, "alert rule %v", key)
+			if before[key][0] != peers[2].peerID {
+				assert.Equal(t, before[key], after[key], "alert rule %v should not have moved", key)
+			}
+		}
+	})
+
+	t.Run("the alert rules of an instance without recent heartbeat move to the other instances", func(t *testing.T) {
+		peerStore := newFakeSchedulerPeerStore()
+		peers := newPeers(peerStore, 2)
+		now := time.Now()
+		heartbeat(now, peers...)
+
+		peers[0].heartbeat(now.Add(2 * time.Minute))
+		require.Equal(t, []string{peers[0].peerID}, peers[0].peers)
+		for _, key := range keys {
+			require.True(t, peers[0].owns(key))
+		}
+		require.NotContains(t, peerStore.heartbeats, peers[1].peerID)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"sort"
 	"sync"
 	"testing"
 	"time"
@@ -116,6 +117,12 @@ func (f *fakeRuleStore) GetNamespaces(_ int64, _ *models2.SignedInUser) (map[str
 func (f *fakeRuleStore) GetNamespaceByTitle(_ string, _ int64, _ *models2.SignedInUser, _ bool) (*models2.Folder, error) {
 	return nil, nil
 }
+func (f *fakeRuleStore) GetAlertRuleVersions(_ *models.ListAlertRuleVersionsQuery) error {
+	return nil
+}
+func (f *fakeRuleStore) GetAlertRuleVersion(_ *models.GetAlertRuleVersionQuery) error {
+	return models.ErrAlertRuleVersionNotFound
+}
 func (f *fakeRuleStore) GetOrgRuleGroups(_ *models.ListOrgRuleGroupsQuery) error { return nil }
 func (f *fakeRuleStore) UpsertAlertRules(_ []store.UpsertRule) error             { return nil }
 func (f *fakeRuleStore) UpdateRuleGroup(cmd store.UpdateRuleGroupCmd) error {
@@ -144,17 +151,22 @@ func (f *fakeRuleStore) UpdateRuleGroup(cmd store.UpdateRuleGroupCmd) error {
 		}
 
 		new := &models.AlertRule{
-			OrgID:           cmd.OrgID,
-			Title:           r.GrafanaManagedAlert.Title,
-			Condition:       r.GrafanaManagedAlert.Condition,
-			Data:            r.GrafanaManagedAlert.Data,
-			UID:             util.GenerateShortUID(),
-			IntervalSeconds: int64(time.Duration(cmd.RuleGroupConfig.Interval).Seconds()),
-			NamespaceUID:    cmd.NamespaceUID,
-			RuleGroup:       cmd.RuleGroupConfig.Name,
-			NoDataState:     models.NoDataState(r.GrafanaManagedAlert.NoDataState),
-			ExecErrState:    models.ExecutionErrorState(r.GrafanaManagedAlert.ExecErrState),
-			Version:         1,
+			OrgID:            cmd.OrgID,
+			Title:            r.GrafanaManagedAlert.Title,
+			Condition:        r.GrafanaManagedAlert.Condition,
+			Data:             r.GrafanaManagedAlert.Data,
+			UID:              util.GenerateShortUID(),
+			IntervalSeconds:  int64(time.Duration(cmd.RuleGroupConfig.Interval).Seconds()),
+			OffsetSeconds:    int64(time.Duration(cmd.RuleGroupConfig.EvaluationOffset).Seconds()),
+			NamespaceUID:     cmd.NamespaceUID,
+			RuleGroup:        cmd.RuleGroupConfig.Name,
+			NoDataState:      models.NoDataState(r.GrafanaManagedAlert.NoDataState),
+			ExecErrState:     models.ExecutionErrorState(r.GrafanaManagedAlert.ExecErrState),
+			NoDataThreshold:  r.GrafanaManagedAlert.NoDataThreshold,
+			ExecErrThreshold: r.GrafanaManagedAlert.ExecErrThreshold,
+			Version:          1,
+
+			DisableDashboardAnnotations: r.GrafanaManagedAlert.DisableDashboardAnnotations,
 		}
 
 		if r.ApiRuleNode != nil {
@@ -189,6 +201,18 @@ func (f *fakeInstanceStore) SaveAlertInstance(_ *models.SaveAlertInstanceCommand
 func (f *fakeInstanceStore) FetchOrgIds() ([]int64, error)                              { return []int64{}, nil }
 func (f *fakeInstanceStore) DeleteAlertInstance(_ int64, _, _ string) error             { return nil }
 
+type fakeStateHistoryStore struct{}
+
+func (f *fakeStateHistoryStore) SaveAlertStateHistory(_ []models.AlertStateHistoryEntry) error {
+	return nil
+}
+func (f *fakeStateHistoryStore) ListAlertStateHistory(_ *models.ListAlertStateHistoryQuery) error {
+	return nil
+}
+func (f *fakeStateHistoryStore) DeleteAlertStateHistoryBefore(_ int64) (int64, error) {
+	return 0, nil
+}
+
 func newFakeAdminConfigStore(t *testing.T) *fakeAdminConfigStore {
 	t.Helper()
 	return &fakeAdminConfigStore{configs: map[int64]*models.AdminConfiguration{}}
@@ -230,13 +254,73 @@ func (f *fakeAdminConfigStore) UpdateAdminConfiguration(cmd store.UpdateAdminCon
 	return nil
 }
 
+func newFakeSchedulerPeerStore() *fakeSchedulerPeerStore {
+	return &fakeSchedulerPeerStore{heartbeats: map[string]int64{}}
+}
+
+// fakeSchedulerPeerStore records the heartbeats of the schedulers that share the alert rules.
+type fakeSchedulerPeerStore struct {
+	mtx        sync.Mutex
+	heartbeats map[string]int64
+}
+
+func (f *fakeSchedulerPeerStore) HeartbeatSchedulerPeer(peerID string, epoch int64) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.heartbeats[peerID] = epoch
+	return nil
+}
+
+func (f *fakeSchedulerPeerStore) GetSchedulerPeers(since int64) ([]string, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	peers := make([]string, 0, len(f.heartbeats))
+	for peer, epoch := range f.heartbeats {
+		if epoch >= since {
+			peers = append(peers, peer)
+		}
+	}
+	sort.Strings(peers)
+	return peers, nil
+}
+
+func (f *fakeSchedulerPeerStore) DeleteSchedulerPeer(peerID string) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	delete(f.heartbeats, peerID)
+	return nil
+}
+
+func (f *fakeSchedulerPeerStore) DeleteSchedulerPeersBefore(epoch int64) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	for peer, e := range f.heartbeats {
+		if e < epoch {
+			delete(f.heartbeats, peer)
+		}
+	}
+	return nil
+}
+
 // fakeNotifier represents a fake internal Alertmanager.
-type fakeNotifier struct{}
+type fakeNotifier struct {
+	mtx         sync.Mutex
+	alertsCount int
+}
 
 func (n *fakeNotifier) PutAlerts(alerts apimodels.PostableAlerts) error {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	n.alertsCount += len(alerts.PostableAlerts)
 	return nil
 }
 
+func (n *fakeNotifier) AlertsCount() int {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	return n.alertsCount
+}
+
 type FakeExternalAlertmanager struct {
 	t      *testing.T
 	mtx    sync.Mutex
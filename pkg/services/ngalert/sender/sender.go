@@ -27,31 +27,48 @@ import (
 const (
 	defaultMaxQueueCapacity = 10000
 	defaultTimeout          = 10 * time.Second
+	// queueLengthMetric is the metric of the notifier manager with the length of its queue.
+	queueLengthMetric = "prometheus_notifications_queue_length"
 )
 
+// TLSConfig configures the TLS connections to the external Alertmanager(s). The files are PEM
+// encoded, and the client certificate is only presented when both its files are set.
+type TLSConfig struct {
+	CAFile             string
+	CertFile           string
+	KeyFile            string
+	InsecureSkipVerify bool
+}
+
 // Sender is responsible for dispatching alert notifications to an external Alertmanager service.
 type Sender struct {
 	logger      log.Logger
 	gokitLogger gokit_log.Logger
 	wg          sync.WaitGroup
 
+	tlsConfig TLSConfig
+
 	manager *notifier.Manager
+	// registry holds the metrics of the manager, it is only used for the length of its queue.
+	registry *prometheus.Registry
 
 	sdCancel  context.CancelFunc
 	sdManager *discovery.Manager
 }
 
-func New(metrics *metrics.Metrics) (*Sender, error) {
+func New(metrics *metrics.Metrics, tlsConfig TLSConfig) (*Sender, error) {
 	l := log.New("sender")
 	sdCtx, sdCancel := context.WithCancel(context.Background())
 	s := &Sender{
 		logger:      l,
 		gokitLogger: gokit_log.NewLogfmtLogger(logging.NewWrapper(l)),
+		tlsConfig:   tlsConfig,
 		sdCancel:    sdCancel,
+		registry:    prometheus.NewRegistry(),
 	}
 
 	s.manager = notifier.NewManager(
-		&notifier.Options{QueueCapacity: defaultMaxQueueCapacity, Registerer: prometheus.NewRegistry()},
+		&notifier.Options{QueueCapacity: defaultMaxQueueCapacity, Registerer: s.registry},
 		s.gokitLogger,
 	)
 
@@ -62,7 +79,7 @@ func New(metrics *metrics.Metrics) (*Sender, error) {
 
 // ApplyConfig syncs a configuration with the sender.
 func (s *Sender) ApplyConfig(cfg *ngmodels.AdminConfiguration) error {
-	notifierCfg, err := buildNotifierConfig(cfg)
+	notifierCfg, err := buildNotifierConfig(cfg, s.tlsConfig)
 	if err != nil {
 		return err
 	}
@@ -118,6 +135,24 @@ func (s *Sender) Stop() {
 	s.wg.Wait()
 }
 
+// QueueLength returns the number of alerts waiting to be sent to the external Alertmanager(s).
+func (s *Sender) QueueLength() int {
+	mfs, err := s.registry.Gather()
+	if err != nil {
+		s.logger.Debug("failed to gather the metrics of the sender", "err", err)
+		return 0
+	}
+	for _, mf := range mfs {
+		if mf.GetName() != queueLengthMetric {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			return int(m.GetGauge().GetValue())
+		}
+	}
+	return 0
+}
+
 // Alertmanagers returns a list of the discovered Alertmanager(s).
 func (s *Sender) Alertmanagers() []*url.URL {
 	return s.manager.Alertmanagers()
@@ -128,7 +163,7 @@ func (s *Sender) DroppedAlertmanagers() []*url.URL {
 	return s.manager.DroppedAlertmanagers()
 }
 
-func buildNotifierConfig(cfg *ngmodels.AdminConfiguration) (*config.Config, error) {
+func buildNotifierConfig(cfg *ngmodels.AdminConfiguration, tlsConfig TLSConfig) (*config.Config, error) {
 	amConfigs := make([]*config.AlertmanagerConfig, 0, len(cfg.Alertmanagers))
 	for _, amURL := range cfg.Alertmanagers {
 		u, err := url.Parse(amURL)
@@ -152,6 +187,13 @@ func buildNotifierConfig(cfg *ngmodels.AdminConfiguration) (*config.Config, erro
 			ServiceDiscoveryConfigs: sdConfig,
 		}
 
+		amConfig.HTTPClientConfig.TLSConfig = common_config.TLSConfig{
+			CAFile:             tlsConfig.CAFile,
+			CertFile:           tlsConfig.CertFile,
+			KeyFile:            tlsConfig.KeyFile,
+			InsecureSkipVerify: tlsConfig.InsecureSkipVerify,
+		}
+
 		// Check the URL for basic authentication information first
 		if u.User != nil {
 			amConfig.HTTPClientConfig.BasicAuth = &common_config.BasicAuth{
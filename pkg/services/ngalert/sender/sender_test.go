@@ -0,0 +1,23 @@
+package sender
+
+import (
+	"testing"
+
+	"github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestSender_QueueLength(t *testing.T) {
+	s, err := New(nil, TLSConfig{})
+	require.NoError(t, err)
+	require.Equal(t, 0, s.QueueLength())
+
+	// the alerts are queued until the sender runs with Alertmanagers to send them to
+	s.SendAlerts(apimodels.PostableAlerts{PostableAlerts: []models.PostableAlert{
+		{Alert: models.Alert{Labels: models.LabelSet{"alertname": "DiskFull"}}},
+		{Alert: models.Alert{Labels: models.LabelSet{"alertname": "HighCPU"}}},
+	}})
+	require.Equal(t, 2, s.QueueLength())
+}
@@ -36,14 +36,7 @@ func (c *cache) getOrCreate(alertRule *ngModels.AlertRule, result eval.Result) *
 	c.mtxStates.Lock()
 	defer c.mtxStates.Unlock()
 
-	// clone the labels so we don't change eval.Result
-	labels := result.Instance.Copy()
-	attachRuleLabels(labels, alertRule)
-	ruleLabels, annotations := c.expandRuleLabelsAndAnnotations(alertRule, labels, result)
-
-	// if duplicate labels exist, alertRule label will take precedence
-	lbs := mergeLabels(ruleLabels, result.Instance)
-	attachRuleLabels(lbs, alertRule)
+	lbs, annotations := InstanceLabelsAndAnnotations(c.log, alertRule, result)
 
 	il := ngModels.InstanceLabels(lbs)
 	id, err := il.StringKey()
@@ -88,14 +81,28 @@ func attachRuleLabels(m map[string]string, alertRule *ngModels.AlertRule) {
 	m[prometheusModel.AlertNameLabel] = alertRule.Title
 }
 
-func (c *cache) expandRuleLabelsAndAnnotations(alertRule *ngModels.AlertRule, labels map[string]string, alertInstance eval.Result) (map[string]string, map[string]string) {
+// InstanceLabelsAndAnnotations returns the labels and the annotations of the alert instance of an
+// evaluation result, with the templates of the labels and the annotations of the alert rule expanded.
+func InstanceLabelsAndAnnotations(logger log.Logger, alertRule *ngModels.AlertRule, result eval.Result) (data.Labels, map[string]string) {
+	// clone the labels so we don't change eval.Result
+	labels := result.Instance.Copy()
+	attachRuleLabels(labels, alertRule)
+	ruleLabels, annotations := expandRuleLabelsAndAnnotations(logger, alertRule, labels, result)
+
+	// if duplicate labels exist, alertRule label will take precedence
+	lbs := mergeLabels(ruleLabels, result.Instance)
+	attachRuleLabels(lbs, alertRule)
+	return lbs, annotations
+}
+
+func expandRuleLabelsAndAnnotations(logger log.Logger, alertRule *ngModels.AlertRule, labels map[string]string, alertInstance eval.Result) (map[string]string, map[string]string) {
 	expand := func(original map[string]string) map[string]string {
 		expanded := make(map[string]string, len(original))
 		for k, v := range original {
 			ev, err := expandTemplate(alertRule.Title, v, labels, alertInstance)
 			expanded[k] = ev
 			if err != nil {
-				c.log.Error("error in expanding template", "name", k, "value", v, "err", err.Error())
+				logger.Error("error in expanding template", "name", k, "value", v, "err", err.Error())
 				// Store the original template on error.
 				expanded[k] = v
 			}
@@ -198,6 +205,22 @@ func (c *cache) getAll(orgID int64) []*State {
 	return states
 }
 
+func (c *cache) getFiring() []*State {
+	var states []*State
+	c.mtxStates.RLock()
+	defer c.mtxStates.RUnlock()
+	for _, orgStates := range c.states {
+		for _, ruleStates := range orgStates {
+			for _, state := range ruleStates {
+				if state.State == eval.Alerting {
+					states = append(states, state)
+				}
+			}
+		}
+	}
+	return states
+}
+
 func (c *cache) getStatesForRuleUID(orgID int64, alertRuleUID string) []*State {
 	var ruleStates []*State
 	c.mtxStates.RLock()
@@ -27,9 +27,10 @@ type Manager struct {
 
 	ruleStore     store.RuleStore
 	instanceStore store.InstanceStore
+	historyStore  store.StateHistoryStore
 }
 
-func NewManager(logger log.Logger, metrics *metrics.Metrics, ruleStore store.RuleStore, instanceStore store.InstanceStore) *Manager {
+func NewManager(logger log.Logger, metrics *metrics.Metrics, ruleStore store.RuleStore, instanceStore store.InstanceStore, historyStore store.StateHistoryStore) *Manager {
 	manager := &Manager{
 		cache:         newCache(logger, metrics),
 		quit:          make(chan struct{}),
@@ -38,6 +39,7 @@ func NewManager(logger log.Logger, metrics *metrics.Metrics, ruleStore store.Rul
 		metrics:       metrics,
 		ruleStore:     ruleStore,
 		instanceStore: instanceStore,
+		historyStore:  historyStore,
 	}
 	go manager.recordMetrics()
 	return manager
@@ -85,25 +87,7 @@ func (st *Manager) Warm() {
 				st.log.Error("rule not found for instance, ignoring", "rule", entry.RuleUID)
 				continue
 			}
-
-			lbs := map[string]string(entry.Labels)
-			cacheId, err := entry.Labels.StringKey()
-			if err != nil {
-				st.log.Error("error getting cacheId for entry", "msg", err.Error())
-			}
-			stateForEntry := &State{
-				AlertRuleUID:       entry.RuleUID,
-				OrgID:              entry.RuleOrgID,
-				CacheId:            cacheId,
-				Labels:             lbs,
-				State:              translateInstanceState(entry.CurrentState),
-				Results:            []Evaluation{},
-				StartsAt:           entry.CurrentStateSince,
-				EndsAt:             entry.CurrentStateEnd,
-				LastEvaluationTime: entry.LastEvalTime,
-				Annotations:        ruleForEntry.Annotations,
-			}
-			states = append(states, stateForEntry)
+			states = append(states, st.stateFromInstance(entry, ruleForEntry))
 		}
 	}
 
@@ -112,6 +96,56 @@ func (st *Manager) Warm() {
 	}
 }
 
+// WarmRule loads the saved states of the alert instances of a single alert rule into the cache,
+// replacing the cached ones. It's used when the evaluation of the rule moves to this instance.
+func (st *Manager) WarmRule(orgID int64, ruleUID string) {
+	st.RemoveByRuleUID(orgID, ruleUID)
+
+	ruleQuery := ngModels.GetAlertRuleByUIDQuery{OrgID: orgID, UID: ruleUID}
+	if err := st.ruleStore.GetAlertRuleByUID(&ruleQuery); err != nil {
+		st.log.Error("unable to fetch the alert rule to warm the cache", "uid", ruleUID, "orgId", orgID, "msg", err.Error())
+		return
+	}
+
+	cmd := ngModels.ListAlertInstancesQuery{RuleOrgID: orgID, RuleUID: ruleUID}
+	if err := st.instanceStore.ListAlertInstances(&cmd); err != nil {
+		st.log.Error("unable to fetch previous state", "uid", ruleUID, "orgId", orgID, "msg", err.Error())
+		return
+	}
+
+	for _, entry := range cmd.Result {
+		st.set(st.stateFromInstance(entry, ruleQuery.Result))
+	}
+}
+
+// stateFromInstance returns the cached state of a saved alert instance.
+func (st *Manager) stateFromInstance(entry *ngModels.ListAlertInstancesQueryResult, alertRule *ngModels.AlertRule) *State {
+	cacheId, err := entry.Labels.StringKey()
+	if err != nil {
+		st.log.Error("error getting cacheId for entry", "msg", err.Error())
+	}
+	s := &State{
+		AlertRuleUID:       entry.RuleUID,
+		OrgID:              entry.RuleOrgID,
+		CacheId:            cacheId,
+		Labels:             map[string]string(entry.Labels),
+		State:              translateInstanceState(entry.CurrentState),
+		Results:            []Evaluation{},
+		StartsAt:           entry.CurrentStateSince,
+		EndsAt:             entry.CurrentStateEnd,
+		LastEvaluationTime: entry.LastEvalTime,
+		Annotations:        alertRule.Annotations,
+		EscalationStep:     entry.EscalationStep,
+		AnnotationID:       entry.AnnotationID,
+	}
+	// the acknowledgement time is saved as 0 or before when the alert is not acknowledged
+	if entry.AcknowledgedAt.Unix() > 0 {
+		s.AcknowledgedBy = entry.AcknowledgedBy
+		s.AcknowledgedAt = entry.AcknowledgedAt
+	}
+	return s
+}
+
 func (st *Manager) getOrCreate(alertRule *ngModels.AlertRule, result eval.Result) *State {
 	return st.cache.getOrCreate(alertRule, result)
 }
@@ -137,18 +171,23 @@ func (st *Manager) RemoveByRuleUID(orgID int64, ruleUID string) {
 func (st *Manager) ProcessEvalResults(alertRule *ngModels.AlertRule, results eval.Results) []*State {
 	st.log.Debug("state manager processing evaluation results", "uid", alertRule.UID, "resultCount", len(results))
 	var states []*State
+	var history []ngModels.AlertStateHistoryEntry
 	processedResults := make(map[string]*State, len(results))
 	for _, result := range results {
-		s := st.setNextState(alertRule, result)
+		s, oldState := st.setNextState(alertRule, result)
 		states = append(states, s)
 		processedResults[s.CacheId] = s
+		if oldState != s.State {
+			history = append(history, newStateHistoryEntry(s, oldState, result))
+		}
 	}
 	st.staleResultsHandler(alertRule, processedResults)
+	st.saveStateHistory(alertRule, history)
 	return states
 }
 
-//Set the current state based on evaluation results
-func (st *Manager) setNextState(alertRule *ngModels.AlertRule, result eval.Result) *State {
+//Set the current state based on evaluation results, returns the new and the previous state
+func (st *Manager) setNextState(alertRule *ngModels.AlertRule, result eval.Result) (*State, eval.State) {
 	currentState := st.getOrCreate(alertRule, result)
 
 	currentState.LastEvaluationTime = result.EvaluatedAt
@@ -179,11 +218,15 @@ func (st *Manager) setNextState(alertRule *ngModels.AlertRule, result eval.Resul
 	// to Alertmanager.
 	currentState.Resolved = oldState == eval.Alerting && currentState.State == eval.Normal
 
-	st.set(currentState)
+	if currentState.State != eval.Alerting {
+		currentState.resetAcknowledgement()
+	}
+
 	if oldState != currentState.State {
-		go st.createAlertAnnotation(currentState.State, alertRule, result, oldState)
+		st.annotateDashboard(currentState, alertRule, result, oldState)
 	}
-	return currentState
+	st.set(currentState)
+	return currentState, oldState
 }
 
 func (st *Manager) GetAll(orgID int64) []*State {
@@ -194,6 +237,80 @@ func (st *Manager) GetStatesForRuleUID(orgID int64, alertRuleUID string) []*Stat
 	return st.cache.getStatesForRuleUID(orgID, alertRuleUID)
 }
 
+// GetFiring returns the firing alerts of all the organizations.
+func (st *Manager) GetFiring() []*State {
+	return st.cache.getFiring()
+}
+
+// Acknowledge acknowledges the firing alerts of an alert rule that have all the given labels, so that
+// they are not escalated anymore, and returns them. The alerts that are already acknowledged keep
+// their acknowledgement.
+func (st *Manager) Acknowledge(orgID int64, ruleUID string, labels map[string]string, by string, at time.Time) []*State {
+	return st.updateAcknowledgements(orgID, ruleUID, labels, func(s *State) bool {
+		if s.IsAcknowledged() {
+			return false
+		}
+		s.AcknowledgedBy = by
+		s.AcknowledgedAt = at
+		return true
+	})
+}
+
+// Unacknowledge removes the acknowledgement of the firing alerts of an alert rule that have all the
+// given labels, so that they are escalated again, and returns them.
+func (st *Manager) Unacknowledge(orgID int64, ruleUID string, labels map[string]string) []*State {
+	return st.updateAcknowledgements(orgID, ruleUID, labels, func(s *State) bool {
+		if !s.IsAcknowledged() {
+			return false
+		}
+		s.AcknowledgedBy = ""
+		s.AcknowledgedAt = time.Time{}
+		return true
+	})
+}
+
+// updateAcknowledgements updates the firing alerts of an alert rule that have all the given labels,
+// saves the updated ones and returns all of them.
+func (st *Manager) updateAcknowledgements(orgID int64, ruleUID string, labels map[string]string, update func(s *State) bool) []*State {
+	var states []*State
+	for _, s := range st.GetStatesForRuleUID(orgID, ruleUID) {
+		if s.State != eval.Alerting || !hasLabels(s.Labels, labels) {
+			continue
+		}
+		states = append(states, s)
+		if !update(s) {
+			continue
+		}
+		cmd := ngModels.SaveAlertInstanceCommand{
+			RuleOrgID:         s.OrgID,
+			RuleUID:           s.AlertRuleUID,
+			Labels:            ngModels.InstanceLabels(s.Labels),
+			State:             ngModels.InstanceStateType(s.State.String()),
+			LastEvalTime:      s.LastEvaluationTime,
+			CurrentStateSince: s.StartsAt,
+			CurrentStateEnd:   s.EndsAt,
+			AcknowledgedBy:    s.AcknowledgedBy,
+			AcknowledgedAt:    s.AcknowledgedAt,
+			EscalationStep:    s.EscalationStep,
+			AnnotationID:      s.AnnotationID,
+		}
+		if err := st.instanceStore.SaveAlertInstance(&cmd); err != nil {
+			st.log.Error("failed to save the acknowledgement of the alert", "uid", s.AlertRuleUID, "orgId", s.OrgID, "labels", s.Labels.String(), "msg", err.Error())
+		}
+	}
+	return states
+}
+
+// hasLabels returns true if the labels of an alert include all the given ones.
+func hasLabels(alertLabels, labels map[string]string) bool {
+	for name, value := range labels {
+		if v, ok := alertLabels[name]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
 func (st *Manager) recordMetrics() {
 	// TODO: parameterize?
 	// Setting to a reasonable default scrape interval for Prometheus.
@@ -229,8 +346,23 @@ func translateInstanceState(state ngModels.InstanceStateType) eval.State {
 	}
 }
 
-func (st *Manager) createAlertAnnotation(new eval.State, alertRule *ngModels.AlertRule, result eval.Result, oldState eval.State) {
-	st.log.Debug("alert state changed creating annotation", "alertRuleUID", alertRule.UID, "newState", new.String())
+// annotateDashboard writes the state change of the alert to the dashboard panel that the alert rule
+// is linked to. A firing alert is written as a region that is ended when the alert stops firing, the
+// other state changes are written as points.
+func (st *Manager) annotateDashboard(s *State, alertRule *ngModels.AlertRule, result eval.Result, oldState eval.State) {
+	if oldState == eval.Alerting && s.AnnotationID != 0 {
+		// end the region even if the annotations were disabled while the alert was firing
+		st.endAnnotation(s, result.EvaluatedAt)
+		if s.State == eval.Normal {
+			return
+		}
+	}
+
+	if alertRule.DisableDashboardAnnotations {
+		return
+	}
+
+	st.log.Debug("alert state changed creating annotation", "alertRuleUID", alertRule.UID, "newState", s.State.String())
 	dashUid, ok := alertRule.Annotations["__dashboardUid__"]
 	if !ok {
 		return
@@ -255,23 +387,79 @@ func (st *Manager) createAlertAnnotation(new eval.State, alertRule *ngModels.Ale
 		return
 	}
 
-	annotationText := fmt.Sprintf("%s {%s} - %s", alertRule.Title, result.Instance.String(), new.String())
-
 	item := &annotations.Item{
 		OrgId:       alertRule.OrgID,
 		DashboardId: query.Result.Id,
 		PanelId:     panelId,
 		PrevState:   oldState.String(),
-		NewState:    new.String(),
-		Text:        annotationText,
+		NewState:    s.State.String(),
+		Text:        fmt.Sprintf("%s {%s} - %s", alertRule.Title, result.Instance.String(), s.State.String()),
 		Epoch:       result.EvaluatedAt.UnixNano() / int64(time.Millisecond),
 	}
 
-	annotationRepo := annotations.GetRepository()
-	if err = annotationRepo.Save(item); err != nil {
+	if err = annotations.GetRepository().Save(item); err != nil {
 		st.log.Error("error saving alert annotation", "alertRuleUID", alertRule.UID, "error", err.Error())
 		return
 	}
+	if s.State == eval.Alerting {
+		s.AnnotationID = item.Id
+	}
+}
+
+// endAnnotation ends the dashboard annotation of the alert that stopped firing.
+func (st *Manager) endAnnotation(s *State, at time.Time) {
+	annotationRepo := annotations.GetRepository()
+	id := s.AnnotationID
+	s.AnnotationID = 0
+
+	items, err := annotationRepo.Find(&annotations.ItemQuery{OrgId: s.OrgID, AnnotationId: id})
+	if err != nil || len(items) == 0 {
+		st.log.Error("error getting alert annotation", "alertRuleUID", s.AlertRuleUID, "annotationID", id, "error", err)
+		return
+	}
+
+	item := &annotations.Item{
+		Id:       id,
+		OrgId:    s.OrgID,
+		Text:     items[0].Text,
+		EpochEnd: at.UnixNano() / int64(time.Millisecond),
+	}
+	if err := annotationRepo.Update(item); err != nil {
+		st.log.Error("error ending alert annotation", "alertRuleUID", s.AlertRuleUID, "annotationID", id, "error", err.Error())
+	}
+}
+
+// newStateHistoryEntry returns the transition of the state from the given previous state caused by the result.
+func newStateHistoryEntry(s *State, previous eval.State, result eval.Result) ngModels.AlertStateHistoryEntry {
+	values := make(ngModels.AlertStateHistoryValues, len(result.Values))
+	for refID, v := range result.Values {
+		values[refID] = v.Value
+	}
+
+	entry := ngModels.AlertStateHistoryEntry{
+		OrgID:            s.OrgID,
+		RuleUID:          s.AlertRuleUID,
+		Labels:           ngModels.InstanceLabels(s.Labels),
+		PreviousState:    ngModels.InstanceStateType(previous.String()),
+		CurrentState:     ngModels.InstanceStateType(s.State.String()),
+		Values:           values,
+		EvaluationString: result.EvaluationString,
+		Epoch:            result.EvaluatedAt.UnixNano() / int64(time.Millisecond),
+	}
+	if result.Error != nil {
+		entry.Error = result.Error.Error()
+	}
+	return entry
+}
+
+func (st *Manager) saveStateHistory(alertRule *ngModels.AlertRule, history []ngModels.AlertStateHistoryEntry) {
+	if len(history) == 0 {
+		return
+	}
+	st.log.Debug("saving alert state transitions", "alertRuleUID", alertRule.UID, "count", len(history))
+	if err := st.historyStore.SaveAlertStateHistory(history); err != nil {
+		st.log.Error("unable to save alert state transitions", "alertRuleUID", alertRule.UID, "error", err.Error())
+	}
 }
 
 func (st *Manager) staleResultsHandler(alertRule *ngModels.AlertRule, states map[string]*State) {
@@ -281,6 +469,9 @@ func (st *Manager) staleResultsHandler(alertRule *ngModels.AlertRule, states map
 		if !ok && isItStale(s.LastEvaluationTime, alertRule.IntervalSeconds) {
 			st.log.Debug("removing stale state entry", "orgID", s.OrgID, "alertRuleUID", s.AlertRuleUID, "cacheID", s.CacheId)
 			st.cache.deleteEntry(s.OrgID, s.AlertRuleUID, s.CacheId)
+			if s.AnnotationID != 0 {
+				st.endAnnotation(s, time.Now())
+			}
 			ilbs := ngModels.InstanceLabels(s.Labels)
 			_, labelsHash, err := ilbs.StringAndHash()
 			if err != nil {
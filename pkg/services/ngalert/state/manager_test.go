@@ -1,11 +1,16 @@
 package state_test
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	gfmodels "github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/registry"
+	"github.com/grafana/grafana/pkg/services/annotations"
 	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
 
 	"github.com/stretchr/testify/require"
 
@@ -544,6 +549,7 @@ func TestProcessEvalResults(t *testing.T) {
 					LastEvaluationTime: evaluationTime.Add(10 * time.Second),
 					EvaluationDuration: evaluationDuration,
 					Annotations:        map[string]string{"annotation": "test"},
+					ConsecutiveNoData:  1,
 				},
 			},
 		},
@@ -607,6 +613,7 @@ func TestProcessEvalResults(t *testing.T) {
 					LastEvaluationTime: evaluationTime.Add(10 * time.Second),
 					EvaluationDuration: evaluationDuration,
 					Annotations:        map[string]string{"annotation": "test"},
+					ConsecutiveNoData:  1,
 				},
 			},
 		},
@@ -670,6 +677,7 @@ func TestProcessEvalResults(t *testing.T) {
 					LastEvaluationTime: evaluationTime.Add(10 * time.Second),
 					EvaluationDuration: evaluationDuration,
 					Annotations:        map[string]string{"annotation": "test"},
+					ConsecutiveNoData:  1,
 				},
 			},
 		},
@@ -734,6 +742,7 @@ func TestProcessEvalResults(t *testing.T) {
 					LastEvaluationTime: evaluationTime.Add(10 * time.Second),
 					EvaluationDuration: evaluationDuration,
 					Annotations:        map[string]string{"annotation": "test"},
+					ConsecutiveNoData:  1,
 				},
 			},
 		},
@@ -798,6 +807,7 @@ func TestProcessEvalResults(t *testing.T) {
 					LastEvaluationTime: evaluationTime.Add(10 * time.Second),
 					EvaluationDuration: evaluationDuration,
 					Annotations:        map[string]string{"annotation": "test"},
+					ConsecutiveErrors:  1,
 				},
 			},
 		},
@@ -854,7 +864,7 @@ func TestProcessEvalResults(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		st := state.NewManager(log.New("test_state_manager"), nilMetrics, nil, nil)
+		st := state.NewManager(log.New("test_state_manager"), nilMetrics, nil, nil, &fakeStateHistoryStore{})
 		t.Run(tc.desc, func(t *testing.T) {
 			for _, res := range tc.evalResults {
 				_ = st.ProcessEvalResults(tc.alertRule, res)
@@ -868,6 +878,75 @@ func TestProcessEvalResults(t *testing.T) {
 	}
 }
 
+type fakeStateHistoryStore struct {
+	entries []models.AlertStateHistoryEntry
+}
+
+func (f *fakeStateHistoryStore) SaveAlertStateHistory(entries []models.AlertStateHistoryEntry) error {
+	f.entries = append(f.entries, entries...)
+	return nil
+}
+
+func (f *fakeStateHistoryStore) ListAlertStateHistory(_ *models.ListAlertStateHistoryQuery) error {
+	return nil
+}
+
+func (f *fakeStateHistoryStore) DeleteAlertStateHistoryBefore(_ int64) (int64, error) {
+	return 0, nil
+}
+
+func TestProcessEvalResultsStateHistory(t *testing.T) {
+	evaluationTime, err := time.Parse("2006-01-02", "2021-03-25")
+	require.NoError(t, err)
+
+	alertRule := &models.AlertRule{
+		OrgID:           1,
+		Title:           "test_title",
+		UID:             "test_alert_rule_uid",
+		NamespaceUID:    "test_namespace_uid",
+		IntervalSeconds: 10,
+		For:             time.Minute,
+	}
+	value := func(v float64) map[string]eval.NumberValueCapture {
+		return map[string]eval.NumberValueCapture{"B": {Var: "B", Value: &v}}
+	}
+	result := func(s eval.State, at time.Duration, v float64) eval.Results {
+		return eval.Results{{
+			Instance:         data.Labels{"instance_label": "test"},
+			State:            s,
+			EvaluatedAt:      evaluationTime.Add(at),
+			EvaluationString: fmt.Sprintf("[ var='B' value=%v ]", v),
+			Values:           value(v),
+		}}
+	}
+
+	history := &fakeStateHistoryStore{}
+	st := state.NewManager(log.New("test_state_history"), nilMetrics, nil, nil, history)
+
+	st.ProcessEvalResults(alertRule, result(eval.Normal, 0, 1))
+	require.Empty(t, history.entries, "a new normal alert instance is not a transition")
+
+	st.ProcessEvalResults(alertRule, result(eval.Alerting, 10*time.Second, 5))
+	st.ProcessEvalResults(alertRule, result(eval.Alerting, 20*time.Second, 6))
+	st.ProcessEvalResults(alertRule, result(eval.Alerting, 90*time.Second, 7))
+	st.ProcessEvalResults(alertRule, result(eval.Normal, 100*time.Second, 2))
+
+	require.Len(t, history.entries, 3)
+	transitions := make([]string, 0, len(history.entries))
+	for _, e := range history.entries {
+		transitions = append(transitions, fmt.Sprintf("%s->%s", e.PreviousState, e.CurrentState))
+	}
+	assert.Equal(t, []string{"Normal->Pending", "Pending->Alerting", "Alerting->Normal"}, transitions)
+
+	firing := history.entries[1]
+	assert.Equal(t, int64(1), firing.OrgID)
+	assert.Equal(t, "test_alert_rule_uid", firing.RuleUID)
+	assert.Equal(t, "test", firing.Labels["instance_label"])
+	assert.Equal(t, 7.0, *firing.Values["B"])
+	assert.Equal(t, "[ var='B' value=7 ]", firing.EvaluationString)
+	assert.Equal(t, evaluationTime.Add(90*time.Second), firing.Time().UTC())
+}
+
 func TestStaleResultsHandler(t *testing.T) {
 	evaluationTime, err := time.Parse("2006-01-02", "2021-03-25")
 	if err != nil {
@@ -951,7 +1030,7 @@ func TestStaleResultsHandler(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		st := state.NewManager(log.New("test_stale_results_handler"), nilMetrics, dbstore, dbstore)
+		st := state.NewManager(log.New("test_stale_results_handler"), nilMetrics, dbstore, dbstore, dbstore)
 		st.Warm()
 		existingStatesForRule := st.GetStatesForRuleUID(rule.OrgID, rule.UID)
 
@@ -971,3 +1050,129 @@ func TestStaleResultsHandler(t *testing.T) {
 		assert.Equal(t, tc.finalStateCount, len(existingStatesForRule))
 	}
 }
+
+func TestAcknowledge(t *testing.T) {
+	evaluationTime, err := time.Parse("2006-01-02", "2021-03-25")
+	require.NoError(t, err)
+
+	dbstore := tests.SetupTestEnv(t, 1)
+	t.Cleanup(registry.ClearOverrides)
+	rule := tests.CreateTestAlertRule(t, dbstore, 10)
+
+	result := func(s eval.State, at time.Duration, instance string) eval.Result {
+		return eval.Result{Instance: data.Labels{"instance": instance}, State: s, EvaluatedAt: evaluationTime.Add(at)}
+	}
+	st := state.NewManager(log.New("test_acknowledge"), nilMetrics, dbstore, dbstore, dbstore)
+	st.ProcessEvalResults(rule, eval.Results{result(eval.Alerting, 0, "a"), result(eval.Alerting, 0, "b"), result(eval.Normal, 0, "c")})
+
+	acknowledgedAt := evaluationTime.Add(time.Minute)
+	acknowledged := st.Acknowledge(rule.OrgID, rule.UID, map[string]string{"instance": "a"}, "editor", acknowledgedAt)
+	require.Len(t, acknowledged, 1)
+	assert.Equal(t, "editor", acknowledged[0].AcknowledgedBy)
+	assert.Equal(t, acknowledgedAt, acknowledged[0].AcknowledgedAt)
+
+	// only the firing alerts are acknowledged, and the acknowledged ones keep their acknowledgement
+	acknowledged = st.Acknowledge(rule.OrgID, rule.UID, nil, "admin", acknowledgedAt.Add(time.Minute))
+	require.Len(t, acknowledged, 2)
+	by := map[string]string{}
+	for _, s := range acknowledged {
+		by[s.Labels["instance"]] = s.AcknowledgedBy
+	}
+	assert.Equal(t, map[string]string{"a": "editor", "b": "admin"}, by)
+
+	// the acknowledgements are saved
+	restored := state.NewManager(log.New("test_acknowledge"), nilMetrics, dbstore, dbstore, dbstore)
+	restored.Warm()
+	restoredStates := restored.GetStatesForRuleUID(rule.OrgID, rule.UID)
+	require.Len(t, restoredStates, 2)
+	for _, s := range restoredStates {
+		if s.Labels["instance"] == "a" {
+			assert.Equal(t, "editor", s.AcknowledgedBy)
+			assert.Equal(t, acknowledgedAt.Unix(), s.AcknowledgedAt.Unix())
+		}
+	}
+
+	unacknowledged := st.Unacknowledge(rule.OrgID, rule.UID, map[string]string{"instance": "b"})
+	require.Len(t, unacknowledged, 1)
+	assert.False(t, unacknowledged[0].IsAcknowledged())
+
+	// the acknowledgement and the escalation end when the alert stops firing
+	for _, s := range st.GetStatesForRuleUID(rule.OrgID, rule.UID) {
+		s.EscalationStep = 1
+	}
+	states := st.ProcessEvalResults(rule, eval.Results{result(eval.Normal, 10*time.Second, "a"), result(eval.Alerting, 10*time.Second, "b")})
+	for _, s := range states {
+		assert.False(t, s.IsAcknowledged())
+		if s.Labels["instance"] == "a" {
+			assert.Equal(t, 0, s.EscalationStep)
+		} else {
+			assert.Equal(t, 1, s.EscalationStep)
+		}
+	}
+	assert.Empty(t, st.Acknowledge(rule.OrgID, rule.UID, map[string]string{"instance": "a"}, "editor", acknowledgedAt))
+}
+
+func TestDashboardAnnotations(t *testing.T) {
+	sqlStore := sqlstore.InitTestDB(t)
+	dash, err := sqlStore.SaveDashboard(gfmodels.SaveDashboardCommand{
+		OrgId:     1,
+		Dashboard: simplejson.NewFromAny(map[string]interface{}{"title": "dashboard"}),
+	})
+	require.NoError(t, err)
+
+	evaluationTime, err := time.Parse("2006-01-02", "2021-03-25")
+	require.NoError(t, err)
+	alertRule := &models.AlertRule{
+		OrgID:           1,
+		Title:           "test_title",
+		UID:             "test_alert_rule_uid",
+		NamespaceUID:    "test_namespace_uid",
+		IntervalSeconds: 10,
+		Annotations:     map[string]string{"__dashboardUid__": dash.Uid, "__panelId__": "2"},
+	}
+	result := func(s eval.State, at time.Duration) eval.Results {
+		return eval.Results{{
+			Instance:    data.Labels{"instance_label": "test"},
+			State:       s,
+			EvaluatedAt: evaluationTime.Add(at),
+		}}
+	}
+	findAnnotations := func(t *testing.T) []*annotations.ItemDTO {
+		t.Helper()
+		items, err := annotations.GetRepository().Find(&annotations.ItemQuery{OrgId: 1, DashboardId: dash.Id, PanelId: 2})
+		require.NoError(t, err)
+		return items
+	}
+
+	st := state.NewManager(log.New("test_dashboard_annotations"), nilMetrics, nil, nil, &fakeStateHistoryStore{})
+
+	t.Run("a firing alert is annotated as a region that ends when the alert is resolved", func(t *testing.T) {
+		st.ProcessEvalResults(alertRule, result(eval.Alerting, 0))
+		states := st.GetStatesForRuleUID(1, alertRule.UID)
+		require.Len(t, states, 1)
+		require.NotZero(t, states[0].AnnotationID)
+
+		items := findAnnotations(t)
+		require.Len(t, items, 1)
+		assert.Equal(t, "Alerting", items[0].NewState)
+		assert.Equal(t, items[0].Time, items[0].TimeEnd)
+
+		st.ProcessEvalResults(alertRule, result(eval.Alerting, 10*time.Second))
+		st.ProcessEvalResults(alertRule, result(eval.Normal, 20*time.Second))
+		assert.Zero(t, st.GetStatesForRuleUID(1, alertRule.UID)[0].AnnotationID)
+
+		items = findAnnotations(t)
+		require.Len(t, items, 1)
+		assert.Equal(t, evaluationTime.UnixNano()/int64(time.Millisecond), items[0].Time)
+		assert.Equal(t, evaluationTime.Add(20*time.Second).UnixNano()/int64(time.Millisecond), items[0].TimeEnd)
+		assert.Equal(t, "test_title {instance_label=test} - Alerting", items[0].Text)
+	})
+
+	t.Run("the annotations are disabled per rule", func(t *testing.T) {
+		disabled := *alertRule
+		disabled.DisableDashboardAnnotations = true
+		st.ProcessEvalResults(&disabled, result(eval.Alerting, 30*time.Second))
+		st.ProcessEvalResults(&disabled, result(eval.Normal, 40*time.Second))
+		assert.Len(t, findAnnotations(t), 1)
+	})
+}
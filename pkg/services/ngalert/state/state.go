@@ -23,6 +23,23 @@ type State struct {
 	Annotations        map[string]string
 	Labels             data.Labels
 	Error              error
+	// ImagePath and ImageURL are the file and the public URL of the image of the panel of the
+	// alert rule, captured when the alert started firing.
+	ImagePath string
+	ImageURL  string
+	// AcknowledgedBy and AcknowledgedAt are the login of the user who acknowledged the firing alert
+	// and when. The acknowledgement stops the escalation of the alert until it stops firing.
+	AcknowledgedBy string
+	AcknowledgedAt time.Time
+	// EscalationStep is the number of steps of its escalation chain the firing alert was escalated to.
+	EscalationStep int
+	// ConsecutiveNoData and ConsecutiveErrors are the number of the last evaluations in a row that had
+	// no data or failed, compared to the thresholds of the alert rule.
+	ConsecutiveNoData int
+	ConsecutiveErrors int
+	// AnnotationID is the dashboard annotation of the firing alert, a region that is ended when the
+	// alert stops firing.
+	AnnotationID int64
 }
 
 type Evaluation struct {
@@ -60,9 +77,11 @@ func (a *State) resultNormal(alertRule *ngModels.AlertRule, result eval.Result)
 	}
 	a.Error = result.Error // should be nil since state is not error
 	a.State = eval.Normal
+	a.ConsecutiveNoData, a.ConsecutiveErrors = 0, 0
 }
 
 func (a *State) resultAlerting(alertRule *ngModels.AlertRule, result eval.Result) {
+	a.ConsecutiveNoData, a.ConsecutiveErrors = 0, 0
 	switch a.State {
 	case eval.Alerting:
 		a.setEndsAt(alertRule, result)
@@ -91,8 +110,20 @@ func (a *State) resultError(alertRule *ngModels.AlertRule, result eval.Result) {
 	}
 	a.setEndsAt(alertRule, result)
 
-	if alertRule.ExecErrState == ngModels.AlertingErrState {
+	a.ConsecutiveNoData = 0
+	a.ConsecutiveErrors++
+	if a.ConsecutiveErrors < alertRule.ExecErrThreshold {
+		// keep the state until the error threshold is reached
+		return
+	}
+
+	switch alertRule.ExecErrState {
+	case ngModels.AlertingErrState:
 		a.State = eval.Alerting
+	case ngModels.OkErrState:
+		a.State = eval.Normal
+	case ngModels.KeepLastStateErrState:
+		// the alert keeps its state
 	}
 }
 
@@ -102,6 +133,13 @@ func (a *State) resultNoData(alertRule *ngModels.AlertRule, result eval.Result)
 	}
 	a.setEndsAt(alertRule, result)
 
+	a.ConsecutiveErrors = 0
+	a.ConsecutiveNoData++
+	if a.ConsecutiveNoData < alertRule.NoDataThreshold {
+		// keep the state until the no data threshold is reached
+		return
+	}
+
 	switch alertRule.NoDataState {
 	case ngModels.Alerting:
 		a.State = eval.Alerting
@@ -109,9 +147,23 @@ func (a *State) resultNoData(alertRule *ngModels.AlertRule, result eval.Result)
 		a.State = eval.NoData
 	case ngModels.OK:
 		a.State = eval.Normal
+	case ngModels.KeepLastState:
+		// the alert keeps its state
 	}
 }
 
+// IsAcknowledged returns true if the firing alert was acknowledged.
+func (a *State) IsAcknowledged() bool {
+	return !a.AcknowledgedAt.IsZero()
+}
+
+// resetAcknowledgement forgets the acknowledgement and the escalation of an alert that stopped firing.
+func (a *State) resetAcknowledgement() {
+	a.AcknowledgedBy = ""
+	a.AcknowledgedAt = time.Time{}
+	a.EscalationStep = 0
+}
+
 func (a *State) NeedsSending(resendDelay time.Duration) bool {
 	if a.State != eval.Alerting && a.State != eval.Normal {
 		return false
@@ -198,3 +198,77 @@ func TestSetEndsAt(t *testing.T) {
 		})
 	}
 }
+
+func TestNoDataAndErrorThresholds(t *testing.T) {
+	evaluationTime, _ := time.Parse("2006-01-02", "2021-03-25")
+	testCases := []struct {
+		name     string
+		testRule *ngmodels.AlertRule
+		results  []eval.State
+		expected []eval.State
+	}{
+		{
+			name:     "no data applies at once without a threshold",
+			testRule: &ngmodels.AlertRule{NoDataState: ngmodels.NoData},
+			results:  []eval.State{eval.Alerting, eval.NoData},
+			expected: []eval.State{eval.Alerting, eval.NoData},
+		},
+		{
+			name:     "no data applies after the threshold",
+			testRule: &ngmodels.AlertRule{NoDataState: ngmodels.OK, NoDataThreshold: 3},
+			results:  []eval.State{eval.Alerting, eval.NoData, eval.NoData, eval.NoData},
+			expected: []eval.State{eval.Alerting, eval.Alerting, eval.Alerting, eval.Normal},
+		},
+		{
+			name:     "the no data count restarts after a result with data",
+			testRule: &ngmodels.AlertRule{NoDataState: ngmodels.NoData, NoDataThreshold: 2},
+			results:  []eval.State{eval.Alerting, eval.NoData, eval.Alerting, eval.NoData, eval.NoData},
+			expected: []eval.State{eval.Alerting, eval.Alerting, eval.Alerting, eval.Alerting, eval.NoData},
+		},
+		{
+			name:     "no data keeps the last state",
+			testRule: &ngmodels.AlertRule{NoDataState: ngmodels.KeepLastState},
+			results:  []eval.State{eval.Alerting, eval.NoData, eval.NoData, eval.Normal, eval.NoData},
+			expected: []eval.State{eval.Alerting, eval.Alerting, eval.Alerting, eval.Normal, eval.Normal},
+		},
+		{
+			name:     "errors apply after the threshold",
+			testRule: &ngmodels.AlertRule{ExecErrState: ngmodels.AlertingErrState, ExecErrThreshold: 2},
+			results:  []eval.State{eval.Normal, eval.Error, eval.Normal, eval.Error, eval.Error},
+			expected: []eval.State{eval.Normal, eval.Normal, eval.Normal, eval.Normal, eval.Alerting},
+		},
+		{
+			name:     "errors set the state to normal",
+			testRule: &ngmodels.AlertRule{ExecErrState: ngmodels.OkErrState},
+			results:  []eval.State{eval.Alerting, eval.Error},
+			expected: []eval.State{eval.Alerting, eval.Normal},
+		},
+		{
+			name:     "errors keep the last state",
+			testRule: &ngmodels.AlertRule{ExecErrState: ngmodels.KeepLastStateErrState},
+			results:  []eval.State{eval.Alerting, eval.Error, eval.Normal, eval.Error},
+			expected: []eval.State{eval.Alerting, eval.Alerting, eval.Normal, eval.Normal},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.testRule.IntervalSeconds = 10
+			s := &State{State: eval.Normal}
+			for i, r := range tc.results {
+				result := eval.Result{State: r, EvaluatedAt: evaluationTime.Add(time.Duration(i) * 10 * time.Second)}
+				switch r {
+				case eval.Normal:
+					s.resultNormal(tc.testRule, result)
+				case eval.Alerting:
+					s.resultAlerting(tc.testRule, result)
+				case eval.Error:
+					s.resultError(tc.testRule, result)
+				case eval.NoData:
+					s.resultNoData(tc.testRule, result)
+				}
+				assert.Equal(t, tc.expected[i], s.State, "evaluation %d", i)
+			}
+		})
+	}
+}
@@ -25,15 +25,25 @@ const AlertRuleMaxTitleLength = 190
 // AlertRuleMaxRuleGroupNameLength is the maximum length of the alert rule group name
 const AlertRuleMaxRuleGroupNameLength = 190
 
+// AlertRuleMaxUIDLength is the maximum length of the alert rule UID
+const AlertRuleMaxUIDLength = 40
+
 type UpdateRuleGroupCmd struct {
 	OrgID           int64
 	NamespaceUID    string
 	RuleGroupConfig apimodels.PostableRuleGroupConfig
+	// KeepUIDs creates the rules with an unknown UID with this UID rather than failing,
+	// so that rules exported from another Grafana instance can be imported as is.
+	KeepUIDs bool
 }
 
 type UpsertRule struct {
 	Existing *ngmodels.AlertRule
 	New      ngmodels.AlertRule
+	// KeepUID creates the new rule with its UID if there's no rule with this UID yet.
+	KeepUID bool
+	// RestoredFrom is the version of the rule that the new rule restores, if any.
+	RestoredFrom int64
 }
 
 // Store is the interface for persisting alert rules and instances
@@ -43,6 +53,8 @@ type RuleStore interface {
 	DeleteRuleGroupAlertRules(orgID int64, namespaceUID string, ruleGroup string) ([]string, error)
 	DeleteAlertInstancesByRuleUID(orgID int64, ruleUID string) error
 	GetAlertRuleByUID(*ngmodels.GetAlertRuleByUIDQuery) error
+	GetAlertRuleVersions(*ngmodels.ListAlertRuleVersionsQuery) error
+	GetAlertRuleVersion(*ngmodels.GetAlertRuleVersionQuery) error
 	GetAlertRulesForScheduling(query *ngmodels.ListAlertRulesQuery) error
 	GetOrgAlertRules(query *ngmodels.ListAlertRulesQuery) error
 	GetNamespaceAlertRules(query *ngmodels.ListNamespaceAlertRulesQuery) error
@@ -183,6 +195,39 @@ func (st DBstore) GetAlertRuleByUID(query *ngmodels.GetAlertRuleByUIDQuery) erro
 	})
 }
 
+// GetAlertRuleVersions is a handler for listing the versions of an alert rule, most recent first.
+func (st DBstore) GetAlertRuleVersions(query *ngmodels.ListAlertRuleVersionsQuery) error {
+	return st.SQLStore.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+		versions := make([]*ngmodels.AlertRuleVersion, 0)
+		q := sess.Where("rule_org_id = ? AND rule_uid = ?", query.OrgID, query.RuleUID).Desc("version")
+		if query.Limit > 0 {
+			q = q.Limit(query.Limit, query.Start)
+		}
+		if err := q.Find(&versions); err != nil {
+			return err
+		}
+		query.Result = versions
+		return nil
+	})
+}
+
+// GetAlertRuleVersion is a handler for retrieving a version of an alert rule.
+// It returns ngmodels.ErrAlertRuleVersionNotFound if the rule has no such version.
+func (st DBstore) GetAlertRuleVersion(query *ngmodels.GetAlertRuleVersionQuery) error {
+	return st.SQLStore.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+		version := ngmodels.AlertRuleVersion{RuleOrgID: query.OrgID, RuleUID: query.RuleUID, Version: query.Version}
+		has, err := sess.Get(&version)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return ngmodels.ErrAlertRuleVersionNotFound
+		}
+		query.Result = &version
+		return nil
+	})
+}
+
 // UpsertAlertRules is a handler for creating/updating alert rules.
 func (st DBstore) UpsertAlertRules(rules []UpsertRule) error {
 	return st.SQLStore.WithTransactionalDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
@@ -192,23 +237,30 @@ func (st DBstore) UpsertAlertRules(rules []UpsertRule) error {
 			if r.Existing == nil && r.New.UID != "" {
 				// check by UID
 				existingAlertRule, err := getAlertRuleByUID(sess, r.New.UID, r.New.OrgID)
-				if err != nil {
-					if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
-						return fmt.Errorf("failed to get alert rule %s: %w", r.New.UID, err)
+				switch {
+				case err == nil:
+					r.Existing = existingAlertRule
+				case errors.Is(err, ngmodels.ErrAlertRuleNotFound) && r.KeepUID:
+					if !util.IsValidShortUID(r.New.UID) || len(r.New.UID) > AlertRuleMaxUIDLength {
+						return fmt.Errorf("%w: invalid UID %q", ngmodels.ErrAlertRuleFailedValidation, r.New.UID)
 					}
+				case errors.Is(err, ngmodels.ErrAlertRuleNotFound):
+					return fmt.Errorf("failed to get alert rule %s: %w", r.New.UID, err)
+				default:
 					return err
 				}
-				r.Existing = existingAlertRule
 			}
 
 			var parentVersion int64
 			switch r.Existing {
 			case nil: // new rule
-				uid, err := GenerateNewAlertRuleUID(sess, r.New.OrgID, r.New.Title)
-				if err != nil {
-					return fmt.Errorf("failed to generate UID for alert rule %q: %w", r.New.Title, err)
+				if r.New.UID == "" {
+					uid, err := GenerateNewAlertRuleUID(sess, r.New.OrgID, r.New.Title)
+					if err != nil {
+						return fmt.Errorf("failed to generate UID for alert rule %q: %w", r.New.Title, err)
+					}
+					r.New.UID = uid
 				}
-				r.New.UID = uid
 
 				if r.New.IntervalSeconds == 0 {
 					r.New.IntervalSeconds = st.DefaultIntervalSeconds
@@ -286,17 +338,24 @@ func (st DBstore) UpsertAlertRules(rules []UpsertRule) error {
 				RuleNamespaceUID: r.New.NamespaceUID,
 				RuleGroup:        r.New.RuleGroup,
 				ParentVersion:    parentVersion,
+				RestoredFrom:     r.RestoredFrom,
 				Version:          r.New.Version,
 				Created:          r.New.Updated,
 				Condition:        r.New.Condition,
 				Title:            r.New.Title,
 				Data:             r.New.Data,
 				IntervalSeconds:  r.New.IntervalSeconds,
+				OffsetSeconds:    r.New.OffsetSeconds,
 				NoDataState:      r.New.NoDataState,
 				ExecErrState:     r.New.ExecErrState,
+				NoDataThreshold:  r.New.NoDataThreshold,
+				ExecErrThreshold: r.New.ExecErrThreshold,
 				For:              r.New.For,
 				Annotations:      r.New.Annotations,
 				Labels:           r.New.Labels,
+				IsPaused:         r.New.IsPaused,
+
+				DisableDashboardAnnotations: r.New.DisableDashboardAnnotations,
 			})
 		}
 
@@ -416,12 +475,12 @@ func (st DBstore) GetNamespaceByTitle(namespace string, orgID int64, user *model
 	return folder, nil
 }
 
-// GetAlertRulesForScheduling returns alert rule info (identifier, interval, version state)
+// GetAlertRulesForScheduling returns alert rule info (identifier, group, interval, offset, version state)
 // that is useful for it's scheduling.
 func (st DBstore) GetAlertRulesForScheduling(query *ngmodels.ListAlertRulesQuery) error {
 	return st.SQLStore.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
 		alerts := make([]*ngmodels.AlertRule, 0)
-		q := "SELECT uid, org_id, interval_seconds, version FROM alert_rule"
+		q := "SELECT uid, org_id, namespace_uid, rule_group, interval_seconds, offset_seconds, version, is_paused FROM alert_rule"
 		if err := sess.SQL(q).Find(&alerts); err != nil {
 			return err
 		}
@@ -465,6 +524,26 @@ func (st DBstore) validateAlertRule(alertRule ngmodels.AlertRule) error {
 		return fmt.Errorf("%w: interval (%v) should be non-zero and divided exactly by scheduler interval: %v", ngmodels.ErrAlertRuleFailedValidation, time.Duration(alertRule.IntervalSeconds)*time.Second, st.BaseInterval)
 	}
 
+	if alertRule.OffsetSeconds%int64(st.BaseInterval.Seconds()) != 0 || alertRule.OffsetSeconds < 0 || alertRule.OffsetSeconds >= alertRule.IntervalSeconds {
+		return fmt.Errorf("%w: evaluation offset (%v) should be shorter than the interval and divided exactly by scheduler interval: %v", ngmodels.ErrAlertRuleFailedValidation, time.Duration(alertRule.OffsetSeconds)*time.Second, st.BaseInterval)
+	}
+
+	switch alertRule.NoDataState {
+	case ngmodels.Alerting, ngmodels.NoData, ngmodels.OK, ngmodels.KeepLastState:
+	default:
+		return fmt.Errorf("%w: unknown no data state %q", ngmodels.ErrAlertRuleFailedValidation, alertRule.NoDataState)
+	}
+
+	switch alertRule.ExecErrState {
+	case ngmodels.AlertingErrState, ngmodels.OkErrState, ngmodels.KeepLastStateErrState:
+	default:
+		return fmt.Errorf("%w: unknown error state %q", ngmodels.ErrAlertRuleFailedValidation, alertRule.ExecErrState)
+	}
+
+	if alertRule.NoDataThreshold < 0 || alertRule.ExecErrThreshold < 0 {
+		return fmt.Errorf("%w: no data and error thresholds should not be negative", ngmodels.ErrAlertRuleFailedValidation)
+	}
+
 	// enfore max name length in SQLite
 	if len(alertRule.Title) > AlertRuleMaxTitleLength {
 		return fmt.Errorf("%w: name length should not be greater than %d", ngmodels.ErrAlertRuleFailedValidation, AlertRuleMaxTitleLength)
@@ -508,16 +587,21 @@ func (st DBstore) UpdateRuleGroup(cmd UpdateRuleGroupCmd) error {
 			}
 
 			new := ngmodels.AlertRule{
-				OrgID:           cmd.OrgID,
-				Title:           r.GrafanaManagedAlert.Title,
-				Condition:       r.GrafanaManagedAlert.Condition,
-				Data:            r.GrafanaManagedAlert.Data,
-				UID:             r.GrafanaManagedAlert.UID,
-				IntervalSeconds: int64(time.Duration(cmd.RuleGroupConfig.Interval).Seconds()),
-				NamespaceUID:    cmd.NamespaceUID,
-				RuleGroup:       ruleGroup,
-				NoDataState:     ngmodels.NoDataState(r.GrafanaManagedAlert.NoDataState),
-				ExecErrState:    ngmodels.ExecutionErrorState(r.GrafanaManagedAlert.ExecErrState),
+				OrgID:            cmd.OrgID,
+				Title:            r.GrafanaManagedAlert.Title,
+				Condition:        r.GrafanaManagedAlert.Condition,
+				Data:             r.GrafanaManagedAlert.Data,
+				UID:              r.GrafanaManagedAlert.UID,
+				IntervalSeconds:  int64(time.Duration(cmd.RuleGroupConfig.Interval).Seconds()),
+				OffsetSeconds:    int64(time.Duration(cmd.RuleGroupConfig.EvaluationOffset).Seconds()),
+				NamespaceUID:     cmd.NamespaceUID,
+				RuleGroup:        ruleGroup,
+				NoDataState:      ngmodels.NoDataState(r.GrafanaManagedAlert.NoDataState),
+				ExecErrState:     ngmodels.ExecutionErrorState(r.GrafanaManagedAlert.ExecErrState),
+				NoDataThreshold:  r.GrafanaManagedAlert.NoDataThreshold,
+				ExecErrThreshold: r.GrafanaManagedAlert.ExecErrThreshold,
+
+				DisableDashboardAnnotations: r.GrafanaManagedAlert.DisableDashboardAnnotations,
 			}
 
 			if r.ApiRuleNode != nil {
@@ -526,12 +610,20 @@ func (st DBstore) UpdateRuleGroup(cmd UpdateRuleGroupCmd) error {
 				new.Labels = r.ApiRuleNode.Labels
 			}
 
+			if r.GrafanaManagedAlert.IsPaused != nil {
+				new.IsPaused = *r.GrafanaManagedAlert.IsPaused
+			}
+
 			upsertRule := UpsertRule{
-				New: new,
+				New:     new,
+				KeepUID: cmd.KeepUIDs,
 			}
 
 			if existingGroupRule, ok := existingGroupRulesUIDs[r.GrafanaManagedAlert.UID]; ok {
 				upsertRule.Existing = &existingGroupRule
+				if r.GrafanaManagedAlert.IsPaused == nil {
+					upsertRule.New.IsPaused = existingGroupRule.IsPaused
+				}
 				// remove the rule from existingGroupRulesUIDs
 				delete(existingGroupRulesUIDs, r.GrafanaManagedAlert.UID)
 			}
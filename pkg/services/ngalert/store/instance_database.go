@@ -100,18 +100,22 @@ func (st DBstore) SaveAlertInstance(cmd *models.SaveAlertInstanceCommand) error
 			CurrentStateSince: cmd.CurrentStateSince,
 			CurrentStateEnd:   cmd.CurrentStateEnd,
 			LastEvalTime:      cmd.LastEvalTime,
+			AcknowledgedBy:    cmd.AcknowledgedBy,
+			AcknowledgedAt:    cmd.AcknowledgedAt,
+			EscalationStep:    cmd.EscalationStep,
+			AnnotationID:      cmd.AnnotationID,
 		}
 
 		if err := models.ValidateAlertInstance(alertInstance); err != nil {
 			return err
 		}
 
-		params := append(make([]interface{}, 0), alertInstance.RuleOrgID, alertInstance.RuleUID, labelTupleJSON, alertInstance.LabelsHash, alertInstance.CurrentState, alertInstance.CurrentStateSince.Unix(), alertInstance.CurrentStateEnd.Unix(), alertInstance.LastEvalTime.Unix())
+		params := append(make([]interface{}, 0), alertInstance.RuleOrgID, alertInstance.RuleUID, labelTupleJSON, alertInstance.LabelsHash, alertInstance.CurrentState, alertInstance.CurrentStateSince.Unix(), alertInstance.CurrentStateEnd.Unix(), alertInstance.LastEvalTime.Unix(), alertInstance.AcknowledgedBy, alertInstance.AcknowledgedAt.Unix(), alertInstance.EscalationStep, alertInstance.AnnotationID)
 
 		upsertSQL := st.SQLStore.Dialect.UpsertSQL(
 			"alert_instance",
 			[]string{"rule_org_id", "rule_uid", "labels_hash"},
-			[]string{"rule_org_id", "rule_uid", "labels", "labels_hash", "current_state", "current_state_since", "current_state_end", "last_eval_time"})
+			[]string{"rule_org_id", "rule_uid", "labels", "labels_hash", "current_state", "current_state_since", "current_state_end", "last_eval_time", "acknowledged_by", "acknowledged_at", "escalation_step", "annotation_id"})
 		_, err = sess.SQL(upsertSQL, params...).Query()
 		if err != nil {
 			return err
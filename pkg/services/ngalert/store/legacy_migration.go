@@ -0,0 +1,38 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrations/ualert"
+)
+
+// errDryRun rolls back the transaction of a dry run.
+var errDryRun = errors.New("dry run")
+
+// LegacyAlertingMigrationStore migrates the dashboard alerts and the notification channels of an organization.
+type LegacyAlertingMigrationStore interface {
+	MigrateLegacyAlerting(orgID int64, dryRun bool) (*ualert.OrgMigrationReport, error)
+}
+
+// MigrateLegacyAlerting migrates the dashboard alerts of the organization to alert rules in a transaction,
+// which is rolled back for a dry run. It returns ualert.ErrOrgAlreadyMigrated when they were already migrated.
+func (st DBstore) MigrateLegacyAlerting(orgID int64, dryRun bool) (*ualert.OrgMigrationReport, error) {
+	var report *ualert.OrgMigrationReport
+	err := st.SQLStore.WithTransactionalDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+		var err error
+		report, err = ualert.MigrateOrg(sess.Session, st.SQLStore.Dialect, st.SQLStore.Cfg, orgID)
+		if err != nil {
+			return err
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,95 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// NotificationDeadLetterMaxLimit is the maximum number of failed notifications returned by a query.
+const NotificationDeadLetterMaxLimit = 1000
+
+type NotificationDeadLetterStore interface {
+	SaveNotificationDeadLetter(deadLetter models.NotificationDeadLetter) error
+	ListNotificationDeadLetters(query *models.ListNotificationDeadLettersQuery) error
+	DeleteNotificationDeadLettersBefore(epoch int64) (int64, error)
+}
+
+// SaveNotificationDeadLetter is a handler for saving a notification that could not be sent.
+func (st DBstore) SaveNotificationDeadLetter(d models.NotificationDeadLetter) error {
+	return st.SQLStore.WithTransactionalDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+		alerts, err := json.Marshal(d.Alerts)
+		if err != nil {
+			return fmt.Errorf("failed to encode the alerts of the notification: %w", err)
+		}
+
+		_, err = sess.Exec(
+			"INSERT INTO alert_notification_dead_letter (org_id, receiver, integration_name, integration_type, alerts, attempts, error, epoch) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+			d.OrgID, d.Receiver, d.IntegrationName, d.IntegrationType, string(alerts), d.Attempts, d.Error, d.Epoch,
+		)
+		return err
+	})
+}
+
+// ListNotificationDeadLetters is a handler for retrieving the failed notifications of an
+// organization, most recent first.
+func (st DBstore) ListNotificationDeadLetters(query *models.ListNotificationDeadLettersQuery) error {
+	return st.SQLStore.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+		deadLetters := make([]*models.NotificationDeadLetter, 0)
+
+		s := strings.Builder{}
+		params := make([]interface{}, 0)
+
+		addToQuery := func(stmt string, p ...interface{}) {
+			s.WriteString(stmt)
+			params = append(params, p...)
+		}
+
+		addToQuery("SELECT * FROM alert_notification_dead_letter WHERE org_id = ?", query.OrgID)
+
+		if query.Receiver != "" {
+			addToQuery(" AND receiver = ?", query.Receiver)
+		}
+
+		if query.From > 0 {
+			addToQuery(" AND epoch >= ?", query.From)
+		}
+
+		if query.To > 0 {
+			addToQuery(" AND epoch <= ?", query.To)
+		}
+
+		limit := query.Limit
+		if limit <= 0 || limit > NotificationDeadLetterMaxLimit {
+			limit = NotificationDeadLetterMaxLimit
+		}
+		addToQuery(" ORDER BY epoch DESC, id DESC")
+		addToQuery(" " + st.SQLStore.Dialect.Limit(int64(limit)))
+
+		if err := sess.SQL(s.String(), params...).Find(&deadLetters); err != nil {
+			return err
+		}
+
+		query.Result = deadLetters
+		return nil
+	})
+}
+
+// DeleteNotificationDeadLettersBefore deletes the failed notifications older than the given epoch
+// in milliseconds and returns how many were deleted.
+func (st DBstore) DeleteNotificationDeadLettersBefore(epoch int64) (int64, error) {
+	var affected int64
+	err := st.SQLStore.WithTransactionalDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+		res, err := sess.Exec("DELETE FROM alert_notification_dead_letter WHERE epoch < ?", epoch)
+		if err != nil {
+			return err
+		}
+		affected, err = res.RowsAffected()
+		return err
+	})
+	return affected, err
+}
@@ -0,0 +1,82 @@
+// +build integration
+
+package store_test
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/registry"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationDeadLetterOperations(t *testing.T) {
+	dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+	t.Cleanup(registry.ClearOverrides)
+
+	deadLetter := func(orgID int64, receiver string, epoch int64) models.NotificationDeadLetter {
+		return models.NotificationDeadLetter{
+			OrgID:           orgID,
+			Receiver:        receiver,
+			IntegrationName: receiver + "-webhook",
+			IntegrationType: "webhook",
+			Alerts:          models.NotificationDeadLetterAlerts{{"alertname": "DiskFull"}},
+			Attempts:        3,
+			Error:           "unavailable",
+			Epoch:           epoch,
+		}
+	}
+	for _, d := range []models.NotificationDeadLetter{
+		deadLetter(1, "ops", 1000),
+		deadLetter(1, "dev", 2000),
+		deadLetter(1, "ops", 3000),
+		deadLetter(2, "ops", 4000),
+	} {
+		require.NoError(t, dbstore.SaveNotificationDeadLetter(d))
+	}
+
+	list := func(t *testing.T, q models.ListNotificationDeadLettersQuery) []int64 {
+		t.Helper()
+		if q.OrgID == 0 {
+			q.OrgID = 1
+		}
+		require.NoError(t, dbstore.ListNotificationDeadLetters(&q))
+		epochs := make([]int64, 0, len(q.Result))
+		for _, d := range q.Result {
+			epochs = append(epochs, d.Epoch)
+		}
+		return epochs
+	}
+
+	t.Run("failed notifications are listed most recent first", func(t *testing.T) {
+		q := models.ListNotificationDeadLettersQuery{OrgID: 1}
+		require.NoError(t, dbstore.ListNotificationDeadLetters(&q))
+		require.Len(t, q.Result, 3)
+
+		d := q.Result[0]
+		assert.Equal(t, "ops", d.Receiver)
+		assert.Equal(t, "ops-webhook", d.IntegrationName)
+		assert.Equal(t, "webhook", d.IntegrationType)
+		assert.Equal(t, models.NotificationDeadLetterAlerts{{"alertname": "DiskFull"}}, d.Alerts)
+		assert.Equal(t, 3, d.Attempts)
+		assert.Equal(t, "unavailable", d.Error)
+		assert.Equal(t, int64(3000), d.Epoch)
+	})
+
+	t.Run("failed notifications can be filtered", func(t *testing.T) {
+		assert.Equal(t, []int64{3000, 1000}, list(t, models.ListNotificationDeadLettersQuery{Receiver: "ops"}))
+		assert.Equal(t, []int64{3000, 2000}, list(t, models.ListNotificationDeadLettersQuery{From: 2000, To: 3000}))
+		assert.Equal(t, []int64{3000}, list(t, models.ListNotificationDeadLettersQuery{Limit: 1}))
+		assert.Equal(t, []int64{4000}, list(t, models.ListNotificationDeadLettersQuery{OrgID: 2}))
+	})
+
+	t.Run("old failed notifications can be deleted", func(t *testing.T) {
+		deleted, err := dbstore.DeleteNotificationDeadLettersBefore(3000)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+		assert.Equal(t, []int64{3000}, list(t, models.ListNotificationDeadLettersQuery{}))
+	})
+}
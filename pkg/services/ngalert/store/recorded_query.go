@@ -0,0 +1,188 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// RecordedQueryMaxNameLength is the maximum length of the metric name of a recorded query.
+const RecordedQueryMaxNameLength = 190
+
+type RecordedQueryStore interface {
+	GetRecordedQueries(query *ngmodels.ListRecordedQueriesQuery) error
+	GetRecordedQueryByUID(query *ngmodels.GetRecordedQueryByUIDQuery) error
+	SaveRecordedQuery(recordedQuery *ngmodels.RecordedQuery) error
+	DeleteRecordedQueryByUID(orgID int64, uid string) error
+}
+
+// GetRecordedQueries is a handler for retrieving the recorded queries of an organization, or of all
+// the organizations.
+func (st DBstore) GetRecordedQueries(query *ngmodels.ListRecordedQueriesQuery) error {
+	return st.SQLStore.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+		recordedQueries := make([]*ngmodels.RecordedQuery, 0)
+
+		s := strings.Builder{}
+		params := make([]interface{}, 0)
+
+		addToQuery := func(stmt string, p ...interface{}) {
+			s.WriteString(stmt)
+			params = append(params, p...)
+		}
+
+		addToQuery("SELECT * FROM recorded_query WHERE 1 = 1")
+
+		if query.OrgID > 0 {
+			addToQuery(" AND org_id = ?", query.OrgID)
+		}
+
+		if query.ActiveOnly {
+			addToQuery(" AND active = ?", true)
+		}
+
+		addToQuery(" ORDER BY org_id, name, id")
+
+		if err := sess.SQL(s.String(), params...).Find(&recordedQueries); err != nil {
+			return err
+		}
+
+		query.Result = recordedQueries
+		return nil
+	})
+}
+
+// GetRecordedQueryByUID is a handler for retrieving a recorded query by its UID.
+func (st DBstore) GetRecordedQueryByUID(query *ngmodels.GetRecordedQueryByUIDQuery) error {
+	return st.SQLStore.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+		recordedQuery := ngmodels.RecordedQuery{OrgID: query.OrgID, UID: query.UID}
+		has, err := sess.Get(&recordedQuery)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return ngmodels.ErrRecordedQueryNotFound
+		}
+		query.Result = &recordedQuery
+		return nil
+	})
+}
+
+// SaveRecordedQuery creates the recorded query when it has no UID, and updates the recorded
+// query with its UID otherwise.
+func (st DBstore) SaveRecordedQuery(rq *ngmodels.RecordedQuery) error {
+	if rq.IntervalSeconds == 0 {
+		rq.IntervalSeconds = st.DefaultIntervalSeconds
+	}
+	if err := st.validateRecordedQuery(*rq); err != nil {
+		return err
+	}
+
+	return st.SQLStore.WithTransactionalDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+		if err := rq.PreSave(TimeNow); err != nil {
+			return err
+		}
+
+		if rq.UID == "" {
+			uid, err := generateNewRecordedQueryUID(sess, rq.OrgID)
+			if err != nil {
+				return err
+			}
+			rq.UID = uid
+			if _, err := sess.Insert(rq); err != nil {
+				return fmt.Errorf("failed to create the recorded query: %w", err)
+			}
+			return nil
+		}
+
+		existing := ngmodels.RecordedQuery{OrgID: rq.OrgID, UID: rq.UID}
+		has, err := sess.Get(&existing)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return ngmodels.ErrRecordedQueryNotFound
+		}
+		rq.ID = existing.ID
+		if _, err := sess.ID(existing.ID).AllCols().Update(rq); err != nil {
+			return fmt.Errorf("failed to update the recorded query: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeleteRecordedQueryByUID is a handler for deleting a recorded query by its UID.
+func (st DBstore) DeleteRecordedQueryByUID(orgID int64, uid string) error {
+	return st.SQLStore.WithTransactionalDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+		res, err := sess.Exec("DELETE FROM recorded_query WHERE org_id = ? AND uid = ?", orgID, uid)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return ngmodels.ErrRecordedQueryNotFound
+		}
+		return nil
+	})
+}
+
+func generateNewRecordedQueryUID(sess *sqlstore.DBSession, orgID int64) (string, error) {
+	for i := 0; i < 3; i++ {
+		uid := util.GenerateShortUID()
+
+		exists, err := sess.Where("org_id=? AND uid=?", orgID, uid).Get(&ngmodels.RecordedQuery{})
+		if err != nil {
+			return "", err
+		}
+
+		if !exists {
+			return uid, nil
+		}
+	}
+
+	return "", fmt.Errorf("failed to generate the UID of the recorded query")
+}
+
+// validateRecordedQuery validates the metric name, the interval and the organization of the recorded query.
+func (st DBstore) validateRecordedQuery(rq ngmodels.RecordedQuery) error {
+	if !model.IsValidMetricName(model.LabelValue(rq.Name)) {
+		return fmt.Errorf("%w: invalid metric name %q", ngmodels.ErrRecordedQueryFailedValidation, rq.Name)
+	}
+
+	if len(rq.Name) > RecordedQueryMaxNameLength {
+		return fmt.Errorf("%w: metric name length should not be greater than %d", ngmodels.ErrRecordedQueryFailedValidation, RecordedQueryMaxNameLength)
+	}
+
+	if len(rq.Data) == 0 {
+		return fmt.Errorf("%w: no queries or expressions are found", ngmodels.ErrRecordedQueryFailedValidation)
+	}
+
+	if rq.Target == "" {
+		return fmt.Errorf("%w: target is empty", ngmodels.ErrRecordedQueryFailedValidation)
+	}
+
+	if rq.IntervalSeconds%int64(st.BaseInterval.Seconds()) != 0 || rq.IntervalSeconds <= 0 {
+		return fmt.Errorf("%w: interval (%v) should be non-zero and divided exactly by scheduler interval: %v", ngmodels.ErrRecordedQueryFailedValidation, time.Duration(rq.IntervalSeconds)*time.Second, st.BaseInterval)
+	}
+
+	for name := range rq.Labels {
+		if !model.LabelName(name).IsValid() || name == model.MetricNameLabel {
+			return fmt.Errorf("%w: invalid label name %q", ngmodels.ErrRecordedQueryFailedValidation, name)
+		}
+	}
+
+	if rq.OrgID == 0 {
+		return fmt.Errorf("%w: no organisation is found", ngmodels.ErrRecordedQueryFailedValidation)
+	}
+
+	return nil
+}
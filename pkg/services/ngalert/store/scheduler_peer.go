@@ -0,0 +1,53 @@
+package store
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// SchedulerPeerStore records the Grafana instances that share the evaluation of the alert rules.
+type SchedulerPeerStore interface {
+	HeartbeatSchedulerPeer(peerID string, epoch int64) error
+	GetSchedulerPeers(since int64) ([]string, error)
+	DeleteSchedulerPeer(peerID string) error
+	DeleteSchedulerPeersBefore(epoch int64) error
+}
+
+// HeartbeatSchedulerPeer records that the instance is evaluating alert rules at the given epoch in seconds.
+func (st DBstore) HeartbeatSchedulerPeer(peerID string, epoch int64) error {
+	return st.SQLStore.WithTransactionalDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+		upsertSQL := st.SQLStore.Dialect.UpsertSQL(
+			"alert_scheduler_peer",
+			[]string{"peer_id"},
+			[]string{"peer_id", "last_heartbeat"})
+		_, err := sess.SQL(upsertSQL, peerID, epoch).Query()
+		return err
+	})
+}
+
+// GetSchedulerPeers returns the IDs of the instances that recorded a heartbeat since the given
+// epoch in seconds, sorted.
+func (st DBstore) GetSchedulerPeers(since int64) ([]string, error) {
+	peers := make([]string, 0)
+	err := st.SQLStore.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+		return sess.SQL("SELECT peer_id FROM alert_scheduler_peer WHERE last_heartbeat >= ? ORDER BY peer_id", since).Find(&peers)
+	})
+	return peers, err
+}
+
+// DeleteSchedulerPeer removes the instance from the instances that evaluate alert rules.
+func (st DBstore) DeleteSchedulerPeer(peerID string) error {
+	return st.SQLStore.WithTransactionalDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+		_, err := sess.Exec("DELETE FROM alert_scheduler_peer WHERE peer_id = ?", peerID)
+		return err
+	})
+}
+
+// DeleteSchedulerPeersBefore removes the instances whose last heartbeat is older than the given epoch in seconds.
+func (st DBstore) DeleteSchedulerPeersBefore(epoch int64) error {
+	return st.SQLStore.WithTransactionalDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+		_, err := sess.Exec("DELETE FROM alert_scheduler_peer WHERE last_heartbeat < ?", epoch)
+		return err
+	})
+}
@@ -0,0 +1,48 @@
+// +build integration
+
+package store_test
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/registry"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedulerPeerOperations(t *testing.T) {
+	dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+	t.Cleanup(registry.ClearOverrides)
+
+	require.NoError(t, dbstore.HeartbeatSchedulerPeer("b", 100))
+	require.NoError(t, dbstore.HeartbeatSchedulerPeer("a", 100))
+	require.NoError(t, dbstore.HeartbeatSchedulerPeer("c", 50))
+
+	t.Run("peers with a recent heartbeat are returned sorted", func(t *testing.T) {
+		peers, err := dbstore.GetSchedulerPeers(60)
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "b"}, peers)
+	})
+
+	t.Run("heartbeat updates the existing peer", func(t *testing.T) {
+		require.NoError(t, dbstore.HeartbeatSchedulerPeer("c", 200))
+		peers, err := dbstore.GetSchedulerPeers(150)
+		require.NoError(t, err)
+		require.Equal(t, []string{"c"}, peers)
+	})
+
+	t.Run("expired peers are deleted", func(t *testing.T) {
+		require.NoError(t, dbstore.DeleteSchedulerPeersBefore(150))
+		peers, err := dbstore.GetSchedulerPeers(0)
+		require.NoError(t, err)
+		require.Equal(t, []string{"c"}, peers)
+	})
+
+	t.Run("a peer that leaves is deleted", func(t *testing.T) {
+		require.NoError(t, dbstore.DeleteSchedulerPeer("c"))
+		peers, err := dbstore.GetSchedulerPeers(0)
+		require.NoError(t, err)
+		require.Empty(t, peers)
+	})
+}
@@ -0,0 +1,117 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// StateHistoryMaxLimit is the maximum number of state transitions returned by a query.
+const StateHistoryMaxLimit = 1000
+
+type StateHistoryStore interface {
+	SaveAlertStateHistory(entries []models.AlertStateHistoryEntry) error
+	ListAlertStateHistory(query *models.ListAlertStateHistoryQuery) error
+	DeleteAlertStateHistoryBefore(epoch int64) (int64, error)
+}
+
+// SaveAlertStateHistory is a handler for saving the state transitions of alert instances.
+func (st DBstore) SaveAlertStateHistory(entries []models.AlertStateHistoryEntry) error {
+	return st.SQLStore.WithTransactionalDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+		for _, e := range entries {
+			labels, labelsHash, err := e.Labels.StringAndHash()
+			if err != nil {
+				return err
+			}
+
+			values, err := json.Marshal(e.Values)
+			if err != nil {
+				return fmt.Errorf("failed to encode the values of the evaluation: %w", err)
+			}
+
+			if _, err := sess.Exec(
+				"INSERT INTO alert_state_history (org_id, rule_uid, labels, labels_hash, previous_state, current_state, evaluation_values, evaluation_string, evaluation_error, epoch) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+				e.OrgID, e.RuleUID, labels, labelsHash, e.PreviousState, e.CurrentState, string(values), e.EvaluationString, e.Error, e.Epoch,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListAlertStateHistory is a handler for retrieving the state transitions of the alert instances
+// of an organization, most recent first.
+func (st DBstore) ListAlertStateHistory(query *models.ListAlertStateHistoryQuery) error {
+	return st.SQLStore.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+		entries := make([]*models.ListAlertStateHistoryQueryResult, 0)
+
+		s := strings.Builder{}
+		params := make([]interface{}, 0)
+
+		addToQuery := func(stmt string, p ...interface{}) {
+			s.WriteString(stmt)
+			params = append(params, p...)
+		}
+
+		addToQuery("SELECT alert_state_history.*, alert_rule.title AS rule_title FROM alert_state_history INNER JOIN alert_rule ON alert_state_history.org_id = alert_rule.org_id AND alert_state_history.rule_uid = alert_rule.uid WHERE alert_state_history.org_id = ?", query.OrgID)
+
+		if len(query.NamespaceUIDs) > 0 {
+			placeholders := make([]string, 0, len(query.NamespaceUIDs))
+			for _, folderUID := range query.NamespaceUIDs {
+				params = append(params, folderUID)
+				placeholders = append(placeholders, "?")
+			}
+			addToQuery(fmt.Sprintf(" AND alert_rule.namespace_uid IN (%s)", strings.Join(placeholders, ",")))
+		}
+
+		if query.RuleUID != "" {
+			addToQuery(" AND alert_state_history.rule_uid = ?", query.RuleUID)
+		}
+
+		if query.State != "" {
+			addToQuery(" AND alert_state_history.current_state = ?", query.State)
+		}
+
+		if query.From > 0 {
+			addToQuery(" AND alert_state_history.epoch >= ?", query.From)
+		}
+
+		if query.To > 0 {
+			addToQuery(" AND alert_state_history.epoch <= ?", query.To)
+		}
+
+		limit := query.Limit
+		if limit <= 0 || limit > StateHistoryMaxLimit {
+			limit = StateHistoryMaxLimit
+		}
+		addToQuery(" ORDER BY alert_state_history.epoch DESC, alert_state_history.id DESC")
+		addToQuery(" " + st.SQLStore.Dialect.Limit(int64(limit)))
+
+		if err := sess.SQL(s.String(), params...).Find(&entries); err != nil {
+			return err
+		}
+
+		query.Result = entries
+		return nil
+	})
+}
+
+// DeleteAlertStateHistoryBefore deletes the state transitions older than the given epoch in
+// milliseconds and returns how many were deleted.
+func (st DBstore) DeleteAlertStateHistoryBefore(epoch int64) (int64, error) {
+	var affected int64
+	err := st.SQLStore.WithTransactionalDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+		res, err := sess.Exec("DELETE FROM alert_state_history WHERE epoch < ?", epoch)
+		if err != nil {
+			return err
+		}
+		affected, err = res.RowsAffected()
+		return err
+	})
+	return affected, err
+}
@@ -0,0 +1,86 @@
+// +build integration
+
+package store_test
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/registry"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertStateHistoryOperations(t *testing.T) {
+	dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+	t.Cleanup(registry.ClearOverrides)
+
+	alertRule1 := tests.CreateTestAlertRule(t, dbstore, 60)
+	alertRule2 := tests.CreateTestAlertRule(t, dbstore, 60)
+	orgID := alertRule1.OrgID
+
+	value := 42.0
+	entry := func(rule *models.AlertRule, previous, current models.InstanceStateType, epoch int64) models.AlertStateHistoryEntry {
+		return models.AlertStateHistoryEntry{
+			OrgID:            rule.OrgID,
+			RuleUID:          rule.UID,
+			Labels:           models.InstanceLabels{"test": "testValue"},
+			PreviousState:    previous,
+			CurrentState:     current,
+			Values:           models.AlertStateHistoryValues{"B": &value},
+			EvaluationString: "[ var='B' value=42 ]",
+			Epoch:            epoch,
+		}
+	}
+
+	require.NoError(t, dbstore.SaveAlertStateHistory([]models.AlertStateHistoryEntry{
+		entry(alertRule1, models.InstanceStateNormal, models.InstanceStatePending, 1000),
+		entry(alertRule1, models.InstanceStatePending, models.InstanceStateFiring, 2000),
+		entry(alertRule2, models.InstanceStateNormal, models.InstanceStateFiring, 3000),
+		entry(alertRule1, models.InstanceStateFiring, models.InstanceStateNormal, 4000),
+	}))
+
+	list := func(t *testing.T, q models.ListAlertStateHistoryQuery) []int64 {
+		t.Helper()
+		q.OrgID = orgID
+		require.NoError(t, dbstore.ListAlertStateHistory(&q))
+		epochs := make([]int64, 0, len(q.Result))
+		for _, e := range q.Result {
+			epochs = append(epochs, e.Epoch)
+		}
+		return epochs
+	}
+
+	t.Run("transitions are listed most recent first with their rule and evaluation", func(t *testing.T) {
+		q := models.ListAlertStateHistoryQuery{OrgID: orgID, RuleUID: alertRule1.UID}
+		require.NoError(t, dbstore.ListAlertStateHistory(&q))
+		require.Len(t, q.Result, 3)
+
+		e := q.Result[1]
+		assert.Equal(t, alertRule1.Title, e.RuleTitle)
+		assert.Equal(t, models.InstanceLabels{"test": "testValue"}, e.Labels)
+		assert.Equal(t, models.InstanceStatePending, e.PreviousState)
+		assert.Equal(t, models.InstanceStateFiring, e.CurrentState)
+		assert.Equal(t, value, *e.Values["B"])
+		assert.Equal(t, "[ var='B' value=42 ]", e.EvaluationString)
+		assert.Equal(t, int64(2000), e.Epoch)
+	})
+
+	t.Run("transitions can be filtered", func(t *testing.T) {
+		assert.Equal(t, []int64{4000, 3000, 2000, 1000}, list(t, models.ListAlertStateHistoryQuery{}))
+		assert.Equal(t, []int64{3000, 2000}, list(t, models.ListAlertStateHistoryQuery{State: models.InstanceStateFiring}))
+		assert.Equal(t, []int64{3000, 2000}, list(t, models.ListAlertStateHistoryQuery{From: 2000, To: 3000}))
+		assert.Equal(t, []int64{4000}, list(t, models.ListAlertStateHistoryQuery{Limit: 1}))
+		assert.Equal(t, []int64{3000}, list(t, models.ListAlertStateHistoryQuery{NamespaceUIDs: []string{alertRule2.NamespaceUID}, RuleUID: alertRule2.UID}))
+		assert.Empty(t, list(t, models.ListAlertStateHistoryQuery{NamespaceUIDs: []string{"unknown"}}))
+	})
+
+	t.Run("old transitions can be deleted", func(t *testing.T) {
+		deleted, err := dbstore.DeleteAlertStateHistoryBefore(3000)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+		assert.Equal(t, []int64{4000, 3000}, list(t, models.ListAlertStateHistoryQuery{}))
+	})
+}
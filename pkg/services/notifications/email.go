@@ -22,6 +22,9 @@ type Message struct {
 	ReplyTo       []string
 	EmbeddedFiles []string
 	AttachedFiles []*AttachedFile
+
+	// queueID is the ID of the email in the send queue, or 0 when it is not stored.
+	queueID int64
 }
 
 func setDefaultTemplateData(data map[string]interface{}, u *models.User) {
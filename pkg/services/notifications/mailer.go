@@ -7,6 +7,7 @@ package notifications
 import (
 	"bytes"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"html/template"
 	"io"
@@ -25,8 +26,10 @@ import (
 )
 
 var (
-	emailsSentTotal  prometheus.Counter
-	emailsSentFailed prometheus.Counter
+	emailsSentTotal    prometheus.Counter
+	emailsSentFailed   prometheus.Counter
+	smtpServerUp       *prometheus.GaugeVec
+	smtpServerFailures *prometheus.CounterVec
 )
 
 func init() {
@@ -41,6 +44,18 @@ func init() {
 		Help:      "Number of emails Grafana failed to send",
 		Namespace: "grafana",
 	})
+
+	smtpServerUp = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name:      "smtp_server_up",
+		Help:      "Whether the last connection to the SMTP server succeeded",
+		Namespace: "grafana",
+	}, []string{"host"})
+
+	smtpServerFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "smtp_server_failures_total",
+		Help:      "Number of failed connections to the SMTP server, after which Grafana fails over to the next server",
+		Namespace: "grafana",
+	}, []string{"host"})
 }
 
 func (ns *NotificationService) Send(msg *Message) (int, error) {
@@ -61,7 +76,7 @@ func (ns *NotificationService) Send(msg *Message) (int, error) {
 
 func (ns *NotificationService) dialAndSend(messages ...*Message) (int, error) {
 	sentEmailsCount := 0
-	dialer, err := ns.createDialer()
+	dialers, err := ns.createDialers()
 	if err != nil {
 		return sentEmailsCount, err
 	}
@@ -69,7 +84,7 @@ func (ns *NotificationService) dialAndSend(messages ...*Message) (int, error) {
 	for _, msg := range messages {
 		m := ns.buildEmail(msg)
 
-		innerError := dialer.DialAndSend(m)
+		innerError := ns.sendWithFailover(dialers, m)
 		emailsSentTotal.Inc()
 		if innerError != nil {
 			// As gomail does not returned typed errors we have to parse the error
@@ -129,8 +144,24 @@ func (ns *NotificationService) setFiles(
 	}
 }
 
-func (ns *NotificationService) createDialer() (*gomail.Dialer, error) {
-	host, port, err := net.SplitHostPort(ns.Cfg.Smtp.Host)
+// createDialers returns the dialers of the SMTP servers, in the order of priority.
+func (ns *NotificationService) createDialers() ([]*gomail.Dialer, error) {
+	dialers := make([]*gomail.Dialer, 0, len(ns.Cfg.Smtp.Hosts))
+	for _, hostPort := range ns.Cfg.Smtp.Hosts {
+		d, err := ns.createDialer(hostPort)
+		if err != nil {
+			return nil, err
+		}
+		dialers = append(dialers, d)
+	}
+	if len(dialers) == 0 {
+		return nil, errors.New("no SMTP server configured")
+	}
+	return dialers, nil
+}
+
+func (ns *NotificationService) createDialer(hostPort string) (*gomail.Dialer, error) {
+	host, port, err := net.SplitHostPort(hostPort)
 	if err != nil {
 		return nil, err
 	}
@@ -155,6 +186,9 @@ func (ns *NotificationService) createDialer() (*gomail.Dialer, error) {
 	d := gomail.NewDialer(host, iPort, ns.Cfg.Smtp.User, ns.Cfg.Smtp.Password)
 	d.TLSConfig = tlsconfig
 	d.StartTLSPolicy = getStartTLSPolicy(ns.Cfg.Smtp.StartTLSPolicy)
+	if ns.Cfg.Smtp.AuthType == smtpAuthTypeOAuth2 {
+		d.Auth = &xoauth2Auth{user: ns.Cfg.Smtp.User, host: host, tokenSource: ns.smtpTokenSource()}
+	}
 
 	if ns.Cfg.Smtp.EhloIdentity != "" {
 		d.LocalName = ns.Cfg.Smtp.EhloIdentity
@@ -194,7 +228,7 @@ func (ns *NotificationService) buildEmailMessage(cmd *models.SendEmailCommand) (
 			return nil, err
 		}
 		var buffer bytes.Buffer
-		err = mailTemplates.ExecuteTemplate(&buffer, cmd.Template+fileExtension, data)
+		err = getMailTemplates().ExecuteTemplate(&buffer, cmd.Template+fileExtension, data)
 		if err != nil {
 			return nil, err
 		}
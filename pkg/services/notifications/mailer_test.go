@@ -2,12 +2,14 @@ package notifications
 
 import (
 	"bytes"
+	"net/smtp"
 	"strings"
 	"testing"
 
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
 )
 
 func TestBuildMail(t *testing.T) {
@@ -39,3 +41,37 @@ func TestBuildMail(t *testing.T) {
 		assert.Less(t, strings.Index(buf.String(), "Some plain text body"), strings.Index(buf.String(), "Some HTML body"))
 	})
 }
+
+func TestXOAuth2Auth(t *testing.T) {
+	auth := &xoauth2Auth{
+		user:        "grafana@example.com",
+		host:        "smtp.example.com",
+		tokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}),
+	}
+
+	t.Run("sends the user and the access token", func(t *testing.T) {
+		mech, resp, err := auth.Start(&smtp.ServerInfo{Name: "smtp.example.com", TLS: true})
+		require.NoError(t, err)
+		assert.Equal(t, "XOAUTH2", mech)
+		assert.Equal(t, "user=grafana@example.com\x01auth=Bearer token\x01\x01", string(resp))
+
+		resp, err = auth.Next([]byte(`{"status":"401"}`), true)
+		require.NoError(t, err)
+		assert.Empty(t, resp)
+	})
+
+	t.Run("requires an encrypted connection", func(t *testing.T) {
+		_, _, err := auth.Start(&smtp.ServerInfo{Name: "smtp.example.com"})
+		require.Error(t, err)
+	})
+
+	t.Run("is used by the dialer", func(t *testing.T) {
+		ns := &NotificationService{Cfg: setting.NewCfg()}
+		ns.Cfg.Smtp.AuthType = smtpAuthTypeOAuth2
+		ns.Cfg.Smtp.OAuth2TokenURL = "https://login.example.com/token"
+
+		d, err := ns.createDialer("smtp.example.com:587")
+		require.NoError(t, err)
+		require.IsType(t, &xoauth2Auth{}, d.Auth)
+	})
+}
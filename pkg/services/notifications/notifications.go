@@ -6,14 +6,17 @@ import (
 	"fmt"
 	"html/template"
 	"net/url"
-	"path/filepath"
-	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
 
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/registry"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -28,12 +31,22 @@ func init() {
 }
 
 type NotificationService struct {
-	Bus bus.Bus      `inject:""`
-	Cfg *setting.Cfg `inject:""`
+	Bus      bus.Bus            `inject:""`
+	Cfg      *setting.Cfg       `inject:""`
+	SQLStore *sqlstore.SQLStore `inject:""`
 
 	mailQueue    chan *Message
 	webhookQueue chan *Webhook
 	log          log.Logger
+
+	tokenSourceMtx sync.Mutex
+	tokenSource    oauth2.TokenSource
+
+	// smtpFailures are the times of the last failed connections to the SMTP servers, by address.
+	smtpHealthMtx sync.Mutex
+	smtpFailures  map[string]time.Time
+
+	smsProvider SMSProvider
 }
 
 func (ns *NotificationService) Init() error {
@@ -44,30 +57,36 @@ func (ns *NotificationService) Init() error {
 	ns.Bus.AddHandler(ns.sendResetPasswordEmail)
 	ns.Bus.AddHandler(ns.validateResetPasswordCode)
 	ns.Bus.AddHandler(ns.sendEmailCommandHandler)
+	ns.Bus.AddHandler(ns.reloadMailTemplates)
+	ns.Bus.AddHandler(ns.sendWebhookCommandHandler)
 
 	ns.Bus.AddHandlerCtx(ns.sendEmailCommandHandlerSync)
 	ns.Bus.AddHandlerCtx(ns.SendWebhookSync)
+	ns.Bus.AddHandlerCtx(ns.sendSMSCommandHandlerSync)
 
 	ns.Bus.AddEventListener(ns.signUpStartedHandler)
 	ns.Bus.AddEventListener(ns.signUpCompletedHandler)
 
-	mailTemplates = template.New("name")
-	mailTemplates.Funcs(template.FuncMap{
-		"Subject": subjectTemplateFunc,
-	})
-
-	for _, pattern := range ns.Cfg.Smtp.TemplatesPatterns {
-		templatePattern := filepath.Join(ns.Cfg.StaticRootPath, pattern)
-		_, err := mailTemplates.ParseGlob(templatePattern)
-		if err != nil {
-			return err
-		}
+	if err := ns.loadMailTemplates(); err != nil {
+		return err
 	}
 
 	if !util.IsEmail(ns.Cfg.Smtp.FromAddress) {
 		return errors.New("invalid email address for SMTP from_address config")
 	}
 
+	if ns.Cfg.Smtp.AuthType == smtpAuthTypeOAuth2 && (ns.Cfg.Smtp.OAuth2ClientID == "" || ns.Cfg.Smtp.OAuth2TokenURL == "") {
+		return errors.New("SMTP oauth2 authentication requires the oauth2_client_id and oauth2_token_url config")
+	}
+
+	if ns.Cfg.Sms.Enabled {
+		provider, err := newSMSProvider(ns.Cfg.Sms)
+		if err != nil {
+			return err
+		}
+		ns.smsProvider = provider
+	}
+
 	if setting.EmailCodeValidMinutes == 0 {
 		setting.EmailCodeValidMinutes = 120
 	}
@@ -76,26 +95,19 @@ func (ns *NotificationService) Init() error {
 }
 
 func (ns *NotificationService) Run(ctx context.Context) error {
+	// the emails queued before a restart are sent when they are due
+	ticker := time.NewTicker(queueRetryInterval)
+	defer ticker.Stop()
+	ns.retryQueuedMessages(ctx)
+
 	for {
 		select {
 		case webhook := <-ns.webhookQueue:
-			err := ns.sendWebRequestSync(context.Background(), webhook)
-
-			if err != nil {
-				ns.log.Error("Failed to send webrequest ", "error", err)
-			}
+			ns.sendQueuedWebhook(ctx, webhook)
 		case msg := <-ns.mailQueue:
-			num, err := ns.Send(msg)
-			tos := strings.Join(msg.To, "; ")
-			info := ""
-			if err != nil {
-				if len(msg.Info) > 0 {
-					info = ", info: " + msg.Info
-				}
-				ns.log.Error(fmt.Sprintf("Async sent email %d succeed, not send emails: %s%s err: %s", num, tos, info, err))
-			} else {
-				ns.log.Debug(fmt.Sprintf("Async sent email %d succeed, sent emails: %s%s", num, tos, info))
-			}
+			ns.sendQueuedMessage(ctx, msg)
+		case <-ticker.C:
+			ns.retryQueuedMessages(ctx)
 		case <-ctx.Done():
 			return ctx.Err()
 		}
@@ -111,6 +123,19 @@ func (ns *NotificationService) SendWebhookSync(ctx context.Context, cmd *models.
 		HttpMethod:  cmd.HttpMethod,
 		HttpHeader:  cmd.HttpHeader,
 		ContentType: cmd.ContentType,
+		TLSConfig:   cmd.TLSConfig,
+	})
+}
+
+func (ns *NotificationService) sendWebhookCommandHandler(cmd *models.SendWebhook) error {
+	return ns.enqueueWebhook(&Webhook{
+		Url:         cmd.Url,
+		User:        cmd.User,
+		Password:    cmd.Password,
+		Body:        cmd.Body,
+		HttpMethod:  cmd.HttpMethod,
+		HttpHeader:  cmd.HttpHeader,
+		ContentType: cmd.ContentType,
 	})
 }
 
@@ -127,6 +152,7 @@ func (ns *NotificationService) sendEmailCommandHandlerSync(ctx context.Context,
 		To:            cmd.To,
 		SingleEmail:   cmd.SingleEmail,
 		EmbeddedFiles: cmd.EmbeddedFiles,
+		AttachedFiles: cmd.AttachedFiles,
 		Subject:       cmd.Subject,
 		ReplyTo:       cmd.ReplyTo,
 	})
@@ -146,8 +172,7 @@ func (ns *NotificationService) sendEmailCommandHandler(cmd *models.SendEmailComm
 		return err
 	}
 
-	ns.mailQueue <- message
-	return nil
+	return ns.enqueueMessage(message)
 }
 
 func (ns *NotificationService) sendResetPasswordEmail(cmd *models.SendResetPasswordEmailCommand) error {
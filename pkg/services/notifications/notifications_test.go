@@ -1,6 +1,9 @@
 package notifications
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/grafana/grafana/pkg/bus"
@@ -37,3 +40,55 @@ func TestNotificationService(t *testing.T) {
 		assert.NotContains(t, sentMsg.Body["text/plain"], "Subject")
 	})
 }
+
+func TestMailTemplatesOverride(t *testing.T) {
+	ns := &NotificationService{
+		Cfg: setting.NewCfg(),
+	}
+	ns.Cfg.StaticRootPath = "../../../public/"
+	ns.Cfg.Smtp.Enabled = true
+	ns.Cfg.Smtp.TemplatesPatterns = []string{"emails/*.html", "emails/*.txt"}
+	ns.Cfg.Smtp.FromAddress = "from@address.com"
+	ns.Cfg.Smtp.ContentTypes = []string{"text/html"}
+	ns.Cfg.Smtp.TemplatesDir = t.TempDir()
+	ns.Bus = bus.New()
+
+	writeTemplate := func(name, content string) {
+		err := ioutil.WriteFile(filepath.Join(ns.Cfg.Smtp.TemplatesDir, name), []byte(content), 0600)
+		require.NoError(t, err)
+	}
+	writeTemplate("reset_password.html", `{{Subject .Subject "ACME password reset"}}<p>Hi {{.Name}}, from ACME</p>`)
+
+	err := ns.Init()
+	require.NoError(t, err)
+
+	t.Run("the template of the directory overrides the built-in template", func(t *testing.T) {
+		msg, err := ns.buildEmailMessage(&models.SendEmailCommand{Template: "reset_password", Data: map[string]interface{}{"Name": "Alice"}})
+		require.NoError(t, err)
+		assert.Equal(t, "ACME password reset", msg.Subject)
+		assert.Equal(t, "<p>Hi Alice, from ACME</p>", msg.Body["text/html"])
+	})
+
+	t.Run("an invalid template is not reloaded", func(t *testing.T) {
+		writeTemplate("reset_password.html", `{{Subject .Subject "Broken"`)
+		err := ns.reloadMailTemplates(&models.ReloadEmailTemplatesCommand{})
+		require.Error(t, err)
+
+		msg, err := ns.buildEmailMessage(&models.SendEmailCommand{Template: "reset_password"})
+		require.NoError(t, err)
+		assert.Equal(t, "ACME password reset", msg.Subject)
+	})
+
+	t.Run("a template that doesn't override a built-in template is rejected", func(t *testing.T) {
+		writeTemplate("reset_password.html", `{{Subject .Subject "ACME password reset, again"}}`)
+		writeTemplate("reset_pasword.txt", `typo`)
+		err := ns.reloadMailTemplates(&models.ReloadEmailTemplatesCommand{})
+		require.EqualError(t, err, `email template "reset_pasword.txt" doesn't override a built-in template`)
+
+		require.NoError(t, os.Remove(filepath.Join(ns.Cfg.Smtp.TemplatesDir, "reset_pasword.txt")))
+		require.NoError(t, ns.reloadMailTemplates(&models.ReloadEmailTemplatesCommand{}))
+		msg, err := ns.buildEmailMessage(&models.SendEmailCommand{Template: "reset_password"})
+		require.NoError(t, err)
+		assert.Equal(t, "ACME password reset, again", msg.Subject)
+	})
+}
@@ -0,0 +1,283 @@
+package notifications
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	// queueRetryInterval is the delay before the first retry of a queued email or webhook, which
+	// doubles with each attempt up to queueMaxRetryInterval.
+	queueRetryInterval    = time.Minute
+	queueMaxRetryInterval = time.Hour
+	// queueMaxAttempts is the number of attempts after which a queued email or webhook is dropped.
+	queueMaxAttempts = 10
+	// queueBatchSize is the maximum number of queued emails and webhooks retried at once.
+	queueBatchSize = 100
+)
+
+// Types of the messages of the send queue.
+const (
+	queueTypeEmail   = "email"
+	queueTypeWebhook = "webhook"
+)
+
+var getTime = time.Now
+
+// queuedMessage is an email or a webhook of the send queue. It is kept in the database until it
+// is sent, so that it is retried after a failure of the SMTP server or the webhook receiver, or a
+// restart.
+type queuedMessage struct {
+	Id   int64
+	Type string
+	// Message is the email as JSON, or the webhook as JSON encrypted with the secret key and
+	// base64 encoded, since webhooks can contain credentials.
+	Message string
+	// Attempts is the number of attempts to send the message that were started.
+	Attempts  int
+	LastError string
+	// NextAttempt is when the message is retried if the current attempt doesn't complete.
+	NextAttempt time.Time
+	Created     time.Time
+}
+
+func (queuedMessage) TableName() string {
+	return "notification_queue"
+}
+
+// retryDelay returns the delay before retrying a message after the given number of attempts.
+func retryDelay(attempts int) time.Duration {
+	delay := queueRetryInterval
+	for i := 1; i < attempts && delay < queueMaxRetryInterval; i++ {
+		delay *= 2
+	}
+	if delay > queueMaxRetryInterval {
+		delay = queueMaxRetryInterval
+	}
+	return delay
+}
+
+// enqueueMessage adds an email to the send queue. It is stored before it is sent, unless
+// there is no database.
+func (ns *NotificationService) enqueueMessage(msg *Message) error {
+	if ns.SQLStore == nil {
+		ns.mailQueue <- msg
+		return nil
+	}
+
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	id, err := ns.storeQueuedMessage(queueTypeEmail, string(b))
+	if err != nil {
+		return err
+	}
+	msg.queueID = id
+
+	select {
+	case ns.mailQueue <- msg:
+	default:
+		// the queue is full, the email is sent when it is retried
+		ns.log.Warn("The email send queue is full, the email is delayed", "to", msg.To)
+	}
+	return nil
+}
+
+// enqueueWebhook adds a webhook to the send queue. It is stored before it is sent, unless there
+// is no database.
+func (ns *NotificationService) enqueueWebhook(webhook *Webhook) error {
+	if ns.SQLStore == nil {
+		ns.webhookQueue <- webhook
+		return nil
+	}
+
+	b, err := json.Marshal(webhook)
+	if err != nil {
+		return err
+	}
+	encrypted, err := util.Encrypt(b, setting.SecretKey)
+	if err != nil {
+		return err
+	}
+	id, err := ns.storeQueuedMessage(queueTypeWebhook, base64.StdEncoding.EncodeToString(encrypted))
+	if err != nil {
+		return err
+	}
+	webhook.queueID = id
+
+	select {
+	case ns.webhookQueue <- webhook:
+	default:
+		// the queue is full, the webhook is sent when it is retried
+		ns.log.Warn("The webhook send queue is full, the webhook is delayed", "url", webhook.Url)
+	}
+	return nil
+}
+
+// storeQueuedMessage stores a message of the send queue, which is claimed by the first attempt
+// to send it, and returns its ID.
+func (ns *NotificationService) storeQueuedMessage(queueType string, message string) (int64, error) {
+	now := getTime()
+	item := &queuedMessage{
+		Type:        queueType,
+		Message:     message,
+		Attempts:    1,
+		NextAttempt: now.Add(retryDelay(1)),
+		Created:     now,
+	}
+	err := ns.SQLStore.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+		_, err := sess.Insert(item)
+		return err
+	})
+	return item.Id, err
+}
+
+// completeQueuedMessage removes a sent email from the send queue. An email that failed is kept
+// to be retried, unless it has reached the maximum number of attempts.
+func (ns *NotificationService) completeQueuedMessage(ctx context.Context, msg *Message, sendErr error) {
+	ns.completeQueued(ctx, msg.queueID, sendErr, "to", msg.To)
+}
+
+// completeQueuedWebhook removes a sent webhook from the send queue. A webhook that failed is kept
+// to be retried, unless it has reached the maximum number of attempts.
+func (ns *NotificationService) completeQueuedWebhook(ctx context.Context, webhook *Webhook, sendErr error) {
+	ns.completeQueued(ctx, webhook.queueID, sendErr, "url", webhook.Url)
+}
+
+func (ns *NotificationService) completeQueued(ctx context.Context, queueID int64, sendErr error, logCtx ...interface{}) {
+	if queueID == 0 {
+		return
+	}
+
+	err := ns.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		item := &queuedMessage{}
+		has, err := sess.ID(queueID).Get(item)
+		if err != nil || !has {
+			return err
+		}
+
+		if sendErr == nil || item.Attempts >= queueMaxAttempts {
+			if sendErr != nil {
+				ns.log.Error(fmt.Sprintf("Dropping the %s after the maximum number of attempts", item.Type),
+					append(logCtx, "attempts", item.Attempts, "error", sendErr)...)
+			}
+			_, err := sess.ID(queueID).Delete(&queuedMessage{})
+			return err
+		}
+
+		_, err = sess.ID(queueID).Cols("last_error").Update(&queuedMessage{LastError: sendErr.Error()})
+		return err
+	})
+	if err != nil {
+		ns.log.Error("Failed to update the send queue", "id", queueID, "error", err)
+	}
+}
+
+// retryQueuedMessages sends the queued emails and webhooks that are due to be retried, such as
+// those that failed or that were queued when Grafana stopped. Each message is claimed by
+// incrementing its attempts, so that it is only retried by one of the Grafana instances sharing
+// the database.
+func (ns *NotificationService) retryQueuedMessages(ctx context.Context) {
+	if ns.SQLStore == nil {
+		return
+	}
+
+	var items []*queuedMessage
+	err := ns.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		return sess.Where("next_attempt <= ?", getTime()).Asc("id").Limit(queueBatchSize).Find(&items)
+	})
+	if err != nil {
+		ns.log.Error("Failed to get the queued messages", "error", err)
+		return
+	}
+
+	for _, item := range items {
+		claimed := false
+		err := ns.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+			attempts := item.Attempts + 1
+			affected, err := sess.Where("id = ? AND attempts = ?", item.Id, item.Attempts).
+				Cols("attempts", "next_attempt").
+				Update(&queuedMessage{Attempts: attempts, NextAttempt: getTime().Add(retryDelay(attempts))})
+			claimed = affected == 1
+			return err
+		})
+		if err != nil {
+			ns.log.Error("Failed to claim the queued message", "id", item.Id, "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if item.Type == queueTypeWebhook {
+			webhook, err := decodeQueuedWebhook(item.Message)
+			if err != nil {
+				ns.log.Error("Dropping the queued webhook that can't be read", "id", item.Id, "error", err)
+				ns.completeQueued(ctx, item.Id, nil)
+				continue
+			}
+			webhook.queueID = item.Id
+			ns.sendQueuedWebhook(ctx, webhook)
+			continue
+		}
+
+		msg := &Message{}
+		if err := json.Unmarshal([]byte(item.Message), msg); err != nil {
+			ns.log.Error("Dropping the queued email that can't be read", "id", item.Id, "error", err)
+			ns.completeQueued(ctx, item.Id, nil)
+			continue
+		}
+		msg.queueID = item.Id
+		ns.sendQueuedMessage(ctx, msg)
+	}
+}
+
+func decodeQueuedWebhook(message string) (*Webhook, error) {
+	encrypted, err := base64.StdEncoding.DecodeString(message)
+	if err != nil {
+		return nil, err
+	}
+	b, err := util.Decrypt(encrypted, setting.SecretKey)
+	if err != nil {
+		return nil, err
+	}
+	webhook := &Webhook{}
+	if err := json.Unmarshal(b, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// sendQueuedMessage sends an email of the send queue.
+func (ns *NotificationService) sendQueuedMessage(ctx context.Context, msg *Message) {
+	num, err := ns.Send(msg)
+	tos := strings.Join(msg.To, "; ")
+	info := ""
+	if len(msg.Info) > 0 {
+		info = ", info: " + msg.Info
+	}
+	if err != nil {
+		ns.log.Error(fmt.Sprintf("Async sent email %d succeed, not send emails: %s%s err: %s", num, tos, info, err))
+	} else {
+		ns.log.Debug(fmt.Sprintf("Async sent email %d succeed, sent emails: %s%s", num, tos, info))
+	}
+	ns.completeQueuedMessage(ctx, msg, err)
+}
+
+// sendQueuedWebhook sends a webhook of the send queue.
+func (ns *NotificationService) sendQueuedWebhook(ctx context.Context, webhook *Webhook) {
+	err := ns.sendWebRequestSync(ctx, webhook)
+	if err != nil {
+		ns.log.Error("Failed to send webrequest ", "error", err)
+	}
+	ns.completeQueuedWebhook(ctx, webhook, err)
+}
@@ -0,0 +1,148 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryDelay(t *testing.T) {
+	require.Equal(t, time.Minute, retryDelay(1))
+	require.Equal(t, 2*time.Minute, retryDelay(2))
+	require.Equal(t, 32*time.Minute, retryDelay(6))
+	require.Equal(t, time.Hour, retryDelay(7))
+	require.Equal(t, time.Hour, retryDelay(queueMaxAttempts))
+}
+
+func TestNotificationQueue(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	getTime = func() time.Time { return now }
+	t.Cleanup(func() { getTime = time.Now })
+
+	ns := &NotificationService{
+		Cfg:       setting.NewCfg(),
+		SQLStore:  sqlstore.InitTestDB(t),
+		mailQueue: make(chan *Message, 1),
+		log:       log.New("test"),
+	}
+	queued := func() []*queuedMessage {
+		var items []*queuedMessage
+		err := ns.SQLStore.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+			return sess.Asc("id").Find(&items)
+		})
+		require.NoError(t, err)
+		return items
+	}
+
+	msg := &Message{To: []string{"ops@example.com"}, Subject: "Reset your password", Body: map[string]string{"text/html": "body"}}
+	require.NoError(t, ns.enqueueMessage(msg))
+	require.NotZero(t, msg.queueID)
+	require.Equal(t, msg, <-ns.mailQueue)
+
+	t.Run("an email that failed is kept to be retried", func(t *testing.T) {
+		ns.completeQueuedMessage(context.Background(), msg, errors.New("connection refused"))
+		items := queued()
+		require.Len(t, items, 1)
+		require.Equal(t, 1, items[0].Attempts)
+		require.Equal(t, "connection refused", items[0].LastError)
+		require.Equal(t, now.Add(time.Minute).Unix(), items[0].NextAttempt.Unix())
+	})
+
+	t.Run("an email is not retried before it is due", func(t *testing.T) {
+		ns.retryQueuedMessages(context.Background())
+		require.Equal(t, 1, queued()[0].Attempts)
+	})
+
+	t.Run("an email that is due is retried", func(t *testing.T) {
+		now = now.Add(time.Minute)
+		// the SMTP server is not configured, so the retry fails
+		ns.retryQueuedMessages(context.Background())
+		items := queued()
+		require.Len(t, items, 1)
+		require.Equal(t, 2, items[0].Attempts)
+		require.Equal(t, now.Add(2*time.Minute).Unix(), items[0].NextAttempt.Unix())
+	})
+
+	t.Run("an email is dropped after the maximum number of attempts", func(t *testing.T) {
+		for i := 2; i < queueMaxAttempts; i++ {
+			now = now.Add(time.Hour)
+			ns.retryQueuedMessages(context.Background())
+		}
+		require.Empty(t, queued())
+	})
+
+	t.Run("a sent email is removed", func(t *testing.T) {
+		msg := &Message{To: []string{"ops@example.com"}}
+		require.NoError(t, ns.enqueueMessage(msg))
+		<-ns.mailQueue
+		ns.completeQueuedMessage(context.Background(), msg, nil)
+		require.Empty(t, queued())
+	})
+}
+
+func TestNotificationQueueWebhooks(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	getTime = func() time.Time { return now }
+	t.Cleanup(func() { getTime = time.Now })
+
+	var status int32 = http.StatusServiceUnavailable
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		user, password, _ := r.BasicAuth()
+		assert.Equal(t, "admin", user)
+		assert.Equal(t, "secret", password)
+		w.WriteHeader(int(atomic.LoadInt32(&status)))
+	}))
+	t.Cleanup(server.Close)
+
+	ns := &NotificationService{
+		Cfg:          setting.NewCfg(),
+		SQLStore:     sqlstore.InitTestDB(t),
+		webhookQueue: make(chan *Webhook, 1),
+		log:          log.New("test"),
+	}
+	queued := func() []*queuedMessage {
+		var items []*queuedMessage
+		err := ns.SQLStore.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+			return sess.Asc("id").Find(&items)
+		})
+		require.NoError(t, err)
+		return items
+	}
+
+	require.NoError(t, ns.sendWebhookCommandHandler(&models.SendWebhook{Url: server.URL, User: "admin", Password: "secret", Body: "{}"}))
+	items := queued()
+	require.Len(t, items, 1)
+	require.Equal(t, queueTypeWebhook, items[0].Type)
+	require.NotContains(t, items[0].Message, "secret", "the credentials of the webhook are stored encrypted")
+
+	t.Run("a webhook that failed is kept to be retried", func(t *testing.T) {
+		ns.sendQueuedWebhook(context.Background(), <-ns.webhookQueue)
+		require.Equal(t, int32(1), atomic.LoadInt32(&requests))
+		items := queued()
+		require.Len(t, items, 1)
+		require.Equal(t, 1, items[0].Attempts)
+		require.Contains(t, items[0].LastError, "503")
+	})
+
+	t.Run("a webhook that is due is retried after a restart and removed once sent", func(t *testing.T) {
+		now = now.Add(time.Minute)
+		atomic.StoreInt32(&status, http.StatusOK)
+		restarted := &NotificationService{Cfg: ns.Cfg, SQLStore: ns.SQLStore, log: ns.log}
+		restarted.retryQueuedMessages(context.Background())
+		require.Equal(t, int32(2), atomic.LoadInt32(&requests))
+		require.Empty(t, queued())
+	})
+}
@@ -0,0 +1,140 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/context/ctxhttp"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// smsMaxLength is the maximum length of the text messages, longer messages are truncated.
+const smsMaxLength = 1600
+
+var twilioAPIURL = "https://api.twilio.com/2010-04-01"
+
+// SMSProvider sends text messages to phone numbers.
+type SMSProvider interface {
+	SendSMS(ctx context.Context, to, message string) error
+}
+
+// newSMSProvider returns the provider of the SMS settings.
+func newSMSProvider(cfg setting.SmsSettings) (SMSProvider, error) {
+	switch cfg.Provider {
+	case "twilio":
+		if cfg.TwilioAccountSID == "" || cfg.TwilioAuthToken == "" || cfg.FromNumber == "" {
+			return nil, fmt.Errorf("the twilio SMS provider requires the twilio_account_sid, twilio_auth_token and from_number config")
+		}
+		return &twilioSMSProvider{accountSID: cfg.TwilioAccountSID, authToken: cfg.TwilioAuthToken, from: cfg.FromNumber}, nil
+	case "webhook":
+		if cfg.WebhookURL == "" {
+			return nil, fmt.Errorf("the webhook SMS provider requires the webhook_url config")
+		}
+		return &webhookSMSProvider{url: cfg.WebhookURL, token: cfg.WebhookToken, from: cfg.FromNumber}, nil
+	default:
+		return nil, fmt.Errorf("unknown SMS provider %q, it must be twilio or webhook", cfg.Provider)
+	}
+}
+
+// twilioSMSProvider sends the text messages with the Messages API of Twilio.
+type twilioSMSProvider struct {
+	accountSID string
+	authToken  string
+	from       string
+}
+
+func (p *twilioSMSProvider) SendSMS(ctx context.Context, to, message string) error {
+	form := url.Values{}
+	form.Set("To", to)
+	form.Set("From", p.from)
+	form.Set("Body", message)
+
+	u := fmt.Sprintf("%s/Accounts/%s/Messages.json", twilioAPIURL, url.PathEscape(p.accountSID))
+	req, err := http.NewRequest(http.MethodPost, u, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(p.accountSID, p.authToken)
+	return doSMSRequest(ctx, req)
+}
+
+// webhookSMSProvider posts the text messages as JSON to a URL, for the SMS gateways without
+// a dedicated provider.
+type webhookSMSProvider struct {
+	url   string
+	token string
+	from  string
+}
+
+func (p *webhookSMSProvider) SendSMS(ctx context.Context, to, message string) error {
+	body, err := json.Marshal(map[string]string{
+		"to":      to,
+		"from":    p.from,
+		"message": message,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+	return doSMSRequest(ctx, req)
+}
+
+func doSMSRequest(ctx context.Context, req *http.Request) error {
+	req.Header.Set("User-Agent", "Grafana")
+	resp, err := ctxhttp.Do(ctx, netClient, req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode/100 == 2 {
+		_, err := io.Copy(ioutil.Discard, resp.Body)
+		return err
+	}
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("SMS provider response status %v: %s", resp.Status, strings.TrimSpace(string(body)))
+}
+
+// sendSMSCommandHandlerSync sends the text message to each phone number. The message is sent to
+// all the numbers even when some fail, and the first error is returned.
+func (ns *NotificationService) sendSMSCommandHandlerSync(ctx context.Context, cmd *models.SendSMSCommandSync) error {
+	if ns.smsProvider == nil {
+		return models.ErrSmsNotEnabled
+	}
+
+	message := cmd.Message
+	if r := []rune(message); len(r) > smsMaxLength {
+		message = string(r[:smsMaxLength-1]) + "…"
+	}
+
+	var firstErr error
+	for _, to := range cmd.To {
+		if err := ns.smsProvider.SendSMS(ctx, to, message); err != nil {
+			ns.log.Error("Failed to send SMS", "to", to, "error", err)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to send SMS to %s: %w", to, err)
+			}
+		}
+	}
+	return firstErr
+}
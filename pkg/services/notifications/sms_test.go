@@ -0,0 +1,105 @@
+package notifications
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSMSProviders(t *testing.T) {
+	var req *http.Request
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		req, body = r, string(b)
+		if strings.Contains(body, "invalid") {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message": "invalid number"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	t.Run("twilio", func(t *testing.T) {
+		twilioAPIURL = server.URL
+		t.Cleanup(func() { twilioAPIURL = "https://api.twilio.com/2010-04-01" })
+
+		p, err := newSMSProvider(setting.SmsSettings{Provider: "twilio", TwilioAccountSID: "AC123", TwilioAuthToken: "secret", FromNumber: "+15550000000"})
+		require.NoError(t, err)
+		require.NoError(t, p.SendSMS(context.Background(), "+15551234567", "DiskFull is firing"))
+
+		require.Equal(t, "/Accounts/AC123/Messages.json", req.URL.Path)
+		user, password, ok := req.BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "AC123", user)
+		require.Equal(t, "secret", password)
+		require.Equal(t, "Body=DiskFull+is+firing&From=%2B15550000000&To=%2B15551234567", body)
+	})
+
+	t.Run("webhook", func(t *testing.T) {
+		p, err := newSMSProvider(setting.SmsSettings{Provider: "webhook", WebhookURL: server.URL + "/sms", WebhookToken: "token"})
+		require.NoError(t, err)
+		require.NoError(t, p.SendSMS(context.Background(), "+15551234567", "DiskFull is firing"))
+
+		require.Equal(t, "/sms", req.URL.Path)
+		require.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+		require.JSONEq(t, `{"to": "+15551234567", "from": "", "message": "DiskFull is firing"}`, body)
+
+		err = p.SendSMS(context.Background(), "invalid", "DiskFull is firing")
+		require.EqualError(t, err, `SMS provider response status 400 Bad Request: {"message": "invalid number"}`)
+	})
+
+	t.Run("invalid settings", func(t *testing.T) {
+		_, err := newSMSProvider(setting.SmsSettings{Provider: "twilio"})
+		require.Error(t, err)
+		_, err = newSMSProvider(setting.SmsSettings{Provider: "webhook"})
+		require.Error(t, err)
+		_, err = newSMSProvider(setting.SmsSettings{Provider: "carrier-pigeon"})
+		require.EqualError(t, err, `unknown SMS provider "carrier-pigeon", it must be twilio or webhook`)
+	})
+}
+
+func TestSendSMSCommandHandler(t *testing.T) {
+	ns := &NotificationService{log: log.New("test")}
+
+	t.Run("SMS must be enabled", func(t *testing.T) {
+		err := ns.sendSMSCommandHandlerSync(context.Background(), &models.SendSMSCommandSync{To: []string{"+15551234567"}})
+		require.Equal(t, models.ErrSmsNotEnabled, err)
+	})
+
+	t.Run("the message is sent to each number and truncated", func(t *testing.T) {
+		provider := &recordingSMSProvider{}
+		ns.smsProvider = provider
+		err := ns.sendSMSCommandHandlerSync(context.Background(), &models.SendSMSCommandSync{
+			To:      []string{"+15551234567", "+15557654321"},
+			Message: strings.Repeat("a", 2000),
+		})
+		require.NoError(t, err)
+		require.Len(t, provider.sent, 2)
+		require.Equal(t, "+15557654321", provider.sent[1].To)
+		require.Len(t, []rune(provider.sent[0].Message), smsMaxLength)
+	})
+}
+
+type sentSMS struct {
+	To      string
+	Message string
+}
+
+type recordingSMSProvider struct {
+	sent []sentSMS
+}
+
+func (p *recordingSMSProvider) SendSMS(_ context.Context, to, message string) error {
+	p.sent = append(p.sent, sentSMS{To: to, Message: message})
+	return nil
+}
+
@@ -0,0 +1,73 @@
+package notifications
+
+import (
+	"fmt"
+	"time"
+
+	gomail "gopkg.in/mail.v2"
+)
+
+// smtpFailedServerDelay is how long the SMTP servers that failed are tried after the other servers.
+const smtpFailedServerDelay = time.Minute
+
+// sendWithFailover sends the email with the first SMTP server that accepts the connection, in
+// the order of priority. The servers that recently failed are tried last, so that a server that
+// is down doesn't delay every email. The errors of the email itself, such as an invalid address,
+// are returned without failing over.
+func (ns *NotificationService) sendWithFailover(dialers []*gomail.Dialer, m *gomail.Message) error {
+	var err error
+	for _, d := range ns.dialersByHealth(dialers) {
+		var sender gomail.SendCloser
+		sender, err = d.Dial()
+		address := fmt.Sprintf("%s:%d", d.Host, d.Port)
+		ns.setSMTPServerHealth(address, err)
+		if err != nil {
+			ns.log.Warn("Failed to connect to the SMTP server", "host", address, "error", err)
+			continue
+		}
+
+		err = gomail.Send(sender, m)
+		if closeErr := sender.Close(); closeErr != nil {
+			ns.log.Debug("Failed to close the connection to the SMTP server", "host", address, "error", closeErr)
+		}
+		return err
+	}
+	return err
+}
+
+// dialersByHealth returns the dialers of the servers that didn't fail recently, then the others,
+// both in the order of priority.
+func (ns *NotificationService) dialersByHealth(dialers []*gomail.Dialer) []*gomail.Dialer {
+	ns.smtpHealthMtx.Lock()
+	defer ns.smtpHealthMtx.Unlock()
+
+	now := getTime()
+	healthy := make([]*gomail.Dialer, 0, len(dialers))
+	var failed []*gomail.Dialer
+	for _, d := range dialers {
+		if failedAt, ok := ns.smtpFailures[fmt.Sprintf("%s:%d", d.Host, d.Port)]; ok && now.Sub(failedAt) < smtpFailedServerDelay {
+			failed = append(failed, d)
+			continue
+		}
+		healthy = append(healthy, d)
+	}
+	return append(healthy, failed...)
+}
+
+// setSMTPServerHealth records whether the connection to an SMTP server succeeded.
+func (ns *NotificationService) setSMTPServerHealth(address string, err error) {
+	ns.smtpHealthMtx.Lock()
+	defer ns.smtpHealthMtx.Unlock()
+
+	if err != nil {
+		if ns.smtpFailures == nil {
+			ns.smtpFailures = map[string]time.Time{}
+		}
+		ns.smtpFailures[address] = getTime()
+		smtpServerUp.WithLabelValues(address).Set(0)
+		smtpServerFailures.WithLabelValues(address).Inc()
+		return
+	}
+	delete(ns.smtpFailures, address)
+	smtpServerUp.WithLabelValues(address).Set(1)
+}
@@ -0,0 +1,116 @@
+package notifications
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTPServer accepts the emails and returns their recipients on the channel.
+func fakeSMTPServer(t *testing.T) (string, chan string) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+
+	recipients := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer func() { _ = conn.Close() }()
+				r := bufio.NewReader(conn)
+				reply := func(s string) { _, _ = conn.Write([]byte(s + "\r\n")) }
+				reply("220 localhost ESMTP")
+				data := false
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					line = strings.TrimRight(line, "\r\n")
+					switch {
+					case data:
+						if line == "." {
+							data = false
+							reply("250 OK")
+						}
+					case strings.HasPrefix(line, "EHLO"), strings.HasPrefix(line, "HELO"):
+						reply("250 localhost")
+					case strings.HasPrefix(line, "RCPT TO:"):
+						recipients <- strings.Trim(strings.TrimPrefix(line, "RCPT TO:"), "<>")
+						reply("250 OK")
+					case line == "DATA":
+						data = true
+						reply("354 Go ahead")
+					case line == "QUIT":
+						reply("221 Bye")
+						return
+					default:
+						reply("250 OK")
+					}
+				}
+			}(conn)
+		}
+	}()
+	return l.Addr().String(), recipients
+}
+
+// unavailableSMTPServer returns the address of a server that refuses the connections.
+func unavailableSMTPServer(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+	return addr
+}
+
+func TestSMTPFailover(t *testing.T) {
+	now := time.Now()
+	getTime = func() time.Time { return now }
+	t.Cleanup(func() { getTime = time.Now })
+
+	down := unavailableSMTPServer(t)
+	up, recipients := fakeSMTPServer(t)
+
+	ns := &NotificationService{Cfg: setting.NewCfg(), log: log.New("test")}
+	ns.Cfg.Smtp.Hosts = []string{down, up}
+	ns.Cfg.Smtp.StartTLSPolicy = "NoStartTLS"
+	ns.Cfg.Smtp.ContentTypes = []string{"text/plain"}
+	msg := &Message{To: []string{"ops@example.com"}, From: "grafana@example.com", Subject: "Test", Body: map[string]string{"text/plain": "body"}}
+
+	t.Run("the email is sent by the next server when the primary server is down", func(t *testing.T) {
+		sent, err := ns.dialAndSend(msg)
+		require.NoError(t, err)
+		require.Equal(t, 1, sent)
+		require.Equal(t, "ops@example.com", <-recipients)
+	})
+
+	t.Run("the server that failed is tried last", func(t *testing.T) {
+		dialers, err := ns.createDialers()
+		require.NoError(t, err)
+		ordered := ns.dialersByHealth(dialers)
+		require.Equal(t, dialers[1], ordered[0])
+		require.Equal(t, dialers[0], ordered[1])
+
+		now = now.Add(smtpFailedServerDelay)
+		require.Equal(t, dialers, ns.dialersByHealth(dialers))
+	})
+
+	t.Run("the email fails when all the servers are down", func(t *testing.T) {
+		ns.Cfg.Smtp.Hosts = []string{down}
+		sent, err := ns.dialAndSend(msg)
+		require.Error(t, err)
+		require.Equal(t, 0, sent)
+	})
+}
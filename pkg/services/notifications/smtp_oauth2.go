@@ -0,0 +1,68 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/smtp"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const smtpAuthTypeOAuth2 = "oauth2"
+
+// xoauth2Auth implements the XOAUTH2 SASL mechanism used by Office 365 and Gmail,
+// with an access token acquired from the token source for each connection.
+type xoauth2Auth struct {
+	user        string
+	host        string
+	tokenSource oauth2.TokenSource
+}
+
+func (a *xoauth2Auth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	// the access token is a bearer credential, it must not be sent in clear text
+	if !server.TLS && !isLocalhost(server.Name) {
+		return "", nil, errors.New("XOAUTH2 authentication requires an encrypted connection")
+	}
+	if server.Name != a.host {
+		return "", nil, errors.New("wrong host name")
+	}
+
+	token, err := a.tokenSource.Token()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get an OAuth2 access token: %w", err)
+	}
+	return "XOAUTH2", []byte("user=" + a.user + "\x01auth=Bearer " + token.AccessToken + "\x01\x01"), nil
+}
+
+func (a *xoauth2Auth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if more {
+		// the server sends the details of the failure as a challenge, an empty
+		// response makes it end the exchange with the error
+		return []byte{}, nil
+	}
+	return nil, nil
+}
+
+func isLocalhost(name string) bool {
+	return name == "localhost" || name == "127.0.0.1" || name == "::1"
+}
+
+// smtpTokenSource returns the source of the access tokens of the XOAUTH2 authentication. The
+// tokens are cached until they expire, so they are shared by all the connections to the server.
+func (ns *NotificationService) smtpTokenSource() oauth2.TokenSource {
+	ns.tokenSourceMtx.Lock()
+	defer ns.tokenSourceMtx.Unlock()
+
+	if ns.tokenSource == nil {
+		cfg := &clientcredentials.Config{
+			ClientID:     ns.Cfg.Smtp.OAuth2ClientID,
+			ClientSecret: ns.Cfg.Smtp.OAuth2ClientSecret,
+			TokenURL:     ns.Cfg.Smtp.OAuth2TokenURL,
+			Scopes:       ns.Cfg.Smtp.OAuth2Scopes,
+		}
+		ns.tokenSource = cfg.TokenSource(context.Background())
+	}
+	return ns.tokenSource
+}
@@ -0,0 +1,80 @@
+package notifications
+
+import (
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+
+	"github.com/grafana/grafana/pkg/models"
+)
+
+var mailTemplatesMtx sync.RWMutex
+
+// getMailTemplates returns the templates of the emails, which are replaced when they are reloaded.
+func getMailTemplates() *template.Template {
+	mailTemplatesMtx.RLock()
+	defer mailTemplatesMtx.RUnlock()
+	return mailTemplates
+}
+
+// loadMailTemplates parses the built-in templates of the emails, and overrides them with the
+// templates of the same file name in the templates directory of the operator.
+func (ns *NotificationService) loadMailTemplates() error {
+	tmpl := template.New("name")
+	tmpl.Funcs(template.FuncMap{
+		"Subject": subjectTemplateFunc,
+	})
+
+	for _, pattern := range ns.Cfg.Smtp.TemplatesPatterns {
+		templatePattern := filepath.Join(ns.Cfg.StaticRootPath, pattern)
+		_, err := tmpl.ParseGlob(templatePattern)
+		if err != nil {
+			return err
+		}
+	}
+
+	if ns.Cfg.Smtp.TemplatesDir != "" {
+		files, err := ioutil.ReadDir(ns.Cfg.Smtp.TemplatesDir)
+		if err != nil {
+			return fmt.Errorf("failed to read the email templates directory: %w", err)
+		}
+		for _, f := range files {
+			ext := filepath.Ext(f.Name())
+			if f.IsDir() || (ext != ".html" && ext != ".txt") {
+				continue
+			}
+			// only the built-in templates are sent, an unknown name is likely a typo
+			if tmpl.Lookup(f.Name()) == nil {
+				return fmt.Errorf("email template %q doesn't override a built-in template", f.Name())
+			}
+			// nolint:gosec
+			// We can ignore the gosec G304 warning on this one because the path comes from the configuration.
+			content, err := ioutil.ReadFile(filepath.Join(ns.Cfg.Smtp.TemplatesDir, f.Name()))
+			if err != nil {
+				return err
+			}
+			if _, err := tmpl.New(f.Name()).Parse(string(content)); err != nil {
+				return fmt.Errorf("invalid email template %q: %w", f.Name(), err)
+			}
+			ns.log.Debug("Overriding email template", "name", f.Name())
+		}
+	}
+
+	mailTemplatesMtx.Lock()
+	defer mailTemplatesMtx.Unlock()
+	mailTemplates = tmpl
+	return nil
+}
+
+// reloadMailTemplates reloads the templates of the emails. The current templates are kept when
+// the new ones are invalid.
+func (ns *NotificationService) reloadMailTemplates(cmd *models.ReloadEmailTemplatesCommand) error {
+	if err := ns.loadMailTemplates(); err != nil {
+		ns.log.Error("Failed to reload the email templates", "error", err)
+		return err
+	}
+	ns.log.Info("Email templates reloaded")
+	return nil
+}
@@ -24,6 +24,11 @@ type Webhook struct {
 	HttpMethod  string
 	HttpHeader  map[string]string
 	ContentType string
+	// TLSConfig can't be stored, so it is only set for webhooks sent synchronously.
+	TLSConfig *tls.Config `json:"-"`
+
+	// queueID is the ID of the webhook in the send queue, or 0 when it is not stored.
+	queueID int64
 }
 
 var netTransport = &http.Transport{
@@ -41,6 +46,23 @@ var netClient = &http.Client{
 	Transport: netTransport,
 }
 
+// webhookClient returns the client of the webhook, which has its own transport when the webhook
+// has a TLS configuration.
+func webhookClient(webhook *Webhook) *http.Client {
+	if webhook.TLSConfig == nil {
+		return netClient
+	}
+	transport := netTransport.Clone()
+	transport.TLSClientConfig = webhook.TLSConfig.Clone()
+	transport.TLSClientConfig.Renegotiation = tls.RenegotiateFreelyAsClient
+	// the transport is not reused, its connections must not be kept open
+	transport.DisableKeepAlives = true
+	return &http.Client{
+		Timeout:   netClient.Timeout,
+		Transport: transport,
+	}
+}
+
 func (ns *NotificationService) sendWebRequestSync(ctx context.Context, webhook *Webhook) error {
 	ns.log.Debug("Sending webhook", "url", webhook.Url, "http method", webhook.HttpMethod)
 
@@ -48,8 +70,8 @@ func (ns *NotificationService) sendWebRequestSync(ctx context.Context, webhook *
 		webhook.HttpMethod = http.MethodPost
 	}
 
-	if webhook.HttpMethod != http.MethodPost && webhook.HttpMethod != http.MethodPut {
-		return fmt.Errorf("webhook only supports HTTP methods PUT or POST")
+	if webhook.HttpMethod != http.MethodPost && webhook.HttpMethod != http.MethodPut && webhook.HttpMethod != http.MethodPatch {
+		return fmt.Errorf("webhook only supports HTTP methods PUT, POST or PATCH")
 	}
 
 	request, err := http.NewRequest(webhook.HttpMethod, webhook.Url, bytes.NewReader([]byte(webhook.Body)))
@@ -72,7 +94,7 @@ func (ns *NotificationService) sendWebRequestSync(ctx context.Context, webhook *
 		request.Header.Set(k, v)
 	}
 
-	resp, err := ctxhttp.Do(ctx, netClient, request)
+	resp, err := ctxhttp.Do(ctx, webhookClient(webhook), request)
 	if err != nil {
 		return err
 	}
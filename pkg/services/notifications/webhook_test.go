@@ -0,0 +1,34 @@
+package notifications
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendWebRequestSyncTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	ns := &NotificationService{log: log.New("test")}
+
+	t.Run("the certificate of the server is verified with the system CAs by default", func(t *testing.T) {
+		err := ns.sendWebRequestSync(context.Background(), &Webhook{Url: server.URL})
+		require.Error(t, err)
+	})
+
+	t.Run("the certificate of the server is verified with the CA of the webhook", func(t *testing.T) {
+		pool := x509.NewCertPool()
+		pool.AddCert(server.Certificate())
+		err := ns.sendWebRequestSync(context.Background(), &Webhook{Url: server.URL, TLSConfig: &tls.Config{RootCAs: pool}})
+		require.NoError(t, err)
+	})
+}
@@ -1,10 +1,14 @@
 package oauthtoken
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"net/http"
+	"time"
 
 	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/login/social"
 	"github.com/grafana/grafana/pkg/models"
@@ -75,6 +79,15 @@ func (o *Service) GetCurrentOAuthToken(ctx context.Context, user *models.SignedI
 	token, err := connect.TokenSource(ctx, persistedToken).Token()
 	if err != nil {
 		logger.Error("failed to retrieve OAuth access token", "provider", authInfoQuery.Result.AuthModule, "userId", user.UserId, "username", user.Login, "error", err)
+		if isAccessRevoked(err) {
+			if err := bus.Publish(&events.UserCredentialsRevoked{
+				Timestamp: time.Now(),
+				UserId:    user.UserId,
+				Reason:    events.CredentialsRevokedOAuthRevoked,
+			}); err != nil {
+				logger.Error("failed to publish OAuth access revocation", "userId", user.UserId, "error", err)
+			}
+		}
 		return nil
 	}
 
@@ -95,11 +108,75 @@ func (o *Service) GetCurrentOAuthToken(ctx context.Context, user *models.SignedI
 	return token
 }
 
+// HasAccess refreshes the OAuth token of the user to check that the provider still grants them
+// access. It returns false when the provider refuses to refresh the token, and true when the
+// user has no refresh token, since the access can't be checked.
+func (o *Service) HasAccess(ctx context.Context, userID int64) (bool, error) {
+	authInfoQuery := &models.GetAuthInfoQuery{UserId: userID}
+	if err := bus.Dispatch(authInfoQuery); err != nil {
+		return false, err
+	}
+	if authInfoQuery.Result.OAuthRefreshToken == "" {
+		return true, nil
+	}
+
+	authProvider := authInfoQuery.Result.AuthModule
+	connect, err := o.SocialService.GetConnector(authProvider)
+	if err != nil {
+		return false, err
+	}
+	client, err := o.SocialService.GetOAuthHttpClient(authProvider)
+	if err != nil {
+		return false, err
+	}
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, client)
+
+	// an expired token forces the token source to refresh it
+	persistedToken := &oauth2.Token{
+		AccessToken:  authInfoQuery.Result.OAuthAccessToken,
+		Expiry:       time.Unix(1, 0),
+		RefreshToken: authInfoQuery.Result.OAuthRefreshToken,
+		TokenType:    authInfoQuery.Result.OAuthTokenType,
+	}
+	token, err := connect.TokenSource(ctx, persistedToken).Token()
+	if err != nil {
+		if isAccessRevoked(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	// providers rotating refresh tokens invalidate the previous one, so the new token is kept
+	updateAuthCommand := &models.UpdateAuthInfoCommand{
+		UserId:     authInfoQuery.Result.UserId,
+		AuthModule: authInfoQuery.Result.AuthModule,
+		AuthId:     authInfoQuery.Result.AuthId,
+		OAuthToken: token,
+	}
+	if err := bus.Dispatch(updateAuthCommand); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
 // IsOAuthPassThruEnabled returns true if Forward OAuth Identity (oauthPassThru) is enabled for the provided data source.
 func (o *Service) IsOAuthPassThruEnabled(ds *models.DataSource) bool {
 	return ds.JsonData != nil && ds.JsonData.Get("oauthPassThru").MustBool()
 }
 
+// isAccessRevoked returns true if the provider refused to refresh the token because the
+// refresh token is no longer valid, for example when the user's access was revoked.
+func isAccessRevoked(err error) bool {
+	var retrieveErr *oauth2.RetrieveError
+	if !errors.As(err, &retrieveErr) || retrieveErr.Response == nil {
+		return false
+	}
+	if retrieveErr.Response.StatusCode != http.StatusBadRequest && retrieveErr.Response.StatusCode != http.StatusUnauthorized {
+		return false
+	}
+	return bytes.Contains(retrieveErr.Body, []byte("invalid_grant"))
+}
+
 // tokensEq checks for OAuth2 token equivalence given the fields of the struct Grafana is interested in
 func tokensEq(t1, t2 *oauth2.Token) bool {
 	return t1.AccessToken == t2.AccessToken &&
@@ -0,0 +1,142 @@
+// Package passwordpolicy enforces the password rules configured in the
+// [security] section: minimum length, required character classes, reuse of
+// previous passwords and the maximum password age.
+package passwordpolicy
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"time"
+	"unicode"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// Error codes returned to the frontend, which uses them to show which rule
+// the password breaks.
+const (
+	CodeTooShort          = "password.too_short"
+	CodeUppercaseRequired = "password.uppercase_required"
+	CodeLowercaseRequired = "password.lowercase_required"
+	CodeDigitRequired     = "password.digit_required"
+	CodeSymbolRequired    = "password.symbol_required"
+	CodeReused            = "password.reused"
+	CodeExpired           = "password.expired"
+)
+
+// ErrPasswordExpired is returned on login when the password is older than password_max_age.
+var ErrPasswordExpired = &ValidationError{Code: CodeExpired, Message: "Password has expired and must be changed"}
+
+// ValidationError describes the rule a password breaks.
+type ValidationError struct {
+	Code    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// Validate checks a new password against the configured rules. The user is
+// nil for users being created, otherwise the password must not match any of
+// their previous password_history passwords.
+func Validate(ctx context.Context, cfg *setting.Cfg, user *models.User, password string) error {
+	if err := validateComplexity(cfg, password); err != nil {
+		return err
+	}
+
+	if user == nil || cfg.PasswordHistory <= 0 {
+		return nil
+	}
+
+	return validateHistory(ctx, cfg, user, password)
+}
+
+// IsExpired returns true if the password of the user is older than password_max_age.
+func IsExpired(ctx context.Context, cfg *setting.Cfg, user *models.User) (bool, error) {
+	if cfg.PasswordMaxAge <= 0 {
+		return false, nil
+	}
+
+	query := models.GetUserPasswordHistoryQuery{UserId: user.Id, Limit: 1}
+	if err := bus.DispatchCtx(ctx, &query); err != nil {
+		return false, err
+	}
+
+	// users created before the history was recorded count from their creation
+	changed := user.Created
+	if len(query.Result) > 0 {
+		changed = query.Result[0].Created
+	}
+
+	return time.Since(changed) > cfg.PasswordMaxAge, nil
+}
+
+func validateComplexity(cfg *setting.Cfg, password string) error {
+	if len([]rune(password)) < cfg.PasswordMinLength {
+		return &ValidationError{
+			Code:    CodeTooShort,
+			Message: fmt.Sprintf("Password must be at least %d characters long", cfg.PasswordMinLength),
+		}
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	switch {
+	case cfg.PasswordRequireUppercase && !hasUpper:
+		return &ValidationError{Code: CodeUppercaseRequired, Message: "Password must contain an uppercase letter"}
+	case cfg.PasswordRequireLowercase && !hasLower:
+		return &ValidationError{Code: CodeLowercaseRequired, Message: "Password must contain a lowercase letter"}
+	case cfg.PasswordRequireDigit && !hasDigit:
+		return &ValidationError{Code: CodeDigitRequired, Message: "Password must contain a digit"}
+	case cfg.PasswordRequireSymbol && !hasSymbol:
+		return &ValidationError{Code: CodeSymbolRequired, Message: "Password must contain a symbol"}
+	}
+
+	return nil
+}
+
+func validateHistory(ctx context.Context, cfg *setting.Cfg, user *models.User, password string) error {
+	hashed, err := util.EncodePassword(password, user.Salt)
+	if err != nil {
+		return err
+	}
+
+	query := models.GetUserPasswordHistoryQuery{UserId: user.Id, Limit: cfg.PasswordHistory}
+	if err := bus.DispatchCtx(ctx, &query); err != nil {
+		return err
+	}
+
+	// the current password is checked too for users without history
+	previous := []string{user.Password}
+	for _, entry := range query.Result {
+		previous = append(previous, entry.Password)
+	}
+
+	for _, p := range previous {
+		if p != "" && subtle.ConstantTimeCompare([]byte(hashed), []byte(p)) == 1 {
+			return &ValidationError{
+				Code:    CodeReused,
+				Message: fmt.Sprintf("Password must differ from the last %d passwords", cfg.PasswordHistory),
+			}
+		}
+	}
+
+	return nil
+}
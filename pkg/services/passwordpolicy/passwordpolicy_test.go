@@ -0,0 +1,100 @@
+package passwordpolicy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func requireCode(t *testing.T, code string, err error) {
+	t.Helper()
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, code, validationErr.Code)
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("checks the minimum length", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.PasswordMinLength = 8
+
+		requireCode(t, CodeTooShort, Validate(context.Background(), cfg, nil, "short"))
+		require.NoError(t, Validate(context.Background(), cfg, nil, "longenough"))
+	})
+
+	t.Run("checks the required character classes", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.PasswordRequireUppercase = true
+		cfg.PasswordRequireLowercase = true
+		cfg.PasswordRequireDigit = true
+		cfg.PasswordRequireSymbol = true
+
+		requireCode(t, CodeUppercaseRequired, Validate(context.Background(), cfg, nil, "password1!"))
+		requireCode(t, CodeLowercaseRequired, Validate(context.Background(), cfg, nil, "PASSWORD1!"))
+		requireCode(t, CodeDigitRequired, Validate(context.Background(), cfg, nil, "Password!"))
+		requireCode(t, CodeSymbolRequired, Validate(context.Background(), cfg, nil, "Password1"))
+		require.NoError(t, Validate(context.Background(), cfg, nil, "Password1!"))
+	})
+
+	t.Run("rejects previous passwords", func(t *testing.T) {
+		t.Cleanup(bus.ClearBusHandlers)
+
+		cfg := setting.NewCfg()
+		cfg.PasswordHistory = 2
+
+		salt := "salt"
+		current, err := util.EncodePassword("current", salt)
+		require.NoError(t, err)
+		previous, err := util.EncodePassword("previous", salt)
+		require.NoError(t, err)
+
+		bus.AddHandlerCtx("test", func(ctx context.Context, query *models.GetUserPasswordHistoryQuery) error {
+			assert.Equal(t, 2, query.Limit)
+			query.Result = []*models.UserPasswordHistory{
+				{UserId: 1, Password: current},
+				{UserId: 1, Password: previous},
+			}
+			return nil
+		})
+
+		user := &models.User{Id: 1, Salt: salt, Password: current}
+		requireCode(t, CodeReused, Validate(context.Background(), cfg, user, "current"))
+		requireCode(t, CodeReused, Validate(context.Background(), cfg, user, "previous"))
+		require.NoError(t, Validate(context.Background(), cfg, user, "new"))
+	})
+}
+
+func TestIsExpired(t *testing.T) {
+	t.Cleanup(bus.ClearBusHandlers)
+
+	var history []*models.UserPasswordHistory
+	bus.AddHandlerCtx("test", func(ctx context.Context, query *models.GetUserPasswordHistoryQuery) error {
+		query.Result = history
+		return nil
+	})
+
+	cfg := setting.NewCfg()
+	user := &models.User{Id: 1, Created: time.Now().Add(-48 * time.Hour)}
+
+	expired, err := IsExpired(context.Background(), cfg, user)
+	require.NoError(t, err)
+	assert.False(t, expired, "no maximum age")
+
+	cfg.PasswordMaxAge = 24 * time.Hour
+	expired, err = IsExpired(context.Background(), cfg, user)
+	require.NoError(t, err)
+	assert.True(t, expired, "created before the maximum age without history")
+
+	history = []*models.UserPasswordHistory{{UserId: 1, Created: time.Now().Add(-time.Hour)}}
+	expired, err = IsExpired(context.Background(), cfg, user)
+	require.NoError(t, err)
+	assert.False(t, expired, "changed recently")
+}
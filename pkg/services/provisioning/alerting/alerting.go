@@ -0,0 +1,195 @@
+package alerting
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/bus"
+	dboards "github.com/grafana/grafana/pkg/dashboards"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// RuleStore is the storage of the provisioned alert rules.
+type RuleStore interface {
+	UpdateRuleGroup(store.UpdateRuleGroupCmd) error
+	DeleteRuleGroupAlertRules(orgID int64, namespaceUID string, ruleGroup string) ([]string, error)
+}
+
+// Alertmanager applies the provisioned contact points, notification policies and mute timings.
+type Alertmanager interface {
+	SaveAndApplyConfig(orgID int64, cfg *apimodels.PostableUserConfig) error
+}
+
+// Provision alert rules, contact points, notification policies and mute timings
+func Provision(configDirectory string, dashboardStore dboards.Store, ruleStore RuleStore, am Alertmanager) error {
+	ap := newAlertingProvisioner(log.New("provisioning.alerting"), dashboardStore, ruleStore, am)
+	return ap.applyChanges(configDirectory)
+}
+
+// AlertingProvisioner is responsible for provisioning unified alerting
+type AlertingProvisioner struct {
+	log            log.Logger
+	cfgProvider    *configReader
+	dashboardStore dboards.Store
+	ruleStore      RuleStore
+	alertmanager   Alertmanager
+}
+
+func newAlertingProvisioner(log log.Logger, dashboardStore dboards.Store, ruleStore RuleStore, am Alertmanager) AlertingProvisioner {
+	return AlertingProvisioner{
+		log:            log,
+		cfgProvider:    &configReader{log: log},
+		dashboardStore: dashboardStore,
+		ruleStore:      ruleStore,
+		alertmanager:   am,
+	}
+}
+
+func (ap *AlertingProvisioner) applyChanges(configPath string) error {
+	configs, err := ap.cfgProvider.readConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	for _, cfg := range configs {
+		if err := ap.resolveOrgID(cfg); err != nil {
+			return err
+		}
+	}
+
+	if err := checkDuplicates(configs); err != nil {
+		return err
+	}
+
+	for _, cfg := range configs {
+		if err := ap.apply(cfg); err != nil {
+			return fmt.Errorf("failed to provision %q: %w", cfg.Filename, err)
+		}
+	}
+
+	return nil
+}
+
+func (ap *AlertingProvisioner) apply(cfg *alertingAsConfig) error {
+	if err := ap.deleteRuleGroups(cfg.OrgID, cfg.DeleteGroups); err != nil {
+		return err
+	}
+
+	if err := ap.mergeRuleGroups(cfg.OrgID, cfg.Groups); err != nil {
+		return err
+	}
+
+	if cfg.Alertmanager != nil {
+		ap.log.Info("Provisioning Alertmanager configuration", "orgId", cfg.OrgID)
+		if err := cfg.Alertmanager.ProcessConfig(); err != nil {
+			return fmt.Errorf("failed to process the Alertmanager configuration: %w", err)
+		}
+		if err := ap.alertmanager.SaveAndApplyConfig(cfg.OrgID, cfg.Alertmanager); err != nil {
+			return fmt.Errorf("failed to apply the Alertmanager configuration: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (ap *AlertingProvisioner) resolveOrgID(cfg *alertingAsConfig) error {
+	if cfg.OrgID == 0 && cfg.OrgName != "" {
+		getOrg := &models.GetOrgByNameQuery{Name: cfg.OrgName}
+		if err := bus.Dispatch(getOrg); err != nil {
+			return fmt.Errorf("failed to provision %q: %w", cfg.Filename, err)
+		}
+		cfg.OrgID = getOrg.Result.Id
+	}
+	return nil
+}
+
+func (ap *AlertingProvisioner) deleteRuleGroups(orgID int64, groups []*deleteGroupConfig) error {
+	for _, group := range groups {
+		ap.log.Info("Deleting rule group", "orgId", orgID, "folder", group.Folder, "name", group.Name)
+
+		folder, err := ap.dashboardStore.GetFolderByTitle(orgID, group.Folder)
+		if err != nil {
+			if errors.Is(err, models.ErrDashboardNotFound) {
+				continue
+			}
+			return err
+		}
+
+		if _, err := ap.ruleStore.DeleteRuleGroupAlertRules(orgID, folder.Uid, group.Name); err != nil {
+			return fmt.Errorf("failed to delete rule group %s of folder %s: %w", group.Name, group.Folder, err)
+		}
+	}
+
+	return nil
+}
+
+func (ap *AlertingProvisioner) mergeRuleGroups(orgID int64, groups []apimodels.AlertingExportRuleGroup) error {
+	for _, group := range groups {
+		ap.log.Debug("Provisioning rule group", "orgId", orgID, "folder", group.Folder, "name", group.Name)
+
+		folder, err := ap.getOrCreateFolder(orgID, group.Folder)
+		if err != nil {
+			return fmt.Errorf("failed to get folder %s: %w", group.Folder, err)
+		}
+
+		cmd := store.UpdateRuleGroupCmd{
+			OrgID:           orgID,
+			NamespaceUID:    folder.Uid,
+			RuleGroupConfig: group.RuleGroupConfig(),
+			KeepUIDs:        true,
+		}
+		if err := ap.ruleStore.UpdateRuleGroup(cmd); err != nil {
+			return fmt.Errorf("failed to update rule group %s of folder %s: %w", group.Name, group.Folder, err)
+		}
+	}
+
+	return nil
+}
+
+// getOrCreateFolder returns the folder of a rule group, which is created if it doesn't exist yet,
+// the way the folders of the provisioned dashboards are.
+func (ap *AlertingProvisioner) getOrCreateFolder(orgID int64, title string) (*models.Dashboard, error) {
+	folder, err := ap.dashboardStore.GetFolderByTitle(orgID, title)
+	if err == nil {
+		return folder, nil
+	}
+	if !errors.Is(err, models.ErrDashboardNotFound) {
+		return nil, err
+	}
+
+	ap.log.Info("Creating folder of provisioned rule groups", "orgId", orgID, "folder", title)
+	dash := &dashboards.SaveDashboardDTO{}
+	dash.Dashboard = models.NewDashboardFolder(title)
+	dash.Dashboard.IsFolder = true
+	dash.Overwrite = true
+	dash.OrgId = orgID
+	return dashboards.NewProvisioningService(ap.dashboardStore).SaveFolderForProvisionedDashboards(dash)
+}
+
+// checkDuplicates rejects the rule groups and the Alertmanager configurations that are
+// provisioned by more than one file, since the last file would silently win otherwise.
+func checkDuplicates(configs []*alertingAsConfig) error {
+	groups := make(map[string]string)
+	alertmanagers := make(map[int64]string)
+	for _, cfg := range configs {
+		for _, group := range cfg.Groups {
+			key := fmt.Sprintf("%d/%s/%s", cfg.OrgID, group.Folder, group.Name)
+			if filename, ok := groups[key]; ok {
+				return fmt.Errorf("rule group %s of folder %s is provisioned by both %q and %q", group.Name, group.Folder, filename, cfg.Filename)
+			}
+			groups[key] = cfg.Filename
+		}
+
+		if cfg.Alertmanager != nil {
+			if filename, ok := alertmanagers[cfg.OrgID]; ok {
+				return fmt.Errorf("the Alertmanager configuration of organization %d is provisioned by both %q and %q", cfg.OrgID, filename, cfg.Filename)
+			}
+			alertmanagers[cfg.OrgID] = cfg.Filename
+		}
+	}
+	return nil
+}
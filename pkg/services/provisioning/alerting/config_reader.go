@@ -0,0 +1,178 @@
+package alerting
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+	yamlv2 "gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
+)
+
+type configReader struct {
+	log log.Logger
+}
+
+func (cr *configReader) readConfig(path string) ([]*alertingAsConfig, error) {
+	var configs []*alertingAsConfig
+	cr.log.Debug("Looking for alerting provisioning files", "path", path)
+
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		cr.log.Error("Can't read alerting provisioning files from directory", "path", path, "error", err)
+		return configs, nil
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml") {
+			cr.log.Debug("Parsing alerting provisioning file", "path", path, "file.Name", file.Name())
+			cfg, err := cr.parseAlertingConfig(path, file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %q: %w", file.Name(), err)
+			}
+
+			configs = append(configs, cfg)
+		}
+	}
+
+	cr.log.Debug("Validating alerting provisioning files")
+	if err := validateRequiredFields(configs); err != nil {
+		return nil, err
+	}
+
+	if err := checkOrgIDAndOrgName(configs); err != nil {
+		return nil, err
+	}
+
+	if err := interpolateSecureSettings(configs); err != nil {
+		return nil, err
+	}
+
+	return configs, nil
+}
+
+func (cr *configReader) parseAlertingConfig(path string, file os.FileInfo) (*alertingAsConfig, error) {
+	filename, _ := filepath.Abs(filepath.Join(path, file.Name()))
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	// the rule groups and the Alertmanager configuration have the format of the export
+	var export apimodels.AlertingExport
+	if err := yaml.Unmarshal(yamlFile, &export); err != nil {
+		return nil, err
+	}
+	if export.APIVersion != apimodels.AlertingExportAPIVersion {
+		return nil, fmt.Errorf("unsupported apiVersion %d, expected %d", export.APIVersion, apimodels.AlertingExportAPIVersion)
+	}
+
+	var cfg alertingAsConfigV1
+	if err := yamlv2.Unmarshal(yamlFile, &cfg); err != nil {
+		return nil, err
+	}
+
+	alerting := cfg.mapToAlertingFromConfig(export)
+	alerting.Filename = file.Name()
+	return alerting, nil
+}
+
+func validateRequiredFields(configs []*alertingAsConfig) error {
+	for _, cfg := range configs {
+		var errStrings []string
+		for index, group := range cfg.Groups {
+			if group.Folder == "" {
+				errStrings = append(errStrings, fmt.Sprintf("Rule group item %d in %s doesn't contain required field folder", index+1, cfg.Filename))
+			}
+			if group.Name == "" {
+				errStrings = append(errStrings, fmt.Sprintf("Rule group item %d in %s doesn't contain required field name", index+1, cfg.Filename))
+			}
+
+			for ruleIndex, rule := range group.Rules {
+				if rule.GrafanaManagedAlert == nil {
+					errStrings = append(errStrings, fmt.Sprintf("Rule item %d of rule group %q in %s isn't a Grafana managed alert rule", ruleIndex+1, group.Name, cfg.Filename))
+					continue
+				}
+				// rules are matched by UID so that their state is kept when the file is provisioned again
+				if rule.GrafanaManagedAlert.UID == "" {
+					errStrings = append(errStrings, fmt.Sprintf("Rule item %d of rule group %q in %s doesn't contain required field uid", ruleIndex+1, group.Name, cfg.Filename))
+				}
+			}
+		}
+
+		if cfg.Alertmanager != nil {
+			for _, r := range cfg.Alertmanager.AlertmanagerConfig.Receivers {
+				for _, gr := range r.PostableGrafanaReceivers.GrafanaManagedReceivers {
+					// contact points are matched by UID as well
+					if gr.UID == "" {
+						errStrings = append(errStrings, fmt.Sprintf("Contact point %q in %s doesn't contain required field uid", gr.Name, cfg.Filename))
+					}
+				}
+			}
+		}
+
+		for index, group := range cfg.DeleteGroups {
+			if group.Folder == "" {
+				errStrings = append(errStrings, fmt.Sprintf("Deleted rule group item %d in %s doesn't contain required field folder", index+1, cfg.Filename))
+			}
+			if group.Name == "" {
+				errStrings = append(errStrings, fmt.Sprintf("Deleted rule group item %d in %s doesn't contain required field name", index+1, cfg.Filename))
+			}
+		}
+
+		if len(errStrings) != 0 {
+			return fmt.Errorf(strings.Join(errStrings, "\n"))
+		}
+	}
+
+	return nil
+}
+
+func checkOrgIDAndOrgName(configs []*alertingAsConfig) error {
+	for _, cfg := range configs {
+		if cfg.OrgID < 1 {
+			if cfg.OrgName == "" {
+				cfg.OrgID = 1
+			} else {
+				cfg.OrgID = 0
+			}
+		} else {
+			if err := utils.CheckOrgExists(cfg.OrgID); err != nil {
+				return fmt.Errorf("failed to provision %q: %w", cfg.Filename, err)
+			}
+		}
+	}
+	return nil
+}
+
+// interpolateSecureSettings expands the environment variables of the secure settings of the contact
+// points, so that secrets don't have to be written in the provisioning files. The rest of the
+// files isn't interpolated, as the expressions and the templates use $ on their own.
+func interpolateSecureSettings(configs []*alertingAsConfig) error {
+	for _, cfg := range configs {
+		if cfg.Alertmanager == nil {
+			continue
+		}
+		for _, r := range cfg.Alertmanager.AlertmanagerConfig.Receivers {
+			for _, gr := range r.PostableGrafanaReceivers.GrafanaManagedReceivers {
+				for key, value := range gr.SecureSettings {
+					interpolated, err := values.InterpolateString(value)
+					if err != nil {
+						return fmt.Errorf("failed to provision %q: secure setting %s of contact point %s: %w", cfg.Filename, key, gr.Name, err)
+					}
+					gr.SecureSettings[key] = interpolated
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package alerting
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	correctProperties            = "./testdata/test-configs/correct-properties"
+	correctPropertiesWithOrgName = "./testdata/test-configs/correct-properties-with-orgName"
+	noRequiredFields             = "./testdata/test-configs/no-required-fields"
+	duplicateGroups              = "./testdata/test-configs/duplicate-groups"
+	brokenYaml                   = "./testdata/test-configs/broken-yaml"
+	unsupportedVersion           = "./testdata/test-configs/unsupported-version"
+	emptyFolder                  = "./testdata/test-configs/empty_folder"
+)
+
+type fakeRuleStore struct {
+	updated []store.UpdateRuleGroupCmd
+	deleted []string
+}
+
+func (f *fakeRuleStore) UpdateRuleGroup(cmd store.UpdateRuleGroupCmd) error {
+	f.updated = append(f.updated, cmd)
+	return nil
+}
+
+func (f *fakeRuleStore) DeleteRuleGroupAlertRules(orgID int64, namespaceUID string, ruleGroup string) ([]string, error) {
+	f.deleted = append(f.deleted, fmt.Sprintf("%d/%s/%s", orgID, namespaceUID, ruleGroup))
+	return nil, nil
+}
+
+type fakeAlertmanager struct {
+	configs map[int64]*apimodels.PostableUserConfig
+}
+
+func (f *fakeAlertmanager) SaveAndApplyConfig(orgID int64, cfg *apimodels.PostableUserConfig) error {
+	f.configs[orgID] = cfg
+	return nil
+}
+
+func TestAlertingAsConfig(t *testing.T) {
+	sqlStore := sqlstore.InitTestDB(t)
+	for i := 1; i < 3; i++ {
+		orgCommand := models.CreateOrgCommand{Name: fmt.Sprintf("Main Org. %v", i)}
+		require.NoError(t, sqlstore.CreateOrg(&orgCommand))
+	}
+
+	t.Run("Can read correct properties", func(t *testing.T) {
+		require.NoError(t, os.Setenv("TEST_SLACK_URL", "https://hooks.slack.com/secret"))
+		t.Cleanup(func() { _ = os.Unsetenv("TEST_SLACK_URL") })
+
+		cfgProvider := &configReader{log: log.New("test logger")}
+		cfgs, err := cfgProvider.readConfig(correctProperties)
+		require.NoError(t, err)
+		require.Len(t, cfgs, 1)
+
+		cfg := cfgs[0]
+		assert.Equal(t, int64(1), cfg.OrgID)
+		assert.Equal(t, []*deleteGroupConfig{{Folder: "Infrastructure", Name: "legacy"}}, cfg.DeleteGroups)
+
+		require.Len(t, cfg.Groups, 1)
+		group := cfg.Groups[0]
+		assert.Equal(t, "Infrastructure", group.Folder)
+		assert.Equal(t, "disk", group.Name)
+		require.Len(t, group.Rules, 1)
+		rule := group.Rules[0]
+		assert.Equal(t, "disk-full", rule.GrafanaManagedAlert.UID)
+		assert.Equal(t, "Disk almost full", rule.GrafanaManagedAlert.Title)
+		assert.Equal(t, "ops", rule.Labels["team"])
+
+		// the expressions are not interpolated
+		var model map[string]interface{}
+		require.NoError(t, json.Unmarshal(rule.GrafanaManagedAlert.Data[0].Model, &model))
+		assert.Equal(t, "$A > 90", model["expression"])
+
+		require.NotNil(t, cfg.Alertmanager)
+		receivers := cfg.Alertmanager.GetGrafanaReceiverMap()
+		require.Contains(t, receivers, "ops-slack")
+		assert.Equal(t, "https://hooks.slack.com/secret", receivers["ops-slack"].SecureSettings["url"])
+
+		inhibitRules := cfg.Alertmanager.AlertmanagerConfig.InhibitRules
+		require.Len(t, inhibitRules, 1)
+		assert.Equal(t, `severity="critical"`, inhibitRules[0].SourceMatchers[0].String())
+		assert.Equal(t, `severity="warning"`, inhibitRules[0].TargetMatchers[0].String())
+		assert.Equal(t, "datacenter", inhibitRules[0].Equal.String())
+	})
+
+	t.Run("Can read correct properties with orgName", func(t *testing.T) {
+		cfgProvider := &configReader{log: log.New("test logger")}
+		cfgs, err := cfgProvider.readConfig(correctPropertiesWithOrgName)
+		require.NoError(t, err)
+		require.Len(t, cfgs, 1)
+		assert.Equal(t, int64(0), cfgs[0].OrgID)
+		assert.Equal(t, "Main Org. 2", cfgs[0].OrgName)
+		assert.Nil(t, cfgs[0].Alertmanager)
+	})
+
+	t.Run("Missing required fields should return error", func(t *testing.T) {
+		cfgProvider := &configReader{log: log.New("test logger")}
+		_, err := cfgProvider.readConfig(noRequiredFields)
+		require.Error(t, err)
+
+		errString := err.Error()
+		assert.Contains(t, errString, "Rule group item 1 in alerting.yaml doesn't contain required field folder")
+		assert.Contains(t, errString, `Rule item 1 of rule group "disk" in alerting.yaml doesn't contain required field uid`)
+		assert.Contains(t, errString, `Contact point "ops" in alerting.yaml doesn't contain required field uid`)
+		assert.Contains(t, errString, "Deleted rule group item 1 in alerting.yaml doesn't contain required field name")
+	})
+
+	t.Run("Broken yaml should return error", func(t *testing.T) {
+		cfgProvider := &configReader{log: log.New("test logger")}
+		_, err := cfgProvider.readConfig(brokenYaml)
+		require.Error(t, err)
+	})
+
+	t.Run("Unsupported apiVersion should return error", func(t *testing.T) {
+		cfgProvider := &configReader{log: log.New("test logger")}
+		_, err := cfgProvider.readConfig(unsupportedVersion)
+		require.EqualError(t, err, `failed to parse "alerting.yaml": unsupported apiVersion 2, expected 1`)
+	})
+
+	t.Run("Empty folder should return no configs", func(t *testing.T) {
+		cfgProvider := &configReader{log: log.New("test logger")}
+		cfgs, err := cfgProvider.readConfig(emptyFolder)
+		require.NoError(t, err)
+		assert.Empty(t, cfgs)
+	})
+
+	t.Run("Rule groups and the Alertmanager configuration are provisioned", func(t *testing.T) {
+		ruleStore := &fakeRuleStore{}
+		am := &fakeAlertmanager{configs: map[int64]*apimodels.PostableUserConfig{}}
+		ap := newAlertingProvisioner(log.New("test logger"), sqlStore, ruleStore, am)
+
+		require.NoError(t, ap.applyChanges(correctProperties))
+
+		// the folder of the rule group is created
+		folder, err := sqlStore.GetFolderByTitle(1, "Infrastructure")
+		require.NoError(t, err)
+
+		require.Len(t, ruleStore.updated, 1)
+		cmd := ruleStore.updated[0]
+		assert.Equal(t, int64(1), cmd.OrgID)
+		assert.Equal(t, folder.Uid, cmd.NamespaceUID)
+		assert.Equal(t, "disk", cmd.RuleGroupConfig.Name)
+		assert.True(t, cmd.KeepUIDs)
+		// there is nothing to delete from a folder that didn't exist
+		assert.Empty(t, ruleStore.deleted)
+
+		require.Contains(t, am.configs, int64(1))
+		secureURL := am.configs[1].GetGrafanaReceiverMap()["ops-slack"].SecureSettings["url"]
+		assert.NotEqual(t, "https://hooks.slack.com/secret", secureURL, "secure settings should be encrypted")
+
+		// provisioning the same files again uses the existing folder
+		require.NoError(t, ap.applyChanges(correctProperties))
+		require.Len(t, ruleStore.updated, 2)
+		assert.Equal(t, folder.Uid, ruleStore.updated[1].NamespaceUID)
+		assert.Equal(t, []string{fmt.Sprintf("1/%s/legacy", folder.Uid)}, ruleStore.deleted)
+	})
+
+	t.Run("Rule groups are provisioned to the organization with the given name", func(t *testing.T) {
+		ruleStore := &fakeRuleStore{}
+		am := &fakeAlertmanager{configs: map[int64]*apimodels.PostableUserConfig{}}
+		ap := newAlertingProvisioner(log.New("test logger"), sqlStore, ruleStore, am)
+
+		require.NoError(t, ap.applyChanges(correctPropertiesWithOrgName))
+
+		require.Len(t, ruleStore.updated, 1)
+		assert.Equal(t, int64(2), ruleStore.updated[0].OrgID)
+		_, err := sqlStore.GetFolderByTitle(2, "Databases")
+		require.NoError(t, err)
+		assert.Empty(t, am.configs)
+	})
+
+	t.Run("Rule groups provisioned by more than one file should return error", func(t *testing.T) {
+		ruleStore := &fakeRuleStore{}
+		am := &fakeAlertmanager{configs: map[int64]*apimodels.PostableUserConfig{}}
+		ap := newAlertingProvisioner(log.New("test logger"), sqlStore, ruleStore, am)
+
+		err := ap.applyChanges(duplicateGroups)
+		require.EqualError(t, err, `rule group disk of folder Infrastructure is provisioned by both "first.yaml" and "second.yaml"`)
+		assert.Empty(t, ruleStore.updated)
+	})
+}
@@ -0,0 +1,54 @@
+package alerting
+
+import (
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+// alertingAsConfig is the normalized content of an alerting provisioning file.
+type alertingAsConfig struct {
+	Filename     string
+	OrgID        int64
+	OrgName      string
+	Groups       []apimodels.AlertingExportRuleGroup
+	DeleteGroups []*deleteGroupConfig
+	Alertmanager *apimodels.PostableUserConfig
+}
+
+type deleteGroupConfig struct {
+	Folder string
+	Name   string
+}
+
+// alertingAsConfigV1 maps the fields of the provisioning files that are not part of the export
+// format of the alerting configuration, which holds the provisioned rule groups and Alertmanager
+// configuration.
+type alertingAsConfigV1 struct {
+	OrgID        values.Int64Value      `json:"orgId" yaml:"orgId"`
+	OrgName      values.StringValue     `json:"orgName" yaml:"orgName"`
+	DeleteGroups []*deleteGroupConfigV1 `json:"deleteGroups" yaml:"deleteGroups"`
+}
+
+type deleteGroupConfigV1 struct {
+	Folder values.StringValue `json:"folder" yaml:"folder"`
+	Name   values.StringValue `json:"name" yaml:"name"`
+}
+
+// mapToAlertingFromConfig maps the provisioning file to the normalized alertingAsConfig object.
+func (cfg *alertingAsConfigV1) mapToAlertingFromConfig(export apimodels.AlertingExport) *alertingAsConfig {
+	r := &alertingAsConfig{
+		OrgID:        cfg.OrgID.Value(),
+		OrgName:      cfg.OrgName.Value(),
+		Groups:       export.Groups,
+		Alertmanager: export.Alertmanager,
+	}
+
+	for _, group := range cfg.DeleteGroups {
+		r.DeleteGroups = append(r.DeleteGroups, &deleteGroupConfig{
+			Folder: group.Folder.Value(),
+			Name:   group.Name.Value(),
+		})
+	}
+
+	return r
+}
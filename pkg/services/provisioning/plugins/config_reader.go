@@ -9,6 +9,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/manager/installer"
 	"gopkg.in/yaml.v2"
 )
 
@@ -89,6 +90,15 @@ func (cr *configReaderImpl) parsePluginConfig(path string, file os.FileInfo) (*p
 func validateRequiredField(apps []*pluginsAsConfig) error {
 	for i := range apps {
 		var errStrings []string
+		for index, plugin := range apps[i].Plugins {
+			if plugin.PluginID == "" {
+				errStrings = append(
+					errStrings,
+					fmt.Sprintf("plugin item %d in configuration doesn't contain required field id", index+1),
+				)
+			}
+		}
+
 		for index, app := range apps[i].Apps {
 			if app.PluginID == "" {
 				errStrings = append(
@@ -107,12 +117,36 @@ func validateRequiredField(apps []*pluginsAsConfig) error {
 }
 
 func (cr *configReaderImpl) validatePluginsConfig(apps []*pluginsAsConfig) error {
+	// the plugins to install are installed before the apps are provisioned
+	versions := map[string]string{}
+	images := map[string]string{}
+	for i := range apps {
+		for _, plugin := range apps[i].Plugins {
+			if version, exists := versions[plugin.PluginID]; exists && version != plugin.Version {
+				return fmt.Errorf("plugin %q is provisioned with different versions: %q and %q", plugin.PluginID, version, plugin.Version)
+			}
+			if image, exists := images[plugin.PluginID]; exists && image != plugin.Image {
+				return fmt.Errorf("plugin %q is provisioned with different images: %q and %q", plugin.PluginID, image, plugin.Image)
+			}
+			if plugin.Image != "" {
+				if _, err := installer.ParseOCIReference(plugin.Image); err != nil {
+					return fmt.Errorf("plugin %q: %w", plugin.PluginID, err)
+				}
+			}
+			versions[plugin.PluginID] = plugin.Version
+			images[plugin.PluginID] = plugin.Image
+		}
+	}
+
 	for i := range apps {
 		if apps[i].Apps == nil {
 			continue
 		}
 
 		for _, app := range apps[i].Apps {
+			if _, provisioned := versions[app.PluginID]; provisioned {
+				continue
+			}
 			if !cr.pluginManager.IsAppInstalled(app.PluginID) {
 				return fmt.Errorf("app plugin not installed: %q", app.PluginID)
 			}
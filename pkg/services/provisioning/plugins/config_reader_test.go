@@ -10,11 +10,14 @@ import (
 )
 
 const (
-	incorrectSettings = "./testdata/test-configs/incorrect-settings"
-	brokenYaml        = "./testdata/test-configs/broken-yaml"
-	emptyFolder       = "./testdata/test-configs/empty_folder"
-	unknownApp        = "./testdata/test-configs/unknown-app"
-	correctProperties = "./testdata/test-configs/correct-properties"
+	incorrectSettings   = "./testdata/test-configs/incorrect-settings"
+	brokenYaml          = "./testdata/test-configs/broken-yaml"
+	emptyFolder         = "./testdata/test-configs/empty_folder"
+	unknownApp          = "./testdata/test-configs/unknown-app"
+	correctProperties   = "./testdata/test-configs/correct-properties"
+	installPlugins      = "./testdata/test-configs/install-plugins"
+	conflictingVersions = "./testdata/test-configs/conflicting-versions"
+	invalidImage        = "./testdata/test-configs/invalid-image"
 )
 
 func TestConfigReader(t *testing.T) {
@@ -87,6 +90,37 @@ func TestConfigReader(t *testing.T) {
 	})
 }
 
+func TestConfigReaderPlugins(t *testing.T) {
+	t.Run("Can read plugins to install", func(t *testing.T) {
+		cfgProvider := newConfigReader(log.New("test logger"), fakePluginManager{})
+		cfg, err := cfgProvider.readConfig(installPlugins)
+		require.NoError(t, err)
+		require.Len(t, cfg, 1)
+
+		require.Equal(t, []*pluginFromConfig{
+			{PluginID: "test-app", Version: "1.2.0"},
+			{PluginID: "test-datasource"},
+			{PluginID: "test-panel", Image: "registry.example.com/plugins/test-panel:1.0.0"},
+		}, cfg[0].Plugins)
+		require.Len(t, cfg[0].Apps, 1)
+		require.Equal(t, "test-app", cfg[0].Apps[0].PluginID)
+	})
+
+	t.Run("Plugin provisioned with different versions should return error", func(t *testing.T) {
+		cfgProvider := newConfigReader(log.New("test logger"), fakePluginManager{})
+		_, err := cfgProvider.readConfig(conflictingVersions)
+		require.Error(t, err)
+		require.Equal(t, `plugin "test-datasource" is provisioned with different versions: "1.0.0" and "2.0.0"`, err.Error())
+	})
+
+	t.Run("Plugin provisioned with an invalid image should return error", func(t *testing.T) {
+		cfgProvider := newConfigReader(log.New("test logger"), fakePluginManager{})
+		_, err := cfgProvider.readConfig(invalidImage)
+		require.Error(t, err)
+		require.Equal(t, `plugin "test-panel": invalid OCI reference "test-panel:1.0.0": the registry is missing`, err.Error())
+	})
+}
+
 type fakePluginManager struct {
 	plugins.Manager
 
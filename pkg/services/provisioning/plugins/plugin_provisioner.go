@@ -1,7 +1,9 @@
 package plugins
 
 import (
+	"context"
 	"errors"
+	"fmt"
 
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -9,22 +11,63 @@ import (
 	"github.com/grafana/grafana/pkg/plugins"
 )
 
-// Provision scans a directory for provisioning config files
-// and provisions the app in those files.
+// Provision scans a directory for provisioning config files, installs
+// the plugins and provisions the apps in those files.
 func Provision(configDirectory string, pluginManager plugins.Manager) error {
 	logger := log.New("provisioning.plugins")
 	ap := PluginProvisioner{
-		log:         logger,
-		cfgProvider: newConfigReader(logger, pluginManager),
+		log:           logger,
+		cfgProvider:   newConfigReader(logger, pluginManager),
+		pluginManager: pluginManager,
 	}
 	return ap.applyChanges(configDirectory)
 }
 
-// PluginProvisioner is responsible for provisioning apps based on
-// configuration read by the `configReader`
+// PluginProvisioner is responsible for installing plugins and provisioning
+// apps based on configuration read by the `configReader`
 type PluginProvisioner struct {
-	log         log.Logger
-	cfgProvider configReader
+	log           log.Logger
+	cfgProvider   configReader
+	pluginManager plugins.Manager
+}
+
+// install installs the plugins that are not installed, and updates the ones
+// installed in another version than the version of the configuration.
+func (ap *PluginProvisioner) install(cfg *pluginsAsConfig) error {
+	for _, p := range cfg.Plugins {
+		plugin := ap.pluginManager.GetPlugin(p.PluginID)
+		if plugin != nil && (p.Version == "" || plugin.Info.Version == p.Version) {
+			continue
+		}
+
+		if plugin == nil {
+			ap.log.Info("Installing plugin from configuration", "id", p.PluginID, "version", p.Version, "image", p.Image)
+		} else {
+			ap.log.Info("Updating plugin from configuration", "id", p.PluginID, "from", plugin.Info.Version, "to", p.Version, "image", p.Image)
+		}
+		if err := ap.installPlugin(p); err != nil {
+			return fmt.Errorf("failed to install plugin %q: %w", p.PluginID, err)
+		}
+	}
+
+	return nil
+}
+
+// installPlugin installs a plugin from its image if it has one, and from the
+// plugin repository otherwise.
+func (ap *PluginProvisioner) installPlugin(p *pluginFromConfig) error {
+	if p.Image == "" {
+		return ap.pluginManager.Install(context.Background(), p.PluginID, p.Version)
+	}
+
+	if err := ap.pluginManager.InstallFromOCI(context.Background(), p.PluginID, p.Image); err != nil {
+		return err
+	}
+	// the plugin would otherwise be installed again on every start
+	if plugin := ap.pluginManager.GetPlugin(p.PluginID); p.Version != "" && plugin != nil && plugin.Info.Version != p.Version {
+		return fmt.Errorf("image %q contains version %q instead of %q", p.Image, plugin.Info.Version, p.Version)
+	}
+	return nil
 }
 
 func (ap *PluginProvisioner) apply(cfg *pluginsAsConfig) error {
@@ -73,6 +116,12 @@ func (ap *PluginProvisioner) applyChanges(configPath string) error {
 		return err
 	}
 
+	for _, cfg := range configs {
+		if err := ap.install(cfg); err != nil {
+			return err
+		}
+	}
+
 	for _, cfg := range configs {
 		if err := ap.apply(cfg); err != nil {
 			return err
@@ -1,12 +1,14 @@
 package plugins
 
 import (
+	"context"
 	"errors"
 	"testing"
 
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/stretchr/testify/require"
 )
 
@@ -85,6 +87,69 @@ func TestPluginProvisioner(t *testing.T) {
 	})
 }
 
+func TestPluginProvisionerInstall(t *testing.T) {
+	cfg := []*pluginsAsConfig{
+		{
+			Plugins: []*pluginFromConfig{
+				{PluginID: "missing-plugin"},
+				{PluginID: "outdated-plugin", Version: "2.0.0"},
+				{PluginID: "up-to-date-plugin", Version: "1.0.0"},
+				{PluginID: "unversioned-plugin"},
+				{PluginID: "image-plugin", Image: "registry.example.com/plugins/image-plugin:1.0.0"},
+			},
+		},
+	}
+
+	t.Run("Should install missing and outdated plugins", func(t *testing.T) {
+		pm := &fakeInstallPluginManager{
+			plugins: map[string]*plugins.PluginBase{
+				"outdated-plugin":    {Info: plugins.PluginInfo{Version: "1.0.0"}},
+				"up-to-date-plugin":  {Info: plugins.PluginInfo{Version: "1.0.0"}},
+				"unversioned-plugin": {Info: plugins.PluginInfo{Version: "1.0.0"}},
+			},
+		}
+		ap := PluginProvisioner{log: log.New("test"), cfgProvider: &testConfigReader{result: cfg}, pluginManager: pm}
+		err := ap.applyChanges("")
+		require.NoError(t, err)
+		require.Equal(t, []string{"missing-plugin@", "outdated-plugin@2.0.0", "image-plugin@registry.example.com/plugins/image-plugin:1.0.0"}, pm.installed)
+	})
+
+	t.Run("Should return error when a plugin fails to install", func(t *testing.T) {
+		pm := &fakeInstallPluginManager{installErr: errors.New("not found")}
+		ap := PluginProvisioner{log: log.New("test"), cfgProvider: &testConfigReader{result: cfg}, pluginManager: pm}
+		err := ap.applyChanges("")
+		require.EqualError(t, err, `failed to install plugin "missing-plugin": not found`)
+	})
+}
+
+type fakeInstallPluginManager struct {
+	plugins.Manager
+
+	plugins    map[string]*plugins.PluginBase
+	installed  []string
+	installErr error
+}
+
+func (pm *fakeInstallPluginManager) GetPlugin(id string) *plugins.PluginBase {
+	return pm.plugins[id]
+}
+
+func (pm *fakeInstallPluginManager) Install(ctx context.Context, pluginID, version string) error {
+	if pm.installErr != nil {
+		return pm.installErr
+	}
+	pm.installed = append(pm.installed, pluginID+"@"+version)
+	return nil
+}
+
+func (pm *fakeInstallPluginManager) InstallFromOCI(ctx context.Context, pluginID, ref string) error {
+	if pm.installErr != nil {
+		return pm.installErr
+	}
+	pm.installed = append(pm.installed, pluginID+"@"+ref)
+	return nil
+}
+
 type testConfigReader struct {
 	result []*pluginsAsConfig
 	err    error
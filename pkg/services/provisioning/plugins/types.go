@@ -5,7 +5,17 @@ import "github.com/grafana/grafana/pkg/services/provisioning/values"
 // pluginsAsConfig is a normalized data object for plugins config data. Any config version should be mappable.
 // to this type.
 type pluginsAsConfig struct {
-	Apps []*appFromConfig
+	Plugins []*pluginFromConfig
+	Apps    []*appFromConfig
+}
+
+// pluginFromConfig is a plugin that must be installed, in a specific version
+// if Version is set. Plugins with an Image are installed from the referenced
+// OCI artifact instead of the plugin repository.
+type pluginFromConfig struct {
+	PluginID string
+	Version  string
+	Image    string
 }
 
 type appFromConfig struct {
@@ -19,6 +29,12 @@ type appFromConfig struct {
 	SecureJSONData map[string]string
 }
 
+type pluginFromConfigV0 struct {
+	ID      values.StringValue `json:"id" yaml:"id"`
+	Version values.StringValue `json:"version" yaml:"version"`
+	Image   values.StringValue `json:"image" yaml:"image"`
+}
+
 type appFromConfigV0 struct {
 	OrgID          values.Int64Value     `json:"org_id" yaml:"org_id"`
 	OrgName        values.StringValue    `json:"org_name" yaml:"org_name"`
@@ -30,7 +46,8 @@ type appFromConfigV0 struct {
 
 // pluginsAsConfigV0 is a mapping for zero version configs. This is mapped to its normalised version.
 type pluginsAsConfigV0 struct {
-	Apps []*appFromConfigV0 `json:"apps" yaml:"apps"`
+	Plugins []*pluginFromConfigV0 `json:"plugins" yaml:"plugins"`
+	Apps    []*appFromConfigV0    `json:"apps" yaml:"apps"`
 }
 
 // mapToPluginsFromConfig maps config syntax to a normalized notificationsAsConfig object. Every version
@@ -41,6 +58,14 @@ func (cfg *pluginsAsConfigV0) mapToPluginsFromConfig() *pluginsAsConfig {
 		return r
 	}
 
+	for _, plugin := range cfg.Plugins {
+		r.Plugins = append(r.Plugins, &pluginFromConfig{
+			PluginID: plugin.ID.Value(),
+			Version:  plugin.Version.Value(),
+			Image:    plugin.Image.Value(),
+		})
+	}
+
 	for _, app := range cfg.Apps {
 		r.Apps = append(r.Apps, &appFromConfig{
 			OrgID:          app.OrgID.Value(),
@@ -5,9 +5,13 @@ import (
 	"path/filepath"
 	"sync"
 
+	dboards "github.com/grafana/grafana/pkg/dashboards"
 	"github.com/grafana/grafana/pkg/infra/log"
 	plugifaces "github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/registry"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/provisioning/alerting"
 	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
 	"github.com/grafana/grafana/pkg/services/provisioning/datasources"
 	"github.com/grafana/grafana/pkg/services/provisioning/notifiers"
@@ -17,12 +21,21 @@ import (
 	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
+// AlertingService is the unified alerting service, which stores the provisioned alert rules and
+// Alertmanager configuration.
+type AlertingService interface {
+	IsDisabled() bool
+	GetRuleStore() store.RuleStore
+	GetAlertmanager() *notifier.Alertmanager
+}
+
 type ProvisioningService interface {
 	registry.BackgroundService
 	RunInitProvisioners() error
 	ProvisionDatasources() error
 	ProvisionPlugins() error
 	ProvisionNotifications() error
+	ProvisionAlerting() error
 	ProvisionDashboards() error
 	GetDashboardProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
@@ -42,6 +55,7 @@ func NewProvisioningServiceImpl() *provisioningServiceImpl {
 		log:                     log.New("provisioning"),
 		newDashboardProvisioner: dashboards.New,
 		provisionNotifiers:      notifiers.Provision,
+		provisionAlerting:       alerting.Provision,
 		provisionDatasources:    datasources.Provision,
 		provisionPlugins:        plugins.Provision,
 	}
@@ -51,6 +65,7 @@ func NewProvisioningServiceImpl() *provisioningServiceImpl {
 func newProvisioningServiceImpl(
 	newDashboardProvisioner dashboards.DashboardProvisionerFactory,
 	provisionNotifiers func(string) error,
+	provisionAlerting func(string, dboards.Store, alerting.RuleStore, alerting.Alertmanager) error,
 	provisionDatasources func(string) error,
 	provisionPlugins func(string, plugifaces.Manager) error,
 ) *provisioningServiceImpl {
@@ -58,6 +73,7 @@ func newProvisioningServiceImpl(
 		log:                     log.New("provisioning"),
 		newDashboardProvisioner: newDashboardProvisioner,
 		provisionNotifiers:      provisionNotifiers,
+		provisionAlerting:       provisionAlerting,
 		provisionDatasources:    provisionDatasources,
 		provisionPlugins:        provisionPlugins,
 	}
@@ -67,11 +83,13 @@ type provisioningServiceImpl struct {
 	Cfg                     *setting.Cfg       `inject:""`
 	SQLStore                *sqlstore.SQLStore `inject:""`
 	PluginManager           plugifaces.Manager `inject:""`
+	AlertingService         AlertingService    `inject:""`
 	log                     log.Logger
 	pollingCtxCancel        context.CancelFunc
 	newDashboardProvisioner dashboards.DashboardProvisionerFactory
 	dashboardProvisioner    dashboards.DashboardProvisioner
 	provisionNotifiers      func(string) error
+	provisionAlerting       func(string, dboards.Store, alerting.RuleStore, alerting.Alertmanager) error
 	provisionDatasources    func(string) error
 	provisionPlugins        func(string, plugifaces.Manager) error
 	mutex                   sync.Mutex
@@ -97,6 +115,11 @@ func (ps *provisioningServiceImpl) RunInitProvisioners() error {
 		return err
 	}
 
+	err = ps.ProvisionAlerting()
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -147,6 +170,16 @@ func (ps *provisioningServiceImpl) ProvisionNotifications() error {
 	return errutil.Wrap("Alert notification provisioning error", err)
 }
 
+func (ps *provisioningServiceImpl) ProvisionAlerting() error {
+	// the rules and the Alertmanager only exist with unified alerting
+	if ps.AlertingService == nil || ps.AlertingService.IsDisabled() {
+		return nil
+	}
+	alertingPath := filepath.Join(ps.Cfg.ProvisioningPath, "alerting")
+	err := ps.provisionAlerting(alertingPath, ps.SQLStore, ps.AlertingService.GetRuleStore(), ps.AlertingService.GetAlertmanager())
+	return errutil.Wrap("Alerting provisioning error", err)
+}
+
 func (ps *provisioningServiceImpl) ProvisionDashboards() error {
 	dashboardPath := filepath.Join(ps.Cfg.ProvisioningPath, "dashboards")
 	dashProvisioner, err := ps.newDashboardProvisioner(dashboardPath, ps.SQLStore)
@@ -241,6 +241,13 @@ func transformMap(i map[interface{}]interface{}) (interface{}, interface{}, erro
 	return transformed, raw, nil
 }
 
+// InterpolateString returns the value of a string of a YAML config with its environment variables
+// expanded, for the strings that are not unmarshaled into one of the value types.
+func InterpolateString(val string) (string, error) {
+	value, _, err := interpolateValue(val)
+	return value, err
+}
+
 // interpolateValue returns the final value after interpolation. In addition to environment variable interpolation,
 // expanders available for the settings file are expanded here.
 // For a literal '$', '$$' can be used to avoid interpolation.
@@ -25,7 +25,13 @@ func (qs *QuotaService) Init() error {
 }
 
 func (qs *QuotaService) QuotaReached(c *models.ReqContext, target string) (bool, error) {
-	if !qs.Cfg.Quota.Enabled {
+	return qs.QuotaReachedWith(c, target, 1)
+}
+
+// QuotaReachedWith returns whether adding the given number of items of the target exceeds its quotas.
+// Removing items never reaches a quota.
+func (qs *QuotaService) QuotaReachedWith(c *models.ReqContext, target string, added int64) (bool, error) {
+	if !qs.Cfg.Quota.Enabled || added <= 0 {
 		return false, nil
 	}
 	// No request context means this is a background service, like LDAP Background Sync.
@@ -68,7 +74,7 @@ func (qs *QuotaService) QuotaReached(c *models.ReqContext, target string) (bool,
 			if err := bus.Dispatch(&query); err != nil {
 				return true, err
 			}
-			if query.Result.Used >= scope.DefaultLimit {
+			if query.Result.Used+added > scope.DefaultLimit {
 				return true, nil
 			}
 		case "org":
@@ -91,7 +97,7 @@ func (qs *QuotaService) QuotaReached(c *models.ReqContext, target string) (bool,
 				return true, nil
 			}
 
-			if query.Result.Used >= query.Result.Limit {
+			if query.Result.Used+added > query.Result.Limit {
 				return true, nil
 			}
 		case "user":
@@ -109,7 +115,7 @@ func (qs *QuotaService) QuotaReached(c *models.ReqContext, target string) (bool,
 				return true, nil
 			}
 
-			if query.Result.Used >= query.Result.Limit {
+			if query.Result.Used+added > query.Result.Limit {
 				return true, nil
 			}
 		}
@@ -170,6 +176,18 @@ func (qs *QuotaService) getQuotaScopes(target string) ([]models.QuotaScope, erro
 			models.QuotaScope{Name: "org", Target: target, DefaultLimit: qs.Cfg.Quota.Org.AlertRule},
 		)
 		return scopes, nil
+	case "alert_rule_group":
+		scopes = append(scopes,
+			models.QuotaScope{Name: "global", Target: target, DefaultLimit: qs.Cfg.Quota.Global.AlertRuleGroup},
+			models.QuotaScope{Name: "org", Target: target, DefaultLimit: qs.Cfg.Quota.Org.AlertRuleGroup},
+		)
+		return scopes, nil
+	case "alert_contact_point":
+		scopes = append(scopes,
+			models.QuotaScope{Name: "global", Target: target, DefaultLimit: qs.Cfg.Quota.Global.AlertContactPoint},
+			models.QuotaScope{Name: "org", Target: target, DefaultLimit: qs.Cfg.Quota.Org.AlertContactPoint},
+		)
+		return scopes, nil
 	default:
 		return scopes, ErrInvalidQuotaTarget
 	}
@@ -0,0 +1,95 @@
+package rendering
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/util"
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+const (
+	// RenderTokenHeader is the header of the requests to the remote rendering
+	// service with their auth token.
+	RenderTokenHeader = "X-Auth-Token"
+	// RenderTokenIssuer and RenderTokenAudience are the issuer and audience
+	// claims of the auth tokens.
+	RenderTokenIssuer   = "grafana"
+	RenderTokenAudience = "grafana-image-renderer"
+	// renderTokenLifetime is how long the auth tokens are valid for, which only
+	// needs to cover the time for the rendering service to receive the request.
+	renderTokenLifetime = 30 * time.Second
+	// renderTokenLeeway is the clock skew tolerated when verifying tokens.
+	renderTokenLeeway = 10 * time.Second
+)
+
+// renderTokenClaims are the claims of the auth tokens of the requests to the
+// remote rendering service. URL is the URL of the page to render, which ties a
+// token to its request.
+type renderTokenClaims struct {
+	jwt.Claims
+	URL string `json:"url"`
+}
+
+// signRenderToken mints an auth token for a request to the remote rendering
+// service to render renderURL, which is a JWT signed with HMAC-SHA256 with the
+// secret shared with the service.
+func signRenderToken(secret, renderURL string, now time.Time) (string, error) {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte(secret)}, (&jose.SignerOptions{}).WithType("JWT"))
+	if err != nil {
+		return "", err
+	}
+
+	id, err := util.GetRandomString(16)
+	if err != nil {
+		return "", err
+	}
+
+	claims := renderTokenClaims{
+		Claims: jwt.Claims{
+			Issuer:   RenderTokenIssuer,
+			Audience: jwt.Audience{RenderTokenAudience},
+			ID:       id,
+			IssuedAt: jwt.NewNumericDate(now),
+			Expiry:   jwt.NewNumericDate(now.Add(renderTokenLifetime)),
+		},
+		URL: renderURL,
+	}
+	return jwt.Signed(signer).Claims(claims).CompactSerialize()
+}
+
+// VerifyRenderToken verifies an auth token of a request to render renderURL,
+// as the remote rendering service does. The service should also reject the
+// tokens whose ID it has already seen within their lifetime.
+func VerifyRenderToken(secret, token, renderURL string, now time.Time) error {
+	parsed, err := jwt.ParseSigned(token)
+	if err != nil {
+		return fmt.Errorf("invalid render token: %w", err)
+	}
+	for _, header := range parsed.Headers {
+		if header.Algorithm != string(jose.HS256) {
+			return fmt.Errorf("invalid render token: unsupported algorithm %q", header.Algorithm)
+		}
+	}
+
+	var claims renderTokenClaims
+	if err := parsed.Claims([]byte(secret), &claims); err != nil {
+		return fmt.Errorf("invalid render token: %w", err)
+	}
+	if err := claims.ValidateWithLeeway(jwt.Expected{
+		Issuer:   RenderTokenIssuer,
+		Audience: jwt.Audience{RenderTokenAudience},
+		Time:     now,
+	}, renderTokenLeeway); err != nil {
+		return fmt.Errorf("invalid render token: %w", err)
+	}
+	if claims.ID == "" || claims.IssuedAt == nil || claims.Expiry == nil {
+		return errors.New("invalid render token: the id, iat and exp claims are required")
+	}
+	if claims.URL != renderURL {
+		return errors.New("invalid render token: the token was issued for another URL")
+	}
+	return nil
+}
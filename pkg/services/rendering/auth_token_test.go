@@ -0,0 +1,65 @@
+package rendering
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderToken(t *testing.T) {
+	now := time.Now()
+	renderURL := "http://localhost:3000/d-solo/abc/test?panelId=1&render=1"
+	token, err := signRenderToken("secret", renderURL, now)
+	require.NoError(t, err)
+
+	t.Run("Should verify tokens", func(t *testing.T) {
+		require.NoError(t, VerifyRenderToken("secret", token, renderURL, now.Add(10*time.Second)))
+	})
+
+	t.Run("Should reject expired tokens", func(t *testing.T) {
+		err := VerifyRenderToken("secret", token, renderURL, now.Add(renderTokenLifetime+renderTokenLeeway+time.Second))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "token is expired")
+	})
+
+	t.Run("Should reject tokens signed with another secret", func(t *testing.T) {
+		require.Error(t, VerifyRenderToken("other", token, renderURL, now))
+	})
+
+	t.Run("Should reject tokens issued for another URL", func(t *testing.T) {
+		err := VerifyRenderToken("secret", token, "http://localhost:3000/d/other", now)
+		require.EqualError(t, err, "invalid render token: the token was issued for another URL")
+	})
+
+	t.Run("Should mint a different token for each request", func(t *testing.T) {
+		other, err := signRenderToken("secret", renderURL, now)
+		require.NoError(t, err)
+		assert.NotEqual(t, token, other)
+	})
+}
+
+func TestRenderViaHTTPWithToken(t *testing.T) {
+	var tokenErr error
+	renderer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenErr = VerifyRenderToken("secret", r.Header.Get(RenderTokenHeader), r.URL.Query().Get("url"), time.Now())
+		_, _ = w.Write([]byte("png"))
+	}))
+	t.Cleanup(renderer.Close)
+
+	rs := &RenderingService{Cfg: setting.NewCfg(), log: log.New("test"), domain: "localhost"}
+	rs.Cfg.ImagesDir = t.TempDir()
+	rs.Cfg.RendererUrl = renderer.URL + "/render"
+	rs.Cfg.RendererCallbackUrl = "http://localhost:3000/"
+	rs.Cfg.RendererAuthToken = "secret"
+
+	_, err := rs.renderViaHTTP(context.Background(), "key", Opts{Timeout: 10 * time.Second, Path: "d-solo/abc/test?panelId=1"})
+	require.NoError(t, err)
+	require.NoError(t, tokenErr)
+}
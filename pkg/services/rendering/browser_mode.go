@@ -0,0 +1,429 @@
+package rendering
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+var (
+	browserExecutables = []string{"chromium", "chromium-browser", "google-chrome", "google-chrome-stable"}
+	reDevToolsURL      = regexp.MustCompile(`DevTools listening on (ws://\S+)`)
+)
+
+// panelsRenderedExpression is evaluated in the rendered page to know whether its
+// panels are rendered, which the frontend profiler counts in window.panelsRendered.
+const panelsRenderedExpression = `(function() {
+	if (document.readyState !== 'complete') {
+		return false;
+	}
+	const panelCount = document.querySelectorAll('.panel-solo').length || document.querySelectorAll('.panel-container').length;
+	return window.panelsRendered >= panelCount;
+})()`
+
+// pageHeightExpression is evaluated in the rendered page to know the height of
+// its whole content, for full page screenshots.
+const pageHeightExpression = `(function() {
+	const view = document.querySelector('.scrollbar-view') || document.documentElement;
+	return view.scrollHeight;
+})()`
+
+// fullPageViewportHeight is the height of the viewport that full page
+// screenshots start from.
+const fullPageViewportHeight = 1080
+
+// browser is a headless Chrome or Chromium process, which the built-in renderer
+// drives through the DevTools protocol.
+type browser struct {
+	cmd         *exec.Cmd
+	userDataDir string
+	devToolsURL string
+	exited      chan struct{}
+}
+
+// findBrowser returns the path of the browser executable, looking up the known
+// executables in the PATH when no path is configured.
+func findBrowser(path string) (string, error) {
+	if path != "" {
+		return exec.LookPath(path)
+	}
+	for _, name := range browserExecutables {
+		if p, err := exec.LookPath(name); err == nil {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("no browser found in the PATH, looked for %s", strings.Join(browserExecutables, ", "))
+}
+
+func startBrowser(path string, logger log.Logger) (*browser, error) {
+	userDataDir, err := ioutil.TempDir("", "grafana-browser")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the browser data directory: %w", err)
+	}
+
+	cmd := exec.Command(path,
+		"--headless",
+		"--disable-gpu",
+		"--hide-scrollbars",
+		"--mute-audio",
+		"--no-first-run",
+		"--no-default-browser-check",
+		"--remote-debugging-address=127.0.0.1",
+		"--remote-debugging-port=0",
+		"--user-data-dir="+userDataDir,
+		"about:blank",
+	)
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		if err := os.RemoveAll(userDataDir); err != nil {
+			logger.Warn("Failed to remove the browser data directory", "dir", userDataDir, "err", err)
+		}
+		return nil, fmt.Errorf("failed to start the browser: %w", err)
+	}
+
+	b := &browser{cmd: cmd, userDataDir: userDataDir, exited: make(chan struct{})}
+
+	// the browser prints the URL of its DevTools endpoint once it listens,
+	// and stderr must be read until it exits so that it doesn't block on it
+	urls := make(chan string, 1)
+	go func() {
+		scanner := bufio.NewScanner(stderr)
+		listening := false
+		for scanner.Scan() {
+			if m := reDevToolsURL.FindStringSubmatch(scanner.Text()); m != nil && !listening {
+				listening = true
+				urls <- m[1]
+			}
+		}
+		_, _ = io.Copy(ioutil.Discard, stderr)
+		err := cmd.Wait()
+		logger.Debug("Browser exited", "err", err)
+		close(b.exited)
+	}()
+
+	select {
+	case u := <-urls:
+		b.devToolsURL = u
+		return b, nil
+	case <-b.exited:
+		b.stop(logger)
+		return nil, errors.New("browser exited before listening for DevTools connections")
+	case <-time.After(30 * time.Second):
+		b.stop(logger)
+		return nil, errors.New("browser didn't listen for DevTools connections within 30s")
+	}
+}
+
+// stop kills the browser process and removes its data directory.
+func (b *browser) stop(logger log.Logger) {
+	if err := b.cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		logger.Warn("Failed to kill the browser", "err", err)
+	}
+	<-b.exited
+	if err := os.RemoveAll(b.userDataDir); err != nil {
+		logger.Warn("Failed to remove the browser data directory", "dir", b.userDataDir, "err", err)
+	}
+}
+
+func (rs *RenderingService) browserAvailable() bool {
+	return rs.Cfg.RendererBrowserEnabled
+}
+
+func (rs *RenderingService) runBrowser(ctx context.Context) error {
+	path, err := findBrowser(rs.Cfg.RendererBrowserPath)
+	if err != nil {
+		return fmt.Errorf("failed to find the browser of the built-in renderer: %w", err)
+	}
+
+	b, err := startBrowser(path, rs.log)
+	if err != nil {
+		return err
+	}
+	rs.browser = b
+	rs.log.Info("Backend rendering via built-in browser", "path", path)
+
+	select {
+	case <-ctx.Done():
+	case <-b.exited:
+		rs.log.Error("Browser of the built-in renderer exited, rendering is unavailable until Grafana restarts")
+	}
+	b.stop(rs.log)
+	return nil
+}
+
+func (rs *RenderingService) renderViaBrowser(ctx context.Context, renderKey string, opts Opts) (*RenderResult, error) {
+	if rs.browser == nil {
+		// the browser is still starting
+		return nil, ErrRenderUnavailable
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	filePath, err := rs.getNewFilePath(RenderPNG)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := dialDevTools(ctx, rs.browser.devToolsURL)
+	if err != nil {
+		return nil, err
+	}
+	// closing the connection interrupts the pending DevTools calls when the
+	// rendering times out
+	go func() {
+		<-ctx.Done()
+		_ = conn.ws.Close()
+	}()
+
+	png, err := conn.screenshot(ctx, browserPage{
+		URL:               rs.getURL(opts.Path),
+		RenderKey:         renderKey,
+		Domain:            rs.domain,
+		Timezone:          opts.Timezone,
+		Width:             opts.Width,
+		Height:            opts.Height,
+		DeviceScaleFactor: opts.DeviceScaleFactor,
+		Headers:           opts.Headers,
+	}, rs.log)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		rs.log.Info("Rendering timed out")
+		return nil, ErrTimeout
+	}
+	if err != nil {
+		return nil, fmt.Errorf("rendering failed: %w", err)
+	}
+
+	if err := ioutil.WriteFile(filePath, png, 0600); err != nil {
+		return nil, err
+	}
+	return &RenderResult{FilePath: filePath}, nil
+}
+
+func (rs *RenderingService) renderCSVViaBrowser(ctx context.Context, renderKey string, opts CSVOpts) (*RenderCSVResult, error) {
+	return nil, ErrRenderUnavailable
+}
+
+// browserPage is a page to take a screenshot of. A negative height takes a
+// screenshot of the full page.
+type browserPage struct {
+	URL               string
+	RenderKey         string
+	Domain            string
+	Timezone          string
+	Width             int
+	Height            int
+	DeviceScaleFactor float64
+	Headers           map[string][]string
+}
+
+type devToolsRequest struct {
+	ID        int64       `json:"id"`
+	SessionID string      `json:"sessionId,omitempty"`
+	Method    string      `json:"method"`
+	Params    interface{} `json:"params,omitempty"`
+}
+
+type devToolsResponse struct {
+	ID     int64           `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// devToolsConn is a connection to the DevTools endpoint of a browser. Its calls
+// aren't safe for concurrent use.
+type devToolsConn struct {
+	ws     *websocket.Conn
+	nextID int64
+}
+
+func dialDevTools(ctx context.Context, url string) (*devToolsConn, error) {
+	ws, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the browser: %w", err)
+	}
+	return &devToolsConn{ws: ws}, nil
+}
+
+// call calls a DevTools method of the browser, or of the page of a session,
+// and unmarshals its result into result unless it's nil.
+func (c *devToolsConn) call(sessionID, method string, params, result interface{}) error {
+	c.nextID++
+	id := c.nextID
+	if err := c.ws.WriteJSON(devToolsRequest{ID: id, SessionID: sessionID, Method: method, Params: params}); err != nil {
+		return fmt.Errorf("failed to call %s: %w", method, err)
+	}
+
+	for {
+		var res devToolsResponse
+		if err := c.ws.ReadJSON(&res); err != nil {
+			return fmt.Errorf("failed to call %s: %w", method, err)
+		}
+		// events have no ID, and aren't of interest
+		if res.ID != id {
+			continue
+		}
+		if res.Error != nil {
+			return fmt.Errorf("%s failed: %s", method, res.Error.Message)
+		}
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal(res.Result, result)
+	}
+}
+
+// screenshot opens the page in a new tab of the browser, waits for its panels
+// to render, and returns a PNG screenshot of it. The tab is opened in its own
+// browser context, so that the render keys of concurrent renders don't overwrite
+// each other, and which the browser disposes when the connection closes.
+func (c *devToolsConn) screenshot(ctx context.Context, page browserPage, logger log.Logger) ([]byte, error) {
+	var browserContext struct {
+		BrowserContextID string `json:"browserContextId"`
+	}
+	if err := c.call("", "Target.createBrowserContext", map[string]interface{}{"disposeOnDetach": true}, &browserContext); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := c.call("", "Target.disposeBrowserContext", map[string]interface{}{"browserContextId": browserContext.BrowserContextID}, nil); err != nil {
+			logger.Debug("Failed to dispose the browser context", "err", err)
+		}
+	}()
+
+	var target struct {
+		TargetID string `json:"targetId"`
+	}
+	if err := c.call("", "Target.createTarget", map[string]interface{}{"url": "about:blank", "browserContextId": browserContext.BrowserContextID}, &target); err != nil {
+		return nil, err
+	}
+
+	var session struct {
+		SessionID string `json:"sessionId"`
+	}
+	if err := c.call("", "Target.attachToTarget", map[string]interface{}{"targetId": target.TargetID, "flatten": true}, &session); err != nil {
+		return nil, err
+	}
+
+	headers := map[string]string{}
+	for k, values := range page.Headers {
+		headers[k] = strings.Join(values, ", ")
+	}
+	height := page.Height
+	if height < 0 {
+		height = fullPageViewportHeight
+	}
+	setup := []struct {
+		method string
+		params interface{}
+	}{
+		{method: "Network.enable"},
+		{method: "Network.setCookie", params: map[string]interface{}{"name": "renderKey", "value": page.RenderKey, "domain": page.Domain, "path": "/"}},
+		{method: "Network.setExtraHTTPHeaders", params: map[string]interface{}{"headers": headers}},
+		{method: "Emulation.setDeviceMetricsOverride", params: map[string]interface{}{
+			"width": page.Width, "height": height, "deviceScaleFactor": page.DeviceScaleFactor, "mobile": false,
+		}},
+	}
+	for _, step := range setup {
+		if err := c.call(session.SessionID, step.method, step.params, nil); err != nil {
+			return nil, err
+		}
+	}
+	if page.Timezone != "" {
+		// the browser only supports IANA time zones, and the dashboard time zone
+		// in the URL applies otherwise
+		if err := c.call(session.SessionID, "Emulation.setTimezoneOverride", map[string]interface{}{"timezoneId": page.Timezone}, nil); err != nil {
+			logger.Debug("Failed to set the time zone of the browser tab", "timezone", page.Timezone, "err", err)
+		}
+	}
+
+	var navigation struct {
+		ErrorText string `json:"errorText"`
+	}
+	if err := c.call(session.SessionID, "Page.navigate", map[string]interface{}{"url": page.URL}, &navigation); err != nil {
+		return nil, err
+	}
+	if navigation.ErrorText != "" {
+		return nil, fmt.Errorf("failed to load %s: %s", page.URL, navigation.ErrorText)
+	}
+
+	if err := c.waitForPanels(ctx, session.SessionID); err != nil {
+		return nil, err
+	}
+
+	if page.Height < 0 {
+		// resizing the viewport to the whole content renders the panels that
+		// were out of view
+		value, err := c.evaluate(session.SessionID, pageHeightExpression)
+		if err != nil {
+			return nil, err
+		}
+		if contentHeight, ok := value.(float64); ok && int(contentHeight) > height {
+			params := map[string]interface{}{"width": page.Width, "height": int(contentHeight), "deviceScaleFactor": page.DeviceScaleFactor, "mobile": false}
+			if err := c.call(session.SessionID, "Emulation.setDeviceMetricsOverride", params, nil); err != nil {
+				return nil, err
+			}
+			if err := c.waitForPanels(ctx, session.SessionID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var capture struct {
+		Data string `json:"data"`
+	}
+	if err := c.call(session.SessionID, "Page.captureScreenshot", map[string]interface{}{"format": "png"}, &capture); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(capture.Data)
+}
+
+// waitForPanels waits until the panels of the page of a session are rendered.
+func (c *devToolsConn) waitForPanels(ctx context.Context, sessionID string) error {
+	for {
+		value, err := c.evaluate(sessionID, panelsRenderedExpression)
+		if err != nil {
+			return err
+		}
+		if rendered, _ := value.(bool); rendered {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// evaluate evaluates a JavaScript expression in the page of a session, and
+// returns its value.
+func (c *devToolsConn) evaluate(sessionID, expression string) (interface{}, error) {
+	var evaluation struct {
+		Result struct {
+			Value interface{} `json:"value"`
+		} `json:"result"`
+	}
+	if err := c.call(sessionID, "Runtime.evaluate", map[string]interface{}{"expression": expression, "returnByValue": true}, &evaluation); err != nil {
+		return nil, err
+	}
+	return evaluation.Result.Value, nil
+}
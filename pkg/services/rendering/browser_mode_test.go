@@ -0,0 +1,140 @@
+package rendering
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderViaBrowser(t *testing.T) {
+	fake := newFakeBrowser(t)
+	rs := &RenderingService{
+		Cfg:     setting.NewCfg(),
+		log:     log.New("test"),
+		browser: &browser{devToolsURL: fake.url},
+		domain:  "localhost",
+	}
+	rs.Cfg.ImagesDir = t.TempDir()
+	rs.Cfg.HTTPPort = "3000"
+	rs.Cfg.Protocol = setting.HTTPScheme
+
+	t.Run("Should take a screenshot of the page once its panels are rendered", func(t *testing.T) {
+		result, err := rs.renderViaBrowser(context.Background(), "key", Opts{
+			Width:             1000,
+			Height:            500,
+			DeviceScaleFactor: 2,
+			Timeout:           10 * time.Second,
+			Path:              "d-solo/abc/test?panelId=1",
+			Headers:           map[string][]string{"Accept-Language": {"en-US"}},
+		})
+		require.NoError(t, err)
+
+		png, err := ioutil.ReadFile(result.FilePath)
+		require.NoError(t, err)
+		assert.Equal(t, "png", string(png))
+
+		assert.Equal(t, map[string]interface{}{"name": "renderKey", "value": "key", "domain": "localhost", "path": "/"}, fake.params["Network.setCookie"])
+		assert.Equal(t, map[string]interface{}{"headers": map[string]interface{}{"Accept-Language": "en-US"}}, fake.params["Network.setExtraHTTPHeaders"])
+		assert.Equal(t, float64(2), fake.params["Emulation.setDeviceMetricsOverride"]["deviceScaleFactor"])
+		assert.Equal(t, "http://localhost:3000/d-solo/abc/test?panelId=1&render=1", fake.params["Page.navigate"]["url"])
+		assert.Contains(t, fake.params, "Target.disposeBrowserContext")
+	})
+
+	t.Run("Should time out when the panels don't render", func(t *testing.T) {
+		fake.renderedAfter = 1000
+		_, err := rs.renderViaBrowser(context.Background(), "key", Opts{Timeout: 300 * time.Millisecond, Path: "d/abc?"})
+		require.Equal(t, ErrTimeout, err)
+	})
+}
+
+func TestFindBrowser(t *testing.T) {
+	t.Run("Should fail when the configured browser doesn't exist", func(t *testing.T) {
+		_, err := findBrowser("/does/not/exist/chromium")
+		require.Error(t, err)
+	})
+
+	t.Run("Should look up the known browsers in the PATH", func(t *testing.T) {
+		path := os.Getenv("PATH")
+		require.NoError(t, os.Setenv("PATH", t.TempDir()))
+		t.Cleanup(func() { require.NoError(t, os.Setenv("PATH", path)) })
+
+		_, err := findBrowser("")
+		require.EqualError(t, err, "no browser found in the PATH, looked for "+strings.Join(browserExecutables, ", "))
+	})
+}
+
+// fakeBrowser is a DevTools endpoint, which records the parameters of the calls
+// and reports the panels rendered after renderedAfter evaluations.
+type fakeBrowser struct {
+	url           string
+	params        map[string]map[string]interface{}
+	renderedAfter int
+}
+
+func newFakeBrowser(t *testing.T) *fakeBrowser {
+	t.Helper()
+
+	b := &fakeBrowser{params: map[string]map[string]interface{}{}, renderedAfter: 2}
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer func() { _ = ws.Close() }()
+
+		evaluations := 0
+		for {
+			var req struct {
+				ID     int64                  `json:"id"`
+				Method string                 `json:"method"`
+				Params map[string]interface{} `json:"params"`
+			}
+			if err := ws.ReadJSON(&req); err != nil {
+				return
+			}
+			b.params[req.Method] = req.Params
+
+			result := map[string]interface{}{}
+			switch req.Method {
+			case "Target.createBrowserContext":
+				result["browserContextId"] = "context"
+			case "Target.createTarget":
+				result["targetId"] = "target"
+			case "Target.attachToTarget":
+				result["sessionId"] = "session"
+			case "Runtime.evaluate":
+				evaluations++
+				result["result"] = map[string]interface{}{"type": "boolean", "value": evaluations > b.renderedAfter}
+			case "Page.captureScreenshot":
+				result["data"] = base64.StdEncoding.EncodeToString([]byte("png"))
+			}
+
+			// events are sent along with the responses of the browser
+			if err := ws.WriteJSON(map[string]interface{}{"method": "Page.frameNavigated"}); err != nil {
+				return
+			}
+			res, _ := json.Marshal(map[string]interface{}{"id": req.ID, "result": result})
+			if err := ws.WriteMessage(websocket.TextMessage, res); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	b.url = "ws" + strings.TrimPrefix(server.URL, "http")
+	return b
+}
@@ -0,0 +1,102 @@
+package rendering
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+)
+
+func init() {
+	remotecache.Register(&cachedImage{})
+}
+
+const (
+	renderCacheKeyPrefix = "render-image-%s"
+	// renderCacheMaxSize is the size of the largest images that are cached.
+	renderCacheMaxSize = 5 << 20
+)
+
+// cachedImage is a rendered image in the remote cache.
+type cachedImage struct {
+	Data []byte
+}
+
+// renderCacheKey returns the cache key of the image rendered with the options,
+// which depends on the dashboard, panel, time range and variables of the path
+// regardless of the order of its parameters, and on the user that renders it.
+func renderCacheKey(opts Opts) string {
+	path := opts.Path
+	if i := strings.Index(path, "?"); i >= 0 {
+		if query, err := url.ParseQuery(path[i+1:]); err == nil {
+			// Encode sorts the parameters by key
+			path = path[:i] + "?" + query.Encode()
+		}
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%d\n%d\n%s\n%s\n%dx%d@%g\n%s\n%s", opts.OrgID, opts.UserID, opts.OrgRole, path,
+		opts.Width, opts.Height, opts.DeviceScaleFactor, opts.Timezone, opts.Encoding)
+	return fmt.Sprintf(renderCacheKeyPrefix, hex.EncodeToString(h.Sum(nil)))
+}
+
+// cachedRender returns a copy of the cached image rendered with the options, if
+// any.
+func (rs *RenderingService) cachedRender(opts Opts) (*RenderResult, bool) {
+	if rs.Cfg.RendererCacheTTL <= 0 {
+		return nil, false
+	}
+
+	val, err := rs.RemoteCacheService.Get(renderCacheKey(opts))
+	if err != nil {
+		if !errors.Is(err, remotecache.ErrCacheItemNotFound) {
+			rs.log.Warn("Failed to get rendered image from cache", "err", err)
+		}
+		return nil, false
+	}
+	image, ok := val.(*cachedImage)
+	if !ok {
+		return nil, false
+	}
+
+	// each render gets its own file, as the callers may remove it or upload it
+	// under its name
+	filePath, err := rs.getNewFilePath(RenderPNG)
+	if err != nil {
+		rs.log.Warn("Failed to copy cached image", "err", err)
+		return nil, false
+	}
+	if err := ioutil.WriteFile(filePath, image.Data, 0600); err != nil {
+		rs.log.Warn("Failed to copy cached image", "path", filePath, "err", err)
+		return nil, false
+	}
+
+	rs.log.Debug("Using cached image", "path", opts.Path)
+	return &RenderResult{FilePath: filePath}, true
+}
+
+// cacheRender caches the image rendered with the options.
+func (rs *RenderingService) cacheRender(opts Opts, result *RenderResult) {
+	if rs.Cfg.RendererCacheTTL <= 0 {
+		return
+	}
+
+	// nolint:gosec
+	data, err := ioutil.ReadFile(result.FilePath)
+	if err != nil {
+		rs.log.Warn("Failed to read rendered image to cache it", "path", result.FilePath, "err", err)
+		return
+	}
+	if len(data) > renderCacheMaxSize {
+		return
+	}
+
+	if err := rs.RemoteCacheService.Set(renderCacheKey(opts), &cachedImage{Data: data}, rs.Cfg.RendererCacheTTL); err != nil {
+		rs.log.Warn("Failed to cache rendered image", "err", err)
+	}
+}
@@ -0,0 +1,85 @@
+package rendering
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderCache(t *testing.T) {
+	renders := 0
+	rs := &RenderingService{
+		Cfg:                setting.NewCfg(),
+		log:                log.New("test"),
+		RemoteCacheService: remotecache.NewFakeStore(t),
+		queue:              newRenderQueue(0, 0),
+	}
+	rs.Cfg.ImagesDir = t.TempDir()
+	rs.Cfg.RendererUrl = "http://localhost:8081/render"
+	rs.Cfg.RendererCacheTTL = time.Minute
+	rs.renderAction = func(ctx context.Context, renderKey string, opts Opts) (*RenderResult, error) {
+		renders++
+		filePath, err := rs.getNewFilePath(RenderPNG)
+		require.NoError(t, err)
+		require.NoError(t, ioutil.WriteFile(filePath, []byte(opts.Path), 0600))
+		return &RenderResult{FilePath: filePath}, nil
+	}
+
+	opts := Opts{Width: 1000, Height: 500, Timeout: time.Second, OrgID: 1, ConcurrentLimit: 1, Path: "d-solo/abc/test?orgId=1&panelId=2&from=1&to=2"}
+
+	t.Run("Should reuse the image of the same panel state", func(t *testing.T) {
+		first, err := rs.Render(context.Background(), opts)
+		require.NoError(t, err)
+
+		reordered := opts
+		reordered.Path = "d-solo/abc/test?to=2&from=1&panelId=2&orgId=1"
+		second, err := rs.Render(context.Background(), reordered)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, renders)
+		assert.NotEqual(t, first.FilePath, second.FilePath)
+		data, err := ioutil.ReadFile(second.FilePath)
+		require.NoError(t, err)
+		assert.Equal(t, opts.Path, string(data))
+	})
+
+	t.Run("Should render other panel states", func(t *testing.T) {
+		renders = 0
+		for _, path := range []string{
+			"d-solo/abc/test?orgId=1&panelId=3&from=1&to=2",
+			"d-solo/abc/test?orgId=1&panelId=2&from=1&to=3",
+			"d-solo/abc/test?orgId=1&panelId=2&from=1&to=2&var-host=a",
+		} {
+			other := opts
+			other.Path = path
+			_, err := rs.Render(context.Background(), other)
+			require.NoError(t, err)
+		}
+
+		otherOrg := opts
+		otherOrg.OrgID = 2
+		_, err := rs.Render(context.Background(), otherOrg)
+		require.NoError(t, err)
+
+		assert.Equal(t, 4, renders)
+	})
+
+	t.Run("Should not cache images when the cache is disabled", func(t *testing.T) {
+		rs.Cfg.RendererCacheTTL = 0
+		renders = 0
+		other := opts
+		other.Path = "d-solo/abc/test?orgId=1&panelId=4"
+		for i := 0; i < 2; i++ {
+			_, err := rs.Render(context.Background(), other)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 2, renders)
+	})
+}
@@ -137,6 +137,15 @@ func (rs *RenderingService) doRequest(ctx context.Context, url *url.URL, headers
 	for k, v := range headers {
 		req.Header[k] = v
 	}
+	if rs.Cfg.RendererAuthToken != "" {
+		// the token is tied to the page to render, so that it can't be replayed
+		// to render other pages
+		token, err := signRenderToken(rs.Cfg.RendererAuthToken, url.Query().Get("url"), time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign render token: %w", err)
+		}
+		req.Header.Set(RenderTokenHeader, token)
+	}
 
 	rs.log.Debug("calling remote rendering service", "url", url)
 
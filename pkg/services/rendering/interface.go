@@ -9,7 +9,9 @@ import (
 )
 
 var ErrTimeout = errors.New("timeout error - you can set timeout in seconds with &timeout url parameter")
-var ErrConcurrentLimitReached = errors.New("rendering concurrent limit reached")
+var ErrQueueFull = errors.New("rendering queue is full")
+var ErrRenderQuotaExceeded = errors.New("hourly render quota of the organization exceeded")
+var ErrImageSizeLimit = errors.New("image size exceeds the limit of the organization")
 var ErrRenderUnavailable = errors.New("rendering plugin not available")
 
 type RenderType string
@@ -17,6 +19,7 @@ type RenderType string
 const (
 	RenderCSV RenderType = "csv"
 	RenderPNG RenderType = "png"
+	RenderPDF RenderType = "pdf"
 )
 
 type Opts struct {
@@ -46,6 +49,19 @@ type CSVOpts struct {
 	Headers         map[string][]string
 }
 
+type PDFOpts struct {
+	Timeout         time.Duration
+	OrgID           int64
+	UserID          int64
+	OrgRole         models.RoleType
+	Path            string
+	Timezone        string
+	ConcurrentLimit int
+	Headers         map[string][]string
+	PaperSize       PaperSize
+	Landscape       bool
+}
+
 type RenderResult struct {
 	FilePath string
 }
@@ -63,6 +79,7 @@ type Service interface {
 	Version() string
 	Render(ctx context.Context, opts Opts) (*RenderResult, error)
 	RenderCSV(ctx context.Context, opts CSVOpts) (*RenderCSVResult, error)
+	RenderPDF(ctx context.Context, opts PDFOpts) (*RenderResult, error)
 	RenderErrorImage(error error) (*RenderResult, error)
 	GetRenderUser(key string) (*RenderUser, bool)
 }
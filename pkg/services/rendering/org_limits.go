@@ -0,0 +1,79 @@
+package rendering
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// applyOrgLimits checks the dimensions of an image to render against the limits
+// of the organization, and returns the timeout of the request reduced to the
+// maximum timeout of the organization. A negative height renders the full page,
+// which isn't limited.
+func (rs *RenderingService) applyOrgLimits(orgID int64, width, height int, timeout time.Duration) (time.Duration, error) {
+	limits := rs.Cfg.RenderingLimits(orgID)
+	if (limits.MaxWidth > 0 && width > limits.MaxWidth) || (limits.MaxHeight > 0 && height > limits.MaxHeight) {
+		return 0, fmt.Errorf("%w: %dx%d requested, the limit is %s", ErrImageSizeLimit, width, height, formatSizeLimit(limits.MaxWidth, limits.MaxHeight))
+	}
+	if limits.MaxTimeout > 0 && (timeout <= 0 || timeout > limits.MaxTimeout) {
+		timeout = limits.MaxTimeout
+	}
+	return timeout, nil
+}
+
+// allowRender counts a render of the organization in its hourly quota, and
+// returns ErrRenderQuotaExceeded when the organization has used it up.
+func (rs *RenderingService) allowRender(orgID int64) error {
+	limit := rs.Cfg.RenderingLimits(orgID).RendersPerHour
+	if limit <= 0 {
+		return nil
+	}
+	if !rs.quota.allow(orgID, limit, time.Now()) {
+		rs.log.Info("Render request rejected, the organization used up its hourly quota", "orgId", orgID, "limit", limit)
+		return ErrRenderQuotaExceeded
+	}
+	return nil
+}
+
+func formatSizeLimit(width, height int) string {
+	format := func(v int) string {
+		if v <= 0 {
+			return "any"
+		}
+		return fmt.Sprint(v)
+	}
+	return format(width) + "x" + format(height)
+}
+
+// renderQuota keeps track of the renders of the organizations in the last hour.
+// It's local to each Grafana server. The zero value is ready to use.
+type renderQuota struct {
+	mutex   sync.Mutex
+	renders map[int64][]time.Time
+}
+
+// allow counts a render of the organization at now, unless it has already
+// rendered limit images in the hour before.
+func (q *renderQuota) allow(orgID int64, limit int, now time.Time) bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.renders == nil {
+		q.renders = map[int64][]time.Time{}
+	}
+
+	renders := q.renders[orgID]
+	start := now.Add(-time.Hour)
+	i := 0
+	for i < len(renders) && !renders[i].After(start) {
+		i++
+	}
+	renders = renders[i:]
+
+	if len(renders) >= limit {
+		q.renders[orgID] = renders
+		return false
+	}
+	q.renders[orgID] = append(renders, now)
+	return true
+}
@@ -0,0 +1,80 @@
+package rendering
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrgLimits(t *testing.T) {
+	var timeouts []time.Duration
+	rs := &RenderingService{
+		Cfg:                setting.NewCfg(),
+		log:                log.New("test"),
+		RemoteCacheService: remotecache.NewFakeStore(t),
+		queue:              newRenderQueue(0, 0),
+	}
+	rs.Cfg.ImagesDir = t.TempDir()
+	rs.Cfg.RendererUrl = "http://localhost:8081/render"
+	rs.Cfg.RendererOrgLimits = setting.RenderingOrgLimits{RendersPerHour: 2, MaxWidth: 1000, MaxHeight: 500, MaxTimeout: 30 * time.Second}
+	rs.Cfg.RendererOrgLimitOverrides = map[int64]setting.RenderingOrgLimits{2: {}}
+	rs.renderAction = func(ctx context.Context, renderKey string, opts Opts) (*RenderResult, error) {
+		timeouts = append(timeouts, opts.Timeout)
+		return &RenderResult{FilePath: "image.png"}, nil
+	}
+
+	opts := Opts{Width: 1000, Height: 500, Timeout: time.Minute, OrgID: 1, ConcurrentLimit: 1, Path: "d-solo/abc/test?orgId=1&panelId=2"}
+
+	t.Run("Should reject images larger than the limits", func(t *testing.T) {
+		for _, size := range [][2]int{{1001, 500}, {1000, 501}} {
+			large := opts
+			large.Width, large.Height = size[0], size[1]
+			_, err := rs.Render(context.Background(), large)
+			require.True(t, errors.Is(err, ErrImageSizeLimit), "unexpected error %v", err)
+		}
+		assert.Empty(t, timeouts)
+	})
+
+	t.Run("Should reduce the timeout and reject renders over the hourly quota", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			_, err := rs.Render(context.Background(), opts)
+			require.NoError(t, err)
+		}
+		_, err := rs.Render(context.Background(), opts)
+		require.Equal(t, ErrRenderQuotaExceeded, err)
+		assert.Equal(t, []time.Duration{30 * time.Second, 30 * time.Second}, timeouts)
+	})
+
+	t.Run("Should apply the limits of the organization", func(t *testing.T) {
+		timeouts = nil
+		other := opts
+		other.OrgID = 2
+		other.Width = 4000
+		for i := 0; i < 3; i++ {
+			_, err := rs.Render(context.Background(), other)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, []time.Duration{time.Minute, time.Minute, time.Minute}, timeouts)
+	})
+}
+
+func TestRenderQuota(t *testing.T) {
+	var q renderQuota
+	now := time.Now()
+
+	require.True(t, q.allow(1, 2, now))
+	require.True(t, q.allow(1, 2, now.Add(10*time.Minute)))
+	require.False(t, q.allow(1, 2, now.Add(20*time.Minute)))
+	require.True(t, q.allow(2, 2, now.Add(20*time.Minute)))
+
+	// the first render leaves the window an hour later
+	require.True(t, q.allow(1, 2, now.Add(time.Hour)))
+	require.False(t, q.allow(1, 2, now.Add(time.Hour+time.Minute)))
+}
@@ -0,0 +1,303 @@
+package rendering
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"time"
+)
+
+// PaperSize is the paper size of the pages of PDF documents.
+type PaperSize string
+
+const (
+	PaperA3     PaperSize = "a3"
+	PaperA4     PaperSize = "a4"
+	PaperLetter PaperSize = "letter"
+	PaperLegal  PaperSize = "legal"
+)
+
+// paperSizes are the width and height of the paper sizes in points, in portrait.
+var paperSizes = map[PaperSize][2]float64{
+	PaperA3:     {842, 1191},
+	PaperA4:     {595, 842},
+	PaperLetter: {612, 792},
+	PaperLegal:  {612, 1008},
+}
+
+const (
+	// pdfMargin is the margin of the pages of PDF documents, in points.
+	pdfMargin = 36
+	// pdfPixelsPerPoint is the number of pixels of the rendered page that fit
+	// in a point of the PDF page.
+	pdfPixelsPerPoint = 2
+	// pdfMaxBreakSearch is the share of a page that is searched upwards from its
+	// end for a uniform row of the image to break the page at.
+	pdfMaxBreakSearch = 0.25
+)
+
+// ValidPaperSize returns whether the paper size is supported.
+func ValidPaperSize(size PaperSize) bool {
+	_, ok := paperSizes[size]
+	return ok
+}
+
+func (rs *RenderingService) RenderPDF(ctx context.Context, opts PDFOpts) (*RenderResult, error) {
+	startTime := time.Now()
+	result, err := rs.renderPDF(ctx, opts)
+
+	elapsedTime := time.Since(startTime).Milliseconds()
+	saveMetrics(elapsedTime, err, RenderPDF)
+
+	return result, err
+}
+
+// renderPDF renders the full page as a PNG image, as wide as the printable area
+// of the paper, and splits it into the pages of a PDF document.
+func (rs *RenderingService) renderPDF(ctx context.Context, opts PDFOpts) (*RenderResult, error) {
+	if !rs.IsAvailable() {
+		return nil, ErrRenderUnavailable
+	}
+	size, ok := paperSizes[opts.PaperSize]
+	if !ok {
+		return nil, fmt.Errorf("unsupported paper size %q", opts.PaperSize)
+	}
+	pageWidth, pageHeight := size[0], size[1]
+	if opts.Landscape {
+		pageWidth, pageHeight = pageHeight, pageWidth
+	}
+
+	result, err := rs.render(ctx, Opts{
+		Width:             int(pageWidth-2*pdfMargin) * pdfPixelsPerPoint,
+		Height:            -1,
+		Timeout:           opts.Timeout,
+		OrgID:             opts.OrgID,
+		UserID:            opts.UserID,
+		OrgRole:           opts.OrgRole,
+		Path:              opts.Path,
+		Timezone:          opts.Timezone,
+		ConcurrentLimit:   opts.ConcurrentLimit,
+		DeviceScaleFactor: 1,
+		Headers:           opts.Headers,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := os.Remove(result.FilePath); err != nil {
+			rs.log.Warn("Failed to remove rendered image", "path", result.FilePath, "err", err)
+		}
+	}()
+
+	img, err := readPNG(result.FilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	filePath, err := rs.getNewFilePath(RenderPDF)
+	if err != nil {
+		return nil, err
+	}
+	out, err := os.Create(filePath)
+	if err != nil {
+		return nil, err
+	}
+	w := bufio.NewWriter(out)
+	err = writePDF(w, img, pageWidth, pageHeight)
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write PDF document: %w", err)
+	}
+
+	return &RenderResult{FilePath: filePath}, nil
+}
+
+func readPNG(path string) (image.Image, error) {
+	// nolint:gosec
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		// the file is only read, so the error is of no interest
+		_ = f.Close()
+	}()
+
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode rendered image: %w", err)
+	}
+	return img, nil
+}
+
+// writePDF writes a PDF document of the image scaled to the printable width of
+// pages of the given size in points, split into as many pages as needed.
+func writePDF(w io.Writer, img image.Image, pageWidth, pageHeight float64) error {
+	bounds := img.Bounds()
+	scale := (pageWidth - 2*pdfMargin) / float64(bounds.Dx())
+	pageRows := int((pageHeight - 2*pdfMargin) / scale)
+	if pageRows < 1 {
+		pageRows = 1
+	}
+
+	var slices []image.Rectangle
+	for y := bounds.Min.Y; y < bounds.Max.Y; {
+		end := y + pageRows
+		if end >= bounds.Max.Y {
+			end = bounds.Max.Y
+		} else {
+			end = pageBreak(img, y, end)
+		}
+		slices = append(slices, image.Rect(bounds.Min.X, y, bounds.Max.X, end))
+		y = end
+	}
+
+	pdf := &pdfWriter{w: w}
+	pdf.printf("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+
+	// the catalog and the page tree are objects 1 and 2, and each page is
+	// followed by its content and image objects
+	pageIDs := make([]int, len(slices))
+	for i := range slices {
+		pageIDs[i] = 3 + 3*i
+	}
+	pdf.object(1, []byte("<< /Type /Catalog /Pages 2 0 R >>"))
+	kids := &bytes.Buffer{}
+	for _, id := range pageIDs {
+		fmt.Fprintf(kids, "%d 0 R ", id)
+	}
+	pdf.object(2, []byte(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", kids.String(), len(slices))))
+
+	for i, r := range slices {
+		pageID := pageIDs[i]
+		height := float64(r.Dy()) * scale
+		content := fmt.Sprintf("q %.2f 0 0 %.2f %d %.2f cm /Im0 Do Q",
+			pageWidth-2*pdfMargin, height, pdfMargin, pageHeight-pdfMargin-height)
+
+		pdf.object(pageID, []byte(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Contents %d 0 R /Resources << /XObject << /Im0 %d 0 R >> >> >>",
+			pageWidth, pageHeight, pageID+1, pageID+2)))
+		pdf.stream(pageID+1, "", []byte(content))
+
+		pixels, err := deflateRGB(img, r)
+		if err != nil {
+			return err
+		}
+		pdf.stream(pageID+2, fmt.Sprintf(
+			"/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode ",
+			r.Dx(), r.Dy()), pixels)
+	}
+
+	pdf.trailer(1)
+	return pdf.err
+}
+
+// pageBreak returns the row to end the page that starts at row start at, which
+// is the last uniform row within the end of the page so that panels aren't
+// split across pages when possible, or end.
+func pageBreak(img image.Image, start, end int) int {
+	limit := end - int(float64(end-start)*pdfMaxBreakSearch)
+	for y := end; y > limit; y-- {
+		if uniformRow(img, y) {
+			return y
+		}
+	}
+	return end
+}
+
+func uniformRow(img image.Image, y int) bool {
+	bounds := img.Bounds()
+	first := color.RGBAModel.Convert(img.At(bounds.Min.X, y))
+	for x := bounds.Min.X + 1; x < bounds.Max.X; x++ {
+		if color.RGBAModel.Convert(img.At(x, y)) != first {
+			return false
+		}
+	}
+	return true
+}
+
+// deflateRGB returns the RGB pixels of an area of the image, compressed with
+// deflate.
+func deflateRGB(img image.Image, r image.Rectangle) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	row := make([]byte, 0, 3*r.Dx())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		row = row[:0]
+		for x := r.Min.X; x < r.Max.X; x++ {
+			c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+			row = append(row, c.R, c.G, c.B)
+		}
+		if _, err := zw.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// pdfWriter writes the objects of a PDF document, and keeps track of their
+// offsets for the cross-reference table. Its first error is kept in err.
+type pdfWriter struct {
+	w       io.Writer
+	offset  int
+	offsets map[int]int
+	err     error
+}
+
+func (p *pdfWriter) printf(format string, args ...interface{}) {
+	p.write([]byte(fmt.Sprintf(format, args...)))
+}
+
+func (p *pdfWriter) write(b []byte) {
+	if p.err != nil {
+		return
+	}
+	n, err := p.w.Write(b)
+	p.offset += n
+	p.err = err
+}
+
+func (p *pdfWriter) object(id int, body []byte) {
+	if p.offsets == nil {
+		p.offsets = map[int]int{}
+	}
+	p.offsets[id] = p.offset
+	p.printf("%d 0 obj\n", id)
+	p.write(body)
+	p.printf("\nendobj\n")
+}
+
+// stream writes a stream object, with the entries of its dictionary besides
+// its length.
+func (p *pdfWriter) stream(id int, entries string, data []byte) {
+	body := &bytes.Buffer{}
+	fmt.Fprintf(body, "<< %s/Length %d >>\nstream\n", entries, len(data))
+	body.Write(data)
+	body.WriteString("\nendstream")
+	p.object(id, body.Bytes())
+}
+
+func (p *pdfWriter) trailer(rootID int) {
+	count := len(p.offsets) + 1
+	xref := p.offset
+	p.printf("xref\n0 %d\n0000000000 65535 f \n", count)
+	for id := 1; id < count; id++ {
+		p.printf("%010d 00000 n \n", p.offsets[id])
+	}
+	p.printf("trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", count, rootID, xref)
+}
@@ -0,0 +1,80 @@
+package rendering
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWritePDF(t *testing.T) {
+	// an image of panels of 170 rows separated by 10 uniform rows
+	img := image.NewRGBA(image.Rect(0, 0, 100, 900))
+	for y := 0; y < 900; y++ {
+		for x := 0; x < 100; x++ {
+			c := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+			if y%180 < 170 && x%2 == 0 {
+				c = color.RGBA{A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+
+	// the printable area is 200x400 points, which fits 200 rows of the image
+	var buf bytes.Buffer
+	require.NoError(t, writePDF(&buf, img, 200+2*pdfMargin, 400+2*pdfMargin))
+	pdf := buf.Bytes()
+	require.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4")))
+
+	// the pages break at the last uniform row between the panels
+	var heights []string
+	for _, m := range regexp.MustCompile(`/Height (\d+) `).FindAllSubmatch(pdf, -1) {
+		heights = append(heights, string(m[1]))
+	}
+	assert.Equal(t, []string{"179", "180", "180", "180", "181"}, heights)
+	assert.Equal(t, 5, bytes.Count(pdf, []byte("/Type /Page ")))
+
+	t.Run("Should reference the objects at their offsets", func(t *testing.T) {
+		m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+		require.NotNil(t, m)
+		xref, err := strconv.Atoi(string(m[1]))
+		require.NoError(t, err)
+		require.True(t, bytes.HasPrefix(pdf[xref:], []byte("xref\n")))
+
+		entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(pdf[xref:], -1)
+		require.Len(t, entries, 2+3*5)
+		for i, entry := range entries {
+			offset, err := strconv.Atoi(string(entry[1]))
+			require.NoError(t, err)
+			assert.True(t, bytes.HasPrefix(pdf[offset:], []byte(fmt.Sprintf("%d 0 obj\n", i+1))), "object %d", i+1)
+		}
+	})
+}
+
+func TestPageBreak(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 10, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 10; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), A: 255})
+		}
+	}
+
+	t.Run("Should break at the end of the page without uniform rows", func(t *testing.T) {
+		assert.Equal(t, 80, pageBreak(img, 0, 80))
+	})
+
+	t.Run("Should break at the last uniform row", func(t *testing.T) {
+		for x := 0; x < 10; x++ {
+			img.Set(x, 70, color.RGBA{A: 255})
+		}
+		assert.Equal(t, 70, pageBreak(img, 0, 80))
+		// the uniform row is too far from the end of the page
+		assert.Equal(t, 100, pageBreak(img, 60, 100))
+	})
+}
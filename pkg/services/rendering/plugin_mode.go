@@ -10,7 +10,16 @@ import (
 )
 
 func (rs *RenderingService) startPlugin(ctx context.Context) error {
-	return rs.pluginInfo.Start(ctx)
+	instances := rs.Cfg.RendererPluginInstances
+	if instances < 1 {
+		instances = 1
+	}
+	if err := rs.pluginInfo.StartInstances(ctx, instances); err != nil {
+		return err
+	}
+
+	rs.pluginPool = newRendererPool(rs.pluginInfo.Instance, instances, rs.Cfg.RendererPluginInstanceConcurrencyLimit)
+	return nil
 }
 
 func (rs *RenderingService) renderViaPlugin(ctx context.Context, renderKey string, opts Opts) (*RenderResult, error) {
@@ -45,7 +54,14 @@ func (rs *RenderingService) renderViaPlugin(ctx context.Context, renderKey strin
 	}
 	rs.log.Debug("Calling renderer plugin", "req", req)
 
-	rsp, err := rs.pluginInfo.GrpcPluginV2.Render(ctx, req)
+	renderer, release, err := rs.pluginPool.acquire(ctx)
+	if err != nil {
+		rs.log.Info("Rendering timed out waiting for a renderer plugin instance")
+		return nil, ErrTimeout
+	}
+	defer release()
+
+	rsp, err := renderer.Render(ctx, req)
 	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 		rs.log.Info("Rendering timed out")
 		return nil, ErrTimeout
@@ -88,7 +104,14 @@ func (rs *RenderingService) renderCSVViaPlugin(ctx context.Context, renderKey st
 	}
 	rs.log.Debug("Calling renderer plugin", "req", req)
 
-	rsp, err := rs.pluginInfo.GrpcPluginV2.RenderCSV(ctx, req)
+	renderer, release, err := rs.pluginPool.acquire(ctx)
+	if err != nil {
+		rs.log.Info("Rendering timed out waiting for a renderer plugin instance")
+		return nil, ErrTimeout
+	}
+	defer release()
+
+	rsp, err := renderer.RenderCSV(ctx, req)
 	if err != nil {
 		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 			rs.log.Info("Rendering timed out")
@@ -0,0 +1,78 @@
+package rendering
+
+import (
+	"context"
+	"sync"
+
+	"github.com/grafana/grafana/pkg/plugins/backendplugin/pluginextensionv2"
+)
+
+// rendererPool dispatches render requests to the instances of the renderer
+// plugin in round-robin order, with at most maxConcurrency requests in progress
+// per instance when it's positive.
+type rendererPool struct {
+	instance       func(i int) pluginextensionv2.RendererPlugin
+	maxConcurrency int
+	// slots limits the requests in progress to the capacity of the pool,
+	// and is nil when the instances have no concurrency limit.
+	slots chan struct{}
+
+	mu       sync.Mutex
+	inFlight []int
+	next     int
+}
+
+func newRendererPool(instance func(i int) pluginextensionv2.RendererPlugin, size, maxConcurrency int) *rendererPool {
+	p := &rendererPool{
+		instance:       instance,
+		maxConcurrency: maxConcurrency,
+		inFlight:       make([]int, size),
+	}
+	if maxConcurrency > 0 {
+		p.slots = make(chan struct{}, size*maxConcurrency)
+	}
+	return p
+}
+
+// acquire returns the next instance with a free slot, waiting for one when
+// all the instances are busy, and the function that releases its slot.
+func (p *rendererPool) acquire(ctx context.Context) (pluginextensionv2.RendererPlugin, func(), error) {
+	if p.slots != nil {
+		select {
+		case p.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+
+	p.mu.Lock()
+	i := p.pick()
+	p.inFlight[i]++
+	p.mu.Unlock()
+
+	release := func() {
+		p.mu.Lock()
+		p.inFlight[i]--
+		p.mu.Unlock()
+		if p.slots != nil {
+			<-p.slots
+		}
+	}
+
+	return p.instance(i), release, nil
+}
+
+// pick returns the next instance in round-robin order that has a free slot,
+// which exists since the slots of the pool were acquired.
+func (p *rendererPool) pick() int {
+	i := p.next
+	for n := 0; n < len(p.inFlight); n++ {
+		candidate := (p.next + n) % len(p.inFlight)
+		if p.maxConcurrency <= 0 || p.inFlight[candidate] < p.maxConcurrency {
+			i = candidate
+			break
+		}
+	}
+	p.next = (i + 1) % len(p.inFlight)
+	return i
+}
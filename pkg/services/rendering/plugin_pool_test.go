@@ -0,0 +1,64 @@
+package rendering
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/plugins/backendplugin/pluginextensionv2"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRenderer struct {
+	pluginextensionv2.RendererPlugin
+	id int
+}
+
+func TestRendererPool(t *testing.T) {
+	instance := func(i int) pluginextensionv2.RendererPlugin {
+		return &fakeRenderer{id: i}
+	}
+
+	acquire := func(t *testing.T, ctx context.Context, pool *rendererPool) (int, func()) {
+		t.Helper()
+		renderer, release, err := pool.acquire(ctx)
+		require.NoError(t, err)
+		return renderer.(*fakeRenderer).id, release
+	}
+
+	t.Run("Should dispatch requests in round-robin order", func(t *testing.T) {
+		pool := newRendererPool(instance, 3, 0)
+		var ids []int
+		for i := 0; i < 4; i++ {
+			id, release := acquire(t, context.Background(), pool)
+			ids = append(ids, id)
+			release()
+		}
+		require.Equal(t, []int{0, 1, 2, 0}, ids)
+	})
+
+	t.Run("Should skip instances at their concurrency limit", func(t *testing.T) {
+		pool := newRendererPool(instance, 2, 1)
+		id, releaseFirst := acquire(t, context.Background(), pool)
+		require.Equal(t, 0, id)
+		id, releaseSecond := acquire(t, context.Background(), pool)
+		require.Equal(t, 1, id)
+
+		releaseFirst()
+		id, releaseThird := acquire(t, context.Background(), pool)
+		require.Equal(t, 0, id)
+
+		t.Run("Should wait for a free instance", func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			_, _, err := pool.acquire(ctx)
+			require.ErrorIs(t, err, context.DeadlineExceeded)
+
+			releaseSecond()
+			id, release := acquire(t, context.Background(), pool)
+			require.Equal(t, 1, id)
+			release()
+			releaseThird()
+		})
+	})
+}
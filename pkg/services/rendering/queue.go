@@ -0,0 +1,114 @@
+package rendering
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/metrics"
+)
+
+// renderQueue limits the number of concurrent render requests, overall and per
+// organization. The requests over the limits wait in the queue for a free slot,
+// up to maxQueued requests, and the further requests are rejected.
+type renderQueue struct {
+	maxQueued        int
+	orgConcurrentMax int
+
+	mutex      sync.Mutex
+	inProgress int
+	orgs       map[int64]int
+	queued     int
+	// released is closed and replaced whenever a request is released, which
+	// wakes the queued requests up.
+	released chan struct{}
+}
+
+func newRenderQueue(maxQueued, orgConcurrentMax int) *renderQueue {
+	return &renderQueue{
+		maxQueued:        maxQueued,
+		orgConcurrentMax: orgConcurrentMax,
+		orgs:             map[int64]int{},
+		released:         make(chan struct{}),
+	}
+}
+
+// acquire waits until a request of the organization can start with at most
+// concurrentMax requests in progress, and returns the function that releases it.
+// It returns ErrQueueFull when the queue is full, and ErrTimeout when ctx is
+// done before the request starts.
+func (q *renderQueue) acquire(ctx context.Context, orgID int64, concurrentMax int) (func(), error) {
+	if concurrentMax < 1 {
+		concurrentMax = 1
+	}
+
+	start := time.Now()
+	queued := false
+	defer func() {
+		if queued {
+			metrics.MRenderingQueueWaitSummary.Observe(float64(time.Since(start).Milliseconds()))
+		}
+	}()
+
+	q.mutex.Lock()
+	for !q.canStart(orgID, concurrentMax) {
+		if !queued {
+			if q.queued >= q.maxQueued {
+				q.mutex.Unlock()
+				return nil, ErrQueueFull
+			}
+			queued = true
+			q.setQueued(q.queued + 1)
+		}
+
+		released := q.released
+		q.mutex.Unlock()
+		select {
+		case <-released:
+		case <-ctx.Done():
+			q.mutex.Lock()
+			q.setQueued(q.queued - 1)
+			q.mutex.Unlock()
+			return nil, ErrTimeout
+		}
+		q.mutex.Lock()
+	}
+
+	if queued {
+		q.setQueued(q.queued - 1)
+	}
+	q.inProgress++
+	q.orgs[orgID]++
+	metrics.MRenderingQueue.Set(float64(q.inProgress))
+	q.mutex.Unlock()
+
+	var once sync.Once
+	return func() { once.Do(func() { q.release(orgID) }) }, nil
+}
+
+func (q *renderQueue) canStart(orgID int64, concurrentMax int) bool {
+	if q.inProgress >= concurrentMax {
+		return false
+	}
+	return q.orgConcurrentMax <= 0 || q.orgs[orgID] < q.orgConcurrentMax
+}
+
+func (q *renderQueue) release(orgID int64) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.inProgress--
+	q.orgs[orgID]--
+	if q.orgs[orgID] == 0 {
+		delete(q.orgs, orgID)
+	}
+	metrics.MRenderingQueue.Set(float64(q.inProgress))
+
+	close(q.released)
+	q.released = make(chan struct{})
+}
+
+func (q *renderQueue) setQueued(queued int) {
+	q.queued = queued
+	metrics.MRenderingQueueWaiting.Set(float64(queued))
+}
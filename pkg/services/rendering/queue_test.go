@@ -0,0 +1,98 @@
+package rendering
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderQueue(t *testing.T) {
+	t.Run("Should start a queued request when a request is released", func(t *testing.T) {
+		q := newRenderQueue(1, 0)
+		release, err := q.acquire(context.Background(), 1, 1)
+		require.NoError(t, err)
+
+		started := make(chan error)
+		go func() {
+			release, err := q.acquire(context.Background(), 2, 1)
+			if err == nil {
+				release()
+			}
+			started <- err
+		}()
+
+		waitForQueued(t, q, 1)
+		release()
+		require.NoError(t, <-started)
+		assert.Equal(t, 0, q.queued)
+		assert.Equal(t, 0, q.inProgress)
+	})
+
+	t.Run("Should reject requests when the queue is full", func(t *testing.T) {
+		q := newRenderQueue(0, 0)
+		release, err := q.acquire(context.Background(), 1, 1)
+		require.NoError(t, err)
+		defer release()
+
+		_, err = q.acquire(context.Background(), 1, 1)
+		require.Equal(t, ErrQueueFull, err)
+	})
+
+	t.Run("Should time out requests waiting in the queue", func(t *testing.T) {
+		q := newRenderQueue(1, 0)
+		release, err := q.acquire(context.Background(), 1, 1)
+		require.NoError(t, err)
+		defer release()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err = q.acquire(ctx, 1, 1)
+		require.Equal(t, ErrTimeout, err)
+		assert.Equal(t, 0, q.queued)
+	})
+
+	t.Run("Should limit the concurrent requests of an organization", func(t *testing.T) {
+		q := newRenderQueue(0, 1)
+		release, err := q.acquire(context.Background(), 1, 10)
+		require.NoError(t, err)
+
+		_, err = q.acquire(context.Background(), 1, 10)
+		require.Equal(t, ErrQueueFull, err)
+
+		releaseOther, err := q.acquire(context.Background(), 2, 10)
+		require.NoError(t, err)
+		releaseOther()
+
+		release()
+		release, err = q.acquire(context.Background(), 1, 10)
+		require.NoError(t, err)
+		release()
+	})
+
+	t.Run("Should let requests with a higher limit start while others wait", func(t *testing.T) {
+		q := newRenderQueue(0, 0)
+		release, err := q.acquire(context.Background(), 1, 1)
+		require.NoError(t, err)
+		defer release()
+
+		_, err = q.acquire(context.Background(), 1, 1)
+		require.Equal(t, ErrQueueFull, err)
+
+		releaseOther, err := q.acquire(context.Background(), 1, 2)
+		require.NoError(t, err)
+		releaseOther()
+	})
+}
+
+func waitForQueued(t *testing.T, q *renderQueue, queued int) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		q.mutex.Lock()
+		defer q.mutex.Unlock()
+		return q.queued == queued
+	}, time.Second, time.Millisecond)
+}
@@ -44,10 +44,13 @@ type RenderUser struct {
 type RenderingService struct {
 	log             log.Logger
 	pluginInfo      *plugins.RendererPlugin
+	pluginPool      *rendererPool
+	browser         *browser
 	renderAction    renderFunc
 	renderCSVAction renderCSVFunc
 	domain          string
-	inProgressCount int
+	queue           *renderQueue
+	quota           renderQuota
 	version         string
 
 	Cfg                *setting.Cfg             `inject:""`
@@ -70,6 +73,8 @@ func (rs *RenderingService) Init() error {
 		return fmt.Errorf("failed to create CSVs directory %q: %w", rs.Cfg.CSVsDir, err)
 	}
 
+	rs.queue = newRenderQueue(rs.Cfg.RendererQueuedRequestLimit, rs.Cfg.RendererOrgConcurrentRequestLimit)
+
 	// set value used for domain attribute of renderKey cookie
 	switch {
 	case rs.Cfg.RendererUrl != "":
@@ -128,6 +133,13 @@ func (rs *RenderingService) Run(ctx context.Context) error {
 		return nil
 	}
 
+	if rs.browserAvailable() {
+		rs.log = rs.log.New("renderer", "browser")
+		rs.renderAction = rs.renderViaBrowser
+		rs.renderCSVAction = rs.renderCSVViaBrowser
+		return rs.runBrowser(ctx)
+	}
+
 	rs.log.Debug("No image renderer found/installed. " +
 		"For image rendering support please install the grafana-image-renderer plugin. " +
 		"Read more at https://grafana.com/docs/grafana/latest/administration/image_rendering/")
@@ -145,7 +157,7 @@ func (rs *RenderingService) remoteAvailable() bool {
 }
 
 func (rs *RenderingService) IsAvailable() bool {
-	return rs.remoteAvailable() || rs.pluginAvailable()
+	return rs.remoteAvailable() || rs.pluginAvailable() || rs.browserAvailable()
 }
 
 func (rs *RenderingService) Version() string {
@@ -179,12 +191,6 @@ func (rs *RenderingService) Render(ctx context.Context, opts Opts) (*RenderResul
 }
 
 func (rs *RenderingService) render(ctx context.Context, opts Opts) (*RenderResult, error) {
-	if rs.inProgressCount > opts.ConcurrentLimit {
-		return &RenderResult{
-			FilePath: filepath.Join(setting.HomePath, "public/img/rendering_limit.png"),
-		}, nil
-	}
-
 	if !rs.IsAvailable() {
 		rs.log.Warn("Could not render image, no image renderer found/installed. " +
 			"For image rendering support please install the grafana-image-renderer plugin. " +
@@ -192,10 +198,29 @@ func (rs *RenderingService) render(ctx context.Context, opts Opts) (*RenderResul
 		return rs.renderUnavailableImage(), nil
 	}
 
-	rs.log.Info("Rendering", "path", opts.Path)
 	if math.IsInf(opts.DeviceScaleFactor, 0) || math.IsNaN(opts.DeviceScaleFactor) || opts.DeviceScaleFactor <= 0 {
 		opts.DeviceScaleFactor = 1
 	}
+	timeout, err := rs.applyOrgLimits(opts.OrgID, opts.Width, opts.Height, opts.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	opts.Timeout = timeout
+
+	if result, ok := rs.cachedRender(opts); ok {
+		return result, nil
+	}
+	if err := rs.allowRender(opts.OrgID); err != nil {
+		return nil, err
+	}
+
+	release, err := rs.acquire(ctx, opts.OrgID, opts.ConcurrentLimit, opts.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	rs.log.Info("Rendering", "path", opts.Path)
 	renderKey, err := rs.generateAndStoreRenderKey(opts.OrgID, opts.UserID, opts.OrgRole)
 	if err != nil {
 		return nil, err
@@ -203,14 +228,12 @@ func (rs *RenderingService) render(ctx context.Context, opts Opts) (*RenderResul
 
 	defer rs.deleteRenderKey(renderKey)
 
-	defer func() {
-		rs.inProgressCount--
-		metrics.MRenderingQueue.Set(float64(rs.inProgressCount))
-	}()
-
-	rs.inProgressCount++
-	metrics.MRenderingQueue.Set(float64(rs.inProgressCount))
-	return rs.renderAction(ctx, renderKey, opts)
+	result, err := rs.renderAction(ctx, renderKey, opts)
+	if err != nil {
+		return nil, err
+	}
+	rs.cacheRender(opts, result)
+	return result, nil
 }
 
 func (rs *RenderingService) RenderCSV(ctx context.Context, opts CSVOpts) (*RenderCSVResult, error) {
@@ -224,14 +247,25 @@ func (rs *RenderingService) RenderCSV(ctx context.Context, opts CSVOpts) (*Rende
 }
 
 func (rs *RenderingService) renderCSV(ctx context.Context, opts CSVOpts) (*RenderCSVResult, error) {
-	if rs.inProgressCount > opts.ConcurrentLimit {
-		return nil, ErrConcurrentLimitReached
-	}
-
 	if !rs.IsAvailable() {
 		return nil, ErrRenderUnavailable
 	}
 
+	timeout, err := rs.applyOrgLimits(opts.OrgID, 0, 0, opts.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	opts.Timeout = timeout
+	if err := rs.allowRender(opts.OrgID); err != nil {
+		return nil, err
+	}
+
+	release, err := rs.acquire(ctx, opts.OrgID, opts.ConcurrentLimit, opts.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	rs.log.Info("Rendering", "path", opts.Path)
 	renderKey, err := rs.generateAndStoreRenderKey(opts.OrgID, opts.UserID, opts.OrgRole)
 	if err != nil {
@@ -240,16 +274,23 @@ func (rs *RenderingService) renderCSV(ctx context.Context, opts CSVOpts) (*Rende
 
 	defer rs.deleteRenderKey(renderKey)
 
-	defer func() {
-		rs.inProgressCount--
-		metrics.MRenderingQueue.Set(float64(rs.inProgressCount))
-	}()
-
-	rs.inProgressCount++
-	metrics.MRenderingQueue.Set(float64(rs.inProgressCount))
 	return rs.renderCSVAction(ctx, renderKey, opts)
 }
 
+// acquire waits in the render queue until a render request of the organization
+// can start, for at most the timeout of the request.
+func (rs *RenderingService) acquire(ctx context.Context, orgID int64, concurrentLimit int, timeout time.Duration) (func(), error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	release, err := rs.queue.acquire(ctx, orgID, concurrentLimit)
+	if err != nil {
+		rs.log.Info("Render request not started", "orgId", orgID, "err", err)
+		return nil, err
+	}
+	return release, nil
+}
+
 func (rs *RenderingService) GetRenderUser(key string) (*RenderUser, bool) {
 	val, err := rs.RemoteCacheService.Get(fmt.Sprintf(renderKeyPrefix, key))
 	if err != nil {
@@ -271,10 +312,9 @@ func (rs *RenderingService) getNewFilePath(rt RenderType) (string, error) {
 		return "", err
 	}
 
-	ext := "png"
+	ext := string(rt)
 	folder := rs.Cfg.ImagesDir
 	if rt == RenderCSV {
-		ext = "csv"
 		folder = rs.Cfg.CSVsDir
 	}
 
@@ -353,7 +393,10 @@ func saveMetrics(elapsedTime int64, err error, renderType RenderType) {
 		return
 	}
 
-	if errors.Is(err, ErrTimeout) {
+	if errors.Is(err, ErrQueueFull) || errors.Is(err, ErrRenderQuotaExceeded) || errors.Is(err, ErrImageSizeLimit) {
+		metrics.MRenderingRequestTotal.WithLabelValues("rejected", string(renderType)).Inc()
+		metrics.MRenderingSummary.WithLabelValues("rejected", string(renderType)).Observe(float64(elapsedTime))
+	} else if errors.Is(err, ErrTimeout) {
 		metrics.MRenderingRequestTotal.WithLabelValues("timeout", string(renderType)).Inc()
 		metrics.MRenderingSummary.WithLabelValues("timeout", string(renderType)).Observe(float64(elapsedTime))
 	} else {
@@ -0,0 +1,312 @@
+// Package reports renders dashboards to PDF documents or PNG images on a cron
+// schedule and emails them to the recipients of the reports.
+package reports
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/registry"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/robfig/cron/v3"
+)
+
+// Formats of the reports
+const (
+	FormatPDF = "pdf"
+	FormatPNG = "png"
+)
+
+// checkInterval is how often the reports due are looked for.
+const checkInterval = 30 * time.Second
+
+var (
+	ErrDisabled       = errors.New("reports are disabled")
+	ErrReportNotFound = errors.New("report not found")
+	// ErrInvalidReport is wrapped by the errors of invalid report definitions.
+	ErrInvalidReport = errors.New("invalid report")
+)
+
+var getTime = time.Now
+
+func init() {
+	registry.RegisterService(&Service{})
+}
+
+// Recipients are the email addresses of the recipients of a report, stored as
+// a JSON array.
+type Recipients []string
+
+// FromDB is part of the xorm Conversion interface.
+func (r *Recipients) FromDB(data []byte) error {
+	return json.Unmarshal(data, r)
+}
+
+// ToDB is part of the xorm Conversion interface.
+func (r *Recipients) ToDB() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// Report is the definition of a report, which renders a dashboard as its
+// creator and emails it to its recipients on its schedule.
+type Report struct {
+	Id           int64      `json:"id"`
+	OrgId        int64      `json:"orgId"`
+	Name         string     `json:"name"`
+	DashboardUid string     `json:"dashboardUid"`
+	Schedule     string     `json:"schedule"`
+	Timezone     string     `json:"timezone"`
+	Recipients   Recipients `json:"recipients"`
+	Format       string     `json:"format"`
+	PaperSize    string     `json:"paperSize"`
+	Orientation  string     `json:"orientation"`
+	TimeFrom     string     `json:"timeFrom"`
+	TimeTo       string     `json:"timeTo"`
+	Message      string     `json:"message"`
+	Enabled      bool       `json:"enabled"`
+	CreatedBy    int64      `json:"createdBy"`
+	Created      time.Time  `json:"created"`
+	Updated      time.Time  `json:"updated"`
+	NextRun      time.Time  `json:"nextRun"`
+	LastRun      *time.Time `json:"lastRun,omitempty"`
+	LastError    string     `json:"lastError,omitempty"`
+}
+
+// SaveReportCommand is the definition of a report to create or update.
+type SaveReportCommand struct {
+	Name         string   `json:"name"`
+	DashboardUID string   `json:"dashboardUid"`
+	Schedule     string   `json:"schedule"`
+	Timezone     string   `json:"timezone"`
+	Recipients   []string `json:"recipients"`
+	Format       string   `json:"format"`
+	PaperSize    string   `json:"paperSize"`
+	Orientation  string   `json:"orientation"`
+	TimeFrom     string   `json:"timeFrom"`
+	TimeTo       string   `json:"timeTo"`
+	Message      string   `json:"message"`
+	// Enabled defaults to true.
+	Enabled *bool `json:"enabled"`
+}
+
+type Service struct {
+	Cfg           *setting.Cfg       `inject:""`
+	SQLStore      *sqlstore.SQLStore `inject:""`
+	RenderService rendering.Service  `inject:""`
+
+	log log.Logger
+}
+
+func (s *Service) Init() error {
+	s.log = log.New("reports")
+	return nil
+}
+
+// IsDisabled returns true if the reports are turned off.
+func (s *Service) IsDisabled() bool {
+	return !s.Cfg.ReportsEnabled
+}
+
+// Run sends the reports when they are due until the context is canceled.
+func (s *Service) Run(ctx context.Context) error {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.sendDue(ctx); err != nil {
+				s.log.Error("Failed to send the reports due", "error", err)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// GetReports returns the reports of the organization, sorted by name.
+func (s *Service) GetReports(ctx context.Context, orgID int64) ([]*Report, error) {
+	reports := []*Report{}
+	err := s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		return sess.Where("org_id = ?", orgID).Asc("name").Find(&reports)
+	})
+	return reports, err
+}
+
+// GetReport returns a report of the organization.
+func (s *Service) GetReport(ctx context.Context, orgID, id int64) (*Report, error) {
+	var report Report
+	err := s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		exists, err := sess.Where("org_id = ? AND id = ?", orgID, id).Get(&report)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrReportNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// CreateReport creates a report of the organization of the user, who the
+// dashboard is rendered as.
+func (s *Service) CreateReport(ctx context.Context, user *models.SignedInUser, cmd SaveReportCommand) (*Report, error) {
+	now := getTime()
+	report := &Report{
+		OrgId:     user.OrgId,
+		CreatedBy: user.UserId,
+		Created:   now,
+	}
+	if err := s.apply(report, cmd, now); err != nil {
+		return nil, err
+	}
+
+	err := s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		_, err := sess.Insert(report)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// UpdateReport updates a report of the organization of the user, who the
+// dashboard is then rendered as.
+func (s *Service) UpdateReport(ctx context.Context, user *models.SignedInUser, id int64, cmd SaveReportCommand) (*Report, error) {
+	report, err := s.GetReport(ctx, user.OrgId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	report.CreatedBy = user.UserId
+	if err := s.apply(report, cmd, getTime()); err != nil {
+		return nil, err
+	}
+
+	err = s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		_, err := sess.ID(report.Id).AllCols().Update(report)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// DeleteReport deletes a report of the organization.
+func (s *Service) DeleteReport(ctx context.Context, orgID, id int64) error {
+	return s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		affected, err := sess.Where("org_id = ? AND id = ?", orgID, id).Delete(&Report{})
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return ErrReportNotFound
+		}
+		return nil
+	})
+}
+
+// apply validates the definition of the report and sets it.
+func (s *Service) apply(report *Report, cmd SaveReportCommand, now time.Time) error {
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s", ErrInvalidReport, fmt.Sprintf(format, args...))
+	}
+
+	if strings.TrimSpace(cmd.Name) == "" {
+		return invalid("name is required")
+	}
+	if cmd.DashboardUID == "" {
+		return invalid("dashboardUid is required")
+	}
+	if cmd.Timezone == "" {
+		cmd.Timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(cmd.Timezone)
+	if err != nil {
+		return invalid("unknown timezone %q", cmd.Timezone)
+	}
+	schedule, err := cron.ParseStandard(cmd.Schedule)
+	if err != nil {
+		return invalid("schedule must be a cron expression, such as 0 8 * * 1: %s", err)
+	}
+
+	if len(cmd.Recipients) == 0 {
+		return invalid("at least one recipient is required")
+	}
+	if len(cmd.Recipients) > s.Cfg.ReportsMaxRecipients {
+		return invalid("a report can have at most %d recipients", s.Cfg.ReportsMaxRecipients)
+	}
+	recipients := make(Recipients, len(cmd.Recipients))
+	for i, recipient := range cmd.Recipients {
+		address, err := mail.ParseAddress(recipient)
+		if err != nil {
+			return invalid("invalid recipient %q", recipient)
+		}
+		recipients[i] = address.Address
+	}
+
+	switch cmd.Format {
+	case "":
+		cmd.Format = FormatPDF
+	case FormatPDF, FormatPNG:
+	default:
+		return invalid("unsupported format %q, expected pdf or png", cmd.Format)
+	}
+	if cmd.PaperSize == "" {
+		cmd.PaperSize = string(rendering.PaperA4)
+	}
+	if !rendering.ValidPaperSize(rendering.PaperSize(cmd.PaperSize)) {
+		return invalid("unsupported paper size %q", cmd.PaperSize)
+	}
+	switch cmd.Orientation {
+	case "":
+		cmd.Orientation = "portrait"
+	case "portrait", "landscape":
+	default:
+		return invalid("unsupported orientation %q, expected portrait or landscape", cmd.Orientation)
+	}
+
+	report.Name = strings.TrimSpace(cmd.Name)
+	report.DashboardUid = cmd.DashboardUID
+	report.Schedule = cmd.Schedule
+	report.Timezone = cmd.Timezone
+	report.Recipients = recipients
+	report.Format = cmd.Format
+	report.PaperSize = cmd.PaperSize
+	report.Orientation = cmd.Orientation
+	report.TimeFrom = cmd.TimeFrom
+	report.TimeTo = cmd.TimeTo
+	report.Message = cmd.Message
+	report.Enabled = cmd.Enabled == nil || *cmd.Enabled
+	report.Updated = now
+	report.NextRun = schedule.Next(now.In(loc))
+	return nil
+}
+
+// nextRun returns the next time the report is due after now.
+func nextRun(report *Report, now time.Time) (time.Time, error) {
+	loc, err := time.LoadLocation(report.Timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	schedule, err := cron.ParseStandard(report.Schedule)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return schedule.Next(now.In(loc)), nil
+}
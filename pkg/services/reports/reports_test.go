@@ -0,0 +1,192 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRenderService struct {
+	rendering.Service
+	dir  string
+	pdfs []rendering.PDFOpts
+	pngs []rendering.Opts
+}
+
+func (s *fakeRenderService) RenderPDF(ctx context.Context, opts rendering.PDFOpts) (*rendering.RenderResult, error) {
+	s.pdfs = append(s.pdfs, opts)
+	return s.write("%PDF-1.4")
+}
+
+func (s *fakeRenderService) Render(ctx context.Context, opts rendering.Opts) (*rendering.RenderResult, error) {
+	s.pngs = append(s.pngs, opts)
+	return s.write("\x89PNG")
+}
+
+func (s *fakeRenderService) write(content string) (*rendering.RenderResult, error) {
+	f, err := ioutil.TempFile(s.dir, "report")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	_, err = f.WriteString(content)
+	return &rendering.RenderResult{FilePath: f.Name()}, err
+}
+
+func TestReports(t *testing.T) {
+	ctx := context.Background()
+	// a Monday
+	now := time.Date(2021, 6, 7, 6, 30, 0, 0, time.UTC)
+	getTime = func() time.Time { return now }
+	t.Cleanup(func() { getTime = time.Now })
+
+	store := sqlstore.InitTestDB(t)
+	renderService := &fakeRenderService{dir: t.TempDir()}
+	s := &Service{
+		Cfg:           &setting.Cfg{ReportsEnabled: true, ReportsMaxRecipients: 2, ReportsRenderTimeout: time.Minute},
+		SQLStore:      store,
+		RenderService: renderService,
+		log:           log.New("reports.test"),
+	}
+	user := &models.SignedInUser{UserId: 3, OrgId: 1, OrgRole: models.ROLE_EDITOR}
+
+	bus.AddHandlerCtx("test", func(ctx context.Context, query *models.GetSignedInUserQuery) error {
+		query.Result = user
+		return nil
+	})
+	bus.AddHandlerCtx("test", func(ctx context.Context, query *models.GetDashboardQuery) error {
+		query.Result = &models.Dashboard{Id: 1, Uid: "abc", Slug: "test-dash", Title: "Test dash"}
+		return nil
+	})
+	var sent []*models.SendEmailCommandSync
+	bus.AddHandlerCtx("test", func(ctx context.Context, cmd *models.SendEmailCommandSync) error {
+		sent = append(sent, cmd)
+		return nil
+	})
+	t.Cleanup(bus.ClearBusHandlers)
+
+	origNewGuardian := guardian.New
+	t.Cleanup(func() { guardian.New = origNewGuardian })
+	guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanViewValue: true})
+
+	valid := SaveReportCommand{
+		Name:         "Weekly",
+		DashboardUID: "abc",
+		Schedule:     "0 8 * * 1",
+		Timezone:     "Europe/Paris",
+		Recipients:   []string{"Jane <jane@example.com>", "ops@example.com"},
+		TimeFrom:     "now-7d",
+		TimeTo:       "now",
+	}
+
+	t.Run("Should validate the reports", func(t *testing.T) {
+		for name, mutate := range map[string]func(cmd *SaveReportCommand){
+			"name":      func(cmd *SaveReportCommand) { cmd.Name = " " },
+			"schedule":  func(cmd *SaveReportCommand) { cmd.Schedule = "every monday" },
+			"timezone":  func(cmd *SaveReportCommand) { cmd.Timezone = "Mars/Olympus" },
+			"recipient": func(cmd *SaveReportCommand) { cmd.Recipients = []string{"jane"} },
+			"recipients": func(cmd *SaveReportCommand) {
+				cmd.Recipients = []string{"a@example.com", "b@example.com", "c@example.com"}
+			},
+			"format":      func(cmd *SaveReportCommand) { cmd.Format = "xlsx" },
+			"paper size":  func(cmd *SaveReportCommand) { cmd.PaperSize = "a0" },
+			"orientation": func(cmd *SaveReportCommand) { cmd.Orientation = "diagonal" },
+		} {
+			cmd := valid
+			mutate(&cmd)
+			_, err := s.CreateReport(ctx, user, cmd)
+			assert.True(t, errors.Is(err, ErrInvalidReport), name)
+		}
+	})
+
+	var report *Report
+	t.Run("Should create reports with defaults", func(t *testing.T) {
+		var err error
+		report, err = s.CreateReport(ctx, user, valid)
+		require.NoError(t, err)
+		assert.Equal(t, Recipients{"jane@example.com", "ops@example.com"}, report.Recipients)
+		assert.Equal(t, FormatPDF, report.Format)
+		assert.Equal(t, "a4", report.PaperSize)
+		assert.Equal(t, "portrait", report.Orientation)
+		assert.True(t, report.Enabled)
+		assert.Equal(t, int64(3), report.CreatedBy)
+		// 8:00 in Paris is 6:00 UTC, which is past on that Monday
+		assert.True(t, report.NextRun.Equal(time.Date(2021, 6, 14, 6, 0, 0, 0, time.UTC)), report.NextRun)
+
+		saved, err := s.GetReport(ctx, 1, report.Id)
+		require.NoError(t, err)
+		assert.Equal(t, report.Recipients, saved.Recipients)
+
+		_, err = s.GetReport(ctx, 2, report.Id)
+		require.True(t, errors.Is(err, ErrReportNotFound))
+	})
+
+	t.Run("Should update reports", func(t *testing.T) {
+		cmd := valid
+		cmd.Format = FormatPNG
+		updated, err := s.UpdateReport(ctx, user, report.Id, cmd)
+		require.NoError(t, err)
+		assert.Equal(t, FormatPNG, updated.Format)
+
+		reports, err := s.GetReports(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, reports, 1)
+		assert.Equal(t, FormatPNG, reports[0].Format)
+	})
+
+	t.Run("Should send the reports due once", func(t *testing.T) {
+		require.NoError(t, s.sendDue(ctx))
+		require.Len(t, sent, 0)
+
+		now = time.Date(2021, 6, 14, 6, 0, 30, 0, time.UTC)
+		require.NoError(t, s.sendDue(ctx))
+		require.NoError(t, s.sendDue(ctx))
+		require.Len(t, sent, 1)
+		assert.Equal(t, []string{"jane@example.com", "ops@example.com"}, sent[0].To)
+		assert.Equal(t, "report", sent[0].Template)
+		require.Len(t, sent[0].AttachedFiles, 1)
+		assert.Equal(t, "test-dash.png", sent[0].AttachedFiles[0].Name)
+		assert.Equal(t, "\x89PNG", string(sent[0].AttachedFiles[0].Content))
+
+		require.Len(t, renderService.pngs, 1)
+		assert.Equal(t, "d/abc/test-dash?from=now-7d&kiosk=&orgId=1&to=now", renderService.pngs[0].Path)
+		assert.Equal(t, int64(3), renderService.pngs[0].UserID)
+		files, err := filepath.Glob(filepath.Join(renderService.dir, "*"))
+		require.NoError(t, err)
+		assert.Empty(t, files)
+
+		saved, err := s.GetReport(ctx, 1, report.Id)
+		require.NoError(t, err)
+		assert.True(t, saved.NextRun.Equal(time.Date(2021, 6, 21, 6, 0, 0, 0, time.UTC)), saved.NextRun)
+		require.NotNil(t, saved.LastRun)
+		assert.Empty(t, saved.LastError)
+	})
+
+	t.Run("Should record the errors of the reports", func(t *testing.T) {
+		guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanViewValue: false})
+		err := s.SendReport(ctx, 1, report.Id)
+		require.Equal(t, errDashboardAccessDenied, err)
+
+		saved, err := s.GetReport(ctx, 1, report.Id)
+		require.NoError(t, err)
+		assert.Equal(t, errDashboardAccessDenied.Error(), saved.LastError)
+	})
+
+	t.Run("Should delete reports", func(t *testing.T) {
+		require.NoError(t, s.DeleteReport(ctx, 1, report.Id))
+		require.True(t, errors.Is(s.DeleteReport(ctx, 1, report.Id), ErrReportNotFound))
+	})
+}
@@ -0,0 +1,221 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// pngWidth is the width of the images of the dashboards of PNG reports, which
+// are as high as the dashboards.
+const pngWidth = 1600
+
+var errDashboardAccessDenied = errors.New("the creator of the report can no longer view the dashboard")
+
+// SendReport renders the dashboard of the report and emails it to the
+// recipients now, regardless of its schedule.
+func (s *Service) SendReport(ctx context.Context, orgID, id int64) error {
+	report, err := s.GetReport(ctx, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	err = s.send(ctx, report)
+	if recordErr := s.recordRun(ctx, report, getTime(), err); recordErr != nil {
+		s.log.Error("Failed to record the run of the report", "report", report.Id, "error", recordErr)
+	}
+	return err
+}
+
+// sendDue sends the enabled reports whose next run is due. Each report is
+// claimed by moving its next run first, so that it is sent by only one
+// instance when several share the database.
+func (s *Service) sendDue(ctx context.Context) error {
+	now := getTime()
+	due := []*Report{}
+	err := s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		return sess.Where("enabled = ? AND next_run <= ?", true, now).Asc("next_run").Find(&due)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, report := range due {
+		claimed, err := s.claim(ctx, report, now)
+		if err != nil {
+			s.log.Error("Failed to schedule the next run of the report", "report", report.Id, "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		s.log.Debug("Sending report", "report", report.Id, "org", report.OrgId)
+		sendErr := s.send(ctx, report)
+		if sendErr != nil {
+			s.log.Warn("Failed to send report", "report", report.Id, "org", report.OrgId, "error", sendErr)
+		}
+		if err := s.recordRun(ctx, report, getTime(), sendErr); err != nil {
+			s.log.Error("Failed to record the run of the report", "report", report.Id, "error", err)
+		}
+	}
+	return nil
+}
+
+// claim moves the next run of the report after now, and returns false if
+// another instance moved it first.
+func (s *Service) claim(ctx context.Context, report *Report, now time.Time) (bool, error) {
+	next, err := nextRun(report, now)
+	if err != nil {
+		return false, err
+	}
+
+	var claimed bool
+	err = s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		affected, err := sess.Where("id = ? AND next_run <= ?", report.Id, now).
+			Cols("next_run").
+			Update(&Report{NextRun: next})
+		claimed = affected == 1
+		return err
+	})
+	report.NextRun = next
+	return claimed, err
+}
+
+func (s *Service) recordRun(ctx context.Context, report *Report, at time.Time, runErr error) error {
+	report.LastRun = &at
+	report.LastError = ""
+	if runErr != nil {
+		report.LastError = runErr.Error()
+	}
+
+	return s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		_, err := sess.ID(report.Id).Cols("last_run", "last_error").Update(report)
+		return err
+	})
+}
+
+// send renders the dashboard of the report as its creator and emails it.
+func (s *Service) send(ctx context.Context, report *Report) error {
+	userQuery := models.GetSignedInUserQuery{UserId: report.CreatedBy, OrgId: report.OrgId}
+	if err := bus.DispatchCtx(ctx, &userQuery); err != nil {
+		return fmt.Errorf("failed to get the creator of the report: %w", err)
+	}
+	user := userQuery.Result
+
+	dashQuery := models.GetDashboardQuery{Uid: report.DashboardUid, OrgId: report.OrgId}
+	if err := bus.DispatchCtx(ctx, &dashQuery); err != nil {
+		return fmt.Errorf("failed to get the dashboard of the report: %w", err)
+	}
+	dash := dashQuery.Result
+
+	g := guardian.New(dash.Id, report.OrgId, user)
+	if canView, err := g.CanView(); err != nil || !canView {
+		if err != nil {
+			return err
+		}
+		return errDashboardAccessDenied
+	}
+
+	filePath, err := s.render(ctx, report, dash, user)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+			s.log.Warn("Failed to remove the rendered report", "path", filePath, "error", err)
+		}
+	}()
+
+	// #nosec G304 - the path is returned by the render service
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	cmd := &models.SendEmailCommandSync{
+		SendEmailCommand: models.SendEmailCommand{
+			To:          report.Recipients,
+			SingleEmail: false,
+			Template:    "report",
+			Subject:     report.Name,
+			Data: map[string]interface{}{
+				"ReportName":     report.Name,
+				"DashboardTitle": dash.Title,
+				"DashboardUrl":   models.GetFullDashboardUrl(dash.Uid, dash.Slug),
+				"From":           report.TimeFrom,
+				"To":             report.TimeTo,
+				"Message":        report.Message,
+			},
+			AttachedFiles: []*models.SendEmailAttachFile{
+				{Name: dash.Slug + "." + report.Format, Content: content},
+			},
+		},
+	}
+	return bus.DispatchCtx(ctx, cmd)
+}
+
+// render renders the dashboard in the format of the report, and returns the
+// path of the file rendered.
+func (s *Service) render(ctx context.Context, report *Report, dash *models.Dashboard, user *models.SignedInUser) (string, error) {
+	path := dashboardPath(report, dash)
+
+	var result *rendering.RenderResult
+	var err error
+	if report.Format == FormatPNG {
+		result, err = s.RenderService.Render(ctx, rendering.Opts{
+			Width:             pngWidth,
+			Height:            -1,
+			Timeout:           s.Cfg.ReportsRenderTimeout,
+			OrgID:             report.OrgId,
+			UserID:            user.UserId,
+			OrgRole:           user.OrgRole,
+			Path:              path,
+			Timezone:          report.Timezone,
+			ConcurrentLimit:   s.Cfg.RendererConcurrentRequestLimit,
+			DeviceScaleFactor: 1,
+		})
+	} else {
+		result, err = s.RenderService.RenderPDF(ctx, rendering.PDFOpts{
+			Timeout:         s.Cfg.ReportsRenderTimeout,
+			OrgID:           report.OrgId,
+			UserID:          user.UserId,
+			OrgRole:         user.OrgRole,
+			Path:            path,
+			Timezone:        report.Timezone,
+			ConcurrentLimit: s.Cfg.RendererConcurrentRequestLimit,
+			PaperSize:       rendering.PaperSize(report.PaperSize),
+			Landscape:       report.Orientation == "landscape",
+		})
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to render the dashboard of the report: %w", err)
+	}
+	return result.FilePath, nil
+}
+
+// dashboardPath returns the path of the dashboard of the report in kiosk
+// mode, with the time range of the report.
+func dashboardPath(report *Report, dash *models.Dashboard) string {
+	query := url.Values{}
+	if report.TimeFrom != "" {
+		query.Set("from", report.TimeFrom)
+	}
+	if report.TimeTo != "" {
+		query.Set("to", report.TimeTo)
+	}
+	query.Set("orgId", strconv.FormatInt(report.OrgId, 10))
+	query.Set("kiosk", "")
+	return fmt.Sprintf("d/%s/%s?%s", dash.Uid, dash.Slug, query.Encode())
+}
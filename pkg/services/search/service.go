@@ -8,6 +8,7 @@ import (
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/registry"
+	"github.com/grafana/grafana/pkg/services/sqlstore/permissions"
 )
 
 func init() {
@@ -85,6 +86,15 @@ func (s *SearchService) searchHandler(query *Query) error {
 		dashboardQuery.Sort = sortOpt
 	}
 
+	if query.SignedInUser.IsAnonymous && s.Cfg.AnonymousDashboardAccess != "all" {
+		dashboardQuery.Filters = append(dashboardQuery.Filters, permissions.AnonymousDashboardFilter{
+			OrgId:         query.SignedInUser.OrgId,
+			Exclude:       s.Cfg.AnonymousDashboardAccess == "exclude",
+			DashboardUIDs: s.Cfg.AnonymousDashboardUIDs,
+			FolderUIDs:    s.Cfg.AnonymousFolderUIDs,
+		})
+	}
+
 	if err := bus.Dispatch(&dashboardQuery); err != nil {
 		return err
 	}
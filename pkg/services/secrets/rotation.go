@@ -0,0 +1,51 @@
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// States of the rotations of the secret key
+const (
+	SecretKeyRotationRunning = "running"
+	SecretKeyRotationDone    = "done"
+)
+
+// ErrSecretKeyRotationInterrupted means that some secrets are encrypted with the new secret key
+// and the others with the old one.
+var ErrSecretKeyRotationInterrupted = errors.New("a rotation of the secret key was interrupted")
+
+// SecretKeyRotation records how far the secrets have been re-encrypted, so that the secrets
+// already encrypted with the new key aren't re-encrypted again after an interruption.
+type SecretKeyRotation struct {
+	Id           int64
+	State        string
+	CurrentTable string
+	LastId       int64
+	Started      time.Time
+	Updated      time.Time
+}
+
+func (SecretKeyRotation) TableName() string {
+	return "secret_key_rotation"
+}
+
+// CheckSecretKeyRotation returns ErrSecretKeyRotationInterrupted if a rotation of the secret key
+// is partially applied.
+func CheckSecretKeyRotation(sess *sqlstore.DBSession) error {
+	rotation := SecretKeyRotation{}
+	has, err := sess.Where("state = ?", SecretKeyRotationRunning).Get(&rotation)
+	if err != nil {
+		return err
+	}
+	if !has {
+		return nil
+	}
+
+	return fmt.Errorf("%w: it started at %s and re-encrypted the %s up to id %d, so some secrets are already "+
+		"encrypted with the new secret key. Restore the database from a backup taken before the rotation",
+		ErrSecretKeyRotationInterrupted, rotation.Started.Format(time.RFC3339), rotation.CurrentTable, rotation.LastId)
+}
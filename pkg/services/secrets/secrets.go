@@ -0,0 +1,236 @@
+// Package secrets encrypts secrets with data keys, themselves encrypted with the secret key and
+// stored in the database. Each data key has a scope, such as a user, so that a leaked data key only
+// exposes the secrets of its scope.
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/registry"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// dataKeyPrefix starts the payloads encrypted with a data key, followed by the base64 encoded
+// name of the data key and another prefix. Payloads encrypted with the secret key start with an
+// alphanumeric salt instead.
+const dataKeyPrefix = '#'
+
+var ErrDataKeyNotFound = errors.New("data key not found")
+
+func init() {
+	registry.RegisterService(&Service{})
+}
+
+// UserScope returns the scope of the data key of a user.
+func UserScope(userID int64) string {
+	return fmt.Sprintf("user:%d", userID)
+}
+
+// IsEncryptedWithDataKey returns true if the payload was encrypted with a data key rather than
+// with the secret key.
+func IsEncryptedWithDataKey(payload []byte) bool {
+	return len(payload) > 0 && payload[0] == dataKeyPrefix
+}
+
+// dataKey is a key of a scope, encrypted with the secret key and base64 encoded.
+type dataKey struct {
+	Id            int64
+	Name          string
+	Scope         string
+	EncryptedData string
+	Created       time.Time
+}
+
+func (dataKey) TableName() string {
+	return "data_keys"
+}
+
+type Service struct {
+	SQLStore *sqlstore.SQLStore `inject:""`
+
+	log log.Logger
+
+	mu           sync.RWMutex
+	keysByName   map[string]string
+	namesByScope map[string]string
+}
+
+// Init refuses to start when a rotation of the secret key is partially applied, since the
+// secrets re-encrypted with the new secret key can't be decrypted.
+func (s *Service) Init() error {
+	s.log = log.New("secrets")
+	s.keysByName = map[string]string{}
+	s.namesByScope = map[string]string{}
+
+	return s.SQLStore.WithDbSession(context.Background(), CheckSecretKeyRotation)
+}
+
+// Encrypt encrypts the payload with the data key of the scope, creating the key if the scope
+// has none yet.
+func (s *Service) Encrypt(ctx context.Context, payload []byte, scope string) ([]byte, error) {
+	name, key, err := s.scopeKey(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := util.Encrypt(payload, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte(dataKeyPrefix)
+	buf.WriteString(base64.RawStdEncoding.EncodeToString([]byte(name)))
+	buf.WriteByte(dataKeyPrefix)
+	buf.Write(encrypted)
+	return buf.Bytes(), nil
+}
+
+// Decrypt decrypts a payload encrypted by Encrypt. Payloads encrypted with the secret key are
+// decrypted with it, so that secrets stored before data keys were used can still be read.
+func (s *Service) Decrypt(ctx context.Context, payload []byte) ([]byte, error) {
+	if !IsEncryptedWithDataKey(payload) {
+		return util.Decrypt(payload, setting.SecretKey)
+	}
+
+	end := bytes.IndexByte(payload[1:], dataKeyPrefix)
+	if end == -1 {
+		return nil, errors.New("could not find the name of the data key")
+	}
+	name, err := base64.RawStdEncoding.DecodeString(string(payload[1 : end+1]))
+	if err != nil {
+		return nil, fmt.Errorf("could not decode the name of the data key: %w", err)
+	}
+
+	key, err := s.keyByName(ctx, string(name))
+	if err != nil {
+		return nil, err
+	}
+	return util.Decrypt(payload[end+2:], key)
+}
+
+func (s *Service) keyByName(ctx context.Context, name string) (string, error) {
+	s.mu.RLock()
+	key, ok := s.keysByName[name]
+	s.mu.RUnlock()
+	if ok {
+		return key, nil
+	}
+
+	stored := dataKey{}
+	err := s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		has, err := sess.Where("name = ?", name).Get(&stored)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return ErrDataKeyNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return s.cache(&stored)
+}
+
+func (s *Service) scopeKey(ctx context.Context, scope string) (string, string, error) {
+	s.mu.RLock()
+	name, ok := s.namesByScope[scope]
+	key := s.keysByName[name]
+	s.mu.RUnlock()
+	if ok {
+		return name, key, nil
+	}
+
+	stored := dataKey{}
+	has := false
+	getKey := func(sess *sqlstore.DBSession) error {
+		var err error
+		has, err = sess.Where("scope = ?", scope).Get(&stored)
+		return err
+	}
+	if err := s.SQLStore.WithDbSession(ctx, getKey); err != nil {
+		return "", "", err
+	}
+
+	if !has {
+		created, err := newDataKey(scope)
+		if err != nil {
+			return "", "", err
+		}
+		err = s.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+			_, err := sess.Insert(created)
+			return err
+		})
+		if err == nil {
+			stored = *created
+		} else {
+			// another instance may have created the key of the scope in the meantime
+			s.log.Debug("Failed to create data key, looking it up again", "scope", scope, "error", err)
+			if err := s.SQLStore.WithDbSession(ctx, getKey); err != nil {
+				return "", "", err
+			}
+			if !has {
+				return "", "", ErrDataKeyNotFound
+			}
+		}
+	}
+
+	key, err := s.cache(&stored)
+	if err != nil {
+		return "", "", err
+	}
+	return stored.Name, key, nil
+}
+
+func (s *Service) cache(stored *dataKey) (string, error) {
+	encrypted, err := base64.StdEncoding.DecodeString(stored.EncryptedData)
+	if err != nil {
+		return "", err
+	}
+	key, err := util.Decrypt(encrypted, setting.SecretKey)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keysByName[stored.Name] = string(key)
+	s.namesByScope[stored.Scope] = stored.Name
+	return string(key), nil
+}
+
+func newDataKey(scope string) (*dataKey, error) {
+	name, err := util.GetRandomString(16)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	encrypted, err := util.Encrypt([]byte(base64.StdEncoding.EncodeToString(raw)), setting.SecretKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dataKey{
+		Name:          name,
+		Scope:         scope,
+		EncryptedData: base64.StdEncoding.EncodeToString(encrypted),
+		Created:       time.Now(),
+	}, nil
+}
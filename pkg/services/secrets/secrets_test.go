@@ -0,0 +1,85 @@
+package secrets
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	sqlStore := sqlstore.InitTestDB(t)
+	s := &Service{SQLStore: sqlStore}
+	require.NoError(t, s.Init())
+
+	t.Run("Payloads are encrypted with the data key of their scope", func(t *testing.T) {
+		encrypted, err := s.Encrypt(ctx, []byte("refresh-token"), UserScope(1))
+		require.NoError(t, err)
+		assert.True(t, IsEncryptedWithDataKey(encrypted))
+
+		withSecretKey, _ := util.Decrypt(encrypted, setting.SecretKey)
+		assert.NotEqual(t, "refresh-token", string(withSecretKey))
+
+		decrypted, err := s.Decrypt(ctx, encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, "refresh-token", string(decrypted))
+	})
+
+	t.Run("Each scope has its own data key", func(t *testing.T) {
+		_, err := s.Encrypt(ctx, []byte("token"), UserScope(2))
+		require.NoError(t, err)
+		_, err = s.Encrypt(ctx, []byte("other token"), UserScope(2))
+		require.NoError(t, err)
+
+		var keys []*dataKey
+		require.NoError(t, sqlStore.NewSession(ctx).Find(&keys))
+		scopes := []string{}
+		for _, key := range keys {
+			scopes = append(scopes, key.Scope)
+		}
+		assert.ElementsMatch(t, []string{UserScope(1), UserScope(2)}, scopes)
+		assert.NotEqual(t, keys[0].EncryptedData, keys[1].EncryptedData)
+	})
+
+	t.Run("Data keys are read from the database by other instances", func(t *testing.T) {
+		encrypted, err := s.Encrypt(ctx, []byte("access-token"), UserScope(3))
+		require.NoError(t, err)
+
+		other := &Service{SQLStore: sqlStore}
+		require.NoError(t, other.Init())
+		decrypted, err := other.Decrypt(ctx, encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, "access-token", string(decrypted))
+	})
+
+	t.Run("Payloads encrypted with the secret key can be decrypted", func(t *testing.T) {
+		encrypted, err := util.Encrypt([]byte("legacy-token"), setting.SecretKey)
+		require.NoError(t, err)
+		assert.False(t, IsEncryptedWithDataKey(encrypted))
+
+		decrypted, err := s.Decrypt(ctx, encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, "legacy-token", string(decrypted))
+	})
+}
+
+func TestService_Init(t *testing.T) {
+	sqlStore := sqlstore.InitTestDB(t)
+	s := &Service{SQLStore: sqlStore}
+
+	rotation := SecretKeyRotation{State: SecretKeyRotationRunning, CurrentTable: "data_source", LastId: 1, Started: time.Now(), Updated: time.Now()}
+	_, err := sqlStore.NewSession(context.Background()).Insert(&rotation)
+	require.NoError(t, err)
+	require.ErrorIs(t, s.Init(), ErrSecretKeyRotationInterrupted)
+
+	rotation.State = SecretKeyRotationDone
+	_, err = sqlStore.NewSession(context.Background()).ID(rotation.Id).Cols("state").Update(&rotation)
+	require.NoError(t, err)
+	require.NoError(t, s.Init())
+}
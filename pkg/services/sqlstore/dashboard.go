@@ -11,6 +11,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/search"
@@ -64,6 +65,9 @@ func saveDashboard(sess *DBSession, cmd *models.SaveDashboardCommand) error {
 		userId = -1
 	}
 
+	// moving a dashboard to another folder changes the permissions it inherits
+	movedToFolder := false
+
 	if dash.Id > 0 {
 		var existing models.Dashboard
 		dashWithIdExists, err := sess.Where("id=? AND org_id=?", dash.Id, dash.OrgId).Get(&existing)
@@ -87,6 +91,8 @@ func saveDashboard(sess *DBSession, cmd *models.SaveDashboardCommand) error {
 		if existing.PluginId != "" && !cmd.Overwrite {
 			return models.UpdatePluginDashboardError{PluginId: existing.PluginId}
 		}
+
+		movedToFolder = existing.FolderId != dash.FolderId
 	}
 
 	if dash.Uid == "" {
@@ -131,6 +137,14 @@ func saveDashboard(sess *DBSession, cmd *models.SaveDashboardCommand) error {
 		return models.ErrDashboardNotFound
 	}
 
+	if movedToFolder {
+		sess.publishAfterCommit(&events.DashboardAclUpdated{
+			Timestamp:   dash.Updated,
+			OrgId:       dash.OrgId,
+			DashboardId: dash.Id,
+		})
+	}
+
 	dashVersion := &models.DashboardVersion{
 		DashboardId:   dash.Id,
 		ParentVersion: parentVersion,
@@ -288,6 +302,8 @@ func findDashboards(query *search.FindPersistedDashboardsQuery) ([]DashboardSear
 		filters = append(filters, filter)
 	}
 
+	filters = append(filters, query.Filters...)
+
 	if query.OrgId != 0 {
 		filters = append(filters, searchstore.OrgFilter{OrgId: query.OrgId})
 	} else if query.SignedInUser.OrgId != 0 {
@@ -606,6 +622,39 @@ func GetDashboardsByPluginId(query *models.GetDashboardsByPluginIdQuery) error {
 	return err
 }
 
+// dashboardBatchSize is the number of dashboards loaded at once by ForEachDashboard.
+const dashboardBatchSize = 100
+
+// ForEachDashboard calls fn for the dashboards of all organizations, in the
+// order of their IDs. Folders are skipped. The dashboards are loaded in batches,
+// and iterating stops at the first error returned by fn.
+func (ss *SQLStore) ForEachDashboard(ctx context.Context, fn func(dashboard *models.Dashboard) error) error {
+	var lastID int64
+	for {
+		dashboards := make([]*models.Dashboard, 0, dashboardBatchSize)
+		err := ss.WithDbSession(ctx, func(sess *DBSession) error {
+			return sess.Where("id > ? AND is_folder = "+dialect.BooleanStr(false), lastID).
+				OrderBy("id").
+				Limit(dashboardBatchSize).
+				Find(&dashboards)
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, dashboard := range dashboards {
+			if err := fn(dashboard); err != nil {
+				return err
+			}
+			lastID = dashboard.Id
+		}
+
+		if len(dashboards) < dashboardBatchSize {
+			return nil
+		}
+	}
+}
+
 type DashboardSlugDTO struct {
 	Slug string
 }
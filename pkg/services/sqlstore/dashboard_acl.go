@@ -3,15 +3,12 @@ package sqlstore
 import (
 	"context"
 	"fmt"
+	"time"
 
-	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/models"
 )
 
-func init() {
-	bus.AddHandler("sql", GetDashboardAclInfoList)
-}
-
 func (ss *SQLStore) UpdateDashboardACL(dashboardID int64, items []*models.DashboardAcl) error {
 	return ss.WithTransactionalDbSession(context.Background(), func(sess *DBSession) error {
 		// delete existing items
@@ -37,8 +34,21 @@ func (ss *SQLStore) UpdateDashboardACL(dashboardID int64, items []*models.Dashbo
 
 		// Update dashboard HasAcl flag
 		dashboard := models.Dashboard{HasAcl: true}
-		_, err = sess.Cols("has_acl").Where("id=?", dashboardID).Update(&dashboard)
-		return err
+		if _, err := sess.Cols("has_acl").Where("id=?", dashboardID).Update(&dashboard); err != nil {
+			return err
+		}
+
+		var orgID int64
+		if _, err := sess.Table("dashboard").Where("id=?", dashboardID).Cols("org_id").Get(&orgID); err != nil {
+			return err
+		}
+
+		sess.publishAfterCommit(&events.DashboardAclUpdated{
+			Timestamp:   time.Now(),
+			OrgId:       orgID,
+			DashboardId: dashboardID,
+		})
+		return nil
 	})
 }
 
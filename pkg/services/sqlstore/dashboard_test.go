@@ -13,6 +13,7 @@ import (
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/search"
+	"github.com/grafana/grafana/pkg/services/sqlstore/permissions"
 	"github.com/grafana/grafana/pkg/services/sqlstore/searchstore"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
@@ -459,6 +460,67 @@ func TestDashboard_SortingOptions(t *testing.T) {
 	})
 }
 
+func TestDashboard_AnonymousDashboardFilter(t *testing.T) {
+	sqlStore := InitTestDB(t)
+	saveFolder := func(orgId int64) *models.Dashboard {
+		folder, err := sqlStore.SaveDashboard(models.SaveDashboardCommand{
+			OrgId:     orgId,
+			IsFolder:  true,
+			Dashboard: simplejson.NewFromAny(map[string]interface{}{"title": "shared", "uid": "shared"}),
+		})
+		require.NoError(t, err)
+		return folder
+	}
+	folder := saveFolder(1)
+	otherFolder := saveFolder(2)
+	dash := insertTestDashboard(t, sqlStore, "listed", 1, folder.Id, false)
+	insertTestDashboard(t, sqlStore, "other org", 2, otherFolder.Id, false)
+	insertTestDashboard(t, sqlStore, "not listed", 1, 0, false)
+
+	q := &search.FindPersistedDashboardsQuery{
+		SignedInUser: &models.SignedInUser{OrgId: 1, OrgRole: models.ROLE_ADMIN},
+		Filters: []interface{}{
+			permissions.AnonymousDashboardFilter{OrgId: 1, FolderUIDs: []string{"shared"}},
+		},
+	}
+	dashboards, err := findDashboards(q)
+	require.NoError(t, err)
+
+	ids := []int64{}
+	for _, d := range dashboards {
+		ids = append(ids, d.ID)
+	}
+	assert.ElementsMatch(t, []int64{folder.Id, dash.Id}, ids)
+}
+
+func TestForEachDashboard(t *testing.T) {
+	sqlStore := InitTestDB(t)
+	folder := insertTestDashboard(t, sqlStore, "folder", 1, 0, true)
+	var expected []string
+	for i := 0; i < dashboardBatchSize+2; i++ {
+		title := fmt.Sprintf("dashboard %d", i)
+		insertTestDashboard(t, sqlStore, title, int64(1+i%2), folder.Id, false)
+		expected = append(expected, title)
+	}
+
+	var titles []string
+	err := sqlStore.ForEachDashboard(context.Background(), func(dashboard *models.Dashboard) error {
+		titles = append(titles, dashboard.Title)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, expected, titles)
+
+	expectedErr := errors.New("stop")
+	calls := 0
+	err = sqlStore.ForEachDashboard(context.Background(), func(dashboard *models.Dashboard) error {
+		calls++
+		return expectedErr
+	})
+	require.Equal(t, expectedErr, err)
+	require.Equal(t, 1, calls)
+}
+
 func insertTestDashboard(t *testing.T, sqlStore *SQLStore, title string, orgId int64,
 	folderId int64, isFolder bool, tags ...interface{}) *models.Dashboard {
 	t.Helper()
@@ -0,0 +1,190 @@
+package sqlstore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// Identity lookups happen on most requests, so their results are cached in
+// memory for Cfg.IdentityCacheTTL. Entries are invalidated by the events
+// published when the underlying rows change.
+
+const (
+	signedInUserCachePrefix     = "signed-in-user-"
+	userByIdCachePrefix         = "user-by-id-"
+	userOrgListCachePrefix      = "user-org-list-"
+	dashboardAclInfoCachePrefix = "dashboard-acl-info-"
+)
+
+func newSignedInUserCacheKey(orgID, userID int64) string {
+	return fmt.Sprintf("%s%d-%d", signedInUserCachePrefix, userID, orgID)
+}
+
+func newUserByIdCacheKey(userID int64) string {
+	return fmt.Sprintf("%s%d", userByIdCachePrefix, userID)
+}
+
+func newUserOrgListCacheKey(userID int64) string {
+	return fmt.Sprintf("%s%d", userOrgListCachePrefix, userID)
+}
+
+func newDashboardAclInfoCacheKey(orgID, dashboardID int64) string {
+	return fmt.Sprintf("%s%d-%d", dashboardAclInfoCachePrefix, orgID, dashboardID)
+}
+
+func (ss *SQLStore) addIdentityCacheHandlers() {
+	bus.AddHandlerCtx("sql", ss.GetUserByIdWithCacheCtx)
+	bus.AddHandler("sql", ss.GetUserOrgListWithCache)
+	bus.AddHandler("sql", ss.GetDashboardAclInfoListWithCache)
+
+	bus.AddEventListener(ss.onUserUpdated)
+	bus.AddEventListener(ss.onUserAccessChanged)
+	bus.AddEventListener(ss.onUserCredentialsRevoked)
+	bus.AddEventListener(ss.onOrgUpdated)
+	bus.AddEventListener(ss.onDashboardAclUpdated)
+}
+
+// identityCacheEnabled reports whether lookups can be served from the cache.
+// Reads within a transaction bypass it, as they may see uncommitted rows.
+func (ss *SQLStore) identityCacheEnabled(ctx context.Context) bool {
+	if ss.Cfg == nil || ss.Cfg.IdentityCacheTTL <= 0 {
+		return false
+	}
+	_, inTransaction := ctx.Value(ContextSessionKey{}).(*DBSession)
+	return !inTransaction
+}
+
+func (ss *SQLStore) GetUserByIdWithCacheCtx(ctx context.Context, query *models.GetUserByIdQuery) error {
+	if !ss.identityCacheEnabled(ctx) {
+		return GetUserById(ctx, query)
+	}
+
+	cacheKey := newUserByIdCacheKey(query.Id)
+	if cached, found := ss.CacheService.Get(cacheKey); found {
+		// hand out copies so that callers can't modify the cached entry
+		user := *cached.(*models.User)
+		query.Result = &user
+		return nil
+	}
+
+	if err := GetUserById(ctx, query); err != nil {
+		return err
+	}
+
+	user := *query.Result
+	ss.CacheService.Set(cacheKey, &user, ss.Cfg.IdentityCacheTTL)
+	return nil
+}
+
+func (ss *SQLStore) GetUserOrgListWithCache(query *models.GetUserOrgListQuery) error {
+	if !ss.identityCacheEnabled(context.Background()) {
+		return GetUserOrgList(query)
+	}
+
+	cacheKey := newUserOrgListCacheKey(query.UserId)
+	if cached, found := ss.CacheService.Get(cacheKey); found {
+		query.Result = copyUserOrgList(cached.([]*models.UserOrgDTO))
+		return nil
+	}
+
+	if err := GetUserOrgList(query); err != nil {
+		return err
+	}
+
+	ss.CacheService.Set(cacheKey, copyUserOrgList(query.Result), ss.Cfg.IdentityCacheTTL)
+	return nil
+}
+
+func (ss *SQLStore) GetDashboardAclInfoListWithCache(query *models.GetDashboardAclInfoListQuery) error {
+	if !ss.identityCacheEnabled(context.Background()) {
+		return GetDashboardAclInfoList(query)
+	}
+
+	cacheKey := newDashboardAclInfoCacheKey(query.OrgID, query.DashboardID)
+	if cached, found := ss.CacheService.Get(cacheKey); found {
+		query.Result = copyDashboardAclInfoList(cached.([]*models.DashboardAclInfoDTO))
+		return nil
+	}
+
+	if err := GetDashboardAclInfoList(query); err != nil {
+		return err
+	}
+
+	ss.CacheService.Set(cacheKey, copyDashboardAclInfoList(query.Result), ss.Cfg.IdentityCacheTTL)
+	return nil
+}
+
+// InvalidateUserCache removes all cached lookups of the given user.
+func (ss *SQLStore) InvalidateUserCache(userID int64) {
+	ss.CacheService.Delete(newUserByIdCacheKey(userID))
+	ss.CacheService.Delete(newUserOrgListCacheKey(userID))
+	ss.deleteCacheKeysWithPrefix(fmt.Sprintf("%s%d-", signedInUserCachePrefix, userID))
+}
+
+// InvalidateOrgCache removes all cached lookups containing organization details.
+func (ss *SQLStore) InvalidateOrgCache() {
+	ss.deleteCacheKeysWithPrefix(signedInUserCachePrefix)
+	ss.deleteCacheKeysWithPrefix(userOrgListCachePrefix)
+}
+
+// InvalidateDashboardAclCache removes all cached dashboard permissions of the
+// given organization, since dashboards inherit the permissions of their folder.
+func (ss *SQLStore) InvalidateDashboardAclCache(orgID int64) {
+	ss.deleteCacheKeysWithPrefix(fmt.Sprintf("%s%d-", dashboardAclInfoCachePrefix, orgID))
+}
+
+func (ss *SQLStore) deleteCacheKeysWithPrefix(prefix string) {
+	for key := range ss.CacheService.Items() {
+		if strings.HasPrefix(key, prefix) {
+			ss.CacheService.Delete(key)
+		}
+	}
+}
+
+func (ss *SQLStore) onUserUpdated(evt *events.UserUpdated) error {
+	ss.InvalidateUserCache(evt.Id)
+	return nil
+}
+
+func (ss *SQLStore) onUserAccessChanged(evt *events.UserAccessChanged) error {
+	ss.InvalidateUserCache(evt.UserId)
+	return nil
+}
+
+func (ss *SQLStore) onUserCredentialsRevoked(evt *events.UserCredentialsRevoked) error {
+	ss.InvalidateUserCache(evt.UserId)
+	return nil
+}
+
+func (ss *SQLStore) onOrgUpdated(evt *events.OrgUpdated) error {
+	ss.InvalidateOrgCache()
+	return nil
+}
+
+func (ss *SQLStore) onDashboardAclUpdated(evt *events.DashboardAclUpdated) error {
+	ss.InvalidateDashboardAclCache(evt.OrgId)
+	return nil
+}
+
+func copyUserOrgList(list []*models.UserOrgDTO) []*models.UserOrgDTO {
+	result := make([]*models.UserOrgDTO, len(list))
+	for i, item := range list {
+		copied := *item
+		result[i] = &copied
+	}
+	return result
+}
+
+func copyDashboardAclInfoList(list []*models.DashboardAclInfoDTO) []*models.DashboardAclInfoDTO {
+	result := make([]*models.DashboardAclInfoDTO, len(list))
+	for i, item := range list {
+		copied := *item
+		result[i] = &copied
+	}
+	return result
+}
@@ -0,0 +1,125 @@
+// +build integration
+
+package sqlstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentityCache(t *testing.T) {
+	ss := InitTestDB(t)
+	ss.Cfg.IdentityCacheTTL = time.Minute
+	t.Cleanup(func() { ss.Cfg.IdentityCacheTTL = 0 })
+
+	user, err := ss.CreateUser(context.Background(), models.CreateUserCommand{
+		Email: "identity-cache@test.com",
+		Name:  "before",
+		Login: "identity_cache_login",
+	})
+	require.NoError(t, err)
+
+	rename := func(t *testing.T, name string) {
+		t.Helper()
+		_, err := x.Exec("UPDATE "+dialect.Quote("user")+" SET name = ? WHERE id = ?", name, user.Id)
+		require.NoError(t, err)
+	}
+
+	t.Run("serves user by id from cache until invalidated", func(t *testing.T) {
+		ss.CacheService.Flush()
+
+		query := &models.GetUserByIdQuery{Id: user.Id}
+		require.NoError(t, ss.GetUserByIdWithCacheCtx(context.Background(), query))
+		assert.Equal(t, "before", query.Result.Name)
+
+		// the cached entry can't be modified through the result
+		query.Result.Name = "modified"
+		rename(t, "after")
+		t.Cleanup(func() { rename(t, "before") })
+
+		query = &models.GetUserByIdQuery{Id: user.Id}
+		require.NoError(t, ss.GetUserByIdWithCacheCtx(context.Background(), query))
+		assert.Equal(t, "before", query.Result.Name)
+
+		ss.InvalidateUserCache(user.Id)
+
+		query = &models.GetUserByIdQuery{Id: user.Id}
+		require.NoError(t, ss.GetUserByIdWithCacheCtx(context.Background(), query))
+		assert.Equal(t, "after", query.Result.Name)
+	})
+
+	t.Run("invalidates signed in user of every organization", func(t *testing.T) {
+		ss.CacheService.Flush()
+
+		query := &models.GetSignedInUserQuery{UserId: user.Id, OrgId: user.OrgId}
+		require.NoError(t, ss.GetSignedInUserWithCacheCtx(context.Background(), query))
+		_, found := ss.CacheService.Get(newSignedInUserCacheKey(user.OrgId, user.Id))
+		require.True(t, found)
+
+		ss.InvalidateUserCache(user.Id)
+
+		_, found = ss.CacheService.Get(newSignedInUserCacheKey(user.OrgId, user.Id))
+		assert.False(t, found)
+	})
+
+	t.Run("invalidates users when they are updated or their credentials revoked", func(t *testing.T) {
+		cached := func() bool {
+			query := &models.GetUserByIdQuery{Id: user.Id}
+			require.NoError(t, ss.GetUserByIdWithCacheCtx(context.Background(), query))
+			_, found := ss.CacheService.Get(newUserByIdCacheKey(user.Id))
+			return found
+		}
+
+		ss.CacheService.Flush()
+		require.True(t, cached())
+		require.NoError(t, UpdateUser(&models.UpdateUserCommand{UserId: user.Id, Name: "before", Login: user.Login, Email: user.Email}))
+		_, found := ss.CacheService.Get(newUserByIdCacheKey(user.Id))
+		assert.False(t, found)
+
+		require.True(t, cached())
+		require.NoError(t, bus.Publish(&events.UserCredentialsRevoked{UserId: user.Id}))
+		_, found = ss.CacheService.Get(newUserByIdCacheKey(user.Id))
+		assert.False(t, found)
+	})
+
+	t.Run("does not cache lookups by login", func(t *testing.T) {
+		ss.CacheService.Flush()
+
+		query := &models.GetSignedInUserQuery{Login: user.Login, OrgId: user.OrgId}
+		require.NoError(t, ss.GetSignedInUserWithCacheCtx(context.Background(), query))
+		assert.Empty(t, ss.CacheService.Items())
+	})
+
+	t.Run("invalidates dashboard permissions per organization", func(t *testing.T) {
+		ss.CacheService.Flush()
+
+		query := &models.GetDashboardAclInfoListQuery{OrgID: user.OrgId, DashboardID: 1}
+		require.NoError(t, ss.GetDashboardAclInfoListWithCache(query))
+		otherOrgQuery := &models.GetDashboardAclInfoListQuery{OrgID: user.OrgId + 1, DashboardID: 1}
+		require.NoError(t, ss.GetDashboardAclInfoListWithCache(otherOrgQuery))
+
+		ss.InvalidateDashboardAclCache(user.OrgId)
+
+		_, found := ss.CacheService.Get(newDashboardAclInfoCacheKey(user.OrgId, 1))
+		assert.False(t, found)
+		_, found = ss.CacheService.Get(newDashboardAclInfoCacheKey(user.OrgId+1, 1))
+		assert.True(t, found)
+	})
+
+	t.Run("bypasses the cache when disabled", func(t *testing.T) {
+		ss.CacheService.Flush()
+		ss.Cfg.IdentityCacheTTL = 0
+		t.Cleanup(func() { ss.Cfg.IdentityCacheTTL = time.Minute })
+
+		query := &models.GetUserOrgListQuery{UserId: user.Id}
+		require.NoError(t, ss.GetUserOrgListWithCache(query))
+		assert.Empty(t, ss.CacheService.Items())
+	})
+}
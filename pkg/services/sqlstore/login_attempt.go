@@ -14,6 +14,7 @@ func init() {
 	bus.AddHandler("sql", CreateLoginAttempt)
 	bus.AddHandler("sql", DeleteOldLoginAttempts)
 	bus.AddHandler("sql", GetUserLoginAttemptCount)
+	bus.AddHandler("sql", GetIPLoginAttemptCount)
 }
 
 func CreateLoginAttempt(cmd *models.CreateLoginAttemptCommand) error {
@@ -79,6 +80,21 @@ func GetUserLoginAttemptCount(query *models.GetUserLoginAttemptCountQuery) error
 	return nil
 }
 
+func GetIPLoginAttemptCount(query *models.GetIPLoginAttemptCountQuery) error {
+	loginAttempt := new(models.LoginAttempt)
+	total, err := x.
+		Where("ip_address = ?", query.IpAddress).
+		And("created >= ?", query.Since.Unix()).
+		Count(loginAttempt)
+
+	if err != nil {
+		return err
+	}
+
+	query.Result = total
+	return nil
+}
+
 func toInt64(i interface{}) int64 {
 	switch i := i.(type) {
 	case []byte:
@@ -84,6 +84,21 @@ func TestLoginAttempts(t *testing.T) {
 			So(query.Result, ShouldEqual, 1)
 		})
 
+		Convey("Should return the total count of login attempts from an address since beginning of time + 1min", func() {
+			query := models.GetIPLoginAttemptCountQuery{
+				IpAddress: "192.168.0.1",
+				Since:     timePlusOneMinute,
+			}
+			err := GetIPLoginAttemptCount(&query)
+			So(err, ShouldBeNil)
+			So(query.Result, ShouldEqual, 2)
+
+			query.IpAddress = "192.168.0.2"
+			err = GetIPLoginAttemptCount(&query)
+			So(err, ShouldBeNil)
+			So(query.Result, ShouldEqual, 0)
+		})
+
 		Convey("Should return deleted rows older than beginning of time", func() {
 			cmd := models.DeleteOldLoginAttemptsCommand{
 				OlderThan: beginningOfTime,
@@ -0,0 +1,90 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addAccessControlMigrations(mg *Migrator) {
+	roleV1 := Table{
+		Name: "role",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "version", Type: DB_BigInt, Nullable: false},
+			{Name: "uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "name", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "description", Type: DB_Text, Nullable: true},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id"}},
+			{Cols: []string{"org_id", "uid"}, Type: UniqueIndex},
+			{Cols: []string{"org_id", "name"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create role table", NewAddTableMigration(roleV1))
+	mg.AddMigration("add index role.org_id", NewAddIndexMigration(roleV1, roleV1.Indices[0]))
+	mg.AddMigration("add unique index role_org_id_uid", NewAddIndexMigration(roleV1, roleV1.Indices[1]))
+	mg.AddMigration("add unique index role_org_id_name", NewAddIndexMigration(roleV1, roleV1.Indices[2]))
+
+	permissionV1 := Table{
+		Name: "permission",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "role_id", Type: DB_BigInt, Nullable: false},
+			{Name: "action", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "scope", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"role_id"}},
+			{Cols: []string{"role_id", "action", "scope"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create permission table", NewAddTableMigration(permissionV1))
+	mg.AddMigration("add index permission.role_id", NewAddIndexMigration(permissionV1, permissionV1.Indices[0]))
+	mg.AddMigration("add unique index permission_role_id_action_scope", NewAddIndexMigration(permissionV1, permissionV1.Indices[1]))
+
+	userRoleV1 := Table{
+		Name: "user_role",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "user_id", Type: DB_BigInt, Nullable: false},
+			{Name: "role_id", Type: DB_BigInt, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "user_id"}},
+			{Cols: []string{"org_id", "user_id", "role_id"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create user_role table", NewAddTableMigration(userRoleV1))
+	mg.AddMigration("add index user_role.org_id_user_id", NewAddIndexMigration(userRoleV1, userRoleV1.Indices[0]))
+	mg.AddMigration("add unique index user_role_org_id_user_id_role_id", NewAddIndexMigration(userRoleV1, userRoleV1.Indices[1]))
+
+	builtinRoleV1 := Table{
+		Name: "builtin_role",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "role", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "role_id", Type: DB_BigInt, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"role_id"}},
+			{Cols: []string{"org_id", "role"}},
+			{Cols: []string{"org_id", "role_id", "role"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create builtin_role table", NewAddTableMigration(builtinRoleV1))
+	mg.AddMigration("add index builtin_role.role_id", NewAddIndexMigration(builtinRoleV1, builtinRoleV1.Indices[0]))
+	mg.AddMigration("add index builtin_role.org_id_role", NewAddIndexMigration(builtinRoleV1, builtinRoleV1.Indices[1]))
+	mg.AddMigration("add unique index builtin_role_org_id_role_id_role", NewAddIndexMigration(builtinRoleV1, builtinRoleV1.Indices[2]))
+}
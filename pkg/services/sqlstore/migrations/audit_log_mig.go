@@ -0,0 +1,34 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addAuditLogMigrations(mg *Migrator) {
+	auditLogV1 := Table{
+		Name: "audit_log",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "action", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "success", Type: DB_Bool, Nullable: false},
+			{Name: "user_id", Type: DB_BigInt, Nullable: false},
+			{Name: "user_login", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "auth_module", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "ip_address", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "target", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "message", Type: DB_Text, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"created"}},
+			{Cols: []string{"user_id"}},
+			{Cols: []string{"action"}},
+		},
+	}
+
+	mg.AddMigration("create audit_log table", NewAddTableMigration(auditLogV1))
+	addTableIndicesMigrations(mg, "v1", auditLogV1)
+
+	mg.AddMigration("Add column impersonator_user_id to audit_log", NewAddColumnMigration(auditLogV1, &Column{
+		Name: "impersonator_user_id", Type: DB_BigInt, Nullable: true,
+	}))
+}
@@ -0,0 +1,23 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addDataKeysMigrations(mg *Migrator) {
+	dataKeysV1 := Table{
+		Name: "data_keys",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "name", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "scope", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "encrypted_data", Type: DB_Text, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"name"}, Type: UniqueIndex},
+			{Cols: []string{"scope"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create data_keys table", NewAddTableMigration(dataKeysV1))
+	addTableIndicesMigrations(mg, "v1", dataKeysV1)
+}
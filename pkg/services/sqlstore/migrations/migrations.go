@@ -9,8 +9,15 @@ import (
 // 1. Never change a migration that is committed and pushed to main
 // 2. Always add new migrations (to change or undo previous migrations)
 // 3. Some migrations are not yet written (rename column, table, drop table, index etc)
+// 4. Increase SchemaVersion when older versions of Grafana can no longer run against the migrated database
+
+// SchemaVersion is the version of the database schema of the migrations. Grafana
+// refuses to start against a database migrated to a newer schema version.
+const SchemaVersion = 1
 
 func AddMigrations(mg *Migrator) {
+	mg.SchemaVersion = SchemaVersion
+
 	addMigrationLogMigrations(mg)
 	addUserMigrations(mg)
 	addTempUserMigrations(mg)
@@ -42,6 +49,15 @@ func AddMigrations(mg *Migrator) {
 	ualert.AddDashAlertMigration(mg)
 	addLibraryElementsMigrations(mg)
 	ualert.RerunDashAlertMigration(mg)
+	addUserPasswordHistoryMigrations(mg)
+	addAccessControlMigrations(mg)
+	addAuditLogMigrations(mg)
+	addUserDeprovisioningMigrations(mg)
+	addSecretKeyRotationMigrations(mg)
+	addDataKeysMigrations(mg)
+	addNotificationQueueMigrations(mg)
+	addReportMigrations(mg)
+	addSchemaVersionMigrations(mg)
 }
 
 func addMigrationLogMigrations(mg *Migrator) {
@@ -60,6 +76,19 @@ func addMigrationLogMigrations(mg *Migrator) {
 	mg.AddMigration("create migration_log table", NewAddTableMigration(migrationLogV1))
 }
 
+func addSchemaVersionMigrations(mg *Migrator) {
+	schemaVersionV1 := Table{
+		Name: "schema_version",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "version", Type: DB_BigInt, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+	}
+
+	mg.AddMigration("create schema_version table", NewAddTableMigration(schemaVersionV1))
+}
+
 func addStarMigrations(mg *Migrator) {
 	starV1 := Table{
 		Name: "star",
@@ -48,3 +48,52 @@ func TestMigrations(t *testing.T) {
 	require.True(t, has)
 	require.Equal(t, expectedMigrations, result.Count)
 }
+
+func TestMigrationsDowngrade(t *testing.T) {
+	testDB := sqlutil.SQLite3TestDB()
+	const query = `select version from schema_version`
+	result := struct{ Version int64 }{}
+
+	x, err := xorm.NewEngine(testDB.DriverName, testDB.ConnStr)
+	require.NoError(t, err)
+
+	err = NewDialect(x).CleanDB()
+	require.NoError(t, err)
+
+	mg := NewMigrator(x, &setting.Cfg{})
+	AddMigrations(mg)
+	err = mg.Start()
+	require.NoError(t, err)
+
+	has, err := x.SQL(query).Get(&result)
+	require.NoError(t, err)
+	require.True(t, has)
+	require.Equal(t, int64(SchemaVersion), result.Version)
+
+	// an older version with fewer migrations but the same schema version
+	mg = NewMigrator(x, &setting.Cfg{})
+	mg.SchemaVersion = SchemaVersion
+	addMigrationLogMigrations(mg)
+	err = mg.Start()
+	require.NoError(t, err)
+
+	// an older version with an older schema version
+	mg = NewMigrator(x, &setting.Cfg{})
+	AddMigrations(mg)
+	mg.SchemaVersion = SchemaVersion - 1
+	err = mg.Start()
+	require.ErrorIs(t, err, ErrIncompatibleDowngrade)
+
+	mg = NewMigrator(x, &setting.Cfg{})
+	AddMigrations(mg)
+	mg.SchemaVersion = SchemaVersion - 1
+	mg.AllowDowngrade = true
+	err = mg.Start()
+	require.NoError(t, err)
+
+	// the schema version isn't lowered by an allowed downgrade
+	has, err = x.SQL(query).Get(&result)
+	require.NoError(t, err)
+	require.True(t, has)
+	require.Equal(t, int64(SchemaVersion), result.Version)
+}
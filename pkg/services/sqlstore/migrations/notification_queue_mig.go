@@ -0,0 +1,27 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addNotificationQueueMigrations(mg *Migrator) {
+	notificationQueueV1 := Table{
+		Name: "notification_queue",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "message", Type: DB_MediumText, Nullable: false},
+			{Name: "attempts", Type: DB_Int, Nullable: false},
+			{Name: "last_error", Type: DB_Text, Nullable: true},
+			{Name: "next_attempt", Type: DB_DateTime, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"next_attempt"}},
+		},
+	}
+
+	mg.AddMigration("create notification_queue table", NewAddTableMigration(notificationQueueV1))
+	addTableIndicesMigrations(mg, "v1", notificationQueueV1)
+
+	mg.AddMigration("Add column type to notification_queue", NewAddColumnMigration(notificationQueueV1, &Column{
+		Name: "type", Type: DB_NVarchar, Length: 20, Nullable: false, Default: "'email'",
+	}))
+}
@@ -0,0 +1,38 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addReportMigrations(mg *Migrator) {
+	reportV1 := Table{
+		Name: "report",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "name", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "schedule", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "timezone", Type: DB_NVarchar, Length: 50, Nullable: false},
+			{Name: "recipients", Type: DB_Text, Nullable: false},
+			{Name: "format", Type: DB_NVarchar, Length: 10, Nullable: false},
+			{Name: "paper_size", Type: DB_NVarchar, Length: 10, Nullable: false},
+			{Name: "orientation", Type: DB_NVarchar, Length: 10, Nullable: false},
+			{Name: "time_from", Type: DB_NVarchar, Length: 50, Nullable: false},
+			{Name: "time_to", Type: DB_NVarchar, Length: 50, Nullable: false},
+			{Name: "message", Type: DB_Text, Nullable: false},
+			{Name: "enabled", Type: DB_Bool, Nullable: false},
+			{Name: "created_by", Type: DB_BigInt, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+			{Name: "next_run", Type: DB_DateTime, Nullable: false},
+			{Name: "last_run", Type: DB_DateTime, Nullable: true},
+			{Name: "last_error", Type: DB_Text, Nullable: true},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id"}},
+			{Cols: []string{"enabled", "next_run"}},
+		},
+	}
+
+	mg.AddMigration("create report table", NewAddTableMigration(reportV1))
+	addTableIndicesMigrations(mg, "v1", reportV1)
+}
@@ -0,0 +1,23 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addSecretKeyRotationMigrations(mg *Migrator) {
+	secretKeyRotationV1 := Table{
+		Name: "secret_key_rotation",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "state", Type: DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "current_table", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "last_id", Type: DB_BigInt, Nullable: false},
+			{Name: "started", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"state"}},
+		},
+	}
+
+	mg.AddMigration("create secret_key_rotation table", NewAddTableMigration(secretKeyRotationV1))
+	addTableIndicesMigrations(mg, "v1", secretKeyRotationV1)
+}
@@ -113,7 +113,7 @@ func (m *migration) makeReceiverAndRoute(ruleUid string, orgID int64, channelUid
 
 	addChannel := func(c *notificationChannel) error {
 		if c.Type == "hipchat" || c.Type == "sensu" {
-			m.mg.Logger.Error("alert migration error: discontinued notification channel found", "type", c.Type, "name", c.Name, "uid", c.Uid)
+			m.reportDiscontinuedChannel(c)
 			return nil
 		}
 
@@ -263,6 +263,8 @@ func (m *migration) addDefaultChannels(amConfigsPerOrg amConfigsPerOrg, allChann
 		}
 		if route != nil {
 			route.Matchers = nil // Don't need matchers for root route.
+			// Group the alerts of each alert rule in a single notification.
+			route.GroupByStr = []string{"alertname"}
 			amConfigsPerOrg[orgID].AlertmanagerConfig.Route = route
 		}
 	}
@@ -284,7 +286,7 @@ func (m *migration) addUnmigratedChannels(orgID int64, amConfigs *PostableUserCo
 			continue
 		}
 		if c.Type == "hipchat" || c.Type == "sensu" {
-			m.mg.Logger.Error("alert migration error: discontinued notification channel found", "type", c.Type, "name", c.Name, "uid", c.Uid)
+			m.reportDiscontinuedChannel(c)
 			continue
 		}
 
@@ -348,6 +350,8 @@ func migrateSettingsToSecureSettings(chanType string, settings *simplejson.Json,
 		keys = []string{"apiToken", "userKey"}
 	case "threema":
 		keys = []string{"api_secret"}
+	case "victorops":
+		keys = []string{"url"}
 	}
 
 	ss := secureSettings.Decrypt()
@@ -419,9 +423,10 @@ type PostableApiAlertingConfig struct {
 }
 
 type Route struct {
-	Receiver string   `yaml:"receiver,omitempty" json:"receiver,omitempty"`
-	Matchers Matchers `yaml:"matchers,omitempty" json:"matchers,omitempty"`
-	Routes   []*Route `yaml:"routes,omitempty" json:"routes,omitempty"`
+	Receiver   string   `yaml:"receiver,omitempty" json:"receiver,omitempty"`
+	GroupByStr []string `yaml:"group_by,omitempty" json:"group_by,omitempty"`
+	Matchers   Matchers `yaml:"matchers,omitempty" json:"matchers,omitempty"`
+	Routes     []*Route `yaml:"routes,omitempty" json:"routes,omitempty"`
 }
 
 type Matchers labels.Matchers
@@ -0,0 +1,211 @@
+package ualert
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"xorm.io/xorm"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// ErrOrgAlreadyMigrated is returned when an alert rule of the organization was already migrated from a dashboard alert.
+var ErrOrgAlreadyMigrated = errors.New("the dashboard alerts of the organization are already migrated")
+
+// OrgMigrationReport is the result of the migration of the dashboard alerts and the notification channels
+// of an organization.
+type OrgMigrationReport struct {
+	MigratedAlerts     []MigratedAlert
+	UnmigratedAlerts   []UnmigratedAlert
+	UnmigratedChannels []UnmigratedChannel
+	// ContactPoints are the names of the receivers of the migrated notification channels.
+	ContactPoints []string
+	// AlertmanagerConfig is the Alertmanager configuration with the receivers and the routes of the notification
+	// channels, with their secure settings in plain text. It is nil when the organization has no notification channels.
+	AlertmanagerConfig json.RawMessage
+}
+
+// MigratedAlert is a dashboard alert that was migrated to an alert rule.
+type MigratedAlert struct {
+	AlertID      int64
+	DashboardUID string
+	PanelID      int64
+	Name         string
+	RuleUID      string
+	FolderUID    string
+	// Paused is set when the dashboard alert was paused, the alert rule is then paused too.
+	Paused bool
+}
+
+// UnmigratedAlert is a dashboard alert that can't be migrated.
+type UnmigratedAlert struct {
+	AlertID      int64
+	DashboardUID string
+	PanelID      int64
+	Name         string
+	Error        string
+}
+
+// UnmigratedChannel is a notification channel that can't be migrated.
+type UnmigratedChannel struct {
+	UID   string
+	Name  string
+	Type  string
+	Error string
+}
+
+// MigrateOrg migrates the dashboard alerts and the notification channels of an organization in the given session,
+// like the migration that runs when unified alerting is enabled, while Grafana is running. The dashboard alerts that
+// can't be migrated are reported instead of failing the migration. The alert rules are saved in the session, and
+// the Alertmanager configuration is returned in the report to be applied by the caller.
+func MigrateOrg(sess *xorm.Session, dialect migrator.Dialect, cfg *setting.Cfg, orgID int64) (*OrgMigrationReport, error) {
+	m := &migration{
+		sess: sess,
+		mg: &migrator.Migrator{
+			Dialect: dialect,
+			Logger:  log.New("ngalert.migration"),
+			Cfg:     cfg,
+		},
+		seenChannelUIDs:           make(map[string]struct{}),
+		migratedChannelsPerOrg:    make(map[int64]map[*notificationChannel]struct{}),
+		portedChannelGroupsPerOrg: make(map[int64]map[string]string),
+		report:                    &OrgMigrationReport{},
+	}
+
+	if err := m.checkOrgNotMigrated(orgID); err != nil {
+		return nil, err
+	}
+
+	allDashAlerts, err := m.slurpDashAlerts()
+	if err != nil {
+		return nil, err
+	}
+	dsIDMap, err := m.slurpDSIDs()
+	if err != nil {
+		return nil, err
+	}
+	dashIDMap, err := m.slurpDashUIDs()
+	if err != nil {
+		return nil, err
+	}
+	allChannelsPerOrg, defaultChannelsPerOrg, err := m.getNotificationChannelMap()
+	if err != nil {
+		return nil, err
+	}
+	// only the channels of the organization are migrated
+	channels := channelsPerOrg{}
+	if c, ok := allChannelsPerOrg[orgID]; ok {
+		channels[orgID] = c
+	}
+
+	amConfigPerOrg := make(amConfigsPerOrg, 1)
+	if err := m.addDefaultChannels(amConfigPerOrg, channels, defaultChannelsPerOrg); err != nil {
+		return nil, err
+	}
+
+	for _, da := range allDashAlerts {
+		if da.OrgId != orgID {
+			continue
+		}
+		rule, err := m.migrateAlert(da, dsIDMap, dashIDMap, channels, defaultChannelsPerOrg, amConfigPerOrg)
+		if err != nil {
+			var migrationErr MigrationError
+			if errors.As(err, &migrationErr) {
+				err = migrationErr.Err
+			}
+			m.report.UnmigratedAlerts = append(m.report.UnmigratedAlerts, UnmigratedAlert{
+				AlertID:      da.Id,
+				DashboardUID: dashIDMap[[2]int64{da.OrgId, da.DashboardId}],
+				PanelID:      da.PanelId,
+				Name:         da.Name,
+				Error:        err.Error(),
+			})
+			continue
+		}
+
+		// the silences of the migration are only loaded when the Alertmanager starts, so the rule is paused instead
+		paused := da.State == "paused"
+		if paused {
+			if err := m.pauseRule(rule); err != nil {
+				return nil, err
+			}
+		}
+		m.report.MigratedAlerts = append(m.report.MigratedAlerts, MigratedAlert{
+			AlertID:      da.Id,
+			DashboardUID: rule.Annotations["__dashboardUid__"],
+			PanelID:      da.PanelId,
+			Name:         da.Name,
+			RuleUID:      rule.UID,
+			FolderUID:    rule.NamespaceUID,
+			Paused:       paused,
+		})
+	}
+
+	amConfig, ok := amConfigPerOrg[orgID]
+	if !ok {
+		return m.report, nil
+	}
+	if err := m.addUnmigratedChannels(orgID, amConfig, channels[orgID], defaultChannelsPerOrg[orgID]); err != nil {
+		return nil, err
+	}
+	for _, r := range amConfig.AlertmanagerConfig.Receivers {
+		m.report.ContactPoints = append(m.report.ContactPoints, r.Name)
+	}
+	if m.report.AlertmanagerConfig, err = json.Marshal(amConfig); err != nil {
+		return nil, err
+	}
+	return m.report, nil
+}
+
+// checkOrgNotMigrated returns ErrOrgAlreadyMigrated when an alert rule of the organization has the
+// annotation of the migrated dashboard alerts.
+func (m *migration) checkOrgNotMigrated(orgID int64) error {
+	var annotations []string
+	if err := m.sess.Table("alert_rule").Where("org_id = ?", orgID).Cols("annotations").Find(&annotations); err != nil {
+		return err
+	}
+	for _, a := range annotations {
+		var parsed map[string]string
+		if err := json.Unmarshal([]byte(a), &parsed); err != nil {
+			continue
+		}
+		if _, ok := parsed["__alertId__"]; ok {
+			return ErrOrgAlreadyMigrated
+		}
+	}
+	return nil
+}
+
+func (m *migration) pauseRule(rule *alertRule) error {
+	for _, q := range []string{
+		"UPDATE alert_rule SET is_paused = ? WHERE org_id = ? AND uid = ?",
+		"UPDATE alert_rule_version SET is_paused = ? WHERE rule_org_id = ? AND rule_uid = ?",
+	} {
+		if _, err := m.sess.Exec(q, true, rule.OrgID, rule.UID); err != nil {
+			return fmt.Errorf("failed to pause alert rule %s: %w", rule.UID, err)
+		}
+	}
+	return nil
+}
+
+// reportDiscontinuedChannel reports a notification channel of a type that unified alerting doesn't support.
+func (m *migration) reportDiscontinuedChannel(c *notificationChannel) {
+	m.mg.Logger.Error("alert migration error: discontinued notification channel found", "type", c.Type, "name", c.Name, "uid", c.Uid)
+	if m.report == nil {
+		return
+	}
+	for _, u := range m.report.UnmigratedChannels {
+		if u.UID == c.Uid && u.Name == c.Name {
+			return
+		}
+	}
+	m.report.UnmigratedChannels = append(m.report.UnmigratedChannels, UnmigratedChannel{
+		UID:   c.Uid,
+		Name:  c.Name,
+		Type:  c.Type,
+		Error: fmt.Sprintf("the %s notification channel is discontinued", c.Type),
+	})
+}
@@ -22,6 +22,18 @@ func AddTablesMigrations(mg *migrator.Migrator) {
 
 	// Create Admin Configuration
 	AddAlertAdminConfigMigrations(mg)
+
+	// Create alert_state_history
+	AddAlertStateHistoryMigrations(mg)
+
+	// Create alert_scheduler_peer
+	AddAlertSchedulerPeerMigrations(mg)
+
+	// Create alert_notification_dead_letter
+	AddNotificationDeadLetterMigrations(mg)
+
+	// Create recorded_query
+	AddRecordedQueryMigrations(mg)
 }
 
 // AddAlertDefinitionMigrations should not be modified.
@@ -150,6 +162,19 @@ func AlertInstanceMigration(mg *migrator.Migrator) {
 	mg.AddMigration("add index rule_org_id, current_state on alert_instance", migrator.NewAddIndexMigration(alertInstance, &migrator.Index{
 		Cols: []string{"rule_org_id", "current_state"}, Type: migrator.IndexType,
 	}))
+
+	mg.AddMigration("add column acknowledged_by to alert_instance", migrator.NewAddColumnMigration(alertInstance, &migrator.Column{
+		Name: "acknowledged_by", Type: migrator.DB_NVarchar, Length: 190, Nullable: true,
+	}))
+	mg.AddMigration("add column acknowledged_at to alert_instance", migrator.NewAddColumnMigration(alertInstance, &migrator.Column{
+		Name: "acknowledged_at", Type: migrator.DB_BigInt, Nullable: false, Default: "0",
+	}))
+	mg.AddMigration("add column escalation_step to alert_instance", migrator.NewAddColumnMigration(alertInstance, &migrator.Column{
+		Name: "escalation_step", Type: migrator.DB_Int, Nullable: false, Default: "0",
+	}))
+	mg.AddMigration("add column annotation_id to alert_instance", migrator.NewAddColumnMigration(alertInstance, &migrator.Column{
+		Name: "annotation_id", Type: migrator.DB_BigInt, Nullable: false, Default: "0",
+	}))
 }
 
 func AddAlertRuleMigrations(mg *migrator.Migrator, defaultIntervalSeconds int64) {
@@ -204,6 +229,19 @@ func AddAlertRuleMigrations(mg *migrator.Migrator, defaultIntervalSeconds int64)
 	mg.AddMigration("add index in alert_rule on org_id, namespase_uid and title columns", migrator.NewAddIndexMigration(alertRule, &migrator.Index{
 		Cols: []string{"org_id", "namespace_uid", "title"}, Type: migrator.UniqueIndex,
 	}))
+
+	// add is_paused column
+	mg.AddMigration("add column is_paused to alert_rule", migrator.NewAddColumnMigration(alertRule, &migrator.Column{Name: "is_paused", Type: migrator.DB_Bool, Nullable: false, Default: "0"}))
+
+	// add offset_seconds column
+	mg.AddMigration("add column offset_seconds to alert_rule", migrator.NewAddColumnMigration(alertRule, &migrator.Column{Name: "offset_seconds", Type: migrator.DB_BigInt, Nullable: false, Default: "0"}))
+
+	// add no_data_threshold and exec_err_threshold columns
+	mg.AddMigration("add column no_data_threshold to alert_rule", migrator.NewAddColumnMigration(alertRule, &migrator.Column{Name: "no_data_threshold", Type: migrator.DB_Int, Nullable: false, Default: "0"}))
+	mg.AddMigration("add column exec_err_threshold to alert_rule", migrator.NewAddColumnMigration(alertRule, &migrator.Column{Name: "exec_err_threshold", Type: migrator.DB_Int, Nullable: false, Default: "0"}))
+
+	// add disable_dashboard_annotations column
+	mg.AddMigration("add column disable_dashboard_annotations to alert_rule", migrator.NewAddColumnMigration(alertRule, &migrator.Column{Name: "disable_dashboard_annotations", Type: migrator.DB_Bool, Nullable: false, Default: "0"}))
 }
 
 func AddAlertRuleVersionMigrations(mg *migrator.Migrator) {
@@ -247,6 +285,19 @@ func AddAlertRuleVersionMigrations(mg *migrator.Migrator) {
 
 	// add labels column
 	mg.AddMigration("add column labels to alert_rule_version", migrator.NewAddColumnMigration(alertRuleVersion, &migrator.Column{Name: "labels", Type: migrator.DB_Text, Nullable: true}))
+
+	// add is_paused column
+	mg.AddMigration("add column is_paused to alert_rule_version", migrator.NewAddColumnMigration(alertRuleVersion, &migrator.Column{Name: "is_paused", Type: migrator.DB_Bool, Nullable: false, Default: "0"}))
+
+	// add offset_seconds column
+	mg.AddMigration("add column offset_seconds to alert_rule_version", migrator.NewAddColumnMigration(alertRuleVersion, &migrator.Column{Name: "offset_seconds", Type: migrator.DB_BigInt, Nullable: false, Default: "0"}))
+
+	// add no_data_threshold and exec_err_threshold columns
+	mg.AddMigration("add column no_data_threshold to alert_rule_version", migrator.NewAddColumnMigration(alertRuleVersion, &migrator.Column{Name: "no_data_threshold", Type: migrator.DB_Int, Nullable: false, Default: "0"}))
+	mg.AddMigration("add column exec_err_threshold to alert_rule_version", migrator.NewAddColumnMigration(alertRuleVersion, &migrator.Column{Name: "exec_err_threshold", Type: migrator.DB_Int, Nullable: false, Default: "0"}))
+
+	// add disable_dashboard_annotations column
+	mg.AddMigration("add column disable_dashboard_annotations to alert_rule_version", migrator.NewAddColumnMigration(alertRuleVersion, &migrator.Column{Name: "disable_dashboard_annotations", Type: migrator.DB_Bool, Nullable: false, Default: "0"}))
 }
 
 func AddAlertmanagerConfigMigrations(mg *migrator.Migrator) {
@@ -296,4 +347,103 @@ func AddAlertAdminConfigMigrations(mg *migrator.Migrator) {
 
 	mg.AddMigration("create_ngalert_configuration_table", migrator.NewAddTableMigration(adminConfiguration))
 	mg.AddMigration("add index in ngalert_configuration on org_id column", migrator.NewAddIndexMigration(adminConfiguration, adminConfiguration.Indices[0]))
+	mg.AddMigration("add column send_alerts_to in ngalert_configuration", migrator.NewAddColumnMigration(adminConfiguration, &migrator.Column{
+		Name: "send_alerts_to", Type: migrator.DB_SmallInt, Nullable: false, Default: "0",
+	}))
+}
+
+func AddAlertStateHistoryMigrations(mg *migrator.Migrator) {
+	alertStateHistory := migrator.Table{
+		Name: "alert_state_history",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "rule_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "labels", Type: migrator.DB_Text, Nullable: false},
+			{Name: "labels_hash", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "previous_state", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "current_state", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "evaluation_values", Type: migrator.DB_Text, Nullable: true},
+			{Name: "evaluation_string", Type: migrator.DB_Text, Nullable: true},
+			{Name: "evaluation_error", Type: migrator.DB_Text, Nullable: true},
+			{Name: "epoch", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "rule_uid", "labels_hash"}, Type: migrator.IndexType},
+			{Cols: []string{"org_id", "epoch"}, Type: migrator.IndexType},
+			{Cols: []string{"epoch"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_state_history table", migrator.NewAddTableMigration(alertStateHistory))
+	mg.AddMigration("add index in alert_state_history on org_id, rule_uid and labels_hash columns", migrator.NewAddIndexMigration(alertStateHistory, alertStateHistory.Indices[0]))
+	mg.AddMigration("add index in alert_state_history on org_id and epoch columns", migrator.NewAddIndexMigration(alertStateHistory, alertStateHistory.Indices[1]))
+	mg.AddMigration("add index in alert_state_history on epoch column", migrator.NewAddIndexMigration(alertStateHistory, alertStateHistory.Indices[2]))
+}
+
+func AddAlertSchedulerPeerMigrations(mg *migrator.Migrator) {
+	alertSchedulerPeer := migrator.Table{
+		Name: "alert_scheduler_peer",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "peer_id", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "last_heartbeat", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"peer_id"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create alert_scheduler_peer table", migrator.NewAddTableMigration(alertSchedulerPeer))
+	mg.AddMigration("add unique index in alert_scheduler_peer on peer_id column", migrator.NewAddIndexMigration(alertSchedulerPeer, alertSchedulerPeer.Indices[0]))
+}
+
+func AddNotificationDeadLetterMigrations(mg *migrator.Migrator) {
+	deadLetter := migrator.Table{
+		Name: "alert_notification_dead_letter",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "receiver", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "integration_name", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "integration_type", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "alerts", Type: migrator.DB_Text, Nullable: false},
+			{Name: "attempts", Type: migrator.DB_Int, Nullable: false},
+			{Name: "error", Type: migrator.DB_Text, Nullable: false},
+			{Name: "epoch", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "epoch"}, Type: migrator.IndexType},
+			{Cols: []string{"epoch"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_notification_dead_letter table", migrator.NewAddTableMigration(deadLetter))
+	mg.AddMigration("add index in alert_notification_dead_letter on org_id and epoch columns", migrator.NewAddIndexMigration(deadLetter, deadLetter.Indices[0]))
+	mg.AddMigration("add index in alert_notification_dead_letter on epoch column", migrator.NewAddIndexMigration(deadLetter, deadLetter.Indices[1]))
+}
+
+func AddRecordedQueryMigrations(mg *migrator.Migrator) {
+	recordedQuery := migrator.Table{
+		Name: "recorded_query",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "name", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "description", Type: migrator.DB_Text, Nullable: false},
+			{Name: "data", Type: migrator.DB_MediumText, Nullable: false},
+			{Name: "target", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "interval_seconds", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "labels", Type: migrator.DB_Text, Nullable: true},
+			{Name: "active", Type: migrator.DB_Bool, Nullable: false},
+			{Name: "updated", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "uid"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create recorded_query table", migrator.NewAddTableMigration(recordedQuery))
+	mg.AddMigration("add unique index in recorded_query on org_id and uid columns", migrator.NewAddIndexMigration(recordedQuery, recordedQuery.Indices[0]))
 }
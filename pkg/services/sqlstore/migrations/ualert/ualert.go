@@ -153,6 +153,9 @@ type migration struct {
 	silences                  []*pb.MeshSilence
 	portedChannelGroupsPerOrg map[int64]map[string]string // Org -> Channel group key -> receiver name.
 	lastReceiverID            int                         // For the auto generated receivers.
+
+	// report is set when the dashboard alerts of an organization are migrated on demand.
+	report *OrgMigrationReport
 }
 
 func (m *migration) SQL(dialect migrator.Dialect) string {
@@ -193,142 +196,174 @@ func (m *migration) Exec(sess *xorm.Session, mg *migrator.Migrator) error {
 	}
 
 	for _, da := range dashAlerts {
-		newCond, err := transConditions(*da.ParsedSettings, da.OrgId, dsIDMap)
+		if _, err := m.migrateAlert(da, dsIDMap, dashIDMap, allChannelsPerOrg, defaultChannelsPerOrg, amConfigPerOrg); err != nil {
+			return err
+		}
+	}
+
+	for orgID, amConfig := range amConfigPerOrg {
+		// Create a separate receiver for all the unmigrated channels.
+		err = m.addUnmigratedChannels(orgID, amConfig, allChannelsPerOrg[orgID], defaultChannelsPerOrg[orgID])
 		if err != nil {
 			return err
 		}
 
-		da.DashboardUID = dashIDMap[[2]int64{da.OrgId, da.DashboardId}]
+		if err := m.writeAlertmanagerConfig(orgID, amConfig, allChannelsPerOrg[orgID]); err != nil {
+			return err
+		}
 
-		// get dashboard
-		dash := dashboard{}
-		exists, err := m.sess.Where("org_id=? AND uid=?", da.OrgId, da.DashboardUID).Get(&dash)
-		if err != nil {
-			return MigrationError{
-				Err:     fmt.Errorf("failed to get dashboard %s under organisation %d: %w", da.DashboardUID, da.OrgId, err),
-				AlertId: da.Id,
-			}
+		if err := m.writeSilencesFile(orgID); err != nil {
+			m.mg.Logger.Error("alert migration error: failed to write silence file", "err", err)
 		}
-		if !exists {
-			return MigrationError{
-				Err:     fmt.Errorf("dashboard with UID %v under organisation %d not found: %w", da.DashboardUID, da.OrgId, err),
-				AlertId: da.Id,
-			}
+	}
+
+	return nil
+}
+
+// migrateAlert migrates a dashboard alert to an alert rule of the folder of its dashboard, and adds
+// the receiver and the route of its notification channels to the Alertmanager configuration of its organization.
+func (m *migration) migrateAlert(da dashAlert, dsIDMap dsUIDLookup, dashIDMap map[[2]int64]string, allChannelsPerOrg channelsPerOrg, defaultChannelsPerOrg defaultChannelsPerOrg, amConfigPerOrg amConfigsPerOrg) (*alertRule, error) {
+	newCond, err := transConditions(*da.ParsedSettings, da.OrgId, dsIDMap)
+	if err != nil {
+		return nil, err
+	}
+
+	da.DashboardUID = dashIDMap[[2]int64{da.OrgId, da.DashboardId}]
+
+	// get dashboard
+	dash := dashboard{}
+	exists, err := m.sess.Where("org_id=? AND uid=?", da.OrgId, da.DashboardUID).Get(&dash)
+	if err != nil {
+		return nil, MigrationError{
+			Err:     fmt.Errorf("failed to get dashboard %s under organisation %d: %w", da.DashboardUID, da.OrgId, err),
+			AlertId: da.Id,
+		}
+	}
+	if !exists {
+		return nil, MigrationError{
+			Err:     fmt.Errorf("dashboard with UID %v under organisation %d not found: %w", da.DashboardUID, da.OrgId, err),
+			AlertId: da.Id,
 		}
+	}
 
-		// get folder if exists
-		folder, err := m.getFolder(dash, da)
+	// get folder if exists
+	folder, err := m.getFolder(dash, da)
+	if err != nil {
+		return nil, MigrationError{
+			Err:     err,
+			AlertId: da.Id,
+		}
+	}
+
+	switch {
+	case dash.HasAcl:
+		// create folder and assign the permissions of the dashboard (included default and inherited)
+		ptr, err := m.createFolder(dash.OrgId, fmt.Sprintf(DASHBOARD_FOLDER, getMigrationString(da)))
 		if err != nil {
-			return MigrationError{
-				Err:     err,
+			return nil, MigrationError{
+				Err:     fmt.Errorf("failed to create folder: %w", err),
 				AlertId: da.Id,
 			}
 		}
-
-		switch {
-		case dash.HasAcl:
-			// create folder and assign the permissions of the dashboard (included default and inherited)
-			ptr, err := m.createFolder(dash.OrgId, fmt.Sprintf(DASHBOARD_FOLDER, getMigrationString(da)))
-			if err != nil {
-				return MigrationError{
-					Err:     fmt.Errorf("failed to create folder: %w", err),
-					AlertId: da.Id,
-				}
-			}
-			folder = *ptr
-			permissions, err := m.getACL(dash.OrgId, dash.Id)
-			if err != nil {
-				return MigrationError{
-					Err:     fmt.Errorf("failed to get dashboard %d under organisation %d permissions: %w", dash.Id, dash.OrgId, err),
-					AlertId: da.Id,
-				}
-			}
-			err = m.setACL(folder.OrgId, folder.Id, permissions)
-			if err != nil {
-				return MigrationError{
-					Err:     fmt.Errorf("failed to set folder %d under organisation %d permissions: %w", folder.Id, folder.OrgId, err),
-					AlertId: da.Id,
-				}
-			}
-		case dash.FolderId > 0:
-			// link the new rule to the existing folder
-		default:
-			// get or create general folder
-			ptr, err := m.getOrCreateGeneralFolder(dash.OrgId)
-			if err != nil {
-				return MigrationError{
-					Err:     fmt.Errorf("failed to get or create general folder under organisation %d: %w", dash.OrgId, err),
-					AlertId: da.Id,
-				}
+		folder = *ptr
+		permissions, err := m.getACL(dash.OrgId, dash.Id)
+		if err != nil {
+			return nil, MigrationError{
+				Err:     fmt.Errorf("failed to get dashboard %d under organisation %d permissions: %w", dash.Id, dash.OrgId, err),
+				AlertId: da.Id,
 			}
-			// No need to assign default permissions to general folder
-			// because they are included to the query result if it's a folder with no permissions
-			// https://github.com/grafana/grafana/blob/076e2ce06a6ecf15804423fcc8dca1b620a321e5/pkg/services/sqlstore/dashboard_acl.go#L109
-			folder = *ptr
 		}
-
-		if folder.Uid == "" {
-			return MigrationError{
-				Err:     fmt.Errorf("empty folder identifier"),
+		err = m.setACL(folder.OrgId, folder.Id, permissions)
+		if err != nil {
+			return nil, MigrationError{
+				Err:     fmt.Errorf("failed to set folder %d under organisation %d permissions: %w", folder.Id, folder.OrgId, err),
 				AlertId: da.Id,
 			}
 		}
-		rule, err := m.makeAlertRule(*newCond, da, folder.Uid)
+	case dash.FolderId > 0:
+		// link the new rule to the existing folder
+	default:
+		// get or create general folder
+		ptr, err := m.getOrCreateGeneralFolder(dash.OrgId)
 		if err != nil {
-			return err
-		}
-
-		if _, ok := amConfigPerOrg[rule.OrgID]; !ok {
-			m.mg.Logger.Info("no configuration found", "org", rule.OrgID)
-		} else {
-			if err := m.updateReceiverAndRoute(allChannelsPerOrg, defaultChannelsPerOrg, da, rule, amConfigPerOrg[rule.OrgID]); err != nil {
-				return err
+			return nil, MigrationError{
+				Err:     fmt.Errorf("failed to get or create general folder under organisation %d: %w", dash.OrgId, err),
+				AlertId: da.Id,
 			}
 		}
+		// No need to assign default permissions to general folder
+		// because they are included to the query result if it's a folder with no permissions
+		// https://github.com/grafana/grafana/blob/076e2ce06a6ecf15804423fcc8dca1b620a321e5/pkg/services/sqlstore/dashboard_acl.go#L109
+		folder = *ptr
+	}
 
-		if strings.HasPrefix(mg.Dialect.DriverName(), migrator.Postgres) {
-			err = mg.InTransaction(func(sess *xorm.Session) error {
-				_, err = sess.Insert(rule)
-				return err
-			})
-		} else {
-			_, err = m.sess.Insert(rule)
+	if folder.Uid == "" {
+		return nil, MigrationError{
+			Err:     fmt.Errorf("empty folder identifier"),
+			AlertId: da.Id,
 		}
-		if err != nil {
-			// TODO better error handling, if constraint
-			rule.Title += fmt.Sprintf(" %v", rule.UID)
-			rule.RuleGroup += fmt.Sprintf(" %v", rule.UID)
+	}
+	rule, err := m.makeAlertRule(*newCond, da, folder.Uid)
+	if err != nil {
+		return nil, err
+	}
 
-			_, err = m.sess.Insert(rule)
-			if err != nil {
-				return err
-			}
+	if _, ok := amConfigPerOrg[rule.OrgID]; !ok {
+		m.mg.Logger.Info("no configuration found", "org", rule.OrgID)
+	} else {
+		if err := m.updateReceiverAndRoute(allChannelsPerOrg, defaultChannelsPerOrg, da, rule, amConfigPerOrg[rule.OrgID]); err != nil {
+			return nil, err
 		}
+	}
 
-		// create entry in alert_rule_version
-		_, err = m.sess.Insert(rule.makeVersion())
-		if err != nil {
-			return err
-		}
+	if err := m.insertRule(rule); err != nil {
+		return nil, err
 	}
+	return rule, nil
+}
 
-	for orgID, amConfig := range amConfigPerOrg {
-		// Create a separate receiver for all the unmigrated channels.
-		err = m.addUnmigratedChannels(orgID, amConfig, allChannelsPerOrg[orgID], defaultChannelsPerOrg[orgID])
+// insertRule inserts the alert rule and its first version. When the title of the rule is already used in
+// its folder, the UID of the rule is appended to its title and to its rule group.
+func (m *migration) insertRule(rule *alertRule) error {
+	var err error
+	switch {
+	case m.report != nil:
+		// the session is a transaction of the caller, which can't recover from a failed insert on Postgres
+		var exists bool
+		exists, err = m.sess.Table("alert_rule").Where("org_id = ? AND namespace_uid = ? AND title = ?", rule.OrgID, rule.NamespaceUID, rule.Title).Exist()
 		if err != nil {
 			return err
 		}
-
-		if err := m.writeAlertmanagerConfig(orgID, amConfig, allChannelsPerOrg[orgID]); err != nil {
+		if exists {
+			rule.Title += fmt.Sprintf(" %v", rule.UID)
+			rule.RuleGroup += fmt.Sprintf(" %v", rule.UID)
+		}
+		_, err = m.sess.Insert(rule)
+	case strings.HasPrefix(m.mg.Dialect.DriverName(), migrator.Postgres):
+		err = m.mg.InTransaction(func(sess *xorm.Session) error {
+			_, err = sess.Insert(rule)
+			return err
+		})
+	default:
+		_, err = m.sess.Insert(rule)
+	}
+	if err != nil {
+		if m.report != nil {
 			return err
 		}
+		// TODO better error handling, if constraint
+		rule.Title += fmt.Sprintf(" %v", rule.UID)
+		rule.RuleGroup += fmt.Sprintf(" %v", rule.UID)
 
-		if err := m.writeSilencesFile(orgID); err != nil {
-			m.mg.Logger.Error("alert migration error: failed to write silence file", "err", err)
+		_, err = m.sess.Insert(rule)
+		if err != nil {
+			return err
 		}
 	}
 
-	return nil
+	// create entry in alert_rule_version
+	_, err = m.sess.Insert(rule.makeVersion())
+	return err
 }
 
 func (m *migration) writeAlertmanagerConfig(orgID int64, amConfig *PostableUserConfig, allChannels map[interface{}]*notificationChannel) error {
@@ -410,6 +445,11 @@ func (m *rmMigration) Exec(sess *xorm.Session, mg *migrator.Migrator) error {
 		return err
 	}
 
+	_, err = sess.Exec("delete from alert_state_history")
+	if err != nil {
+		return err
+	}
+
 	files, err := getSilenceFileNamesForAllOrgs(mg)
 	if err != nil {
 		return err
@@ -44,4 +44,16 @@ func addUserAuthTokenMigrations(mg *Migrator) {
 			},
 		),
 	)
+
+	mg.AddMigration(
+		"Add impersonator_user_id to the user auth token",
+		NewAddColumnMigration(
+			userAuthTokenV1,
+			&Column{
+				Name:     "impersonator_user_id",
+				Type:     DB_BigInt,
+				Nullable: true,
+			},
+		),
+	)
 }
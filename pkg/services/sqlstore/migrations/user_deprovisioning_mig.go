@@ -0,0 +1,21 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addUserDeprovisioningMigrations(mg *Migrator) {
+	userDeprovisioningV1 := Table{
+		Name: "user_deprovisioning",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "user_id", Type: DB_BigInt, Nullable: false},
+			{Name: "auth_module", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "missing_since", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"user_id"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create user_deprovisioning table", NewAddTableMigration(userDeprovisioningV1))
+	addTableIndicesMigrations(mg, "v1", userDeprovisioningV1)
+}
@@ -1,6 +1,7 @@
 package migrator
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -13,12 +14,22 @@ import (
 	"xorm.io/xorm"
 )
 
+// ErrIncompatibleDowngrade is returned when the database was migrated by a
+// newer version of Grafana than the running one.
+var ErrIncompatibleDowngrade = errors.New("database schema is newer than this version of Grafana")
+
 type Migrator struct {
 	x          *xorm.Engine
 	Dialect    Dialect
 	migrations []Migration
 	Logger     log.Logger
 	Cfg        *setting.Cfg
+
+	// SchemaVersion is the version of the database schema of the migrations,
+	// which is stored once they have been executed.
+	SchemaVersion int64
+	// AllowDowngrade starts Grafana even if the database was migrated by a newer version.
+	AllowDowngrade bool
 }
 
 type MigrationLog struct {
@@ -30,6 +41,18 @@ type MigrationLog struct {
 	Timestamp   time.Time
 }
 
+// schemaVersion is the row of the schema_version table, which holds the
+// highest schema version the database was migrated to.
+type schemaVersion struct {
+	Id      int64
+	Version int64
+	Updated time.Time
+}
+
+func (schemaVersion) TableName() string {
+	return "schema_version"
+}
+
 func NewMigrator(engine *xorm.Engine, cfg *setting.Cfg) *Migrator {
 	mg := &Migrator{}
 	mg.x = engine
@@ -83,6 +106,14 @@ func (mg *Migrator) Start() error {
 		return err
 	}
 
+	storedVersion, err := mg.getSchemaVersion()
+	if err != nil {
+		return err
+	}
+	if err := mg.checkDowngrade(storedVersion); err != nil {
+		return err
+	}
+
 	migrationsPerformed := 0
 	migrationsSkipped := 0
 	start := time.Now()
@@ -131,10 +162,74 @@ func (mg *Migrator) Start() error {
 
 	mg.Logger.Info("migrations completed", "performed", migrationsPerformed, "skipped", migrationsSkipped, "duration", time.Since(start))
 
+	if storedVersion < mg.SchemaVersion {
+		if err := mg.setSchemaVersion(); err != nil {
+			return errutil.Wrap("failed to store schema version", err)
+		}
+	}
+
 	// Make sure migrations are synced
 	return mg.x.Sync2()
 }
 
+// getSchemaVersion returns the schema version stored by the last migration of
+// the database, or 0 if there is none.
+func (mg *Migrator) getSchemaVersion() (int64, error) {
+	exists, err := mg.x.IsTableExist(new(schemaVersion))
+	if err != nil {
+		return 0, errutil.Wrap("failed to check table existence", err)
+	}
+	if !exists {
+		return 0, nil
+	}
+
+	var row schemaVersion
+	if _, err := mg.x.Desc("version").Get(&row); err != nil {
+		return 0, err
+	}
+	return row.Version, nil
+}
+
+// setSchemaVersion stores the schema version of the migrations, if the
+// schema_version table has been created by them.
+func (mg *Migrator) setSchemaVersion() error {
+	exists, err := mg.x.IsTableExist(new(schemaVersion))
+	if err != nil || !exists {
+		return err
+	}
+
+	return mg.InTransaction(func(sess *xorm.Session) error {
+		if _, err := sess.Where("1 = 1").Delete(&schemaVersion{}); err != nil {
+			return err
+		}
+		_, err := sess.Insert(&schemaVersion{Version: mg.SchemaVersion, Updated: time.Now()})
+		return err
+	})
+}
+
+// checkDowngrade verifies that the database wasn't migrated to a newer schema
+// version than the one of this version of Grafana, which could corrupt data by
+// writing it in an outdated format.
+func (mg *Migrator) checkDowngrade(storedVersion int64) error {
+	if storedVersion <= mg.SchemaVersion {
+		return nil
+	}
+
+	if mg.AllowDowngrade {
+		mg.Logger.Warn("Database was migrated by a newer version of Grafana, starting anyway since downgrades are allowed",
+			"schemaVersion", storedVersion, "supportedSchemaVersion", mg.SchemaVersion)
+		return nil
+	}
+
+	mg.Logger.Error("Database was migrated by a newer version of Grafana",
+		"schemaVersion", storedVersion, "supportedSchemaVersion", mg.SchemaVersion)
+	return fmt.Errorf("%w: the database schema version is %d, but this version supports up to %d. "+
+		"Running an older version against this database can corrupt data. Upgrade Grafana to the version that last ran "+
+		"against this database, or restore a backup of the database taken before the upgrade. If you are sure this "+
+		"version is compatible, set allow_downgrade = true in the [database] section to start anyway",
+		ErrIncompatibleDowngrade, storedVersion, mg.SchemaVersion)
+}
+
 func (mg *Migrator) exec(m Migration, sess *xorm.Session) error {
 	mg.Logger.Info("Executing migration", "id", m.Id())
 
@@ -241,6 +241,10 @@ func DeleteOrg(cmd *models.DeleteOrgCommand) error {
 			"DELETE FROM org_user WHERE org_id = ?",
 			"DELETE FROM org WHERE id = ?",
 			"DELETE FROM temp_user WHERE org_id = ?",
+			"DELETE FROM user_role WHERE org_id = ?",
+			"DELETE FROM builtin_role WHERE org_id = ?",
+			"DELETE FROM permission WHERE role_id IN (SELECT id FROM role WHERE org_id = ?)",
+			"DELETE FROM role WHERE org_id = ?",
 		}
 
 		for _, sql := range deletes {
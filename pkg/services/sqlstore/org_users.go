@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -52,6 +53,18 @@ func AddOrgUser(cmd *models.AddOrgUserCommand) error {
 			return err
 		}
 
+		sess.publishAfterCommit(&events.UserAccessChanged{
+			Timestamp: entity.Created,
+			UserId:    cmd.UserId,
+		})
+		if cmd.Role == models.ROLE_ADMIN {
+			sess.publishAfterCommit(&events.UserAdminRoleGranted{
+				Timestamp: entity.Created,
+				UserId:    cmd.UserId,
+				OrgId:     cmd.OrgId,
+			})
+		}
+
 		var userOrgs []*models.UserOrgDTO
 		sess.Table("org_user")
 		sess.Join("INNER", "org", "org_user.org_id=org.id")
@@ -83,6 +96,7 @@ func UpdateOrgUser(cmd *models.UpdateOrgUserCommand) error {
 			return models.ErrOrgUserNotFound
 		}
 
+		granted := cmd.Role == models.ROLE_ADMIN && orgUser.Role != models.ROLE_ADMIN
 		orgUser.Role = cmd.Role
 		orgUser.Updated = time.Now()
 		_, err = sess.ID(orgUser.Id).Update(&orgUser)
@@ -90,6 +104,18 @@ func UpdateOrgUser(cmd *models.UpdateOrgUserCommand) error {
 			return err
 		}
 
+		sess.publishAfterCommit(&events.UserAccessChanged{
+			Timestamp: orgUser.Updated,
+			UserId:    cmd.UserId,
+		})
+		if granted {
+			sess.publishAfterCommit(&events.UserAdminRoleGranted{
+				Timestamp: orgUser.Updated,
+				UserId:    cmd.UserId,
+				OrgId:     cmd.OrgId,
+			})
+		}
+
 		return validateOneAdminLeftInOrg(cmd.OrgId, sess)
 	})
 }
@@ -221,6 +247,7 @@ func RemoveOrgUser(cmd *models.RemoveOrgUserCommand) error {
 			"DELETE FROM org_user WHERE org_id=? and user_id=?",
 			"DELETE FROM dashboard_acl WHERE org_id=? and user_id = ?",
 			"DELETE FROM team_member WHERE org_id=? and user_id = ?",
+			"DELETE FROM user_role WHERE org_id=? and user_id = ?",
 		}
 
 		for _, sql := range deletes {
@@ -230,6 +257,15 @@ func RemoveOrgUser(cmd *models.RemoveOrgUserCommand) error {
 			}
 		}
 
+		sess.publishAfterCommit(&events.UserAccessChanged{
+			Timestamp: time.Now(),
+			UserId:    cmd.UserId,
+		})
+		sess.publishAfterCommit(&events.DashboardAclUpdated{
+			Timestamp: time.Now(),
+			OrgId:     cmd.OrgId,
+		})
+
 		// validate that after delete there is at least one user with admin role in org
 		if err := validateOneAdminLeftInOrg(cmd.OrgId, sess); err != nil {
 			return err
@@ -75,3 +75,45 @@ func (d DashboardPermissionFilter) Where() (string, []interface{}) {
 	params = append(params, okRoles...)
 	return sql, params
 }
+
+// AnonymousDashboardFilter restricts anonymous users to, or excludes them from, a list
+// of dashboards and folders identified by UID. Dashboards inherit the list of their folder.
+type AnonymousDashboardFilter struct {
+	OrgId         int64
+	Exclude       bool
+	DashboardUIDs []string
+	FolderUIDs    []string
+}
+
+func (f AnonymousDashboardFilter) Where() (string, []interface{}) {
+	uids := append(append([]string{}, f.DashboardUIDs...), f.FolderUIDs...)
+
+	conditions := []string{}
+	params := []interface{}{}
+	if len(uids) > 0 {
+		conditions = append(conditions, "dashboard.uid IN (?"+strings.Repeat(",?", len(uids)-1)+")")
+		for _, uid := range uids {
+			params = append(params, uid)
+		}
+	}
+	if len(f.FolderUIDs) > 0 {
+		conditions = append(conditions, "dashboard.folder_id IN (SELECT id FROM dashboard WHERE org_id = ? AND uid IN (?"+strings.Repeat(",?", len(f.FolderUIDs)-1)+"))")
+		params = append(params, f.OrgId)
+		for _, uid := range f.FolderUIDs {
+			params = append(params, uid)
+		}
+	}
+
+	if len(conditions) == 0 {
+		if f.Exclude {
+			return "", nil
+		}
+		return "1 = 0", nil
+	}
+
+	sql := "(" + strings.Join(conditions, " OR ") + ")"
+	if f.Exclude {
+		sql = "NOT " + sql
+	}
+	return sql, params
+}
@@ -1,6 +1,7 @@
 package sqlstore
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -10,8 +11,10 @@ import (
 )
 
 const (
-	alertRuleTarget = "alert_rule"
-	dashboardTarget = "dashboard"
+	alertRuleTarget         = "alert_rule"
+	alertRuleGroupTarget    = "alert_rule_group"
+	alertContactPointTarget = "alert_contact_point"
+	dashboardTarget         = "dashboard"
 )
 
 func init() {
@@ -41,7 +44,14 @@ func GetOrgQuotaByTarget(query *models.GetOrgQuotaByTargetQuery) error {
 	}
 
 	var used int64
-	if query.Target != alertRuleTarget || query.IsNgAlertEnabled {
+	switch {
+	case isNgAlertTarget(query.Target) && !query.IsNgAlertEnabled:
+		// nothing is used when unified alerting is disabled
+	case query.Target == alertRuleGroupTarget || query.Target == alertContactPointTarget:
+		if used, err = getAlertingQuotaUsed(query.Target, query.OrgId); err != nil {
+			return err
+		}
+	default:
 		// get quota used.
 		rawSQL := fmt.Sprintf("SELECT COUNT(*) AS count FROM %s WHERE org_id=?",
 			dialect.Quote(query.Target))
@@ -94,7 +104,15 @@ func GetOrgQuotas(query *models.GetOrgQuotasQuery) error {
 	result := make([]*models.OrgQuotaDTO, len(quotas))
 	for i, q := range quotas {
 		var used int64
-		if q.Target != alertRuleTarget || query.IsNgAlertEnabled {
+		switch {
+		case isNgAlertTarget(q.Target) && !query.IsNgAlertEnabled:
+			// nothing is used when unified alerting is disabled
+		case q.Target == alertRuleGroupTarget || q.Target == alertContactPointTarget:
+			var err error
+			if used, err = getAlertingQuotaUsed(q.Target, q.OrgId); err != nil {
+				return err
+			}
+		default:
 			// get quota used.
 			rawSQL := fmt.Sprintf("SELECT COUNT(*) as count from %s where org_id=?", dialect.Quote(q.Target))
 			resp := make([]*targetCount, 0)
@@ -158,7 +176,7 @@ func GetUserQuotaByTarget(query *models.GetUserQuotaByTargetQuery) error {
 	}
 
 	var used int64
-	if query.Target != alertRuleTarget || query.IsNgAlertEnabled {
+	if !isNgAlertTarget(query.Target) || query.IsNgAlertEnabled {
 		// get quota used.
 		rawSQL := fmt.Sprintf("SELECT COUNT(*) as count from %s where user_id=?", dialect.Quote(query.Target))
 		resp := make([]*targetCount, 0)
@@ -205,7 +223,7 @@ func GetUserQuotas(query *models.GetUserQuotasQuery) error {
 	result := make([]*models.UserQuotaDTO, len(quotas))
 	for i, q := range quotas {
 		var used int64
-		if q.Target != alertRuleTarget || query.IsNgAlertEnabled {
+		if !isNgAlertTarget(q.Target) || query.IsNgAlertEnabled {
 			// get quota used.
 			rawSQL := fmt.Sprintf("SELECT COUNT(*) as count from %s where user_id=?", dialect.Quote(q.Target))
 			resp := make([]*targetCount, 0)
@@ -258,7 +276,15 @@ func UpdateUserQuota(cmd *models.UpdateUserQuotaCmd) error {
 
 func GetGlobalQuotaByTarget(query *models.GetGlobalQuotaByTargetQuery) error {
 	var used int64
-	if query.Target != alertRuleTarget || query.IsNgAlertEnabled {
+	switch {
+	case isNgAlertTarget(query.Target) && !query.IsNgAlertEnabled:
+		// nothing is used when unified alerting is disabled
+	case query.Target == alertRuleGroupTarget || query.Target == alertContactPointTarget:
+		var err error
+		if used, err = getAlertingQuotaUsed(query.Target, 0); err != nil {
+			return err
+		}
+	default:
 		// get quota used.
 		rawSQL := fmt.Sprintf("SELECT COUNT(*) AS count FROM %s",
 			dialect.Quote(query.Target))
@@ -282,3 +308,57 @@ func GetGlobalQuotaByTarget(query *models.GetGlobalQuotaByTargetQuery) error {
 
 	return nil
 }
+
+func isNgAlertTarget(target string) bool {
+	return target == alertRuleTarget || target == alertRuleGroupTarget || target == alertContactPointTarget
+}
+
+// getAlertingQuotaUsed returns the usage of the quota targets of unified alerting that do not match
+// a table, of an org or of all the orgs when orgID is 0. The contact points are the receivers of the
+// latest Alertmanager configuration of each org.
+func getAlertingQuotaUsed(target string, orgID int64) (int64, error) {
+	switch target {
+	case alertRuleGroupTarget:
+		rawSQL := "SELECT COUNT(*) AS count FROM (SELECT DISTINCT org_id, namespace_uid, rule_group FROM alert_rule"
+		args := []interface{}{}
+		if orgID != 0 {
+			rawSQL += " WHERE org_id=?"
+			args = append(args, orgID)
+		}
+		rawSQL += ") rule_groups"
+
+		resp := make([]*targetCount, 0)
+		if err := x.SQL(rawSQL, args...).Find(&resp); err != nil {
+			return 0, err
+		}
+		return resp[0].Count, nil
+	case alertContactPointTarget:
+		rawSQL := "SELECT alertmanager_configuration FROM alert_configuration WHERE id IN (SELECT MAX(id) FROM alert_configuration GROUP BY org_id)"
+		args := []interface{}{}
+		if orgID != 0 {
+			rawSQL += " AND org_id=?"
+			args = append(args, orgID)
+		}
+
+		configurations := make([]string, 0)
+		if err := x.SQL(rawSQL, args...).Find(&configurations); err != nil {
+			return 0, err
+		}
+
+		var used int64
+		for _, c := range configurations {
+			var cfg struct {
+				AlertmanagerConfig struct {
+					Receivers []json.RawMessage `json:"receivers"`
+				} `json:"alertmanager_config"`
+			}
+			if err := json.Unmarshal([]byte(c), &cfg); err != nil {
+				return 0, fmt.Errorf("failed to parse the Alertmanager configuration: %w", err)
+			}
+			used += int64(len(cfg.AlertmanagerConfig.Receivers))
+		}
+		return used, nil
+	default:
+		return 0, fmt.Errorf("unknown quota target of unified alerting: %s", target)
+	}
+}
@@ -38,6 +38,10 @@ func TestQuotaCommandsAndQueries(t *testing.T) {
 			AlertRule:  5,
 		},
 	}
+	setting.Quota.Org.AlertRuleGroup = 5
+	setting.Quota.Org.AlertContactPoint = 5
+	setting.Quota.Global.AlertRuleGroup = 5
+	setting.Quota.Global.AlertContactPoint = 5
 
 	// create a new org and add user_id 1 as admin.
 	// we will then have an org with 1 user. and a user
@@ -105,7 +109,7 @@ func TestQuotaCommandsAndQueries(t *testing.T) {
 			err = GetOrgQuotas(&query)
 
 			require.NoError(t, err)
-			require.Len(t, query.Result, 5)
+			require.Len(t, query.Result, 7)
 			for _, res := range query.Result {
 				limit := int64(5) // default quota limit
 				used := int64(0)
@@ -217,6 +221,17 @@ func TestQuotaCommandsAndQueries(t *testing.T) {
 		require.Equal(t, int64(0), query.Result.Used)
 	})
 
+	t.Run("Should be able to get zero used global alert rule group and contact point quotas when ngalert is not enabled", func(t *testing.T) {
+		for _, target := range []string{alertRuleGroupTarget, alertContactPointTarget} {
+			query := models.GetGlobalQuotaByTargetQuery{Target: target, Default: 5}
+			err = GetGlobalQuotaByTarget(&query)
+			require.NoError(t, err)
+
+			require.Equal(t, int64(5), query.Result.Limit)
+			require.Equal(t, int64(0), query.Result.Used)
+		}
+	})
+
 	t.Run("Should be able to global dashboard quota", func(t *testing.T) {
 		query := models.GetGlobalQuotaByTargetQuery{Target: dashboardTarget, Default: 5}
 		err = GetGlobalQuotaByTarget(&query)
@@ -86,6 +86,7 @@ func (ss *SQLStore) Init() error {
 
 	if !ss.dbCfg.SkipMigrations {
 		migrator := migrator.NewMigrator(ss.engine, ss.Cfg)
+		migrator.AllowDowngrade = ss.dbCfg.AllowDowngrade
 		migrations.AddMigrations(migrator)
 
 		for _, descriptor := range registry.GetServices() {
@@ -110,6 +111,7 @@ func (ss *SQLStore) Init() error {
 	ss.addAlertNotificationUidByIdHandler()
 	ss.addPreferencesQueryAndCommandHandlers()
 	ss.addDashboardQueryAndCommandHandlers()
+	ss.addIdentityCacheHandlers()
 
 	if err := ss.Reset(); err != nil {
 		return err
@@ -394,6 +396,7 @@ func (ss *SQLStore) readConfig() error {
 
 	ss.dbCfg.CacheMode = sec.Key("cache_mode").MustString("private")
 	ss.dbCfg.SkipMigrations = sec.Key("skip_migrations").MustBool()
+	ss.dbCfg.AllowDowngrade = sec.Key("allow_downgrade").MustBool(false)
 	return nil
 }
 
@@ -551,4 +554,5 @@ type DatabaseConfig struct {
 	CacheMode        string
 	UrlQueryParams   map[string][]string
 	SkipMigrations   bool
+	AllowDowngrade   bool
 }
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/models"
 )
 
@@ -144,6 +145,11 @@ func DeleteTeam(cmd *models.DeleteTeamCommand) error {
 				return err
 			}
 		}
+
+		sess.publishAfterCommit(&events.DashboardAclUpdated{
+			Timestamp: time.Now(),
+			OrgId:     cmd.OrgId,
+		})
 		return nil
 	})
 }
@@ -301,8 +307,15 @@ func (ss *SQLStore) AddTeamMember(userID, orgID, teamID int64, isExternal bool,
 			Permission: permission,
 		}
 
-		_, err := sess.Insert(&entity)
-		return err
+		if _, err := sess.Insert(&entity); err != nil {
+			return err
+		}
+
+		sess.publishAfterCommit(&events.UserAccessChanged{
+			Timestamp: entity.Created,
+			UserId:    userID,
+		})
+		return nil
 	})
 }
 
@@ -370,8 +383,15 @@ func RemoveTeamMember(cmd *models.RemoveTeamMemberCommand) error {
 		if rows == 0 {
 			return models.ErrTeamMemberNotFound
 		}
+		if err != nil {
+			return err
+		}
 
-		return err
+		sess.publishAfterCommit(&events.UserAccessChanged{
+			Timestamp: time.Now(),
+			UserId:    cmd.UserId,
+		})
+		return nil
 	})
 }
 
@@ -18,16 +18,15 @@ import (
 func (ss *SQLStore) addUserQueryAndCommandHandlers() {
 	ss.Bus.AddHandlerCtx(ss.GetSignedInUserWithCacheCtx)
 
-	bus.AddHandlerCtx("sql", GetUserById)
 	bus.AddHandler("sql", UpdateUser)
 	bus.AddHandler("sql", ChangeUserPassword)
+	bus.AddHandlerCtx("sql", GetUserPasswordHistory)
 	bus.AddHandler("sql", GetUserByLogin)
 	bus.AddHandler("sql", GetUserByEmail)
 	bus.AddHandler("sql", SetUsingOrg)
 	bus.AddHandler("sql", UpdateUserLastSeenAt)
 	bus.AddHandler("sql", GetUserProfile)
 	bus.AddHandler("sql", SearchUsers)
-	bus.AddHandler("sql", GetUserOrgList)
 	bus.AddHandler("sql", DisableUser)
 	bus.AddHandler("sql", BatchDisableUsers)
 	bus.AddHandler("sql", DeleteUser)
@@ -149,6 +148,12 @@ func (ss *SQLStore) createUser(ctx context.Context, sess *DBSession, args userCr
 		return user, err
 	}
 
+	if user.Password != "" {
+		if err := addUserPasswordHistory(sess, user.Id, user.Password); err != nil {
+			return user, err
+		}
+	}
+
 	sess.publishAfterCommit(&events.UserCreated{
 		Timestamp: user.Created,
 		Id:        user.Id,
@@ -243,6 +248,12 @@ func (ss *SQLStore) CreateUser(ctx context.Context, cmd models.CreateUserCommand
 			return err
 		}
 
+		if user.Password != "" {
+			if err := addUserPasswordHistory(sess, user.Id, user.Password); err != nil {
+				return err
+			}
+		}
+
 		sess.publishAfterCommit(&events.UserCreated{
 			Timestamp: user.Created,
 			Id:        user.Id,
@@ -363,8 +374,8 @@ func UpdateUser(cmd *models.UpdateUserCommand) error {
 		}
 
 		sess.publishAfterCommit(&events.UserUpdated{
-			Timestamp: user.Created,
-			Id:        user.Id,
+			Timestamp: user.Updated,
+			Id:        cmd.UserId,
 			Name:      user.Name,
 			Login:     user.Login,
 			Email:     user.Email,
@@ -381,8 +392,17 @@ func ChangeUserPassword(cmd *models.ChangeUserPasswordCommand) error {
 			Updated:  time.Now(),
 		}
 
-		_, err := sess.ID(cmd.UserId).Update(&user)
-		return err
+		if _, err := sess.ID(cmd.UserId).Update(&user); err != nil {
+			return err
+		}
+
+		sess.publishAfterCommit(&events.UserCredentialsRevoked{
+			Timestamp: time.Now(),
+			UserId:    cmd.UserId,
+			Reason:    events.CredentialsRevokedPasswordChanged,
+		})
+
+		return addUserPasswordHistory(sess, cmd.UserId, cmd.NewPassword)
 	})
 }
 
@@ -425,8 +445,15 @@ func setUsingOrgInTransaction(sess *DBSession, userID int64, orgID int64) error
 		OrgId: orgID,
 	}
 
-	_, err := sess.ID(userID).Update(&user)
-	return err
+	if _, err := sess.ID(userID).Update(&user); err != nil {
+		return err
+	}
+
+	sess.publishAfterCommit(&events.UserAccessChanged{
+		Timestamp: time.Now(),
+		UserId:    userID,
+	})
+	return nil
 }
 
 func GetUserProfile(query *models.GetUserProfileQuery) error {
@@ -488,15 +515,20 @@ func GetUserOrgList(query *models.GetUserOrgListQuery) error {
 	return err
 }
 
-func newSignedInUserCacheKey(orgID, userID int64) string {
-	return fmt.Sprintf("signed-in-user-%d-%d", userID, orgID)
-}
-
 func (ss *SQLStore) GetSignedInUserWithCacheCtx(ctx context.Context, query *models.GetSignedInUserQuery) error {
+	// lookups by login or email are not cached, they would need their own invalidation
+	if query.UserId == 0 || !ss.identityCacheEnabled(ctx) {
+		return GetSignedInUser(ctx, query)
+	}
+
 	cacheKey := newSignedInUserCacheKey(query.OrgId, query.UserId)
 	if cached, found := ss.CacheService.Get(cacheKey); found {
-		query.Result = cached.(*models.SignedInUser)
-		return nil
+		user := *cached.(*models.SignedInUser)
+		// a stale last seen timestamp would make every request update it until the entry expires
+		if !user.ShouldUpdateLastSeenAt() {
+			query.Result = &user
+			return nil
+		}
 	}
 
 	err := GetSignedInUser(ctx, query)
@@ -504,8 +536,9 @@ func (ss *SQLStore) GetSignedInUserWithCacheCtx(ctx context.Context, query *mode
 		return err
 	}
 
+	user := *query.Result
 	cacheKey = newSignedInUserCacheKey(query.Result.OrgId, query.UserId)
-	ss.CacheService.Set(cacheKey, query.Result, time.Second*5)
+	ss.CacheService.Set(cacheKey, &user, ss.Cfg.IdentityCacheTTL)
 	return nil
 }
 
@@ -648,20 +681,33 @@ func SearchUsers(query *models.SearchUsersQuery) error {
 }
 
 func DisableUser(cmd *models.DisableUserCommand) error {
-	user := models.User{}
-	sess := x.Table("user")
+	return inTransaction(func(sess *DBSession) error {
+		user := models.User{}
+		if has, err := sess.Table("user").ID(cmd.UserId).Get(&user); err != nil {
+			return err
+		} else if !has {
+			return models.ErrUserNotFound
+		}
 
-	if has, err := sess.ID(cmd.UserId).Get(&user); err != nil {
-		return err
-	} else if !has {
-		return models.ErrUserNotFound
-	}
+		user.IsDisabled = cmd.IsDisabled
+		if _, err := sess.Table("user").ID(cmd.UserId).UseBool("is_disabled").Update(&user); err != nil {
+			return err
+		}
 
-	user.IsDisabled = cmd.IsDisabled
-	sess.UseBool("is_disabled")
+		if cmd.IsDisabled {
+			sess.publishAfterCommit(&events.UserCredentialsRevoked{
+				Timestamp: time.Now(),
+				UserId:    cmd.UserId,
+				Reason:    events.CredentialsRevokedUserDisabled,
+			})
+		}
+		sess.publishAfterCommit(&events.UserAccessChanged{
+			Timestamp: time.Now(),
+			UserId:    cmd.UserId,
+		})
 
-	_, err := sess.ID(cmd.UserId).Update(&user)
-	return err
+		return nil
+	})
 }
 
 func BatchDisableUsers(cmd *models.BatchDisableUsersCommand) error {
@@ -685,6 +731,20 @@ func BatchDisableUsers(cmd *models.BatchDisableUsersCommand) error {
 			return err
 		}
 
+		for _, id := range userIds {
+			if cmd.IsDisabled {
+				sess.publishAfterCommit(&events.UserCredentialsRevoked{
+					Timestamp: time.Now(),
+					UserId:    id,
+					Reason:    events.CredentialsRevokedUserDisabled,
+				})
+			}
+			sess.publishAfterCommit(&events.UserAccessChanged{
+				Timestamp: time.Now(),
+				UserId:    id,
+			})
+		}
+
 		return nil
 	})
 }
@@ -716,6 +776,8 @@ func deleteUserInTransaction(sess *DBSession, cmd *models.DeleteUserCommand) err
 		"DELETE FROM user_auth WHERE user_id = ?",
 		"DELETE FROM user_auth_token WHERE user_id = ?",
 		"DELETE FROM quota WHERE user_id = ?",
+		"DELETE FROM user_password_history WHERE user_id = ?",
+		"DELETE FROM user_role WHERE user_id = ?",
 	}
 
 	for _, sql := range deletes {
@@ -725,6 +787,11 @@ func deleteUserInTransaction(sess *DBSession, cmd *models.DeleteUserCommand) err
 		}
 	}
 
+	sess.publishAfterCommit(&events.UserAccessChanged{
+		Timestamp: time.Now(),
+		UserId:    cmd.UserId,
+	})
+
 	return nil
 }
 
@@ -735,6 +802,7 @@ func (ss *SQLStore) UpdateUserPermissions(userID int64, isAdmin bool) error {
 			return err
 		}
 
+		granted := isAdmin && !user.IsAdmin
 		user.IsAdmin = isAdmin
 		sess.UseBool("is_admin")
 
@@ -748,6 +816,17 @@ func (ss *SQLStore) UpdateUserPermissions(userID int64, isAdmin bool) error {
 			return err
 		}
 
+		sess.publishAfterCommit(&events.UserAccessChanged{
+			Timestamp: time.Now(),
+			UserId:    user.Id,
+		})
+		if granted {
+			sess.publishAfterCommit(&events.UserAdminRoleGranted{
+				Timestamp: time.Now(),
+				UserId:    user.Id,
+			})
+		}
+
 		return nil
 	})
 }
@@ -760,8 +839,15 @@ func SetUserHelpFlag(cmd *models.SetUserHelpFlagCommand) error {
 			Updated:    time.Now(),
 		}
 
-		_, err := sess.ID(cmd.UserId).Cols("help_flags1").Update(&user)
-		return err
+		if _, err := sess.ID(cmd.UserId).Cols("help_flags1").Update(&user); err != nil {
+			return err
+		}
+
+		sess.publishAfterCommit(&events.UserAccessChanged{
+			Timestamp: user.Updated,
+			UserId:    cmd.UserId,
+		})
+		return nil
 	})
 }
 
@@ -0,0 +1,49 @@
+package sqlstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// maxPasswordHistoryEntries is the number of passwords kept per user, which
+// bounds the password_history setting.
+const maxPasswordHistoryEntries = 24
+
+func GetUserPasswordHistory(ctx context.Context, query *models.GetUserPasswordHistoryQuery) error {
+	limit := query.Limit
+	if limit <= 0 || limit > maxPasswordHistoryEntries {
+		limit = maxPasswordHistoryEntries
+	}
+
+	return withDbSession(ctx, x, func(sess *DBSession) error {
+		query.Result = make([]*models.UserPasswordHistory, 0)
+		return sess.Where("user_id = ?", query.UserId).Desc("created", "id").Limit(limit).Find(&query.Result)
+	})
+}
+
+// addUserPasswordHistory records the hashed password of the user and removes
+// the entries exceeding maxPasswordHistoryEntries.
+func addUserPasswordHistory(sess *DBSession, userID int64, password string) error {
+	entry := models.UserPasswordHistory{
+		UserId:   userID,
+		Password: password,
+		Created:  time.Now(),
+	}
+	if _, err := sess.Insert(&entry); err != nil {
+		return err
+	}
+
+	var expired []int64
+	if err := sess.Table("user_password_history").Cols("id").Where("user_id = ?", userID).
+		Desc("created", "id").Limit(1000, maxPasswordHistoryEntries).Find(&expired); err != nil {
+		return err
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+
+	_, err := sess.In("id", expired).Delete(&models.UserPasswordHistory{})
+	return err
+}
@@ -0,0 +1,46 @@
+// +build integration
+
+package sqlstore
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserPasswordHistory(t *testing.T) {
+	ss := InitTestDB(t)
+
+	user, err := ss.CreateUser(context.Background(), models.CreateUserCommand{
+		Login:    "history",
+		Password: "initial",
+	})
+	require.NoError(t, err)
+
+	t.Run("records the password on creation", func(t *testing.T) {
+		query := models.GetUserPasswordHistoryQuery{UserId: user.Id}
+		require.NoError(t, GetUserPasswordHistory(context.Background(), &query))
+		require.Len(t, query.Result, 1)
+		require.Equal(t, user.Password, query.Result[0].Password)
+	})
+
+	t.Run("keeps the newest passwords", func(t *testing.T) {
+		for i := 0; i < maxPasswordHistoryEntries+2; i++ {
+			err := ChangeUserPassword(&models.ChangeUserPasswordCommand{UserId: user.Id, NewPassword: fmt.Sprintf("hash-%d", i)})
+			require.NoError(t, err)
+		}
+
+		query := models.GetUserPasswordHistoryQuery{UserId: user.Id, Limit: 1}
+		require.NoError(t, GetUserPasswordHistory(context.Background(), &query))
+		require.Len(t, query.Result, 1)
+		require.Equal(t, fmt.Sprintf("hash-%d", maxPasswordHistoryEntries+1), query.Result[0].Password)
+
+		var count int64
+		count, err = x.Where("user_id = ?", user.Id).Count(&models.UserPasswordHistory{})
+		require.NoError(t, err)
+		require.Equal(t, int64(maxPasswordHistoryEntries), count)
+	})
+}
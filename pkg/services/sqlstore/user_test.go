@@ -6,6 +6,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/setting"
@@ -300,6 +301,8 @@ func TestUserDataAccess(t *testing.T) {
 		})
 		require.Nil(t, err)
 
+		ss.Cfg.IdentityCacheTTL = 5 * time.Second
+		t.Cleanup(func() { ss.Cfg.IdentityCacheTTL = 0 })
 		ss.CacheService.Flush()
 
 		query3 := &models.GetSignedInUserQuery{OrgId: users[1].OrgId, UserId: users[1].Id}
@@ -0,0 +1,280 @@
+// Package uploads stores resumable chunked uploads, so that large plugin and
+// dashboard archives can be uploaded over unreliable connections without
+// starting from scratch after an interruption.
+package uploads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/registry"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	metadataExt = ".json"
+	dataExt     = ".part"
+
+	cleanupInterval = time.Hour
+)
+
+var (
+	ErrUploadNotFound   = errors.New("upload not found")
+	ErrInvalidSize      = errors.New("upload size is invalid")
+	ErrUploadTooLarge   = errors.New("upload exceeds its declared size")
+	ErrOffsetMismatch   = errors.New("upload offset does not match the stored data")
+	ErrUploadIncomplete = errors.New("upload is not complete")
+)
+
+func init() {
+	registry.RegisterService(&Service{})
+}
+
+// Upload describes a resumable upload. Offset is the number of bytes
+// received so far.
+type Upload struct {
+	UID     string    `json:"uid"`
+	OrgID   int64     `json:"orgId"`
+	UserID  int64     `json:"userId"`
+	Size    int64     `json:"size"`
+	Created time.Time `json:"created"`
+
+	Offset int64 `json:"-"`
+}
+
+// IsComplete returns true if all bytes of the upload have been received.
+func (u *Upload) IsComplete() bool {
+	return u.Offset == u.Size
+}
+
+// Service stores uploads in the uploads folder of the data path. Every upload
+// consists of a metadata file and a data file which chunks are appended to.
+type Service struct {
+	Cfg *setting.Cfg `inject:""`
+
+	dir   string
+	log   log.Logger
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+func (s *Service) Init() error {
+	s.log = log.New("uploads")
+	s.locks = map[string]*sync.Mutex{}
+	s.dir = filepath.Join(s.Cfg.DataPath, "uploads")
+
+	if err := os.MkdirAll(s.dir, 0750); err != nil {
+		return fmt.Errorf("failed to create uploads directory: %w", err)
+	}
+
+	return nil
+}
+
+// Run deletes expired uploads until the context is canceled.
+func (s *Service) Run(ctx context.Context) error {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		s.deleteExpired(time.Now())
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Create starts a new upload of the given size.
+func (s *Service) Create(orgID, userID, size int64) (*Upload, error) {
+	if size <= 0 || size > s.Cfg.UploadMaxSize {
+		return nil, ErrInvalidSize
+	}
+
+	upload := &Upload{
+		UID:     util.GenerateShortUID(),
+		OrgID:   orgID,
+		UserID:  userID,
+		Size:    size,
+		Created: time.Now(),
+	}
+
+	metadata, err := json.Marshal(upload)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ioutil.WriteFile(s.dataPath(upload.UID), nil, 0600); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(s.metadataPath(upload.UID), metadata, 0600); err != nil {
+		if err := s.remove(upload.UID); err != nil {
+			s.log.Warn("Failed to remove upload", "uid", upload.UID, "err", err)
+		}
+		return nil, err
+	}
+
+	return upload, nil
+}
+
+// Get returns the upload with the given UID if it was created by the given user.
+func (s *Service) Get(orgID, userID int64, uid string) (*Upload, error) {
+	if uid == "" || !util.IsValidShortUID(uid) {
+		return nil, ErrUploadNotFound
+	}
+
+	// nolint:gosec
+	// The path is built from a validated UID.
+	metadata, err := ioutil.ReadFile(s.metadataPath(uid))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrUploadNotFound
+		}
+		return nil, err
+	}
+
+	upload := &Upload{}
+	if err := json.Unmarshal(metadata, upload); err != nil {
+		return nil, err
+	}
+	if upload.OrgID != orgID || upload.UserID != userID {
+		return nil, ErrUploadNotFound
+	}
+
+	info, err := os.Stat(s.dataPath(uid))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrUploadNotFound
+		}
+		return nil, err
+	}
+	upload.Offset = info.Size()
+
+	return upload, nil
+}
+
+// Append writes the chunk read from r at the given offset, which has to match
+// the number of bytes received so far. Bytes received before a read error are
+// kept, so that the client can resume from the returned offset.
+func (s *Service) Append(upload *Upload, offset int64, r io.Reader) (int64, error) {
+	lock := s.lock(upload.UID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	f, err := os.OpenFile(s.dataPath(upload.UID), os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, ErrUploadNotFound
+		}
+		return 0, err
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			s.log.Warn("Failed to close upload", "uid", upload.UID, "err", err)
+		}
+	}()
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	upload.Offset = info.Size()
+	if offset != upload.Offset {
+		return upload.Offset, ErrOffsetMismatch
+	}
+
+	// read one byte more than remaining to detect oversized chunks
+	n, err := io.Copy(f, io.LimitReader(r, upload.Size-offset+1))
+	if offset+n > upload.Size {
+		if err := f.Truncate(offset); err != nil {
+			return offset, err
+		}
+		return offset, ErrUploadTooLarge
+	}
+
+	upload.Offset = offset + n
+	return upload.Offset, err
+}
+
+// Path returns the path of the data file of a complete upload.
+func (s *Service) Path(upload *Upload) (string, error) {
+	if !upload.IsComplete() {
+		return "", ErrUploadIncomplete
+	}
+	return s.dataPath(upload.UID), nil
+}
+
+// Delete removes the upload and its data.
+func (s *Service) Delete(upload *Upload) error {
+	lock := s.lock(upload.UID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	return s.remove(upload.UID)
+}
+
+func (s *Service) deleteExpired(now time.Time) {
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		s.log.Warn("Failed to list uploads", "err", err)
+		return
+	}
+
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), dataExt) || now.Sub(file.ModTime()) < s.Cfg.UploadExpiry {
+			continue
+		}
+
+		uid := strings.TrimSuffix(file.Name(), dataExt)
+		if err := s.Delete(&Upload{UID: uid}); err != nil {
+			s.log.Warn("Failed to delete expired upload", "uid", uid, "err", err)
+			continue
+		}
+		s.log.Debug("Deleted expired upload", "uid", uid)
+	}
+}
+
+func (s *Service) remove(uid string) error {
+	s.mu.Lock()
+	delete(s.locks, uid)
+	s.mu.Unlock()
+
+	for _, path := range []string{s.metadataPath(uid), s.dataPath(uid)} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) lock(uid string) *sync.Mutex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lock, ok := s.locks[uid]
+	if !ok {
+		lock = &sync.Mutex{}
+		s.locks[uid] = lock
+	}
+	return lock
+}
+
+func (s *Service) metadataPath(uid string) string {
+	return filepath.Join(s.dir, uid+metadataExt)
+}
+
+func (s *Service) dataPath(uid string) string {
+	return filepath.Join(s.dir, uid+dataExt)
+}
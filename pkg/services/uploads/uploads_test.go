@@ -0,0 +1,135 @@
+package uploads
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupService(t *testing.T) *Service {
+	t.Helper()
+
+	cfg := setting.NewCfg()
+	cfg.DataPath = t.TempDir()
+	cfg.UploadMaxSize = 1024
+	cfg.UploadExpiry = time.Hour
+
+	s := &Service{Cfg: cfg}
+	require.NoError(t, s.Init())
+	return s
+}
+
+type failingReader struct {
+	data []byte
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, errors.New("connection reset")
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestService(t *testing.T) {
+	t.Run("rejects invalid sizes", func(t *testing.T) {
+		s := setupService(t)
+
+		_, err := s.Create(1, 1, 0)
+		require.ErrorIs(t, err, ErrInvalidSize)
+		_, err = s.Create(1, 1, 2048)
+		require.ErrorIs(t, err, ErrInvalidSize)
+	})
+
+	t.Run("resumes an interrupted upload", func(t *testing.T) {
+		s := setupService(t)
+
+		upload, err := s.Create(1, 2, 10)
+		require.NoError(t, err)
+
+		offset, err := s.Append(upload, 0, &failingReader{data: []byte("0123")})
+		require.Error(t, err)
+		assert.Equal(t, int64(4), offset)
+
+		upload, err = s.Get(1, 2, upload.UID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), upload.Offset)
+		assert.False(t, upload.IsComplete())
+
+		_, err = s.Path(upload)
+		require.ErrorIs(t, err, ErrUploadIncomplete)
+
+		offset, err = s.Append(upload, 4, strings.NewReader("456789"))
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), offset)
+		assert.True(t, upload.IsComplete())
+
+		path, err := s.Path(upload)
+		require.NoError(t, err)
+		data, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "0123456789", string(data))
+	})
+
+	t.Run("rejects chunks at the wrong offset", func(t *testing.T) {
+		s := setupService(t)
+
+		upload, err := s.Create(1, 1, 10)
+		require.NoError(t, err)
+
+		offset, err := s.Append(upload, 3, strings.NewReader("abc"))
+		require.ErrorIs(t, err, ErrOffsetMismatch)
+		assert.Equal(t, int64(0), offset)
+	})
+
+	t.Run("rejects chunks exceeding the declared size", func(t *testing.T) {
+		s := setupService(t)
+
+		upload, err := s.Create(1, 1, 4)
+		require.NoError(t, err)
+
+		_, err = s.Append(upload, 0, bytes.NewReader([]byte("abcde")))
+		require.ErrorIs(t, err, ErrUploadTooLarge)
+
+		upload, err = s.Get(1, 1, upload.UID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), upload.Offset)
+	})
+
+	t.Run("only returns uploads of their creator", func(t *testing.T) {
+		s := setupService(t)
+
+		upload, err := s.Create(1, 1, 4)
+		require.NoError(t, err)
+
+		_, err = s.Get(1, 2, upload.UID)
+		require.ErrorIs(t, err, ErrUploadNotFound)
+		_, err = s.Get(2, 1, upload.UID)
+		require.ErrorIs(t, err, ErrUploadNotFound)
+		_, err = s.Get(1, 1, "../"+upload.UID)
+		require.ErrorIs(t, err, ErrUploadNotFound)
+	})
+
+	t.Run("deletes expired uploads", func(t *testing.T) {
+		s := setupService(t)
+
+		upload, err := s.Create(1, 1, 4)
+		require.NoError(t, err)
+
+		s.deleteExpired(time.Now())
+		_, err = s.Get(1, 1, upload.UID)
+		require.NoError(t, err)
+
+		s.deleteExpired(time.Now().Add(2 * time.Hour))
+		_, err = s.Get(1, 1, upload.UID)
+		require.ErrorIs(t, err, ErrUploadNotFound)
+	})
+}
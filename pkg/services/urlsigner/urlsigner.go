@@ -0,0 +1,201 @@
+// Package urlsigner signs URLs granting time-limited access to rendered images and snapshots,
+// so that the links of notifications and reports don't need to embed long-lived API keys.
+// A URL is signed either for a user, or for a role in an organization when there is no user
+// to sign it for, such as for the images of alert notifications.
+package urlsigner
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/registry"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const ServiceName = "URLSigner"
+
+// Query parameters added to signed URLs.
+const (
+	UserIDParam    = "signedUserId"
+	OrgIDParam     = "signedOrgId"
+	RoleParam      = "signedRole"
+	ExpiresParam   = "signedExpires"
+	SignatureParam = "signature"
+)
+
+var (
+	ErrDisabled         = errors.New("URL signing is disabled")
+	ErrPathNotSignable  = errors.New("only render and snapshot URLs can be signed")
+	ErrNotSigned        = errors.New("URL is not signed")
+	ErrInvalidSignature = errors.New("invalid URL signature")
+	ErrExpired          = errors.New("signed URL has expired")
+	ErrInvalidRole      = errors.New("invalid role")
+)
+
+// signablePaths are the path prefixes of the URLs that can be signed.
+var signablePaths = []string{
+	"/render/",
+	"/api/snapshots/",
+	"/dashboard/snapshot/",
+}
+
+var getTime = time.Now
+
+func init() {
+	registry.Register(&registry.Descriptor{
+		Name:         ServiceName,
+		Instance:     &Service{},
+		InitPriority: registry.Medium,
+	})
+}
+
+// Grant is the access a signed URL grants. UserID is 0 for URLs signed for a role.
+type Grant struct {
+	UserID  int64
+	OrgID   int64
+	OrgRole models.RoleType
+}
+
+// Service signs and verifies URLs with an HMAC of the secret key.
+type Service struct {
+	Cfg *setting.Cfg `inject:""`
+
+	key []byte
+}
+
+func (s *Service) Init() error {
+	// derive the key, so that the signatures can't be used for anything else signed with the secret key
+	mac := hmac.New(sha256.New, []byte(setting.SecretKey))
+	mac.Write([]byte("url-signing"))
+	s.key = mac.Sum(nil)
+	return nil
+}
+
+// Sign returns the path, relative to the root URL of Grafana, with the query parameters granting the
+// user access to it in the organization until ttl has passed. The default TTL is used when ttl is 0,
+// and ttl is capped to the maximum TTL.
+func (s *Service) Sign(path string, userID, orgID int64, ttl time.Duration) (string, error) {
+	return s.sign(path, ttl, func(query url.Values) {
+		query.Set(UserIDParam, strconv.FormatInt(userID, 10))
+		query.Set(OrgIDParam, strconv.FormatInt(orgID, 10))
+	})
+}
+
+// SignForRole returns the path with the query parameters granting access to it with the role in the
+// organization, without a user, until ttl has passed. It is meant for URLs created by Grafana itself,
+// such as the links of the images of alert notifications.
+func (s *Service) SignForRole(path string, orgID int64, role models.RoleType, ttl time.Duration) (string, error) {
+	if !role.IsValid() {
+		return "", ErrInvalidRole
+	}
+	return s.sign(path, ttl, func(query url.Values) {
+		query.Set(OrgIDParam, strconv.FormatInt(orgID, 10))
+		query.Set(RoleParam, string(role))
+	})
+}
+
+func (s *Service) sign(path string, ttl time.Duration, grant func(query url.Values)) (string, error) {
+	if !s.Cfg.URLSigningEnabled {
+		return "", ErrDisabled
+	}
+
+	u, err := url.Parse(path)
+	if err != nil {
+		return "", err
+	}
+	if u.IsAbs() || !isSignable(u.Path) {
+		return "", ErrPathNotSignable
+	}
+
+	if ttl <= 0 {
+		ttl = s.Cfg.URLSigningDefaultTTL
+	}
+	if ttl > s.Cfg.URLSigningMaxTTL {
+		ttl = s.Cfg.URLSigningMaxTTL
+	}
+
+	query := u.Query()
+	for _, param := range []string{UserIDParam, OrgIDParam, RoleParam, SignatureParam} {
+		query.Del(param)
+	}
+	grant(query)
+	query.Set(ExpiresParam, strconv.FormatInt(getTime().Add(ttl).Unix(), 10))
+	query.Set(SignatureParam, s.signature(u.Path, query))
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// Verify checks the signature of a request URL and returns the access it grants. URLs without a
+// signature, or of paths that can't be signed, return ErrNotSigned.
+func (s *Service) Verify(u *url.URL) (*Grant, error) {
+	path := u.Path
+	if s.Cfg.ServeFromSubPath {
+		path = strings.TrimPrefix(path, s.Cfg.AppSubURL)
+	}
+
+	query := u.Query()
+	signature := query.Get(SignatureParam)
+	if signature == "" || !isSignable(path) {
+		return nil, ErrNotSigned
+	}
+	if !s.Cfg.URLSigningEnabled {
+		return nil, ErrDisabled
+	}
+
+	query.Del(SignatureParam)
+	if !hmac.Equal([]byte(signature), []byte(s.signature(path, query))) {
+		return nil, ErrInvalidSignature
+	}
+
+	expires, err := strconv.ParseInt(query.Get(ExpiresParam), 10, 64)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	if getTime().Unix() >= expires {
+		return nil, ErrExpired
+	}
+
+	grant := &Grant{}
+	if grant.OrgID, err = strconv.ParseInt(query.Get(OrgIDParam), 10, 64); err != nil {
+		return nil, ErrInvalidSignature
+	}
+	if role := models.RoleType(query.Get(RoleParam)); role != "" {
+		if !role.IsValid() {
+			return nil, ErrInvalidRole
+		}
+		grant.OrgRole = role
+		return grant, nil
+	}
+	if grant.UserID, err = strconv.ParseInt(query.Get(UserIDParam), 10, 64); err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	return grant, nil
+}
+
+// signature returns the HMAC of the path and the query parameters, which Encode sorts by key.
+func (s *Service) signature(path string, query url.Values) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(path + "?" + query.Encode()))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func isSignable(path string) bool {
+	if strings.Contains(path, "..") {
+		return false
+	}
+	for _, prefix := range signablePaths {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package urlsigner
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService(t *testing.T) {
+	origGetTime := getTime
+	t.Cleanup(func() {
+		getTime = origGetTime
+	})
+	now := time.Date(2021, time.June, 1, 12, 0, 0, 0, time.UTC)
+	getTime = func() time.Time {
+		return now
+	}
+
+	s := &Service{Cfg: &setting.Cfg{
+		URLSigningEnabled:    true,
+		URLSigningDefaultTTL: time.Hour,
+		URLSigningMaxTTL:     24 * time.Hour,
+	}}
+	require.NoError(t, s.Init())
+
+	sign := func(t *testing.T, path string, ttl time.Duration) *url.URL {
+		t.Helper()
+		signed, err := s.Sign(path, 2, 3, ttl)
+		require.NoError(t, err)
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		return u
+	}
+
+	t.Run("Signed URL grants access to the user and organization", func(t *testing.T) {
+		u := sign(t, "/render/d-solo/abc/dash?panelId=2&width=1000", 0)
+		assert.Equal(t, "1000", u.Query().Get("width"))
+
+		grant, err := s.Verify(u)
+		require.NoError(t, err)
+		assert.Equal(t, &Grant{UserID: 2, OrgID: 3}, grant)
+	})
+
+	t.Run("URL signed for a role grants access to the role without a user", func(t *testing.T) {
+		signed, err := s.SignForRole("/render/d-solo/abc/dash?panelId=2&"+UserIDParam+"=1", 3, models.ROLE_VIEWER, 0)
+		require.NoError(t, err)
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+
+		grant, err := s.Verify(u)
+		require.NoError(t, err)
+		assert.Equal(t, &Grant{OrgID: 3, OrgRole: models.ROLE_VIEWER}, grant)
+
+		query := u.Query()
+		query.Set(RoleParam, string(models.ROLE_ADMIN))
+		u.RawQuery = query.Encode()
+		_, err = s.Verify(u)
+		require.ErrorIs(t, err, ErrInvalidSignature)
+
+		_, err = s.SignForRole("/render/d-solo/abc/dash", 3, "Owner", 0)
+		require.ErrorIs(t, err, ErrInvalidRole)
+	})
+
+	t.Run("Tampered URL is refused", func(t *testing.T) {
+		u := sign(t, "/render/d-solo/abc/dash?panelId=2", 0)
+		query := u.Query()
+		query.Set("panelId", "3")
+		u.RawQuery = query.Encode()
+
+		_, err := s.Verify(u)
+		require.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("Expired URL is refused", func(t *testing.T) {
+		u := sign(t, "/api/snapshots/key", 48*time.Hour)
+
+		now = now.Add(24 * time.Hour)
+		t.Cleanup(func() {
+			now = now.Add(-24 * time.Hour)
+		})
+
+		_, err := s.Verify(u)
+		require.ErrorIs(t, err, ErrExpired)
+	})
+
+	t.Run("Only render and snapshot URLs can be signed", func(t *testing.T) {
+		_, err := s.Sign("/api/admin/users", 2, 3, 0)
+		require.ErrorIs(t, err, ErrPathNotSignable)
+		_, err = s.Sign("/render/../api/admin/users", 2, 3, 0)
+		require.ErrorIs(t, err, ErrPathNotSignable)
+		_, err = s.Sign("https://example.com/render/d-solo/abc", 2, 3, 0)
+		require.ErrorIs(t, err, ErrPathNotSignable)
+	})
+
+	t.Run("URL without signature isn't signed", func(t *testing.T) {
+		_, err := s.Verify(&url.URL{Path: "/render/d-solo/abc"})
+		require.ErrorIs(t, err, ErrNotSigned)
+	})
+}
@@ -224,16 +224,48 @@ type Cfg struct {
 	// SMTP email settings
 	Smtp SmtpSettings
 
+	// SMS settings
+	Sms SmsSettings
+
 	// Rendering
-	ImagesDir                      string
-	CSVsDir                        string
-	RendererUrl                    string
-	RendererCallbackUrl            string
-	RendererConcurrentRequestLimit int
+	ImagesDir                         string
+	CSVsDir                           string
+	RendererUrl                       string
+	RendererCallbackUrl               string
+	RendererAuthToken                 string
+	RendererCacheTTL                  time.Duration
+	RendererOrgLimits                 RenderingOrgLimits
+	RendererOrgLimitOverrides         map[int64]RenderingOrgLimits
+	RendererConcurrentRequestLimit    int
+	RendererOrgConcurrentRequestLimit int
+	RendererQueuedRequestLimit        int
+
+	RendererPluginInstances                int
+	RendererPluginInstanceConcurrencyLimit int
+
+	RendererBrowserEnabled bool
+	RendererBrowserPath    string
+
+	// Scheduled reports
+	ReportsEnabled       bool
+	ReportsMaxRecipients int
+	ReportsRenderTimeout time.Duration
 
 	// Security
 	DisableInitAdminCreation          bool
 	DisableBruteForceLoginProtection  bool
+	BruteForceMaxAttemptsPerUser      int64
+	BruteForceMaxAttemptsPerIP        int64
+	BruteForceAttemptsWindow          time.Duration
+	BruteForceLockoutDuration         time.Duration
+	BruteForceMaxLockoutDuration      time.Duration
+	PasswordMinLength                 int
+	PasswordRequireUppercase          bool
+	PasswordRequireLowercase          bool
+	PasswordRequireDigit              bool
+	PasswordRequireSymbol             bool
+	PasswordHistory                   int
+	PasswordMaxAge                    time.Duration
 	CookieSecure                      bool
 	CookieSameSiteDisabled            bool
 	CookieSameSiteMode                http.SameSite
@@ -257,6 +289,18 @@ type Cfg struct {
 	PluginCatalogURL                 string
 	PluginAdminEnabled               bool
 	PluginAdminExternalManageEnabled bool
+	PluginsHotReloadInterval         time.Duration
+	PluginsHealthCheckInterval       time.Duration
+	PluginsHealthCheckTimeout        time.Duration
+	PluginsMetricsScrapeInterval     time.Duration
+	PluginRepositoryURL              string
+	PluginsCDNBaseURL                string
+	PluginRepositoryAuthToken        string
+	PluginSignaturePolicies          map[string]PluginSignaturePolicy
+	PluginVersionPins                map[string]string
+	PluginProcessCgroupPath          string
+	PluginProcessDefaultLimits       PluginProcessLimits
+	PluginProcessLimits              map[string]PluginProcessLimits
 	DisableSanitizeHtml              bool
 	EnterpriseLicensePath            string
 
@@ -275,6 +319,8 @@ type Cfg struct {
 	LoginMaxInactiveLifetime     time.Duration
 	LoginMaxLifetime             time.Duration
 	TokenRotationIntervalMinutes int
+	ImpersonationEnabled         bool
+	ImpersonationSessionDuration time.Duration
 	SigV4AuthEnabled             bool
 	BasicAuthEnabled             bool
 	AdminUser                    string
@@ -297,20 +343,75 @@ type Cfg struct {
 	AuthProxyWhitelist        string
 	AuthProxyHeaders          map[string]string
 	AuthProxySyncTTL          int
+	AuthProxySignatureSecret  string
+	AuthProxySignatureHeader  string
+	AuthProxyTimestampHeader  string
+	AuthProxySignatureMaxAge  time.Duration
 
 	// OAuth
 	OAuthCookieMaxAge int
 
+	// SCIM provisioning
+	SCIMEnabled bool
+	SCIMOrgRole string
+
 	// JWT Auth
-	JWTAuthEnabled       bool
-	JWTAuthHeaderName    string
-	JWTAuthEmailClaim    string
-	JWTAuthUsernameClaim string
-	JWTAuthExpectClaims  string
-	JWTAuthJWKSetURL     string
-	JWTAuthCacheTTL      time.Duration
-	JWTAuthKeyFile       string
-	JWTAuthJWKSetFile    string
+	JWTAuthEnabled             bool
+	JWTAuthHeaderName          string
+	JWTAuthEmailClaim          string
+	JWTAuthUsernameClaim       string
+	JWTAuthExpectClaims        string
+	JWTAuthJWKSetURL           string
+	JWTAuthCacheTTL            time.Duration
+	JWTAuthKeyFile             string
+	JWTAuthJWKSetFile          string
+	JWTAuthAllowedIssuers      []string
+	JWTAuthAllowedAudiences    []string
+	JWTAuthRoleAttributePath   string
+	JWTAuthRoleAttributeStrict bool
+	JWTAuthAutoSignUp          bool
+
+	// Kerberos Auth
+	KerberosAuthEnabled      bool
+	KerberosKeytabFile       string
+	KerberosServicePrincipal string
+	KerberosAllowedRealms    []string
+	KerberosStripRealm       bool
+	KerberosAutoSignUp       bool
+	KerberosAutoLogin        bool
+
+	// Client Certificate Auth
+	ClientCertAuthEnabled    bool
+	ClientCertCAFile         string
+	ClientCertRequired       bool
+	ClientCertUsernameSource string
+	ClientCertCRLFile        string
+	ClientCertOCSPEnabled    bool
+	ClientCertOCSPTimeout    time.Duration
+	ClientCertAutoSignUp     bool
+
+	// Audit log
+	AuditLogEnabled        bool
+	AuditLogRetentionDays  int
+	AuditLogFilePath       string
+	AuditLogWebhookURL     string
+	AuditLogWebhookTimeout time.Duration
+
+	// Authentication event webhook
+	AuthWebhookURL     string
+	AuthWebhookEvents  []string
+	AuthWebhookTimeout time.Duration
+
+	// User deprovisioning
+	DeprovisioningEnabled     bool
+	DeprovisioningInterval    time.Duration
+	DeprovisioningSources     []string
+	DeprovisioningAction      string
+	DeprovisioningGracePeriod time.Duration
+	DeprovisioningDryRun      bool
+
+	// SAML Auth
+	SAML SAMLSettings
 
 	// Dataproxy
 	SendUserHeader                 bool
@@ -327,16 +428,29 @@ type Cfg struct {
 	// DistributedCache
 	RemoteCacheOptions *RemoteCacheOptions
 
+	// IdentityCacheTTL is how long user, organization membership and
+	// dashboard permission lookups are cached in memory.
+	IdentityCacheTTL time.Duration
+
+	// Resumable uploads
+	UploadMaxSize                    int64
+	UploadExpiry                     time.Duration
+	UploadArchiveMaxEntries          int
+	UploadArchiveMaxUncompressedSize int64
+
 	EditorsCanAdmin bool
 
 	ApiKeyMaxSecondsToLive int64
 
 	// Use to enable new features which may still be in alpha/beta stage.
-	FeatureToggles       map[string]bool
-	AnonymousEnabled     bool
-	AnonymousOrgName     string
-	AnonymousOrgRole     string
-	AnonymousHideVersion bool
+	FeatureToggles           map[string]bool
+	AnonymousEnabled         bool
+	AnonymousOrgName         string
+	AnonymousOrgRole         string
+	AnonymousHideVersion     bool
+	AnonymousDashboardAccess string
+	AnonymousDashboardUIDs   []string
+	AnonymousFolderUIDs      []string
 
 	DateFormats DateFormats
 
@@ -359,6 +473,13 @@ type Cfg struct {
 	// Snapshots
 	SnapshotPublicMode bool
 
+	// URL signing
+	URLSigningEnabled    bool
+	URLSigningDefaultTTL time.Duration
+	URLSigningMaxTTL     time.Duration
+	// URLSigningNotificationTTL is the TTL of the links of the images of alert notifications
+	URLSigningNotificationTTL time.Duration
+
 	ErrTemplateName string
 
 	Env string
@@ -407,7 +528,26 @@ type Cfg struct {
 	GeomapEnableCustomBaseLayers bool
 
 	// Unified Alerting
-	AdminConfigPollInterval time.Duration
+	AdminConfigPollInterval           time.Duration
+	ExternalAlertmanagerCAFile        string
+	ExternalAlertmanagerCertFile      string
+	ExternalAlertmanagerKeyFile       string
+	ExternalAlertmanagerTLSSkipVerify bool
+	StateHistoryMaxAge                time.Duration
+	HAEvaluationSharding              bool
+	HAPeerTimeout                     time.Duration
+	EvaluationJitter                  string
+	CaptureScreenshots                bool
+	NotificationMaxAttempts           int
+	NotificationRetryBackoff          time.Duration
+	NotificationTimeout               time.Duration
+	NotificationDeadLetterMaxAge      time.Duration
+	// RecordedQueriesRemoteWriteURL is the Prometheus remote write endpoint that the results of the
+	// recorded queries are written to, the recorded queries are not evaluated when it is empty.
+	RecordedQueriesRemoteWriteURL      string
+	RecordedQueriesRemoteWriteUser     string
+	RecordedQueriesRemoteWritePassword string
+	RecordedQueriesRemoteWriteTimeout  time.Duration
 }
 
 // IsLiveConfigEnabled returns true if live should be able to save configs to SQL tables
@@ -863,6 +1003,12 @@ func (cfg *Cfg) Load(args *CommandLineArgs) error {
 		return err
 	}
 
+	urlSigning := iniFile.Section("url_signing")
+	cfg.URLSigningEnabled = urlSigning.Key("enabled").MustBool(false)
+	cfg.URLSigningDefaultTTL = urlSigning.Key("default_ttl").MustDuration(time.Hour)
+	cfg.URLSigningMaxTTL = urlSigning.Key("max_ttl").MustDuration(7 * 24 * time.Hour)
+	cfg.URLSigningNotificationTTL = urlSigning.Key("notification_ttl").MustDuration(7 * 24 * time.Hour)
+
 	// read dashboard settings
 	dashboards := iniFile.Section("dashboards")
 	DashboardVersionsToKeep = dashboards.Key("versions_to_keep").MustInt(20)
@@ -879,6 +1025,7 @@ func (cfg *Cfg) Load(args *CommandLineArgs) error {
 	if err := readRenderingSettings(iniFile, cfg); err != nil {
 		return err
 	}
+	readReportsSettings(iniFile, cfg)
 
 	cfg.TempDataLifetime = iniFile.Section("paths").Key("temp_data_lifetime").MustDuration(time.Second * 3600 * 24)
 	cfg.MetricsEndpointEnabled = iniFile.Section("metrics").Key("enabled").MustBool(true)
@@ -916,6 +1063,19 @@ func (cfg *Cfg) Load(args *CommandLineArgs) error {
 	cfg.PluginsEnableAlpha = pluginsSection.Key("enable_alpha").MustBool(false)
 	cfg.PluginsAppsSkipVerifyTLS = pluginsSection.Key("app_tls_skip_verify_insecure").MustBool(false)
 	cfg.PluginSettings = extractPluginSettings(iniFile.Sections())
+	cfg.PluginSignaturePolicies, err = readPluginSignaturePolicies(cfg.PluginSettings)
+	if err != nil {
+		return err
+	}
+	cfg.PluginVersionPins, err = readPluginVersionPins(cfg.PluginSettings)
+	if err != nil {
+		return err
+	}
+	cfg.PluginProcessCgroupPath = pluginsSection.Key("process_cgroup_path").MustString("")
+	cfg.PluginProcessDefaultLimits, cfg.PluginProcessLimits, err = readPluginProcessLimits(pluginsSection.KeysHash(), cfg.PluginSettings)
+	if err != nil {
+		return err
+	}
 	pluginsAllowUnsigned := pluginsSection.Key("allow_loading_unsigned_plugins").MustString("")
 	for _, plug := range strings.Split(pluginsAllowUnsigned, ",") {
 		plug = strings.TrimSpace(plug)
@@ -924,6 +1084,21 @@ func (cfg *Cfg) Load(args *CommandLineArgs) error {
 	cfg.PluginCatalogURL = pluginsSection.Key("plugin_catalog_url").MustString("https://grafana.com/grafana/plugins/")
 	cfg.PluginAdminEnabled = pluginsSection.Key("plugin_admin_enabled").MustBool(false)
 	cfg.PluginAdminExternalManageEnabled = pluginsSection.Key("plugin_admin_external_manage_enabled").MustBool(false)
+	cfg.PluginsHotReloadInterval = pluginsSection.Key("hot_reload_interval").MustDuration(0)
+	cfg.PluginsHealthCheckInterval = pluginsSection.Key("health_check_interval").MustDuration(30 * time.Second)
+	cfg.PluginsHealthCheckTimeout = pluginsSection.Key("health_check_timeout").MustDuration(5 * time.Second)
+	if cfg.PluginsHealthCheckTimeout <= 0 {
+		cfg.PluginsHealthCheckTimeout = 5 * time.Second
+	}
+	cfg.PluginsMetricsScrapeInterval = pluginsSection.Key("metrics_scrape_interval").MustDuration(0)
+	cfg.PluginRepositoryURL = pluginsSection.Key("plugin_repository_url").MustString("https://grafana.com/api/plugins")
+	cfg.PluginRepositoryAuthToken = pluginsSection.Key("plugin_repository_auth_token").MustString("")
+	cfg.PluginsCDNBaseURL = strings.TrimSuffix(pluginsSection.Key("cdn_base_url").MustString(""), "/")
+	if cfg.PluginsCDNBaseURL != "" {
+		if u, err := url.Parse(cfg.PluginsCDNBaseURL); err != nil || !u.IsAbs() {
+			return fmt.Errorf("invalid plugins cdn_base_url %q, it must be an absolute URL", cfg.PluginsCDNBaseURL)
+		}
+	}
 
 	// Read and populate feature toggles list
 	featureTogglesSection := iniFile.Section("feature_toggles")
@@ -943,6 +1118,8 @@ func (cfg *Cfg) Load(args *CommandLineArgs) error {
 	cfg.readAzureSettings()
 	cfg.readSessionConfig()
 	cfg.readSmtpSettings()
+	cfg.readSmsSettings()
+	cfg.readSAMLSettings()
 	cfg.readQuotaSettings()
 	cfg.readAnnotationSettings()
 	cfg.readExpressionsSettings()
@@ -979,6 +1156,15 @@ func (cfg *Cfg) Load(args *CommandLineArgs) error {
 		ConnStr: connStr,
 	}
 
+	identityCache := iniFile.Section("identity_cache")
+	cfg.IdentityCacheTTL = identityCache.Key("ttl").MustDuration(5 * time.Second)
+
+	uploads := iniFile.Section("uploads")
+	cfg.UploadMaxSize = uploads.Key("max_size_mb").MustInt64(500) * 1024 * 1024
+	cfg.UploadExpiry = uploads.Key("expiry").MustDuration(24 * time.Hour)
+	cfg.UploadArchiveMaxEntries = uploads.Key("archive_max_entries").MustInt(1000)
+	cfg.UploadArchiveMaxUncompressedSize = uploads.Key("archive_max_uncompressed_size_mb").MustInt64(10) * 1024 * 1024
+
 	geomapSection := iniFile.Section("geomap")
 	basemapJSON := valueAsString(geomapSection, "default_baselayer_config", "")
 	if basemapJSON != "" {
@@ -1129,6 +1315,22 @@ func readSecuritySettings(iniFile *ini.File, cfg *Cfg) error {
 	SecretKey = valueAsString(security, "secret_key", "")
 	DisableGravatar = security.Key("disable_gravatar").MustBool(true)
 	cfg.DisableBruteForceLoginProtection = security.Key("disable_brute_force_login_protection").MustBool(false)
+	cfg.BruteForceMaxAttemptsPerUser = security.Key("brute_force_max_attempts_per_user").MustInt64(5)
+	cfg.BruteForceMaxAttemptsPerIP = security.Key("brute_force_max_attempts_per_ip").MustInt64(0)
+	cfg.BruteForceAttemptsWindow = security.Key("brute_force_attempts_window").MustDuration(5 * time.Minute)
+	cfg.BruteForceLockoutDuration = security.Key("brute_force_lockout_duration").MustDuration(5 * time.Minute)
+	cfg.BruteForceMaxLockoutDuration = security.Key("brute_force_max_lockout_duration").MustDuration(5 * time.Minute)
+	if cfg.BruteForceMaxLockoutDuration < cfg.BruteForceLockoutDuration {
+		cfg.BruteForceMaxLockoutDuration = cfg.BruteForceLockoutDuration
+	}
+
+	cfg.PasswordMinLength = security.Key("password_min_length").MustInt(4)
+	cfg.PasswordRequireUppercase = security.Key("password_require_uppercase").MustBool(false)
+	cfg.PasswordRequireLowercase = security.Key("password_require_lowercase").MustBool(false)
+	cfg.PasswordRequireDigit = security.Key("password_require_digit").MustBool(false)
+	cfg.PasswordRequireSymbol = security.Key("password_require_symbol").MustBool(false)
+	cfg.PasswordHistory = security.Key("password_history").MustInt(0)
+	cfg.PasswordMaxAge = security.Key("password_max_age").MustDuration(0)
 
 	CookieSecure = security.Key("cookie_secure").MustBool(false)
 	cfg.CookieSecure = CookieSecure
@@ -1217,6 +1419,9 @@ func readAuthSettings(iniFile *ini.File, cfg *Cfg) (err error) {
 		cfg.TokenRotationIntervalMinutes = 2
 	}
 
+	cfg.ImpersonationEnabled = auth.Key("impersonation_enabled").MustBool(false)
+	cfg.ImpersonationSessionDuration = auth.Key("impersonation_session_duration").MustDuration(time.Hour)
+
 	DisableLoginForm = auth.Key("disable_login_form").MustBool(false)
 	DisableSignoutMenu = auth.Key("disable_signout_menu").MustBool(false)
 	OAuthAutoLogin = auth.Key("oauth_auto_login").MustBool(false)
@@ -1233,6 +1438,9 @@ func readAuthSettings(iniFile *ini.File, cfg *Cfg) (err error) {
 	cfg.AnonymousOrgName = valueAsString(iniFile.Section("auth.anonymous"), "org_name", "")
 	cfg.AnonymousOrgRole = valueAsString(iniFile.Section("auth.anonymous"), "org_role", "")
 	cfg.AnonymousHideVersion = iniFile.Section("auth.anonymous").Key("hide_version").MustBool(false)
+	cfg.AnonymousDashboardAccess = iniFile.Section("auth.anonymous").Key("dashboard_access").In("all", []string{"all", "include", "exclude"})
+	cfg.AnonymousDashboardUIDs = util.SplitString(valueAsString(iniFile.Section("auth.anonymous"), "dashboards", ""))
+	cfg.AnonymousFolderUIDs = util.SplitString(valueAsString(iniFile.Section("auth.anonymous"), "folders", ""))
 
 	// basic auth
 	authBasic := iniFile.Section("auth.basic")
@@ -1250,6 +1458,57 @@ func readAuthSettings(iniFile *ini.File, cfg *Cfg) (err error) {
 	cfg.JWTAuthCacheTTL = authJWT.Key("cache_ttl").MustDuration(time.Minute * 60)
 	cfg.JWTAuthKeyFile = valueAsString(authJWT, "key_file", "")
 	cfg.JWTAuthJWKSetFile = valueAsString(authJWT, "jwk_set_file", "")
+	cfg.JWTAuthAllowedIssuers = util.SplitString(valueAsString(authJWT, "allowed_issuers", ""))
+	cfg.JWTAuthAllowedAudiences = util.SplitString(valueAsString(authJWT, "allowed_audiences", ""))
+	cfg.JWTAuthRoleAttributePath = valueAsString(authJWT, "role_attribute_path", "")
+	cfg.JWTAuthRoleAttributeStrict = authJWT.Key("role_attribute_strict").MustBool(false)
+	cfg.JWTAuthAutoSignUp = authJWT.Key("auto_sign_up").MustBool(false)
+
+	// Kerberos auth
+	authKerberos := iniFile.Section("auth.kerberos")
+	cfg.KerberosAuthEnabled = authKerberos.Key("enabled").MustBool(false)
+	cfg.KerberosKeytabFile = valueAsString(authKerberos, "keytab_file", "")
+	cfg.KerberosServicePrincipal = valueAsString(authKerberos, "service_principal", "")
+	cfg.KerberosAllowedRealms = util.SplitString(valueAsString(authKerberos, "allowed_realms", ""))
+	cfg.KerberosStripRealm = authKerberos.Key("strip_realm").MustBool(true)
+	cfg.KerberosAutoSignUp = authKerberos.Key("auto_sign_up").MustBool(false)
+	cfg.KerberosAutoLogin = authKerberos.Key("auto_login").MustBool(false)
+
+	// Client certificate auth
+	authClientCert := iniFile.Section("auth.client_cert")
+	cfg.ClientCertAuthEnabled = authClientCert.Key("enabled").MustBool(false)
+	cfg.ClientCertCAFile = valueAsString(authClientCert, "ca_file", "")
+	cfg.ClientCertRequired = authClientCert.Key("required").MustBool(false)
+	cfg.ClientCertUsernameSource = authClientCert.Key("username_source").In("subject_cn", []string{"subject_cn", "san_email", "san_dns", "san_uri"})
+	cfg.ClientCertCRLFile = valueAsString(authClientCert, "crl_file", "")
+	cfg.ClientCertOCSPEnabled = authClientCert.Key("ocsp_enabled").MustBool(false)
+	cfg.ClientCertOCSPTimeout = authClientCert.Key("ocsp_timeout").MustDuration(5 * time.Second)
+	cfg.ClientCertAutoSignUp = authClientCert.Key("auto_sign_up").MustBool(false)
+
+	authAudit := iniFile.Section("auth.audit")
+	cfg.AuditLogEnabled = authAudit.Key("enabled").MustBool(false)
+	cfg.AuditLogRetentionDays = authAudit.Key("retention_days").MustInt(90)
+	cfg.AuditLogFilePath = valueAsString(authAudit, "file_path", "")
+	cfg.AuditLogWebhookURL = valueAsString(authAudit, "webhook_url", "")
+	cfg.AuditLogWebhookTimeout = authAudit.Key("webhook_timeout").MustDuration(10 * time.Second)
+
+	authWebhook := iniFile.Section("auth.webhook")
+	cfg.AuthWebhookURL = valueAsString(authWebhook, "url", "")
+	cfg.AuthWebhookEvents = util.SplitString(valueAsString(authWebhook, "events", "login-failed,login-new-device,admin-role-granted"))
+	cfg.AuthWebhookTimeout = authWebhook.Key("timeout").MustDuration(10 * time.Second)
+
+	deprovisioning := iniFile.Section("auth.deprovisioning")
+	cfg.DeprovisioningEnabled = deprovisioning.Key("enabled").MustBool(false)
+	cfg.DeprovisioningInterval = deprovisioning.Key("interval").MustDuration(24 * time.Hour)
+	cfg.DeprovisioningSources = util.SplitString(valueAsString(deprovisioning, "sources", "ldap,oauth"))
+	cfg.DeprovisioningAction = deprovisioning.Key("action").In("disable", []string{"disable", "delete"})
+	cfg.DeprovisioningGracePeriod = deprovisioning.Key("grace_period").MustDuration(7 * 24 * time.Hour)
+	cfg.DeprovisioningDryRun = deprovisioning.Key("dry_run").MustBool(true)
+
+	// SCIM provisioning
+	authSCIM := iniFile.Section("auth.scim")
+	cfg.SCIMEnabled = authSCIM.Key("enabled").MustBool(false)
+	cfg.SCIMOrgRole = authSCIM.Key("org_role").In("Viewer", []string{"Viewer", "Editor", "Admin"})
 
 	authProxy := iniFile.Section("auth.proxy")
 	AuthProxyEnabled = authProxy.Key("enabled").MustBool(false)
@@ -1283,6 +1542,11 @@ func readAuthSettings(iniFile *ini.File, cfg *Cfg) (err error) {
 		}
 	}
 
+	cfg.AuthProxySignatureSecret = valueAsString(authProxy, "signature_secret", "")
+	cfg.AuthProxySignatureHeader = valueAsString(authProxy, "signature_header", "X-WEBAUTH-SIGNATURE")
+	cfg.AuthProxyTimestampHeader = valueAsString(authProxy, "timestamp_header", "X-WEBAUTH-TIMESTAMP")
+	cfg.AuthProxySignatureMaxAge = authProxy.Key("signature_max_age").MustDuration(time.Minute)
+
 	return nil
 }
 
@@ -1336,6 +1600,8 @@ func readRenderingSettings(iniFile *ini.File, cfg *Cfg) error {
 	renderSec := iniFile.Section("rendering")
 	cfg.RendererUrl = valueAsString(renderSec, "server_url", "")
 	cfg.RendererCallbackUrl = valueAsString(renderSec, "callback_url", "")
+	cfg.RendererAuthToken = valueAsString(renderSec, "renderer_token", "")
+	cfg.RendererCacheTTL = renderSec.Key("render_cache_ttl").MustDuration(0)
 
 	if cfg.RendererCallbackUrl == "" {
 		cfg.RendererCallbackUrl = AppUrl
@@ -1351,16 +1617,84 @@ func readRenderingSettings(iniFile *ini.File, cfg *Cfg) error {
 	}
 
 	cfg.RendererConcurrentRequestLimit = renderSec.Key("concurrent_render_request_limit").MustInt(30)
+	cfg.RendererOrgConcurrentRequestLimit = renderSec.Key("org_concurrent_render_request_limit").MustInt(0)
+	cfg.RendererQueuedRequestLimit = renderSec.Key("queued_render_request_limit").MustInt(100)
+
+	var err error
+	if cfg.RendererOrgLimits, cfg.RendererOrgLimitOverrides, err = readRenderingOrgLimits(iniFile); err != nil {
+		return err
+	}
+
+	cfg.RendererPluginInstances = renderSec.Key("plugin_instances").MustInt(1)
+	cfg.RendererPluginInstanceConcurrencyLimit = renderSec.Key("plugin_instance_concurrency_limit").MustInt(0)
+	cfg.RendererBrowserEnabled = renderSec.Key("browser_enabled").MustBool(false)
+	cfg.RendererBrowserPath = valueAsString(renderSec, "browser_path", "")
 	cfg.ImagesDir = filepath.Join(cfg.DataPath, "png")
 	cfg.CSVsDir = filepath.Join(cfg.DataPath, "csv")
 
 	return nil
 }
 
+func readReportsSettings(iniFile *ini.File, cfg *Cfg) {
+	reportsSec := iniFile.Section("reporting")
+	cfg.ReportsEnabled = reportsSec.Key("enabled").MustBool(true)
+	cfg.ReportsMaxRecipients = reportsSec.Key("max_recipients").MustInt(50)
+	cfg.ReportsRenderTimeout = reportsSec.Key("render_timeout").MustDuration(2 * time.Minute)
+}
+
 func (cfg *Cfg) readUnifiedAlertingSettings(iniFile *ini.File) error {
 	ua := iniFile.Section("unified_alerting")
 	s := ua.Key("admin_config_poll_interval_seconds").MustInt(60)
 	cfg.AdminConfigPollInterval = time.Second * time.Duration(s)
+	cfg.ExternalAlertmanagerCAFile = valueAsString(ua, "external_alertmanager_ca_file", "")
+	cfg.ExternalAlertmanagerCertFile = valueAsString(ua, "external_alertmanager_cert_file", "")
+	cfg.ExternalAlertmanagerKeyFile = valueAsString(ua, "external_alertmanager_key_file", "")
+	cfg.ExternalAlertmanagerTLSSkipVerify = ua.Key("external_alertmanager_tls_skip_verify").MustBool(false)
+	stateHistoryMaxAge, err := gtime.ParseDuration(valueAsString(ua, "state_history_max_age", "30d"))
+	if err != nil {
+		return fmt.Errorf("invalid state_history_max_age: %w", err)
+	}
+	cfg.StateHistoryMaxAge = stateHistoryMaxAge
+	cfg.HAEvaluationSharding = ua.Key("ha_evaluation_sharding").MustBool(false)
+	haPeerTimeout, err := gtime.ParseDuration(valueAsString(ua, "ha_peer_timeout", "1m"))
+	if err != nil {
+		return fmt.Errorf("invalid ha_peer_timeout: %w", err)
+	}
+	cfg.HAPeerTimeout = haPeerTimeout
+	cfg.EvaluationJitter = valueAsString(ua, "evaluation_jitter", "none")
+	switch cfg.EvaluationJitter {
+	case "none", "group", "rule":
+	default:
+		return fmt.Errorf("invalid evaluation_jitter %q, it must be none, group or rule", cfg.EvaluationJitter)
+	}
+	cfg.CaptureScreenshots = ua.Key("capture_screenshots").MustBool(false)
+	cfg.NotificationMaxAttempts = ua.Key("notification_max_attempts").MustInt(3)
+	if cfg.NotificationMaxAttempts < 1 {
+		return fmt.Errorf("notification_max_attempts must be at least 1")
+	}
+	notificationRetryBackoff, err := gtime.ParseDuration(valueAsString(ua, "notification_retry_backoff", "1s"))
+	if err != nil {
+		return fmt.Errorf("invalid notification_retry_backoff: %w", err)
+	}
+	cfg.NotificationRetryBackoff = notificationRetryBackoff
+	notificationTimeout, err := gtime.ParseDuration(valueAsString(ua, "notification_timeout", "30s"))
+	if err != nil {
+		return fmt.Errorf("invalid notification_timeout: %w", err)
+	}
+	cfg.NotificationTimeout = notificationTimeout
+	notificationDeadLetterMaxAge, err := gtime.ParseDuration(valueAsString(ua, "notification_dead_letter_max_age", "7d"))
+	if err != nil {
+		return fmt.Errorf("invalid notification_dead_letter_max_age: %w", err)
+	}
+	cfg.NotificationDeadLetterMaxAge = notificationDeadLetterMaxAge
+	cfg.RecordedQueriesRemoteWriteURL = valueAsString(ua, "recorded_queries_remote_write_url", "")
+	cfg.RecordedQueriesRemoteWriteUser = valueAsString(ua, "recorded_queries_remote_write_basic_auth_user", "")
+	cfg.RecordedQueriesRemoteWritePassword = valueAsString(ua, "recorded_queries_remote_write_basic_auth_password", "")
+	recordedQueriesRemoteWriteTimeout, err := gtime.ParseDuration(valueAsString(ua, "recorded_queries_remote_write_timeout", "30s"))
+	if err != nil {
+		return fmt.Errorf("invalid recorded_queries_remote_write_timeout: %w", err)
+	}
+	cfg.RecordedQueriesRemoteWriteTimeout = recordedQueriesRemoteWriteTimeout
 	return nil
 }
 
@@ -1,9 +1,14 @@
 package setting
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/hashicorp/go-version"
 	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/util"
 )
 
 // PluginSettings maps plugin id to map of key/value settings.
@@ -23,3 +28,178 @@ func extractPluginSettings(sections []*ini.Section) PluginSettings {
 
 	return psMap
 }
+
+// PluginSignatureVerification is how the signature of a plugin is verified
+// when the plugin is loaded.
+type PluginSignatureVerification string
+
+const (
+	// PluginSignatureRequire only loads the plugin when its signature is valid.
+	PluginSignatureRequire PluginSignatureVerification = "require"
+	// PluginSignatureWarn loads the plugin, logging a warning when its signature isn't valid.
+	PluginSignatureWarn PluginSignatureVerification = "warn"
+	// PluginSignatureSkip loads the plugin without verifying its signature.
+	PluginSignatureSkip PluginSignatureVerification = "skip"
+)
+
+var pluginSignatureTypes = []string{"grafana", "commercial", "community", "private"}
+
+// PluginSignaturePolicy overrides the signature verification of a plugin,
+// configured in the plugin's [plugin.<id>] section.
+type PluginSignaturePolicy struct {
+	// Verification is empty when the plugin is verified like the other plugins.
+	Verification PluginSignatureVerification
+	// Types are the accepted signature types, any type is accepted when empty.
+	Types []string
+}
+
+func readPluginSignaturePolicies(ps PluginSettings) (map[string]PluginSignaturePolicy, error) {
+	policies := map[string]PluginSignaturePolicy{}
+	for pluginID, settings := range ps {
+		policy := PluginSignaturePolicy{
+			Verification: PluginSignatureVerification(strings.TrimSpace(settings["signature_verification"])),
+			Types:        util.SplitString(settings["signature_types"]),
+		}
+
+		switch policy.Verification {
+		case "", PluginSignatureRequire, PluginSignatureWarn, PluginSignatureSkip:
+		default:
+			return nil, fmt.Errorf("invalid signature_verification %q for plugin %q, must be one of require, warn or skip",
+				policy.Verification, pluginID)
+		}
+
+		for _, t := range policy.Types {
+			if !isPluginSignatureType(t) {
+				return nil, fmt.Errorf("invalid signature type %q in signature_types for plugin %q, must be one of %s",
+					t, pluginID, strings.Join(pluginSignatureTypes, ", "))
+			}
+		}
+
+		if policy.Verification != "" || len(policy.Types) > 0 {
+			policies[pluginID] = policy
+		}
+	}
+
+	return policies, nil
+}
+
+func isPluginSignatureType(t string) bool {
+	for _, signatureType := range pluginSignatureTypes {
+		if t == signatureType {
+			return true
+		}
+	}
+	return false
+}
+
+// readPluginVersionPins reads the pinned_version settings of the [plugin.<id>]
+// sections, which are version constraints such as 1.2.3 or ">= 1.2, < 2.0"
+// that the installed versions of the plugins must satisfy.
+func readPluginVersionPins(ps PluginSettings) (map[string]string, error) {
+	pins := map[string]string{}
+	for pluginID, settings := range ps {
+		pin := strings.TrimSpace(settings["pinned_version"])
+		if pin == "" {
+			continue
+		}
+		if _, err := version.NewConstraint(pin); err != nil {
+			return nil, fmt.Errorf("invalid pinned_version %q for plugin %q: %w", pin, pluginID, err)
+		}
+		pins[pluginID] = pin
+	}
+
+	return pins, nil
+}
+
+// PluginProcessLimits are the resource limits of the process of a backend plugin,
+// and of the connections to it.
+type PluginProcessLimits struct {
+	// MemoryLimitMB is the memory limit of the process in megabytes, 0 for no limit.
+	MemoryLimitMB int64
+	// CPULimit is the number of CPUs the process can use, 0 for no limit.
+	CPULimit float64
+	// Nice is the nice level of the process, 0 to keep the level of the Grafana server.
+	Nice int
+	// EnvAllowlist are the environment variables of the Grafana server passed to
+	// the process, all of them when empty.
+	EnvAllowlist []string
+	// Sandbox is whether the process runs in a sandbox restricting it to its
+	// plugin directory, and to the SandboxAllowedHosts network hosts.
+	Sandbox bool
+	// SandboxAllowedHosts are the hosts that a sandboxed process can connect
+	// to, none when empty.
+	SandboxAllowedHosts []string
+	// GRPCConnections is the number of gRPC connections to the process, over
+	// which the queries and resource calls are spread.
+	GRPCConnections int
+	// MaxConcurrentRequests is the number of queries and resource calls sent
+	// concurrently to the process, 0 for no limit. Further requests wait.
+	MaxConcurrentRequests int
+}
+
+// HasCgroupLimits returns whether the limits require a cgroup.
+func (l PluginProcessLimits) HasCgroupLimits() bool {
+	return l.MemoryLimitMB > 0 || l.CPULimit > 0
+}
+
+// readPluginProcessLimits reads the default process limits of the [plugins]
+// section, and the limits of the [plugin.<id>] sections that override them.
+func readPluginProcessLimits(defaultSettings map[string]string, ps PluginSettings) (PluginProcessLimits, map[string]PluginProcessLimits, error) {
+	defaults, err := parsePluginProcessLimits(PluginProcessLimits{GRPCConnections: 1}, defaultSettings)
+	if err != nil {
+		return PluginProcessLimits{}, nil, fmt.Errorf("invalid plugin process limits in [plugins]: %w", err)
+	}
+
+	limits := map[string]PluginProcessLimits{}
+	for pluginID, settings := range ps {
+		l, err := parsePluginProcessLimits(defaults, settings)
+		if err != nil {
+			return PluginProcessLimits{}, nil, fmt.Errorf("invalid plugin process limits for plugin %q: %w", pluginID, err)
+		}
+		limits[pluginID] = l
+	}
+
+	return defaults, limits, nil
+}
+
+func parsePluginProcessLimits(limits PluginProcessLimits, settings map[string]string) (PluginProcessLimits, error) {
+	var err error
+	if v := strings.TrimSpace(settings["process_memory_limit_mb"]); v != "" {
+		if limits.MemoryLimitMB, err = strconv.ParseInt(v, 10, 64); err != nil || limits.MemoryLimitMB < 0 {
+			return limits, fmt.Errorf("process_memory_limit_mb must be a positive number of megabytes, got %q", v)
+		}
+	}
+	if v := strings.TrimSpace(settings["process_cpu_limit"]); v != "" {
+		if limits.CPULimit, err = strconv.ParseFloat(v, 64); err != nil || limits.CPULimit < 0 {
+			return limits, fmt.Errorf("process_cpu_limit must be a positive number of CPUs, got %q", v)
+		}
+	}
+	if v := strings.TrimSpace(settings["process_nice"]); v != "" {
+		if limits.Nice, err = strconv.Atoi(v); err != nil || limits.Nice < -20 || limits.Nice > 19 {
+			return limits, fmt.Errorf("process_nice must be between -20 and 19, got %q", v)
+		}
+	}
+	if v, ok := settings["process_env_allowlist"]; ok {
+		limits.EnvAllowlist = util.SplitString(strings.TrimSpace(v))
+	}
+	if v := strings.TrimSpace(settings["process_sandbox"]); v != "" {
+		if limits.Sandbox, err = strconv.ParseBool(v); err != nil {
+			return limits, fmt.Errorf("process_sandbox must be true or false, got %q", v)
+		}
+	}
+	if v, ok := settings["process_sandbox_allowed_hosts"]; ok {
+		limits.SandboxAllowedHosts = util.SplitString(strings.TrimSpace(v))
+	}
+	if v := strings.TrimSpace(settings["grpc_connections"]); v != "" {
+		if limits.GRPCConnections, err = strconv.Atoi(v); err != nil || limits.GRPCConnections < 1 {
+			return limits, fmt.Errorf("grpc_connections must be a number of connections of at least 1, got %q", v)
+		}
+	}
+	if v := strings.TrimSpace(settings["max_concurrent_requests"]); v != "" {
+		if limits.MaxConcurrentRequests, err = strconv.Atoi(v); err != nil || limits.MaxConcurrentRequests < 0 {
+			return limits, fmt.Errorf("max_concurrent_requests must be a positive number of requests, got %q", v)
+		}
+	}
+
+	return limits, nil
+}
@@ -41,3 +41,71 @@ func TestPluginSettings(t *testing.T) {
 	require.Equal(t, ps["plugin2"]["key3"], "value3")
 	require.Equal(t, ps["plugin2"]["key4"], "value4")
 }
+
+func TestPluginSignaturePolicies(t *testing.T) {
+	policies, err := readPluginSignaturePolicies(PluginSettings{
+		"internal-panel":   {"signature_verification": "skip"},
+		"internal-app":     {"signature_verification": "warn", "signature_types": "private, commercial"},
+		"partner-app":      {"signature_types": "commercial"},
+		"unchanged-plugin": {"key": "value"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]PluginSignaturePolicy{
+		"internal-panel": {Verification: PluginSignatureSkip, Types: []string{}},
+		"internal-app":   {Verification: PluginSignatureWarn, Types: []string{"private", "commercial"}},
+		"partner-app":    {Types: []string{"commercial"}},
+	}, policies)
+
+	_, err = readPluginSignaturePolicies(PluginSettings{"plugin": {"signature_verification": "ignore"}})
+	require.EqualError(t, err, `invalid signature_verification "ignore" for plugin "plugin", must be one of require, warn or skip`)
+
+	_, err = readPluginSignaturePolicies(PluginSettings{"plugin": {"signature_types": "grafana,unknown"}})
+	require.EqualError(t, err, `invalid signature type "unknown" in signature_types for plugin "plugin", must be one of grafana, commercial, community, private`)
+}
+
+func TestPluginProcessLimits(t *testing.T) {
+	defaults, limits, err := readPluginProcessLimits(map[string]string{
+		"process_memory_limit_mb": "512",
+		"process_env_allowlist":   "PATH, HOME",
+	}, PluginSettings{
+		"heavy-datasource": {"process_memory_limit_mb": "2048", "process_cpu_limit": "1.5", "process_nice": "10"},
+		"isolated-app":     {"process_env_allowlist": "", "process_sandbox": "true", "process_sandbox_allowed_hosts": "api.example.com"},
+		"busy-datasource":  {"grpc_connections": "4", "max_concurrent_requests": "100"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, PluginProcessLimits{MemoryLimitMB: 512, EnvAllowlist: []string{"PATH", "HOME"}, GRPCConnections: 1}, defaults)
+	require.Equal(t, map[string]PluginProcessLimits{
+		"heavy-datasource": {MemoryLimitMB: 2048, CPULimit: 1.5, Nice: 10, EnvAllowlist: []string{"PATH", "HOME"}, GRPCConnections: 1},
+		"isolated-app": {MemoryLimitMB: 512, EnvAllowlist: []string{}, Sandbox: true,
+			SandboxAllowedHosts: []string{"api.example.com"}, GRPCConnections: 1},
+		"busy-datasource": {MemoryLimitMB: 512, EnvAllowlist: []string{"PATH", "HOME"}, GRPCConnections: 4, MaxConcurrentRequests: 100},
+	}, limits)
+
+	_, _, err = readPluginProcessLimits(map[string]string{"process_cpu_limit": "-1"}, PluginSettings{})
+	require.EqualError(t, err, `invalid plugin process limits in [plugins]: process_cpu_limit must be a positive number of CPUs, got "-1"`)
+
+	_, _, err = readPluginProcessLimits(map[string]string{}, PluginSettings{"plugin": {"process_sandbox": "yes please"}})
+	require.EqualError(t, err, `invalid plugin process limits for plugin "plugin": process_sandbox must be true or false, got "yes please"`)
+
+	_, _, err = readPluginProcessLimits(map[string]string{}, PluginSettings{"plugin": {"process_nice": "20"}})
+	require.EqualError(t, err, `invalid plugin process limits for plugin "plugin": process_nice must be between -20 and 19, got "20"`)
+
+	_, _, err = readPluginProcessLimits(map[string]string{"grpc_connections": "0"}, PluginSettings{})
+	require.EqualError(t, err, `invalid plugin process limits in [plugins]: grpc_connections must be a number of connections of at least 1, got "0"`)
+}
+
+func TestPluginVersionPins(t *testing.T) {
+	pins, err := readPluginVersionPins(PluginSettings{
+		"pinned-panel":    {"pinned_version": "1.2.3"},
+		"pinned-app":      {"pinned_version": " >= 2.0, < 3.0 "},
+		"unpinned-plugin": {"key": "value"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"pinned-panel": "1.2.3",
+		"pinned-app":   ">= 2.0, < 3.0",
+	}, pins)
+
+	_, err = readPluginVersionPins(PluginSettings{"plugin": {"pinned_version": "latest"}})
+	require.EqualError(t, err, `invalid pinned_version "latest" for plugin "plugin": Malformed constraint: latest`)
+}
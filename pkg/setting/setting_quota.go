@@ -5,11 +5,13 @@ import (
 )
 
 type OrgQuota struct {
-	User       int64 `target:"org_user"`
-	DataSource int64 `target:"data_source"`
-	Dashboard  int64 `target:"dashboard"`
-	ApiKey     int64 `target:"api_key"`
-	AlertRule  int64 `target:"alert_rule"`
+	User              int64 `target:"org_user"`
+	DataSource        int64 `target:"data_source"`
+	Dashboard         int64 `target:"dashboard"`
+	ApiKey            int64 `target:"api_key"`
+	AlertRule         int64 `target:"alert_rule"`
+	AlertRuleGroup    int64 `target:"alert_rule_group"`
+	AlertContactPoint int64 `target:"alert_contact_point"`
 }
 
 type UserQuota struct {
@@ -17,13 +19,15 @@ type UserQuota struct {
 }
 
 type GlobalQuota struct {
-	Org        int64 `target:"org"`
-	User       int64 `target:"user"`
-	DataSource int64 `target:"data_source"`
-	Dashboard  int64 `target:"dashboard"`
-	ApiKey     int64 `target:"api_key"`
-	Session    int64 `target:"-"`
-	AlertRule  int64 `target:"alert_rule"`
+	Org               int64 `target:"org"`
+	User              int64 `target:"user"`
+	DataSource        int64 `target:"data_source"`
+	Dashboard         int64 `target:"dashboard"`
+	ApiKey            int64 `target:"api_key"`
+	Session           int64 `target:"-"`
+	AlertRule         int64 `target:"alert_rule"`
+	AlertRuleGroup    int64 `target:"alert_rule_group"`
+	AlertContactPoint int64 `target:"alert_contact_point"`
 }
 
 func (q *OrgQuota) ToMap() map[string]int64 {
@@ -66,19 +70,25 @@ func (cfg *Cfg) readQuotaSettings() {
 	quota := cfg.Raw.Section("quota")
 	Quota.Enabled = quota.Key("enabled").MustBool(false)
 
-	var alertOrgQuota int64
-	var alertGlobalQuota int64
+	var alertOrgQuota, alertRuleGroupOrgQuota, alertContactPointOrgQuota int64
+	var alertGlobalQuota, alertRuleGroupGlobalQuota, alertContactPointGlobalQuota int64
 	if cfg.IsNgAlertEnabled() {
 		alertOrgQuota = quota.Key("org_alert_rule").MustInt64(100)
+		alertRuleGroupOrgQuota = quota.Key("org_alert_rule_group").MustInt64(100)
+		alertContactPointOrgQuota = quota.Key("org_alert_contact_point").MustInt64(100)
 		alertGlobalQuota = quota.Key("global_alert_rule").MustInt64(-1)
+		alertRuleGroupGlobalQuota = quota.Key("global_alert_rule_group").MustInt64(-1)
+		alertContactPointGlobalQuota = quota.Key("global_alert_contact_point").MustInt64(-1)
 	}
 	// per ORG Limits
 	Quota.Org = &OrgQuota{
-		User:       quota.Key("org_user").MustInt64(10),
-		DataSource: quota.Key("org_data_source").MustInt64(10),
-		Dashboard:  quota.Key("org_dashboard").MustInt64(10),
-		ApiKey:     quota.Key("org_api_key").MustInt64(10),
-		AlertRule:  alertOrgQuota,
+		User:              quota.Key("org_user").MustInt64(10),
+		DataSource:        quota.Key("org_data_source").MustInt64(10),
+		Dashboard:         quota.Key("org_dashboard").MustInt64(10),
+		ApiKey:            quota.Key("org_api_key").MustInt64(10),
+		AlertRule:         alertOrgQuota,
+		AlertRuleGroup:    alertRuleGroupOrgQuota,
+		AlertContactPoint: alertContactPointOrgQuota,
 	}
 
 	// per User limits
@@ -88,13 +98,15 @@ func (cfg *Cfg) readQuotaSettings() {
 
 	// Global Limits
 	Quota.Global = &GlobalQuota{
-		User:       quota.Key("global_user").MustInt64(-1),
-		Org:        quota.Key("global_org").MustInt64(-1),
-		DataSource: quota.Key("global_data_source").MustInt64(-1),
-		Dashboard:  quota.Key("global_dashboard").MustInt64(-1),
-		ApiKey:     quota.Key("global_api_key").MustInt64(-1),
-		Session:    quota.Key("global_session").MustInt64(-1),
-		AlertRule:  alertGlobalQuota,
+		User:              quota.Key("global_user").MustInt64(-1),
+		Org:               quota.Key("global_org").MustInt64(-1),
+		DataSource:        quota.Key("global_data_source").MustInt64(-1),
+		Dashboard:         quota.Key("global_dashboard").MustInt64(-1),
+		ApiKey:            quota.Key("global_api_key").MustInt64(-1),
+		Session:           quota.Key("global_session").MustInt64(-1),
+		AlertRule:         alertGlobalQuota,
+		AlertRuleGroup:    alertRuleGroupGlobalQuota,
+		AlertContactPoint: alertContactPointGlobalQuota,
 	}
 
 	cfg.Quota = Quota
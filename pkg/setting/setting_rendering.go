@@ -0,0 +1,86 @@
+package setting
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// RenderingOrgLimits are the rendering limits of an organization.
+type RenderingOrgLimits struct {
+	// RendersPerHour is the number of images the organization can render in an
+	// hour, 0 for no limit.
+	RendersPerHour int
+	// MaxWidth and MaxHeight are the maximum dimensions of the images in pixels,
+	// 0 for no limit.
+	MaxWidth  int
+	MaxHeight int
+	// MaxTimeout is the maximum timeout of render requests, longer timeouts are
+	// reduced to it. 0 for no limit.
+	MaxTimeout time.Duration
+}
+
+// RenderingLimits returns the rendering limits of an organization.
+func (cfg *Cfg) RenderingLimits(orgID int64) RenderingOrgLimits {
+	if limits, ok := cfg.RendererOrgLimitOverrides[orgID]; ok {
+		return limits
+	}
+	return cfg.RendererOrgLimits
+}
+
+// readRenderingOrgLimits reads the default rendering limits of organizations of
+// the [rendering] section, and the limits of the [rendering.org.<id>] sections
+// that override them.
+func readRenderingOrgLimits(iniFile *ini.File) (RenderingOrgLimits, map[int64]RenderingOrgLimits, error) {
+	defaults, err := parseRenderingOrgLimits(RenderingOrgLimits{}, iniFile.Section("rendering").KeysHash())
+	if err != nil {
+		return RenderingOrgLimits{}, nil, fmt.Errorf("invalid rendering limits in [rendering]: %w", err)
+	}
+
+	overrides := map[int64]RenderingOrgLimits{}
+	for _, section := range iniFile.Sections() {
+		if !strings.HasPrefix(section.Name(), "rendering.org.") {
+			continue
+		}
+		orgID, err := strconv.ParseInt(strings.TrimPrefix(section.Name(), "rendering.org."), 10, 64)
+		if err != nil {
+			return RenderingOrgLimits{}, nil, fmt.Errorf("invalid organization ID in section [%s]", section.Name())
+		}
+		limits, err := parseRenderingOrgLimits(defaults, section.KeysHash())
+		if err != nil {
+			return RenderingOrgLimits{}, nil, fmt.Errorf("invalid rendering limits in [%s]: %w", section.Name(), err)
+		}
+		overrides[orgID] = limits
+	}
+
+	return defaults, overrides, nil
+}
+
+func parseRenderingOrgLimits(limits RenderingOrgLimits, settings map[string]string) (RenderingOrgLimits, error) {
+	var err error
+	if v := strings.TrimSpace(settings["org_renders_per_hour"]); v != "" {
+		if limits.RendersPerHour, err = strconv.Atoi(v); err != nil || limits.RendersPerHour < 0 {
+			return limits, fmt.Errorf("org_renders_per_hour must be a positive number of renders, got %q", v)
+		}
+	}
+	if v := strings.TrimSpace(settings["org_max_width"]); v != "" {
+		if limits.MaxWidth, err = strconv.Atoi(v); err != nil || limits.MaxWidth < 0 {
+			return limits, fmt.Errorf("org_max_width must be a positive number of pixels, got %q", v)
+		}
+	}
+	if v := strings.TrimSpace(settings["org_max_height"]); v != "" {
+		if limits.MaxHeight, err = strconv.Atoi(v); err != nil || limits.MaxHeight < 0 {
+			return limits, fmt.Errorf("org_max_height must be a positive number of pixels, got %q", v)
+		}
+	}
+	if v := strings.TrimSpace(settings["org_max_timeout"]); v != "" {
+		if limits.MaxTimeout, err = time.ParseDuration(v); err != nil || limits.MaxTimeout < 0 {
+			return limits, fmt.Errorf("org_max_timeout must be a positive duration such as 30s, got %q", v)
+		}
+	}
+
+	return limits, nil
+}
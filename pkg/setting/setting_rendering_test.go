@@ -0,0 +1,61 @@
+package setting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+)
+
+func TestRenderingOrgLimits(t *testing.T) {
+	iniFile, err := ini.Load([]byte(`
+[rendering]
+org_renders_per_hour = 100
+org_max_width = 1920
+org_max_timeout = 30s
+
+[rendering.org.2]
+org_renders_per_hour = 0
+org_max_height = 1080
+
+[rendering.org.3]
+org_max_timeout = 2m
+`))
+	require.NoError(t, err)
+
+	cfg := NewCfg()
+	cfg.RendererOrgLimits, cfg.RendererOrgLimitOverrides, err = readRenderingOrgLimits(iniFile)
+	require.NoError(t, err)
+
+	require.Equal(t, RenderingOrgLimits{RendersPerHour: 100, MaxWidth: 1920, MaxTimeout: 30 * time.Second}, cfg.RenderingLimits(1))
+	require.Equal(t, RenderingOrgLimits{MaxWidth: 1920, MaxHeight: 1080, MaxTimeout: 30 * time.Second}, cfg.RenderingLimits(2))
+	require.Equal(t, RenderingOrgLimits{RendersPerHour: 100, MaxWidth: 1920, MaxTimeout: 2 * time.Minute}, cfg.RenderingLimits(3))
+
+	for _, tc := range []struct {
+		ini string
+		err string
+	}{
+		{
+			ini: "[rendering]\norg_renders_per_hour = -1",
+			err: `invalid rendering limits in [rendering]: org_renders_per_hour must be a positive number of renders, got "-1"`,
+		},
+		{
+			ini: "[rendering.org.2]\norg_max_width = wide",
+			err: `invalid rendering limits in [rendering.org.2]: org_max_width must be a positive number of pixels, got "wide"`,
+		},
+		{
+			ini: "[rendering.org.2]\norg_max_timeout = 30",
+			err: `invalid rendering limits in [rendering.org.2]: org_max_timeout must be a positive duration such as 30s, got "30"`,
+		},
+		{
+			ini: "[rendering.org.main]\norg_max_height = 1080",
+			err: `invalid organization ID in section [rendering.org.main]`,
+		},
+	} {
+		iniFile, err := ini.Load([]byte(tc.ini))
+		require.NoError(t, err)
+		_, _, err = readRenderingOrgLimits(iniFile)
+		require.EqualError(t, err, tc.err)
+	}
+}
@@ -0,0 +1,56 @@
+package setting
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type SAMLSettings struct {
+	Enabled     bool
+	AllowSignUp bool
+
+	CertificatePath string
+	PrivateKeyPath  string
+	IdPMetadataPath string
+	IdPMetadataURL  string
+
+	MaxIssueDelay         time.Duration
+	MetadataValidDuration time.Duration
+	AllowIdPInitiated     bool
+
+	AssertionAttributeName   string
+	AssertionAttributeLogin  string
+	AssertionAttributeEmail  string
+	AssertionAttributeGroups string
+	AssertionAttributeRole   string
+
+	RoleValuesEditor       []string
+	RoleValuesAdmin        []string
+	RoleValuesGrafanaAdmin []string
+}
+
+func (cfg *Cfg) readSAMLSettings() {
+	sec := cfg.Raw.Section("auth.generic_saml")
+	cfg.SAML.Enabled = sec.Key("enabled").MustBool(false)
+	cfg.SAML.AllowSignUp = sec.Key("allow_sign_up").MustBool(true)
+
+	cfg.SAML.CertificatePath = valueAsString(sec, "certificate_path", "")
+	cfg.SAML.PrivateKeyPath = valueAsString(sec, "private_key_path", "")
+	cfg.SAML.IdPMetadataPath = valueAsString(sec, "idp_metadata_path", "")
+	cfg.SAML.IdPMetadataURL = valueAsString(sec, "idp_metadata_url", "")
+
+	cfg.SAML.MaxIssueDelay = sec.Key("max_issue_delay").MustDuration(90 * time.Second)
+	cfg.SAML.MetadataValidDuration = sec.Key("metadata_valid_duration").MustDuration(48 * time.Hour)
+	cfg.SAML.AllowIdPInitiated = sec.Key("allow_idp_initiated").MustBool(false)
+
+	cfg.SAML.AssertionAttributeName = valueAsString(sec, "assertion_attribute_name", "displayName")
+	cfg.SAML.AssertionAttributeLogin = valueAsString(sec, "assertion_attribute_login", "mail")
+	cfg.SAML.AssertionAttributeEmail = valueAsString(sec, "assertion_attribute_email", "mail")
+	cfg.SAML.AssertionAttributeGroups = valueAsString(sec, "assertion_attribute_groups", "")
+	cfg.SAML.AssertionAttributeRole = valueAsString(sec, "assertion_attribute_role", "")
+
+	cfg.SAML.RoleValuesEditor = util.SplitString(valueAsString(sec, "role_values_editor", ""))
+	cfg.SAML.RoleValuesAdmin = util.SplitString(valueAsString(sec, "role_values_admin", ""))
+	cfg.SAML.RoleValuesGrafanaAdmin = util.SplitString(valueAsString(sec, "role_values_grafana_admin", ""))
+}
@@ -0,0 +1,25 @@
+package setting
+
+type SmsSettings struct {
+	Enabled bool
+	// Provider is the service that sends the text messages, "twilio" or "webhook".
+	Provider   string
+	FromNumber string
+
+	TwilioAccountSID string
+	TwilioAuthToken  string
+
+	WebhookURL   string
+	WebhookToken string
+}
+
+func (cfg *Cfg) readSmsSettings() {
+	sec := cfg.Raw.Section("sms")
+	cfg.Sms.Enabled = sec.Key("enabled").MustBool(false)
+	cfg.Sms.Provider = sec.Key("provider").MustString("twilio")
+	cfg.Sms.FromNumber = sec.Key("from_number").String()
+	cfg.Sms.TwilioAccountSID = sec.Key("twilio_account_sid").String()
+	cfg.Sms.TwilioAuthToken = sec.Key("twilio_auth_token").String()
+	cfg.Sms.WebhookURL = sec.Key("webhook_url").String()
+	cfg.Sms.WebhookToken = sec.Key("webhook_token").String()
+}
@@ -3,8 +3,10 @@ package setting
 import "github.com/grafana/grafana/pkg/util"
 
 type SmtpSettings struct {
-	Enabled        bool
-	Host           string
+	Enabled bool
+	Host    string
+	// Hosts are the SMTP servers of the comma-separated host setting, in the order of priority.
+	Hosts          []string
 	User           string
 	Password       string
 	CertFile       string
@@ -15,8 +17,17 @@ type SmtpSettings struct {
 	StartTLSPolicy string
 	SkipVerify     bool
 
+	// AuthType is the authentication of the SMTP server, "plain" for the user and password,
+	// or "oauth2" for XOAUTH2 with an access token of the OAuth2 client credentials flow.
+	AuthType           string
+	OAuth2ClientID     string
+	OAuth2ClientSecret string
+	OAuth2TokenURL     string
+	OAuth2Scopes       []string
+
 	SendWelcomeEmailOnSignUp bool
 	TemplatesPatterns        []string
+	TemplatesDir             string
 	ContentTypes             []string
 }
 
@@ -24,6 +35,7 @@ func (cfg *Cfg) readSmtpSettings() {
 	sec := cfg.Raw.Section("smtp")
 	cfg.Smtp.Enabled = sec.Key("enabled").MustBool(false)
 	cfg.Smtp.Host = sec.Key("host").String()
+	cfg.Smtp.Hosts = util.SplitString(cfg.Smtp.Host)
 	cfg.Smtp.User = sec.Key("user").String()
 	cfg.Smtp.Password = sec.Key("password").String()
 	cfg.Smtp.CertFile = sec.Key("cert_file").String()
@@ -33,9 +45,15 @@ func (cfg *Cfg) readSmtpSettings() {
 	cfg.Smtp.EhloIdentity = sec.Key("ehlo_identity").String()
 	cfg.Smtp.StartTLSPolicy = sec.Key("startTLS_policy").String()
 	cfg.Smtp.SkipVerify = sec.Key("skip_verify").MustBool(false)
+	cfg.Smtp.AuthType = sec.Key("auth_type").In("plain", []string{"plain", "oauth2"})
+	cfg.Smtp.OAuth2ClientID = sec.Key("oauth2_client_id").String()
+	cfg.Smtp.OAuth2ClientSecret = sec.Key("oauth2_client_secret").String()
+	cfg.Smtp.OAuth2TokenURL = sec.Key("oauth2_token_url").String()
+	cfg.Smtp.OAuth2Scopes = util.SplitString(sec.Key("oauth2_scopes").String())
 
 	emails := cfg.Raw.Section("emails")
 	cfg.Smtp.SendWelcomeEmailOnSignUp = emails.Key("welcome_email_on_sign_up").MustBool(false)
 	cfg.Smtp.TemplatesPatterns = util.SplitString(emails.Key("templates_pattern").MustString("emails/*.html, emails/*.txt"))
+	cfg.Smtp.TemplatesDir = emails.Key("templates_dir").String()
 	cfg.Smtp.ContentTypes = util.SplitString(emails.Key("content_types").MustString("text/html"))
 }
@@ -391,6 +391,30 @@ func TestAuthDurationSettings(t *testing.T) {
 	require.Equal(t, maxLifetimeDurationTest, cfg.LoginMaxLifetime)
 }
 
+func TestBruteForceLockoutDurationSettings(t *testing.T) {
+	f := ini.Empty()
+	cfg := NewCfg()
+	err := readSecuritySettings(f, cfg)
+	require.NoError(t, err)
+	require.Equal(t, 5*time.Minute, cfg.BruteForceLockoutDuration)
+	require.Equal(t, 5*time.Minute, cfg.BruteForceMaxLockoutDuration)
+
+	f = ini.Empty()
+	sec, err := f.NewSection("security")
+	require.NoError(t, err)
+	_, err = sec.NewKey("brute_force_lockout_duration", "10m")
+	require.NoError(t, err)
+	err = readSecuritySettings(f, cfg)
+	require.NoError(t, err)
+	require.Equal(t, 10*time.Minute, cfg.BruteForceMaxLockoutDuration)
+
+	_, err = sec.NewKey("brute_force_max_lockout_duration", "24h")
+	require.NoError(t, err)
+	err = readSecuritySettings(f, cfg)
+	require.NoError(t, err)
+	require.Equal(t, 24*time.Hour, cfg.BruteForceMaxLockoutDuration)
+}
+
 func TestGetCDNPath(t *testing.T) {
 	var err error
 	cfg := NewCfg()
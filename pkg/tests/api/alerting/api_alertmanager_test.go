@@ -14,6 +14,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/bus"
 
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -24,6 +25,7 @@ import (
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	ngstore "github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/tests/testinfra"
 )
 
@@ -206,7 +208,7 @@ func TestAMConfigAccess(t *testing.T) {
 		{
 			"comment": "string",
 			"createdBy": "string",
-			"endsAt": "2023-03-31T14:17:04.419Z",
+			"endsAt": "2099-03-31T14:17:04.419Z",
 			"matchers": [
 			  {
 				"isRegex": true,
@@ -234,14 +236,14 @@ func TestAMConfigAccess(t *testing.T) {
 			{
 				desc:      "editor request should succeed",
 				url:       "http://editor:editor@%s/api/alertmanager/grafana/api/v2/silences",
-				expStatus: http.StatusAccepted,
-				expBody:   `{"id": "0", "message":"silence created"}`,
+				expStatus: http.StatusOK,
+				expBody:   `{"id": "0", "silenceID": "0", "message":"silence created"}`,
 			},
 			{
 				desc:      "admin request should succeed",
 				url:       "http://admin:admin@%s/api/alertmanager/grafana/api/v2/silences",
-				expStatus: http.StatusAccepted,
-				expBody:   `{"id": "0", "message":"silence created"}`,
+				expStatus: http.StatusOK,
+				expBody:   `{"id": "0", "silenceID": "0", "message":"silence created"}`,
 			},
 		}
 
@@ -258,9 +260,9 @@ func TestAMConfigAccess(t *testing.T) {
 				require.Equal(t, tc.expStatus, resp.StatusCode)
 				b, err := ioutil.ReadAll(resp.Body)
 				require.NoError(t, err)
-				if tc.expStatus == http.StatusAccepted {
-					re := regexp.MustCompile(`"id":"([\w|-]+)"`)
-					b = re.ReplaceAll(b, []byte(`"id":"0"`))
+				if tc.expStatus == http.StatusOK {
+					re := regexp.MustCompile(`"(id|silenceID)":"([\w|-]+)"`)
+					b = re.ReplaceAll(b, []byte(`"$1":"0"`))
 				}
 				require.JSONEq(t, tc.expBody, string(b))
 			})
@@ -774,7 +776,11 @@ func TestDeleteFolderWithRules(t *testing.T) {
 								"namespace_id": 1,
 								"rule_group": "arulegroup",
 								"no_data_state": "NoData",
-								"exec_err_state": "Alerting"
+								"exec_err_state": "Alerting",
+								"is_paused": false,
+								"no_data_threshold": 0,
+								"exec_err_threshold": 0,
+								"disable_dashboard_annotations": false
 							}
 						}
 					]
@@ -873,6 +879,7 @@ func TestAlertRuleCRUD(t *testing.T) {
 			desc             string
 			rulegroup        string
 			interval         model.Duration
+			offset           model.Duration
 			rule             apimodels.PostableExtendedRuleNode
 			expectedResponse string
 		}{
@@ -1013,6 +1020,102 @@ func TestAlertRuleCRUD(t *testing.T) {
 				},
 				expectedResponse: `{"message":"failed to update rule group: invalid alert rule: interval (1s) should be non-zero and divided exactly by scheduler interval: 10s"}`,
 			},
+			{
+				desc:      "alert rule with an evaluation offset as long as the interval",
+				rulegroup: "arulegroup",
+				interval:  interval,
+				offset:    interval,
+				rule: apimodels.PostableExtendedRuleNode{
+					ApiRuleNode: &apimodels.ApiRuleNode{
+						For:         interval,
+						Labels:      map[string]string{"label1": "val1"},
+						Annotations: map[string]string{"annotation1": "val1"},
+					},
+					GrafanaManagedAlert: &apimodels.PostableGrafanaRule{
+						Title:     "AlwaysFiring",
+						Condition: "A",
+						Data: []ngmodels.AlertQuery{
+							{
+								RefID: "A",
+								RelativeTimeRange: ngmodels.RelativeTimeRange{
+									From: ngmodels.Duration(time.Duration(5) * time.Hour),
+									To:   ngmodels.Duration(time.Duration(3) * time.Hour),
+								},
+								DatasourceUID: "-100",
+								Model: json.RawMessage(`{
+									"type": "math",
+									"expression": "2 + 3 > 1"
+									}`),
+							},
+						},
+					},
+				},
+				expectedResponse: `{"message":"failed to update rule group: invalid alert rule: evaluation offset (1m0s) should be shorter than the interval and divided exactly by scheduler interval: 10s"}`,
+			},
+			{
+				desc:      "alert rule with an unknown no data state",
+				rulegroup: "arulegroup",
+				interval:  interval,
+				rule: apimodels.PostableExtendedRuleNode{
+					ApiRuleNode: &apimodels.ApiRuleNode{
+						For:         interval,
+						Labels:      map[string]string{"label1": "val1"},
+						Annotations: map[string]string{"annotation1": "val1"},
+					},
+					GrafanaManagedAlert: &apimodels.PostableGrafanaRule{
+						Title:     "AlwaysFiring",
+						Condition: "A",
+						Data: []ngmodels.AlertQuery{
+							{
+								RefID: "A",
+								RelativeTimeRange: ngmodels.RelativeTimeRange{
+									From: ngmodels.Duration(time.Duration(5) * time.Hour),
+									To:   ngmodels.Duration(time.Duration(3) * time.Hour),
+								},
+								DatasourceUID: "-100",
+								Model: json.RawMessage(`{
+									"type": "math",
+									"expression": "2 + 3 > 1"
+									}`),
+							},
+						},
+						NoDataState: "Pending",
+					},
+				},
+				expectedResponse: `{"message":"failed to update rule group: invalid alert rule: unknown no data state \"Pending\""}`,
+			},
+			{
+				desc:      "alert rule with a negative no data threshold",
+				rulegroup: "arulegroup",
+				interval:  interval,
+				rule: apimodels.PostableExtendedRuleNode{
+					ApiRuleNode: &apimodels.ApiRuleNode{
+						For:         interval,
+						Labels:      map[string]string{"label1": "val1"},
+						Annotations: map[string]string{"annotation1": "val1"},
+					},
+					GrafanaManagedAlert: &apimodels.PostableGrafanaRule{
+						Title:     "AlwaysFiring",
+						Condition: "A",
+						Data: []ngmodels.AlertQuery{
+							{
+								RefID: "A",
+								RelativeTimeRange: ngmodels.RelativeTimeRange{
+									From: ngmodels.Duration(time.Duration(5) * time.Hour),
+									To:   ngmodels.Duration(time.Duration(3) * time.Hour),
+								},
+								DatasourceUID: "-100",
+								Model: json.RawMessage(`{
+									"type": "math",
+									"expression": "2 + 3 > 1"
+									}`),
+							},
+						},
+						NoDataThreshold: -1,
+					},
+				},
+				expectedResponse: `{"message":"failed to update rule group: invalid alert rule: no data and error thresholds should not be negative"}`,
+			},
 			{
 				desc:      "alert rule with unknown datasource",
 				rulegroup: "arulegroup",
@@ -1078,8 +1181,9 @@ func TestAlertRuleCRUD(t *testing.T) {
 		for _, tc := range testCases {
 			t.Run(tc.desc, func(t *testing.T) {
 				rules := apimodels.PostableRuleGroupConfig{
-					Name:     tc.rulegroup,
-					Interval: tc.interval,
+					Name:             tc.rulegroup,
+					Interval:         tc.interval,
+					EvaluationOffset: tc.offset,
 					Rules: []apimodels.PostableExtendedRuleNode{
 						tc.rule,
 					},
@@ -1254,7 +1358,11 @@ func TestAlertRuleCRUD(t *testing.T) {
 						  "namespace_id":1,
 						  "rule_group":"arulegroup",
 						  "no_data_state":"NoData",
-						  "exec_err_state":"Alerting"
+						  "exec_err_state":"Alerting",
+						  "is_paused":false,
+						  "no_data_threshold":0,
+						  "exec_err_threshold":0,
+						  "disable_dashboard_annotations":false
 					   }
 					},
 					{
@@ -1289,7 +1397,11 @@ func TestAlertRuleCRUD(t *testing.T) {
 						  "namespace_id":1,
 						  "rule_group":"arulegroup",
 						  "no_data_state":"Alerting",
-						  "exec_err_state":"Alerting"
+						  "exec_err_state":"Alerting",
+						  "is_paused":false,
+						  "no_data_threshold":0,
+						  "exec_err_threshold":0,
+						  "disable_dashboard_annotations":false
 					   }
 					}
 				 ]
@@ -1516,7 +1628,11 @@ func TestAlertRuleCRUD(t *testing.T) {
 		                  "namespace_id":1,
 		                  "rule_group":"arulegroup",
 		                  "no_data_state":"Alerting",
-		                  "exec_err_state":"Alerting"
+		                  "exec_err_state":"Alerting",
+		                  "is_paused":false,
+		                  "no_data_threshold":0,
+		                  "exec_err_threshold":0,
+		                  "disable_dashboard_annotations":false
 		               }
 		            }
 		         ]
@@ -1640,7 +1756,11 @@ func TestAlertRuleCRUD(t *testing.T) {
 					  "namespace_id":1,
 					  "rule_group":"arulegroup",
 					  "no_data_state":"Alerting",
-					  "exec_err_state":"Alerting"
+					  "exec_err_state":"Alerting",
+					  "is_paused":false,
+					  "no_data_threshold":0,
+					  "exec_err_threshold":0,
+					  "disable_dashboard_annotations":false
 				       }
 				    }
 				 ]
@@ -1739,7 +1859,11 @@ func TestAlertRuleCRUD(t *testing.T) {
 					  "namespace_id":1,
 					  "rule_group":"arulegroup",
 					  "no_data_state":"Alerting",
-					  "exec_err_state":"Alerting"
+					  "exec_err_state":"Alerting",
+					  "is_paused":false,
+					  "no_data_threshold":0,
+					  "exec_err_threshold":0,
+					  "disable_dashboard_annotations":false
 				       }
 				    }
 				 ]
@@ -1808,6 +1932,20 @@ func TestAlertmanagerStatus(t *testing.T) {
 		b, err := ioutil.ReadAll(resp.Body)
 		require.NoError(t, err)
 		require.Equal(t, 200, resp.StatusCode)
+
+		// the status can be read by the clients of the Alertmanager API
+		var amStatus amv2.AlertmanagerStatus
+		require.NoError(t, json.Unmarshal(b, &amStatus))
+		require.NotNil(t, amStatus.Config.Original)
+		require.Contains(t, *amStatus.Config.Original, "receiver: grafana-default-email")
+		require.NotNil(t, amStatus.Uptime)
+
+		var status map[string]interface{}
+		require.NoError(t, json.Unmarshal(b, &status))
+		delete(status["config"].(map[string]interface{}), "original")
+		delete(status, "uptime")
+		b, err = json.Marshal(status)
+		require.NoError(t, err)
 		require.JSONEq(t, `
 {
 	"cluster": {
@@ -1816,7 +1954,8 @@ func TestAlertmanagerStatus(t *testing.T) {
 	},
 	"config": {
 		"route": {
-			"receiver": "grafana-default-email"
+			"receiver": "grafana-default-email",
+			"group_by": ["alertname"]
 		},
 		"templates": null,
 		"receivers": [{
@@ -1833,7 +1972,6 @@ func TestAlertmanagerStatus(t *testing.T) {
 			}]
 		}]
 	},
-	"uptime": null,
 	"versionInfo": {
 		"branch": "N/A",
 		"buildDate": "N/A",
@@ -1847,6 +1985,76 @@ func TestAlertmanagerStatus(t *testing.T) {
 	}
 }
 
+func TestAlertmanagerAPICompatibility(t *testing.T) {
+	dir, path := testinfra.CreateGrafDir(t, testinfra.GrafanaOpts{
+		EnableFeatureToggles: []string{"ngalert"},
+		DisableAnonymous:     true,
+	})
+	store := testinfra.SetUpDatabase(t, dir)
+	// override bus to get the GetSignedInUserQuery handler
+	store.Bus = bus.GetBus()
+	grafanaListedAddr := testinfra.StartGrafana(t, dir, path, store)
+
+	createUser(t, store, models.CreateUserCommand{
+		DefaultOrgRole: string(models.ROLE_EDITOR),
+		Password:       "editor",
+		Login:          "editor",
+	})
+	createUser(t, store, models.CreateUserCommand{
+		DefaultOrgRole: string(models.ROLE_VIEWER),
+		Password:       "viewer",
+		Login:          "viewer",
+	})
+
+	// the alerts are posted as a JSON array, like amtool does
+	alerts := fmt.Sprintf(`[{
+		"labels": {"alertname": "DiskFull", "instance": "db-1"},
+		"annotations": {"summary": "The disk is full"},
+		"startsAt": %q,
+		"endsAt": %q
+	}]`, time.Now().Add(-time.Minute).Format(time.RFC3339), time.Now().Add(time.Hour).Format(time.RFC3339))
+
+	t.Run("viewers can't post alerts", func(t *testing.T) {
+		postRequest(t, fmt.Sprintf("http://viewer:viewer@%s/api/alertmanager/grafana/api/v2/alerts", grafanaListedAddr), alerts, http.StatusForbidden)
+	})
+
+	t.Run("the posted alerts are listed", func(t *testing.T) {
+		postRequest(t, fmt.Sprintf("http://editor:editor@%s/api/alertmanager/grafana/api/v2/alerts", grafanaListedAddr), alerts, http.StatusOK)
+
+		resp := getRequest(t, fmt.Sprintf("http://editor:editor@%s/api/alertmanager/grafana/api/v2/alerts", grafanaListedAddr), http.StatusOK)
+		var gettable amv2.GettableAlerts
+		require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &gettable))
+		require.Len(t, gettable, 1)
+		require.Equal(t, "DiskFull", gettable[0].Labels["alertname"])
+		require.Equal(t, "The disk is full", gettable[0].Annotations["summary"])
+	})
+
+	t.Run("invalid alerts are rejected", func(t *testing.T) {
+		postRequest(t, fmt.Sprintf("http://editor:editor@%s/api/alertmanager/grafana/api/v2/alerts", grafanaListedAddr), `[{"labels": {}}]`, http.StatusBadRequest)
+	})
+
+	t.Run("the ID of a created silence is returned like the Alertmanager does", func(t *testing.T) {
+		silence := fmt.Sprintf(`{
+			"comment": "maintenance",
+			"createdBy": "amtool",
+			"matchers": [{"isRegex": false, "name": "alertname", "value": "DiskFull"}],
+			"startsAt": %q,
+			"endsAt": %q
+		}`, time.Now().Format(time.RFC3339), time.Now().Add(time.Hour).Format(time.RFC3339))
+		resp := postRequest(t, fmt.Sprintf("http://editor:editor@%s/api/alertmanager/grafana/api/v2/silences", grafanaListedAddr), silence, http.StatusOK)
+		var created struct {
+			SilenceID string `json:"silenceID"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &created))
+		require.NotEmpty(t, created.SilenceID)
+
+		resp = getRequest(t, fmt.Sprintf("http://editor:editor@%s/api/alertmanager/grafana/api/v2/silence/%s", grafanaListedAddr, created.SilenceID), http.StatusOK)
+		var gettable amv2.GettableSilence
+		require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &gettable))
+		require.Equal(t, "maintenance", *gettable.Comment)
+	})
+}
+
 func TestQuota(t *testing.T) {
 	// Setup Grafana and its Database
 	dir, path := testinfra.CreateGrafDir(t, testinfra.GrafanaOpts{
@@ -1874,34 +2082,22 @@ func TestQuota(t *testing.T) {
 	interval, err := model.ParseDuration("1m")
 	require.NoError(t, err)
 
-	// check quota limits
-	t.Run("when quota limit exceed", func(t *testing.T) {
-		// get existing org quota
-		query := models.GetOrgQuotaByTargetQuery{OrgId: 1, Target: "alert_rule"}
-		err = sqlstore.GetOrgQuotaByTarget(&query)
-		require.NoError(t, err)
-		used := query.Result.Used
+	// setOrgQuotaToUsed sets the org quota of the target to its usage until the end of the test.
+	setOrgQuotaToUsed := func(t *testing.T, target string) {
+		t.Helper()
+		query := models.GetOrgQuotaByTargetQuery{OrgId: 1, Target: target, Default: setting.Quota.Org.ToMap()[target], IsNgAlertEnabled: true}
+		require.NoError(t, sqlstore.GetOrgQuotaByTarget(&query))
 		limit := query.Result.Limit
-
-		// set org quota limit to equal used
-		orgCmd := models.UpdateOrgQuotaCmd{
-			OrgId:  1,
-			Target: "alert_rule",
-			Limit:  used,
-		}
-		err := sqlstore.UpdateOrgQuota(&orgCmd)
-		require.NoError(t, err)
-
+		require.NoError(t, sqlstore.UpdateOrgQuota(&models.UpdateOrgQuotaCmd{OrgId: 1, Target: target, Limit: query.Result.Used}))
 		t.Cleanup(func() {
-			// reset org quota to original value
-			orgCmd := models.UpdateOrgQuotaCmd{
-				OrgId:  1,
-				Target: "alert_rule",
-				Limit:  limit,
-			}
-			err := sqlstore.UpdateOrgQuota(&orgCmd)
-			require.NoError(t, err)
+			require.NoError(t, sqlstore.UpdateOrgQuota(&models.UpdateOrgQuotaCmd{OrgId: 1, Target: target, Limit: limit}))
 		})
+	}
+
+	// check quota limits
+	t.Run("when quota limit exceed", func(t *testing.T) {
+		// set org quota limit to equal used
+		setOrgQuotaToUsed(t, "alert_rule")
 
 		// try to create an alert rule
 		rules := apimodels.PostableRuleGroupConfig{
@@ -1948,6 +2144,65 @@ func TestQuota(t *testing.T) {
 		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 		require.JSONEq(t, `{"message":"quota reached"}`, string(b))
 	})
+
+	rulesURL := fmt.Sprintf("http://grafana:password@%s/api/ruler/grafana/api/v1/rules/default", grafanaListedAddr)
+	ruleGroup := func(name, title string) string {
+		return fmt.Sprintf(`{
+			"name": %q,
+			"interval": "1m",
+			"rules": [{
+				"grafana_alert": {
+					"title": %q,
+					"condition": "A",
+					"data": [{
+						"refId": "A",
+						"relativeTimeRange": {"from": 18000, "to": 10800},
+						"datasourceUid": "-100",
+						"model": {"type": "math", "expression": "2 + 3 > 1"}
+					}]
+				}
+			}]
+		}`, name, title)
+	}
+
+	t.Run("when the quota of rule groups is reached", func(t *testing.T) {
+		postRequest(t, rulesURL, ruleGroup("existinggroup", "Existing alert rule"), http.StatusAccepted)
+
+		query := models.GetOrgQuotaByTargetQuery{OrgId: 1, Target: "alert_rule_group", IsNgAlertEnabled: true}
+		require.NoError(t, sqlstore.GetOrgQuotaByTarget(&query))
+		require.Equal(t, int64(1), query.Result.Used)
+		setOrgQuotaToUsed(t, "alert_rule_group")
+
+		resp := postRequest(t, rulesURL, ruleGroup("newgroup", "New alert rule"), http.StatusForbidden)
+		require.JSONEq(t, `{"message":"quota reached"}`, getBody(t, resp.Body))
+
+		// the existing rule groups can still be updated
+		postRequest(t, rulesURL, ruleGroup("existinggroup", "Updated alert rule"), http.StatusAccepted)
+	})
+
+	t.Run("when the quota of contact points is reached", func(t *testing.T) {
+		alertConfigURL := fmt.Sprintf("http://grafana:password@%s/api/alertmanager/grafana/config/api/v1/alerts", grafanaListedAddr)
+		config := func(receivers ...string) string {
+			configs := make([]string, 0, len(receivers))
+			for _, r := range receivers {
+				configs = append(configs, fmt.Sprintf(`{"name": %q, "grafana_managed_receiver_configs": [{"name": %q, "type": "email", "settings": {"addresses": "%s@example.com"}}]}`, r, r, r))
+			}
+			return fmt.Sprintf(`{
+				"alertmanager_config": {
+					"route": {"receiver": %q},
+					"receivers": [%s]
+				}
+			}`, receivers[0], strings.Join(configs, ","))
+		}
+		postRequest(t, alertConfigURL, config("ops"), http.StatusAccepted)
+		setOrgQuotaToUsed(t, "alert_contact_point")
+
+		resp := postRequest(t, alertConfigURL, config("ops", "dev"), http.StatusForbidden)
+		require.JSONEq(t, `{"message":"quota reached"}`, getBody(t, resp.Body))
+
+		// the existing contact points can still be replaced
+		postRequest(t, alertConfigURL, config("dev"), http.StatusAccepted)
+	})
 }
 
 func TestEval(t *testing.T) {
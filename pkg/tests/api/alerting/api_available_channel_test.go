@@ -114,16 +114,41 @@ var expAvailableChannelJsonOutput = `
   },
   {
     "type": "kafka",
-    "name": "Kafka REST Proxy",
+    "name": "Kafka",
     "heading": "Kafka settings",
-    "description": "Sends notifications to Kafka Rest Proxy",
+    "description": "Sends notifications to Kafka through a Kafka REST Proxy or the Kafka protocol",
     "info": "",
     "options": [
+      {
+        "element": "select",
+        "inputType": "",
+        "label": "Transport",
+        "description": "Publish the events through a Kafka REST Proxy, or directly to the brokers",
+        "placeholder": "",
+        "propertyName": "kafkaTransport",
+        "selectOptions": [
+          {
+            "value": "rest",
+            "label": "REST Proxy"
+          },
+          {
+            "value": "native",
+            "label": "Native"
+          }
+        ],
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
       {
         "element": "input",
         "inputType": "text",
         "label": "Kafka REST Proxy",
-        "description": "",
+        "description": "Required with the REST Proxy transport",
         "placeholder": "http://localhost:8082",
         "propertyName": "kafkaRestProxy",
         "selectOptions": null,
@@ -131,10 +156,74 @@ var expAvailableChannelJsonOutput = `
           "field": "",
           "is": ""
         },
-        "required": true,
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "input",
+        "inputType": "text",
+        "label": "Brokers",
+        "description": "Comma-separated list of the addresses of the brokers",
+        "placeholder": "broker1:9092,broker2:9092",
+        "propertyName": "kafkaBrokers",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "kafkaTransport",
+          "is": "native"
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "checkbox",
+        "inputType": "",
+        "label": "TLS",
+        "description": "Connect to the brokers with TLS",
+        "placeholder": "",
+        "propertyName": "kafkaTLS",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "kafkaTransport",
+          "is": "native"
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "input",
+        "inputType": "text",
+        "label": "Username",
+        "description": "Basic authentication of the REST Proxy, or SASL/PLAIN authentication of the brokers",
+        "placeholder": "",
+        "propertyName": "kafkaUsername",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
         "validationRule": "",
         "secure": false
       },
+      {
+        "element": "input",
+        "inputType": "password",
+        "label": "Password",
+        "description": "",
+        "placeholder": "",
+        "propertyName": "kafkaPassword",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": true
+      },
       {
         "element": "input",
         "inputType": "text",
@@ -150,6 +239,47 @@ var expAvailableChannelJsonOutput = `
         "required": true,
         "validationRule": "",
         "secure": false
+      },
+      {
+        "element": "select",
+        "inputType": "",
+        "label": "Format",
+        "description": "",
+        "placeholder": "",
+        "propertyName": "kafkaFormat",
+        "selectOptions": [
+          {
+            "value": "json",
+            "label": "JSON"
+          },
+          {
+            "value": "avro",
+            "label": "Avro"
+          }
+        ],
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "input",
+        "inputType": "text",
+        "label": "Schema registry",
+        "description": "The schema registry where the Avro schema of the events is registered, required with the native transport",
+        "placeholder": "http://localhost:8081",
+        "propertyName": "kafkaSchemaRegistry",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "kafkaFormat",
+          "is": "avro"
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
       }
     ]
   },
@@ -192,6 +322,22 @@ var expAvailableChannelJsonOutput = `
         "validationRule": "",
         "secure": false
       },
+      {
+        "element": "input",
+        "inputType": "text",
+        "label": "Subject",
+        "description": "Templated subject of the email",
+        "placeholder": "{{ template \"default.title\" . }}",
+        "propertyName": "subject",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
       {
         "element": "textarea",
         "inputType": "",
@@ -207,6 +353,22 @@ var expAvailableChannelJsonOutput = `
         "required": false,
         "validationRule": "",
         "secure": false
+      },
+      {
+        "element": "checkbox",
+        "inputType": "",
+        "label": "Embed images",
+        "description": "Embed the images of the panels in the email, even when they are uploaded to an external image storage, for the mail clients that block remote content",
+        "placeholder": "",
+        "propertyName": "embedImages",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
       }
     ]
   },
@@ -353,13 +515,13 @@ var expAvailableChannelJsonOutput = `
         },
         "required": true,
         "validationRule": "",
-        "secure": false
+        "secure": true
       },
       {
         "element": "select",
         "inputType": "",
         "label": "Message Type",
-        "description": "",
+        "description": "Leave empty to set the message type from the severity label of the alerts, CRITICAL by default.",
         "placeholder": "",
         "propertyName": "messageType",
         "selectOptions": [
@@ -370,6 +532,10 @@ var expAvailableChannelJsonOutput = `
           {
             "value": "WARNING",
             "label": "WARNING"
+          },
+          {
+            "value": "INFO",
+            "label": "INFO"
           }
         ],
         "showWhen": {
@@ -1234,6 +1400,10 @@ var expAvailableChannelJsonOutput = `
           {
             "value": "PUT",
             "label": "PUT"
+          },
+          {
+            "value": "PATCH",
+            "label": "PATCH"
           }
         ],
         "showWhen": {
@@ -1291,6 +1461,150 @@ var expAvailableChannelJsonOutput = `
         "required": false,
         "validationRule": "",
         "secure": false
+      },
+      {
+        "element": "input",
+        "inputType": "text",
+        "label": "Title",
+        "description": "Templated title field of the webhook payload",
+        "placeholder": "{{ template \"default.title\" . }}",
+        "propertyName": "title",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "textarea",
+        "inputType": "",
+        "label": "Message",
+        "description": "Templated message field of the webhook payload",
+        "placeholder": "{{ template \"default.message\" . }}",
+        "propertyName": "message",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "textarea",
+        "inputType": "",
+        "label": "Payload",
+        "description": "Templated body of the request, which replaces the webhook payload. The title and message are not sent when it's set.",
+        "placeholder": "",
+        "propertyName": "payload",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "input",
+        "inputType": "text",
+        "label": "Content Type",
+        "description": "Content type of the body of the request",
+        "placeholder": "application/json",
+        "propertyName": "contentType",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "textarea",
+        "inputType": "",
+        "label": "HTTP Headers",
+        "description": "Additional headers of the request, one \"Name: value\" header per line. The values are templated.",
+        "placeholder": "",
+        "propertyName": "httpHeaders",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "textarea",
+        "inputType": "",
+        "label": "TLS CA Certificate",
+        "description": "PEM encoded certificates of the CA of the receiver, instead of the CAs of the system",
+        "placeholder": "",
+        "propertyName": "tlsCACert",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "textarea",
+        "inputType": "",
+        "label": "TLS Client Certificate",
+        "description": "PEM encoded client certificate, for the receivers that require mutual TLS",
+        "placeholder": "",
+        "propertyName": "tlsClientCert",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "textarea",
+        "inputType": "",
+        "label": "TLS Client Key",
+        "description": "PEM encoded key of the client certificate",
+        "placeholder": "",
+        "propertyName": "tlsClientKey",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": true
+      },
+      {
+        "element": "checkbox",
+        "inputType": "",
+        "label": "Skip TLS Verify",
+        "description": "Don't verify the certificate of the receiver",
+        "placeholder": "",
+        "propertyName": "tlsSkipVerify",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
       }
     ]
   },
@@ -1574,7 +1888,7 @@ var expAvailableChannelJsonOutput = `
         "element": "checkbox",
         "inputType": "",
         "label": "Override priority",
-        "description": "Allow the alert priority to be set using the og_priority annotation",
+        "description": "Allow the alert priority to be set using the og_priority annotation or label, or else the severity label",
         "placeholder": "",
         "propertyName": "overridePriority",
         "selectOptions": null,
@@ -1616,6 +1930,339 @@ var expAvailableChannelJsonOutput = `
         "secure": false
       }
     ]
+  },
+  {
+    "type": "matrix",
+    "name": "Matrix",
+    "heading": "Matrix settings",
+    "description": "Sends HTML-formatted notifications to a Matrix room",
+    "info": "",
+    "options": [
+      {
+        "element": "input",
+        "inputType": "text",
+        "label": "Homeserver URL",
+        "description": "",
+        "placeholder": "https://matrix.org",
+        "propertyName": "homeserverUrl",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": true,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "input",
+        "inputType": "password",
+        "label": "Access token",
+        "description": "The access token of the user that sends the notifications, who must have joined the room",
+        "placeholder": "",
+        "propertyName": "accessToken",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": true,
+        "validationRule": "",
+        "secure": true
+      },
+      {
+        "element": "input",
+        "inputType": "text",
+        "label": "Room ID",
+        "description": "The internal ID of the room, in its advanced settings",
+        "placeholder": "!abcdefghijklmnop:matrix.org",
+        "propertyName": "roomId",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": true,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "textarea",
+        "inputType": "",
+        "label": "Message",
+        "description": "",
+        "placeholder": "{{ template \"default.message\" . }}",
+        "propertyName": "message",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      }
+    ]
+  },
+  {
+    "type": "sns",
+    "name": "AWS SNS",
+    "heading": "AWS SNS settings",
+    "description": "Publishes notifications to an AWS SNS topic",
+    "info": "",
+    "options": [
+      {
+        "element": "input",
+        "inputType": "text",
+        "label": "Topic ARN",
+        "description": "Topics whose name ends with .fifo are FIFO topics",
+        "placeholder": "arn:aws:sns:us-east-1:123456789012:alerts",
+        "propertyName": "topicArn",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": true,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "input",
+        "inputType": "text",
+        "label": "Region",
+        "description": "Defaults to the region of the topic",
+        "placeholder": "",
+        "propertyName": "region",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "input",
+        "inputType": "text",
+        "label": "Endpoint",
+        "description": "Custom endpoint of the SNS API, such as a VPC endpoint",
+        "placeholder": "",
+        "propertyName": "endpoint",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "select",
+        "inputType": "",
+        "label": "Authentication provider",
+        "description": "The AWS SDK default uses the environment, the shared credentials file or the IAM role of the instance",
+        "placeholder": "",
+        "propertyName": "authProvider",
+        "selectOptions": [
+          {
+            "value": "default",
+            "label": "AWS SDK default"
+          },
+          {
+            "value": "keys",
+            "label": "Access and secret key"
+          }
+        ],
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "input",
+        "inputType": "text",
+        "label": "Access key",
+        "description": "",
+        "placeholder": "",
+        "propertyName": "accessKey",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "authProvider",
+          "is": "keys"
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": true
+      },
+      {
+        "element": "input",
+        "inputType": "password",
+        "label": "Secret key",
+        "description": "",
+        "placeholder": "",
+        "propertyName": "secretKey",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "authProvider",
+          "is": "keys"
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": true
+      },
+      {
+        "element": "input",
+        "inputType": "text",
+        "label": "Assume role ARN",
+        "description": "The ARN of an IAM role to assume to publish the notifications",
+        "placeholder": "",
+        "propertyName": "assumeRoleArn",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "input",
+        "inputType": "text",
+        "label": "External ID",
+        "description": "The external ID required by the trust policy of the assumed role",
+        "placeholder": "",
+        "propertyName": "externalId",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "input",
+        "inputType": "text",
+        "label": "Subject",
+        "description": "Used by the email subscriptions, truncated to 100 characters",
+        "placeholder": "{{ template \"default.title\" . }}",
+        "propertyName": "subject",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "textarea",
+        "inputType": "",
+        "label": "Message",
+        "description": "",
+        "placeholder": "{{ template \"default.message\" . }}",
+        "propertyName": "message",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "select",
+        "inputType": "",
+        "label": "Message format",
+        "description": "JSON publishes the payload of the webhook contact point, with the message in its message field",
+        "placeholder": "",
+        "propertyName": "messageFormat",
+        "selectOptions": [
+          {
+            "value": "text",
+            "label": "Text"
+          },
+          {
+            "value": "json",
+            "label": "JSON"
+          }
+        ],
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "input",
+        "inputType": "text",
+        "label": "Message group ID",
+        "description": "The message group of FIFO topics, defaults to a hash of the alert group",
+        "placeholder": "",
+        "propertyName": "messageGroupId",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      }
+    ]
+  },
+  {
+    "type": "sms",
+    "name": "SMS",
+    "heading": "SMS settings",
+    "description": "Sends text messages using the Grafana server configured SMS provider",
+    "info": "",
+    "options": [
+      {
+        "element": "textarea",
+        "inputType": "",
+        "label": "Phone numbers",
+        "description": "Phone numbers in the international format. You can enter multiple phone numbers using a \";\" separator",
+        "placeholder": "+15551234567",
+        "propertyName": "phoneNumbers",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": true,
+        "validationRule": "",
+        "secure": false
+      },
+      {
+        "element": "textarea",
+        "inputType": "",
+        "label": "Message",
+        "description": "Templated text message, truncated to 1600 characters",
+        "placeholder": "{{ template \"default.title\" . }}",
+        "propertyName": "message",
+        "selectOptions": null,
+        "showWhen": {
+          "field": "",
+          "is": ""
+        },
+        "required": false,
+        "validationRule": "",
+        "secure": false
+      }
+    ]
   }
 ]
 `
@@ -0,0 +1,77 @@
+package alerting
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	ngstore "github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/tests/testinfra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationDeadLetters(t *testing.T) {
+	dir, path := testinfra.CreateGrafDir(t, testinfra.GrafanaOpts{
+		EnableFeatureToggles: []string{"ngalert"},
+		DisableAnonymous:     true,
+	})
+	store := testinfra.SetUpDatabase(t, dir)
+	// override bus to get the GetSignedInUserQuery handler
+	store.Bus = bus.GetBus()
+	grafanaListedAddr := testinfra.StartGrafana(t, dir, path, store)
+
+	createUser(t, store, models.CreateUserCommand{
+		DefaultOrgRole: string(models.ROLE_EDITOR),
+		Password:       "editor",
+		Login:          "editor",
+	})
+	createUser(t, store, models.CreateUserCommand{
+		DefaultOrgRole: string(models.ROLE_VIEWER),
+		Password:       "viewer",
+		Login:          "viewer",
+	})
+
+	dbstore := ngstore.DBstore{SQLStore: store}
+	require.NoError(t, dbstore.SaveNotificationDeadLetter(ngmodels.NotificationDeadLetter{
+		OrgID:           1,
+		Receiver:        "ops",
+		IntegrationName: "ops",
+		IntegrationType: "webhook",
+		Alerts:          ngmodels.NotificationDeadLetterAlerts{{"alertname": "DiskFull"}},
+		Attempts:        3,
+		Error:           "unavailable",
+		Epoch:           1000,
+	}))
+
+	deadLettersURL := fmt.Sprintf("http://editor:editor@%s/api/v1/ngalert/dead-letters", grafanaListedAddr)
+
+	t.Run("viewers can't list the failed notifications", func(t *testing.T) {
+		getRequest(t, fmt.Sprintf("http://viewer:viewer@%s/api/v1/ngalert/dead-letters", grafanaListedAddr), http.StatusForbidden)
+	})
+
+	t.Run("the failed notifications are listed", func(t *testing.T) {
+		resp := getRequest(t, deadLettersURL, http.StatusOK)
+		var result apimodels.GettableNotificationDeadLetters
+		require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &result))
+		require.Len(t, result.DeadLetters, 1)
+		d := result.DeadLetters[0]
+		assert.Equal(t, "ops", d.Receiver)
+		assert.Equal(t, "webhook", d.IntegrationType)
+		assert.Equal(t, []map[string]string{{"alertname": "DiskFull"}}, d.Alerts)
+		assert.Equal(t, 3, d.Attempts)
+		assert.Equal(t, "unavailable", d.Error)
+
+		resp = getRequest(t, deadLettersURL+"?receiver=dev", http.StatusOK)
+		require.JSONEq(t, `{"deadLetters": []}`, getBody(t, resp.Body))
+	})
+
+	t.Run("the limit is validated", func(t *testing.T) {
+		getRequest(t, deadLettersURL+"?limit=1001", http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,147 @@
+package alerting
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/tests/testinfra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertAcknowledgements(t *testing.T) {
+	dir, path := testinfra.CreateGrafDir(t, testinfra.GrafanaOpts{
+		EnableFeatureToggles: []string{"ngalert"},
+		DisableAnonymous:     true,
+	})
+	store := testinfra.SetUpDatabase(t, dir)
+	// override bus to get the GetSignedInUserQuery handler
+	store.Bus = bus.GetBus()
+	grafanaListedAddr := testinfra.StartGrafana(t, dir, path, store)
+
+	createUser(t, store, models.CreateUserCommand{
+		DefaultOrgRole: string(models.ROLE_EDITOR),
+		Password:       "editor",
+		Login:          "editor",
+	})
+	createUser(t, store, models.CreateUserCommand{
+		DefaultOrgRole: string(models.ROLE_VIEWER),
+		Password:       "viewer",
+		Login:          "viewer",
+	})
+
+	_, err := createFolder(t, store, 0, "default")
+	require.NoError(t, err)
+
+	postRequest(t, fmt.Sprintf("http://editor:editor@%s/api/ruler/grafana/api/v1/rules/default", grafanaListedAddr), `
+{
+	"name": "arulegroup",
+	"interval": "10s",
+	"rules": [{
+		"labels": {"severity": "critical"},
+		"grafana_alert": {
+			"title": "AlwaysFiring",
+			"condition": "A",
+			"exec_err_state": "Alerting",
+			"data": [{
+				"refId": "A",
+				"relativeTimeRange": {"from": 18000, "to": 10800},
+				"datasourceUid": "-100",
+				"model": {"type": "math", "expression": "2 + 3 > 1"}
+			}]
+		}
+	}]
+}`, http.StatusAccepted)
+
+	resp := getRequest(t, fmt.Sprintf("http://editor:editor@%s/api/ruler/grafana/api/v1/rules/default/arulegroup", grafanaListedAddr), http.StatusAccepted)
+	var group apimodels.GettableRuleGroupConfig
+	require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &group))
+	require.Len(t, group.Rules, 1)
+	ruleUID := group.Rules[0].GrafanaManagedAlert.UID
+
+	acknowledgeURL := fmt.Sprintf("http://editor:editor@%s/api/v1/ngalert/alerts/acknowledge", grafanaListedAddr)
+	unacknowledgeURL := fmt.Sprintf("http://editor:editor@%s/api/v1/ngalert/alerts/unacknowledge", grafanaListedAddr)
+	decode := func(t *testing.T, resp *http.Response) apimodels.AcknowledgeResult {
+		t.Helper()
+		var result apimodels.AcknowledgeResult
+		require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &result))
+		return result
+	}
+
+	t.Run("viewers can't acknowledge the alerts", func(t *testing.T) {
+		postRequest(t, fmt.Sprintf("http://viewer:viewer@%s/api/v1/ngalert/alerts/acknowledge", grafanaListedAddr), fmt.Sprintf(`{"ruleUid": %q}`, ruleUID), http.StatusForbidden)
+	})
+
+	t.Run("the alert rule must exist", func(t *testing.T) {
+		postRequest(t, acknowledgeURL, `{}`, http.StatusBadRequest)
+		postRequest(t, acknowledgeURL, `{"ruleUid": "unknown"}`, http.StatusNotFound)
+	})
+
+	// the first evaluation of the rule fires the alert
+	require.Eventually(t, func() bool {
+		resp := postRequest(t, acknowledgeURL, fmt.Sprintf(`{"ruleUid": %q, "labels": {"severity": "unknown"}}`, ruleUID), http.StatusOK)
+		if len(decode(t, resp).Alerts) > 0 {
+			t.Fatal("the alerts that don't match the labels must not be acknowledged")
+		}
+		resp = postRequest(t, unacknowledgeURL, fmt.Sprintf(`{"ruleUid": %q}`, ruleUID), http.StatusOK)
+		return len(decode(t, resp).Alerts) > 0
+	}, 30*time.Second, 500*time.Millisecond)
+
+	t.Run("the firing alerts are acknowledged", func(t *testing.T) {
+		resp := postRequest(t, acknowledgeURL, fmt.Sprintf(`{"ruleUid": %q, "labels": {"severity": "critical"}}`, ruleUID), http.StatusOK)
+		result := decode(t, resp)
+		require.Len(t, result.Alerts, 1)
+		assert.Equal(t, "editor", result.Alerts[0].AcknowledgedBy)
+		require.NotNil(t, result.Alerts[0].AcknowledgedAt)
+
+		resp = getRequest(t, fmt.Sprintf("http://editor:editor@%s/api/prometheus/grafana/api/v1/alerts", grafanaListedAddr), http.StatusOK)
+		var alerts apimodels.AlertResponse
+		require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &alerts))
+		require.Len(t, alerts.Data.Alerts, 1)
+		assert.Equal(t, "editor", alerts.Data.Alerts[0].AcknowledgedBy)
+
+		resp = postRequest(t, unacknowledgeURL, fmt.Sprintf(`{"ruleUid": %q}`, ruleUID), http.StatusOK)
+		result = decode(t, resp)
+		require.Len(t, result.Alerts, 1)
+		assert.Empty(t, result.Alerts[0].AcknowledgedBy)
+		assert.Nil(t, result.Alerts[0].AcknowledgedAt)
+	})
+
+	t.Run("the receivers of the escalation chains must be defined", func(t *testing.T) {
+		alertConfigURL := fmt.Sprintf("http://editor:editor@%s/api/alertmanager/grafana/config/api/v1/alerts", grafanaListedAddr)
+		config := func(receiver string) string {
+			return fmt.Sprintf(`{
+				"alertmanager_config": {
+					"route": {"receiver": "on-call"},
+					"escalation_chains": [{
+						"name": "critical",
+						"matchers": ["severity=\"critical\""],
+						"steps": [{"after": "15m", "receiver": %q}]
+					}],
+					"receivers": [{
+						"name": "on-call",
+						"grafana_managed_receiver_configs": [{
+							"name": "webhook",
+							"type": "webhook",
+							"settings": {"url": "http://localhost:9999"}
+						}]
+					}]
+				}
+			}`, receiver)
+		}
+		postRequest(t, alertConfigURL, config("managers"), http.StatusBadRequest)
+		postRequest(t, alertConfigURL, config("on-call"), http.StatusAccepted)
+
+		resp := getRequest(t, alertConfigURL, http.StatusOK)
+		var cfg apimodels.GettableUserConfig
+		require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &cfg))
+		require.Len(t, cfg.AlertmanagerConfig.EscalationChains, 1)
+		assert.Equal(t, "on-call", cfg.AlertmanagerConfig.EscalationChains[0].Steps[0].Receiver)
+	})
+}
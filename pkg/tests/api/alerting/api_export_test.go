@@ -0,0 +1,133 @@
+package alerting
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/tests/testinfra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestAlertingExportImport(t *testing.T) {
+	dir, path := testinfra.CreateGrafDir(t, testinfra.GrafanaOpts{
+		EnableFeatureToggles: []string{"ngalert"},
+		DisableAnonymous:     true,
+	})
+	store := testinfra.SetUpDatabase(t, dir)
+	// override bus to get the GetSignedInUserQuery handler
+	store.Bus = bus.GetBus()
+	grafanaListedAddr := testinfra.StartGrafana(t, dir, path, store)
+
+	createUser(t, store, models.CreateUserCommand{
+		DefaultOrgRole: string(models.ROLE_EDITOR),
+		Password:       "editor",
+		Login:          "editor",
+	})
+	createUser(t, store, models.CreateUserCommand{
+		DefaultOrgRole: string(models.ROLE_VIEWER),
+		Password:       "viewer",
+		Login:          "viewer",
+	})
+
+	_, err := createFolder(t, store, 0, "folder1")
+	require.NoError(t, err)
+	createRule(t, grafanaListedAddr, "folder1", "editor", "editor")
+
+	alertConfigURL := fmt.Sprintf("http://editor:editor@%s/api/alertmanager/grafana/config/api/v1/alerts", grafanaListedAddr)
+	postRequest(t, alertConfigURL, `
+{
+	"alertmanager_config": {
+		"route": {
+			"receiver": "slack.receiver"
+		},
+		"receivers": [{
+			"name": "slack.receiver",
+			"grafana_managed_receiver_configs": [{
+				"settings": {
+					"recipient": "#unified-alerting-test"
+				},
+				"secureSettings": {
+					"url": "http://averysecureurl.com/webhook"
+				},
+				"type": "slack",
+				"name": "slack.receiver"
+			}]
+		}]
+	}
+}`, http.StatusAccepted)
+
+	exportURL := fmt.Sprintf("http://editor:editor@%s/api/v1/ngalert/export", grafanaListedAddr)
+	importURL := fmt.Sprintf("http://editor:editor@%s/api/v1/ngalert/import", grafanaListedAddr)
+
+	export := func(t *testing.T) (string, apimodels.AlertingExport) {
+		t.Helper()
+		resp := getRequest(t, exportURL, http.StatusOK)
+		assert.Equal(t, "application/yaml", resp.Header.Get("Content-Type"))
+		body := getBody(t, resp.Body)
+
+		var exported apimodels.AlertingExport
+		require.NoError(t, yaml.Unmarshal([]byte(body), &exported))
+		return body, exported
+	}
+
+	t.Run("viewers can't export", func(t *testing.T) {
+		getRequest(t, fmt.Sprintf("http://viewer:viewer@%s/api/v1/ngalert/export", grafanaListedAddr), http.StatusForbidden)
+	})
+
+	body, exported := export(t)
+	require.Len(t, exported.Groups, 1)
+	assert.Equal(t, "folder1", exported.Groups[0].Folder)
+	assert.Equal(t, "arulegroup", exported.Groups[0].Name)
+	require.Len(t, exported.Groups[0].Rules, 1)
+	ruleUID := exported.Groups[0].Rules[0].GrafanaManagedAlert.UID
+	assert.Equal(t, "rule under folder folder1", exported.Groups[0].Rules[0].GrafanaManagedAlert.Title)
+
+	require.NotNil(t, exported.Alertmanager)
+	receivers := exported.Alertmanager.GetGrafanaReceiverMap()
+	require.Len(t, receivers, 1)
+	for _, receiver := range receivers {
+		assert.Empty(t, receiver.SecureSettings)
+	}
+	assert.NotContains(t, body, "averysecureurl")
+
+	t.Run("importing the export again leaves the configuration unchanged", func(t *testing.T) {
+		postRequest(t, importURL, body, http.StatusAccepted)
+
+		reimported, exported := export(t)
+		assert.Equal(t, body, reimported)
+		assert.Equal(t, ruleUID, exported.Groups[0].Rules[0].GrafanaManagedAlert.UID)
+
+		// the secure settings of the contact point are kept
+		resp := getRequest(t, alertConfigURL, http.StatusOK)
+		assert.Contains(t, getBody(t, resp.Body), `"secureFields":{"url":true}`)
+	})
+
+	t.Run("rules of another instance are created with their UID", func(t *testing.T) {
+		document := strings.ReplaceAll(body, ruleUID, "imported-uid")
+		document = strings.ReplaceAll(document, "arulegroup", "importedgroup")
+		document = strings.ReplaceAll(document, "rule under folder folder1", "imported rule")
+		postRequest(t, importURL, document, http.StatusAccepted)
+
+		_, exported := export(t)
+		require.Len(t, exported.Groups, 2)
+		assert.Equal(t, "importedgroup", exported.Groups[1].Name)
+		assert.Equal(t, "imported-uid", exported.Groups[1].Rules[0].GrafanaManagedAlert.UID)
+	})
+
+	t.Run("groups of unknown folders are rejected", func(t *testing.T) {
+		document := strings.ReplaceAll(body, "folder: folder1", "folder: unknown")
+		postRequest(t, importURL, document, http.StatusNotFound)
+	})
+
+	t.Run("invalid documents are rejected", func(t *testing.T) {
+		postRequest(t, importURL, "apiVersion: 2", http.StatusBadRequest)
+		postRequest(t, importURL, "groups: [", http.StatusBadRequest)
+	})
+}
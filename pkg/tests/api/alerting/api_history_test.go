@@ -0,0 +1,101 @@
+package alerting
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/tests/testinfra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertStateHistory(t *testing.T) {
+	dir, path := testinfra.CreateGrafDir(t, testinfra.GrafanaOpts{
+		EnableFeatureToggles: []string{"ngalert"},
+		DisableAnonymous:     true,
+	})
+	store := testinfra.SetUpDatabase(t, dir)
+	// override bus to get the GetSignedInUserQuery handler
+	store.Bus = bus.GetBus()
+	grafanaListedAddr := testinfra.StartGrafana(t, dir, path, store)
+
+	createUser(t, store, models.CreateUserCommand{
+		DefaultOrgRole: string(models.ROLE_EDITOR),
+		Password:       "editor",
+		Login:          "editor",
+	})
+	createUser(t, store, models.CreateUserCommand{
+		DefaultOrgRole: string(models.ROLE_VIEWER),
+		Password:       "viewer",
+		Login:          "viewer",
+	})
+
+	_, err := createFolder(t, store, 0, "default")
+	require.NoError(t, err)
+
+	postRequest(t, fmt.Sprintf("http://editor:editor@%s/api/ruler/grafana/api/v1/rules/default", grafanaListedAddr), `
+{
+	"name": "arulegroup",
+	"interval": "10s",
+	"rules": [{
+		"labels": {"label1": "val1"},
+		"grafana_alert": {
+			"title": "AlwaysFiring",
+			"condition": "A",
+			"data": [{
+				"refId": "A",
+				"relativeTimeRange": {"from": 18000, "to": 10800},
+				"datasourceUid": "-100",
+				"model": {"type": "math", "expression": "2 + 3 > 1"}
+			}]
+		}
+	}]
+}`, http.StatusAccepted)
+
+	historyURL := fmt.Sprintf("http://viewer:viewer@%s/api/v1/ngalert/history", grafanaListedAddr)
+	getHistory := func(t *testing.T, url string) apimodels.GettableAlertStateHistory {
+		t.Helper()
+		resp := getRequest(t, url, http.StatusOK)
+		var history apimodels.GettableAlertStateHistory
+		require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &history))
+		return history
+	}
+
+	// the first evaluation of the rule fires the alert
+	var history apimodels.GettableAlertStateHistory
+	require.Eventually(t, func() bool {
+		history = getHistory(t, historyURL)
+		return len(history.History) > 0
+	}, 30*time.Second, 500*time.Millisecond)
+
+	transition := history.History[0]
+	assert.Equal(t, "AlwaysFiring", transition.RuleTitle)
+	assert.NotEmpty(t, transition.RuleUID)
+	assert.Equal(t, "Normal", transition.PreviousState)
+	assert.Equal(t, "Alerting", transition.State)
+	assert.Equal(t, "val1", transition.Labels["label1"])
+	assert.False(t, transition.Time.IsZero())
+
+	t.Run("transitions can be filtered", func(t *testing.T) {
+		history := getHistory(t, fmt.Sprintf("%s?ruleUID=%s&state=Alerting&limit=1", historyURL, transition.RuleUID))
+		require.Len(t, history.History, 1)
+		assert.Equal(t, transition, history.History[0])
+
+		history = getHistory(t, fmt.Sprintf("%s?ruleUID=unknown", historyURL))
+		assert.Empty(t, history.History)
+
+		history = getHistory(t, fmt.Sprintf("%s?state=NoData", historyURL))
+		assert.Empty(t, history.History)
+	})
+
+	t.Run("invalid filters are rejected", func(t *testing.T) {
+		getRequest(t, fmt.Sprintf("%s?state=Firing", historyURL), http.StatusBadRequest)
+		getRequest(t, fmt.Sprintf("%s?limit=5000", historyURL), http.StatusBadRequest)
+	})
+}
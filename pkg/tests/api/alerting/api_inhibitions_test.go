@@ -0,0 +1,135 @@
+package alerting
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/tests/testinfra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInhibitRules(t *testing.T) {
+	dir, path := testinfra.CreateGrafDir(t, testinfra.GrafanaOpts{
+		EnableFeatureToggles: []string{"ngalert"},
+		DisableAnonymous:     true,
+	})
+	store := testinfra.SetUpDatabase(t, dir)
+	// override bus to get the GetSignedInUserQuery handler
+	store.Bus = bus.GetBus()
+	grafanaListedAddr := testinfra.StartGrafana(t, dir, path, store)
+
+	createUser(t, store, models.CreateUserCommand{
+		DefaultOrgRole: string(models.ROLE_EDITOR),
+		Password:       "editor",
+		Login:          "editor",
+	})
+	createUser(t, store, models.CreateUserCommand{
+		DefaultOrgRole: string(models.ROLE_VIEWER),
+		Password:       "viewer",
+		Login:          "viewer",
+	})
+
+	inhibitRulesURL := fmt.Sprintf("http://editor:editor@%s/api/v1/ngalert/inhibit-rules", grafanaListedAddr)
+	inhibitRules := `
+{
+	"inhibit_rules": [{
+		"source_matchers": ["severity=\"critical\""],
+		"target_matchers": ["severity=\"warning\""],
+		"equal": ["datacenter"]
+	}]
+}`
+
+	t.Run("viewers can't manage the inhibition rules", func(t *testing.T) {
+		getRequest(t, fmt.Sprintf("http://viewer:viewer@%s/api/v1/ngalert/inhibit-rules", grafanaListedAddr), http.StatusForbidden)
+		putRequest(t, fmt.Sprintf("http://viewer:viewer@%s/api/v1/ngalert/inhibit-rules", grafanaListedAddr), inhibitRules, http.StatusForbidden)
+	})
+
+	t.Run("the default configuration has no inhibition rules", func(t *testing.T) {
+		resp := getRequest(t, inhibitRulesURL, http.StatusOK)
+		require.JSONEq(t, `{"inhibit_rules": []}`, getBody(t, resp.Body))
+	})
+
+	t.Run("invalid inhibition rules are rejected", func(t *testing.T) {
+		putRequest(t, inhibitRulesURL, `{"inhibit_rules": [{"source_match": {"invalid-label": "critical"}}]}`, http.StatusBadRequest)
+	})
+
+	t.Run("the inhibition rules are replaced", func(t *testing.T) {
+		putRequest(t, inhibitRulesURL, inhibitRules, http.StatusAccepted)
+
+		resp := getRequest(t, inhibitRulesURL, http.StatusOK)
+		var rules apimodels.InhibitRules
+		require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &rules))
+		require.Len(t, rules.InhibitRules, 1)
+		assert.Equal(t, `severity="critical"`, rules.InhibitRules[0].SourceMatchers[0].String())
+		assert.Equal(t, `severity="warning"`, rules.InhibitRules[0].TargetMatchers[0].String())
+		assert.Equal(t, "datacenter", rules.InhibitRules[0].Equal.String())
+
+		// the rest of the configuration is unchanged
+		resp = getRequest(t, fmt.Sprintf("http://editor:editor@%s/api/alertmanager/grafana/config/api/v1/alerts", grafanaListedAddr), http.StatusOK)
+		var cfg apimodels.GettableUserConfig
+		require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &cfg))
+		assert.Equal(t, "grafana-default-email", cfg.AlertmanagerConfig.Route.Receiver)
+		require.Len(t, cfg.AlertmanagerConfig.InhibitRules, 1)
+	})
+
+	t.Run("the firing alerts inhibit the matching alerts", func(t *testing.T) {
+		_, err := createFolder(t, store, 0, "default")
+		require.NoError(t, err)
+
+		rule := func(title, severity string) string {
+			return fmt.Sprintf(`{
+				"labels": {"severity": %q, "datacenter": "eu"},
+				"grafana_alert": {
+					"title": %q,
+					"condition": "A",
+					"exec_err_state": "Alerting",
+					"data": [{
+						"refId": "A",
+						"relativeTimeRange": {"from": 18000, "to": 10800},
+						"datasourceUid": "-100",
+						"model": {"type": "math", "expression": "2 + 3 > 1"}
+					}]
+				}
+			}`, severity, title)
+		}
+		postRequest(t, fmt.Sprintf("http://editor:editor@%s/api/ruler/grafana/api/v1/rules/default", grafanaListedAddr), fmt.Sprintf(`{
+			"name": "datacenter",
+			"interval": "10s",
+			"rules": [%s, %s]
+		}`, rule("DatacenterDown", "critical"), rule("HostDown", "warning")), http.StatusAccepted)
+
+		alertsURL := fmt.Sprintf("http://editor:editor@%s/api/alertmanager/grafana/api/v2/alerts", grafanaListedAddr)
+		var alerts apimodels.GettableAlerts
+		require.Eventually(t, func() bool {
+			resp := getRequest(t, alertsURL, http.StatusOK)
+			alerts = nil
+			require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &alerts))
+			return len(alerts) == 2
+		}, 30*time.Second, 500*time.Millisecond)
+
+		for _, a := range alerts {
+			switch a.Labels["alertname"] {
+			case "DatacenterDown":
+				assert.Empty(t, a.Status.InhibitedBy)
+			case "HostDown":
+				assert.Len(t, a.Status.InhibitedBy, 1)
+			default:
+				t.Fatalf("unexpected alert %s", a.Labels["alertname"])
+			}
+		}
+
+		// the inhibited alerts are filtered out on request
+		resp := getRequest(t, alertsURL+"?inhibited=false", http.StatusOK)
+		alerts = nil
+		require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &alerts))
+		require.Len(t, alerts, 1)
+		assert.Equal(t, "DatacenterDown", alerts[0].Labels["alertname"])
+	})
+}
@@ -0,0 +1,183 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/tests/testinfra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLegacyAlertingMigration(t *testing.T) {
+	dir, path := testinfra.CreateGrafDir(t, testinfra.GrafanaOpts{
+		EnableFeatureToggles: []string{"ngalert"},
+		DisableAnonymous:     true,
+	})
+	store := testinfra.SetUpDatabase(t, dir)
+	// override bus to get the GetSignedInUserQuery handler
+	store.Bus = bus.GetBus()
+	grafanaListedAddr := testinfra.StartGrafana(t, dir, path, store)
+
+	createUser(t, store, models.CreateUserCommand{
+		DefaultOrgRole: string(models.ROLE_ADMIN),
+		Password:       "admin",
+		Login:          "admin",
+	})
+	createUser(t, store, models.CreateUserCommand{
+		DefaultOrgRole: string(models.ROLE_EDITOR),
+		Password:       "editor",
+		Login:          "editor",
+	})
+
+	createLegacyAlerts(t, store)
+
+	migrate := func(t *testing.T, user string, dryRun bool, expStatusCode int) apimodels.LegacyAlertingMigrationReport {
+		t.Helper()
+		u := fmt.Sprintf("http://%s:%s@%s/api/v1/ngalert/migrate", user, user, grafanaListedAddr)
+		resp := postRequest(t, u, fmt.Sprintf(`{"dryRun": %t}`, dryRun), expStatusCode)
+		var report apimodels.LegacyAlertingMigrationReport
+		if expStatusCode == http.StatusOK {
+			require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &report))
+		}
+		return report
+	}
+	getRules := func(t *testing.T) apimodels.NamespaceConfigResponse {
+		t.Helper()
+		resp := getRequest(t, fmt.Sprintf("http://admin:admin@%s/api/ruler/grafana/api/v1/rules", grafanaListedAddr), http.StatusAccepted)
+		var rules apimodels.NamespaceConfigResponse
+		require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &rules))
+		return rules
+	}
+
+	t.Run("editors can't migrate", func(t *testing.T) {
+		migrate(t, "editor", true, http.StatusForbidden)
+	})
+
+	t.Run("a dry run reports the migration without migrating", func(t *testing.T) {
+		report := migrate(t, "admin", true, http.StatusOK)
+		assert.True(t, report.DryRun)
+
+		require.Len(t, report.MigratedAlerts, 1)
+		assert.Equal(t, "High CPU", report.MigratedAlerts[0].Name)
+		assert.True(t, report.MigratedAlerts[0].Paused)
+
+		require.Len(t, report.UnmigratedAlerts, 1)
+		assert.Equal(t, "Broken", report.UnmigratedAlerts[0].Name)
+		assert.Equal(t, "unexpected number of query parameters in cond 1, want 3 got 2", report.UnmigratedAlerts[0].Error)
+
+		assert.Equal(t, []string{"autogen-contact-point-default", "autogen-contact-point-1"}, report.ContactPoints)
+		require.Len(t, report.UnmigratedChannels, 1)
+		assert.Equal(t, "legacy-hipchat", report.UnmigratedChannels[0].UID)
+
+		assert.Empty(t, getRules(t))
+	})
+
+	t.Run("the migration saves the alert rules and the contact points", func(t *testing.T) {
+		report := migrate(t, "admin", false, http.StatusOK)
+		assert.False(t, report.DryRun)
+		require.Len(t, report.MigratedAlerts, 1)
+
+		rules := getRules(t)
+		require.Len(t, rules, 1)
+		for _, groups := range rules {
+			require.Len(t, groups, 1)
+			require.Len(t, groups[0].Rules, 1)
+			rule := groups[0].Rules[0].GrafanaManagedAlert
+			assert.Equal(t, "High CPU", rule.Title)
+			assert.Equal(t, report.MigratedAlerts[0].RuleUID, rule.UID)
+			assert.True(t, rule.IsPaused)
+		}
+
+		resp := getRequest(t, fmt.Sprintf("http://admin:admin@%s/api/alertmanager/grafana/config/api/v1/alerts", grafanaListedAddr), http.StatusOK)
+		var cfg struct {
+			AlertmanagerConfig struct {
+				Receivers []struct {
+					Name                    string `json:"name"`
+					GrafanaManagedReceivers []struct {
+						Name string `json:"name"`
+						Type string `json:"type"`
+					} `json:"grafana_managed_receiver_configs"`
+				} `json:"receivers"`
+			} `json:"alertmanager_config"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &cfg))
+		integrations := map[string]string{}
+		for _, r := range cfg.AlertmanagerConfig.Receivers {
+			for _, i := range r.GrafanaManagedReceivers {
+				integrations[i.Name] = r.Name
+			}
+		}
+		assert.Equal(t, map[string]string{"Ops": "autogen-contact-point-1"}, integrations)
+	})
+
+	t.Run("the dashboard alerts can't be migrated twice", func(t *testing.T) {
+		migrate(t, "admin", true, http.StatusConflict)
+	})
+}
+
+// createLegacyAlerts creates a dashboard with a dashboard alert that can be migrated and one that can't,
+// and the notification channels of the alerts.
+func createLegacyAlerts(t *testing.T, store *sqlstore.SQLStore) {
+	t.Helper()
+
+	err := store.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+		for _, n := range []*models.AlertNotification{
+			{OrgId: 1, Uid: "legacy-slack", Name: "Ops", Type: "slack", Settings: simplejson.NewFromAny(map[string]interface{}{"url": "http://localhost/slack"})},
+			{OrgId: 1, Uid: "legacy-hipchat", Name: "HipChat", Type: "hipchat", Settings: simplejson.New()},
+		} {
+			n.Created = time.Now()
+			n.Updated = time.Now()
+			if _, err := sess.MustCols("send_reminder").Insert(n); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	dash, err := store.SaveDashboard(models.SaveDashboardCommand{
+		OrgId: 1,
+		Dashboard: simplejson.NewFromAny(map[string]interface{}{
+			"title": "Legacy",
+		}),
+	})
+	require.NoError(t, err)
+
+	settings := func(params ...string) *simplejson.Json {
+		return simplejson.NewFromAny(map[string]interface{}{
+			"noDataState":         "no_data",
+			"executionErrorState": "alerting",
+			"conditions": []interface{}{map[string]interface{}{
+				"evaluator": map[string]interface{}{"params": []float64{80}, "type": "gt"},
+				"operator":  map[string]interface{}{"type": "and"},
+				"query": map[string]interface{}{
+					"params": params,
+					"model":  map[string]interface{}{"refId": "A", "expr": "cpu"},
+				},
+				"reducer": map[string]interface{}{"type": "avg"},
+			}},
+			"notifications": []interface{}{map[string]interface{}{"uid": "legacy-slack"}},
+		})
+	}
+	err = store.SaveAlerts(dash.Id, []*models.Alert{
+		{OrgId: 1, DashboardId: dash.Id, PanelId: 1, Name: "High CPU", Frequency: 60, Settings: settings("A", "5m", "now")},
+		{OrgId: 1, DashboardId: dash.Id, PanelId: 2, Name: "Broken", Frequency: 60, Settings: settings("A", "5m")},
+	})
+	require.NoError(t, err)
+
+	err = store.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+		_, err := sess.Exec("UPDATE alert SET state = ? WHERE name = ?", models.AlertStatePaused, "High CPU")
+		return err
+	})
+	require.NoError(t, err)
+}
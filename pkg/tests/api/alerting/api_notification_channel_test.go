@@ -619,11 +619,6 @@ func (nc *mockNotificationChannel) matchesExpNotifications(t *testing.T, exp map
 			case "webhook_recv/webhook_test":
 				// It has a time component "startsAt".
 				r1 = regexp.MustCompile(`.*"startsAt"\s*:\s*"([^"]+)"`)
-			case "slack_recvX/slack_testX":
-				fallthrough
-			case "slack_recv1/slack_test_without_token":
-				// It has a time component "ts".
-				r1 = regexp.MustCompile(`.*"ts"\s*:\s*([0-9]+)`)
 			case "sensugo/events":
 				// It has a time component "ts".
 				r1 = regexp.MustCompile(`.*"issued"\s*:\s*([0-9]+)`)
@@ -1752,16 +1747,47 @@ var expNonEmailNotifications = map[string][]string{
 		  "username": "Integration Test",
 		  "icon_emoji": "🚀",
 		  "icon_url": "https://awesomeemoji.com/rocket",
+		  "text": "Integration Test [FIRING:1] SlackAlert1 ",
 		  "attachments": [
 			{
-			  "title": "Integration Test [FIRING:1] SlackAlert1 ",
-			  "title_link": "http://localhost:3000/alerting/list",
-			  "text": "Integration Test ",
-			  "fallback": "Integration Test [FIRING:1] SlackAlert1 ",
-			  "footer": "Grafana v",
-			  "footer_icon": "https://grafana.com/assets/img/fav32.png",
 			  "color": "#D63232",
-			  "ts": %s
+			  "fallback": "Integration Test [FIRING:1] SlackAlert1 ",
+			  "blocks": [
+				{
+				  "type": "header",
+				  "text": {"type": "plain_text", "text": "Integration Test [FIRING:1] SlackAlert1 "}
+				},
+				{
+				  "type": "section",
+				  "text": {"type": "mrkdwn", "text": "Integration Test "}
+				},
+				{
+				  "type": "section",
+				  "fields": [{"type": "mrkdwn", "text": "*alertname*\nSlackAlert1"}]
+				},
+				{
+				  "type": "actions",
+				  "elements": [
+					{
+					  "type": "button",
+					  "text": {"type": "plain_text", "text": "View alert rule"},
+					  "url": "http://localhost:3000/alerting/UID_SlackAlert1/edit"
+					},
+					{
+					  "type": "button",
+					  "text": {"type": "plain_text", "text": "Silence"},
+					  "url": "http://localhost:3000/alerting/silence/new?alertmanager=grafana&matchers=alertname%3DSlackAlert1"
+					}
+				  ]
+				},
+				{
+				  "type": "context",
+				  "elements": [
+					{"type": "image", "image_url": "https://grafana.com/assets/img/fav32.png", "alt_text": "Grafana"},
+					{"type": "mrkdwn", "text": "Grafana v"}
+				  ]
+				}
+			  ]
 			}
 		  ],
 		  "blocks": [
@@ -1779,16 +1805,47 @@ var expNonEmailNotifications = map[string][]string{
 		`{
 		  "channel": "#test-channel",
 		  "username": "Integration Test",
+		  "text": "[FIRING:1] SlackAlert2 ",
 		  "attachments": [
 			{
-			  "title": "[FIRING:1] SlackAlert2 ",
-			  "title_link": "http://localhost:3000/alerting/list",
-			  "text": "**Firing**\n\nLabels:\n - alertname = SlackAlert2\nAnnotations:\nSource: http://localhost:3000/alerting/UID_SlackAlert2/edit\nSilence: http://localhost:3000/alerting/silence/new?alertmanager=grafana&matchers=alertname%%3DSlackAlert2\n",
-			  "fallback": "[FIRING:1] SlackAlert2 ",
-			  "footer": "Grafana v",
-			  "footer_icon": "https://grafana.com/assets/img/fav32.png",
 			  "color": "#D63232",
-			  "ts": %s
+			  "fallback": "[FIRING:1] SlackAlert2 ",
+			  "blocks": [
+				{
+				  "type": "header",
+				  "text": {"type": "plain_text", "text": "[FIRING:1] SlackAlert2 "}
+				},
+				{
+				  "type": "section",
+				  "text": {"type": "mrkdwn", "text": "**Firing**\n\nLabels:\n - alertname = SlackAlert2\nAnnotations:\nSource: http://localhost:3000/alerting/UID_SlackAlert2/edit\nSilence: http://localhost:3000/alerting/silence/new?alertmanager=grafana&matchers=alertname%3DSlackAlert2\n"}
+				},
+				{
+				  "type": "section",
+				  "fields": [{"type": "mrkdwn", "text": "*alertname*\nSlackAlert2"}]
+				},
+				{
+				  "type": "actions",
+				  "elements": [
+					{
+					  "type": "button",
+					  "text": {"type": "plain_text", "text": "View alert rule"},
+					  "url": "http://localhost:3000/alerting/UID_SlackAlert2/edit"
+					},
+					{
+					  "type": "button",
+					  "text": {"type": "plain_text", "text": "Silence"},
+					  "url": "http://localhost:3000/alerting/silence/new?alertmanager=grafana&matchers=alertname%3DSlackAlert2"
+					}
+				  ]
+				},
+				{
+				  "type": "context",
+				  "elements": [
+					{"type": "image", "image_url": "https://grafana.com/assets/img/fav32.png", "alt_text": "Grafana"},
+					{"type": "mrkdwn", "text": "Grafana v"}
+				  ]
+				}
+			  ]
 			}
 		  ],
 		  "blocks": [
@@ -1883,6 +1940,7 @@ var expNonEmailNotifications = map[string][]string{
 			  "annotations": {},
 			  "startsAt": "%s",
         "valueString": "[ var='A' labels={} value=1 ]",
+			  "values": {"A": 1},
 			  "endsAt": "0001-01-01T00:00:00Z",
 			  "generatorURL": "http://localhost:3000/alerting/UID_WebhookAlert/edit",
 			  "fingerprint": "929467973978d053",
@@ -2008,7 +2066,10 @@ var expNonEmailNotifications = map[string][]string{
 				"client_url": "http://localhost:3000/alerting/list",
 				"description": "[FIRING:1] KafkaAlert ",
 				"details": "**Firing**\n\nLabels:\n - alertname = KafkaAlert\nAnnotations:\nSource: http://localhost:3000/alerting/UID_KafkaAlert/edit\nSilence: http://localhost:3000/alerting/silence/new?alertmanager=grafana&matchers=alertname%3DKafkaAlert\n",
-				"incident_key": "35c0bdb1715f9162a20d7b2a01cb2e3a4c5b1dc663571701e3f67212b696332f"
+				"incident_key": "35c0bdb1715f9162a20d7b2a01cb2e3a4c5b1dc663571701e3f67212b696332f",
+				"labels": {
+				  "alertname": "KafkaAlert"
+				}
 			  }
 			}
 		  ]
@@ -2052,7 +2113,8 @@ var expNonEmailNotifications = map[string][]string{
 			  "alertname": "AlertmanagerAlert"
 			},
 			"annotations": {
-        "__value_string__": "[ var='A' labels={} value=1 ]"
+        "__value_string__": "[ var='A' labels={} value=1 ]",
+        "__values__": "{\"A\":1}"
       },
 			"startsAt": "%s",
 			"endsAt": "0001-01-01T00:00:00Z",
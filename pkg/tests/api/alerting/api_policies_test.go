@@ -0,0 +1,156 @@
+package alerting
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/tests/testinfra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationPolicies(t *testing.T) {
+	dir, path := testinfra.CreateGrafDir(t, testinfra.GrafanaOpts{
+		EnableFeatureToggles: []string{"ngalert"},
+		DisableAnonymous:     true,
+	})
+	store := testinfra.SetUpDatabase(t, dir)
+	// override bus to get the GetSignedInUserQuery handler
+	store.Bus = bus.GetBus()
+	grafanaListedAddr := testinfra.StartGrafana(t, dir, path, store)
+
+	createUser(t, store, models.CreateUserCommand{
+		DefaultOrgRole: string(models.ROLE_EDITOR),
+		Password:       "editor",
+		Login:          "editor",
+	})
+	createUser(t, store, models.CreateUserCommand{
+		DefaultOrgRole: string(models.ROLE_VIEWER),
+		Password:       "viewer",
+		Login:          "viewer",
+	})
+
+	alertConfigURL := fmt.Sprintf("http://editor:editor@%s/api/alertmanager/grafana/config/api/v1/alerts", grafanaListedAddr)
+	postRequest(t, alertConfigURL, `
+{
+	"alertmanager_config": {
+		"route": {
+			"receiver": "default"
+		},
+		"mute_time_intervals": [{
+			"name": "weekends",
+			"time_intervals": [{"weekdays": ["saturday", "sunday"]}]
+		}],
+		"receivers": [{
+			"name": "default",
+			"grafana_managed_receiver_configs": [{
+				"name": "default",
+				"type": "email",
+				"settings": {"addresses": "default@example.com"}
+			}]
+		}, {
+			"name": "pager",
+			"grafana_managed_receiver_configs": [{
+				"name": "pager",
+				"type": "slack",
+				"settings": {"recipient": "#pager"},
+				"secureSettings": {"url": "http://averysecureurl.com/webhook"}
+			}]
+		}]
+	}
+}`, http.StatusAccepted)
+
+	policiesURL := fmt.Sprintf("http://editor:editor@%s/api/v1/ngalert/policies", grafanaListedAddr)
+	getTree := func(t *testing.T) apimodels.PolicyTree {
+		t.Helper()
+		resp := getRequest(t, policiesURL, http.StatusOK)
+		var tree apimodels.PolicyTree
+		require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &tree))
+		return tree
+	}
+
+	t.Run("viewers can't manage the notification policies", func(t *testing.T) {
+		getRequest(t, fmt.Sprintf("http://viewer:viewer@%s/api/v1/ngalert/policies", grafanaListedAddr), http.StatusForbidden)
+		putRequest(t, fmt.Sprintf("http://viewer:viewer@%s/api/v1/ngalert/policies", grafanaListedAddr), `{"receiver": "pager"}`, http.StatusForbidden)
+	})
+
+	t.Run("the policy tree is replaced", func(t *testing.T) {
+		putRequest(t, policiesURL, `{
+			"receiver": "default",
+			"group_by": ["alertname"],
+			"routes": [{
+				"receiver": "pager",
+				"matchers": ["severity=\"critical\""],
+				"group_wait": "10s",
+				"mute_time_intervals": ["weekends"]
+			}]
+		}`, http.StatusAccepted)
+
+		tree := getTree(t)
+		assert.Equal(t, "default", tree.Receiver)
+		assert.Equal(t, []string{"alertname"}, tree.GroupByStr)
+		require.Len(t, tree.Routes, 1)
+		assert.Equal(t, "pager", tree.Routes[0].Receiver)
+		assert.Equal(t, []string{"weekends"}, tree.Routes[0].MuteTimeIntervals)
+
+		// the contact points are unchanged, with their secure settings
+		resp := getRequest(t, alertConfigURL, http.StatusOK)
+		var cfg apimodels.GettableUserConfig
+		require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &cfg))
+		require.Len(t, cfg.AlertmanagerConfig.Receivers, 2)
+		assert.Equal(t, map[string]bool{"url": true}, cfg.AlertmanagerConfig.Receivers[1].GrafanaManagedReceivers[0].SecureFields)
+	})
+
+	t.Run("invalid policy trees are rejected", func(t *testing.T) {
+		putRequest(t, policiesURL, `{"receiver": "default", "routes": [{"receiver": "unknown"}]}`, http.StatusBadRequest)
+		putRequest(t, policiesURL, `{"receiver": "default", "routes": [{"receiver": "pager", "mute_time_intervals": ["holidays"]}]}`, http.StatusBadRequest)
+		putRequest(t, policiesURL, `{"routes": [{"receiver": "pager"}]}`, http.StatusBadRequest)
+
+		tree := getTree(t)
+		require.Len(t, tree.Routes, 1)
+		assert.Equal(t, "pager", tree.Routes[0].Receiver)
+	})
+
+	t.Run("the matching policies of labels are tested", func(t *testing.T) {
+		testURL := policiesURL + "/test"
+		test := func(t *testing.T, body string) apimodels.PolicyTestResult {
+			t.Helper()
+			resp := postRequest(t, testURL, body, http.StatusOK)
+			var result apimodels.PolicyTestResult
+			require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &result))
+			return result
+		}
+
+		result := test(t, `{"labels": {"alertname": "cpu", "severity": "critical"}}`)
+		require.Len(t, result.Matches, 1)
+		assert.Equal(t, "pager", result.Matches[0].Receiver)
+		assert.Equal(t, []string{"alertname"}, result.Matches[0].GroupBy)
+		assert.Equal(t, []string{"weekends"}, result.Matches[0].MuteTimeIntervals)
+
+		result = test(t, `{"labels": {"alertname": "cpu", "severity": "warning"}}`)
+		require.Len(t, result.Matches, 1)
+		assert.Equal(t, "default", result.Matches[0].Receiver)
+
+		// a tree that is not saved yet
+		result = test(t, `{"labels": {"alertname": "cpu", "severity": "warning"}, "tree": {"receiver": "pager"}}`)
+		require.Len(t, result.Matches, 1)
+		assert.Equal(t, "pager", result.Matches[0].Receiver)
+		assert.Equal(t, "default", getTree(t).Receiver)
+
+		postRequest(t, testURL, `{"labels": {"alertname": "cpu"}, "tree": {"receiver": "unknown"}}`, http.StatusBadRequest)
+	})
+
+	t.Run("the specific policies are deleted", func(t *testing.T) {
+		deleteRequest(t, policiesURL, http.StatusAccepted)
+
+		tree := getTree(t)
+		assert.Equal(t, "default", tree.Receiver)
+		assert.Equal(t, []string{"alertname"}, tree.GroupByStr)
+		assert.Empty(t, tree.Routes)
+	})
+}
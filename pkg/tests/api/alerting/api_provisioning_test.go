@@ -0,0 +1,108 @@
+package alerting
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/tests/testinfra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const provisionedAlertingConfig = `
+apiVersion: 1
+
+groups:
+  - folder: Provisioned
+    name: arulegroup
+    interval: 1m
+    rules:
+      - grafana_alert:
+          uid: provisioned-rule
+          title: %s
+          condition: A
+          data:
+            - refId: A
+              relativeTimeRange:
+                from: 600
+                to: 0
+              datasourceUid: "-100"
+              model:
+                type: math
+                expression: "2 + 3 > 1"
+
+alertmanager:
+  alertmanager_config:
+    route:
+      receiver: ops
+    receivers:
+      - name: ops
+        grafana_managed_receiver_configs:
+          - uid: provisioned-contact-point
+            name: ops
+            type: email
+            settings:
+              addresses: ops@example.com
+`
+
+func TestAlertingProvisioning(t *testing.T) {
+	dir, path := testinfra.CreateGrafDir(t, testinfra.GrafanaOpts{
+		EnableFeatureToggles: []string{"ngalert"},
+		DisableAnonymous:     true,
+	})
+
+	provisioningFile := filepath.Join(dir, "conf", "provisioning", "alerting", "alerting.yaml")
+	writeConfig := func(t *testing.T, title string) {
+		t.Helper()
+		require.NoError(t, ioutil.WriteFile(provisioningFile, []byte(fmt.Sprintf(provisionedAlertingConfig, title)), 0600))
+	}
+	writeConfig(t, "Provisioned rule")
+
+	store := testinfra.SetUpDatabase(t, dir)
+	// override bus to get the GetSignedInUserQuery handler
+	store.Bus = bus.GetBus()
+	grafanaListedAddr := testinfra.StartGrafana(t, dir, path, store)
+
+	createUser(t, store, models.CreateUserCommand{
+		DefaultOrgRole: string(models.ROLE_ADMIN),
+		Password:       "admin",
+		Login:          "admin",
+		IsAdmin:        true,
+	})
+
+	getExport := func(t *testing.T) apimodels.AlertingExport {
+		t.Helper()
+		resp := getRequest(t, fmt.Sprintf("http://admin:admin@%s/api/v1/ngalert/export", grafanaListedAddr), http.StatusOK)
+		var export apimodels.AlertingExport
+		require.NoError(t, yaml.Unmarshal([]byte(getBody(t, resp.Body)), &export))
+		return export
+	}
+
+	// the files are provisioned at startup
+	export := getExport(t)
+	require.Len(t, export.Groups, 1)
+	assert.Equal(t, "Provisioned", export.Groups[0].Folder)
+	require.Len(t, export.Groups[0].Rules, 1)
+	assert.Equal(t, "provisioned-rule", export.Groups[0].Rules[0].GrafanaManagedAlert.UID)
+	assert.Equal(t, "Provisioned rule", export.Groups[0].Rules[0].GrafanaManagedAlert.Title)
+	require.NotNil(t, export.Alertmanager)
+	assert.Contains(t, export.Alertmanager.GetGrafanaReceiverMap(), "provisioned-contact-point")
+
+	t.Run("the files are provisioned again on reload", func(t *testing.T) {
+		writeConfig(t, "Updated rule")
+		postRequest(t, fmt.Sprintf("http://admin:admin@%s/api/admin/provisioning/alerting/reload", grafanaListedAddr), "", http.StatusOK)
+
+		export := getExport(t)
+		require.Len(t, export.Groups, 1)
+		require.Len(t, export.Groups[0].Rules, 1)
+		assert.Equal(t, "provisioned-rule", export.Groups[0].Rules[0].GrafanaManagedAlert.UID)
+		assert.Equal(t, "Updated rule", export.Groups[0].Rules[0].GrafanaManagedAlert.Title)
+	})
+}
@@ -0,0 +1,125 @@
+package alerting
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/tests/testinfra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordedQueries(t *testing.T) {
+	var (
+		mtx    sync.Mutex
+		writes []http.Header
+	)
+	remoteWrite := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		writes = append(writes, r.Header)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(remoteWrite.Close)
+
+	dir, path := testinfra.CreateGrafDir(t, testinfra.GrafanaOpts{
+		EnableFeatureToggles:                 []string{"ngalert"},
+		DisableAnonymous:                     true,
+		NGAlertRecordedQueriesRemoteWriteURL: remoteWrite.URL + "/api/v1/write",
+	})
+	store := testinfra.SetUpDatabase(t, dir)
+	// override bus to get the GetSignedInUserQuery handler
+	store.Bus = bus.GetBus()
+	grafanaListedAddr := testinfra.StartGrafana(t, dir, path, store)
+
+	createUser(t, store, models.CreateUserCommand{
+		DefaultOrgRole: string(models.ROLE_EDITOR),
+		Password:       "editor",
+		Login:          "editor",
+	})
+	createUser(t, store, models.CreateUserCommand{
+		DefaultOrgRole: string(models.ROLE_VIEWER),
+		Password:       "viewer",
+		Login:          "viewer",
+	})
+
+	recordedQueriesURL := fmt.Sprintf("http://editor:editor@%s/api/v1/ngalert/recorded-queries", grafanaListedAddr)
+	recordedQuery := func(name, interval string, active bool) string {
+		return fmt.Sprintf(`{
+			"name": %q,
+			"data": [{
+				"refId": "A",
+				"relativeTimeRange": {"from": 18000, "to": 10800},
+				"datasourceUid": "-100",
+				"model": {"type": "math", "expression": "2 + 3"}
+			}],
+			"target": "A",
+			"interval": %q,
+			"labels": {"team": "db"},
+			"active": %t
+		}`, name, interval, active)
+	}
+	decode := func(t *testing.T, resp *http.Response) apimodels.GettableRecordedQuery {
+		t.Helper()
+		var rq apimodels.GettableRecordedQuery
+		require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &rq))
+		return rq
+	}
+
+	t.Run("viewers can't manage the recorded queries", func(t *testing.T) {
+		getRequest(t, fmt.Sprintf("http://viewer:viewer@%s/api/v1/ngalert/recorded-queries", grafanaListedAddr), http.StatusForbidden)
+		postRequest(t, fmt.Sprintf("http://viewer:viewer@%s/api/v1/ngalert/recorded-queries", grafanaListedAddr), recordedQuery("viewer_metric", "10s", false), http.StatusForbidden)
+	})
+
+	t.Run("invalid recorded queries are rejected", func(t *testing.T) {
+		postRequest(t, recordedQueriesURL, recordedQuery("invalid-metric", "10s", false), http.StatusBadRequest)
+		postRequest(t, recordedQueriesURL, recordedQuery("metric", "15s", false), http.StatusBadRequest)
+		postRequest(t, recordedQueriesURL, `{"name": "metric", "data": [], "target": "A"}`, http.StatusBadRequest)
+	})
+
+	var uid string
+	t.Run("a recorded query is created, updated and deleted", func(t *testing.T) {
+		created := decode(t, postRequest(t, recordedQueriesURL, recordedQuery("inactive_metric", "20s", false), http.StatusCreated))
+		require.NotEmpty(t, created.UID)
+		assert.Equal(t, "inactive_metric", created.Name)
+		assert.Equal(t, "20s", created.Interval.String())
+		assert.Equal(t, map[string]string{"team": "db"}, created.Labels)
+
+		updated := decode(t, putRequest(t, recordedQueriesURL+"/"+created.UID, recordedQuery("updated_metric", "1m", false), http.StatusOK))
+		assert.Equal(t, created.UID, updated.UID)
+		assert.Equal(t, "updated_metric", decode(t, getRequest(t, recordedQueriesURL+"/"+created.UID, http.StatusOK)).Name)
+
+		resp := getRequest(t, recordedQueriesURL, http.StatusOK)
+		var list apimodels.GettableRecordedQueries
+		require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &list))
+		require.Len(t, list, 1)
+
+		deleteRequest(t, recordedQueriesURL+"/"+created.UID, http.StatusAccepted)
+		getRequest(t, recordedQueriesURL+"/"+created.UID, http.StatusNotFound)
+		putRequest(t, recordedQueriesURL+"/"+created.UID, recordedQuery("updated_metric", "1m", false), http.StatusNotFound)
+
+		uid = decode(t, postRequest(t, recordedQueriesURL, recordedQuery("active_metric", "10s", true), http.StatusCreated)).UID
+	})
+
+	t.Run("the results of the active recorded queries are written", func(t *testing.T) {
+		require.NotEmpty(t, uid)
+		require.Eventually(t, func() bool {
+			mtx.Lock()
+			defer mtx.Unlock()
+			return len(writes) > 0
+		}, 30*time.Second, 500*time.Millisecond)
+
+		mtx.Lock()
+		defer mtx.Unlock()
+		assert.Equal(t, "snappy", writes[0].Get("Content-Encoding"))
+		assert.Equal(t, "application/x-protobuf", writes[0].Get("Content-Type"))
+	})
+}
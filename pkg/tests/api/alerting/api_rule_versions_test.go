@@ -0,0 +1,159 @@
+package alerting
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/tests/testinfra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleVersions(t *testing.T) {
+	dir, path := testinfra.CreateGrafDir(t, testinfra.GrafanaOpts{
+		EnableFeatureToggles: []string{"ngalert"},
+		DisableAnonymous:     true,
+	})
+	store := testinfra.SetUpDatabase(t, dir)
+	// override bus to get the GetSignedInUserQuery handler
+	store.Bus = bus.GetBus()
+	grafanaListedAddr := testinfra.StartGrafana(t, dir, path, store)
+
+	createUser(t, store, models.CreateUserCommand{
+		DefaultOrgRole: string(models.ROLE_EDITOR),
+		Password:       "editor",
+		Login:          "editor",
+	})
+	createUser(t, store, models.CreateUserCommand{
+		DefaultOrgRole: string(models.ROLE_VIEWER),
+		Password:       "viewer",
+		Login:          "viewer",
+	})
+
+	_, err := createFolder(t, store, 0, "folder1")
+	require.NoError(t, err)
+
+	rulesURL := fmt.Sprintf("http://editor:editor@%s/api/ruler/grafana/api/v1/rules/folder1", grafanaListedAddr)
+	postRule := func(t *testing.T, uid, title, expression string) {
+		t.Helper()
+		postRequest(t, rulesURL, fmt.Sprintf(`{"name": "group1", "interval": "10s", "rules": [{
+			"for": "1m",
+			"labels": {"team": "db"},
+			"grafana_alert": {
+				"uid": %q,
+				"title": %q,
+				"condition": "A",
+				"data": [{
+					"refId": "A",
+					"relativeTimeRange": {"from": 18000, "to": 10800},
+					"datasourceUid": "-100",
+					"model": {"type": "math", "expression": %q}
+				}]
+			}
+		}]}`, uid, title, expression), http.StatusAccepted)
+	}
+	getRule := func(t *testing.T) apimodels.GettableExtendedRuleNode {
+		t.Helper()
+		resp := getRequest(t, rulesURL, http.StatusAccepted)
+		var namespaces apimodels.NamespaceConfigResponse
+		require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &namespaces))
+		require.Len(t, namespaces["folder1"], 1)
+		require.Len(t, namespaces["folder1"][0].Rules, 1)
+		return namespaces["folder1"][0].Rules[0]
+	}
+
+	postRule(t, "", "DB", "2 + 3 > 1")
+	uid := getRule(t).GrafanaManagedAlert.UID
+	postRule(t, uid, "DB down", "2 + 3 > 4")
+
+	versionsURL := fmt.Sprintf("http://editor:editor@%s/api/v1/ngalert/rules/%s/versions", grafanaListedAddr, uid)
+	getVersions := func(t *testing.T, query string) apimodels.RuleVersions {
+		t.Helper()
+		resp := getRequest(t, versionsURL+query, http.StatusOK)
+		var versions apimodels.RuleVersions
+		require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &versions))
+		return versions
+	}
+
+	t.Run("viewers can't see the versions", func(t *testing.T) {
+		getRequest(t, fmt.Sprintf("http://viewer:viewer@%s/api/v1/ngalert/rules/%s/versions", grafanaListedAddr, uid), http.StatusForbidden)
+	})
+
+	t.Run("unknown rules and versions are not found", func(t *testing.T) {
+		getRequest(t, fmt.Sprintf("http://editor:editor@%s/api/v1/ngalert/rules/unknown/versions", grafanaListedAddr), http.StatusNotFound)
+		getRequest(t, versionsURL+"/42", http.StatusNotFound)
+		postRequest(t, versionsURL+"/diff", `{"base": 1, "new": 42}`, http.StatusNotFound)
+	})
+
+	t.Run("the versions are listed, most recent first", func(t *testing.T) {
+		versions := getVersions(t, "")
+		require.Len(t, versions, 2)
+		assert.Equal(t, int64(2), versions[0].Version)
+		assert.Equal(t, int64(1), versions[0].ParentVersion)
+		assert.Equal(t, "DB down", versions[0].Title)
+		assert.Empty(t, versions[0].Message)
+		assert.Nil(t, versions[0].Rule)
+		assert.Equal(t, int64(1), versions[1].Version)
+		assert.Equal(t, "Initial save", versions[1].Message)
+
+		versions = getVersions(t, "?limit=1&start=1")
+		require.Len(t, versions, 1)
+		assert.Equal(t, int64(1), versions[0].Version)
+	})
+
+	t.Run("a version has the definition of the rule", func(t *testing.T) {
+		resp := getRequest(t, versionsURL+"/1", http.StatusOK)
+		var version apimodels.RuleVersion
+		require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &version))
+		require.NotNil(t, version.Rule)
+		assert.Equal(t, "DB", version.Rule.GrafanaManagedAlert.Title)
+		assert.Equal(t, uid, version.Rule.GrafanaManagedAlert.UID)
+		assert.Equal(t, int64(1), version.Rule.GrafanaManagedAlert.Version)
+		assert.Equal(t, map[string]string{"team": "db"}, version.Rule.Labels)
+	})
+
+	t.Run("the versions are compared", func(t *testing.T) {
+		resp := postRequest(t, versionsURL+"/diff", `{"base": 1, "new": 2, "diffType": "delta"}`, http.StatusOK)
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		var delta map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &delta))
+		assert.Contains(t, delta, "grafana_alert")
+		assert.NotContains(t, delta, "labels")
+
+		for _, diffType := range []string{"basic", "json"} {
+			resp = postRequest(t, versionsURL+"/diff", fmt.Sprintf(`{"base": 1, "new": 2, "diffType": %q}`, diffType), http.StatusOK)
+			assert.Equal(t, "text/html", resp.Header.Get("Content-Type"))
+			assert.Contains(t, getBody(t, resp.Body), "DB down")
+		}
+
+		postRequest(t, versionsURL+"/diff", `{"base": 1, "new": 1}`, http.StatusBadRequest)
+		postRequest(t, versionsURL+"/diff", `{"base": 1, "new": 2, "diffType": "xml"}`, http.StatusBadRequest)
+	})
+
+	t.Run("a version is restored", func(t *testing.T) {
+		restoreURL := fmt.Sprintf("http://%%s@%s/api/v1/ngalert/rules/%s/restore", grafanaListedAddr, uid)
+		postRequest(t, fmt.Sprintf(restoreURL, "viewer:viewer"), `{"version": 1}`, http.StatusForbidden)
+		postRequest(t, fmt.Sprintf(restoreURL, "editor:editor"), `{"version": 2}`, http.StatusBadRequest)
+		postRequest(t, fmt.Sprintf(restoreURL, "editor:editor"), `{"version": 1}`, http.StatusAccepted)
+
+		rule := getRule(t)
+		assert.Equal(t, "DB", rule.GrafanaManagedAlert.Title)
+		assert.Equal(t, int64(3), rule.GrafanaManagedAlert.Version)
+		assert.Equal(t, int64(10), rule.GrafanaManagedAlert.IntervalSeconds)
+
+		versions := getVersions(t, "")
+		require.Len(t, versions, 3)
+		assert.Equal(t, int64(3), versions[0].Version)
+		assert.Equal(t, int64(2), versions[0].ParentVersion)
+		assert.Equal(t, int64(1), versions[0].RestoredFrom)
+		assert.Equal(t, "Restored from version 1", versions[0].Message)
+
+		// the restored version has the same definition as the version it restores
+		postRequest(t, versionsURL+"/diff", `{"base": 1, "new": 3}`, http.StatusBadRequest)
+	})
+}
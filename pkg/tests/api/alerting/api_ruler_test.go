@@ -113,7 +113,11 @@ func TestAlertRulePermissions(t *testing.T) {
 						  "namespace_id":1,
 						  "rule_group":"arulegroup",
 						  "no_data_state":"NoData",
-						  "exec_err_state":"Alerting"
+						  "exec_err_state":"Alerting",
+						  "is_paused":false,
+						  "no_data_threshold":0,
+						  "exec_err_threshold":0,
+						  "disable_dashboard_annotations":false
 					   }
 					}
 				 ]
@@ -163,7 +167,11 @@ func TestAlertRulePermissions(t *testing.T) {
 						"namespace_id":2,
 						"rule_group":"arulegroup",
 						"no_data_state":"NoData",
-						"exec_err_state":"Alerting"
+						"exec_err_state":"Alerting",
+						"is_paused":false,
+						"no_data_threshold":0,
+						"exec_err_threshold":0,
+						"disable_dashboard_annotations":false
 					 }
 				  }
 			   ]
@@ -235,7 +243,11 @@ func TestAlertRulePermissions(t *testing.T) {
 						  "namespace_id":1,
 						  "rule_group":"arulegroup",
 						  "no_data_state":"NoData",
-						  "exec_err_state":"Alerting"
+						  "exec_err_state":"Alerting",
+						  "is_paused":false,
+						  "no_data_threshold":0,
+						  "exec_err_threshold":0,
+						  "disable_dashboard_annotations":false
 					   }
 					}
 				 ]
@@ -426,3 +438,61 @@ func TestAlertRuleConflictingTitle(t *testing.T) {
 		require.JSONEq(t, `{"message":"rule group updated successfully"}`, string(b))
 	})
 }
+
+func TestRuleGroupEvaluationOffset(t *testing.T) {
+	dir, path := testinfra.CreateGrafDir(t, testinfra.GrafanaOpts{
+		EnableFeatureToggles: []string{"ngalert"},
+		DisableAnonymous:     true,
+	})
+	store := testinfra.SetUpDatabase(t, dir)
+	// override bus to get the GetSignedInUserQuery handler
+	store.Bus = bus.GetBus()
+	grafanaListedAddr := testinfra.StartGrafana(t, dir, path, store)
+
+	createUser(t, store, models.CreateUserCommand{
+		DefaultOrgRole: string(models.ROLE_EDITOR),
+		Password:       "editor",
+		Login:          "editor",
+	})
+	_, err := createFolder(t, store, 0, "folder1")
+	require.NoError(t, err)
+
+	groupURL := fmt.Sprintf("http://editor:editor@%s/api/ruler/grafana/api/v1/rules/folder1", grafanaListedAddr)
+	var uid string
+	postGroup := func(t *testing.T, interval, offset string, expStatusCode int) {
+		t.Helper()
+		postRequest(t, groupURL, fmt.Sprintf(`{"name": "group1", "interval": %q, "evaluation_offset": %q, "rules": [{
+			"grafana_alert": {
+				"uid": %q,
+				"title": "DB",
+				"condition": "A",
+				"data": [{
+					"refId": "A",
+					"relativeTimeRange": {"from": 18000, "to": 10800},
+					"datasourceUid": "-100",
+					"model": {"type": "math", "expression": "2 + 3 > 1"}
+				}]
+			}
+		}]}`, interval, offset, uid), expStatusCode)
+	}
+	getOffset := func(t *testing.T) model.Duration {
+		t.Helper()
+		resp := getRequest(t, groupURL+"/group1", http.StatusAccepted)
+		var group apimodels.RuleGroupConfigResponse
+		require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &group))
+		require.Len(t, group.Rules, 1)
+		uid = group.Rules[0].GrafanaManagedAlert.UID
+		return group.EvaluationOffset
+	}
+
+	postGroup(t, "1m", "20s", http.StatusAccepted)
+	assert.Equal(t, model.Duration(20*time.Second), getOffset(t))
+
+	// the offset must be within the interval and a multiple of the scheduler interval
+	postGroup(t, "1m", "1m", http.StatusBadRequest)
+	postGroup(t, "1m", "15s", http.StatusBadRequest)
+	assert.Equal(t, model.Duration(20*time.Second), getOffset(t))
+
+	postGroup(t, "1m", "0s", http.StatusAccepted)
+	assert.Equal(t, model.Duration(0), getOffset(t))
+}
@@ -0,0 +1,142 @@
+package alerting
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/tests/testinfra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkRules(t *testing.T) {
+	dir, path := testinfra.CreateGrafDir(t, testinfra.GrafanaOpts{
+		EnableFeatureToggles: []string{"ngalert"},
+		DisableAnonymous:     true,
+	})
+	store := testinfra.SetUpDatabase(t, dir)
+	// override bus to get the GetSignedInUserQuery handler
+	store.Bus = bus.GetBus()
+	grafanaListedAddr := testinfra.StartGrafana(t, dir, path, store)
+
+	createUser(t, store, models.CreateUserCommand{
+		DefaultOrgRole: string(models.ROLE_EDITOR),
+		Password:       "editor",
+		Login:          "editor",
+	})
+	createUser(t, store, models.CreateUserCommand{
+		DefaultOrgRole: string(models.ROLE_VIEWER),
+		Password:       "viewer",
+		Login:          "viewer",
+	})
+
+	folder1UID, err := createFolder(t, store, 0, "folder1")
+	require.NoError(t, err)
+	_, err = createFolder(t, store, 0, "folder2")
+	require.NoError(t, err)
+
+	rule := func(title, team string) string {
+		return fmt.Sprintf(`{
+			"labels": {"team": %q},
+			"grafana_alert": {
+				"title": %q,
+				"condition": "A",
+				"data": [{
+					"refId": "A",
+					"relativeTimeRange": {"from": 18000, "to": 10800},
+					"datasourceUid": "-100",
+					"model": {"type": "math", "expression": "2 + 3 > 1"}
+				}]
+			}
+		}`, team, title)
+	}
+	postRequest(t, fmt.Sprintf("http://editor:editor@%s/api/ruler/grafana/api/v1/rules/folder1", grafanaListedAddr),
+		fmt.Sprintf(`{"name": "group1", "interval": "10s", "rules": [%s, %s]}`, rule("DB", "db"), rule("Web", "web")), http.StatusAccepted)
+	postRequest(t, fmt.Sprintf("http://editor:editor@%s/api/ruler/grafana/api/v1/rules/folder2", grafanaListedAddr),
+		fmt.Sprintf(`{"name": "group2", "interval": "10s", "rules": [%s]}`, rule("OtherDB", "db")), http.StatusAccepted)
+
+	getRules := func(t *testing.T) map[string]apimodels.GettableExtendedRuleNode {
+		t.Helper()
+		resp := getRequest(t, fmt.Sprintf("http://editor:editor@%s/api/ruler/grafana/api/v1/rules", grafanaListedAddr), http.StatusAccepted)
+		var namespaces apimodels.NamespaceConfigResponse
+		require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &namespaces))
+		rules := make(map[string]apimodels.GettableExtendedRuleNode)
+		for _, groups := range namespaces {
+			for _, g := range groups {
+				for _, r := range g.Rules {
+					rules[r.GrafanaManagedAlert.Title] = r
+				}
+			}
+		}
+		return rules
+	}
+	rulesURL := fmt.Sprintf("http://editor:editor@%s/api/v1/ngalert/rules", grafanaListedAddr)
+	bulk := func(t *testing.T, action, body string) []string {
+		t.Helper()
+		resp := postRequest(t, rulesURL+"/"+action, body, http.StatusOK)
+		var result apimodels.BulkRulesResult
+		require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &result))
+		return result.UIDs
+	}
+	rules := getRules(t)
+	require.Len(t, rules, 3)
+
+	t.Run("viewers can't change the rules", func(t *testing.T) {
+		postRequest(t, fmt.Sprintf("http://viewer:viewer@%s/api/v1/ngalert/rules/pause", grafanaListedAddr), `{"selector": {"matchers": ["team=\"db\""]}}`, http.StatusForbidden)
+	})
+
+	t.Run("invalid selectors are rejected", func(t *testing.T) {
+		postRequest(t, rulesURL+"/pause", `{"selector": {}}`, http.StatusBadRequest)
+		postRequest(t, rulesURL+"/pause", `{"selector": {"matchers": ["team"]}}`, http.StatusBadRequest)
+	})
+
+	t.Run("rules are paused and resumed", func(t *testing.T) {
+		uids := bulk(t, "pause", `{"selector": {"matchers": ["team=\"db\""]}}`)
+		assert.ElementsMatch(t, []string{rules["DB"].GrafanaManagedAlert.UID, rules["OtherDB"].GrafanaManagedAlert.UID}, uids)
+		paused := getRules(t)
+		assert.True(t, paused["DB"].GrafanaManagedAlert.IsPaused)
+		assert.True(t, paused["OtherDB"].GrafanaManagedAlert.IsPaused)
+		assert.False(t, paused["Web"].GrafanaManagedAlert.IsPaused)
+
+		// the rules that are already paused are unchanged
+		assert.Empty(t, bulk(t, "pause", `{"selector": {"matchers": ["team=\"db\""]}}`))
+
+		uids = bulk(t, "resume", fmt.Sprintf(`{"selector": {"folderUids": [%q], "matchers": ["team=\"db\""]}}`, folder1UID))
+		assert.Equal(t, []string{rules["DB"].GrafanaManagedAlert.UID}, uids)
+		uids = bulk(t, "resume", `{"selector": {"datasourceUids": ["-100"]}}`)
+		assert.Equal(t, []string{rules["OtherDB"].GrafanaManagedAlert.UID}, uids)
+		for _, r := range getRules(t) {
+			assert.False(t, r.GrafanaManagedAlert.IsPaused)
+		}
+	})
+
+	t.Run("the interval and the labels of the rules are changed", func(t *testing.T) {
+		uids := bulk(t, "edit", fmt.Sprintf(`{
+			"selector": {"folderUids": [%q], "matchers": ["team=\"web\""]},
+			"interval": "20s",
+			"labels": {"severity": "critical"},
+			"removeLabels": ["team"]
+		}`, folder1UID))
+		// the interval is changed on the whole rule group
+		assert.ElementsMatch(t, []string{rules["DB"].GrafanaManagedAlert.UID, rules["Web"].GrafanaManagedAlert.UID}, uids)
+
+		edited := getRules(t)
+		assert.Equal(t, map[string]string{"severity": "critical"}, edited["Web"].Labels)
+		assert.Equal(t, map[string]string{"team": "db"}, edited["DB"].Labels)
+		assert.Equal(t, int64(20), edited["Web"].GrafanaManagedAlert.IntervalSeconds)
+		assert.Equal(t, int64(20), edited["DB"].GrafanaManagedAlert.IntervalSeconds)
+		assert.Equal(t, int64(10), edited["OtherDB"].GrafanaManagedAlert.IntervalSeconds)
+	})
+
+	t.Run("invalid edits are rejected", func(t *testing.T) {
+		postRequest(t, rulesURL+"/edit", `{"selector": {"matchers": ["team=\"db\""]}}`, http.StatusBadRequest)
+		postRequest(t, rulesURL+"/edit", `{"selector": {"matchers": ["team=\"db\""]}, "interval": "7s"}`, http.StatusBadRequest)
+		postRequest(t, rulesURL+"/edit", `{"selector": {"matchers": ["team=\"db\""]}, "labels": {"in-valid": "x"}}`, http.StatusBadRequest)
+		assert.Equal(t, int64(20), getRules(t)["DB"].GrafanaManagedAlert.IntervalSeconds)
+	})
+}
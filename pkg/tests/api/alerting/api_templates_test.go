@@ -0,0 +1,123 @@
+package alerting
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/tests/testinfra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationTemplates(t *testing.T) {
+	dir, path := testinfra.CreateGrafDir(t, testinfra.GrafanaOpts{
+		EnableFeatureToggles: []string{"ngalert"},
+		DisableAnonymous:     true,
+	})
+	store := testinfra.SetUpDatabase(t, dir)
+	// override bus to get the GetSignedInUserQuery handler
+	store.Bus = bus.GetBus()
+	grafanaListedAddr := testinfra.StartGrafana(t, dir, path, store)
+
+	createUser(t, store, models.CreateUserCommand{
+		DefaultOrgRole: string(models.ROLE_EDITOR),
+		Password:       "editor",
+		Login:          "editor",
+	})
+	createUser(t, store, models.CreateUserCommand{
+		DefaultOrgRole: string(models.ROLE_VIEWER),
+		Password:       "viewer",
+		Login:          "viewer",
+	})
+
+	alertConfigURL := fmt.Sprintf("http://editor:editor@%s/api/alertmanager/grafana/config/api/v1/alerts", grafanaListedAddr)
+	postRequest(t, alertConfigURL, `
+{
+	"alertmanager_config": {
+		"route": {
+			"receiver": "default"
+		},
+		"receivers": [{
+			"name": "default",
+			"grafana_managed_receiver_configs": [{
+				"name": "default",
+				"type": "email",
+				"settings": {"addresses": "default@example.com", "subject": "{{ template \"custom.title\" . }}"}
+			}]
+		}]
+	}
+}`, http.StatusAccepted)
+
+	templatesURL := fmt.Sprintf("http://editor:editor@%s/api/v1/ngalert/templates", grafanaListedAddr)
+	getTemplates := func(t *testing.T) apimodels.NotificationTemplates {
+		t.Helper()
+		resp := getRequest(t, templatesURL, http.StatusOK)
+		var templates apimodels.NotificationTemplates
+		require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &templates))
+		return templates
+	}
+
+	t.Run("viewers can't manage the templates", func(t *testing.T) {
+		getRequest(t, fmt.Sprintf("http://viewer:viewer@%s/api/v1/ngalert/templates", grafanaListedAddr), http.StatusForbidden)
+		putRequest(t, fmt.Sprintf("http://viewer:viewer@%s/api/v1/ngalert/templates/custom.tmpl", grafanaListedAddr), `{"template": ""}`, http.StatusForbidden)
+	})
+
+	t.Run("a template is saved", func(t *testing.T) {
+		putRequest(t, templatesURL+"/custom.tmpl", `{"template": "{{ define \"custom.title\" }}{{ .CommonLabels.alertname }} is {{ .Status }}{{ end }}"}`, http.StatusAccepted)
+
+		assert.Equal(t, apimodels.NotificationTemplates{{
+			Name:     "custom.tmpl",
+			Template: `{{ define "custom.title" }}{{ .CommonLabels.alertname }} is {{ .Status }}{{ end }}`,
+		}}, getTemplates(t))
+
+		resp := getRequest(t, templatesURL+"/custom.tmpl", http.StatusOK)
+		var template apimodels.NotificationTemplate
+		require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &template))
+		assert.Equal(t, "custom.tmpl", template.Name)
+
+		// the rest of the configuration is unchanged
+		resp = getRequest(t, alertConfigURL, http.StatusOK)
+		var cfg apimodels.GettableUserConfig
+		require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &cfg))
+		require.Len(t, cfg.AlertmanagerConfig.Receivers, 1)
+		assert.Equal(t, []string{"custom.tmpl"}, cfg.AlertmanagerConfig.Templates)
+	})
+
+	t.Run("invalid templates are rejected", func(t *testing.T) {
+		resp := putRequest(t, templatesURL+"/invalid.tmpl", `{"template": "{{ define \"invalid\" }}{{ .Status"}`, http.StatusBadRequest)
+		assert.Contains(t, getBody(t, resp.Body), "the template is invalid")
+		putRequest(t, templatesURL+"/__default__.tmpl", `{"template": ""}`, http.StatusBadRequest)
+		assert.Len(t, getTemplates(t), 1)
+	})
+
+	t.Run("a template is previewed", func(t *testing.T) {
+		test := func(t *testing.T, body string) apimodels.TestTemplateResult {
+			t.Helper()
+			resp := postRequest(t, templatesURL+"/test", body, http.StatusOK)
+			var result apimodels.TestTemplateResult
+			require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &result))
+			return result
+		}
+
+		assert.Equal(t, "TestAlert is firing", test(t, `{"template": "{{ template \"custom.title\" . }}"}`).Text)
+		assert.Equal(t, "db-1 is 95.5% full", test(t, `{
+			"template": "{{ range .Alerts }}{{ .Labels.instance }} is {{ .Values.B }}% full{{ end }}",
+			"alerts": [{"labels": {"alertname": "DiskFull", "instance": "db-1"}, "values": {"B": 95.5}}]
+		}`).Text)
+
+		resp := postRequest(t, templatesURL+"/test", `{"template": "{{ template \"missing\" . }}"}`, http.StatusBadRequest)
+		assert.Contains(t, getBody(t, resp.Body), "the template is invalid")
+	})
+
+	t.Run("a template is deleted", func(t *testing.T) {
+		deleteRequest(t, templatesURL+"/custom.tmpl", http.StatusAccepted)
+		assert.Empty(t, getTemplates(t))
+		getRequest(t, templatesURL+"/custom.tmpl", http.StatusNotFound)
+		deleteRequest(t, templatesURL+"/custom.tmpl", http.StatusNotFound)
+	})
+}
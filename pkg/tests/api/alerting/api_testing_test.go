@@ -0,0 +1,95 @@
+package alerting
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/tests/testinfra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestAlertRule(t *testing.T) {
+	dir, path := testinfra.CreateGrafDir(t, testinfra.GrafanaOpts{
+		EnableFeatureToggles: []string{"ngalert"},
+		DisableAnonymous:     true,
+	})
+	store := testinfra.SetUpDatabase(t, dir)
+	// override bus to get the GetSignedInUserQuery handler
+	store.Bus = bus.GetBus()
+	grafanaListedAddr := testinfra.StartGrafana(t, dir, path, store)
+
+	createUser(t, store, models.CreateUserCommand{
+		DefaultOrgRole: string(models.ROLE_EDITOR),
+		Password:       "editor",
+		Login:          "editor",
+	})
+
+	testURL := fmt.Sprintf("http://editor:editor@%s/api/v1/alert-rules/test", grafanaListedAddr)
+
+	t.Run("the alert rule is evaluated with its labels", func(t *testing.T) {
+		resp := postRequest(t, testURL, `
+{
+	"namespace_uid": "folder",
+	"rule": {
+		"labels": {"severity": "critical", "rule": "{{ $labels.alertname }}"},
+		"grafana_alert": {
+			"title": "AlwaysFiring",
+			"uid": "always-firing",
+			"condition": "A",
+			"data": [{
+				"refId": "A",
+				"relativeTimeRange": {"from": 18000, "to": 10800},
+				"datasourceUid": "-100",
+				"model": {"type": "math", "expression": "2 + 3 > 1"}
+			}]
+		}
+	}
+}`, http.StatusOK)
+
+		var res apimodels.TestAlertRuleResponse
+		require.NoError(t, json.Unmarshal([]byte(getBody(t, resp.Body)), &res))
+		require.Len(t, res.Instances, 1)
+		assert.Equal(t, map[string]string{
+			"__alert_rule_uid__":           "always-firing",
+			"__alert_rule_namespace_uid__": "folder",
+			"alertname":                    "AlwaysFiring",
+			"severity":                     "critical",
+			"rule":                         "AlwaysFiring",
+		}, res.Instances[0].Labels)
+	})
+
+	t.Run("the alert rule is not saved", func(t *testing.T) {
+		resp := getRequest(t, fmt.Sprintf("http://editor:editor@%s/api/ruler/grafana/api/v1/rules", grafanaListedAddr), http.StatusAccepted)
+		assert.JSONEq(t, "{}", getBody(t, resp.Body))
+	})
+
+	t.Run("an invalid condition is rejected", func(t *testing.T) {
+		resp := postRequest(t, testURL, `
+{
+	"rule": {
+		"grafana_alert": {
+			"title": "Invalid",
+			"condition": "B",
+			"data": [{
+				"refId": "A",
+				"relativeTimeRange": {"from": 18000, "to": 10800},
+				"datasourceUid": "-100",
+				"model": {"type": "math", "expression": "2 + 3 > 1"}
+			}]
+		}
+	}
+}`, http.StatusBadRequest)
+		assert.Contains(t, getBody(t, resp.Body), "invalid condition")
+	})
+
+	t.Run("Prometheus style alert rules are rejected", func(t *testing.T) {
+		resp := postRequest(t, testURL, `{"rule": {"alert": "HighLatency", "expr": "latency > 1"}}`, http.StatusBadRequest)
+		assert.Contains(t, getBody(t, resp.Body), "only Grafana managed alert rules can be tested")
+	})
+}
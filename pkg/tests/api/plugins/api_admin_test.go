@@ -0,0 +1,94 @@
+package plugins
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/tests/testinfra"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminPluginsAPI(t *testing.T) {
+	dir, cfgPath := testinfra.CreateGrafDir(t, testinfra.GrafanaOpts{
+		PluginAdminEnabled: true,
+	})
+	store := testinfra.SetUpDatabase(t, dir)
+	store.Bus = bus.GetBus() // in order to allow successful user auth
+	grafanaListedAddr := testinfra.StartGrafana(t, dir, cfgPath, store)
+
+	createUser(t, store, usernameNonAdmin, defaultPassword, false)
+	createUser(t, store, usernameAdmin, defaultPassword, true)
+
+	t.Run("Request is forbidden if not from an admin", func(t *testing.T) {
+		for _, req := range []struct {
+			method string
+			path   string
+			body   string
+		}{
+			{method: http.MethodGet, path: "admin/plugins"},
+			{method: http.MethodPost, path: "admin/plugins", body: `{"id": "grafana-plugin"}`},
+			{method: http.MethodPut, path: "admin/plugins/grafana-plugin", body: `{}`},
+			{method: http.MethodDelete, path: "admin/plugins/grafana-plugin"},
+		} {
+			status, body := makeRequest(t, req.method, grafanaAPIURL(usernameNonAdmin, grafanaListedAddr, req.path), req.body)
+			assert.Equal(t, http.StatusForbidden, status, "%s %s", req.method, req.path)
+			assert.Contains(t, string(body), "Permission denied")
+		}
+	})
+
+	t.Run("Admin can list installed plugins", func(t *testing.T) {
+		status, body := makeRequest(t, http.MethodGet, grafanaAPIURL(usernameAdmin, grafanaListedAddr, "admin/plugins"), "")
+		require.Equal(t, http.StatusOK, status)
+		assert.JSONEq(t, `[]`, string(body))
+	})
+
+	t.Run("Install requires a plugin ID", func(t *testing.T) {
+		status, _ := makeRequest(t, http.MethodPost, grafanaAPIURL(usernameAdmin, grafanaListedAddr, "admin/plugins"), `{}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, status)
+	})
+
+	t.Run("Updating or removing a plugin that isn't installed fails", func(t *testing.T) {
+		status, body := makeRequest(t, http.MethodPut, grafanaAPIURL(usernameAdmin, grafanaListedAddr, "admin/plugins/test"), `{}`)
+		assert.Equal(t, http.StatusNotFound, status)
+		assert.Contains(t, string(body), "Plugin not installed")
+
+		status, body = makeRequest(t, http.MethodDelete, grafanaAPIURL(usernameAdmin, grafanaListedAddr, "admin/plugins/test"), "")
+		assert.Equal(t, http.StatusNotFound, status)
+		assert.Contains(t, string(body), "Plugin not installed")
+	})
+}
+
+func TestAdminPluginsAPIDisabled(t *testing.T) {
+	dir, cfgPath := testinfra.CreateGrafDir(t)
+	store := testinfra.SetUpDatabase(t, dir)
+	store.Bus = bus.GetBus() // in order to allow successful user auth
+	grafanaListedAddr := testinfra.StartGrafana(t, dir, cfgPath, store)
+
+	createUser(t, store, usernameAdmin, defaultPassword, true)
+
+	status, _ := makeRequest(t, http.MethodGet, grafanaAPIURL(usernameAdmin, grafanaListedAddr, "admin/plugins"), "")
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func makeRequest(t *testing.T, method, URL, body string) (int, []byte) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, URL, bytes.NewBufferString(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = resp.Body.Close()
+	})
+	b, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return resp.StatusCode, b
+}
@@ -170,6 +170,9 @@ func CreateGrafDir(t *testing.T, opts ...GrafanaOpts) (string, string) {
 	provNotifiersDir := filepath.Join(provDir, "notifiers")
 	err = os.MkdirAll(provNotifiersDir, 0750)
 	require.NoError(t, err)
+	provAlertingDir := filepath.Join(provDir, "alerting")
+	err = os.MkdirAll(provAlertingDir, 0750)
+	require.NoError(t, err)
 	provPluginsDir := filepath.Join(provDir, "plugins")
 	err = os.MkdirAll(provPluginsDir, 0750)
 	require.NoError(t, err)
@@ -230,6 +233,12 @@ func CreateGrafDir(t *testing.T, opts ...GrafanaOpts) (string, string) {
 			_, err = ngalertingSection.NewKey("admin_config_poll_interval_seconds", fmt.Sprintf("%d", o.NGAlertAdminConfigIntervalSeconds))
 			require.NoError(t, err)
 		}
+		if o.NGAlertRecordedQueriesRemoteWriteURL != "" {
+			unifiedAlertingSection, err := cfg.NewSection("unified_alerting")
+			require.NoError(t, err)
+			_, err = unifiedAlertingSection.NewKey("recorded_queries_remote_write_url", o.NGAlertRecordedQueriesRemoteWriteURL)
+			require.NoError(t, err)
+		}
 
 		if o.AnonymousUserRole != "" {
 			_, err = anonSect.NewKey("org_role", string(o.AnonymousUserRole))
@@ -275,10 +284,12 @@ type GrafanaOpts struct {
 	EnableCSP                         bool
 	EnableFeatureToggles              []string
 	NGAlertAdminConfigIntervalSeconds int
-	AnonymousUserRole                 models.RoleType
-	EnableQuota                       bool
-	DisableAnonymous                  bool
-	CatalogAppEnabled                 bool
-	ViewersCanEdit                    bool
-	PluginAdminEnabled                bool
+	// NGAlertRecordedQueriesRemoteWriteURL is the remote write endpoint of the recorded queries.
+	NGAlertRecordedQueriesRemoteWriteURL string
+	AnonymousUserRole                    models.RoleType
+	EnableQuota                          bool
+	DisableAnonymous                     bool
+	CatalogAppEnabled                    bool
+	ViewersCanEdit                       bool
+	PluginAdminEnabled                   bool
 }
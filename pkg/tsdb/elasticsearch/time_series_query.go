@@ -402,6 +402,20 @@ func (p *timeSeriesQueryParser) parse(tsdbQuery []backend.DataQuery) ([]*Query,
 		alias := model.Get("alias").MustString("")
 		interval := model.Get("interval").MustString("")
 
+		// logs queries are alerted on by counting their documents over time, with zeros when
+		// there are no documents
+		if len(metrics) == 1 && metrics[0].Type == logsType {
+			metrics[0].Type = countType
+			if len(bucketAggs) == 0 {
+				bucketAggs = []*BucketAgg{{
+					Type:     dateHistType,
+					ID:       "2",
+					Field:    timeField,
+					Settings: simplejson.NewFromAny(map[string]interface{}{"min_doc_count": 0}),
+				}}
+			}
+		}
+
 		queries = append(queries, &Query{
 			TimeField:  timeField,
 			RawQuery:   rawQuery,
@@ -378,6 +378,27 @@ func TestExecuteTimeSeriesQuery(t *testing.T) {
 			require.Equal(t, sr.Size, 1337)
 		})
 
+		t.Run("With logs metric", func(t *testing.T) {
+			c := newFakeClient("5.0.0")
+			_, err := executeTsdbQuery(c, `{
+				"timeField": "@timestamp",
+				"query": "level:error",
+				"bucketAggs": [],
+				"metrics": [{ "id": "1", "type": "logs" }]
+			}`, from, to, 15*time.Second)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			require.Equal(t, 0, sr.Size)
+			firstLevel := sr.Aggs[0]
+			require.Equal(t, "2", firstLevel.Key)
+			hAgg := firstLevel.Aggregation.Aggregation.(*es.DateHistogramAgg)
+			require.Equal(t, "@timestamp", hAgg.Field)
+			require.Equal(t, 0, hAgg.MinDocCount)
+			require.Equal(t, fromStr, hAgg.ExtendedBounds.Min)
+			require.Empty(t, firstLevel.Aggregation.Aggs)
+		})
+
 		t.Run("With date histogram agg", func(t *testing.T) {
 			c := newFakeClient("5.0.0")
 			_, err := executeTsdbQuery(c, `{
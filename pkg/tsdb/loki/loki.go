@@ -218,19 +218,37 @@ func (s *Service) parseQuery(dsInfo *datasourceInfo, queryContext *backend.Query
 
 		step := time.Duration(int64(interval.Value) * resolution)
 
+		expr, countLogs := model.Expr, isLogQuery(model.Expr)
+		if countLogs {
+			expr = countLogsExpr(expr, step)
+		}
+
 		qs = append(qs, &lokiQuery{
-			Expr:         model.Expr,
+			Expr:         expr,
 			Step:         step,
 			LegendFormat: model.LegendFormat,
 			Start:        start,
 			End:          end,
 			RefID:        query.RefID,
+			CountLogs:    countLogs,
 		})
 	}
 
 	return qs, nil
 }
 
+// isLogQuery returns whether the expression is a log query, which returns log lines instead of
+// samples. Log queries start with a stream selector, while metric queries start with a function.
+func isLogQuery(expr string) bool {
+	return strings.HasPrefix(strings.TrimSpace(expr), "{")
+}
+
+// countLogsExpr returns a metric query that counts the lines of the log query during each step,
+// so that log queries can be alerted on.
+func countLogsExpr(expr string, step time.Duration) string {
+	return fmt.Sprintf("count_over_time(%s [%s])", strings.TrimSpace(expr), model.Duration(step))
+}
+
 func parseResponse(value *loghttp.QueryResponse, query *lokiQuery) (data.Frames, error) {
 	frames := data.Frames{}
 
@@ -241,28 +259,62 @@ func parseResponse(value *loghttp.QueryResponse, query *lokiQuery) (data.Frames,
 	}
 
 	for _, v := range matrix {
-		name := formatLegend(v.Metric, query)
 		tags := make(map[string]string, len(v.Metric))
-		timeVector := make([]time.Time, 0, len(v.Values))
-		values := make([]float64, 0, len(v.Values))
-
 		for k, v := range v.Metric {
 			tags[string(k)] = string(v)
 		}
 
-		for _, k := range v.Values {
-			timeVector = append(timeVector, time.Unix(k.Timestamp.Unix(), 0).UTC())
-			values = append(values, float64(k.Value))
+		samples := v.Values
+		if query.CountLogs {
+			samples = fillLogCounts(samples, query)
 		}
+		frames = append(frames, newSeriesFrame(formatLegend(v.Metric, query), tags, samples))
+	}
 
-		frames = append(frames, data.NewFrame(name,
-			data.NewField("time", nil, timeVector),
-			data.NewField("value", tags, values).SetConfig(&data.FieldConfig{DisplayNameFromDS: name})))
+	// Loki returns no series when no line matches the log query, which means there are no logs
+	// rather than no data.
+	if query.CountLogs && len(frames) == 0 {
+		frames = append(frames, newSeriesFrame(formatLegend(model.Metric{}, query), map[string]string{}, fillLogCounts(nil, query)))
 	}
 
 	return frames, nil
 }
 
+func newSeriesFrame(name string, tags map[string]string, samples []model.SamplePair) *data.Frame {
+	timeVector := make([]time.Time, 0, len(samples))
+	values := make([]float64, 0, len(samples))
+	for _, k := range samples {
+		timeVector = append(timeVector, time.Unix(k.Timestamp.Unix(), 0).UTC())
+		values = append(values, float64(k.Value))
+	}
+
+	return data.NewFrame(name,
+		data.NewField("time", nil, timeVector),
+		data.NewField("value", tags, values).SetConfig(&data.FieldConfig{DisplayNameFromDS: name}))
+}
+
+// fillLogCounts returns the counts of log lines with a zero for each step of the query that has
+// no count, as Loki skips the steps without logs.
+func fillLogCounts(samples []model.SamplePair, query *lokiQuery) []model.SamplePair {
+	if query.Step <= 0 {
+		return samples
+	}
+
+	counts := make(map[int64]model.SampleValue, len(samples))
+	for _, s := range samples {
+		counts[s.Timestamp.Unix()] = s.Value
+	}
+
+	filled := make([]model.SamplePair, 0, len(samples))
+	for t := query.Start.Truncate(time.Second); !t.After(query.End); t = t.Add(query.Step) {
+		filled = append(filled, model.SamplePair{
+			Timestamp: model.TimeFromUnix(t.Unix()),
+			Value:     counts[t.Unix()],
+		})
+	}
+	return filled
+}
+
 func (s *Service) getDSInfo(pluginCtx backend.PluginContext) (*datasourceInfo, error) {
 	i, err := s.im.Get(pluginCtx)
 	if err != nil {
@@ -115,6 +115,37 @@ func TestLoki(t *testing.T) {
 		fmt.Println(models)
 		require.Equal(t, time.Second*2, models[0].Step)
 	})
+
+	t.Run("parsing log query model", func(t *testing.T) {
+		queryContext := &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{
+				{
+					JSON: []byte(`
+					{
+						"expr": "{app=\"backend\"} |= \"error\"",
+						"refId": "A"
+					}`,
+					),
+					TimeRange: backend.TimeRange{
+						From: time.Now().Add(-30 * time.Minute),
+						To:   time.Now(),
+					},
+				},
+			},
+		}
+		service := &Service{
+			intervalCalculator: mockCalculator{
+				interval: tsdb.Interval{
+					Value: time.Minute,
+				},
+			},
+		}
+		dsInfo := &datasourceInfo{}
+		models, err := service.parseQuery(dsInfo, queryContext)
+		require.NoError(t, err)
+		require.Equal(t, `count_over_time({app="backend"} |= "error" [1m])`, models[0].Expr)
+		require.True(t, models[0].CountLogs)
+	})
 }
 
 func TestParseResponse(t *testing.T) {
@@ -172,6 +203,37 @@ func TestParseResponse(t *testing.T) {
 			t.Errorf("Result mismatch (-want +got):\n%s", diff)
 		}
 	})
+
+	t.Run("log counts should be filled with zeros", func(t *testing.T) {
+		query := &lokiQuery{
+			Start:     time.Unix(0, 0),
+			End:       time.Unix(3, 0),
+			Step:      time.Second,
+			CountLogs: true,
+		}
+		value := loghttp.QueryResponse{
+			Data: loghttp.QueryResponseData{
+				Result: loghttp.Matrix{
+					p.SampleStream{
+						Metric: p.Metric{"app": "backend"},
+						Values: []p.SamplePair{{Value: 2, Timestamp: 1000}},
+					},
+				},
+			},
+		}
+		frames, err := parseResponse(&value, query)
+		require.NoError(t, err)
+		require.Len(t, frames, 1)
+		require.Equal(t, []float64{0, 2, 0, 0}, frameValues(frames[0]))
+		require.Equal(t, data.Labels{"app": "backend"}, frames[0].Fields[1].Labels)
+
+		value.Data.Result = loghttp.Matrix{}
+		frames, err = parseResponse(&value, query)
+		require.NoError(t, err)
+		require.Len(t, frames, 1)
+		require.Equal(t, []float64{0, 0, 0, 0}, frameValues(frames[0]))
+		require.Empty(t, frames[0].Fields[1].Labels)
+	})
 }
 
 type mockCalculator struct {
@@ -185,3 +247,11 @@ func (m mockCalculator) Calculate(timerange backend.TimeRange, minInterval time.
 func (m mockCalculator) CalculateSafeInterval(timerange backend.TimeRange, resolution int64) tsdb.Interval {
 	return m.interval
 }
+
+func frameValues(frame *data.Frame) []float64 {
+	values := make([]float64, 0, frame.Fields[1].Len())
+	for i := 0; i < frame.Fields[1].Len(); i++ {
+		values = append(values, frame.Fields[1].At(i).(float64))
+	}
+	return values
+}
@@ -9,4 +9,6 @@ type lokiQuery struct {
 	Start        time.Time
 	End          time.Time
 	RefID        string
+	// CountLogs is set when Expr counts the lines of a log query, whose missing points are zeros.
+	CountLogs bool
 }
@@ -0,0 +1,41 @@
+import React from 'react';
+import { css } from '@emotion/css';
+import { GrafanaTheme2 } from '@grafana/data';
+import { config } from '@grafana/runtime';
+import { Alert, LinkButton, useStyles2 } from '@grafana/ui';
+import { contextSrv } from 'app/core/services/context_srv';
+
+/**
+ * Shown for as long as a Grafana Admin is impersonating the signed in user.
+ */
+export function ImpersonationBanner() {
+  const styles = useStyles2(getStyles);
+  const { impersonatedBy, login } = contextSrv.user;
+
+  if (!impersonatedBy) {
+    return null;
+  }
+
+  return (
+    <div className={styles.banner}>
+      <Alert severity="warning" title={`Impersonating ${login}`}>
+        {impersonatedBy} is signed in as {login}. Actions are recorded in the audit log.{' '}
+        <LinkButton size="sm" variant="secondary" href={`${config.appSubUrl}/logout`} target="_self">
+          Stop impersonating
+        </LinkButton>
+      </Alert>
+    </div>
+  );
+}
+
+const getStyles = (theme: GrafanaTheme2) => ({
+  banner: css`
+    position: fixed;
+    bottom: 0;
+    left: 0;
+    right: 0;
+    z-index: ${theme.zIndex.portal};
+    max-width: 600px;
+    margin: ${theme.spacing(2)} auto;
+  `,
+});
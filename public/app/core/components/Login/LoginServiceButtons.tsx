@@ -27,6 +27,12 @@ const loginServices: () => LoginServices = () => {
       name: 'SAML',
       icon: 'key-skeleton-alt',
     },
+    generic_saml: {
+      bgColor: '#464646',
+      enabled: config.genericSamlEnabled,
+      name: 'SAML',
+      icon: 'key-skeleton-alt',
+    },
     google: {
       bgColor: '#e84d3c',
       enabled: oauthEnabled && config.oauth.google,
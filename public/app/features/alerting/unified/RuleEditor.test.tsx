@@ -221,6 +221,9 @@ describe('RuleEditor', () => {
             data: getDefaultQueries(),
             exec_err_state: 'Alerting',
             no_data_state: 'NoData',
+            no_data_threshold: 1,
+            exec_err_threshold: 1,
+            disable_dashboard_annotations: false,
             title: 'my great new rule',
           },
         },
@@ -1,9 +1,15 @@
 import React, { FC } from 'react';
+import { useFormContext } from 'react-hook-form';
+import { Field, Switch } from '@grafana/ui';
 import LabelsField from './LabelsField';
 import AnnotationsField from './AnnotationsField';
 import { RuleEditorSection } from './RuleEditorSection';
+import { RuleFormType, RuleFormValues } from '../../types/rule-form';
 
 export const DetailsStep: FC = () => {
+  const { register, watch } = useFormContext<RuleFormValues>();
+  const type = watch('type');
+
   return (
     <RuleEditorSection
       stepNo={4}
@@ -12,6 +18,14 @@ export const DetailsStep: FC = () => {
     >
       <AnnotationsField />
       <LabelsField />
+      {type === RuleFormType.grafana && (
+        <Field
+          label="Annotate dashboard"
+          description="Write the firing and resolved alerts as annotations on the dashboard panel that the rule is linked to"
+        >
+          <Switch {...register('annotateDashboard')} />
+        </Field>
+      )}
     </RuleEditorSection>
   );
 };
@@ -12,6 +12,7 @@ const options: SelectableValue[] = [
   { value: GrafanaAlertStateDecision.Alerting, label: 'Alerting' },
   { value: GrafanaAlertStateDecision.NoData, label: 'No Data' },
   { value: GrafanaAlertStateDecision.OK, label: 'OK' },
+  { value: GrafanaAlertStateDecision.KeepLastState, label: 'Keep Last State' },
 ];
 
 export const GrafanaAlertStatePicker: FC<Props> = ({ includeNoData, ...props }) => {
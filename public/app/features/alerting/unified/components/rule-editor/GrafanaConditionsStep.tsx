@@ -22,6 +22,18 @@ const forValidationOptions: RegisterOptions = {
   pattern: durationValidationPattern,
 };
 
+const thresholdValidationOptions: RegisterOptions = {
+  required: {
+    value: true,
+    message: 'Required.',
+  },
+  valueAsNumber: true,
+  min: {
+    value: 1,
+    message: 'Must be at least 1.',
+  },
+};
+
 const evaluateEveryValidationOptions: RegisterOptions = {
   required: {
     value: true,
@@ -105,6 +117,14 @@ export const GrafanaConditionsStep: FC = () => {
               name="noDataState"
             />
           </Field>
+          <Field
+            label="Evaluations with no data before the state changes"
+            description="Keeps the current state until this many evaluations in a row have no data"
+            error={errors.noDataThreshold?.message}
+            invalid={!!errors.noDataThreshold?.message}
+          >
+            <Input type="number" width={8} {...register('noDataThreshold', thresholdValidationOptions)} />
+          </Field>
           <Field label="Alert state if execution error or timeout">
             <InputControl
               render={({ field: { onChange, ref, ...field } }) => (
@@ -118,6 +138,14 @@ export const GrafanaConditionsStep: FC = () => {
               name="execErrState"
             />
           </Field>
+          <Field
+            label="Failed evaluations before the state changes"
+            description="Keeps the current state until this many evaluations in a row fail"
+            error={errors.execErrThreshold?.message}
+            invalid={!!errors.execErrThreshold?.message}
+          >
+            <Input type="number" width={8} {...register('execErrThreshold', thresholdValidationOptions)} />
+          </Field>
         </>
       )}
       <PreviewRule />
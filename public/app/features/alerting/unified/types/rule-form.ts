@@ -19,6 +19,9 @@ export interface RuleFormValues {
   condition: string | null; // refId of the query that gets alerted on
   noDataState: GrafanaAlertStateDecision;
   execErrState: GrafanaAlertStateDecision;
+  noDataThreshold: number; // consecutive evaluations with no data before noDataState applies
+  execErrThreshold: number; // consecutive failed evaluations before execErrState applies
+  annotateDashboard: boolean;
   folder: { title: string; id: number } | null;
   evaluateEvery: string;
   evaluateFor: string;
@@ -48,6 +48,9 @@ export const getDefaultFormValues = (): RuleFormValues =>
     condition: '',
     noDataState: GrafanaAlertStateDecision.NoData,
     execErrState: GrafanaAlertStateDecision.Alerting,
+    noDataThreshold: 1,
+    execErrThreshold: 1,
+    annotateDashboard: true,
     evaluateEvery: '1m',
     evaluateFor: '5m',
 
@@ -75,7 +78,17 @@ function listifyLabelsOrAnnotations(item: Labels | Annotations | undefined): Arr
 }
 
 export function formValuesToRulerGrafanaRuleDTO(values: RuleFormValues): PostableRuleGrafanaRuleDTO {
-  const { name, condition, noDataState, execErrState, evaluateFor, queries } = values;
+  const {
+    name,
+    condition,
+    noDataState,
+    execErrState,
+    noDataThreshold,
+    execErrThreshold,
+    annotateDashboard,
+    evaluateFor,
+    queries,
+  } = values;
   if (condition) {
     return {
       grafana_alert: {
@@ -83,6 +96,9 @@ export function formValuesToRulerGrafanaRuleDTO(values: RuleFormValues): Postabl
         condition,
         no_data_state: noDataState,
         exec_err_state: execErrState,
+        no_data_threshold: noDataThreshold,
+        exec_err_threshold: execErrThreshold,
+        disable_dashboard_annotations: !annotateDashboard,
         data: queries,
       },
       for: evaluateFor,
@@ -108,6 +124,9 @@ export function rulerRuleToFormValues(ruleWithLocation: RuleWithLocation): RuleF
         evaluateEvery: group.interval || defaultFormValues.evaluateEvery,
         noDataState: ga.no_data_state,
         execErrState: ga.exec_err_state,
+        noDataThreshold: ga.no_data_threshold || defaultFormValues.noDataThreshold,
+        execErrThreshold: ga.exec_err_threshold || defaultFormValues.execErrThreshold,
+        annotateDashboard: !ga.disable_dashboard_annotations,
         queries: ga.data,
         condition: ga.condition,
         annotations: listifyLabelsOrAnnotations(rule.annotations),
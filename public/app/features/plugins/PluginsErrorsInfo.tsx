@@ -2,7 +2,7 @@ import React from 'react';
 import { selectors } from '@grafana/e2e-selectors';
 import { HorizontalGroup, InfoBox, List, PluginSignatureBadge, useTheme } from '@grafana/ui';
 import { StoreState } from '../../types';
-import { getAllPluginsErrors } from './state/selectors';
+import { getPluginsSignatureErrors } from './state/selectors';
 import { loadPlugins, loadPluginsErrors } from './state/actions';
 import useAsync from 'react-use/lib/useAsync';
 import { connect, ConnectedProps } from 'react-redux';
@@ -11,7 +11,7 @@ import { PluginErrorCode, PluginSignatureStatus } from '@grafana/data';
 import { css } from '@emotion/css';
 
 const mapStateToProps = (state: StoreState) => ({
-  errors: getAllPluginsErrors(state.plugins),
+  errors: getPluginsSignatureErrors(state.plugins),
 });
 
 const mapDispatchToProps = {
@@ -78,6 +78,20 @@ grafanaRuntime.SystemJS.config({
   },
 });
 
+// Plugin modules served from the plugin CDN are loaded with a script tag, so that
+// the browser checks them against the integrity hash computed by the server.
+for (const [modulePath, integrity] of Object.entries(config.pluginModuleIntegrity ?? {})) {
+  grafanaRuntime.SystemJS.config({
+    meta: {
+      [modulePath]: {
+        scriptLoad: true,
+        crossOrigin: 'anonymous',
+        integrity,
+      },
+    },
+  });
+}
+
 function exposeToPlugin(name: string, component: any) {
   grafanaRuntime.SystemJS.registerDynamic(name, [], true, (require: any, exports: any, module: { exports: any }) => {
     module.exports = component;
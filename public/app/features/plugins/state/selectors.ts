@@ -1,3 +1,4 @@
+import { PluginErrorCode } from '@grafana/data';
 import { PluginsState } from 'app/types/plugins';
 
 export const getPlugins = (state: PluginsState) => {
@@ -16,4 +17,14 @@ export const getAllPluginsErrors = (state: PluginsState) => {
   return state.errors;
 };
 
+const nonSignatureErrorCodes = [
+  PluginErrorCode.incompatibleGrafanaVersion,
+  PluginErrorCode.missingExecutable,
+  PluginErrorCode.crashedProcess,
+];
+
+export const getPluginsSignatureErrors = (state: PluginsState) => {
+  return state.errors.filter((error) => !nonSignatureErrorCodes.includes(error.errorCode));
+};
+
 export const getPluginsSearchQuery = (state: PluginsState) => state.searchQuery;
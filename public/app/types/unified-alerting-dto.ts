@@ -120,6 +120,9 @@ export interface PostableGrafanaRuleDefinition {
   condition: string;
   no_data_state: GrafanaAlertStateDecision;
   exec_err_state: GrafanaAlertStateDecision;
+  no_data_threshold?: number;
+  exec_err_threshold?: number;
+  disable_dashboard_annotations?: boolean;
   data: AlertQuery[];
 }
 export interface GrafanaRuleDefinition extends PostableGrafanaRuleDefinition {